@@ -2,33 +2,127 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"log"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/mail"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
+	"yuplan/internal/admintoken"
+	"yuplan/internal/analytics"
+	"yuplan/internal/badges"
+	"yuplan/internal/cache"
+	"yuplan/internal/calibration"
+	"yuplan/internal/captcha"
+	"yuplan/internal/catalogsync"
+	"yuplan/internal/claims"
 	"yuplan/internal/config"
+	"yuplan/internal/contentfilter"
 	"yuplan/internal/database"
+	"yuplan/internal/dataquality"
+	"yuplan/internal/digest"
+	"yuplan/internal/exemption"
+	"yuplan/internal/export"
+	"yuplan/internal/feed"
 	"yuplan/internal/handlers"
+	"yuplan/internal/jobs"
+	"yuplan/internal/keywords"
+	"yuplan/internal/logging"
+	"yuplan/internal/mailer"
+	"yuplan/internal/metrics"
 	"yuplan/internal/middleware"
+	"yuplan/internal/migrate"
+	"yuplan/internal/models"
+	"yuplan/internal/moderation"
+	"yuplan/internal/offerings"
+	"yuplan/internal/photo"
+	"yuplan/internal/redact"
+	"yuplan/internal/render"
+	"yuplan/internal/reports"
 	"yuplan/internal/repository"
+	"yuplan/internal/retention"
+	"yuplan/internal/rmp"
+	"yuplan/internal/schema"
+	"yuplan/internal/scraper"
+	"yuplan/internal/search"
+	"yuplan/internal/session"
+	"yuplan/internal/verification"
+	"yuplan/internal/waitlist"
+	"yuplan/internal/watches"
+	"yuplan/migrations"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 )
 
 func main() {
-	ctx := context.Background()
+	// Everything, including the log package, writes JSON lines to stdout
+	slog.SetDefault(logging.New(os.Stdout))
+
+	// SIGINT/SIGTERM stop the background workers and drain the HTTP server
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 	cfg := config.Load()
 
 	pool, err := initDatabase(ctx, cfg.DatabaseURL)
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
-	defer pool.Close()
 
-	router := setupRouter(pool)
+	// `api migrate ...` migrates the database and exits instead of serving
+	if len(os.Args) > 1 {
+		if os.Args[1] != "migrate" {
+			log.Fatalf("Unknown command %q; %s", os.Args[1], migrateUsage)
+		}
+		err := runMigrate(ctx, pool, os.Args[2:], os.Stdout)
+		pool.Close()
+		if err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		return
+	}
+	if cfg.AutoMigrate {
+		if err := runMigrate(ctx, pool, []string{"up"}, log.Writer()); err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+	}
+
+	if err := checkSchema(ctx, pool, cfg.SchemaCheck); err != nil {
+		log.Fatalf("Schema check failed: %v", err)
+	}
+
+	db, bg, err := wire(cfg, pool)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	// Workers outlive the signal until requests have drained, so searches made
+	// while draining are still recorded
+	bgCtx, stopBackground := context.WithCancel(context.Background())
+	bg.start(bgCtx, cfg)
+
+	engine, err := newEngine(cfg)
+	if err != nil {
+		log.Fatalf("Invalid HTTP engine config: %v", err)
+	}
+	router := setupRouter(engine, db, cfg, bg)
 
-	if err := startServer(router, cfg.Port); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	err = startServer(ctx, newServer(middleware.HeadAsGet(router), cfg), cfg.ShutdownTimeout)
+	stopBackground()
+	<-bg.searchRecorder.Stopped()
+	pool.Close()
+	if err != nil {
+		log.Fatalf("Server failed: %v", err)
 	}
+	log.Printf("Server stopped")
 }
 
 func initDatabase(ctx context.Context, databaseURL string) (*pgxpool.Pool, error) {
@@ -39,48 +133,863 @@ func initDatabase(ctx context.Context, databaseURL string) (*pgxpool.Pool, error
 	return pool, nil
 }
 
-func setupRouter(pool *pgxpool.Pool) *gin.Engine {
-	courseRepo := repository.NewCourseRepository(pool)
-	sectionActivityRepo := repository.NewSectionActivityRepository(pool)
-	sectionRepo := repository.NewSectionRepository(pool, sectionActivityRepo)
-	courseHandler := handlers.NewCourseHandler(courseRepo, sectionRepo)
+// checkSchema compares the live schema with the one this build expects. Drift
+// is an error in "fail" mode and only logged in "warn" mode; "off" skips it.
+func checkSchema(ctx context.Context, pool *pgxpool.Pool, mode string) error {
+	if mode == "off" {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	drifts, err := schema.Check(ctx, pool)
+	if err != nil {
+		return err
+	}
+	if len(drifts) == 0 {
+		return nil
+	}
+	lines := make([]string, len(drifts))
+	for i, d := range drifts {
+		lines[i] = d.String()
+	}
+	diff := strings.Join(lines, "\n  ")
+	if mode == "warn" {
+		log.Printf("Database schema has drifted; were migrations applied?\n  %s", diff)
+		return nil
+	}
+	return fmt.Errorf("database schema has drifted; were migrations applied?\n  %s", diff)
+}
+
+const migrateUsage = "usage: api migrate [up | down N | version | force V]"
+
+// runMigrate runs `api migrate` against the migrations embedded in the
+// binary: up (the default) applies every pending one, down N reverts the
+// last N, version prints the current version and force V records V without
+// running anything, once a dirty database has been repaired by hand.
+func runMigrate(ctx context.Context, pool migrateDB, args []string, out io.Writer) error {
+	m, err := migrate.New(pool, migrations.FS)
+	if err != nil {
+		return err
+	}
+	command := "up"
+	if len(args) > 0 {
+		command, args = args[0], args[1:]
+	}
+
+	switch {
+	case command == "up" && len(args) == 0:
+		applied, err := m.Up(ctx)
+		if err != nil {
+			return err
+		}
+		for _, migration := range applied {
+			fmt.Fprintf(out, "Applied migration %06d_%s\n", migration.Version, migration.Name)
+		}
+		if len(applied) == 0 {
+			fmt.Fprintln(out, "No migrations to apply")
+		}
+		return nil
+	case command == "down" && len(args) == 1:
+		steps, err := strconv.Atoi(args[0])
+		if err != nil || steps < 1 {
+			return errors.New(migrateUsage)
+		}
+		reverted, err := m.Down(ctx, steps)
+		if err != nil {
+			return err
+		}
+		for _, migration := range reverted {
+			fmt.Fprintf(out, "Reverted migration %06d_%s\n", migration.Version, migration.Name)
+		}
+		return nil
+	case command == "version" && len(args) == 0:
+		version, dirty, err := m.Version(ctx)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			fmt.Fprintf(out, "%d (dirty)\n", version)
+		} else {
+			fmt.Fprintf(out, "%d\n", version)
+		}
+		return nil
+	case command == "force" && len(args) == 1:
+		version, err := strconv.ParseUint(args[0], 10, 0)
+		if err != nil {
+			return errors.New(migrateUsage)
+		}
+		return m.Force(ctx, uint(version))
+	}
+	return errors.New(migrateUsage)
+}
+
+// migrateDB is what migrations run on. Implemented by *pgxpool.Pool.
+type migrateDB interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// wire applies cfg's process-wide settings and builds the repositories' shared
+// database handle and the background workers around pool. It is what main and
+// the end-to-end tests both boot the router from.
+func wire(cfg *config.Config, pool *pgxpool.Pool) (*repository.ResilientDB, *background, error) {
+	repository.SetTimeouts(repository.Timeouts{
+		Read:      cfg.DBReadTimeout,
+		Write:     cfg.DBWriteTimeout,
+		Aggregate: cfg.DBAggregateTimeout,
+	})
+	handlers.SetPageSizes(cfg.PageSizes)
+
+	// Repositories share one retry policy and circuit breaker
+	db := repository.NewResilientDB(pool, repository.Resilience{
+		Retries:          cfg.DBRetries,
+		BaseDelay:        25 * time.Millisecond,
+		MaxDelay:         250 * time.Millisecond,
+		BreakerThreshold: cfg.DBBreakerThreshold,
+		BreakerCooldown:  cfg.DBBreakerCooldown,
+	})
+
+	var err error
+	bg := newBackground(cfg, pool, db)
+	if bg.captcha, err = newCaptchaVerifier(cfg); err != nil {
+		return nil, nil, fmt.Errorf("invalid CAPTCHA config: %w", err)
+	}
+	if bg.contentFilter, err = newContentFilter(cfg); err != nil {
+		return nil, nil, fmt.Errorf("invalid content filter config: %w", err)
+	}
+	if bg.mailer, err = newMailer(cfg); err != nil {
+		return nil, nil, fmt.Errorf("invalid SMTP config: %w", err)
+	}
+	bg.watches = watches.NewWatcher(repository.NewSeatWatchRepository(db), bg.mailer).WithLocker(bg.locker)
+	if cfg.SMTPHost != "" {
+		// Otherwise digests keep the LogNotifier and stay due until mail is configured
+		bg.digests.WithNotifier(digest.NewMailNotifier(bg.mailer))
+	}
+	if bg.catalogSync, bg.syncSchedule, err = newCatalogSync(cfg, db, bg.locker, bg.cache); err != nil {
+		return nil, nil, fmt.Errorf("invalid catalog sync config: %w", err)
+	}
+	return db, bg, nil
+}
+
+// background holds the workers that run alongside the HTTP server.
+type background struct {
+	pool           *pgxpool.Pool    // pinged directly by readiness, bypassing the circuit breaker
+	exporter       *export.Exporter // nil when exports are disabled
+	searchRecorder *analytics.SearchRecorder
+	reloader       *config.Reloader
+	locker         *jobs.Locker // keeps scheduled jobs to one instance
+	offerings      *offerings.Refresher
+	keywords       *keywords.Aggregator
+	badges         *badges.Awarder
+	digests        *digest.Sender
+	calibration    *calibration.Calibrator
+	retention      *retention.Purger
+	captcha        captcha.Verifier // nil when CAPTCHA_PROVIDER is unset
+	contentFilter  contentfilter.Chain
+	mailer         mailer.Mailer // logs instead of sending when SMTP_HOST is unset
+	watches        *watches.Watcher
+	catalogSync    *catalogsync.Syncer // nil when SCRAPER_TERM is unset
+	ratings        *rmp.Refresher      // nil when RMP_SCHOOL_ID is unset
+	syncSchedule   jobs.Schedule
+	cache          *cache.Store // catalog reads; dropped by course after a sync, or whole when a seed.sh load is detected
+}
+
+// newBackground wires the workers. Jobs hold their advisory locks on
+// connections of their own from pool; everything else goes through db.
+func newBackground(cfg *config.Config, pool *pgxpool.Pool, db *repository.ResilientDB) *background {
+	locker := jobs.NewLocker(pool)
+	exporter := newExporter(cfg, db)
+	if exporter != nil {
+		exporter.WithLocker(locker)
+	}
+	purger := retention.NewPurger(repository.NewRetentionRepository(db), []models.RetentionPolicy{
+		{Name: models.RetentionSearchStats, MaxAge: cfg.RetentionSearchStats},
+		{Name: models.RetentionResolvedQuarantine, MaxAge: cfg.RetentionResolvedQuarantine},
+		{Name: models.RetentionCourseViews, MaxAge: cfg.CourseSeenTTL},
+		{Name: models.RetentionAnonymousSessions, MaxAge: cfg.SessionTTL},
+		{Name: models.RetentionUnverifiedReviews, MaxAge: cfg.RetentionUnverifiedReviews},
+		{Name: models.RetentionVerificationTokens, MaxAge: cfg.RetentionVerificationTokens},
+	}).WithDryRun(cfg.RetentionDryRun).WithLocker(locker)
+	catalogCache := cache.NewStore(newCacheBackend(cfg), cfg.CacheTTL)
+	invalidateCatalog := func(ctx context.Context) {
+		if err := catalogCache.Invalidate(ctx, repository.CatalogCaches...); err != nil {
+			log.Printf("invalidating catalog cache: %v", err)
+		}
+	}
+	dataQuality := dataquality.NewScorer(repository.NewDataQualityRepository(db))
+	onNewSeed := func(ctx context.Context, cacheInvalidated bool) {
+		if !cacheInvalidated {
+			invalidateCatalog(ctx)
+		}
+		if _, err := dataQuality.Run(ctx); err != nil {
+			log.Printf("scoring catalog data quality: %v", err)
+		}
+	}
+	digests := digest.NewSender(repository.NewDigestRepository(db), digest.LogNotifier{}).
+		WithLocker(locker).
+		WithCatalogChanged(onNewSeed)
+	return &background{
+		pool:           pool,
+		exporter:       exporter,
+		locker:         locker,
+		offerings:      offerings.NewRefresher(repository.NewOfferingRepository(db)).WithLocker(locker),
+		keywords:       keywords.NewAggregator(repository.NewReviewKeywordRepository(db)).WithLocker(locker),
+		badges:         badges.NewAwarder(repository.NewBadgeRepository(db)).WithLocker(locker),
+		digests:        digests,
+		calibration:    calibration.NewCalibrator(repository.NewCalibrationRepository(db), cfg.ReviewStatsWindow).WithLocker(locker),
+		ratings:        newRatingRefresher(cfg, db, locker),
+		retention:      purger,
+		searchRecorder: analytics.NewSearchRecorder(repository.NewSearchStatsRepository(db), 1000, 30*time.Second),
+		reloader:       config.NewReloader(cfg.ConfigFile, cfg.Tunables),
+		cache:          catalogCache,
+	}
+}
+
+// newCacheBackend returns Redis when REDIS_URL is set and memory otherwise.
+// Like the tunables, a bad REDIS_URL falls back rather than refusing to boot,
+// and so does a Redis that can't be reached at startup.
+func newCacheBackend(cfg *config.Config) cache.Backend {
+	memory := cache.NewMemory(cfg.CacheMemoryEntries)
+	if cfg.RedisURL == "" {
+		return memory
+	}
+	redis, err := cache.NewRedis(cfg.RedisURL, 16)
+	if err != nil {
+		log.Printf("Invalid REDIS_URL, caching in memory: %v", err)
+		return memory
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := redis.Ping(ctx); err != nil {
+		log.Printf("Redis unreachable, caching in memory: %v", err)
+		return memory
+	}
+	return redis
+}
+
+func (b *background) start(ctx context.Context, cfg *config.Config) {
+	if b.exporter != nil {
+		b.exporter.Start(ctx, cfg.ExportInterval)
+	}
+	b.offerings.Start(ctx, cfg.OfferingRefreshInterval)
+	b.keywords.Start(ctx, cfg.ReviewKeywordsInterval)
+	b.badges.Start(ctx, cfg.ReviewBadgesInterval)
+	b.digests.Start(ctx, cfg.DigestInterval)
+	b.calibration.Start(ctx, cfg.DifficultyCalibrationInterval)
+	b.retention.Start(ctx, cfg.RetentionInterval)
+	b.watches.Start(ctx, cfg.SeatWatchInterval)
+	if b.catalogSync != nil {
+		b.catalogSync.Start(ctx, b.syncSchedule)
+	}
+	if b.ratings != nil {
+		b.ratings.Start(ctx, cfg.RMPRefreshInterval)
+	}
+	b.searchRecorder.Start(ctx)
+	b.reloader.WatchSignals(ctx)
+}
+
+// newExporter builds the snapshot exporter, or returns nil when EXPORT_STORE is unset.
+func newExporter(cfg *config.Config, db *repository.ResilientDB) *export.Exporter {
+	var store export.Store
+	switch cfg.ExportStore {
+	case "s3":
+		store = export.NewS3Store(cfg.S3Endpoint, cfg.S3Region, cfg.S3Bucket, cfg.S3AccessKeyID, cfg.S3SecretKey)
+	case "file":
+		store = export.NewFileStore(cfg.ExportDir)
+	default:
+		return nil
+	}
+	return export.NewExporter(repository.NewExportRepository(db), store, cfg.ExportRetention)
+}
+
+// newPhotoLibrary builds the instructor photo library, or returns nil when
+// PHOTO_STORE is unset. Photos go under the instructors/ prefix of the store.
+func newPhotoLibrary(cfg *config.Config) *photo.Library {
+	var store export.Store
+	switch cfg.PhotoStore {
+	case "s3":
+		store = export.NewS3Store(cfg.S3Endpoint, cfg.S3Region, cfg.S3Bucket, cfg.S3AccessKeyID, cfg.S3SecretKey)
+	case "file":
+		store = export.NewFileStore(cfg.PhotoDir)
+	default:
+		return nil
+	}
+	return photo.NewLibrary(store, cfg.PhotoBaseURL)
+}
+
+// newCaptchaVerifier builds the CAPTCHA verifier, or returns nil when CAPTCHA_PROVIDER is unset.
+func newCaptchaVerifier(cfg *config.Config) (captcha.Verifier, error) {
+	if cfg.CaptchaProvider == "" {
+		return nil, nil
+	}
+	verifier, err := captcha.New(cfg.CaptchaProvider, cfg.CaptchaSecret)
+	if err != nil {
+		return nil, err
+	}
+	return verifier, nil
+}
+
+// newRatingRefresher builds the RateMyProfessors rating refresh, or returns
+// nil when RMP_SCHOOL_ID is unset.
+func newRatingRefresher(cfg *config.Config, db *repository.ResilientDB, locker *jobs.Locker) *rmp.Refresher {
+	if cfg.RMPSchoolID == "" {
+		return nil
+	}
+	url := cfg.RMPURL
+	if url == "" {
+		url = rmp.DefaultURL
+	}
+	client := rmp.NewClient(url, cfg.RMPSchoolID)
+	return rmp.NewRefresher(repository.NewInstructorRatingRepository(db), client).WithLocker(locker)
+}
+
+// newCatalogSync builds the scheduled catalog re-scrape, or returns nil when
+// SCRAPER_TERM is unset. Descriptions are read once, at startup. Each run
+// drops the cached reads of the courses it changed from catalogCache.
+func newCatalogSync(cfg *config.Config, db *repository.ResilientDB, locker *jobs.Locker, catalogCache *cache.Store) (*catalogsync.Syncer, jobs.Schedule, error) {
+	if cfg.ScraperTerm == "" {
+		return nil, jobs.Schedule{}, nil
+	}
+	term, ok := models.NewTerm(cfg.ScraperTerm)
+	if !ok {
+		return nil, jobs.Schedule{}, fmt.Errorf("SCRAPER_TERM must be a session code and year such as FW2025, got %q", cfg.ScraperTerm)
+	}
+	schedule, err := jobs.ParseSchedule(cfg.SyncSchedule)
+	if err != nil {
+		return nil, jobs.Schedule{}, err
+	}
+
+	s := scraper.New(repository.NewCatalogRepository(db), repository.NewQuarantineRepository(db), slog.Default()).
+		WithInvalidator(repository.NewCatalogCacheInvalidator(catalogCache))
+	if cfg.ScraperDescriptions != "" {
+		f, err := os.Open(cfg.ScraperDescriptions)
+		if err != nil {
+			return nil, jobs.Schedule{}, err
+		}
+		descriptions, err := scraper.LoadDescriptions(f)
+		f.Close()
+		if err != nil {
+			return nil, jobs.Schedule{}, err
+		}
+		s.WithDescriptions(descriptions)
+	}
+
+	baseURL, faculties := cfg.ScraperBaseURL, cfg.ScraperFaculties
+	if baseURL == "" {
+		baseURL = scraper.DefaultBaseURL
+	}
+	if len(faculties) == 0 {
+		faculties = scraper.DefaultFaculties
+	}
+	pages := scraper.FacultyPages(baseURL, term, faculties)
+	return catalogsync.NewSyncer(s, repository.NewSyncRepository(db), term, pages).WithLocker(locker), schedule, nil
+}
+
+// newContentFilter builds the review content filter. The profanity filter
+// shares the moderation word list unless CONTENT_FILTER_WORDS is set.
+func newContentFilter(cfg *config.Config) (contentfilter.Chain, error) {
+	words := cfg.ContentFilterWords
+	if len(words) == 0 {
+		words = cfg.ModerationBlockedWords
+	}
+	return contentfilter.New(cfg.ContentFilterProfanity, cfg.ContentFilterLinks, cfg.ContentFilterSpam, words)
+}
+
+// newMailer sends through SMTP_HOST, or only logs mail when it is unset.
+func newMailer(cfg *config.Config) (mailer.Mailer, error) {
+	if cfg.SMTPHost == "" {
+		return mailer.Log{}, nil
+	}
+	if _, err := mail.ParseAddress(cfg.SMTPFrom); err != nil {
+		return nil, fmt.Errorf("SMTP_FROM %q: %w", cfg.SMTPFrom, err)
+	}
+	return mailer.NewSMTP(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPFrom), nil
+}
+
+// newReportNotifier mails MODERATOR_EMAIL through SMTP_HOST when reports
+// hide a review, and only logs it when either is unset.
+func newReportNotifier(cfg *config.Config, m mailer.Mailer) reports.Notifier {
+	if cfg.SMTPHost == "" || cfg.ModeratorEmail == "" {
+		return reports.LogNotifier{}
+	}
+	return reports.NewMailNotifier(m, cfg.ModeratorEmail)
+}
+
+// newSessionSigner returns nil when SESSION_SECRET is unset, which leaves
+// anonymous sessions off.
+func newSessionSigner(cfg *config.Config) *session.Signer {
+	if cfg.SessionSecret == "" {
+		return nil
+	}
+	return session.NewSigner(cfg.SessionSecret, cfg.SessionTTL)
+}
+
+// newExemptionSigner returns nil when RATE_LIMIT_EXEMPTION_SECRET is unset,
+// which leaves exemptions off.
+func newExemptionSigner(cfg *config.Config) *exemption.Signer {
+	if cfg.RateLimitExemptionSecret == "" {
+		return nil
+	}
+	return exemption.NewSigner(cfg.RateLimitExemptionSecret, cfg.RateLimitExemptionMaxTTL, cfg.RateLimitExemptionMaxLimit)
+}
+
+// newAdminTokens returns nil when ADMIN_JWT_SECRET is unset, which leaves the
+// admin API key as the only way in.
+func newAdminTokens(cfg *config.Config) *admintoken.Signer {
+	if cfg.AdminJWTSecret == "" {
+		return nil
+	}
+	return admintoken.NewSigner(cfg.AdminJWTSecret)
+}
+
+// requireCaptcha requires a CAPTCHA on a route while flag is on. Without a
+// verifier it lets everything through.
+func requireCaptcha(bg *background, flag string) gin.HandlerFunc {
+	if bg.captcha == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return middleware.RequireCaptcha(bg.captcha, func() bool { return bg.reloader.Current().Enabled(flag) })
+}
+
+// trustedPlatforms maps TRUSTED_PLATFORM names to the client IP header each sets.
+var trustedPlatforms = map[string]string{
+	"cloudflare": gin.PlatformCloudflare,
+	"appengine":  gin.PlatformGoogleAppEngine,
+	"flyio":      gin.PlatformFlyIO,
+}
+
+// newEngine builds the bare gin engine setupRouter registers on, in the
+// configured mode and with the configured view of which forwarding headers
+// to believe for client IPs.
+func newEngine(cfg *config.Config) (*gin.Engine, error) {
+	switch cfg.GinMode {
+	case gin.ReleaseMode, gin.DebugMode, gin.TestMode:
+		gin.SetMode(cfg.GinMode)
+	default:
+		return nil, fmt.Errorf("unknown GIN_MODE %q", cfg.GinMode)
+	}
+
+	engine := gin.New()
+	if len(cfg.TrustedProxies) > 0 {
+		if err := engine.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+			return nil, fmt.Errorf("TRUSTED_PROXIES: %w", err)
+		}
+	}
+	if platform := cfg.TrustedPlatform; platform != "" {
+		if header, ok := trustedPlatforms[platform]; ok {
+			platform = header
+		}
+		engine.TrustedPlatform = platform
+	}
+	return engine, nil
+}
 
-	instructorRepo := repository.NewInstructorRepository(pool)
+// setupRouter registers the middleware and routes on router, which comes
+// from newEngine, or from gin.New in tests.
+func setupRouter(router *gin.Engine, db *repository.ResilientDB, cfg *config.Config, bg *background) *gin.Engine {
+	metricsRegistry := metrics.NewRegistry()
+	businessMetrics := metrics.NewBusiness(metricsRegistry)
+	metricsHandler := handlers.NewMetricsHandler(metricsRegistry)
+	httpMetrics := metrics.NewHTTP(metricsRegistry)
+	if bg.pool != nil {
+		metrics.NewPool(metricsRegistry, func() metrics.PoolStats { return bg.pool.Stat() })
+	}
+
+	bg.cache.WithObserver(metrics.NewCache(metricsRegistry))
+	courseRepo := repository.NewCachedCourseRepository(repository.NewCourseRepository(db), bg.cache)
+	sectionActivityRepo := repository.NewSectionActivityRepository(db)
+	sectionRepo := repository.NewCachedSectionRepository(repository.NewSectionRepository(db, sectionActivityRepo), bg.cache)
+	offeringRepo := repository.NewOfferingRepository(db)
+	offeringHandler := handlers.NewOfferingHandler(offeringRepo, bg.offerings)
+	var instructorRepo repository.InstructorRepositoryInterface = repository.NewCachedInstructorRepository(repository.NewInstructorRepository(db), bg.cache)
+	photoLibrary := newPhotoLibrary(cfg)
+	instructorPhotoHandler := handlers.NewInstructorPhotoHandler(instructorRepo, bg.cache, repository.NewAuditRepository(db))
+	if photoLibrary != nil {
+		instructorRepo = repository.NewPhotoInstructorRepository(instructorRepo, photoLibrary)
+		instructorPhotoHandler.WithLibrary(photoLibrary)
+	}
 	instructorHandler := handlers.NewInstructorHandler(instructorRepo)
+	instructorReviewHandler := handlers.NewInstructorReviewHandler(repository.NewInstructorReviewRepository(db), instructorRepo).
+		WithContentFilter(bg.contentFilter)
+	instructorClaimHandler := handlers.NewInstructorClaimHandler(nil)
+	if tokens := newAdminTokens(cfg); tokens != nil {
+		instructorClaimHandler = handlers.NewInstructorClaimHandler(claims.New(repository.NewInstructorClaimRepository(db), bg.mailer, tokens,
+			cfg.InstructorClaimVerifyURL, cfg.InstructorClaimTTL, cfg.InstructorTokenTTL, cfg.InstructorClaimDomains))
+	}
+
+	liteRepo := repository.NewLiteRepository(db)
+	termRepo := repository.NewTermRepository(db)
+	filterPresetRepo := repository.NewFilterPresetRepository(db)
+	filterPresetHandler := handlers.NewFilterPresetHandler(filterPresetRepo)
+	courseHandler := handlers.NewCourseHandler(courseRepo, sectionRepo).
+		WithSearchRecorder(bg.searchRecorder).
+		WithMetrics(businessMetrics).
+		WithOfferingHistory(offeringRepo).
+		WithInstructors(instructorRepo).
+		WithCourseStats(liteRepo, cfg.ReviewStatsWindow).
+		WithFeed(feed.NewService(repository.NewFeedRepository(db), courseRepo, cfg.CourseSeenTTL)).
+		WithDetails(repository.NewCourseDetailRepository(db, instructorRepo)).
+		WithSearch(search.NewService(courseRepo)).
+		WithPresets(filterPresetRepo).
+		WithDeadlines(termRepo)
+	sessions := newSessionSigner(cfg)
+	if sessions != nil {
+		courseHandler.WithSessionShuffle(repository.NewCourseRepository(db))
+	}
+	sessionHandler := handlers.NewSessionHandler(repository.NewSessionRepository(db), sessions, cfg.SessionCookieSecure)
 
 	sectionHandler := handlers.NewSectionHandler(sectionRepo)
+	availabilityRepo := repository.NewAvailabilityRepository(db)
+	availabilityHandler := handlers.NewAvailabilityHandler(availabilityRepo)
+	waitlistHandler := handlers.NewWaitlistHandler(waitlist.NewEstimator(availabilityRepo))
 
-	reviewRepo := repository.NewReviewRepository(pool)
-	reviewHandler := handlers.NewReviewHandler(reviewRepo)
+	blockRepo := repository.NewBlockRepository(db)
+	blockHandler := handlers.NewBlockHandler(blockRepo)
 
-	router := gin.Default()
+	scheduleHandler := handlers.NewScheduleHandler(courseRepo, sectionRepo).
+		WithBlocks(blockRepo).
+		WithMetrics(businessMetrics).
+		WithImages(sectionActivityRepo, render.NewRenderer()).
+		WithCalendar(sectionActivityRepo)
+	savedScheduleHandler := handlers.NewSavedScheduleHandler(repository.NewSavedScheduleRepository(db), sectionActivityRepo, bg.mailer, cfg.CalendarFeedURL).
+		WithSwaps(repository.NewSectionRepository(db, sectionActivityRepo))
+
+	requisiteRepo := repository.NewRequisiteRepository(db)
+	requisiteHandler := handlers.NewRequisiteHandler(requisiteRepo, courseRepo).
+		WithTransfers(repository.NewTransferRepository(db))
 
 	// Add rate limiting to protect the server (0.5 CPU, 512MB RAM)
-	// Conservative limit: 100 requests per minute per IP
-	rateLimiter := middleware.NewRateLimiter(100, 1*time.Minute)
+	// Conservative default: 100 requests per minute per IP, adjustable via config reload
+	tunables := bg.reloader.Current()
+	rateLimiter := middleware.NewRateLimiter(tunables.RateLimit, tunables.RateLimitWindow).
+		Exempt("/api/v1/lite").
+		WithObserver(httpMetrics, "default")
+	// Load tests and partners send a signed token to run above the limit
+	exemptionHandler := handlers.NewExemptionHandler(repository.NewAuditRepository(db))
+	if exemptions := newExemptionSigner(cfg); exemptions != nil {
+		rateLimiter.WithExemptions(exemptions)
+		exemptionHandler.WithIssuer(exemptions)
+	}
+	// The browser extension fetches on every enrollment page view, so it gets its own tier
+	liteLimiter := middleware.NewRateLimiter(tunables.LiteRateLimit, tunables.LiteRateLimitWindow).WithObserver(httpMetrics, "lite")
+
+	termHandler := handlers.NewTermHandler(termRepo)
+
+	badgeRepo := repository.NewBadgeRepository(db)
+	badgeHandler := handlers.NewBadgeHandler(badgeRepo, bg.badges)
+
+	moderationRepo := repository.NewModerationRepository(db)
+	moderationHandler := handlers.NewModerationHandler(moderationRepo)
+
+	reviewRepo := repository.NewReviewRepository(db)
+	reviewEventRepo := repository.NewReviewEventRepository(db)
+	reviewHandler := handlers.NewReviewHandler(reviewRepo).
+		WithRateQuota(rateLimiter).
+		WithStatsWindow(cfg.ReviewStatsWindow).
+		WithEmbargo(termRepo, bg.reloader).
+		WithBadges(badgeRepo).
+		WithEvents(reviewEventRepo).
+		WithMetrics(businessMetrics).
+		WithCalibration(repository.NewCalibrationRepository(db)).
+		WithModeration(moderation.NewModerator(moderationRepo, cfg.ModerationBlockedWords)).
+		WithContentFilter(bg.contentFilter).
+		WithVerification(verification.New(repository.NewReviewVerificationRepository(db), bg.mailer, cfg.ReviewVerifyURL, cfg.ReviewVerificationTTL))
+
+	reviewVoteHandler := handlers.NewReviewVoteHandler(repository.NewReviewVoteRepository(db))
+
+	reviewKeywordRepo := repository.NewReviewKeywordRepository(db)
+	reviewKeywordHandler := handlers.NewReviewKeywordHandler(reviewKeywordRepo, bg.keywords)
+
+	metaHandler := handlers.NewMetaHandler().WithTunables(bg.reloader)
+
+	exportHandler := handlers.NewExportHandler(nil)
+	if bg.exporter != nil {
+		exportHandler = handlers.NewExportHandler(bg.exporter)
+	}
+
+	searchStatsRepo := repository.NewSearchStatsRepository(db)
+	reportRepo := repository.NewReportRepository(db)
+	analyticsHandler := handlers.NewAnalyticsHandler(searchStatsRepo).WithReviewFunnel(reviewEventRepo).WithReportTrends(reportRepo)
+
+	configHandler := handlers.NewConfigHandler(bg.reloader)
+
+	cacheHandler := handlers.NewCacheHandler(bg.cache)
+
+	jobsHandler := handlers.NewJobsHandler(bg.locker)
+
+	transferRepo := repository.NewTransferRepository(db)
+	transferHandler := handlers.NewTransferHandler(transferRepo)
+
+	quarantineRepo := repository.NewQuarantineRepository(db)
+	quarantineHandler := handlers.NewQuarantineHandler(quarantineRepo)
+
+	dataQualityHandler := handlers.NewDataQualityHandler(repository.NewDataQualityRepository(db))
+
+	subscriptionHandler := handlers.NewSubscriptionHandler(repository.NewDigestRepository(db))
+	seatWatchHandler := handlers.NewSeatWatchHandler(repository.NewSeatWatchRepository(db))
+
+	reportHandler := handlers.NewReportHandler(reports.NewService(reportRepo, bg.reloader, newReportNotifier(cfg, bg.mailer)))
+
+	retentionHandler := handlers.NewRetentionHandler(bg.retention)
+
+	syncHandler := handlers.NewSyncHandler(repository.NewSyncRepository(db))
+	if bg.catalogSync != nil {
+		syncHandler.WithSyncer(bg.catalogSync)
+	}
+
+	courseEditHandler := handlers.NewCourseEditHandler(repository.NewCourseRepository(db), bg.cache)
+
+	liteHandler := handlers.NewLiteHandler(liteRepo).WithStatsWindow(cfg.ReviewStatsWindow)
+
+	departmentRepo := repository.NewCachedDepartmentRepository(repository.NewDepartmentRepository(db), bg.cache)
+	departmentHandler := handlers.NewDepartmentHandler(departmentRepo).WithStatsWindow(cfg.ReviewStatsWindow)
+
+	publicStatsRepo := repository.NewPublicStatsRepository(db)
+	publicStatsHandler := handlers.NewPublicStatsHandler(publicStatsRepo)
+
+	// Probes, the scrape endpoint and locally stored photos are registered
+	// before the middleware so rate limiting, load shedding, maintenance mode
+	// and the access log never see them
+	healthHandler := handlers.NewHealthHandler(bg.pool).WithCache(bg.cache)
+	router.GET("/healthz", healthHandler.Live)
+	router.GET("/readyz", healthHandler.Ready)
+	router.GET("/metrics", middleware.RequireBearerToken(cfg.MetricsToken), metricsHandler.GetMetrics)
+	if cfg.PhotoStore == "file" {
+		router.Static("/photos", cfg.PhotoDir)
+	}
+
+	// The request ID comes first so the access log and any error logged while
+	// serving the request carry it
+	router.Use(middleware.RequestID(), middleware.Instrument(httpMetrics))
+	router.Use(middleware.AccessLog(slog.Default(), func() string { return bg.reloader.Current().LogLevel }), middleware.Recovery(slog.Default()))
+	// Admins see fields such as reviewer emails on every route; see internal/redact
+	router.Use(middleware.CallerRole(cfg.AdminAPIKey, newAdminTokens(cfg)))
+
+	// Before rate limiting so preflights are answered even for limited clients
+	router.Use(middleware.CORS("/api/v1/lite", cfg.LiteCORSOrigins))
 	router.Use(rateLimiter.Limit())
 
+	// Track latency/concurrency for every request; low-priority routes opt into shedding
+	loadShedder := middleware.NewLoadShedder(tunables.LoadShedMaxInFlight, tunables.LoadShedTargetP99)
+	router.Use(loadShedder.Track())
+
+	router.Use(middleware.Maintenance(func() bool { return bg.reloader.Current().MaintenanceMode }))
+	router.Use(middleware.ClientVersion(func() map[string]string { return bg.reloader.Current().MinClientVersions }))
+	// Every :id, :course_id and :review_id in the routes below is a row UUID
+	router.Use(middleware.UUIDParams("id", "course_id", "review_id"))
+	if sessions != nil {
+		router.Use(middleware.AnonymousSession(sessions))
+	}
+
+	bg.reloader.OnChange(func(t config.Tunables) {
+		rateLimiter.SetLimit(t.RateLimit, t.RateLimitWindow)
+		liteLimiter.SetLimit(t.LiteRateLimit, t.LiteRateLimitWindow)
+		loadShedder.SetLimits(t.LoadShedMaxInFlight, t.LoadShedTargetP99)
+	})
+
+	// Answers OPTIONS with the methods a path allows; HEAD is handled around
+	// the engine by HeadAsGet, see main
+	router.NoRoute(middleware.Options(router.Routes))
+
 	api := router.Group("/api/v1")
+	if sessions != nil {
+		api.POST("/session", sessionHandler.StartSession)
+		api.DELETE("/session", sessionHandler.EndSession)
+		api.GET("/session/recent", sessionHandler.GetRecentViews)
+		api.POST("/session/recent", sessionHandler.RecordRecentView)
+		api.GET("/session/drafts", sessionHandler.ListDrafts)
+		api.GET("/session/drafts/:key", sessionHandler.GetDraft)
+		api.PUT("/session/drafts/:key", sessionHandler.SaveDraft)
+		api.DELETE("/session/drafts/:key", sessionHandler.DeleteDraft)
+	}
 	{
 		api.GET("/courses", courseHandler.GetCourses)
 		api.GET("/courses/paginated", courseHandler.GetPaginatedCourses)
+		api.GET("/courses/all", loadShedder.Shed(), courseHandler.GetAllCourses)
 		api.GET("/courses/search", courseHandler.SearchCourses)
+		api.POST("/courses/seen", courseHandler.RecordSeen)
 		api.GET("/courses/:course_code", courseHandler.GetCoursesByCode)
+		api.GET("/courses/:course_code/full", courseHandler.GetCourseFull) // :course_code is the course id; see the handler
+		api.GET("/instructors", instructorHandler.ListInstructors)
 		api.GET("/instructors/:course_id", instructorHandler.GetInstructorsByCourseID)
+		api.GET("/instructors/:course_id/profile", instructorHandler.GetInstructorProfile)   // :course_id is the instructor id; see the handler
+		api.GET("/instructors/:course_id/schedule", instructorHandler.GetInstructorSchedule) // likewise
+		api.GET("/instructors/:course_id/courses", instructorHandler.GetInstructorCourses)   // likewise
+		api.GET("/instructors/:course_id/reviews", instructorReviewHandler.GetReviews)       // likewise
+		api.POST("/instructors/:course_id/reviews", requireCaptcha(bg, config.FlagCaptchaReviews), instructorReviewHandler.CreateReview)
+		api.POST("/instructors/:course_id/claims", instructorClaimHandler.RequestClaim) // likewise
+		api.GET("/instructor-claims/verify", instructorClaimHandler.VerifyClaim)
 		api.GET("/sections/:course_id", sectionHandler.GetSectionsByCourseID)
+		api.GET("/sections/:course_id/availability", availabilityHandler.GetAvailability)
+		api.GET("/sections/:course_id/waitlist-odds", waitlistHandler.GetWaitlistOdds)
+		api.GET("/blocks/:course_id", blockHandler.GetBlocksByCourseID)
+		api.POST("/schedules/generate", loadShedder.Shed(), scheduleHandler.GenerateSchedules)
+		api.POST("/schedules/export.png", loadShedder.Shed(), scheduleHandler.ExportPNG)
+		api.POST("/schedules/:id/swap-suggestions", savedScheduleHandler.SuggestSwaps)
+		api.GET("/export/ical", scheduleHandler.ExportICal)
 
 		// Review endpoints
 		api.GET("/reviews", reviewHandler.GetAllReviews)
+		api.GET("/courses/:course_code/offering", offeringHandler.GetOffering)
+		api.GET("/courses/:course_code/prerequisites", requisiteHandler.GetPrerequisites)
 		api.GET("/courses/:course_code/reviews", reviewHandler.GetReviews)
-		api.POST("/courses/:course_code/reviews", reviewHandler.CreateReview)
+		api.GET("/courses/:course_code/reviews/keywords", reviewKeywordHandler.GetKeywords)
+		api.GET("/courses/:course_code/reviews/eligibility", reviewHandler.GetReviewEligibility)
+		api.GET("/courses/:course_code/reviews/mine", reviewHandler.GetOwnReview)
+		api.POST("/courses/:course_code/reviews", requireCaptcha(bg, config.FlagCaptchaReviews), reviewHandler.CreateReview)
+		api.PUT("/courses/:course_code/reviews/:review_id", reviewHandler.UpdateReview)
+		api.DELETE("/courses/:course_code/reviews/:review_id", reviewHandler.DeleteReview)
+		api.POST("/courses/:course_code/reviews/:review_id/verification", reviewHandler.ResendVerification)
+		api.GET("/reviews/verify", reviewHandler.VerifyReview)
+		api.POST("/reviews/:review_id/vote", reviewVoteHandler.Vote)
+		api.GET("/reviews/:review_id/history", reviewHandler.GetReviewHistory)
+		api.POST("/reports", requireCaptcha(bg, config.FlagCaptchaReports), reportHandler.CreateReport)
+		api.GET("/badges", badgeHandler.ListBadges)
+
+		// Department catalog digests
+		api.GET("/subscriptions", subscriptionHandler.GetSubscriptions)
+		api.POST("/subscriptions", subscriptionHandler.Subscribe)
+		api.PUT("/subscriptions/frequency", subscriptionHandler.SetFrequency)
+		api.DELETE("/subscriptions/:department", subscriptionHandler.Unsubscribe)
+		api.GET("/watches", seatWatchHandler.ListWatches)
+		api.POST("/watches", seatWatchHandler.CreateWatch)
+		api.DELETE("/watches/:id", seatWatchHandler.DeleteWatch)
+		api.GET("/users/me/filters", filterPresetHandler.GetPresets)
+		api.POST("/users/me/filters", filterPresetHandler.CreatePreset)
+		api.PUT("/users/me/filters/:id", filterPresetHandler.UpdatePreset)
+		api.DELETE("/users/me/filters/:id", filterPresetHandler.DeletePreset)
+		api.GET("/users/me/schedules", savedScheduleHandler.GetSchedules)
+		api.POST("/users/me/schedules", savedScheduleHandler.CreateSchedule)
+		api.PUT("/users/me/schedules/:id", savedScheduleHandler.UpdateSchedule)
+		api.DELETE("/users/me/schedules/:id", savedScheduleHandler.DeleteSchedule)
+		api.POST("/users/me/schedules/:id/calendar-token", savedScheduleHandler.IssueCalendarToken)
+		api.DELETE("/users/me/schedules/:id/calendar-token", savedScheduleHandler.RevokeCalendarToken)
+		api.GET("/users/me/schedules/:id/calendar.ics", savedScheduleHandler.GetCalendarFeed)
+		api.GET("/users/me/readiness/:course_code", requisiteHandler.GetReadiness)
+
+		// Transfer credit equivalencies
+		api.POST("/transfer/evaluate", transferHandler.Evaluate)
+
+		// Shared enumerations for clients
+		api.GET("/terms", termHandler.ListSessions)
+		api.GET("/meta/enums", metaHandler.GetEnums)
+		api.GET("/meta/client", metaHandler.GetClient)
+
+		// Landing and department page stats
+		api.GET("/stats/public", publicStatsHandler.GetPublicStats)
+		api.GET("/departments/:department/stats", departmentHandler.GetStats)
+	}
+
+	lite := api.Group("/lite")
+	lite.Use(liteLimiter.Limit())
+	{
+		lite.GET("/courses", liteHandler.GetCourses)
+		lite.GET("/courses/:course_code", liteHandler.GetCourse)
+	}
+
+	admin := api.Group("/admin")
+	if cfg.AdminAPIKey == "" && cfg.AdminJWTSecret == "" {
+		// Nobody could ever authenticate, so say so rather than answer 401
+		admin.Use(middleware.Disabled("Admin API is disabled"))
+	}
+	admin.Use(middleware.RequireRole(redact.RoleAdmin))
+	{
+		admin.GET("/exports", exportHandler.ListExports)
+		admin.POST("/exports", loadShedder.Shed(), exportHandler.TriggerExport)
+		admin.GET("/analytics/searches", loadShedder.Shed(), analyticsHandler.GetSearchAnalytics)
+		admin.GET("/analytics/reviews", loadShedder.Shed(), analyticsHandler.GetReviewAnalytics)
+		admin.GET("/analytics/reports", loadShedder.Shed(), analyticsHandler.GetReportAnalytics)
+		admin.GET("/metrics", metricsHandler.GetMetrics)
+		admin.GET("/jobs/locks", jobsHandler.GetLockStats)
+		admin.GET("/config", configHandler.GetConfig)
+		admin.POST("/config/reload", configHandler.ReloadConfig)
+		admin.PUT("/config/flags/:flag", configHandler.SetFeatureFlag)
+		admin.POST("/cache/invalidate", cacheHandler.InvalidateCache)
+		admin.POST("/rate-limit/exemptions", exemptionHandler.IssueExemption)
+		admin.GET("/transfer/equivalencies", transferHandler.ListEquivalencies)
+		admin.POST("/transfer/equivalencies", transferHandler.UpsertEquivalency)
+		admin.DELETE("/transfer/equivalencies/:id", transferHandler.DeleteEquivalency)
+		admin.POST("/offerings/refresh", offeringHandler.RefreshOfferings)
+		admin.POST("/reviews/keywords/refresh", reviewKeywordHandler.RefreshKeywords)
+		admin.GET("/reviews/embargoed", reviewHandler.ListEmbargoedReviews)
+		admin.POST("/reviews/:id/publish", reviewHandler.PublishReview)
+		admin.POST("/reviews/:id/embargo", reviewHandler.EmbargoReview)
+		admin.GET("/moderation/rules", moderationHandler.GetRules)
+		admin.PUT("/moderation/rules", moderationHandler.SetRules)
+		admin.POST("/badges", badgeHandler.CreateBadge)
+		admin.DELETE("/badges/:slug", badgeHandler.DeleteBadge)
+		admin.POST("/badges/refresh", badgeHandler.RefreshBadges)
+		admin.POST("/courses", courseEditHandler.CreateCourse)
+		admin.PATCH("/courses/:id", courseEditHandler.PatchCourse)
+		admin.DELETE("/courses/:id", courseEditHandler.DeleteCourse)
+		admin.POST("/courses/:id/merge", courseEditHandler.MergeCourse)
+		admin.PUT("/blocks/:course_id/:name", blockHandler.SetBlock)
+		admin.DELETE("/blocks/:course_id/:name", blockHandler.DeleteBlock)
+		admin.PUT("/courses/:course_code/requisites", requisiteHandler.SetRequisites)
+		admin.PUT("/instructors/:id/photo", instructorPhotoHandler.UploadPhoto)
+		admin.DELETE("/instructors/:id/photo", instructorPhotoHandler.DeletePhoto)
+		admin.GET("/terms", termHandler.ListTerms)
+		admin.PUT("/terms/:academic_year/:term", termHandler.UpsertTerm)
+		admin.GET("/data-quality", dataQualityHandler.GetDataQuality)
+		admin.PUT("/availability", availabilityHandler.UpdateAvailability)
+		admin.GET("/quarantine", quarantineHandler.ListQuarantine)
+		admin.GET("/quarantine/:id", quarantineHandler.GetQuarantined)
+		admin.POST("/quarantine/:id/reprocess", quarantineHandler.ReprocessQuarantined)
+		admin.POST("/quarantine/:id/dismiss", quarantineHandler.DismissQuarantined)
+		admin.GET("/reports", reportHandler.ListReports)
+		admin.POST("/reports/:id/resolve", reportHandler.ResolveReport)
+		admin.POST("/reports/:id/dismiss", reportHandler.DismissReport)
+		admin.GET("/instructor-claims", instructorClaimHandler.ListClaims)
+		admin.POST("/instructor-claims/:id/approve", instructorClaimHandler.ApproveClaim)
+		admin.POST("/instructor-claims/:id/reject", instructorClaimHandler.RejectClaim)
+		admin.GET("/retention", retentionHandler.GetRetention)
+		admin.POST("/retention/run", loadShedder.Shed(), retentionHandler.RunRetention)
+		admin.GET("/syncs", syncHandler.ListSyncs)
+		admin.POST("/syncs", syncHandler.TriggerSync)
 	}
 	return router
 }
 
-func startServer(router *gin.Engine, port string) error {
-	log.Printf("Starting server on port %s", port)
-	if err := router.Run(":" + port); err != nil {
+// newServer serves handler on cfg.Port with the configured timeouts.
+func newServer(handler http.Handler, cfg *config.Config) *http.Server {
+	return &http.Server{
+		Addr:         ":" + cfg.Port,
+		Handler:      handler,
+		ReadTimeout:  cfg.HTTPReadTimeout,
+		WriteTimeout: cfg.HTTPWriteTimeout,
+		IdleTimeout:  cfg.HTTPIdleTimeout,
+	}
+}
+
+// startServer listens on srv.Addr and serves until ctx is done.
+func startServer(ctx context.Context, srv *http.Server, drainTimeout time.Duration) error {
+	ln, err := net.Listen("tcp", srv.Addr)
+	if err != nil {
 		return err
 	}
+	log.Printf("Starting server on %s", ln.Addr())
+	return serve(ctx, srv, ln, drainTimeout)
+}
+
+// serve runs srv on ln until ctx is done, then stops accepting connections
+// and waits up to drainTimeout for in-flight requests to finish. Requests
+// still running after that have their connections closed.
+func serve(ctx context.Context, srv *http.Server, ln net.Listener, drainTimeout time.Duration) error {
+	errs := make(chan error, 1)
+	go func() { errs <- srv.Serve(ln) }()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	log.Printf("Shutting down, waiting up to %s for in-flight requests", drainTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		srv.Close()
+		return fmt.Errorf("drain connections: %w", err)
+	}
 	return nil
 }
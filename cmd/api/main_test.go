@@ -2,17 +2,26 @@ package main
 
 import (
 	"context"
+	"io"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
+	"yuplan/internal/cache"
+	"yuplan/internal/config"
 
 	"github.com/gin-gonic/gin"
+	"github.com/pashagolub/pgxmock"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestSetupRouter_RegistersCourseRoutes(t *testing.T) {
 	// Passing nil is OK here: setupRouter only wires dependencies.
 	// We won't execute any handlers that require a real database.
-	r := setupRouter(nil)
+	cfg := &config.Config{Tunables: config.DefaultTunables()}
+	r := setupRouter(gin.New(), nil, cfg, newBackground(cfg, nil, nil))
 
 	routes := r.Routes()
 	assert.NotEmpty(t, routes)
@@ -22,9 +31,165 @@ func TestSetupRouter_RegistersCourseRoutes(t *testing.T) {
 		seen[rt.Method+" "+rt.Path] = true
 	}
 
+	assert.True(t, seen[http.MethodGet+" /healthz"], "expected GET /healthz route")
+	assert.True(t, seen[http.MethodGet+" /readyz"], "expected GET /readyz route")
+	assert.True(t, seen[http.MethodGet+" /metrics"], "expected GET /metrics route")
 	assert.True(t, seen[http.MethodGet+" /api/v1/courses"], "expected GET /api/v1/courses route")
+	assert.True(t, seen[http.MethodGet+" /api/v1/courses/all"], "expected GET /api/v1/courses/all route")
 	assert.True(t, seen[http.MethodGet+" /api/v1/courses/search"], "expected GET /api/v1/courses/search route")
 	assert.True(t, seen[http.MethodGet+" /api/v1/courses/:course_code"], "expected GET /api/v1/courses/:course_code route")
+	assert.True(t, seen[http.MethodGet+" /api/v1/courses/:course_code/full"], "expected GET /api/v1/courses/:course_code/full route")
+	assert.True(t, seen[http.MethodPost+" /api/v1/courses/seen"], "expected POST /api/v1/courses/seen route")
+	assert.True(t, seen[http.MethodPost+" /api/v1/admin/exports"], "expected POST /api/v1/admin/exports route")
+	assert.True(t, seen[http.MethodGet+" /api/v1/admin/analytics/searches"], "expected GET /api/v1/admin/analytics/searches route")
+	assert.True(t, seen[http.MethodGet+" /api/v1/admin/analytics/reviews"], "expected GET /api/v1/admin/analytics/reviews route")
+	assert.True(t, seen[http.MethodGet+" /api/v1/admin/analytics/reports"], "expected GET /api/v1/admin/analytics/reports route")
+	assert.True(t, seen[http.MethodGet+" /api/v1/admin/metrics"], "expected GET /api/v1/admin/metrics route")
+	assert.True(t, seen[http.MethodPost+" /api/v1/transfer/evaluate"], "expected POST /api/v1/transfer/evaluate route")
+	assert.True(t, seen[http.MethodGet+" /api/v1/stats/public"], "expected GET /api/v1/stats/public route")
+	assert.True(t, seen[http.MethodGet+" /api/v1/departments/:department/stats"], "expected GET /api/v1/departments/:department/stats route")
+	assert.True(t, seen[http.MethodGet+" /api/v1/instructors/:course_id/schedule"], "expected GET /api/v1/instructors/:course_id/schedule route")
+	assert.True(t, seen[http.MethodGet+" /api/v1/terms"], "expected GET /api/v1/terms route")
+	assert.True(t, seen[http.MethodPost+" /api/v1/schedules/export.png"], "expected POST /api/v1/schedules/export.png route")
+	assert.True(t, seen[http.MethodGet+" /api/v1/export/ical"], "expected GET /api/v1/export/ical route")
+	assert.True(t, seen[http.MethodPost+" /api/v1/admin/cache/invalidate"], "expected POST /api/v1/admin/cache/invalidate route")
+	assert.True(t, seen[http.MethodPost+" /api/v1/admin/rate-limit/exemptions"], "expected POST /api/v1/admin/rate-limit/exemptions route")
+	assert.True(t, seen[http.MethodGet+" /api/v1/instructors/:course_id/courses"], "expected GET /api/v1/instructors/:course_id/courses route")
+	assert.True(t, seen[http.MethodGet+" /api/v1/instructors/:course_id/profile"], "expected GET /api/v1/instructors/:course_id/profile route")
+	assert.True(t, seen[http.MethodPost+" /api/v1/admin/retention/run"], "expected POST /api/v1/admin/retention/run route")
+	assert.True(t, seen[http.MethodPost+" /api/v1/admin/config/reload"], "expected POST /api/v1/admin/config/reload route")
+	assert.True(t, seen[http.MethodPost+" /api/v1/admin/quarantine/:id/reprocess"], "expected POST /api/v1/admin/quarantine/:id/reprocess route")
+	assert.True(t, seen[http.MethodGet+" /api/v1/courses/:course_code/reviews/keywords"], "expected GET /api/v1/courses/:course_code/reviews/keywords route")
+	assert.True(t, seen[http.MethodGet+" /api/v1/courses/:course_code/reviews/mine"], "expected GET /api/v1/courses/:course_code/reviews/mine route")
+	assert.True(t, seen[http.MethodPut+" /api/v1/courses/:course_code/reviews/:review_id"], "expected PUT /api/v1/courses/:course_code/reviews/:review_id route")
+	assert.True(t, seen[http.MethodDelete+" /api/v1/courses/:course_code/reviews/:review_id"], "expected DELETE /api/v1/courses/:course_code/reviews/:review_id route")
+	assert.True(t, seen[http.MethodPost+" /api/v1/admin/reviews/:id/publish"], "expected POST /api/v1/admin/reviews/:id/publish route")
+	assert.True(t, seen[http.MethodPut+" /api/v1/admin/moderation/rules"], "expected PUT /api/v1/admin/moderation/rules route")
+	assert.True(t, seen[http.MethodGet+" /api/v1/badges"], "expected GET /api/v1/badges route")
+	assert.True(t, seen[http.MethodGet+" /api/v1/lite/courses"], "expected GET /api/v1/lite/courses route")
+	assert.True(t, seen[http.MethodGet+" /api/v1/lite/courses/:course_code"], "expected GET /api/v1/lite/courses/:course_code route")
+	assert.True(t, seen[http.MethodPost+" /api/v1/admin/badges/refresh"], "expected POST /api/v1/admin/badges/refresh route")
+	assert.True(t, seen[http.MethodPut+" /api/v1/admin/terms/:academic_year/:term"], "expected PUT /api/v1/admin/terms/:academic_year/:term route")
+	assert.True(t, seen[http.MethodGet+" /api/v1/courses/:course_code/offering"], "expected GET /api/v1/courses/:course_code/offering route")
+	assert.True(t, seen[http.MethodGet+" /api/v1/courses/:course_code/prerequisites"], "expected GET /api/v1/courses/:course_code/prerequisites route")
+	assert.True(t, seen[http.MethodPut+" /api/v1/admin/courses/:course_code/requisites"], "expected PUT /api/v1/admin/courses/:course_code/requisites route")
+	assert.True(t, seen[http.MethodPatch+" /api/v1/admin/courses/:id"], "expected PATCH /api/v1/admin/courses/:id route")
+	assert.True(t, seen[http.MethodPost+" /api/v1/admin/courses"], "expected POST /api/v1/admin/courses route")
+	assert.True(t, seen[http.MethodDelete+" /api/v1/admin/courses/:id"], "expected DELETE /api/v1/admin/courses/:id route")
+	assert.True(t, seen[http.MethodPost+" /api/v1/admin/syncs"], "expected POST /api/v1/admin/syncs route")
+	assert.True(t, seen[http.MethodPost+" /api/v1/schedules/generate"], "expected POST /api/v1/schedules/generate route")
+	assert.True(t, seen[http.MethodPost+" /api/v1/schedules/:id/swap-suggestions"], "expected POST /api/v1/schedules/:id/swap-suggestions route")
+	assert.True(t, seen[http.MethodPost+" /api/v1/subscriptions"], "expected POST /api/v1/subscriptions route")
+	assert.True(t, seen[http.MethodDelete+" /api/v1/subscriptions/:department"], "expected DELETE /api/v1/subscriptions/:department route")
+	assert.True(t, seen[http.MethodPost+" /api/v1/users/me/filters"], "expected POST /api/v1/users/me/filters route")
+	assert.True(t, seen[http.MethodPut+" /api/v1/users/me/filters/:id"], "expected PUT /api/v1/users/me/filters/:id route")
+	assert.True(t, seen[http.MethodPost+" /api/v1/users/me/schedules"], "expected POST /api/v1/users/me/schedules route")
+	assert.True(t, seen[http.MethodPost+" /api/v1/users/me/schedules/:id/calendar-token"], "expected POST /api/v1/users/me/schedules/:id/calendar-token route")
+	assert.True(t, seen[http.MethodGet+" /api/v1/users/me/schedules/:id/calendar.ics"], "expected GET /api/v1/users/me/schedules/:id/calendar.ics route")
+	assert.True(t, seen[http.MethodGet+" /api/v1/users/me/readiness/:course_code"], "expected GET /api/v1/users/me/readiness/:course_code route")
+	assert.True(t, seen[http.MethodPost+" /api/v1/reports"], "expected POST /api/v1/reports route")
+	assert.True(t, seen[http.MethodPost+" /api/v1/admin/reports/:id/resolve"], "expected POST /api/v1/admin/reports/:id/resolve route")
+	assert.True(t, seen[http.MethodPost+" /api/v1/instructors/:course_id/claims"], "expected POST /api/v1/instructors/:course_id/claims route")
+	assert.True(t, seen[http.MethodGet+" /api/v1/instructor-claims/verify"], "expected GET /api/v1/instructor-claims/verify route")
+	assert.True(t, seen[http.MethodPost+" /api/v1/admin/instructor-claims/:id/approve"], "expected POST /api/v1/admin/instructor-claims/:id/approve route")
+}
+
+func TestSetupRouter_AnswersOptions(t *testing.T) {
+	cfg := &config.Config{Tunables: config.DefaultTunables()}
+	r := setupRouter(gin.New(), nil, cfg, newBackground(cfg, nil, nil))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodOptions, "/api/v1/courses/EECS2030/reviews", nil))
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "GET, HEAD, OPTIONS, POST", w.Header().Get("Allow"))
+}
+
+func TestSetupRouter_HealthzIsNotRateLimited(t *testing.T) {
+	cfg := &config.Config{Tunables: config.DefaultTunables()}
+	cfg.Tunables.RateLimit = 1
+	r := setupRouter(gin.New(), nil, cfg, newBackground(cfg, nil, nil))
+
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+}
+
+func TestSetupRouter_MetricsScrape(t *testing.T) {
+	cfg := &config.Config{Tunables: config.DefaultTunables(), MetricsToken: "scrape"}
+	r := setupRouter(gin.New(), nil, cfg, newBackground(cfg, nil, nil))
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodOptions, "/api/v1/courses", nil))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Authorization", "Bearer scrape")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `yuplan_http_requests_total{method="OPTIONS",route="unmatched",status="204"} 1`)
+}
+
+func TestNewEngine(t *testing.T) {
+	defer gin.SetMode(gin.TestMode)
+
+	engine, err := newEngine(&config.Config{GinMode: gin.ReleaseMode, TrustedProxies: []string{"10.0.0.0/8"}})
+	assert.NoError(t, err)
+	assert.Equal(t, gin.ReleaseMode, gin.Mode())
+	engine.GET("/ip", func(c *gin.Context) { c.String(http.StatusOK, c.ClientIP()) })
+
+	clientIP := func(remoteAddr string) string {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("X-Forwarded-For", "203.0.113.7")
+		engine.ServeHTTP(w, req)
+		return w.Body.String()
+	}
+	assert.Equal(t, "203.0.113.7", clientIP("10.1.2.3:1234"), "forwarded address from a trusted proxy")
+	assert.Equal(t, "198.51.100.1", clientIP("198.51.100.1:1234"), "forwarded address from anyone else")
+
+	engine, err = newEngine(&config.Config{GinMode: gin.TestMode, TrustedPlatform: "cloudflare"})
+	assert.NoError(t, err)
+	assert.Equal(t, gin.PlatformCloudflare, engine.TrustedPlatform)
+
+	_, err = newEngine(&config.Config{GinMode: "production"})
+	assert.Error(t, err)
+	_, err = newEngine(&config.Config{GinMode: gin.TestMode, TrustedProxies: []string{"not-an-ip"}})
+	assert.Error(t, err)
+}
+
+func TestNewExporter_DisabledWithoutStore(t *testing.T) {
+	assert.Nil(t, newExporter(&config.Config{}, nil))
+	assert.NotNil(t, newExporter(&config.Config{ExportStore: "file", ExportDir: t.TempDir()}, nil))
+}
+
+func TestNewCacheBackend_MemoryWithoutRedis(t *testing.T) {
+	assert.IsType(t, &cache.Memory{}, newCacheBackend(&config.Config{}))
+	assert.IsType(t, &cache.Memory{}, newCacheBackend(&config.Config{RedisURL: "http://localhost"}))
+}
+
+func TestNewExemptionSigner(t *testing.T) {
+	assert.Nil(t, newExemptionSigner(&config.Config{}))
+	assert.NotNil(t, newExemptionSigner(&config.Config{RateLimitExemptionSecret: "secret", RateLimitExemptionMaxTTL: time.Hour, RateLimitExemptionMaxLimit: 100}))
+}
+
+func TestNewCaptchaVerifier(t *testing.T) {
+	verifier, err := newCaptchaVerifier(&config.Config{})
+	assert.NoError(t, err)
+	assert.Nil(t, verifier)
+
+	verifier, err = newCaptchaVerifier(&config.Config{CaptchaProvider: "turnstile", CaptchaSecret: "secret"})
+	assert.NoError(t, err)
+	assert.NotNil(t, verifier)
+
+	_, err = newCaptchaVerifier(&config.Config{CaptchaProvider: "recaptcha", CaptchaSecret: "secret"})
+	assert.Error(t, err)
 }
 
 func TestInitDatabase_InvalidURL_ReturnsError(t *testing.T) {
@@ -33,10 +198,112 @@ func TestInitDatabase_InvalidURL_ReturnsError(t *testing.T) {
 	assert.Nil(t, pool)
 }
 
+func TestRunMigrate_RejectsBadArgs(t *testing.T) {
+	for _, args := range [][]string{{"sideways"}, {"down"}, {"down", "0"}, {"force", "x"}, {"version", "1"}} {
+		err := runMigrate(context.Background(), nil, args, io.Discard)
+		assert.EqualError(t, err, migrateUsage, "%v", args)
+	}
+}
+
+func TestRunMigrate_Version(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SELECT pg_advisory_xact_lock").WillReturnResult(pgxmock.NewResult("SELECT", 1))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(pgxmock.NewResult("CREATE TABLE", 0))
+	mock.ExpectQuery("SELECT version, dirty FROM schema_migrations").
+		WillReturnRows(pgxmock.NewRows([]string{"version", "dirty"}).AddRow(int64(51), true))
+	mock.ExpectCommit()
+
+	var out strings.Builder
+	assert.NoError(t, runMigrate(context.Background(), mock, []string{"version"}, &out))
+	assert.Equal(t, "51 (dirty)\n", out.String())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestStartServer_InvalidPort_ReturnsError(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
 
-	err := startServer(r, "not-a-number")
+	err := startServer(context.Background(), newServer(r, &config.Config{Port: "not-a-number"}), time.Second)
 	assert.Error(t, err)
 }
+
+func TestNewServer_Timeouts(t *testing.T) {
+	srv := newServer(http.NotFoundHandler(), &config.Config{
+		Port:             "8080",
+		HTTPReadTimeout:  15 * time.Second,
+		HTTPWriteTimeout: time.Minute,
+		HTTPIdleTimeout:  2 * time.Minute,
+	})
+	assert.Equal(t, ":8080", srv.Addr)
+	assert.Equal(t, 15*time.Second, srv.ReadTimeout)
+	assert.Equal(t, time.Minute, srv.WriteTimeout)
+	assert.Equal(t, 2*time.Minute, srv.IdleTimeout)
+}
+
+func TestServe_DrainsInFlightRequests(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.Write([]byte("done"))
+	})}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() { served <- serve(ctx, srv, ln, 5*time.Second) }()
+
+	responses := make(chan string, 1)
+	go func() {
+		resp, err := http.Get("http://" + ln.Addr().String())
+		if err != nil {
+			responses <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		responses <- string(body)
+	}()
+
+	<-started
+	cancel()
+	// Shutdown closes the listener before waiting on the request
+	assert.Eventually(t, func() bool {
+		conn, err := net.Dial("tcp", ln.Addr().String())
+		if err == nil {
+			conn.Close()
+		}
+		return err != nil
+	}, time.Second, 5*time.Millisecond)
+
+	close(release)
+	assert.Equal(t, "done", <-responses)
+	assert.NoError(t, <-served)
+}
+
+func TestServe_GivesUpAfterDrainTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	started := make(chan struct{})
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	})}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() { served <- serve(ctx, srv, ln, 50*time.Millisecond) }()
+	go http.Get("http://" + ln.Addr().String())
+
+	<-started
+	cancel()
+	assert.ErrorIs(t, <-served, context.DeadlineExceeded)
+}
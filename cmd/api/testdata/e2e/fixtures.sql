@@ -0,0 +1,29 @@
+-- Catalog the end-to-end tests run against. IDs are fixed so the tests can
+-- address rows directly; the terms come from migration 000033.
+
+INSERT INTO courses (id, name, code, credits, description, faculty, term) VALUES
+('00000000-0000-0000-0000-00000000c001', 'Advanced Object Oriented Programming', 'EECS2030', 3.00, 'Object oriented design with Java: classes, interfaces, inheritance and testing.', 'LE', 'F'),
+('00000000-0000-0000-0000-00000000c002', 'Fundamentals of Data Structures', 'EECS2011', 3.00, 'Lists, trees, hash tables and graphs, with their analysis.', 'LE', 'W'),
+('00000000-0000-0000-0000-00000000c003', 'Differential Calculus with Applications', 'MATH1300', 3.00, 'Limits, derivatives and their applications.', 'SC', 'F');
+
+INSERT INTO sections (id, course_id, letter, term_id, capacity, enrolled) VALUES
+('00000000-0000-0000-0000-00000000a001', '00000000-0000-0000-0000-00000000c001', 'A', 'FW2025', 150, 142),
+('00000000-0000-0000-0000-00000000a002', '00000000-0000-0000-0000-00000000c001', 'B', 'FW2025', 150, 97),
+('00000000-0000-0000-0000-00000000a003', '00000000-0000-0000-0000-00000000c002', 'M', 'FW2025', 200, 200),
+('00000000-0000-0000-0000-00000000a004', '00000000-0000-0000-0000-00000000c003', 'A', 'FW2025', 300, 250);
+
+INSERT INTO section_activities (id, course_type, section_id, catalog_number, times) VALUES
+('00000000-0000-0000-0000-00000000b001', 'LECT', '00000000-0000-0000-0000-00000000a001', 'K12A01', '[{"day": "M", "time": "10:30", "duration": "80", "campus": "Keele", "room": "LAS A"}]'),
+('00000000-0000-0000-0000-00000000b002', 'LAB', '00000000-0000-0000-0000-00000000a001', 'K12A02', '[{"day": "W", "time": "14:30", "duration": "110", "campus": "Keele", "room": "LAS 1006"}]'),
+('00000000-0000-0000-0000-00000000b003', 'LECT', '00000000-0000-0000-0000-00000000a002', 'K12B01', '[{"day": "T", "time": "16:00", "duration": "80", "campus": "Keele", "room": "CLH I"}]'),
+('00000000-0000-0000-0000-00000000b004', 'LECT', '00000000-0000-0000-0000-00000000a003', 'N34M01', '[{"day": "R", "time": "11:30", "duration": "80", "campus": "Keele", "room": "VH A"}]'),
+('00000000-0000-0000-0000-00000000b005', 'LECT', '00000000-0000-0000-0000-00000000a004', 'S56A01', '[{"day": "F", "time": "08:30", "duration": "80", "campus": "Keele", "room": "ACW 109"}]');
+
+INSERT INTO instructors (id, first_name, last_name, rate_my_prof_link, section_id) VALUES
+('00000000-0000-0000-0000-00000000d001', 'Jackie', 'Wang', NULL, '00000000-0000-0000-0000-00000000a001'),
+('00000000-0000-0000-0000-00000000d002', 'Sam', 'Okafor', NULL, '00000000-0000-0000-0000-00000000a002'),
+('00000000-0000-0000-0000-00000000d003', 'Priya', 'Natarajan', NULL, '00000000-0000-0000-0000-00000000a003');
+
+-- One review already published, so listings have something before the journey posts its own
+INSERT INTO reviews (id, course_code, email, author_name, liked, difficulty, real_world_relevance, review_text, academic_year, term, created_at, updated_at) VALUES
+('00000000-0000-0000-0000-00000000e001', 'EECS2030', 'seed@my.yorku.ca', 'Seeded', true, 3, 4, 'Labs every week, but they pay off on the exams.', 2024, 'F', '2025-01-15 12:00:00', '2025-01-15 12:00:00');
@@ -0,0 +1,151 @@
+// Command scraper loads a session's timetable from the York Courses Website
+// into the database, in place of generating and loading db/seed.sql.
+//
+//	scraper [-term FW2025] [-faculties AP,ED,...] [-descriptions FILE] [-record DIR | -replay DIR] [page ...]
+//
+// Pages given as arguments, URLs or saved copies such as those in
+// scraping/page_source, are loaded instead of the faculty pages. -record
+// archives every page fetched into DIR; -replay loads them from there instead
+// of the network, so a recorded catalog can be reloaded offline. The database
+// is DATABASE_URL. Each run is recorded in sync_history alongside the API's
+// scheduled syncs.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+	"yuplan/internal/catalogsync"
+	"yuplan/internal/config"
+	"yuplan/internal/database"
+	"yuplan/internal/logging"
+	"yuplan/internal/models"
+	"yuplan/internal/repository"
+	"yuplan/internal/scraper"
+)
+
+type options struct {
+	term         models.Term
+	baseURL      string
+	faculties    []string
+	descriptions string
+	record       string
+	replay       string
+	pages        []string
+}
+
+func main() {
+	slog.SetDefault(logging.New(os.Stdout))
+
+	opts, err := parseFlags(os.Args[1:], os.Stderr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "scraper: %v\n", err)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	if err := run(ctx, opts); err != nil {
+		slog.Error("scrape failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+func parseFlags(args []string, stderr io.Writer) (options, error) {
+	fs := flag.NewFlagSet("scraper", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	term := fs.String("term", getEnv("SCRAPER_TERM", ""), "session to load, e.g. FW2025 or SU2026")
+	baseURL := fs.String("base-url", getEnv("SCRAPER_BASE_URL", scraper.DefaultBaseURL), "where the faculty timetable pages are published")
+	faculties := fs.String("faculties", getEnv("SCRAPER_FACULTIES", strings.Join(scraper.DefaultFaculties, ",")), "faculty codes whose pages to load")
+	descriptions := fs.String("descriptions", getEnv("SCRAPER_DESCRIPTIONS", ""), "course descriptions JSON, as scraping/scrapers/descriptions writes it")
+	record := fs.String("record", "", "archive fetched pages into this directory")
+	replay := fs.String("replay", "", "load pages from an archive recorded with -record instead of fetching them")
+	if err := fs.Parse(args); err != nil {
+		return options{}, err
+	}
+	if *record != "" && *replay != "" {
+		return options{}, fmt.Errorf("-record and -replay can't be used together")
+	}
+
+	t, ok := models.NewTerm(*term)
+	if !ok {
+		return options{}, fmt.Errorf("-term must be a session code and year such as FW2025, got %q", *term)
+	}
+	opts := options{term: t, baseURL: *baseURL, descriptions: *descriptions, record: *record, replay: *replay, pages: fs.Args()}
+	for _, f := range strings.Split(*faculties, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			opts.faculties = append(opts.faculties, f)
+		}
+	}
+	if len(opts.pages) == 0 && len(opts.faculties) == 0 {
+		return options{}, fmt.Errorf("no pages to load")
+	}
+	return opts, nil
+}
+
+func run(ctx context.Context, opts options) error {
+	cfg := config.Load()
+	repository.SetTimeouts(repository.Timeouts{
+		Read:      cfg.DBReadTimeout,
+		Write:     cfg.DBWriteTimeout,
+		Aggregate: cfg.DBAggregateTimeout,
+	})
+
+	pool, err := database.NewPool(ctx, cfg.DatabaseURL)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	s := scraper.New(repository.NewCatalogRepository(pool), repository.NewQuarantineRepository(pool), slog.Default())
+	if opts.descriptions != "" {
+		f, err := os.Open(opts.descriptions)
+		if err != nil {
+			return err
+		}
+		descriptions, err := scraper.LoadDescriptions(f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+		s.WithDescriptions(descriptions)
+	}
+	switch {
+	case opts.record != "":
+		archive, err := scraper.OpenArchive(opts.record)
+		if err != nil {
+			return err
+		}
+		s.WithHTTPClient(&http.Client{Timeout: time.Minute, Transport: archive.Recorder(http.DefaultTransport)})
+	case opts.replay != "":
+		archive, err := scraper.OpenArchive(opts.replay)
+		if err != nil {
+			return err
+		}
+		s.WithHTTPClient(&http.Client{Transport: archive.Replayer()})
+	}
+
+	pages := scraper.FacultyPages(opts.baseURL, opts.term, opts.faculties)
+	if len(opts.pages) > 0 {
+		pages = scraper.LocalPages(opts.term, opts.pages)
+	}
+	result, err := catalogsync.NewSyncer(s, repository.NewSyncRepository(pool), opts.term, pages).Run(ctx)
+	slog.Info("scrape finished", "sync", result.ID, "term", opts.term.ID, "status", result.Status, "pages", result.Pages,
+		"quarantined", result.Quarantined, "diff", result.Diff)
+	return err
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
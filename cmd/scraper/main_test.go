@@ -0,0 +1,30 @@
+package main
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFlags(t *testing.T) {
+	opts, err := parseFlags([]string{"-term", "su2026", "-faculties", "AP, LE,", "pages/ap.html"}, io.Discard)
+	assert.NoError(t, err)
+	assert.Equal(t, "SU2026", opts.term.ID)
+	assert.Equal(t, 2025, opts.term.AcademicYear)
+	assert.Equal(t, []string{"AP", "LE"}, opts.faculties)
+	assert.Equal(t, []string{"pages/ap.html"}, opts.pages)
+
+	_, err = parseFlags([]string{"-term", "2025"}, io.Discard)
+	assert.ErrorContains(t, err, "-term")
+
+	_, err = parseFlags([]string{"-term", "FW2025", "-faculties", ""}, io.Discard)
+	assert.ErrorContains(t, err, "no pages")
+
+	opts, err = parseFlags([]string{"-term", "FW2025", "-replay", "archive/fw2025"}, io.Discard)
+	assert.NoError(t, err)
+	assert.Equal(t, "archive/fw2025", opts.replay)
+
+	_, err = parseFlags([]string{"-term", "FW2025", "-record", "a", "-replay", "b"}, io.Discard)
+	assert.ErrorContains(t, err, "together")
+}
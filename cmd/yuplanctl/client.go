@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// client is a thin wrapper over the /api/v1/admin endpoints.
+type client struct {
+	baseURL string
+	apiKey  string
+	http    *http.Client
+}
+
+// envelope matches the {"data": ..., "count": ...} / {"error": ...} shape every
+// handler returns. body is the whole response, for the few that answer
+// outside data.
+type envelope struct {
+	Data    json.RawMessage `json:"data"`
+	Count   *int            `json:"count,omitempty"`
+	Message string          `json:"message,omitempty"`
+	Error   string          `json:"error,omitempty"`
+	body    json.RawMessage
+}
+
+func (c *client) do(method, path string, body any) (*envelope, error) {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequest(method, strings.TrimRight(c.baseURL, "/")+"/api/v1/admin"+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-API-Key", c.apiKey)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response (HTTP %d): %w", resp.StatusCode, err)
+	}
+	var env envelope
+	if err := json.Unmarshal(raw, &env); err != nil {
+		return nil, fmt.Errorf("decode response (HTTP %d): %w", resp.StatusCode, err)
+	}
+	env.body = raw
+	if resp.StatusCode >= 400 {
+		if env.Error == "" {
+			env.Error = http.StatusText(resp.StatusCode)
+		}
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, env.Error)
+	}
+	return &env, nil
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+type courseMerge struct {
+	Course struct {
+		ID   string `json:"id"`
+		Code string `json:"code"`
+		Term string `json:"term"`
+	} `json:"course"`
+	Sections int `json:"sections"`
+	Blocks   int `json:"blocks"`
+	Reviews  int `json:"reviews"`
+}
+
+// mergeCourse folds a duplicate course into another and deletes the duplicate.
+func mergeCourse(c *client, args []string, out *output) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: courses merge <duplicate-id> <into-id>")
+	}
+	env, err := c.do(http.MethodPost, "/courses/"+url.PathEscape(args[0])+"/merge", map[string]string{"into": args[1]})
+	if err != nil {
+		return err
+	}
+
+	var m courseMerge
+	if err := json.Unmarshal(env.Data, &m); err != nil {
+		return err
+	}
+	return out.print(env.Data, []string{"INTO", "CODE", "TERM", "SECTIONS", "BLOCKS", "REVIEWS"}, [][]string{{
+		m.Course.ID, m.Course.Code, m.Course.Term,
+		strconv.Itoa(m.Sections), strconv.Itoa(m.Blocks), strconv.Itoa(m.Reviews),
+	}})
+}
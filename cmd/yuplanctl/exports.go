@@ -0,0 +1,43 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type exportObject struct {
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+func listExports(c *client, args []string, out *output) error {
+	env, err := c.do(http.MethodGet, "/exports", nil)
+	if err != nil {
+		return err
+	}
+	return printExports(env.Data, out)
+}
+
+func runExport(c *client, args []string, out *output) error {
+	env, err := c.do(http.MethodPost, "/exports", nil)
+	if err != nil {
+		return err
+	}
+	return printExports(env.Data, out)
+}
+
+func printExports(raw json.RawMessage, out *output) error {
+	var objects []exportObject
+	if err := json.Unmarshal(raw, &objects); err != nil {
+		return err
+	}
+
+	rows := make([][]string, 0, len(objects))
+	for _, o := range objects {
+		rows = append(rows, []string{o.Key, strconv.FormatInt(o.Size, 10), o.LastModified.Format(time.RFC3339)})
+	}
+	return out.print(raw, []string{"KEY", "SIZE", "LAST MODIFIED"}, rows)
+}
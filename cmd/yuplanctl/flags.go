@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+type tunables struct {
+	FeatureFlags []string `json:"feature_flags"`
+}
+
+func listFlags(c *client, args []string, out *output) error {
+	env, err := c.do(http.MethodGet, "/config", nil)
+	if err != nil {
+		return err
+	}
+	return printFlags(env.Data, out)
+}
+
+// setFlag turns a feature flag on or off on the instance that answers, until
+// it restarts; FEATURE_FLAGS is what lasts.
+func setFlag(c *client, args []string, out *output) error {
+	if len(args) != 2 || (args[1] != "on" && args[1] != "off") {
+		return fmt.Errorf("usage: flags set <flag> on|off")
+	}
+	env, err := c.do(http.MethodPut, "/config/flags/"+url.PathEscape(args[0]), map[string]bool{"enabled": args[1] == "on"})
+	if err != nil {
+		return err
+	}
+	return printFlags(env.Data, out)
+}
+
+func reloadConfig(c *client, args []string, out *output) error {
+	env, err := c.do(http.MethodPost, "/config/reload", nil)
+	if err != nil {
+		return err
+	}
+	return out.message(env)
+}
+
+func printFlags(raw json.RawMessage, out *output) error {
+	var t tunables
+	if err := json.Unmarshal(raw, &t); err != nil {
+		return err
+	}
+	rows := make([][]string, 0, len(t.FeatureFlags))
+	for _, flag := range t.FeatureFlags {
+		rows = append(rows, []string{flag})
+	}
+	return out.print(raw, []string{"ENABLED FLAG"}, rows)
+}
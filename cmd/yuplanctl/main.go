@@ -0,0 +1,89 @@
+// Command yuplanctl is an operator CLI for the core-api admin endpoints.
+//
+//	yuplanctl [--url URL] [--api-key KEY] [--json] <resource> <action>
+//
+// The base URL and API key default to YUPLAN_URL and ADMIN_API_KEY.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+type command struct {
+	usage string
+	run   func(c *client, args []string, out *output) error
+}
+
+// commands maps "<resource> <action>" to its implementation.
+var commands = map[string]command{
+	"exports list":     {usage: "List stored review/audit log snapshots", run: listExports},
+	"exports run":      {usage: "Export today's snapshots now", run: runExport},
+	"reviews approve":  {usage: "Approve and publish a held review: <review-id>", run: approveReview},
+	"courses merge":    {usage: "Merge a duplicate course into another: <duplicate-id> <into-id>", run: mergeCourse},
+	"syncs list":       {usage: "List recent catalog syncs", run: listSyncs},
+	"syncs run":        {usage: "Start a catalog sync (scrape) now", run: runSync},
+	"exemptions issue": {usage: "Issue a rate limit exemption key: -name N -limit L -ttl 1h [-reason R]", run: issueExemption},
+	"flags list":       {usage: "List enabled feature flags", run: listFlags},
+	"flags set":        {usage: "Turn a feature flag on or off: <flag> on|off", run: setFlag},
+	"config reload":    {usage: "Reload hot-reloadable settings", run: reloadConfig},
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "yuplanctl: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("yuplanctl", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	baseURL := fs.String("url", getEnv("YUPLAN_URL", "http://localhost:8080"), "core-api base URL")
+	apiKey := fs.String("api-key", os.Getenv("ADMIN_API_KEY"), "admin API key")
+	asJSON := fs.Bool("json", false, "print raw JSON instead of a table")
+	fs.Usage = func() { printUsage(stderr, fs) }
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 2 {
+		fs.Usage()
+		return fmt.Errorf("expected <resource> <action>")
+	}
+
+	name := fs.Arg(0) + " " + fs.Arg(1)
+	cmd, ok := commands[name]
+	if !ok {
+		fs.Usage()
+		return fmt.Errorf("unknown command %q", name)
+	}
+
+	c := &client{
+		baseURL: *baseURL,
+		apiKey:  *apiKey,
+		http:    &http.Client{Timeout: 5 * time.Minute},
+	}
+	return cmd.run(c, fs.Args()[2:], &output{w: stdout, json: *asJSON})
+}
+
+func printUsage(w io.Writer, fs *flag.FlagSet) {
+	fmt.Fprintln(w, "Usage: yuplanctl [flags] <resource> <action> [args]")
+	fmt.Fprintln(w, "\nCommands:")
+	for _, name := range sortedCommandNames() {
+		fmt.Fprintf(w, "  %-20s %s\n", name, commands[name].usage)
+	}
+	fmt.Fprintln(w, "\nFlags:")
+	fs.PrintDefaults()
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
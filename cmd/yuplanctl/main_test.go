@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newAdminServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-Key") != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"Invalid or missing API key"}`))
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/admin/exports":
+			w.Write([]byte(`{"data":[{"key":"reviews/2026-03-01.ndjson.gz","size":120,"last_modified":"2026-03-01T00:00:00Z"}],"count":1}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/admin/exports":
+			w.Write([]byte(`{"data":[],"count":0}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/admin/reviews/r-1/publish":
+			w.Write([]byte(`{"data":{"id":"r-1","course_code":"EECS2030","status":"published"},"message":"Review published"}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/admin/courses/c-dup/merge":
+			body, _ := io.ReadAll(r.Body)
+			assert.JSONEq(t, `{"into":"c-1"}`, string(body))
+			w.Write([]byte(`{"data":{"course":{"id":"c-1","code":"EECS2030","term":"F"},"sections":2,"blocks":0,"reviews":7}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/admin/syncs":
+			w.Write([]byte(`{"latest":null,"data":[{"id":3,"term":"FW2025","status":"succeeded","started_at":"2026-03-01T00:00:00Z","finished_at":"2026-03-01T00:04:00Z"}],"count":1}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/admin/syncs":
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{"message":"Catalog sync started"}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/admin/rate-limit/exemptions":
+			body, _ := io.ReadAll(r.Body)
+			assert.JSONEq(t, `{"name":"k6","limit":5000,"ttl_minutes":90,"reason":"load test"}`, string(body))
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"token":"tok.en","header":"X-RateLimit-Exemption","id":"e-1","name":"k6","limit":5000,"expires_at":"2026-03-01T01:30:00Z"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/admin/config":
+			w.Write([]byte(`{"data":{"feature_flags":["review_embargo"]}}`))
+		case r.Method == http.MethodPut && r.URL.Path == "/api/v1/admin/config/flags/captcha_reviews":
+			body, _ := io.ReadAll(r.Body)
+			assert.JSONEq(t, `{"enabled":true}`, string(body))
+			w.Write([]byte(`{"data":{"feature_flags":["captcha_reviews","review_embargo"]},"message":"Feature flag captcha_reviews enabled"}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/admin/config/reload":
+			w.Write([]byte(`{"data":{"feature_flags":[]},"message":"Configuration reloaded"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"not found"}`))
+		}
+	}))
+}
+
+func TestRun_ExportsListTable(t *testing.T) {
+	server := newAdminServer(t)
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	err := run([]string{"--url", server.URL, "--api-key", "s3cret", "exports", "list"}, &stdout, &stderr)
+
+	assert.NoError(t, err)
+	assert.Contains(t, stdout.String(), "KEY")
+	assert.Contains(t, stdout.String(), "reviews/2026-03-01.ndjson.gz")
+	assert.Contains(t, stdout.String(), "120")
+}
+
+func TestRun_ExportsListJSON(t *testing.T) {
+	server := newAdminServer(t)
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	err := run([]string{"--url", server.URL, "--api-key", "s3cret", "--json", "exports", "list"}, &stdout, &stderr)
+
+	assert.NoError(t, err)
+	assert.Contains(t, stdout.String(), `"key": "reviews/2026-03-01.ndjson.gz"`)
+}
+
+func TestRun_ExportsRun(t *testing.T) {
+	server := newAdminServer(t)
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	err := run([]string{"--url", server.URL, "--api-key", "s3cret", "exports", "run"}, &stdout, &stderr)
+
+	assert.NoError(t, err)
+	assert.Contains(t, stdout.String(), "KEY")
+}
+
+func TestRun_AdminActions(t *testing.T) {
+	server := newAdminServer(t)
+	defer server.Close()
+
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"reviews", "approve", "r-1"}, []string{"COURSE", "EECS2030", "published"}},
+		{[]string{"courses", "merge", "c-dup", "c-1"}, []string{"SECTIONS", "c-1", "7"}},
+		{[]string{"syncs", "list"}, []string{"FW2025", "succeeded", "2026-03-01T00:04:00Z"}},
+		{[]string{"syncs", "run"}, []string{"Catalog sync started"}},
+		{[]string{"exemptions", "issue", "-name", "k6", "-limit", "5000", "-ttl", "90m", "-reason", "load test"}, []string{"X-RateLimit-Exemption", "tok.en"}},
+		{[]string{"flags", "list"}, []string{"review_embargo"}},
+		{[]string{"flags", "set", "captcha_reviews", "on"}, []string{"captcha_reviews", "review_embargo"}},
+		{[]string{"config", "reload"}, []string{"Configuration reloaded"}},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		err := run(append([]string{"--url", server.URL, "--api-key", "s3cret"}, tt.args...), &stdout, &stderr)
+		assert.NoError(t, err, "%v", tt.args)
+		for _, want := range tt.want {
+			assert.Contains(t, stdout.String(), want, "%v", tt.args)
+		}
+	}
+}
+
+func TestRun_MessageJSON(t *testing.T) {
+	server := newAdminServer(t)
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	err := run([]string{"--url", server.URL, "--api-key", "s3cret", "--json", "syncs", "run"}, &stdout, &stderr)
+
+	assert.NoError(t, err)
+	assert.Contains(t, stdout.String(), `"message": "Catalog sync started"`)
+}
+
+func TestRun_RejectsBadArgs(t *testing.T) {
+	for _, args := range [][]string{
+		{"reviews", "approve"},
+		{"courses", "merge", "c-dup"},
+		{"flags", "set", "captcha_reviews", "maybe"},
+		{"exemptions", "issue", "-limit", "10"},
+	} {
+		var stdout, stderr bytes.Buffer
+		err := run(append([]string{"--url", "http://127.0.0.1:0"}, args...), &stdout, &stderr)
+		assert.ErrorContains(t, err, "usage:", "%v", args)
+	}
+}
+
+func TestRun_SurfacesAPIErrors(t *testing.T) {
+	server := newAdminServer(t)
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	err := run([]string{"--url", server.URL, "--api-key", "wrong", "exports", "list"}, &stdout, &stderr)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "HTTP 401: Invalid or missing API key")
+}
+
+func TestRun_UnknownCommand(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	err := run([]string{"reviews", "explode"}, &stdout, &stderr)
+
+	assert.Error(t, err)
+	assert.Contains(t, stderr.String(), "exports list")
+}
+
+func TestRun_MissingCommand(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	err := run([]string{}, &stdout, &stderr)
+
+	assert.Error(t, err)
+	assert.Contains(t, stderr.String(), "Usage: yuplanctl")
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+type output struct {
+	w    io.Writer
+	json bool
+}
+
+// print writes raw JSON when --json is set, otherwise an aligned table of rows.
+func (o *output) print(raw json.RawMessage, headers []string, rows [][]string) error {
+	if o.json {
+		var v any
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return err
+		}
+		enc := json.NewEncoder(o.w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+
+	tw := tabwriter.NewWriter(o.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// message writes the response's message, or the whole response with --json,
+// for actions whose result is a sentence rather than rows.
+func (o *output) message(env *envelope) error {
+	if o.json {
+		return o.print(env.body, nil, nil)
+	}
+	_, err := fmt.Fprintln(o.w, env.Message)
+	return err
+}
+
+func sortedCommandNames() []string {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
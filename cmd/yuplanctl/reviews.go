@@ -0,0 +1,31 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+type review struct {
+	ID         string `json:"id"`
+	CourseCode string `json:"course_code"`
+	Status     string `json:"status"`
+}
+
+// approveReview publishes a review held by moderation, its reports or an embargo.
+func approveReview(c *client, args []string, out *output) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: reviews approve <review-id>")
+	}
+	env, err := c.do(http.MethodPost, "/reviews/"+url.PathEscape(args[0])+"/publish", nil)
+	if err != nil {
+		return err
+	}
+
+	var r review
+	if err := json.Unmarshal(env.Data, &r); err != nil {
+		return err
+	}
+	return out.print(env.Data, []string{"ID", "COURSE", "STATUS"}, [][]string{{r.ID, r.CourseCode, r.Status}})
+}
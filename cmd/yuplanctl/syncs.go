@@ -0,0 +1,47 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type syncRun struct {
+	ID         int64      `json:"id"`
+	Term       string     `json:"term"`
+	Status     string     `json:"status"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+	Error      string     `json:"error"`
+}
+
+func listSyncs(c *client, args []string, out *output) error {
+	env, err := c.do(http.MethodGet, "/syncs", nil)
+	if err != nil {
+		return err
+	}
+
+	var runs []syncRun
+	if err := json.Unmarshal(env.Data, &runs); err != nil {
+		return err
+	}
+	rows := make([][]string, 0, len(runs))
+	for _, r := range runs {
+		finished := ""
+		if r.FinishedAt != nil {
+			finished = r.FinishedAt.Format(time.RFC3339)
+		}
+		rows = append(rows, []string{strconv.FormatInt(r.ID, 10), r.Term, r.Status, r.StartedAt.Format(time.RFC3339), finished, r.Error})
+	}
+	return out.print(env.Data, []string{"ID", "TERM", "STATUS", "STARTED", "FINISHED", "ERROR"}, rows)
+}
+
+// runSync starts a catalog sync, which scrapes the timetable; follow it with syncs list.
+func runSync(c *client, args []string, out *output) error {
+	env, err := c.do(http.MethodPost, "/syncs", nil)
+	if err != nil {
+		return err
+	}
+	return out.message(env)
+}
@@ -0,0 +1,80 @@
+package admintoken
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSigner_SignAndVerify(t *testing.T) {
+	signer := NewSigner("secret")
+	want := Claims{Subject: "jdoe@yorku.ca", Role: "admin", ExpiresAt: time.Now().Add(time.Hour).Unix()}
+
+	token, err := signer.Sign(want)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(token, "."))
+
+	claims, err := signer.Verify(token)
+	assert.NoError(t, err)
+	assert.Equal(t, want, claims)
+}
+
+func TestSigner_VerifyRejectsTampering(t *testing.T) {
+	signer := NewSigner("secret")
+	claims := Claims{Subject: "jdoe", Role: "public", ExpiresAt: time.Now().Add(time.Hour).Unix()}
+	token, err := signer.Sign(claims)
+	assert.NoError(t, err)
+
+	_, err = NewSigner("other").Verify(token)
+	assert.ErrorIs(t, err, ErrInvalid)
+
+	// Promoting the role invalidates the signature
+	claims.Role = "admin"
+	forged, _ := NewSigner("other").Sign(claims)
+	parts, original := strings.Split(forged, "."), strings.Split(token, ".")
+	_, err = signer.Verify(parts[0] + "." + parts[1] + "." + original[2])
+	assert.ErrorIs(t, err, ErrInvalid)
+
+	// An unsigned token is refused even with an empty signature
+	none := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`))
+	_, err = signer.Verify(none + "." + parts[1] + ".")
+	assert.ErrorIs(t, err, ErrInvalid)
+
+	for _, bad := range []string{"", "a.b", "a.b.c.d", "!!.!!.!!"} {
+		_, err = signer.Verify(bad)
+		assert.ErrorIs(t, err, ErrInvalid, bad)
+	}
+
+	// Subject, role and expiry are all required
+	for _, missing := range []Claims{
+		{Role: "admin", ExpiresAt: claims.ExpiresAt},
+		{Subject: "jdoe", ExpiresAt: claims.ExpiresAt},
+		{Subject: "jdoe", Role: "admin"},
+	} {
+		token, _ := signer.Sign(missing)
+		_, err = signer.Verify(token)
+		assert.ErrorIs(t, err, ErrInvalid)
+	}
+}
+
+func TestSigner_VerifyExpiry(t *testing.T) {
+	signer := NewSigner("secret")
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	signer.now = func() time.Time { return now }
+
+	expired, _ := signer.Sign(Claims{Subject: "jdoe", Role: "admin", ExpiresAt: now.Add(-time.Minute).Unix()})
+	_, err := signer.Verify(expired)
+	assert.ErrorIs(t, err, ErrExpired)
+
+	early, _ := signer.Sign(Claims{Subject: "jdoe", Role: "admin", ExpiresAt: now.Add(time.Hour).Unix(), NotBefore: now.Add(time.Minute).Unix()})
+	_, err = signer.Verify(early)
+	assert.ErrorIs(t, err, ErrExpired)
+
+	// Within the leeway either way is still accepted
+	skewed, _ := signer.Sign(Claims{Subject: "jdoe", Role: "admin", ExpiresAt: now.Add(-10 * time.Second).Unix(), NotBefore: now.Add(10 * time.Second).Unix()})
+	_, err = signer.Verify(skewed)
+	assert.NoError(t, err)
+}
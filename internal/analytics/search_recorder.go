@@ -0,0 +1,142 @@
+// Package analytics collects anonymized usage signals off the request path.
+package analytics
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+	"yuplan/internal/models"
+)
+
+// SearchStore persists aggregated search counts. Implemented by repository.SearchStatsRepository.
+type SearchStore interface {
+	Increment(ctx context.Context, day time.Time, stats []models.SearchStat) error
+}
+
+type searchEvent struct {
+	query   string
+	results int
+}
+
+// SearchRecorder buffers search events in memory and writes them in batches,
+// so recording never adds a database round trip to the search path.
+// Events are dropped (not blocked on) if the buffer is full.
+type SearchRecorder struct {
+	store    SearchStore
+	events   chan searchEvent
+	interval time.Duration
+	now      func() time.Time
+	stopped  chan struct{}
+}
+
+const maxQueryLength = 100
+
+// NewSearchRecorder creates a recorder
+// buffer: max events queued between flushes (e.g., 1000)
+// interval: how often batches are written (e.g., 30 seconds)
+func NewSearchRecorder(store SearchStore, buffer int, interval time.Duration) *SearchRecorder {
+	return &SearchRecorder{
+		store:    store,
+		events:   make(chan searchEvent, buffer),
+		interval: interval,
+		now:      time.Now,
+		stopped:  make(chan struct{}),
+	}
+}
+
+// Record queues a search. It never blocks.
+func (r *SearchRecorder) Record(query string, results int) {
+	q := normalizeQuery(query)
+	if q == "" {
+		return
+	}
+	select {
+	case r.events <- searchEvent{query: q, results: results}:
+	default:
+	}
+}
+
+// Start aggregates queued events and flushes them every interval until ctx is
+// cancelled, with a final flush on shutdown.
+func (r *SearchRecorder) Start(ctx context.Context) {
+	go func() {
+		defer close(r.stopped)
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		pending := make(map[string]*models.SearchStat)
+		for {
+			select {
+			case ev := <-r.events:
+				r.add(pending, ev)
+			case <-ticker.C:
+				r.flush(context.Background(), pending)
+				pending = make(map[string]*models.SearchStat)
+			case <-ctx.Done():
+				r.drain(pending)
+				r.flush(context.Background(), pending)
+				return
+			}
+		}
+	}()
+}
+
+// Stopped is closed once Start has made its final flush, so the database can
+// be closed after it.
+func (r *SearchRecorder) Stopped() <-chan struct{} {
+	return r.stopped
+}
+
+func (r *SearchRecorder) add(pending map[string]*models.SearchStat, ev searchEvent) {
+	stat, ok := pending[ev.query]
+	if !ok {
+		stat = &models.SearchStat{Query: ev.query}
+		pending[ev.query] = stat
+	}
+	stat.Searches++
+	if ev.results == 0 {
+		stat.ZeroResults++
+	}
+}
+
+func (r *SearchRecorder) drain(pending map[string]*models.SearchStat) {
+	for {
+		select {
+		case ev := <-r.events:
+			r.add(pending, ev)
+		default:
+			return
+		}
+	}
+}
+
+func (r *SearchRecorder) flush(ctx context.Context, pending map[string]*models.SearchStat) {
+	if len(pending) == 0 {
+		return
+	}
+	stats := make([]models.SearchStat, 0, len(pending))
+	for _, s := range pending {
+		stats = append(stats, *s)
+	}
+	if err := r.store.Increment(ctx, r.now().UTC(), stats); err != nil {
+		log.Printf("failed to record search stats: %v", err)
+	}
+}
+
+// normalizeQuery lowercases, collapses whitespace and truncates so equivalent
+// searches aggregate together and nothing unbounded reaches the database. The
+// cut is at most maxQueryLength bytes and never splits a character, since
+// Postgres rejects invalid UTF-8 and would fail the whole flush.
+func normalizeQuery(query string) string {
+	q := strings.ToLower(strings.Join(strings.Fields(query), " "))
+	if len(q) > maxQueryLength {
+		cut := maxQueryLength
+		for cut > 0 && !utf8.RuneStart(q[cut]) {
+			cut--
+		}
+		q = q[:cut]
+	}
+	return q
+}
@@ -0,0 +1,103 @@
+package analytics
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+	"yuplan/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSearchStore struct {
+	mu      sync.Mutex
+	batches [][]models.SearchStat
+}
+
+func (f *fakeSearchStore) Increment(ctx context.Context, day time.Time, stats []models.SearchStat) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.batches = append(f.batches, stats)
+	return nil
+}
+
+func (f *fakeSearchStore) all() []models.SearchStat {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make([]models.SearchStat, 0)
+	for _, b := range f.batches {
+		out = append(out, b...)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Query < out[j].Query })
+	return out
+}
+
+func TestSearchRecorder_AggregatesAndFlushesOnShutdown(t *testing.T) {
+	store := &fakeSearchStore{}
+	recorder := NewSearchRecorder(store, 100, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	recorder.Start(ctx)
+
+	recorder.Record("EECS", 10)
+	recorder.Record("  eecs ", 4)
+	recorder.Record("Nursing   101", 0)
+	recorder.Record("   ", 0)
+
+	cancel()
+	select {
+	case <-recorder.Stopped():
+	case <-time.After(time.Second):
+		t.Fatal("Expected the recorder to stop after its final flush")
+	}
+
+	assert.Equal(t, []models.SearchStat{
+		{Query: "eecs", Searches: 2},
+		{Query: "nursing 101", Searches: 1, ZeroResults: 1},
+	}, store.all())
+}
+
+func TestSearchRecorder_FlushesOnInterval(t *testing.T) {
+	store := &fakeSearchStore{}
+	recorder := NewSearchRecorder(store, 100, 10*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	recorder.Start(ctx)
+
+	recorder.Record("math", 3)
+
+	assert.Eventually(t, func() bool { return len(store.all()) == 1 }, time.Second, 5*time.Millisecond)
+}
+
+func TestSearchRecorder_RecordNeverBlocks(t *testing.T) {
+	recorder := NewSearchRecorder(&fakeSearchStore{}, 1, time.Hour)
+
+	done := make(chan struct{})
+	go func() {
+		recorder.Record("a", 1)
+		recorder.Record("b", 1)
+		recorder.Record("c", 1)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Record blocked on a full buffer")
+	}
+}
+
+func TestNormalizeQuery(t *testing.T) {
+	assert.Equal(t, "eecs 2030", normalizeQuery("  EECS \t 2030 "))
+	assert.Len(t, normalizeQuery(strings.Repeat("a", 500)), maxQueryLength)
+
+	// Byte 100 falls inside the 33rd three-byte rune, so the cut drops it whole
+	long := normalizeQuery("AB" + strings.Repeat("学", 200))
+	assert.True(t, utf8.ValidString(long))
+	assert.Equal(t, "ab"+strings.Repeat("学", 32), long)
+}
@@ -0,0 +1,99 @@
+// Package badges awards reviewer badges from the rules in the badges table.
+package badges
+
+import (
+	"context"
+	"log"
+	"time"
+	"yuplan/internal/jobs"
+	"yuplan/internal/models"
+)
+
+// Store reads badge rules and reviewer stats and replaces the awarded badges.
+// Implemented by repository.BadgeRepository.
+type Store interface {
+	ListBadges(ctx context.Context) ([]models.Badge, error)
+	ListReviewerStats(ctx context.Context) ([]models.ReviewerStats, error)
+	ReplaceAwards(ctx context.Context, awards []models.BadgeAward) error
+}
+
+// jobLocker keeps scheduled runs to one instance at a time, once per tick. Implemented by jobs.Locker.
+type jobLocker interface {
+	Do(ctx context.Context, job string, tick time.Time, fn func(ctx context.Context) error) (bool, error)
+}
+
+// Awarder rebuilds reviewer_badges from the badge rules and published reviews.
+type Awarder struct {
+	store  Store
+	locker jobLocker
+}
+
+func NewAwarder(store Store) *Awarder {
+	return &Awarder{store: store}
+}
+
+// WithLocker makes Start skip runs while another instance holds the badge lock.
+func (a *Awarder) WithLocker(locker jobLocker) *Awarder {
+	a.locker = locker
+	return a
+}
+
+// Run checks every reviewer against every rule and returns how many badges are held.
+// Badges are recomputed rather than accumulated, so a reviewer whose reviews
+// are removed or re-embargoed loses badges they no longer qualify for.
+func (a *Awarder) Run(ctx context.Context) (int, error) {
+	rules, err := a.store.ListBadges(ctx)
+	if err != nil {
+		return 0, err
+	}
+	stats, err := a.store.ListReviewerStats(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var awards []models.BadgeAward
+	for _, s := range stats {
+		for _, b := range rules {
+			if b.EarnedBy(s) {
+				awards = append(awards, models.BadgeAward{Email: s.Email, Badge: b.Slug})
+			}
+		}
+	}
+
+	if err := a.store.ReplaceAwards(ctx, awards); err != nil {
+		return 0, err
+	}
+	return len(awards), nil
+}
+
+// Start awards badges immediately and then every interval until ctx is done.
+func (a *Awarder) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := a.runScheduled(ctx, jobs.Tick(time.Now(), interval)); err != nil {
+				log.Printf("review badge awarding failed: %v", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (a *Awarder) runScheduled(ctx context.Context, tick time.Time) error {
+	run := func(ctx context.Context) error {
+		_, err := a.Run(ctx)
+		return err
+	}
+	if a.locker == nil {
+		return run(ctx)
+	}
+	_, err := a.locker.Do(ctx, "review_badges", tick, run)
+	return err
+}
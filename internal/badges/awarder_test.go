@@ -0,0 +1,88 @@
+package badges
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeStore struct {
+	rules    []models.Badge
+	stats    []models.ReviewerStats
+	statsErr error
+	saved    []models.BadgeAward
+	calls    int
+}
+
+func (f *fakeStore) ListBadges(ctx context.Context) ([]models.Badge, error) {
+	return f.rules, nil
+}
+
+func (f *fakeStore) ListReviewerStats(ctx context.Context) ([]models.ReviewerStats, error) {
+	return f.stats, f.statsErr
+}
+
+func (f *fakeStore) ReplaceAwards(ctx context.Context, awards []models.BadgeAward) error {
+	f.saved = awards
+	f.calls++
+	return nil
+}
+
+type fakeLocker struct {
+	held bool
+}
+
+func (f *fakeLocker) Do(ctx context.Context, job string, tick time.Time, fn func(ctx context.Context) error) (bool, error) {
+	if f.held {
+		return false, nil
+	}
+	return true, fn(ctx)
+}
+
+func TestAwarder_Run(t *testing.T) {
+	store := &fakeStore{
+		rules: []models.Badge{
+			{Slug: "first-review", Metric: models.BadgeMetricReviews, Threshold: 1},
+			{Slug: "department-expert", Metric: models.BadgeMetricDepartmentReviews, Threshold: 5},
+		},
+		stats: []models.ReviewerStats{
+			{Email: "a@yorku.ca", Reviews: 7, DepartmentReviews: 5},
+			{Email: "b@yorku.ca", Reviews: 6, DepartmentReviews: 2},
+		},
+	}
+
+	n, err := NewAwarder(store).Run(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, []models.BadgeAward{
+		{Email: "a@yorku.ca", Badge: "first-review"},
+		{Email: "a@yorku.ca", Badge: "department-expert"},
+		{Email: "b@yorku.ca", Badge: "first-review"},
+	}, store.saved)
+}
+
+func TestAwarder_RunStatsError(t *testing.T) {
+	store := &fakeStore{statsErr: errors.New("db down")}
+
+	_, err := NewAwarder(store).Run(context.Background())
+	assert.Error(t, err)
+	assert.Zero(t, store.calls)
+}
+
+func TestAwarder_ScheduledRunsRespectLocker(t *testing.T) {
+	store := &fakeStore{}
+	locker := &fakeLocker{held: true}
+	awarder := NewAwarder(store).WithLocker(locker)
+
+	assert.NoError(t, awarder.runScheduled(context.Background(), time.Now()))
+	assert.Zero(t, store.calls)
+
+	locker.held = false
+	assert.NoError(t, awarder.runScheduled(context.Background(), time.Now()))
+	assert.Equal(t, 1, store.calls)
+}
@@ -0,0 +1,180 @@
+// Package cache keeps copies of read results that rarely change, such as
+// course, section and instructor data, in Redis or, without it, in memory.
+// A cache that fails is skipped rather than failing the request: the caller
+// loads from the database as if it were a miss.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+)
+
+// KeyPrefix starts every key this service writes, so a shared Redis can be
+// told apart and flushed without touching anyone else's keys.
+const KeyPrefix = "yuplan:"
+
+// Lookup results, as counted by an Observer.
+const (
+	ResultHit   = "hit"
+	ResultMiss  = "miss"
+	ResultError = "error"
+)
+
+// Invalidation scopes, as counted by an Observer: single keys, or everything
+// in a namespace.
+const (
+	ScopeKey       = "key"
+	ScopeNamespace = "namespace"
+)
+
+// Backend stores raw values with an expiry. Implemented by Redis and Memory.
+type Backend interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+	DeletePrefix(ctx context.Context, prefix string) error
+}
+
+// Observer counts lookups by namespace and result, and invalidations by
+// namespace and scope. An invalidation that fails is counted as stale: what
+// it meant to drop may be served until something else drops it or it
+// expires. Implemented by metrics.Cache.
+type Observer interface {
+	CacheLookup(namespace, result string)
+	CacheInvalidated(namespace, scope string, n int)
+	CacheStale(namespace string)
+}
+
+// Store caches values as JSON under namespaced keys.
+type Store struct {
+	backend  Backend
+	ttl      time.Duration
+	ttls     map[string]time.Duration
+	observer Observer
+}
+
+// NewStore caches in backend for ttl unless a namespace has its own.
+func NewStore(backend Backend, ttl time.Duration) *Store {
+	return &Store{backend: backend, ttl: ttl, ttls: map[string]time.Duration{}}
+}
+
+// WithTTL keeps values in namespace for ttl instead of the default.
+func (s *Store) WithTTL(namespace string, ttl time.Duration) *Store {
+	s.ttls[namespace] = ttl
+	return s
+}
+
+// WithObserver counts every lookup.
+func (s *Store) WithObserver(observer Observer) *Store {
+	s.observer = observer
+	return s
+}
+
+// Invalidate drops everything cached in the given namespaces, or in every
+// namespace when none are given.
+func (s *Store) Invalidate(ctx context.Context, namespaces ...string) error {
+	if len(namespaces) == 0 {
+		if err := s.backend.DeletePrefix(ctx, KeyPrefix); err != nil {
+			s.stale("all")
+			return err
+		}
+		s.invalidated("all", ScopeNamespace, 1)
+		return nil
+	}
+	for _, ns := range namespaces {
+		if err := s.backend.DeletePrefix(ctx, KeyPrefix+ns+":"); err != nil {
+			s.stale(ns)
+			return err
+		}
+		s.invalidated(ns, ScopeNamespace, 1)
+	}
+	return nil
+}
+
+// Delete drops the given keys of namespace, as passed to Fetch.
+func (s *Store) Delete(ctx context.Context, namespace string, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	fullKeys := make([]string, len(keys))
+	for i, key := range keys {
+		fullKeys[i] = KeyPrefix + namespace + ":" + key
+	}
+	if err := s.backend.Delete(ctx, fullKeys...); err != nil {
+		s.stale(namespace)
+		return err
+	}
+	s.invalidated(namespace, ScopeKey, len(keys))
+	return nil
+}
+
+// Ping checks the backend can be reached. Only Redis can fail it; the
+// in-memory backend is always there.
+func (s *Store) Ping(ctx context.Context) error {
+	if p, ok := s.backend.(interface{ Ping(context.Context) error }); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (s *Store) observe(namespace, result string) {
+	if s.observer != nil {
+		s.observer.CacheLookup(namespace, result)
+	}
+}
+
+func (s *Store) invalidated(namespace, scope string, n int) {
+	if s.observer != nil {
+		s.observer.CacheInvalidated(namespace, scope, n)
+	}
+}
+
+func (s *Store) stale(namespace string) {
+	if s.observer != nil {
+		s.observer.CacheStale(namespace)
+	}
+}
+
+// Fetch returns the value cached under key in namespace, or calls load and
+// caches what it returns. Load errors are returned and never cached. With a
+// nil store it only calls load.
+func Fetch[T any](ctx context.Context, s *Store, namespace, key string, load func() (T, error)) (T, error) {
+	if s == nil {
+		return load()
+	}
+	fullKey := KeyPrefix + namespace + ":" + key
+
+	data, ok, err := s.backend.Get(ctx, fullKey)
+	switch {
+	case err != nil:
+		s.observe(namespace, ResultError)
+		log.Printf("cache get %s: %v", fullKey, err)
+	case ok:
+		var value T
+		if err := json.Unmarshal(data, &value); err == nil {
+			s.observe(namespace, ResultHit)
+			return value, nil
+		}
+		// Written by an older build with a different shape; reload it
+		s.observe(namespace, ResultMiss)
+	default:
+		s.observe(namespace, ResultMiss)
+	}
+
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+	if data, err := json.Marshal(value); err == nil {
+		ttl := s.ttl
+		if nsTTL, ok := s.ttls[namespace]; ok {
+			ttl = nsTTL
+		}
+		if err := s.backend.Set(ctx, fullKey, data, ttl); err != nil {
+			log.Printf("cache set %s: %v", fullKey, err)
+		}
+	}
+	return value, nil
+}
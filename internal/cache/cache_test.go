@@ -0,0 +1,220 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type countingObserver map[string]int
+
+func (o countingObserver) CacheLookup(namespace, result string) {
+	o[namespace+" "+result]++
+}
+
+func (o countingObserver) CacheInvalidated(namespace, scope string, n int) {
+	o[namespace+" "+scope] += n
+}
+
+func (o countingObserver) CacheStale(namespace string) {
+	o[namespace+" stale"]++
+}
+
+type failingBackend struct{}
+
+func (failingBackend) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	return nil, false, errors.New("connection refused")
+}
+func (failingBackend) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return errors.New("connection refused")
+}
+func (failingBackend) Delete(ctx context.Context, keys ...string) error {
+	return errors.New("connection refused")
+}
+func (failingBackend) DeletePrefix(ctx context.Context, prefix string) error {
+	return errors.New("connection refused")
+}
+
+type course struct {
+	Code string
+}
+
+func TestFetch(t *testing.T) {
+	observer := countingObserver{}
+	store := NewStore(NewMemory(100), time.Minute).WithObserver(observer)
+	loads := 0
+	load := func() (*course, error) {
+		loads++
+		return &course{Code: "EECS2030"}, nil
+	}
+
+	for i := 0; i < 3; i++ {
+		got, err := Fetch(context.Background(), store, "courses", "id-1", load)
+		if err != nil || got == nil || got.Code != "EECS2030" {
+			t.Fatalf("Fetch = %+v, %v", got, err)
+		}
+	}
+	if loads != 1 {
+		t.Errorf("Expected one load, got %d", loads)
+	}
+	if observer["courses hit"] != 2 || observer["courses miss"] != 1 {
+		t.Errorf("Expected 2 hits and 1 miss, got %v", observer)
+	}
+
+	// A nil result is cached too, so a missing course isn't looked up every time
+	nilLoads := 0
+	for i := 0; i < 2; i++ {
+		got, _ := Fetch(context.Background(), store, "courses", "missing", func() (*course, error) {
+			nilLoads++
+			return nil, nil
+		})
+		if got != nil {
+			t.Errorf("Expected nil, got %+v", got)
+		}
+	}
+	if nilLoads != 1 {
+		t.Errorf("Expected the nil result to be cached, got %d loads", nilLoads)
+	}
+}
+
+func TestFetch_LoadErrorsAreNotCached(t *testing.T) {
+	store := NewStore(NewMemory(100), time.Minute)
+	loads := 0
+	load := func() ([]course, error) {
+		loads++
+		return nil, errors.New("db down")
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := Fetch(context.Background(), store, "sections", "c1", load); err == nil {
+			t.Error("Expected the load error")
+		}
+	}
+	if loads != 2 {
+		t.Errorf("Expected every failed load to be retried, got %d loads", loads)
+	}
+}
+
+func TestFetch_BackendErrorsFallThrough(t *testing.T) {
+	observer := countingObserver{}
+	store := NewStore(failingBackend{}, time.Minute).WithObserver(observer)
+
+	got, err := Fetch(context.Background(), store, "courses", "id-1", func() (course, error) {
+		return course{Code: "EECS2030"}, nil
+	})
+	if err != nil || got.Code != "EECS2030" {
+		t.Errorf("Expected the loaded value despite the cache failing, got %+v, %v", got, err)
+	}
+	if observer["courses error"] != 1 {
+		t.Errorf("Expected the error to be counted, got %v", observer)
+	}
+
+	got, _ = Fetch[course](context.Background(), nil, "courses", "id-1", func() (course, error) {
+		return course{Code: "MATH1090"}, nil
+	})
+	if got.Code != "MATH1090" {
+		t.Errorf("Expected a nil store to only load, got %+v", got)
+	}
+}
+
+func TestStore_Invalidate(t *testing.T) {
+	store := NewStore(NewMemory(100), time.Minute)
+	ctx := context.Background()
+	loads := map[string]int{}
+	fetch := func(namespace string) {
+		Fetch(ctx, store, namespace, "k", func() (int, error) {
+			loads[namespace]++
+			return 1, nil
+		})
+	}
+
+	fetch("courses")
+	fetch("sections")
+	if err := store.Invalidate(ctx, "courses"); err != nil {
+		t.Fatalf("Invalidate: %v", err)
+	}
+	fetch("courses")
+	fetch("sections")
+	if loads["courses"] != 2 || loads["sections"] != 1 {
+		t.Errorf("Expected only courses to be reloaded, got %v", loads)
+	}
+
+	store.Invalidate(ctx)
+	fetch("sections")
+	if loads["sections"] != 2 {
+		t.Errorf("Expected everything to be dropped, got %v", loads)
+	}
+}
+
+func TestStore_Delete(t *testing.T) {
+	observer := countingObserver{}
+	store := NewStore(NewMemory(100), time.Minute).WithObserver(observer)
+	ctx := context.Background()
+	loads := map[string]int{}
+	fetch := func(key string) {
+		Fetch(ctx, store, "courses", key, func() (int, error) {
+			loads[key]++
+			return 1, nil
+		})
+	}
+
+	fetch("id:1")
+	fetch("id:2")
+	if err := store.Delete(ctx, "courses", "id:1", "id:3"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	fetch("id:1")
+	fetch("id:2")
+	if loads["id:1"] != 2 || loads["id:2"] != 1 {
+		t.Errorf("Expected only the deleted key to be reloaded, got %v", loads)
+	}
+	if observer["courses key"] != 2 {
+		t.Errorf("Expected 2 keys counted as invalidated, got %v", observer)
+	}
+
+	failing := NewStore(failingBackend{}, time.Minute).WithObserver(observer)
+	if err := failing.Delete(ctx, "sections", "c1"); err == nil {
+		t.Error("Expected the backend error")
+	}
+	if err := failing.Invalidate(ctx, "instructors"); err == nil {
+		t.Error("Expected the backend error")
+	}
+	if observer["sections stale"] != 1 || observer["instructors stale"] != 1 {
+		t.Errorf("Expected failed invalidations counted as stale, got %v", observer)
+	}
+}
+
+func TestStore_WithTTL(t *testing.T) {
+	memory := NewMemory(100)
+	now := time.Now()
+	memory.now = func() time.Time { return now }
+	store := NewStore(memory, time.Hour).WithTTL("instructors", time.Minute)
+	loads := 0
+	fetch := func(namespace string) {
+		Fetch(context.Background(), store, namespace, "k", func() (int, error) {
+			loads++
+			return 1, nil
+		})
+	}
+
+	fetch("instructors")
+	fetch("courses")
+	now = now.Add(2 * time.Minute)
+	fetch("instructors")
+	fetch("courses")
+	if loads != 3 {
+		t.Errorf("Expected only the short-lived namespace to expire, got %d loads", loads)
+	}
+}
+
+func TestStore_Ping(t *testing.T) {
+	if err := NewStore(NewMemory(10), time.Minute).Ping(context.Background()); err != nil {
+		t.Errorf("Expected memory to always answer, got %v", err)
+	}
+
+	_, addr := startFakeRedis(t, "")
+	redis, _ := NewRedis("redis://"+addr, 1)
+	if err := NewStore(redis, time.Minute).Ping(context.Background()); err != nil {
+		t.Errorf("Expected Redis to answer, got %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Memory is an in-process Backend for when there is no Redis. Each instance
+// has its own copy, so an invalidation only reaches the instance it runs on;
+// the TTL bounds how stale the others get.
+type Memory struct {
+	mu         sync.Mutex
+	entries    map[string]memoryEntry
+	maxEntries int
+	now        func() time.Time
+}
+
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewMemory holds at most maxEntries values. When full, expired entries are
+// dropped first and then arbitrary ones.
+func NewMemory(maxEntries int) *Memory {
+	return &Memory{entries: map[string]memoryEntry{}, maxEntries: maxEntries, now: time.Now}
+}
+
+func (m *Memory) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	if !m.now().Before(entry.expiresAt) {
+		delete(m.entries, key)
+		return nil, false, nil
+	}
+	return entry.value, true, nil
+}
+
+func (m *Memory) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if m.maxEntries < 1 || ttl <= 0 {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	if _, exists := m.entries[key]; !exists && len(m.entries) >= m.maxEntries {
+		m.evict(now)
+	}
+	m.entries[key] = memoryEntry{value: value, expiresAt: now.Add(ttl)}
+	return nil
+}
+
+func (m *Memory) Delete(ctx context.Context, keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, key := range keys {
+		delete(m.entries, key)
+	}
+	return nil
+}
+
+func (m *Memory) DeletePrefix(ctx context.Context, prefix string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key := range m.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(m.entries, key)
+		}
+	}
+	return nil
+}
+
+// evict makes room for one entry. Callers hold mu.
+func (m *Memory) evict(now time.Time) {
+	for key, entry := range m.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(m.entries, key)
+		}
+	}
+	for key := range m.entries {
+		if len(m.entries) < m.maxEntries {
+			return
+		}
+		delete(m.entries, key)
+	}
+}
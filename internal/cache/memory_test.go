@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemory(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory(2)
+	now := time.Now()
+	m.now = func() time.Time { return now }
+
+	m.Set(ctx, "a", []byte("1"), time.Minute)
+	if value, ok, _ := m.Get(ctx, "a"); !ok || string(value) != "1" {
+		t.Errorf("Expected a cached value, got %q, %v", value, ok)
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok, _ := m.Get(ctx, "a"); ok {
+		t.Error("Expected the value to expire")
+	}
+
+	m.Set(ctx, "a", []byte("1"), time.Minute)
+	m.Set(ctx, "b", []byte("2"), time.Minute)
+	m.Set(ctx, "c", []byte("3"), time.Minute)
+	if len(m.entries) != 2 {
+		t.Errorf("Expected at most 2 entries, got %d", len(m.entries))
+	}
+	if _, ok, _ := m.Get(ctx, "c"); !ok {
+		t.Error("Expected the newest value to be kept")
+	}
+}
+
+func TestMemory_DeletePrefix(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory(10)
+	m.Set(ctx, "yuplan:courses:1", []byte("1"), time.Minute)
+	m.Set(ctx, "yuplan:sections:1", []byte("1"), time.Minute)
+
+	m.Delete(ctx, "yuplan:sections:1", "yuplan:sections:2")
+	if _, ok, _ := m.Get(ctx, "yuplan:sections:1"); ok {
+		t.Error("Expected the key to be deleted")
+	}
+	m.Set(ctx, "yuplan:sections:1", []byte("1"), time.Minute)
+
+	m.DeletePrefix(ctx, "yuplan:courses:")
+	if _, ok, _ := m.Get(ctx, "yuplan:courses:1"); ok {
+		t.Error("Expected the prefix to be deleted")
+	}
+	if _, ok, _ := m.Get(ctx, "yuplan:sections:1"); !ok {
+		t.Error("Expected other keys to be kept")
+	}
+}
@@ -0,0 +1,277 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultRedisTimeout bounds a command when ctx has no deadline of its own.
+// A cache slower than the database it stands in for isn't worth waiting on.
+const defaultRedisTimeout = 250 * time.Millisecond
+
+// Redis is a Backend speaking the Redis protocol (RESP2) to one server. It
+// only uses the handful of commands the cache needs, over a small pool of
+// connections.
+type Redis struct {
+	addr     string
+	username string
+	password string
+	db       int
+	idle     chan *redisConn
+	dial     func(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+type redisConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// NewRedis connects lazily to the server in rawURL, e.g.
+// redis://:password@localhost:6379/0, keeping up to maxIdle connections open.
+func NewRedis(rawURL string, maxIdle int) (*Redis, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse REDIS_URL: %w", err)
+	}
+	if u.Scheme != "redis" {
+		return nil, fmt.Errorf("REDIS_URL must start with redis://, got %q", u.Scheme)
+	}
+	r := &Redis{
+		addr: u.Host,
+		idle: make(chan *redisConn, max(maxIdle, 1)),
+		dial: (&net.Dialer{Timeout: time.Second}).DialContext,
+	}
+	if u.Port() == "" {
+		r.addr = net.JoinHostPort(u.Hostname(), "6379")
+	}
+	if u.User != nil {
+		r.username = u.User.Username()
+		r.password, _ = u.User.Password()
+	}
+	if path := strings.Trim(u.Path, "/"); path != "" {
+		if r.db, err = strconv.Atoi(path); err != nil {
+			return nil, fmt.Errorf("REDIS_URL database must be a number, got %q", path)
+		}
+	}
+	return r, nil
+}
+
+func (r *Redis) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	reply, err := r.do(ctx, "GET", key)
+	if err != nil {
+		return nil, false, err
+	}
+	if reply == nil {
+		return nil, false, nil
+	}
+	value, ok := reply.([]byte)
+	if !ok {
+		return nil, false, fmt.Errorf("GET %s: unexpected reply %T", key, reply)
+	}
+	return value, true, nil
+}
+
+func (r *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+	_, err := r.do(ctx, "SET", key, string(value), "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	return err
+}
+
+func (r *Redis) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	_, err := r.do(ctx, append([]string{"UNLINK"}, keys...)...)
+	return err
+}
+
+// DeletePrefix walks the keyspace with SCAN rather than KEYS, so a large
+// database isn't blocked while the keys are found.
+func (r *Redis) DeletePrefix(ctx context.Context, prefix string) error {
+	pattern := escapeGlob(prefix) + "*"
+	cursor := "0"
+	for {
+		reply, err := r.do(ctx, "SCAN", cursor, "MATCH", pattern, "COUNT", "500")
+		if err != nil {
+			return err
+		}
+		page, ok := reply.([]any)
+		if !ok || len(page) != 2 {
+			return fmt.Errorf("SCAN: unexpected reply %v", reply)
+		}
+		next, _ := page[0].([]byte)
+		keys, _ := page[1].([]any)
+		if len(keys) > 0 {
+			args := make([]string, 0, len(keys)+1)
+			args = append(args, "UNLINK")
+			for _, k := range keys {
+				if b, ok := k.([]byte); ok {
+					args = append(args, string(b))
+				}
+			}
+			if _, err := r.do(ctx, args...); err != nil {
+				return err
+			}
+		}
+		if cursor = string(next); cursor == "0" || cursor == "" {
+			return nil
+		}
+	}
+}
+
+// Ping checks the server can be reached and the credentials work.
+func (r *Redis) Ping(ctx context.Context) error {
+	_, err := r.do(ctx, "PING")
+	return err
+}
+
+// do runs one command and returns its reply: nil, []byte, int64, string
+// (status) or []any. Redis errors are returned as errors. A connection that
+// failed mid-command is closed rather than reused.
+func (r *Redis) do(ctx context.Context, args ...string) (any, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultRedisTimeout)
+		defer cancel()
+	}
+	c, err := r.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	deadline, _ := ctx.Deadline()
+	c.conn.SetDeadline(deadline)
+
+	reply, err := c.roundTrip(args)
+	var redisErr redisError
+	if err != nil && !errors.As(err, &redisErr) {
+		c.conn.Close()
+		return nil, fmt.Errorf("redis %s: %w", args[0], err)
+	}
+	r.put(c)
+	if err != nil {
+		return nil, fmt.Errorf("redis %s: %w", args[0], err)
+	}
+	return reply, nil
+}
+
+func (r *Redis) get(ctx context.Context) (*redisConn, error) {
+	select {
+	case c := <-r.idle:
+		return c, nil
+	default:
+	}
+	conn, err := r.dial(ctx, "tcp", r.addr)
+	if err != nil {
+		return nil, fmt.Errorf("connect to redis: %w", err)
+	}
+	c := &redisConn{conn: conn, r: bufio.NewReader(conn)}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	if r.password != "" {
+		auth := []string{"AUTH", r.password}
+		if r.username != "" {
+			auth = []string{"AUTH", r.username, r.password}
+		}
+		if _, err := c.roundTrip(auth); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("redis AUTH: %w", err)
+		}
+	}
+	if r.db != 0 {
+		if _, err := c.roundTrip([]string{"SELECT", strconv.Itoa(r.db)}); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("redis SELECT: %w", err)
+		}
+	}
+	return c, nil
+}
+
+func (r *Redis) put(c *redisConn) {
+	select {
+	case r.idle <- c:
+	default:
+		c.conn.Close()
+	}
+}
+
+// redisError is an error reply, after which the connection is still usable.
+type redisError string
+
+func (e redisError) Error() string { return string(e) }
+
+func (c *redisConn) roundTrip(args []string) (any, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(c.conn, b.String()); err != nil {
+		return nil, err
+	}
+	return readReply(c.r)
+}
+
+func readReply(r *bufio.Reader) (any, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || !strings.HasSuffix(line, "\r\n") {
+		return nil, fmt.Errorf("malformed reply %q", line)
+	}
+	kind, body := line[0], line[1:len(line)-2]
+	switch kind {
+	case '+':
+		return body, nil
+	case '-':
+		return nil, redisError(body)
+	case ':':
+		return strconv.ParseInt(body, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, fmt.Errorf("malformed bulk length %q", body)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		return buf[:n], nil
+	case '*':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, fmt.Errorf("malformed array length %q", body)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		items := make([]any, n)
+		for i := range items {
+			if items[i], err = readReply(r); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("unknown reply type %q", kind)
+	}
+}
+
+// escapeGlob escapes the characters SCAN MATCH treats as a pattern.
+func escapeGlob(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`).Replace(s)
+}
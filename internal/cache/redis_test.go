@@ -0,0 +1,196 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRedis answers the commands Redis uses from an in-memory map.
+type fakeRedis struct {
+	mu       sync.Mutex
+	data     map[string]string
+	commands []string
+	password string
+}
+
+func startFakeRedis(t *testing.T, password string) (*fakeRedis, string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	f := &fakeRedis{data: map[string]string{}, password: password}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+	return f, ln.Addr().String()
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	authed := f.password == ""
+	for {
+		reply, err := readReply(r)
+		if err != nil {
+			return
+		}
+		items := reply.([]any)
+		args := make([]string, len(items))
+		for i, item := range items {
+			args[i] = string(item.([]byte))
+		}
+
+		f.mu.Lock()
+		f.commands = append(f.commands, strings.Join(args, " "))
+		var out string
+		switch {
+		case args[0] == "AUTH":
+			authed = args[len(args)-1] == f.password
+			out = "+OK\r\n"
+			if !authed {
+				out = "-WRONGPASS invalid password\r\n"
+			}
+		case !authed:
+			out = "-NOAUTH Authentication required.\r\n"
+		case args[0] == "PING":
+			out = "+PONG\r\n"
+		case args[0] == "SELECT":
+			out = "+OK\r\n"
+		case args[0] == "GET":
+			if v, ok := f.data[args[1]]; ok {
+				out = fmt.Sprintf("$%d\r\n%s\r\n", len(v), v)
+			} else {
+				out = "$-1\r\n"
+			}
+		case args[0] == "SET":
+			f.data[args[1]] = args[2]
+			out = "+OK\r\n"
+		case args[0] == "SCAN":
+			prefix := strings.TrimSuffix(args[3], "*")
+			var keys []string
+			for k := range f.data {
+				if strings.HasPrefix(k, prefix) {
+					keys = append(keys, k)
+				}
+			}
+			out = fmt.Sprintf("*2\r\n$1\r\n0\r\n*%d\r\n", len(keys))
+			for _, k := range keys {
+				out += fmt.Sprintf("$%d\r\n%s\r\n", len(k), k)
+			}
+		case args[0] == "UNLINK":
+			for _, k := range args[1:] {
+				delete(f.data, k)
+			}
+			out = fmt.Sprintf(":%d\r\n", len(args)-1)
+		default:
+			out = "-ERR unknown command\r\n"
+		}
+		f.mu.Unlock()
+		conn.Write([]byte(out))
+	}
+}
+
+func TestRedis(t *testing.T) {
+	fake, addr := startFakeRedis(t, "s3cret")
+	r, err := NewRedis("redis://:s3cret@"+addr+"/2", 2)
+	if err != nil {
+		t.Fatalf("NewRedis: %v", err)
+	}
+	ctx := context.Background()
+
+	if err := r.Ping(ctx); err != nil {
+		t.Fatalf("Ping: %v", err)
+	}
+	if _, ok, err := r.Get(ctx, "yuplan:courses:1"); ok || err != nil {
+		t.Errorf("Expected a miss, got %v, %v", ok, err)
+	}
+	if err := r.Set(ctx, "yuplan:courses:1", []byte(`{"code":"EECS2030"}`), time.Minute); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	r.Set(ctx, "yuplan:sections:1", []byte(`[]`), time.Minute)
+	value, ok, err := r.Get(ctx, "yuplan:courses:1")
+	if err != nil || !ok || string(value) != `{"code":"EECS2030"}` {
+		t.Errorf("Expected the stored value, got %q, %v, %v", value, ok, err)
+	}
+
+	r.Set(ctx, "yuplan:courses:2", []byte(`{}`), time.Minute)
+	if err := r.Delete(ctx, "yuplan:courses:2", "yuplan:courses:3"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, ok, _ := r.Get(ctx, "yuplan:courses:2"); ok {
+		t.Error("Expected the key to be deleted")
+	}
+
+	if err := r.DeletePrefix(ctx, "yuplan:courses:"); err != nil {
+		t.Fatalf("DeletePrefix: %v", err)
+	}
+	if _, ok, _ := r.Get(ctx, "yuplan:courses:1"); ok {
+		t.Error("Expected the prefix to be deleted")
+	}
+	if _, ok, _ := r.Get(ctx, "yuplan:sections:1"); !ok {
+		t.Error("Expected other keys to be kept")
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if fake.commands[0] != "AUTH s3cret" || fake.commands[1] != "SELECT 2" {
+		t.Errorf("Expected AUTH and SELECT on connect, got %v", fake.commands[:2])
+	}
+	if !containsCommand(fake.commands, `SET yuplan:courses:1 {"code":"EECS2030"} PX 60000`) {
+		t.Errorf("Expected SET with a millisecond expiry, got %v", fake.commands)
+	}
+	auths := 0
+	for _, c := range fake.commands {
+		if strings.HasPrefix(c, "AUTH") {
+			auths++
+		}
+	}
+	if auths != 1 {
+		t.Errorf("Expected the connection to be reused, got %d AUTHs", auths)
+	}
+}
+
+func TestRedis_Errors(t *testing.T) {
+	_, addr := startFakeRedis(t, "s3cret")
+	r, _ := NewRedis("redis://:wrong@"+addr, 1)
+	if err := r.Ping(context.Background()); err == nil {
+		t.Error("Expected a bad password to be reported")
+	}
+
+	ln, _ := net.Listen("tcp", "127.0.0.1:0")
+	closedAddr := ln.Addr().String()
+	ln.Close()
+	r, _ = NewRedis("redis://"+closedAddr, 1)
+	if _, _, err := r.Get(context.Background(), "k"); err == nil {
+		t.Error("Expected an unreachable server to be reported")
+	}
+
+	for _, bad := range []string{"http://localhost:6379", "redis://localhost:6379/zero"} {
+		if _, err := NewRedis(bad, 1); err == nil {
+			t.Errorf("Expected %q to be rejected", bad)
+		}
+	}
+}
+
+func containsCommand(commands []string, want string) bool {
+	for _, c := range commands {
+		if c == want {
+			return true
+		}
+	}
+	return false
+}
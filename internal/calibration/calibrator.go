@@ -0,0 +1,144 @@
+// Package calibration puts course difficulty on a common scale by comparing
+// each course with the other courses in its department.
+package calibration
+
+import (
+	"context"
+	"log"
+	"math"
+	"sort"
+	"time"
+	"yuplan/internal/jobs"
+	"yuplan/internal/models"
+)
+
+// Store reads course difficulty and writes department baselines. Implemented by repository.CalibrationRepository.
+type Store interface {
+	ListCourseDifficulties(ctx context.Context, since time.Time) ([]models.CourseDifficulty, error)
+	ReplaceCalibration(ctx context.Context, departments []models.DepartmentDifficulty) error
+}
+
+// jobLocker keeps scheduled runs to one instance at a time, once per tick. Implemented by jobs.Locker.
+type jobLocker interface {
+	Do(ctx context.Context, job string, tick time.Time, fn func(ctx context.Context) error) (bool, error)
+}
+
+// Calibrator rebuilds difficulty_calibration from reviews.
+type Calibrator struct {
+	store  Store
+	window time.Duration
+	locker jobLocker
+}
+
+// NewCalibrator builds department baselines from reviews created within
+// window, the same window course review stats use by default.
+func NewCalibrator(store Store, window time.Duration) *Calibrator {
+	return &Calibrator{store: store, window: window}
+}
+
+// WithLocker makes Start skip runs while another instance holds the calibration lock.
+func (c *Calibrator) WithLocker(locker jobLocker) *Calibrator {
+	c.locker = locker
+	return c
+}
+
+// Run recomputes every department's baseline and returns how many departments have one.
+func (c *Calibrator) Run(ctx context.Context) (int, error) {
+	courses, err := c.store.ListCourseDifficulties(ctx, time.Now().UTC().Add(-c.window))
+	if err != nil {
+		return 0, err
+	}
+
+	departments := Baselines(courses)
+	if err := c.store.ReplaceCalibration(ctx, departments); err != nil {
+		return 0, err
+	}
+	return len(departments), nil
+}
+
+// Baselines returns the mean and population standard deviation of course
+// average difficulty per department, counting only courses with at least
+// models.CalibrationMinReviews reviews. Departments with fewer than
+// models.CalibrationMinCourses such courses, or no spread at all, are left
+// out since a z-score against them would mean nothing.
+func Baselines(courses []models.CourseDifficulty) []models.DepartmentDifficulty {
+	averages := map[string][]float64{}
+	for _, c := range courses {
+		if c.Reviews < models.CalibrationMinReviews {
+			continue
+		}
+		dept := models.DepartmentOf(c.CourseCode)
+		averages[dept] = append(averages[dept], c.AvgDifficulty)
+	}
+
+	departments := make([]models.DepartmentDifficulty, 0, len(averages))
+	for dept, values := range averages {
+		if len(values) < models.CalibrationMinCourses {
+			continue
+		}
+		var sum float64
+		for _, v := range values {
+			sum += v
+		}
+		mean := sum / float64(len(values))
+		var squares float64
+		for _, v := range values {
+			squares += (v - mean) * (v - mean)
+		}
+		stddev := math.Sqrt(squares / float64(len(values)))
+		if stddev == 0 {
+			continue
+		}
+		departments = append(departments, models.DepartmentDifficulty{
+			Department: dept,
+			Mean:       mean,
+			StdDev:     stddev,
+			Courses:    len(values),
+		})
+	}
+	sort.Slice(departments, func(i, j int) bool { return departments[i].Department < departments[j].Department })
+	return departments
+}
+
+// Calibrate places a course's average difficulty against its department's baseline.
+func Calibrate(avgDifficulty float64, baseline models.DepartmentDifficulty) models.CalibratedDifficulty {
+	z := (avgDifficulty - baseline.Mean) / baseline.StdDev
+	return models.CalibratedDifficulty{
+		ZScore:     math.Round(z*100) / 100,
+		Method:     models.CalibrationMethod,
+		MinReviews: models.CalibrationMinReviews,
+		Baseline:   baseline,
+	}
+}
+
+// Start calibrates immediately and then every interval until ctx is done.
+func (c *Calibrator) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := c.runScheduled(ctx, jobs.Tick(time.Now(), interval)); err != nil {
+				log.Printf("difficulty calibration failed: %v", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (c *Calibrator) runScheduled(ctx context.Context, tick time.Time) error {
+	run := func(ctx context.Context) error {
+		_, err := c.Run(ctx)
+		return err
+	}
+	if c.locker == nil {
+		return run(ctx)
+	}
+	_, err := c.locker.Do(ctx, "difficulty_calibration", tick, run)
+	return err
+}
@@ -0,0 +1,110 @@
+package calibration
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeStore struct {
+	courses  []models.CourseDifficulty
+	listErr  error
+	gotSince time.Time
+	saved    []models.DepartmentDifficulty
+	calls    int
+}
+
+func (f *fakeStore) ListCourseDifficulties(ctx context.Context, since time.Time) ([]models.CourseDifficulty, error) {
+	f.gotSince = since
+	return f.courses, f.listErr
+}
+
+func (f *fakeStore) ReplaceCalibration(ctx context.Context, departments []models.DepartmentDifficulty) error {
+	f.saved = departments
+	f.calls++
+	return nil
+}
+
+type fakeLocker struct {
+	held bool
+}
+
+func (f *fakeLocker) Do(ctx context.Context, job string, tick time.Time, fn func(ctx context.Context) error) (bool, error) {
+	if f.held {
+		return false, nil
+	}
+	return true, fn(ctx)
+}
+
+func TestBaselines(t *testing.T) {
+	departments := Baselines([]models.CourseDifficulty{
+		{CourseCode: "EECS1012", AvgDifficulty: 2, Reviews: 10},
+		{CourseCode: "EECS2030", AvgDifficulty: 3, Reviews: 5},
+		{CourseCode: "EECS3101", AvgDifficulty: 4, Reviews: 3},
+		{CourseCode: "EECS4101", AvgDifficulty: 5, Reviews: 2}, // too few reviews to count
+		{CourseCode: "MATH1013", AvgDifficulty: 3, Reviews: 9},
+		{CourseCode: "MATH1014", AvgDifficulty: 4, Reviews: 9}, // MATH has too few courses
+		{CourseCode: "HUMA1000", AvgDifficulty: 2, Reviews: 4},
+		{CourseCode: "HUMA1100", AvgDifficulty: 2, Reviews: 4},
+		{CourseCode: "HUMA1200", AvgDifficulty: 2, Reviews: 4}, // HUMA has no spread
+	})
+
+	assert.Len(t, departments, 1)
+	assert.Equal(t, "EECS", departments[0].Department)
+	assert.Equal(t, 3, departments[0].Courses)
+	assert.InDelta(t, 3.0, departments[0].Mean, 1e-9)
+	assert.InDelta(t, 0.8165, departments[0].StdDev, 1e-4)
+}
+
+func TestCalibrate(t *testing.T) {
+	baseline := models.DepartmentDifficulty{Department: "EECS", Mean: 3, StdDev: 0.5, Courses: 12}
+
+	calibrated := Calibrate(3.8, baseline)
+
+	assert.Equal(t, models.CalibratedDifficulty{
+		ZScore:     1.6,
+		Method:     models.CalibrationMethod,
+		MinReviews: models.CalibrationMinReviews,
+		Baseline:   baseline,
+	}, calibrated)
+}
+
+func TestCalibrator_Run(t *testing.T) {
+	store := &fakeStore{courses: []models.CourseDifficulty{
+		{CourseCode: "EECS1012", AvgDifficulty: 2, Reviews: 10},
+		{CourseCode: "EECS2030", AvgDifficulty: 3, Reviews: 5},
+		{CourseCode: "EECS3101", AvgDifficulty: 4, Reviews: 3},
+	}}
+
+	n, err := NewCalibrator(store, 30*24*time.Hour).Run(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Len(t, store.saved, 1)
+	assert.WithinDuration(t, time.Now().Add(-30*24*time.Hour), store.gotSince, time.Minute)
+}
+
+func TestCalibrator_RunListError(t *testing.T) {
+	store := &fakeStore{listErr: errors.New("db down")}
+
+	_, err := NewCalibrator(store, time.Hour).Run(context.Background())
+	assert.Error(t, err)
+	assert.Zero(t, store.calls)
+}
+
+func TestCalibrator_ScheduledRunsRespectLocker(t *testing.T) {
+	store := &fakeStore{}
+	locker := &fakeLocker{held: true}
+	calibrator := NewCalibrator(store, time.Hour).WithLocker(locker)
+
+	assert.NoError(t, calibrator.runScheduled(context.Background(), time.Now()))
+	assert.Zero(t, store.calls)
+
+	locker.held = false
+	assert.NoError(t, calibrator.runScheduled(context.Background(), time.Now()))
+	assert.Equal(t, 1, store.calls)
+}
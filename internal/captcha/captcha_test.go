@@ -0,0 +1,74 @@
+package captcha
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSiteVerify(t *testing.T) {
+	var form map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		form = map[string]string{"secret": r.PostForm.Get("secret"), "response": r.PostForm.Get("response"), "remoteip": r.PostForm.Get("remoteip")}
+		switch r.PostForm.Get("response") {
+		case "good":
+			w.Write([]byte(`{"success": true}`))
+		default:
+			w.Write([]byte(`{"success": false, "error-codes": ["invalid-input-response"]}`))
+		}
+	}))
+	defer server.Close()
+
+	v := NewSiteVerify(server.URL, "s3cret")
+	ok, err := v.Verify(context.Background(), "good", "10.0.0.1")
+	if err != nil || !ok {
+		t.Fatalf("Expected a good token to pass, got %v, %v", ok, err)
+	}
+	if form["secret"] != "s3cret" || form["response"] != "good" || form["remoteip"] != "10.0.0.1" {
+		t.Errorf("Expected the secret, token and client IP to be sent, got %v", form)
+	}
+
+	if ok, err := v.Verify(context.Background(), "bad", ""); err != nil || ok {
+		t.Errorf("Expected a bad token to fail without an error, got %v, %v", ok, err)
+	}
+
+	form = nil
+	if ok, err := v.Verify(context.Background(), "", ""); err != nil || ok || form != nil {
+		t.Errorf("Expected a missing token to fail without asking the provider, got %v, %v", ok, err)
+	}
+}
+
+func TestSiteVerify_ProviderErrors(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(`{"success": false, "error-codes": ["invalid-input-secret"]}`))
+	}))
+	defer server.Close()
+
+	v := NewSiteVerify(server.URL, "wrong")
+	if _, err := v.Verify(context.Background(), "token", ""); err == nil {
+		t.Error("Expected a bad secret to be reported as an error")
+	}
+	status = http.StatusBadGateway
+	if _, err := v.Verify(context.Background(), "token", ""); err == nil {
+		t.Error("Expected a failed request to be reported as an error")
+	}
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(ProviderTurnstile, "secret"); err != nil {
+		t.Errorf("Expected turnstile to be supported, got %v", err)
+	}
+	if _, err := New(ProviderHCaptcha, "secret"); err != nil {
+		t.Errorf("Expected hcaptcha to be supported, got %v", err)
+	}
+	if _, err := New("recaptcha", "secret"); err == nil {
+		t.Error("Expected an unknown provider to be rejected")
+	}
+	if _, err := New(ProviderTurnstile, ""); err == nil {
+		t.Error("Expected a missing secret to be rejected")
+	}
+}
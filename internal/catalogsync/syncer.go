@@ -0,0 +1,144 @@
+// Package catalogsync re-scrapes the catalog on a cron schedule and keeps a
+// history of each run and the rows it changed.
+package catalogsync
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+	"yuplan/internal/jobs"
+	"yuplan/internal/models"
+	"yuplan/internal/scraper"
+)
+
+// Loader loads timetable pages into the catalog. Implemented by scraper.Scraper.
+type Loader interface {
+	Run(ctx context.Context, term models.Term, pages []scraper.Page) (scraper.Summary, error)
+}
+
+// History records sync runs. Implemented by repository.SyncRepository.
+type History interface {
+	Start(ctx context.Context, term string) (int64, error)
+	Finish(ctx context.Context, run models.SyncRun) error
+}
+
+// jobLocker keeps scheduled runs to one instance at a time, once per tick. Implemented by jobs.Locker.
+type jobLocker interface {
+	Do(ctx context.Context, job string, tick time.Time, fn func(ctx context.Context) error) (bool, error)
+}
+
+// Syncer re-runs catalog ingestion for one term.
+type Syncer struct {
+	loader  Loader
+	history History
+	term    models.Term
+	pages   []scraper.Page
+	locker  jobLocker
+	running atomic.Bool // a scheduled or triggered run is under way here
+}
+
+func NewSyncer(loader Loader, history History, term models.Term, pages []scraper.Page) *Syncer {
+	return &Syncer{loader: loader, history: history, term: term, pages: pages}
+}
+
+// WithLocker makes Start skip runs while another instance holds the sync lock.
+func (s *Syncer) WithLocker(locker jobLocker) *Syncer {
+	s.locker = locker
+	return s
+}
+
+// Run loads the term's pages and records the run in the sync history,
+// whether or not it succeeds. A run cut short by ctx is still recorded as
+// failed.
+func (s *Syncer) Run(ctx context.Context) (models.SyncRun, error) {
+	id, err := s.history.Start(ctx, s.term.ID)
+	if err != nil {
+		return models.SyncRun{}, err
+	}
+
+	summary, runErr := s.loader.Run(ctx, s.term, s.pages)
+	run := models.SyncRun{
+		ID:          id,
+		Term:        s.term.ID,
+		Status:      models.SyncSucceeded,
+		Pages:       summary.Pages,
+		Quarantined: summary.Quarantined,
+		Diff:        summary.Diff,
+	}
+	if runErr != nil {
+		run.Status = models.SyncFailed
+		run.Error = runErr.Error()
+	}
+	if err := s.history.Finish(context.WithoutCancel(ctx), run); err != nil && runErr == nil {
+		return run, err
+	}
+	return run, runErr
+}
+
+// Start runs at each time schedule fires until ctx is done. Unlike the
+// interval jobs it doesn't run at startup: a full re-scrape on every deploy
+// would hammer the timetable site.
+func (s *Syncer) Start(ctx context.Context, schedule jobs.Schedule) {
+	go func() {
+		for {
+			next := schedule.Next(time.Now())
+			if next.IsZero() {
+				log.Printf("catalog sync schedule %q never fires; not syncing", schedule)
+				return
+			}
+			timer := time.NewTimer(time.Until(next))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+
+			if err := s.runScheduled(ctx, next); err != nil {
+				log.Printf("catalog sync failed: %v", err)
+			}
+		}
+	}()
+}
+
+// Trigger starts a run now, in the background and off the schedule, and
+// reports whether it did: false while a run on this instance is under way.
+// It takes the same lock as scheduled runs, so it is skipped if another
+// instance is syncing. The run outlives ctx, which is usually a request's.
+func (s *Syncer) Trigger(ctx context.Context) bool {
+	if !s.running.CompareAndSwap(false, true) {
+		return false
+	}
+	go func() {
+		defer s.running.Store(false)
+		if err := s.runLocked(context.WithoutCancel(ctx), time.Time{}); err != nil {
+			log.Printf("triggered catalog sync failed: %v", err)
+		}
+	}()
+	return true
+}
+
+func (s *Syncer) runScheduled(ctx context.Context, tick time.Time) error {
+	if !s.running.CompareAndSwap(false, true) {
+		log.Printf("catalog sync still running; skipping scheduled run")
+		return nil
+	}
+	defer s.running.Store(false)
+	return s.runLocked(ctx, tick)
+}
+
+func (s *Syncer) runLocked(ctx context.Context, tick time.Time) error {
+	run := func(ctx context.Context) error {
+		r, err := s.Run(ctx)
+		if err == nil {
+			log.Printf("catalog sync %d of %s: %d pages, %d quarantined, %+v", r.ID, r.Term, r.Pages, r.Quarantined, r.Diff)
+		}
+		return err
+	}
+	if s.locker == nil {
+		return run(ctx)
+	}
+	_, err := s.locker.Do(ctx, "catalog_sync", tick, run)
+	return err
+}
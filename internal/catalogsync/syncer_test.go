@@ -0,0 +1,121 @@
+package catalogsync
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"yuplan/internal/models"
+	"yuplan/internal/scraper"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeLoader struct {
+	summary scraper.Summary
+	err     error
+	runs    int
+}
+
+func (f *fakeLoader) Run(ctx context.Context, term models.Term, pages []scraper.Page) (scraper.Summary, error) {
+	f.runs++
+	return f.summary, f.err
+}
+
+type fakeHistory struct {
+	started  []string
+	finished []models.SyncRun
+}
+
+func (f *fakeHistory) Start(ctx context.Context, term string) (int64, error) {
+	f.started = append(f.started, term)
+	return int64(len(f.started)), nil
+}
+
+func (f *fakeHistory) Finish(ctx context.Context, run models.SyncRun) error {
+	f.finished = append(f.finished, run)
+	return nil
+}
+
+type fakeLocker struct {
+	held bool
+}
+
+func (f *fakeLocker) Do(ctx context.Context, job string, tick time.Time, fn func(ctx context.Context) error) (bool, error) {
+	if f.held {
+		return false, nil
+	}
+	return true, fn(ctx)
+}
+
+func TestSyncer_Run(t *testing.T) {
+	term, _ := models.NewTerm("FW2025")
+	diff := models.CatalogDiff{Courses: models.RowDiff{Added: 2}, Activities: models.RowDiff{Changed: 5, Removed: 1}}
+	loader := &fakeLoader{summary: scraper.Summary{Pages: 10, Quarantined: 3, Diff: diff}}
+	history := &fakeHistory{}
+
+	run, err := NewSyncer(loader, history, term, nil).Run(context.Background())
+	assert.NoError(t, err)
+	want := models.SyncRun{ID: 1, Term: "FW2025", Status: models.SyncSucceeded, Pages: 10, Quarantined: 3, Diff: diff}
+	assert.Equal(t, want, run)
+	assert.Equal(t, []string{"FW2025"}, history.started)
+	assert.Equal(t, []models.SyncRun{want}, history.finished)
+}
+
+func TestSyncer_Run_RecordsFailure(t *testing.T) {
+	term, _ := models.NewTerm("FW2025")
+	loader := &fakeLoader{summary: scraper.Summary{Pages: 4}, err: errors.New("fetch timetable: 503 Service Unavailable")}
+	history := &fakeHistory{}
+
+	_, err := NewSyncer(loader, history, term, nil).Run(context.Background())
+	assert.ErrorContains(t, err, "503")
+	assert.Len(t, history.finished, 1)
+	assert.Equal(t, models.SyncFailed, history.finished[0].Status)
+	assert.Equal(t, 4, history.finished[0].Pages)
+	assert.Equal(t, "fetch timetable: 503 Service Unavailable", history.finished[0].Error)
+}
+
+func TestSyncer_ScheduledRunsRespectLocker(t *testing.T) {
+	term, _ := models.NewTerm("FW2025")
+	loader := &fakeLoader{}
+	locker := &fakeLocker{held: true}
+	syncer := NewSyncer(loader, &fakeHistory{}, term, nil).WithLocker(locker)
+
+	assert.NoError(t, syncer.runScheduled(context.Background(), time.Now()))
+	assert.Equal(t, 0, loader.runs)
+
+	locker.held = false
+	assert.NoError(t, syncer.runScheduled(context.Background(), time.Now()))
+	assert.Equal(t, 1, loader.runs)
+}
+
+// blockingLoader holds each run until release is closed.
+type blockingLoader struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingLoader) Run(ctx context.Context, term models.Term, pages []scraper.Page) (scraper.Summary, error) {
+	b.started <- struct{}{}
+	<-b.release
+	return scraper.Summary{}, nil
+}
+
+func TestSyncer_Trigger(t *testing.T) {
+	term, _ := models.NewTerm("FW2025")
+	loader := &blockingLoader{started: make(chan struct{}, 1), release: make(chan struct{})}
+	syncer := NewSyncer(loader, &fakeHistory{}, term, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	assert.True(t, syncer.Trigger(ctx))
+	cancel() // the request ending doesn't stop the run
+	<-loader.started
+
+	// One run at a time, scheduled or not
+	assert.False(t, syncer.Trigger(context.Background()))
+	assert.NoError(t, syncer.runScheduled(context.Background(), time.Now()))
+
+	close(loader.release)
+	assert.Eventually(t, func() bool { return syncer.Trigger(context.Background()) }, time.Second, 10*time.Millisecond)
+	<-loader.started
+}
@@ -0,0 +1,192 @@
+// Package claims lets instructors claim their profile. A claim is made with an
+// institutional email and confirmed by following a link mailed to it; an admin
+// then approves or rejects it. Approval mails the instructor a token for the
+// instructor role, an admintoken JWT whose subject is the claim's id, which
+// they send as "Authorization: Bearer <token>" like staff do.
+package claims
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+	"yuplan/internal/admintoken"
+	"yuplan/internal/mailer"
+	"yuplan/internal/models"
+	"yuplan/internal/redact"
+)
+
+// ErrEmailDomain is returned for a claim made with an email outside the
+// institutional domains.
+var ErrEmailDomain = errors.New("email is not an institutional address")
+
+// DefaultDomains are the institutional domains used when none are configured.
+// Subdomains don't count: students' addresses are at my.yorku.ca.
+var DefaultDomains = []string{"yorku.ca"}
+
+// Store keeps the claims. Implemented by repository.InstructorClaimRepository.
+type Store interface {
+	// Create records an unverified claim on the instructor with the given row
+	// id. It returns nil when there is no such instructor and
+	// repository.ErrInstructorClaimed when they are already claimed.
+	Create(ctx context.Context, instructorID, email, tokenHash string, expiresAt time.Time) (*models.InstructorClaim, error)
+	// Verify makes the unverified claim an unexpired token belongs to pending,
+	// and returns it, or nil if the token is unknown or expired.
+	Verify(ctx context.Context, tokenHash string) (*models.InstructorClaim, error)
+	List(ctx context.Context, status string) ([]models.InstructorClaim, error)
+	// Decide approves or rejects a pending claim and returns it, or nil if
+	// there is no pending claim with that id. An approved claim may be
+	// approved again.
+	Decide(ctx context.Context, id, status string) (*models.InstructorClaim, error)
+}
+
+// Service takes claims, mails their confirmation links and hands approved
+// instructors their role.
+type Service struct {
+	store   Store
+	mailer  mailer.Mailer
+	tokens  *admintoken.Signer
+	linkURL string // the token is added to it as ?token=
+	ttl     time.Duration
+	roleTTL time.Duration
+	domains []string
+	now     func() time.Time
+}
+
+// New returns a Service signing instructor tokens valid for roleTTL with
+// tokens. Claims may only be made with emails at domains, DefaultDomains if
+// empty.
+func New(store Store, m mailer.Mailer, tokens *admintoken.Signer, linkURL string, ttl, roleTTL time.Duration, domains []string) *Service {
+	if len(domains) == 0 {
+		domains = DefaultDomains
+	}
+	return &Service{
+		store:   store,
+		mailer:  m,
+		tokens:  tokens,
+		linkURL: linkURL,
+		ttl:     ttl,
+		roleTTL: roleTTL,
+		domains: domains,
+		now:     time.Now,
+	}
+}
+
+// Request records a claim by email on the instructor with the given row id
+// and mails email a link to confirm it. It returns nil when there is no such
+// instructor, and ErrEmailDomain for an email outside the institutional
+// domains.
+func (s *Service) Request(ctx context.Context, instructorID, email string) (*models.InstructorClaim, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	_, domain, _ := strings.Cut(email, "@")
+	if !slices.Contains(s.domains, domain) {
+		return nil, ErrEmailDomain
+	}
+
+	token, hash, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	claim, err := s.store.Create(ctx, instructorID, email, hash, s.now().Add(s.ttl))
+	if err != nil || claim == nil {
+		return nil, err
+	}
+
+	link := s.linkURL + "?token=" + url.QueryEscape(token)
+	body := fmt.Sprintf("Someone asked to claim the YU Plan profile of %s %s with this address.\n\n"+
+		"If it was you, follow this link to confirm it. An admin will then review your claim:\n\n%s\n\n"+
+		"The link expires in %s. If it wasn't you, ignore this email and nothing will happen.\n",
+		claim.FirstName, claim.LastName, link, formatTTL(s.ttl))
+	if err := s.mailer.Send(ctx, mailer.Message{
+		To:      email,
+		Subject: "Confirm your YU Plan instructor profile claim",
+		Body:    body,
+	}); err != nil {
+		return nil, fmt.Errorf("send claim verification email: %w", err)
+	}
+	return claim, nil
+}
+
+// Verify confirms the claim token was mailed for, queueing it for an admin,
+// and returns it, or nil if the token is unknown, already used or expired.
+func (s *Service) Verify(ctx context.Context, token string) (*models.InstructorClaim, error) {
+	if token == "" {
+		return nil, nil
+	}
+	return s.store.Verify(ctx, hashToken(token))
+}
+
+// List returns the claims with status, or every claim when status is empty.
+func (s *Service) List(ctx context.Context, status string) ([]models.InstructorClaim, error) {
+	return s.store.List(ctx, status)
+}
+
+// Approve approves a pending claim, which links the instructor to its email,
+// and mails that email a token for the instructor role. Approving a claim
+// already approved mails a fresh token. It returns nil if the claim isn't
+// pending or approved, or its instructor has another approved claim.
+func (s *Service) Approve(ctx context.Context, id string) (*models.InstructorClaim, error) {
+	claim, err := s.store.Decide(ctx, id, models.ClaimApproved)
+	if err != nil || claim == nil {
+		return nil, err
+	}
+
+	expiresAt := s.now().Add(s.roleTTL)
+	token, err := s.tokens.Sign(admintoken.Claims{
+		Subject:   claim.ID,
+		Role:      string(redact.RoleInstructor),
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("sign instructor token: %w", err)
+	}
+
+	body := fmt.Sprintf("Your claim on the YU Plan profile of %s %s was approved.\n\n"+
+		"Send this token as \"Authorization: Bearer <token>\" to use the instructor features of the API:\n\n%s\n\n"+
+		"It expires on %s. Keep it private; anyone holding it can act as you.\n",
+		claim.FirstName, claim.LastName, token, expiresAt.UTC().Format("January 2, 2006"))
+	if err := s.mailer.Send(ctx, mailer.Message{
+		To:      claim.Email,
+		Subject: "Your YU Plan instructor profile claim was approved",
+		Body:    body,
+	}); err != nil {
+		return nil, fmt.Errorf("send instructor token: %w", err)
+	}
+	return claim, nil
+}
+
+// Reject rejects a pending claim and returns it, or nil if it isn't pending.
+func (s *Service) Reject(ctx context.Context, id string) (*models.InstructorClaim, error) {
+	return s.store.Decide(ctx, id, models.ClaimRejected)
+}
+
+// newToken returns a random link token and the hash stored for it.
+func newToken() (token, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("generate token: %w", err)
+	}
+	token = hex.EncodeToString(b)
+	return token, hashToken(token), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func formatTTL(d time.Duration) string {
+	if d >= 48*time.Hour && d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%d days", d/(24*time.Hour))
+	}
+	if d >= 2*time.Hour && d%time.Hour == 0 {
+		return fmt.Sprintf("%d hours", d/time.Hour)
+	}
+	return d.String()
+}
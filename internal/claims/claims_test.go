@@ -0,0 +1,166 @@
+package claims
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+	"yuplan/internal/admintoken"
+	"yuplan/internal/mailer"
+	"yuplan/internal/models"
+	"yuplan/internal/redact"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeStore struct {
+	claims map[string]*models.InstructorClaim // by id
+	tokens map[string]string                  // token hash -> claim id
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{claims: map[string]*models.InstructorClaim{}, tokens: map[string]string{}}
+}
+
+func (f *fakeStore) Create(ctx context.Context, instructorID, email, tokenHash string, expiresAt time.Time) (*models.InstructorClaim, error) {
+	if instructorID != "inst-1" {
+		return nil, nil
+	}
+	claim := &models.InstructorClaim{ID: "claim-1", FirstName: "Jackie", LastName: "Wang", Email: email, Status: models.ClaimUnverified}
+	f.claims[claim.ID] = claim
+	f.tokens[tokenHash] = claim.ID
+	return claim, nil
+}
+
+func (f *fakeStore) Verify(ctx context.Context, tokenHash string) (*models.InstructorClaim, error) {
+	id, ok := f.tokens[tokenHash]
+	if !ok {
+		return nil, nil
+	}
+	delete(f.tokens, tokenHash)
+	f.claims[id].Status = models.ClaimPending
+	return f.claims[id], nil
+}
+
+func (f *fakeStore) List(ctx context.Context, status string) ([]models.InstructorClaim, error) {
+	return nil, nil
+}
+
+func (f *fakeStore) Decide(ctx context.Context, id, status string) (*models.InstructorClaim, error) {
+	claim, ok := f.claims[id]
+	if !ok || claim.Status != models.ClaimPending {
+		return nil, nil
+	}
+	claim.Status = status
+	return claim, nil
+}
+
+type fakeMailer struct {
+	sent []mailer.Message
+	err  error
+}
+
+func (f *fakeMailer) Send(ctx context.Context, msg mailer.Message) error {
+	f.sent = append(f.sent, msg)
+	return f.err
+}
+
+// linkToken pulls the token out of the link in a claim email.
+func linkToken(t *testing.T, body string) string {
+	t.Helper()
+	for _, line := range strings.Split(body, "\n") {
+		if strings.HasPrefix(line, "https://") {
+			u, err := url.Parse(line)
+			assert.NoError(t, err)
+			return u.Query().Get("token")
+		}
+	}
+	t.Fatalf("no link in %q", body)
+	return ""
+}
+
+func TestClaimFlow(t *testing.T) {
+	store, m := newFakeStore(), &fakeMailer{}
+	tokens := admintoken.NewSigner("secret")
+	s := New(store, m, tokens, "https://yuplan.test/claims/verify", 48*time.Hour, 180*24*time.Hour, nil)
+
+	claim, err := s.Request(context.Background(), "inst-1", " JWang@YorkU.ca ")
+	assert.NoError(t, err)
+	assert.Equal(t, "jwang@yorku.ca", claim.Email)
+	assert.Len(t, m.sent, 1)
+	assert.Equal(t, "jwang@yorku.ca", m.sent[0].To)
+	assert.Contains(t, m.sent[0].Body, "Jackie Wang")
+	assert.Contains(t, m.sent[0].Body, "expires in 2 days")
+
+	// Nobody can approve a claim before its email is confirmed
+	approved, err := s.Approve(context.Background(), "claim-1")
+	assert.NoError(t, err)
+	assert.Nil(t, approved)
+
+	verified, err := s.Verify(context.Background(), linkToken(t, m.sent[0].Body))
+	assert.NoError(t, err)
+	assert.Equal(t, models.ClaimPending, verified.Status)
+
+	approved, err = s.Approve(context.Background(), "claim-1")
+	assert.NoError(t, err)
+	assert.Equal(t, models.ClaimApproved, approved.Status)
+	assert.Len(t, m.sent, 2)
+	assert.Equal(t, "jwang@yorku.ca", m.sent[1].To)
+
+	var token string
+	for _, line := range strings.Split(m.sent[1].Body, "\n") {
+		if strings.Count(line, ".") == 2 && !strings.Contains(line, " ") {
+			token = line
+		}
+	}
+	got, err := tokens.Verify(token)
+	assert.NoError(t, err)
+	assert.Equal(t, string(redact.RoleInstructor), got.Role)
+	assert.Equal(t, "claim-1", got.Subject)
+}
+
+func TestRequest_Rejections(t *testing.T) {
+	store, m := newFakeStore(), &fakeMailer{}
+	s := New(store, m, admintoken.NewSigner("secret"), "https://yuplan.test/claims/verify", time.Hour, time.Hour, nil)
+
+	_, err := s.Request(context.Background(), "inst-1", "student@my.yorku.ca")
+	assert.ErrorIs(t, err, ErrEmailDomain)
+	_, err = s.Request(context.Background(), "inst-1", "jwang@gmail.com")
+	assert.ErrorIs(t, err, ErrEmailDomain)
+
+	claim, err := s.Request(context.Background(), "missing", "jwang@yorku.ca")
+	assert.NoError(t, err)
+	assert.Nil(t, claim)
+	assert.Empty(t, m.sent)
+}
+
+func TestRequest_Domains(t *testing.T) {
+	s := New(newFakeStore(), &fakeMailer{}, nil, "https://yuplan.test/claims/verify", time.Hour, time.Hour, []string{"lassonde.yorku.ca"})
+
+	_, err := s.Request(context.Background(), "inst-1", "jwang@yorku.ca")
+	assert.ErrorIs(t, err, ErrEmailDomain)
+	claim, err := s.Request(context.Background(), "inst-1", "jwang@lassonde.yorku.ca")
+	assert.NoError(t, err)
+	assert.NotNil(t, claim)
+}
+
+func TestRequest_MailFails(t *testing.T) {
+	m := &fakeMailer{err: errors.New("smtp down")}
+	s := New(newFakeStore(), m, nil, "https://yuplan.test/claims/verify", time.Hour, time.Hour, nil)
+
+	_, err := s.Request(context.Background(), "inst-1", "jwang@yorku.ca")
+	assert.Error(t, err)
+}
+
+func TestVerify_UnknownToken(t *testing.T) {
+	s := New(newFakeStore(), &fakeMailer{}, nil, "https://yuplan.test/claims/verify", time.Hour, time.Hour, nil)
+
+	claim, err := s.Verify(context.Background(), "")
+	assert.NoError(t, err)
+	assert.Nil(t, claim)
+	claim, err = s.Verify(context.Background(), "nope")
+	assert.NoError(t, err)
+	assert.Nil(t, claim)
+}
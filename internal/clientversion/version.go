@@ -0,0 +1,59 @@
+// Package clientversion parses the X-Client-Version header sent by the mobile apps.
+package clientversion
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Header carries "<platform>/<version>", e.g. "ios/2.3.1". Browsers don't send it.
+const Header = "X-Client-Version"
+
+// Version is a major.minor.patch release number. Missing parts are zero.
+type Version [3]int
+
+// Parse accepts "2", "2.3" or "2.3.1", with an optional leading "v".
+func Parse(s string) (Version, error) {
+	var v Version
+	parts := strings.Split(strings.TrimPrefix(strings.TrimSpace(s), "v"), ".")
+	if len(parts) > len(v) {
+		return Version{}, fmt.Errorf("invalid version %q", s)
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return Version{}, fmt.Errorf("invalid version %q", s)
+		}
+		v[i] = n
+	}
+	return v, nil
+}
+
+// Less reports whether v is an older release than other.
+func (v Version) Less(other Version) bool {
+	for i := range v {
+		if v[i] != other[i] {
+			return v[i] < other[i]
+		}
+	}
+	return false
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v[0], v[1], v[2])
+}
+
+// ParseHeader splits an X-Client-Version value into a lowercase platform and version.
+func ParseHeader(value string) (platform string, version Version, err error) {
+	platform, raw, ok := strings.Cut(value, "/")
+	platform = strings.ToLower(strings.TrimSpace(platform))
+	if !ok || platform == "" {
+		return "", Version{}, fmt.Errorf("expected <platform>/<version>, got %q", value)
+	}
+	version, err = Parse(raw)
+	if err != nil {
+		return "", Version{}, err
+	}
+	return platform, version, nil
+}
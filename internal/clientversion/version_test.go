@@ -0,0 +1,46 @@
+package clientversion
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := map[string]Version{
+		"2":       {2, 0, 0},
+		"2.3":     {2, 3, 0},
+		"2.3.1":   {2, 3, 1},
+		"v1.10.0": {1, 10, 0},
+	}
+	for input, expected := range tests {
+		v, err := Parse(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, v, input)
+	}
+
+	for _, input := range []string{"", "2.x", "1.2.3.4", "-1.0"} {
+		_, err := Parse(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestVersion_Less(t *testing.T) {
+	assert.True(t, Version{1, 9, 9}.Less(Version{1, 10, 0}))
+	assert.True(t, Version{1, 0, 0}.Less(Version{2, 0, 0}))
+	assert.False(t, Version{2, 0, 0}.Less(Version{2, 0, 0}))
+	assert.False(t, Version{2, 0, 1}.Less(Version{2, 0, 0}))
+}
+
+func TestParseHeader(t *testing.T) {
+	platform, version, err := ParseHeader("iOS/2.3.1")
+	assert.NoError(t, err)
+	assert.Equal(t, "ios", platform)
+	assert.Equal(t, Version{2, 3, 1}, version)
+	assert.Equal(t, "2.3.1", version.String())
+
+	for _, value := range []string{"2.3.1", "/2.3.1", "android/latest"} {
+		_, _, err := ParseHeader(value)
+		assert.Error(t, err, value)
+	}
+}
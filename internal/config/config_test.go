@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -16,3 +17,127 @@ func TestLoadConfig(t *testing.T) {
 	assert.Equal(t, "test_database_url", config.DatabaseURL)
 	assert.Equal(t, "9090", config.Port)
 }
+
+func TestLoadConfig_ExportSettings(t *testing.T) {
+	os.Setenv("EXPORT_STORE", "s3")
+	os.Setenv("EXPORT_INTERVAL", "6h")
+	os.Setenv("EXPORT_RETENTION_DAYS", "30")
+	defer os.Unsetenv("EXPORT_STORE")
+	defer os.Unsetenv("EXPORT_INTERVAL")
+	defer os.Unsetenv("EXPORT_RETENTION_DAYS")
+
+	config := Load()
+
+	assert.Equal(t, "s3", config.ExportStore)
+	assert.Equal(t, 6*time.Hour, config.ExportInterval)
+	assert.Equal(t, 30*24*time.Hour, config.ExportRetention)
+}
+
+func TestLoadConfig_InvalidNumbersFallBackToDefaults(t *testing.T) {
+	os.Setenv("EXPORT_INTERVAL", "soon")
+	os.Setenv("EXPORT_RETENTION_DAYS", "forever")
+	defer os.Unsetenv("EXPORT_INTERVAL")
+	defer os.Unsetenv("EXPORT_RETENTION_DAYS")
+
+	config := Load()
+
+	assert.Equal(t, 24*time.Hour, config.ExportInterval)
+	assert.Equal(t, 365*24*time.Hour, config.ExportRetention)
+	assert.Equal(t, 24*time.Hour, config.OfferingRefreshInterval)
+	assert.Equal(t, time.Hour, config.ReviewKeywordsInterval)
+	assert.Equal(t, time.Hour, config.ReviewBadgesInterval)
+	assert.Equal(t, time.Hour, config.DigestInterval)
+	assert.Equal(t, 500*time.Millisecond, config.DBReadTimeout)
+	assert.Equal(t, time.Second, config.DBWriteTimeout)
+	assert.Equal(t, 2*time.Second, config.DBAggregateTimeout)
+}
+
+func TestLoadConfig_DBTimeouts(t *testing.T) {
+	os.Setenv("DB_READ_TIMEOUT", "250ms")
+	os.Setenv("DB_AGGREGATE_TIMEOUT", "0")
+	defer os.Unsetenv("DB_READ_TIMEOUT")
+	defer os.Unsetenv("DB_AGGREGATE_TIMEOUT")
+
+	config := Load()
+
+	assert.Equal(t, 250*time.Millisecond, config.DBReadTimeout)
+	assert.Equal(t, time.Second, config.DBWriteTimeout)
+	assert.Equal(t, time.Duration(0), config.DBAggregateTimeout)
+	assert.Equal(t, 2, config.DBRetries)
+	assert.Equal(t, 5, config.DBBreakerThreshold)
+	assert.Equal(t, 10*time.Second, config.DBBreakerCooldown)
+}
+
+func TestLoadConfig_HTTPServer(t *testing.T) {
+	os.Setenv("HTTP_WRITE_TIMEOUT", "0")
+	os.Setenv("SHUTDOWN_TIMEOUT", "5s")
+	defer os.Unsetenv("HTTP_WRITE_TIMEOUT")
+	defer os.Unsetenv("SHUTDOWN_TIMEOUT")
+
+	config := Load()
+
+	assert.Equal(t, 15*time.Second, config.HTTPReadTimeout)
+	assert.Equal(t, time.Duration(0), config.HTTPWriteTimeout)
+	assert.Equal(t, 2*time.Minute, config.HTTPIdleTimeout)
+	assert.Equal(t, 5*time.Second, config.ShutdownTimeout)
+}
+
+func TestLoadConfig_LiteCORSOrigins(t *testing.T) {
+	assert.Empty(t, Load().LiteCORSOrigins)
+
+	os.Setenv("LITE_CORS_ORIGINS", "chrome-extension://abc, https://w2prod.sis.yorku.ca,")
+	defer os.Unsetenv("LITE_CORS_ORIGINS")
+
+	assert.Equal(t, []string{"chrome-extension://abc", "https://w2prod.sis.yorku.ca"}, Load().LiteCORSOrigins)
+}
+
+func TestLoadConfig_SchemaCheck(t *testing.T) {
+	assert.Equal(t, "fail", Load().SchemaCheck)
+
+	os.Setenv("SCHEMA_CHECK", "warn")
+	defer os.Unsetenv("SCHEMA_CHECK")
+
+	assert.Equal(t, "warn", Load().SchemaCheck)
+}
+
+func TestLoadConfig_AutoMigrate(t *testing.T) {
+	assert.False(t, Load().AutoMigrate)
+
+	os.Setenv("AUTO_MIGRATE", "true")
+	defer os.Unsetenv("AUTO_MIGRATE")
+
+	assert.True(t, Load().AutoMigrate)
+}
+
+func TestLoadConfig_Retention(t *testing.T) {
+	os.Setenv("RETENTION_DRY_RUN", "true")
+	os.Setenv("RETENTION_SEARCH_STATS_DAYS", "0")
+	defer os.Unsetenv("RETENTION_DRY_RUN")
+	defer os.Unsetenv("RETENTION_SEARCH_STATS_DAYS")
+
+	config := Load()
+
+	assert.True(t, config.RetentionDryRun)
+	assert.Equal(t, time.Duration(0), config.RetentionSearchStats)
+	assert.Equal(t, 90*24*time.Hour, config.RetentionResolvedQuarantine)
+	assert.Equal(t, 30*24*time.Hour, config.RetentionUnverifiedReviews)
+	assert.Equal(t, 7*24*time.Hour, config.RetentionVerificationTokens)
+	assert.Equal(t, 24*time.Hour, config.RetentionInterval)
+	assert.Equal(t, 30*24*time.Hour, config.CourseSeenTTL)
+
+	os.Setenv("RETENTION_DRY_RUN", "maybe")
+	assert.False(t, Load().RetentionDryRun, "unparseable falls back to the default")
+}
+
+func TestLoadConfig_RateLimitExemptions(t *testing.T) {
+	os.Setenv("RATE_LIMIT_EXEMPTION_SECRET", "s3cret")
+	os.Setenv("RATE_LIMIT_EXEMPTION_MAX_TTL", "2h")
+	defer os.Unsetenv("RATE_LIMIT_EXEMPTION_SECRET")
+	defer os.Unsetenv("RATE_LIMIT_EXEMPTION_MAX_TTL")
+
+	config := Load()
+
+	assert.Equal(t, "s3cret", config.RateLimitExemptionSecret)
+	assert.Equal(t, 2*time.Hour, config.RateLimitExemptionMaxTTL)
+	assert.Equal(t, 10000, config.RateLimitExemptionMaxLimit)
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Endpoint groups with their own page size policy.
+const (
+	PagesCourses     = "courses"      // GET /courses discovery feed
+	PagesSearch      = "search"       // GET /courses/search
+	PagesCatalog     = "catalog"      // GET /courses/paginated page_size
+	PagesReviews     = "reviews"      // course and instructor review pages
+	PagesKeywords    = "keywords"     // review keywords per course
+	PagesAnalytics   = "analytics"    // admin search analytics
+	PagesDataQuality = "data_quality" // admin data quality scores
+	PagesSyncs       = "syncs"        // admin catalog sync history
+)
+
+// PageSize is how many items a list returns when the caller doesn't say, and
+// the most it may ask for.
+type PageSize struct {
+	Default int
+	Max     int
+}
+
+// DefaultPageSizes returns the policy used for groups PAGE_SIZES leaves out.
+func DefaultPageSizes() map[string]PageSize {
+	return map[string]PageSize{
+		PagesCourses:     {Default: 20, Max: 100},
+		PagesSearch:      {Default: 50, Max: 100},
+		PagesCatalog:     {Default: 20, Max: 100},
+		PagesReviews:     {Default: 10, Max: 50},
+		PagesKeywords:    {Default: 30, Max: 50},
+		PagesAnalytics:   {Default: 20, Max: 100},
+		PagesDataQuality: {Default: 100, Max: 1000},
+		PagesSyncs:       {Default: 20, Max: 100},
+	}
+}
+
+// ParsePageSizes overrides the defaults with a comma-separated list of
+// group=default:max entries, e.g. "reviews=10:50,search=25:100".
+func ParsePageSizes(raw string) (map[string]PageSize, error) {
+	sizes := DefaultPageSizes()
+	for _, entry := range strings.Split(raw, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		group, limits, ok := strings.Cut(entry, "=")
+		group = strings.ToLower(strings.TrimSpace(group))
+		if _, known := sizes[group]; !known {
+			return nil, fmt.Errorf("invalid PAGE_SIZES entry %q: unknown group, expected one of %s", entry, strings.Join(sortedGroups(sizes), ", "))
+		}
+		rawDefault, rawMax, hasMax := strings.Cut(limits, ":")
+		def, errDefault := strconv.Atoi(strings.TrimSpace(rawDefault))
+		maximum, errMax := strconv.Atoi(strings.TrimSpace(rawMax))
+		if !ok || !hasMax || errDefault != nil || errMax != nil {
+			return nil, fmt.Errorf("invalid PAGE_SIZES entry %q: expected group=default:max", entry)
+		}
+		if def < 1 || def > maximum {
+			return nil, fmt.Errorf("invalid PAGE_SIZES entry %q: default must be between 1 and max", entry)
+		}
+		sizes[group] = PageSize{Default: def, Max: maximum}
+	}
+	return sizes, nil
+}
+
+// loadPageSizes falls back to the defaults at startup rather than refusing to boot.
+func loadPageSizes(raw string) map[string]PageSize {
+	sizes, err := ParsePageSizes(raw)
+	if err != nil {
+		log.Printf("Invalid page sizes, using defaults: %v", err)
+		return DefaultPageSizes()
+	}
+	return sizes
+}
+
+func sortedGroups(sizes map[string]PageSize) []string {
+	groups := make([]string, 0, len(sizes))
+	for group := range sizes {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+	return groups
+}
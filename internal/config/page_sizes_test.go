@@ -0,0 +1,30 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePageSizes(t *testing.T) {
+	sizes, err := ParsePageSizes(" Reviews=5:25 , search=25:200")
+	assert.NoError(t, err)
+	assert.Equal(t, PageSize{Default: 5, Max: 25}, sizes[PagesReviews])
+	assert.Equal(t, PageSize{Default: 25, Max: 200}, sizes[PagesSearch])
+	assert.Equal(t, DefaultPageSizes()[PagesCatalog], sizes[PagesCatalog], "groups left out keep their defaults")
+}
+
+func TestParsePageSizes_Invalid(t *testing.T) {
+	for _, raw := range []string{"reviews", "reviews=10", "reviews=ten:50", "reviews=60:50", "reviews=0:50", "everything=10:50"} {
+		_, err := ParsePageSizes(raw)
+		assert.Error(t, err, raw)
+	}
+}
+
+func TestLoadConfig_InvalidPageSizesFallBackToDefaults(t *testing.T) {
+	os.Setenv("PAGE_SIZES", "reviews=100:50")
+	defer os.Unsetenv("PAGE_SIZES")
+
+	assert.Equal(t, DefaultPageSizes(), Load().PageSizes)
+}
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+)
+
+// Reloader holds the live Tunables and swaps them atomically on reload.
+// Readers call Current on every use; components that cache settings (rate
+// limiter, load shedder) register with OnChange instead.
+type Reloader struct {
+	path      string
+	current   atomic.Pointer[Tunables]
+	mu        sync.Mutex // serializes reloads and guards listeners and flags
+	listeners []func(Tunables)
+	flags     map[string]bool // set with SetFlag; they outlast reloads
+}
+
+// NewReloader starts from initial and re-reads the environment plus path (CONFIG_FILE) on reload.
+func NewReloader(path string, initial Tunables) *Reloader {
+	r := &Reloader{path: path}
+	r.current.Store(&initial)
+	return r
+}
+
+// Current returns the tunables in effect. Callers must not modify the result.
+func (r *Reloader) Current() Tunables {
+	return *r.current.Load()
+}
+
+// OnChange registers fn to be called with the new tunables after each successful reload.
+func (r *Reloader) OnChange(fn func(Tunables)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.listeners = append(r.listeners, fn)
+}
+
+// Reload re-reads and validates the tunables. On error the current values stay in effect.
+func (r *Reloader) Reload() (Tunables, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, err := LoadTunables(r.path)
+	if err != nil {
+		return r.Current(), err
+	}
+	r.swap(t)
+	return r.Current(), nil
+}
+
+// SetFlag turns a feature flag on or off over what FEATURE_FLAGS says, and
+// keeps it that way across reloads until the process exits. It only changes
+// this instance; edit FEATURE_FLAGS to make it stick.
+func (r *Reloader) SetFlag(flag string, on bool) Tunables {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.flags == nil {
+		r.flags = map[string]bool{}
+	}
+	r.flags[flag] = on
+	r.swap(r.Current())
+	return r.Current()
+}
+
+// swap applies the SetFlag overrides to t, makes it current and tells the
+// listeners. Callers hold mu.
+func (r *Reloader) swap(t Tunables) {
+	if len(r.flags) > 0 {
+		flags := make(map[string]bool, len(t.FeatureFlags)+len(r.flags))
+		for flag, on := range t.FeatureFlags {
+			flags[flag] = on
+		}
+		for flag, on := range r.flags {
+			flags[flag] = on
+		}
+		t.FeatureFlags = flags
+	}
+	r.current.Store(&t)
+	for _, fn := range r.listeners {
+		fn(t)
+	}
+}
+
+// WatchSignals reloads on every SIGHUP until ctx is done.
+func (r *Reloader) WatchSignals(ctx context.Context) {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(sigs)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sigs:
+				if _, err := r.Reload(); err != nil {
+					log.Printf("config reload rejected: %v", err)
+					continue
+				}
+				log.Printf("config reloaded")
+			}
+		}
+	}()
+}
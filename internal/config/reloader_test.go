@@ -0,0 +1,78 @@
+package config
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReloader_SwapsValidConfig(t *testing.T) {
+	path := writeConfigFile(t, "RATE_LIMIT=50\n")
+	reloader := NewReloader(path, DefaultTunables())
+
+	var notified []int
+	reloader.OnChange(func(t Tunables) { notified = append(notified, t.RateLimit) })
+
+	assert.Equal(t, 100, reloader.Current().RateLimit)
+
+	tunables, err := reloader.Reload()
+	assert.NoError(t, err)
+	assert.Equal(t, 50, tunables.RateLimit)
+	assert.Equal(t, 50, reloader.Current().RateLimit)
+	assert.Equal(t, []int{50}, notified)
+}
+
+func TestReloader_KeepsCurrentOnInvalidConfig(t *testing.T) {
+	path := writeConfigFile(t, "RATE_LIMIT=-5\n")
+	reloader := NewReloader(path, DefaultTunables())
+
+	notified := false
+	reloader.OnChange(func(Tunables) { notified = true })
+
+	tunables, err := reloader.Reload()
+	assert.Error(t, err)
+	assert.Equal(t, 100, tunables.RateLimit)
+	assert.Equal(t, 100, reloader.Current().RateLimit)
+	assert.False(t, notified)
+}
+
+func TestReloader_SetFlagOutlastsReloads(t *testing.T) {
+	path := writeConfigFile(t, "FEATURE_FLAGS=captcha_reviews,review_embargo\n")
+	reloader := NewReloader(path, DefaultTunables())
+	_, err := reloader.Reload()
+	assert.NoError(t, err)
+
+	var notified []bool
+	reloader.OnChange(func(t Tunables) { notified = append(notified, t.Enabled(FlagCaptchaReviews)) })
+
+	tunables := reloader.SetFlag(FlagCaptchaReviews, false)
+	assert.False(t, tunables.Enabled(FlagCaptchaReviews))
+	assert.True(t, tunables.Enabled(FlagReviewEmbargo), "other flags are left alone")
+
+	tunables = reloader.SetFlag(FlagCaptchaReports, true)
+	assert.True(t, tunables.Enabled(FlagCaptchaReports))
+
+	tunables, err = reloader.Reload()
+	assert.NoError(t, err)
+	assert.False(t, tunables.Enabled(FlagCaptchaReviews))
+	assert.True(t, reloader.Current().Enabled(FlagCaptchaReports))
+	assert.Equal(t, []bool{false, false, false}, notified)
+}
+
+func TestReloader_WatchSignals(t *testing.T) {
+	path := writeConfigFile(t, "MAINTENANCE_MODE=true\n")
+	reloader := NewReloader(path, DefaultTunables())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reloader.WatchSignals(ctx)
+
+	assert.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+	assert.Eventually(t, func() bool {
+		return reloader.Current().MaintenanceMode
+	}, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,228 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"yuplan/internal/clientversion"
+)
+
+// Log levels accepted by LOG_LEVEL, from most to least verbose.
+var LogLevels = []string{"debug", "info", "warn", "error"}
+
+// FlagReviewEmbargo holds reviews submitted during an exam period until the
+// term's grades are released.
+const FlagReviewEmbargo = "review_embargo"
+
+// FlagCaptchaReviews and FlagCaptchaReports require a solved CAPTCHA to
+// submit a review or a report. They have no effect without CAPTCHA_PROVIDER.
+const (
+	FlagCaptchaReviews = "captcha_reviews"
+	FlagCaptchaReports = "captcha_reports"
+)
+
+// FeatureFlags lists every flag the code checks, the ones an admin can toggle.
+var FeatureFlags = []string{FlagReviewEmbargo, FlagCaptchaReviews, FlagCaptchaReports}
+
+// Tunables are the settings ops can change on a running server with SIGHUP or
+// POST /api/v1/admin/config/reload, e.g. to tighten rate limits mid-incident.
+type Tunables struct {
+	RateLimit           int // requests per RateLimitWindow per client
+	RateLimitWindow     time.Duration
+	LiteRateLimit       int // requests per LiteRateLimitWindow per client on /api/v1/lite
+	LiteRateLimitWindow time.Duration
+	LoadShedMaxInFlight int
+	LoadShedTargetP99   time.Duration
+	MaintenanceMode     bool // reject writes outside /api/v1/admin
+	LogLevel            string
+	FeatureFlags        map[string]bool
+	MinClientVersions   map[string]string // platform -> oldest supported app version; older builds get 426
+	// ReviewReportHideThreshold is how many open reports hide a review until a
+	// moderator looks at it; 0 never hides one
+	ReviewReportHideThreshold int
+}
+
+// DefaultTunables returns the values used when nothing is configured.
+func DefaultTunables() Tunables {
+	return Tunables{
+		RateLimit:           100,
+		RateLimitWindow:     time.Minute,
+		LiteRateLimit:       600,
+		LiteRateLimitWindow: time.Minute,
+		LoadShedMaxInFlight: 50,
+		LoadShedTargetP99:   500 * time.Millisecond,
+		LogLevel:            "info",
+		FeatureFlags:        map[string]bool{},
+		MinClientVersions:   map[string]string{},
+
+		ReviewReportHideThreshold: 3,
+	}
+}
+
+// Enabled reports whether a feature flag is on. Unknown flags are off.
+func (t Tunables) Enabled(flag string) bool {
+	return t.FeatureFlags[flag]
+}
+
+// Validate rejects values that would take the server down if swapped in.
+func (t Tunables) Validate() error {
+	if t.RateLimit < 1 {
+		return fmt.Errorf("RATE_LIMIT must be at least 1, got %d", t.RateLimit)
+	}
+	if t.RateLimitWindow < time.Second {
+		return fmt.Errorf("RATE_LIMIT_WINDOW must be at least 1s, got %s", t.RateLimitWindow)
+	}
+	if t.LiteRateLimit < 1 {
+		return fmt.Errorf("LITE_RATE_LIMIT must be at least 1, got %d", t.LiteRateLimit)
+	}
+	if t.LiteRateLimitWindow < time.Second {
+		return fmt.Errorf("LITE_RATE_LIMIT_WINDOW must be at least 1s, got %s", t.LiteRateLimitWindow)
+	}
+	if t.LoadShedMaxInFlight < 0 {
+		return fmt.Errorf("LOAD_SHED_MAX_IN_FLIGHT must not be negative, got %d", t.LoadShedMaxInFlight)
+	}
+	if t.LoadShedTargetP99 < 0 {
+		return fmt.Errorf("LOAD_SHED_TARGET_P99 must not be negative, got %s", t.LoadShedTargetP99)
+	}
+	if t.ReviewReportHideThreshold < 0 {
+		return fmt.Errorf("REVIEW_REPORT_HIDE_THRESHOLD must not be negative, got %d", t.ReviewReportHideThreshold)
+	}
+	for platform, version := range t.MinClientVersions {
+		if _, err := clientversion.Parse(version); err != nil {
+			return fmt.Errorf("MIN_CLIENT_VERSIONS entry for %s: %w", platform, err)
+		}
+	}
+	for _, level := range LogLevels {
+		if t.LogLevel == level {
+			return nil
+		}
+	}
+	return fmt.Errorf("LOG_LEVEL must be one of %s, got %q", strings.Join(LogLevels, ", "), t.LogLevel)
+}
+
+// LoadTunables reads tunables from the environment, with KEY=VALUE lines in
+// path (if set) taking precedence. Unlike Load, malformed values are errors:
+// a typo during an incident should be reported, not silently defaulted.
+func LoadTunables(path string) (Tunables, error) {
+	overrides := map[string]string{}
+	if path != "" {
+		var err error
+		if overrides, err = readEnvFile(path); err != nil {
+			return Tunables{}, err
+		}
+	}
+	lookup := func(key string) string {
+		if value, ok := overrides[key]; ok {
+			return value
+		}
+		return os.Getenv(key)
+	}
+
+	t := DefaultTunables()
+	var err error
+	if t.RateLimit, err = parseInt(lookup, "RATE_LIMIT", t.RateLimit); err != nil {
+		return Tunables{}, err
+	}
+	if t.RateLimitWindow, err = parseDuration(lookup, "RATE_LIMIT_WINDOW", t.RateLimitWindow); err != nil {
+		return Tunables{}, err
+	}
+	if t.LiteRateLimit, err = parseInt(lookup, "LITE_RATE_LIMIT", t.LiteRateLimit); err != nil {
+		return Tunables{}, err
+	}
+	if t.LiteRateLimitWindow, err = parseDuration(lookup, "LITE_RATE_LIMIT_WINDOW", t.LiteRateLimitWindow); err != nil {
+		return Tunables{}, err
+	}
+	if t.LoadShedMaxInFlight, err = parseInt(lookup, "LOAD_SHED_MAX_IN_FLIGHT", t.LoadShedMaxInFlight); err != nil {
+		return Tunables{}, err
+	}
+	if t.LoadShedTargetP99, err = parseDuration(lookup, "LOAD_SHED_TARGET_P99", t.LoadShedTargetP99); err != nil {
+		return Tunables{}, err
+	}
+	if t.ReviewReportHideThreshold, err = parseInt(lookup, "REVIEW_REPORT_HIDE_THRESHOLD", t.ReviewReportHideThreshold); err != nil {
+		return Tunables{}, err
+	}
+	if value := lookup("MAINTENANCE_MODE"); value != "" {
+		if t.MaintenanceMode, err = strconv.ParseBool(value); err != nil {
+			return Tunables{}, fmt.Errorf("invalid MAINTENANCE_MODE %q: %w", value, err)
+		}
+	}
+	if value := lookup("LOG_LEVEL"); value != "" {
+		t.LogLevel = strings.ToLower(value)
+	}
+	// FEATURE_FLAGS is a comma-separated list of enabled flags
+	for _, flag := range strings.Split(lookup("FEATURE_FLAGS"), ",") {
+		if flag = strings.TrimSpace(flag); flag != "" {
+			t.FeatureFlags[flag] = true
+		}
+	}
+	// MIN_CLIENT_VERSIONS is a comma-separated list of platform=version pairs, e.g. "ios=2.0.0,android=2.1"
+	for _, entry := range strings.Split(lookup("MIN_CLIENT_VERSIONS"), ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		platform, version, ok := strings.Cut(entry, "=")
+		if !ok {
+			return Tunables{}, fmt.Errorf("invalid MIN_CLIENT_VERSIONS entry %q: expected platform=version", entry)
+		}
+		t.MinClientVersions[strings.ToLower(strings.TrimSpace(platform))] = strings.TrimSpace(version)
+	}
+
+	if err := t.Validate(); err != nil {
+		return Tunables{}, err
+	}
+	return t, nil
+}
+
+func parseInt(lookup func(string) string, key string, defaultValue int) (int, error) {
+	value := lookup(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", key, value, err)
+	}
+	return n, nil
+}
+
+func parseDuration(lookup func(string) string, key string, defaultValue time.Duration) (time.Duration, error) {
+	value := lookup(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", key, value, err)
+	}
+	return d, nil
+}
+
+// readEnvFile parses KEY=VALUE lines, skipping blanks and # comments.
+func readEnvFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open config file: %w", err)
+	}
+	defer f.Close()
+
+	values := map[string]string{}
+	scanner := bufio.NewScanner(f)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("config file %s line %d: expected KEY=VALUE", path, lineNo)
+		}
+		values[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"'`)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	return values, nil
+}
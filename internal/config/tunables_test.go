@@ -0,0 +1,84 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeConfigFile(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "yuplan.env")
+	assert.NoError(t, os.WriteFile(path, []byte(contents), 0o600))
+	return path
+}
+
+func TestLoadTunables_Defaults(t *testing.T) {
+	tunables, err := LoadTunables("")
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultTunables(), tunables)
+}
+
+func TestLoadTunables_FileOverridesEnv(t *testing.T) {
+	os.Setenv("RATE_LIMIT", "200")
+	os.Setenv("LOG_LEVEL", "debug")
+	defer os.Unsetenv("RATE_LIMIT")
+	defer os.Unsetenv("LOG_LEVEL")
+
+	path := writeConfigFile(t, `
+# tightened during enrollment
+RATE_LIMIT=20
+RATE_LIMIT_WINDOW=30s
+LITE_RATE_LIMIT=1200
+MAINTENANCE_MODE=true
+FEATURE_FLAGS="review_tags, lite_api"
+MIN_CLIENT_VERSIONS=iOS=2.0.0, android=2.1
+REVIEW_REPORT_HIDE_THRESHOLD=5
+`)
+
+	tunables, err := LoadTunables(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 20, tunables.RateLimit)
+	assert.Equal(t, 30*time.Second, tunables.RateLimitWindow)
+	assert.Equal(t, 1200, tunables.LiteRateLimit)
+	assert.Equal(t, time.Minute, tunables.LiteRateLimitWindow)
+	assert.True(t, tunables.MaintenanceMode)
+	assert.Equal(t, "debug", tunables.LogLevel)
+	assert.True(t, tunables.Enabled("review_tags"))
+	assert.True(t, tunables.Enabled("lite_api"))
+	assert.False(t, tunables.Enabled("unknown"))
+	assert.Equal(t, map[string]string{"ios": "2.0.0", "android": "2.1"}, tunables.MinClientVersions)
+	assert.Equal(t, 5, tunables.ReviewReportHideThreshold)
+}
+
+func TestLoadTunables_RejectsInvalidValues(t *testing.T) {
+	tests := map[string]string{
+		"malformed number":   "RATE_LIMIT=lots",
+		"zero rate limit":    "RATE_LIMIT=0",
+		"tiny window":        "RATE_LIMIT_WINDOW=1ms",
+		"zero lite limit":    "LITE_RATE_LIMIT=0",
+		"negative shedding":  "LOAD_SHED_MAX_IN_FLIGHT=-1",
+		"malformed duration": "LOAD_SHED_TARGET_P99=fast",
+		"malformed bool":     "MAINTENANCE_MODE=sometimes",
+		"unknown log level":  "LOG_LEVEL=verbose",
+		"not key value":      "RATE_LIMIT",
+		"bad client version": "MIN_CLIENT_VERSIONS=ios=latest",
+		"bad client entry":   "MIN_CLIENT_VERSIONS=ios",
+		"negative threshold": "REVIEW_REPORT_HIDE_THRESHOLD=-1",
+	}
+
+	for name, contents := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := LoadTunables(writeConfigFile(t, contents))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadTunables_MissingFile(t *testing.T) {
+	_, err := LoadTunables(filepath.Join(t.TempDir(), "missing.env"))
+	assert.Error(t, err)
+}
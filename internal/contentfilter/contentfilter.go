@@ -0,0 +1,215 @@
+// Package contentfilter screens review text before it is stored. Each Filter
+// looks for one kind of unwanted content and says whether to reject the
+// review outright or flag it for a moderator; a Chain runs several and keeps
+// the strictest verdict. Filters are an interface so a model-based check can
+// be added next to the rule-based ones here.
+package contentfilter
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"yuplan/internal/moderation"
+)
+
+// Actions a filter can take on text it matches, from least to most strict.
+const (
+	ActionAllow  = "allow"
+	ActionFlag   = "flag"   // hold the review for a moderator
+	ActionReject = "reject" // refuse the submission
+)
+
+// Actions are the values a filter can be configured with; ActionAllow turns it off.
+var Actions = []string{ActionAllow, ActionFlag, ActionReject}
+
+// Verdict is what a filter, or a chain of them, decided about some text.
+type Verdict struct {
+	Action string // one of Actions
+	Filter string // name of the filter that decided; empty when allowed
+	Reason string // shown to the author when the review is rejected
+}
+
+// Filter checks text for one kind of unwanted content.
+type Filter interface {
+	Check(ctx context.Context, text string) (Verdict, error)
+}
+
+// Chain runs filters in order and returns the strictest verdict, the first
+// of equally strict ones. A filter error stops the chain.
+type Chain []Filter
+
+func (c Chain) Check(ctx context.Context, text string) (Verdict, error) {
+	verdict := Verdict{Action: ActionAllow}
+	for _, f := range c {
+		v, err := f.Check(ctx, text)
+		if err != nil {
+			return Verdict{}, err
+		}
+		if strictness(v.Action) > strictness(verdict.Action) {
+			verdict = v
+		}
+		if verdict.Action == ActionReject {
+			break
+		}
+	}
+	return verdict, nil
+}
+
+func strictness(action string) int {
+	switch action {
+	case ActionReject:
+		return 2
+	case ActionFlag:
+		return 1
+	}
+	return 0
+}
+
+// New builds the standard chain from each built-in filter's configured
+// action. Profanity looks for words, or moderation.DefaultBlockedWords if
+// there are none.
+func New(profanity, links, spam string, words []string) (Chain, error) {
+	actions := make([]string, 3)
+	for i, s := range []string{profanity, links, spam} {
+		action, err := ParseAction(s)
+		if err != nil {
+			return nil, err
+		}
+		actions[i] = action
+	}
+	if len(words) == 0 {
+		words = moderation.DefaultBlockedWords
+	}
+	return Chain{NewProfanity(actions[0], words), NewLinks(actions[1]), NewSpam(actions[2])}, nil
+}
+
+// ParseAction validates a configured action.
+func ParseAction(s string) (string, error) {
+	switch a := strings.ToLower(strings.TrimSpace(s)); a {
+	case ActionAllow, ActionFlag, ActionReject:
+		return a, nil
+	case "off", "":
+		return ActionAllow, nil
+	default:
+		return "", fmt.Errorf("unknown content filter action %q; expected one of %s", s, strings.Join(Actions, ", "))
+	}
+}
+
+// Profanity matches text containing any of a list of words, by whole word
+// and ignoring case.
+type Profanity struct {
+	action string
+	words  map[string]bool
+}
+
+func NewProfanity(action string, words []string) *Profanity {
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		set[strings.ToLower(strings.TrimSpace(w))] = true
+	}
+	return &Profanity{action: action, words: set}
+}
+
+func (p *Profanity) Check(ctx context.Context, text string) (Verdict, error) {
+	if p.action == ActionAllow {
+		return Verdict{Action: ActionAllow}, nil
+	}
+	for _, w := range words(text) {
+		if p.words[w] {
+			return Verdict{Action: p.action, Filter: "profanity", Reason: "Reviews can't contain profanity"}, nil
+		}
+	}
+	return Verdict{Action: ActionAllow}, nil
+}
+
+// linkPattern finds URLs and bare domains such as example.com/deal, but not
+// course codes or version numbers.
+var linkPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+|\b[a-z0-9-]+\.(?:com|net|org|io|ca|co|info|biz|xyz|ly|me|gg)\b`)
+
+// Links matches text containing a URL or a domain name.
+type Links struct {
+	action string
+}
+
+func NewLinks(action string) *Links {
+	return &Links{action: action}
+}
+
+func (l *Links) Check(ctx context.Context, text string) (Verdict, error) {
+	if l.action == ActionAllow || !linkPattern.MatchString(text) {
+		return Verdict{Action: ActionAllow}, nil
+	}
+	return Verdict{Action: l.action, Filter: "links", Reason: "Reviews can't contain links"}, nil
+}
+
+// Spam thresholds. Text this repetitive is rarely a genuine review.
+const (
+	maxRunLength    = 8   // the same character this many times in a row: "!!!!!!!!", "soooooooo"
+	minSpamWords    = 10  // word repetition is only judged on text at least this long
+	maxWordShare    = 0.4 // one word making up more than this share of the text
+	maxRepeatedLine = 3   // the same non-empty line this many times
+)
+
+// Spam matches text padded with repeated characters, words or lines.
+type Spam struct {
+	action string
+}
+
+func NewSpam(action string) *Spam {
+	return &Spam{action: action}
+}
+
+func (s *Spam) Check(ctx context.Context, text string) (Verdict, error) {
+	if s.action == ActionAllow || !spammy(text) {
+		return Verdict{Action: ActionAllow}, nil
+	}
+	return Verdict{Action: s.action, Filter: "spam", Reason: "Reviews can't be mostly repeated text"}, nil
+}
+
+func spammy(text string) bool {
+	var prev rune
+	run := 0
+	for _, r := range text {
+		if r == prev && !unicode.IsSpace(r) {
+			run++
+			if run >= maxRunLength {
+				return true
+			}
+		} else {
+			prev, run = r, 1
+		}
+	}
+
+	ws := words(text)
+	if len(ws) >= minSpamWords {
+		counts := map[string]int{}
+		for _, w := range ws {
+			counts[w]++
+			if float64(counts[w]) > maxWordShare*float64(len(ws)) {
+				return true
+			}
+		}
+	}
+
+	lines := map[string]int{}
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.ToLower(strings.TrimSpace(line))
+		if line == "" {
+			continue
+		}
+		lines[line]++
+		if lines[line] >= maxRepeatedLine {
+			return true
+		}
+	}
+	return false
+}
+
+// words splits text into lower-cased words of letters and digits.
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
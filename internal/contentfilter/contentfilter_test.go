@@ -0,0 +1,109 @@
+package contentfilter
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfanity(t *testing.T) {
+	f := NewProfanity(ActionReject, []string{"Heck"})
+
+	v, _ := f.Check(context.Background(), "What the HECK was that midterm")
+	assert.Equal(t, ActionReject, v.Action)
+	assert.Equal(t, "profanity", v.Filter)
+
+	v, _ = f.Check(context.Background(), "Heckling in lectures was common")
+	assert.Equal(t, ActionAllow, v.Action)
+
+	v, _ = NewProfanity(ActionAllow, []string{"heck"}).Check(context.Background(), "heck")
+	assert.Equal(t, ActionAllow, v.Action)
+}
+
+func TestLinks(t *testing.T) {
+	f := NewLinks(ActionFlag)
+	for _, text := range []string{
+		"Notes at https://example.org/notes",
+		"check www.cheap-essays.net",
+		"buy answers at essayhelp.com today",
+	} {
+		v, _ := f.Check(context.Background(), text)
+		assert.Equal(t, ActionFlag, v.Action, text)
+	}
+	for _, text := range []string{
+		"Take EECS2030 before EECS3311.",
+		"Uses Java 17.0.2 and Python 3.12",
+		"Great prof. Tough exams.",
+	} {
+		v, _ := f.Check(context.Background(), text)
+		assert.Equal(t, ActionAllow, v.Action, text)
+	}
+}
+
+func TestSpam(t *testing.T) {
+	f := NewSpam(ActionReject)
+	for _, text := range []string{
+		"Best course ever!!!!!!!!!!",
+		strings.Repeat("easy ", 12),
+		"Great course\nGreat course\ngreat course",
+	} {
+		v, _ := f.Check(context.Background(), text)
+		assert.Equal(t, ActionReject, v.Action, text)
+	}
+
+	v, _ := f.Check(context.Background(), "The course is hard but the course is fair, and the labs help a lot with the course.")
+	assert.Equal(t, ActionAllow, v.Action)
+}
+
+type failing struct{}
+
+func (failing) Check(ctx context.Context, text string) (Verdict, error) {
+	return Verdict{}, errors.New("model unavailable")
+}
+
+func TestChain(t *testing.T) {
+	chain := Chain{NewLinks(ActionFlag), NewProfanity(ActionReject, []string{"heck"}), NewSpam(ActionFlag)}
+
+	v, err := chain.Check(context.Background(), "heck, see example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, Verdict{Action: ActionReject, Filter: "profanity", Reason: "Reviews can't contain profanity"}, v)
+
+	v, _ = chain.Check(context.Background(), "see example.com")
+	assert.Equal(t, "links", v.Filter)
+
+	v, _ = chain.Check(context.Background(), "Fair grading.")
+	assert.Equal(t, Verdict{Action: ActionAllow}, v)
+
+	_, err = append(chain[:1:1], failing{}).Check(context.Background(), "ok")
+	assert.Error(t, err)
+}
+
+func TestParseAction(t *testing.T) {
+	a, err := ParseAction(" Flag ")
+	assert.NoError(t, err)
+	assert.Equal(t, ActionFlag, a)
+
+	a, err = ParseAction("off")
+	assert.NoError(t, err)
+	assert.Equal(t, ActionAllow, a)
+
+	_, err = ParseAction("delete")
+	assert.Error(t, err)
+}
+
+func TestNew(t *testing.T) {
+	chain, err := New("reject", "off", "flag", nil)
+	assert.NoError(t, err)
+
+	v, _ := chain.Check(context.Background(), "see example.com")
+	assert.Equal(t, ActionAllow, v.Action)
+
+	v, _ = chain.Check(context.Background(), "zzzzzzzzzzz")
+	assert.Equal(t, ActionFlag, v.Action)
+
+	_, err = New("reject", "block", "flag", nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,69 @@
+// Package dataquality scores each course on how complete the scraped catalog
+// data for it is, after every seed, so scraper fixes can go where the data is
+// worst and their effect shows up in the trend.
+package dataquality
+
+import (
+	"context"
+	"yuplan/internal/dbtypes"
+	"yuplan/internal/models"
+)
+
+// Store reads the catalog and records scores. Implemented by repository.DataQualityRepository.
+type Store interface {
+	ListCourseData(ctx context.Context) ([]models.CourseData, error)
+	RecordScores(ctx context.Context, scores []models.CourseQuality) error
+}
+
+// Scorer scores every course in the catalog.
+type Scorer struct {
+	store Store
+}
+
+func NewScorer(store Store) *Scorer {
+	return &Scorer{store: store}
+}
+
+// Run scores the catalog as it is now, records the scores as a new run, and
+// returns how many courses were scored. Call it once a seed has finished.
+func (s *Scorer) Run(ctx context.Context) (int, error) {
+	courses, err := s.store.ListCourseData(ctx)
+	if err != nil {
+		return 0, err
+	}
+	scores := make([]models.CourseQuality, len(courses))
+	for i, c := range courses {
+		scores[i] = Score(c)
+	}
+	if err := s.store.RecordScores(ctx, scores); err != nil {
+		return 0, err
+	}
+	return len(scores), nil
+}
+
+// Score takes each issue's weight in models.DataIssueWeights off 100. A
+// course with no sections has no times or instructors to check.
+func Score(c models.CourseData) models.CourseQuality {
+	q := models.CourseQuality{CourseCode: c.CourseCode, Term: c.Term, Score: 100, Issues: []string{}}
+	add := func(issue string) {
+		q.Issues = append(q.Issues, issue)
+		q.Score -= models.DataIssueWeights[issue]
+	}
+
+	if !c.HasDescription {
+		add(models.DataIssueMissingDescription)
+	}
+	if c.Sections == 0 {
+		add(models.DataIssueNoSections)
+	}
+	for _, times := range c.Times {
+		if _, err := models.ParseMeetings(dbtypes.NewNullString(times)); err != nil {
+			add(models.DataIssueUnparsedTimes)
+			break
+		}
+	}
+	if c.SectionsWithoutInstructors > 0 {
+		add(models.DataIssueMissingInstructors)
+	}
+	return q
+}
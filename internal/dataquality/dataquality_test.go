@@ -0,0 +1,63 @@
+package dataquality
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"yuplan/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		name   string
+		course models.CourseData
+		score  int
+		issues []string
+	}{
+		{"complete", models.CourseData{HasDescription: true, Sections: 2, Times: []string{`[{"day":"M","time":"10:00","duration":"80"}]`}}, 100, []string{}},
+		{"no sections", models.CourseData{HasDescription: true}, 50, []string{models.DataIssueNoSections}},
+		{"everything missing", models.CourseData{Sections: 1, SectionsWithoutInstructors: 1, Times: []string{"[]", "M 10:00"}}, 50,
+			[]string{models.DataIssueMissingDescription, models.DataIssueUnparsedTimes, models.DataIssueMissingInstructors}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := Score(tt.course)
+			assert.Equal(t, tt.score, q.Score)
+			assert.Equal(t, tt.issues, q.Issues)
+		})
+	}
+}
+
+type fakeStore struct {
+	courses  []models.CourseData
+	recorded []models.CourseQuality
+	err      error
+}
+
+func (f *fakeStore) ListCourseData(ctx context.Context) ([]models.CourseData, error) {
+	return f.courses, f.err
+}
+
+func (f *fakeStore) RecordScores(ctx context.Context, scores []models.CourseQuality) error {
+	f.recorded = scores
+	return nil
+}
+
+func TestRun(t *testing.T) {
+	store := &fakeStore{courses: []models.CourseData{
+		{CourseCode: "EECS2030", Term: "F", HasDescription: true, Sections: 1},
+		{CourseCode: "EECS3311", Term: "W"},
+	}}
+
+	n, err := NewScorer(store).Run(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, "EECS2030", store.recorded[0].CourseCode)
+	assert.Equal(t, 100, store.recorded[0].Score)
+	assert.Equal(t, 30, store.recorded[1].Score)
+
+	_, err = NewScorer(&fakeStore{err: errors.New("db down")}).Run(context.Background())
+	assert.Error(t, err)
+}
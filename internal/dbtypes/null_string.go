@@ -0,0 +1,85 @@
+// Package dbtypes holds nullable column types that scan safely from both pgx
+// and pgxmock and marshal to JSON as either a value or null.
+package dbtypes
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// NullString is a nullable text column. The zero value is NULL.
+type NullString struct {
+	String string
+	Valid  bool
+}
+
+// NewNullString returns a valid (non-NULL) NullString.
+func NewNullString(s string) NullString {
+	return NullString{String: s, Valid: true}
+}
+
+// NullStringFromPtr maps nil to NULL and anything else to its value.
+func NullStringFromPtr(p *string) NullString {
+	if p == nil {
+		return NullString{}
+	}
+	return NewNullString(*p)
+}
+
+// Ptr returns nil for NULL, or a pointer to a copy of the value.
+func (n NullString) Ptr() *string {
+	if !n.Valid {
+		return nil
+	}
+	s := n.String
+	return &s
+}
+
+// Scan implements sql.Scanner.
+func (n *NullString) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*n = NullString{}
+	case string:
+		*n = NewNullString(v)
+	case *string:
+		*n = NullStringFromPtr(v)
+	case []byte:
+		*n = NewNullString(string(v))
+	case NullString:
+		*n = v
+	default:
+		return fmt.Errorf("dbtypes: cannot scan %T into NullString", src)
+	}
+	return nil
+}
+
+// Value implements driver.Valuer.
+func (n NullString) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.String, nil
+}
+
+func (n NullString) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.String)
+}
+
+func (n *NullString) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*n = NullString{}
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*n = NewNullString(s)
+	return nil
+}
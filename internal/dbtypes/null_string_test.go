@@ -0,0 +1,78 @@
+package dbtypes
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNullString_Scan(t *testing.T) {
+	s := "hello"
+	tests := []struct {
+		name     string
+		src      any
+		expected NullString
+	}{
+		{name: "nil", src: nil, expected: NullString{}},
+		{name: "string", src: "hello", expected: NewNullString("hello")},
+		{name: "pointer", src: &s, expected: NewNullString("hello")},
+		{name: "nil pointer", src: (*string)(nil), expected: NullString{}},
+		{name: "bytes", src: []byte("hello"), expected: NewNullString("hello")},
+		{name: "empty string is not null", src: "", expected: NewNullString("")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := NewNullString("stale")
+			assert.NoError(t, n.Scan(tt.src))
+			assert.Equal(t, tt.expected, n)
+		})
+	}
+}
+
+func TestNullString_ScanUnsupportedType(t *testing.T) {
+	var n NullString
+	assert.Error(t, n.Scan(42))
+}
+
+func TestNullString_Value(t *testing.T) {
+	v, err := NullString{}.Value()
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+
+	v, err = NewNullString("x").Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "x", v)
+}
+
+func TestNullString_JSON(t *testing.T) {
+	type wrapper struct {
+		Name NullString `json:"name"`
+		Link NullString `json:"link,omitzero"`
+	}
+
+	data, err := json.Marshal(wrapper{Name: NewNullString("Ada")})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"name":"Ada"}`, string(data))
+
+	data, err = json.Marshal(wrapper{})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"name":null}`, string(data))
+
+	var decoded wrapper
+	assert.NoError(t, json.Unmarshal([]byte(`{"name":null,"link":"x"}`), &decoded))
+	assert.False(t, decoded.Name.Valid)
+	assert.Equal(t, NewNullString("x"), decoded.Link)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"name":5}`), &decoded))
+}
+
+func TestNullString_Ptr(t *testing.T) {
+	assert.Nil(t, NullString{}.Ptr())
+	assert.Equal(t, "x", *NewNullString("x").Ptr())
+
+	s := "y"
+	assert.Equal(t, NewNullString("y"), NullStringFromPtr(&s))
+	assert.Equal(t, NullString{}, NullStringFromPtr(nil))
+}
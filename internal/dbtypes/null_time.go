@@ -0,0 +1,82 @@
+package dbtypes
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// NullTime is a nullable timestamp column. The zero value is NULL.
+type NullTime struct {
+	Time  time.Time
+	Valid bool
+}
+
+// NewNullTime returns a valid (non-NULL) NullTime.
+func NewNullTime(t time.Time) NullTime {
+	return NullTime{Time: t, Valid: true}
+}
+
+// NullTimeFromPtr maps nil to NULL and anything else to its value.
+func NullTimeFromPtr(p *time.Time) NullTime {
+	if p == nil {
+		return NullTime{}
+	}
+	return NewNullTime(*p)
+}
+
+// Ptr returns nil for NULL, or a pointer to a copy of the value.
+func (n NullTime) Ptr() *time.Time {
+	if !n.Valid {
+		return nil
+	}
+	t := n.Time
+	return &t
+}
+
+// Scan implements sql.Scanner.
+func (n *NullTime) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*n = NullTime{}
+	case time.Time:
+		*n = NewNullTime(v)
+	case *time.Time:
+		*n = NullTimeFromPtr(v)
+	case NullTime:
+		*n = v
+	default:
+		return fmt.Errorf("dbtypes: cannot scan %T into NullTime", src)
+	}
+	return nil
+}
+
+// Value implements driver.Valuer.
+func (n NullTime) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Time, nil
+}
+
+func (n NullTime) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Time)
+}
+
+func (n *NullTime) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*n = NullTime{}
+		return nil
+	}
+	var t time.Time
+	if err := json.Unmarshal(data, &t); err != nil {
+		return err
+	}
+	*n = NewNullTime(t)
+	return nil
+}
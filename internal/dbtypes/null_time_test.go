@@ -0,0 +1,62 @@
+package dbtypes
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNullTime_Scan(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	var n NullTime
+	assert.NoError(t, n.Scan(now))
+	assert.Equal(t, NewNullTime(now), n)
+
+	assert.NoError(t, n.Scan(&now))
+	assert.Equal(t, NewNullTime(now), n)
+
+	assert.NoError(t, n.Scan(nil))
+	assert.False(t, n.Valid)
+
+	assert.Error(t, n.Scan("2026-01-02"))
+}
+
+func TestNullTime_Value(t *testing.T) {
+	v, err := NullTime{}.Value()
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+
+	now := time.Now()
+	v, err = NewNullTime(now).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, now, v)
+}
+
+func TestNullTime_JSON(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	data, err := json.Marshal(NewNullTime(now))
+	assert.NoError(t, err)
+	assert.Equal(t, `"2026-01-02T03:04:05Z"`, string(data))
+
+	data, err = json.Marshal(NullTime{})
+	assert.NoError(t, err)
+	assert.Equal(t, `null`, string(data))
+
+	var decoded NullTime
+	assert.NoError(t, json.Unmarshal([]byte(`"2026-01-02T03:04:05Z"`), &decoded))
+	assert.True(t, decoded.Time.Equal(now))
+	assert.NoError(t, json.Unmarshal([]byte(`null`), &decoded))
+	assert.False(t, decoded.Valid)
+}
+
+func TestNullTime_Ptr(t *testing.T) {
+	now := time.Now()
+	assert.Nil(t, NullTime{}.Ptr())
+	assert.Equal(t, now, *NewNullTime(now).Ptr())
+	assert.Equal(t, NewNullTime(now), NullTimeFromPtr(&now))
+	assert.Equal(t, NullTime{}, NullTimeFromPtr(nil))
+}
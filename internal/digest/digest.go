@@ -0,0 +1,200 @@
+// Package digest finds what changed in the catalog after each seed and sends
+// subscribers a digest of the changes in the departments they follow.
+package digest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"yuplan/internal/jobs"
+	"yuplan/internal/mailer"
+	"yuplan/internal/models"
+)
+
+// ErrNotDelivered is returned by LogNotifier, so digests it only logs stay
+// due and go out once mail is configured.
+var ErrNotDelivered = errors.New("digest not delivered: no mailer configured")
+
+// Store records catalog changes and tracks what each subscriber has been sent.
+// Implemented by repository.DigestRepository.
+type Store interface {
+	DetectCatalogChanges(ctx context.Context) (snapshotted bool, changes int, cacheInvalidated bool, err error)
+	ListDueDigests(ctx context.Context, now time.Time) ([]models.Digest, error)
+	MarkDigestSent(ctx context.Context, email string, through time.Time) error
+}
+
+// Notifier delivers a digest to its subscriber.
+type Notifier interface {
+	SendDigest(ctx context.Context, digest models.Digest) error
+}
+
+// jobLocker keeps scheduled runs to one instance at a time, once per tick. Implemented by jobs.Locker.
+type jobLocker interface {
+	Do(ctx context.Context, job string, tick time.Time, fn func(ctx context.Context) error) (bool, error)
+}
+
+// Sender checks for a new seed and sends the digests that are due.
+type Sender struct {
+	store    Store
+	notifier Notifier
+	locker   jobLocker
+	changed  func(ctx context.Context, cacheInvalidated bool)
+	now      func() time.Time
+}
+
+func NewSender(store Store, notifier Notifier) *Sender {
+	return &Sender{store: store, notifier: notifier, now: time.Now}
+}
+
+// WithNotifier replaces the notifier digests are sent through.
+func (s *Sender) WithNotifier(notifier Notifier) *Sender {
+	s.notifier = notifier
+	return s
+}
+
+// WithLocker makes Start skip runs while another instance holds the digest lock.
+func (s *Sender) WithLocker(locker jobLocker) *Sender {
+	s.locker = locker
+	return s
+}
+
+// WithCatalogChanged calls fn after a new seed has been compared, e.g. to
+// drop cached catalog data unless the load already dropped what it changed.
+func (s *Sender) WithCatalogChanged(fn func(ctx context.Context, cacheInvalidated bool)) *Sender {
+	s.changed = fn
+	return s
+}
+
+// Run records the changes from a seed that hasn't been compared yet, then
+// sends every digest that is due and returns how many went out. A digest that
+// fails to send is logged and retried on the next run.
+func (s *Sender) Run(ctx context.Context) (int, error) {
+	snapshotted, changes, cacheInvalidated, err := s.store.DetectCatalogChanges(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if snapshotted {
+		log.Printf("catalog snapshot retaken: %d changes since the previous seed", changes)
+		if s.changed != nil {
+			s.changed(ctx, cacheInvalidated)
+		}
+	}
+
+	digests, err := s.store.ListDueDigests(ctx, s.now())
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	for _, d := range digests {
+		if err := s.notifier.SendDigest(ctx, d); err != nil {
+			log.Printf("catalog digest of %d changes: %v", len(d.Changes), err)
+			continue
+		}
+		if err := s.store.MarkDigestSent(ctx, d.Email, d.Through()); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// Start runs immediately and then every interval until ctx is done.
+func (s *Sender) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := s.runScheduled(ctx, jobs.Tick(time.Now(), interval)); err != nil {
+				log.Printf("catalog digests failed: %v", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (s *Sender) runScheduled(ctx context.Context, tick time.Time) error {
+	run := func(ctx context.Context) error {
+		_, err := s.Run(ctx)
+		return err
+	}
+	if s.locker == nil {
+		return run(ctx)
+	}
+	_, err := s.locker.Do(ctx, "catalog_digests", tick, run)
+	return err
+}
+
+// Render writes a digest as a plain-text email.
+func Render(d models.Digest) (subject, body string) {
+	var departments []string
+	var b strings.Builder
+	b.WriteString("Here is what changed in the course catalog for the departments you follow.\n")
+
+	for i, c := range d.Changes {
+		if i == 0 || c.Department != d.Changes[i-1].Department {
+			departments = append(departments, c.Department)
+			fmt.Fprintf(&b, "\n%s\n", c.Department)
+		}
+		fmt.Fprintf(&b, "- %s\n", describe(c))
+	}
+
+	subject = "Course catalog changes in " + strings.Join(departments, ", ")
+	return subject, b.String()
+}
+
+func describe(c models.CatalogChange) string {
+	switch c.Kind {
+	case models.CatalogNewCourse:
+		return fmt.Sprintf("New course: %s (%s)", c.CourseCode, c.Term)
+	case models.CatalogRemovedSection:
+		return fmt.Sprintf("Section removed: %s section %s (%s)", c.CourseCode, c.Section, c.Term)
+	case models.CatalogInstructorChange:
+		return fmt.Sprintf("Instructor change: %s section %s (%s), from %s to %s",
+			c.CourseCode, c.Section, c.Term, orTBA(c.Before.String), orTBA(c.After.String))
+	}
+	return fmt.Sprintf("%s: %s %s (%s)", c.Kind, c.CourseCode, c.Section, c.Term)
+}
+
+func orTBA(instructors string) string {
+	if instructors == "" {
+		return "TBA"
+	}
+	return instructors
+}
+
+// MailNotifier mails each digest, as Render writes it, to its subscriber.
+type MailNotifier struct {
+	mailer mailer.Mailer
+}
+
+func NewMailNotifier(m mailer.Mailer) *MailNotifier {
+	return &MailNotifier{mailer: m}
+}
+
+func (n *MailNotifier) SendDigest(ctx context.Context, digest models.Digest) error {
+	subject, body := Render(digest)
+	if err := n.mailer.Send(ctx, mailer.Message{To: digest.Email, Subject: subject, Body: body}); err != nil {
+		return fmt.Errorf("send digest: %w", err)
+	}
+	return nil
+}
+
+// LogNotifier logs digests instead of sending them, for deployments without
+// mail delivery. Subscribers' addresses are left out of the log. It returns
+// ErrNotDelivered, so the digests aren't marked sent.
+type LogNotifier struct{}
+
+func (LogNotifier) SendDigest(ctx context.Context, digest models.Digest) error {
+	subject, _ := Render(digest)
+	log.Printf("catalog digest not delivered (no mailer): %q, %d changes", subject, len(digest.Changes))
+	return ErrNotDelivered
+}
@@ -0,0 +1,155 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"yuplan/internal/dbtypes"
+	"yuplan/internal/mailer"
+	"yuplan/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var now = time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+type fakeStore struct {
+	detectErr        error
+	cacheInvalidated bool
+	digests          []models.Digest
+	dueAt            time.Time
+	marked           map[string]time.Time
+}
+
+func (f *fakeStore) DetectCatalogChanges(ctx context.Context) (bool, int, bool, error) {
+	return true, 2, f.cacheInvalidated, f.detectErr
+}
+
+func (f *fakeStore) ListDueDigests(ctx context.Context, at time.Time) ([]models.Digest, error) {
+	f.dueAt = at
+	return f.digests, nil
+}
+
+func (f *fakeStore) MarkDigestSent(ctx context.Context, email string, through time.Time) error {
+	if f.marked == nil {
+		f.marked = map[string]time.Time{}
+	}
+	f.marked[email] = through
+	return nil
+}
+
+type fakeNotifier struct {
+	failFor string
+	sent    []models.Digest
+}
+
+func (f *fakeNotifier) SendDigest(ctx context.Context, d models.Digest) error {
+	if d.Email == f.failFor {
+		return errors.New("mail server down")
+	}
+	f.sent = append(f.sent, d)
+	return nil
+}
+
+func newSender(store *fakeStore, notifier *fakeNotifier) *Sender {
+	s := NewSender(store, notifier)
+	s.now = func() time.Time { return now }
+	return s
+}
+
+func TestSender_Run(t *testing.T) {
+	earlier := now.Add(-2 * time.Hour)
+	later := now.Add(-time.Hour)
+	store := &fakeStore{digests: []models.Digest{
+		{Email: "a@yorku.ca", Changes: []models.CatalogChange{
+			{Department: "EECS", Kind: models.CatalogNewCourse, CourseCode: "EECS4000", DetectedAt: later},
+			{Department: "MATH", Kind: models.CatalogNewCourse, CourseCode: "MATH4000", DetectedAt: earlier},
+		}},
+		{Email: "b@yorku.ca", Changes: []models.CatalogChange{{Department: "EECS", DetectedAt: later}}},
+	}}
+	notifier := &fakeNotifier{failFor: "b@yorku.ca"}
+
+	sent, err := newSender(store, notifier).Run(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, now, store.dueAt)
+	assert.Equal(t, map[string]time.Time{"a@yorku.ca": later}, store.marked, "a failed send is retried next run")
+}
+
+func TestSender_Run_DetectError(t *testing.T) {
+	store := &fakeStore{detectErr: errors.New("db down"), digests: []models.Digest{{Email: "a@yorku.ca"}}}
+	notifier := &fakeNotifier{}
+
+	_, err := newSender(store, notifier).Run(context.Background())
+
+	assert.Error(t, err)
+	assert.Empty(t, notifier.sent)
+}
+
+func TestSender_Run_CatalogChanged(t *testing.T) {
+	calls := 0
+	var invalidated bool
+	s := newSender(&fakeStore{cacheInvalidated: true}, &fakeNotifier{}).WithCatalogChanged(func(ctx context.Context, cacheInvalidated bool) {
+		calls++
+		invalidated = cacheInvalidated
+	})
+
+	_, err := s.Run(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, calls, "a new seed was compared")
+	assert.True(t, invalidated, "the load dropped its own cached reads")
+}
+
+func TestRender(t *testing.T) {
+	subject, body := Render(models.Digest{Email: "a@yorku.ca", Changes: []models.CatalogChange{
+		{Department: "EECS", Kind: models.CatalogNewCourse, CourseCode: "EECS4000", Term: models.TermFall},
+		{Department: "EECS", Kind: models.CatalogInstructorChange, CourseCode: "EECS2030", Term: models.TermFall, Section: "A",
+			Before: dbtypes.NewNullString("Jane Smith"), After: dbtypes.NewNullString("")},
+		{Department: "MATH", Kind: models.CatalogRemovedSection, CourseCode: "MATH1090", Term: models.TermWinter, Section: "B"},
+	}})
+
+	assert.Equal(t, "Course catalog changes in EECS, MATH", subject)
+	assert.Contains(t, body, "\nEECS\n- New course: EECS4000 (F)\n")
+	assert.Contains(t, body, "Instructor change: EECS2030 section A (F), from Jane Smith to TBA")
+	assert.Contains(t, body, "\nMATH\n- Section removed: MATH1090 section B (W)\n")
+	assert.False(t, strings.Contains(body, "a@yorku.ca"))
+}
+
+type fakeMailer struct {
+	sent []mailer.Message
+	err  error
+}
+
+func (f *fakeMailer) Send(ctx context.Context, msg mailer.Message) error {
+	f.sent = append(f.sent, msg)
+	return f.err
+}
+
+func TestMailNotifier(t *testing.T) {
+	m := &fakeMailer{}
+	d := models.Digest{Email: "a@yorku.ca", Changes: []models.CatalogChange{
+		{Department: "EECS", Kind: models.CatalogNewCourse, CourseCode: "EECS4000", Term: models.TermFall},
+	}}
+
+	assert.NoError(t, NewMailNotifier(m).SendDigest(context.Background(), d))
+	subject, body := Render(d)
+	assert.Equal(t, []mailer.Message{{To: "a@yorku.ca", Subject: subject, Body: body}}, m.sent)
+
+	m.err = errors.New("smtp down")
+	assert.Error(t, NewMailNotifier(m).SendDigest(context.Background(), d))
+}
+
+func TestSender_Run_LogNotifier(t *testing.T) {
+	store := &fakeStore{digests: []models.Digest{{Email: "a@yorku.ca", Changes: []models.CatalogChange{{Department: "EECS"}}}}}
+	s := NewSender(store, LogNotifier{})
+
+	sent, err := s.Run(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, sent)
+	assert.Empty(t, store.marked, "logged digests stay due until mail is configured")
+}
@@ -0,0 +1,108 @@
+// Package exemption issues and checks signed rate limit exemption tokens, so
+// scheduled load tests and partner integrations can run above the public
+// limit without a config change. Tokens are stateless: the claims travel in
+// the token and an HMAC over them proves the API issued it.
+package exemption
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"yuplan/internal/id"
+)
+
+// Header is the request header a token is sent in.
+const Header = "X-RateLimit-Exemption"
+
+var (
+	ErrInvalid = errors.New("invalid rate limit exemption")
+	ErrExpired = errors.New("rate limit exemption expired")
+)
+
+// Claims say who a token was issued to and how far it lifts the limit.
+type Claims struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`  // who the token is for, e.g. "k6 nightly"
+	Limit     int       `json:"limit"` // requests per rate limit window
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Signer issues tokens and verifies them. Every token is held to MaxTTL and
+// MaxLimit, at issue and again when verified, so lowering a cap also reins in
+// tokens already handed out.
+type Signer struct {
+	secret   []byte
+	maxTTL   time.Duration
+	maxLimit int
+	now      func() time.Time
+}
+
+func NewSigner(secret string, maxTTL time.Duration, maxLimit int) *Signer {
+	return &Signer{secret: []byte(secret), maxTTL: maxTTL, maxLimit: maxLimit, now: time.Now}
+}
+
+// Issue signs a token for name allowing limit requests per window for ttl.
+func (s *Signer) Issue(name string, limit int, ttl time.Duration) (string, Claims, error) {
+	switch {
+	case name == "":
+		return "", Claims{}, errors.New("name is required")
+	case limit <= 0 || limit > s.maxLimit:
+		return "", Claims{}, fmt.Errorf("limit must be between 1 and %d", s.maxLimit)
+	case ttl <= 0 || ttl > s.maxTTL:
+		return "", Claims{}, fmt.Errorf("ttl must be positive and at most %s", s.maxTTL)
+	}
+
+	claims := Claims{
+		ID:        id.New(),
+		Name:      name,
+		Limit:     limit,
+		ExpiresAt: s.now().Add(ttl).UTC().Truncate(time.Second),
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", Claims{}, fmt.Errorf("encode exemption claims: %w", err)
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(s.sign(encoded)), claims, nil
+}
+
+// Verify returns the claims of a token this signer issued, with Limit lowered
+// to MaxLimit if the cap has come down since. It returns ErrExpired for a
+// genuine token past its expiry, or one that outlives MaxTTL, and ErrInvalid
+// for anything else.
+func (s *Signer) Verify(token string) (Claims, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return Claims{}, ErrInvalid
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(sig, s.sign(encoded)) {
+		return Claims{}, ErrInvalid
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return Claims{}, ErrInvalid
+	}
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.ID == "" || claims.Limit <= 0 {
+		return Claims{}, ErrInvalid
+	}
+
+	now := s.now()
+	if !now.Before(claims.ExpiresAt) || claims.ExpiresAt.Sub(now) > s.maxTTL {
+		return Claims{}, ErrExpired
+	}
+	claims.Limit = min(claims.Limit, s.maxLimit)
+	return claims, nil
+}
+
+func (s *Signer) sign(encoded string) []byte {
+	h := hmac.New(sha256.New, s.secret)
+	h.Write([]byte(encoded))
+	return h.Sum(nil)
+}
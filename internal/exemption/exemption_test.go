@@ -0,0 +1,80 @@
+package exemption
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSigner_IssueAndVerify(t *testing.T) {
+	signer := NewSigner("secret", 24*time.Hour, 10000)
+
+	token, issued, err := signer.Issue("k6 nightly", 5000, time.Hour)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, issued.ID)
+
+	claims, err := signer.Verify(token)
+	assert.NoError(t, err)
+	assert.Equal(t, issued, claims)
+	assert.Equal(t, "k6 nightly", claims.Name)
+	assert.Equal(t, 5000, claims.Limit)
+}
+
+func TestSigner_IssueEnforcesCaps(t *testing.T) {
+	signer := NewSigner("secret", 24*time.Hour, 10000)
+
+	_, _, err := signer.Issue("", 100, time.Hour)
+	assert.Error(t, err)
+	_, _, err = signer.Issue("partner", 10001, time.Hour)
+	assert.Error(t, err)
+	_, _, err = signer.Issue("partner", 0, time.Hour)
+	assert.Error(t, err)
+	_, _, err = signer.Issue("partner", 100, 25*time.Hour)
+	assert.Error(t, err)
+}
+
+func TestSigner_VerifyRejectsTampering(t *testing.T) {
+	signer := NewSigner("secret", 24*time.Hour, 10000)
+	token, _, err := signer.Issue("partner", 100, time.Hour)
+	assert.NoError(t, err)
+
+	// Another secret, a forged payload, and garbage are all invalid
+	_, err = NewSigner("other", 24*time.Hour, 10000).Verify(token)
+	assert.ErrorIs(t, err, ErrInvalid)
+
+	forged, _, _ := NewSigner("other", 24*time.Hour, 10000).Issue("partner", 10000, time.Hour)
+	payload, _, _ := strings.Cut(forged, ".")
+	_, signature, _ := strings.Cut(token, ".")
+	_, err = signer.Verify(payload + "." + signature)
+	assert.ErrorIs(t, err, ErrInvalid)
+
+	for _, bad := range []string{"", "nodot", "a.b", "!!.!!"} {
+		_, err = signer.Verify(bad)
+		assert.ErrorIs(t, err, ErrInvalid, bad)
+	}
+}
+
+func TestSigner_VerifyExpiry(t *testing.T) {
+	signer := NewSigner("secret", 24*time.Hour, 10000)
+	token, _, err := signer.Issue("partner", 100, time.Hour)
+	assert.NoError(t, err)
+
+	signer.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	_, err = signer.Verify(token)
+	assert.ErrorIs(t, err, ErrExpired)
+
+	// Lowering MaxTTL below what is left retires the token
+	_, err = NewSigner("secret", 30*time.Minute, 10000).Verify(token)
+	assert.ErrorIs(t, err, ErrExpired)
+}
+
+func TestSigner_VerifyAppliesCurrentLimitCap(t *testing.T) {
+	token, _, err := NewSigner("secret", 24*time.Hour, 10000).Issue("partner", 8000, time.Hour)
+	assert.NoError(t, err)
+
+	claims, err := NewSigner("secret", 24*time.Hour, 2000).Verify(token)
+	assert.NoError(t, err)
+	assert.Equal(t, 2000, claims.Limit)
+}
@@ -0,0 +1,215 @@
+package export
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+	"yuplan/internal/jobs"
+	"yuplan/internal/models"
+	"yuplan/internal/redact"
+)
+
+const (
+	DatasetReviews  = "reviews"
+	DatasetAuditLog = "audit_log"
+
+	keyDateLayout = "2006-01-02"
+	keySuffix     = ".ndjson.gz"
+)
+
+// Source provides the rows to snapshot. Implemented by repository.ExportRepository.
+type Source interface {
+	StreamReviews(ctx context.Context, fn func(models.Review) error) error
+	StreamAuditLog(ctx context.Context, fn func(models.AuditEntry) error) error
+}
+
+// Exporter writes one gzip-compressed NDJSON snapshot per dataset per UTC day.
+// Object keys are derived from the snapshot date, so re-running an export
+// (after a crash, a redeploy, or a manual trigger) never duplicates data.
+type Exporter struct {
+	source    Source
+	store     Store
+	retention time.Duration
+	now       func() time.Time
+	mu        sync.Mutex
+	locker    jobLocker
+}
+
+// jobLocker keeps scheduled runs to one instance at a time, once per tick. Implemented by jobs.Locker.
+type jobLocker interface {
+	Do(ctx context.Context, job string, tick time.Time, fn func(ctx context.Context) error) (bool, error)
+}
+
+func NewExporter(source Source, store Store, retention time.Duration) *Exporter {
+	return &Exporter{
+		source:    source,
+		store:     store,
+		retention: retention,
+		now:       time.Now,
+	}
+}
+
+// WithLocker makes Start skip runs while another instance holds the export lock.
+func (e *Exporter) WithLocker(locker jobLocker) *Exporter {
+	e.locker = locker
+	return e
+}
+
+// Run exports today's snapshot of every dataset that has not been exported yet,
+// then prunes snapshots older than the retention window. It returns the objects written.
+func (e *Exporter) Run(ctx context.Context) ([]Object, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := e.now().UTC()
+	written := make([]Object, 0, 2)
+
+	for _, dataset := range []string{DatasetReviews, DatasetAuditLog} {
+		key := snapshotKey(dataset, now)
+
+		existing, err := e.store.List(ctx, key)
+		if err != nil {
+			return written, err
+		}
+		if len(existing) > 0 {
+			continue
+		}
+
+		body, err := e.encode(ctx, dataset)
+		if err != nil {
+			return written, fmt.Errorf("export %s: %w", dataset, err)
+		}
+		if err := e.store.Put(ctx, key, body); err != nil {
+			return written, err
+		}
+		written = append(written, Object{Key: key, Size: int64(len(body)), LastModified: now})
+	}
+
+	if err := e.prune(ctx, now); err != nil {
+		return written, err
+	}
+	return written, nil
+}
+
+// List returns every stored snapshot across datasets.
+func (e *Exporter) List(ctx context.Context) ([]Object, error) {
+	objects := make([]Object, 0)
+	for _, dataset := range []string{DatasetReviews, DatasetAuditLog} {
+		found, err := e.store.List(ctx, dataset+"/")
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, found...)
+	}
+	return objects, nil
+}
+
+// Start runs the exporter immediately and then on every interval until ctx is cancelled.
+func (e *Exporter) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := e.runScheduled(ctx, jobs.Tick(time.Now(), interval)); err != nil {
+				log.Printf("export failed: %v", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (e *Exporter) runScheduled(ctx context.Context, tick time.Time) error {
+	run := func(ctx context.Context) error {
+		written, err := e.Run(ctx)
+		if len(written) > 0 {
+			log.Printf("exported %d snapshot(s)", len(written))
+		}
+		return err
+	}
+	if e.locker == nil {
+		return run(ctx)
+	}
+	_, err := e.locker.Do(ctx, "export", tick, run)
+	return err
+}
+
+// encode compresses a dataset's rows as they stream in. The compressed
+// snapshot is buffered whole, since S3Store signs the body's hash before
+// uploading it.
+func (e *Exporter) encode(ctx context.Context, dataset string) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	enc := json.NewEncoder(gz)
+
+	var err error
+	switch dataset {
+	case DatasetReviews:
+		// Snapshots live outside the database, so they get the public view of each review
+		err = e.source.StreamReviews(ctx, func(r models.Review) error {
+			redact.Apply(&r, redact.RolePublic)
+			return enc.Encode(r)
+		})
+	case DatasetAuditLog:
+		err = e.source.StreamAuditLog(ctx, func(a models.AuditEntry) error { return enc.Encode(a) })
+	default:
+		err = fmt.Errorf("unknown dataset %q", dataset)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (e *Exporter) prune(ctx context.Context, now time.Time) error {
+	if e.retention <= 0 {
+		return nil
+	}
+	cutoff := now.Add(-e.retention)
+
+	objects, err := e.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, obj := range objects {
+		day, ok := snapshotDate(obj.Key)
+		if !ok || !day.Before(cutoff) {
+			continue
+		}
+		if err := e.store.Delete(ctx, obj.Key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func snapshotKey(dataset string, t time.Time) string {
+	return dataset + "/" + t.Format(keyDateLayout) + keySuffix
+}
+
+func snapshotDate(key string) (time.Time, bool) {
+	slash := strings.LastIndex(key, "/")
+	if slash < 0 || !strings.HasSuffix(key, keySuffix) {
+		return time.Time{}, false
+	}
+	day, err := time.Parse(keyDateLayout, strings.TrimSuffix(key[slash+1:], keySuffix))
+	if err != nil {
+		return time.Time{}, false
+	}
+	return day, true
+}
@@ -0,0 +1,184 @@
+package export
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSource struct {
+	reviews []models.Review
+	audit   []models.AuditEntry
+	err     error
+	calls   int
+}
+
+func (f *fakeSource) StreamReviews(ctx context.Context, fn func(models.Review) error) error {
+	f.calls++
+	if f.err != nil {
+		return f.err
+	}
+	for _, r := range f.reviews {
+		if err := fn(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *fakeSource) StreamAuditLog(ctx context.Context, fn func(models.AuditEntry) error) error {
+	f.calls++
+	for _, a := range f.audit {
+		if err := fn(a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func gunzip(t *testing.T, body []byte) string {
+	t.Helper()
+	r, err := gzip.NewReader(bytes.NewReader(body))
+	assert.NoError(t, err)
+	out, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	return string(out)
+}
+
+type memStore struct {
+	objects map[string][]byte
+}
+
+func (m *memStore) Put(ctx context.Context, key string, body []byte) error {
+	m.objects[key] = body
+	return nil
+}
+
+func (m *memStore) List(ctx context.Context, prefix string) ([]Object, error) {
+	out := make([]Object, 0)
+	for k, v := range m.objects {
+		if strings.HasPrefix(k, prefix) {
+			out = append(out, Object{Key: k, Size: int64(len(v))})
+		}
+	}
+	return out, nil
+}
+
+func (m *memStore) Delete(ctx context.Context, key string) error {
+	delete(m.objects, key)
+	return nil
+}
+
+func TestExporter_RunWritesOneSnapshotPerDataset(t *testing.T) {
+	source := &fakeSource{
+		reviews: []models.Review{{ID: "r1", CourseCode: "EECS2030", Email: "secret@yorku.ca"}},
+		audit:   []models.AuditEntry{{ID: 1, Entity: "review", EntityID: "r1", Action: "insert"}},
+	}
+	store := &memStore{objects: map[string][]byte{}}
+	exporter := NewExporter(source, store, 0)
+	exporter.now = func() time.Time { return time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC) }
+
+	written, err := exporter.Run(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, written, 2)
+	assert.Contains(t, store.objects, "reviews/2026-03-01.ndjson.gz")
+	assert.Contains(t, store.objects, "audit_log/2026-03-01.ndjson.gz")
+
+	reviews := gunzip(t, store.objects["reviews/2026-03-01.ndjson.gz"])
+	assert.Contains(t, reviews, `"id":"r1"`)
+	assert.NotContains(t, reviews, "secret@yorku.ca")
+}
+
+func TestExporter_RunIsReplaySafe(t *testing.T) {
+	source := &fakeSource{reviews: []models.Review{{ID: "r1"}}}
+	store := &memStore{objects: map[string][]byte{}}
+	exporter := NewExporter(source, store, 0)
+
+	_, err := exporter.Run(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, source.calls)
+
+	written, err := exporter.Run(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, written)
+	assert.Equal(t, 2, source.calls, "second run on the same day must not re-read the source")
+}
+
+func TestExporter_RunPrunesExpiredSnapshots(t *testing.T) {
+	store := &memStore{objects: map[string][]byte{
+		"reviews/2025-01-01.ndjson.gz":   {},
+		"reviews/2026-02-27.ndjson.gz":   {},
+		"audit_log/2025-01-01.ndjson.gz": {},
+		"reviews/not-a-date.ndjson.gz":   {},
+	}}
+	exporter := NewExporter(&fakeSource{}, store, 30*24*time.Hour)
+	exporter.now = func() time.Time { return time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC) }
+
+	_, err := exporter.Run(context.Background())
+	assert.NoError(t, err)
+
+	assert.NotContains(t, store.objects, "reviews/2025-01-01.ndjson.gz")
+	assert.NotContains(t, store.objects, "audit_log/2025-01-01.ndjson.gz")
+	assert.Contains(t, store.objects, "reviews/2026-02-27.ndjson.gz")
+	assert.Contains(t, store.objects, "reviews/not-a-date.ndjson.gz")
+}
+
+func TestExporter_RunSourceErrorWritesNothing(t *testing.T) {
+	store := &memStore{objects: map[string][]byte{}}
+	exporter := NewExporter(&fakeSource{err: errors.New("db down")}, store, 0)
+
+	_, err := exporter.Run(context.Background())
+
+	assert.Error(t, err)
+	assert.Empty(t, store.objects)
+}
+
+func TestExporter_List(t *testing.T) {
+	store := &memStore{objects: map[string][]byte{
+		"reviews/2026-03-01.ndjson.gz":   {},
+		"audit_log/2026-03-01.ndjson.gz": {},
+		"unrelated/file":                 {},
+	}}
+	exporter := NewExporter(&fakeSource{}, store, 0)
+
+	objects, err := exporter.List(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, objects, 2)
+}
+
+type fakeLocker struct {
+	held bool
+	jobs []string
+}
+
+func (f *fakeLocker) Do(ctx context.Context, job string, tick time.Time, fn func(ctx context.Context) error) (bool, error) {
+	f.jobs = append(f.jobs, job)
+	if f.held {
+		return false, nil
+	}
+	return true, fn(ctx)
+}
+
+func TestExporter_ScheduledRunsRespectLocker(t *testing.T) {
+	source := &fakeSource{}
+	store := &memStore{objects: map[string][]byte{}}
+	locker := &fakeLocker{held: true}
+	exporter := NewExporter(source, store, 0).WithLocker(locker)
+
+	assert.NoError(t, exporter.runScheduled(context.Background(), time.Now()))
+	assert.Equal(t, 0, source.calls, "must not export while another instance holds the lock")
+
+	locker.held = false
+	assert.NoError(t, exporter.runScheduled(context.Background(), time.Now()))
+	assert.Equal(t, 2, source.calls)
+	assert.Equal(t, []string{"export", "export"}, locker.jobs)
+}
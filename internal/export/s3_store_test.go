@@ -0,0 +1,83 @@
+package export
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestS3Store_PutSignsRequest(t *testing.T) {
+	var gotPath, gotAuth, gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotAuth = r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	store := NewS3Store(server.URL, "us-east-1", "yuplan", "AKID", "secret")
+	store.now = func() time.Time { return time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC) }
+
+	err := store.Put(context.Background(), "reviews/2026-03-01.ndjson.gz", []byte("payload"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "/yuplan/reviews/2026-03-01.ndjson.gz", gotPath)
+	assert.Equal(t, "payload", gotBody)
+	assert.True(t, strings.HasPrefix(gotAuth, "AWS4-HMAC-SHA256 Credential=AKID/20260301/us-east-1/s3/aws4_request"))
+	assert.Contains(t, gotAuth, "SignedHeaders=host;x-amz-content-sha256;x-amz-date")
+}
+
+func TestS3Store_ListPaginates(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		assert.Equal(t, "2", r.URL.Query().Get("list-type"))
+		assert.Equal(t, "reviews/", r.URL.Query().Get("prefix"))
+		if r.URL.Query().Get("continuation-token") == "" {
+			io.WriteString(w, `<ListBucketResult><IsTruncated>true</IsTruncated><NextContinuationToken>next</NextContinuationToken>
+<Contents><Key>reviews/2026-03-01.ndjson.gz</Key><Size>10</Size><LastModified>2026-03-01T00:00:00.000Z</LastModified></Contents>
+</ListBucketResult>`)
+			return
+		}
+		io.WriteString(w, `<ListBucketResult><IsTruncated>false</IsTruncated>
+<Contents><Key>reviews/2026-03-02.ndjson.gz</Key><Size>12</Size><LastModified>2026-03-02T00:00:00.000Z</LastModified></Contents>
+</ListBucketResult>`)
+	}))
+	defer server.Close()
+
+	store := NewS3Store(server.URL, "us-east-1", "yuplan", "AKID", "secret")
+	objects, err := store.List(context.Background(), "reviews/")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+	assert.Len(t, objects, 2)
+	assert.Equal(t, "reviews/2026-03-02.ndjson.gz", objects[1].Key)
+	assert.Equal(t, int64(12), objects[1].Size)
+}
+
+func TestS3Store_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		io.WriteString(w, "AccessDenied")
+	}))
+	defer server.Close()
+
+	store := NewS3Store(server.URL, "us-east-1", "yuplan", "AKID", "secret")
+	err := store.Delete(context.Background(), "reviews/x")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "403")
+}
+
+func TestCanonicalQuery(t *testing.T) {
+	assert.Equal(t, "", canonicalQuery(nil))
+	assert.Equal(t, "a=1&b=x%20y", canonicalQuery(map[string][]string{"b": {"x y"}, "a": {"1"}}))
+}
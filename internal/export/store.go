@@ -0,0 +1,96 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Object describes a stored export file.
+type Object struct {
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+// Store is the minimal object storage surface the exporter needs.
+type Store interface {
+	Put(ctx context.Context, key string, body []byte) error
+	List(ctx context.Context, prefix string) ([]Object, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// FileStore keeps exports on the local filesystem. Useful for development and
+// for deployments that mount a backed-up volume instead of using S3.
+type FileStore struct {
+	root string
+}
+
+func NewFileStore(root string) *FileStore {
+	return &FileStore{root: root}
+}
+
+func (s *FileStore) Put(ctx context.Context, key string, body []byte) error {
+	path := filepath.Join(s.root, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create export dir: %w", err)
+	}
+
+	// Write to a temp file and rename so a crashed export never leaves a partial object behind.
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, body, 0o644); err != nil {
+		return fmt.Errorf("write export: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("finalize export: %w", err)
+	}
+	return nil
+}
+
+func (s *FileStore) List(ctx context.Context, prefix string) ([]Object, error) {
+	objects := make([]Object, 0)
+	err := filepath.WalkDir(s.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || strings.HasSuffix(path, ".tmp") {
+			return nil
+		}
+		rel, err := filepath.Rel(s.root, path)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, Object{Key: key, Size: info.Size(), LastModified: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list exports: %w", err)
+	}
+
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Key < objects[j].Key })
+	return objects, nil
+}
+
+func (s *FileStore) Delete(ctx context.Context, key string) error {
+	if err := os.Remove(filepath.Join(s.root, filepath.FromSlash(key))); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("delete export: %w", err)
+	}
+	return nil
+}
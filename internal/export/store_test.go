@@ -0,0 +1,38 @@
+package export
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileStore_PutListDelete(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	ctx := context.Background()
+
+	assert.NoError(t, store.Put(ctx, "reviews/2026-03-01.ndjson.gz", []byte("abc")))
+	assert.NoError(t, store.Put(ctx, "audit_log/2026-03-01.ndjson.gz", []byte("de")))
+
+	objects, err := store.List(ctx, "reviews/")
+	assert.NoError(t, err)
+	assert.Len(t, objects, 1)
+	assert.Equal(t, "reviews/2026-03-01.ndjson.gz", objects[0].Key)
+	assert.Equal(t, int64(3), objects[0].Size)
+
+	assert.NoError(t, store.Delete(ctx, "reviews/2026-03-01.ndjson.gz"))
+	objects, err = store.List(ctx, "reviews/")
+	assert.NoError(t, err)
+	assert.Empty(t, objects)
+
+	// Deleting a missing object is not an error
+	assert.NoError(t, store.Delete(ctx, "reviews/2026-03-01.ndjson.gz"))
+}
+
+func TestFileStore_ListMissingRoot(t *testing.T) {
+	store := NewFileStore(t.TempDir() + "/does-not-exist")
+
+	objects, err := store.List(context.Background(), "")
+	assert.NoError(t, err)
+	assert.Empty(t, objects)
+}
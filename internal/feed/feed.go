@@ -0,0 +1,69 @@
+// Package feed builds the randomized course discovery feed, personalized for
+// reviewers who identify themselves by email.
+package feed
+
+import (
+	"context"
+	"log"
+	"time"
+	"yuplan/internal/models"
+)
+
+// Store picks courses for and records views by one reviewer. Implemented by repository.FeedRepository.
+type Store interface {
+	PersonalizedCourses(ctx context.Context, email string, seenSince time.Time, limit int) ([]models.Course, error)
+	RecordSeen(ctx context.Context, email string, courseCodes []string) error
+}
+
+// Shuffler picks random courses for anyone. Implemented by repository.CourseRepository.
+type Shuffler interface {
+	GetRandomCourses(ctx context.Context, limit int) ([]models.Course, error)
+}
+
+// Service serves the course feed.
+type Service struct {
+	store   Store
+	shuffle Shuffler
+	seenTTL time.Duration
+	now     func() time.Time
+}
+
+// NewService keeps courses a reviewer has seen out of their feed for seenTTL.
+func NewService(store Store, shuffle Shuffler, seenTTL time.Duration) *Service {
+	return &Service{store: store, shuffle: shuffle, seenTTL: seenTTL, now: time.Now}
+}
+
+// Courses returns up to limit random courses. For a reviewer, courses they
+// have reviewed or seen recently are left out and their departments are
+// favoured. Anyone else, or a reviewer whose feed fails or has run dry, gets
+// the anonymous shuffle.
+func (s *Service) Courses(ctx context.Context, email string, limit int) ([]models.Course, error) {
+	if email != "" {
+		courses, err := s.store.PersonalizedCourses(ctx, email, s.now().Add(-s.seenTTL), limit)
+		if err == nil && len(courses) > 0 {
+			return courses, nil
+		}
+		if err != nil {
+			log.Printf("personalized course feed: %v; falling back to shuffle", err)
+		}
+	}
+	return s.shuffle.GetRandomCourses(ctx, limit)
+}
+
+// RecordSeen keeps courses out of the reviewer's feed for the seen TTL.
+func (s *Service) RecordSeen(ctx context.Context, email string, courseCodes []string) error {
+	codes := make([]string, 0, len(courseCodes))
+	seen := map[string]bool{}
+	for _, code := range courseCodes {
+		code = models.NormalizeCourseCode(code)
+		if code == "" || seen[code] {
+			continue
+		}
+		seen[code] = true
+		codes = append(codes, code)
+	}
+	if len(codes) == 0 {
+		return nil
+	}
+	return s.store.RecordSeen(ctx, email, codes)
+}
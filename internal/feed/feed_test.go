@@ -0,0 +1,85 @@
+package feed
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeStore struct {
+	courses   []models.Course
+	err       error
+	seenSince time.Time
+	recorded  []string
+}
+
+func (f *fakeStore) PersonalizedCourses(ctx context.Context, email string, seenSince time.Time, limit int) ([]models.Course, error) {
+	f.seenSince = seenSince
+	return f.courses, f.err
+}
+
+func (f *fakeStore) RecordSeen(ctx context.Context, email string, courseCodes []string) error {
+	f.recorded = courseCodes
+	return f.err
+}
+
+type fakeShuffler struct{ calls int }
+
+func (f *fakeShuffler) GetRandomCourses(ctx context.Context, limit int) ([]models.Course, error) {
+	f.calls++
+	return []models.Course{{Code: "RAND1000"}}, nil
+}
+
+func newService(store *fakeStore, shuffle *fakeShuffler) *Service {
+	s := NewService(store, shuffle, 30*24*time.Hour)
+	s.now = func() time.Time { return time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC) }
+	return s
+}
+
+func TestCourses_Personalized(t *testing.T) {
+	store := &fakeStore{courses: []models.Course{{Code: "EECS2030"}}}
+	shuffle := &fakeShuffler{}
+
+	courses, err := newService(store, shuffle).Courses(context.Background(), "student@my.yorku.ca", 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "EECS2030", courses[0].Code)
+	assert.Equal(t, time.Date(2026, 9, 16, 12, 0, 0, 0, time.UTC), store.seenSince)
+	assert.Zero(t, shuffle.calls)
+}
+
+func TestCourses_FallsBackToShuffle(t *testing.T) {
+	tests := []struct {
+		name  string
+		email string
+		store *fakeStore
+	}{
+		{"anonymous", "", &fakeStore{courses: []models.Course{{Code: "EECS2030"}}}},
+		{"feed fails", "student@my.yorku.ca", &fakeStore{err: errors.New("db down")}},
+		{"feed ran dry", "student@my.yorku.ca", &fakeStore{courses: []models.Course{}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shuffle := &fakeShuffler{}
+
+			courses, err := newService(tt.store, shuffle).Courses(context.Background(), tt.email, 10)
+
+			assert.NoError(t, err)
+			assert.Equal(t, "RAND1000", courses[0].Code)
+			assert.Equal(t, 1, shuffle.calls)
+		})
+	}
+}
+
+func TestRecordSeen_NormalizesCodes(t *testing.T) {
+	store := &fakeStore{}
+
+	err := newService(store, &fakeShuffler{}).RecordSeen(context.Background(), "student@my.yorku.ca", []string{"eecs 2030", "EECS2030", " ", "MATH1090"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"EECS2030", "MATH1090"}, store.recorded)
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"yuplan/internal/config"
+	"yuplan/internal/models"
+	"yuplan/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// reviewFunnel summarizes review lifecycle events. Implemented by repository.ReviewEventRepository.
+type reviewFunnel interface {
+	Funnel(ctx context.Context, since time.Time) (*models.ReviewFunnel, error)
+}
+
+// reportTrends counts review reports over time. Implemented by repository.ReportRepository.
+type reportTrends interface {
+	Trends(ctx context.Context, since time.Time, interval string) ([]models.ReportTrend, error)
+}
+
+type AnalyticsHandler struct {
+	searchStats repository.SearchStatsRepositoryInterface
+	reviews     reviewFunnel
+	reports     reportTrends
+}
+
+func NewAnalyticsHandler(searchStats repository.SearchStatsRepositoryInterface) *AnalyticsHandler {
+	return &AnalyticsHandler{searchStats: searchStats}
+}
+
+// WithReviewFunnel enables GET /api/v1/admin/analytics/reviews.
+func (h *AnalyticsHandler) WithReviewFunnel(reviews reviewFunnel) *AnalyticsHandler {
+	h.reviews = reviews
+	return h
+}
+
+// WithReportTrends enables GET /api/v1/admin/analytics/reports.
+func (h *AnalyticsHandler) WithReportTrends(reports reportTrends) *AnalyticsHandler {
+	h.reports = reports
+	return h
+}
+
+// analyticsDays reads ?days=, falling back to 30 outside 1-365.
+func analyticsDays(c *gin.Context) int {
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if days < 1 || days > 365 {
+		days = 30
+	}
+	return days
+}
+
+// GetSearchAnalytics handles GET /api/v1/admin/analytics/searches
+func (h *AnalyticsHandler) GetSearchAnalytics(c *gin.Context) {
+	days := analyticsDays(c)
+	limit, ok := pageLimit(c, config.PagesAnalytics, "limit")
+	if !ok {
+		return
+	}
+
+	since := time.Now().UTC().AddDate(0, 0, -days)
+
+	top, err := h.searchStats.Top(c.Request.Context(), since, limit)
+	if err != nil {
+		serverError(c, err, "Failed to fetch search analytics")
+		return
+	}
+
+	zeroResults, err := h.searchStats.TopZeroResult(c.Request.Context(), since, limit)
+	if err != nil {
+		serverError(c, err, "Failed to fetch search analytics")
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"data": gin.H{
+			"top":          top,
+			"zero_results": zeroResults,
+		},
+		"days": days,
+	})
+}
+
+// GetReviewAnalytics handles GET /api/v1/admin/analytics/reviews?days=30: of the
+// reviews submitted in the window, how many were verified and published.
+func (h *AnalyticsHandler) GetReviewAnalytics(c *gin.Context) {
+	if h.reviews == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Review analytics are not enabled"})
+		return
+	}
+
+	days := analyticsDays(c)
+	funnel, err := h.reviews.Funnel(c.Request.Context(), time.Now().UTC().AddDate(0, 0, -days))
+	if err != nil {
+		serverError(c, err, "Failed to fetch review analytics")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": funnel,
+		"days": days,
+	})
+}
+
+// GetReportAnalytics handles GET /api/v1/admin/analytics/reports?days=30&interval=week:
+// how many reviews were reported in each day, week or month of the window, by
+// reason, to show which kinds of abuse are growing.
+func (h *AnalyticsHandler) GetReportAnalytics(c *gin.Context) {
+	if h.reports == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Report analytics are not enabled"})
+		return
+	}
+	interval := c.DefaultQuery("interval", models.TrendWeek)
+	if !slices.Contains(models.TrendIntervals, interval) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "interval must be one of " + strings.Join(models.TrendIntervals, ", "),
+			"code":  models.ErrCodeBadRequest,
+		})
+		return
+	}
+
+	days := analyticsDays(c)
+	trends, err := h.reports.Trends(c.Request.Context(), time.Now().UTC().AddDate(0, 0, -days), interval)
+	if err != nil {
+		serverError(c, err, "Failed to fetch report analytics")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":     trends,
+		"days":     days,
+		"interval": interval,
+	})
+}
@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockSearchStatsRepository struct {
+	topFunc           func(ctx context.Context, since time.Time, limit int) ([]models.SearchStat, error)
+	topZeroResultFunc func(ctx context.Context, since time.Time, limit int) ([]models.SearchStat, error)
+}
+
+func (m *mockSearchStatsRepository) Increment(ctx context.Context, day time.Time, stats []models.SearchStat) error {
+	return nil
+}
+
+func (m *mockSearchStatsRepository) Top(ctx context.Context, since time.Time, limit int) ([]models.SearchStat, error) {
+	if m.topFunc != nil {
+		return m.topFunc(ctx, since, limit)
+	}
+	return []models.SearchStat{}, nil
+}
+
+func (m *mockSearchStatsRepository) TopZeroResult(ctx context.Context, since time.Time, limit int) ([]models.SearchStat, error) {
+	if m.topZeroResultFunc != nil {
+		return m.topZeroResultFunc(ctx, since, limit)
+	}
+	return []models.SearchStat{}, nil
+}
+
+func TestGetSearchAnalytics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var gotLimit int
+	var gotSince time.Time
+	repo := &mockSearchStatsRepository{
+		topFunc: func(ctx context.Context, since time.Time, limit int) ([]models.SearchStat, error) {
+			gotLimit, gotSince = limit, since
+			return []models.SearchStat{{Query: "eecs", Searches: 40}}, nil
+		},
+		topZeroResultFunc: func(ctx context.Context, since time.Time, limit int) ([]models.SearchStat, error) {
+			return []models.SearchStat{{Query: "nurs", Searches: 9, ZeroResults: 9}}, nil
+		},
+	}
+
+	router := gin.New()
+	router.GET("/admin/analytics/searches", NewAnalyticsHandler(repo).GetSearchAnalytics)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/admin/analytics/searches?days=7&limit=5", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 5, gotLimit)
+	assert.WithinDuration(t, time.Now().AddDate(0, 0, -7), gotSince, time.Minute)
+	assert.Contains(t, w.Body.String(), `"top":[{"query":"eecs"`)
+	assert.Contains(t, w.Body.String(), `"zero_results":[{"query":"nurs"`)
+}
+
+func TestGetSearchAnalytics_InvalidDaysUseDefault(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var gotLimit int
+	repo := &mockSearchStatsRepository{
+		topFunc: func(ctx context.Context, since time.Time, limit int) ([]models.SearchStat, error) {
+			gotLimit = limit
+			return []models.SearchStat{}, nil
+		},
+	}
+
+	router := gin.New()
+	router.GET("/admin/analytics/searches", NewAnalyticsHandler(repo).GetSearchAnalytics)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/admin/analytics/searches?days=9999", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 20, gotLimit)
+	assert.Contains(t, w.Body.String(), `"days":30`)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/analytics/searches?limit=-1", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetSearchAnalytics_RepoError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := &mockSearchStatsRepository{
+		topZeroResultFunc: func(ctx context.Context, since time.Time, limit int) ([]models.SearchStat, error) {
+			return nil, errors.New("db down")
+		},
+	}
+
+	router := gin.New()
+	router.GET("/admin/analytics/searches", NewAnalyticsHandler(repo).GetSearchAnalytics)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/analytics/searches", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+type stubReviewFunnel struct {
+	funnel   *models.ReviewFunnel
+	err      error
+	gotSince time.Time
+}
+
+func (s *stubReviewFunnel) Funnel(ctx context.Context, since time.Time) (*models.ReviewFunnel, error) {
+	s.gotSince = since
+	return s.funnel, s.err
+}
+
+func TestGetReviewAnalytics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	funnel := &stubReviewFunnel{funnel: &models.ReviewFunnel{
+		Submitted: 4, Published: 3, PublicationRate: 0.75,
+		Events: map[string]int{models.ReviewEventCreated: 4},
+	}}
+	router := gin.New()
+	router.GET("/admin/analytics/reviews", NewAnalyticsHandler(&mockSearchStatsRepository{}).WithReviewFunnel(funnel).GetReviewAnalytics)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/analytics/reviews?days=7", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.WithinDuration(t, time.Now().AddDate(0, 0, -7), funnel.gotSince, time.Minute)
+	assert.Contains(t, w.Body.String(), `"submitted":4`)
+	assert.Contains(t, w.Body.String(), `"publication_rate":0.75`)
+	assert.Contains(t, w.Body.String(), `"events":{"created":4}`)
+	assert.Contains(t, w.Body.String(), `"days":7`)
+}
+
+func TestGetReviewAnalytics_Errors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		handler        *AnalyticsHandler
+		expectedStatus int
+	}{
+		{"not enabled", NewAnalyticsHandler(&mockSearchStatsRepository{}), http.StatusNotFound},
+		{"repo error", NewAnalyticsHandler(&mockSearchStatsRepository{}).WithReviewFunnel(&stubReviewFunnel{err: errors.New("db down")}), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/admin/analytics/reviews", tt.handler.GetReviewAnalytics)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/analytics/reviews", nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+type stubReportTrends struct {
+	trends      []models.ReportTrend
+	err         error
+	gotSince    time.Time
+	gotInterval string
+}
+
+func (s *stubReportTrends) Trends(ctx context.Context, since time.Time, interval string) ([]models.ReportTrend, error) {
+	s.gotSince, s.gotInterval = since, interval
+	return s.trends, s.err
+}
+
+func TestGetReportAnalytics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	week := time.Date(2026, 9, 28, 0, 0, 0, 0, time.UTC)
+	reports := &stubReportTrends{trends: []models.ReportTrend{
+		{PeriodStart: week, Total: 3, Reasons: map[string]int{models.ReportReasonSpam: 3}},
+	}}
+	router := gin.New()
+	router.GET("/admin/analytics/reports", NewAnalyticsHandler(&mockSearchStatsRepository{}).WithReportTrends(reports).GetReportAnalytics)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/analytics/reports?days=90", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.WithinDuration(t, time.Now().AddDate(0, 0, -90), reports.gotSince, time.Minute)
+	assert.Equal(t, models.TrendWeek, reports.gotInterval, "weeks by default")
+	assert.Contains(t, w.Body.String(), `"period_start":"2026-09-28T00:00:00Z"`)
+	assert.Contains(t, w.Body.String(), `"reasons":{"spam":3}`)
+	assert.Contains(t, w.Body.String(), `"interval":"week"`)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/analytics/reports?interval=month", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, models.TrendMonth, reports.gotInterval)
+}
+
+func TestGetReportAnalytics_Errors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		handler        *AnalyticsHandler
+		query          string
+		expectedStatus int
+	}{
+		{"not enabled", NewAnalyticsHandler(&mockSearchStatsRepository{}), "", http.StatusNotFound},
+		{"bad interval", NewAnalyticsHandler(&mockSearchStatsRepository{}).WithReportTrends(&stubReportTrends{}), "?interval=hour", http.StatusBadRequest},
+		{"repo error", NewAnalyticsHandler(&mockSearchStatsRepository{}).WithReportTrends(&stubReportTrends{err: errors.New("db down")}), "", http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/admin/analytics/reports", tt.handler.GetReportAnalytics)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/analytics/reports"+tt.query, nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"yuplan/internal/models"
+	"yuplan/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AvailabilityHandler struct {
+	repo repository.AvailabilityRepositoryInterface
+}
+
+func NewAvailabilityHandler(repo repository.AvailabilityRepositoryInterface) *AvailabilityHandler {
+	return &AvailabilityHandler{repo: repo}
+}
+
+// GetAvailability handles GET /api/v1/sections/:section_id/availability. The
+// route shares its wildcard with /sections/:course_id, so the id arrives as
+// course_id.
+func (h *AvailabilityHandler) GetAvailability(c *gin.Context) {
+	availability, err := h.repo.GetBySection(c.Request.Context(), c.Param("course_id"))
+	if err != nil {
+		serverError(c, err, "Failed to fetch seat availability")
+		return
+	}
+	if availability == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Section not found"})
+		return
+	}
+	respond(c, http.StatusOK, gin.H{"data": availability})
+}
+
+// UpdateAvailability handles PUT /api/v1/admin/availability
+// Stores the seat counts the scraper reports; see models.UpdateAvailabilityRequest.
+// Updates naming no known section or activity are returned as unmatched.
+func (h *AvailabilityHandler) UpdateAvailability(c *gin.Context) {
+	var req models.UpdateAvailabilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// A key reported twice keeps its last count
+	updates := make([]models.AvailabilityUpdate, 0, len(req.Updates))
+	index := map[string]int{}
+	for i, u := range req.Updates {
+		key := u.SectionID
+		if u.SectionID == "" {
+			term, ok := models.ParseTermID(u.Term)
+			if !ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("updates[%d]: term must be a session and year, like FW2025 or SU2026", i)})
+				return
+			}
+			u.Term = term
+			u.CatalogNumber = strings.ToUpper(strings.TrimSpace(u.CatalogNumber))
+			key = u.Term + "/" + u.CatalogNumber
+		}
+		if j, seen := index[key]; seen {
+			updates[j] = u
+			continue
+		}
+		index[key] = len(updates)
+		updates = append(updates, u)
+	}
+
+	unmatched, err := h.repo.Apply(c.Request.Context(), updates)
+	if err != nil {
+		serverError(c, err, "Failed to update seat availability")
+		return
+	}
+	if unmatched == nil {
+		unmatched = []models.AvailabilityUpdate{}
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"count":     len(updates) - len(unmatched),
+		"unmatched": unmatched,
+		"message":   "Seat availability updated",
+	})
+}
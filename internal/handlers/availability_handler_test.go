@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"yuplan/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockAvailabilityRepository struct {
+	sections map[string]*models.SectionAvailability
+	applied  []models.AvailabilityUpdate
+	err      error
+}
+
+func (m *mockAvailabilityRepository) GetBySection(ctx context.Context, sectionID string) (*models.SectionAvailability, error) {
+	return m.sections[sectionID], m.err
+}
+
+func (m *mockAvailabilityRepository) Apply(ctx context.Context, updates []models.AvailabilityUpdate) ([]models.AvailabilityUpdate, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.applied = updates
+	var unmatched []models.AvailabilityUpdate
+	for _, u := range updates {
+		if u.SectionID == "" && u.CatalogNumber == "MISSING" {
+			unmatched = append(unmatched, u)
+		}
+	}
+	return unmatched, nil
+}
+
+func (m *mockAvailabilityRepository) PastOfferingSnapshots(ctx context.Context, sectionID string) (bool, []models.EnrollmentSnapshot, error) {
+	return m.sections[sectionID] != nil, nil, m.err
+}
+
+const availableSection = "7c9e6679-7425-40de-944b-e07fc1f90ae7"
+
+func newAvailabilityRouter(repo *mockAvailabilityRepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewAvailabilityHandler(repo)
+	router := gin.New()
+	router.GET("/sections/:course_id/availability", handler.GetAvailability)
+	router.PUT("/admin/availability", handler.UpdateAvailability)
+	return router
+}
+
+func serveAvailability(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+	return w
+}
+
+func TestGetAvailability(t *testing.T) {
+	capacity, enrolled := 120, 130
+	section := &models.SectionAvailability{
+		SectionID:        availableSection,
+		Letter:           "A",
+		SeatAvailability: models.SeatAvailability{Capacity: &capacity, Enrolled: &enrolled},
+		Activities:       []models.ActivityAvailability{{ID: "act-1", CourseType: "LECT", CatalogNumber: "K12A01"}},
+	}
+	section.FillRemaining()
+	router := newAvailabilityRouter(&mockAvailabilityRepository{sections: map[string]*models.SectionAvailability{availableSection: section}})
+
+	w := serveAvailability(router, http.MethodGet, "/sections/"+availableSection+"/availability", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"capacity":120,"enrolled":130,"seats_remaining":0`)
+	assert.Contains(t, w.Body.String(), `"catalog_number":"K12A01","capacity":null,"enrolled":null,"seats_remaining":null,"updated_at":null`)
+
+	w = serveAvailability(router, http.MethodGet, "/sections/00000000-0000-0000-0000-000000000000/availability", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestGetAvailability_Error(t *testing.T) {
+	router := newAvailabilityRouter(&mockAvailabilityRepository{err: errors.New("db down")})
+
+	w := serveAvailability(router, http.MethodGet, "/sections/"+availableSection+"/availability", "")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestUpdateAvailability(t *testing.T) {
+	repo := &mockAvailabilityRepository{}
+	router := newAvailabilityRouter(repo)
+
+	w := serveAvailability(router, http.MethodPut, "/admin/availability", `{"updates": [
+		{"section_id": "`+availableSection+`", "capacity": 100, "enrolled": 40},
+		{"term": "fw2025", "catalog_number": " k12a01 ", "capacity": 50, "enrolled": 10},
+		{"term": "FW2025", "catalog_number": "K12A01", "capacity": 50, "enrolled": 12},
+		{"term": "FW2025", "catalog_number": "MISSING", "capacity": 0, "enrolled": 0}
+	]}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, repo.applied, 3)
+	assert.Equal(t, "FW2025", repo.applied[1].Term)
+	assert.Equal(t, "K12A01", repo.applied[1].CatalogNumber)
+	assert.Equal(t, 12, *repo.applied[1].Enrolled, "the last count for a key wins")
+	assert.Contains(t, w.Body.String(), `"count":2`)
+	assert.Contains(t, w.Body.String(), `"catalog_number":"MISSING"`)
+}
+
+func TestUpdateAvailability_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"empty", `{"updates": []}`},
+		{"no target", `{"updates": [{"capacity": 1, "enrolled": 0}]}`},
+		{"both targets", `{"updates": [{"section_id": "` + availableSection + `", "term": "FW2025", "catalog_number": "K12A01", "capacity": 1, "enrolled": 0}]}`},
+		{"no term", `{"updates": [{"catalog_number": "K12A01", "capacity": 1, "enrolled": 0}]}`},
+		{"bad term", `{"updates": [{"term": "fall", "catalog_number": "K12A01", "capacity": 1, "enrolled": 0}]}`},
+		{"missing count", `{"updates": [{"section_id": "` + availableSection + `", "capacity": 1}]}`},
+		{"negative", `{"updates": [{"section_id": "` + availableSection + `", "capacity": -1, "enrolled": 0}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockAvailabilityRepository{}
+			w := serveAvailability(newAvailabilityRouter(repo), http.MethodPut, "/admin/availability", tt.body)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+			assert.Nil(t, repo.applied)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"yuplan/internal/models"
+	"yuplan/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// badgeAwarder recomputes reviewer badges on demand. Implemented by badges.Awarder.
+type badgeAwarder interface {
+	Run(ctx context.Context) (int, error)
+}
+
+var badgeSlugRe = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+type BadgeHandler struct {
+	repo    repository.BadgeRepositoryInterface
+	awarder badgeAwarder
+}
+
+func NewBadgeHandler(repo repository.BadgeRepositoryInterface, awarder badgeAwarder) *BadgeHandler {
+	return &BadgeHandler{repo: repo, awarder: awarder}
+}
+
+// ListBadges handles GET /api/v1/badges so clients can show names for the
+// slugs in review author_badges.
+func (h *BadgeHandler) ListBadges(c *gin.Context) {
+	badges, err := h.repo.ListBadges(c.Request.Context())
+	if err != nil {
+		serverError(c, err, "Failed to fetch badges")
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"data":  badges,
+		"count": len(badges),
+	})
+}
+
+// CreateBadge handles POST /api/v1/admin/badges. The badge is awarded on the next job run.
+func (h *BadgeHandler) CreateBadge(c *gin.Context) {
+	var req models.CreateBadgeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !badgeSlugRe.MatchString(req.Slug) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Slug must be lowercase letters and digits separated by dashes"})
+		return
+	}
+
+	badge := &models.Badge{
+		Slug:        req.Slug,
+		Name:        req.Name,
+		Description: req.Description,
+		Metric:      req.Metric,
+		Threshold:   req.Threshold,
+	}
+	created, err := h.repo.CreateBadge(c.Request.Context(), badge)
+	if err != nil {
+		serverError(c, err, "Failed to create badge")
+		return
+	}
+	if !created {
+		c.JSON(http.StatusConflict, gin.H{"error": "A badge with that slug already exists"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data":    badge,
+		"message": "Badge created",
+	})
+}
+
+// DeleteBadge handles DELETE /api/v1/admin/badges/:slug, revoking it from every reviewer.
+func (h *BadgeHandler) DeleteBadge(c *gin.Context) {
+	deleted, err := h.repo.DeleteBadge(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		serverError(c, err, "Failed to delete badge")
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Badge not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Badge deleted"})
+}
+
+// RefreshBadges handles POST /api/v1/admin/badges/refresh
+func (h *BadgeHandler) RefreshBadges(c *gin.Context) {
+	n, err := h.awarder.Run(c.Request.Context())
+	if err != nil {
+		serverError(c, err, "Failed to award badges")
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"count":   n,
+		"message": "Badges refreshed",
+	})
+}
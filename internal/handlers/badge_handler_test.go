@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"yuplan/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockBadgeRepository struct {
+	badges    []models.Badge
+	created   *models.Badge
+	createErr error
+	taken     bool
+	deleted   bool
+	byEmail   map[string][]string
+}
+
+func (m *mockBadgeRepository) ListBadges(ctx context.Context) ([]models.Badge, error) {
+	return m.badges, nil
+}
+
+func (m *mockBadgeRepository) CreateBadge(ctx context.Context, badge *models.Badge) (bool, error) {
+	if m.createErr != nil || m.taken {
+		return false, m.createErr
+	}
+	m.created = badge
+	return true, nil
+}
+
+func (m *mockBadgeRepository) DeleteBadge(ctx context.Context, slug string) (bool, error) {
+	return m.deleted, nil
+}
+
+func (m *mockBadgeRepository) ListReviewerStats(ctx context.Context) ([]models.ReviewerStats, error) {
+	return nil, nil
+}
+
+func (m *mockBadgeRepository) ReplaceAwards(ctx context.Context, awards []models.BadgeAward) error {
+	return nil
+}
+
+func (m *mockBadgeRepository) BadgesByEmail(ctx context.Context, emails []string) (map[string][]string, error) {
+	return m.byEmail, nil
+}
+
+type fakeBadgeAwarder struct {
+	n   int
+	err error
+}
+
+func (f fakeBadgeAwarder) Run(ctx context.Context) (int, error) {
+	return f.n, f.err
+}
+
+func serveBadges(handler *BadgeHandler, method, path, body string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/badges", handler.ListBadges)
+	router.POST("/admin/badges", handler.CreateBadge)
+	router.DELETE("/admin/badges/:slug", handler.DeleteBadge)
+	router.POST("/admin/badges/refresh", handler.RefreshBadges)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(method, path, bytes.NewBufferString(body)))
+	return w
+}
+
+func TestCreateBadge(t *testing.T) {
+	valid := `{"slug": "prolific", "name": "Prolific", "metric": "reviews", "threshold": 20}`
+	tests := []struct {
+		name           string
+		body           string
+		repo           *mockBadgeRepository
+		expectedStatus int
+	}{
+		{"created", valid, &mockBadgeRepository{}, http.StatusCreated},
+		{"slug taken", valid, &mockBadgeRepository{taken: true}, http.StatusConflict},
+		{"helpful votes metric", `{"slug": "helpful", "name": "Helpful", "metric": "helpful_votes", "threshold": 10}`, &mockBadgeRepository{}, http.StatusCreated},
+		{"unknown metric", `{"slug": "streak", "name": "Streak", "metric": "streak_days", "threshold": 10}`, &mockBadgeRepository{}, http.StatusBadRequest},
+		{"zero threshold", `{"slug": "x", "name": "X", "metric": "reviews", "threshold": 0}`, &mockBadgeRepository{}, http.StatusBadRequest},
+		{"bad slug", `{"slug": "Not A Slug", "name": "X", "metric": "reviews", "threshold": 1}`, &mockBadgeRepository{}, http.StatusBadRequest},
+		{"repository error", valid, &mockBadgeRepository{createErr: errors.New("db down")}, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveBadges(NewBadgeHandler(tt.repo, nil), http.MethodPost, "/admin/badges", tt.body)
+
+			assert.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			if tt.body == valid && tt.expectedStatus == http.StatusCreated {
+				assert.Equal(t, "prolific", tt.repo.created.Slug)
+				assert.Equal(t, 20, tt.repo.created.Threshold)
+			}
+		})
+	}
+}
+
+func TestDeleteBadge_NotFound(t *testing.T) {
+	w := serveBadges(NewBadgeHandler(&mockBadgeRepository{}, nil), http.MethodDelete, "/admin/badges/missing", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRefreshBadges(t *testing.T) {
+	w := serveBadges(NewBadgeHandler(&mockBadgeRepository{}, fakeBadgeAwarder{n: 12}), http.MethodPost, "/admin/badges/refresh", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"count":12`)
+
+	w = serveBadges(NewBadgeHandler(&mockBadgeRepository{}, fakeBadgeAwarder{err: errors.New("db down")}), http.MethodPost, "/admin/badges/refresh", "")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"yuplan/internal/models"
+	"yuplan/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BlockHandler struct {
+	repo repository.BlockRepositoryInterface
+}
+
+func NewBlockHandler(repo repository.BlockRepositoryInterface) *BlockHandler {
+	return &BlockHandler{repo: repo}
+}
+
+// GetBlocksByCourseID handles GET /api/v1/blocks/:course_id
+func (h *BlockHandler) GetBlocksByCourseID(c *gin.Context) {
+	courseID := c.Param("course_id")
+
+	blocks, err := h.repo.GetByCourseID(c.Request.Context(), courseID)
+	if err != nil {
+		serverError(c, err, "Failed to fetch blocks")
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"data":  blocks,
+		"count": len(blocks),
+	})
+}
+
+// SetBlock handles PUT /api/v1/admin/blocks/:course_id/:name
+// Creates the course's block with that name, or replaces its activities; see
+// models.SetBlockRequest.
+func (h *BlockHandler) SetBlock(c *gin.Context) {
+	var req models.SetBlockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": models.ErrCodeBadRequest})
+		return
+	}
+	name := strings.TrimSpace(c.Param("name"))
+	if name == "" || len(name) > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Block name must be 1 to 100 characters", "code": models.ErrCodeBadRequest})
+		return
+	}
+
+	block, err := h.repo.Set(c.Request.Context(), c.Param("course_id"), name, req.ActivityIDs)
+	if errors.Is(err, repository.ErrForeignBlockActivity) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": models.ErrCodeBadRequest})
+		return
+	}
+	if err != nil {
+		serverError(c, err, "Failed to save block")
+		return
+	}
+	if block == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Course not found", "code": models.ErrCodeNotFound})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": block, "message": "Block saved"})
+}
+
+// DeleteBlock handles DELETE /api/v1/admin/blocks/:course_id/:name
+func (h *BlockHandler) DeleteBlock(c *gin.Context) {
+	deleted, err := h.repo.Delete(c.Request.Context(), c.Param("course_id"), c.Param("name"))
+	if err != nil {
+		serverError(c, err, "Failed to delete block")
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Block not found", "code": models.ErrCodeNotFound})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Block deleted"})
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"yuplan/internal/models"
+	"yuplan/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type MockBlockRepository struct {
+	getByCourseID func(ctx context.Context, courseID string) ([]models.Block, error)
+	set           func(ctx context.Context, courseID, name string, activityIDs []string) (*models.Block, error)
+	deleted       bool
+}
+
+func (m *MockBlockRepository) GetByCourseID(ctx context.Context, courseID string) ([]models.Block, error) {
+	if m.getByCourseID != nil {
+		return m.getByCourseID(ctx, courseID)
+	}
+	return []models.Block{}, nil
+}
+
+func (m *MockBlockRepository) Set(ctx context.Context, courseID, name string, activityIDs []string) (*models.Block, error) {
+	return m.set(ctx, courseID, name, activityIDs)
+}
+
+func (m *MockBlockRepository) Delete(ctx context.Context, courseID, name string) (bool, error) {
+	return m.deleted, nil
+}
+
+func TestGetBlocksByCourseID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewBlockHandler(&MockBlockRepository{
+		getByCourseID: func(ctx context.Context, courseID string) ([]models.Block, error) {
+			return []models.Block{
+				{ID: "block-1", CourseID: courseID, Name: "Block 1", Activities: []models.SectionActivity{{ID: "act-1"}}},
+			}, nil
+		},
+	})
+
+	r := gin.New()
+	r.GET("/blocks/:course_id", handler.GetBlocksByCourseID)
+
+	req, _ := http.NewRequest("GET", "/blocks/course-1", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"count":1`)
+	assert.Contains(t, w.Body.String(), `"course_id":"course-1"`)
+}
+
+func TestGetBlocksByCourseID_WhenRepoErrors_Returns500(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewBlockHandler(&MockBlockRepository{
+		getByCourseID: func(ctx context.Context, courseID string) ([]models.Block, error) {
+			return nil, errors.New("db down")
+		},
+	})
+
+	r := gin.New()
+	r.GET("/blocks/:course_id", handler.GetBlocksByCourseID)
+
+	req, _ := http.NewRequest("GET", "/blocks/course-1", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.True(t, strings.Contains(w.Body.String(), "Failed to fetch blocks"))
+}
+
+func TestSetBlock(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	const lect, lab = "00000000-0000-0000-0000-00000000b001", "00000000-0000-0000-0000-00000000b002"
+	var gotName string
+	var gotIDs []string
+	handler := NewBlockHandler(&MockBlockRepository{
+		set: func(ctx context.Context, courseID, name string, activityIDs []string) (*models.Block, error) {
+			switch courseID {
+			case "missing":
+				return nil, nil
+			case "foreign":
+				return nil, repository.ErrForeignBlockActivity
+			}
+			gotName, gotIDs = name, activityIDs
+			return &models.Block{ID: "block-1", CourseID: courseID, Name: name, Activities: []models.SectionActivity{{ID: lect}, {ID: lab}}}, nil
+		},
+	})
+
+	r := gin.New()
+	r.PUT("/admin/blocks/:course_id/:name", handler.SetBlock)
+
+	put := func(path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("PUT", path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := put("/admin/blocks/course-1/Block%20A", `{"activity_ids": ["`+lect+`", "`+lab+`"]}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "Block A", gotName)
+	assert.Equal(t, []string{lect, lab}, gotIDs)
+	assert.Contains(t, w.Body.String(), `"name":"Block A"`)
+
+	assert.Equal(t, http.StatusBadRequest, put("/admin/blocks/course-1/A", `{"activity_ids": []}`).Code)
+	assert.Equal(t, http.StatusBadRequest, put("/admin/blocks/course-1/A", `{"activity_ids": ["not-a-uuid"]}`).Code)
+	assert.Equal(t, http.StatusBadRequest, put("/admin/blocks/course-1/%20", `{"activity_ids": ["`+lect+`"]}`).Code)
+
+	w = put("/admin/blocks/foreign/A", `{"activity_ids": ["`+lect+`"]}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "course's sections")
+
+	assert.Equal(t, http.StatusNotFound, put("/admin/blocks/missing/A", `{"activity_ids": ["`+lect+`"]}`).Code)
+}
+
+func TestDeleteBlock(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := &MockBlockRepository{deleted: true}
+	r := gin.New()
+	r.DELETE("/admin/blocks/:course_id/:name", NewBlockHandler(repo).DeleteBlock)
+
+	req, _ := http.NewRequest("DELETE", "/admin/blocks/course-1/A", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	repo.deleted = false
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
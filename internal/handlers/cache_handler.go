@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"yuplan/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// cacheInvalidator drops cached values by namespace, or all of them when none are given.
+// Implemented by cache.Store.
+type cacheInvalidator interface {
+	Invalidate(ctx context.Context, namespaces ...string) error
+}
+
+type CacheHandler struct {
+	cache cacheInvalidator
+}
+
+func NewCacheHandler(cache cacheInvalidator) *CacheHandler {
+	return &CacheHandler{cache: cache}
+}
+
+// InvalidateCache handles POST /api/v1/admin/cache/invalidate?namespace=courses,sections
+// Without namespace every cached catalog read is dropped, e.g. after a seed.
+func (h *CacheHandler) InvalidateCache(c *gin.Context) {
+	namespaces := repository.CatalogCaches
+	if raw := c.Query("namespace"); raw != "" {
+		namespaces = nil
+		for _, ns := range strings.Split(raw, ",") {
+			ns = strings.TrimSpace(ns)
+			if !slices.Contains(repository.CatalogCaches, ns) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unknown cache namespace %q; expected one of %s", ns, strings.Join(repository.CatalogCaches, ", "))})
+				return
+			}
+			namespaces = append(namespaces, ns)
+		}
+	}
+
+	if err := h.cache.Invalidate(c.Request.Context(), namespaces...); err != nil {
+		serverError(c, err, "Failed to invalidate cache")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Cache invalidated",
+		"namespaces": namespaces,
+	})
+}
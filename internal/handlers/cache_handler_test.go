@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockCacheInvalidator struct {
+	namespaces []string
+	err        error
+}
+
+func (m *mockCacheInvalidator) Invalidate(ctx context.Context, namespaces ...string) error {
+	m.namespaces = namespaces
+	return m.err
+}
+
+func invalidateCache(cache *mockCacheInvalidator, query string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/admin/cache/invalidate", NewCacheHandler(cache).InvalidateCache)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/cache/invalidate"+query, nil))
+	return w
+}
+
+func TestInvalidateCache(t *testing.T) {
+	cache := &mockCacheInvalidator{}
+	w := invalidateCache(cache, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"courses", "sections", "instructors"}, cache.namespaces)
+
+	w = invalidateCache(cache, "?namespace=sections,%20instructors")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"sections", "instructors"}, cache.namespaces)
+}
+
+func TestInvalidateCache_Errors(t *testing.T) {
+	w := invalidateCache(&mockCacheInvalidator{}, "?namespace=reviews")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = invalidateCache(&mockCacheInvalidator{err: errors.New("redis down")}, "")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"yuplan/internal/config"
+	"yuplan/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type tunablesSource interface {
+	Current() config.Tunables
+}
+
+type tunablesReloader interface {
+	tunablesSource
+	Reload() (config.Tunables, error)
+	SetFlag(flag string, on bool) config.Tunables
+}
+
+type ConfigHandler struct {
+	reloader tunablesReloader
+}
+
+func NewConfigHandler(reloader tunablesReloader) *ConfigHandler {
+	return &ConfigHandler{reloader: reloader}
+}
+
+// GetConfig handles GET /api/v1/admin/config
+func (h *ConfigHandler) GetConfig(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": tunablesResponse(h.reloader.Current())})
+}
+
+// ReloadConfig handles POST /api/v1/admin/config/reload
+func (h *ConfigHandler) ReloadConfig(c *gin.Context) {
+	t, err := h.reloader.Reload()
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": "Configuration rejected, previous values kept: " + err.Error(),
+			"data":  tunablesResponse(t),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":    tunablesResponse(t),
+		"message": "Configuration reloaded",
+	})
+}
+
+// SetFeatureFlag handles PUT /api/v1/admin/config/flags/:flag with
+// {"enabled": true|false}. The change survives reloads but not a restart, and
+// only reaches the instance that served it; FEATURE_FLAGS is what lasts.
+func (h *ConfigHandler) SetFeatureFlag(c *gin.Context) {
+	flag := c.Param("flag")
+	if !slices.Contains(config.FeatureFlags, flag) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Unknown feature flag; known flags are " + strings.Join(config.FeatureFlags, ", "),
+			"code":  models.ErrCodeNotFound,
+		})
+		return
+	}
+	var req models.SetFeatureFlagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": models.ErrCodeBadRequest})
+		return
+	}
+
+	t := h.reloader.SetFlag(flag, *req.Enabled)
+	state := "disabled"
+	if *req.Enabled {
+		state = "enabled"
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":    tunablesResponse(t),
+		"message": "Feature flag " + flag + " " + state,
+	})
+}
+
+func tunablesResponse(t config.Tunables) gin.H {
+	flags := []string{}
+	for flag, on := range t.FeatureFlags {
+		if on {
+			flags = append(flags, flag)
+		}
+	}
+	sort.Strings(flags)
+
+	return gin.H{
+		"rate_limit":              t.RateLimit,
+		"rate_limit_window":       t.RateLimitWindow.String(),
+		"lite_rate_limit":         t.LiteRateLimit,
+		"lite_rate_limit_window":  t.LiteRateLimitWindow.String(),
+		"load_shed_max_in_flight": t.LoadShedMaxInFlight,
+		"load_shed_target_p99":    t.LoadShedTargetP99.String(),
+		"maintenance_mode":        t.MaintenanceMode,
+		"log_level":               t.LogLevel,
+		"feature_flags":           flags,
+		"min_client_versions":     t.MinClientVersions,
+
+		"review_report_hide_threshold": t.ReviewReportHideThreshold,
+	}
+}
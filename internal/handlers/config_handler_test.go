@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"yuplan/internal/config"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockReloader struct {
+	current config.Tunables
+	next    config.Tunables
+	err     error
+}
+
+func (m *mockReloader) Current() config.Tunables {
+	return m.current
+}
+
+func (m *mockReloader) SetFlag(flag string, on bool) config.Tunables {
+	flags := map[string]bool{}
+	for f, v := range m.current.FeatureFlags {
+		flags[f] = v
+	}
+	flags[flag] = on
+	m.current.FeatureFlags = flags
+	return m.current
+}
+
+func (m *mockReloader) Reload() (config.Tunables, error) {
+	if m.err != nil {
+		return m.current, m.err
+	}
+	m.current = m.next
+	return m.current, nil
+}
+
+func setupConfigRouter(handler *ConfigHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin/config", handler.GetConfig)
+	router.POST("/admin/config/reload", handler.ReloadConfig)
+	router.PUT("/admin/config/flags/:flag", handler.SetFeatureFlag)
+	return router
+}
+
+func TestGetConfig(t *testing.T) {
+	current := config.DefaultTunables()
+	current.FeatureFlags = map[string]bool{"b": true, "a": true, "off": false}
+	router := setupConfigRouter(NewConfigHandler(&mockReloader{current: current}))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/config", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var body struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, float64(100), body.Data["rate_limit"])
+	assert.Equal(t, "1m0s", body.Data["rate_limit_window"])
+	assert.Equal(t, "info", body.Data["log_level"])
+	assert.Equal(t, []interface{}{"a", "b"}, body.Data["feature_flags"])
+}
+
+func TestReloadConfig(t *testing.T) {
+	next := config.DefaultTunables()
+	next.RateLimit = 10
+	next.RateLimitWindow = 30 * time.Second
+
+	tests := []struct {
+		name              string
+		reloader          *mockReloader
+		expectedStatus    int
+		expectedRateLimit float64
+	}{
+		{
+			name:              "success",
+			reloader:          &mockReloader{current: config.DefaultTunables(), next: next},
+			expectedStatus:    http.StatusOK,
+			expectedRateLimit: 10,
+		},
+		{
+			name:              "invalid config keeps current values",
+			reloader:          &mockReloader{current: config.DefaultTunables(), err: errors.New("RATE_LIMIT must be at least 1, got 0")},
+			expectedStatus:    http.StatusUnprocessableEntity,
+			expectedRateLimit: 100,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := setupConfigRouter(NewConfigHandler(tt.reloader))
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/config/reload", nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			var body struct {
+				Data  map[string]interface{} `json:"data"`
+				Error string                 `json:"error"`
+			}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, tt.expectedRateLimit, body.Data["rate_limit"])
+			if tt.reloader.err != nil {
+				assert.Contains(t, body.Error, "RATE_LIMIT")
+			}
+		})
+	}
+}
+
+func TestSetFeatureFlag(t *testing.T) {
+	current := config.DefaultTunables()
+	current.FeatureFlags = map[string]bool{config.FlagReviewEmbargo: true}
+	reloader := &mockReloader{current: current}
+	router := setupConfigRouter(NewConfigHandler(reloader))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/admin/config/flags/captcha_reviews", strings.NewReader(`{"enabled": true}`)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var body struct {
+		Data    map[string]interface{} `json:"data"`
+		Message string                 `json:"message"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, []interface{}{"captcha_reviews", "review_embargo"}, body.Data["feature_flags"])
+	assert.Equal(t, "Feature flag captcha_reviews enabled", body.Message)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/admin/config/flags/review_embargo", strings.NewReader(`{"enabled": false}`)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.False(t, reloader.current.Enabled(config.FlagReviewEmbargo))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/admin/config/flags/review_embargo", strings.NewReader(`{}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code, "enabled is required")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/admin/config/flags/teleport", strings.NewReader(`{"enabled": true}`)))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+	"yuplan/internal/logging"
+	"yuplan/internal/models"
+	"yuplan/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// courseEditor applies admin edits to courses. Implemented by repository.CourseRepository.
+type courseEditor interface {
+	Create(ctx context.Context, req models.CreateCourseRequest) (*models.Course, error)
+	Patch(ctx context.Context, courseID string, patch models.CoursePatch, updatedAt *time.Time) (*models.Course, bool, error)
+	Delete(ctx context.Context, courseID string) (bool, error)
+	Merge(ctx context.Context, sourceID, targetID string) (*models.CourseMerge, error)
+}
+
+type CourseEditHandler struct {
+	repo  courseEditor
+	cache cacheInvalidator
+}
+
+func NewCourseEditHandler(repo courseEditor, cache cacheInvalidator) *CourseEditHandler {
+	return &CourseEditHandler{repo: repo, cache: cache}
+}
+
+// CreateCourse handles POST /api/v1/admin/courses, adding a course the
+// scraper hasn't picked up. It is 409 if the code already runs in the term.
+func (h *CourseEditHandler) CreateCourse(c *gin.Context) {
+	var req models.CreateCourseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": models.ErrCodeBadRequest})
+		return
+	}
+	if err := req.Normalize(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": models.ErrCodeBadRequest})
+		return
+	}
+
+	ctx := c.Request.Context()
+	course, err := h.repo.Create(ctx, req)
+	if err != nil {
+		serverError(c, err, "Failed to create course")
+		return
+	}
+	if course == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "That course already runs in that term", "code": models.ErrCodeConflict})
+		return
+	}
+
+	h.invalidate(ctx)
+	c.Header("ETag", courseETag(course))
+	respond(c, http.StatusCreated, gin.H{"data": course})
+}
+
+// PatchCourse handles PATCH /api/v1/admin/courses/:id with a JSON merge patch
+// of the course's name, credits, description or faculty. Sent with If-Match
+// set to the course's ETag, its updated_at in quotes, the edit only applies
+// if nobody has changed the course since; otherwise it is 412 with the course
+// as it now is. Each changed field is written to the audit log.
+func (h *CourseEditHandler) PatchCourse(c *gin.Context) {
+	updatedAt, ok := ifMatchUpdatedAt(c)
+	if !ok {
+		return
+	}
+	var patch models.CoursePatch
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": models.ErrCodeBadRequest})
+		return
+	}
+	if patch.Empty() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Patch sets no fields", "code": models.ErrCodeBadRequest})
+		return
+	}
+
+	ctx := c.Request.Context()
+	course, applied, err := h.repo.Patch(ctx, c.Param("id"), patch, updatedAt)
+	if err != nil {
+		serverError(c, err, "Failed to update course")
+		return
+	}
+	if course == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Course not found", "code": models.ErrCodeNotFound})
+		return
+	}
+	c.Header("ETag", courseETag(course))
+	if !applied {
+		respond(c, http.StatusPreconditionFailed, gin.H{
+			"error": "Course has changed since the version in If-Match",
+			"code":  models.ErrCodePreconditionFailed,
+			"data":  course,
+		})
+		return
+	}
+
+	h.invalidate(ctx)
+	respond(c, http.StatusOK, gin.H{"data": course})
+}
+
+// DeleteCourse handles DELETE /api/v1/admin/courses/:id, removing the course
+// with its sections. A course the timetable still lists comes back on the
+// next scrape of its term.
+func (h *CourseEditHandler) DeleteCourse(c *gin.Context) {
+	ctx := c.Request.Context()
+	deleted, err := h.repo.Delete(ctx, c.Param("id"))
+	if err != nil {
+		serverError(c, err, "Failed to delete course")
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Course not found", "code": models.ErrCodeNotFound})
+		return
+	}
+
+	h.invalidate(ctx)
+	c.JSON(http.StatusOK, gin.H{"message": "Course deleted"})
+}
+
+// MergeCourse handles POST /api/v1/admin/courses/:id/merge with {"into": id},
+// folding a duplicate course into another and deleting it; see
+// repository.CourseRepository.Merge for what moves.
+func (h *CourseEditHandler) MergeCourse(c *gin.Context) {
+	var req models.MergeCourseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": models.ErrCodeBadRequest})
+		return
+	}
+	if strings.EqualFold(req.Into, c.Param("id")) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A course can't be merged into itself", "code": models.ErrCodeBadRequest})
+		return
+	}
+
+	ctx := c.Request.Context()
+	merge, err := h.repo.Merge(ctx, c.Param("id"), req.Into)
+	if err != nil {
+		serverError(c, err, "Failed to merge course")
+		return
+	}
+	if merge == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Course not found", "code": models.ErrCodeNotFound})
+		return
+	}
+
+	// Sections and instructors are cached under the course they now belong to
+	if err := h.cache.Invalidate(ctx, append(slices.Clone(repository.CatalogCaches), repository.CacheDepartments)...); err != nil {
+		logging.FromContext(ctx).Error("Failed to invalidate course cache", "error", err)
+	}
+	respond(c, http.StatusOK, gin.H{"data": merge, "message": "Course merged"})
+}
+
+// invalidate drops cached course reads after a write. A failure only means
+// stale reads until the entries expire, so it is logged, not returned.
+func (h *CourseEditHandler) invalidate(ctx context.Context) {
+	if err := h.cache.Invalidate(ctx, repository.CacheCourses); err != nil {
+		logging.FromContext(ctx).Error("Failed to invalidate course cache", "error", err)
+	}
+}
+
+// courseETag identifies a version of a course by its updated_at, formatted
+// as the course's JSON formats it.
+func courseETag(course *models.Course) string {
+	return `"` + course.UpdatedAt.UTC().Format(time.RFC3339Nano) + `"`
+}
+
+// ifMatchUpdatedAt reads the updated_at an If-Match header names, nil when
+// it's absent or "*". ok is false once a 400 has been written for a header
+// that isn't a course ETag.
+func ifMatchUpdatedAt(c *gin.Context) (updatedAt *time.Time, ok bool) {
+	raw := strings.TrimSpace(c.GetHeader("If-Match"))
+	if raw == "" || raw == "*" {
+		return nil, true
+	}
+	t, err := time.Parse(time.RFC3339Nano, strings.Trim(raw, `"`))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": `If-Match must be the course's ETag, its updated_at in quotes`,
+			"code":  models.ErrCodeBadRequest,
+		})
+		return nil, false
+	}
+	t = t.UTC()
+	return &t, true
+}
@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"yuplan/internal/models"
+	"yuplan/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockCourseEditor struct {
+	course    *models.Course
+	stale     bool
+	patch     models.CoursePatch
+	updatedAt *time.Time
+	created   models.CreateCourseRequest
+	calls     int
+}
+
+func (m *mockCourseEditor) Create(ctx context.Context, req models.CreateCourseRequest) (*models.Course, error) {
+	m.calls++
+	m.created = req
+	if m.course != nil {
+		return nil, nil
+	}
+	return &models.Course{ID: "c-2", Code: req.Code, Term: req.Term, Name: req.Name}, nil
+}
+
+func (m *mockCourseEditor) Delete(ctx context.Context, courseID string) (bool, error) {
+	m.calls++
+	return m.course != nil && m.course.ID == courseID, nil
+}
+
+func (m *mockCourseEditor) Merge(ctx context.Context, sourceID, targetID string) (*models.CourseMerge, error) {
+	m.calls++
+	if m.course == nil || m.course.ID != sourceID {
+		return nil, nil
+	}
+	return &models.CourseMerge{Course: models.Course{ID: targetID}, Sections: 2, Reviews: 5}, nil
+}
+
+func (m *mockCourseEditor) Patch(ctx context.Context, courseID string, patch models.CoursePatch, updatedAt *time.Time) (*models.Course, bool, error) {
+	m.calls++
+	m.patch = patch
+	m.updatedAt = updatedAt
+	if m.course == nil || m.stale {
+		return m.course, false, nil
+	}
+	if patch.Name != nil {
+		m.course.Name = *patch.Name
+	}
+	m.course.UpdatedAt = m.course.UpdatedAt.Add(time.Second)
+	return m.course, true, nil
+}
+
+func patchCourse(repo *mockCourseEditor, cache *mockCacheInvalidator, body, ifMatch string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.PATCH("/admin/courses/:id", NewCourseEditHandler(repo, cache).PatchCourse)
+	req := httptest.NewRequest(http.MethodPatch, "/admin/courses/c-1", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	if ifMatch != "" {
+		req.Header.Set("If-Match", ifMatch)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestPatchCourse(t *testing.T) {
+	updated := time.Date(2026, 10, 1, 12, 0, 0, 123456000, time.UTC)
+	repo := &mockCourseEditor{course: &models.Course{ID: "c-1", Code: "EECS2030", Name: "OOP", UpdatedAt: updated}}
+	cache := &mockCacheInvalidator{}
+
+	w := patchCourse(repo, cache, `{"name": "Advanced OOP", "description": null}`, `"2026-10-01T12:00:00.123456Z"`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, updated, *repo.updatedAt)
+	assert.Equal(t, "Advanced OOP", *repo.patch.Name)
+	assert.False(t, repo.patch.Description.Valid)
+	assert.Nil(t, repo.patch.Credits)
+	assert.Equal(t, `"2026-10-01T12:00:01.123456Z"`, w.Header().Get("ETag"))
+	assert.Equal(t, []string{repository.CacheCourses}, cache.namespaces)
+
+	var body struct {
+		Data models.Course `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "Advanced OOP", body.Data.Name)
+
+	// Without If-Match the edit applies to whatever version is current
+	w = patchCourse(repo, cache, `{"credits": 4}`, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Nil(t, repo.updatedAt)
+}
+
+func TestPatchCourse_Stale(t *testing.T) {
+	updated := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	repo := &mockCourseEditor{course: &models.Course{ID: "c-1", Name: "OOP", UpdatedAt: updated}, stale: true}
+	cache := &mockCacheInvalidator{}
+
+	w := patchCourse(repo, cache, `{"name": "Advanced OOP"}`, `"2026-09-01T00:00:00Z"`)
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	assert.Equal(t, `"2026-10-01T12:00:00Z"`, w.Header().Get("ETag"))
+	assert.Contains(t, w.Body.String(), models.ErrCodePreconditionFailed)
+	assert.Contains(t, w.Body.String(), `"name":"OOP"`)
+	assert.Nil(t, cache.namespaces, "nothing changed, so nothing is dropped from the cache")
+}
+
+func TestPatchCourse_Invalid(t *testing.T) {
+	repo := &mockCourseEditor{course: &models.Course{ID: "c-1"}}
+	for _, tt := range []struct {
+		body, ifMatch string
+	}{
+		{`{"code": "EECS9999"}`, ""},
+		{`{"name": null}`, ""},
+		{`{"credits": "three"}`, ""},
+		{`{}`, ""},
+		{`[]`, ""},
+		{`{"name": "OOP"}`, `"yesterday"`},
+	} {
+		w := patchCourse(repo, &mockCacheInvalidator{}, tt.body, tt.ifMatch)
+		assert.Equal(t, http.StatusBadRequest, w.Code, tt.body)
+	}
+	assert.Equal(t, 0, repo.calls)
+
+	w := patchCourse(&mockCourseEditor{}, &mockCacheInvalidator{}, `{"name": "OOP"}`, "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func sendCourse(repo *mockCourseEditor, cache *mockCacheInvalidator, method, path, body string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	h := NewCourseEditHandler(repo, cache)
+	router.POST("/admin/courses", h.CreateCourse)
+	router.DELETE("/admin/courses/:id", h.DeleteCourse)
+	router.POST("/admin/courses/:id/merge", h.MergeCourse)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+	return w
+}
+
+func TestCreateCourse(t *testing.T) {
+	repo := &mockCourseEditor{}
+	cache := &mockCacheInvalidator{}
+
+	w := sendCourse(repo, cache, http.MethodPost, "/admin/courses",
+		`{"code": "eecs 4088", "term": "F", "name": "Special Topics", "credits": 3, "faculty": "le"}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "EECS4088", repo.created.Code)
+	assert.Equal(t, "LE", repo.created.Faculty)
+	assert.NotEmpty(t, w.Header().Get("ETag"))
+	assert.Equal(t, []string{repository.CacheCourses}, cache.namespaces)
+	assert.Contains(t, w.Body.String(), `"id":"c-2"`)
+
+	// Already in the term
+	repo = &mockCourseEditor{course: &models.Course{ID: "c-1"}}
+	w = sendCourse(repo, &mockCacheInvalidator{}, http.MethodPost, "/admin/courses",
+		`{"code": "EECS4088", "term": "F", "name": "Special Topics", "credits": 3, "faculty": "LE"}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), models.ErrCodeConflict)
+}
+
+func TestCreateCourse_Invalid(t *testing.T) {
+	repo := &mockCourseEditor{}
+	for _, body := range []string{
+		`{"code": "EECS4088", "term": "F", "name": "Topics", "credits": 3}`,
+		`{"code": "EECS4088", "term": "Q", "name": "Topics", "credits": 3, "faculty": "LE"}`,
+		`{"code": "EECS4088", "term": "F", "name": "Topics", "credits": 0, "faculty": "LE"}`,
+		`{"code": "4088", "term": "F", "name": "Topics", "credits": 3, "faculty": "LE"}`,
+	} {
+		w := sendCourse(repo, &mockCacheInvalidator{}, http.MethodPost, "/admin/courses", body)
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+	assert.Equal(t, 0, repo.calls)
+}
+
+func TestDeleteCourse(t *testing.T) {
+	repo := &mockCourseEditor{course: &models.Course{ID: "c-1"}}
+	cache := &mockCacheInvalidator{}
+
+	w := sendCourse(repo, cache, http.MethodDelete, "/admin/courses/c-1", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{repository.CacheCourses}, cache.namespaces)
+
+	cache = &mockCacheInvalidator{}
+	w = sendCourse(repo, cache, http.MethodDelete, "/admin/courses/missing", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Nil(t, cache.namespaces)
+}
+
+func TestMergeCourse(t *testing.T) {
+	source, target := "6f1c7f3e-8a53-4c43-9d0e-5b0e6a0f9a01", "6f1c7f3e-8a53-4c43-9d0e-5b0e6a0f9a02"
+	repo := &mockCourseEditor{course: &models.Course{ID: source}}
+	cache := &mockCacheInvalidator{}
+
+	w := sendCourse(repo, cache, http.MethodPost, "/admin/courses/"+source+"/merge", `{"into": "`+target+`"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var body struct {
+		Data models.CourseMerge `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, target, body.Data.Course.ID)
+	assert.Equal(t, 2, body.Data.Sections)
+	assert.Equal(t, 5, body.Data.Reviews)
+	assert.Equal(t, []string{repository.CacheCourses, repository.CacheSections, repository.CacheInstructors, repository.CacheDepartments}, cache.namespaces)
+
+	cache = &mockCacheInvalidator{}
+	w = sendCourse(repo, cache, http.MethodPost, "/admin/courses/"+target+"/merge", `{"into": "`+source+`"}`)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Nil(t, cache.namespaces)
+
+	calls := repo.calls
+	for _, body := range []string{`{}`, `{"into": "EECS2030"}`, `{"into": "` + source + `"}`} {
+		w = sendCourse(repo, cache, http.MethodPost, "/admin/courses/"+source+"/merge", body)
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+	assert.Equal(t, calls, repo.calls)
+}
@@ -1,39 +1,225 @@
 package handlers
 
 import (
+	"context"
+	"fmt"
+	"log"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
+	"yuplan/internal/config"
+	"yuplan/internal/id"
 	"yuplan/internal/models"
 	"yuplan/internal/repository"
+	"yuplan/internal/search"
+	"yuplan/internal/session"
 
 	"github.com/gin-gonic/gin"
 )
 
+// searchRecorder receives every search query and its result count for analytics.
+type searchRecorder interface {
+	Record(query string, results int)
+}
+
+// searchMetrics counts searches by the path that answered and whether they found anything. Implemented by metrics.Business.
+type searchMetrics interface {
+	Searched(path string, results int)
+}
+
+// courseSearch answers searches, exact course codes first. Implemented by search.Service.
+type courseSearch interface {
+	Courses(ctx context.Context, query, termID string, limit, offset int) ([]models.Course, string, error)
+}
+
+// offeringHistory supplies the "last offered" summary shown on course detail.
+type offeringHistory interface {
+	GetSummary(ctx context.Context, courseCode string) (*models.OfferingSummary, error)
+}
+
+// courseDetails loads a whole course page at once. Implemented by repository.CourseDetailRepository.
+type courseDetails interface {
+	GetFull(ctx context.Context, courseID string, since time.Time) (*models.CourseDetail, error)
+}
+
+// filterPresets looks up saved browsing filters for ?preset=. Implemented by repository.FilterPresetRepository.
+type filterPresets interface {
+	Get(ctx context.Context, id string) (*models.FilterPreset, error)
+}
+
+// courseFeed picks the random courses on the landing page, personalized by email.
+type courseFeed interface {
+	Courses(ctx context.Context, email string, limit int) ([]models.Course, error)
+	RecordSeen(ctx context.Context, email string, courseCodes []string) error
+}
+
+// courseShuffle pages through every course in an order fixed by a seed.
+// Implemented by repository.CourseRepository.
+type courseShuffle interface {
+	GetShuffledCourses(ctx context.Context, seed string, limit, offset int) ([]models.Course, error)
+}
+
 type CourseHandler struct {
 	repo        repository.CourseRepositoryInterface
 	sectionRepo repository.SectionRepositoryInterface
+	searches    searchRecorder
+	metrics     searchMetrics
+	offerings   offeringHistory
+	feed        courseFeed
+	instructors courseInstructors
+	stats       courseSummaries
+	statsWindow time.Duration
+	details     courseDetails
+	search      courseSearch
+	presets     filterPresets
+	shuffle     courseShuffle
+	deadlines   termDeadlines
 }
 
 func NewCourseHandler(repo repository.CourseRepositoryInterface, sectionRepo repository.SectionRepositoryInterface) *CourseHandler {
-	return &CourseHandler{repo: repo, sectionRepo: sectionRepo}
+	return &CourseHandler{repo: repo, sectionRepo: sectionRepo, statsWindow: defaultStatsWindow}
+}
+
+// WithSearchRecorder records search queries for analytics. Without it searches are not recorded.
+func (h *CourseHandler) WithSearchRecorder(recorder searchRecorder) *CourseHandler {
+	h.searches = recorder
+	return h
+}
+
+// WithMetrics counts first-page searches and how many came back empty.
+func (h *CourseHandler) WithMetrics(metrics searchMetrics) *CourseHandler {
+	h.metrics = metrics
+	return h
+}
+
+// WithInstructors offers ?include=instructors on course responses.
+func (h *CourseHandler) WithInstructors(instructors courseInstructors) *CourseHandler {
+	h.instructors = instructors
+	return h
+}
+
+// WithCourseStats offers ?include=stats on course responses, counting reviews within window.
+func (h *CourseHandler) WithCourseStats(stats courseSummaries, window time.Duration) *CourseHandler {
+	h.stats = stats
+	h.statsWindow = window
+	return h
+}
+
+// WithOfferingHistory adds an "offering" summary to course detail responses.
+func (h *CourseHandler) WithOfferingHistory(offerings offeringHistory) *CourseHandler {
+	h.offerings = offerings
+	return h
+}
+
+// WithFeed serves GET /courses from feed, which leaves out courses the
+// ?email= reviewer has reviewed or seen. Without it every caller gets the plain shuffle.
+func (h *CourseHandler) WithFeed(feed courseFeed) *CourseHandler {
+	h.feed = feed
+	return h
+}
+
+// WithDetails serves GET /courses/:course_id/full from details.
+func (h *CourseHandler) WithDetails(details courseDetails) *CourseHandler {
+	h.details = details
+	return h
+}
+
+// WithSearch answers /courses/search through search, which looks up
+// code-shaped queries exactly before falling back to the fuzzy search.
+// Without it every query goes to the fuzzy search.
+func (h *CourseHandler) WithSearch(search courseSearch) *CourseHandler {
+	h.search = search
+	return h
+}
+
+// WithPresets runs saved filter presets for GET /courses?preset=. Without it
+// the parameter is ignored.
+func (h *CourseHandler) WithPresets(presets filterPresets) *CourseHandler {
+	h.presets = presets
+	return h
+}
+
+// WithSessionShuffle gives callers with an anonymous session, and no ?email=,
+// their own stable shuffle to page through with ?offset=. Without it they get
+// a fresh shuffle on every call.
+func (h *CourseHandler) WithSessionShuffle(shuffle courseShuffle) *CourseHandler {
+	h.shuffle = shuffle
+	return h
+}
+
+// WithDeadlines adds each course's upcoming enroll and drop deadlines to
+// course responses as "deadlines", so pages can count down to them without
+// another request. Without it courses carry no deadlines.
+func (h *CourseHandler) WithDeadlines(deadlines termDeadlines) *CourseHandler {
+	h.deadlines = deadlines
+	return h
 }
 
 func (h *CourseHandler) GetCourses(c *gin.Context) {
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if c.Query("ids") != "" {
+		h.getCoursesByIDs(c)
+		return
+	}
+	if presetID := c.Query("preset"); presetID != "" && h.presets != nil {
+		h.getPresetCourses(c, presetID)
+		return
+	}
+	limit, ok := pageLimit(c, config.PagesCourses, "limit")
+	if !ok {
+		return
+	}
+	email := strings.TrimSpace(c.Query("email"))
+	sess, hasSession := session.FromContext(c.Request.Context())
 
-	courses, err := h.repo.GetRandomCourses(c.Request.Context(), limit)
+	var courses []models.Course
+	var err error
+	if h.shuffle != nil && hasSession && email == "" {
+		offset, ok := pageOffset(c)
+		if !ok {
+			return
+		}
+		courses, err = h.shuffle.GetShuffledCourses(c.Request.Context(), sess.ID, limit, offset)
+	} else if h.feed != nil {
+		courses, err = h.feed.Courses(c.Request.Context(), email, limit)
+	} else {
+		courses, err = h.repo.GetRandomCourses(c.Request.Context(), limit)
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch courses"})
+		serverError(c, err, "Failed to fetch courses")
+		return
+	}
+	expanded, ok := h.includeCourses(c, courses)
+	if !ok {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data":  courses,
-		"count": len(courses),
+	respond(c, http.StatusOK, gin.H{
+		"data":  expanded,
+		"count": len(expanded),
 	})
 }
 
+// RecordSeen handles POST /api/v1/courses/seen
+// Body: {"email": "student@my.yorku.ca", "course_codes": ["EECS2030"]}
+// Keeps the courses out of that reviewer's GET /courses feed for a while.
+func (h *CourseHandler) RecordSeen(c *gin.Context) {
+	if h.feed == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Course feed not configured"})
+		return
+	}
+	var req models.RecordSeenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.feed.RecordSeen(c.Request.Context(), strings.TrimSpace(req.Email), req.CourseCodes); err != nil {
+		serverError(c, err, "Failed to record seen courses")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Courses marked as seen"})
+}
+
 func (h *CourseHandler) GetCourseByID(c *gin.Context) {
 	// Deprecated: kept for backwards compatibility with older tests/routes.
 	// This endpoint now always treats the identifier as a course code.
@@ -41,12 +227,46 @@ func (h *CourseHandler) GetCourseByID(c *gin.Context) {
 	h.getCoursesByCode(c, identifier)
 }
 
-type CourseOffering struct {
-	models.Course
-	Sections []models.Section `json:"sections"`
+// GetCourseFull handles GET /api/v1/courses/:course_id/full, one course
+// offering with its sections, instructors, labs, tutorials and review stats,
+// so the course page needs one round trip instead of five. The route shares
+// its wildcard with /courses/:course_code, so gin names the id course_code
+// and UUIDParams doesn't check it.
+func (h *CourseHandler) GetCourseFull(c *gin.Context) {
+	if h.details == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Course details not configured"})
+		return
+	}
+
+	courseID := c.Param("course_code")
+	if !id.Valid(courseID) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "course_id must be a UUID", "code": models.ErrCodeInvalidID})
+		return
+	}
+
+	detail, err := h.details.GetFull(c.Request.Context(), courseID, time.Now().UTC().Add(-h.statsWindow))
+	if err != nil {
+		serverError(c, err, "Failed to fetch course")
+		return
+	}
+	if detail == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+		return
+	}
+	if h.deadlines != nil {
+		byTerm, err := h.deadlinesByTerm(c.Request.Context(), []models.Course{detail.Course})
+		if err != nil {
+			serverError(c, err, "Failed to fetch term deadlines")
+			return
+		}
+		detail.Deadlines = byTerm[detail.Term]
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": detail})
 }
 
-// GetCoursesByCode returns all term-offerings for a course code, each hydrated with sections + activities.
+// GetCoursesByCode returns all term-offerings for a course code, each hydrated
+// with sections + activities and anything else asked for with ?include=.
 func (h *CourseHandler) GetCoursesByCode(c *gin.Context) {
 	rawCode := c.Param("course_code")
 	h.getCoursesByCode(c, rawCode)
@@ -65,7 +285,7 @@ func (h *CourseHandler) getCoursesByCode(c *gin.Context, rawCode string) {
 
 	courses, err := h.repo.GetByCode(c.Request.Context(), rawCode)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch courses"})
+		serverError(c, err, "Failed to fetch courses")
 		return
 	}
 	if len(courses) == 0 {
@@ -73,24 +293,25 @@ func (h *CourseHandler) getCoursesByCode(c *gin.Context, rawCode string) {
 		return
 	}
 
-	resp := make([]CourseOffering, 0, len(courses))
-	for _, course := range courses {
-		sections, err := h.sectionRepo.GetByCourseID(c.Request.Context(), course.ID)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch sections"})
-			return
-		}
-
-		resp = append(resp, CourseOffering{
-			Course:   course,
-			Sections: sections,
-		})
+	resp, ok := h.includeCourses(c, courses, "sections")
+	if !ok {
+		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	body := gin.H{
 		"data":  resp,
 		"count": len(resp),
-	})
+	}
+	if h.offerings != nil {
+		// Offering history is supplementary; a failure here shouldn't hide the course.
+		summary, err := h.offerings.GetSummary(c.Request.Context(), rawCode)
+		if err != nil {
+			log.Printf("offering summary for %s: %v", rawCode, err)
+		}
+		body["offering"] = summary
+	}
+
+	respond(c, http.StatusOK, body)
 }
 
 func (h *CourseHandler) SearchCourses(c *gin.Context) {
@@ -100,35 +321,68 @@ func (h *CourseHandler) SearchCourses(c *gin.Context) {
 		return
 	}
 
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	termID, ok := termFilter(c)
+	if !ok {
+		return
+	}
+	limit, ok := pageLimit(c, config.PagesSearch, "limit")
+	if !ok {
+		return
+	}
+	offset, ok := pageOffset(c)
+	if !ok {
+		return
+	}
 
-	courses, err := h.repo.Search(c.Request.Context(), query, limit, offset)
+	var courses []models.Course
+	var err error
+	path := search.PathFuzzy
+	if h.search != nil {
+		courses, path, err = h.search.Courses(c.Request.Context(), query, termID, limit, offset)
+	} else {
+		courses, err = h.repo.Search(c.Request.Context(), query, termID, limit, offset)
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search courses"})
+		serverError(c, err, "Failed to search courses")
 		return
 	}
+	expanded, ok := h.includeCourses(c, courses)
+	if !ok {
+		return
+	}
+
+	// Only the first page counts as a search; paging through results is not a new query
+	if offset == 0 {
+		if h.searches != nil {
+			h.searches.Record(query, len(courses))
+		}
+		if h.metrics != nil {
+			h.metrics.Searched(path, len(courses))
+		}
+	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data":  courses,
-		"count": len(courses),
+	respond(c, http.StatusOK, gin.H{
+		"data":  expanded,
+		"count": len(expanded),
 	})
 }
 
+// GetAllCourses handles GET /api/v1/courses/all
+// It streams the whole catalog for clients that keep an offline copy.
+func (h *CourseHandler) GetAllCourses(c *gin.Context) {
+	out := newJSONArrayWriter(c)
+	err := h.repo.StreamAll(c.Request.Context(), func(course models.Course) error {
+		return out.Write(course)
+	})
+	if err != nil {
+		out.Fail(err, "Failed to fetch courses")
+		return
+	}
+	out.Close()
+}
+
 // GetPaginatedCourses handles paginated course requests with optional filtering
 func (h *CourseHandler) GetPaginatedCourses(c *gin.Context) {
-	// Parse pagination parameters
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
-	
-	// Validate pagination parameters
-	if page < 1 {
-		page = 1
-	}
-	if pageSize < 1 || pageSize > 100 {
-		pageSize = 20
-	}
-	
 	// Parse optional filter parameters
 	var faculty *string
 	if f := c.Query("faculty"); f != "" {
@@ -139,11 +393,121 @@ func (h *CourseHandler) GetPaginatedCourses(c *gin.Context) {
 	if ccr := c.Query("course_code_range"); ccr != "" {
 		courseCodeRange = &ccr
 	}
+
+	term, ok := termFilter(c)
+	if !ok {
+		return
+	}
+	var termID *string
+	if term != "" {
+		termID = &term
+	}
+	h.paginateCourses(c, faculty, courseCodeRange, termID)
+}
+
+// getPresetCourses answers GET /courses?preset= like GET /courses/paginated
+// with the preset's filters.
+// getCoursesByIDs answers GET /api/v1/courses?ids= with those courses, in
+// the order asked for, so a saved plan loads in one request.
+func (h *CourseHandler) getCoursesByIDs(c *gin.Context) {
+	ids, err := queryIDs(c, "ids")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": models.ErrCodeInvalidID})
+		return
+	}
+	if len(ids) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter 'ids' must list at least one id"})
+		return
+	}
+	if len(ids) > models.MaxCourseIDs {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("At most %d ids per request", models.MaxCourseIDs)})
+		return
+	}
+
+	courses, err := h.repo.GetByIDs(c.Request.Context(), ids)
+	if err != nil {
+		serverError(c, err, "Failed to fetch courses")
+		return
+	}
+	expanded, ok := h.includeCourses(c, courses)
+	if !ok {
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"data":  expanded,
+		"count": len(expanded),
+	})
+}
+
+func (h *CourseHandler) getPresetCourses(c *gin.Context, presetID string) {
+	if !id.Valid(presetID) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "preset must be a UUID", "code": models.ErrCodeInvalidID})
+		return
+	}
+	preset, err := h.presets.Get(c.Request.Context(), presetID)
+	if err != nil {
+		serverError(c, err, "Failed to fetch filter preset")
+		return
+	}
+	if preset == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Filter preset not found"})
+		return
+	}
+
+	var faculty, courseCodeRange, termID *string
+	if preset.Faculty != "" {
+		faculty = &preset.Faculty
+	}
+	if preset.CourseCodeRange != "" {
+		courseCodeRange = &preset.CourseCodeRange
+	}
+	if preset.TermID != "" {
+		termID = &preset.TermID
+	}
+	h.paginateCourses(c, faculty, courseCodeRange, termID)
+}
+
+// paginateCourses answers with the ?page= of courses matching the filters,
+// in ?sort= order; see models.ParseCourseSorts. With ?cursor= it answers with
+// keyset pages instead; see cursorCourses.
+func (h *CourseHandler) paginateCourses(c *gin.Context, faculty, courseCodeRange, termID *string) {
+	sorts, err := models.ParseCourseSorts(c.QueryArray("sort"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	after, cursorMode, ok := pageCursor[models.CourseCursor](c, "page")
+	if !ok {
+		return
+	}
+	if cursorMode {
+		if len(sorts) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter 'sort' can't be combined with 'cursor'; cursor pages are in course code order", "code": models.ErrCodeBadRequest})
+			return
+		}
+		if after != nil && !id.Valid(after.ID) {
+			badCursor(c)
+			return
+		}
+		h.cursorCourses(c, after, faculty, courseCodeRange, termID)
+		return
+	}
+
+	// Parse pagination parameters
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+	pageSize, ok := pageLimit(c, config.PagesCatalog, "page_size")
+	if !ok {
+		return
+	}
 	
 	// Get total count for pagination metadata
-	totalCount, err := h.repo.GetCoursesCount(c.Request.Context(), faculty, courseCodeRange)
+	totalCount, err := h.repo.GetCoursesCount(c.Request.Context(), faculty, courseCodeRange, termID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch course count"})
+		serverError(c, err, "Failed to fetch course count")
 		return
 	}
 	
@@ -154,17 +518,51 @@ func (h *CourseHandler) GetPaginatedCourses(c *gin.Context) {
 	}
 	
 	// Get paginated courses
-	courses, err := h.repo.GetPaginatedCourses(c.Request.Context(), page, pageSize, faculty, courseCodeRange)
+	courses, err := h.repo.GetPaginatedCourses(c.Request.Context(), page, pageSize, faculty, courseCodeRange, termID, sorts)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch courses"})
+		serverError(c, err, "Failed to fetch courses")
+		return
+	}
+	expanded, ok := h.includeCourses(c, courses)
+	if !ok {
 		return
 	}
 	
-	c.JSON(http.StatusOK, gin.H{
-		"data":        courses,
+	respond(c, http.StatusOK, gin.H{
+		"data":        expanded,
 		"page":        page,
 		"page_size":   pageSize,
 		"total_items": totalCount,
 		"total_pages": totalPages,
 	})
 }
+
+// cursorCourses answers with the page of courses after the cursor, in code
+// and term order, and the next_cursor to continue from (null on the last
+// page). Pages don't shift as courses are added or removed, and skip the
+// count query offset pages run for total_items.
+func (h *CourseHandler) cursorCourses(c *gin.Context, after *models.CourseCursor, faculty, courseCodeRange, termID *string) {
+	pageSize, ok := pageLimit(c, config.PagesCatalog, "page_size")
+	if !ok {
+		return
+	}
+
+	courses, err := h.repo.GetCoursesAfter(c.Request.Context(), after, pageSize+1, faculty, courseCodeRange, termID)
+	if err != nil {
+		serverError(c, err, "Failed to fetch courses")
+		return
+	}
+	courses, next := trimPage(courses, pageSize, func(course models.Course) any {
+		return models.CourseCursorAfter(course)
+	})
+	expanded, ok := h.includeCourses(c, courses)
+	if !ok {
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"data":        expanded,
+		"page_size":   pageSize,
+		"next_cursor": next,
+	})
+}
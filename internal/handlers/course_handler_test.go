@@ -2,13 +2,20 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
+	"yuplan/internal/config"
+	"yuplan/internal/dbtypes"
 	"yuplan/internal/models"
 	"yuplan/internal/repository"
+	"yuplan/internal/search"
+	"yuplan/internal/session"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -17,10 +24,14 @@ import (
 type MockCourseRepository struct {
 	getRandomCourses    func(ctx context.Context, limit int) ([]models.Course, error)
 	getByID             func(ctx context.Context, courseID string) (*models.Course, error)
+	getByIDs            func(ctx context.Context, ids []string) ([]models.Course, error)
 	getByCode           func(ctx context.Context, courseCode string) ([]models.Course, error)
-	search              func(ctx context.Context, query string, limit, offset int) ([]models.Course, error)
-	getPaginatedCourses func(ctx context.Context, page, pageSize int, faculty, courseCodeRange *string) ([]models.Course, error)
-	getCoursesCount     func(ctx context.Context, faculty, courseCodeRange *string) (int, error)
+	search              func(ctx context.Context, query, termID string, limit, offset int) ([]models.Course, error)
+	searchExactCode     func(ctx context.Context, code, termID string) ([]models.Course, error)
+	getPaginatedCourses func(ctx context.Context, page, pageSize int, faculty, courseCodeRange, termID *string, sorts []models.CourseSort) ([]models.Course, error)
+	getCoursesAfter     func(ctx context.Context, after *models.CourseCursor, limit int, faculty, courseCodeRange, termID *string) ([]models.Course, error)
+	getCoursesCount     func(ctx context.Context, faculty, courseCodeRange, termID *string) (int, error)
+	streamAll           func(ctx context.Context, fn func(models.Course) error) error
 }
 
 func (m *MockCourseRepository) GetRandomCourses(ctx context.Context, limit int) ([]models.Course, error) {
@@ -37,6 +48,13 @@ func (m *MockCourseRepository) GetByID(ctx context.Context, courseID string) (*m
 	return &models.Course{}, nil
 }
 
+func (m *MockCourseRepository) GetByIDs(ctx context.Context, ids []string) ([]models.Course, error) {
+	if m.getByIDs != nil {
+		return m.getByIDs(ctx, ids)
+	}
+	return []models.Course{}, nil
+}
+
 func (m *MockCourseRepository) GetByCode(ctx context.Context, courseCode string) ([]models.Course, error) {
 	if m.getByCode != nil {
 		return m.getByCode(ctx, courseCode)
@@ -44,27 +62,48 @@ func (m *MockCourseRepository) GetByCode(ctx context.Context, courseCode string)
 	return []models.Course{}, nil
 }
 
-func (m *MockCourseRepository) Search(ctx context.Context, query string, limit, offset int) ([]models.Course, error) {
+func (m *MockCourseRepository) Search(ctx context.Context, query, termID string, limit, offset int) ([]models.Course, error) {
 	if m.search != nil {
-		return m.search(ctx, query, limit, offset)
+		return m.search(ctx, query, termID, limit, offset)
 	}
 	return []models.Course{}, nil
 }
 
-func (m *MockCourseRepository) GetPaginatedCourses(ctx context.Context, page, pageSize int, faculty, courseCodeRange *string) ([]models.Course, error) {
+func (m *MockCourseRepository) SearchExactCode(ctx context.Context, code, termID string) ([]models.Course, error) {
+	if m.searchExactCode != nil {
+		return m.searchExactCode(ctx, code, termID)
+	}
+	return []models.Course{}, nil
+}
+
+func (m *MockCourseRepository) GetPaginatedCourses(ctx context.Context, page, pageSize int, faculty, courseCodeRange, termID *string, sorts []models.CourseSort) ([]models.Course, error) {
 	if m.getPaginatedCourses != nil {
-		return m.getPaginatedCourses(ctx, page, pageSize, faculty, courseCodeRange)
+		return m.getPaginatedCourses(ctx, page, pageSize, faculty, courseCodeRange, termID, sorts)
 	}
 	return []models.Course{}, nil
 }
 
-func (m *MockCourseRepository) GetCoursesCount(ctx context.Context, faculty, courseCodeRange *string) (int, error) {
+func (m *MockCourseRepository) GetCoursesAfter(ctx context.Context, after *models.CourseCursor, limit int, faculty, courseCodeRange, termID *string) ([]models.Course, error) {
+	if m.getCoursesAfter != nil {
+		return m.getCoursesAfter(ctx, after, limit, faculty, courseCodeRange, termID)
+	}
+	return []models.Course{}, nil
+}
+
+func (m *MockCourseRepository) GetCoursesCount(ctx context.Context, faculty, courseCodeRange, termID *string) (int, error) {
 	if m.getCoursesCount != nil {
-		return m.getCoursesCount(ctx, faculty, courseCodeRange)
+		return m.getCoursesCount(ctx, faculty, courseCodeRange, termID)
 	}
 	return 0, nil
 }
 
+func (m *MockCourseRepository) StreamAll(ctx context.Context, fn func(models.Course) error) error {
+	if m.streamAll != nil {
+		return m.streamAll(ctx, fn)
+	}
+	return nil
+}
+
 func TestGetCourses(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -121,6 +160,117 @@ func TestGetCoursesByCode_ReturnsAllOfferingsWithSections(t *testing.T) {
 	assert.Contains(t, recorder.Body.String(), "sec-1")
 }
 
+type mockCourseInstructors struct{}
+
+func (mockCourseInstructors) GetByCourseID(ctx context.Context, courseID string) ([]models.Instructor, error) {
+	return []models.Instructor{{ID: "inst-" + courseID, FirstName: "Jane", LastName: "Smith"}}, nil
+}
+
+type mockCourseSummaries struct {
+	gotCodes []string
+}
+
+func (m *mockCourseSummaries) CourseSummaries(ctx context.Context, codes []string, since time.Time) ([]models.LiteCourse, error) {
+	m.gotCodes = codes
+	return []models.LiteCourse{{Code: "EECS2030", Name: "Advanced OOP", ReviewCount: 12}}, nil
+}
+
+func TestGetCourses_Includes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var repo repository.CourseRepositoryInterface = &MockCourseRepository{
+		getRandomCourses: func(ctx context.Context, limit int) ([]models.Course, error) {
+			return []models.Course{
+				{ID: "course-fall", Code: "EECS2030", Term: "F"},
+				{ID: "course-winter", Code: "EECS2030", Term: "W"},
+				{ID: "course-math", Code: "MATH1013", Term: "F"},
+			}, nil
+		},
+	}
+	summaries := &mockCourseSummaries{}
+	handler := NewCourseHandler(repo, nil).
+		WithInstructors(mockCourseInstructors{}).
+		WithCourseStats(summaries, time.Hour)
+
+	router := gin.New()
+	router.GET("/courses", handler.GetCourses)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest("GET", "/courses?include=instructors,stats", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	body := recorder.Body.String()
+	assert.Contains(t, body, `"instructors":[{"id":"inst-course-fall"`)
+	assert.Contains(t, body, `"stats":{"code":"EECS2030","name":"Advanced OOP","avg_difficulty":null,"like_percentage":null,"review_count":12}`)
+	assert.NotContains(t, body, `"sections"`)
+	assert.Equal(t, []string{"EECS2030", "MATH1013"}, summaries.gotCodes, "stats are fetched once per distinct code")
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest("GET", "/courses", nil))
+	assert.NotContains(t, recorder.Body.String(), `"instructors"`)
+	assert.NotContains(t, recorder.Body.String(), `"stats"`)
+}
+
+func TestGetCourses_RejectsUnofferedAndCostlyIncludes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var repo repository.CourseRepositoryInterface = &MockCourseRepository{
+		getRandomCourses: func(ctx context.Context, limit int) ([]models.Course, error) {
+			return make([]models.Course, limit), nil
+		},
+	}
+	router := gin.New()
+	router.GET("/courses", NewCourseHandler(repo, nil).GetCourses)
+
+	for _, query := range []string{
+		"?include=instructors",        // not configured
+		"?include=sections&limit=500", // too many queries
+	} {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest("GET", "/courses"+query, nil))
+		assert.Equal(t, http.StatusBadRequest, recorder.Code, query)
+	}
+}
+
+func TestGetCoursesByCode_IncludesOfferingHistory(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var repo repository.CourseRepositoryInterface = &MockCourseRepository{
+		getByCode: func(ctx context.Context, courseCode string) ([]models.Course, error) {
+			return []models.Course{{ID: "course-fall", Code: "EECS2030", Term: "F"}}, nil
+		},
+	}
+	var sectionRepo repository.SectionRepositoryInterface = &MockSectionRepositoryForCourseHandler{
+		getByCourseID: func(ctx context.Context, courseID string) ([]models.Section, error) {
+			return []models.Section{}, nil
+		},
+	}
+	history := &mockOfferingRepository{summary: &models.OfferingSummary{
+		Code:            "EECS2030",
+		LastOfferedYear: 2025,
+		LastOfferedTerm: models.TermFall,
+		Frequency:       models.OfferingAnnual,
+	}}
+	handler := NewCourseHandler(repo, sectionRepo).WithOfferingHistory(history)
+
+	router := gin.New()
+	router.GET("/courses/:course_code", handler.GetCoursesByCode)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest("GET", "/courses/EECS2030", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"last_offered_year":2025`)
+
+	// A failing history lookup still returns the course.
+	history.summary, history.err = nil, errors.New("db down")
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest("GET", "/courses/EECS2030", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"offering":null`)
+}
+
 func TestGetCourses_WhenRepoErrors_Returns500(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -142,6 +292,183 @@ func TestGetCourses_WhenRepoErrors_Returns500(t *testing.T) {
 	assert.Contains(t, strings.ToLower(recorder.Body.String()), "failed")
 }
 
+func TestGetCourses_ByIDs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	first := "0192f3a1-5b2c-7d4e-8f60-0123456789ab"
+	second := "0192f3a1-5b2c-7d4e-8f60-0123456789ac"
+	var looked []string
+	handler := NewCourseHandler(&MockCourseRepository{
+		getByIDs: func(ctx context.Context, ids []string) ([]models.Course, error) {
+			looked = ids
+			return []models.Course{{ID: second, Code: "MATH1090"}, {ID: first, Code: "EECS2030"}}, nil
+		},
+		getRandomCourses: func(ctx context.Context, limit int) ([]models.Course, error) {
+			t.Error("Expected an id lookup, not random courses")
+			return nil, nil
+		},
+	}, nil)
+	router := gin.New()
+	router.GET("/courses", handler.GetCourses)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/courses?ids="+second+","+first+","+second, nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, []string{second, first}, looked)
+	assert.Contains(t, recorder.Body.String(), `"count":2`)
+}
+
+func TestGetCourses_ByIDsErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tooMany := make([]string, models.MaxCourseIDs+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("0192f3a1-5b2c-7d4e-8f60-%012d", i)
+	}
+	tests := []struct {
+		name           string
+		query          string
+		err            error
+		expectedStatus int
+	}{
+		{"no ids", "?ids=,", nil, http.StatusBadRequest},
+		{"not a uuid", "?ids=EECS2030", nil, http.StatusBadRequest},
+		{"too many", "?ids=" + strings.Join(tooMany, ","), nil, http.StatusBadRequest},
+		{"repo error", "?ids=0192f3a1-5b2c-7d4e-8f60-0123456789ab", errors.New("db down"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewCourseHandler(&MockCourseRepository{
+				getByIDs: func(ctx context.Context, ids []string) ([]models.Course, error) {
+					return nil, tt.err
+				},
+			}, nil)
+			router := gin.New()
+			router.GET("/courses", handler.GetCourses)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/courses"+tt.query, nil))
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+type mockCourseFeed struct {
+	email string
+	limit int
+	seen  []string
+}
+
+func (m *mockCourseFeed) Courses(ctx context.Context, email string, limit int) ([]models.Course, error) {
+	m.email, m.limit = email, limit
+	return []models.Course{{Code: "EECS2030"}}, nil
+}
+
+func (m *mockCourseFeed) RecordSeen(ctx context.Context, email string, courseCodes []string) error {
+	m.email, m.seen = email, courseCodes
+	return nil
+}
+
+func TestGetCourses_UsesFeedForEmail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	feed := &mockCourseFeed{}
+	handler := NewCourseHandler(&MockCourseRepository{}, nil).WithFeed(feed)
+
+	router := gin.New()
+	router.GET("/courses", handler.GetCourses)
+
+	req, _ := http.NewRequest(http.MethodGet, "/courses?limit=5&email=student@my.yorku.ca", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "EECS2030")
+	assert.Equal(t, "student@my.yorku.ca", feed.email)
+	assert.Equal(t, 5, feed.limit)
+}
+
+type mockCourseShuffle struct {
+	seed          string
+	limit, offset int
+}
+
+func (m *mockCourseShuffle) GetShuffledCourses(ctx context.Context, seed string, limit, offset int) ([]models.Course, error) {
+	m.seed, m.limit, m.offset = seed, limit, offset
+	return []models.Course{{Code: "MATH1090"}}, nil
+}
+
+func TestGetCourses_SessionShuffle(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	shuffle := &mockCourseShuffle{}
+	feed := &mockCourseFeed{}
+	handler := NewCourseHandler(&MockCourseRepository{}, nil).WithFeed(feed).WithSessionShuffle(shuffle)
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if c.Query("session") != "" {
+			c.Request = c.Request.WithContext(session.WithSession(c.Request.Context(), session.Session{ID: c.Query("session")}))
+		}
+	})
+	router.GET("/courses", handler.GetCourses)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/courses?limit=5&offset=10&session=s-1", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "MATH1090")
+	assert.Equal(t, mockCourseShuffle{seed: "s-1", limit: 5, offset: 10}, *shuffle)
+
+	// A reviewer's personalized feed takes precedence, as does having no session
+	*shuffle = mockCourseShuffle{}
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/courses?email=student@my.yorku.ca&session=s-1", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/courses", nil))
+	assert.Empty(t, shuffle.seed)
+	assert.Equal(t, "", feed.email)
+}
+
+func TestRecordSeen(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	feed := &mockCourseFeed{}
+	handler := NewCourseHandler(&MockCourseRepository{}, nil).WithFeed(feed)
+
+	router := gin.New()
+	router.POST("/courses/seen", handler.RecordSeen)
+
+	body := `{"email": "student@my.yorku.ca", "course_codes": ["EECS2030", "MATH1090"]}`
+	req, _ := http.NewRequest(http.MethodPost, "/courses/seen", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "student@my.yorku.ca", feed.email)
+	assert.Equal(t, []string{"EECS2030", "MATH1090"}, feed.seen)
+}
+
+func TestRecordSeen_InvalidBody_Returns400(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewCourseHandler(&MockCourseRepository{}, nil).WithFeed(&mockCourseFeed{})
+
+	router := gin.New()
+	router.POST("/courses/seen", handler.RecordSeen)
+
+	for _, body := range []string{
+		`{"email": "not-an-email", "course_codes": ["EECS2030"]}`,
+		`{"email": "student@my.yorku.ca", "course_codes": []}`,
+	} {
+		req, _ := http.NewRequest(http.MethodPost, "/courses/seen", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code, body)
+	}
+}
+
 func TestGetCoursesByCode_WhenNoneFound_Returns404(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -163,11 +490,119 @@ func TestGetCoursesByCode_WhenNoneFound_Returns404(t *testing.T) {
 	assert.Contains(t, strings.ToLower(recorder.Body.String()), "not found")
 }
 
+type mockCourseDetails struct {
+	detail *models.CourseDetail
+	err    error
+	since  time.Time
+}
+
+func (m *mockCourseDetails) GetFull(ctx context.Context, courseID string, since time.Time) (*models.CourseDetail, error) {
+	m.since = since
+	return m.detail, m.err
+}
+
+func TestGetCourseFull(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	courseID := "01928c6a-7b3e-7a21-9f00-0123456789ab"
+	found := &models.CourseDetail{
+		Course:    models.Course{ID: courseID, Code: "EECS2030"},
+		Sections:  []models.Section{{ID: "section-a", Letter: "A"}},
+		Labs:      []models.SectionActivity{{ID: "act-2", CourseType: models.ActivityLab}},
+		Tutorials: []models.SectionActivity{},
+		Stats:     models.LiteCourse{Code: "EECS2030", ReviewCount: 4},
+	}
+
+	tests := []struct {
+		name           string
+		path           string
+		details        *mockCourseDetails
+		expectedStatus int
+		expectedBody   string
+	}{
+		{"found", "/courses/" + courseID + "/full", &mockCourseDetails{detail: found}, http.StatusOK, `"labs":[{"id":"act-2"`},
+		{"not found", "/courses/" + courseID + "/full", &mockCourseDetails{}, http.StatusNotFound, "not found"},
+		{"course code instead of id", "/courses/EECS2030/full", &mockCourseDetails{detail: found}, http.StatusBadRequest, "invalid_id"},
+		{"repo error", "/courses/" + courseID + "/full", &mockCourseDetails{err: errors.New("db down")}, http.StatusInternalServerError, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewCourseHandler(&MockCourseRepository{}, nil).WithDetails(tt.details)
+			router := gin.New()
+			router.GET("/courses/:course_code/full", handler.GetCourseFull)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			assert.Contains(t, strings.ToLower(recorder.Body.String()), strings.ToLower(tt.expectedBody))
+		})
+	}
+}
+
+func TestGetCourseFull_StatsWindow(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	details := &mockCourseDetails{detail: &models.CourseDetail{}}
+	handler := NewCourseHandler(&MockCourseRepository{}, nil).
+		WithCourseStats(&mockCourseSummaries{}, 30*24*time.Hour).
+		WithDetails(details)
+	router := gin.New()
+	router.GET("/courses/:course_code/full", handler.GetCourseFull)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/courses/01928c6a-7b3e-7a21-9f00-0123456789ab/full", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.WithinDuration(t, time.Now().Add(-30*24*time.Hour), details.since, time.Minute)
+}
+
+func TestCourseDeadlines(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	dropBy := time.Date(2026, 11, 8, 4, 59, 0, 0, time.UTC)
+	terms := &mockTermRepository{deadlines: []models.TermDeadlines{
+		{AcademicYear: 2026, Term: models.TermFall, DropDeadline: dbtypes.NewNullTime(dropBy)},
+	}}
+	repo := &MockCourseRepository{
+		search: func(ctx context.Context, query, termID string, limit, offset int) ([]models.Course, error) {
+			return []models.Course{
+				{ID: "1", Code: "EECS3311", Term: models.TermFall},
+				{ID: "2", Code: "EECS4313", Term: models.TermWinter},
+			}, nil
+		},
+	}
+	details := &mockCourseDetails{detail: &models.CourseDetail{Course: models.Course{Code: "EECS3311", Term: models.TermFall}}}
+	handler := NewCourseHandler(repo, nil).WithDetails(details).WithDeadlines(terms)
+	router := gin.New()
+	router.GET("/courses/search", handler.SearchCourses)
+	router.GET("/courses/:course_code/full", handler.GetCourseFull)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/courses/search?q=EECS", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	var body struct {
+		Data []ExpandedCourse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	assert.Len(t, body.Data, 2)
+	assert.Equal(t, &models.TermDeadlines{AcademicYear: 2026, Term: models.TermFall, DropDeadline: dbtypes.NewNullTime(dropBy)}, body.Data[0].Deadlines)
+	assert.Nil(t, body.Data[1].Deadlines)
+	assert.Contains(t, recorder.Body.String(), `"enroll_deadline":null`)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/courses/01928c6a-7b3e-7a21-9f00-0123456789ab/full", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"deadlines":{"academic_year":2026,"term":"F"`)
+}
+
 func TestSearchCourses(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	var repo repository.CourseRepositoryInterface = &MockCourseRepository{
-		search: func(ctx context.Context, query string, limit, offset int) ([]models.Course, error) {
+		search: func(ctx context.Context, query, termID string, limit, offset int) ([]models.Course, error) {
 			return []models.Course{
 				{ID: "1", Code: "EECS3311", Name: "Software Design"},
 				{ID: "2", Code: "EECS4313", Name: "Software Engineering"},
@@ -190,11 +625,49 @@ func TestSearchCourses(t *testing.T) {
 	assert.Contains(t, recorder.Body.String(), "Software Design")
 }
 
+type recordedSearch struct {
+	query   string
+	results int
+}
+
+type fakeSearchRecorder struct {
+	searches []recordedSearch
+}
+
+func (f *fakeSearchRecorder) Record(query string, results int) {
+	f.searches = append(f.searches, recordedSearch{query: query, results: results})
+}
+
+func TestSearchCourses_RecordsFirstPageSearches(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var repo repository.CourseRepositoryInterface = &MockCourseRepository{
+		search: func(ctx context.Context, query, termID string, limit, offset int) ([]models.Course, error) {
+			if query == "nothing" {
+				return []models.Course{}, nil
+			}
+			return []models.Course{{ID: "1", Code: "EECS3311"}}, nil
+		},
+	}
+	recorder := &fakeSearchRecorder{}
+	handler := NewCourseHandler(repo, nil).WithSearchRecorder(recorder)
+
+	router := gin.New()
+	router.GET("/courses/search", handler.SearchCourses)
+
+	for _, url := range []string{"/courses/search?q=EECS", "/courses/search?q=nothing", "/courses/search?q=EECS&offset=50"} {
+		req, _ := http.NewRequest("GET", url, nil)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	assert.Equal(t, []recordedSearch{{query: "EECS", results: 1}, {query: "nothing", results: 0}}, recorder.searches)
+}
+
 func TestSearchCourses_WithPagination(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	var repo repository.CourseRepositoryInterface = &MockCourseRepository{
-		search: func(ctx context.Context, query string, limit, offset int) ([]models.Course, error) {
+		search: func(ctx context.Context, query, termID string, limit, offset int) ([]models.Course, error) {
 			assert.Equal(t, "Software", query)
 			assert.Equal(t, 10, limit)
 			assert.Equal(t, 5, offset)
@@ -237,7 +710,7 @@ func TestSearchCourses_WhenRepoErrors_Returns500(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	var repo repository.CourseRepositoryInterface = &MockCourseRepository{
-		search: func(ctx context.Context, query string, limit, offset int) ([]models.Course, error) {
+		search: func(ctx context.Context, query, termID string, limit, offset int) ([]models.Course, error) {
 			return nil, errors.New("db error")
 		},
 	}
@@ -258,7 +731,7 @@ func TestSearchCourses_EmptyResults(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	var repo repository.CourseRepositoryInterface = &MockCourseRepository{
-		search: func(ctx context.Context, query string, limit, offset int) ([]models.Course, error) {
+		search: func(ctx context.Context, query, termID string, limit, offset int) ([]models.Course, error) {
 			return []models.Course{}, nil
 		},
 	}
@@ -280,13 +753,13 @@ func TestGetPaginatedCourses(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	var repo repository.CourseRepositoryInterface = &MockCourseRepository{
-		getPaginatedCourses: func(ctx context.Context, page, pageSize int, faculty, courseCodeRange *string) ([]models.Course, error) {
+		getPaginatedCourses: func(ctx context.Context, page, pageSize int, faculty, courseCodeRange, termID *string, sorts []models.CourseSort) ([]models.Course, error) {
 			return []models.Course{
 				{ID: "1", Code: "EECS1000", Name: "Course 1", Faculty: "SC"},
 				{ID: "2", Code: "MATH1010", Name: "Course 2", Faculty: "SC"},
 			}, nil
 		},
-		getCoursesCount: func(ctx context.Context, faculty, courseCodeRange *string) (int, error) {
+		getCoursesCount: func(ctx context.Context, faculty, courseCodeRange, termID *string) (int, error) {
 			return 100, nil
 		},
 	}
@@ -308,16 +781,73 @@ func TestGetPaginatedCourses(t *testing.T) {
 	assert.Contains(t, recorder.Body.String(), "EECS1000")
 }
 
+func TestGetPaginatedCourses_Cursor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	courses := []models.Course{
+		{ID: "0192f3a1-5b2c-7d4e-8f60-000000000001", Code: "EECS1001", Term: "F"},
+		{ID: "0192f3a1-5b2c-7d4e-8f60-000000000002", Code: "EECS1012", Term: "F"},
+		{ID: "0192f3a1-5b2c-7d4e-8f60-000000000003", Code: "EECS1012", Term: "W"},
+	}
+	var gotAfter *models.CourseCursor
+	var gotLimit int
+	handler := NewCourseHandler(&MockCourseRepository{
+		getCoursesAfter: func(ctx context.Context, after *models.CourseCursor, limit int, faculty, courseCodeRange, termID *string) ([]models.Course, error) {
+			gotAfter, gotLimit = after, limit
+			if after == nil {
+				return courses, nil
+			}
+			return courses[2:], nil
+		},
+	}, nil)
+	router := gin.New()
+	router.GET("/courses/paginated", handler.GetPaginatedCourses)
+	get := func(query string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/courses/paginated"+query, nil))
+		return recorder
+	}
+
+	recorder := get("?page_size=2&cursor=")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var body struct {
+		Data       []models.Course `json:"data"`
+		NextCursor *string         `json:"next_cursor"`
+	}
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	assert.Nil(t, gotAfter)
+	assert.Equal(t, 3, gotLimit)
+	assert.Len(t, body.Data, 2)
+	assert.NotContains(t, recorder.Body.String(), "total_items")
+	if !assert.NotNil(t, body.NextCursor) {
+		return
+	}
+
+	recorder = get("?page_size=2&cursor=" + *body.NextCursor)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, &models.CourseCursor{Code: "EECS1012", Term: "F", ID: courses[1].ID}, gotAfter)
+	assert.Contains(t, recorder.Body.String(), `"next_cursor":null`)
+
+	for _, query := range []string{
+		"?cursor=&sort=name",
+		"?cursor=&page=2",
+		"?cursor=garbage",
+		"?cursor=" + models.EncodeCursor(models.CourseCursor{Code: "EECS1012", ID: "1; DROP TABLE courses"}),
+	} {
+		assert.Equal(t, http.StatusBadRequest, get(query).Code, query)
+	}
+}
+
 func TestGetPaginatedCourses_WithDefaultValues(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	var repo repository.CourseRepositoryInterface = &MockCourseRepository{
-		getPaginatedCourses: func(ctx context.Context, page, pageSize int, faculty, courseCodeRange *string) ([]models.Course, error) {
+		getPaginatedCourses: func(ctx context.Context, page, pageSize int, faculty, courseCodeRange, termID *string, sorts []models.CourseSort) ([]models.Course, error) {
 			assert.Equal(t, 1, page)
 			assert.Equal(t, 20, pageSize)
 			return []models.Course{}, nil
 		},
-		getCoursesCount: func(ctx context.Context, faculty, courseCodeRange *string) (int, error) {
+		getCoursesCount: func(ctx context.Context, faculty, courseCodeRange, termID *string) (int, error) {
 			return 0, nil
 		},
 	}
@@ -339,14 +869,14 @@ func TestGetPaginatedCourses_WithFacultyFilter(t *testing.T) {
 
 	faculty := "SC"
 	var repo repository.CourseRepositoryInterface = &MockCourseRepository{
-		getPaginatedCourses: func(ctx context.Context, page, pageSize int, facultyFilter, courseCodeRange *string) ([]models.Course, error) {
+		getPaginatedCourses: func(ctx context.Context, page, pageSize int, facultyFilter, courseCodeRange, termID *string, sorts []models.CourseSort) ([]models.Course, error) {
 			assert.NotNil(t, facultyFilter)
 			assert.Equal(t, "SC", *facultyFilter)
 			return []models.Course{
 				{ID: "1", Code: "EECS1000", Name: "Course 1", Faculty: "SC"},
 			}, nil
 		},
-		getCoursesCount: func(ctx context.Context, facultyFilter, courseCodeRange *string) (int, error) {
+		getCoursesCount: func(ctx context.Context, facultyFilter, courseCodeRange, termID *string) (int, error) {
 			assert.NotNil(t, facultyFilter)
 			assert.Equal(t, "SC", *facultyFilter)
 			return 50, nil
@@ -369,14 +899,14 @@ func TestGetPaginatedCourses_WithCourseCodeRangeFilter(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	var repo repository.CourseRepositoryInterface = &MockCourseRepository{
-		getPaginatedCourses: func(ctx context.Context, page, pageSize int, faculty, courseCodeRange *string) ([]models.Course, error) {
+		getPaginatedCourses: func(ctx context.Context, page, pageSize int, faculty, courseCodeRange, termID *string, sorts []models.CourseSort) ([]models.Course, error) {
 			assert.NotNil(t, courseCodeRange)
 			assert.Equal(t, "1000s", *courseCodeRange)
 			return []models.Course{
 				{ID: "1", Code: "EECS1000", Name: "Course 1", Faculty: "SC"},
 			}, nil
 		},
-		getCoursesCount: func(ctx context.Context, faculty, courseCodeRange *string) (int, error) {
+		getCoursesCount: func(ctx context.Context, faculty, courseCodeRange, termID *string) (int, error) {
 			assert.NotNil(t, courseCodeRange)
 			assert.Equal(t, "1000s", *courseCodeRange)
 			return 25, nil
@@ -399,7 +929,7 @@ func TestGetPaginatedCourses_WithBothFilters(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	var repo repository.CourseRepositoryInterface = &MockCourseRepository{
-		getPaginatedCourses: func(ctx context.Context, page, pageSize int, faculty, courseCodeRange *string) ([]models.Course, error) {
+		getPaginatedCourses: func(ctx context.Context, page, pageSize int, faculty, courseCodeRange, termID *string, sorts []models.CourseSort) ([]models.Course, error) {
 			assert.NotNil(t, faculty)
 			assert.NotNil(t, courseCodeRange)
 			assert.Equal(t, "SC", *faculty)
@@ -408,7 +938,7 @@ func TestGetPaginatedCourses_WithBothFilters(t *testing.T) {
 				{ID: "1", Code: "EECS2030", Name: "Course 1", Faculty: "SC"},
 			}, nil
 		},
-		getCoursesCount: func(ctx context.Context, faculty, courseCodeRange *string) (int, error) {
+		getCoursesCount: func(ctx context.Context, faculty, courseCodeRange, termID *string) (int, error) {
 			assert.NotNil(t, faculty)
 			assert.NotNil(t, courseCodeRange)
 			return 15, nil
@@ -431,14 +961,14 @@ func TestGetPaginatedCourses_WithPagination(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	var repo repository.CourseRepositoryInterface = &MockCourseRepository{
-		getPaginatedCourses: func(ctx context.Context, page, pageSize int, faculty, courseCodeRange *string) ([]models.Course, error) {
+		getPaginatedCourses: func(ctx context.Context, page, pageSize int, faculty, courseCodeRange, termID *string, sorts []models.CourseSort) ([]models.Course, error) {
 			assert.Equal(t, 2, page)
 			assert.Equal(t, 10, pageSize)
 			return []models.Course{
 				{ID: "11", Code: "EECS3010", Name: "Course 11", Faculty: "SC"},
 			}, nil
 		},
-		getCoursesCount: func(ctx context.Context, faculty, courseCodeRange *string) (int, error) {
+		getCoursesCount: func(ctx context.Context, faculty, courseCodeRange, termID *string) (int, error) {
 			return 100, nil
 		},
 	}
@@ -461,11 +991,11 @@ func TestGetPaginatedCourses_InvalidPageNumber_DefaultsToOne(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	var repo repository.CourseRepositoryInterface = &MockCourseRepository{
-		getPaginatedCourses: func(ctx context.Context, page, pageSize int, faculty, courseCodeRange *string) ([]models.Course, error) {
+		getPaginatedCourses: func(ctx context.Context, page, pageSize int, faculty, courseCodeRange, termID *string, sorts []models.CourseSort) ([]models.Course, error) {
 			assert.Equal(t, 1, page) // Should default to 1
 			return []models.Course{}, nil
 		},
-		getCoursesCount: func(ctx context.Context, faculty, courseCodeRange *string) (int, error) {
+		getCoursesCount: func(ctx context.Context, faculty, courseCodeRange, termID *string) (int, error) {
 			return 0, nil
 		},
 	}
@@ -481,59 +1011,65 @@ func TestGetPaginatedCourses_InvalidPageNumber_DefaultsToOne(t *testing.T) {
 	assert.Equal(t, http.StatusOK, recorder.Code)
 }
 
-func TestGetPaginatedCourses_InvalidPageSize_DefaultsToTwenty(t *testing.T) {
+func TestGetPaginatedCourses_PageSizeOutOfRange_Returns400(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	var repo repository.CourseRepositoryInterface = &MockCourseRepository{
-		getPaginatedCourses: func(ctx context.Context, page, pageSize int, faculty, courseCodeRange *string) ([]models.Course, error) {
-			assert.Equal(t, 20, pageSize) // Should default to 20
+		getPaginatedCourses: func(ctx context.Context, page, pageSize int, faculty, courseCodeRange, termID *string, sorts []models.CourseSort) ([]models.Course, error) {
+			t.Error("Out of range page sizes should not reach the repository")
 			return []models.Course{}, nil
 		},
-		getCoursesCount: func(ctx context.Context, faculty, courseCodeRange *string) (int, error) {
+		getCoursesCount: func(ctx context.Context, faculty, courseCodeRange, termID *string) (int, error) {
 			return 0, nil
 		},
 	}
 	handler := NewCourseHandler(repo, nil)
 
-	router := gin.Default()
+	router := gin.New()
 	router.GET("/courses/paginated", handler.GetPaginatedCourses)
 
-	req, _ := http.NewRequest("GET", "/courses/paginated?page_size=0", nil)
-	recorder := httptest.NewRecorder()
-	router.ServeHTTP(recorder, req)
+	for _, pageSize := range []string{"0", "200", "ten"} {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest("GET", "/courses/paginated?page_size="+pageSize, nil))
 
-	assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code, pageSize)
+		assert.Contains(t, recorder.Body.String(), "between 1 and 100", pageSize)
+	}
 }
 
-func TestGetPaginatedCourses_PageSizeExceedsMax_DefaultsToTwenty(t *testing.T) {
+func TestGetPaginatedCourses_ConfiguredPageSizes(t *testing.T) {
 	gin.SetMode(gin.TestMode)
+	SetPageSizes(map[string]config.PageSize{config.PagesCatalog: {Default: 5, Max: 10}})
+	defer SetPageSizes(config.DefaultPageSizes())
 
+	var gotPageSize int
 	var repo repository.CourseRepositoryInterface = &MockCourseRepository{
-		getPaginatedCourses: func(ctx context.Context, page, pageSize int, faculty, courseCodeRange *string) ([]models.Course, error) {
-			assert.Equal(t, 20, pageSize) // Should default to 20 (max is 100, but invalid values default to 20)
+		getPaginatedCourses: func(ctx context.Context, page, pageSize int, faculty, courseCodeRange, termID *string, sorts []models.CourseSort) ([]models.Course, error) {
+			gotPageSize = pageSize
 			return []models.Course{}, nil
 		},
-		getCoursesCount: func(ctx context.Context, faculty, courseCodeRange *string) (int, error) {
+		getCoursesCount: func(ctx context.Context, faculty, courseCodeRange, termID *string) (int, error) {
 			return 0, nil
 		},
 	}
-	handler := NewCourseHandler(repo, nil)
-
-	router := gin.Default()
-	router.GET("/courses/paginated", handler.GetPaginatedCourses)
+	router := gin.New()
+	router.GET("/courses/paginated", NewCourseHandler(repo, nil).GetPaginatedCourses)
 
-	req, _ := http.NewRequest("GET", "/courses/paginated?page_size=200", nil)
 	recorder := httptest.NewRecorder()
-	router.ServeHTTP(recorder, req)
-
+	router.ServeHTTP(recorder, httptest.NewRequest("GET", "/courses/paginated", nil))
 	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, 5, gotPageSize)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest("GET", "/courses/paginated?page_size=20", nil))
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
 }
 
 func TestGetPaginatedCourses_WhenGetCoursesCountErrors_Returns500(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	var repo repository.CourseRepositoryInterface = &MockCourseRepository{
-		getCoursesCount: func(ctx context.Context, faculty, courseCodeRange *string) (int, error) {
+		getCoursesCount: func(ctx context.Context, faculty, courseCodeRange, termID *string) (int, error) {
 			return 0, errors.New("db error")
 		},
 	}
@@ -554,10 +1090,10 @@ func TestGetPaginatedCourses_WhenGetPaginatedCoursesErrors_Returns500(t *testing
 	gin.SetMode(gin.TestMode)
 
 	var repo repository.CourseRepositoryInterface = &MockCourseRepository{
-		getPaginatedCourses: func(ctx context.Context, page, pageSize int, faculty, courseCodeRange *string) ([]models.Course, error) {
+		getPaginatedCourses: func(ctx context.Context, page, pageSize int, faculty, courseCodeRange, termID *string, sorts []models.CourseSort) ([]models.Course, error) {
 			return nil, errors.New("db error")
 		},
-		getCoursesCount: func(ctx context.Context, faculty, courseCodeRange *string) (int, error) {
+		getCoursesCount: func(ctx context.Context, faculty, courseCodeRange, termID *string) (int, error) {
 			return 100, nil
 		},
 	}
@@ -578,10 +1114,10 @@ func TestGetPaginatedCourses_EmptyResults(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	var repo repository.CourseRepositoryInterface = &MockCourseRepository{
-		getPaginatedCourses: func(ctx context.Context, page, pageSize int, faculty, courseCodeRange *string) ([]models.Course, error) {
+		getPaginatedCourses: func(ctx context.Context, page, pageSize int, faculty, courseCodeRange, termID *string, sorts []models.CourseSort) ([]models.Course, error) {
 			return []models.Course{}, nil
 		},
-		getCoursesCount: func(ctx context.Context, faculty, courseCodeRange *string) (int, error) {
+		getCoursesCount: func(ctx context.Context, faculty, courseCodeRange, termID *string) (int, error) {
 			return 0, nil
 		},
 	}
@@ -599,8 +1135,55 @@ func TestGetPaginatedCourses_EmptyResults(t *testing.T) {
 	assert.Contains(t, recorder.Body.String(), "\"total_pages\":1")
 }
 
+func TestGetAllCourses_Streams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var repo repository.CourseRepositoryInterface = &MockCourseRepository{
+		streamAll: func(ctx context.Context, fn func(models.Course) error) error {
+			for _, code := range []string{"EECS2030", "MATH1013"} {
+				if err := fn(models.Course{ID: "id-" + code, Code: code}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	handler := NewCourseHandler(repo, nil)
+
+	router := gin.New()
+	router.GET("/courses/all", handler.GetAllCourses)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/courses/all", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.True(t, strings.HasPrefix(recorder.Body.String(), `{"data":[{"id":"id-EECS2030"`))
+	assert.True(t, strings.HasSuffix(recorder.Body.String(), `],"count":2}`))
+	assert.Equal(t, "application/json; charset=utf-8", recorder.Header().Get("Content-Type"))
+}
+
+func TestGetAllCourses_Error(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var repo repository.CourseRepositoryInterface = &MockCourseRepository{
+		streamAll: func(ctx context.Context, fn func(models.Course) error) error {
+			return errors.New("db down")
+		},
+	}
+	handler := NewCourseHandler(repo, nil)
+
+	router := gin.New()
+	router.GET("/courses/all", handler.GetAllCourses)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/courses/all", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+}
+
 type MockSectionRepositoryForCourseHandler struct {
-	getByCourseID func(ctx context.Context, courseID string) ([]models.Section, error)
+	getByCourseID       func(ctx context.Context, courseID string) ([]models.Section, error)
+	getByCourseIDInTerm func(ctx context.Context, courseID, termID string) ([]models.Section, error)
 }
 
 func (m *MockSectionRepositoryForCourseHandler) GetByCourseID(ctx context.Context, courseID string) ([]models.Section, error) {
@@ -610,4 +1193,132 @@ func (m *MockSectionRepositoryForCourseHandler) GetByCourseID(ctx context.Contex
 	return []models.Section{}, nil
 }
 
+func (m *MockSectionRepositoryForCourseHandler) GetByCourseIDInTerm(ctx context.Context, courseID, termID string) ([]models.Section, error) {
+	if m.getByCourseIDInTerm != nil {
+		return m.getByCourseIDInTerm(ctx, courseID, termID)
+	}
+	return []models.Section{}, nil
+}
+
 // Note: legacy "identifier is course code" tests were consolidated into the tests above.
+
+func TestSearchCourses_TermFilter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var gotTerm string
+	repo := &MockCourseRepository{
+		search: func(ctx context.Context, query, termID string, limit, offset int) ([]models.Course, error) {
+			gotTerm = termID
+			return []models.Course{}, nil
+		},
+	}
+	router := gin.New()
+	router.GET("/courses/search", NewCourseHandler(repo, nil).SearchCourses)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/courses/search?q=EECS&term=SU2026", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "SU2026", gotTerm)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/courses/search?q=EECS&term=Fall", nil))
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "like FW2025")
+}
+
+func TestGetPaginatedCourses_TermFilter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var listed, counted *string
+	repo := &MockCourseRepository{
+		getPaginatedCourses: func(ctx context.Context, page, pageSize int, faculty, courseCodeRange, termID *string, sorts []models.CourseSort) ([]models.Course, error) {
+			listed = termID
+			return []models.Course{}, nil
+		},
+		getCoursesCount: func(ctx context.Context, faculty, courseCodeRange, termID *string) (int, error) {
+			counted = termID
+			return 0, nil
+		},
+	}
+	router := gin.New()
+	router.GET("/courses/paginated", NewCourseHandler(repo, nil).GetPaginatedCourses)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/courses/paginated?term=FW2025", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	if assert.NotNil(t, listed) && assert.NotNil(t, counted) {
+		assert.Equal(t, "FW2025", *listed)
+		assert.Equal(t, "FW2025", *counted)
+	}
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/courses/paginated?term=2025", nil))
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestGetPaginatedCourses_Sorts(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var sorted []models.CourseSort
+	repo := &MockCourseRepository{
+		getPaginatedCourses: func(ctx context.Context, page, pageSize int, faculty, courseCodeRange, termID *string, sorts []models.CourseSort) ([]models.Course, error) {
+			sorted = sorts
+			return []models.Course{}, nil
+		},
+	}
+	router := gin.New()
+	router.GET("/courses/paginated", NewCourseHandler(repo, nil).GetPaginatedCourses)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/courses/paginated?course_code_range=4000s&sort=level,asc&sort=avg_difficulty,desc", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, []models.CourseSort{{Key: models.CourseSortLevel}, {Key: models.CourseSortAvgDifficulty, Desc: true}}, sorted)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/courses/paginated?sort=created_at", nil))
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "unknown sort key")
+}
+
+type fakeCourseSearch struct {
+	path string
+}
+
+func (f fakeCourseSearch) Courses(ctx context.Context, query, termID string, limit, offset int) ([]models.Course, string, error) {
+	return []models.Course{{ID: "1", Code: "EECS2030"}}, f.path, nil
+}
+
+type recordedPaths struct {
+	paths []string
+}
+
+func (r *recordedPaths) Searched(path string, results int) {
+	r.paths = append(r.paths, path)
+}
+
+func TestSearchCourses_CountsSearchPath(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	metrics := &recordedPaths{}
+	router := gin.New()
+	router.GET("/courses/search", NewCourseHandler(&MockCourseRepository{}, nil).
+		WithSearch(fakeCourseSearch{path: search.PathExact}).
+		WithMetrics(metrics).
+		SearchCourses)
+	fuzzyRouter := gin.New()
+	fuzzyRouter.GET("/courses/search", NewCourseHandler(&MockCourseRepository{}, nil).
+		WithMetrics(metrics).
+		SearchCourses)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/courses/search?q=eecs+2030", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "EECS2030")
+
+	// Without a search service the repository's fuzzy search answers
+	recorder = httptest.NewRecorder()
+	fuzzyRouter.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/courses/search?q=software", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	assert.Equal(t, []string{search.PathExact, search.PathFuzzy}, metrics.paths)
+}
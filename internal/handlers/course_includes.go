@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// courseInstructors lists the instructors of a course's sections. Implemented by repository.InstructorRepository.
+type courseInstructors interface {
+	GetByCourseID(ctx context.Context, courseID string) ([]models.Instructor, error)
+}
+
+// courseSummaries returns review stats for a batch of course codes. Implemented by repository.LiteRepository.
+type courseSummaries interface {
+	CourseSummaries(ctx context.Context, codes []string, since time.Time) ([]models.LiteCourse, error)
+}
+
+// termDeadlines looks up the registration deadlines still ahead for course
+// term codes. Implemented by repository.TermRepository.
+type termDeadlines interface {
+	UpcomingDeadlines(ctx context.Context, terms []string) ([]models.TermDeadlines, error)
+}
+
+// ExpandedCourse is a course with whatever ?include= asked for, and its
+// term's upcoming deadlines when the handler has them. With neither it
+// serializes exactly like models.Course.
+type ExpandedCourse struct {
+	models.Course
+	Sections    []models.Section      `json:"sections,omitzero"`
+	Instructors []models.Instructor   `json:"instructors,omitzero"`
+	Stats       *models.LiteCourse    `json:"stats,omitzero"`
+	Deadlines   *models.TermDeadlines `json:"deadlines,omitzero"`
+}
+
+// courseIncludes are the includes course responses offer, given what the handler was built with.
+func (h *CourseHandler) courseIncludes() []include {
+	offered := []include{
+		// One query for the sections and one per section for its activities
+		{name: "sections", cost: 4},
+	}
+	if h.instructors != nil {
+		offered = append(offered, include{name: "instructors", cost: 1})
+	}
+	if h.stats != nil {
+		offered = append(offered, include{name: "stats", cost: 1, batched: true})
+	}
+	return offered
+}
+
+// expandCourses attaches the requested includes to each course.
+func (h *CourseHandler) expandCourses(ctx context.Context, courses []models.Course, includes map[string]bool) ([]ExpandedCourse, error) {
+	expanded := make([]ExpandedCourse, len(courses))
+	for i, course := range courses {
+		expanded[i].Course = course
+	}
+
+	if includes["stats"] {
+		codes := make([]string, 0, len(courses))
+		seen := map[string]bool{}
+		for _, course := range courses {
+			if !seen[course.Code] {
+				seen[course.Code] = true
+				codes = append(codes, course.Code)
+			}
+		}
+		summaries, err := h.stats.CourseSummaries(ctx, codes, time.Now().UTC().Add(-h.statsWindow))
+		if err != nil {
+			return nil, err
+		}
+		byCode := make(map[string]*models.LiteCourse, len(summaries))
+		for i := range summaries {
+			byCode[summaries[i].Code] = &summaries[i]
+		}
+		for i := range expanded {
+			expanded[i].Stats = byCode[expanded[i].Code]
+		}
+	}
+
+	if h.deadlines != nil {
+		byTerm, err := h.deadlinesByTerm(ctx, courses)
+		if err != nil {
+			return nil, err
+		}
+		for i := range expanded {
+			expanded[i].Deadlines = byTerm[expanded[i].Term]
+		}
+	}
+
+	for i := range expanded {
+		if includes["sections"] {
+			sections, err := h.sectionRepo.GetByCourseID(ctx, expanded[i].ID)
+			if err != nil {
+				return nil, err
+			}
+			if sections == nil {
+				sections = []models.Section{}
+			}
+			expanded[i].Sections = sections
+		}
+		if includes["instructors"] {
+			instructors, err := h.instructors.GetByCourseID(ctx, expanded[i].ID)
+			if err != nil {
+				return nil, err
+			}
+			if instructors == nil {
+				instructors = []models.Instructor{}
+			}
+			expanded[i].Instructors = instructors
+		}
+	}
+	return expanded, nil
+}
+
+// deadlinesByTerm fetches the upcoming deadlines of every term among courses
+// in one query, keyed by term code.
+func (h *CourseHandler) deadlinesByTerm(ctx context.Context, courses []models.Course) (map[string]*models.TermDeadlines, error) {
+	terms := make([]string, 0, len(courses))
+	seen := map[string]bool{}
+	for _, course := range courses {
+		if !seen[course.Term] {
+			seen[course.Term] = true
+			terms = append(terms, course.Term)
+		}
+	}
+	if len(terms) == 0 {
+		return nil, nil
+	}
+	deadlines, err := h.deadlines.UpcomingDeadlines(ctx, terms)
+	if err != nil {
+		return nil, err
+	}
+	byTerm := make(map[string]*models.TermDeadlines, len(deadlines))
+	for i := range deadlines {
+		byTerm[deadlines[i].Term] = &deadlines[i]
+	}
+	return byTerm, nil
+}
+
+// includeCourses expands courses with ?include=, writing a 400 or a server
+// error and returning false if it can't. always names includes the endpoint
+// has embedded since before ?include= existed.
+func (h *CourseHandler) includeCourses(c *gin.Context, courses []models.Course, always ...string) ([]ExpandedCourse, bool) {
+	includes, err := parseIncludes(c, h.courseIncludes(), len(courses))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	for _, name := range always {
+		includes[name] = true
+	}
+
+	expanded, err := h.expandCourses(c.Request.Context(), courses, includes)
+	if err != nil {
+		serverError(c, err, "Failed to fetch included resources")
+		return nil, false
+	}
+	return expanded, true
+}
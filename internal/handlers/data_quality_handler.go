@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"yuplan/internal/config"
+	"yuplan/internal/models"
+	"yuplan/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DataQualityHandler struct {
+	repo repository.DataQualityRepositoryInterface
+}
+
+func NewDataQualityHandler(repo repository.DataQualityRepositoryInterface) *DataQualityHandler {
+	return &DataQualityHandler{repo: repo}
+}
+
+// GetDataQuality handles GET /api/v1/admin/data-quality?sort=score&issue=&department=&term=&max_score=&limit=100&runs=10
+// It lists course scores from the latest seed, worst first by default, and
+// summarizes the last `runs` seeds, newest first, to show the trend.
+func (h *DataQualityHandler) GetDataQuality(c *gin.Context) {
+	filter := models.DataQualityFilter{
+		Department: strings.ToUpper(strings.TrimSpace(c.Query("department"))),
+		Term:       c.Query("term"),
+		Issue:      c.Query("issue"),
+		Sort:       c.DefaultQuery("sort", models.DataQualitySortScore),
+	}
+	if !slices.Contains(models.DataQualitySorts, filter.Sort) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unknown sort %q; expected one of %s", filter.Sort, strings.Join(models.DataQualitySorts, ", "))})
+		return
+	}
+	if filter.Issue != "" && !slices.Contains(models.DataIssues, filter.Issue) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unknown issue %q; expected one of %s", filter.Issue, strings.Join(models.DataIssues, ", "))})
+		return
+	}
+	var err error
+	if filter.MaxScore, err = strconv.Atoi(c.DefaultQuery("max_score", "100")); err != nil || filter.MaxScore < 0 || filter.MaxScore > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "max_score must be between 0 and 100"})
+		return
+	}
+	var ok bool
+	if filter.Limit, ok = pageLimit(c, config.PagesDataQuality, "limit"); !ok {
+		return
+	}
+	runs, _ := strconv.Atoi(c.DefaultQuery("runs", "10"))
+	if runs < 1 || runs > 100 {
+		runs = 10
+	}
+
+	scores, err := h.repo.ListScores(c.Request.Context(), filter)
+	if err != nil {
+		serverError(c, err, "Failed to fetch data quality scores")
+		return
+	}
+	trend, err := h.repo.ListRuns(c.Request.Context(), runs)
+	if err != nil {
+		serverError(c, err, "Failed to fetch data quality trend")
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"data":  scores,
+		"count": len(scores),
+		"trend": trend,
+	})
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockDataQualityRepository struct {
+	scores []models.CourseQuality
+	runs   []models.DataQualityRun
+	filter models.DataQualityFilter
+	limit  int
+	err    error
+}
+
+func (m *mockDataQualityRepository) ListCourseData(ctx context.Context) ([]models.CourseData, error) {
+	return nil, nil
+}
+
+func (m *mockDataQualityRepository) RecordScores(ctx context.Context, scores []models.CourseQuality) error {
+	return nil
+}
+
+func (m *mockDataQualityRepository) ListScores(ctx context.Context, filter models.DataQualityFilter) ([]models.CourseQuality, error) {
+	m.filter = filter
+	return m.scores, m.err
+}
+
+func (m *mockDataQualityRepository) ListRuns(ctx context.Context, limit int) ([]models.DataQualityRun, error) {
+	m.limit = limit
+	return m.runs, m.err
+}
+
+func getDataQuality(repo *mockDataQualityRepository, query string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin/data-quality", NewDataQualityHandler(repo).GetDataQuality)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/data-quality"+query, nil))
+	return w
+}
+
+func TestGetDataQuality(t *testing.T) {
+	now := time.Now().UTC()
+	repo := &mockDataQualityRepository{
+		scores: []models.CourseQuality{{CourseCode: "EECS1001", Term: "F", Score: 50, Issues: []string{models.DataIssueNoSections}, ScoredAt: now}},
+		runs:   []models.DataQualityRun{{ScoredAt: now, Courses: 1, AverageScore: 50, Issues: map[string]int{models.DataIssueNoSections: 1}}},
+	}
+
+	w := getDataQuality(repo, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, models.DataQualityFilter{Sort: models.DataQualitySortScore, MaxScore: 100, Limit: 100}, repo.filter)
+	assert.Equal(t, 10, repo.limit)
+
+	var body struct {
+		Data  []models.CourseQuality  `json:"data"`
+		Count int                     `json:"count"`
+		Trend []models.DataQualityRun `json:"trend"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, 1, body.Count)
+	assert.Nil(t, body.Data[0].PreviousScore)
+	assert.Equal(t, 1, body.Trend[0].Issues[models.DataIssueNoSections])
+
+	w = getDataQuality(repo, "?sort=change&issue=unparsed_times&department=eecs&term=W&max_score=80&limit=20&runs=30")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, models.DataQualityFilter{
+		Department: "EECS", Term: "W", Issue: models.DataIssueUnparsedTimes, MaxScore: 80, Sort: models.DataQualitySortChange, Limit: 20,
+	}, repo.filter)
+	assert.Equal(t, 30, repo.limit)
+}
+
+func TestGetDataQuality_Errors(t *testing.T) {
+	for _, query := range []string{"?sort=name", "?issue=typos", "?max_score=101", "?max_score=low"} {
+		w := getDataQuality(&mockDataQualityRepository{}, query)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+
+	w := getDataQuality(&mockDataQualityRepository{err: errors.New("db down")}, "")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
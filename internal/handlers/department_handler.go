@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+	"yuplan/internal/models"
+	"yuplan/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DepartmentHandler serves the department landing pages.
+type DepartmentHandler struct {
+	repo        repository.DepartmentRepositoryInterface
+	statsWindow time.Duration
+}
+
+func NewDepartmentHandler(repo repository.DepartmentRepositoryInterface) *DepartmentHandler {
+	return &DepartmentHandler{repo: repo, statsWindow: defaultStatsWindow}
+}
+
+// WithStatsWindow overrides how far back review stats look, matching course pages.
+func (h *DepartmentHandler) WithStatsWindow(window time.Duration) *DepartmentHandler {
+	h.statsWindow = window
+	return h
+}
+
+// GetStats handles GET /api/v1/departments/:department/stats, review stats rolled up
+// across a department's courses such as EECS.
+func (h *DepartmentHandler) GetStats(c *gin.Context) {
+	department, ok := models.NormalizeDepartment(c.Param("department"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "department must be a department code such as EECS"})
+		return
+	}
+
+	stats, err := h.repo.GetStats(c.Request.Context(), department, time.Now().UTC().Add(-h.statsWindow))
+	if err != nil {
+		serverError(c, err, "Failed to fetch department stats")
+		return
+	}
+	if stats == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No courses in department " + department})
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{"data": stats})
+}
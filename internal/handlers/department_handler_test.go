@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockDepartmentRepository struct {
+	stats      map[string]*models.DepartmentStats
+	err        error
+	department string
+	since      time.Time
+}
+
+func (m *mockDepartmentRepository) GetStats(ctx context.Context, department string, since time.Time) (*models.DepartmentStats, error) {
+	m.department, m.since = department, since
+	return m.stats[department], m.err
+}
+
+func newDepartmentRouter(repo *mockDepartmentRepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/departments/:department/stats", NewDepartmentHandler(repo).WithStatsWindow(24*time.Hour).GetStats)
+	return router
+}
+
+func TestGetDepartmentStats(t *testing.T) {
+	avg, pct := 3.2, 64
+	repo := &mockDepartmentRepository{stats: map[string]*models.DepartmentStats{
+		"EECS": {
+			Department: "EECS", Courses: 120, ReviewedCourses: 40, Reviews: 310,
+			AvgDifficulty: &avg, LikePercentage: &pct,
+			MostLiked:  []models.DepartmentCourseStats{{Code: "EECS1012", Reviews: 12, LikePercentage: 92}},
+			LeastLiked: []models.DepartmentCourseStats{{Code: "EECS2031", Reviews: 9, LikePercentage: 11}},
+		},
+	}}
+	router := newDepartmentRouter(repo)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/departments/eecs/stats", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "EECS", repo.department)
+	assert.WithinDuration(t, time.Now().Add(-24*time.Hour), repo.since, time.Minute)
+	assert.Contains(t, w.Body.String(), `"avg_difficulty":3.2`)
+	assert.Contains(t, w.Body.String(), `"most_liked":[{"code":"EECS1012"`)
+	assert.Contains(t, w.Body.String(), `"least_liked":[{"code":"EECS2031"`)
+}
+
+func TestGetDepartmentStats_Errors(t *testing.T) {
+	repo := &mockDepartmentRepository{}
+	router := newDepartmentRouter(repo)
+
+	tests := []struct {
+		path string
+		err  error
+		want int
+	}{
+		{"/departments/ZZZ/stats", nil, http.StatusNotFound},
+		{"/departments/EECS2030/stats", nil, http.StatusBadRequest},
+		{"/departments/EECS/stats", errors.New("db down"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		repo.err = tt.err
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		assert.Equal(t, tt.want, w.Code, tt.path)
+	}
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http"
+	"yuplan/internal/logging"
+	"yuplan/internal/models"
+	"yuplan/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// serverError responds to a failed repository or job call. Calls that ran out
+// of time get 504, and calls refused while the database circuit breaker is
+// open get 503, each with a distinct code so clients can tell them from
+// failures and retry; everything else is a 500 with the given message. The
+// error itself is only logged, tagged with the request ID.
+func serverError(c *gin.Context, err error, message string) {
+	logging.FromContext(c.Request.Context()).Error(message, "error", err, "path", c.Request.URL.Path)
+	if repository.IsUnavailable(err) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "The database is unavailable. Please try again shortly.",
+			"code":  models.ErrCodeUnavailable,
+		})
+		return
+	}
+	if repository.IsTimeout(err) {
+		c.JSON(http.StatusGatewayTimeout, gin.H{
+			"error": "Timed out waiting for the database",
+			"code":  models.ErrCodeTimeout,
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+}
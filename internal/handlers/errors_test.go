@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"yuplan/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServerError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+		wantBody string
+	}{
+		{"failure", errors.New("db down"), http.StatusInternalServerError, `{"error":"Failed to fetch courses"}`},
+		{"timeout", fmt.Errorf("query courses: %w", context.DeadlineExceeded), http.StatusGatewayTimeout,
+			`{"code":"timeout","error":"Timed out waiting for the database"}`},
+		{"circuit open", fmt.Errorf("query courses: %w", repository.ErrUnavailable), http.StatusServiceUnavailable,
+			`{"code":"unavailable","error":"The database is unavailable. Please try again shortly."}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/courses", nil)
+
+			serverError(c, tt.err, "Failed to fetch courses")
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			assert.JSONEq(t, tt.wantBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+	"yuplan/internal/exemption"
+	"yuplan/internal/models"
+	"yuplan/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// exemptionIssuer signs rate limit exemption tokens. Implemented by exemption.Signer.
+type exemptionIssuer interface {
+	Issue(name string, limit int, ttl time.Duration) (string, exemption.Claims, error)
+}
+
+type ExemptionHandler struct {
+	issuer exemptionIssuer // nil when RATE_LIMIT_EXEMPTION_SECRET is unset
+	audit  repository.AuditRepositoryInterface
+}
+
+func NewExemptionHandler(audit repository.AuditRepositoryInterface) *ExemptionHandler {
+	return &ExemptionHandler{audit: audit}
+}
+
+// WithIssuer enables issuing tokens.
+func (h *ExemptionHandler) WithIssuer(issuer exemptionIssuer) *ExemptionHandler {
+	h.issuer = issuer
+	return h
+}
+
+// IssueExemption handles POST /api/v1/admin/rate-limit/exemptions. The token
+// is only handed out once its issue is in the audit log.
+func (h *ExemptionHandler) IssueExemption(c *gin.Context) {
+	if h.issuer == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Rate limit exemptions are disabled"})
+		return
+	}
+
+	var req models.RateLimitExemptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	token, claims, err := h.issuer.Issue(req.Name, req.Limit, time.Duration(req.TTLMinutes)*time.Minute)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.audit.Record(c.Request.Context(), "rate_limit_exemption", claims.ID, "issue", gin.H{
+		"name":       claims.Name,
+		"limit":      claims.Limit,
+		"expires_at": claims.ExpiresAt,
+		"reason":     req.Reason,
+		"client_ip":  c.ClientIP(),
+	}); err != nil {
+		serverError(c, err, "Failed to record rate limit exemption")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"token":      token,
+		"header":     exemption.Header,
+		"id":         claims.ID,
+		"name":       claims.Name,
+		"limit":      claims.Limit,
+		"expires_at": claims.ExpiresAt,
+	})
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+	"yuplan/internal/exemption"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockAuditRepository struct {
+	err     error
+	entries []string
+}
+
+func (m *mockAuditRepository) Record(ctx context.Context, entity, entityID, action string, details any) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.entries = append(m.entries, entity+" "+entityID+" "+action)
+	return nil
+}
+
+func exemptionRouter(handler *ExemptionHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/admin/rate-limit/exemptions", handler.IssueExemption)
+	return router
+}
+
+func TestIssueExemption(t *testing.T) {
+	signer := exemption.NewSigner("secret", 24*time.Hour, 10000)
+	audit := &mockAuditRepository{}
+	router := exemptionRouter(NewExemptionHandler(audit).WithIssuer(signer))
+
+	w := serveSubscriptions(router, http.MethodPost, "/admin/rate-limit/exemptions",
+		`{"name": "k6 nightly", "limit": 5000, "ttl_minutes": 90, "reason": "release load test"}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var resp struct {
+		Token string `json:"token"`
+		ID    string `json:"id"`
+		Limit int    `json:"limit"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 5000, resp.Limit)
+	assert.Equal(t, []string{"rate_limit_exemption " + resp.ID + " issue"}, audit.entries)
+
+	claims, err := signer.Verify(resp.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, resp.ID, claims.ID)
+}
+
+func TestIssueExemption_Rejected(t *testing.T) {
+	signer := exemption.NewSigner("secret", 24*time.Hour, 10000)
+
+	tests := []struct {
+		name     string
+		handler  *ExemptionHandler
+		body     string
+		wantCode int
+	}{
+		{"disabled", NewExemptionHandler(&mockAuditRepository{}), `{"name": "k6", "limit": 10, "ttl_minutes": 10}`, http.StatusForbidden},
+		{"missing name", NewExemptionHandler(&mockAuditRepository{}).WithIssuer(signer), `{"limit": 10, "ttl_minutes": 10}`, http.StatusBadRequest},
+		{"over limit cap", NewExemptionHandler(&mockAuditRepository{}).WithIssuer(signer), `{"name": "k6", "limit": 20000, "ttl_minutes": 10}`, http.StatusBadRequest},
+		{"over ttl cap", NewExemptionHandler(&mockAuditRepository{}).WithIssuer(signer), `{"name": "k6", "limit": 10, "ttl_minutes": 1441}`, http.StatusBadRequest},
+		{"audit fails", NewExemptionHandler(&mockAuditRepository{err: errors.New("db down")}).WithIssuer(signer), `{"name": "k6", "limit": 10, "ttl_minutes": 10}`, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveSubscriptions(exemptionRouter(tt.handler), http.MethodPost, "/admin/rate-limit/exemptions", tt.body)
+			assert.Equal(t, tt.wantCode, w.Code)
+			assert.NotContains(t, w.Body.String(), "token")
+		})
+	}
+}
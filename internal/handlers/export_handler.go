@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"yuplan/internal/export"
+
+	"github.com/gin-gonic/gin"
+)
+
+type exportRunner interface {
+	Run(ctx context.Context) ([]export.Object, error)
+	List(ctx context.Context) ([]export.Object, error)
+}
+
+type ExportHandler struct {
+	exporter exportRunner
+}
+
+// NewExportHandler accepts a nil exporter when no export store is configured.
+func NewExportHandler(exporter exportRunner) *ExportHandler {
+	return &ExportHandler{exporter: exporter}
+}
+
+// TriggerExport handles POST /api/v1/admin/exports
+func (h *ExportHandler) TriggerExport(c *gin.Context) {
+	if h.exporter == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Exports are not configured"})
+		return
+	}
+
+	written, err := h.exporter.Run(c.Request.Context())
+	if err != nil {
+		serverError(c, err, "Failed to run export")
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"data":  written,
+		"count": len(written),
+	})
+}
+
+// ListExports handles GET /api/v1/admin/exports
+func (h *ExportHandler) ListExports(c *gin.Context) {
+	if h.exporter == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Exports are not configured"})
+		return
+	}
+
+	objects, err := h.exporter.List(c.Request.Context())
+	if err != nil {
+		serverError(c, err, "Failed to list exports")
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"data":  objects,
+		"count": len(objects),
+	})
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"yuplan/internal/export"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockExporter struct {
+	runFunc  func(ctx context.Context) ([]export.Object, error)
+	listFunc func(ctx context.Context) ([]export.Object, error)
+}
+
+func (m *mockExporter) Run(ctx context.Context) ([]export.Object, error) {
+	if m.runFunc != nil {
+		return m.runFunc(ctx)
+	}
+	return []export.Object{}, nil
+}
+
+func (m *mockExporter) List(ctx context.Context) ([]export.Object, error) {
+	if m.listFunc != nil {
+		return m.listFunc(ctx)
+	}
+	return []export.Object{}, nil
+}
+
+func setupExportRouter(handler *ExportHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/admin/exports", handler.TriggerExport)
+	router.GET("/admin/exports", handler.ListExports)
+	return router
+}
+
+func TestTriggerExport(t *testing.T) {
+	tests := []struct {
+		name           string
+		exporter       exportRunner
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			exporter: &mockExporter{runFunc: func(ctx context.Context) ([]export.Object, error) {
+				return []export.Object{{Key: "reviews/2026-03-01.ndjson.gz"}}, nil
+			}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "exporter error",
+			exporter: &mockExporter{runFunc: func(ctx context.Context) ([]export.Object, error) {
+				return nil, errors.New("s3 down")
+			}},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "not configured",
+			exporter:       nil,
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := setupExportRouter(NewExportHandler(tt.exporter))
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/admin/exports", nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestListExports(t *testing.T) {
+	exporter := &mockExporter{listFunc: func(ctx context.Context) ([]export.Object, error) {
+		return []export.Object{{Key: "reviews/2026-03-01.ndjson.gz"}, {Key: "audit_log/2026-03-01.ndjson.gz"}}, nil
+	}}
+	router := setupExportRouter(NewExportHandler(exporter))
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/admin/exports", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"count":2`)
+}
+
+func TestListExports_Error(t *testing.T) {
+	exporter := &mockExporter{listFunc: func(ctx context.Context) ([]export.Object, error) {
+		return nil, errors.New("boom")
+	}}
+	router := setupExportRouter(NewExportHandler(exporter))
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/admin/exports", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"yuplan/internal/id"
+	"yuplan/internal/models"
+	"yuplan/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FilterPresetHandler struct {
+	repo repository.FilterPresetRepositoryInterface
+}
+
+func NewFilterPresetHandler(repo repository.FilterPresetRepositoryInterface) *FilterPresetHandler {
+	return &FilterPresetHandler{repo: repo}
+}
+
+// GetPresets handles GET /api/v1/users/me/filters?email=
+func (h *FilterPresetHandler) GetPresets(c *gin.Context) {
+	email, ok := emailQuery(c)
+	if !ok {
+		return
+	}
+
+	presets, err := h.repo.List(c.Request.Context(), email)
+	if err != nil {
+		serverError(c, err, "Failed to fetch filter presets")
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"data":  presets,
+		"count": len(presets),
+	})
+}
+
+// CreatePreset handles POST /api/v1/users/me/filters
+// Body: {"email": "student@my.yorku.ca", "name": "3000-level EECS", "faculty": "LE", "course_code_range": "3000s", "term_id": "FW2025"}
+func (h *FilterPresetHandler) CreatePreset(c *gin.Context) {
+	preset, ok := bindPreset(c)
+	if !ok {
+		return
+	}
+
+	err := h.repo.Create(c.Request.Context(), preset)
+	if errors.Is(err, repository.ErrDuplicatePresetName) {
+		c.JSON(http.StatusConflict, gin.H{"error": "You already have a filter preset named " + preset.Name})
+		return
+	}
+	if err != nil {
+		serverError(c, err, "Failed to save filter preset")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": preset})
+}
+
+// UpdatePreset handles PUT /api/v1/users/me/filters/:id with the same body as
+// CreatePreset. The email must be the preset's owner.
+func (h *FilterPresetHandler) UpdatePreset(c *gin.Context) {
+	presetID, ok := presetIDParam(c)
+	if !ok {
+		return
+	}
+	preset, ok := bindPreset(c)
+	if !ok {
+		return
+	}
+	preset.ID = presetID
+
+	updated, err := h.repo.Update(c.Request.Context(), preset)
+	if errors.Is(err, repository.ErrDuplicatePresetName) {
+		c.JSON(http.StatusConflict, gin.H{"error": "You already have a filter preset named " + preset.Name})
+		return
+	}
+	if err != nil {
+		serverError(c, err, "Failed to update filter preset")
+		return
+	}
+	if !updated {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No such filter preset for that email"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": preset})
+}
+
+// DeletePreset handles DELETE /api/v1/users/me/filters/:id?email=
+func (h *FilterPresetHandler) DeletePreset(c *gin.Context) {
+	presetID, ok := presetIDParam(c)
+	if !ok {
+		return
+	}
+	email, ok := emailQuery(c)
+	if !ok {
+		return
+	}
+
+	removed, err := h.repo.Delete(c.Request.Context(), presetID, email)
+	if err != nil {
+		serverError(c, err, "Failed to delete filter preset")
+		return
+	}
+	if !removed {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No such filter preset for that email"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Filter preset deleted"})
+}
+
+func bindPreset(c *gin.Context) (*models.FilterPreset, bool) {
+	var req models.FilterPresetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if problem := req.Normalize(); problem != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+		return nil, false
+	}
+	return &models.FilterPreset{
+		Email:           req.Email,
+		Name:            req.Name,
+		Faculty:         req.Faculty,
+		CourseCodeRange: req.CourseCodeRange,
+		TermID:          req.TermID,
+	}, true
+}
+
+func presetIDParam(c *gin.Context) (string, bool) {
+	presetID := c.Param("id")
+	if !id.Valid(presetID) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id must be a UUID", "code": models.ErrCodeInvalidID})
+		return "", false
+	}
+	return presetID, true
+}
@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+	"yuplan/internal/models"
+	"yuplan/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+const testPresetID = "6f1c2d3e-4b5a-4c6d-8e7f-9a0b1c2d3e4f"
+
+type mockFilterPresetRepository struct {
+	presets map[string]*models.FilterPreset
+	err     error
+
+	saved   *models.FilterPreset
+	deleted []string // id, email
+}
+
+func (m *mockFilterPresetRepository) List(ctx context.Context, email string) ([]models.FilterPreset, error) {
+	presets := []models.FilterPreset{}
+	for _, p := range m.presets {
+		if p.Email == email {
+			presets = append(presets, *p)
+		}
+	}
+	return presets, m.err
+}
+
+func (m *mockFilterPresetRepository) Get(ctx context.Context, id string) (*models.FilterPreset, error) {
+	return m.presets[id], m.err
+}
+
+func (m *mockFilterPresetRepository) Create(ctx context.Context, preset *models.FilterPreset) error {
+	if m.err != nil {
+		return m.err
+	}
+	preset.ID = testPresetID
+	preset.CreatedAt = time.Now()
+	m.saved = preset
+	return nil
+}
+
+func (m *mockFilterPresetRepository) Update(ctx context.Context, preset *models.FilterPreset) (bool, error) {
+	existing := m.presets[preset.ID]
+	if m.err != nil || existing == nil || existing.Email != preset.Email {
+		return false, m.err
+	}
+	m.saved = preset
+	return true, nil
+}
+
+func (m *mockFilterPresetRepository) Delete(ctx context.Context, id, email string) (bool, error) {
+	m.deleted = []string{id, email}
+	existing := m.presets[id]
+	return existing != nil && existing.Email == email, m.err
+}
+
+func newFilterPresetRouter(repo *mockFilterPresetRepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewFilterPresetHandler(repo)
+	router := gin.New()
+	router.GET("/users/me/filters", handler.GetPresets)
+	router.POST("/users/me/filters", handler.CreatePreset)
+	router.PUT("/users/me/filters/:id", handler.UpdatePreset)
+	router.DELETE("/users/me/filters/:id", handler.DeletePreset)
+	return router
+}
+
+func TestGetPresets(t *testing.T) {
+	repo := &mockFilterPresetRepository{presets: map[string]*models.FilterPreset{
+		testPresetID: {ID: testPresetID, Email: "a@yorku.ca", Name: "3000-level EECS"},
+	}}
+	router := newFilterPresetRouter(repo)
+
+	w := serveSubscriptions(router, http.MethodGet, "/users/me/filters?email=a@yorku.ca", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"3000-level EECS"`)
+	assert.Contains(t, w.Body.String(), `"count":1`)
+
+	w = serveSubscriptions(router, http.MethodGet, "/users/me/filters", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCreatePreset(t *testing.T) {
+	repo := &mockFilterPresetRepository{}
+	router := newFilterPresetRouter(repo)
+
+	w := serveSubscriptions(router, http.MethodPost, "/users/me/filters",
+		`{"email": "a@yorku.ca", "name": " 3000-level ", "faculty": "le", "course_code_range": "3000s", "term_id": "fw2025"}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), testPresetID)
+	assert.Equal(t, &models.FilterPreset{
+		ID: testPresetID, Email: "a@yorku.ca", Name: "3000-level", Faculty: "LE", CourseCodeRange: "3000s", TermID: "FW2025",
+		CreatedAt: repo.saved.CreatedAt,
+	}, repo.saved)
+
+	for _, body := range []string{
+		`{"email": "a@yorku.ca"}`,
+		`{"email": "not-an-email", "name": "x"}`,
+		`{"email": "a@yorku.ca", "name": "x", "course_code_range": "3050s"}`,
+		`{"email": "a@yorku.ca", "name": "x", "term_id": "2025"}`,
+	} {
+		w = serveSubscriptions(router, http.MethodPost, "/users/me/filters", body)
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+
+	repo.err = repository.ErrDuplicatePresetName
+	w = serveSubscriptions(router, http.MethodPost, "/users/me/filters", `{"email": "a@yorku.ca", "name": "3000-level"}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestUpdatePreset(t *testing.T) {
+	repo := &mockFilterPresetRepository{presets: map[string]*models.FilterPreset{
+		testPresetID: {ID: testPresetID, Email: "a@yorku.ca", Name: "Old"},
+	}}
+	router := newFilterPresetRouter(repo)
+
+	w := serveSubscriptions(router, http.MethodPut, "/users/me/filters/"+testPresetID,
+		`{"email": "a@yorku.ca", "name": "New", "course_code_range": "5000s+"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "New", repo.saved.Name)
+	assert.Equal(t, "5000s+", repo.saved.CourseCodeRange)
+
+	w = serveSubscriptions(router, http.MethodPut, "/users/me/filters/"+testPresetID, `{"email": "b@yorku.ca", "name": "New"}`)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = serveSubscriptions(router, http.MethodPut, "/users/me/filters/not-a-uuid", `{"email": "a@yorku.ca", "name": "New"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestDeletePreset(t *testing.T) {
+	repo := &mockFilterPresetRepository{presets: map[string]*models.FilterPreset{
+		testPresetID: {ID: testPresetID, Email: "a@yorku.ca", Name: "Old"},
+	}}
+	router := newFilterPresetRouter(repo)
+
+	w := serveSubscriptions(router, http.MethodDelete, "/users/me/filters/"+testPresetID+"?email=b@yorku.ca", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = serveSubscriptions(router, http.MethodDelete, "/users/me/filters/"+testPresetID+"?email=a@yorku.ca", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{testPresetID, "a@yorku.ca"}, repo.deleted)
+
+	w = serveSubscriptions(router, http.MethodDelete, "/users/me/filters/"+testPresetID, "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetCourses_Preset(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var courseRepo repository.CourseRepositoryInterface = &MockCourseRepository{
+		getPaginatedCourses: func(ctx context.Context, page, pageSize int, faculty, courseCodeRange, termID *string, sorts []models.CourseSort) ([]models.Course, error) {
+			assert.Nil(t, faculty)
+			assert.Equal(t, "3000s", *courseCodeRange)
+			assert.Equal(t, "FW2025", *termID)
+			assert.Equal(t, 2, page)
+			return []models.Course{{ID: "1", Code: "EECS3101", Name: "Design and Analysis of Algorithms"}}, nil
+		},
+		getCoursesCount: func(ctx context.Context, faculty, courseCodeRange, termID *string) (int, error) {
+			return 21, nil
+		},
+	}
+	presets := &mockFilterPresetRepository{presets: map[string]*models.FilterPreset{
+		testPresetID: {ID: testPresetID, Email: "a@yorku.ca", Name: "3000-level", CourseCodeRange: "3000s", TermID: "FW2025"},
+	}}
+	handler := NewCourseHandler(courseRepo, nil).WithPresets(presets)
+	router := gin.New()
+	router.GET("/courses", handler.GetCourses)
+
+	w := serveSubscriptions(router, http.MethodGet, "/courses?preset="+testPresetID+"&page=2", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "EECS3101")
+	assert.Contains(t, w.Body.String(), `"total_pages":2`)
+
+	w = serveSubscriptions(router, http.MethodGet, "/courses?preset=6f1c2d3e-0000-4c6d-8e7f-9a0b1c2d3e4f", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = serveSubscriptions(router, http.MethodGet, "/courses?preset=mine", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
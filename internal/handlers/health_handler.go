@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// readinessTimeout bounds each dependency check, well inside a probe's own timeout.
+const readinessTimeout = 2 * time.Second
+
+// Dependency statuses reported by GET /readyz.
+const (
+	dependencyOK          = "ok"
+	dependencyUnavailable = "unavailable"
+)
+
+// pinger checks a dependency can be reached. Implemented by pgxpool.Pool and cache.Store.
+type pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// HealthHandler answers the liveness and readiness probes.
+type HealthHandler struct {
+	db    pinger
+	cache pinger
+}
+
+func NewHealthHandler(db pinger) *HealthHandler {
+	return &HealthHandler{db: db}
+}
+
+// WithCache reports the catalog cache in readiness. It never makes the
+// service unready, since a failing cache is skipped for the database.
+func (h *HealthHandler) WithCache(cache pinger) *HealthHandler {
+	h.cache = cache
+	return h
+}
+
+// Live handles GET /healthz. It only shows the process is serving requests.
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Ready handles GET /readyz: 200 when the database answers a ping, 503
+// otherwise, with the status of each dependency. Errors are logged rather
+// than returned, since the probe is public.
+func (h *HealthHandler) Ready(c *gin.Context) {
+	checks := gin.H{"database": h.check(c.Request.Context(), "database", h.db)}
+	if h.cache != nil {
+		checks["cache"] = h.check(c.Request.Context(), "cache", h.cache)
+	}
+
+	if checks["database"] != dependencyOK {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "unavailable", "checks": checks})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ready", "checks": checks})
+}
+
+func (h *HealthHandler) check(ctx context.Context, name string, dependency pinger) string {
+	ctx, cancel := context.WithTimeout(ctx, readinessTimeout)
+	defer cancel()
+	if err := dependency.Ping(ctx); err != nil {
+		log.Printf("readiness: %s: %v", name, err)
+		return dependencyUnavailable
+	}
+	return dependencyOK
+}
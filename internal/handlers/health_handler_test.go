@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockPinger struct{ err error }
+
+func (m mockPinger) Ping(ctx context.Context) error { return m.err }
+
+func serveHealth(handler *HealthHandler, path string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/healthz", handler.Live)
+	router.GET("/readyz", handler.Ready)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+func TestHealthz(t *testing.T) {
+	// Liveness never touches the database
+	w := serveHealth(NewHealthHandler(mockPinger{err: errors.New("down")}), "/healthz")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status": "ok"}`, w.Body.String())
+}
+
+func TestReadyz(t *testing.T) {
+	tests := []struct {
+		name     string
+		db       error
+		cache    error
+		wantCode int
+		wantBody string
+	}{
+		{"all up", nil, nil, http.StatusOK,
+			`{"status": "ready", "checks": {"database": "ok", "cache": "ok"}}`},
+		{"cache down", nil, errors.New("connection refused"), http.StatusOK,
+			`{"status": "ready", "checks": {"database": "ok", "cache": "unavailable"}}`},
+		{"database down", errors.New("dial tcp 10.0.0.5:5432: connection refused"), nil, http.StatusServiceUnavailable,
+			`{"status": "unavailable", "checks": {"database": "unavailable", "cache": "ok"}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHealthHandler(mockPinger{err: tt.db}).WithCache(mockPinger{err: tt.cache})
+			w := serveHealth(handler, "/readyz")
+			assert.Equal(t, tt.wantCode, w.Code)
+			assert.JSONEq(t, tt.wantBody, w.Body.String())
+		})
+	}
+
+	w := serveHealth(NewHealthHandler(mockPinger{}), "/readyz")
+	assert.JSONEq(t, `{"status": "ready", "checks": {"database": "ok"}}`, w.Body.String())
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// include is a related resource a client can embed in a response with
+// ?include=a,b (JSON:API style) instead of calling a dedicated endpoint.
+type include struct {
+	name    string
+	cost    int  // database round trips added per item in the response
+	batched bool // cost is per request rather than per item
+}
+
+// maxIncludeCost caps the round trips one request's includes may add, so a
+// large page asking for everything can't fan out into hundreds of queries.
+const maxIncludeCost = 100
+
+// parseIncludes reads ?include= against the includes an endpoint offers for a
+// response of items entries. Unknown names and combinations costing more than
+// maxIncludeCost are errors meant for the client.
+func parseIncludes(c *gin.Context, offered []include, items int) (map[string]bool, error) {
+	byName := make(map[string]include, len(offered))
+	for _, inc := range offered {
+		byName[inc.name] = inc
+	}
+
+	requested := map[string]bool{}
+	cost := 0
+	for _, name := range strings.Split(c.Query("include"), ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || requested[name] {
+			continue
+		}
+		inc, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("Unknown include %q; available: %s", name, includeNames(offered))
+		}
+		requested[name] = true
+		if inc.batched {
+			cost += inc.cost
+		} else {
+			cost += inc.cost * items
+		}
+	}
+
+	if cost > maxIncludeCost {
+		return nil, fmt.Errorf("include=%s is too expensive for %d items; request fewer items or fewer includes", c.Query("include"), items)
+	}
+	return requested, nil
+}
+
+func includeNames(offered []include) string {
+	names := make([]string, len(offered))
+	for i, inc := range offered {
+		names[i] = inc.name
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
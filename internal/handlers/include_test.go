@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseIncludes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	offered := []include{
+		{name: "sections", cost: 4},
+		{name: "stats", cost: 1, batched: true},
+	}
+
+	tests := []struct {
+		name    string
+		query   string
+		items   int
+		want    map[string]bool
+		wantErr string
+	}{
+		{name: "none", query: "", items: 50, want: map[string]bool{}},
+		{name: "trims and dedupes", query: "?include=sections,%20sections,,stats", items: 20, want: map[string]bool{"sections": true, "stats": true}},
+		{name: "batched ignores item count", query: "?include=stats", items: 1000, want: map[string]bool{"stats": true}},
+		{name: "at the limit", query: "?include=sections", items: maxIncludeCost / 4, want: map[string]bool{"sections": true}},
+		{name: "over the limit", query: "?include=sections", items: maxIncludeCost/4 + 1, wantErr: "too expensive"},
+		{name: "unknown", query: "?include=reviews", items: 1, wantErr: `Unknown include "reviews"; available: sections, stats`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("GET", "/courses"+tt.query, nil)
+
+			got, err := parseIncludes(c, offered, tt.items)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
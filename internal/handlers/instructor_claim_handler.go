@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"yuplan/internal/claims"
+	"yuplan/internal/id"
+	"yuplan/internal/models"
+	"yuplan/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// instructorClaims takes instructors' claims on their profiles and decides
+// them. Implemented by claims.Service.
+type instructorClaims interface {
+	Request(ctx context.Context, instructorID, email string) (*models.InstructorClaim, error)
+	Verify(ctx context.Context, token string) (*models.InstructorClaim, error)
+	List(ctx context.Context, status string) ([]models.InstructorClaim, error)
+	Approve(ctx context.Context, id string) (*models.InstructorClaim, error)
+	Reject(ctx context.Context, id string) (*models.InstructorClaim, error)
+}
+
+type InstructorClaimHandler struct {
+	claims instructorClaims
+}
+
+// NewInstructorClaimHandler returns a handler answering 404 to every claim
+// route when claims is nil, as it is without ADMIN_JWT_SECRET to sign the
+// instructor role's tokens with.
+func NewInstructorClaimHandler(claims instructorClaims) *InstructorClaimHandler {
+	return &InstructorClaimHandler{claims: claims}
+}
+
+func (h *InstructorClaimHandler) enabled(c *gin.Context) bool {
+	if h.claims == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Instructor claims are not enabled"})
+		return false
+	}
+	return true
+}
+
+// RequestClaim handles POST /api/v1/instructors/:instructor_id/claims
+// Body: {"email": "jwang@yorku.ca"}
+// The email must be at an institutional domain; it is mailed a link to
+// confirm the claim, which then waits for an admin. As with instructor
+// reviews, gin names the id course_id.
+func (h *InstructorClaimHandler) RequestClaim(c *gin.Context) {
+	if !h.enabled(c) {
+		return
+	}
+	instructorID := c.Param("course_id")
+	if !id.Valid(instructorID) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "instructor_id must be a UUID", "code": models.ErrCodeInvalidID})
+		return
+	}
+	var req models.CreateInstructorClaimRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	claim, err := h.claims.Request(c.Request.Context(), instructorID, req.Email)
+	switch {
+	case errors.Is(err, claims.ErrEmailDomain):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Claims must be made with your institutional email"})
+		return
+	case errors.Is(err, repository.ErrInstructorClaimed):
+		c.JSON(http.StatusConflict, gin.H{"error": "This instructor's profile has already been claimed"})
+		return
+	case err != nil:
+		serverError(c, err, "Failed to create claim")
+		return
+	case claim == nil:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Instructor not found"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"data":    gin.H{"id": claim.ID, "status": claim.Status},
+		"message": "Check your email for a link to confirm your claim",
+	})
+}
+
+// VerifyClaim handles GET /api/v1/instructor-claims/verify?token=, the link
+// mailed with a claim. The claim then waits for an admin.
+func (h *InstructorClaimHandler) VerifyClaim(c *gin.Context) {
+	if !h.enabled(c) {
+		return
+	}
+	claim, err := h.claims.Verify(c.Request.Context(), c.Query("token"))
+	if err != nil {
+		serverError(c, err, "Failed to verify claim")
+		return
+	}
+	if claim == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "This link is invalid or has expired; claim the profile again"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":    gin.H{"id": claim.ID, "status": claim.Status},
+		"message": "Email confirmed; an admin will review your claim",
+	})
+}
+
+// ListClaims handles GET /api/v1/admin/instructor-claims?status=pending
+// status defaults to pending, the claims waiting for a decision; "all" lists
+// every claim.
+func (h *InstructorClaimHandler) ListClaims(c *gin.Context) {
+	if !h.enabled(c) {
+		return
+	}
+	status := c.DefaultQuery("status", models.ClaimPending)
+	if status == "all" {
+		status = ""
+	} else if !slices.Contains(models.ClaimStatuses, status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unknown status %q", status)})
+		return
+	}
+
+	list, err := h.claims.List(c.Request.Context(), status)
+	if err != nil {
+		serverError(c, err, "Failed to fetch claims")
+		return
+	}
+	respond(c, http.StatusOK, gin.H{
+		"data":  list,
+		"count": len(list),
+	})
+}
+
+// ApproveClaim handles POST /api/v1/admin/instructor-claims/:id/approve
+// The instructor is mailed a token for the instructor role. Approving an
+// approved claim again mails a fresh one.
+func (h *InstructorClaimHandler) ApproveClaim(c *gin.Context) {
+	if !h.enabled(c) {
+		return
+	}
+	claim, err := h.claims.Approve(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		serverError(c, err, "Failed to approve claim")
+		return
+	}
+	if claim == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No pending claim with that id, or its instructor is already claimed"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Claim approved; the instructor was mailed their token"})
+}
+
+// RejectClaim handles POST /api/v1/admin/instructor-claims/:id/reject
+func (h *InstructorClaimHandler) RejectClaim(c *gin.Context) {
+	if !h.enabled(c) {
+		return
+	}
+	claim, err := h.claims.Reject(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		serverError(c, err, "Failed to reject claim")
+		return
+	}
+	if claim == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No pending claim with that id"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Claim rejected"})
+}
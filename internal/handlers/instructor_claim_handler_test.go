@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"yuplan/internal/claims"
+	"yuplan/internal/models"
+	"yuplan/internal/redact"
+	"yuplan/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeInstructorClaims struct {
+	claims   []models.InstructorClaim
+	listedAs string
+	err      error
+}
+
+func (f *fakeInstructorClaims) Request(ctx context.Context, instructorID, email string) (*models.InstructorClaim, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if !strings.HasSuffix(email, "@yorku.ca") {
+		return nil, claims.ErrEmailDomain
+	}
+	if instructorID != reportedInstructor {
+		return nil, nil
+	}
+	return &models.InstructorClaim{ID: "claim-1", Email: email, Status: models.ClaimUnverified}, nil
+}
+
+func (f *fakeInstructorClaims) Verify(ctx context.Context, token string) (*models.InstructorClaim, error) {
+	if token != "good" {
+		return nil, f.err
+	}
+	return &models.InstructorClaim{ID: "claim-1", Status: models.ClaimPending}, nil
+}
+
+func (f *fakeInstructorClaims) List(ctx context.Context, status string) ([]models.InstructorClaim, error) {
+	f.listedAs = status
+	return f.claims, f.err
+}
+
+func (f *fakeInstructorClaims) Approve(ctx context.Context, id string) (*models.InstructorClaim, error) {
+	return f.decide(id, models.ClaimApproved)
+}
+
+func (f *fakeInstructorClaims) Reject(ctx context.Context, id string) (*models.InstructorClaim, error) {
+	return f.decide(id, models.ClaimRejected)
+}
+
+func (f *fakeInstructorClaims) decide(id, status string) (*models.InstructorClaim, error) {
+	for i := range f.claims {
+		if f.claims[i].ID == id && f.claims[i].Status == models.ClaimPending {
+			f.claims[i].Status = status
+			return &f.claims[i], nil
+		}
+	}
+	return nil, f.err
+}
+
+func newInstructorClaimRouter(handler *InstructorClaimHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/instructors/:course_id/claims", handler.RequestClaim)
+	router.GET("/instructor-claims/verify", handler.VerifyClaim)
+	admin := router.Group("/admin", func(c *gin.Context) {
+		c.Request = c.Request.WithContext(redact.WithRole(c.Request.Context(), redact.RoleAdmin))
+	})
+	admin.GET("/instructor-claims", handler.ListClaims)
+	admin.POST("/instructor-claims/:id/approve", handler.ApproveClaim)
+	admin.POST("/instructor-claims/:id/reject", handler.RejectClaim)
+	return router
+}
+
+func TestRequestClaim(t *testing.T) {
+	tests := []struct {
+		name       string
+		instructor string
+		body       string
+		err        error
+		wantStatus int
+	}{
+		{"claimed", reportedInstructor, `{"email":"jwang@yorku.ca"}`, nil, http.StatusAccepted},
+		{"student email", reportedInstructor, `{"email":"jwang@my.yorku.ca"}`, nil, http.StatusUnprocessableEntity},
+		{"no email", reportedInstructor, `{}`, nil, http.StatusBadRequest},
+		{"bad id", "not-a-uuid", `{"email":"jwang@yorku.ca"}`, nil, http.StatusBadRequest},
+		{"unknown instructor", "7c9e6679-7425-40de-944b-e07fc1f90ae7", `{"email":"jwang@yorku.ca"}`, nil, http.StatusNotFound},
+		{"already claimed", reportedInstructor, `{"email":"jwang@yorku.ca"}`, repository.ErrInstructorClaimed, http.StatusConflict},
+		{"mail down", reportedInstructor, `{"email":"jwang@yorku.ca"}`, errors.New("smtp down"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newInstructorClaimRouter(NewInstructorClaimHandler(&fakeInstructorClaims{err: tt.err}))
+			w := serveReports(router, http.MethodPost, "/instructors/"+tt.instructor+"/claims", tt.body)
+			assert.Equal(t, tt.wantStatus, w.Code, w.Body.String())
+		})
+	}
+}
+
+func TestVerifyClaim(t *testing.T) {
+	router := newInstructorClaimRouter(NewInstructorClaimHandler(&fakeInstructorClaims{}))
+
+	w := serveReports(router, http.MethodGet, "/instructor-claims/verify?token=good", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"pending"`)
+
+	w = serveReports(router, http.MethodGet, "/instructor-claims/verify?token=stale", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestListClaims(t *testing.T) {
+	fake := &fakeInstructorClaims{claims: []models.InstructorClaim{
+		{ID: "claim-1", FirstName: "Jackie", LastName: "Wang", Email: "jwang@yorku.ca", Status: models.ClaimPending},
+	}}
+	router := newInstructorClaimRouter(NewInstructorClaimHandler(fake))
+
+	w := serveReports(router, http.MethodGet, "/admin/instructor-claims", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, models.ClaimPending, fake.listedAs)
+	var body struct {
+		Data  []models.InstructorClaim `json:"data"`
+		Count int                      `json:"count"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, 1, body.Count)
+	assert.Equal(t, "jwang@yorku.ca", body.Data[0].Email)
+
+	w = serveReports(router, http.MethodGet, "/admin/instructor-claims?status=all", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "", fake.listedAs)
+
+	w = serveReports(router, http.MethodGet, "/admin/instructor-claims?status=maybe", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestDecideClaim(t *testing.T) {
+	fake := &fakeInstructorClaims{claims: []models.InstructorClaim{
+		{ID: "claim-1", Status: models.ClaimPending},
+		{ID: "claim-2", Status: models.ClaimPending},
+	}}
+	router := newInstructorClaimRouter(NewInstructorClaimHandler(fake))
+
+	w := serveReports(router, http.MethodPost, "/admin/instructor-claims/claim-1/approve", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, models.ClaimApproved, fake.claims[0].Status)
+
+	w = serveReports(router, http.MethodPost, "/admin/instructor-claims/claim-2/reject", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, models.ClaimRejected, fake.claims[1].Status)
+
+	w = serveReports(router, http.MethodPost, "/admin/instructor-claims/claim-2/reject", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestInstructorClaims_Disabled(t *testing.T) {
+	router := newInstructorClaimRouter(NewInstructorClaimHandler(nil))
+
+	w := serveReports(router, http.MethodPost, "/instructors/"+reportedInstructor+"/claims", `{"email":"jwang@yorku.ca"}`)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = serveReports(router, http.MethodGet, "/admin/instructor-claims", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
+	"yuplan/internal/models"
 	"yuplan/internal/repository"
 
 	"github.com/gin-gonic/gin"
@@ -15,18 +17,140 @@ func NewInstructorHandler(repo repository.InstructorRepositoryInterface) *Instru
 	return &InstructorHandler{repo: repo}
 }
 
+// ListInstructors handles GET /api/v1/instructors?ids=..., the instructors
+// with the given comma-separated ids in one lookup, so a schedule can show
+// every section's instructor without a request per course. Ids that match no
+// instructor are left out.
+func (h *InstructorHandler) ListInstructors(c *gin.Context) {
+	ids, err := queryIDs(c, "ids")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": models.ErrCodeInvalidID})
+		return
+	}
+	if len(ids) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter 'ids' is required"})
+		return
+	}
+	if len(ids) > models.MaxInstructorIDs {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("At most %d ids per request", models.MaxInstructorIDs)})
+		return
+	}
+
+	instructors, err := h.repo.GetByIDs(c.Request.Context(), ids)
+	if err != nil {
+		serverError(c, err, "Failed to fetch instructors")
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"data":  instructors,
+		"count": len(instructors),
+	})
+}
+
+// GetInstructorsByCourseID handles GET /api/v1/instructors/:course_id, the
+// instructors of a course's sections.
 func (h *InstructorHandler) GetInstructorsByCourseID(c *gin.Context) {
 	courseID := c.Param("course_id")
 
 	instructors, err := h.repo.GetByCourseID(c.Request.Context(), courseID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch instructors"})
+		serverError(c, err, "Failed to fetch instructors")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	respond(c, http.StatusOK, gin.H{
 		"data":  instructors,
 		"count": len(instructors),
 	})
 }
 
+// GetInstructorProfile handles GET /api/v1/instructors/:instructor_id/profile,
+// the instructor's profile gathered across every section taught under their
+// name. Like the schedule, the route shares its wildcard with
+// /instructors/:course_id.
+func (h *InstructorHandler) GetInstructorProfile(c *gin.Context) {
+	profile, err := h.repo.GetProfile(c.Request.Context(), c.Param("course_id"))
+	if err != nil {
+		serverError(c, err, "Failed to fetch instructor")
+		return
+	}
+	if profile == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Instructor not found"})
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{"data": profile})
+}
+
+// GetInstructorCourses handles GET /api/v1/instructors/:instructor_id/courses,
+// every course offering the instructor teaches with their sections in it. Like
+// the schedule, the route shares its wildcard with /instructors/:course_id.
+func (h *InstructorHandler) GetInstructorCourses(c *gin.Context) {
+	id := c.Param("course_id")
+
+	instructor, err := h.repo.GetByID(c.Request.Context(), id)
+	if err != nil {
+		serverError(c, err, "Failed to fetch instructor")
+		return
+	}
+	if instructor == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Instructor not found"})
+		return
+	}
+
+	courses, err := h.repo.ListCourses(c.Request.Context(), id)
+	if err != nil {
+		serverError(c, err, "Failed to fetch instructor courses")
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"data":  courses,
+		"count": len(courses),
+	})
+}
+
+// GetInstructorSchedule handles GET /api/v1/instructors/:instructor_id/schedule?term=F,
+// the instructor's weekly teaching meetings as a Monday-to-Sunday grid. The
+// route shares its wildcard with /instructors/:course_id, so gin names the
+// id course_id.
+func (h *InstructorHandler) GetInstructorSchedule(c *gin.Context) {
+	id := c.Param("course_id")
+	term := c.Query("term")
+	if term != "" && !isTerm(term) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown term " + term})
+		return
+	}
+
+	instructor, err := h.repo.GetByID(c.Request.Context(), id)
+	if err != nil {
+		serverError(c, err, "Failed to fetch instructor")
+		return
+	}
+	if instructor == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Instructor not found"})
+		return
+	}
+
+	activities, err := h.repo.ListTeachingActivities(c.Request.Context(), id, term)
+	if err != nil {
+		serverError(c, err, "Failed to fetch instructor schedule")
+		return
+	}
+
+	days := models.WeekGrid(activities)
+	count := 0
+	for _, d := range days {
+		count += len(d.Meetings)
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"data": gin.H{
+			"instructor": instructor,
+			"term":       term,
+			"days":       days,
+		},
+		"count": count,
+	})
+}
@@ -3,11 +3,13 @@ package handlers
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
+	"yuplan/internal/dbtypes"
 	"yuplan/internal/models"
 	"yuplan/internal/repository"
 
@@ -16,7 +18,13 @@ import (
 )
 
 type MockInstructorRepository struct {
-	getByCourseID func(ctx context.Context, courseID string) ([]models.Instructor, error)
+	getByCourseID          func(ctx context.Context, courseID string) ([]models.Instructor, error)
+	getByID                func(ctx context.Context, id string) (*models.Instructor, error)
+	getByIDs               func(ctx context.Context, ids []string) ([]models.Instructor, error)
+	listTeachingActivities func(ctx context.Context, id, term string) ([]models.TeachingActivity, error)
+	getProfile             func(ctx context.Context, id string) (*models.InstructorProfile, error)
+	listCourses            func(ctx context.Context, id string) ([]models.InstructorCourse, error)
+	setPhoto               func(ctx context.Context, id string, photoID dbtypes.NullString) (bool, error)
 }
 
 func (m *MockInstructorRepository) GetByCourseID(ctx context.Context, courseID string) ([]models.Instructor, error) {
@@ -26,6 +34,107 @@ func (m *MockInstructorRepository) GetByCourseID(ctx context.Context, courseID s
 	return []models.Instructor{}, nil
 }
 
+func (m *MockInstructorRepository) GetByID(ctx context.Context, id string) (*models.Instructor, error) {
+	if m.getByID != nil {
+		return m.getByID(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockInstructorRepository) GetByIDs(ctx context.Context, ids []string) ([]models.Instructor, error) {
+	if m.getByIDs != nil {
+		return m.getByIDs(ctx, ids)
+	}
+	return []models.Instructor{}, nil
+}
+
+func (m *MockInstructorRepository) ListTeachingActivities(ctx context.Context, id, term string) ([]models.TeachingActivity, error) {
+	if m.listTeachingActivities != nil {
+		return m.listTeachingActivities(ctx, id, term)
+	}
+	return []models.TeachingActivity{}, nil
+}
+
+func (m *MockInstructorRepository) GetProfile(ctx context.Context, id string) (*models.InstructorProfile, error) {
+	if m.getProfile != nil {
+		return m.getProfile(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockInstructorRepository) ListCourses(ctx context.Context, id string) ([]models.InstructorCourse, error) {
+	if m.listCourses != nil {
+		return m.listCourses(ctx, id)
+	}
+	return []models.InstructorCourse{}, nil
+}
+
+func (m *MockInstructorRepository) SetPhoto(ctx context.Context, id string, photoID dbtypes.NullString) (bool, error) {
+	if m.setPhoto != nil {
+		return m.setPhoto(ctx, id, photoID)
+	}
+	return false, nil
+}
+
+func TestListInstructors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	first := "0192f3a1-5b2c-7d4e-8f60-0123456789ab"
+	second := "0192f3a1-5b2c-7d4e-8f60-0123456789ac"
+	var looked []string
+	handler := NewInstructorHandler(&MockInstructorRepository{
+		getByIDs: func(ctx context.Context, ids []string) ([]models.Instructor, error) {
+			looked = ids
+			return []models.Instructor{{ID: first, FirstName: "John", LastName: "Doe"}}, nil
+		},
+	})
+	r := gin.New()
+	r.GET("/instructors", handler.ListInstructors)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/instructors?ids="+first+","+second+","+first, nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{first, second}, looked)
+	assert.Contains(t, w.Body.String(), `"count":1`)
+	assert.Contains(t, w.Body.String(), "Doe")
+}
+
+func TestListInstructors_Errors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tooMany := make([]string, models.MaxInstructorIDs+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("0192f3a1-5b2c-7d4e-8f60-%012d", i)
+	}
+	tests := []struct {
+		name           string
+		query          string
+		err            error
+		expectedStatus int
+	}{
+		{"missing ids", "", nil, http.StatusBadRequest},
+		{"not a uuid", "?ids=instructor-1", nil, http.StatusBadRequest},
+		{"too many", "?ids=" + strings.Join(tooMany, ","), nil, http.StatusBadRequest},
+		{"repo error", "?ids=0192f3a1-5b2c-7d4e-8f60-0123456789ab", errors.New("db down"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewInstructorHandler(&MockInstructorRepository{
+				getByIDs: func(ctx context.Context, ids []string) ([]models.Instructor, error) {
+					return nil, tt.err
+				},
+			})
+			r := gin.New()
+			r.GET("/instructors", handler.ListInstructors)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/instructors"+tt.query, nil))
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
 func TestGetInstructorsByCourseID(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -39,8 +148,8 @@ func TestGetInstructorsByCourseID(t *testing.T) {
 					ID:             "instructor-1",
 					FirstName:      "John",
 					LastName:       "Doe",
-					RateMyProfLink: &rmpLink,
-					SectionID:      &sectionID,
+					RateMyProfLink: dbtypes.NewNullString(rmpLink),
+					SectionID:      dbtypes.NewNullString(sectionID),
 					CreatedAt:      time.Now(),
 					UpdatedAt:      time.Now(),
 				},
@@ -48,7 +157,7 @@ func TestGetInstructorsByCourseID(t *testing.T) {
 					ID:        "instructor-2",
 					FirstName: "Jane",
 					LastName:  "Smith",
-					SectionID: &sectionID,
+					SectionID: dbtypes.NewNullString(sectionID),
 					CreatedAt: time.Now(),
 					UpdatedAt: time.Now(),
 				},
@@ -121,3 +230,142 @@ func TestGetInstructorsByCourseID_WhenRepoErrors_Returns500(t *testing.T) {
 	assert.Contains(t, strings.ToLower(w.Body.String()), "failed")
 }
 
+func TestGetInstructorSchedule(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var gotTerm string
+	repo := &MockInstructorRepository{
+		getByID: func(ctx context.Context, id string) (*models.Instructor, error) {
+			if id != "instructor-1" {
+				return nil, nil
+			}
+			return &models.Instructor{ID: id, FirstName: "John", LastName: "Doe"}, nil
+		},
+		listTeachingActivities: func(ctx context.Context, id, term string) ([]models.TeachingActivity, error) {
+			gotTerm = term
+			return []models.TeachingActivity{{
+				CourseCode: "EECS2030", Term: models.TermFall, Section: "A", Type: models.ActivityLecture,
+				Times: dbtypes.NewNullString(`[{"day": "M", "time": "19:00", "duration": "170", "campus": "Keele", "room": "CLH A"}]`),
+			}}, nil
+		},
+	}
+	r := gin.New()
+	r.GET("/instructors/:course_id/schedule", NewInstructorHandler(repo).GetInstructorSchedule)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/instructors/instructor-1/schedule?term=F", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, models.TermFall, gotTerm)
+	assert.Contains(t, w.Body.String(), `"count":1`)
+	assert.Contains(t, w.Body.String(), `{"day":"M","meetings":[{"course_code":"EECS2030","term":"F","section":"A","type":"LECT","start":1140,"end":1310,"campus":"Keele","room":"CLH A"}]}`)
+	assert.Contains(t, w.Body.String(), `{"day":"T","meetings":[]}`)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/instructors/missing/schedule", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/instructors/instructor-1/schedule?term=fall", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetInstructorSchedule_RepoError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := &MockInstructorRepository{
+		getByID: func(ctx context.Context, id string) (*models.Instructor, error) {
+			return nil, errors.New("db down")
+		},
+	}
+	r := gin.New()
+	r.GET("/instructors/:course_id/schedule", NewInstructorHandler(repo).GetInstructorSchedule)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/instructors/instructor-1/schedule", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestGetInstructorProfile(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := &MockInstructorRepository{
+		getProfile: func(ctx context.Context, id string) (*models.InstructorProfile, error) {
+			if id != "instructor-1" {
+				return nil, nil
+			}
+			return &models.InstructorProfile{
+				ID: id, FirstName: "John", LastName: "Doe",
+				SectionCount: 3, CourseCount: 2, Terms: []string{models.TermFall, models.TermWinter},
+			}, nil
+		},
+	}
+	r := gin.New()
+	r.GET("/instructors/:course_id/profile", NewInstructorHandler(repo).GetInstructorProfile)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/instructors/instructor-1/profile", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"section_count":3`)
+	assert.Contains(t, w.Body.String(), `"course_count":2`)
+	assert.Contains(t, w.Body.String(), `"terms":["F","W"]`)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/instructors/unknown/profile", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestGetInstructorsByCourseID_NeverReturnsProfile(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := &MockInstructorRepository{
+		getProfile: func(ctx context.Context, id string) (*models.InstructorProfile, error) {
+			t.Fatal("course lookup fetched a profile")
+			return nil, nil
+		},
+	}
+	r := gin.New()
+	r.GET("/instructors/:course_id", NewInstructorHandler(repo).GetInstructorsByCourseID)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/instructors/instructor-1", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"count":0`)
+}
+
+func TestGetInstructorCourses(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := &MockInstructorRepository{
+		getByID: func(ctx context.Context, id string) (*models.Instructor, error) {
+			if id != "instructor-1" {
+				return nil, nil
+			}
+			return &models.Instructor{ID: id, FirstName: "John", LastName: "Doe"}, nil
+		},
+		listCourses: func(ctx context.Context, id string) ([]models.InstructorCourse, error) {
+			return []models.InstructorCourse{
+				{CourseID: "course-1", Code: "EECS2030", Name: "Advanced OOP", Term: models.TermFall, Sections: []string{"A", "B"}},
+			}, nil
+		},
+	}
+	r := gin.New()
+	r.GET("/instructors/:course_id/courses", NewInstructorHandler(repo).GetInstructorCourses)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/instructors/instructor-1/courses", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"count":1`)
+	assert.Contains(t, w.Body.String(), `"sections":["A","B"]`)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/instructors/missing/courses", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	repo.listCourses = func(ctx context.Context, id string) ([]models.InstructorCourse, error) {
+		return nil, errors.New("db down")
+	}
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/instructors/instructor-1/courses", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"yuplan/internal/dbtypes"
+	"yuplan/internal/logging"
+	"yuplan/internal/models"
+	"yuplan/internal/photo"
+	"yuplan/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// photoLibrary stores photos in their standard sizes. Implemented by photo.Library.
+type photoLibrary interface {
+	Save(ctx context.Context, data []byte) (string, error)
+	URLs(id string) *models.InstructorPhoto
+}
+
+// instructorPhotos sets the photo of an instructor, under every row with their name.
+// Implemented by repository.InstructorRepository.
+type instructorPhotos interface {
+	SetPhoto(ctx context.Context, id string, photoID dbtypes.NullString) (bool, error)
+}
+
+type InstructorPhotoHandler struct {
+	repo    instructorPhotos
+	library photoLibrary // nil when PHOTO_STORE is unset
+	cache   cacheInvalidator
+	audit   repository.AuditRepositoryInterface
+}
+
+func NewInstructorPhotoHandler(repo instructorPhotos, cache cacheInvalidator, audit repository.AuditRepositoryInterface) *InstructorPhotoHandler {
+	return &InstructorPhotoHandler{repo: repo, cache: cache, audit: audit}
+}
+
+// WithLibrary enables uploads.
+func (h *InstructorPhotoHandler) WithLibrary(library photoLibrary) *InstructorPhotoHandler {
+	h.library = library
+	return h
+}
+
+// UploadPhoto handles PUT /api/v1/admin/instructors/:id/photo. The body is
+// the image itself, a JPEG, PNG or GIF of up to 5 MB; it is cropped to a
+// square and stored in every size in photo.Sizes.
+func (h *InstructorPhotoHandler) UploadPhoto(c *gin.Context) {
+	if h.library == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Instructor photos are disabled"})
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, photo.MaxUploadBytes))
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Photos must be at most 5 MB"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read photo"})
+		return
+	}
+
+	photoID, err := h.library.Save(c.Request.Context(), data)
+	if errors.Is(err, photo.ErrUnsupported) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Photo must be a JPEG, PNG or GIF image"})
+		return
+	}
+	if err != nil {
+		serverError(c, err, "Failed to store photo")
+		return
+	}
+
+	h.set(c, dbtypes.NewNullString(photoID), h.library.URLs(photoID), "Photo updated")
+}
+
+// DeletePhoto handles DELETE /api/v1/admin/instructors/:id/photo. The stored
+// images are kept, since other instructors or cached pages may still use them.
+func (h *InstructorPhotoHandler) DeletePhoto(c *gin.Context) {
+	h.set(c, dbtypes.NullString{}, nil, "Photo removed")
+}
+
+func (h *InstructorPhotoHandler) set(c *gin.Context, photoID dbtypes.NullString, urls *models.InstructorPhoto, message string) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	found, err := h.repo.SetPhoto(ctx, id, photoID)
+	if err != nil {
+		serverError(c, err, "Failed to update instructor")
+		return
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Instructor not found"})
+		return
+	}
+
+	// The photo shows under every cached row and course of the instructor
+	if err := h.cache.Invalidate(ctx, repository.CacheInstructors); err != nil {
+		logging.FromContext(ctx).Error("Failed to invalidate instructor cache", "error", err)
+	}
+	if err := h.audit.Record(ctx, "instructor", id, "set_photo", gin.H{"photo_id": photoID}); err != nil {
+		logging.FromContext(ctx).Error("Failed to record photo change", "error", err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": message,
+		"data":    gin.H{"photo_id": photoID, "photo": urls},
+	})
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"yuplan/internal/dbtypes"
+	"yuplan/internal/models"
+	"yuplan/internal/photo"
+	"yuplan/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockPhotoLibrary struct {
+	saved []byte
+}
+
+func (m *mockPhotoLibrary) Save(ctx context.Context, data []byte) (string, error) {
+	if string(data) == "not an image" {
+		return "", photo.ErrUnsupported
+	}
+	m.saved = data
+	return "0a1b2c", nil
+}
+
+func (m *mockPhotoLibrary) URLs(id string) *models.InstructorPhoto {
+	return &models.InstructorPhoto{Medium: "https://cdn.example.com/" + id + "/medium.jpg"}
+}
+
+func photoRouter(handler *InstructorPhotoHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.PUT("/admin/instructors/:id/photo", handler.UploadPhoto)
+	router.DELETE("/admin/instructors/:id/photo", handler.DeletePhoto)
+	return router
+}
+
+func TestUploadInstructorPhoto(t *testing.T) {
+	var set dbtypes.NullString
+	repo := &MockInstructorRepository{
+		setPhoto: func(ctx context.Context, id string, photoID dbtypes.NullString) (bool, error) {
+			set = photoID
+			return id == "instructor-1", nil
+		},
+	}
+	cache := &mockCacheInvalidator{}
+	audit := &mockAuditRepository{}
+	library := &mockPhotoLibrary{}
+	router := photoRouter(NewInstructorPhotoHandler(repo, cache, audit).WithLibrary(library))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/admin/instructors/instructor-1/photo", strings.NewReader("jpeg bytes")))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "jpeg bytes", string(library.saved))
+	assert.Equal(t, dbtypes.NewNullString("0a1b2c"), set)
+	assert.Contains(t, w.Body.String(), "https://cdn.example.com/0a1b2c/medium.jpg")
+	assert.Equal(t, []string{repository.CacheInstructors}, cache.namespaces)
+	assert.Equal(t, []string{"instructor instructor-1 set_photo"}, audit.entries)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/admin/instructors/instructor-1/photo", strings.NewReader("not an image")))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/admin/instructors/missing/photo", strings.NewReader("jpeg bytes")))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/admin/instructors/instructor-1/photo", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.False(t, set.Valid)
+}
+
+func TestUploadInstructorPhoto_Disabled(t *testing.T) {
+	router := photoRouter(NewInstructorPhotoHandler(&MockInstructorRepository{}, &mockCacheInvalidator{}, &mockAuditRepository{}))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/admin/instructors/instructor-1/photo", strings.NewReader("jpeg bytes")))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestUploadInstructorPhoto_TooLarge(t *testing.T) {
+	router := photoRouter(NewInstructorPhotoHandler(&MockInstructorRepository{}, &mockCacheInvalidator{}, &mockAuditRepository{}).WithLibrary(&mockPhotoLibrary{}))
+
+	w := httptest.NewRecorder()
+	body := strings.NewReader(strings.Repeat("x", photo.MaxUploadBytes+1))
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/admin/instructors/instructor-1/photo", body))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+}
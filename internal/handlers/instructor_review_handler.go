@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"yuplan/internal/config"
+	"yuplan/internal/contentfilter"
+	"yuplan/internal/dbtypes"
+	"yuplan/internal/markdown"
+	"yuplan/internal/models"
+	"yuplan/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// instructorLookup finds an instructor row by id, nil if there is none.
+// Implemented by repository.InstructorRepository.
+type instructorLookup interface {
+	GetByID(ctx context.Context, id string) (*models.Instructor, error)
+}
+
+type InstructorReviewHandler struct {
+	repo        repository.InstructorReviewRepositoryInterface
+	instructors instructorLookup
+	filter      contentFilter
+}
+
+func NewInstructorReviewHandler(repo repository.InstructorReviewRepositoryInterface, instructors instructorLookup) *InstructorReviewHandler {
+	return &InstructorReviewHandler{repo: repo, instructors: instructors}
+}
+
+// WithContentFilter screens new reviews. Instructor reviews have no moderation
+// queue to hold a flagged review in, so flagged ones are refused like rejected
+// ones. Without it nothing is screened.
+func (h *InstructorReviewHandler) WithContentFilter(filter contentFilter) *InstructorReviewHandler {
+	h.filter = filter
+	return h
+}
+
+// CreateReview handles POST /api/v1/instructors/:instructor_id/reviews. The
+// route shares its wildcard with /instructors/:course_id, so gin names the id
+// course_id.
+func (h *InstructorReviewHandler) CreateReview(c *gin.Context) {
+	var req models.CreateInstructorReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	review := &models.InstructorReview{
+		Email:       req.Email,
+		AuthorName:  req.AuthorName,
+		Clarity:     req.Clarity,
+		Helpfulness: req.Helpfulness,
+		Workload:    req.Workload,
+		ReviewText:  req.ReviewText,
+	}
+	if h.filter != nil {
+		verdict, err := h.filter.Check(c.Request.Context(), review.AuthorName.String+"\n"+review.ReviewText.String)
+		if err != nil {
+			serverError(c, err, "Failed to screen review")
+			return
+		}
+		if verdict.Action != contentfilter.ActionAllow {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": verdict.Reason, "code": models.ErrCodeContentRejected})
+			return
+		}
+	}
+
+	created, err := h.repo.Create(c.Request.Context(), c.Param("course_id"), review)
+	if err != nil {
+		if errors.Is(err, repository.ErrDuplicateInstructorReview) {
+			c.JSON(http.StatusConflict, gin.H{"error": "You have already reviewed this instructor"})
+			return
+		}
+		serverError(c, err, "Failed to create review")
+		return
+	}
+	if !created {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Instructor not found"})
+		return
+	}
+
+	renderInstructorReviewText(review)
+	respond(c, http.StatusCreated, gin.H{
+		"data":    review,
+		"message": "Review created successfully",
+	})
+}
+
+// GetReviews handles GET /api/v1/instructors/:instructor_id/reviews?limit=&offset=,
+// a page of the instructor's reviews, newest first, with stats over all of
+// them. As with CreateReview, gin names the id course_id.
+func (h *InstructorReviewHandler) GetReviews(c *gin.Context) {
+	id := c.Param("course_id")
+	limit, ok := pageLimit(c, config.PagesReviews, "limit")
+	if !ok {
+		return
+	}
+	offset, ok := pageOffset(c)
+	if !ok {
+		return
+	}
+
+	instructor, err := h.instructors.GetByID(c.Request.Context(), id)
+	if err != nil {
+		serverError(c, err, "Failed to fetch instructor")
+		return
+	}
+	if instructor == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Instructor not found"})
+		return
+	}
+
+	reviews, err := h.repo.ListByInstructor(c.Request.Context(), id, limit, offset)
+	if err != nil {
+		serverError(c, err, "Failed to fetch reviews")
+		return
+	}
+	stats, err := h.repo.GetStats(c.Request.Context(), id)
+	if err != nil {
+		serverError(c, err, "Failed to fetch review stats")
+		return
+	}
+	for i := range reviews {
+		renderInstructorReviewText(&reviews[i])
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"data":  reviews,
+		"count": len(reviews),
+		"stats": stats,
+	})
+}
+
+func renderInstructorReviewText(review *models.InstructorReview) {
+	if !review.ReviewText.Valid {
+		review.RenderedHTML = dbtypes.NullString{}
+		return
+	}
+	review.RenderedHTML = dbtypes.NewNullString(markdown.Render(review.ReviewText.String))
+}
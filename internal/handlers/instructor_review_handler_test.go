@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"yuplan/internal/contentfilter"
+	"yuplan/internal/dbtypes"
+	"yuplan/internal/models"
+	"yuplan/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockInstructorReviewRepository struct {
+	instructors map[string]bool // instructor ids that exist
+	reviews     []models.InstructorReview
+	stats       models.InstructorReviewStats
+	created     *models.InstructorReview
+	err         error
+}
+
+func (m *mockInstructorReviewRepository) Create(ctx context.Context, instructorID string, review *models.InstructorReview) (bool, error) {
+	if m.err != nil {
+		return false, m.err
+	}
+	if !m.instructors[instructorID] {
+		return false, nil
+	}
+	review.ID = "ir-1"
+	m.created = review
+	return true, nil
+}
+
+func (m *mockInstructorReviewRepository) ListByInstructor(ctx context.Context, instructorID string, limit, offset int) ([]models.InstructorReview, error) {
+	return m.reviews, m.err
+}
+
+func (m *mockInstructorReviewRepository) GetStats(ctx context.Context, instructorID string) (models.InstructorReviewStats, error) {
+	return m.stats, m.err
+}
+
+func newInstructorReviewRouter(repo *mockInstructorReviewRepository, filter contentFilter) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	instructors := &MockInstructorRepository{getByID: func(ctx context.Context, id string) (*models.Instructor, error) {
+		if !repo.instructors[id] {
+			return nil, nil
+		}
+		return &models.Instructor{ID: id}, nil
+	}}
+	handler := NewInstructorReviewHandler(repo, instructors)
+	if filter != nil {
+		handler.WithContentFilter(filter)
+	}
+	router := gin.New()
+	router.GET("/instructors/:course_id/reviews", handler.GetReviews)
+	router.POST("/instructors/:course_id/reviews", handler.CreateReview)
+	return router
+}
+
+func TestCreateInstructorReview(t *testing.T) {
+	repo := &mockInstructorReviewRepository{instructors: map[string]bool{"inst-1": true}}
+	router := newInstructorReviewRouter(repo, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/instructors/inst-1/reviews", strings.NewReader(
+		`{"email": "student@my.yorku.ca", "clarity": 5, "helpfulness": 4, "workload": 2, "review_text": "Explains **everything**"}`)))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, 2, repo.created.Workload)
+	assert.Contains(t, w.Body.String(), `\u003cstrong\u003eeverything`)
+	assert.NotContains(t, w.Body.String(), "student@my.yorku.ca")
+}
+
+func TestCreateInstructorReview_Errors(t *testing.T) {
+	valid := `{"email": "student@my.yorku.ca", "clarity": 5, "helpfulness": 4, "workload": 2}`
+	tests := []struct {
+		name   string
+		path   string
+		body   string
+		repo   *mockInstructorReviewRepository
+		filter contentFilter
+		want   int
+	}{
+		{"rating out of range", "/instructors/inst-1/reviews", `{"email": "student@my.yorku.ca", "clarity": 6, "helpfulness": 4, "workload": 2}`, &mockInstructorReviewRepository{}, nil, http.StatusBadRequest},
+		{"no such instructor", "/instructors/missing/reviews", valid, &mockInstructorReviewRepository{}, nil, http.StatusNotFound},
+		{"already reviewed", "/instructors/inst-1/reviews", valid, &mockInstructorReviewRepository{err: repository.ErrDuplicateInstructorReview}, nil, http.StatusConflict},
+		{"flagged is refused", "/instructors/inst-1/reviews", valid, &mockInstructorReviewRepository{instructors: map[string]bool{"inst-1": true}},
+			fakeContentFilter{verdict: contentfilter.Verdict{Action: contentfilter.ActionFlag, Reason: "Reviews can't contain links"}}, http.StatusUnprocessableEntity},
+		{"repo error", "/instructors/inst-1/reviews", valid, &mockInstructorReviewRepository{err: errors.New("db down")}, nil, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			newInstructorReviewRouter(tt.repo, tt.filter).ServeHTTP(w, httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.want, w.Code, w.Body.String())
+			assert.Nil(t, tt.repo.created)
+		})
+	}
+}
+
+func TestGetInstructorReviews(t *testing.T) {
+	repo := &mockInstructorReviewRepository{
+		instructors: map[string]bool{"inst-1": true},
+		reviews:     []models.InstructorReview{{ID: "ir-1", Clarity: 4, ReviewText: dbtypes.NewNullString("Great")}},
+		stats:       models.InstructorReviewStats{TotalReviews: 1, AvgClarity: 4, AvgHelpfulness: 5, AvgWorkload: 3},
+	}
+	router := newInstructorReviewRouter(repo, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/instructors/inst-1/reviews", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var body struct {
+		Data  []models.InstructorReview    `json:"data"`
+		Count int                          `json:"count"`
+		Stats models.InstructorReviewStats `json:"stats"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, 1, body.Count)
+	assert.Equal(t, "<p>Great</p>", strings.TrimSpace(body.Data[0].RenderedHTML.String))
+	assert.Equal(t, repo.stats, body.Stats)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/instructors/missing/reviews", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http"
+	"yuplan/internal/jobs"
+
+	"github.com/gin-gonic/gin"
+)
+
+type lockStatser interface {
+	Stats() []jobs.LockStats
+}
+
+type JobsHandler struct {
+	locks lockStatser
+}
+
+func NewJobsHandler(locks lockStatser) *JobsHandler {
+	return &JobsHandler{locks: locks}
+}
+
+// GetLockStats handles GET /api/v1/admin/jobs/locks
+func (h *JobsHandler) GetLockStats(c *gin.Context) {
+	stats := h.locks.Stats()
+	respond(c, http.StatusOK, gin.H{
+		"data":  stats,
+		"count": len(stats),
+	})
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"yuplan/internal/jobs"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockLockStats []jobs.LockStats
+
+func (m mockLockStats) Stats() []jobs.LockStats {
+	return m
+}
+
+func TestGetLockStats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewJobsHandler(mockLockStats{{Job: "export", Acquired: 3, Contended: 5}})
+	router := gin.New()
+	router.GET("/admin/jobs/locks", handler.GetLockStats)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/jobs/locks", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var body struct {
+		Data  []jobs.LockStats `json:"data"`
+		Count int              `json:"count"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, 1, body.Count)
+	assert.Equal(t, int64(5), body.Data[0].Contended)
+}
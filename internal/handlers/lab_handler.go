@@ -20,11 +20,11 @@ func (h *LabHandler) GetLabsBySectionID(c *gin.Context) {
 
 	labs, err := h.repo.GetBySectionID(c.Request.Context(), sectionID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch labs"})
+		serverError(c, err, "Failed to fetch labs")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	respond(c, http.StatusOK, gin.H{
 		"data":  labs,
 		"count": len(labs),
 	})
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"testing"
 	"time"
+	"yuplan/internal/dbtypes"
 	"yuplan/internal/models"
 	"yuplan/internal/repository"
 
@@ -37,7 +38,7 @@ func TestGetLabsBySectionID(t *testing.T) {
 					ID:            "lab-1",
 					SectionID:     sectionID,
 					CatalogNumber: "LAB001",
-					Times:         &times,
+					Times:         dbtypes.NewNullString(times),
 					CreatedAt:     time.Now(),
 					UpdatedAt:     time.Now(),
 				},
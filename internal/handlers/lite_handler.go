@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"yuplan/internal/models"
+	"yuplan/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxLiteCodes caps ?codes= so one enrollment page can be annotated in a
+// single request without turning it into a bulk export.
+const maxLiteCodes = 100
+
+// liteCacheControl lets browsers and the CDN reuse summaries for an hour and
+// serve stale ones for a day while revalidating; stats move slowly.
+const liteCacheControl = "public, max-age=3600, stale-while-revalidate=86400"
+
+// LiteHandler serves trimmed course summaries to the browser extension that
+// annotates York's enrollment site.
+type LiteHandler struct {
+	repo        repository.LiteRepositoryInterface
+	statsWindow time.Duration
+}
+
+func NewLiteHandler(repo repository.LiteRepositoryInterface) *LiteHandler {
+	return &LiteHandler{repo: repo, statsWindow: defaultStatsWindow}
+}
+
+// WithStatsWindow overrides how far back review stats look, matching course pages.
+func (h *LiteHandler) WithStatsWindow(window time.Duration) *LiteHandler {
+	h.statsWindow = window
+	return h
+}
+
+// GetCourse handles GET /api/v1/lite/courses/:course_code
+func (h *LiteHandler) GetCourse(c *gin.Context) {
+	code := models.NormalizeCourseCode(c.Param("course_code"))
+	courses, ok := h.summaries(c, []string{code})
+	if !ok {
+		return
+	}
+	if len(courses) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+		return
+	}
+
+	c.Header("Cache-Control", liteCacheControl)
+	c.JSON(http.StatusOK, gin.H{"data": courses[0]})
+}
+
+// GetCourses handles GET /api/v1/lite/courses?codes=EECS2030,MATH1013
+// Unknown codes are left out of the response.
+func (h *LiteHandler) GetCourses(c *gin.Context) {
+	var codes []string
+	seen := map[string]bool{}
+	for _, code := range strings.Split(c.Query("codes"), ",") {
+		code = models.NormalizeCourseCode(code)
+		if code != "" && !seen[code] {
+			seen[code] = true
+			codes = append(codes, code)
+		}
+	}
+	if len(codes) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "codes is required"})
+		return
+	}
+	if len(codes) > maxLiteCodes {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("At most %d codes per request", maxLiteCodes)})
+		return
+	}
+
+	courses, ok := h.summaries(c, codes)
+	if !ok {
+		return
+	}
+
+	c.Header("Cache-Control", liteCacheControl)
+	respond(c, http.StatusOK, gin.H{
+		"data":  courses,
+		"count": len(courses),
+	})
+}
+
+func (h *LiteHandler) summaries(c *gin.Context, codes []string) ([]models.LiteCourse, bool) {
+	since := time.Now().UTC().Add(-h.statsWindow)
+	courses, err := h.repo.CourseSummaries(c.Request.Context(), codes, since)
+	if err != nil {
+		serverError(c, err, "Failed to fetch course summaries")
+		return nil, false
+	}
+	return courses, true
+}
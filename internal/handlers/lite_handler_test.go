@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockLiteRepository struct {
+	courses   []models.LiteCourse
+	err       error
+	lastCodes []string
+	lastSince time.Time
+}
+
+func (m *mockLiteRepository) CourseSummaries(ctx context.Context, codes []string, since time.Time) ([]models.LiteCourse, error) {
+	m.lastCodes = codes
+	m.lastSince = since
+	return m.courses, m.err
+}
+
+func serveLite(repo *mockLiteRepository, path string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	handler := NewLiteHandler(repo).WithStatsWindow(365 * 24 * time.Hour)
+	router := gin.New()
+	router.GET("/lite/courses", handler.GetCourses)
+	router.GET("/lite/courses/:course_code", handler.GetCourse)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+func TestLiteGetCourse(t *testing.T) {
+	avg, pct := 3.5, 66
+	repo := &mockLiteRepository{courses: []models.LiteCourse{
+		{Code: "EECS2030", Name: "Advanced Object Oriented Programming", AvgDifficulty: &avg, LikePercentage: &pct, ReviewCount: 3},
+	}}
+
+	w := serveLite(repo, "/lite/courses/eecs%202030")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"EECS2030"}, repo.lastCodes)
+	assert.WithinDuration(t, time.Now().Add(-365*24*time.Hour), repo.lastSince, time.Minute)
+	assert.JSONEq(t, `{"data":{"code":"EECS2030","name":"Advanced Object Oriented Programming","avg_difficulty":3.5,"like_percentage":66,"review_count":3}}`, w.Body.String())
+	assert.Equal(t, liteCacheControl, w.Header().Get("Cache-Control"))
+}
+
+func TestLiteGetCourse_NotFound(t *testing.T) {
+	w := serveLite(&mockLiteRepository{courses: []models.LiteCourse{}}, "/lite/courses/NOPE9999")
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Empty(t, w.Header().Get("Cache-Control"))
+}
+
+func TestLiteGetCourses(t *testing.T) {
+	repo := &mockLiteRepository{courses: []models.LiteCourse{
+		{Code: "EECS2030", Name: "Advanced Object Oriented Programming"},
+		{Code: "MATH1013", Name: "Applied Calculus I"},
+	}}
+
+	w := serveLite(repo, "/lite/courses?codes=eecs2030,MATH1013,,EECS2030")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"EECS2030", "MATH1013"}, repo.lastCodes)
+	assert.Contains(t, w.Body.String(), `"count":2`)
+	assert.Contains(t, w.Body.String(), `"avg_difficulty":null`)
+	assert.Equal(t, liteCacheControl, w.Header().Get("Cache-Control"))
+}
+
+func TestLiteGetCourses_BadRequest(t *testing.T) {
+	tooMany := make([]string, maxLiteCodes+1)
+	for i := range tooMany {
+		tooMany[i] = "EECS" + string(rune('A'+i/26)) + string(rune('A'+i%26))
+	}
+
+	for name, path := range map[string]string{
+		"missing codes": "/lite/courses",
+		"blank codes":   "/lite/courses?codes=,%20,",
+		"too many":      "/lite/courses?codes=" + strings.Join(tooMany, ","),
+	} {
+		t.Run(name, func(t *testing.T) {
+			repo := &mockLiteRepository{}
+			w := serveLite(repo, path)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Nil(t, repo.lastCodes)
+		})
+	}
+}
+
+func TestLiteGetCourses_Error(t *testing.T) {
+	w := serveLite(&mockLiteRepository{err: errors.New("db down")}, "/lite/courses?codes=EECS2030")
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
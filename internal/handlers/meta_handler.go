@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"net/http"
+	"yuplan/internal/clientversion"
+	"yuplan/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MetaHandler struct {
+	tunables tunablesSource
+}
+
+func NewMetaHandler() *MetaHandler {
+	return &MetaHandler{}
+}
+
+// WithTunables supplies the live minimum client versions. Without it none are reported.
+func (h *MetaHandler) WithTunables(tunables tunablesSource) *MetaHandler {
+	h.tunables = tunables
+	return h
+}
+
+// GetEnums handles GET /api/v1/meta/enums
+func (h *MetaHandler) GetEnums(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"activity_types":        models.ActivityTypes,
+			"campuses":              models.Campuses,
+			"deliveries":            models.Deliveries,
+			"terms":                 models.Terms,
+			"sessions":              models.Sessions,
+			"offering_frequencies":  models.OfferingFrequencies,
+			"review_sort_modes":     models.ReviewSortModes,
+			"course_sort_keys":      models.CourseSortKeys,
+			"review_tags":           models.ReviewTags,
+			"review_statuses":       models.ReviewStatuses,
+			"review_delivery_modes": models.ReviewDeliveryModes,
+			"transfer_confidences":  models.EquivalencyConfidences,
+			"report_types":          models.ReportTypes,
+			"report_reasons":        models.ReportReasons,
+			"digest_frequencies":    models.DigestFrequencies,
+			"catalog_change_kinds":  models.CatalogChangeKinds,
+			"error_codes":           models.ErrorCodes,
+		},
+	})
+}
+
+// GetClient handles GET /api/v1/meta/client
+func (h *MetaHandler) GetClient(c *gin.Context) {
+	minVersions := map[string]string{}
+	if h.tunables != nil {
+		minVersions = h.tunables.Current().MinClientVersions
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"version_header": clientversion.Header,
+			"min_versions":   minVersions,
+		},
+	})
+}
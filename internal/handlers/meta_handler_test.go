@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"yuplan/internal/config"
+	"yuplan/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetEnums(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewMetaHandler()
+	router := gin.New()
+	router.GET("/meta/enums", handler.GetEnums)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/meta/enums", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var body struct {
+		Data map[string][]string `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, models.ActivityTypes, body.Data["activity_types"])
+	assert.Equal(t, models.Campuses, body.Data["campuses"])
+	assert.Equal(t, models.Deliveries, body.Data["deliveries"])
+	assert.Equal(t, models.Terms, body.Data["terms"])
+	assert.Equal(t, models.OfferingFrequencies, body.Data["offering_frequencies"])
+	assert.Equal(t, models.ReviewSortModes, body.Data["review_sort_modes"])
+	assert.Equal(t, models.CourseSortKeys, body.Data["course_sort_keys"])
+	assert.Equal(t, models.ReviewTags, body.Data["review_tags"])
+	assert.Equal(t, models.ReviewStatuses, body.Data["review_statuses"])
+	assert.Equal(t, models.ReviewDeliveryModes, body.Data["review_delivery_modes"])
+	assert.Equal(t, models.EquivalencyConfidences, body.Data["transfer_confidences"])
+	assert.Equal(t, models.ReportTypes, body.Data["report_types"])
+	assert.Equal(t, models.ReportReasons, body.Data["report_reasons"])
+	assert.Equal(t, models.ErrorCodes, body.Data["error_codes"])
+}
+
+func TestGetClient(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tunables := config.DefaultTunables()
+	tunables.MinClientVersions = map[string]string{"ios": "2.0.0"}
+
+	tests := []struct {
+		name     string
+		handler  *MetaHandler
+		expected map[string]string
+	}{
+		{"configured", NewMetaHandler().WithTunables(&mockReloader{current: tunables}), map[string]string{"ios": "2.0.0"}},
+		{"no tunables", NewMetaHandler(), map[string]string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/meta/client", tt.handler.GetClient)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/meta/client", nil))
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			var body struct {
+				Data struct {
+					VersionHeader string            `json:"version_header"`
+					MinVersions   map[string]string `json:"min_versions"`
+				} `json:"data"`
+			}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, "X-Client-Version", body.Data.VersionHeader)
+			assert.Equal(t, tt.expected, body.Data.MinVersions)
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// metricsWriter renders the registered counters. Implemented by metrics.Registry.
+type metricsWriter interface {
+	WriteText(w io.Writer) error
+}
+
+type MetricsHandler struct {
+	registry metricsWriter
+}
+
+func NewMetricsHandler(registry metricsWriter) *MetricsHandler {
+	return &MetricsHandler{registry: registry}
+}
+
+// GetMetrics handles GET /api/v1/admin/metrics in the Prometheus text format.
+func (h *MetricsHandler) GetMetrics(c *gin.Context) {
+	var buf bytes.Buffer
+	if err := h.registry.WriteText(&buf); err != nil {
+		serverError(c, err, "Failed to render metrics")
+		return
+	}
+	c.Data(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", buf.Bytes())
+}
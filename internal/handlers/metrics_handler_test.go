@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"yuplan/internal/metrics"
+	"yuplan/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetMetrics_CountsBusinessEvents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	registry := metrics.NewRegistry()
+	business := metrics.NewBusiness(registry)
+	courses := NewCourseHandler(&MockCourseRepository{}, nil).WithMetrics(business)
+
+	router := gin.New()
+	router.GET("/courses/search", courses.SearchCourses)
+	router.GET("/metrics", NewMetricsHandler(registry).GetMetrics)
+
+	for _, path := range []string{"/courses/search?q=nothing", "/courses/search?q=nothing&offset=50"} {
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	business.ReviewEvent(models.ReviewEventCreated)
+
+	req, _ := http.NewRequest(http.MethodGet, "/metrics", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.True(t, strings.HasPrefix(recorder.Header().Get("Content-Type"), "text/plain"))
+	body := recorder.Body.String()
+	assert.Contains(t, body, `yuplan_course_searches_total{path="fuzzy",results="zero"} 1`)
+	assert.Contains(t, body, `yuplan_review_events_total{event="created"} 1`)
+	assert.Contains(t, body, "# TYPE yuplan_schedule_generations_total counter")
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"net/http"
+	"yuplan/internal/models"
+	"yuplan/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ModerationHandler struct {
+	repo repository.ModerationRepositoryInterface
+}
+
+func NewModerationHandler(repo repository.ModerationRepositoryInterface) *ModerationHandler {
+	return &ModerationHandler{repo: repo}
+}
+
+// GetRules handles GET /api/v1/admin/moderation/rules, in the order they are checked.
+func (h *ModerationHandler) GetRules(c *gin.Context) {
+	rules, err := h.repo.ListRules(c.Request.Context())
+	if err != nil {
+		serverError(c, err, "Failed to fetch moderation rules")
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"data":  rules,
+		"count": len(rules),
+	})
+}
+
+// SetRules handles PUT /api/v1/admin/moderation/rules, replacing the whole
+// rule list. An empty list approves every new review.
+func (h *ModerationHandler) SetRules(c *gin.Context) {
+	var req models.SetModerationRulesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rules, err := h.repo.SetRules(c.Request.Context(), req.ModerationRules())
+	if err != nil {
+		serverError(c, err, "Failed to save moderation rules")
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"data":    rules,
+		"count":   len(rules),
+		"message": "Moderation rules updated",
+	})
+}
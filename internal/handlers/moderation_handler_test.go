@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockModerationRepository struct {
+	rules []models.ModerationRule
+	set   []models.ModerationRule
+	err   error
+}
+
+func (m *mockModerationRepository) ListRules(ctx context.Context) ([]models.ModerationRule, error) {
+	return m.rules, m.err
+}
+
+func (m *mockModerationRepository) SetRules(ctx context.Context, rules []models.ModerationRule) ([]models.ModerationRule, error) {
+	m.set = rules
+	return rules, m.err
+}
+
+func (m *mockModerationRepository) FirstReviewAt(ctx context.Context, email string) (time.Time, bool, error) {
+	return time.Time{}, false, m.err
+}
+
+func newModerationRouter(repo *mockModerationRepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewModerationHandler(repo)
+	router := gin.New()
+	router.GET("/admin/moderation/rules", handler.GetRules)
+	router.PUT("/admin/moderation/rules", handler.SetRules)
+	return router
+}
+
+func serveModeration(router *gin.Engine, method, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(method, "/admin/moderation/rules", strings.NewReader(body)))
+	return w
+}
+
+func TestGetModerationRules(t *testing.T) {
+	repo := &mockModerationRepository{rules: []models.ModerationRule{
+		{ID: 1, Position: 1, Name: "yorku staff", Action: models.ModerationActionApprove, Enabled: true, EmailDomains: []string{"yorku.ca"}},
+	}}
+
+	w := serveModeration(newModerationRouter(repo), http.MethodGet, "")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"name":"yorku staff"`)
+	assert.Contains(t, w.Body.String(), `"count":1`)
+
+	w = serveModeration(newModerationRouter(&mockModerationRepository{err: errors.New("db down")}), http.MethodGet, "")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestSetModerationRules(t *testing.T) {
+	repo := &mockModerationRepository{}
+
+	w := serveModeration(newModerationRouter(repo), http.MethodPut,
+		`{"rules": [{"name": "clean", "action": "approve", "no_profanity": true, "email_domains": ["@YorkU.ca"]}, {"name": "rest", "action": "queue"}]}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	if assert.Len(t, repo.set, 2) {
+		assert.Equal(t, 1, repo.set[0].Position)
+		assert.Equal(t, []string{"yorku.ca"}, repo.set[0].EmailDomains)
+		assert.True(t, repo.set[1].Enabled)
+	}
+}
+
+func TestSetModerationRules_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"unknown action", `{"rules": [{"name": "x", "action": "reject"}]}`},
+		{"missing name", `{"rules": [{"action": "approve"}]}`},
+		{"negative age", `{"rules": [{"name": "x", "action": "approve", "min_author_age_days": -1}]}`},
+	}
+	for _, tt := range tests {
+		repo := &mockModerationRepository{}
+		w := serveModeration(newModerationRouter(repo), http.MethodPut, tt.body)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, tt.name)
+		assert.Nil(t, repo.set, tt.name)
+	}
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"yuplan/internal/models"
+	"yuplan/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+type offeringRefresher interface {
+	Run(ctx context.Context) (int, error)
+}
+
+type OfferingHandler struct {
+	repo      repository.OfferingRepositoryInterface
+	refresher offeringRefresher
+}
+
+func NewOfferingHandler(repo repository.OfferingRepositoryInterface, refresher offeringRefresher) *OfferingHandler {
+	return &OfferingHandler{repo: repo, refresher: refresher}
+}
+
+// GetOffering handles GET /api/v1/courses/:course_code/offering?year=&term=
+// With year (academic year start, e.g. 2026 for 2026-2027) and term it also
+// estimates whether the course will run then, for planners.
+func (h *OfferingHandler) GetOffering(c *gin.Context) {
+	yearParam, term := c.Query("year"), c.Query("term")
+	if (yearParam == "") != (term == "") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameters 'year' and 'term' must be given together"})
+		return
+	}
+	var year int
+	if yearParam != "" {
+		var err error
+		if year, err = strconv.Atoi(yearParam); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter 'year' must be a number"})
+			return
+		}
+		if !isTerm(term) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unknown term %q", term)})
+			return
+		}
+	}
+
+	summary, err := h.repo.GetSummary(c.Request.Context(), c.Param("course_code"))
+	if err != nil {
+		serverError(c, err, "Failed to fetch offering history")
+		return
+	}
+	if summary == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No offering history for this course"})
+		return
+	}
+
+	resp := gin.H{"data": summary}
+	if term != "" {
+		likelihood := summary.Likelihood(year, term)
+		resp["likelihood"] = likelihood
+		if likelihood == models.OfferingUnlikely {
+			resp["warning"] = fmt.Sprintf("%s is unlikely to be offered in %s %d-%d (usually %s, last offered %s %d-%d)",
+				summary.Code, term, year, year+1, summary.Frequency, summary.LastOfferedTerm, summary.LastOfferedYear, summary.LastOfferedYear+1)
+		}
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// RefreshOfferings handles POST /api/v1/admin/offerings/refresh
+func (h *OfferingHandler) RefreshOfferings(c *gin.Context) {
+	n, err := h.refresher.Run(c.Request.Context())
+	if err != nil {
+		serverError(c, err, "Failed to refresh offering summaries")
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"count":   n,
+		"message": "Offering summaries refreshed",
+	})
+}
+
+func isTerm(term string) bool {
+	for _, t := range models.Terms {
+		if t == term {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"yuplan/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockOfferingRepository struct {
+	summary *models.OfferingSummary
+	err     error
+}
+
+func (m *mockOfferingRepository) ListOfferings(ctx context.Context) ([]models.CourseOffering, error) {
+	return []models.CourseOffering{}, nil
+}
+
+func (m *mockOfferingRepository) SaveSummaries(ctx context.Context, summaries []models.OfferingSummary) error {
+	return nil
+}
+
+func (m *mockOfferingRepository) GetSummary(ctx context.Context, courseCode string) (*models.OfferingSummary, error) {
+	return m.summary, m.err
+}
+
+type mockOfferingRefresher struct {
+	count int
+	err   error
+}
+
+func (m *mockOfferingRefresher) Run(ctx context.Context) (int, error) {
+	return m.count, m.err
+}
+
+func TestGetOffering(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	fallOnly := &models.OfferingSummary{
+		Code:            "EECS2030",
+		LastOfferedYear: 2025,
+		LastOfferedTerm: models.TermFall,
+		Frequency:       models.OfferingAnnual,
+		Terms:           []string{models.TermFall},
+		YearsObserved:   3,
+	}
+
+	tests := []struct {
+		name               string
+		repo               *mockOfferingRepository
+		query              string
+		expectedStatus     int
+		expectedLikelihood string
+		expectWarning      bool
+	}{
+		{name: "summary only", repo: &mockOfferingRepository{summary: fallOnly}, expectedStatus: http.StatusOK},
+		{name: "likely term", repo: &mockOfferingRepository{summary: fallOnly}, query: "?year=2026&term=F", expectedStatus: http.StatusOK, expectedLikelihood: models.OfferingLikely},
+		{name: "unlikely term warns", repo: &mockOfferingRepository{summary: fallOnly}, query: "?year=2026&term=W", expectedStatus: http.StatusOK, expectedLikelihood: models.OfferingUnlikely, expectWarning: true},
+		{name: "term without year", repo: &mockOfferingRepository{summary: fallOnly}, query: "?term=F", expectedStatus: http.StatusBadRequest},
+		{name: "bad year", repo: &mockOfferingRepository{summary: fallOnly}, query: "?year=next&term=F", expectedStatus: http.StatusBadRequest},
+		{name: "unknown term", repo: &mockOfferingRepository{summary: fallOnly}, query: "?year=2026&term=Q", expectedStatus: http.StatusBadRequest},
+		{name: "no history", repo: &mockOfferingRepository{}, expectedStatus: http.StatusNotFound},
+		{name: "repository error", repo: &mockOfferingRepository{err: errors.New("db down")}, expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewOfferingHandler(tt.repo, &mockOfferingRefresher{})
+			router := gin.New()
+			router.GET("/courses/:course_code/offering", handler.GetOffering)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/courses/EECS2030/offering"+tt.query, nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if w.Code != http.StatusOK {
+				return
+			}
+
+			var body struct {
+				Data       models.OfferingSummary `json:"data"`
+				Likelihood string                 `json:"likelihood"`
+				Warning    string                 `json:"warning"`
+			}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, "EECS2030", body.Data.Code)
+			assert.Equal(t, tt.expectedLikelihood, body.Likelihood)
+			assert.Equal(t, tt.expectWarning, body.Warning != "")
+		})
+	}
+}
+
+func TestRefreshOfferings(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		refresher      *mockOfferingRefresher
+		expectedStatus int
+	}{
+		{"success", &mockOfferingRefresher{count: 42}, http.StatusOK},
+		{"error", &mockOfferingRefresher{err: errors.New("db down")}, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewOfferingHandler(&mockOfferingRepository{}, tt.refresher)
+			router := gin.New()
+			router.POST("/admin/offerings/refresh", handler.RefreshOfferings)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/offerings/refresh", nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Contains(t, w.Body.String(), `"count":42`)
+			}
+		})
+	}
+}
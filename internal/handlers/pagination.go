@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"yuplan/internal/config"
+	"yuplan/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+var pageSizes atomic.Value // map[string]config.PageSize
+
+func init() {
+	pageSizes.Store(config.DefaultPageSizes())
+}
+
+// SetPageSizes replaces the page size policy for all handlers.
+func SetPageSizes(sizes map[string]config.PageSize) {
+	pageSizes.Store(sizes)
+}
+
+// pageLimit reads the page size query parameter param under group's policy:
+// the group's default when it's absent, 400 when it isn't between 1 and the
+// group's maximum. ok is false once the error response has been written.
+func pageLimit(c *gin.Context, group, param string) (limit int, ok bool) {
+	size, known := pageSizes.Load().(map[string]config.PageSize)[group]
+	if !known {
+		size = config.DefaultPageSizes()[group]
+	}
+	raw := c.Query(param)
+	if raw == "" {
+		return size.Default, true
+	}
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit < 1 || limit > size.Max {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Query parameter '%s' must be between 1 and %d", param, size.Max),
+			"code":  models.ErrCodeBadRequest,
+		})
+		return 0, false
+	}
+	return limit, true
+}
+
+// pageOffset reads ?offset=, 0 when absent and 400 when negative or not a number.
+func pageOffset(c *gin.Context) (offset int, ok bool) {
+	raw := c.Query("offset")
+	if raw == "" {
+		return 0, true
+	}
+	offset, err := strconv.Atoi(raw)
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Query parameter 'offset' must be a non-negative integer",
+			"code":  models.ErrCodeBadRequest,
+		})
+		return 0, false
+	}
+	return offset, true
+}
+
+// pageCursor reads ?cursor=, which switches a listing from offset to keyset
+// pages. present is false when it is absent; an empty cursor asks for the
+// first page and leaves after nil. It is a 400 to send it with offsetParam,
+// or to send a cursor that doesn't decode. ok is false once the error
+// response has been written.
+func pageCursor[T any](c *gin.Context, offsetParam string) (after *T, present, ok bool) {
+	raw, present := c.GetQuery("cursor")
+	if !present {
+		return nil, false, true
+	}
+	if c.Query(offsetParam) != "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Query parameters 'cursor' and '%s' can't be combined", offsetParam),
+			"code":  models.ErrCodeBadRequest,
+		})
+		return nil, true, false
+	}
+	if raw == "" {
+		return nil, true, true
+	}
+	after = new(T)
+	if err := models.DecodeCursor(raw, after); err != nil {
+		badCursor(c)
+		return nil, true, false
+	}
+	return after, true, true
+}
+
+func badCursor(c *gin.Context) {
+	c.JSON(http.StatusBadRequest, gin.H{
+		"error": "Query parameter 'cursor' must be a next_cursor from this listing",
+		"code":  models.ErrCodeBadRequest,
+	})
+}
+
+// trimPage cuts a page fetched with one extra row back to limit, and returns
+// the next_cursor for the row it ends on, or nil when that was the last page.
+func trimPage[T any](rows []T, limit int, cursorAfter func(T) any) ([]T, *string) {
+	if len(rows) <= limit {
+		return rows, nil
+	}
+	rows = rows[:limit]
+	next := models.EncodeCursor(cursorAfter(rows[limit-1]))
+	return rows, &next
+}
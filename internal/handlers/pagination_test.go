@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"yuplan/internal/config"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPageLimitAndOffset(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		query      string
+		wantStatus int
+		wantLimit  int
+		wantOffset int
+	}{
+		{"", http.StatusOK, 10, 0},
+		{"?limit=50&offset=20", http.StatusOK, 50, 20},
+		{"?limit=51", http.StatusBadRequest, 0, 0},
+		{"?limit=0", http.StatusBadRequest, 0, 0},
+		{"?limit=abc", http.StatusBadRequest, 0, 0},
+		{"?offset=-1", http.StatusBadRequest, 0, 0},
+	}
+	for _, tt := range tests {
+		var limit, offset int
+		router := gin.New()
+		router.GET("/reviews", func(c *gin.Context) {
+			var ok bool
+			if limit, ok = pageLimit(c, config.PagesReviews, "limit"); !ok {
+				return
+			}
+			if offset, ok = pageOffset(c); !ok {
+				return
+			}
+			c.Status(http.StatusOK)
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/reviews"+tt.query, nil))
+
+		assert.Equal(t, tt.wantStatus, w.Code, tt.query)
+		if tt.wantStatus == http.StatusOK {
+			assert.Equal(t, tt.wantLimit, limit, tt.query)
+			assert.Equal(t, tt.wantOffset, offset, tt.query)
+		} else {
+			assert.Contains(t, w.Body.String(), `"code":"bad_request"`, tt.query)
+		}
+	}
+}
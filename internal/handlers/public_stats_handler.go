@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+	"sync"
+	"time"
+	"yuplan/internal/models"
+	"yuplan/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// publicStatsTTL is how long one computation of the landing page counters
+	// is served from memory; they scan whole tables, and nobody needs them live.
+	publicStatsTTL = 10 * time.Minute
+
+	publicStatsCacheControl = "public, max-age=600, stale-while-revalidate=3600"
+
+	publicStatsDepartments = 5
+)
+
+// PublicStatsHandler serves the landing page counters.
+type PublicStatsHandler struct {
+	repo repository.PublicStatsRepositoryInterface
+	now  func() time.Time
+
+	mu     sync.Mutex
+	cached *models.PublicStats
+}
+
+func NewPublicStatsHandler(repo repository.PublicStatsRepositoryInterface) *PublicStatsHandler {
+	return &PublicStatsHandler{repo: repo, now: time.Now}
+}
+
+// GetPublicStats handles GET /api/v1/stats/public
+func (h *PublicStatsHandler) GetPublicStats(c *gin.Context) {
+	// Held across the query so a burst of requests after expiry computes once
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := h.now().UTC()
+	if h.cached == nil || now.Sub(h.cached.GeneratedAt) >= publicStatsTTL {
+		stats, err := h.repo.PublicStats(c.Request.Context(), now.AddDate(0, 0, -7), publicStatsDepartments)
+		if err != nil {
+			serverError(c, err, "Failed to fetch stats")
+			return
+		}
+		stats.GeneratedAt = now
+		h.cached = stats
+	}
+
+	c.Header("Cache-Control", publicStatsCacheControl)
+	c.JSON(http.StatusOK, gin.H{"data": h.cached})
+}
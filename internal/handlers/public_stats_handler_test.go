@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockPublicStatsRepository struct {
+	calls        int
+	err          error
+	gotWeekStart time.Time
+}
+
+func (m *mockPublicStatsRepository) PublicStats(ctx context.Context, weekStart time.Time, departments int) (*models.PublicStats, error) {
+	m.calls++
+	m.gotWeekStart = weekStart
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &models.PublicStats{
+		Courses:           5321,
+		Reviews:           812 + m.calls,
+		MostReviewedDepts: []models.DepartmentReviewCount{{Department: "EECS", Reviews: 240}},
+	}, nil
+}
+
+func TestGetPublicStats_Cached(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := &mockPublicStatsRepository{}
+	handler := NewPublicStatsHandler(repo)
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	handler.now = func() time.Time { return now }
+
+	router := gin.New()
+	router.GET("/stats/public", handler.GetPublicStats)
+	get := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stats/public", nil))
+		return w
+	}
+
+	w := get()
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, publicStatsCacheControl, w.Header().Get("Cache-Control"))
+	assert.Contains(t, w.Body.String(), `"reviews":813`)
+	assert.Contains(t, w.Body.String(), `"most_reviewed_departments":[{"department":"EECS","reviews":240}]`)
+	assert.Equal(t, now.AddDate(0, 0, -7), repo.gotWeekStart)
+
+	now = now.Add(publicStatsTTL - time.Second)
+	assert.Contains(t, get().Body.String(), `"reviews":813`)
+	assert.Equal(t, 1, repo.calls)
+
+	now = now.Add(time.Second)
+	assert.Contains(t, get().Body.String(), `"reviews":814`)
+	assert.Equal(t, 2, repo.calls)
+}
+
+func TestGetPublicStats_RepoError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := &mockPublicStatsRepository{err: errors.New("db down")}
+	router := gin.New()
+	router.GET("/stats/public", NewPublicStatsHandler(repo).GetPublicStats)
+
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stats/public", nil))
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	}
+	assert.Equal(t, 2, repo.calls, "failures aren't cached")
+}
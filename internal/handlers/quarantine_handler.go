@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"yuplan/internal/models"
+	"yuplan/internal/repository"
+	"yuplan/internal/seedcheck"
+
+	"github.com/gin-gonic/gin"
+)
+
+type QuarantineHandler struct {
+	repo repository.QuarantineRepositoryInterface
+}
+
+func NewQuarantineHandler(repo repository.QuarantineRepositoryInterface) *QuarantineHandler {
+	return &QuarantineHandler{repo: repo}
+}
+
+// ListQuarantine handles GET /api/v1/admin/quarantine?status=pending
+// status defaults to pending; "all" lists every record.
+func (h *QuarantineHandler) ListQuarantine(c *gin.Context) {
+	status := c.DefaultQuery("status", models.QuarantinePending)
+	if status == "all" {
+		status = ""
+	} else if !isQuarantineStatus(status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unknown status %q", status)})
+		return
+	}
+
+	records, err := h.repo.List(c.Request.Context(), status)
+	if err != nil {
+		serverError(c, err, "Failed to fetch quarantined records")
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"data":  records,
+		"count": len(records),
+	})
+}
+
+// GetQuarantined handles GET /api/v1/admin/quarantine/:id
+func (h *QuarantineHandler) GetQuarantined(c *gin.Context) {
+	record, ok := h.load(c, false)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": record})
+}
+
+// ReprocessQuarantined handles POST /api/v1/admin/quarantine/:id/reprocess
+// It re-validates the stored record, or a corrected one given as {"record": {...}},
+// and inserts it if it now passes.
+func (h *QuarantineHandler) ReprocessQuarantined(c *gin.Context) {
+	var req models.ReprocessQuarantineRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	entry, ok := h.load(c, true)
+	if !ok {
+		return
+	}
+	raw := entry.Record
+	if len(req.Record) > 0 {
+		raw = req.Record
+	}
+
+	record, reasons := seedcheck.Parse(raw)
+	if reasons == nil {
+		reasons = seedcheck.Validate(record)
+	}
+	if len(reasons) == 0 {
+		conflicts, err := h.repo.Conflicts(c.Request.Context(), record.Code(), record.Term, record.CatalogNumbers())
+		if err != nil {
+			serverError(c, err, "Failed to check quarantined record")
+			return
+		}
+		reasons = conflicts
+	}
+	if len(reasons) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   "Record still fails validation",
+			"reasons": reasons,
+		})
+		return
+	}
+
+	courseID, err := h.repo.Release(c.Request.Context(), entry.ID, raw, seedcheck.NewPlan(record))
+	if err != nil {
+		serverError(c, err, "Failed to insert quarantined record")
+		return
+	}
+	if courseID == "" {
+		c.JSON(http.StatusConflict, gin.H{"error": "Record is no longer pending"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Record re-processed",
+		"course_id": courseID,
+	})
+}
+
+// DismissQuarantined handles POST /api/v1/admin/quarantine/:id/dismiss
+func (h *QuarantineHandler) DismissQuarantined(c *gin.Context) {
+	dismissed, err := h.repo.Dismiss(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		serverError(c, err, "Failed to dismiss quarantined record")
+		return
+	}
+	if !dismissed {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No pending quarantined record with that id"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Record dismissed"})
+}
+
+// load loads the record named by :id, writing the error response itself
+// when it is missing or, if requirePending, already resolved.
+func (h *QuarantineHandler) load(c *gin.Context, requirePending bool) (*models.QuarantinedRecord, bool) {
+	record, err := h.repo.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		serverError(c, err, "Failed to fetch quarantined record")
+		return nil, false
+	}
+	if record == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Quarantined record not found"})
+		return nil, false
+	}
+	if requirePending && record.Status != models.QuarantinePending {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Record is already %s", record.Status)})
+		return nil, false
+	}
+	return record, true
+}
+
+func isQuarantineStatus(status string) bool {
+	for _, s := range models.QuarantineStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"yuplan/internal/models"
+	"yuplan/internal/seedcheck"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockQuarantineRepository struct {
+	records   []models.QuarantinedRecord
+	conflicts []string
+	released  *seedcheck.Plan
+	listErr   error
+	listedAs  string
+}
+
+func (m *mockQuarantineRepository) List(ctx context.Context, status string) ([]models.QuarantinedRecord, error) {
+	m.listedAs = status
+	return m.records, m.listErr
+}
+
+func (m *mockQuarantineRepository) Get(ctx context.Context, id string) (*models.QuarantinedRecord, error) {
+	for i := range m.records {
+		if m.records[i].ID == id {
+			return &m.records[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockQuarantineRepository) Conflicts(ctx context.Context, code, term string, catalogNumbers []string) ([]string, error) {
+	return m.conflicts, nil
+}
+
+func (m *mockQuarantineRepository) Release(ctx context.Context, id string, record json.RawMessage, plan seedcheck.Plan) (string, error) {
+	m.released = &plan
+	return "course-1", nil
+}
+
+func (m *mockQuarantineRepository) Dismiss(ctx context.Context, id string) (bool, error) {
+	r, _ := m.Get(ctx, id)
+	return r != nil && r.Status == models.QuarantinePending, nil
+}
+
+func (m *mockQuarantineRepository) Add(ctx context.Context, source, key string, record json.RawMessage, reasons []string) (bool, error) {
+	return false, nil
+}
+
+const brokenRecord = `{"department": "SUST", "courseId": "", "courseTitle": "Ethics and Technology", "credits": "", "term": "W2", "sections": []}`
+
+const fixedRecord = `{"department": "SUST", "courseId": "6200", "courseTitle": "Ethics and Technology", "credits": "1.50", "term": "W2",
+	"sections": [{"type": "LECT", "section": "A", "catalogNumber": "Q12R01", "schedule": [{"day": "M", "time": "19:00", "duration": "180"}], "instructors": ["Ada Lovelace"]}]}`
+
+func newQuarantineRouter(repo *mockQuarantineRepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewQuarantineHandler(repo)
+	router := gin.New()
+	router.GET("/admin/quarantine", handler.ListQuarantine)
+	router.GET("/admin/quarantine/:id", handler.GetQuarantined)
+	router.POST("/admin/quarantine/:id/reprocess", handler.ReprocessQuarantined)
+	router.POST("/admin/quarantine/:id/dismiss", handler.DismissQuarantined)
+	return router
+}
+
+func quarantineRepo() *mockQuarantineRepository {
+	return &mockQuarantineRepository{records: []models.QuarantinedRecord{
+		{ID: "q-1", Source: "fall-winter-2025-2026/schulich.json", RecordKey: "SUST W2", Record: json.RawMessage(brokenRecord), Reasons: []string{"missing courseId"}, Status: models.QuarantinePending},
+		{ID: "q-2", RecordKey: "SUST C", Record: json.RawMessage(brokenRecord), Status: models.QuarantineDismissed},
+	}}
+}
+
+func serveQuarantine(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+	return w
+}
+
+func TestListQuarantine(t *testing.T) {
+	repo := quarantineRepo()
+	router := newQuarantineRouter(repo)
+
+	w := serveQuarantine(router, http.MethodGet, "/admin/quarantine", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, models.QuarantinePending, repo.listedAs)
+	assert.Contains(t, w.Body.String(), `"count":2`)
+
+	w = serveQuarantine(router, http.MethodGet, "/admin/quarantine?status=all", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "", repo.listedAs)
+
+	w = serveQuarantine(router, http.MethodGet, "/admin/quarantine?status=lost", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	repo.listErr = errors.New("db down")
+	w = serveQuarantine(router, http.MethodGet, "/admin/quarantine", "")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestGetQuarantined(t *testing.T) {
+	router := newQuarantineRouter(quarantineRepo())
+
+	w := serveQuarantine(router, http.MethodGet, "/admin/quarantine/q-1", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"record_key":"SUST W2"`)
+	assert.Contains(t, w.Body.String(), `"courseTitle":"Ethics and Technology"`)
+
+	w = serveQuarantine(router, http.MethodGet, "/admin/quarantine/missing", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestReprocessQuarantined_StoredRecordStillInvalid(t *testing.T) {
+	repo := quarantineRepo()
+	router := newQuarantineRouter(repo)
+
+	w := serveQuarantine(router, http.MethodPost, "/admin/quarantine/q-1/reprocess", "")
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "missing courseId")
+	assert.Nil(t, repo.released)
+}
+
+func TestReprocessQuarantined_CorrectedRecord(t *testing.T) {
+	repo := quarantineRepo()
+	router := newQuarantineRouter(repo)
+
+	w := serveQuarantine(router, http.MethodPost, "/admin/quarantine/q-1/reprocess", `{"record": `+fixedRecord+`}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"course_id":"course-1"`)
+	if assert.NotNil(t, repo.released) {
+		assert.Equal(t, "SUST6200", repo.released.Code)
+		assert.Equal(t, []string{"A"}, repo.released.Letters)
+		assert.Len(t, repo.released.Instructors, 1)
+	}
+}
+
+func TestReprocessQuarantined_Conflicts(t *testing.T) {
+	repo := quarantineRepo()
+	repo.conflicts = []string{"course SUST6200 W2 already exists"}
+	router := newQuarantineRouter(repo)
+
+	w := serveQuarantine(router, http.MethodPost, "/admin/quarantine/q-1/reprocess", `{"record": `+fixedRecord+`}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "already exists")
+	assert.Nil(t, repo.released)
+}
+
+func TestReprocessQuarantined_Errors(t *testing.T) {
+	router := newQuarantineRouter(quarantineRepo())
+
+	assert.Equal(t, http.StatusNotFound, serveQuarantine(router, http.MethodPost, "/admin/quarantine/missing/reprocess", "").Code)
+	assert.Equal(t, http.StatusConflict, serveQuarantine(router, http.MethodPost, "/admin/quarantine/q-2/reprocess", "").Code)
+	assert.Equal(t, http.StatusBadRequest, serveQuarantine(router, http.MethodPost, "/admin/quarantine/q-1/reprocess", "{").Code)
+
+	w := serveQuarantine(router, http.MethodPost, "/admin/quarantine/q-1/reprocess", `{"record": {"sections": "none"}}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "does not match the scraper format")
+}
+
+func TestDismissQuarantined(t *testing.T) {
+	router := newQuarantineRouter(quarantineRepo())
+
+	assert.Equal(t, http.StatusOK, serveQuarantine(router, http.MethodPost, "/admin/quarantine/q-1/dismiss", "").Code)
+	assert.Equal(t, http.StatusNotFound, serveQuarantine(router, http.MethodPost, "/admin/quarantine/q-2/dismiss", "").Code)
+}
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"yuplan/internal/dbtypes"
+	"yuplan/internal/models"
+	"yuplan/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxReportDetails caps the free-text part of a report, in characters.
+const maxReportDetails = 2000
+
+type ReportHandler struct {
+	repo repository.ReportRepositoryInterface
+}
+
+func NewReportHandler(repo repository.ReportRepositoryInterface) *ReportHandler {
+	return &ReportHandler{repo: repo}
+}
+
+// CreateReport handles POST /api/v1/reports
+// Body: {"type": "broken_rmp_link", "entity_type": "instructor", "entity_id": "...", "details": "...", "email": "..."}
+// Review reports also take a "reason", other if left out. The report joins
+// the admin queue as open. Enough open reports on a review hide it; see
+// reports.Service.
+func (h *ReportHandler) CreateReport(c *gin.Context) {
+	var req models.CreateReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	entityType, ok := models.ReportTypeEntities[req.Type]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unknown report type %q", req.Type)})
+		return
+	}
+	if req.EntityType != entityType {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("A %s report must refer to a %s", req.Type, entityType)})
+		return
+	}
+
+	var reason dbtypes.NullString
+	switch {
+	case entityType == models.ReportEntityReview && req.Reason == "":
+		reason = dbtypes.NewNullString(models.ReportReasonOther)
+	case entityType == models.ReportEntityReview && slices.Contains(models.ReportReasons, req.Reason):
+		reason = dbtypes.NewNullString(req.Reason)
+	case entityType == models.ReportEntityReview:
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Reason must be one of %s", strings.Join(models.ReportReasons, ", "))})
+		return
+	case req.Reason != "":
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Only review reports take a reason"})
+		return
+	}
+
+	details := req.Details
+	details.String = strings.TrimSpace(details.String)
+	if details.Valid && details.String == "" {
+		details = dbtypes.NullString{}
+	}
+	if len([]rune(details.String)) > maxReportDetails {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Details must be at most %d characters", maxReportDetails)})
+		return
+	}
+
+	report := &models.Report{
+		Type:       req.Type,
+		EntityType: req.EntityType,
+		EntityID:   req.EntityID,
+		Reason:     reason,
+		Details:    details,
+	}
+	if req.Email != "" {
+		report.Email = dbtypes.NewNullString(req.Email)
+	}
+
+	created, err := h.repo.Create(c.Request.Context(), report)
+	if err != nil {
+		serverError(c, err, "Failed to file report")
+		return
+	}
+	if !created {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("No %s with that id", req.EntityType)})
+		return
+	}
+
+	respond(c, http.StatusCreated, gin.H{
+		"data":    report,
+		"message": "Report received",
+	})
+}
+
+// ListReports handles GET /api/v1/admin/reports?status=open&reason=
+// status defaults to open; "all" lists every report. reason narrows the list
+// to review reports with that reason. "reasons" counts the review reports
+// with each reason whatever reason is, so the queue can show them as tabs.
+func (h *ReportHandler) ListReports(c *gin.Context) {
+	status := c.DefaultQuery("status", models.ReportOpen)
+	if status == "all" {
+		status = ""
+	} else if !isReportStatus(status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unknown status %q", status)})
+		return
+	}
+	reason := c.Query("reason")
+	if reason != "" && !slices.Contains(models.ReportReasons, reason) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unknown reason %q", reason)})
+		return
+	}
+
+	reports, err := h.repo.List(c.Request.Context(), status)
+	if err != nil {
+		serverError(c, err, "Failed to fetch reports")
+		return
+	}
+
+	reasons := make(map[string]int, len(models.ReportReasons))
+	for _, name := range models.ReportReasons {
+		reasons[name] = 0
+	}
+	listed := make([]models.Report, 0, len(reports))
+	for _, report := range reports {
+		if report.Reason.Valid {
+			reasons[report.Reason.String]++
+		}
+		if reason == "" || report.Reason.String == reason {
+			listed = append(listed, report)
+		}
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"data":    listed,
+		"count":   len(listed),
+		"reasons": reasons,
+	})
+}
+
+// ResolveReport handles POST /api/v1/admin/reports/:id/resolve
+// Use once the reported metadata has been corrected, or to uphold a report on
+// a review, which keeps the review hidden.
+func (h *ReportHandler) ResolveReport(c *gin.Context) {
+	h.close(c, models.ReportResolved, "Report resolved")
+}
+
+// DismissReport handles POST /api/v1/admin/reports/:id/dismiss
+// Dismissing every report on a review the reports hid republishes it.
+func (h *ReportHandler) DismissReport(c *gin.Context) {
+	h.close(c, models.ReportDismissed, "Report dismissed")
+}
+
+func (h *ReportHandler) close(c *gin.Context, status, message string) {
+	closed, err := h.repo.Close(c.Request.Context(), c.Param("id"), status)
+	if err != nil {
+		serverError(c, err, "Failed to update report")
+		return
+	}
+	if closed == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No open report with that id"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message})
+}
+
+func isReportStatus(status string) bool {
+	for _, s := range models.ReportStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"yuplan/internal/dbtypes"
+	"yuplan/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockReportRepository struct {
+	entities map[string]bool // entity ids that exist
+	reports  []models.Report
+	created  *models.Report // as the handler filed it
+	listedAs string
+	err      error
+}
+
+func (m *mockReportRepository) Create(ctx context.Context, report *models.Report) (bool, error) {
+	if m.err != nil {
+		return false, m.err
+	}
+	if !m.entities[report.EntityID] {
+		return false, nil
+	}
+	report.ID = "rep-1"
+	report.Status = models.ReportOpen
+	created := *report // respond redacts the original in place
+	m.created = &created
+	return true, nil
+}
+
+func (m *mockReportRepository) List(ctx context.Context, status string) ([]models.Report, error) {
+	m.listedAs = status
+	return m.reports, m.err
+}
+
+func (m *mockReportRepository) Close(ctx context.Context, id, status string) (*models.Report, error) {
+	for i := range m.reports {
+		if m.reports[i].ID == id && m.reports[i].Status == models.ReportOpen {
+			m.reports[i].Status = status
+			return &m.reports[i], nil
+		}
+	}
+	return nil, m.err
+}
+
+const reportedInstructor = "2b1f4c3e-8d6a-4f0e-9a57-3c1d2e4f5a6b"
+
+func newReportRouter(repo *mockReportRepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewReportHandler(repo)
+	router := gin.New()
+	router.POST("/reports", handler.CreateReport)
+	router.GET("/admin/reports", handler.ListReports)
+	router.POST("/admin/reports/:id/resolve", handler.ResolveReport)
+	router.POST("/admin/reports/:id/dismiss", handler.DismissReport)
+	return router
+}
+
+func serveReports(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+	return w
+}
+
+func TestCreateReport(t *testing.T) {
+	repo := &mockReportRepository{entities: map[string]bool{reportedInstructor: true}}
+	router := newReportRouter(repo)
+
+	w := serveReports(router, http.MethodPost, "/reports", `{"type": "broken_rmp_link", "entity_type": "instructor",
+		"entity_id": "`+reportedInstructor+`", "details": "  Links to someone else  ", "email": "student@my.yorku.ca"}`)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "Links to someone else", repo.created.Details.String)
+	assert.Equal(t, "student@my.yorku.ca", repo.created.Email.String)
+
+	var body struct {
+		Data map[string]any `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "open", body.Data["status"])
+	assert.NotContains(t, body.Data, "email")
+}
+
+func TestCreateReport_Review(t *testing.T) {
+	repo := &mockReportRepository{entities: map[string]bool{reportedInstructor: true}}
+	router := newReportRouter(repo)
+
+	w := serveReports(router, http.MethodPost, "/reports", `{"type": "inappropriate_review", "entity_type": "review", "entity_id": "`+reportedInstructor+`"}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, models.ReportEntityReview, repo.created.EntityType)
+	assert.Equal(t, models.ReportReasonOther, repo.created.Reason.String, "reason defaults to other")
+
+	w = serveReports(router, http.MethodPost, "/reports", `{"type": "inappropriate_review", "entity_type": "review", "entity_id": "`+reportedInstructor+`", "reason": "harassment"}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, models.ReportReasonHarassment, repo.created.Reason.String)
+
+	w = serveReports(router, http.MethodPost, "/reports", `{"type": "inappropriate_review", "entity_type": "course", "entity_id": "`+reportedInstructor+`"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCreateReport_BlankDetailsAreNull(t *testing.T) {
+	repo := &mockReportRepository{entities: map[string]bool{reportedInstructor: true}}
+	router := newReportRouter(repo)
+
+	w := serveReports(router, http.MethodPost, "/reports", `{"type": "wrong_instructor_info", "entity_type": "instructor", "entity_id": "`+reportedInstructor+`", "details": "   "}`)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.False(t, repo.created.Details.Valid)
+	assert.False(t, repo.created.Email.Valid)
+}
+
+func TestCreateReport_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"unknown type", `{"type": "spam", "entity_type": "course", "entity_id": "` + reportedInstructor + `"}`, `Unknown report type \"spam\"`},
+		{"wrong entity", `{"type": "broken_rmp_link", "entity_type": "course", "entity_id": "` + reportedInstructor + `"}`, "must refer to a instructor"},
+		{"bad id", `{"type": "broken_rmp_link", "entity_type": "instructor", "entity_id": "42"}`, "EntityID"},
+		{"bad email", `{"type": "broken_rmp_link", "entity_type": "instructor", "entity_id": "` + reportedInstructor + `", "email": "nope"}`, "Email"},
+		{"unknown reason", `{"type": "inappropriate_review", "entity_type": "review", "entity_id": "` + reportedInstructor + `", "reason": "rude"}`, "Reason must be one of spam, harassment"},
+		{"reason on metadata", `{"type": "broken_rmp_link", "entity_type": "instructor", "entity_id": "` + reportedInstructor + `", "reason": "spam"}`, "Only review reports take a reason"},
+		{"long details", `{"type": "broken_rmp_link", "entity_type": "instructor", "entity_id": "` + reportedInstructor + `", "details": "` + strings.Repeat("x", maxReportDetails+1) + `"}`, "at most 2000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockReportRepository{entities: map[string]bool{reportedInstructor: true}}
+			w := serveReports(newReportRouter(repo), http.MethodPost, "/reports", tt.body)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), tt.want)
+			assert.Nil(t, repo.created)
+		})
+	}
+}
+
+func TestCreateReport_MissingEntity(t *testing.T) {
+	router := newReportRouter(&mockReportRepository{})
+
+	w := serveReports(router, http.MethodPost, "/reports", `{"type": "offensive_course_resource", "entity_type": "course", "entity_id": "`+reportedInstructor+`"}`)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "No course with that id")
+}
+
+func TestCreateReport_RepoError(t *testing.T) {
+	router := newReportRouter(&mockReportRepository{err: errors.New("db down")})
+
+	w := serveReports(router, http.MethodPost, "/reports", `{"type": "broken_rmp_link", "entity_type": "instructor", "entity_id": "`+reportedInstructor+`"}`)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestListReports(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"", "open"},
+		{"?status=resolved", "resolved"},
+		{"?status=all", ""},
+	}
+	for _, tt := range tests {
+		repo := &mockReportRepository{reports: []models.Report{{ID: "rep-1", Status: models.ReportOpen}}}
+		w := serveReports(newReportRouter(repo), http.MethodGet, "/admin/reports"+tt.query, "")
+
+		assert.Equal(t, http.StatusOK, w.Code, tt.query)
+		assert.Equal(t, tt.want, repo.listedAs, tt.query)
+		assert.Contains(t, w.Body.String(), `"count":1`)
+	}
+
+	w := serveReports(newReportRouter(&mockReportRepository{}), http.MethodGet, "/admin/reports?status=closed", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = serveReports(newReportRouter(&mockReportRepository{}), http.MethodGet, "/admin/reports?reason=rude", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestListReports_Reasons(t *testing.T) {
+	repo := &mockReportRepository{reports: []models.Report{
+		{ID: "rep-1", Status: models.ReportOpen, EntityType: models.ReportEntityReview, Reason: dbtypes.NewNullString(models.ReportReasonSpam)},
+		{ID: "rep-2", Status: models.ReportOpen, EntityType: models.ReportEntityReview, Reason: dbtypes.NewNullString(models.ReportReasonSpam)},
+		{ID: "rep-3", Status: models.ReportOpen, EntityType: models.ReportEntityReview, Reason: dbtypes.NewNullString(models.ReportReasonOffTopic)},
+		{ID: "rep-4", Status: models.ReportOpen, EntityType: models.ReportEntityInstructor},
+	}}
+
+	var body struct {
+		Data    []models.Report `json:"data"`
+		Count   int             `json:"count"`
+		Reasons map[string]int  `json:"reasons"`
+	}
+	w := serveReports(newReportRouter(repo), http.MethodGet, "/admin/reports", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, 4, body.Count)
+	assert.Equal(t, map[string]int{"spam": 2, "harassment": 0, "misinformation": 0, "off_topic": 1, "other": 0}, body.Reasons)
+
+	// Narrowing by reason keeps the counts of the whole queue
+	w = serveReports(newReportRouter(repo), http.MethodGet, "/admin/reports?reason=spam", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, 2, body.Count)
+	assert.Equal(t, "rep-2", body.Data[1].ID)
+	assert.Equal(t, 1, body.Reasons["off_topic"])
+}
+
+func TestResolveAndDismissReport(t *testing.T) {
+	repo := &mockReportRepository{reports: []models.Report{
+		{ID: "rep-1", Status: models.ReportOpen},
+		{ID: "rep-2", Status: models.ReportOpen},
+	}}
+	router := newReportRouter(repo)
+
+	assert.Equal(t, http.StatusOK, serveReports(router, http.MethodPost, "/admin/reports/rep-1/resolve", "").Code)
+	assert.Equal(t, models.ReportResolved, repo.reports[0].Status)
+
+	assert.Equal(t, http.StatusOK, serveReports(router, http.MethodPost, "/admin/reports/rep-2/dismiss", "").Code)
+	assert.Equal(t, models.ReportDismissed, repo.reports[1].Status)
+
+	w := serveReports(router, http.MethodPost, "/admin/reports/rep-1/dismiss", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"yuplan/internal/models"
+	"yuplan/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxRequisiteDepth is how far ?depth=full follows prerequisite chains.
+const maxRequisiteDepth = 10
+
+// maxTranscriptCodes caps each course list a readiness check takes.
+const maxTranscriptCodes = 100
+
+// transferFinder maps external courses to York courses. Implemented by repository.TransferRepository.
+type transferFinder interface {
+	FindEquivalencies(ctx context.Context, institution string, externalCodes []string) ([]models.Equivalency, error)
+}
+
+type RequisiteHandler struct {
+	repo      repository.RequisiteRepositoryInterface
+	courses   repository.CourseRepositoryInterface
+	transfers transferFinder
+}
+
+func NewRequisiteHandler(repo repository.RequisiteRepositoryInterface, courses repository.CourseRepositoryInterface) *RequisiteHandler {
+	return &RequisiteHandler{repo: repo, courses: courses}
+}
+
+// GetPrerequisites handles GET /api/v1/courses/:course_code/prerequisites?depth=1
+// Returns the course's prerequisite groups, corequisites and exclusions.
+// depth is how many levels of prerequisites to resolve: 1 (default) for
+// direct ones only, up to maxRequisiteDepth, or "full" for the whole chain.
+func (h *RequisiteHandler) GetPrerequisites(c *gin.Context) {
+	depth, err := requisiteDepth(c.DefaultQuery("depth", "1"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	code, name, ok := h.course(c)
+	if !ok {
+		return
+	}
+
+	edges, err := h.repo.Graph(c.Request.Context(), code, depth)
+	if err != nil {
+		serverError(c, err, "Failed to fetch prerequisites")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": models.BuildRequisiteTree(code, name, edges, depth)})
+}
+
+// WithTransfers lets readiness checks count transfer credit.
+func (h *RequisiteHandler) WithTransfers(transfers transferFinder) *RequisiteHandler {
+	h.transfers = transfers
+	return h
+}
+
+// GetReadiness handles
+// GET /api/v1/users/me/readiness/:course_code?completed=EECS1012,MATH1090&planned=EECS2001&institution=Seneca&transfer=IPC144
+// The API keeps no transcripts, so the student's completed and planned York
+// courses come with the request, plus courses from another institution that
+// count as the York courses they are mapped to. Reports whether the course
+// can be taken and, if not, exactly which requirements are missing.
+func (h *RequisiteHandler) GetReadiness(c *gin.Context) {
+	completed, err := courseCodeSet(c.Query("completed"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "completed: " + err.Error()})
+		return
+	}
+	planned, err := courseCodeSet(c.Query("planned"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "planned: " + err.Error()})
+		return
+	}
+	transfer, err := courseCodeSet(c.Query("transfer"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "transfer: " + err.Error()})
+		return
+	}
+	institution := models.NormalizeInstitution(c.Query("institution"))
+	if len(transfer) > 0 && (institution == "" || h.transfers == nil) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "transfer requires institution"})
+		return
+	}
+
+	code, name, ok := h.course(c)
+	if !ok {
+		return
+	}
+
+	var credits []models.TransferCredit
+	if len(transfer) > 0 {
+		codes := make([]string, 0, len(transfer))
+		for code := range transfer {
+			codes = append(codes, code)
+		}
+		equivalencies, err := h.transfers.FindEquivalencies(c.Request.Context(), institution, codes)
+		if err != nil {
+			serverError(c, err, "Failed to fetch transfer credits")
+			return
+		}
+		for _, eq := range equivalencies {
+			york := models.NormalizeCourseCode(eq.YorkCourseCode)
+			completed[york] = true
+			credits = append(credits, models.TransferCredit{
+				ExternalCourseCode: eq.ExternalCourseCode,
+				YorkCourseCode:     york,
+				Confidence:         eq.Confidence,
+			})
+		}
+	}
+
+	edges, err := h.repo.Graph(c.Request.Context(), code, 1)
+	if err != nil {
+		serverError(c, err, "Failed to fetch prerequisites")
+		return
+	}
+
+	readiness := models.CheckReadiness(models.BuildRequisiteTree(code, name, edges, 1), completed, planned)
+	if credits != nil {
+		readiness.TransferCredits = credits
+	}
+	c.JSON(http.StatusOK, gin.H{"data": readiness})
+}
+
+// SetRequisites handles PUT /api/v1/admin/courses/:course_code/requisites
+// Replaces the course's requisites; see models.SetRequisitesRequest.
+func (h *RequisiteHandler) SetRequisites(c *gin.Context) {
+	var req models.SetRequisitesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	code, name, ok := h.course(c)
+	if !ok {
+		return
+	}
+
+	edges := req.Requisites(code)
+	if err := h.repo.Set(c.Request.Context(), code, edges); err != nil {
+		serverError(c, err, "Failed to save requisites")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":    models.BuildRequisiteTree(code, name, edges, 1),
+		"message": "Requisites updated",
+	})
+}
+
+// course looks up :course_code, returning its stored code and name, and
+// writes the error response itself when it doesn't exist.
+func (h *RequisiteHandler) course(c *gin.Context) (code, name string, ok bool) {
+	courses, err := h.courses.GetByCode(c.Request.Context(), c.Param("course_code"))
+	if err != nil {
+		serverError(c, err, "Failed to fetch course")
+		return "", "", false
+	}
+	if len(courses) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+		return "", "", false
+	}
+	return models.NormalizeCourseCode(courses[0].Code), courses[0].Name, true
+}
+
+func requisiteDepth(raw string) (int, error) {
+	if raw == "full" {
+		return maxRequisiteDepth, nil
+	}
+	depth, err := strconv.Atoi(raw)
+	if err != nil || depth < 1 || depth > maxRequisiteDepth {
+		return 0, fmt.Errorf("depth must be between 1 and %d, or full", maxRequisiteDepth)
+	}
+	return depth, nil
+}
+
+// courseCodeSet parses a comma-separated list of course codes into a set of
+// normalized codes, holding it to maxTranscriptCodes.
+func courseCodeSet(raw string) (map[string]bool, error) {
+	codes := map[string]bool{}
+	for _, code := range strings.Split(raw, ",") {
+		if code = models.NormalizeCourseCode(code); code != "" {
+			codes[code] = true
+		}
+	}
+	if len(codes) > maxTranscriptCodes {
+		return nil, fmt.Errorf("at most %d courses", maxTranscriptCodes)
+	}
+	return codes, nil
+}
@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"yuplan/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockRequisiteRepository struct {
+	edges     []models.Requisite
+	err       error
+	graphedAt int
+	set       []models.Requisite
+}
+
+func (m *mockRequisiteRepository) Graph(ctx context.Context, courseCode string, depth int) ([]models.Requisite, error) {
+	m.graphedAt = depth
+	return m.edges, m.err
+}
+
+func (m *mockRequisiteRepository) Set(ctx context.Context, courseCode string, requisites []models.Requisite) error {
+	m.set = requisites
+	return m.err
+}
+
+func newRequisiteRouter(repo *mockRequisiteRepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	courses := &MockCourseRepository{getByCode: func(ctx context.Context, code string) ([]models.Course, error) {
+		if models.NormalizeCourseCode(code) != "EECS3101" {
+			return []models.Course{}, nil
+		}
+		return []models.Course{{ID: "c-1", Code: "EECS3101", Name: "Design and Analysis of Algorithms", Term: models.TermFall}}, nil
+	}}
+	transfers := &mockTransferRepository{findFunc: func(ctx context.Context, institution string, externalCodes []string) ([]models.Equivalency, error) {
+		if institution != "seneca college" || len(externalCodes) != 1 || externalCodes[0] != "BTP200" {
+			return []models.Equivalency{}, nil
+		}
+		return []models.Equivalency{{ExternalCourseCode: "BTP200", YorkCourseCode: "EECS2030", Confidence: "high"}}, nil
+	}}
+	handler := NewRequisiteHandler(repo, courses).WithTransfers(transfers)
+	router := gin.New()
+	router.GET("/courses/:course_code/prerequisites", handler.GetPrerequisites)
+	router.PUT("/admin/courses/:course_code/requisites", handler.SetRequisites)
+	router.GET("/users/me/readiness/:course_code", handler.GetReadiness)
+	return router
+}
+
+func serveRequisites(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+	return w
+}
+
+func TestGetPrerequisites(t *testing.T) {
+	repo := &mockRequisiteRepository{edges: []models.Requisite{
+		{CourseCode: "EECS3101", Kind: models.RequisitePrerequisite, Group: 1, RequisiteCode: "EECS2030", RequisiteName: "Advanced Object Oriented Programming"},
+		{CourseCode: "EECS3101", Kind: models.RequisiteExclusion, RequisiteCode: "EECS3100"},
+	}}
+
+	w := serveRequisites(newRequisiteRouter(repo), http.MethodGet, "/courses/eecs3101/prerequisites", "")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 1, repo.graphedAt)
+	assert.Contains(t, w.Body.String(), `"course_code":"EECS3101","name":"Design and Analysis of Algorithms","depth":1`)
+	assert.Contains(t, w.Body.String(), `"prerequisites":[{"any_of":[{"course_code":"EECS2030","name":"Advanced Object Oriented Programming"}]}]`)
+	assert.Contains(t, w.Body.String(), `"exclusions":[{"course_code":"EECS3100"}]`)
+}
+
+func TestGetPrerequisites_Depth(t *testing.T) {
+	tests := []struct {
+		query string
+		code  int
+		depth int
+	}{
+		{"?depth=full", http.StatusOK, maxRequisiteDepth},
+		{"?depth=3", http.StatusOK, 3},
+		{"?depth=0", http.StatusBadRequest, 0},
+		{"?depth=11", http.StatusBadRequest, 0},
+		{"?depth=all", http.StatusBadRequest, 0},
+	}
+	for _, tt := range tests {
+		repo := &mockRequisiteRepository{}
+		w := serveRequisites(newRequisiteRouter(repo), http.MethodGet, "/courses/EECS3101/prerequisites"+tt.query, "")
+
+		assert.Equal(t, tt.code, w.Code, tt.query)
+		assert.Equal(t, tt.depth, repo.graphedAt, tt.query)
+	}
+}
+
+func TestGetPrerequisites_Errors(t *testing.T) {
+	w := serveRequisites(newRequisiteRouter(&mockRequisiteRepository{}), http.MethodGet, "/courses/NOPE1000/prerequisites", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = serveRequisites(newRequisiteRouter(&mockRequisiteRepository{err: errors.New("db down")}), http.MethodGet, "/courses/EECS3101/prerequisites", "")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestSetRequisites(t *testing.T) {
+	repo := &mockRequisiteRepository{}
+
+	w := serveRequisites(newRequisiteRouter(repo), http.MethodPut, "/admin/courses/EECS3101/requisites",
+		`{"prerequisites": [["EECS2030"], ["MATH1090", "MATH1019"]], "exclusions": ["eecs 3100"]}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, repo.set, 4)
+	assert.Equal(t, "EECS3100", repo.set[3].RequisiteCode)
+	assert.Contains(t, w.Body.String(), `{"any_of":[{"course_code":"MATH1090"},{"course_code":"MATH1019"}]}`)
+}
+
+func TestSetRequisites_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		body string
+		code int
+	}{
+		{"empty group", "/admin/courses/EECS3101/requisites", `{"prerequisites": [[]]}`, http.StatusBadRequest},
+		{"blank code", "/admin/courses/EECS3101/requisites", `{"exclusions": [""]}`, http.StatusBadRequest},
+		{"unknown course", "/admin/courses/NOPE1000/requisites", `{}`, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockRequisiteRepository{}
+			w := serveRequisites(newRequisiteRouter(repo), http.MethodPut, tt.path, tt.body)
+			assert.Equal(t, tt.code, w.Code)
+			assert.Nil(t, repo.set)
+		})
+	}
+}
+
+func TestGetReadiness(t *testing.T) {
+	repo := &mockRequisiteRepository{edges: []models.Requisite{
+		{CourseCode: "EECS3101", Kind: models.RequisitePrerequisite, Group: 1, RequisiteCode: "EECS2030"},
+		{CourseCode: "EECS3101", Kind: models.RequisitePrerequisite, Group: 2, RequisiteCode: "MATH1090"},
+		{CourseCode: "EECS3101", Kind: models.RequisitePrerequisite, Group: 2, RequisiteCode: "MATH1019"},
+		{CourseCode: "EECS3101", Kind: models.RequisiteCorequisite, Group: 1, RequisiteCode: "EECS2001"},
+		{CourseCode: "EECS3101", Kind: models.RequisiteExclusion, RequisiteCode: "EECS3100"},
+	}}
+	router := newRequisiteRouter(repo)
+	codes := make([]string, maxTranscriptCodes+1)
+	for i := range codes {
+		codes[i] = fmt.Sprintf("EECS%04d", i)
+	}
+	tooManyCodes := strings.Join(codes, ",")
+
+	tests := []struct {
+		name     string
+		query    string
+		wantCode int
+		want     []string
+	}{
+		{"eligible", "?completed=eecs2030,MATH%201019&planned=EECS2001", http.StatusOK,
+			[]string{`"eligible":true`, `"missing_prerequisites":[]`, `"missing_corequisites":[]`}},
+		{"missing", "?completed=EECS2030", http.StatusOK, []string{
+			`"eligible":false`,
+			`"missing_prerequisites":[{"any_of":[{"course_code":"MATH1090"},{"course_code":"MATH1019"}]}]`,
+			`"missing_corequisites":[{"any_of":[{"course_code":"EECS2001"}]}]`,
+		}},
+		{"excluded", "?completed=EECS2030,MATH1090,EECS2001,EECS3100", http.StatusOK,
+			[]string{`"eligible":false`, `"excluded_by":[{"course_code":"EECS3100"}]`}},
+		{"transfer credit", "?completed=MATH1090,EECS2001&institution=Seneca%20College&transfer=btp200", http.StatusOK, []string{
+			`"eligible":true`,
+			`"transfer_credits":[{"external_course_code":"BTP200","york_course_code":"EECS2030","confidence":"high"}]`,
+		}},
+		{"transfer without institution", "?transfer=BTP200", http.StatusBadRequest, nil},
+		{"too many courses", "?completed=" + tooManyCodes, http.StatusBadRequest, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveRequisites(router, http.MethodGet, "/users/me/readiness/eecs3101"+tt.query, "")
+			assert.Equal(t, tt.wantCode, w.Code, w.Body.String())
+			for _, want := range tt.want {
+				assert.Contains(t, w.Body.String(), want)
+			}
+		})
+	}
+
+	w := serveRequisites(router, http.MethodGet, "/users/me/readiness/EECS9999", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"reflect"
+	"yuplan/internal/redact"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respond writes obj as JSON without the fields the caller's role may not see
+// (see redact). Responses that carry models with redact tags, and every list
+// response, go through here rather than c.JSON.
+//
+// Nil slices in a gin.H, and in the gin.H values nested in it, are written as
+// [] rather than null, so clients can iterate "data" and the like without
+// checking for null first.
+func respond(c *gin.Context, status int, obj any) {
+	if h, ok := obj.(gin.H); ok {
+		emptyNilSlices(h)
+	}
+	redact.Apply(&obj, redact.RoleFrom(c.Request.Context()))
+	c.JSON(status, obj)
+}
+
+// emptyNilSlices replaces each nil slice in h with an empty one of the same type.
+func emptyNilSlices(h gin.H) {
+	for key, value := range h {
+		if nested, ok := value.(gin.H); ok {
+			emptyNilSlices(nested)
+			continue
+		}
+		v := reflect.ValueOf(value)
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			h[key] = reflect.MakeSlice(v.Type(), 0, 0).Interface()
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRespond_NilSlicesAreEmpty(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+
+	var reviews []models.Review
+	var reasons []string
+	respond(c, http.StatusOK, gin.H{
+		"data":   reviews,
+		"count":  0,
+		"nested": gin.H{"reasons": reasons},
+		"detail": (*models.Review)(nil),
+	})
+
+	assert.JSONEq(t, `{"data": [], "count": 0, "nested": {"reasons": []}, "detail": null}`, w.Body.String())
+}
+
+// Every list endpoint answers "data": [] rather than null when its repository
+// hands back a nil slice.
+func TestListEndpoints_NilDataIsEmpty(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	courses := &MockCourseRepository{
+		getPaginatedCourses: func(ctx context.Context, page, pageSize int, faculty, courseCodeRange, termID *string, sorts []models.CourseSort) ([]models.Course, error) {
+			return nil, nil
+		},
+		getCoursesCount: func(ctx context.Context, faculty, courseCodeRange, termID *string) (int, error) {
+			return 0, nil
+		},
+	}
+	reviews := &mockReviewRepository{
+		getByCourseCodeFunc: func(ctx context.Context, courseCode string, sortBy, deliveryMode string, limit, offset int) ([]models.Review, error) {
+			return nil, nil
+		},
+	}
+	labs := &MockLabRepository{getBySectionID: func(ctx context.Context, sectionID string) ([]models.Lab, error) {
+		return nil, nil
+	}}
+	tutorials := &MockTutorialRepository{getBySectionID: func(ctx context.Context, sectionID string) ([]models.Tutorial, error) {
+		return nil, nil
+	}}
+	sections := &MockSectionRepository{getByCourseID: func(ctx context.Context, courseID string) ([]models.Section, error) {
+		return nil, nil
+	}}
+	terms := &mockTermRepository{listFunc: func(ctx context.Context) ([]models.AcademicTerm, error) {
+		return nil, nil
+	}}
+
+	router := gin.New()
+	router.GET("/courses/paginated", NewCourseHandler(courses, nil).GetPaginatedCourses)
+	router.GET("/courses/:course_code/reviews", NewReviewHandler(reviews).WithStatsWindow(time.Hour).GetReviews)
+	router.GET("/labs/:section_id", NewLabHandler(labs).GetLabsBySectionID)
+	router.GET("/tutorials/:section_id", NewTutorialHandler(tutorials).GetTutorialsBySectionID)
+	router.GET("/sections/:course_id", NewSectionHandler(sections).GetSectionsByCourseID)
+	router.GET("/admin/terms", NewTermHandler(terms).ListTerms)
+
+	for _, path := range []string{
+		"/courses/paginated",
+		"/courses/EECS2030/reviews",
+		"/labs/s1",
+		"/tutorials/s1",
+		"/sections/c1",
+		"/admin/terms",
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusOK, w.Code, path)
+		assert.Contains(t, w.Body.String(), `"data":[]`, path)
+		assert.NotContains(t, w.Body.String(), `null`, path)
+	}
+}
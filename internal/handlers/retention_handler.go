@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"yuplan/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// retentionPurger applies retention policies. Implemented by retention.Purger.
+type retentionPurger interface {
+	Run(ctx context.Context, dryRun bool) ([]models.RetentionStats, error)
+	Stats() []models.RetentionStats
+	DryRun() bool
+}
+
+type RetentionHandler struct {
+	purger retentionPurger
+}
+
+func NewRetentionHandler(purger retentionPurger) *RetentionHandler {
+	return &RetentionHandler{purger: purger}
+}
+
+// GetRetention handles GET /api/v1/admin/retention
+func (h *RetentionHandler) GetRetention(c *gin.Context) {
+	stats := h.purger.Stats()
+	respond(c, http.StatusOK, gin.H{
+		"data":    stats,
+		"count":   len(stats),
+		"dry_run": h.purger.DryRun(),
+	})
+}
+
+// RunRetention handles POST /api/v1/admin/retention/run?dry_run=true. Without
+// dry_run it purges unless RETENTION_DRY_RUN is set.
+func (h *RetentionHandler) RunRetention(c *gin.Context) {
+	dryRun := h.purger.DryRun()
+	if v := c.Query("dry_run"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "dry_run must be true or false"})
+			return
+		}
+		dryRun = b
+	}
+
+	stats, err := h.purger.Run(c.Request.Context(), dryRun)
+	if err != nil {
+		serverError(c, err, "Failed to apply retention policies")
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"data":    stats,
+		"count":   len(stats),
+		"dry_run": dryRun,
+	})
+}
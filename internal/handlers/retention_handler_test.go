@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"yuplan/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockRetentionPurger struct {
+	dryRun    bool
+	gotDryRun *bool
+	err       error
+}
+
+func (m *mockRetentionPurger) Run(ctx context.Context, dryRun bool) ([]models.RetentionStats, error) {
+	m.gotDryRun = &dryRun
+	return m.Stats(), m.err
+}
+
+func (m *mockRetentionPurger) Stats() []models.RetentionStats {
+	return []models.RetentionStats{{Policy: models.RetentionSearchStats, MaxAgeDays: 730, Runs: 1, LastMatched: 12}}
+}
+
+func (m *mockRetentionPurger) DryRun() bool { return m.dryRun }
+
+func retentionRouter(purger *mockRetentionPurger) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewRetentionHandler(purger)
+	router := gin.New()
+	router.GET("/admin/retention", handler.GetRetention)
+	router.POST("/admin/retention/run", handler.RunRetention)
+	return router
+}
+
+func TestGetRetention(t *testing.T) {
+	router := retentionRouter(&mockRetentionPurger{dryRun: true})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/retention", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"policy":"search_stats"`)
+	assert.Contains(t, w.Body.String(), `"dry_run":true`)
+}
+
+func TestRunRetention_DryRunParam(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		configured bool
+		wantCode   int
+		wantDryRun *bool
+	}{
+		{"defaults to configured mode", "", true, http.StatusOK, boolPtr(true)},
+		{"explicit dry run", "?dry_run=true", false, http.StatusOK, boolPtr(true)},
+		{"explicit purge", "?dry_run=false", true, http.StatusOK, boolPtr(false)},
+		{"invalid", "?dry_run=perhaps", false, http.StatusBadRequest, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			purger := &mockRetentionPurger{dryRun: tt.configured}
+			router := retentionRouter(purger)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/retention/run"+tt.query, nil))
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			assert.Equal(t, tt.wantDryRun, purger.gotDryRun)
+		})
+	}
+}
+
+func TestRunRetention_Error(t *testing.T) {
+	router := retentionRouter(&mockRetentionPurger{err: errors.New("retention policy search_stats: boom")})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/retention/run", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func boolPtr(b bool) *bool { return &b }
@@ -1,21 +1,196 @@
 package handlers
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
-	"strconv"
+	"slices"
+	"time"
+	"yuplan/internal/calibration"
+	"yuplan/internal/config"
+	"yuplan/internal/contentfilter"
+	"yuplan/internal/dbtypes"
+	"yuplan/internal/id"
+	"yuplan/internal/markdown"
 	"yuplan/internal/models"
+	"yuplan/internal/redact"
 	"yuplan/internal/repository"
 
 	"github.com/gin-gonic/gin"
+	"golang.org/x/sync/errgroup"
 )
 
+// rateQuota reports how many requests a client key has left in the current window.
+type rateQuota interface {
+	Remaining(key string) int
+}
+
+// examCalendar finds the term whose exams are under way, if any.
+type examCalendar interface {
+	CurrentExamPeriod(ctx context.Context) (*models.AcademicTerm, error)
+}
+
+// badgeLookup returns the badge slugs held by each reviewer email. Implemented by repository.BadgeRepository.
+type badgeLookup interface {
+	BadgesByEmail(ctx context.Context, emails []string) (map[string][]string, error)
+}
+
+// reviewEvents records review lifecycle events for analytics. Implemented by repository.ReviewEventRepository.
+type reviewEvents interface {
+	Record(ctx context.Context, reviewID, event string, details map[string]any) error
+}
+
+// reviewMetrics counts review lifecycle events for monitoring. Implemented by metrics.Business.
+type reviewMetrics interface {
+	ReviewEvent(event string)
+}
+
+// departmentBaselines looks up a department's difficulty baseline, nil if it has none.
+// Implemented by repository.CalibrationRepository.
+type departmentBaselines interface {
+	GetDepartment(ctx context.Context, department string) (*models.DepartmentDifficulty, error)
+}
+
+// reviewModerator decides whether a new review is published or held for an admin.
+// Implemented by moderation.Moderator.
+type reviewModerator interface {
+	Decide(ctx context.Context, review *models.Review) (models.ModerationDecision, error)
+}
+
+// contentFilter screens review text for profanity, links and spam.
+// Implemented by contentfilter.Chain.
+type contentFilter interface {
+	Check(ctx context.Context, text string) (contentfilter.Verdict, error)
+}
+
+// reviewVerifier mails authors a link to confirm new reviews. Implemented by verification.Verifier.
+type reviewVerifier interface {
+	Request(ctx context.Context, review *models.Review, moderation string) error
+	Resend(ctx context.Context, courseCode, reviewID, email string) (bool, error)
+	Confirm(ctx context.Context, token string) (*models.Review, error)
+}
+
+// defaultStatsWindow keeps course stats focused on recent offerings, so a course
+// overhauled a few years ago isn't dragged down by reviews of the old version.
+const defaultStatsWindow = 3 * 365 * 24 * time.Hour
+
 type ReviewHandler struct {
-	repo repository.ReviewRepositoryInterface
+	repo        repository.ReviewRepositoryInterface
+	quota       rateQuota
+	statsWindow time.Duration
+	calendar    examCalendar
+	tunables    tunablesSource
+	badges      badgeLookup
+	events      reviewEvents
+	metrics     reviewMetrics
+	baselines   departmentBaselines
+	moderator   reviewModerator
+	filter      contentFilter
+	verifier    reviewVerifier
 }
 
 func NewReviewHandler(repo repository.ReviewRepositoryInterface) *ReviewHandler {
 	return &ReviewHandler{
-		repo: repo,
+		repo:        repo,
+		statsWindow: defaultStatsWindow,
+	}
+}
+
+// WithStatsWindow overrides how far back course stats look by default.
+func (h *ReviewHandler) WithStatsWindow(window time.Duration) *ReviewHandler {
+	h.statsWindow = window
+	return h
+}
+
+// WithRateQuota lets the eligibility check report rate limiting. Without it the check is skipped.
+func (h *ReviewHandler) WithRateQuota(quota rateQuota) *ReviewHandler {
+	h.quota = quota
+	return h
+}
+
+// WithEmbargo holds reviews submitted during an exam period until the term's
+// grades are released, while the review_embargo feature flag is on.
+func (h *ReviewHandler) WithEmbargo(calendar examCalendar, tunables tunablesSource) *ReviewHandler {
+	h.calendar = calendar
+	h.tunables = tunables
+	return h
+}
+
+// WithBadges shows author badges on reviews. Without it none are shown.
+func (h *ReviewHandler) WithBadges(badges badgeLookup) *ReviewHandler {
+	h.badges = badges
+	return h
+}
+
+// WithEvents records review lifecycle events. Without it none are recorded.
+func (h *ReviewHandler) WithEvents(events reviewEvents) *ReviewHandler {
+	h.events = events
+	return h
+}
+
+// WithMetrics counts review lifecycle events as they are recorded.
+func (h *ReviewHandler) WithMetrics(metrics reviewMetrics) *ReviewHandler {
+	h.metrics = metrics
+	return h
+}
+
+// WithCalibration adds department-relative difficulty to course stats. Without it only the raw average is shown.
+func (h *ReviewHandler) WithCalibration(baselines departmentBaselines) *ReviewHandler {
+	h.baselines = baselines
+	return h
+}
+
+// WithModeration runs new reviews through the moderation rules. Without it every review is approved.
+func (h *ReviewHandler) WithModeration(moderator reviewModerator) *ReviewHandler {
+	h.moderator = moderator
+	return h
+}
+
+// WithContentFilter screens new and edited reviews. Without it nothing is screened.
+func (h *ReviewHandler) WithContentFilter(filter contentFilter) *ReviewHandler {
+	h.filter = filter
+	return h
+}
+
+// WithVerification keeps new reviews unverified until their author follows an
+// emailed link. Without it reviews take their moderation state straight away.
+func (h *ReviewHandler) WithVerification(verifier reviewVerifier) *ReviewHandler {
+	h.verifier = verifier
+	return h
+}
+
+// screen runs the review through the content filter. It responds and returns
+// false when the review is rejected; a flagged verdict is returned for the
+// caller to hold the review. A failing filter flags rather than rejects.
+func (h *ReviewHandler) screen(c *gin.Context, review *models.Review) (contentfilter.Verdict, bool) {
+	if h.filter == nil {
+		return contentfilter.Verdict{Action: contentfilter.ActionAllow}, true
+	}
+	verdict, err := h.filter.Check(c.Request.Context(), review.AuthorName.String+"\n"+review.ReviewText.String)
+	if err != nil {
+		log.Printf("filtering review for %s: %v", review.CourseCode, err)
+		return contentfilter.Verdict{Action: contentfilter.ActionFlag, Filter: "error"}, true
+	}
+	if verdict.Action == contentfilter.ActionReject {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": verdict.Reason, "code": models.ErrCodeContentRejected})
+		return verdict, false
+	}
+	return verdict, true
+}
+
+// recordEvent appends a lifecycle event. Failures are logged rather than
+// returned, since the change it describes has already been made.
+func (h *ReviewHandler) recordEvent(ctx context.Context, reviewID, event string, details map[string]any) {
+	if h.metrics != nil {
+		h.metrics.ReviewEvent(event)
+	}
+	if h.events == nil {
+		return
+	}
+	if err := h.events.Record(ctx, reviewID, event, details); err != nil {
+		log.Printf("review %s %s event: %v", reviewID, event, err)
 	}
 }
 
@@ -29,6 +204,22 @@ func (h *ReviewHandler) CreateReview(c *gin.Context) {
 		return
 	}
 
+	tags, err := normalizeReviewTags(req.Tags)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.DeliveryMode.Valid && !models.IsReviewDeliveryMode(req.DeliveryMode.String) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unknown delivery mode %q", req.DeliveryMode.String)})
+		return
+	}
+
+	academicYear, term, err := reviewTerm(req.AcademicYear, req.Term, time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	review := &models.Review{
 		CourseCode:         courseCode,
 		Email:              req.Email,
@@ -37,68 +228,694 @@ func (h *ReviewHandler) CreateReview(c *gin.Context) {
 		Difficulty:         req.Difficulty,
 		RealWorldRelevance: req.RealWorldRelevance,
 		ReviewText:         req.ReviewText,
+		DeliveryMode:       req.DeliveryMode,
+		AcademicYear:       academicYear,
+		Term:               term,
+		Tags:               tags,
+	}
+
+	if h.calendar != nil && h.tunables.Current().Enabled(config.FlagReviewEmbargo) {
+		term, err := h.calendar.CurrentExamPeriod(c.Request.Context())
+		if err != nil {
+			serverError(c, err, "Failed to check exam period")
+			return
+		}
+		if term != nil {
+			review.PublishAt = dbtypes.NewNullTime(term.GradesReleased)
+		}
+	}
+
+	verdict, ok := h.screen(c, review)
+	if !ok {
+		return
+	}
+	if verdict.Action == contentfilter.ActionFlag {
+		review.Moderation = models.ModerationPending
+	} else if h.moderator != nil {
+		decision, err := h.moderator.Decide(c.Request.Context(), review)
+		if err != nil {
+			// Hold rather than fail the submission; an admin can still approve it
+			log.Printf("moderating review for %s: %v", courseCode, err)
+			decision = models.ModerationDecision{Moderation: models.ModerationPending}
+		}
+		review.Moderation = decision.Moderation
+	}
+	// The moderation decision waits with the verification until the author confirms
+	moderation := review.Moderation
+	if h.verifier != nil {
+		if moderation == "" {
+			moderation = models.ModerationApproved
+		}
+		review.Moderation = models.ModerationUnverified
 	}
 
 	if err := h.repo.Create(c.Request.Context(), review); err != nil {
-		// Check for duplicate review (UNIQUE constraint violation)
-		if err.Error() == "ERROR: duplicate key value violates unique constraint \"reviews_course_code_email_key\" (SQLSTATE 23505)" {
-			c.JSON(http.StatusConflict, gin.H{"error": "You have already submitted a review for this course"})
+		if errors.Is(err, repository.ErrDuplicateReview) {
+			c.JSON(http.StatusConflict, gin.H{"error": "You have already submitted a review for this course this term"})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create review"})
+		serverError(c, err, "Failed to create review")
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
+	presentReview(review)
+	details := map[string]any{
+		"course_code": review.CourseCode,
+		"status":      review.Status,
+	}
+	if verdict.Action == contentfilter.ActionFlag {
+		details["flagged_by"] = verdict.Filter
+	}
+	h.recordEvent(c.Request.Context(), review.ID, models.ReviewEventCreated, details)
+
+	message := "Review created successfully"
+	switch review.Status {
+	case models.ReviewUnverified:
+		message = "Review submitted; check your email for a link to confirm it"
+		// The review is kept either way; the author can ask for another link
+		if err := h.verifier.Request(c.Request.Context(), review, moderation); err != nil {
+			log.Printf("requesting verification of review %s: %v", review.ID, err)
+			message = "Review submitted, but the confirmation email could not be sent; request another one to confirm it"
+		}
+	case models.ReviewEmbargoed:
+		message = "Review submitted; it will be published once grades are released"
+	case models.ReviewPending:
+		message = "Review submitted; it will appear once a moderator approves it"
+	}
+	respond(c, http.StatusCreated, gin.H{
 		"data":    review,
-		"message": "Review created successfully",
+		"message": message,
 	})
 }
 
+// UpdateReview handles PUT /api/v1/courses/:course_code/reviews/:review_id,
+// replacing what the review says. Only its author, identified by email, can.
+func (h *ReviewHandler) UpdateReview(c *gin.Context) {
+	var req models.UpdateReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tags, err := normalizeReviewTags(req.Tags)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.DeliveryMode.Valid && !models.IsReviewDeliveryMode(req.DeliveryMode.String) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unknown delivery mode %q", req.DeliveryMode.String)})
+		return
+	}
+
+	// Edits are screened so an approved review can't be rewritten into spam:
+	// a rejected edit is refused and a flagged one goes back to the moderators
+	edit := &models.Review{
+		ID:                 c.Param("review_id"),
+		CourseCode:         c.Param("course_code"),
+		Email:              req.Email,
+		AuthorName:         req.AuthorName,
+		Liked:              req.Liked,
+		Difficulty:         req.Difficulty,
+		RealWorldRelevance: req.RealWorldRelevance,
+		ReviewText:         req.ReviewText,
+		DeliveryMode:       req.DeliveryMode,
+		Tags:               tags,
+	}
+	verdict, ok := h.screen(c, edit)
+	if !ok {
+		return
+	}
+	if verdict.Action == contentfilter.ActionFlag {
+		edit.Moderation = models.ModerationPending
+	}
+
+	review, err := h.repo.Update(c.Request.Context(), edit)
+	if err != nil {
+		serverError(c, err, "Failed to update review")
+		return
+	}
+	// Someone else's review is reported as missing rather than forbidden, so
+	// the endpoint doesn't confirm which email wrote a review
+	if review == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No review with that id for this course from that email"})
+		return
+	}
+	presentReview(review)
+	details := map[string]any{
+		"course_code": review.CourseCode,
+		"status":      review.Status,
+	}
+	if verdict.Action == contentfilter.ActionFlag {
+		details["flagged_by"] = verdict.Filter
+	}
+	h.recordEvent(c.Request.Context(), review.ID, models.ReviewEventEdited, details)
+
+	message := "Review updated"
+	if verdict.Action == contentfilter.ActionFlag && review.Status == models.ReviewPending {
+		message = "Review updated; it will appear again once a moderator approves it"
+	}
+	respond(c, http.StatusOK, gin.H{
+		"data":    review,
+		"message": message,
+	})
+}
+
+// DeleteReview handles DELETE /api/v1/courses/:course_code/reviews/:review_id?email=
+// for the review's author.
+func (h *ReviewHandler) DeleteReview(c *gin.Context) {
+	var query struct {
+		Email string `form:"email" binding:"required,email"`
+	}
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter 'email' must be a valid email"})
+		return
+	}
+
+	reviewID, courseCode := c.Param("review_id"), c.Param("course_code")
+	deleted, err := h.repo.Delete(c.Request.Context(), reviewID, courseCode, query.Email)
+	if err != nil {
+		serverError(c, err, "Failed to delete review")
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No review with that id for this course from that email"})
+		return
+	}
+	h.recordEvent(c.Request.Context(), reviewID, models.ReviewEventDeleted, map[string]any{
+		"course_code": courseCode,
+	})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Review deleted"})
+}
+
+// VerifyReview handles GET /api/v1/reviews/verify?token=, the link mailed to
+// the author of a new review. The review then publishes, or goes to the
+// moderators if it was held.
+func (h *ReviewHandler) VerifyReview(c *gin.Context) {
+	if h.verifier == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Review verification is not enabled"})
+		return
+	}
+	review, err := h.verifier.Confirm(c.Request.Context(), c.Query("token"))
+	if err != nil {
+		serverError(c, err, "Failed to verify review")
+		return
+	}
+	if review == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "This link is invalid or has expired; request another one"})
+		return
+	}
+	presentReview(review)
+	h.recordEvent(c.Request.Context(), review.ID, models.ReviewEventVerified, map[string]any{
+		"course_code": review.CourseCode,
+		"status":      review.Status,
+	})
+
+	message := "Review confirmed and published"
+	switch review.Status {
+	case models.ReviewEmbargoed:
+		message = "Review confirmed; it will be published once grades are released"
+	case models.ReviewPending:
+		message = "Review confirmed; it will appear once a moderator approves it"
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":    gin.H{"id": review.ID, "course_code": review.CourseCode, "status": review.Status},
+		"message": message,
+	})
+}
+
+// ResendVerification handles POST /api/v1/courses/:course_code/reviews/:review_id/verification,
+// mailing a new confirmation link for an unverified review to its author.
+func (h *ReviewHandler) ResendVerification(c *gin.Context) {
+	if h.verifier == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Review verification is not enabled"})
+		return
+	}
+	var req models.ResendVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sent, err := h.verifier.Resend(c.Request.Context(), c.Param("course_code"), c.Param("review_id"), req.Email)
+	if err != nil {
+		serverError(c, err, "Failed to send confirmation email")
+		return
+	}
+	if !sent {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No unverified review with that id for this course from that email"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Confirmation email sent"})
+}
+
+// firstAcademicYear is York's first session; no review can be about an earlier one.
+const firstAcademicYear = 1959
+
+// reviewTerm validates the term a review is about, given as both an academic
+// year and a term or neither. Neither means the term in progress at now.
+func reviewTerm(academicYear int, term string, now time.Time) (int, string, error) {
+	currentYear, currentTerm := models.TermAt(now)
+	if academicYear == 0 && term == "" {
+		return currentYear, currentTerm, nil
+	}
+	if academicYear == 0 || term == "" {
+		return 0, "", errors.New("academic_year and term must be given together")
+	}
+	if !isTerm(term) {
+		return 0, "", fmt.Errorf("Unknown term %q", term)
+	}
+	if academicYear < firstAcademicYear || academicYear > currentYear {
+		return 0, "", fmt.Errorf("academic_year must be between %d and %d", firstAcademicYear, currentYear)
+	}
+	return academicYear, term, nil
+}
+
 // GetReviews handles GET /api/v1/courses/:course_code/reviews
 func (h *ReviewHandler) GetReviews(c *gin.Context) {
 	courseCode := c.Param("course_code")
 
 	// Parse query parameters
-	sortBy := c.DefaultQuery("sort", "recent") // "recent" or "earliest"
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	sortBy := c.DefaultQuery("sort", models.ReviewSortRecent) // "recent" or "earliest"
+	if !slices.Contains(models.ReviewSortModes, sortBy) {
+		sortBy = models.ReviewSortRecent
+	}
+	limit, ok := pageLimit(c, config.PagesReviews, "limit")
+	if !ok {
+		return
+	}
+	offset, ok := pageOffset(c)
+	if !ok {
+		return
+	}
+	after, cursorMode, ok := pageCursor[models.ReviewCursor](c, "offset")
+	if !ok {
+		return
+	}
+	if after != nil && (after.Sort != sortBy || !id.Valid(after.ID)) {
+		badCursor(c)
+		return
+	}
+
+	deliveryMode := c.Query("delivery_mode")
+	if deliveryMode != "" && !models.IsReviewDeliveryMode(deliveryMode) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unknown delivery mode %q", deliveryMode)})
+		return
+	}
+
+	// Stats cover the recency window unless ?since=YYYY-MM-DD or ?since=all is given
+	since := time.Now().UTC().Add(-h.statsWindow)
+	switch s := c.Query("since"); s {
+	case "":
+	case "all":
+		since = time.Time{}
+	default:
+		parsed, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter 'since' must be YYYY-MM-DD or 'all'"})
+			return
+		}
+		since = parsed
+	}
+
+	// The page, stats and histogram are independent queries, so they run
+	// together; each is bounded by the repository's own deadline, and the
+	// first to fail cancels the rest.
+	var (
+		reviews   []models.Review
+		next      *string
+		stats     map[string]interface{}
+		histogram *models.RatingHistogram
+	)
+	g, ctx := errgroup.WithContext(c.Request.Context())
+	g.Go(func() error {
+		var page []models.Review
+		var err error
+		if cursorMode {
+			page, err = h.repo.GetByCourseCodeAfter(ctx, courseCode, sortBy, deliveryMode, after, limit+1)
+			page, next = trimPage(page, limit, func(review models.Review) any {
+				return models.ReviewCursorAfter(sortBy, review)
+			})
+		} else {
+			page, err = h.repo.GetByCourseCode(ctx, courseCode, sortBy, deliveryMode, limit, offset)
+		}
+		if err != nil {
+			return &fetchError{"Failed to fetch reviews", err}
+		}
+		for i := range page {
+			presentReview(&page[i])
+		}
+		h.attachBadges(ctx, page)
+		reviews = page
+		return nil
+	})
+	g.Go(func() error {
+		s, err := h.repo.GetCourseStats(ctx, courseCode, since)
+		if err != nil {
+			return &fetchError{"Failed to fetch course stats", err}
+		}
+		h.calibrate(ctx, courseCode, s)
+		stats = s
+		return nil
+	})
+	g.Go(func() error {
+		hist, err := h.repo.GetRatingHistogram(ctx, courseCode, since)
+		if err != nil {
+			return &fetchError{"Failed to fetch rating histogram", err}
+		}
+		histogram = hist
+		return nil
+	})
+	if err := g.Wait(); err != nil {
+		var fe *fetchError
+		if errors.As(err, &fe) {
+			serverError(c, fe.err, fe.message)
+			return
+		}
+		serverError(c, err, "Failed to fetch reviews")
+		return
+	}
 
-	// Validate limit
-	if limit < 1 || limit > 50 {
-		limit = 10
+	body := gin.H{
+		"data":      reviews,
+		"count":     len(reviews),
+		"stats":     stats,
+		"histogram": histogram,
+	}
+	if cursorMode {
+		body["next_cursor"] = next
 	}
+	respond(c, http.StatusOK, body)
+}
+
+// fetchError pairs a failed lookup with the message its response should carry.
+type fetchError struct {
+	message string
+	err     error
+}
+
+func (e *fetchError) Error() string { return e.message + ": " + e.err.Error() }
+
+func (e *fetchError) Unwrap() error { return e.err }
 
-	reviews, err := h.repo.GetByCourseCode(c.Request.Context(), courseCode, sortBy, limit, offset)
+// calibrate adds stats["calibrated_difficulty"] when the course has reviews and
+// its department has a baseline. Lookup failures only drop the field.
+func (h *ReviewHandler) calibrate(ctx context.Context, courseCode string, stats map[string]interface{}) {
+	if h.baselines == nil {
+		return
+	}
+	total, _ := stats["total_reviews"].(int)
+	avg, ok := stats["avg_difficulty"].(float64)
+	if total == 0 || !ok {
+		return
+	}
+	baseline, err := h.baselines.GetDepartment(ctx, models.DepartmentOf(courseCode))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reviews"})
+		log.Printf("difficulty calibration for %s: %v", courseCode, err)
 		return
 	}
+	if baseline != nil {
+		stats["calibrated_difficulty"] = calibration.Calibrate(avg, *baseline)
+	}
+}
+
+// GetAllReviews handles GET /api/v1/reviews
+func (h *ReviewHandler) GetAllReviews(c *gin.Context) {
+	ctx := c.Request.Context()
+	out := newJSONArrayWriter(c)
+
+	// Reviews are written a batch at a time so each batch shares one badge lookup
+	batch := make([]models.Review, 0, streamBatchSize)
+	writeBatch := func() error {
+		for i := range batch {
+			presentReview(&batch[i])
+		}
+		h.attachBadges(ctx, batch)
+		for _, review := range batch {
+			if err := out.Write(review); err != nil {
+				return err
+			}
+		}
+		batch = batch[:0]
+		return nil
+	}
 
-	// Get course stats
-	stats, err := h.repo.GetCourseStats(c.Request.Context(), courseCode)
+	err := h.repo.StreamAll(ctx, func(review models.Review) error {
+		batch = append(batch, review)
+		if len(batch) < streamBatchSize {
+			return nil
+		}
+		return writeBatch()
+	})
+	if err == nil {
+		err = writeBatch()
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch course stats"})
+		out.Fail(err, "Failed to fetch reviews")
 		return
 	}
+	out.Close()
+}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data":  reviews,
-		"count": len(reviews),
-		"stats": stats,
+// normalizeReviewTags drops duplicates and rejects tags outside the curated list.
+func normalizeReviewTags(tags []string) ([]string, error) {
+	seen := make(map[string]bool, len(tags))
+	var normalized []string
+	for _, tag := range tags {
+		if !models.IsReviewTag(tag) {
+			return nil, fmt.Errorf("Unknown review tag %q", tag)
+		}
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) > models.MaxReviewTags {
+		return nil, fmt.Errorf("A review can have at most %d tags", models.MaxReviewTags)
+	}
+	return normalized, nil
+}
+
+// presentReview fills the computed fields of a review before it is returned.
+func presentReview(review *models.Review) {
+	renderReviewText(review)
+	review.Status = review.StatusAt(time.Now())
+	review.Edited = review.EditedAt.Valid
+}
+
+// attachBadges fills AuthorBadges on named reviews. Anonymous reviews get none,
+// since shared badges would hint that two reviews have the same author. A failed
+// lookup only costs the badges, so it is logged rather than returned.
+func (h *ReviewHandler) attachBadges(ctx context.Context, reviews []models.Review) {
+	if h.badges == nil {
+		return
+	}
+	var emails []string
+	for _, r := range reviews {
+		if r.AuthorName.Valid {
+			emails = append(emails, r.Email)
+		}
+	}
+	if len(emails) == 0 {
+		return
+	}
+	byEmail, err := h.badges.BadgesByEmail(ctx, emails)
+	if err != nil {
+		log.Printf("review author badges: %v", err)
+		return
+	}
+	for i := range reviews {
+		if reviews[i].AuthorName.Valid {
+			reviews[i].AuthorBadges = byEmail[reviews[i].Email]
+		}
+	}
+}
+
+// renderReviewText fills RenderedHTML from the review's markdown text.
+func renderReviewText(review *models.Review) {
+	if !review.ReviewText.Valid {
+		review.RenderedHTML = dbtypes.NullString{}
+		return
+	}
+	review.RenderedHTML = dbtypes.NewNullString(markdown.Render(review.ReviewText.String))
+}
+
+// GetReviewEligibility handles GET /api/v1/courses/:course_code/reviews/eligibility?email=&academic_year=&term=
+// so clients can disable the review form before the user types anything. The
+// term defaults to the one in progress, as it does for new reviews.
+func (h *ReviewHandler) GetReviewEligibility(c *gin.Context) {
+	courseCode := c.Param("course_code")
+
+	var query struct {
+		Email        string `form:"email" binding:"required,email"`
+		AcademicYear int    `form:"academic_year"`
+		Term         string `form:"term"`
+	}
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter 'email' must be a valid email"})
+		return
+	}
+	academicYear, term, err := reviewTerm(query.AcademicYear, query.Term, time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	reasons := make([]string, 0)
+
+	reviewed, err := h.repo.HasReviewed(c.Request.Context(), courseCode, query.Email, academicYear, term)
+	if err != nil {
+		serverError(c, err, "Failed to check review eligibility")
+		return
+	}
+	if reviewed {
+		reasons = append(reasons, models.ErrCodeDuplicateReview)
+	}
+
+	if h.quota != nil && h.quota.Remaining(c.ClientIP()) <= 0 {
+		reasons = append(reasons, models.ErrCodeRateLimited)
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"data": gin.H{
+			"eligible":      len(reasons) == 0,
+			"reasons":       reasons,
+			"academic_year": academicYear,
+			"term":          term,
+		},
 	})
 }
 
-// GetAllReviews handles GET /api/v1/reviews
-func (h *ReviewHandler) GetAllReviews(c *gin.Context) {
-	reviews, err := h.repo.GetAll(c.Request.Context())
+// GetOwnReview handles GET /api/v1/courses/:course_code/reviews/mine?email=
+// so authors can see their latest review, including whether it is still embargoed,
+// and the badges they hold.
+func (h *ReviewHandler) GetOwnReview(c *gin.Context) {
+	var query struct {
+		Email string `form:"email" binding:"required,email"`
+	}
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter 'email' must be a valid email"})
+		return
+	}
+
+	review, err := h.repo.GetByAuthor(c.Request.Context(), c.Param("course_code"), query.Email)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reviews"})
+		serverError(c, err, "Failed to fetch review")
 		return
 	}
+	if review == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No review for this course from that email"})
+		return
+	}
+	presentReview(review)
 
-	c.JSON(http.StatusOK, gin.H{
+	// Authors always see their own badges, even on an anonymous review
+	if h.badges != nil {
+		byEmail, err := h.badges.BadgesByEmail(c.Request.Context(), []string{review.Email})
+		if err != nil {
+			log.Printf("review author badges: %v", err)
+		}
+		review.AuthorBadges = byEmail[review.Email]
+	}
+
+	respond(c, http.StatusOK, gin.H{"data": review})
+}
+
+// GetReviewHistory handles GET /api/v1/reviews/:review_id/history. Everyone
+// sees when a published review was edited and which fields changed; admins
+// also see what each earlier version said, and can see unpublished reviews.
+func (h *ReviewHandler) GetReviewHistory(c *gin.Context) {
+	ctx := c.Request.Context()
+	admin := redact.RoleFrom(ctx).Allows(redact.RoleAdmin)
+
+	review, err := h.repo.GetByID(ctx, c.Param("review_id"))
+	if err != nil {
+		serverError(c, err, "Failed to fetch review")
+		return
+	}
+	if review == nil || (!admin && review.StatusAt(time.Now()) != models.ReviewPublished) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Review not found"})
+		return
+	}
+	revisions, err := h.repo.ListRevisions(ctx, review.ID)
+	if err != nil {
+		serverError(c, err, "Failed to fetch review history")
+		return
+	}
+
+	// Each revision's changes are those made by the edit that replaced it
+	next := models.ContentOf(*review)
+	for i := len(revisions) - 1; i >= 0; i-- {
+		revisions[i].Changed = revisions[i].Content.ChangedFields(next)
+		next = *revisions[i].Content
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"data": models.ReviewHistory{
+			ReviewID:  review.ID,
+			Edited:    review.EditedAt.Valid,
+			EditedAt:  review.EditedAt,
+			Revisions: revisions,
+		},
+	})
+}
+
+// ListEmbargoedReviews handles GET /api/v1/admin/reviews/embargoed
+func (h *ReviewHandler) ListEmbargoedReviews(c *gin.Context) {
+	reviews, err := h.repo.ListEmbargoed(c.Request.Context())
+	if err != nil {
+		serverError(c, err, "Failed to fetch embargoed reviews")
+		return
+	}
+	for i := range reviews {
+		presentReview(&reviews[i])
+	}
+
+	respond(c, http.StatusOK, gin.H{
 		"data":  reviews,
 		"count": len(reviews),
 	})
 }
+
+// PublishReview handles POST /api/v1/admin/reviews/:id/publish, lifting an embargo early.
+func (h *ReviewHandler) PublishReview(c *gin.Context) {
+	h.setPublishAt(c, dbtypes.NullTime{}, "publish", "Review published")
+}
+
+// EmbargoReview handles POST /api/v1/admin/reviews/:id/embargo with {"until": "..."},
+// holding a review until the given time or moving an existing embargo.
+func (h *ReviewHandler) EmbargoReview(c *gin.Context) {
+	var req models.EmbargoReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !req.Until.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "'until' must be in the future"})
+		return
+	}
+	h.setPublishAt(c, dbtypes.NewNullTime(req.Until.UTC()), "embargo", "Review embargoed")
+}
+
+func (h *ReviewHandler) setPublishAt(c *gin.Context, publishAt dbtypes.NullTime, action, message string) {
+	review, err := h.repo.SetPublishAt(c.Request.Context(), c.Param("id"), publishAt)
+	if err != nil {
+		serverError(c, err, "Failed to update review")
+		return
+	}
+	if review == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Review not found"})
+		return
+	}
+	presentReview(review)
+	h.recordEvent(c.Request.Context(), review.ID, models.ReviewEventModerated, map[string]any{
+		"action":     action,
+		"publish_at": review.PublishAt,
+	})
+
+	respond(c, http.StatusOK, gin.H{
+		"data":    review,
+		"message": message,
+	})
+}
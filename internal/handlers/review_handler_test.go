@@ -4,19 +4,40 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+	"yuplan/internal/config"
+	"yuplan/internal/contentfilter"
+	"yuplan/internal/dbtypes"
 	"yuplan/internal/models"
+	"yuplan/internal/redact"
+	"yuplan/internal/repository"
 
 	"github.com/gin-gonic/gin"
 )
 
 type mockReviewRepository struct {
 	createFunc          func(ctx context.Context, review *models.Review) error
-	getByCourseCodeFunc func(ctx context.Context, courseCode string, sortBy string, limit, offset int) ([]models.Review, error)
-	getCourseStatsFunc  func(ctx context.Context, courseCode string) (map[string]interface{}, error)
-	getAllFunc          func(ctx context.Context) ([]models.Review, error)
+	getByCourseCodeFunc func(ctx context.Context, courseCode string, sortBy, deliveryMode string, limit, offset int) ([]models.Review, error)
+	getAfterFunc        func(ctx context.Context, courseCode string, sortBy, deliveryMode string, after *models.ReviewCursor, limit int) ([]models.Review, error)
+	getCourseStatsFunc  func(ctx context.Context, courseCode string, since time.Time) (map[string]interface{}, error)
+	getHistogramFunc    func(ctx context.Context, courseCode string, since time.Time) (*models.RatingHistogram, error)
+	streamAllFunc       func(ctx context.Context, fn func(models.Review) error) error
+	hasReviewedFunc     func(ctx context.Context, courseCode, email string, academicYear int, term string) (bool, error)
+	getByAuthorFunc     func(ctx context.Context, courseCode, email string) (*models.Review, error)
+	listEmbargoedFunc   func(ctx context.Context) ([]models.Review, error)
+	setPublishAtFunc    func(ctx context.Context, id string, publishAt dbtypes.NullTime) (*models.Review, error)
+	updateFunc          func(ctx context.Context, review *models.Review) (*models.Review, error)
+	deleteFunc          func(ctx context.Context, id, courseCode, email string) (bool, error)
+	getByIDFunc         func(ctx context.Context, id string) (*models.Review, error)
+	listRevisionsFunc   func(ctx context.Context, reviewID string) ([]models.ReviewRevision, error)
 }
 
 func (m *mockReviewRepository) Create(ctx context.Context, review *models.Review) error {
@@ -26,16 +47,23 @@ func (m *mockReviewRepository) Create(ctx context.Context, review *models.Review
 	return nil
 }
 
-func (m *mockReviewRepository) GetByCourseCode(ctx context.Context, courseCode string, sortBy string, limit, offset int) ([]models.Review, error) {
+func (m *mockReviewRepository) GetByCourseCode(ctx context.Context, courseCode string, sortBy, deliveryMode string, limit, offset int) ([]models.Review, error) {
 	if m.getByCourseCodeFunc != nil {
-		return m.getByCourseCodeFunc(ctx, courseCode, sortBy, limit, offset)
+		return m.getByCourseCodeFunc(ctx, courseCode, sortBy, deliveryMode, limit, offset)
 	}
 	return []models.Review{}, nil
 }
 
-func (m *mockReviewRepository) GetCourseStats(ctx context.Context, courseCode string) (map[string]interface{}, error) {
+func (m *mockReviewRepository) GetByCourseCodeAfter(ctx context.Context, courseCode string, sortBy, deliveryMode string, after *models.ReviewCursor, limit int) ([]models.Review, error) {
+	if m.getAfterFunc != nil {
+		return m.getAfterFunc(ctx, courseCode, sortBy, deliveryMode, after, limit)
+	}
+	return []models.Review{}, nil
+}
+
+func (m *mockReviewRepository) GetCourseStats(ctx context.Context, courseCode string, since time.Time) (map[string]interface{}, error) {
 	if m.getCourseStatsFunc != nil {
-		return m.getCourseStatsFunc(ctx, courseCode)
+		return m.getCourseStatsFunc(ctx, courseCode, since)
 	}
 	return map[string]interface{}{
 		"total_reviews":            0,
@@ -47,13 +75,88 @@ func (m *mockReviewRepository) GetCourseStats(ctx context.Context, courseCode st
 	}, nil
 }
 
-func (m *mockReviewRepository) GetAll(ctx context.Context) ([]models.Review, error) {
-	if m.getAllFunc != nil {
-		return m.getAllFunc(ctx)
+func (m *mockReviewRepository) GetRatingHistogram(ctx context.Context, courseCode string, since time.Time) (*models.RatingHistogram, error) {
+	if m.getHistogramFunc != nil {
+		return m.getHistogramFunc(ctx, courseCode, since)
+	}
+	return &models.RatingHistogram{}, nil
+}
+
+func (m *mockReviewRepository) StreamAll(ctx context.Context, fn func(models.Review) error) error {
+	if m.streamAllFunc != nil {
+		return m.streamAllFunc(ctx, fn)
+	}
+	return nil
+}
+
+// streamReviews streams reviews, then fails with err if it is set.
+func streamReviews(reviews []models.Review, err error) func(context.Context, func(models.Review) error) error {
+	return func(ctx context.Context, fn func(models.Review) error) error {
+		for _, r := range reviews {
+			if ferr := fn(r); ferr != nil {
+				return ferr
+			}
+		}
+		return err
+	}
+}
+
+func (m *mockReviewRepository) HasReviewed(ctx context.Context, courseCode, email string, academicYear int, term string) (bool, error) {
+	if m.hasReviewedFunc != nil {
+		return m.hasReviewedFunc(ctx, courseCode, email, academicYear, term)
+	}
+	return false, nil
+}
+
+func (m *mockReviewRepository) GetByAuthor(ctx context.Context, courseCode, email string) (*models.Review, error) {
+	if m.getByAuthorFunc != nil {
+		return m.getByAuthorFunc(ctx, courseCode, email)
+	}
+	return nil, nil
+}
+
+func (m *mockReviewRepository) ListEmbargoed(ctx context.Context) ([]models.Review, error) {
+	if m.listEmbargoedFunc != nil {
+		return m.listEmbargoedFunc(ctx)
 	}
 	return []models.Review{}, nil
 }
 
+func (m *mockReviewRepository) SetPublishAt(ctx context.Context, id string, publishAt dbtypes.NullTime) (*models.Review, error) {
+	if m.setPublishAtFunc != nil {
+		return m.setPublishAtFunc(ctx, id, publishAt)
+	}
+	return nil, nil
+}
+
+func (m *mockReviewRepository) Update(ctx context.Context, review *models.Review) (*models.Review, error) {
+	if m.updateFunc != nil {
+		return m.updateFunc(ctx, review)
+	}
+	return nil, nil
+}
+
+func (m *mockReviewRepository) GetByID(ctx context.Context, id string) (*models.Review, error) {
+	if m.getByIDFunc != nil {
+		return m.getByIDFunc(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *mockReviewRepository) ListRevisions(ctx context.Context, reviewID string) ([]models.ReviewRevision, error) {
+	if m.listRevisionsFunc != nil {
+		return m.listRevisionsFunc(ctx, reviewID)
+	}
+	return []models.ReviewRevision{}, nil
+}
+
+func (m *mockReviewRepository) Delete(ctx context.Context, id, courseCode, email string) (bool, error) {
+	if m.deleteFunc != nil {
+		return m.deleteFunc(ctx, id, courseCode, email)
+	}
+	return false, nil
+}
+
 func TestCreateReview(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -70,7 +173,7 @@ func TestCreateReview(t *testing.T) {
 			courseCode: "EECS2030",
 			requestBody: models.CreateReviewRequest{
 				Email:              "student@yorku.ca",
-				AuthorName:         &authorName,
+				AuthorName:         dbtypes.NewNullString(authorName),
 				Liked:              true,
 				Difficulty:         3,
 				RealWorldRelevance: 5,
@@ -83,7 +186,7 @@ func TestCreateReview(t *testing.T) {
 			courseCode: "EECS2030",
 			requestBody: models.CreateReviewRequest{
 				Email:              "student@yorku.ca",
-				AuthorName:         nil,
+				AuthorName:         dbtypes.NullString{},
 				Liked:              true,
 				Difficulty:         3,
 				RealWorldRelevance: 5,
@@ -91,6 +194,127 @@ func TestCreateReview(t *testing.T) {
 			mockError:      nil,
 			expectedStatus: http.StatusCreated,
 		},
+		{
+			name:       "Valid review with tags",
+			courseCode: "EECS2030",
+			requestBody: models.CreateReviewRequest{
+				Email:              "student@yorku.ca",
+				Liked:              true,
+				Difficulty:         4,
+				RealWorldRelevance: 5,
+				Tags:               []string{models.ReviewTagMathHeavy, models.ReviewTagMathHeavy, models.ReviewTagExamHeavy},
+			},
+			mockError:      nil,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:       "Valid review with delivery mode",
+			courseCode: "EECS2030",
+			requestBody: models.CreateReviewRequest{
+				Email:              "student@yorku.ca",
+				Liked:              true,
+				Difficulty:         2,
+				RealWorldRelevance: 4,
+				DeliveryMode:       dbtypes.NewNullString(models.ReviewDeliveryOnline),
+			},
+			mockError:      nil,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:       "Unknown delivery mode",
+			courseCode: "EECS2030",
+			requestBody: map[string]interface{}{
+				"email":                "student@yorku.ca",
+				"liked":                true,
+				"difficulty":           3,
+				"real_world_relevance": 5,
+				"delivery_mode":        "correspondence",
+			},
+			mockError:      nil,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Unknown tag",
+			courseCode: "EECS2030",
+			requestBody: map[string]interface{}{
+				"email":                "student@yorku.ca",
+				"liked":                true,
+				"difficulty":           3,
+				"real_world_relevance": 5,
+				"tags":                 []string{"easy-a"},
+			},
+			mockError:      nil,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Too many tags",
+			courseCode: "EECS2030",
+			requestBody: map[string]interface{}{
+				"email":                "student@yorku.ca",
+				"liked":                true,
+				"difficulty":           3,
+				"real_world_relevance": 5,
+				"tags": []string{
+					models.ReviewTagMathHeavy, models.ReviewTagExamHeavy,
+					models.ReviewTagGroupProjects, models.ReviewTagGoodElective,
+				},
+			},
+			mockError:      nil,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Retake attributed to an earlier term",
+			courseCode: "EECS2030",
+			requestBody: models.CreateReviewRequest{
+				Email:              "student@yorku.ca",
+				Liked:              true,
+				Difficulty:         3,
+				RealWorldRelevance: 4,
+				AcademicYear:       2024,
+				Term:               models.TermWinter,
+			},
+			mockError:      nil,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:       "Already reviewed this term",
+			courseCode: "EECS2030",
+			requestBody: models.CreateReviewRequest{
+				Email:              "student@yorku.ca",
+				Liked:              true,
+				Difficulty:         3,
+				RealWorldRelevance: 4,
+			},
+			mockError:      repository.ErrDuplicateReview,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:       "Term without academic year",
+			courseCode: "EECS2030",
+			requestBody: map[string]interface{}{
+				"email":                "student@yorku.ca",
+				"liked":                true,
+				"difficulty":           3,
+				"real_world_relevance": 5,
+				"term":                 models.TermFall,
+			},
+			mockError:      nil,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Unknown term",
+			courseCode: "EECS2030",
+			requestBody: map[string]interface{}{
+				"email":                "student@yorku.ca",
+				"liked":                true,
+				"difficulty":           3,
+				"real_world_relevance": 5,
+				"academic_year":        2024,
+				"term":                 "Q",
+			},
+			mockError:      nil,
+			expectedStatus: http.StatusBadRequest,
+		},
 		{
 			name:       "Invalid email",
 			courseCode: "EECS2030",
@@ -182,11 +406,11 @@ func TestGetReviews(t *testing.T) {
 			ID:                 "review-1",
 			CourseCode:         "EECS2030",
 			Email:              "student@yorku.ca",
-			AuthorName:         &authorName,
+			AuthorName:         dbtypes.NewNullString(authorName),
 			Liked:              true,
 			Difficulty:         3,
 			RealWorldRelevance: 5,
-			ReviewText:         &reviewText,
+			ReviewText:         dbtypes.NewNullString(reviewText),
 		},
 	}
 
@@ -228,10 +452,10 @@ func TestGetReviews(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockReviewRepo := &mockReviewRepository{
-				getByCourseCodeFunc: func(ctx context.Context, courseCode string, sortBy string, limit, offset int) ([]models.Review, error) {
+				getByCourseCodeFunc: func(ctx context.Context, courseCode string, sortBy, deliveryMode string, limit, offset int) ([]models.Review, error) {
 					return mockReviews, nil
 				},
-				getCourseStatsFunc: func(ctx context.Context, courseCode string) (map[string]interface{}, error) {
+				getCourseStatsFunc: func(ctx context.Context, courseCode string, since time.Time) (map[string]interface{}, error) {
 					return mockStats, nil
 				},
 			}
@@ -276,6 +500,47 @@ func TestGetReviews(t *testing.T) {
 	}
 }
 
+func TestGetReviews_DeliveryModeFilter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedMode   string
+	}{
+		{"no filter", "", http.StatusOK, ""},
+		{"online only", "?delivery_mode=online", http.StatusOK, models.ReviewDeliveryOnline},
+		{"unknown mode", "?delivery_mode=correspondence", http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotMode string
+			handler := NewReviewHandler(&mockReviewRepository{
+				getByCourseCodeFunc: func(ctx context.Context, courseCode string, sortBy, deliveryMode string, limit, offset int) ([]models.Review, error) {
+					gotMode = deliveryMode
+					return []models.Review{}, nil
+				},
+			})
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("GET", "/api/v1/courses/EECS2030/reviews"+tt.query, nil)
+			c.Params = gin.Params{{Key: "course_code", Value: "EECS2030"}}
+
+			handler.GetReviews(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d. Body: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if gotMode != tt.expectedMode {
+				t.Errorf("Expected delivery mode %q, got %q", tt.expectedMode, gotMode)
+			}
+		})
+	}
+}
+
 func TestGetAllReviews(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -287,38 +552,36 @@ func TestGetAllReviews(t *testing.T) {
 			ID:                 "review-1",
 			CourseCode:         "EECS2030",
 			Email:              "student1@yorku.ca",
-			AuthorName:         &authorName1,
+			AuthorName:         dbtypes.NewNullString(authorName1),
 			Liked:              true,
 			Difficulty:         3,
 			RealWorldRelevance: 5,
-			ReviewText:         &reviewText,
+			ReviewText:         dbtypes.NewNullString(reviewText),
 		},
 		{
 			ID:                 "review-2",
 			CourseCode:         "EECS3101",
 			Email:              "student2@yorku.ca",
-			AuthorName:         nil, // Anonymous
+			AuthorName:         dbtypes.NullString{}, // Anonymous
 			Liked:              false,
 			Difficulty:         4,
 			RealWorldRelevance: 3,
-			ReviewText:         &reviewText,
+			ReviewText:         dbtypes.NewNullString(reviewText),
 		},
 		{
 			ID:                 "review-3",
 			CourseCode:         "EECS2030",
 			Email:              "student3@yorku.ca",
-			AuthorName:         &authorName2,
+			AuthorName:         dbtypes.NewNullString(authorName2),
 			Liked:              true,
 			Difficulty:         2,
 			RealWorldRelevance: 4,
-			ReviewText:         &reviewText,
+			ReviewText:         dbtypes.NewNullString(reviewText),
 		},
 	}
 
 	mockReviewRepo := &mockReviewRepository{
-		getAllFunc: func(ctx context.Context) ([]models.Review, error) {
-			return mockReviews, nil
-		},
+		streamAllFunc: streamReviews(mockReviews, nil),
 	}
 
 	handler := NewReviewHandler(mockReviewRepo)
@@ -357,3 +620,1475 @@ func TestGetAllReviews(t *testing.T) {
 		t.Errorf("Expected count 3, got %d", int(count))
 	}
 }
+
+func TestGetAllReviews_Streaming(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var many []models.Review
+	for i := 0; i < streamBatchSize+1; i++ {
+		many = append(many, models.Review{ID: fmt.Sprintf("review-%d", i), CourseCode: "EECS2030", Difficulty: 3, RealWorldRelevance: 3})
+	}
+
+	tests := []struct {
+		name           string
+		reviews        []models.Review
+		err            error
+		expectedStatus int
+		expectedBody   string
+		validJSON      bool
+	}{
+		{"empty", nil, nil, http.StatusOK, `"count":0`, true},
+		{"more than one batch", many, nil, http.StatusOK, fmt.Sprintf(`"count":%d`, len(many)), true},
+		{"error before first review", nil, errors.New("db down"), http.StatusInternalServerError, "Failed to fetch reviews", true},
+		{"error mid-stream", many, errors.New("db down"), http.StatusOK, `"id":"review-0"`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewReviewHandler(&mockReviewRepository{streamAllFunc: streamReviews(tt.reviews, tt.err)})
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("GET", "/api/v1/reviews", nil)
+			handler.GetAllReviews(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if !strings.Contains(w.Body.String(), tt.expectedBody) {
+				t.Errorf("Expected body to contain %s", tt.expectedBody)
+			}
+			if json.Valid(w.Body.Bytes()) != tt.validJSON {
+				t.Errorf("Expected valid JSON to be %v", tt.validJSON)
+			}
+		})
+	}
+}
+
+type fixedQuota int
+
+func (q fixedQuota) Remaining(key string) int {
+	return int(q)
+}
+
+func TestGetReviewEligibility(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name             string
+		query            string
+		hasReviewed      bool
+		repoErr          error
+		quota            rateQuota
+		expectedStatus   int
+		expectedEligible bool
+		expectedReasons  []interface{}
+	}{
+		{
+			name:             "eligible",
+			query:            "?email=student@yorku.ca",
+			expectedStatus:   http.StatusOK,
+			expectedEligible: true,
+			expectedReasons:  []interface{}{},
+		},
+		{
+			name:             "already reviewed",
+			query:            "?email=student@yorku.ca",
+			hasReviewed:      true,
+			expectedStatus:   http.StatusOK,
+			expectedEligible: false,
+			expectedReasons:  []interface{}{models.ErrCodeDuplicateReview},
+		},
+		{
+			name:             "rate limited",
+			query:            "?email=student@yorku.ca",
+			quota:            fixedQuota(0),
+			expectedStatus:   http.StatusOK,
+			expectedEligible: false,
+			expectedReasons:  []interface{}{models.ErrCodeRateLimited},
+		},
+		{
+			name:           "missing email",
+			query:          "",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid email",
+			query:          "?email=nope",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "repository error",
+			query:          "?email=student@yorku.ca",
+			repoErr:        errors.New("db down"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockReviewRepo := &mockReviewRepository{
+				hasReviewedFunc: func(ctx context.Context, courseCode, email string, academicYear int, term string) (bool, error) {
+					return tt.hasReviewed, tt.repoErr
+				},
+			}
+
+			handler := NewReviewHandler(mockReviewRepo)
+			if tt.quota != nil {
+				handler = handler.WithRateQuota(tt.quota)
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("GET", "/api/v1/courses/EECS2030/reviews/eligibility"+tt.query, nil)
+			c.Params = gin.Params{{Key: "course_code", Value: "EECS2030"}}
+
+			handler.GetReviewEligibility(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d. Body: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if w.Code != http.StatusOK {
+				return
+			}
+
+			var response struct {
+				Data map[string]interface{} `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+			if response.Data["eligible"] != tt.expectedEligible {
+				t.Errorf("Expected eligible %v, got %v", tt.expectedEligible, response.Data["eligible"])
+			}
+			if !reflect.DeepEqual(response.Data["reasons"], tt.expectedReasons) {
+				t.Errorf("Expected reasons %v, got %v", tt.expectedReasons, response.Data["reasons"])
+			}
+		})
+	}
+}
+
+func TestGetReviewEligibility_Term(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var gotYear int
+	var gotTerm string
+	handler := NewReviewHandler(&mockReviewRepository{
+		hasReviewedFunc: func(ctx context.Context, courseCode, email string, academicYear int, term string) (bool, error) {
+			gotYear, gotTerm = academicYear, term
+			return false, nil
+		},
+	})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/api/v1/courses/EECS2030/reviews/eligibility?email=student@yorku.ca&academic_year=2023&term=F", nil)
+	c.Params = gin.Params{{Key: "course_code", Value: "EECS2030"}}
+
+	handler.GetReviewEligibility(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	if gotYear != 2023 || gotTerm != models.TermFall {
+		t.Errorf("Expected eligibility checked for 2023 F, got %d %s", gotYear, gotTerm)
+	}
+	if !strings.Contains(w.Body.String(), `"academic_year":2023`) {
+		t.Errorf("Expected the checked term in the response, got %s", w.Body.String())
+	}
+}
+
+func TestReviewTerm(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		academicYear int
+		term         string
+		wantYear     int
+		wantTerm     string
+		wantErr      bool
+	}{
+		{name: "defaults to current term", wantYear: 2026, wantTerm: models.TermFall},
+		{name: "earlier term", academicYear: 2025, term: models.TermSummer, wantYear: 2025, wantTerm: models.TermSummer},
+		{name: "year only", academicYear: 2025, wantErr: true},
+		{name: "term only", term: models.TermWinter, wantErr: true},
+		{name: "unknown term", academicYear: 2025, term: "Q", wantErr: true},
+		{name: "future session", academicYear: 2027, term: models.TermFall, wantErr: true},
+		{name: "before York existed", academicYear: 1950, term: models.TermFall, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			year, term, err := reviewTerm(tt.academicYear, tt.term, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if year != tt.wantYear || term != tt.wantTerm {
+				t.Errorf("Expected %d %s, got %d %s", tt.wantYear, tt.wantTerm, year, term)
+			}
+		})
+	}
+}
+
+func TestGetReviews_FetchesConcurrently(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// Each lookup waits until all three have started, so run one after
+	// another they would time out
+	var started sync.WaitGroup
+	started.Add(3)
+	waitForOthers := func(ctx context.Context) error {
+		started.Done()
+		done := make(chan struct{})
+		go func() { started.Wait(); close(done) }()
+		select {
+		case <-done:
+			return nil
+		case <-time.After(2 * time.Second):
+			return errors.New("lookups ran one at a time")
+		}
+	}
+	repo := &mockReviewRepository{
+		getByCourseCodeFunc: func(ctx context.Context, courseCode string, sortBy, deliveryMode string, limit, offset int) ([]models.Review, error) {
+			return []models.Review{{ID: "review-1", CourseCode: courseCode}}, waitForOthers(ctx)
+		},
+		getCourseStatsFunc: func(ctx context.Context, courseCode string, since time.Time) (map[string]interface{}, error) {
+			return map[string]interface{}{"total_reviews": 1}, waitForOthers(ctx)
+		},
+		getHistogramFunc: func(ctx context.Context, courseCode string, since time.Time) (*models.RatingHistogram, error) {
+			return &models.RatingHistogram{Difficulty: [5]int{0, 0, 1, 0, 0}, RealWorldRelevance: [5]int{0, 0, 0, 0, 1}}, waitForOthers(ctx)
+		},
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/api/v1/courses/EECS2030/reviews", nil)
+	c.Params = gin.Params{{Key: "course_code", Value: "EECS2030"}}
+
+	NewReviewHandler(repo).GetReviews(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `"histogram":{"difficulty":[0,0,1,0,0],"real_world_relevance":[0,0,0,0,1]}`) {
+		t.Errorf("Expected the histogram in the response, got %s", w.Body.String())
+	}
+}
+
+func TestGetReviews_Cursor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	first := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	page := []models.Review{
+		{ID: "0192f3a1-5b2c-7d4e-8f60-000000000003", CreatedAt: first, HelpfulCount: 4},
+		{ID: "0192f3a1-5b2c-7d4e-8f60-000000000002", CreatedAt: first.Add(-time.Hour), HelpfulCount: 2, NotHelpfulCount: 1},
+		{ID: "0192f3a1-5b2c-7d4e-8f60-000000000001", CreatedAt: first.Add(-2 * time.Hour)},
+	}
+	var gotAfter *models.ReviewCursor
+	var gotLimit int
+	repo := &mockReviewRepository{
+		getByCourseCodeFunc: func(ctx context.Context, courseCode string, sortBy, deliveryMode string, limit, offset int) ([]models.Review, error) {
+			t.Error("cursor request fell back to offset pagination")
+			return nil, nil
+		},
+		getAfterFunc: func(ctx context.Context, courseCode string, sortBy, deliveryMode string, after *models.ReviewCursor, limit int) ([]models.Review, error) {
+			gotAfter, gotLimit = after, limit
+			if after == nil {
+				return page, nil
+			}
+			return page[2:], nil
+		},
+	}
+	get := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/api/v1/courses/EECS2030/reviews"+query, nil)
+		c.Params = gin.Params{{Key: "course_code", Value: "EECS2030"}}
+		NewReviewHandler(repo).GetReviews(c)
+		return w
+	}
+
+	w := get("?sort=most_helpful&limit=2&cursor=")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	var body struct {
+		Count      int     `json:"count"`
+		NextCursor *string `json:"next_cursor"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if gotAfter != nil || gotLimit != 3 || body.Count != 2 || body.NextCursor == nil {
+		t.Fatalf("first page: after %v, limit %d, count %d, next %v", gotAfter, gotLimit, body.Count, body.NextCursor)
+	}
+
+	w = get("?sort=most_helpful&limit=2&cursor=" + *body.NextCursor)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	want := models.ReviewCursor{Sort: models.ReviewSortMostHelpful, Score: 1, CreatedAt: page[1].CreatedAt, ID: page[1].ID}
+	if gotAfter == nil || *gotAfter != want {
+		t.Errorf("second page after = %+v, want %+v", gotAfter, want)
+	}
+	if !strings.Contains(w.Body.String(), `"next_cursor":null`) {
+		t.Errorf("Expected no next cursor on the last page, got %s", w.Body.String())
+	}
+
+	for _, query := range []string{
+		"?sort=recent&cursor=" + *body.NextCursor, // issued for most_helpful
+		"?cursor=not-a-cursor",
+		"?cursor=&offset=10",
+	} {
+		if w := get(query); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", query, w.Code)
+		}
+	}
+}
+
+func TestGetReviews_AggregateError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name        string
+		repo        *mockReviewRepository
+		wantStatus  int
+		wantMessage string
+	}{
+		{
+			name: "stats",
+			repo: &mockReviewRepository{
+				getCourseStatsFunc: func(ctx context.Context, courseCode string, since time.Time) (map[string]interface{}, error) {
+					return nil, errors.New("db down")
+				},
+			},
+			wantStatus:  http.StatusInternalServerError,
+			wantMessage: "Failed to fetch course stats",
+		},
+		{
+			name: "histogram timeout",
+			repo: &mockReviewRepository{
+				getHistogramFunc: func(ctx context.Context, courseCode string, since time.Time) (*models.RatingHistogram, error) {
+					return nil, context.DeadlineExceeded
+				},
+			},
+			wantStatus:  http.StatusGatewayTimeout,
+			wantMessage: "Timed out waiting for the database",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("GET", "/api/v1/courses/EECS2030/reviews", nil)
+			c.Params = gin.Params{{Key: "course_code", Value: "EECS2030"}}
+
+			NewReviewHandler(tt.repo).GetReviews(c)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			if !strings.Contains(w.Body.String(), tt.wantMessage) {
+				t.Errorf("Expected %q in body, got %s", tt.wantMessage, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetReviews_StatsWindow(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		query          string
+		window         time.Duration
+		expectedSince  time.Time
+		expectedStatus int
+	}{
+		{
+			name:           "default window",
+			query:          "",
+			window:         0,
+			expectedSince:  time.Now().UTC().Add(-defaultStatsWindow),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "configured window",
+			query:          "",
+			window:         365 * 24 * time.Hour,
+			expectedSince:  time.Now().UTC().Add(-365 * 24 * time.Hour),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "explicit since",
+			query:          "?since=2022-09-01",
+			expectedSince:  time.Date(2022, 9, 1, 0, 0, 0, 0, time.UTC),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "all reviews",
+			query:          "?since=all",
+			expectedSince:  time.Time{},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid since",
+			query:          "?since=last-year",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotSince time.Time
+			mockReviewRepo := &mockReviewRepository{
+				getCourseStatsFunc: func(ctx context.Context, courseCode string, since time.Time) (map[string]interface{}, error) {
+					gotSince = since
+					return map[string]interface{}{"total_reviews": 0}, nil
+				},
+			}
+
+			handler := NewReviewHandler(mockReviewRepo)
+			if tt.window > 0 {
+				handler = handler.WithStatsWindow(tt.window)
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("GET", "/api/v1/courses/EECS2030/reviews"+tt.query, nil)
+			c.Params = gin.Params{{Key: "course_code", Value: "EECS2030"}}
+
+			handler.GetReviews(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d. Body: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if w.Code != http.StatusOK {
+				return
+			}
+			if tt.expectedSince.IsZero() {
+				if !gotSince.IsZero() {
+					t.Errorf("Expected zero since, got %v", gotSince)
+				}
+				return
+			}
+			if d := gotSince.Sub(tt.expectedSince); d > time.Minute || d < -time.Minute {
+				t.Errorf("Expected since %v, got %v", tt.expectedSince, gotSince)
+			}
+		})
+	}
+}
+
+func TestGetReviews_RendersReviewText(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockReviewRepo := &mockReviewRepository{
+		getByCourseCodeFunc: func(ctx context.Context, courseCode string, sortBy, deliveryMode string, limit, offset int) ([]models.Review, error) {
+			return []models.Review{
+				{ID: "review-1", ReviewText: dbtypes.NewNullString("**Tough** <script>alert(1)</script>")},
+				{ID: "review-2"},
+			}, nil
+		},
+	}
+
+	handler := NewReviewHandler(mockReviewRepo)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/api/v1/courses/EECS2030/reviews", nil)
+	c.Params = gin.Params{{Key: "course_code", Value: "EECS2030"}}
+
+	handler.GetReviews(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response struct {
+		Data []map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if len(response.Data) != 2 {
+		t.Fatalf("Expected 2 reviews, got %d", len(response.Data))
+	}
+
+	if raw := response.Data[0]["review_text"]; raw != "**Tough** <script>alert(1)</script>" {
+		t.Errorf("Expected raw review_text to be unchanged, got %v", raw)
+	}
+	expected := "<p><strong>Tough</strong> &lt;script&gt;alert(1)&lt;/script&gt;</p>"
+	if got := response.Data[0]["rendered_html"]; got != expected {
+		t.Errorf("Expected rendered_html %q, got %v", expected, got)
+	}
+	if got, ok := response.Data[1]["rendered_html"]; !ok || got != nil {
+		t.Errorf("Expected null rendered_html for review without text, got %v", got)
+	}
+}
+
+func TestNormalizeReviewTags(t *testing.T) {
+	tags, err := normalizeReviewTags([]string{models.ReviewTagLightWorkload, models.ReviewTagLightWorkload, models.ReviewTagGoodElective})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []string{models.ReviewTagLightWorkload, models.ReviewTagGoodElective}
+	if !reflect.DeepEqual(tags, expected) {
+		t.Errorf("Expected %v, got %v", expected, tags)
+	}
+
+	tags, err = normalizeReviewTags(nil)
+	if err != nil || tags != nil {
+		t.Errorf("Expected no tags and no error, got %v, %v", tags, err)
+	}
+}
+
+type fakeExamCalendar struct {
+	term *models.AcademicTerm
+	err  error
+}
+
+func (f fakeExamCalendar) CurrentExamPeriod(ctx context.Context) (*models.AcademicTerm, error) {
+	return f.term, f.err
+}
+
+func TestCreateReview_Embargo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	gradesReleased := time.Now().Add(14 * 24 * time.Hour).UTC().Truncate(time.Second)
+	examTerm := &models.AcademicTerm{AcademicYear: 2026, Term: models.TermFall, GradesReleased: gradesReleased}
+	flagOn := config.DefaultTunables()
+	flagOn.FeatureFlags = map[string]bool{config.FlagReviewEmbargo: true}
+
+	tests := []struct {
+		name           string
+		tunables       config.Tunables
+		calendar       fakeExamCalendar
+		expectedStatus int
+		expectedState  string
+	}{
+		{"flag off", config.DefaultTunables(), fakeExamCalendar{term: examTerm}, http.StatusCreated, models.ReviewPublished},
+		{"outside exam period", flagOn, fakeExamCalendar{}, http.StatusCreated, models.ReviewPublished},
+		{"during exam period", flagOn, fakeExamCalendar{term: examTerm}, http.StatusCreated, models.ReviewEmbargoed},
+		{"calendar error", flagOn, fakeExamCalendar{err: errors.New("db down")}, http.StatusInternalServerError, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved *models.Review
+			handler := NewReviewHandler(&mockReviewRepository{
+				createFunc: func(ctx context.Context, review *models.Review) error {
+					saved = review
+					return nil
+				},
+			}).WithEmbargo(tt.calendar, &mockReloader{current: tt.tunables})
+
+			body, _ := json.Marshal(map[string]interface{}{
+				"email":                "student@yorku.ca",
+				"liked":                true,
+				"difficulty":           3,
+				"real_world_relevance": 4,
+			})
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("POST", "/api/v1/courses/EECS2030/reviews", bytes.NewBuffer(body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "course_code", Value: "EECS2030"}}
+
+			handler.CreateReview(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d. Body: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if w.Code != http.StatusCreated {
+				if saved != nil {
+					t.Error("Review should not be saved when the exam period can't be checked")
+				}
+				return
+			}
+
+			var response struct {
+				Data models.Review `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			if response.Data.Status != tt.expectedState {
+				t.Errorf("Expected status %q, got %q", tt.expectedState, response.Data.Status)
+			}
+			if tt.expectedState == models.ReviewEmbargoed && !saved.PublishAt.Time.Equal(gradesReleased) {
+				t.Errorf("Expected publish_at %v, got %v", gradesReleased, saved.PublishAt)
+			}
+			if tt.expectedState == models.ReviewPublished && saved.PublishAt.Valid {
+				t.Errorf("Expected no publish_at, got %v", saved.PublishAt.Time)
+			}
+		})
+	}
+}
+
+type fakeModerator struct {
+	decision models.ModerationDecision
+	err      error
+}
+
+func (f fakeModerator) Decide(ctx context.Context, review *models.Review) (models.ModerationDecision, error) {
+	return f.decision, f.err
+}
+
+func TestCreateReview_Moderation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		moderator      fakeModerator
+		expectedState  string
+		expectedStored string
+	}{
+		{"approved", fakeModerator{decision: models.ModerationDecision{Moderation: models.ModerationApproved, Rule: "trusted"}}, models.ReviewPublished, models.ModerationApproved},
+		{"queued", fakeModerator{decision: models.ModerationDecision{Moderation: models.ModerationPending}}, models.ReviewPending, models.ModerationPending},
+		{"moderator error holds the review", fakeModerator{err: errors.New("db down")}, models.ReviewPending, models.ModerationPending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved *models.Review
+			handler := NewReviewHandler(&mockReviewRepository{
+				createFunc: func(ctx context.Context, review *models.Review) error {
+					saved = review
+					return nil
+				},
+			}).WithModeration(tt.moderator)
+
+			body, _ := json.Marshal(map[string]interface{}{
+				"email":                "student@yorku.ca",
+				"liked":                true,
+				"difficulty":           3,
+				"real_world_relevance": 4,
+			})
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("POST", "/api/v1/courses/EECS2030/reviews", bytes.NewBuffer(body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "course_code", Value: "EECS2030"}}
+
+			handler.CreateReview(c)
+
+			if w.Code != http.StatusCreated {
+				t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusCreated, w.Code, w.Body.String())
+			}
+			if saved.Moderation != tt.expectedStored {
+				t.Errorf("Expected moderation %q, got %q", tt.expectedStored, saved.Moderation)
+			}
+
+			var response struct {
+				Data    models.Review `json:"data"`
+				Message string        `json:"message"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			if response.Data.Status != tt.expectedState {
+				t.Errorf("Expected status %q, got %q", tt.expectedState, response.Data.Status)
+			}
+			if tt.expectedState == models.ReviewPending && !strings.Contains(response.Message, "moderator") {
+				t.Errorf("Expected a pending-moderation message, got %q", response.Message)
+			}
+		})
+	}
+}
+
+type fakeContentFilter struct {
+	verdict contentfilter.Verdict
+	err     error
+}
+
+func (f fakeContentFilter) Check(ctx context.Context, text string) (contentfilter.Verdict, error) {
+	return f.verdict, f.err
+}
+
+func TestCreateReview_ContentFilter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		filter         fakeContentFilter
+		expectedStatus int
+		expectedStored string // moderation of the saved review; empty when nothing is saved
+	}{
+		{"allowed goes to the moderator", fakeContentFilter{verdict: contentfilter.Verdict{Action: contentfilter.ActionAllow}}, http.StatusCreated, models.ModerationApproved},
+		{"flagged is held", fakeContentFilter{verdict: contentfilter.Verdict{Action: contentfilter.ActionFlag, Filter: "links"}}, http.StatusCreated, models.ModerationPending},
+		{"rejected", fakeContentFilter{verdict: contentfilter.Verdict{Action: contentfilter.ActionReject, Filter: "profanity", Reason: "Reviews can't contain profanity"}}, http.StatusUnprocessableEntity, ""},
+		{"filter error holds the review", fakeContentFilter{err: errors.New("model down")}, http.StatusCreated, models.ModerationPending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved *models.Review
+			handler := NewReviewHandler(&mockReviewRepository{
+				createFunc: func(ctx context.Context, review *models.Review) error {
+					saved = review
+					return nil
+				},
+			}).WithModeration(fakeModerator{decision: models.ModerationDecision{Moderation: models.ModerationApproved}}).
+				WithContentFilter(tt.filter)
+
+			body, _ := json.Marshal(map[string]interface{}{
+				"email":                "student@yorku.ca",
+				"liked":                true,
+				"difficulty":           3,
+				"real_world_relevance": 4,
+				"review_text":          "Tough but fair",
+			})
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("POST", "/api/v1/courses/EECS2030/reviews", bytes.NewBuffer(body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "course_code", Value: "EECS2030"}}
+
+			handler.CreateReview(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d. Body: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStored == "" {
+				if saved != nil {
+					t.Error("Expected the review not to be saved")
+				}
+				if !strings.Contains(w.Body.String(), models.ErrCodeContentRejected) {
+					t.Errorf("Expected a content_rejected code, got %s", w.Body.String())
+				}
+				return
+			}
+			if saved.Moderation != tt.expectedStored {
+				t.Errorf("Expected moderation %q, got %q", tt.expectedStored, saved.Moderation)
+			}
+		})
+	}
+}
+
+func TestUpdateReview_ContentFilter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	updated := false
+	handler := NewReviewHandler(&mockReviewRepository{
+		updateFunc: func(ctx context.Context, review *models.Review) (*models.Review, error) {
+			updated = true
+			return review, nil
+		},
+	}).WithContentFilter(fakeContentFilter{verdict: contentfilter.Verdict{Action: contentfilter.ActionReject, Reason: "Reviews can't contain links"}})
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"email":                "student@yorku.ca",
+		"liked":                true,
+		"difficulty":           3,
+		"real_world_relevance": 4,
+		"review_text":          "notes at example.com",
+	})
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("PUT", "/api/v1/courses/EECS2030/reviews/r1", bytes.NewBuffer(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "course_code", Value: "EECS2030"}, {Key: "review_id", Value: "r1"}}
+
+	handler.UpdateReview(c)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	}
+	if updated {
+		t.Error("Expected the edit not to be saved")
+	}
+}
+
+func TestUpdateReview_Flagged(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var saved *models.Review
+	events := &fakeReviewEvents{}
+	handler := NewReviewHandler(&mockReviewRepository{
+		updateFunc: func(ctx context.Context, review *models.Review) (*models.Review, error) {
+			saved = review
+			updated := *review
+			return &updated, nil
+		},
+	}).WithEvents(events).
+		WithContentFilter(fakeContentFilter{verdict: contentfilter.Verdict{Action: contentfilter.ActionFlag, Filter: "links"}})
+
+	router := gin.New()
+	router.PUT("/courses/:course_code/reviews/:review_id", handler.UpdateReview)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/courses/EECS2030/reviews/review-1",
+		bytes.NewBufferString(`{"email": "student@yorku.ca", "liked": true, "difficulty": 3, "real_world_relevance": 4, "review_text": "notes at example.com"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if saved == nil || saved.Moderation != models.ModerationPending {
+		t.Fatalf("Expected the edit to be held for moderation, got %+v", saved)
+	}
+	if !bytes.Contains(w.Body.Bytes(), []byte(`"status":"pending"`)) {
+		t.Errorf("Expected the review to be pending in %s", w.Body.String())
+	}
+	expected := []recordedEvent{{"review-1", models.ReviewEventEdited, map[string]any{
+		"course_code": "EECS2030",
+		"status":      models.ReviewPending,
+		"flagged_by":  "links",
+	}}}
+	if !reflect.DeepEqual(events.recorded, expected) {
+		t.Errorf("Expected events %+v, got %+v", expected, events.recorded)
+	}
+}
+
+func TestGetOwnReview(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	publishAt := time.Now().Add(24 * time.Hour)
+	tests := []struct {
+		name           string
+		query          string
+		review         *models.Review
+		expectedStatus int
+	}{
+		{"embargoed review", "?email=student@yorku.ca", &models.Review{ID: "review-1", PublishAt: dbtypes.NewNullTime(publishAt)}, http.StatusOK},
+		{"no review", "?email=student@yorku.ca", nil, http.StatusNotFound},
+		{"invalid email", "?email=nope", nil, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewReviewHandler(&mockReviewRepository{
+				getByAuthorFunc: func(ctx context.Context, courseCode, email string) (*models.Review, error) {
+					if courseCode != "EECS2030" || email != "student@yorku.ca" {
+						t.Errorf("Unexpected lookup %s %s", courseCode, email)
+					}
+					return tt.review, nil
+				},
+			})
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("GET", "/api/v1/courses/EECS2030/reviews/mine"+tt.query, nil)
+			c.Params = gin.Params{{Key: "course_code", Value: "EECS2030"}}
+
+			handler.GetOwnReview(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d. Body: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if w.Code == http.StatusOK && !bytes.Contains(w.Body.Bytes(), []byte(`"status":"embargoed"`)) {
+				t.Errorf("Expected embargoed status in %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestReviewEmbargoOverrides(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	future := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Second)
+	tests := []struct {
+		name            string
+		action          string
+		body            string
+		found           bool
+		expectedStatus  int
+		expectedPublish dbtypes.NullTime
+	}{
+		{"publish now", "publish", "", true, http.StatusOK, dbtypes.NullTime{}},
+		{"publish missing review", "publish", "", false, http.StatusNotFound, dbtypes.NullTime{}},
+		{"embargo", "embargo", `{"until": "` + future.Format(time.RFC3339) + `"}`, true, http.StatusOK, dbtypes.NewNullTime(future)},
+		{"embargo into the past", "embargo", `{"until": "2020-01-01T00:00:00Z"}`, true, http.StatusBadRequest, dbtypes.NullTime{}},
+		{"embargo without until", "embargo", `{}`, true, http.StatusBadRequest, dbtypes.NullTime{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			handler := NewReviewHandler(&mockReviewRepository{
+				setPublishAtFunc: func(ctx context.Context, id string, publishAt dbtypes.NullTime) (*models.Review, error) {
+					called = true
+					if publishAt.Valid != tt.expectedPublish.Valid || !publishAt.Time.Equal(tt.expectedPublish.Time) {
+						t.Errorf("Expected publish_at %v, got %v", tt.expectedPublish, publishAt)
+					}
+					if !tt.found {
+						return nil, nil
+					}
+					return &models.Review{ID: id, PublishAt: publishAt}, nil
+				},
+			})
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("POST", "/api/v1/admin/reviews/review-1/"+tt.action, bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "id", Value: "review-1"}}
+
+			if tt.action == "publish" {
+				handler.PublishReview(c)
+			} else {
+				handler.EmbargoReview(c)
+			}
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d. Body: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if called != (tt.expectedStatus != http.StatusBadRequest) {
+				t.Errorf("Repository called = %v for status %d", called, w.Code)
+			}
+		})
+	}
+}
+
+func TestGetReviews_AuthorBadgesOnlyOnNamedReviews(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewReviewHandler(&mockReviewRepository{
+		getByCourseCodeFunc: func(ctx context.Context, courseCode string, sortBy, deliveryMode string, limit, offset int) ([]models.Review, error) {
+			return []models.Review{
+				{ID: "named", Email: "a@yorku.ca", AuthorName: dbtypes.NewNullString("Ada")},
+				{ID: "anonymous", Email: "a@yorku.ca"},
+			}, nil
+		},
+	}).WithBadges(&mockBadgeRepository{byEmail: map[string][]string{"a@yorku.ca": {"first-review"}}})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/api/v1/courses/EECS2030/reviews", nil)
+	c.Params = gin.Params{{Key: "course_code", Value: "EECS2030"}}
+
+	handler.GetReviews(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var response struct {
+		Data []models.Review `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if !reflect.DeepEqual(response.Data[0].AuthorBadges, []string{"first-review"}) {
+		t.Errorf("Expected badges on the named review, got %v", response.Data[0].AuthorBadges)
+	}
+	if response.Data[1].AuthorBadges != nil {
+		t.Errorf("Expected no badges on the anonymous review, got %v", response.Data[1].AuthorBadges)
+	}
+}
+
+func TestGetReviewHistory(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	edited := time.Now().Add(-time.Hour)
+	current := &models.Review{
+		ID: "review-1", Moderation: models.ModerationApproved, Liked: true, Difficulty: 4, RealWorldRelevance: 2,
+		ReviewText: dbtypes.NewNullString("Brutal but fair"), Tags: []string{"heavy_workload"}, EditedAt: dbtypes.NewNullTime(edited),
+	}
+	held := &models.Review{ID: "review-2", Moderation: models.ModerationPending}
+	handler := NewReviewHandler(&mockReviewRepository{
+		getByIDFunc: func(ctx context.Context, id string) (*models.Review, error) {
+			switch id {
+			case "review-1":
+				return current, nil
+			case "review-2":
+				return held, nil
+			}
+			return nil, nil
+		},
+		listRevisionsFunc: func(ctx context.Context, reviewID string) ([]models.ReviewRevision, error) {
+			if reviewID != "review-1" {
+				return []models.ReviewRevision{}, nil
+			}
+			return []models.ReviewRevision{
+				{Revision: 1, Content: &models.ReviewContent{Liked: false, Difficulty: 5, RealWorldRelevance: 2, ReviewText: dbtypes.NewNullString("Brutal")}},
+				{Revision: 2, Content: &models.ReviewContent{Liked: true, Difficulty: 4, RealWorldRelevance: 2, ReviewText: dbtypes.NewNullString("Brutal")}},
+			}, nil
+		},
+	})
+
+	tests := []struct {
+		name           string
+		reviewID       string
+		role           redact.Role
+		expectedStatus int
+	}{
+		{"public sees metadata", "review-1", redact.RolePublic, http.StatusOK},
+		{"admin sees content", "review-1", redact.RoleAdmin, http.StatusOK},
+		{"unpublished hidden from public", "review-2", redact.RolePublic, http.StatusNotFound},
+		{"unpublished shown to admin", "review-2", redact.RoleAdmin, http.StatusOK},
+		{"missing review", "review-3", redact.RoleAdmin, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("GET", "/api/v1/reviews/"+tt.reviewID+"/history", nil)
+			c.Request = c.Request.WithContext(redact.WithRole(c.Request.Context(), tt.role))
+			c.Params = gin.Params{{Key: "review_id", Value: tt.reviewID}}
+
+			handler.GetReviewHistory(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d. Body: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.reviewID != "review-1" || w.Code != http.StatusOK {
+				return
+			}
+			var body struct {
+				Data struct {
+					Edited    bool `json:"edited"`
+					Revisions []struct {
+						Changed []string        `json:"changed"`
+						Content json.RawMessage `json:"content"`
+					} `json:"revisions"`
+				} `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			if !body.Data.Edited || len(body.Data.Revisions) != 2 {
+				t.Fatalf("Expected an edited review with 2 revisions, got %s", w.Body.String())
+			}
+			if got := body.Data.Revisions[0].Changed; !reflect.DeepEqual(got, []string{"liked", "difficulty"}) {
+				t.Errorf("Expected first edit to change liked and difficulty, got %v", got)
+			}
+			if got := body.Data.Revisions[1].Changed; !reflect.DeepEqual(got, []string{"review_text", "tags"}) {
+				t.Errorf("Expected second edit to change review_text and tags, got %v", got)
+			}
+			if shown := body.Data.Revisions[0].Content != nil; shown != (tt.role == redact.RoleAdmin) {
+				t.Errorf("Content shown = %v for %s. Body: %s", shown, tt.role, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestReviewResponses_EmailOnlyForAdmins(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	reviews := []models.Review{{ID: "review-1", CourseCode: "EECS2030", Email: "student@yorku.ca"}}
+	handler := NewReviewHandler(&mockReviewRepository{
+		getByCourseCodeFunc: func(ctx context.Context, courseCode string, sortBy, deliveryMode string, limit, offset int) ([]models.Review, error) {
+			return append([]models.Review(nil), reviews...), nil
+		},
+		listEmbargoedFunc: func(ctx context.Context) ([]models.Review, error) {
+			return append([]models.Review(nil), reviews...), nil
+		},
+		streamAllFunc: streamReviews(reviews, nil),
+	})
+
+	endpoints := map[string]gin.HandlerFunc{
+		"course reviews": handler.GetReviews,
+		"all reviews":    handler.GetAllReviews,
+		"embargoed":      handler.ListEmbargoedReviews,
+	}
+	for name, serve := range endpoints {
+		for _, role := range []redact.Role{redact.RolePublic, redact.RoleAdmin} {
+			t.Run(name+" as "+string(role), func(t *testing.T) {
+				w := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(w)
+				c.Request = httptest.NewRequest("GET", "/", nil)
+				c.Request = c.Request.WithContext(redact.WithRole(c.Request.Context(), role))
+				c.Params = gin.Params{{Key: "course_code", Value: "EECS2030"}}
+
+				serve(c)
+
+				if w.Code != http.StatusOK {
+					t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+				}
+				if shown := strings.Contains(w.Body.String(), "student@yorku.ca"); shown != (role == redact.RoleAdmin) {
+					t.Errorf("Email shown = %v for %s. Body: %s", shown, role, w.Body.String())
+				}
+			})
+		}
+	}
+}
+
+func TestUpdateReview(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{"author updates", `{"email": "student@yorku.ca", "liked": false, "difficulty": 4, "real_world_relevance": 2, "review_text": "Harder than I said", "tags": ["great-for-beginners", "great-for-beginners"]}`, http.StatusOK},
+		{"someone else's review", `{"email": "other@yorku.ca", "liked": false, "difficulty": 4, "real_world_relevance": 2}`, http.StatusNotFound},
+		{"missing email", `{"liked": false, "difficulty": 4, "real_world_relevance": 2}`, http.StatusBadRequest},
+		{"unknown tag", `{"email": "student@yorku.ca", "difficulty": 4, "real_world_relevance": 2, "tags": ["nope"]}`, http.StatusBadRequest},
+		{"unknown delivery mode", `{"email": "student@yorku.ca", "difficulty": 4, "real_world_relevance": 2, "delivery_mode": "carrier_pigeon"}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := &fakeReviewEvents{}
+			handler := NewReviewHandler(&mockReviewRepository{
+				updateFunc: func(ctx context.Context, review *models.Review) (*models.Review, error) {
+					if review.ID != "review-1" || review.CourseCode != "EECS2030" {
+						t.Errorf("Unexpected update of %s on %s", review.ID, review.CourseCode)
+					}
+					if review.Email != "student@yorku.ca" {
+						return nil, nil
+					}
+					updated := *review
+					return &updated, nil
+				},
+			}).WithEvents(events)
+
+			router := gin.New()
+			router.PUT("/courses/:course_code/reviews/:review_id", handler.UpdateReview)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPut, "/courses/EECS2030/reviews/review-1", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d. Body: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if w.Code != http.StatusOK {
+				if len(events.recorded) != 0 {
+					t.Errorf("Expected no events, got %+v", events.recorded)
+				}
+				return
+			}
+			if bytes.Contains(w.Body.Bytes(), []byte("student@yorku.ca")) {
+				t.Errorf("Expected the email to be redacted in %s", w.Body.String())
+			}
+			if !bytes.Contains(w.Body.Bytes(), []byte(`"tags":["great-for-beginners"]`)) {
+				t.Errorf("Expected deduplicated tags in %s", w.Body.String())
+			}
+			expected := []recordedEvent{{"review-1", models.ReviewEventEdited, map[string]any{"course_code": "EECS2030", "status": models.ReviewPublished}}}
+			if !reflect.DeepEqual(events.recorded, expected) {
+				t.Errorf("Expected events %+v, got %+v", expected, events.recorded)
+			}
+		})
+	}
+}
+
+func TestDeleteReview(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+	}{
+		{"author deletes", "?email=student@yorku.ca", http.StatusOK},
+		{"someone else's review", "?email=other@yorku.ca", http.StatusNotFound},
+		{"invalid email", "?email=nope", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := &fakeReviewEvents{}
+			handler := NewReviewHandler(&mockReviewRepository{
+				deleteFunc: func(ctx context.Context, id, courseCode, email string) (bool, error) {
+					return id == "review-1" && courseCode == "EECS2030" && email == "student@yorku.ca", nil
+				},
+			}).WithEvents(events)
+
+			router := gin.New()
+			router.DELETE("/courses/:course_code/reviews/:review_id", handler.DeleteReview)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/courses/EECS2030/reviews/review-1"+tt.query, nil))
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d. Body: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			wantEvents := 0
+			if w.Code == http.StatusOK {
+				wantEvents = 1
+			}
+			if len(events.recorded) != wantEvents {
+				t.Errorf("Expected %d events, got %+v", wantEvents, events.recorded)
+			}
+		})
+	}
+}
+
+type recordedEvent struct {
+	reviewID string
+	event    string
+	details  map[string]any
+}
+
+type fakeReviewEvents struct {
+	recorded []recordedEvent
+	err      error
+}
+
+func (f *fakeReviewEvents) Record(ctx context.Context, reviewID, event string, details map[string]any) error {
+	f.recorded = append(f.recorded, recordedEvent{reviewID, event, details})
+	return f.err
+}
+
+func TestReviewLifecycleEvents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	events := &fakeReviewEvents{}
+	handler := NewReviewHandler(&mockReviewRepository{
+		createFunc: func(ctx context.Context, review *models.Review) error {
+			review.ID = "review-1"
+			return nil
+		},
+		setPublishAtFunc: func(ctx context.Context, id string, publishAt dbtypes.NullTime) (*models.Review, error) {
+			if id == "missing" {
+				return nil, nil
+			}
+			return &models.Review{ID: id, PublishAt: publishAt}, nil
+		},
+	}).WithEvents(events)
+
+	router := gin.New()
+	router.POST("/courses/:course_code/reviews", handler.CreateReview)
+	router.POST("/admin/reviews/:id/publish", handler.PublishReview)
+
+	body := `{"email": "a@my.yorku.ca", "liked": true, "difficulty": 3, "real_world_relevance": 4, "review_text": "Solid course"}`
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodPost, "/courses/EECS2030/reviews", bytes.NewBufferString(body)),
+		httptest.NewRequest(http.MethodPost, "/admin/reviews/review-1/publish", nil),
+		httptest.NewRequest(http.MethodPost, "/admin/reviews/missing/publish", nil),
+	} {
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	expected := []recordedEvent{
+		{"review-1", models.ReviewEventCreated, map[string]any{"course_code": "EECS2030", "status": models.ReviewPublished}},
+		{"review-1", models.ReviewEventModerated, map[string]any{"action": "publish", "publish_at": dbtypes.NullTime{}}},
+	}
+	if !reflect.DeepEqual(events.recorded, expected) {
+		t.Errorf("Expected events %+v, got %+v", expected, events.recorded)
+	}
+}
+
+func TestReviewLifecycleEvents_FailureDoesNotFailRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewReviewHandler(&mockReviewRepository{}).WithEvents(&fakeReviewEvents{err: errors.New("db down")})
+	router := gin.New()
+	router.POST("/courses/:course_code/reviews", handler.CreateReview)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/courses/EECS2030/reviews",
+		bytes.NewBufferString(`{"email": "a@my.yorku.ca", "liked": true, "difficulty": 3, "real_world_relevance": 4, "review_text": "Solid course"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Errorf("Expected status %d, got %d. Body: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+}
+
+type fakeBaselines map[string]*models.DepartmentDifficulty
+
+func (f fakeBaselines) GetDepartment(ctx context.Context, department string) (*models.DepartmentDifficulty, error) {
+	if department == "FAIL" {
+		return nil, errors.New("db down")
+	}
+	return f[department], nil
+}
+
+func TestGetReviews_CalibratedDifficulty(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	baselines := fakeBaselines{"EECS": {Department: "EECS", Mean: 3, StdDev: 0.5, Courses: 12}}
+	tests := []struct {
+		name       string
+		courseCode string
+		total      int
+		expectZ    bool
+	}{
+		{"department with baseline", "EECS2030", 4, true},
+		{"no reviews", "EECS2030", 0, false},
+		{"department without baseline", "NURS1000", 4, false},
+		{"lookup fails", "FAIL1000", 4, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewReviewHandler(&mockReviewRepository{
+				getCourseStatsFunc: func(ctx context.Context, courseCode string, since time.Time) (map[string]interface{}, error) {
+					return map[string]interface{}{"total_reviews": tt.total, "avg_difficulty": 3.75}, nil
+				},
+			}).WithCalibration(baselines)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("GET", "/api/v1/courses/"+tt.courseCode+"/reviews", nil)
+			c.Params = gin.Params{{Key: "course_code", Value: tt.courseCode}}
+
+			handler.GetReviews(c)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+			}
+			var response struct {
+				Stats struct {
+					Calibrated *models.CalibratedDifficulty `json:"calibrated_difficulty"`
+				} `json:"stats"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			if !tt.expectZ {
+				if response.Stats.Calibrated != nil {
+					t.Errorf("Expected no calibrated difficulty, got %+v", response.Stats.Calibrated)
+				}
+				return
+			}
+			if response.Stats.Calibrated == nil || response.Stats.Calibrated.ZScore != 1.5 || response.Stats.Calibrated.Baseline.Department != "EECS" {
+				t.Errorf("Expected z-score 1.5 against EECS, got %+v", response.Stats.Calibrated)
+			}
+		})
+	}
+}
+
+type fakeVerifier struct {
+	requested  *models.Review
+	moderation string // as passed to Request
+	requestErr error
+	resent     bool
+	confirmed  *models.Review
+}
+
+func (f *fakeVerifier) Request(ctx context.Context, review *models.Review, moderation string) error {
+	f.requested, f.moderation = review, moderation
+	return f.requestErr
+}
+
+func (f *fakeVerifier) Resend(ctx context.Context, courseCode, reviewID, email string) (bool, error) {
+	return f.resent, nil
+}
+
+func (f *fakeVerifier) Confirm(ctx context.Context, token string) (*models.Review, error) {
+	if token != "good-token" {
+		return nil, nil
+	}
+	return f.confirmed, nil
+}
+
+func TestCreateReview_Verification(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name            string
+		decision        string
+		requestErr      error
+		expectedMessage string
+	}{
+		{"approved waits for the author", models.ModerationApproved, nil, "check your email"},
+		{"held waits for the author too", models.ModerationPending, nil, "check your email"},
+		{"mail failure still keeps the review", models.ModerationApproved, errors.New("smtp down"), "request another one"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved *models.Review
+			verifier := &fakeVerifier{requestErr: tt.requestErr}
+			handler := NewReviewHandler(&mockReviewRepository{
+				createFunc: func(ctx context.Context, review *models.Review) error {
+					review.ID = "review-1"
+					saved = review
+					return nil
+				},
+			}).WithModeration(fakeModerator{decision: models.ModerationDecision{Moderation: tt.decision}}).
+				WithVerification(verifier)
+
+			body, _ := json.Marshal(map[string]interface{}{
+				"email":                "student@yorku.ca",
+				"liked":                true,
+				"difficulty":           3,
+				"real_world_relevance": 4,
+			})
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("POST", "/api/v1/courses/EECS2030/reviews", bytes.NewBuffer(body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "course_code", Value: "EECS2030"}}
+
+			handler.CreateReview(c)
+
+			if w.Code != http.StatusCreated {
+				t.Fatalf("Expected status %d, got %d. Body: %s", http.StatusCreated, w.Code, w.Body.String())
+			}
+			if saved.Moderation != models.ModerationUnverified {
+				t.Errorf("Expected the review to be stored unverified, got %q", saved.Moderation)
+			}
+			if verifier.requested != saved || verifier.moderation != tt.decision {
+				t.Errorf("Expected verification requested with %q, got %q", tt.decision, verifier.moderation)
+			}
+			if !strings.Contains(w.Body.String(), `"status":"unverified"`) || !strings.Contains(w.Body.String(), tt.expectedMessage) {
+				t.Errorf("Unexpected response %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestVerifyReview(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	events := &fakeReviewEvents{}
+	verifier := &fakeVerifier{confirmed: &models.Review{ID: "review-1", CourseCode: "EECS2030", Moderation: models.ModerationApproved}}
+	handler := NewReviewHandler(&mockReviewRepository{}).WithVerification(verifier).WithEvents(events)
+
+	tests := []struct {
+		query          string
+		expectedStatus int
+	}{
+		{"?token=good-token", http.StatusOK},
+		{"?token=stale-token", http.StatusNotFound},
+		{"", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/api/v1/reviews/verify"+tt.query, nil)
+
+		handler.VerifyReview(c)
+
+		if w.Code != tt.expectedStatus {
+			t.Errorf("%q: expected status %d, got %d. Body: %s", tt.query, tt.expectedStatus, w.Code, w.Body.String())
+		}
+	}
+	if len(events.recorded) != 1 || events.recorded[0].event != models.ReviewEventVerified || events.recorded[0].details["status"] != models.ReviewPublished {
+		t.Errorf("Expected one verified event for a published review, got %+v", events.recorded)
+	}
+}
+
+func TestResendVerification(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		body           string
+		resent         bool
+		expectedStatus int
+	}{
+		{"sent", `{"email": "student@yorku.ca"}`, true, http.StatusOK},
+		{"no unverified review", `{"email": "student@yorku.ca"}`, false, http.StatusNotFound},
+		{"invalid email", `{"email": "nope"}`, true, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewReviewHandler(&mockReviewRepository{}).WithVerification(&fakeVerifier{resent: tt.resent})
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("POST", "/api/v1/courses/EECS2030/reviews/review-1/verification", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "course_code", Value: "EECS2030"}, {Key: "review_id", Value: "review-1"}}
+
+			handler.ResendVerification(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d. Body: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
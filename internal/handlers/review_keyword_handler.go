@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"yuplan/internal/config"
+	"yuplan/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// keywordAggregator rebuilds review keywords on demand. Implemented by keywords.Aggregator.
+type keywordAggregator interface {
+	Run(ctx context.Context) (int, error)
+}
+
+type ReviewKeywordHandler struct {
+	repo       repository.ReviewKeywordRepositoryInterface
+	aggregator keywordAggregator
+}
+
+func NewReviewKeywordHandler(repo repository.ReviewKeywordRepositoryInterface, aggregator keywordAggregator) *ReviewKeywordHandler {
+	return &ReviewKeywordHandler{repo: repo, aggregator: aggregator}
+}
+
+// GetKeywords handles GET /api/v1/courses/:course_code/reviews/keywords?limit=30
+func (h *ReviewKeywordHandler) GetKeywords(c *gin.Context) {
+	limit, ok := pageLimit(c, config.PagesKeywords, "limit")
+	if !ok {
+		return
+	}
+
+	result, err := h.repo.GetKeywords(c.Request.Context(), c.Param("course_code"), limit)
+	if err != nil {
+		serverError(c, err, "Failed to fetch review keywords")
+		return
+	}
+
+	resp := gin.H{
+		"data":  result.Keywords,
+		"count": len(result.Keywords),
+	}
+	if !result.UpdatedAt.IsZero() {
+		resp["updated_at"] = result.UpdatedAt
+	}
+
+	// Keywords only change when the aggregation job runs
+	c.Header("Cache-Control", "public, max-age=600")
+	c.JSON(http.StatusOK, resp)
+}
+
+// RefreshKeywords handles POST /api/v1/admin/reviews/keywords/refresh
+func (h *ReviewKeywordHandler) RefreshKeywords(c *gin.Context) {
+	n, err := h.aggregator.Run(c.Request.Context())
+	if err != nil {
+		serverError(c, err, "Failed to aggregate review keywords")
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"count":   n,
+		"message": "Review keywords refreshed",
+	})
+}
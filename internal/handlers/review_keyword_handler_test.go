@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockReviewKeywordRepository struct {
+	result    *models.CourseKeywords
+	err       error
+	lastLimit int
+}
+
+func (m *mockReviewKeywordRepository) ListReviewTexts(ctx context.Context) ([]models.ReviewText, error) {
+	return nil, nil
+}
+
+func (m *mockReviewKeywordRepository) ReplaceKeywords(ctx context.Context, keywords []models.CourseKeywords) error {
+	return nil
+}
+
+func (m *mockReviewKeywordRepository) GetKeywords(ctx context.Context, courseCode string, limit int) (*models.CourseKeywords, error) {
+	m.lastLimit = limit
+	return m.result, m.err
+}
+
+type mockKeywordAggregator struct {
+	count int
+	err   error
+}
+
+func (m *mockKeywordAggregator) Run(ctx context.Context) (int, error) {
+	return m.count, m.err
+}
+
+func TestGetReviewKeywords(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := &mockReviewKeywordRepository{result: &models.CourseKeywords{
+		CourseCode: "EECS2030",
+		Keywords:   []models.Keyword{{Term: "labs", Count: 4}, {Term: "office hours", Count: 2}},
+		UpdatedAt:  time.Date(2026, 10, 1, 3, 0, 0, 0, time.UTC),
+	}}
+	handler := NewReviewKeywordHandler(repo, &mockKeywordAggregator{})
+	router := gin.New()
+	router.GET("/courses/:course_code/reviews/keywords", handler.GetKeywords)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/courses/EECS2030/reviews/keywords?limit=10", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 10, repo.lastLimit)
+	assert.Contains(t, w.Body.String(), `{"term":"office hours","count":2}`)
+	assert.Contains(t, w.Body.String(), `"count":2`)
+	assert.Contains(t, w.Body.String(), `"updated_at":"2026-10-01T03:00:00Z"`)
+	assert.NotEmpty(t, w.Header().Get("Cache-Control"))
+
+	// Without a limit the default applies; past the maximum is refused
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/courses/EECS2030/reviews/keywords", nil))
+	assert.Equal(t, 30, repo.lastLimit)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/courses/EECS2030/reviews/keywords?limit=1000", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"bad_request"`)
+}
+
+func TestGetReviewKeywords_NoKeywords(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := &mockReviewKeywordRepository{result: &models.CourseKeywords{CourseCode: "NEW1000", Keywords: []models.Keyword{}}}
+	handler := NewReviewKeywordHandler(repo, &mockKeywordAggregator{})
+	router := gin.New()
+	router.GET("/courses/:course_code/reviews/keywords", handler.GetKeywords)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/courses/NEW1000/reviews/keywords", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data":[],"count":0}`, w.Body.String())
+}
+
+func TestGetReviewKeywords_RepoError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewReviewKeywordHandler(&mockReviewKeywordRepository{err: errors.New("db down")}, &mockKeywordAggregator{})
+	router := gin.New()
+	router.GET("/courses/:course_code/reviews/keywords", handler.GetKeywords)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/courses/EECS2030/reviews/keywords", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestRefreshReviewKeywords(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		aggregator     *mockKeywordAggregator
+		expectedStatus int
+	}{
+		{"success", &mockKeywordAggregator{count: 12}, http.StatusOK},
+		{"error", &mockKeywordAggregator{err: errors.New("db down")}, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewReviewKeywordHandler(&mockReviewKeywordRepository{}, tt.aggregator)
+			router := gin.New()
+			router.POST("/admin/reviews/keywords/refresh", handler.RefreshKeywords)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/reviews/keywords/refresh", nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Contains(t, w.Body.String(), `"count":12`)
+			}
+		})
+	}
+}
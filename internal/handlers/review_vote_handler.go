@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"yuplan/internal/models"
+	"yuplan/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReviewVoteHandler struct {
+	repo repository.ReviewVoteRepositoryInterface
+}
+
+func NewReviewVoteHandler(repo repository.ReviewVoteRepositoryInterface) *ReviewVoteHandler {
+	return &ReviewVoteHandler{repo: repo}
+}
+
+// Vote handles POST /api/v1/reviews/:review_id/vote
+// Body: {"email": "...", "helpful": true}. Voting again replaces the earlier vote.
+func (h *ReviewVoteHandler) Vote(c *gin.Context) {
+	var req models.ReviewVoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	votes, err := h.repo.Vote(c.Request.Context(), c.Param("review_id"), req.Email, *req.Helpful)
+	if errors.Is(err, repository.ErrOwnReviewVote) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can't vote on your own review"})
+		return
+	}
+	if err != nil {
+		serverError(c, err, "Failed to record vote")
+		return
+	}
+	if votes == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No review with that id"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": votes})
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"yuplan/internal/models"
+	"yuplan/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockReviewVoteRepository struct {
+	votes   map[string]*models.ReviewVotes // by review id
+	author  string
+	helpful *bool
+	err     error
+}
+
+func (m *mockReviewVoteRepository) Vote(ctx context.Context, reviewID, email string, helpful bool) (*models.ReviewVotes, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if email == m.author {
+		return nil, repository.ErrOwnReviewVote
+	}
+	m.helpful = &helpful
+	return m.votes[reviewID], nil
+}
+
+func serveReviewVote(repo *mockReviewVoteRepository, reviewID, body string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/reviews/:review_id/vote", NewReviewVoteHandler(repo).Vote)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/reviews/"+reviewID+"/vote", strings.NewReader(body)))
+	return w
+}
+
+func TestReviewVote(t *testing.T) {
+	repo := &mockReviewVoteRepository{
+		votes:  map[string]*models.ReviewVotes{"review-1": {ReviewID: "review-1", HelpfulCount: 2, NotHelpfulCount: 1}},
+		author: "author@yorku.ca",
+	}
+
+	w := serveReviewVote(repo, "review-1", `{"email": "reader@yorku.ca", "helpful": false}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	if assert.NotNil(t, repo.helpful) {
+		assert.False(t, *repo.helpful)
+	}
+	var body struct {
+		Data models.ReviewVotes `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, 2, body.Data.HelpfulCount)
+	assert.Equal(t, 1, body.Data.NotHelpfulCount)
+
+	// A verdict is required; false alone doesn't count as missing
+	w = serveReviewVote(repo, "review-1", `{"email": "reader@yorku.ca"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = serveReviewVote(repo, "review-1", `{"email": "author@yorku.ca", "helpful": true}`)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = serveReviewVote(repo, "review-2", `{"email": "reader@yorku.ca", "helpful": true}`)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	repo.err = errors.New("db error")
+	w = serveReviewVote(repo, "review-1", `{"email": "reader@yorku.ca", "helpful": true}`)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
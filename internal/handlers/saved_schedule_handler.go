@@ -0,0 +1,387 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+	"yuplan/internal/ical"
+	"yuplan/internal/id"
+	"yuplan/internal/mailer"
+	"yuplan/internal/models"
+	"yuplan/internal/planner"
+	"yuplan/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// scheduleSwaps loads the sections a schedule's section could be swapped for. Implemented by repository.SectionRepository.
+type scheduleSwaps interface {
+	GetSiblings(ctx context.Context, sectionID string) (string, []models.Section, error)
+}
+
+type SavedScheduleHandler struct {
+	repo     repository.SavedScheduleRepositoryInterface
+	calendar scheduleCalendar
+	swaps    scheduleSwaps
+	mailer   mailer.Mailer
+	feedURL  string // feed links are <feedURL>/<id>/calendar.ics?token=
+}
+
+func NewSavedScheduleHandler(repo repository.SavedScheduleRepositoryInterface, calendar scheduleCalendar, m mailer.Mailer, feedURL string) *SavedScheduleHandler {
+	return &SavedScheduleHandler{repo: repo, calendar: calendar, mailer: m, feedURL: strings.TrimSuffix(feedURL, "/")}
+}
+
+// WithSwaps enables SuggestSwaps.
+func (h *SavedScheduleHandler) WithSwaps(swaps scheduleSwaps) *SavedScheduleHandler {
+	h.swaps = swaps
+	return h
+}
+
+// GetSchedules handles GET /api/v1/users/me/schedules?email=
+func (h *SavedScheduleHandler) GetSchedules(c *gin.Context) {
+	email, ok := emailQuery(c)
+	if !ok {
+		return
+	}
+
+	schedules, err := h.repo.List(c.Request.Context(), email)
+	if err != nil {
+		serverError(c, err, "Failed to fetch saved schedules")
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"data":  schedules,
+		"count": len(schedules),
+	})
+}
+
+// CreateSchedule handles POST /api/v1/users/me/schedules
+// Body: {"email": "student@my.yorku.ca", "name": "Fall plan", "section_ids": [...], "activity_ids": [...]}
+func (h *SavedScheduleHandler) CreateSchedule(c *gin.Context) {
+	schedule, ok := bindSavedSchedule(c)
+	if !ok {
+		return
+	}
+
+	err := h.repo.Create(c.Request.Context(), schedule)
+	if errors.Is(err, repository.ErrDuplicateScheduleName) {
+		c.JSON(http.StatusConflict, gin.H{"error": "You already have a saved schedule named " + schedule.Name})
+		return
+	}
+	if err != nil {
+		serverError(c, err, "Failed to save schedule")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": schedule})
+}
+
+// UpdateSchedule handles PUT /api/v1/users/me/schedules/:id with the same
+// body as CreateSchedule, e.g. after swapping sections. The feed token is
+// kept, so subscribed calendars pick the change up on their next refresh.
+func (h *SavedScheduleHandler) UpdateSchedule(c *gin.Context) {
+	scheduleID, ok := scheduleIDParam(c)
+	if !ok {
+		return
+	}
+	schedule, ok := bindSavedSchedule(c)
+	if !ok {
+		return
+	}
+	schedule.ID = scheduleID
+
+	updated, err := h.repo.Update(c.Request.Context(), schedule)
+	if errors.Is(err, repository.ErrDuplicateScheduleName) {
+		c.JSON(http.StatusConflict, gin.H{"error": "You already have a saved schedule named " + schedule.Name})
+		return
+	}
+	if err != nil {
+		serverError(c, err, "Failed to update saved schedule")
+		return
+	}
+	if !updated {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No such saved schedule for that email"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": schedule})
+}
+
+// DeleteSchedule handles DELETE /api/v1/users/me/schedules/:id?email=
+func (h *SavedScheduleHandler) DeleteSchedule(c *gin.Context) {
+	scheduleID, ok := scheduleIDParam(c)
+	if !ok {
+		return
+	}
+	email, ok := emailQuery(c)
+	if !ok {
+		return
+	}
+
+	removed, err := h.repo.Delete(c.Request.Context(), scheduleID, email)
+	if err != nil {
+		serverError(c, err, "Failed to delete saved schedule")
+		return
+	}
+	if !removed {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No such saved schedule for that email"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Saved schedule deleted"})
+}
+
+// IssueCalendarToken handles POST /api/v1/users/me/schedules/:id/calendar-token
+// Body: {"email": "..."}. Issues the token of the schedule's calendar feed,
+// revoking any earlier one, and mails the feed's url to the schedule's email.
+// It is never in the response, so knowing an email and a schedule id isn't
+// enough to subscribe to someone's calendar. Only the token's hash is stored.
+func (h *SavedScheduleHandler) IssueCalendarToken(c *gin.Context) {
+	scheduleID, ok := scheduleIDParam(c)
+	if !ok {
+		return
+	}
+	var req models.CalendarTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	token, hash, err := newFeedToken()
+	if err != nil {
+		serverError(c, err, "Failed to issue calendar token")
+		return
+	}
+	email := strings.TrimSpace(req.Email)
+	issued, err := h.repo.SetFeedToken(c.Request.Context(), scheduleID, email, hash)
+	if err != nil {
+		serverError(c, err, "Failed to issue calendar token")
+		return
+	}
+	if !issued {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No such saved schedule for that email"})
+		return
+	}
+
+	link := fmt.Sprintf("%s/%s/calendar.ics?token=%s", h.feedURL, scheduleID, token)
+	err = h.mailer.Send(c.Request.Context(), mailer.Message{
+		To:      email,
+		Subject: "Your YU Plan calendar link",
+		Body: "Subscribe to this link in Google Calendar, Apple Calendar or Outlook to keep your saved schedule in your calendar:\n\n" +
+			link + "\n\n" +
+			"Changes to the schedule show up on the calendar's next refresh. Any link sent for this schedule before no longer works. " +
+			"If you didn't ask for a calendar link, you can revoke it from YU Plan.\n",
+	})
+	if err != nil {
+		serverError(c, err, "Failed to send calendar link")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Calendar link sent to the schedule's email"})
+}
+
+// RevokeCalendarToken handles DELETE /api/v1/users/me/schedules/:id/calendar-token?email=
+// Calendars subscribed with the token stop updating.
+func (h *SavedScheduleHandler) RevokeCalendarToken(c *gin.Context) {
+	scheduleID, ok := scheduleIDParam(c)
+	if !ok {
+		return
+	}
+	email, ok := emailQuery(c)
+	if !ok {
+		return
+	}
+
+	revoked, err := h.repo.SetFeedToken(c.Request.Context(), scheduleID, email, "")
+	if err != nil {
+		serverError(c, err, "Failed to revoke calendar token")
+		return
+	}
+	if !revoked {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No such saved schedule for that email"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Calendar token revoked"})
+}
+
+// GetCalendarFeed handles GET /api/v1/users/me/schedules/:id/calendar.ics?token=
+// the subscription url calendar apps poll. The calendar is rebuilt from the
+// schedule's current sections on every fetch, as ExportICal builds it. A
+// wrong or revoked token is 404, like a schedule that doesn't exist.
+func (h *SavedScheduleHandler) GetCalendarFeed(c *gin.Context) {
+	scheduleID, ok := scheduleIDParam(c)
+	if !ok {
+		return
+	}
+	token := c.Query("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter 'token' is required"})
+		return
+	}
+
+	schedule, err := h.repo.GetByFeedToken(c.Request.Context(), scheduleID, hashFeedToken(token))
+	if err != nil {
+		serverError(c, err, "Failed to fetch saved schedule")
+		return
+	}
+	if schedule == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No such calendar"})
+		return
+	}
+
+	activities, err := h.calendar.ListForCalendar(c.Request.Context(), schedule.SectionIDs, schedule.ActivityIDs)
+	if err != nil {
+		serverError(c, err, "Failed to fetch activities")
+		return
+	}
+
+	// An empty calendar rather than 404, so subscribers drop the events of
+	// sections that are gone instead of keeping stale ones
+	var buf bytes.Buffer
+	if _, err := ical.Write(&buf, activities, time.Now()); err != nil {
+		serverError(c, err, "Failed to write calendar")
+		return
+	}
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Content-Disposition", `inline; filename="schedule.ics"`)
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", buf.Bytes())
+}
+
+// SuggestSwaps handles POST /api/v1/schedules/:id/swap-suggestions
+// Body: {"email": "student@my.yorku.ca", "section_id": "..."}
+// section_id is one of the schedule's sections the student wants to drop.
+// Returns the other sections of its course, each with a pick of its
+// activities that overlaps nothing else in the schedule, best first; see
+// planner.Swaps.
+func (h *SavedScheduleHandler) SuggestSwaps(c *gin.Context) {
+	if h.swaps == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Swap suggestions not configured"})
+		return
+	}
+	scheduleID, ok := scheduleIDParam(c)
+	if !ok {
+		return
+	}
+	var req models.SwapSuggestionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	sectionID := strings.ToLower(req.SectionID)
+
+	schedule, err := h.repo.Get(c.Request.Context(), scheduleID, strings.TrimSpace(req.Email))
+	if err != nil {
+		serverError(c, err, "Failed to fetch saved schedule")
+		return
+	}
+	if schedule == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No such saved schedule for that email"})
+		return
+	}
+	if !slices.Contains(schedule.SectionIDs, sectionID) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "section_id is not one of the schedule's sections"})
+		return
+	}
+
+	code, sections, err := h.swaps.GetSiblings(c.Request.Context(), sectionID)
+	if err != nil {
+		serverError(c, err, "Failed to fetch sections")
+		return
+	}
+	i := slices.IndexFunc(sections, func(s models.Section) bool { return s.ID == sectionID })
+	if i < 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Section not found"})
+		return
+	}
+
+	// The rest of the schedule leaves out the labs and tutorials chosen
+	// within the dropped section, as well as its other activities
+	dropped := map[string]bool{}
+	for _, a := range sections[i].Activities {
+		dropped[a.ID] = true
+	}
+	sectionIDs := slices.DeleteFunc(slices.Clone(schedule.SectionIDs), func(id string) bool { return id == sectionID })
+	activityIDs := slices.DeleteFunc(slices.Clone(schedule.ActivityIDs), func(id string) bool { return dropped[id] })
+	activities, err := h.calendar.ListForCalendar(c.Request.Context(), sectionIDs, activityIDs)
+	if err != nil {
+		serverError(c, err, "Failed to fetch activities")
+		return
+	}
+	rest := make([]planner.Activity, 0, len(activities))
+	for _, a := range activities {
+		meetings, err := models.ScheduledMeetings(a.Times)
+		if err != nil {
+			continue // can't be checked for conflicts, as in planner.Generate
+		}
+		rest = append(rest, planner.Activity{CourseCode: a.CourseCode, Section: a.Section, Type: a.Type, Meetings: meetings})
+	}
+
+	swaps := planner.Swaps(rest, planner.Course{CourseCode: code, Sections: sections}, sectionID)
+	respond(c, http.StatusOK, gin.H{
+		"data":  swaps,
+		"count": len(swaps),
+	})
+}
+
+func bindSavedSchedule(c *gin.Context) (*models.SavedSchedule, bool) {
+	var req models.SavedScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if problem := req.Normalize(); problem != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+		return nil, false
+	}
+	return &models.SavedSchedule{
+		Email:       req.Email,
+		Name:        req.Name,
+		SectionIDs:  req.SectionIDs,
+		ActivityIDs: req.ActivityIDs,
+	}, true
+}
+
+func scheduleIDParam(c *gin.Context) (string, bool) {
+	scheduleID := c.Param("id")
+	if !id.Valid(scheduleID) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id must be a UUID", "code": models.ErrCodeInvalidID})
+		return "", false
+	}
+	return scheduleID, true
+}
+
+func emailQuery(c *gin.Context) (string, bool) {
+	var query struct {
+		Email string `form:"email" binding:"required,email"`
+	}
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter 'email' must be a valid email"})
+		return "", false
+	}
+	return strings.TrimSpace(query.Email), true
+}
+
+// newFeedToken returns a random calendar feed token and the hash stored for it.
+func newFeedToken() (token, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("generate token: %w", err)
+	}
+	token = hex.EncodeToString(b)
+	return token, hashFeedToken(token), nil
+}
+
+func hashFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
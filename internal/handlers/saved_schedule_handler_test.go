@@ -0,0 +1,348 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+	"yuplan/internal/dbtypes"
+	"yuplan/internal/mailer"
+	"yuplan/internal/models"
+	"yuplan/internal/planner"
+	"yuplan/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testScheduleID = "0190f3a2-7b1c-7d2e-8f3a-1b2c3d4e5f70"
+	testSectionID  = "0190f3a2-7b1c-7d2e-8f3a-1b2c3d4e5f60"
+)
+
+type mockSavedScheduleRepository struct {
+	schedules map[string]*models.SavedSchedule
+	tokens    map[string]string // schedule id -> token hash
+	err       error
+
+	saved *models.SavedSchedule
+}
+
+func (m *mockSavedScheduleRepository) owned(id, email string) *models.SavedSchedule {
+	if s := m.schedules[id]; s != nil && s.Email == email {
+		return s
+	}
+	return nil
+}
+
+func (m *mockSavedScheduleRepository) List(ctx context.Context, email string) ([]models.SavedSchedule, error) {
+	schedules := []models.SavedSchedule{}
+	for _, s := range m.schedules {
+		if s.Email == email {
+			schedules = append(schedules, *s)
+		}
+	}
+	return schedules, m.err
+}
+
+func (m *mockSavedScheduleRepository) Get(ctx context.Context, id, email string) (*models.SavedSchedule, error) {
+	return m.owned(id, email), m.err
+}
+
+func (m *mockSavedScheduleRepository) Create(ctx context.Context, schedule *models.SavedSchedule) error {
+	if m.err != nil {
+		return m.err
+	}
+	schedule.ID = testScheduleID
+	schedule.CreatedAt = time.Now()
+	m.saved = schedule
+	return nil
+}
+
+func (m *mockSavedScheduleRepository) Update(ctx context.Context, schedule *models.SavedSchedule) (bool, error) {
+	if m.err != nil || m.owned(schedule.ID, schedule.Email) == nil {
+		return false, m.err
+	}
+	schedule.Subscribed = m.tokens[schedule.ID] != ""
+	m.schedules[schedule.ID] = schedule
+	m.saved = schedule
+	return true, nil
+}
+
+func (m *mockSavedScheduleRepository) Delete(ctx context.Context, id, email string) (bool, error) {
+	if m.err != nil || m.owned(id, email) == nil {
+		return false, m.err
+	}
+	delete(m.schedules, id)
+	return true, nil
+}
+
+func (m *mockSavedScheduleRepository) SetFeedToken(ctx context.Context, id, email, tokenHash string) (bool, error) {
+	if m.err != nil || m.owned(id, email) == nil {
+		return false, m.err
+	}
+	m.tokens[id] = tokenHash
+	return true, nil
+}
+
+func (m *mockSavedScheduleRepository) GetByFeedToken(ctx context.Context, id, tokenHash string) (*models.SavedSchedule, error) {
+	if m.err != nil || m.tokens[id] == "" || m.tokens[id] != tokenHash {
+		return nil, m.err
+	}
+	return m.schedules[id], nil
+}
+
+type fakeMailer struct {
+	sent []mailer.Message
+	err  error
+}
+
+func (f *fakeMailer) Send(ctx context.Context, msg mailer.Message) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.sent = append(f.sent, msg)
+	return nil
+}
+
+func newSavedScheduleRouter(repo *mockSavedScheduleRepository, calendar *stubScheduleCalendar) *gin.Engine {
+	return newSavedScheduleRouterWithMailer(repo, calendar, &fakeMailer{})
+}
+
+func newSavedScheduleRouterWithMailer(repo *mockSavedScheduleRepository, calendar *stubScheduleCalendar, m *fakeMailer) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewSavedScheduleHandler(repo, calendar, m, "https://api.yuplan.ca/api/v1/users/me/schedules/")
+	router := gin.New()
+	router.GET("/users/me/schedules", handler.GetSchedules)
+	router.POST("/users/me/schedules", handler.CreateSchedule)
+	router.PUT("/users/me/schedules/:id", handler.UpdateSchedule)
+	router.DELETE("/users/me/schedules/:id", handler.DeleteSchedule)
+	router.POST("/users/me/schedules/:id/calendar-token", handler.IssueCalendarToken)
+	router.DELETE("/users/me/schedules/:id/calendar-token", handler.RevokeCalendarToken)
+	router.GET("/users/me/schedules/:id/calendar.ics", handler.GetCalendarFeed)
+	return router
+}
+
+func newMockSavedScheduleRepository() *mockSavedScheduleRepository {
+	return &mockSavedScheduleRepository{
+		schedules: map[string]*models.SavedSchedule{
+			testScheduleID: {ID: testScheduleID, Email: "a@yorku.ca", Name: "Fall plan", SectionIDs: []string{testSectionID}, ActivityIDs: []string{}},
+		},
+		tokens: map[string]string{},
+	}
+}
+
+func TestGetSchedules(t *testing.T) {
+	router := newSavedScheduleRouter(newMockSavedScheduleRepository(), &stubScheduleCalendar{})
+
+	w := serveSubscriptions(router, http.MethodGet, "/users/me/schedules?email=a@yorku.ca", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"Fall plan"`)
+	assert.Contains(t, w.Body.String(), `"count":1`)
+	assert.NotContains(t, w.Body.String(), "a@yorku.ca", "email is redacted from public payloads")
+
+	w = serveSubscriptions(router, http.MethodGet, "/users/me/schedules", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCreateSchedule(t *testing.T) {
+	repo := newMockSavedScheduleRepository()
+	router := newSavedScheduleRouter(repo, &stubScheduleCalendar{})
+
+	w := serveSubscriptions(router, http.MethodPost, "/users/me/schedules",
+		`{"email": "a@yorku.ca", "name": " Winter ", "section_ids": ["`+testSectionID+`", "`+testSectionID+`"]}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "Winter", repo.saved.Name)
+	assert.Equal(t, []string{testSectionID}, repo.saved.SectionIDs)
+	assert.Equal(t, []string{}, repo.saved.ActivityIDs)
+
+	for _, body := range []string{
+		`{"email": "a@yorku.ca", "name": "x"}`,
+		`{"email": "a@yorku.ca", "name": " ", "section_ids": ["` + testSectionID + `"]}`,
+		`{"email": "a@yorku.ca", "name": "x", "section_ids": ["abc"]}`,
+		`{"email": "not-an-email", "name": "x", "section_ids": ["` + testSectionID + `"]}`,
+	} {
+		w = serveSubscriptions(router, http.MethodPost, "/users/me/schedules", body)
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+
+	repo.err = repository.ErrDuplicateScheduleName
+	w = serveSubscriptions(router, http.MethodPost, "/users/me/schedules",
+		`{"email": "a@yorku.ca", "name": "Fall plan", "section_ids": ["`+testSectionID+`"]}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestUpdateAndDeleteSchedule(t *testing.T) {
+	repo := newMockSavedScheduleRepository()
+	router := newSavedScheduleRouter(repo, &stubScheduleCalendar{})
+	labID := "0190f3a2-7b1c-7d2e-8f3a-1b2c3d4e5f61"
+
+	w := serveSubscriptions(router, http.MethodPut, "/users/me/schedules/"+testScheduleID,
+		`{"email": "a@yorku.ca", "name": "Fall plan", "section_ids": ["`+testSectionID+`"], "activity_ids": ["`+labID+`"]}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{labID}, repo.saved.ActivityIDs)
+
+	w = serveSubscriptions(router, http.MethodPut, "/users/me/schedules/"+testScheduleID,
+		`{"email": "b@yorku.ca", "name": "Fall plan", "section_ids": ["`+testSectionID+`"]}`)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = serveSubscriptions(router, http.MethodPut, "/users/me/schedules/not-a-uuid",
+		`{"email": "a@yorku.ca", "name": "Fall plan", "section_ids": ["`+testSectionID+`"]}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = serveSubscriptions(router, http.MethodDelete, "/users/me/schedules/"+testScheduleID+"?email=b@yorku.ca", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = serveSubscriptions(router, http.MethodDelete, "/users/me/schedules/"+testScheduleID+"?email=a@yorku.ca", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, repo.schedules)
+}
+
+func TestCalendarFeed(t *testing.T) {
+	repo := newMockSavedScheduleRepository()
+	calendar := &stubScheduleCalendar{activities: []models.CalendarActivity{{
+		ID: testSectionID, CourseCode: "EECS2030", Term: models.TermFall, Section: "A", Type: models.ActivityLecture,
+		Times:        dbtypes.NewNullString(`[{"day": "M", "time": "10:00", "duration": "80", "campus": "Keele", "room": "CLH A"}]`),
+		SessionStart: dbtypes.NewNullTime(time.Date(2025, time.September, 2, 0, 0, 0, 0, time.UTC)),
+		SessionEnd:   dbtypes.NewNullTime(time.Date(2026, time.April, 30, 0, 0, 0, 0, time.UTC)),
+	}}}
+	m := &fakeMailer{}
+	router := newSavedScheduleRouterWithMailer(repo, calendar, m)
+	feed := "/users/me/schedules/" + testScheduleID + "/calendar.ics"
+
+	w := serveSubscriptions(router, http.MethodPost, "/users/me/schedules/"+testScheduleID+"/calendar-token", `{"email": "b@yorku.ca"}`)
+	assert.Equal(t, http.StatusNotFound, w.Code, "only the owner can subscribe")
+
+	assert.Empty(t, m.sent)
+
+	w = serveSubscriptions(router, http.MethodPost, "/users/me/schedules/"+testScheduleID+"/calendar-token", `{"email": "a@yorku.ca"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, m.sent, 1)
+	assert.Equal(t, "a@yorku.ca", m.sent[0].To)
+	link := regexp.MustCompile(`https://\S+`).FindString(m.sent[0].Body)
+	require.NotEmpty(t, link)
+	assert.Contains(t, link, "https://api.yuplan.ca/api/v1"+feed+"?token=")
+	u, err := url.Parse(link)
+	require.NoError(t, err)
+	token := u.Query().Get("token")
+	assert.Len(t, token, 64)
+	assert.NotContains(t, w.Body.String(), token, "the token only goes to the schedule's email")
+	assert.NotEqual(t, token, repo.tokens[testScheduleID], "only the hash is stored")
+
+	w = serveSubscriptions(router, http.MethodGet, feed+"?token="+token, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/calendar; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "no-cache", w.Header().Get("Cache-Control"))
+	assert.Contains(t, w.Body.String(), "DTSTART;TZID=America/Toronto:20250908T100000")
+	assert.Equal(t, []string{testSectionID}, calendar.gotSectionIDs)
+
+	// Sections that are gone leave an empty calendar, not an error
+	calendar.activities = nil
+	w = serveSubscriptions(router, http.MethodGet, feed+"?token="+token, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "BEGIN:VCALENDAR")
+	assert.NotContains(t, w.Body.String(), "BEGIN:VEVENT")
+
+	w = serveSubscriptions(router, http.MethodGet, feed+"?token=wrong", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = serveSubscriptions(router, http.MethodGet, feed, "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = serveSubscriptions(router, http.MethodDelete, "/users/me/schedules/"+testScheduleID+"/calendar-token?email=a@yorku.ca", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = serveSubscriptions(router, http.MethodGet, feed+"?token="+token, "")
+	assert.Equal(t, http.StatusNotFound, w.Code, "a revoked token stops the feed")
+}
+
+func TestIssueCalendarToken_MailFails(t *testing.T) {
+	m := &fakeMailer{err: errors.New("smtp down")}
+	router := newSavedScheduleRouterWithMailer(newMockSavedScheduleRepository(), &stubScheduleCalendar{}, m)
+
+	w := serveSubscriptions(router, http.MethodPost, "/users/me/schedules/"+testScheduleID+"/calendar-token", `{"email": "a@yorku.ca"}`)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "token=")
+}
+
+type stubScheduleSwaps struct {
+	code     string
+	sections []models.Section
+}
+
+func (s *stubScheduleSwaps) GetSiblings(ctx context.Context, sectionID string) (string, []models.Section, error) {
+	return s.code, s.sections, nil
+}
+
+func withID(id string, a models.SectionActivity) models.SectionActivity {
+	a.ID = id
+	return a
+}
+
+func TestSuggestSwaps(t *testing.T) {
+	const (
+		mathSection = "0190f3a2-7b1c-7d2e-8f3a-1b2c3d4e5f80"
+		mathTut     = "0190f3a2-7b1c-7d2e-8f3a-1b2c3d4e5f81"
+		droppedLab  = "0190f3a2-7b1c-7d2e-8f3a-1b2c3d4e5f62"
+	)
+	repo := newMockSavedScheduleRepository()
+	repo.schedules[testScheduleID].SectionIDs = []string{testSectionID, mathSection}
+	repo.schedules[testScheduleID].ActivityIDs = []string{droppedLab, mathTut}
+	calendar := &stubScheduleCalendar{activities: []models.CalendarActivity{{
+		ID: mathTut, CourseCode: "MATH1090", Section: "M", Type: models.ActivityTutorial,
+		Times: dbtypes.NewNullString(`[{"day": "M", "time": "11:30", "duration": "80", "campus": "Keele"}]`),
+	}}}
+	swaps := &stubScheduleSwaps{code: "EECS2030", sections: []models.Section{
+		{ID: testSectionID, Letter: "A", Activities: []models.SectionActivity{
+			withID("a1", lecture("A1", "M", "10:00", "80")),
+			{ID: droppedLab, CourseType: models.ActivityLab, Times: dbtypes.NewNullString(`[{"day": "W", "time": "10:00", "duration": "170", "campus": "Keele"}]`)},
+		}},
+		{ID: "section-b", Letter: "B", Activities: []models.SectionActivity{
+			withID("b1", lecture("B1", "T", "10:00", "80")),
+		}},
+		{ID: "section-c", Letter: "C", Activities: []models.SectionActivity{
+			withID("c1", lecture("C1", "M", "12:00", "80")),
+		}},
+		{ID: "section-d", Letter: "D", Activities: []models.SectionActivity{
+			withID("d1", lecture("D1", "M", "13:00", "80")),
+		}},
+	}}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/schedules/:id/swap-suggestions",
+		NewSavedScheduleHandler(repo, calendar, &fakeMailer{}, "").WithSwaps(swaps).SuggestSwaps)
+	path := "/schedules/" + testScheduleID + "/swap-suggestions"
+
+	w := serveSubscriptions(router, http.MethodPost, path, `{"email": "a@yorku.ca", "section_id": "`+testSectionID+`"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var body struct {
+		Data  []planner.Swap `json:"data"`
+		Count int            `json:"count"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, 2, body.Count)
+	assert.Equal(t, "section-d", body.Data[0].SectionID, "one day on campus ranks first")
+	assert.Equal(t, "d1", body.Data[0].Activities[0].ID)
+	assert.Equal(t, "section-b", body.Data[1].SectionID)
+	assert.Equal(t, []string{mathSection}, calendar.gotSectionIDs)
+	assert.Equal(t, []string{mathTut}, calendar.gotActivityIDs, "the lab chosen in the dropped section goes with it")
+
+	w = serveSubscriptions(router, http.MethodPost, path, `{"email": "b@yorku.ca", "section_id": "`+testSectionID+`"}`)
+	assert.Equal(t, http.StatusNotFound, w.Code, "only the owner can ask")
+
+	w = serveSubscriptions(router, http.MethodPost, path, `{"email": "a@yorku.ca", "section_id": "`+mathTut+`"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	w = serveSubscriptions(router, http.MethodPost, path, `{"email": "a@yorku.ca"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	swaps.sections = nil
+	w = serveSubscriptions(router, http.MethodPost, path, `{"email": "a@yorku.ca", "section_id": "`+mathSection+`"}`)
+	assert.Equal(t, http.StatusNotFound, w.Code, "a section gone since the schedule was saved")
+}
@@ -0,0 +1,356 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+	"yuplan/internal/ical"
+	"yuplan/internal/id"
+	"yuplan/internal/models"
+	"yuplan/internal/planner"
+	"yuplan/internal/render"
+	"yuplan/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultScheduleLimit = 20
+	maxScheduleLimit     = 50
+)
+
+// scheduleMetrics counts timetable generations by whether anything fit. Implemented by metrics.Business.
+type scheduleMetrics interface {
+	ScheduleGenerated(found int)
+}
+
+// scheduleActivities loads the activities a timetable is made of. Implemented by repository.SectionActivityRepository.
+type scheduleActivities interface {
+	ListByIDs(ctx context.Context, ids []string) ([]models.TeachingActivity, error)
+}
+
+// scheduleRenderer draws a week grid as an image. Implemented by render.Renderer.
+type scheduleRenderer interface {
+	PNG(w io.Writer, days []models.ScheduleDay, opts render.Options) error
+}
+
+// scheduleCalendar loads activities with their session dates. Implemented by repository.SectionActivityRepository.
+type scheduleCalendar interface {
+	ListForCalendar(ctx context.Context, sectionIDs, activityIDs []string) ([]models.CalendarActivity, error)
+}
+
+// scheduleBlocks lists the blocks a course offering enrolls students into. Implemented by repository.BlockRepository.
+type scheduleBlocks interface {
+	GetByCourseID(ctx context.Context, courseID string) ([]models.Block, error)
+}
+
+type ScheduleHandler struct {
+	courses    repository.CourseRepositoryInterface
+	sections   repository.SectionRepositoryInterface
+	blocks     scheduleBlocks
+	metrics    scheduleMetrics
+	activities scheduleActivities
+	renderer   scheduleRenderer
+	calendar   scheduleCalendar
+}
+
+func NewScheduleHandler(courses repository.CourseRepositoryInterface, sections repository.SectionRepositoryInterface) *ScheduleHandler {
+	return &ScheduleHandler{courses: courses, sections: sections}
+}
+
+// WithBlocks makes GenerateSchedules take courses that have blocks a whole
+// block at a time.
+func (h *ScheduleHandler) WithBlocks(blocks scheduleBlocks) *ScheduleHandler {
+	h.blocks = blocks
+	return h
+}
+
+// WithMetrics counts generations that reach the planner.
+func (h *ScheduleHandler) WithMetrics(metrics scheduleMetrics) *ScheduleHandler {
+	h.metrics = metrics
+	return h
+}
+
+// WithImages enables ExportPNG, drawing the activities it loads with renderer.
+func (h *ScheduleHandler) WithImages(activities scheduleActivities, renderer scheduleRenderer) *ScheduleHandler {
+	h.activities = activities
+	h.renderer = renderer
+	return h
+}
+
+// WithCalendar enables ExportICal.
+func (h *ScheduleHandler) WithCalendar(calendar scheduleCalendar) *ScheduleHandler {
+	h.calendar = calendar
+	return h
+}
+
+// GenerateSchedules handles POST /api/v1/schedules/generate
+// Body: {"course_codes": ["EECS2030", "MATH1090"], "term": "F", "earliest_start": "10:00", "days_off": ["F"]}
+// Returns conflict-free timetables, one section of each course with one of
+// each of its activity types, best first; see planner.Generate.
+func (h *ScheduleHandler) GenerateSchedules(c *gin.Context) {
+	var req models.GenerateScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	codes := scheduleCourseCodes(req.CourseCodes)
+	if len(codes) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "course_codes is required"})
+		return
+	}
+	if len(codes) > models.MaxScheduleCourses {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("At most %d courses per timetable", models.MaxScheduleCourses)})
+		return
+	}
+	if !isTerm(req.Term) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unknown term %q", req.Term)})
+		return
+	}
+	constraints, err := scheduleConstraints(req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	limit := req.Limit
+	if limit < 1 || limit > maxScheduleLimit {
+		limit = defaultScheduleLimit
+	}
+
+	courses := make([]planner.Course, 0, len(codes))
+	var notOffered []string
+	for _, code := range codes {
+		course, err := h.load(c, code, req.Term)
+		if err != nil {
+			serverError(c, err, "Failed to fetch courses")
+			return
+		}
+		if len(course.Sections) == 0 {
+			notOffered = append(notOffered, code)
+			continue
+		}
+		courses = append(courses, course)
+	}
+	if len(notOffered) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":       fmt.Sprintf("Not offered in term %s: %s", req.Term, strings.Join(notOffered, ", ")),
+			"not_offered": notOffered,
+		})
+		return
+	}
+
+	result := planner.Generate(courses, constraints, planner.TransferPreference{
+		BufferMinutes: req.TransferBufferMinutes,
+		Reject:        req.RejectTightTransfers,
+	}, limit)
+	if h.metrics != nil {
+		h.metrics.ScheduleGenerated(result.Found)
+	}
+
+	body := gin.H{
+		"data":      result.Schedules,
+		"count":     len(result.Schedules),
+		"found":     result.Found,
+		"truncated": result.Truncated,
+	}
+	if len(result.Reasons) > 0 {
+		body["reasons"] = result.Reasons
+	}
+	respond(c, http.StatusOK, body)
+}
+
+// ExportPNG handles POST /api/v1/schedules/export.png
+// Body: {"activity_ids": ["..."], "title": "Fall 2025", "theme": "dark", "font_size": "large"}
+// Returns the activities' weekly meetings drawn as a timetable image, for
+// sharing. Ids that don't exist are left out; asynchronous activities have
+// no slot in the grid.
+func (h *ScheduleHandler) ExportPNG(c *gin.Context) {
+	if h.activities == nil || h.renderer == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Schedule images not configured"})
+		return
+	}
+	var req models.ScheduleImageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	activities, err := h.activities.ListByIDs(c.Request.Context(), req.ActivityIDs)
+	if err != nil {
+		serverError(c, err, "Failed to fetch activities")
+		return
+	}
+	if len(activities) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No activities found"})
+		return
+	}
+
+	var buf bytes.Buffer
+	opts := render.Options{Title: req.Title, Theme: req.Theme, FontSize: req.FontSize}
+	if err := h.renderer.PNG(&buf, models.WeekGrid(activities), opts); err != nil {
+		serverError(c, err, "Failed to render schedule")
+		return
+	}
+	c.Header("Content-Disposition", `inline; filename="schedule.png"`)
+	c.Data(http.StatusOK, "image/png", buf.Bytes())
+}
+
+// ExportICal handles GET /api/v1/export/ical?section_ids=...&activity_ids=...
+// Returns an iCalendar file with a weekly recurring event for every meeting
+// of the sections' lectures and other shared activities, plus the chosen labs
+// and tutorials given as activity_ids, over the dates of the course's term.
+// Ids that don't exist are left out, as are activities whose section has no
+// session dates and asynchronous ones.
+func (h *ScheduleHandler) ExportICal(c *gin.Context) {
+	if h.calendar == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Calendar export not configured"})
+		return
+	}
+	sectionIDs, err := queryIDs(c, "section_ids")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": models.ErrCodeInvalidID})
+		return
+	}
+	activityIDs, err := queryIDs(c, "activity_ids")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": models.ErrCodeInvalidID})
+		return
+	}
+	if len(sectionIDs)+len(activityIDs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "section_ids or activity_ids is required"})
+		return
+	}
+	if len(sectionIDs)+len(activityIDs) > models.MaxCalendarIDs {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("At most %d ids per calendar", models.MaxCalendarIDs)})
+		return
+	}
+
+	activities, err := h.calendar.ListForCalendar(c.Request.Context(), sectionIDs, activityIDs)
+	if err != nil {
+		serverError(c, err, "Failed to fetch activities")
+		return
+	}
+	if len(activities) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No activities found"})
+		return
+	}
+
+	var buf bytes.Buffer
+	if _, err := ical.Write(&buf, activities, time.Now()); err != nil {
+		serverError(c, err, "Failed to write calendar")
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="schedule.ics"`)
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", buf.Bytes())
+}
+
+// queryIDs reads a comma-separated list of UUIDs from a query parameter, dropping repeats.
+func queryIDs(c *gin.Context, name string) ([]string, error) {
+	var ids []string
+	seen := map[string]bool{}
+	for _, raw := range strings.Split(c.Query(name), ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" || seen[raw] {
+			continue
+		}
+		if !id.Valid(raw) {
+			return nil, fmt.Errorf("%s must be UUIDs, got %q", name, raw)
+		}
+		seen[raw] = true
+		ids = append(ids, raw)
+	}
+	return ids, nil
+}
+
+// load gathers the sections and blocks of every offering of code that runs in
+// term. A course with none, including one that doesn't exist, has no sections.
+func (h *ScheduleHandler) load(c *gin.Context, code, term string) (planner.Course, error) {
+	course := planner.Course{CourseCode: code}
+	offerings, err := h.courses.GetByCode(c.Request.Context(), code)
+	if err != nil {
+		return course, err
+	}
+	for _, offering := range offerings {
+		if !models.TermRunsIn(offering.Term, term) {
+			continue
+		}
+		sections, err := h.sections.GetByCourseID(c.Request.Context(), offering.ID)
+		if err != nil {
+			return course, err
+		}
+		course.Sections = append(course.Sections, sections...)
+
+		if h.blocks == nil {
+			continue
+		}
+		blocks, err := h.blocks.GetByCourseID(c.Request.Context(), offering.ID)
+		if err != nil {
+			return course, err
+		}
+		course.Blocks = append(course.Blocks, blocks...)
+	}
+	return course, nil
+}
+
+// scheduleCourseCodes normalizes codes to upper case without spaces and drops repeats.
+func scheduleCourseCodes(raw []string) []string {
+	seen := map[string]bool{}
+	var codes []string
+	for _, code := range raw {
+		code = strings.ToUpper(strings.ReplaceAll(code, " ", ""))
+		if code == "" || seen[code] {
+			continue
+		}
+		seen[code] = true
+		codes = append(codes, code)
+	}
+	return codes
+}
+
+func scheduleConstraints(req models.GenerateScheduleRequest) (planner.Constraints, error) {
+	var constraints planner.Constraints
+	var err error
+	if constraints.EarliestStart, err = clockMinutes("earliest_start", req.EarliestStart); err != nil {
+		return constraints, err
+	}
+	if constraints.LatestEnd, err = clockMinutes("latest_end", req.LatestEnd); err != nil {
+		return constraints, err
+	}
+	if constraints.LatestEnd > 0 && constraints.LatestEnd <= constraints.EarliestStart {
+		return constraints, errors.New("latest_end must be after earliest_start")
+	}
+	for _, day := range req.DaysOff {
+		if !isWeekday(day) {
+			return constraints, fmt.Errorf("Unknown day %q; use one of %s", day, strings.Join(models.Weekdays, ", "))
+		}
+		constraints.DaysOff = append(constraints.DaysOff, day)
+	}
+	return constraints, nil
+}
+
+// clockMinutes parses an optional "HH:MM" into minutes since midnight.
+func clockMinutes(field, value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a time like 10:00", field)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func isWeekday(day string) bool {
+	for _, d := range models.Weekdays {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
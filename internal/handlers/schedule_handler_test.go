@@ -0,0 +1,309 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"yuplan/internal/dbtypes"
+	"yuplan/internal/models"
+	"yuplan/internal/render"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// scheduleCatalog holds each course code's offerings and each offering's sections.
+type scheduleCatalog struct {
+	courses  map[string][]models.Course
+	sections map[string][]models.Section
+	blocks   map[string][]models.Block
+	err      error
+}
+
+func (s scheduleCatalog) GetByCourseID(ctx context.Context, courseID string) ([]models.Block, error) {
+	return s.blocks[courseID], nil
+}
+
+func (s scheduleCatalog) router() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	courses := &MockCourseRepository{getByCode: func(ctx context.Context, code string) ([]models.Course, error) {
+		return s.courses[code], s.err
+	}}
+	sections := &MockSectionRepositoryForCourseHandler{getByCourseID: func(ctx context.Context, id string) ([]models.Section, error) {
+		return s.sections[id], nil
+	}}
+	router := gin.New()
+	router.POST("/schedules/generate", NewScheduleHandler(courses, sections).WithBlocks(s).GenerateSchedules)
+	return router
+}
+
+func lecture(catalog, day, start, duration string) models.SectionActivity {
+	times, _ := json.Marshal([]models.Meeting{{Day: day, Time: start, Duration: duration, Campus: "Keele"}})
+	return models.SectionActivity{CourseType: models.ActivityLecture, CatalogNumber: catalog, Times: dbtypes.NewNullString(string(times))}
+}
+
+func testCatalog() scheduleCatalog {
+	return scheduleCatalog{
+		courses: map[string][]models.Course{
+			"EECS2030": {{ID: "eecs-f", Term: models.TermFall}, {ID: "eecs-w", Term: models.TermWinter}},
+			"MATH1090": {{ID: "math-y", Term: models.TermFullYear}},
+		},
+		sections: map[string][]models.Section{
+			"eecs-f": {
+				{Letter: "A", Activities: []models.SectionActivity{lecture("EF-A", "M", "08:30", "80")}},
+				{Letter: "B", Activities: []models.SectionActivity{lecture("EF-B", "T", "13:00", "80")}},
+			},
+			"eecs-w": {{Letter: "A", Activities: []models.SectionActivity{lecture("EW-A", "W", "13:00", "80")}}},
+			"math-y": {{Letter: "M", Activities: []models.SectionActivity{lecture("MY-M", "T", "13:30", "80"), lecture("MY-M2", "R", "13:30", "80")}}},
+		},
+	}
+}
+
+func generate(router *gin.Engine, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/schedules/generate", strings.NewReader(body)))
+	return w
+}
+
+func TestGenerateSchedules(t *testing.T) {
+	w := generate(testCatalog().router(), `{"course_codes": ["eecs 2030"], "term": "F", "earliest_start": "10:00"}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var body struct {
+		Data  []map[string]any `json:"data"`
+		Count int              `json:"count"`
+		Found int              `json:"found"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, 1, body.Found)
+	assert.Equal(t, 1, body.Count)
+	assert.Contains(t, w.Body.String(), `"catalog_number":"EF-B"`)
+	assert.NotContains(t, w.Body.String(), "EW-A")
+	assert.NotContains(t, w.Body.String(), `"reasons"`)
+}
+
+func TestGenerateSchedules_NothingFits(t *testing.T) {
+	catalog := testCatalog()
+	catalog.sections["math-y"] = []models.Section{{Letter: "M", Activities: []models.SectionActivity{lecture("MY-M", "T", "13:30", "80")}}}
+
+	w := generate(catalog.router(), `{"course_codes": ["EECS2030", "MATH1090"], "term": "F", "days_off": ["M"]}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"count":0`)
+	assert.Contains(t, w.Body.String(), "EECS2030 Section B Lecture overlaps MATH1090 Section M Lecture on Tuesdays 13:30–14:20")
+}
+
+func TestGenerateSchedules_Blocks(t *testing.T) {
+	catalog := testCatalog()
+	tutorial := lecture("EF-A-T", "R", "10:00", "50")
+	tutorial.CourseType = models.ActivityTutorial
+	catalog.sections["eecs-f"][0].Activities = append(catalog.sections["eecs-f"][0].Activities, tutorial)
+	catalog.blocks = map[string][]models.Block{
+		"eecs-f": {{Name: "Block 1", Activities: []models.SectionActivity{lecture("EF-A", "M", "08:30", "80"), tutorial}}},
+	}
+
+	// Section B would fit on its own, but the course's only block is section A's lecture and tutorial
+	w := generate(catalog.router(), `{"course_codes": ["EECS2030"], "term": "F"}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"found":1`)
+	assert.Contains(t, w.Body.String(), `"catalog_number":"EF-A"`)
+	assert.Contains(t, w.Body.String(), `"catalog_number":"EF-A-T"`)
+	assert.NotContains(t, w.Body.String(), "EF-B")
+}
+
+func TestGenerateSchedules_NotOffered(t *testing.T) {
+	w := generate(testCatalog().router(), `{"course_codes": ["EECS2030", "MATH1090", "PHYS1010"], "term": "SU"}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), `"not_offered":["EECS2030","MATH1090","PHYS1010"]`)
+}
+
+func TestGenerateSchedules_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"no courses", `{"course_codes": [], "term": "F"}`, "CourseCodes"},
+		{"blank courses", `{"course_codes": [" "], "term": "F"}`, "course_codes is required"},
+		{"too many courses", `{"course_codes": ["A1","A2","A3","A4","A5","A6","A7","A8","A9"], "term": "F"}`, "At most 8 courses"},
+		{"unknown term", `{"course_codes": ["EECS2030"], "term": "Fall"}`, `Unknown term \"Fall\"`},
+		{"bad time", `{"course_codes": ["EECS2030"], "term": "F", "earliest_start": "10am"}`, "earliest_start must be a time"},
+		{"inverted times", `{"course_codes": ["EECS2030"], "term": "F", "earliest_start": "12:00", "latest_end": "11:00"}`, "latest_end must be after"},
+		{"unknown day", `{"course_codes": ["EECS2030"], "term": "F", "days_off": ["Fri"]}`, `Unknown day \"Fri\"`},
+		{"negative buffer", `{"course_codes": ["EECS2030"], "term": "F", "transfer_buffer_minutes": -5}`, "TransferBufferMinutes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := generate(testCatalog().router(), tt.body)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), tt.want)
+		})
+	}
+}
+
+func TestGenerateSchedules_RepoError(t *testing.T) {
+	catalog := testCatalog()
+	catalog.err = errors.New("db down")
+
+	w := generate(catalog.router(), `{"course_codes": ["EECS2030"], "term": "F"}`)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+type stubScheduleActivities struct {
+	activities []models.TeachingActivity
+	err        error
+	gotIDs     []string
+}
+
+func (s *stubScheduleActivities) ListByIDs(ctx context.Context, ids []string) ([]models.TeachingActivity, error) {
+	s.gotIDs = ids
+	return s.activities, s.err
+}
+
+type stubScheduleRenderer struct {
+	opts render.Options
+	days []models.ScheduleDay
+}
+
+func (s *stubScheduleRenderer) PNG(w io.Writer, days []models.ScheduleDay, opts render.Options) error {
+	s.days, s.opts = days, opts
+	return render.NewRenderer().PNG(w, days, opts)
+}
+
+func exportPNG(activities *stubScheduleActivities, renderer *stubScheduleRenderer, body string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/schedules/export.png", NewScheduleHandler(&MockCourseRepository{}, &MockSectionRepositoryForCourseHandler{}).
+		WithImages(activities, renderer).ExportPNG)
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/schedules/export.png", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestExportPNG(t *testing.T) {
+	activities := &stubScheduleActivities{activities: []models.TeachingActivity{{
+		CourseCode: "EECS2030", Term: models.TermFall, Section: "A", Type: models.ActivityLecture,
+		Times: dbtypes.NewNullString(`[{"day": "M", "time": "10:00", "duration": "80", "campus": "Keele", "room": "CLH A"}]`),
+	}}}
+	renderer := &stubScheduleRenderer{}
+	id := "0190f3a2-7b1c-7d2e-8f3a-1b2c3d4e5f60"
+
+	w := exportPNG(activities, renderer, `{"activity_ids": ["`+id+`"], "title": "Fall", "theme": "dark", "font_size": "large"}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+	_, err := png.Decode(w.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{id}, activities.gotIDs)
+	assert.Equal(t, render.Options{Title: "Fall", Theme: render.ThemeDark, FontSize: render.FontLarge}, renderer.opts)
+	assert.Len(t, renderer.days[0].Meetings, 1)
+}
+
+func TestExportPNG_Errors(t *testing.T) {
+	id := `"0190f3a2-7b1c-7d2e-8f3a-1b2c3d4e5f60"`
+	tests := []struct {
+		name       string
+		activities *stubScheduleActivities
+		body       string
+		wantStatus int
+	}{
+		{"no ids", &stubScheduleActivities{}, `{"activity_ids": []}`, http.StatusBadRequest},
+		{"malformed id", &stubScheduleActivities{}, `{"activity_ids": ["abc"]}`, http.StatusBadRequest},
+		{"unknown theme", &stubScheduleActivities{}, `{"activity_ids": [` + id + `], "theme": "neon"}`, http.StatusBadRequest},
+		{"unknown font size", &stubScheduleActivities{}, `{"activity_ids": [` + id + `], "font_size": "huge"}`, http.StatusBadRequest},
+		{"nothing found", &stubScheduleActivities{}, `{"activity_ids": [` + id + `]}`, http.StatusNotFound},
+		{"repo error", &stubScheduleActivities{err: errors.New("db down")}, `{"activity_ids": [` + id + `]}`, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := exportPNG(tt.activities, &stubScheduleRenderer{}, tt.body)
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+type stubScheduleCalendar struct {
+	activities     []models.CalendarActivity
+	err            error
+	gotSectionIDs  []string
+	gotActivityIDs []string
+}
+
+func (s *stubScheduleCalendar) ListForCalendar(ctx context.Context, sectionIDs, activityIDs []string) ([]models.CalendarActivity, error) {
+	s.gotSectionIDs, s.gotActivityIDs = sectionIDs, activityIDs
+	return s.activities, s.err
+}
+
+func exportICal(calendar *stubScheduleCalendar, query string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/export/ical", NewScheduleHandler(&MockCourseRepository{}, &MockSectionRepositoryForCourseHandler{}).
+		WithCalendar(calendar).ExportICal)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/export/ical?"+query, nil))
+	return w
+}
+
+func TestExportICal(t *testing.T) {
+	sectionID := "0190f3a2-7b1c-7d2e-8f3a-1b2c3d4e5f60"
+	labID := "0190f3a2-7b1c-7d2e-8f3a-1b2c3d4e5f61"
+	calendar := &stubScheduleCalendar{activities: []models.CalendarActivity{{
+		ID: sectionID, CourseCode: "EECS2030", Term: models.TermFall, Section: "A", Type: models.ActivityLecture,
+		Times:        dbtypes.NewNullString(`[{"day": "M", "time": "10:00", "duration": "80", "campus": "Keele", "room": "CLH A"}]`),
+		SessionStart: dbtypes.NewNullTime(time.Date(2025, time.September, 2, 0, 0, 0, 0, time.UTC)),
+		SessionEnd:   dbtypes.NewNullTime(time.Date(2026, time.April, 30, 0, 0, 0, 0, time.UTC)),
+	}}}
+
+	w := exportICal(calendar, "section_ids="+sectionID+","+sectionID+"&activity_ids="+labID)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/calendar; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "schedule.ics")
+	assert.Contains(t, w.Body.String(), "DTSTART;TZID=America/Toronto:20250908T100000")
+	assert.Equal(t, []string{sectionID}, calendar.gotSectionIDs)
+	assert.Equal(t, []string{labID}, calendar.gotActivityIDs)
+}
+
+func TestExportICal_Errors(t *testing.T) {
+	id := "0190f3a2-7b1c-7d2e-8f3a-1b2c3d4e5f60"
+	tests := []struct {
+		name       string
+		calendar   *stubScheduleCalendar
+		query      string
+		wantStatus int
+	}{
+		{"no ids", &stubScheduleCalendar{}, "", http.StatusBadRequest},
+		{"malformed id", &stubScheduleCalendar{}, "activity_ids=abc", http.StatusBadRequest},
+		{"too many ids", &stubScheduleCalendar{}, "section_ids=" + manyIDs(models.MaxCalendarIDs+1), http.StatusBadRequest},
+		{"nothing found", &stubScheduleCalendar{}, "section_ids=" + id, http.StatusNotFound},
+		{"repo error", &stubScheduleCalendar{err: errors.New("db down")}, "section_ids=" + id, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := exportICal(tt.calendar, tt.query)
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+// manyIDs returns n distinct UUIDs separated by commas.
+func manyIDs(n int) string {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("0190f3a2-7b1c-7d2e-8f3a-%012d", i)
+	}
+	return strings.Join(ids, ",")
+}
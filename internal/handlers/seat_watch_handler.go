@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"yuplan/internal/models"
+	"yuplan/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SeatWatchHandler struct {
+	repo repository.SeatWatchRepositoryInterface
+}
+
+func NewSeatWatchHandler(repo repository.SeatWatchRepositoryInterface) *SeatWatchHandler {
+	return &SeatWatchHandler{repo: repo}
+}
+
+// CreateWatch handles POST /api/v1/watches
+// Body: {"email": "student@my.yorku.ca", "section_id": "..."}
+func (h *SeatWatchHandler) CreateWatch(c *gin.Context) {
+	var req models.CreateSeatWatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	watch := &models.SeatWatch{Email: strings.TrimSpace(req.Email), SectionID: req.SectionID}
+	found, err := h.repo.Create(c.Request.Context(), watch)
+	switch {
+	case errors.Is(err, repository.ErrDuplicateSeatWatch):
+		c.JSON(http.StatusConflict, gin.H{"error": "You are already watching this section", "code": models.ErrCodeConflict})
+		return
+	case errors.Is(err, repository.ErrTooManySeatWatches):
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("You can watch at most %d sections", models.MaxSeatWatches), "code": models.ErrCodeConflict})
+		return
+	case err != nil:
+		serverError(c, err, "Failed to save seat watch")
+		return
+	case !found:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Section not found"})
+		return
+	}
+
+	respond(c, http.StatusCreated, gin.H{"data": watch})
+}
+
+// ListWatches handles GET /api/v1/watches?email=
+func (h *SeatWatchHandler) ListWatches(c *gin.Context) {
+	var query struct {
+		Email string `form:"email" binding:"required,email"`
+	}
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter 'email' must be a valid email"})
+		return
+	}
+
+	watches, err := h.repo.List(c.Request.Context(), strings.TrimSpace(query.Email))
+	if err != nil {
+		serverError(c, err, "Failed to fetch seat watches")
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"data":  watches,
+		"count": len(watches),
+	})
+}
+
+// DeleteWatch handles DELETE /api/v1/watches/:id?email=
+func (h *SeatWatchHandler) DeleteWatch(c *gin.Context) {
+	var query struct {
+		Email string `form:"email" binding:"required,email"`
+	}
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter 'email' must be a valid email"})
+		return
+	}
+
+	removed, err := h.repo.Delete(c.Request.Context(), c.Param("id"), strings.TrimSpace(query.Email))
+	if err != nil {
+		serverError(c, err, "Failed to delete seat watch")
+		return
+	}
+	if !removed {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No such seat watch for that email"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Seat watch deleted"})
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"yuplan/internal/models"
+	"yuplan/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockSeatWatchRepository struct {
+	sections map[string]bool
+	watches  []models.SeatWatch
+	err      error
+}
+
+func (m *mockSeatWatchRepository) Create(ctx context.Context, watch *models.SeatWatch) (bool, error) {
+	if m.err != nil {
+		return false, m.err
+	}
+	if !m.sections[watch.SectionID] {
+		return false, nil
+	}
+	for _, w := range m.watches {
+		if w.Email == watch.Email && w.SectionID == watch.SectionID {
+			return true, repository.ErrDuplicateSeatWatch
+		}
+	}
+	watch.ID = "watch-1"
+	m.watches = append(m.watches, *watch)
+	return true, nil
+}
+
+func (m *mockSeatWatchRepository) List(ctx context.Context, email string) ([]models.SeatWatch, error) {
+	var watches []models.SeatWatch
+	for _, w := range m.watches {
+		if w.Email == email {
+			watches = append(watches, w)
+		}
+	}
+	return watches, m.err
+}
+
+func (m *mockSeatWatchRepository) Delete(ctx context.Context, id, email string) (bool, error) {
+	for i, w := range m.watches {
+		if w.ID == id && w.Email == email {
+			m.watches = append(m.watches[:i], m.watches[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, m.err
+}
+
+func newSeatWatchRouter(repo *mockSeatWatchRepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewSeatWatchHandler(repo)
+	router := gin.New()
+	router.GET("/watches", handler.ListWatches)
+	router.POST("/watches", handler.CreateWatch)
+	router.DELETE("/watches/:id", handler.DeleteWatch)
+	return router
+}
+
+func serveSeatWatches(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+	return w
+}
+
+func TestCreateWatch(t *testing.T) {
+	repo := &mockSeatWatchRepository{sections: map[string]bool{availableSection: true}}
+	router := newSeatWatchRouter(repo)
+	body := `{"email": "student@my.yorku.ca", "section_id": "` + availableSection + `"}`
+
+	w := serveSeatWatches(router, http.MethodPost, "/watches", body)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "student@my.yorku.ca", repo.watches[0].Email)
+	assert.NotContains(t, w.Body.String(), "student@my.yorku.ca")
+
+	w = serveSeatWatches(router, http.MethodPost, "/watches", body)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "already watching")
+}
+
+func TestCreateWatch_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		repo       *mockSeatWatchRepository
+		body       string
+		wantStatus int
+	}{
+		{"bad email", &mockSeatWatchRepository{}, `{"email": "nope", "section_id": "` + availableSection + `"}`, http.StatusBadRequest},
+		{"bad section id", &mockSeatWatchRepository{}, `{"email": "student@my.yorku.ca", "section_id": "42"}`, http.StatusBadRequest},
+		{"no such section", &mockSeatWatchRepository{}, `{"email": "student@my.yorku.ca", "section_id": "` + availableSection + `"}`, http.StatusNotFound},
+		{"too many", &mockSeatWatchRepository{err: repository.ErrTooManySeatWatches}, `{"email": "student@my.yorku.ca", "section_id": "` + availableSection + `"}`, http.StatusConflict},
+		{"db down", &mockSeatWatchRepository{err: errors.New("db down")}, `{"email": "student@my.yorku.ca", "section_id": "` + availableSection + `"}`, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveSeatWatches(newSeatWatchRouter(tt.repo), http.MethodPost, "/watches", tt.body)
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestListAndDeleteWatches(t *testing.T) {
+	repo := &mockSeatWatchRepository{watches: []models.SeatWatch{
+		{ID: "watch-1", Email: "student@my.yorku.ca", SectionID: availableSection, CourseCode: "EECS2030", Letter: "A"},
+		{ID: "watch-2", Email: "other@my.yorku.ca", SectionID: availableSection},
+	}}
+	router := newSeatWatchRouter(repo)
+
+	w := serveSeatWatches(router, http.MethodGet, "/watches?email=student@my.yorku.ca", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"count":1`)
+	assert.Contains(t, w.Body.String(), `"course_code":"EECS2030"`)
+
+	assert.Equal(t, http.StatusBadRequest, serveSeatWatches(router, http.MethodGet, "/watches", "").Code)
+	assert.Equal(t, http.StatusNotFound, serveSeatWatches(router, http.MethodDelete, "/watches/watch-2?email=student@my.yorku.ca", "").Code)
+	assert.Equal(t, http.StatusOK, serveSeatWatches(router, http.MethodDelete, "/watches/watch-1?email=student@my.yorku.ca", "").Code)
+	assert.Len(t, repo.watches, 1)
+}
@@ -20,11 +20,11 @@ func (h *SectionActivityHandler) GetActivitiesBySectionID(c *gin.Context) {
 
 	activities, err := h.repo.GetBySectionID(c.Request.Context(), sectionID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch section activities"})
+		serverError(c, err, "Failed to fetch section activities")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	respond(c, http.StatusOK, gin.H{
 		"data":  activities,
 		"count": len(activities),
 	})
@@ -41,11 +41,11 @@ func (h *SectionActivityHandler) GetActivitiesBySectionIDAndType(c *gin.Context)
 
 	activities, err := h.repo.GetBySectionIDAndType(c.Request.Context(), sectionID, courseType)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch section activities"})
+		serverError(c, err, "Failed to fetch section activities")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	respond(c, http.StatusOK, gin.H{
 		"data":  activities,
 		"count": len(activities),
 	})
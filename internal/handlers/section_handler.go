@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"net/http"
+	"yuplan/internal/models"
 	"yuplan/internal/repository"
 
 	"github.com/gin-gonic/gin"
@@ -15,17 +16,44 @@ func NewSectionHandler(repo repository.SectionRepositoryInterface) *SectionHandl
 	return &SectionHandler{repo: repo}
 }
 
+// GetSectionsByCourseID handles GET /api/v1/sections/:course_id, optionally
+// keeping only the sections in ?term=, e.g. FW2025.
 func (h *SectionHandler) GetSectionsByCourseID(c *gin.Context) {
 	courseID := c.Param("course_id")
+	termID, ok := termFilter(c)
+	if !ok {
+		return
+	}
 
-	sections, err := h.repo.GetByCourseID(c.Request.Context(), courseID)
+	var sections []models.Section
+	var err error
+	if termID != "" {
+		sections, err = h.repo.GetByCourseIDInTerm(c.Request.Context(), courseID, termID)
+	} else {
+		sections, err = h.repo.GetByCourseID(c.Request.Context(), courseID)
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch sections"})
+		serverError(c, err, "Failed to fetch sections")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data":  sections,
-		"count": len(sections),
+	// Asynchronous activities have no slot in a weekly timetable, so list them
+	// separately; they stay in their sections so a section can still be picked.
+	asynchronous := []models.SectionActivity{}
+	activityCount := 0
+	for _, section := range sections {
+		for _, activity := range section.Activities {
+			activityCount++
+			if activity.Delivery == models.DeliveryAsynchronous {
+				asynchronous = append(asynchronous, activity)
+			}
+		}
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"data":               sections,
+		"count":              len(sections),
+		"asynchronous":       asynchronous,
+		"fully_asynchronous": activityCount > 0 && len(asynchronous) == activityCount,
 	})
 }
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -16,7 +17,8 @@ import (
 )
 
 type MockSectionRepository struct {
-	getByCourseID func(ctx context.Context, courseID string) ([]models.Section, error)
+	getByCourseID       func(ctx context.Context, courseID string) ([]models.Section, error)
+	getByCourseIDInTerm func(ctx context.Context, courseID, termID string) ([]models.Section, error)
 }
 
 func (m *MockSectionRepository) GetByCourseID(ctx context.Context, courseID string) ([]models.Section, error) {
@@ -26,6 +28,13 @@ func (m *MockSectionRepository) GetByCourseID(ctx context.Context, courseID stri
 	return []models.Section{}, nil
 }
 
+func (m *MockSectionRepository) GetByCourseIDInTerm(ctx context.Context, courseID, termID string) ([]models.Section, error) {
+	if m.getByCourseIDInTerm != nil {
+		return m.getByCourseIDInTerm(ctx, courseID, termID)
+	}
+	return []models.Section{}, nil
+}
+
 func TestGetSectionsByCourseID(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -110,3 +119,94 @@ func TestGetSectionsByCourseID_WhenRepoErrors_Returns500(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 	assert.Contains(t, strings.ToLower(w.Body.String()), "failed")
 }
+
+func TestGetSectionsByCourseID_AsynchronousActivities(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name              string
+		activities        []models.SectionActivity
+		expectedAsync     []string
+		fullyAsynchronous bool
+	}{
+		{
+			name: "mixed",
+			activities: []models.SectionActivity{
+				{ID: "lect", CourseType: models.ActivityLecture, Delivery: models.DeliveryScheduled},
+				{ID: "onln", CourseType: models.ActivityOnline, Delivery: models.DeliveryAsynchronous},
+			},
+			expectedAsync:     []string{"onln"},
+			fullyAsynchronous: false,
+		},
+		{
+			name: "fully asynchronous",
+			activities: []models.SectionActivity{
+				{ID: "onln-1", CourseType: models.ActivityOnline, Delivery: models.DeliveryAsynchronous},
+				{ID: "onln-2", CourseType: models.ActivityOnline, Delivery: models.DeliveryAsynchronous},
+			},
+			expectedAsync:     []string{"onln-1", "onln-2"},
+			fullyAsynchronous: true,
+		},
+		{
+			name:              "no activities",
+			activities:        nil,
+			expectedAsync:     []string{},
+			fullyAsynchronous: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &MockSectionRepository{
+				getByCourseID: func(ctx context.Context, courseID string) ([]models.Section, error) {
+					return []models.Section{{ID: "section-1", CourseID: courseID, Letter: "A", Activities: tt.activities}}, nil
+				},
+			}
+			handler := NewSectionHandler(repo)
+
+			r := gin.New()
+			r.GET("/sections/:course_id", handler.GetSectionsByCourseID)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest("GET", "/sections/course-1", nil))
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var response struct {
+				Asynchronous      []models.SectionActivity `json:"asynchronous"`
+				FullyAsynchronous bool                     `json:"fully_asynchronous"`
+			}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+			ids := []string{}
+			for _, a := range response.Asynchronous {
+				ids = append(ids, a.ID)
+			}
+			assert.Equal(t, tt.expectedAsync, ids)
+			assert.Equal(t, tt.fullyAsynchronous, response.FullyAsynchronous)
+		})
+	}
+}
+
+func TestGetSectionsByCourseID_InTerm(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var gotTerm string
+	repo := &MockSectionRepository{
+		getByCourseIDInTerm: func(ctx context.Context, courseID, termID string) ([]models.Section, error) {
+			gotTerm = termID
+			return []models.Section{{ID: "section-1", CourseID: courseID, Letter: "A"}}, nil
+		},
+	}
+	r := gin.New()
+	r.GET("/sections/:course_id", NewSectionHandler(repo).GetSectionsByCourseID)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/sections/course-1?term=fw2025", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "FW2025", gotTerm)
+	assert.Contains(t, w.Body.String(), `"count":1`)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/sections/course-1?term=F", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+	"yuplan/internal/models"
+	"yuplan/internal/repository"
+	"yuplan/internal/session"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sessionStarter starts anonymous sessions. Implemented by session.Signer.
+type sessionStarter interface {
+	Start() (session.Session, string, error)
+}
+
+// draftKeyPattern is what clients may name drafts, e.g. review:EECS2030.
+var draftKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_.:-]{1,100}$`)
+
+// SessionHandler serves what an anonymous session keeps. The caller's session
+// comes from middleware.AnonymousSession; writes start one when there is none.
+type SessionHandler struct {
+	repo         repository.SessionRepositoryInterface
+	sessions     sessionStarter
+	secureCookie bool
+}
+
+func NewSessionHandler(repo repository.SessionRepositoryInterface, sessions sessionStarter, secureCookie bool) *SessionHandler {
+	return &SessionHandler{repo: repo, sessions: sessions, secureCookie: secureCookie}
+}
+
+// ensure returns the caller's session, starting one and setting its cookie
+// when they have none. It responds and returns false if that fails.
+func (h *SessionHandler) ensure(c *gin.Context) (session.Session, bool) {
+	if sess, ok := session.FromContext(c.Request.Context()); ok {
+		return sess, true
+	}
+	sess, token, err := h.sessions.Start()
+	if err != nil {
+		serverError(c, err, "Failed to start session")
+		return session.Session{}, false
+	}
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(session.Cookie, token, int(time.Until(sess.ExpiresAt).Seconds()), "/", "", h.secureCookie, true)
+	c.Header(session.Header, token)
+	c.Request = c.Request.WithContext(session.WithSession(c.Request.Context(), sess))
+	return sess, true
+}
+
+// StartSession handles POST /api/v1/session, starting an anonymous session
+// unless the caller already has one. The token is set as a cookie and also
+// returned in the X-Session-Token header for clients without cookies.
+func (h *SessionHandler) StartSession(c *gin.Context) {
+	_, existing := session.FromContext(c.Request.Context())
+	sess, ok := h.ensure(c)
+	if !ok {
+		return
+	}
+	status := http.StatusCreated
+	if existing {
+		status = http.StatusOK
+	}
+	c.JSON(status, gin.H{"data": sess})
+}
+
+// EndSession handles DELETE /api/v1/session, deleting everything the caller's
+// session stored and clearing its cookie.
+func (h *SessionHandler) EndSession(c *gin.Context) {
+	if sess, ok := session.FromContext(c.Request.Context()); ok {
+		if err := h.repo.Delete(c.Request.Context(), sess.ID); err != nil {
+			serverError(c, err, "Failed to clear session")
+			return
+		}
+	}
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(session.Cookie, "", -1, "/", "", h.secureCookie, true)
+	c.JSON(http.StatusOK, gin.H{"message": "Session cleared"})
+}
+
+// GetRecentViews handles GET /api/v1/session/recent, the courses the caller
+// opened most recently, latest first. Without a session the list is empty.
+func (h *SessionHandler) GetRecentViews(c *gin.Context) {
+	views := []models.RecentView{}
+	if sess, ok := session.FromContext(c.Request.Context()); ok {
+		var err error
+		if views, err = h.repo.ListRecentViews(c.Request.Context(), sess.ID); err != nil {
+			serverError(c, err, "Failed to fetch recently viewed courses")
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"data": views, "count": len(views)})
+}
+
+// RecordRecentView handles POST /api/v1/session/recent
+// Body: {"course_code": "EECS2030"}
+func (h *SessionHandler) RecordRecentView(c *gin.Context) {
+	var req models.RecordRecentViewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	code := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(req.CourseCode), " ", ""))
+	if code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "course_code is required"})
+		return
+	}
+
+	sess, ok := h.ensure(c)
+	if !ok {
+		return
+	}
+	if err := h.repo.RecordView(c.Request.Context(), sess, code); err != nil {
+		serverError(c, err, "Failed to record recently viewed course")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Course recorded"})
+}
+
+// ListDrafts handles GET /api/v1/session/drafts, the keys of the caller's
+// drafts without their bodies. Without a session the list is empty.
+func (h *SessionHandler) ListDrafts(c *gin.Context) {
+	drafts := []models.SessionDraft{}
+	if sess, ok := session.FromContext(c.Request.Context()); ok {
+		var err error
+		if drafts, err = h.repo.ListDrafts(c.Request.Context(), sess.ID); err != nil {
+			serverError(c, err, "Failed to fetch drafts")
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"data": drafts, "count": len(drafts)})
+}
+
+// GetDraft handles GET /api/v1/session/drafts/:key
+func (h *SessionHandler) GetDraft(c *gin.Context) {
+	sess, ok := session.FromContext(c.Request.Context())
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No draft with that key"})
+		return
+	}
+	draft, err := h.repo.GetDraft(c.Request.Context(), sess.ID, c.Param("key"))
+	if err != nil {
+		serverError(c, err, "Failed to fetch draft")
+		return
+	}
+	if draft == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No draft with that key"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": draft})
+}
+
+// SaveDraft handles PUT /api/v1/session/drafts/:key. The body is any JSON
+// up to MaxDraftBytes and replaces what was saved under key.
+func (h *SessionHandler) SaveDraft(c *gin.Context) {
+	key := c.Param("key")
+	if !draftKeyPattern.MatchString(key) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Draft keys are 1 to 100 letters, digits and _ . : -"})
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, models.MaxDraftBytes+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Could not read the draft"})
+		return
+	}
+	if len(body) > models.MaxDraftBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Drafts can be at most 16 KB"})
+		return
+	}
+	if !json.Valid(body) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The draft must be JSON"})
+		return
+	}
+
+	sess, ok := h.ensure(c)
+	if !ok {
+		return
+	}
+	draft, err := h.repo.SaveDraft(c.Request.Context(), sess, key, body)
+	if errors.Is(err, repository.ErrTooManyDrafts) {
+		c.JSON(http.StatusConflict, gin.H{"error": "This session already has the most drafts it can keep; delete one first"})
+		return
+	}
+	if err != nil {
+		serverError(c, err, "Failed to save draft")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": draft})
+}
+
+// DeleteDraft handles DELETE /api/v1/session/drafts/:key
+func (h *SessionHandler) DeleteDraft(c *gin.Context) {
+	sess, ok := session.FromContext(c.Request.Context())
+	deleted := false
+	if ok {
+		var err error
+		if deleted, err = h.repo.DeleteDraft(c.Request.Context(), sess.ID, c.Param("key")); err != nil {
+			serverError(c, err, "Failed to delete draft")
+			return
+		}
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No draft with that key"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Draft deleted"})
+}
@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"yuplan/internal/middleware"
+	"yuplan/internal/models"
+	"yuplan/internal/repository"
+	"yuplan/internal/session"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// memorySessions keeps session data in memory, by session id.
+type memorySessions struct {
+	views  map[string][]models.RecentView
+	drafts map[string]map[string]json.RawMessage
+}
+
+func newMemorySessions() *memorySessions {
+	return &memorySessions{views: map[string][]models.RecentView{}, drafts: map[string]map[string]json.RawMessage{}}
+}
+
+func (m *memorySessions) RecordView(ctx context.Context, sess session.Session, courseCode string) error {
+	m.views[sess.ID] = append([]models.RecentView{{CourseCode: courseCode}}, m.views[sess.ID]...)
+	return nil
+}
+
+func (m *memorySessions) ListRecentViews(ctx context.Context, sessionID string) ([]models.RecentView, error) {
+	return m.views[sessionID], nil
+}
+
+func (m *memorySessions) ListDrafts(ctx context.Context, sessionID string) ([]models.SessionDraft, error) {
+	var drafts []models.SessionDraft
+	for key := range m.drafts[sessionID] {
+		drafts = append(drafts, models.SessionDraft{Key: key})
+	}
+	return drafts, nil
+}
+
+func (m *memorySessions) GetDraft(ctx context.Context, sessionID, key string) (*models.SessionDraft, error) {
+	body, ok := m.drafts[sessionID][key]
+	if !ok {
+		return nil, nil
+	}
+	return &models.SessionDraft{Key: key, Body: body}, nil
+}
+
+func (m *memorySessions) SaveDraft(ctx context.Context, sess session.Session, key string, body json.RawMessage) (*models.SessionDraft, error) {
+	if m.drafts[sess.ID] == nil {
+		m.drafts[sess.ID] = map[string]json.RawMessage{}
+	}
+	if _, ok := m.drafts[sess.ID][key]; !ok && len(m.drafts[sess.ID]) >= models.MaxSessionDrafts {
+		return nil, repository.ErrTooManyDrafts
+	}
+	m.drafts[sess.ID][key] = body
+	return &models.SessionDraft{Key: key, Body: body}, nil
+}
+
+func (m *memorySessions) DeleteDraft(ctx context.Context, sessionID, key string) (bool, error) {
+	_, ok := m.drafts[sessionID][key]
+	delete(m.drafts[sessionID], key)
+	return ok, nil
+}
+
+func (m *memorySessions) Delete(ctx context.Context, sessionID string) error {
+	delete(m.views, sessionID)
+	delete(m.drafts, sessionID)
+	return nil
+}
+
+func newSessionRouter(repo *memorySessions) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	signer := session.NewSigner("secret", time.Hour)
+	handler := NewSessionHandler(repo, signer, true)
+	router := gin.New()
+	router.Use(middleware.AnonymousSession(signer))
+	router.POST("/session", handler.StartSession)
+	router.DELETE("/session", handler.EndSession)
+	router.GET("/session/recent", handler.GetRecentViews)
+	router.POST("/session/recent", handler.RecordRecentView)
+	router.GET("/session/drafts", handler.ListDrafts)
+	router.GET("/session/drafts/:key", handler.GetDraft)
+	router.PUT("/session/drafts/:key", handler.SaveDraft)
+	router.DELETE("/session/drafts/:key", handler.DeleteDraft)
+	return router
+}
+
+func serveSession(router *gin.Engine, method, path, body, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		req.AddCookie(&http.Cookie{Name: session.Cookie, Value: token})
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestStartSession(t *testing.T) {
+	router := newSessionRouter(newMemorySessions())
+
+	w := serveSession(router, http.MethodPost, "/session", "", "")
+	assert.Equal(t, http.StatusCreated, w.Code)
+	token := w.Header().Get(session.Header)
+	assert.NotEmpty(t, token)
+	cookie := w.Result().Cookies()[0]
+	assert.Equal(t, token, cookie.Value)
+	assert.True(t, cookie.HttpOnly)
+	assert.True(t, cookie.Secure)
+	assert.NotContains(t, w.Body.String(), `"id"`, "the session id stays in the token")
+
+	w = serveSession(router, http.MethodPost, "/session", "", token)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Set-Cookie"))
+}
+
+func TestRecentViews(t *testing.T) {
+	router := newSessionRouter(newMemorySessions())
+
+	w := serveSession(router, http.MethodGet, "/session/recent", "", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"count":0`)
+
+	// The first write starts a session
+	w = serveSession(router, http.MethodPost, "/session/recent", `{"course_code": "eecs 2030"}`, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	token := w.Header().Get(session.Header)
+	assert.NotEmpty(t, token)
+
+	serveSession(router, http.MethodPost, "/session/recent", `{"course_code": "MATH1090"}`, token)
+	w = serveSession(router, http.MethodGet, "/session/recent", "", token)
+	var body struct {
+		Data []models.RecentView `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, []string{"MATH1090", "EECS2030"}, []string{body.Data[0].CourseCode, body.Data[1].CourseCode})
+
+	w = serveSession(router, http.MethodPost, "/session/recent", `{}`, token)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestDrafts(t *testing.T) {
+	repo := newMemorySessions()
+	router := newSessionRouter(repo)
+
+	w := serveSession(router, http.MethodPut, "/session/drafts/review:EECS2030", `{"review_text": "So far"}`, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	token := w.Header().Get(session.Header)
+
+	w = serveSession(router, http.MethodGet, "/session/drafts/review:EECS2030", "", token)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"body":{"review_text":"So far"}`)
+
+	assert.Equal(t, http.StatusNotFound, serveSession(router, http.MethodGet, "/session/drafts/review:EECS2030", "", "").Code)
+	assert.Equal(t, http.StatusBadRequest, serveSession(router, http.MethodPut, "/session/drafts/bad%20key", `{}`, token).Code)
+	assert.Equal(t, http.StatusBadRequest, serveSession(router, http.MethodPut, "/session/drafts/k", `not json`, token).Code)
+	assert.Equal(t, http.StatusRequestEntityTooLarge,
+		serveSession(router, http.MethodPut, "/session/drafts/k", `"`+strings.Repeat("x", models.MaxDraftBytes)+`"`, token).Code)
+
+	w = serveSession(router, http.MethodGet, "/session/drafts", "", token)
+	assert.Contains(t, w.Body.String(), `"count":1`)
+
+	assert.Equal(t, http.StatusOK, serveSession(router, http.MethodDelete, "/session/drafts/review:EECS2030", "", token).Code)
+	assert.Equal(t, http.StatusNotFound, serveSession(router, http.MethodDelete, "/session/drafts/review:EECS2030", "", token).Code)
+}
+
+func TestDrafts_TooMany(t *testing.T) {
+	repo := newMemorySessions()
+	router := newSessionRouter(repo)
+
+	token := serveSession(router, http.MethodPost, "/session", "", "").Header().Get(session.Header)
+	for i := range models.MaxSessionDrafts {
+		assert.Equal(t, http.StatusOK, serveSession(router, http.MethodPut, "/session/drafts/d"+string(rune('a'+i)), `{}`, token).Code)
+	}
+	assert.Equal(t, http.StatusConflict, serveSession(router, http.MethodPut, "/session/drafts/one-more", `{}`, token).Code)
+	assert.Equal(t, http.StatusOK, serveSession(router, http.MethodPut, "/session/drafts/da", `{"again": true}`, token).Code)
+}
+
+func TestEndSession(t *testing.T) {
+	repo := newMemorySessions()
+	router := newSessionRouter(repo)
+
+	token := serveSession(router, http.MethodPost, "/session/recent", `{"course_code": "EECS2030"}`, "").Header().Get(session.Header)
+	serveSession(router, http.MethodPut, "/session/drafts/k", `{}`, token)
+
+	w := serveSession(router, http.MethodDelete, "/session", "", token)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, repo.views)
+	assert.Empty(t, repo.drafts)
+	assert.Equal(t, -1, w.Result().Cookies()[0].MaxAge)
+}
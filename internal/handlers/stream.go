@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"yuplan/internal/logging"
+	"yuplan/internal/redact"
+
+	"github.com/gin-gonic/gin"
+)
+
+// streamBatchSize is how many rows are written between flushes, and how many
+// reviews share one badge lookup, when a listing is streamed.
+const streamBatchSize = 500
+
+// jsonArrayWriter writes a {"data": [...], "count": n} response one item at a
+// time, so full listings are never held in memory. Nothing is sent until the
+// first item, so an error before then still gets a normal error response.
+type jsonArrayWriter struct {
+	c     *gin.Context
+	role  redact.Role
+	count int
+}
+
+func newJSONArrayWriter(c *gin.Context) *jsonArrayWriter {
+	return &jsonArrayWriter{c: c, role: redact.RoleFrom(c.Request.Context())}
+}
+
+// Write appends one item to the data array, redacted for the caller's role.
+func (w *jsonArrayWriter) Write(item any) error {
+	redact.Apply(&item, w.role)
+	b, err := json.Marshal(item)
+	if err != nil {
+		return fmt.Errorf("encode item: %w", err)
+	}
+
+	prefix := ","
+	if w.count == 0 {
+		w.c.Header("Content-Type", "application/json; charset=utf-8")
+		w.c.Status(http.StatusOK)
+		prefix = `{"data":[`
+	}
+	if _, err := w.c.Writer.WriteString(prefix); err != nil {
+		return err
+	}
+	if _, err := w.c.Writer.Write(b); err != nil {
+		return err
+	}
+
+	w.count++
+	if w.count%streamBatchSize == 0 {
+		w.c.Writer.Flush()
+	}
+	return nil
+}
+
+// Close ends the response.
+func (w *jsonArrayWriter) Close() {
+	if w.count == 0 {
+		w.c.JSON(http.StatusOK, gin.H{"data": []any{}, "count": 0})
+		return
+	}
+	fmt.Fprintf(w.c.Writer, `],"count":%d}`, w.count)
+	w.c.Writer.Flush()
+}
+
+// Fail reports err. Once items have been sent the status can't change, so the
+// response is left unterminated; clients see invalid JSON rather than a
+// listing that looks complete.
+func (w *jsonArrayWriter) Fail(err error, message string) {
+	if w.count == 0 {
+		serverError(w.c, err, message)
+		return
+	}
+	logging.FromContext(w.c.Request.Context()).Error(message, "error", err, "items_sent", w.count)
+	w.c.Abort()
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"yuplan/internal/models"
+	"yuplan/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SubscriptionHandler struct {
+	repo repository.DigestRepositoryInterface
+}
+
+func NewSubscriptionHandler(repo repository.DigestRepositoryInterface) *SubscriptionHandler {
+	return &SubscriptionHandler{repo: repo}
+}
+
+// GetSubscriptions handles GET /api/v1/subscriptions?email=, the departments
+// an email follows and how often it gets digests.
+func (h *SubscriptionHandler) GetSubscriptions(c *gin.Context) {
+	var query struct {
+		Email string `form:"email" binding:"required,email"`
+	}
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter 'email' must be a valid email"})
+		return
+	}
+
+	subscriber, err := h.repo.GetSubscriber(c.Request.Context(), strings.TrimSpace(query.Email))
+	if err != nil {
+		serverError(c, err, "Failed to fetch subscriptions")
+		return
+	}
+	if subscriber == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No subscriptions for that email"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": subscriber})
+}
+
+// Subscribe handles POST /api/v1/subscriptions
+// Body: {"email": "student@my.yorku.ca", "department": "EECS", "frequency": "weekly"}
+func (h *SubscriptionHandler) Subscribe(c *gin.Context) {
+	var req models.SubscribeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	department, ok := models.NormalizeDepartment(req.Department)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "department must be a department code such as EECS"})
+		return
+	}
+
+	exists, err := h.repo.DepartmentExists(c.Request.Context(), department)
+	if err != nil {
+		serverError(c, err, "Failed to check department")
+		return
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No courses in department " + department})
+		return
+	}
+
+	email := strings.TrimSpace(req.Email)
+	if err := h.repo.Subscribe(c.Request.Context(), email, department, req.Frequency); err != nil {
+		serverError(c, err, "Failed to subscribe")
+		return
+	}
+	subscriber, err := h.repo.GetSubscriber(c.Request.Context(), email)
+	if err != nil {
+		serverError(c, err, "Failed to fetch subscriptions")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":    subscriber,
+		"message": "Subscribed to " + department,
+	})
+}
+
+// Unsubscribe handles DELETE /api/v1/subscriptions/:department?email=
+func (h *SubscriptionHandler) Unsubscribe(c *gin.Context) {
+	var query struct {
+		Email string `form:"email" binding:"required,email"`
+	}
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter 'email' must be a valid email"})
+		return
+	}
+	department, ok := models.NormalizeDepartment(c.Param("department"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "department must be a department code such as EECS"})
+		return
+	}
+
+	removed, err := h.repo.Unsubscribe(c.Request.Context(), strings.TrimSpace(query.Email), department)
+	if err != nil {
+		serverError(c, err, "Failed to unsubscribe")
+		return
+	}
+	if !removed {
+		c.JSON(http.StatusNotFound, gin.H{"error": "That email doesn't follow " + department})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Unsubscribed from " + department})
+}
+
+// SetFrequency handles PUT /api/v1/subscriptions/frequency
+// Body: {"email": "student@my.yorku.ca", "frequency": "immediate"}
+func (h *SubscriptionHandler) SetFrequency(c *gin.Context) {
+	var req models.SetDigestFrequencyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := h.repo.SetFrequency(c.Request.Context(), strings.TrimSpace(req.Email), req.Frequency)
+	if err != nil {
+		serverError(c, err, "Failed to update digest frequency")
+		return
+	}
+	if !updated {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No subscriptions for that email"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Digest frequency set to " + req.Frequency})
+}
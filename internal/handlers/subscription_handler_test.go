@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockDigestRepository struct {
+	departments map[string]bool
+	subscriber  *models.DigestSubscriber
+	err         error
+
+	subscribed []string // email, department, frequency
+	removed    bool
+	frequency  string
+}
+
+func (m *mockDigestRepository) DepartmentExists(ctx context.Context, department string) (bool, error) {
+	return m.departments[department], m.err
+}
+
+func (m *mockDigestRepository) Subscribe(ctx context.Context, email, department, frequency string) error {
+	m.subscribed = []string{email, department, frequency}
+	return m.err
+}
+
+func (m *mockDigestRepository) Unsubscribe(ctx context.Context, email, department string) (bool, error) {
+	return m.removed, m.err
+}
+
+func (m *mockDigestRepository) GetSubscriber(ctx context.Context, email string) (*models.DigestSubscriber, error) {
+	return m.subscriber, m.err
+}
+
+func (m *mockDigestRepository) SetFrequency(ctx context.Context, email, frequency string) (bool, error) {
+	m.frequency = frequency
+	return m.subscriber != nil, m.err
+}
+
+func (m *mockDigestRepository) DetectCatalogChanges(ctx context.Context) (bool, int, bool, error) {
+	return false, 0, false, m.err
+}
+
+func (m *mockDigestRepository) ListDueDigests(ctx context.Context, now time.Time) ([]models.Digest, error) {
+	return nil, m.err
+}
+
+func (m *mockDigestRepository) MarkDigestSent(ctx context.Context, email string, through time.Time) error {
+	return m.err
+}
+
+func newSubscriptionRouter(repo *mockDigestRepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewSubscriptionHandler(repo)
+	router := gin.New()
+	router.GET("/subscriptions", handler.GetSubscriptions)
+	router.POST("/subscriptions", handler.Subscribe)
+	router.PUT("/subscriptions/frequency", handler.SetFrequency)
+	router.DELETE("/subscriptions/:department", handler.Unsubscribe)
+	return router
+}
+
+func serveSubscriptions(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+	return w
+}
+
+func TestSubscribe(t *testing.T) {
+	subscriber := &models.DigestSubscriber{Email: "a@yorku.ca", Frequency: models.DigestDaily, Departments: []string{"EECS"}}
+
+	tests := []struct {
+		name       string
+		body       string
+		repo       *mockDigestRepository
+		code       int
+		subscribed []string
+	}{
+		{"new subscription", `{"email": "a@yorku.ca", "department": "eecs"}`,
+			&mockDigestRepository{departments: map[string]bool{"EECS": true}, subscriber: subscriber}, http.StatusOK, []string{"a@yorku.ca", "EECS", ""}},
+		{"with frequency", `{"email": "a@yorku.ca", "department": "EECS", "frequency": "weekly"}`,
+			&mockDigestRepository{departments: map[string]bool{"EECS": true}, subscriber: subscriber}, http.StatusOK, []string{"a@yorku.ca", "EECS", "weekly"}},
+		{"unknown department", `{"email": "a@yorku.ca", "department": "NOPE"}`, &mockDigestRepository{}, http.StatusNotFound, nil},
+		{"not a department code", `{"email": "a@yorku.ca", "department": "EECS2030"}`, &mockDigestRepository{}, http.StatusBadRequest, nil},
+		{"unknown frequency", `{"email": "a@yorku.ca", "department": "EECS", "frequency": "hourly"}`, &mockDigestRepository{}, http.StatusBadRequest, nil},
+		{"invalid email", `{"email": "nope", "department": "EECS"}`, &mockDigestRepository{}, http.StatusBadRequest, nil},
+		{"repo error", `{"email": "a@yorku.ca", "department": "EECS"}`, &mockDigestRepository{err: errors.New("db down")}, http.StatusInternalServerError, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveSubscriptions(newSubscriptionRouter(tt.repo), http.MethodPost, "/subscriptions", tt.body)
+
+			assert.Equal(t, tt.code, w.Code, w.Body.String())
+			assert.Equal(t, tt.subscribed, tt.repo.subscribed)
+		})
+	}
+}
+
+func TestGetSubscriptions(t *testing.T) {
+	repo := &mockDigestRepository{subscriber: &models.DigestSubscriber{Email: "a@yorku.ca", Frequency: models.DigestWeekly, Departments: []string{"EECS", "MATH"}}}
+
+	w := serveSubscriptions(newSubscriptionRouter(repo), http.MethodGet, "/subscriptions?email=a@yorku.ca", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"departments":["EECS","MATH"]`)
+
+	w = serveSubscriptions(newSubscriptionRouter(&mockDigestRepository{}), http.MethodGet, "/subscriptions?email=b@yorku.ca", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = serveSubscriptions(newSubscriptionRouter(repo), http.MethodGet, "/subscriptions", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestUnsubscribe(t *testing.T) {
+	w := serveSubscriptions(newSubscriptionRouter(&mockDigestRepository{removed: true}), http.MethodDelete, "/subscriptions/eecs?email=a@yorku.ca", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "EECS")
+
+	w = serveSubscriptions(newSubscriptionRouter(&mockDigestRepository{}), http.MethodDelete, "/subscriptions/EECS?email=a@yorku.ca", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = serveSubscriptions(newSubscriptionRouter(&mockDigestRepository{removed: true}), http.MethodDelete, "/subscriptions/EECS", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestSetDigestFrequency(t *testing.T) {
+	repo := &mockDigestRepository{subscriber: &models.DigestSubscriber{Email: "a@yorku.ca"}}
+
+	w := serveSubscriptions(newSubscriptionRouter(repo), http.MethodPut, "/subscriptions/frequency", `{"email": "a@yorku.ca", "frequency": "immediate"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, models.DigestImmediate, repo.frequency)
+
+	w = serveSubscriptions(newSubscriptionRouter(&mockDigestRepository{}), http.MethodPut, "/subscriptions/frequency", `{"email": "b@yorku.ca", "frequency": "daily"}`)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = serveSubscriptions(newSubscriptionRouter(repo), http.MethodPut, "/subscriptions/frequency", `{"email": "a@yorku.ca"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
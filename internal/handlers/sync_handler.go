@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"yuplan/internal/config"
+	"yuplan/internal/models"
+	"yuplan/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// syncTrigger starts a catalog sync off schedule. Implemented by catalogsync.Syncer.
+type syncTrigger interface {
+	Trigger(ctx context.Context) bool
+}
+
+type SyncHandler struct {
+	repo   repository.SyncRepositoryInterface
+	syncer syncTrigger // nil when SCRAPER_TERM is unset
+}
+
+func NewSyncHandler(repo repository.SyncRepositoryInterface) *SyncHandler {
+	return &SyncHandler{repo: repo}
+}
+
+// WithSyncer enables triggering syncs.
+func (h *SyncHandler) WithSyncer(syncer syncTrigger) *SyncHandler {
+	h.syncer = syncer
+	return h
+}
+
+// ListSyncs handles GET /api/v1/admin/syncs?limit=20
+// It lists catalog syncs newest first, with the most recent as "latest" (null
+// before the first run).
+func (h *SyncHandler) ListSyncs(c *gin.Context) {
+	limit, ok := pageLimit(c, config.PagesSyncs, "limit")
+	if !ok {
+		return
+	}
+
+	runs, err := h.repo.List(c.Request.Context(), limit)
+	if err != nil {
+		serverError(c, err, "Failed to fetch sync history")
+		return
+	}
+
+	var latest *models.SyncRun
+	if len(runs) > 0 {
+		latest = &runs[0]
+	}
+	respond(c, http.StatusOK, gin.H{
+		"latest": latest,
+		"data":   runs,
+		"count":  len(runs),
+	})
+}
+
+// TriggerSync handles POST /api/v1/admin/syncs, starting a catalog sync now
+// rather than at the next scheduled time. It answers 202 once the run has
+// started; follow it with ListSyncs. It is 409 while a sync is running, here
+// or on another instance.
+func (h *SyncHandler) TriggerSync(c *gin.Context) {
+	if h.syncer == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Catalog sync is disabled"})
+		return
+	}
+
+	runs, err := h.repo.List(c.Request.Context(), 1)
+	if err != nil {
+		serverError(c, err, "Failed to fetch sync history")
+		return
+	}
+	if (len(runs) > 0 && runs[0].Status == models.SyncRunning) || !h.syncer.Trigger(c.Request.Context()) {
+		c.JSON(http.StatusConflict, gin.H{"error": "A catalog sync is already running", "code": models.ErrCodeConflict})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Catalog sync started"})
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockSyncRepository struct {
+	runs  []models.SyncRun
+	limit int
+	err   error
+}
+
+func (m *mockSyncRepository) Start(ctx context.Context, term string) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockSyncRepository) Finish(ctx context.Context, run models.SyncRun) error {
+	return nil
+}
+
+func (m *mockSyncRepository) List(ctx context.Context, limit int) ([]models.SyncRun, error) {
+	m.limit = limit
+	return m.runs, m.err
+}
+
+func getSyncs(repo *mockSyncRepository, query string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin/syncs", NewSyncHandler(repo).ListSyncs)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/syncs"+query, nil))
+	return w
+}
+
+func TestListSyncs(t *testing.T) {
+	now := time.Now().UTC()
+	repo := &mockSyncRepository{runs: []models.SyncRun{
+		{ID: 2, Term: "FW2025", Status: models.SyncRunning, StartedAt: now},
+		{ID: 1, Term: "FW2025", Status: models.SyncSucceeded, StartedAt: now.Add(-24 * time.Hour), Pages: 10,
+			Diff: models.CatalogDiff{Sections: models.RowDiff{Added: 3}}},
+	}}
+
+	w := getSyncs(repo, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 20, repo.limit)
+
+	var body struct {
+		Latest *models.SyncRun  `json:"latest"`
+		Data   []models.SyncRun `json:"data"`
+		Count  int              `json:"count"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, 2, body.Count)
+	assert.Equal(t, int64(2), body.Latest.ID)
+	assert.Equal(t, models.SyncRunning, body.Latest.Status)
+	assert.Equal(t, 3, body.Data[1].Diff.Sections.Added)
+
+	w = getSyncs(repo, "?limit=5")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 5, repo.limit)
+}
+
+func TestListSyncs_NoRuns(t *testing.T) {
+	w := getSyncs(&mockSyncRepository{runs: []models.SyncRun{}}, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"latest": null, "data": [], "count": 0}`, w.Body.String())
+}
+
+func TestListSyncs_Errors(t *testing.T) {
+	w := getSyncs(&mockSyncRepository{}, "?limit=500")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = getSyncs(&mockSyncRepository{err: errors.New("db down")}, "")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+type mockSyncTrigger struct {
+	busy  bool
+	calls int
+}
+
+func (m *mockSyncTrigger) Trigger(ctx context.Context) bool {
+	m.calls++
+	return !m.busy
+}
+
+func triggerSync(h *SyncHandler) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/admin/syncs", h.TriggerSync)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/syncs", nil))
+	return w
+}
+
+func TestTriggerSync(t *testing.T) {
+	finished := models.SyncRun{ID: 1, Status: models.SyncSucceeded}
+	repo := &mockSyncRepository{runs: []models.SyncRun{finished}}
+	syncer := &mockSyncTrigger{}
+
+	w := triggerSync(NewSyncHandler(repo).WithSyncer(syncer))
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, 1, repo.limit)
+	assert.Equal(t, 1, syncer.calls)
+
+	// Running on this instance
+	syncer.busy = true
+	w = triggerSync(NewSyncHandler(repo).WithSyncer(syncer))
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	// Running on another instance: not even tried here
+	syncer = &mockSyncTrigger{}
+	repo.runs = []models.SyncRun{{ID: 2, Status: models.SyncRunning}}
+	w = triggerSync(NewSyncHandler(repo).WithSyncer(syncer))
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, 0, syncer.calls)
+}
+
+func TestTriggerSync_Errors(t *testing.T) {
+	w := triggerSync(NewSyncHandler(&mockSyncRepository{}))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = triggerSync(NewSyncHandler(&mockSyncRepository{err: errors.New("db down")}).WithSyncer(&mockSyncTrigger{}))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"yuplan/internal/dbtypes"
+	"yuplan/internal/models"
+	"yuplan/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TermHandler struct {
+	repo repository.TermRepositoryInterface
+}
+
+func NewTermHandler(repo repository.TermRepositoryInterface) *TermHandler {
+	return &TermHandler{repo: repo}
+}
+
+// ListTerms handles GET /api/v1/admin/terms
+func (h *TermHandler) ListTerms(c *gin.Context) {
+	terms, err := h.repo.List(c.Request.Context())
+	if err != nil {
+		serverError(c, err, "Failed to fetch terms")
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"data":  terms,
+		"count": len(terms),
+	})
+}
+
+// ListSessions handles GET /api/v1/terms, the sessions that ?term= filters
+// on course and section listings accept.
+func (h *TermHandler) ListSessions(c *gin.Context) {
+	terms, err := h.repo.ListSessions(c.Request.Context())
+	if err != nil {
+		serverError(c, err, "Failed to fetch terms")
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"data":  terms,
+		"count": len(terms),
+	})
+}
+
+// UpsertTerm handles PUT /api/v1/admin/terms/:academic_year/:term
+// with the term's exams_start, exams_end and grades_released, and optionally
+// its enroll_deadline and drop_deadline.
+func (h *TermHandler) UpsertTerm(c *gin.Context) {
+	year, err := strconv.Atoi(c.Param("academic_year"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Academic year must be a number"})
+		return
+	}
+	term := c.Param("term")
+	if !isTerm(term) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unknown term %q", term)})
+		return
+	}
+
+	var req models.UpsertAcademicTermRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	t := &models.AcademicTerm{
+		AcademicYear:   year,
+		Term:           term,
+		ExamsStart:     req.ExamsStart.UTC(),
+		ExamsEnd:       req.ExamsEnd.UTC(),
+		GradesReleased: req.GradesReleased.UTC(),
+	}
+	if req.EnrollDeadline != nil {
+		t.EnrollDeadline = dbtypes.NewNullTime(req.EnrollDeadline.UTC())
+	}
+	if req.DropDeadline != nil {
+		t.DropDeadline = dbtypes.NewNullTime(req.DropDeadline.UTC())
+	}
+	if err := h.repo.Upsert(c.Request.Context(), t); err != nil {
+		serverError(c, err, "Failed to save term")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":    t,
+		"message": "Term saved",
+	})
+}
+
+// termFilter reads the optional ?term= session filter, e.g. FW2025, writing
+// a 400 and returning ok false when it is malformed. "" means no filter.
+func termFilter(c *gin.Context) (termID string, ok bool) {
+	raw := c.Query("term")
+	if raw == "" {
+		return "", true
+	}
+	termID, ok = models.ParseTermID(raw)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter 'term' must be a session and year, like FW2025 or SU2026"})
+	}
+	return termID, ok
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockTermRepository struct {
+	listFunc   func(ctx context.Context) ([]models.AcademicTerm, error)
+	upsertFunc func(ctx context.Context, term *models.AcademicTerm) error
+	sessions   []models.Term
+	deadlines  []models.TermDeadlines
+}
+
+func (m *mockTermRepository) List(ctx context.Context) ([]models.AcademicTerm, error) {
+	if m.listFunc != nil {
+		return m.listFunc(ctx)
+	}
+	return []models.AcademicTerm{}, nil
+}
+
+func (m *mockTermRepository) Upsert(ctx context.Context, term *models.AcademicTerm) error {
+	if m.upsertFunc != nil {
+		return m.upsertFunc(ctx, term)
+	}
+	return nil
+}
+
+func (m *mockTermRepository) CurrentExamPeriod(ctx context.Context) (*models.AcademicTerm, error) {
+	return nil, nil
+}
+
+func (m *mockTermRepository) ListSessions(ctx context.Context) ([]models.Term, error) {
+	if m.sessions == nil {
+		return []models.Term{}, nil
+	}
+	return m.sessions, nil
+}
+
+func (m *mockTermRepository) UpcomingDeadlines(ctx context.Context, terms []string) ([]models.TermDeadlines, error) {
+	deadlines := []models.TermDeadlines{}
+	for _, d := range m.deadlines {
+		if slices.Contains(terms, d.Term) {
+			deadlines = append(deadlines, d)
+		}
+	}
+	return deadlines, nil
+}
+
+func TestListSessions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := &mockTermRepository{sessions: []models.Term{
+		{ID: "SU2026", Session: models.SessionSummer, AcademicYear: 2025},
+		{ID: "FW2025", Session: models.SessionFallWinter, AcademicYear: 2025},
+	}}
+	r := gin.New()
+	r.GET("/terms", NewTermHandler(repo).ListSessions)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/terms", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"count":2`)
+	assert.Contains(t, w.Body.String(), `"id":"SU2026","session":"SU","academic_year":2025`)
+}
+
+func TestUpsertTerm(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	valid := `{"exams_start": "2026-12-08T09:00:00-05:00", "exams_end": "2026-12-23T00:00:00-05:00", "grades_released": "2027-01-08T00:00:00-05:00"}`
+	tests := []struct {
+		name           string
+		path           string
+		body           string
+		expectedStatus int
+	}{
+		{"valid", "/admin/terms/2026/F", valid, http.StatusOK},
+		{"bad year", "/admin/terms/fall/F", valid, http.StatusBadRequest},
+		{"unknown term", "/admin/terms/2026/Q", valid, http.StatusBadRequest},
+		{"missing dates", "/admin/terms/2026/F", `{"exams_start": "2026-12-08T09:00:00Z"}`, http.StatusBadRequest},
+		{"with deadlines", "/admin/terms/2026/F",
+			`{"enroll_deadline": "2026-09-15T23:59:00-04:00", "drop_deadline": "2026-11-07T23:59:00-05:00", "exams_start": "2026-12-08T09:00:00-05:00", "exams_end": "2026-12-23T00:00:00-05:00", "grades_released": "2027-01-08T00:00:00-05:00"}`,
+			http.StatusOK},
+		{"drop deadline during exams", "/admin/terms/2026/F",
+			`{"drop_deadline": "2026-12-10T00:00:00Z", "exams_start": "2026-12-08T09:00:00Z", "exams_end": "2026-12-23T00:00:00Z", "grades_released": "2027-01-08T00:00:00Z"}`,
+			http.StatusBadRequest},
+		{"grades before exams end", "/admin/terms/2026/F",
+			`{"exams_start": "2026-12-08T00:00:00Z", "exams_end": "2026-12-23T00:00:00Z", "grades_released": "2026-12-20T00:00:00Z"}`,
+			http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved *models.AcademicTerm
+			handler := NewTermHandler(&mockTermRepository{
+				upsertFunc: func(ctx context.Context, term *models.AcademicTerm) error {
+					saved = term
+					return nil
+				},
+			})
+			router := gin.New()
+			router.PUT("/admin/terms/:academic_year/:term", handler.UpsertTerm)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, tt.path, bytes.NewBufferString(tt.body)))
+
+			assert.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			if tt.expectedStatus != http.StatusOK {
+				assert.Nil(t, saved)
+				return
+			}
+			assert.Equal(t, 2026, saved.AcademicYear)
+			assert.Equal(t, models.TermFall, saved.Term)
+			assert.Equal(t, time.Date(2026, 12, 8, 14, 0, 0, 0, time.UTC), saved.ExamsStart)
+			if tt.name == "with deadlines" {
+				assert.Equal(t, time.Date(2026, 9, 16, 3, 59, 0, 0, time.UTC), saved.EnrollDeadline.Time)
+				assert.True(t, saved.DropDeadline.Valid)
+			} else {
+				assert.False(t, saved.EnrollDeadline.Valid)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"yuplan/internal/models"
+	"yuplan/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TransferHandler struct {
+	repo repository.TransferRepositoryInterface
+}
+
+func NewTransferHandler(repo repository.TransferRepositoryInterface) *TransferHandler {
+	return &TransferHandler{repo: repo}
+}
+
+// Evaluate handles POST /api/v1/transfer/evaluate
+func (h *TransferHandler) Evaluate(c *gin.Context) {
+	var req models.TransferEvaluateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Normalize and dedupe while keeping the order the courses were given in
+	var codes []string
+	seen := make(map[string]bool, len(req.Courses))
+	for _, course := range req.Courses {
+		code := models.NormalizeCourseCode(course)
+		if code == "" || seen[code] {
+			continue
+		}
+		seen[code] = true
+		codes = append(codes, code)
+	}
+
+	found, err := h.repo.FindEquivalencies(c.Request.Context(), models.NormalizeInstitution(req.Institution), codes)
+	if err != nil {
+		serverError(c, err, "Failed to evaluate transfer credits")
+		return
+	}
+
+	byCode := make(map[string][]models.Equivalency, len(codes))
+	for _, eq := range found {
+		byCode[eq.ExternalCourseCode] = append(byCode[eq.ExternalCourseCode], eq)
+	}
+
+	evaluations := make([]models.TransferEvaluation, 0, len(codes))
+	matched := 0
+	for _, code := range codes {
+		equivalencies := byCode[code]
+		if equivalencies == nil {
+			equivalencies = []models.Equivalency{}
+		} else {
+			matched++
+		}
+		sort.SliceStable(equivalencies, func(i, j int) bool {
+			return models.ConfidenceRank(equivalencies[i].Confidence) < models.ConfidenceRank(equivalencies[j].Confidence)
+		})
+		evaluations = append(evaluations, models.TransferEvaluation{
+			ExternalCourseCode: code,
+			Equivalencies:      equivalencies,
+		})
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"data":    evaluations,
+		"count":   len(evaluations),
+		"matched": matched,
+	})
+}
+
+// ListEquivalencies handles GET /api/v1/admin/transfer/equivalencies?institution=
+func (h *TransferHandler) ListEquivalencies(c *gin.Context) {
+	institution := models.NormalizeInstitution(c.Query("institution"))
+
+	equivalencies, err := h.repo.ListEquivalencies(c.Request.Context(), institution)
+	if err != nil {
+		serverError(c, err, "Failed to fetch equivalencies")
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"data":  equivalencies,
+		"count": len(equivalencies),
+	})
+}
+
+// UpsertEquivalency handles POST /api/v1/admin/transfer/equivalencies
+func (h *TransferHandler) UpsertEquivalency(c *gin.Context) {
+	var req models.UpsertEquivalencyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	eq := &models.Equivalency{
+		Institution:        models.NormalizeInstitution(req.Institution),
+		ExternalCourseCode: models.NormalizeCourseCode(req.ExternalCourseCode),
+		YorkCourseCode:     models.NormalizeCourseCode(req.YorkCourseCode),
+		Confidence:         req.Confidence,
+		Notes:              req.Notes,
+	}
+
+	if err := h.repo.UpsertEquivalency(c.Request.Context(), eq); err != nil {
+		serverError(c, err, "Failed to save equivalency")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":    eq,
+		"message": "Equivalency saved",
+	})
+}
+
+// DeleteEquivalency handles DELETE /api/v1/admin/transfer/equivalencies/:id
+func (h *TransferHandler) DeleteEquivalency(c *gin.Context) {
+	deleted, err := h.repo.DeleteEquivalency(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		serverError(c, err, "Failed to delete equivalency")
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Equivalency not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Equivalency deleted"})
+}
@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"yuplan/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockTransferRepository struct {
+	findFunc   func(ctx context.Context, institution string, externalCodes []string) ([]models.Equivalency, error)
+	listFunc   func(ctx context.Context, institution string) ([]models.Equivalency, error)
+	upsertFunc func(ctx context.Context, eq *models.Equivalency) error
+	deleteFunc func(ctx context.Context, id string) (bool, error)
+}
+
+func (m *mockTransferRepository) FindEquivalencies(ctx context.Context, institution string, externalCodes []string) ([]models.Equivalency, error) {
+	if m.findFunc != nil {
+		return m.findFunc(ctx, institution, externalCodes)
+	}
+	return []models.Equivalency{}, nil
+}
+
+func (m *mockTransferRepository) ListEquivalencies(ctx context.Context, institution string) ([]models.Equivalency, error) {
+	if m.listFunc != nil {
+		return m.listFunc(ctx, institution)
+	}
+	return []models.Equivalency{}, nil
+}
+
+func (m *mockTransferRepository) UpsertEquivalency(ctx context.Context, eq *models.Equivalency) error {
+	if m.upsertFunc != nil {
+		return m.upsertFunc(ctx, eq)
+	}
+	return nil
+}
+
+func (m *mockTransferRepository) DeleteEquivalency(ctx context.Context, id string) (bool, error) {
+	if m.deleteFunc != nil {
+		return m.deleteFunc(ctx, id)
+	}
+	return true, nil
+}
+
+func setupTransferRouter(repo *mockTransferRepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewTransferHandler(repo)
+	router := gin.New()
+	router.POST("/transfer/evaluate", handler.Evaluate)
+	router.GET("/admin/transfer/equivalencies", handler.ListEquivalencies)
+	router.POST("/admin/transfer/equivalencies", handler.UpsertEquivalency)
+	router.DELETE("/admin/transfer/equivalencies/:id", handler.DeleteEquivalency)
+	return router
+}
+
+func TestEvaluateTransfer(t *testing.T) {
+	var gotInstitution string
+	var gotCodes []string
+	repo := &mockTransferRepository{
+		findFunc: func(ctx context.Context, institution string, externalCodes []string) ([]models.Equivalency, error) {
+			gotInstitution = institution
+			gotCodes = externalCodes
+			return []models.Equivalency{
+				{ExternalCourseCode: "IPC144", YorkCourseCode: "EECS1015", Confidence: models.EquivalencyConfidenceLow},
+				{ExternalCourseCode: "IPC144", YorkCourseCode: "EECS1012", Confidence: models.EquivalencyConfidenceHigh},
+			}, nil
+		},
+	}
+	router := setupTransferRouter(repo)
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"institution": "Seneca  Polytechnic",
+		"courses":     []string{"ipc 144", "EAC150", "IPC144"},
+	})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/transfer/evaluate", bytes.NewReader(body)))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "seneca polytechnic", gotInstitution)
+	assert.Equal(t, []string{"IPC144", "EAC150"}, gotCodes)
+
+	var response struct {
+		Data    []models.TransferEvaluation `json:"data"`
+		Count   int                         `json:"count"`
+		Matched int                         `json:"matched"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 2, response.Count)
+	assert.Equal(t, 1, response.Matched)
+	assert.Equal(t, "IPC144", response.Data[0].ExternalCourseCode)
+	assert.Equal(t, "EECS1012", response.Data[0].Equivalencies[0].YorkCourseCode, "high confidence first")
+	assert.Equal(t, "EAC150", response.Data[1].ExternalCourseCode)
+	assert.NotNil(t, response.Data[1].Equivalencies)
+	assert.Empty(t, response.Data[1].Equivalencies)
+}
+
+func TestEvaluateTransfer_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		repo           *mockTransferRepository
+		expectedStatus int
+	}{
+		{
+			name:           "missing institution",
+			body:           `{"courses":["IPC144"]}`,
+			repo:           &mockTransferRepository{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "no courses",
+			body:           `{"institution":"Seneca","courses":[]}`,
+			repo:           &mockTransferRepository{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "repository error",
+			body: `{"institution":"Seneca","courses":["IPC144"]}`,
+			repo: &mockTransferRepository{findFunc: func(ctx context.Context, institution string, externalCodes []string) ([]models.Equivalency, error) {
+				return nil, errors.New("db down")
+			}},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			setupTransferRouter(tt.repo).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/transfer/evaluate", bytes.NewBufferString(tt.body)))
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestListEquivalencies(t *testing.T) {
+	var gotInstitution string
+	router := setupTransferRouter(&mockTransferRepository{
+		listFunc: func(ctx context.Context, institution string) ([]models.Equivalency, error) {
+			gotInstitution = institution
+			return []models.Equivalency{{ID: "eq-1"}}, nil
+		},
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/transfer/equivalencies?institution=Seneca", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "seneca", gotInstitution)
+	assert.Contains(t, w.Body.String(), `"count":1`)
+}
+
+func TestUpsertEquivalency(t *testing.T) {
+	var saved *models.Equivalency
+	router := setupTransferRouter(&mockTransferRepository{
+		upsertFunc: func(ctx context.Context, eq *models.Equivalency) error {
+			saved = eq
+			eq.ID = "eq-1"
+			return nil
+		},
+	})
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{"valid", `{"institution":"Seneca","external_course_code":"ipc 144","york_course_code":"eecs1012","confidence":"high"}`, http.StatusOK},
+		{"bad confidence", `{"institution":"Seneca","external_course_code":"IPC144","york_course_code":"EECS1012","confidence":"certain"}`, http.StatusBadRequest},
+		{"missing york course", `{"institution":"Seneca","external_course_code":"IPC144","confidence":"high"}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/transfer/equivalencies", bytes.NewBufferString(tt.body)))
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+
+	assert.Equal(t, "seneca", saved.Institution)
+	assert.Equal(t, "IPC144", saved.ExternalCourseCode)
+	assert.Equal(t, "EECS1012", saved.YorkCourseCode)
+}
+
+func TestDeleteEquivalency(t *testing.T) {
+	tests := []struct {
+		name           string
+		deleteFunc     func(ctx context.Context, id string) (bool, error)
+		expectedStatus int
+	}{
+		{"deleted", func(ctx context.Context, id string) (bool, error) { return true, nil }, http.StatusOK},
+		{"not found", func(ctx context.Context, id string) (bool, error) { return false, nil }, http.StatusNotFound},
+		{"error", func(ctx context.Context, id string) (bool, error) { return false, errors.New("db down") }, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := setupTransferRouter(&mockTransferRepository{deleteFunc: tt.deleteFunc})
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/admin/transfer/equivalencies/eq-1", nil))
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
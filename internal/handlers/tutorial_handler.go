@@ -20,11 +20,11 @@ func (h *TutorialHandler) GetTutorialsBySectionID(c *gin.Context) {
 
 	tutorials, err := h.repo.GetBySectionID(c.Request.Context(), sectionID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tutorials"})
+		serverError(c, err, "Failed to fetch tutorials")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	respond(c, http.StatusOK, gin.H{
 		"data":  tutorials,
 		"count": len(tutorials),
 	})
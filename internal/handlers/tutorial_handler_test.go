@@ -8,6 +8,7 @@ import (
 	"strings"
 	"testing"
 	"time"
+	"yuplan/internal/dbtypes"
 	"yuplan/internal/models"
 	"yuplan/internal/repository"
 
@@ -37,7 +38,7 @@ func TestGetTutorialsBySectionID(t *testing.T) {
 					ID:            "tutorial-1",
 					SectionID:     sectionID,
 					CatalogNumber: "TUTR01",
-					Times:         &times,
+					Times:         dbtypes.NewNullString(times),
 					CreatedAt:     time.Now(),
 					UpdatedAt:     time.Now(),
 				},
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"yuplan/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// waitlistOdds estimates the odds of getting off a section's waitlist. Implemented by waitlist.Estimator.
+type waitlistOdds interface {
+	Odds(ctx context.Context, sectionID string, position int) (*models.WaitlistOdds, error)
+}
+
+type WaitlistHandler struct {
+	odds waitlistOdds
+}
+
+func NewWaitlistHandler(odds waitlistOdds) *WaitlistHandler {
+	return &WaitlistHandler{odds: odds}
+}
+
+// GetWaitlistOdds handles GET /api/v1/sections/:section_id/waitlist-odds?position=
+// The route shares its wildcard with /sections/:course_id, so the id arrives
+// as course_id.
+func (h *WaitlistHandler) GetWaitlistOdds(c *gin.Context) {
+	position, err := strconv.Atoi(c.Query("position"))
+	if err != nil || position < 1 || position > models.MaxWaitlistPosition {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Query parameter 'position' must be a whole number from 1 to %d", models.MaxWaitlistPosition)})
+		return
+	}
+
+	odds, err := h.odds.Odds(c.Request.Context(), c.Param("course_id"), position)
+	if err != nil {
+		serverError(c, err, "Failed to estimate waitlist odds")
+		return
+	}
+	if odds == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Section not found"})
+		return
+	}
+	respond(c, http.StatusOK, gin.H{"data": odds})
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"yuplan/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockWaitlistOdds struct {
+	position int
+	err      error
+}
+
+func (m *mockWaitlistOdds) Odds(ctx context.Context, sectionID string, position int) (*models.WaitlistOdds, error) {
+	m.position = position
+	if m.err != nil || sectionID != availableSection {
+		return nil, m.err
+	}
+	p := 0.5
+	return &models.WaitlistOdds{SectionID: sectionID, Position: position, Probability: &p, SampleSize: 8, Confidence: models.WaitlistConfidenceMedium}, nil
+}
+
+func TestGetWaitlistOdds(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		section        string
+		query          string
+		err            error
+		expectedStatus int
+	}{
+		{"estimate", availableSection, "?position=3", nil, http.StatusOK},
+		{"missing position", availableSection, "", nil, http.StatusBadRequest},
+		{"position zero", availableSection, "?position=0", nil, http.StatusBadRequest},
+		{"position too high", availableSection, "?position=1001", nil, http.StatusBadRequest},
+		{"no such section", "0192f3a1-5b2c-7d4e-8f60-0123456789ab", "?position=3", nil, http.StatusNotFound},
+		{"estimator error", availableSection, "?position=3", errors.New("db down"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			odds := &mockWaitlistOdds{err: tt.err}
+			router := gin.New()
+			router.GET("/sections/:course_id/waitlist-odds", NewWaitlistHandler(odds).GetWaitlistOdds)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sections/"+tt.section+"/waitlist-odds"+tt.query, nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, 3, odds.position)
+				assert.Contains(t, w.Body.String(), `"probability":0.5`)
+				assert.Contains(t, w.Body.String(), `"confidence":"medium"`)
+			}
+		})
+	}
+}
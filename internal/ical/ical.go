@@ -0,0 +1,178 @@
+// Package ical writes timetables as iCalendar (RFC 5545) files that calendar
+// apps such as Google Calendar can import.
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	_ "time/tzdata" // campus time zone rules, even where the OS has none
+	"yuplan/internal/models"
+)
+
+// TimeZone is where every meeting takes place.
+const TimeZone = "America/Toronto"
+
+// maxLineOctets is the longest a content line may be before it is folded.
+const maxLineOctets = 75
+
+var campusTime = mustLoadLocation(TimeZone)
+
+// weekdays maps the day letters in a times column to iCalendar days.
+var weekdays = map[string]struct {
+	weekday time.Weekday
+	byDay   string
+}{
+	"M": {time.Monday, "MO"},
+	"T": {time.Tuesday, "TU"},
+	"W": {time.Wednesday, "WE"},
+	"R": {time.Thursday, "TH"},
+	"F": {time.Friday, "FR"},
+	"S": {time.Saturday, "SA"},
+	"U": {time.Sunday, "SU"},
+}
+
+// vtimezone describes TimeZone for apps that don't know it by name.
+var vtimezone = []string{
+	"BEGIN:VTIMEZONE",
+	"TZID:" + TimeZone,
+	"BEGIN:DAYLIGHT",
+	"TZOFFSETFROM:-0500",
+	"TZOFFSETTO:-0400",
+	"TZNAME:EDT",
+	"DTSTART:19700308T020000",
+	"RRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=2SU",
+	"END:DAYLIGHT",
+	"BEGIN:STANDARD",
+	"TZOFFSETFROM:-0400",
+	"TZOFFSETTO:-0500",
+	"TZNAME:EST",
+	"DTSTART:19701101T020000",
+	"RRULE:FREQ=YEARLY;BYMONTH=11;BYDAY=1SU",
+	"END:STANDARD",
+	"END:VTIMEZONE",
+}
+
+// Write writes activities as a calendar with one weekly recurring event per
+// meeting, from its first occurrence in the course's term to the term's last
+// day, and returns how many events it wrote. Activities without session
+// dates, unreadable times or a scheduled meeting are left out.
+func Write(w io.Writer, activities []models.CalendarActivity, now time.Time) (int, error) {
+	bw := bufio.NewWriter(w)
+	line := func(s string) { writeLine(bw, s) }
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//yuplan//core-api//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	for _, l := range vtimezone {
+		line(l)
+	}
+
+	stamp := now.UTC().Format("20060102T150405Z")
+	events := 0
+	for _, a := range activities {
+		if !a.SessionStart.Valid || !a.SessionEnd.Valid {
+			continue
+		}
+		meetings, err := models.ScheduledMeetings(a.Times)
+		if err != nil {
+			continue
+		}
+		first, last := models.CourseTermDates(a.Term, a.SessionStart.Time, a.SessionEnd.Time)
+		for _, m := range meetings {
+			day, ok := weekdays[m.Day]
+			startMinute, endMinute, windowOK := m.Window()
+			if !ok || !windowOK {
+				continue
+			}
+			date := firstWeekday(first, day.weekday)
+			if date.After(last) {
+				continue
+			}
+			start := at(date, startMinute)
+			end := at(date, endMinute)
+			// UNTIL is in UTC when DTSTART has a time zone; take the end of the last day
+			until := at(last, 24*60).Add(-time.Second).UTC()
+
+			line("BEGIN:VEVENT")
+			line(fmt.Sprintf("UID:%s-%s-%04d@yuplan", a.ID, m.Day, startMinute))
+			line("DTSTAMP:" + stamp)
+			line("DTSTART;TZID=" + TimeZone + ":" + start.Format("20060102T150405"))
+			line("DTEND;TZID=" + TimeZone + ":" + end.Format("20060102T150405"))
+			line("RRULE:FREQ=WEEKLY;BYDAY=" + day.byDay + ";UNTIL=" + until.Format("20060102T150405Z"))
+			line("SUMMARY:" + escape(fmt.Sprintf("%s %s %s", a.CourseCode, a.Section, a.Type)))
+			if a.CourseName != "" {
+				line("DESCRIPTION:" + escape(a.CourseName))
+			}
+			if where := meetingPlace(m); where != "" {
+				line("LOCATION:" + escape(where))
+			}
+			line("END:VEVENT")
+			events++
+		}
+	}
+
+	line("END:VCALENDAR")
+	return events, bw.Flush()
+}
+
+// firstWeekday returns the first date on or after from that falls on weekday.
+func firstWeekday(from time.Time, weekday time.Weekday) time.Time {
+	return from.AddDate(0, 0, (int(weekday)-int(from.Weekday())+7)%7)
+}
+
+// at is minute minutes into date's day, campus time.
+func at(date time.Time, minute int) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, minute, 0, 0, campusTime)
+}
+
+// meetingPlace is where a meeting is held, room first.
+func meetingPlace(m models.Meeting) string {
+	parts := make([]string, 0, 2)
+	for _, p := range []string{m.Room, m.Campus} {
+		if p = strings.TrimSpace(p); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// escape escapes a TEXT value.
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// writeLine writes a content line ending in CRLF, folding it onto
+// continuation lines that start with a space so none exceeds 75 octets.
+// Folds fall between characters, never inside a multi-byte one.
+func writeLine(w *bufio.Writer, s string) {
+	limit := maxLineOctets
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(s[cut]) {
+			cut--
+		}
+		w.WriteString(s[:cut])
+		w.WriteString("\r\n ")
+		s = s[cut:]
+		limit = maxLineOctets - 1 // the leading space counts
+	}
+	w.WriteString(s)
+	w.WriteString("\r\n")
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic("ical: loading " + name + ": " + err.Error())
+	}
+	return loc
+}
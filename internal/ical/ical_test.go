@@ -0,0 +1,98 @@
+package ical
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"yuplan/internal/dbtypes"
+	"yuplan/internal/models"
+)
+
+func TestWrite(t *testing.T) {
+	session := func(start, end time.Time) (dbtypes.NullTime, dbtypes.NullTime) {
+		return dbtypes.NewNullTime(start), dbtypes.NewNullTime(end)
+	}
+	fwStart, fwEnd := session(time.Date(2025, time.September, 2, 0, 0, 0, 0, time.UTC), time.Date(2026, time.April, 30, 0, 0, 0, 0, time.UTC))
+	activities := []models.CalendarActivity{
+		{
+			ID: "a1", CourseCode: "EECS2030", CourseName: "Advanced Object Oriented Programming", Term: models.TermFall,
+			Section: "A", Type: models.ActivityLecture, SessionStart: fwStart, SessionEnd: fwEnd,
+			Times: dbtypes.NewNullString(`[{"day":"W","time":"10:00","duration":"80","campus":"Keele","room":"CLH A"}]`),
+		},
+		{
+			ID: "a2", CourseCode: "EECS2030", Term: models.TermFall, Section: "A", Type: models.ActivityLab,
+			Times: dbtypes.NewNullString(`[{"day":"M","time":"14:30","duration":"90"}]`),
+		},
+		{
+			ID: "a3", CourseCode: "MATH1090", Term: models.TermWinter, Section: "M", Type: models.ActivityOnline,
+			SessionStart: fwStart, SessionEnd: fwEnd,
+			Times: dbtypes.NewNullString(`[{"day":"","time":"0:00","duration":"0"}]`),
+		},
+	}
+
+	var buf bytes.Buffer
+	now := time.Date(2025, time.August, 1, 12, 0, 0, 0, time.UTC)
+	events, err := Write(&buf, activities, now)
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if events != 1 {
+		t.Errorf("Expected one event (no session dates and no scheduled meetings are left out), got %d", events)
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"TZID:America/Toronto\r\n",
+		"UID:a1-W-0600@yuplan\r\n",
+		"DTSTAMP:20250801T120000Z\r\n",
+		// The first Wednesday on or after September 2
+		"DTSTART;TZID=America/Toronto:20250903T100000\r\n",
+		"DTEND;TZID=America/Toronto:20250903T112000\r\n",
+		// Fall ends with the year: 23:59:59 EST is 04:59:59 UTC
+		"RRULE:FREQ=WEEKLY;BYDAY=WE;UNTIL=20260101T045959Z\r\n",
+		"SUMMARY:EECS2030 A LECT\r\n",
+		"LOCATION:CLH A\\, Keele\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteLine_Folds(t *testing.T) {
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	writeLine(w, "DESCRIPTION:"+strings.Repeat("é", 60))
+	w.Flush()
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n")
+	if len(lines) < 2 {
+		t.Fatalf("Expected a long line to be folded, got %q", buf.String())
+	}
+	var joined strings.Builder
+	for i, l := range lines {
+		if len(l) > maxLineOctets {
+			t.Errorf("Line %d is %d octets", i, len(l))
+		}
+		if i > 0 {
+			if !strings.HasPrefix(l, " ") {
+				t.Errorf("Expected continuation line %d to start with a space, got %q", i, l)
+			}
+			l = l[1:]
+		}
+		joined.WriteString(l)
+	}
+	if joined.String() != "DESCRIPTION:"+strings.Repeat("é", 60) {
+		t.Errorf("Expected unfolding to restore the line, got %q", joined.String())
+	}
+}
+
+func TestEscape(t *testing.T) {
+	if got := escape("Intro; logic, sets\\proofs\nfun"); got != `Intro\; logic\, sets\\proofs\nfun` {
+		t.Errorf("Unexpected escape: %q", got)
+	}
+}
@@ -0,0 +1,98 @@
+// Package id generates and checks the UUIDs used as row IDs.
+package id
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+var (
+	mu     sync.Mutex
+	lastMs int64
+	seq    uint16 // 12-bit counter keeping IDs from one millisecond in order
+)
+
+// New returns a version 7 UUID: a millisecond timestamp followed by random
+// bits, so IDs sort roughly by creation time and index like a sequence.
+// IDs made in the same millisecond by this process still sort in order.
+func New() string {
+	return newAt(time.Now())
+}
+
+func newAt(now time.Time) string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		// crypto/rand only fails if the OS source is broken
+		panic("id: reading random bytes: " + err.Error())
+	}
+
+	ms := now.UnixMilli()
+	mu.Lock()
+	if ms <= lastMs {
+		// Same millisecond (or the clock went back): keep the last timestamp and count on
+		ms = lastMs
+		seq++
+		if seq > 0x0fff {
+			ms++
+			seq = 0
+		}
+	} else {
+		seq = binary.BigEndian.Uint16(b[6:8]) & 0x07ff // random start, leaving room to count
+	}
+	lastMs = ms
+	counter := seq
+	mu.Unlock()
+
+	b[0] = byte(ms >> 40)
+	b[1] = byte(ms >> 32)
+	b[2] = byte(ms >> 24)
+	b[3] = byte(ms >> 16)
+	b[4] = byte(ms >> 8)
+	b[5] = byte(ms)
+	b[6] = 0x70 | byte(counter>>8)
+	b[7] = byte(counter)
+	b[8] = 0x80 | b[8]&0x3f // RFC 9562 variant
+	return format(b)
+}
+
+func format(b [16]byte) string {
+	var out [36]byte
+	hex.Encode(out[0:8], b[0:4])
+	out[8] = '-'
+	hex.Encode(out[9:13], b[4:6])
+	out[13] = '-'
+	hex.Encode(out[14:18], b[6:8])
+	out[18] = '-'
+	hex.Encode(out[19:23], b[8:10])
+	out[23] = '-'
+	hex.Encode(out[24:36], b[10:16])
+	return string(out[:])
+}
+
+// Valid reports whether s is a UUID in the canonical 8-4-4-4-12 hex form
+// Postgres returns, of any version. Upper-case hex is accepted.
+func Valid(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		switch i {
+		case 8, 13, 18, 23:
+			if s[i] != '-' {
+				return false
+			}
+		default:
+			if !isHex(s[i]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
@@ -0,0 +1,53 @@
+package id
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew_IsVersion7(t *testing.T) {
+	got := New()
+	assert.True(t, Valid(got), got)
+	assert.Equal(t, byte('7'), got[14], "version nibble")
+	assert.Contains(t, "89ab", string(got[19]), "variant nibble")
+}
+
+func TestNew_SortsByCreation(t *testing.T) {
+	at := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	ids := []string{newAt(at), newAt(at), newAt(at), newAt(at.Add(time.Millisecond)), newAt(at.Add(time.Second))}
+
+	sorted := append([]string(nil), ids...)
+	sort.Strings(sorted)
+	assert.Equal(t, ids, sorted)
+	assert.Len(t, map[string]bool{ids[0]: true, ids[1]: true, ids[2]: true}, 3)
+}
+
+func TestNew_ClockGoingBackKeepsOrder(t *testing.T) {
+	at := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	first := newAt(at)
+	second := newAt(at.Add(-time.Minute))
+	assert.Less(t, first, second)
+}
+
+func TestValid(t *testing.T) {
+	for _, s := range []string{
+		"6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+		"01928C6A-7B3E-7A21-9F00-0123456789AB",
+	} {
+		assert.True(t, Valid(s), s)
+	}
+	for _, s := range []string{
+		"",
+		"not-a-uuid",
+		"6ba7b8109dad11d180b400c04fd430c8",
+		"6ba7b810-9dad-11d1-80b4-00c04fd430c",
+		"6ba7b810-9dad-11d1-80b4-00c04fd430cg",
+		"6ba7b810_9dad-11d1-80b4-00c04fd430c8",
+		"EECS2030",
+	} {
+		assert.False(t, Valid(s), s)
+	}
+}
@@ -0,0 +1,188 @@
+// Package jobs coordinates scheduled work across API instances.
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// lockConn is the connection a job's lock is held on for the whole run.
+type lockConn interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	// Release hands the connection back, or closes it when its lock may
+	// still be held, so the next user can't inherit the lock.
+	Release(ctx context.Context, locked bool)
+}
+
+type lockDB interface {
+	Acquire(ctx context.Context) (lockConn, error)
+}
+
+// LockStats counts how often a job's lock was taken here versus held elsewhere.
+type LockStats struct {
+	Job          string    `json:"job"`
+	Acquired     int64     `json:"acquired"`    // runs on this instance
+	Contended    int64     `json:"contended"`   // skipped because another instance held the lock
+	AlreadyRan   int64     `json:"already_ran"` // skipped because another instance ran the tick
+	Errors       int64     `json:"errors"`      // lock could not be checked, or the job failed
+	LastAcquired time.Time `json:"last_acquired,omitzero"`
+}
+
+// Locker makes a job run on at most one instance at a time, and once per
+// schedule tick. The lock is a Postgres session-level advisory lock on a
+// connection of its own, held outside any transaction, so a long run doesn't
+// sit idle in a transaction and the lock goes away with the connection if the
+// instance dies mid-run. Each job's last tick is kept in job_runs.
+type Locker struct {
+	db    lockDB
+	mu    sync.Mutex
+	stats map[string]*LockStats
+}
+
+func NewLocker(pool *pgxpool.Pool) *Locker {
+	return newLocker(poolDB{pool})
+}
+
+func newLocker(db lockDB) *Locker {
+	return &Locker{db: db, stats: make(map[string]*LockStats)}
+}
+
+// Tick is the schedule tick t falls in for a job run every interval: t
+// truncated to the interval in UTC, the same on every instance.
+func Tick(t time.Time, interval time.Duration) time.Time {
+	return t.UTC().Truncate(interval)
+}
+
+// Do runs fn for tick if no other instance is running job and none has run
+// it for tick or a later one. A zero tick is a run off the schedule, which
+// only needs the lock. ran is false when the job was skipped, which is not an
+// error. A tick is spent once fn starts, even if it fails; the next one
+// retries.
+func (l *Locker) Do(ctx context.Context, job string, tick time.Time, fn func(ctx context.Context) error) (ran bool, err error) {
+	conn, err := l.db.Acquire(ctx)
+	if err != nil {
+		l.record(job, func(s *LockStats) { s.Errors++ })
+		return false, fmt.Errorf("acquire lock connection for %s: %w", job, err)
+	}
+
+	var acquired bool
+	if err := conn.QueryRow(ctx, "SELECT pg_try_advisory_lock($1)", lockKey(job)).Scan(&acquired); err != nil {
+		conn.Release(ctx, true)
+		l.record(job, func(s *LockStats) { s.Errors++ })
+		return false, fmt.Errorf("acquire lock for %s: %w", job, err)
+	}
+	if !acquired {
+		conn.Release(ctx, false)
+		l.record(job, func(s *LockStats) { s.Contended++ })
+		return false, nil
+	}
+	defer func() {
+		unlockCtx := context.WithoutCancel(ctx)
+		_, unlockErr := conn.Exec(unlockCtx, "SELECT pg_advisory_unlock($1)", lockKey(job))
+		conn.Release(unlockCtx, unlockErr != nil)
+	}()
+
+	if !tick.IsZero() {
+		claimed, err := claimTick(ctx, conn, job, tick)
+		if err != nil {
+			l.record(job, func(s *LockStats) { s.Errors++ })
+			return false, err
+		}
+		if !claimed {
+			l.record(job, func(s *LockStats) { s.AlreadyRan++ })
+			return false, nil
+		}
+	}
+
+	l.record(job, func(s *LockStats) {
+		s.Acquired++
+		s.LastAcquired = time.Now().UTC()
+	})
+	if err := fn(ctx); err != nil {
+		l.record(job, func(s *LockStats) { s.Errors++ })
+		return true, err
+	}
+	return true, nil
+}
+
+// claimTick records tick as job's last one and reports whether it is newer
+// than the last one recorded.
+func claimTick(ctx context.Context, conn lockConn, job string, tick time.Time) (bool, error) {
+	tag, err := conn.Exec(ctx,
+		`INSERT INTO job_runs (job, tick, started_at)
+		 VALUES ($1, $2, NOW())
+		 ON CONFLICT (job) DO UPDATE SET tick = EXCLUDED.tick, started_at = EXCLUDED.started_at
+		 WHERE job_runs.tick < EXCLUDED.tick`,
+		job, tick.UTC(),
+	)
+	if err != nil {
+		return false, fmt.Errorf("claim %s tick %s: %w", job, tick.UTC().Format(time.RFC3339), err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// Stats returns a snapshot of per-job lock counters sorted by job name.
+func (l *Locker) Stats() []LockStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats := make([]LockStats, 0, len(l.stats))
+	for _, s := range l.stats {
+		stats = append(stats, *s)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Job < stats[j].Job })
+	return stats
+}
+
+func (l *Locker) record(job string, update func(*LockStats)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	s, ok := l.stats[job]
+	if !ok {
+		s = &LockStats{Job: job}
+		l.stats[job] = s
+	}
+	update(s)
+}
+
+// lockKey maps a job name onto the bigint advisory lock keyspace.
+func lockKey(job string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte("yuplan/jobs/" + job))
+	return int64(h.Sum64())
+}
+
+// poolDB hands out pool connections for locks.
+type poolDB struct {
+	pool *pgxpool.Pool
+}
+
+func (p poolDB) Acquire(ctx context.Context) (lockConn, error) {
+	conn, err := p.pool.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return poolConn{conn}, nil
+}
+
+type poolConn struct {
+	*pgxpool.Conn
+}
+
+func (c poolConn) Release(ctx context.Context, locked bool) {
+	if locked {
+		// The pool drops closed connections instead of reusing them
+		c.Conn.Conn().Close(ctx)
+	}
+	c.Conn.Release()
+}
@@ -0,0 +1,189 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockLockConn is a pgxmock connection that remembers how it was released.
+type mockLockConn struct {
+	pgxmock.PgxConnIface
+	released bool
+	closed   bool
+}
+
+func (c *mockLockConn) Release(ctx context.Context, locked bool) {
+	c.released = true
+	c.closed = locked
+}
+
+type mockLockDB struct {
+	conn *mockLockConn
+	err  error
+}
+
+func (d *mockLockDB) Acquire(ctx context.Context) (lockConn, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+	return d.conn, nil
+}
+
+func newMockLocker(t *testing.T) (*Locker, *mockLockConn) {
+	mock, err := pgxmock.NewConn()
+	require.NoError(t, err)
+	t.Cleanup(func() { mock.Close(context.Background()) })
+	conn := &mockLockConn{PgxConnIface: mock}
+	return newLocker(&mockLockDB{conn: conn}), conn
+}
+
+func expectLock(conn *mockLockConn, job string, acquired bool) {
+	conn.ExpectQuery("SELECT pg_try_advisory_lock\\(\\$1\\)").
+		WithArgs(lockKey(job)).
+		WillReturnRows(pgxmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(acquired))
+}
+
+func expectUnlock(conn *mockLockConn, job string) {
+	conn.ExpectExec("SELECT pg_advisory_unlock\\(\\$1\\)").
+		WithArgs(lockKey(job)).
+		WillReturnResult(pgxmock.NewResult("SELECT", 1))
+}
+
+func TestLocker_RunsWhenLockAcquired(t *testing.T) {
+	locker, conn := newMockLocker(t)
+	expectLock(conn, "export", true)
+	expectUnlock(conn, "export")
+
+	calls := 0
+	ran, err := locker.Do(context.Background(), "export", time.Time{}, func(ctx context.Context) error {
+		calls++
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, ran)
+	assert.Equal(t, 1, calls)
+	assert.NoError(t, conn.ExpectationsWereMet())
+	assert.True(t, conn.released)
+	assert.False(t, conn.closed)
+
+	stats := locker.Stats()
+	assert.Len(t, stats, 1)
+	assert.Equal(t, "export", stats[0].Job)
+	assert.Equal(t, int64(1), stats[0].Acquired)
+	assert.False(t, stats[0].LastAcquired.IsZero())
+}
+
+func TestLocker_SkipsWhenLockHeldElsewhere(t *testing.T) {
+	locker, conn := newMockLocker(t)
+	expectLock(conn, "export", false)
+
+	ran, err := locker.Do(context.Background(), "export", time.Time{}, func(ctx context.Context) error {
+		t.Fatal("job should not run without the lock")
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.False(t, ran)
+	assert.Equal(t, int64(1), locker.Stats()[0].Contended)
+	assert.NoError(t, conn.ExpectationsWereMet())
+	assert.True(t, conn.released)
+}
+
+func TestLocker_RunsEachTickOnce(t *testing.T) {
+	locker, conn := newMockLocker(t)
+	tick := time.Date(2025, time.September, 2, 7, 0, 0, 0, time.UTC)
+
+	expectLock(conn, "export", true)
+	conn.ExpectExec("INSERT INTO job_runs(.+)ON CONFLICT \\(job\\) DO UPDATE(.+)WHERE job_runs.tick < EXCLUDED.tick").
+		WithArgs("export", tick).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	expectUnlock(conn, "export")
+
+	// Another instance ran the tick before this one got the lock
+	expectLock(conn, "export", true)
+	conn.ExpectExec("INSERT INTO job_runs").
+		WithArgs("export", tick).
+		WillReturnResult(pgxmock.NewResult("INSERT", 0))
+	expectUnlock(conn, "export")
+
+	calls := 0
+	fn := func(ctx context.Context) error {
+		calls++
+		return nil
+	}
+	ran, err := locker.Do(context.Background(), "export", tick, fn)
+	assert.NoError(t, err)
+	assert.True(t, ran)
+
+	ran, err = locker.Do(context.Background(), "export", tick, fn)
+	assert.NoError(t, err)
+	assert.False(t, ran)
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, int64(1), locker.Stats()[0].AlreadyRan)
+	assert.NoError(t, conn.ExpectationsWereMet())
+}
+
+func TestLocker_Errors(t *testing.T) {
+	t.Run("no connection", func(t *testing.T) {
+		locker := newLocker(&mockLockDB{err: errors.New("db down")})
+		ran, err := locker.Do(context.Background(), "export", time.Time{}, func(ctx context.Context) error { return nil })
+		assert.Error(t, err)
+		assert.False(t, ran)
+		assert.Equal(t, int64(1), locker.Stats()[0].Errors)
+	})
+
+	t.Run("lock check fails", func(t *testing.T) {
+		locker, conn := newMockLocker(t)
+		conn.ExpectQuery("SELECT pg_try_advisory_lock").WillReturnError(errors.New("connection reset"))
+
+		ran, err := locker.Do(context.Background(), "export", time.Time{}, func(ctx context.Context) error { return nil })
+		assert.Error(t, err)
+		assert.False(t, ran)
+		assert.True(t, conn.closed, "a connection that may hold the lock isn't reused")
+	})
+
+	t.Run("job fails", func(t *testing.T) {
+		locker, conn := newMockLocker(t)
+		expectLock(conn, "export", true)
+		expectUnlock(conn, "export")
+
+		ran, err := locker.Do(context.Background(), "export", time.Time{}, func(ctx context.Context) error { return errors.New("s3 down") })
+		assert.EqualError(t, err, "s3 down")
+		assert.True(t, ran)
+		assert.Equal(t, int64(1), locker.Stats()[0].Acquired)
+		assert.Equal(t, int64(1), locker.Stats()[0].Errors)
+		assert.NoError(t, conn.ExpectationsWereMet())
+	})
+
+	t.Run("unlock fails", func(t *testing.T) {
+		locker, conn := newMockLocker(t)
+		expectLock(conn, "export", true)
+		conn.ExpectExec("SELECT pg_advisory_unlock").WillReturnError(errors.New("connection reset"))
+
+		ran, err := locker.Do(context.Background(), "export", time.Time{}, func(ctx context.Context) error { return nil })
+		assert.NoError(t, err)
+		assert.True(t, ran)
+		assert.True(t, conn.closed)
+	})
+}
+
+func TestTick(t *testing.T) {
+	toronto, err := time.LoadLocation("America/Toronto")
+	require.NoError(t, err)
+	at := time.Date(2025, time.September, 2, 3, 47, 12, 0, toronto)
+	assert.Equal(t, time.Date(2025, time.September, 2, 7, 45, 0, 0, time.UTC), Tick(at, 15*time.Minute))
+	assert.Equal(t, Tick(at, time.Hour), Tick(at.Add(-40*time.Minute), time.Hour))
+}
+
+func TestLockKey_StablePerJob(t *testing.T) {
+	assert.Equal(t, lockKey("export"), lockKey("export"))
+	assert.NotEqual(t, lockKey("export"), lockKey("scrape"))
+}
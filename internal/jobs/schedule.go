@@ -0,0 +1,135 @@
+package jobs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a standard five-field cron expression: minute, hour, day of
+// month, month and day of week, evaluated in UTC. Fields take *, numbers,
+// ranges (1-5), steps (*/15, 0-30/10) and comma-separated lists of those; day
+// of week runs 0-6 from Sunday, with 7 also Sunday. As in cron, when both day
+// fields are restricted a day matching either one fires.
+type Schedule struct {
+	expr                          string
+	minute, hour, dom, month, dow uint64
+	domRestricted, dowRestricted  bool
+}
+
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var cronFields = [5]cronField{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// ParseSchedule parses a cron expression such as "0 7 * * *" (07:00 UTC daily).
+func ParseSchedule(expr string) (Schedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return Schedule{}, fmt.Errorf("invalid schedule %q: expected 5 fields (minute hour day-of-month month day-of-week), got %d", expr, len(fields))
+	}
+	var bits [5]uint64
+	for i, field := range fields {
+		b, err := parseCronField(field, cronFields[i])
+		if err != nil {
+			return Schedule{}, fmt.Errorf("invalid schedule %q: %w", expr, err)
+		}
+		bits[i] = b
+	}
+	if bits[4]&(1<<7) != 0 {
+		bits[4] = bits[4]&^(1<<7) | 1
+	}
+	return Schedule{
+		expr:          strings.Join(fields, " "),
+		minute:        bits[0],
+		hour:          bits[1],
+		dom:           bits[2],
+		month:         bits[3],
+		dow:           bits[4],
+		domRestricted: !strings.HasPrefix(fields[2], "*"),
+		dowRestricted: !strings.HasPrefix(fields[4], "*"),
+	}, nil
+}
+
+func parseCronField(field string, f cronField) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, rawStep, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(rawStep); err != nil || step < 1 {
+				return 0, fmt.Errorf("%s step %q must be a positive number", f.name, rawStep)
+			}
+		}
+
+		lo, hi := f.min, f.max
+		if rng != "*" {
+			rawLo, rawHi, isRange := strings.Cut(rng, "-")
+			var err error
+			if lo, err = strconv.Atoi(rawLo); err != nil {
+				return 0, fmt.Errorf("%s %q is not a number", f.name, part)
+			}
+			hi = lo
+			if isRange {
+				if hi, err = strconv.Atoi(rawHi); err != nil {
+					return 0, fmt.Errorf("%s %q is not a number", f.name, part)
+				}
+			} else if hasStep {
+				hi = f.max
+			}
+			if lo < f.min || hi > f.max || lo > hi {
+				return 0, fmt.Errorf("%s %q is outside %d-%d", f.name, part, f.min, f.max)
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+// Next returns the first time after t that the schedule fires, or the zero
+// time if it never does, e.g. on February 30th.
+func (s Schedule) Next(t time.Time) time.Time {
+	t = t.UTC().Truncate(time.Minute).Add(time.Minute)
+	// Every date a schedule can match comes round within four years of leap days.
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<t.Month()) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		case !s.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+		case s.hour&(1<<t.Hour()) == 0:
+			t = t.Truncate(time.Hour).Add(time.Hour)
+		case s.minute&(1<<t.Minute()) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (s Schedule) matchesDay(t time.Time) bool {
+	dom := s.dom&(1<<t.Day()) != 0
+	dow := s.dow&(1<<t.Weekday()) != 0
+	if s.domRestricted && s.dowRestricted {
+		return dom || dow
+	}
+	return dom && dow
+}
+
+func (s Schedule) String() string {
+	return s.expr
+}
@@ -0,0 +1,43 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSchedule_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "0 7 * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "5-1 * * * *", "*/0 * * * *", "a * * * *"} {
+		_, err := ParseSchedule(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestSchedule_Next(t *testing.T) {
+	from := time.Date(2026, 10, 16, 7, 0, 30, 0, time.UTC) // a Friday
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"0 7 * * *", time.Date(2026, 10, 17, 7, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 10, 16, 7, 15, 0, 0, time.UTC)},
+		{"30 2 1,15 * *", time.Date(2026, 11, 1, 2, 30, 0, 0, time.UTC)},
+		{"0 9-17/4 * * 1-5", time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: the 20th or any Monday
+		{"0 0 20 * 1", time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+	for _, tt := range tests {
+		s, err := ParseSchedule(tt.expr)
+		assert.NoError(t, err, tt.expr)
+		assert.Equal(t, tt.want, s.Next(from), tt.expr)
+	}
+
+	s, _ := ParseSchedule("0 7 * * *")
+	toronto := time.FixedZone("EDT", -4*60*60)
+	assert.Equal(t, time.Date(2026, 10, 17, 7, 0, 0, 0, time.UTC), s.Next(time.Date(2026, 10, 16, 23, 0, 0, 0, toronto)))
+	assert.Equal(t, "0 7 * * *", s.String())
+}
@@ -0,0 +1,99 @@
+package keywords
+
+import (
+	"context"
+	"log"
+	"time"
+	"yuplan/internal/jobs"
+	"yuplan/internal/models"
+)
+
+// PerCourse is how many keywords are stored for each course.
+const PerCourse = 50
+
+// Store reads review text and replaces the stored keywords. Implemented by repository.ReviewKeywordRepository.
+type Store interface {
+	ListReviewTexts(ctx context.Context) ([]models.ReviewText, error)
+	ReplaceKeywords(ctx context.Context, keywords []models.CourseKeywords) error
+}
+
+// jobLocker keeps scheduled runs to one instance at a time, once per tick. Implemented by jobs.Locker.
+type jobLocker interface {
+	Do(ctx context.Context, job string, tick time.Time, fn func(ctx context.Context) error) (bool, error)
+}
+
+// Aggregator rebuilds review_keywords from reviews.
+type Aggregator struct {
+	store  Store
+	locker jobLocker
+}
+
+func NewAggregator(store Store) *Aggregator {
+	return &Aggregator{store: store}
+}
+
+// WithLocker makes Start skip runs while another instance holds the aggregation lock.
+func (a *Aggregator) WithLocker(locker jobLocker) *Aggregator {
+	a.locker = locker
+	return a
+}
+
+// Run recomputes every course's keywords and returns how many courses have any.
+func (a *Aggregator) Run(ctx context.Context) (int, error) {
+	reviews, err := a.store.ListReviewTexts(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	// Reviews are ordered by course, so each course's texts are adjacent
+	var courses []models.CourseKeywords
+	for start := 0; start < len(reviews); {
+		end := start
+		var texts []string
+		for end < len(reviews) && reviews[end].CourseCode == reviews[start].CourseCode {
+			texts = append(texts, reviews[end].Text)
+			end++
+		}
+		if keywords := Extract(texts, PerCourse); len(keywords) > 0 {
+			courses = append(courses, models.CourseKeywords{CourseCode: reviews[start].CourseCode, Keywords: keywords})
+		}
+		start = end
+	}
+
+	if err := a.store.ReplaceKeywords(ctx, courses); err != nil {
+		return 0, err
+	}
+	return len(courses), nil
+}
+
+// Start aggregates immediately and then every interval until ctx is done.
+func (a *Aggregator) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := a.runScheduled(ctx, jobs.Tick(time.Now(), interval)); err != nil {
+				log.Printf("review keyword aggregation failed: %v", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (a *Aggregator) runScheduled(ctx context.Context, tick time.Time) error {
+	run := func(ctx context.Context) error {
+		_, err := a.Run(ctx)
+		return err
+	}
+	if a.locker == nil {
+		return run(ctx)
+	}
+	_, err := a.locker.Do(ctx, "review_keywords", tick, run)
+	return err
+}
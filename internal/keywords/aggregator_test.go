@@ -0,0 +1,86 @@
+package keywords
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeStore struct {
+	reviews []models.ReviewText
+	listErr error
+	saved   []models.CourseKeywords
+	calls   int
+}
+
+func (f *fakeStore) ListReviewTexts(ctx context.Context) ([]models.ReviewText, error) {
+	return f.reviews, f.listErr
+}
+
+func (f *fakeStore) ReplaceKeywords(ctx context.Context, keywords []models.CourseKeywords) error {
+	f.saved = keywords
+	f.calls++
+	return nil
+}
+
+type fakeLocker struct {
+	held bool
+}
+
+func (f *fakeLocker) Do(ctx context.Context, job string, tick time.Time, fn func(ctx context.Context) error) (bool, error) {
+	if f.held {
+		return false, nil
+	}
+	return true, fn(ctx)
+}
+
+func TestAggregator_Run(t *testing.T) {
+	store := &fakeStore{reviews: []models.ReviewText{
+		{CourseCode: "EECS2030", Text: "Great labs"},
+		{CourseCode: "EECS2030", Text: "The labs were great"},
+		{CourseCode: "MATH1013", Text: "Only one review here"},
+	}}
+
+	n, err := NewAggregator(store).Run(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Len(t, store.saved, 1)
+	assert.Equal(t, "EECS2030", store.saved[0].CourseCode)
+	assert.Equal(t, []models.Keyword{{Term: "great", Count: 2}, {Term: "labs", Count: 2}}, store.saved[0].Keywords)
+}
+
+func TestAggregator_RunClearsWhenNothingQualifies(t *testing.T) {
+	store := &fakeStore{}
+
+	n, err := NewAggregator(store).Run(context.Background())
+
+	assert.NoError(t, err)
+	assert.Zero(t, n)
+	assert.Equal(t, 1, store.calls)
+}
+
+func TestAggregator_RunListError(t *testing.T) {
+	store := &fakeStore{listErr: errors.New("db down")}
+
+	_, err := NewAggregator(store).Run(context.Background())
+	assert.Error(t, err)
+	assert.Zero(t, store.calls)
+}
+
+func TestAggregator_ScheduledRunsRespectLocker(t *testing.T) {
+	store := &fakeStore{}
+	locker := &fakeLocker{held: true}
+	aggregator := NewAggregator(store).WithLocker(locker)
+
+	assert.NoError(t, aggregator.runScheduled(context.Background(), time.Now()))
+	assert.Zero(t, store.calls)
+
+	locker.held = false
+	assert.NoError(t, aggregator.runScheduled(context.Background(), time.Now()))
+	assert.Equal(t, 1, store.calls)
+}
@@ -0,0 +1,127 @@
+// Package keywords aggregates the most used words and phrases in course reviews.
+package keywords
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"yuplan/internal/models"
+)
+
+// MinReviews is how many reviews must use a term before it is shown. Terms
+// from a single review are noise and can identify the reviewer.
+const MinReviews = 2
+
+// Extract returns up to limit terms used by at least MinReviews of the texts,
+// most used first. Terms are single words and two-word phrases with stop words
+// removed. Each review counts a term once, however often it repeats it.
+func Extract(texts []string, limit int) []models.Keyword {
+	counts := make(map[string]int)
+	for _, text := range texts {
+		for term := range terms(text) {
+			counts[term]++
+		}
+	}
+
+	// A word that only ever appears inside a shown phrase adds nothing
+	// ("office hours" makes "office" and "hours" redundant).
+	for term, n := range counts {
+		if n < MinReviews || !strings.Contains(term, " ") {
+			continue
+		}
+		for _, word := range strings.Fields(term) {
+			if counts[word] <= n {
+				delete(counts, word)
+			}
+		}
+	}
+
+	keywords := make([]models.Keyword, 0, len(counts))
+	for term, n := range counts {
+		if n >= MinReviews {
+			keywords = append(keywords, models.Keyword{Term: term, Count: n})
+		}
+	}
+	sort.Slice(keywords, func(i, j int) bool {
+		if keywords[i].Count != keywords[j].Count {
+			return keywords[i].Count > keywords[j].Count
+		}
+		return keywords[i].Term < keywords[j].Term
+	})
+	if limit > 0 && len(keywords) > limit {
+		keywords = keywords[:limit]
+	}
+	return keywords
+}
+
+// terms returns the distinct words and adjacent word pairs in a text.
+// Pairs never span punctuation or a dropped word.
+func terms(text string) map[string]bool {
+	found := make(map[string]bool)
+	text = strings.ReplaceAll(strings.ToLower(text), "’", "'")
+	for _, clause := range strings.FieldsFunc(text, isClauseBreak) {
+		prev := ""
+		for _, word := range strings.FieldsFunc(clause, isWordBreak) {
+			word = strings.TrimSuffix(strings.Trim(word, "'"), "'s")
+			if !keep(word) {
+				prev = ""
+				continue
+			}
+			found[word] = true
+			if prev != "" {
+				found[prev+" "+word] = true
+			}
+			prev = word
+		}
+	}
+	return found
+}
+
+func isClauseBreak(r rune) bool {
+	return strings.ContainsRune(".,;:!?()[]{}\"“”\n", r)
+}
+
+func isWordBreak(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\'' && r != '+' && r != '#'
+}
+
+func keep(word string) bool {
+	if len([]rune(word)) < 3 || stopWords[word] {
+		return false
+	}
+	for _, r := range word {
+		if unicode.IsLetter(r) {
+			return true
+		}
+	}
+	return false // numbers only
+}
+
+var stopWords = toSet(
+	// English
+	"about", "above", "after", "again", "against", "all", "also", "although", "always", "and", "any", "are",
+	"aren't", "around", "because", "been", "before", "being", "below", "between", "both", "but", "can",
+	"can't", "cannot", "could", "couldn't", "did", "didn't", "does", "doesn't", "doing", "don't", "down",
+	"during", "each", "either", "else", "enough", "even", "ever", "every", "few", "for", "from", "further",
+	"get", "gets", "getting", "got", "had", "hadn't", "has", "hasn't", "have", "haven't", "having", "her",
+	"here", "hers", "herself", "him", "himself", "his", "how", "however", "i'd", "i'll", "i'm", "i've",
+	"into", "isn't", "it's", "its", "itself", "just", "let", "lot", "lots", "many", "may", "might", "more",
+	"most", "much", "must", "myself", "need", "never", "nor", "not", "now", "off", "often", "once", "one",
+	"only", "other", "others", "our", "ours", "ourselves", "out", "over", "own", "quite", "rather", "really",
+	"same", "see", "she", "should", "shouldn't", "since", "some", "something", "still", "such", "than",
+	"that", "that's", "the", "their", "theirs", "them", "themselves", "then", "there", "there's", "these",
+	"they", "they're", "thing", "things", "this", "those", "though", "through", "too", "under", "until",
+	"very", "was", "wasn't", "way", "we're", "well", "were", "weren't", "what", "when", "where", "which",
+	"while", "who", "whom", "why", "will", "with", "won't", "would", "wouldn't", "yet", "you", "you'll",
+	"you're", "your", "yours", "yourself",
+	// Words every course review uses
+	"course", "courses", "class", "classes", "take", "taking", "took", "taken",
+)
+
+func toSet(words ...string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		set[w] = true
+	}
+	return set
+}
@@ -0,0 +1,72 @@
+package keywords
+
+import (
+	"testing"
+	"yuplan/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtract_CountsEachReviewOnce(t *testing.T) {
+	keywords := Extract([]string{
+		"Heavy workload, heavy workload, HEAVY workload.",
+		"The workload was fair",
+		"Great labs",
+	}, 10)
+
+	assert.Equal(t, []models.Keyword{
+		{Term: "workload", Count: 2},
+	}, keywords)
+}
+
+func TestExtract_Bigrams(t *testing.T) {
+	keywords := Extract([]string{
+		"Go to office hours! The midterm was hard.",
+		"Office hours saved me; midterm was easy",
+		"Office space is nice. The final exam was hard",
+		"final exam covers everything",
+	}, 10)
+
+	assert.Equal(t, []models.Keyword{
+		{Term: "office", Count: 3}, // also used outside "office hours"
+		{Term: "final exam", Count: 2},
+		{Term: "hard", Count: 2},
+		{Term: "midterm", Count: 2},
+		{Term: "office hours", Count: 2},
+	}, keywords)
+}
+
+func TestExtract_BigramsDoNotSpanPunctuationOrStopWords(t *testing.T) {
+	keywords := Extract([]string{
+		"Assignments, projects and the exam",
+		"Assignments. Projects with an exam",
+	}, 10)
+
+	for _, k := range keywords {
+		assert.NotEqual(t, "assignments projects", k.Term)
+		assert.NotEqual(t, "projects exam", k.Term)
+	}
+	assert.Len(t, keywords, 3)
+}
+
+func TestExtract_FiltersNoise(t *testing.T) {
+	keywords := Extract([]string{
+		"I took this course in 2024 and it's a lot of C++ and EECS",
+		"Took the class in 2024, C++ is used, it's OK",
+	}, 10)
+
+	assert.Equal(t, []models.Keyword{{Term: "c++", Count: 2}}, keywords)
+}
+
+func TestExtract_Possessives(t *testing.T) {
+	keywords := Extract([]string{"The professor's notes", "Professor’s slides and notes"}, 10)
+
+	assert.Contains(t, keywords, models.Keyword{Term: "professor", Count: 2})
+	assert.Contains(t, keywords, models.Keyword{Term: "notes", Count: 2})
+}
+
+func TestExtract_Limit(t *testing.T) {
+	texts := []string{"alpha beta gamma delta", "alpha beta gamma delta"}
+	assert.Len(t, Extract(texts, 2), 2)
+	assert.Empty(t, Extract(nil, 10))
+}
@@ -0,0 +1,37 @@
+// Package logging builds the JSON logger the API writes to and carries each
+// request's ID on its context, so anything logged while serving a request can
+// be matched to its access log line.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+)
+
+type requestIDKey struct{}
+
+// New returns a logger writing one JSON object per line to w. Installed with
+// slog.SetDefault it also takes over the standard log package's output.
+func New(w io.Writer) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(w, nil))
+}
+
+// WithRequestID returns ctx carrying the request ID id.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID on ctx, or "" outside a request.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// FromContext returns the default logger, tagged with ctx's request ID if it has one.
+func FromContext(ctx context.Context) *slog.Logger {
+	if id := RequestID(ctx); id != "" {
+		return slog.Default().With("request_id", id)
+	}
+	return slog.Default()
+}
@@ -0,0 +1,38 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromContext_TagsRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(New(&buf))
+	defer slog.SetDefault(previous)
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	assert.Equal(t, "req-1", RequestID(ctx))
+	FromContext(ctx).Error("query failed", "error", "timeout")
+
+	var line map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, "ERROR", line["level"])
+	assert.Equal(t, "query failed", line["msg"])
+	assert.Equal(t, "req-1", line["request_id"])
+
+	// Outside a request nothing is tagged, and the log package goes through slog too
+	buf.Reset()
+	assert.Empty(t, RequestID(context.Background()))
+	log.Printf("digest sent")
+	line = nil
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, "digest sent", line["msg"])
+	assert.NotContains(t, line, "request_id")
+}
@@ -0,0 +1,129 @@
+// Package mailer sends plain-text email, over SMTP or, when no server is
+// configured, to the log.
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Message is a plain-text email to one recipient.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends email.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// SMTP sends mail through an SMTP server, upgrading to TLS with STARTTLS
+// when the server offers it.
+type SMTP struct {
+	addr     string // host:port
+	host     string
+	username string // empty skips authentication
+	password string
+	from     string
+}
+
+func NewSMTP(host string, port int, username, password, from string) *SMTP {
+	return &SMTP{
+		addr:     net.JoinHostPort(host, strconv.Itoa(port)),
+		host:     host,
+		username: username,
+		password: password,
+		from:     from,
+	}
+}
+
+// Send delivers msg, giving up when ctx is done.
+func (m *SMTP) Send(ctx context.Context, msg Message) error {
+	from, err := mail.ParseAddress(m.from)
+	if err != nil {
+		return fmt.Errorf("invalid sender %q: %w", m.from, err)
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", m.addr)
+	if err != nil {
+		return fmt.Errorf("connect to SMTP server: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		return fmt.Errorf("SMTP greeting: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: m.host, MinVersion: tls.VersionTLS12}); err != nil {
+			return fmt.Errorf("SMTP STARTTLS: %w", err)
+		}
+	}
+	if m.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", m.username, m.password, m.host)); err != nil {
+			return fmt.Errorf("SMTP auth: %w", err)
+		}
+	}
+	if err := client.Mail(from.Address); err != nil {
+		return fmt.Errorf("SMTP MAIL FROM: %w", err)
+	}
+	if err := client.Rcpt(msg.To); err != nil {
+		return fmt.Errorf("SMTP RCPT TO: %w", err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("SMTP DATA: %w", err)
+	}
+	if _, err := w.Write(format(m.from, msg, time.Now())); err != nil {
+		return fmt.Errorf("write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("send message: %w", err)
+	}
+	return client.Quit()
+}
+
+// format renders msg as an RFC 5322 message with CRLF line endings.
+func format(from string, msg Message, now time.Time) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	body := strings.ReplaceAll(strings.ReplaceAll(msg.Body, "\r\n", "\n"), "\n", "\r\n")
+	b.WriteString(body)
+	if !strings.HasSuffix(body, "\r\n") {
+		b.WriteString("\r\n")
+	}
+	return b.Bytes()
+}
+
+// Log writes messages to the log instead of sending them, for development
+// and deployments without an SMTP server.
+type Log struct{}
+
+func (Log) Send(ctx context.Context, msg Message) error {
+	log.Printf("email not sent (no SMTP server) to %s: %q\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
@@ -0,0 +1,97 @@
+package mailer
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeSMTP accepts one message and returns what the client sent.
+func fakeSMTP(t *testing.T) (addr string, received chan string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	received = make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+		var transcript strings.Builder
+		reply("220 fake ESMTP")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			transcript.WriteString(line)
+			switch cmd := strings.ToUpper(strings.TrimSpace(line)); {
+			case strings.HasPrefix(cmd, "EHLO"):
+				reply("250 fake")
+			case cmd == "DATA":
+				reply("354 go ahead")
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if line == ".\r\n" {
+						break
+					}
+					transcript.WriteString(line)
+				}
+				reply("250 queued")
+			case cmd == "QUIT":
+				reply("221 bye")
+				received <- transcript.String()
+				return
+			default:
+				reply("250 ok")
+			}
+		}
+	}()
+	return ln.Addr().String(), received
+}
+
+func TestSMTPSend(t *testing.T) {
+	addr, received := fakeSMTP(t)
+	host, port, _ := net.SplitHostPort(addr)
+	var portNum int
+	for _, c := range port {
+		portNum = portNum*10 + int(c-'0')
+	}
+
+	m := NewSMTP(host, portNum, "", "", "YU Plan <no-reply@yuplan.ca>")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := m.Send(ctx, Message{To: "student@my.yorku.ca", Subject: "Confirm your review", Body: "Line one\nLine two"})
+	assert.NoError(t, err)
+
+	transcript := <-received
+	assert.Contains(t, transcript, "MAIL FROM:<no-reply@yuplan.ca>")
+	assert.Contains(t, transcript, "RCPT TO:<student@my.yorku.ca>")
+	assert.Contains(t, transcript, "Subject: Confirm your review\r\n")
+	assert.Contains(t, transcript, "\r\n\r\nLine one\r\nLine two\r\n")
+}
+
+func TestSMTPSend_BadSender(t *testing.T) {
+	err := NewSMTP("127.0.0.1", 25, "", "", "not an address").Send(context.Background(), Message{To: "a@b.c"})
+	assert.ErrorContains(t, err, "invalid sender")
+}
+
+func TestFormat(t *testing.T) {
+	msg := format("a@yuplan.ca", Message{To: "b@yorku.ca", Subject: "Café", Body: "hi"}, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	assert.Equal(t, "From: a@yuplan.ca\r\nTo: b@yorku.ca\r\nSubject: =?utf-8?q?Caf=C3=A9?=\r\n"+
+		"Date: Fri, 02 Jan 2026 03:04:05 +0000\r\nMIME-Version: 1.0\r\nContent-Type: text/plain; charset=utf-8\r\n\r\nhi\r\n", string(msg))
+}
@@ -0,0 +1,92 @@
+// Package markdown renders the small markdown subset allowed in review text.
+//
+// Supported: paragraphs, line breaks, **bold**, *italic* / _italic_, and
+// "-", "*" or "1." lists. Everything else, including links, images and raw
+// HTML, is rendered as escaped text. Input is HTML-escaped before any markup
+// is applied and only fixed tags are ever emitted, so the output is safe to
+// insert into a page without further sanitizing.
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	boldPattern       = regexp.MustCompile(`\*\*([^*\n]+?)\*\*`)
+	italicStarPattern = regexp.MustCompile(`\*([^*\n]+?)\*`)
+	italicUndPattern  = regexp.MustCompile(`(^|[^\w])_([^_\n]+?)_([^\w]|$)`)
+	unorderedItem     = regexp.MustCompile(`^\s*[-*]\s+(.*)$`)
+	orderedItem       = regexp.MustCompile(`^\s*\d+[.)]\s+(.*)$`)
+)
+
+// Render converts review text to sanitized HTML.
+func Render(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	var out strings.Builder
+	var paragraph []string
+	listTag := ""
+
+	flushParagraph := func() {
+		if len(paragraph) == 0 {
+			return
+		}
+		out.WriteString("<p>")
+		out.WriteString(strings.Join(paragraph, "<br>"))
+		out.WriteString("</p>")
+		paragraph = nil
+	}
+	closeList := func() {
+		if listTag != "" {
+			out.WriteString("</" + listTag + ">")
+			listTag = ""
+		}
+	}
+	openList := func(tag string) {
+		if listTag == tag {
+			return
+		}
+		closeList()
+		out.WriteString("<" + tag + ">")
+		listTag = tag
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			flushParagraph()
+			closeList()
+			continue
+		}
+
+		if m := unorderedItem.FindStringSubmatch(line); m != nil {
+			flushParagraph()
+			openList("ul")
+			out.WriteString("<li>" + inline(m[1]) + "</li>")
+			continue
+		}
+		if m := orderedItem.FindStringSubmatch(line); m != nil {
+			flushParagraph()
+			openList("ol")
+			out.WriteString("<li>" + inline(m[1]) + "</li>")
+			continue
+		}
+
+		closeList()
+		paragraph = append(paragraph, inline(strings.TrimSpace(line)))
+	}
+	flushParagraph()
+	closeList()
+
+	return out.String()
+}
+
+// inline escapes a line and applies emphasis.
+func inline(s string) string {
+	s = html.EscapeString(s)
+	s = boldPattern.ReplaceAllString(s, "<strong>$1</strong>")
+	s = italicStarPattern.ReplaceAllString(s, "<em>$1</em>")
+	s = italicUndPattern.ReplaceAllString(s, "$1<em>$2</em>$3")
+	return s
+}
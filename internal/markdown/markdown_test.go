@@ -0,0 +1,74 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender_Formatting(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "plain text", input: "Great course", expected: "<p>Great course</p>"},
+		{name: "bold", input: "**Hard** but fair", expected: "<p><strong>Hard</strong> but fair</p>"},
+		{name: "italic star", input: "really *fun*", expected: "<p>really <em>fun</em></p>"},
+		{name: "italic underscore", input: "really _fun_ stuff", expected: "<p>really <em>fun</em> stuff</p>"},
+		{name: "snake_case left alone", input: "use snake_case_names", expected: "<p>use snake_case_names</p>"},
+		{name: "line break", input: "one\ntwo", expected: "<p>one<br>two</p>"},
+		{name: "paragraphs", input: "one\n\ntwo", expected: "<p>one</p><p>two</p>"},
+		{name: "unordered list", input: "Pros:\n- labs\n* tests", expected: "<p>Pros:</p><ul><li>labs</li><li>tests</li></ul>"},
+		{name: "ordered list", input: "1. read\n2) practice", expected: "<ol><li>read</li><li>practice</li></ol>"},
+		{name: "list then paragraph", input: "- a\n\nafter", expected: "<ul><li>a</li></ul><p>after</p>"},
+		{name: "empty", input: "", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Render(tt.input))
+		})
+	}
+}
+
+func TestRender_XSSPayloads(t *testing.T) {
+	payloads := []string{
+		`<script>alert(1)</script>`,
+		`<img src=x onerror=alert(1)>`,
+		`[click](javascript:alert(1))`,
+		`![img](http://evil.example/x.png)`,
+		`**<svg onload=alert(1)>**`,
+		`<a href="http://evil.example">link</a>`,
+		`"><iframe src=//evil.example>`,
+		`- <b onmouseover=alert(1)>item</b>`,
+		"*<style>body{display:none}</style>*",
+	}
+
+	for _, payload := range payloads {
+		t.Run(payload, func(t *testing.T) {
+			out := Render(payload)
+
+			assert.NotContains(t, out, "<script")
+			assert.NotContains(t, out, "<img")
+			assert.NotContains(t, out, "<svg")
+			assert.NotContains(t, out, "<a ")
+			assert.NotContains(t, out, "<iframe")
+			assert.NotContains(t, out, "<style")
+			assert.NotContains(t, out, "<b ")
+
+			// Only allowlisted tags are emitted
+			stripped := out
+			for _, tag := range []string{"<p>", "</p>", "<br>", "<strong>", "</strong>", "<em>", "</em>", "<ul>", "</ul>", "<ol>", "</ol>", "<li>", "</li>"} {
+				stripped = strings.ReplaceAll(stripped, tag, "")
+			}
+			assert.NotContains(t, stripped, "<")
+			assert.NotContains(t, stripped, ">")
+		})
+	}
+}
+
+func TestRender_LinksAreText(t *testing.T) {
+	assert.Equal(t, "<p>[docs](http://example.com)</p>", Render("[docs](http://example.com)"))
+}
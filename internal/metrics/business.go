@@ -0,0 +1,49 @@
+package metrics
+
+// Business counts the product events that would otherwise take ad-hoc SQL to
+// answer. Ratios such as verification conversion or the zero-result search
+// rate are left to the query, e.g.
+//
+//	sum(rate(yuplan_review_events_total{event="verified"}[1d]))
+//	  / sum(rate(yuplan_review_events_total{event="created"}[1d]))
+type Business struct {
+	reviewEvents *Counter
+	schedules    *Counter
+	searches     *Counter
+}
+
+// NewBusiness registers the business counters on r.
+func NewBusiness(r *Registry) *Business {
+	return &Business{
+		reviewEvents: r.Counter("yuplan_review_events_total",
+			"Review lifecycle events (created, verified, edited, moderated, ...) by event.", "event"),
+		schedules: r.Counter("yuplan_schedule_generations_total",
+			"Timetable generation requests answered, by whether any timetable fit (found or none).", "outcome"),
+		searches: r.Counter("yuplan_course_searches_total",
+			"First-page course searches, by the path that answered (exact code or fuzzy) and whether they returned anything (some or zero).", "path", "results"),
+	}
+}
+
+// ReviewEvent counts a review lifecycle event, one of models.ReviewEvents.
+func (b *Business) ReviewEvent(event string) {
+	b.reviewEvents.Inc(event)
+}
+
+// ScheduleGenerated counts a timetable generation that found timetables that fit.
+func (b *Business) ScheduleGenerated(found int) {
+	if found > 0 {
+		b.schedules.Inc("found")
+	} else {
+		b.schedules.Inc("none")
+	}
+}
+
+// Searched counts a course search answered by path, one of search.Path*,
+// that returned results courses.
+func (b *Business) Searched(path string, results int) {
+	if results > 0 {
+		b.searches.Inc(path, "some")
+	} else {
+		b.searches.Inc(path, "zero")
+	}
+}
@@ -0,0 +1,42 @@
+package metrics
+
+// Cache counts read-through cache lookups, so the hit rate can be watched:
+//
+//	sum by (namespace) (rate(yuplan_cache_lookups_total{result="hit"}[5m]))
+//	  / sum by (namespace) (rate(yuplan_cache_lookups_total[5m]))
+//
+// and invalidations, by whether single keys or whole namespaces were dropped
+// and how often one failed and left stale values to be served.
+type Cache struct {
+	lookups       *Counter
+	invalidations *Counter
+	stale         *Counter
+}
+
+// NewCache registers the cache counters on r.
+func NewCache(r *Registry) *Cache {
+	return &Cache{
+		lookups: r.Counter("yuplan_cache_lookups_total",
+			"Read-through cache lookups, by namespace and result (hit, miss or error).", "namespace", "result"),
+		invalidations: r.Counter("yuplan_cache_invalidations_total",
+			"Cache entries dropped by key, or namespaces dropped whole, by namespace and scope (key or namespace).", "namespace", "scope"),
+		stale: r.Counter("yuplan_cache_stale_fallbacks_total",
+			"Invalidations that failed, leaving cached values to be served until a later flush or their TTL, by namespace.", "namespace"),
+	}
+}
+
+// CacheLookup counts a lookup in namespace with result, one of cache.Result*.
+func (c *Cache) CacheLookup(namespace, result string) {
+	c.lookups.Inc(namespace, result)
+}
+
+// CacheInvalidated counts n keys, or one namespace, dropped from namespace;
+// scope is one of cache.Scope*.
+func (c *Cache) CacheInvalidated(namespace, scope string, n int) {
+	c.invalidations.Add(float64(n), namespace, scope)
+}
+
+// CacheStale counts an invalidation of namespace that failed.
+func (c *Cache) CacheStale(namespace string) {
+	c.stale.Inc(namespace)
+}
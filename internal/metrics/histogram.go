@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// LatencyBuckets are upper bounds in seconds suiting API request latencies,
+// from a cached read to a slow aggregate.
+var LatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Histogram counts observations into buckets, split into one series per
+// combination of label values, so quantiles can be estimated with
+// histogram_quantile().
+type Histogram struct {
+	name    string
+	help    string
+	labels  []string
+	buckets []float64 // upper bounds, ascending
+
+	mu     sync.Mutex
+	values map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	labelValues []string
+	counts      []uint64 // per bucket, not cumulative; the last is +Inf
+	sum         float64
+	count       uint64
+}
+
+// Histogram registers a histogram named name with the given bucket upper
+// bounds, which must be ascending, and label names.
+func (r *Registry) Histogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	h := &Histogram{name: name, help: help, labels: labels, buckets: buckets, values: map[string]*histogramSeries{}}
+	r.register(h)
+	return h
+}
+
+// Observe records v in the series with labelValues, given in the order the
+// labels were registered.
+func (h *Histogram) Observe(v float64, labelValues ...string) {
+	values := make([]string, len(h.labels))
+	copy(values, labelValues)
+	key := seriesKey(values)
+	bucket := sort.SearchFloat64s(h.buckets, v)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.values[key]
+	if !ok {
+		s = &histogramSeries{labelValues: values, counts: make([]uint64, len(h.buckets)+1)}
+		h.values[key] = s
+	}
+	s.counts[bucket]++
+	s.sum += v
+	s.count++
+}
+
+// Count is how many observations the series with labelValues has.
+func (h *Histogram) Count(labelValues ...string) uint64 {
+	values := make([]string, len(h.labels))
+	copy(values, labelValues)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if s, ok := h.values[seriesKey(values)]; ok {
+		return s.count
+	}
+	return 0
+}
+
+func (h *Histogram) writeText(w io.Writer) error {
+	h.mu.Lock()
+	all := make([]histogramSeries, 0, len(h.values))
+	for _, s := range h.values {
+		copied := *s
+		copied.counts = append([]uint64(nil), s.counts...)
+		all = append(all, copied)
+	}
+	h.mu.Unlock()
+	sort.Slice(all, func(i, j int) bool {
+		return seriesKey(all[i].labelValues) < seriesKey(all[j].labelValues)
+	})
+
+	if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", h.name, escapeHelp(h.help), h.name); err != nil {
+		return err
+	}
+	for _, s := range all {
+		var cumulative uint64
+		for i, count := range s.counts {
+			cumulative += count
+			le := "+Inf"
+			if i < len(h.buckets) {
+				le = strconv.FormatFloat(h.buckets[i], 'g', -1, 64)
+			}
+			if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, labelText(h.labels, s.labelValues, "le", le), cumulative); err != nil {
+				return err
+			}
+		}
+		labels := labelText(h.labels, s.labelValues)
+		if _, err := fmt.Fprintf(w, "%s_sum%s %g\n%s_count%s %d\n", h.name, labels, s.sum, h.name, labels, s.count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+)
+
+// HTTP counts and times requests by route, and counts the requests rate
+// limiters turned away, for per-route dashboards:
+//
+//	histogram_quantile(0.99, sum by (le, route) (rate(yuplan_http_request_duration_seconds_bucket[5m])))
+type HTTP struct {
+	requests    *Counter
+	duration    *Histogram
+	rateLimited *Counter
+}
+
+// NewHTTP registers the HTTP metrics on r.
+func NewHTTP(r *Registry) *HTTP {
+	return &HTTP{
+		requests: r.Counter("yuplan_http_requests_total",
+			"HTTP requests answered, by method, route pattern and status code.", "method", "route", "status"),
+		duration: r.Histogram("yuplan_http_request_duration_seconds",
+			"Time to answer HTTP requests, by method and route pattern.", LatencyBuckets, "method", "route"),
+		rateLimited: r.Counter("yuplan_rate_limited_total",
+			"Requests rejected with 429, by rate limiter (default or lite).", "limiter"),
+	}
+}
+
+// ObserveRequest counts a request to route, its pattern such as
+// /api/v1/courses/:course_code, answered with status after elapsed.
+func (h *HTTP) ObserveRequest(method, route string, status int, elapsed time.Duration) {
+	h.requests.Inc(method, route, strconv.Itoa(status))
+	h.duration.Observe(elapsed.Seconds(), method, route)
+}
+
+// RateLimited counts a request limiter turned away.
+func (h *HTTP) RateLimited(limiter string) {
+	h.rateLimited.Inc(limiter)
+}
@@ -0,0 +1,188 @@
+// Package metrics counts events and times requests in process and writes them
+// in the Prometheus text exposition format. Counts are per instance and start
+// from zero on restart; Prometheus' rate() and increase() account for both.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Registry holds the metrics served on one metrics endpoint.
+type Registry struct {
+	mu         sync.Mutex
+	collectors []collector
+}
+
+type collector interface {
+	writeText(w io.Writer) error
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Counter registers a counter named name with the given label names.
+func (r *Registry) Counter(name, help string, labels ...string) *Counter {
+	c := &Counter{name: name, help: help, labels: labels, values: map[string]*series{}}
+	r.register(c)
+	return c
+}
+
+// GaugeFunc registers a gauge whose value is read from fn at every scrape,
+// for values something else already tracks, such as pool sizes.
+func (r *Registry) GaugeFunc(name, help string, fn func() float64) {
+	r.register(&funcMetric{name: name, help: help, kind: "gauge", fn: fn})
+}
+
+// CounterFunc registers a counter read from fn at every scrape. fn must never
+// go down while the process runs.
+func (r *Registry) CounterFunc(name, help string, fn func() float64) {
+	r.register(&funcMetric{name: name, help: help, kind: "counter", fn: fn})
+}
+
+func (r *Registry) register(c collector) {
+	r.mu.Lock()
+	r.collectors = append(r.collectors, c)
+	r.mu.Unlock()
+}
+
+// WriteText writes every metric in the Prometheus text format, in the order
+// they were registered, with series sorted by label values.
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	collectors := append([]collector(nil), r.collectors...)
+	r.mu.Unlock()
+
+	for _, c := range collectors {
+		if err := c.writeText(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Counter is a monotonically increasing count, split into one series per
+// combination of label values.
+type Counter struct {
+	name   string
+	help   string
+	labels []string
+
+	mu     sync.Mutex
+	values map[string]*series
+}
+
+type series struct {
+	labelValues []string
+	value       float64
+}
+
+// Inc adds one to the series with labelValues, given in the order the labels
+// were registered. Missing values are empty and extra ones are ignored.
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add adds n, which must not be negative, to the series with labelValues.
+func (c *Counter) Add(n float64, labelValues ...string) {
+	if n < 0 {
+		return
+	}
+	values := make([]string, len(c.labels))
+	copy(values, labelValues)
+	key := seriesKey(values)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.values[key]
+	if !ok {
+		s = &series{labelValues: values}
+		c.values[key] = s
+	}
+	s.value += n
+}
+
+// Value is the current count of the series with labelValues.
+func (c *Counter) Value(labelValues ...string) float64 {
+	values := make([]string, len(c.labels))
+	copy(values, labelValues)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if s, ok := c.values[seriesKey(values)]; ok {
+		return s.value
+	}
+	return 0
+}
+
+func (c *Counter) writeText(w io.Writer) error {
+	c.mu.Lock()
+	all := make([]series, 0, len(c.values))
+	for _, s := range c.values {
+		all = append(all, *s)
+	}
+	c.mu.Unlock()
+	sort.Slice(all, func(i, j int) bool {
+		return seriesKey(all[i].labelValues) < seriesKey(all[j].labelValues)
+	})
+
+	if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", c.name, escapeHelp(c.help), c.name); err != nil {
+		return err
+	}
+	// A counter without labels is reported as zero before its first increment
+	if len(c.labels) == 0 && len(all) == 0 {
+		all = append(all, series{})
+	}
+	for _, s := range all {
+		if _, err := fmt.Fprintf(w, "%s%s %g\n", c.name, labelText(c.labels, s.labelValues), s.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// labelText renders labels and their values, plus any extra name="value"
+// pairs such as a histogram's le, as {a="1",b="2"}.
+func labelText(labels, values []string, extra ...string) string {
+	pairs := make([]string, 0, len(labels)+len(extra)/2)
+	for i, label := range labels {
+		pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", label, escapeLabel(values[i])))
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", extra[i], escapeLabel(extra[i+1])))
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// seriesKey identifies a series by its label values.
+func seriesKey(values []string) string {
+	return strings.Join(values, "\xff")
+}
+
+// funcMetric is a gauge or counter without labels whose value is read at scrape time.
+type funcMetric struct {
+	name string
+	help string
+	kind string
+	fn   func() float64
+}
+
+func (m *funcMetric) writeText(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %g\n", m.name, escapeHelp(m.help), m.name, m.kind, m.name, m.fn())
+	return err
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string  { return helpEscaper.Replace(s) }
+func escapeLabel(s string) string { return labelEscaper.Replace(s) }
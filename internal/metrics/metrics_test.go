@@ -0,0 +1,134 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry_WriteText(t *testing.T) {
+	r := NewRegistry()
+	plain := r.Counter("yuplan_plain_total", "A counter without labels.")
+	labelled := r.Counter("yuplan_labelled_total", "A counter\nwith labels.", "kind")
+
+	labelled.Inc("b")
+	labelled.Add(2, "a")
+	labelled.Inc(`say "hi"`)
+
+	var out strings.Builder
+	assert.NoError(t, r.WriteText(&out))
+	assert.Equal(t, `# HELP yuplan_plain_total A counter without labels.
+# TYPE yuplan_plain_total counter
+yuplan_plain_total 0
+# HELP yuplan_labelled_total A counter\nwith labels.
+# TYPE yuplan_labelled_total counter
+yuplan_labelled_total{kind="a"} 2
+yuplan_labelled_total{kind="b"} 1
+yuplan_labelled_total{kind="say \"hi\""} 1
+`, out.String())
+
+	plain.Inc()
+	assert.Equal(t, 1.0, plain.Value())
+}
+
+func TestCounter_IgnoresNegativeAdds(t *testing.T) {
+	c := NewRegistry().Counter("yuplan_test_total", "Test.")
+	c.Add(3)
+	c.Add(-1)
+	assert.Equal(t, 3.0, c.Value())
+}
+
+func TestBusiness(t *testing.T) {
+	b := NewBusiness(NewRegistry())
+
+	b.ReviewEvent("created")
+	b.ReviewEvent("created")
+	b.ScheduleGenerated(0)
+	b.ScheduleGenerated(4)
+	b.Searched("fuzzy", 0)
+	b.Searched("fuzzy", 0)
+	b.Searched("fuzzy", 12)
+	b.Searched("exact", 1)
+
+	assert.Equal(t, 2.0, b.reviewEvents.Value("created"))
+	assert.Equal(t, 1.0, b.schedules.Value("none"))
+	assert.Equal(t, 1.0, b.schedules.Value("found"))
+	assert.Equal(t, 2.0, b.searches.Value("fuzzy", "zero"))
+	assert.Equal(t, 1.0, b.searches.Value("fuzzy", "some"))
+	assert.Equal(t, 1.0, b.searches.Value("exact", "some"))
+}
+
+func TestCache(t *testing.T) {
+	c := NewCache(NewRegistry())
+
+	c.CacheLookup("courses", "hit")
+	c.CacheLookup("courses", "hit")
+	c.CacheLookup("courses", "miss")
+
+	assert.Equal(t, 2.0, c.lookups.Value("courses", "hit"))
+	assert.Equal(t, 1.0, c.lookups.Value("courses", "miss"))
+
+	c.CacheInvalidated("courses", "key", 3)
+	c.CacheInvalidated("instructors", "namespace", 1)
+	c.CacheStale("sections")
+
+	assert.Equal(t, 3.0, c.invalidations.Value("courses", "key"))
+	assert.Equal(t, 1.0, c.invalidations.Value("instructors", "namespace"))
+	assert.Equal(t, 1.0, c.stale.Value("sections"))
+}
+
+func TestHistogram_WriteText(t *testing.T) {
+	r := NewRegistry()
+	h := r.Histogram("yuplan_test_seconds", "A histogram.", []float64{0.1, 1}, "route")
+
+	h.Observe(0.05, "/a")
+	h.Observe(0.1, "/a")
+	h.Observe(3, "/a")
+
+	var out strings.Builder
+	assert.NoError(t, r.WriteText(&out))
+	assert.Equal(t, `# HELP yuplan_test_seconds A histogram.
+# TYPE yuplan_test_seconds histogram
+yuplan_test_seconds_bucket{route="/a",le="0.1"} 2
+yuplan_test_seconds_bucket{route="/a",le="1"} 2
+yuplan_test_seconds_bucket{route="/a",le="+Inf"} 3
+yuplan_test_seconds_sum{route="/a"} 3.15
+yuplan_test_seconds_count{route="/a"} 3
+`, out.String())
+}
+
+func TestHTTP(t *testing.T) {
+	h := NewHTTP(NewRegistry())
+
+	h.ObserveRequest("GET", "/api/v1/courses/:course_code", 200, 30*time.Millisecond)
+	h.ObserveRequest("GET", "/api/v1/courses/:course_code", 404, time.Millisecond)
+	h.RateLimited("lite")
+
+	assert.Equal(t, 1.0, h.requests.Value("GET", "/api/v1/courses/:course_code", "200"))
+	assert.Equal(t, 1.0, h.requests.Value("GET", "/api/v1/courses/:course_code", "404"))
+	assert.Equal(t, uint64(2), h.duration.Count("GET", "/api/v1/courses/:course_code"))
+	assert.Equal(t, 1.0, h.rateLimited.Value("lite"))
+}
+
+type fakePoolStats struct{}
+
+func (fakePoolStats) TotalConns() int32              { return 4 }
+func (fakePoolStats) IdleConns() int32               { return 3 }
+func (fakePoolStats) AcquiredConns() int32           { return 1 }
+func (fakePoolStats) MaxConns() int32                { return 10 }
+func (fakePoolStats) AcquireCount() int64            { return 120 }
+func (fakePoolStats) EmptyAcquireCount() int64       { return 2 }
+func (fakePoolStats) AcquireDuration() time.Duration { return 1500 * time.Millisecond }
+
+func TestPool(t *testing.T) {
+	r := NewRegistry()
+	NewPool(r, func() PoolStats { return fakePoolStats{} })
+
+	var out strings.Builder
+	assert.NoError(t, r.WriteText(&out))
+	assert.Contains(t, out.String(), "# TYPE yuplan_db_pool_connections gauge\nyuplan_db_pool_connections 4\n")
+	assert.Contains(t, out.String(), "# TYPE yuplan_db_pool_acquires_total counter\nyuplan_db_pool_acquires_total 120\n")
+	assert.Contains(t, out.String(), "yuplan_db_pool_acquire_seconds_total 1.5\n")
+}
@@ -0,0 +1,34 @@
+package metrics
+
+import "time"
+
+// PoolStats is a snapshot of a database connection pool. Implemented by pgxpool.Stat.
+type PoolStats interface {
+	TotalConns() int32
+	IdleConns() int32
+	AcquiredConns() int32
+	MaxConns() int32
+	AcquireCount() int64
+	EmptyAcquireCount() int64
+	AcquireDuration() time.Duration
+}
+
+// NewPool registers gauges and counters read from the pool at every scrape. A
+// rising yuplan_db_pool_empty_acquires_total means requests are waiting for a
+// connection.
+func NewPool(r *Registry, stat func() PoolStats) {
+	r.GaugeFunc("yuplan_db_pool_connections", "Open database connections, idle or in use.",
+		func() float64 { return float64(stat().TotalConns()) })
+	r.GaugeFunc("yuplan_db_pool_idle_connections", "Open database connections not in use.",
+		func() float64 { return float64(stat().IdleConns()) })
+	r.GaugeFunc("yuplan_db_pool_acquired_connections", "Database connections in use.",
+		func() float64 { return float64(stat().AcquiredConns()) })
+	r.GaugeFunc("yuplan_db_pool_max_connections", "Most database connections the pool will open.",
+		func() float64 { return float64(stat().MaxConns()) })
+	r.CounterFunc("yuplan_db_pool_acquires_total", "Connections acquired from the pool.",
+		func() float64 { return float64(stat().AcquireCount()) })
+	r.CounterFunc("yuplan_db_pool_empty_acquires_total", "Acquires that had to wait because no connection was idle.",
+		func() float64 { return float64(stat().EmptyAcquireCount()) })
+	r.CounterFunc("yuplan_db_pool_acquire_seconds_total", "Time spent acquiring connections.",
+		func() float64 { return stat().AcquireDuration().Seconds() })
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+	"yuplan/internal/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AccessLog writes one JSON line per request to logger, with the request ID
+// set by RequestID, filtered by the current log level: "warn" keeps only
+// 4xx/5xx responses and "error" only 5xx. level is checked on every request
+// so it can follow a config reload.
+func AccessLog(logger *slog.Logger, level func() string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		path := c.Request.URL.Path
+		c.Next()
+
+		status := c.Writer.Status()
+		if skipAccessLog(level(), status) {
+			return
+		}
+		attrs := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			slog.String("path", path),
+			slog.Int("status", status),
+			slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.String("client_ip", c.ClientIP()),
+			slog.Int("bytes", c.Writer.Size()),
+		}
+		if requestID := logging.RequestID(c.Request.Context()); requestID != "" {
+			attrs = append(attrs, slog.String("request_id", requestID))
+		}
+		if errs := c.Errors.ByType(gin.ErrorTypePrivate).String(); errs != "" {
+			attrs = append(attrs, slog.String("error", errs))
+		}
+		logger.LogAttrs(c.Request.Context(), accessLogLevel(status), "request", attrs...)
+	}
+}
+
+func accessLogLevel(status int) slog.Level {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return slog.LevelError
+	case status >= http.StatusBadRequest:
+		return slog.LevelWarn
+	default:
+		return slog.LevelInfo
+	}
+}
+
+func skipAccessLog(level string, status int) bool {
+	switch level {
+	case "warn":
+		return status < http.StatusBadRequest
+	case "error":
+		return status < http.StatusInternalServerError
+	default:
+		return false
+	}
+}
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"yuplan/internal/logging"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSkipAccessLog(t *testing.T) {
+	tests := []struct {
+		level  string
+		status int
+		skip   bool
+	}{
+		{"debug", http.StatusOK, false},
+		{"info", http.StatusOK, false},
+		{"warn", http.StatusOK, true},
+		{"warn", http.StatusNotFound, false},
+		{"error", http.StatusNotFound, true},
+		{"error", http.StatusInternalServerError, false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.skip, skipAccessLog(tt.level, tt.status), "level %s status %d", tt.level, tt.status)
+	}
+}
+
+func TestAccessLog_FollowsLevel(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var buf bytes.Buffer
+	level := "info"
+	router := gin.New()
+	router.Use(AccessLog(logging.New(&buf), func() string { return level }))
+	router.GET("/test", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/test", nil))
+	assert.Contains(t, buf.String(), "/test")
+
+	buf.Reset()
+	level = "warn"
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/test", nil))
+	assert.Empty(t, buf.String())
+}
+
+func TestAccessLog_JSONWithRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var buf bytes.Buffer
+	router := gin.New()
+	router.Use(RequestID(), AccessLog(logging.New(&buf), func() string { return "info" }))
+	router.GET("/courses/:code", func(c *gin.Context) { c.Status(http.StatusNotFound) })
+
+	req := httptest.NewRequest("GET", "/courses/EECS9999", nil)
+	req.Header.Set(RequestIDHeader, "lb-abc123")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var line map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, "WARN", line["level"])
+	assert.Equal(t, "GET", line["method"])
+	assert.Equal(t, "/courses/EECS9999", line["path"])
+	assert.Equal(t, float64(http.StatusNotFound), line["status"])
+	assert.Contains(t, line, "latency_ms")
+	assert.Equal(t, "lb-abc123", line["request_id"])
+	assert.Equal(t, "lb-abc123", w.Header().Get(RequestIDHeader))
+}
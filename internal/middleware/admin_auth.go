@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"yuplan/internal/admintoken"
+	"yuplan/internal/redact"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRole rejects callers CallerRole didn't find to have at least role:
+// 401 for callers without credentials, 403 for those whose role falls short.
+func RequireRole(role redact.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		caller := redact.RoleFrom(c.Request.Context())
+		if caller.Allows(role) {
+			c.Next()
+			return
+		}
+
+		if caller == redact.RolePublic {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or missing API key or admin token"})
+		} else {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Your role can't use this endpoint"})
+		}
+		c.Abort()
+	}
+}
+
+// Disabled rejects every request with 403 and message, for route groups
+// whose feature isn't configured.
+func Disabled(message string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusForbidden, gin.H{"error": message})
+		c.Abort()
+	}
+}
+
+// RequireBearerToken guards a route with a shared secret sent as
+// "Authorization: Bearer <token>", which is what scrapers such as Prometheus
+// send. An empty token disables the route.
+func RequireBearerToken(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Endpoint is disabled"})
+			c.Abort()
+			return
+		}
+
+		provided, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or missing bearer token"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// CallerRole records on the request context which redact.Role the caller has,
+// so responses strip the fields it may not see and RequireRole can guard
+// routes. Callers with the admin API key are admins; so is a caller sending
+// "Authorization: Bearer <jwt>" with a token tokens verifies, in the role its
+// claim names. Everyone else is public. tokens may be nil to accept only the
+// key. It never rejects a request: a bad token just leaves the caller public.
+func CallerRole(key string, tokens *admintoken.Signer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := redact.RolePublic
+		if key != "" && hasAPIKey(c, key) {
+			role = redact.RoleAdmin
+		} else if tokens != nil {
+			if bearer, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); ok {
+				if claims, err := tokens.Verify(bearer); err == nil && redact.Role(claims.Role).Known() {
+					role = redact.Role(claims.Role)
+				}
+			}
+		}
+		c.Request = c.Request.WithContext(redact.WithRole(c.Request.Context(), role))
+		c.Next()
+	}
+}
+
+func hasAPIKey(c *gin.Context, key string) bool {
+	provided := c.GetHeader("X-API-Key")
+	return subtle.ConstantTimeCompare([]byte(provided), []byte(key)) == 1
+}
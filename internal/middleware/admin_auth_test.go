@@ -0,0 +1,166 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"yuplan/internal/admintoken"
+	"yuplan/internal/redact"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newAdminRouter(key string, tokens *admintoken.Signer) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(CallerRole(key, tokens), RequireRole(redact.RoleAdmin))
+	router.GET("/admin", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "ok"})
+	})
+	return router
+}
+
+func signToken(t *testing.T, tokens *admintoken.Signer, role string) string {
+	t.Helper()
+	token, err := tokens.Sign(admintoken.Claims{Subject: "jdoe", Role: role, ExpiresAt: time.Now().Add(time.Hour).Unix()})
+	assert.NoError(t, err)
+	return token
+}
+
+func TestRequireRole(t *testing.T) {
+	tokens := admintoken.NewSigner("jwt-secret")
+	tests := []struct {
+		name           string
+		configuredKey  string
+		headerKey      string
+		authorization  string
+		expectedStatus int
+	}{
+		{name: "valid key", configuredKey: "s3cret", headerKey: "s3cret", expectedStatus: http.StatusOK},
+		{name: "wrong key", configuredKey: "s3cret", headerKey: "nope", expectedStatus: http.StatusUnauthorized},
+		{name: "missing key", configuredKey: "s3cret", headerKey: "", expectedStatus: http.StatusUnauthorized},
+		{name: "no key configured", configuredKey: "", headerKey: "", expectedStatus: http.StatusUnauthorized},
+		{name: "admin token", configuredKey: "", authorization: "Bearer " + signToken(t, tokens, "admin"), expectedStatus: http.StatusOK},
+		{name: "public token", configuredKey: "", authorization: "Bearer " + signToken(t, tokens, "public"), expectedStatus: http.StatusUnauthorized},
+		{name: "forged token", configuredKey: "", authorization: "Bearer " + signToken(t, admintoken.NewSigner("other"), "admin"), expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newAdminRouter(tt.configuredKey, tokens)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/admin", nil)
+			if tt.headerKey != "" {
+				req.Header.Set("X-API-Key", tt.headerKey)
+			}
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestRequireRole_ShortRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(redact.WithRole(c.Request.Context(), redact.RoleAdmin))
+	}, RequireRole("owner"))
+	router.GET("/owner", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/owner", nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestDisabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Disabled("Admin API is disabled"))
+	router.GET("/admin", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/admin", nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "Admin API is disabled")
+}
+
+func TestRequireBearerToken(t *testing.T) {
+	tests := []struct {
+		name           string
+		token          string
+		header         string
+		expectedStatus int
+	}{
+		{name: "valid token", token: "s3cret", header: "Bearer s3cret", expectedStatus: http.StatusOK},
+		{name: "wrong token", token: "s3cret", header: "Bearer nope", expectedStatus: http.StatusUnauthorized},
+		{name: "not bearer", token: "s3cret", header: "s3cret", expectedStatus: http.StatusUnauthorized},
+		{name: "disabled", token: "", header: "Bearer ", expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.GET("/metrics", RequireBearerToken(tt.token), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/metrics", nil)
+			req.Header.Set("Authorization", tt.header)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestCallerRole(t *testing.T) {
+	tokens := admintoken.NewSigner("jwt-secret")
+	tests := []struct {
+		name          string
+		configuredKey string
+		headerKey     string
+		authorization string
+		expectedRole  redact.Role
+	}{
+		{name: "valid key", configuredKey: "s3cret", headerKey: "s3cret", expectedRole: redact.RoleAdmin},
+		{name: "wrong key", configuredKey: "s3cret", headerKey: "nope", expectedRole: redact.RolePublic},
+		{name: "no key", configuredKey: "s3cret", headerKey: "", expectedRole: redact.RolePublic},
+		{name: "admin disabled", configuredKey: "", headerKey: "", expectedRole: redact.RolePublic},
+		{name: "admin token", configuredKey: "", authorization: "Bearer " + signToken(t, tokens, "admin"), expectedRole: redact.RoleAdmin},
+		{name: "unknown role", configuredKey: "", authorization: "Bearer " + signToken(t, tokens, "owner"), expectedRole: redact.RolePublic},
+		{name: "not a token", configuredKey: "", authorization: "Bearer s3cret", expectedRole: redact.RolePublic},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			var role redact.Role
+			router := gin.New()
+			router.Use(CallerRole(tt.configuredKey, tokens))
+			router.GET("/courses", func(c *gin.Context) {
+				role = redact.RoleFrom(c.Request.Context())
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/courses", nil)
+			if tt.headerKey != "" {
+				req.Header.Set("X-API-Key", tt.headerKey)
+			}
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.expectedRole, role)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeCaptcha struct {
+	err   error
+	calls int
+}
+
+func (f *fakeCaptcha) Verify(ctx context.Context, token, remoteIP string) (bool, error) {
+	f.calls++
+	return token == "solved", f.err
+}
+
+func TestRequireCaptcha(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		enabled        bool
+		token          string
+		err            error
+		expectedStatus int
+		expectedCalls  int
+	}{
+		{"solved", true, "solved", nil, http.StatusOK, 1},
+		{"missing token", true, "", nil, http.StatusBadRequest, 0},
+		{"failed token", true, "bot", nil, http.StatusForbidden, 1},
+		{"provider down", true, "solved", errors.New("timeout"), http.StatusServiceUnavailable, 1},
+		{"flag off", false, "", nil, http.StatusOK, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier := &fakeCaptcha{err: tt.err}
+			router := gin.New()
+			router.POST("/api/v1/reports", RequireCaptcha(verifier, func() bool { return tt.enabled }), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/reports", nil)
+			if tt.token != "" {
+				req.Header.Set("X-Captcha-Token", tt.token)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedCalls, verifier.calls)
+		})
+	}
+}
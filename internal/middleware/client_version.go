@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"yuplan/internal/clientversion"
+
+	"github.com/gin-gonic/gin"
+)
+
+// clientVersionExempt stays reachable from outdated builds so they can show an upgrade prompt.
+const clientVersionExempt = "/api/v1/meta/client"
+
+// ClientVersion rejects requests from app builds older than the minimum for their
+// platform with 426 Upgrade Required. Requests without X-Client-Version (browsers),
+// with an unparsable header, or from platforms without a minimum pass through.
+// minimums is checked on every request so it can follow a config reload.
+func ClientVersion(minimums func() map[string]string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader(clientversion.Header)
+		if header == "" || c.Request.URL.Path == clientVersionExempt {
+			c.Next()
+			return
+		}
+
+		platform, version, err := clientversion.ParseHeader(header)
+		if err != nil {
+			c.Next()
+			return
+		}
+		minimum, err := clientversion.Parse(minimums()[platform])
+		if err != nil || !version.Less(minimum) {
+			c.Next()
+			return
+		}
+
+		c.JSON(http.StatusUpgradeRequired, gin.H{
+			"error":       "This version of the app is no longer supported. Please update.",
+			"platform":    platform,
+			"min_version": minimum.String(),
+		})
+		c.Abort()
+	}
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientVersion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	minimums := map[string]string{"ios": "2.0.0", "android": "2.1"}
+	router := gin.New()
+	router.Use(ClientVersion(func() map[string]string { return minimums }))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/api/v1/courses", ok)
+	router.GET("/api/v1/meta/client", ok)
+
+	tests := []struct {
+		name           string
+		header         string
+		path           string
+		expectedStatus int
+	}{
+		{"no header", "", "/api/v1/courses", http.StatusOK},
+		{"current build", "ios/2.0.0", "/api/v1/courses", http.StatusOK},
+		{"newer build", "android/3.0", "/api/v1/courses", http.StatusOK},
+		{"outdated build", "ios/1.9.9", "/api/v1/courses", http.StatusUpgradeRequired},
+		{"outdated minor", "Android/2.0.5", "/api/v1/courses", http.StatusUpgradeRequired},
+		{"outdated build can read minimums", "ios/1.0.0", "/api/v1/meta/client", http.StatusOK},
+		{"platform without minimum", "web/0.1", "/api/v1/courses", http.StatusOK},
+		{"malformed header", "ios-nightly", "/api/v1/courses", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set("X-Client-Version", tt.header)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusUpgradeRequired {
+				assert.Contains(t, w.Body.String(), `"min_version"`)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CORS lets browsers on other origins make read-only calls under prefix. With
+// no origins any origin is allowed; otherwise only the listed ones are, and
+// others get no CORS headers. Preflight requests are answered here, since the
+// routes only register GET.
+func CORS(prefix string, origins []string) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(origins))
+	for _, o := range origins {
+		allowed[o] = true
+	}
+
+	return func(c *gin.Context) {
+		if !strings.HasPrefix(c.Request.URL.Path, prefix) {
+			c.Next()
+			return
+		}
+
+		origin := c.GetHeader("Origin")
+		switch {
+		case origin == "":
+		case len(allowed) == 0:
+			c.Header("Access-Control-Allow-Origin", "*")
+		case allowed[origin]:
+			c.Header("Access-Control-Allow-Origin", origin)
+			c.Header("Vary", "Origin")
+		default:
+			c.Header("Vary", "Origin")
+		}
+
+		if c.Request.Method == http.MethodOptions {
+			c.Header("Access-Control-Allow-Methods", "GET, OPTIONS")
+			c.Header("Access-Control-Allow-Headers", "Content-Type, X-Client-Version")
+			c.Header("Access-Control-Max-Age", "86400")
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORS(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		origins        []string
+		method         string
+		path           string
+		origin         string
+		expectedStatus int
+		expectedAllow  string
+	}{
+		{"any origin allowed", nil, http.MethodGet, "/api/v1/lite/courses", "https://w2prod.sis.yorku.ca", http.StatusOK, "*"},
+		{"listed origin echoed", []string{"chrome-extension://abc"}, http.MethodGet, "/api/v1/lite/courses", "chrome-extension://abc", http.StatusOK, "chrome-extension://abc"},
+		{"unlisted origin", []string{"chrome-extension://abc"}, http.MethodGet, "/api/v1/lite/courses", "https://evil.example", http.StatusOK, ""},
+		{"preflight answered", nil, http.MethodOptions, "/api/v1/lite/courses", "chrome-extension://abc", http.StatusNoContent, "*"},
+		{"other routes untouched", nil, http.MethodGet, "/api/v1/courses", "https://w2prod.sis.yorku.ca", http.StatusOK, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(CORS("/api/v1/lite", tt.origins))
+			ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+			router.GET("/api/v1/lite/courses", ok)
+			router.GET("/api/v1/courses", ok)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Origin", tt.origin)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedAllow, w.Header().Get("Access-Control-Allow-Origin"))
+		})
+	}
+}
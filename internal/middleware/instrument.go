@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requestObserver records answered requests. Implemented by metrics.HTTP.
+type requestObserver interface {
+	ObserveRequest(method, route string, status int, elapsed time.Duration)
+}
+
+// unmatchedRoute stands in for paths no route matched, so scanners probing
+// random URLs can't create a series each.
+const unmatchedRoute = "unmatched"
+
+// Instrument records every request by its route pattern, e.g.
+// /api/v1/courses/:course_code rather than the path it was called with.
+func Instrument(observer requestObserver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		observer.ObserveRequest(c.Request.Method, route, c.Writer.Status(), time.Since(start))
+	}
+}
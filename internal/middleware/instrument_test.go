@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordedRequest struct {
+	method, route string
+	status        int
+}
+
+type mockRequestObserver struct {
+	requests []recordedRequest
+}
+
+func (m *mockRequestObserver) ObserveRequest(method, route string, status int, elapsed time.Duration) {
+	m.requests = append(m.requests, recordedRequest{method, route, status})
+}
+
+func TestInstrument(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	observer := &mockRequestObserver{}
+	router := gin.New()
+	router.Use(Instrument(observer))
+	router.GET("/courses/:course_code", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for _, path := range []string{"/courses/EECS2030", "/courses/MATH1090", "/wp-login.php"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+
+	assert.Equal(t, []recordedRequest{
+		{"GET", "/courses/:course_code", http.StatusOK},
+		{"GET", "/courses/:course_code", http.StatusOK},
+		{"GET", unmatchedRoute, http.StatusNotFound},
+	}, observer.requests)
+}
@@ -0,0 +1,143 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LoadShedder rejects low-priority requests with 503 once the server is under
+// pressure, so reads that students actually need keep working during
+// enrollment-day spikes. Pressure means either too many requests in flight or
+// the recent p99 latency of high-priority requests exceeding the target.
+// Low-priority requests aren't sampled, so slow exports can't keep themselves
+// shed, nor can the quick 503s of shed requests hide real latency.
+type LoadShedder struct {
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int           // shed low-priority traffic above this many in-flight requests
+	targetP99   time.Duration // shed low-priority traffic while p99 latency exceeds this
+	samples     []time.Duration
+	next        int
+	filled      bool
+}
+
+const loadShedderSampleSize = 512
+
+// loadShedderMinSamples is how many high-priority requests have to be timed
+// before p99 latency is trusted, so a few slow requests after a restart don't
+// shed traffic.
+const loadShedderMinSamples = 100
+
+// lowPriorityKey carries a flag Shed sets for Track to skip the request's latency.
+type lowPriorityKey struct{}
+
+// NewLoadShedder creates a load shedder
+// maxInFlight: concurrent requests above which low-priority traffic is shed (e.g., 50)
+// targetP99: p99 latency SLO (e.g., 500ms)
+func NewLoadShedder(maxInFlight int, targetP99 time.Duration) *LoadShedder {
+	return &LoadShedder{
+		maxInFlight: maxInFlight,
+		targetP99:   targetP99,
+		samples:     make([]time.Duration, loadShedderSampleSize),
+	}
+}
+
+// Track measures every request. It must be registered before any Shed middleware.
+func (ls *LoadShedder) Track() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ls.mu.Lock()
+		ls.inFlight++
+		ls.mu.Unlock()
+
+		lowPriority := false
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), lowPriorityKey{}, &lowPriority))
+
+		start := time.Now()
+		c.Next()
+		elapsed := time.Since(start)
+
+		ls.mu.Lock()
+		defer ls.mu.Unlock()
+		ls.inFlight--
+		if lowPriority {
+			return
+		}
+		ls.samples[ls.next] = elapsed
+		ls.next = (ls.next + 1) % len(ls.samples)
+		if ls.next == 0 {
+			ls.filled = true
+		}
+	}
+}
+
+// Shed rejects the request with 503 while the server is overloaded. Attach it
+// only to low-priority routes (exports, analytics).
+func (ls *LoadShedder) Shed() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if lowPriority, ok := c.Request.Context().Value(lowPriorityKey{}).(*bool); ok {
+			*lowPriority = true
+		}
+		if ls.Overloaded() {
+			c.Header("Retry-After", "30")
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error": "Server is busy. Please try again later.",
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// SetLimits changes the shedding thresholds. Latency samples are kept.
+func (ls *LoadShedder) SetLimits(maxInFlight int, targetP99 time.Duration) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	ls.maxInFlight = maxInFlight
+	ls.targetP99 = targetP99
+}
+
+// Overloaded reports whether low-priority traffic should currently be shed.
+func (ls *LoadShedder) Overloaded() bool {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	// Track counts the shed-candidate itself, so compare strictly above the limit
+	if ls.maxInFlight > 0 && ls.inFlight > ls.maxInFlight {
+		return true
+	}
+	return ls.targetP99 > 0 && ls.p99Locked() > ls.targetP99
+}
+
+// P99 returns the p99 latency over the most recent high-priority requests, or
+// 0 until loadShedderMinSamples of them have been timed.
+func (ls *LoadShedder) P99() time.Duration {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	return ls.p99Locked()
+}
+
+func (ls *LoadShedder) p99Locked() time.Duration {
+	n := ls.next
+	if ls.filled {
+		n = len(ls.samples)
+	}
+	if n < loadShedderMinSamples {
+		return 0
+	}
+
+	sorted := make([]time.Duration, n)
+	copy(sorted, ls.samples[:n])
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	idx := (n*99 + 99) / 100
+	if idx > n {
+		idx = n
+	}
+	return sorted[idx-1]
+}
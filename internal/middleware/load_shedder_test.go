@@ -0,0 +1,148 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadShedder_AllowsRequestsWhenIdle(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	shedder := NewLoadShedder(10, time.Second)
+
+	router := gin.New()
+	router.Use(shedder.Track())
+	router.GET("/export", shedder.Shed(), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "ok"})
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/export", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestLoadShedder_ShedsLowPriorityWhenTooManyInFlight(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	shedder := NewLoadShedder(1, 0)
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	router := gin.New()
+	router.Use(shedder.Track())
+	router.GET("/slow", func(c *gin.Context) {
+		close(started)
+		<-release
+		c.JSON(http.StatusOK, gin.H{"message": "ok"})
+	})
+	router.GET("/export", shedder.Shed(), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "ok"})
+	})
+	router.GET("/courses", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "ok"})
+	})
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/slow", nil))
+	}()
+	<-started
+
+	// Low-priority route is shed
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/export", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "30", w.Header().Get("Retry-After"))
+
+	// High-priority route still served
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/courses", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	close(release)
+	wg.Wait()
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/export", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestLoadShedder_ShedsWhenP99AboveTarget(t *testing.T) {
+	shedder := NewLoadShedder(0, 10*time.Millisecond)
+
+	for i := 0; i < 100; i++ {
+		shedder.samples[shedder.next] = time.Millisecond
+		shedder.next++
+	}
+	assert.False(t, shedder.Overloaded())
+
+	shedder.samples[shedder.next] = time.Second
+	shedder.next++
+	shedder.samples[shedder.next] = time.Second
+	shedder.next++
+	assert.True(t, shedder.Overloaded())
+	assert.Equal(t, time.Second, shedder.P99())
+}
+
+func TestLoadShedder_P99Empty(t *testing.T) {
+	shedder := NewLoadShedder(10, time.Second)
+	assert.Equal(t, time.Duration(0), shedder.P99())
+	assert.False(t, shedder.Overloaded())
+}
+
+func TestLoadShedder_NeedsMinSamples(t *testing.T) {
+	shedder := NewLoadShedder(0, 10*time.Millisecond)
+	for i := 0; i < loadShedderMinSamples-1; i++ {
+		shedder.samples[shedder.next] = time.Second
+		shedder.next++
+	}
+	assert.False(t, shedder.Overloaded(), "a few slow requests after a restart don't shed")
+	assert.Equal(t, time.Duration(0), shedder.P99())
+
+	shedder.samples[shedder.next] = time.Second
+	shedder.next++
+	assert.True(t, shedder.Overloaded())
+}
+
+func TestLoadShedder_SamplesOnlyHighPriority(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	shedder := NewLoadShedder(0, time.Second)
+	router := gin.New()
+	router.Use(shedder.Track())
+	router.GET("/export", shedder.Shed(), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "ok"})
+	})
+	router.GET("/courses", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "ok"})
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/export", nil))
+	assert.Equal(t, 0, shedder.next, "low-priority requests aren't sampled")
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/courses", nil))
+	assert.Equal(t, 1, shedder.next)
+	assert.Equal(t, 0, shedder.inFlight)
+}
+
+func TestLoadShedder_SetLimits(t *testing.T) {
+	shedder := NewLoadShedder(0, time.Second)
+	for i := 0; i < loadShedderMinSamples; i++ {
+		shedder.samples[shedder.next] = 100 * time.Millisecond
+		shedder.next++
+	}
+	assert.False(t, shedder.Overloaded())
+
+	shedder.SetLimits(0, 10*time.Millisecond)
+	assert.True(t, shedder.Overloaded())
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Maintenance rejects writes with 503 while enabled reports true, leaving reads
+// and the admin API (so maintenance can be switched off again) untouched.
+// enabled is checked on every request so it can follow a config reload.
+func Maintenance(enabled func() bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !enabled() || isReadOnly(c.Request.Method) || strings.HasPrefix(c.Request.URL.Path, "/api/v1/admin") {
+			c.Next()
+			return
+		}
+
+		c.Header("Retry-After", "300")
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "The service is in maintenance mode. Please try again later.",
+		})
+		c.Abort()
+	}
+}
+
+func isReadOnly(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaintenance(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	enabled := true
+	router := gin.New()
+	router.Use(Maintenance(func() bool { return enabled }))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/api/v1/courses", ok)
+	router.POST("/api/v1/courses/:course_code/reviews", ok)
+	router.POST("/api/v1/admin/config/reload", ok)
+
+	tests := []struct {
+		name           string
+		enabled        bool
+		method         string
+		path           string
+		expectedStatus int
+	}{
+		{"reads allowed", true, http.MethodGet, "/api/v1/courses", http.StatusOK},
+		{"writes rejected", true, http.MethodPost, "/api/v1/courses/EECS2030/reviews", http.StatusServiceUnavailable},
+		{"admin writes allowed", true, http.MethodPost, "/api/v1/admin/config/reload", http.StatusOK},
+		{"writes allowed when disabled", false, http.MethodPost, "/api/v1/courses/EECS2030/reviews", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enabled = tt.enabled
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusServiceUnavailable {
+				assert.Equal(t, "300", w.Header().Get("Retry-After"))
+			}
+		})
+	}
+}
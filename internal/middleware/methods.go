@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HeadAsGet serves HEAD requests through the matching GET route, discarding
+// the body but keeping the status, headers and a Content-Length for what the
+// body would have been. gin only routes methods that were registered, so a
+// HEAD probe would otherwise 404. Handlers and the access log see GET.
+func HeadAsGet(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		get := r.Clone(r.Context())
+		get.Method = http.MethodGet
+		hw := &headWriter{ResponseWriter: w}
+		next.ServeHTTP(hw, get)
+		hw.finish()
+	})
+}
+
+// headWriter counts the body instead of sending it, and holds back the status
+// until the handler is done so Content-Length can be set first.
+type headWriter struct {
+	http.ResponseWriter
+	status int
+	size   int
+}
+
+func (w *headWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *headWriter) Write(b []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	w.size += len(b)
+	return len(b), nil
+}
+
+// Flush is a no-op; streamed GET responses are counted to the end.
+func (w *headWriter) Flush() {}
+
+func (w *headWriter) finish() {
+	w.WriteHeader(http.StatusOK)
+	if w.status != http.StatusNoContent && w.status != http.StatusNotModified && w.Header().Get("Content-Length") == "" {
+		w.Header().Set("Content-Length", strconv.Itoa(w.size))
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+}
+
+// Options answers OPTIONS for any registered path with 204 and an Allow
+// header listing its methods. Register it with NoRoute so it only sees
+// requests no route claimed (CORS preflights under /api/v1/lite are answered
+// earlier by CORS); anything else falls through to the usual 404.
+func Options(routes func() gin.RoutesInfo) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodOptions {
+			return
+		}
+		allowed := allowedMethods(routes(), c.Request.URL.Path)
+		if len(allowed) == 0 {
+			return
+		}
+		c.Header("Allow", strings.Join(allowed, ", "))
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// allowedMethods lists the methods registered for path, adding HEAD wherever
+// GET is allowed (see HeadAsGet) and OPTIONS itself.
+func allowedMethods(routes gin.RoutesInfo, path string) []string {
+	methods := map[string]bool{}
+	for _, rt := range routes {
+		if routeMatches(rt.Path, path) {
+			methods[rt.Method] = true
+		}
+	}
+	if len(methods) == 0 {
+		return nil
+	}
+	if methods[http.MethodGet] {
+		methods[http.MethodHead] = true
+	}
+	methods[http.MethodOptions] = true
+
+	allowed := make([]string, 0, len(methods))
+	for m := range methods {
+		allowed = append(allowed, m)
+	}
+	sort.Strings(allowed)
+	return allowed
+}
+
+// routeMatches reports whether path fits a gin route pattern, where :name
+// matches one segment and *name the rest of the path.
+func routeMatches(pattern, path string) bool {
+	want := strings.Split(strings.Trim(pattern, "/"), "/")
+	got := strings.Split(strings.Trim(path, "/"), "/")
+	for i, seg := range want {
+		if strings.HasPrefix(seg, "*") {
+			return true
+		}
+		if i >= len(got) {
+			return false
+		}
+		if strings.HasPrefix(seg, ":") {
+			if got[i] == "" {
+				return false
+			}
+			continue
+		}
+		if seg != got[i] {
+			return false
+		}
+	}
+	return len(want) == len(got)
+}
@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func methodsRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.NoRoute(Options(router.Routes))
+	router.GET("/api/v1/courses/:course_code", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"data": c.Param("course_code")})
+	})
+	router.POST("/api/v1/courses/:course_code/reviews", func(c *gin.Context) { c.Status(http.StatusCreated) })
+	router.GET("/api/v1/courses/:course_code/reviews", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/api/v1/stream", func(c *gin.Context) {
+		c.Header("Content-Type", "application/json")
+		c.Writer.WriteString("[1,")
+		c.Writer.Flush()
+		c.Writer.WriteString("2]")
+	})
+	router.GET("/api/v1/empty", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	return router
+}
+
+func TestHeadAsGet(t *testing.T) {
+	handler := HeadAsGet(methodsRouter())
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		expectedLength string
+	}{
+		{"json route", "/api/v1/courses/EECS2030", http.StatusOK, "19"},
+		{"streamed route", "/api/v1/stream", http.StatusOK, "5"},
+		{"no content", "/api/v1/empty", http.StatusNoContent, ""},
+		{"unknown route", "/api/v1/nope", http.StatusNotFound, "18"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodHead, tt.path, nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedLength, w.Header().Get("Content-Length"))
+			assert.Empty(t, w.Body.String())
+		})
+	}
+
+	t.Run("GET untouched", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/courses/EECS2030", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"data":"EECS2030"}`, w.Body.String())
+	})
+}
+
+func TestOptions(t *testing.T) {
+	router := methodsRouter()
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+		expectedAllow  string
+	}{
+		{"get route", http.MethodOptions, "/api/v1/courses/EECS2030", http.StatusNoContent, "GET, HEAD, OPTIONS"},
+		{"get and post route", http.MethodOptions, "/api/v1/courses/EECS2030/reviews", http.StatusNoContent, "GET, HEAD, OPTIONS, POST"},
+		{"unknown path", http.MethodOptions, "/api/v1/nope", http.StatusNotFound, ""},
+		{"other methods still 404", http.MethodGet, "/api/v1/nope", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedAllow, w.Header().Get("Allow"))
+		})
+	}
+}
+
+func TestRouteMatches(t *testing.T) {
+	assert.True(t, routeMatches("/api/v1/courses", "/api/v1/courses"))
+	assert.True(t, routeMatches("/api/v1/courses/:course_code", "/api/v1/courses/EECS2030"))
+	assert.True(t, routeMatches("/static/*filepath", "/static/css/app.css"))
+	assert.False(t, routeMatches("/api/v1/courses/:course_code", "/api/v1/courses"))
+	assert.False(t, routeMatches("/api/v1/courses/:course_code", "/api/v1/courses/EECS2030/reviews"))
+	assert.False(t, routeMatches("/api/v1/courses/search", "/api/v1/courses/other"))
+}
@@ -1,9 +1,16 @@
 package middleware
 
 import (
+	"errors"
+	"log"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
+	"yuplan/internal/exemption"
+	"yuplan/internal/models"
 
 	"github.com/gin-gonic/gin"
 )
@@ -19,6 +26,20 @@ type RateLimiter struct {
 	limit    int           // requests per window
 	window   time.Duration // time window
 	cleanup  time.Duration // cleanup interval
+	exempt   []string      // path prefixes limited elsewhere
+	tokens   exemptionVerifier
+	observer rateLimitObserver
+	name     string // which limiter, for the observer
+}
+
+// rateLimitObserver counts rejected requests. Implemented by metrics.HTTP.
+type rateLimitObserver interface {
+	RateLimited(limiter string)
+}
+
+// exemptionVerifier checks rate limit exemption tokens. Implemented by exemption.Signer.
+type exemptionVerifier interface {
+	Verify(token string) (exemption.Claims, error)
 }
 
 // NewRateLimiter creates a new rate limiter
@@ -70,18 +91,69 @@ func (rl *RateLimiter) getVisitor(ip string) *visitor {
 	return v
 }
 
+// Exempt leaves requests under prefix to another limiter, for routes with their own tier.
+func (rl *RateLimiter) Exempt(prefix string) *RateLimiter {
+	rl.exempt = append(rl.exempt, prefix)
+	return rl
+}
+
+// WithExemptions honours exemption tokens sent in the X-RateLimit-Exemption
+// header: a request carrying a valid one is counted against the token, at the
+// token's limit, instead of against its IP. A bad or expired token gets 401
+// rather than quietly falling back to the IP limit.
+func (rl *RateLimiter) WithExemptions(tokens exemptionVerifier) *RateLimiter {
+	rl.tokens = tokens
+	return rl
+}
+
+// WithObserver reports every request rejected with 429 as limited by name.
+func (rl *RateLimiter) WithObserver(observer rateLimitObserver, name string) *RateLimiter {
+	rl.observer, rl.name = observer, name
+	return rl
+}
+
 func (rl *RateLimiter) Limit() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		for _, prefix := range rl.exempt {
+			if strings.HasPrefix(c.Request.URL.Path, prefix) {
+				c.Next()
+				return
+			}
+		}
+
 		ip := c.ClientIP()
-		
+		key, limit := ip, -1
+		var claims exemption.Claims
+		if token := c.GetHeader(exemption.Header); token != "" && rl.tokens != nil {
+			var err error
+			if claims, err = rl.tokens.Verify(token); err != nil {
+				message := "Invalid rate limit exemption"
+				if errors.Is(err, exemption.ErrExpired) {
+					message = "Rate limit exemption expired"
+				}
+				c.JSON(http.StatusUnauthorized, gin.H{"error": message})
+				c.Abort()
+				return
+			}
+			key, limit = "exemption:"+claims.ID, claims.Limit
+		}
+
 		rl.mu.Lock()
-		v := rl.visitors[ip]
+		if limit < 0 {
+			limit = rl.limit
+		}
+		v := rl.visitors[key]
 		if v == nil {
 			v = &visitor{
 				requests:  0,
 				lastReset: time.Now(),
 			}
-			rl.visitors[ip] = v
+			rl.visitors[key] = v
+			if claims.ID != "" {
+				// Logged when a token is first used and again after it sits idle;
+				// its issue is recorded in audit_log
+				log.Printf("Rate limit exemption %s (%s) in use from %s, limit %d", claims.ID, claims.Name, ip, limit)
+			}
 		}
 		
 		now := time.Now()
@@ -93,12 +165,13 @@ func (rl *RateLimiter) Limit() gin.HandlerFunc {
 		}
 		
 		// Check if limit exceeded
-		if v.requests >= rl.limit {
+		if v.requests >= limit {
+			reset := v.lastReset.Add(rl.window)
 			rl.mu.Unlock()
-			c.JSON(http.StatusTooManyRequests, gin.H{
-				"error": "Rate limit exceeded. Please try again later.",
-			})
-			c.Abort()
+			if rl.observer != nil {
+				rl.observer.RateLimited(rl.name)
+			}
+			rejectOverLimit(c, limit, reset)
 			return
 		}
 		
@@ -109,3 +182,43 @@ func (rl *RateLimiter) Limit() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// rejectOverLimit answers 429 with when the caller's window resets, both as
+// Retry-After and in the body, so clients can wait it out instead of retrying.
+func rejectOverLimit(c *gin.Context, limit int, reset time.Time) {
+	// Whole seconds, rounded up so a client that waits them is let through
+	retryAfter := max(1, int(math.Ceil(time.Until(reset).Seconds())))
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"error":               "Rate limit exceeded. Please try again later.",
+		"code":                models.ErrCodeRateLimited,
+		"limit":               limit,
+		"remaining":           0,
+		"reset_at":            reset.UTC(),
+		"retry_after_seconds": retryAfter,
+	})
+	c.Abort()
+}
+
+// Remaining returns how many requests key may still make in the current window.
+func (rl *RateLimiter) Remaining(key string) int {
+	rl.mu.RLock()
+	defer rl.mu.RUnlock()
+
+	v, exists := rl.visitors[key]
+	if !exists || time.Since(v.lastReset) > rl.window {
+		return rl.limit
+	}
+	if v.requests >= rl.limit {
+		return 0
+	}
+	return rl.limit - v.requests
+}
+
+// SetLimit changes the limit and window for subsequent requests. Counters already in progress are kept.
+func (rl *RateLimiter) SetLimit(limit int, window time.Duration) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.limit = limit
+	rl.window = window
+}
@@ -1,10 +1,12 @@
 package middleware
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
+	"yuplan/internal/exemption"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -62,6 +64,41 @@ func TestRateLimiter_BlocksRequestsOverLimit(t *testing.T) {
 	assert.Equal(t, http.StatusTooManyRequests, w.Code, "4th request should be blocked")
 }
 
+func TestRateLimiter_RejectionSaysWhenToRetry(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	limiter := NewRateLimiter(1, 90*time.Second)
+	router := gin.New()
+	router.Use(limiter.Limit())
+	router.GET("/test", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "ok"})
+	})
+
+	var w *httptest.ResponseRecorder
+	for range 2 {
+		w = httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.RemoteAddr = "192.168.1.1:1234"
+		router.ServeHTTP(w, req)
+	}
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "90", w.Header().Get("Retry-After"))
+	var body struct {
+		Code              string    `json:"code"`
+		Limit             int       `json:"limit"`
+		Remaining         int       `json:"remaining"`
+		ResetAt           time.Time `json:"reset_at"`
+		RetryAfterSeconds int       `json:"retry_after_seconds"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "rate_limited", body.Code)
+	assert.Equal(t, 1, body.Limit)
+	assert.Equal(t, 0, body.Remaining)
+	assert.Equal(t, 90, body.RetryAfterSeconds)
+	assert.WithinDuration(t, time.Now().Add(90*time.Second), body.ResetAt, 2*time.Second)
+}
+
 func TestRateLimiter_DifferentIPsHaveSeparateLimits(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	
@@ -135,3 +172,141 @@ func TestRateLimiter_ResetsAfterWindow(t *testing.T) {
 	router.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusOK, w.Code, "Should work after window reset")
 }
+
+func TestRateLimiter_Remaining(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	limiter := NewRateLimiter(3, 1*time.Minute)
+
+	router := gin.New()
+	router.Use(limiter.Limit())
+	router.GET("/test", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "ok"})
+	})
+
+	assert.Equal(t, 3, limiter.Remaining("192.168.1.1"))
+
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.RemoteAddr = "192.168.1.1:1234"
+		router.ServeHTTP(w, req)
+	}
+
+	assert.Equal(t, 1, limiter.Remaining("192.168.1.1"))
+	assert.Equal(t, 3, limiter.Remaining("10.0.0.1"))
+}
+
+func TestRateLimiter_SetLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	limiter := NewRateLimiter(1, 1*time.Minute)
+
+	router := gin.New()
+	router.Use(limiter.Limit())
+	router.GET("/test", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "ok"})
+	})
+
+	request := func() int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.RemoteAddr = "192.168.1.1:1234"
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, request())
+	assert.Equal(t, http.StatusTooManyRequests, request())
+
+	// Rejected requests don't count, so one of the three is used
+	limiter.SetLimit(3, 1*time.Minute)
+	assert.Equal(t, 2, limiter.Remaining("192.168.1.1"))
+	assert.Equal(t, http.StatusOK, request())
+	assert.Equal(t, http.StatusOK, request())
+	assert.Equal(t, http.StatusTooManyRequests, request())
+}
+
+func TestRateLimiter_Exempt(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	limiter := NewRateLimiter(1, 1*time.Minute).Exempt("/api/v1/lite")
+
+	router := gin.New()
+	router.Use(limiter.Limit())
+	ok := func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"message": "ok"}) }
+	router.GET("/api/v1/courses", ok)
+	router.GET("/api/v1/lite/courses", ok)
+
+	request := func(path string) int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", path, nil)
+		req.RemoteAddr = "192.168.1.1:1234"
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, request("/api/v1/courses"))
+	assert.Equal(t, http.StatusTooManyRequests, request("/api/v1/courses"))
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, http.StatusOK, request("/api/v1/lite/courses"))
+	}
+}
+
+func TestRateLimiter_WithExemptions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	signer := exemption.NewSigner("secret", time.Hour, 3)
+	limiter := NewRateLimiter(1, 1*time.Minute).WithExemptions(signer)
+
+	router := gin.New()
+	router.Use(limiter.Limit())
+	router.GET("/test", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"message": "ok"}) })
+
+	request := func(token string) int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.RemoteAddr = "192.168.1.1:1234"
+		if token != "" {
+			req.Header.Set(exemption.Header, token)
+		}
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	token, _, err := signer.Issue("k6 nightly", 3, time.Minute)
+	assert.NoError(t, err)
+
+	// The IP is limited to one request; the token gets its own three
+	assert.Equal(t, http.StatusOK, request(""))
+	assert.Equal(t, http.StatusTooManyRequests, request(""))
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, http.StatusOK, request(token), "Request %d with the token should succeed", i+1)
+	}
+	assert.Equal(t, http.StatusTooManyRequests, request(token))
+
+	assert.Equal(t, http.StatusUnauthorized, request("forged.token"))
+}
+
+type countingRateLimitObserver map[string]int
+
+func (m countingRateLimitObserver) RateLimited(limiter string) { m[limiter]++ }
+
+func TestRateLimiter_WithObserver(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	observer := countingRateLimitObserver{}
+	limiter := NewRateLimiter(1, 1*time.Minute).WithObserver(observer, "lite")
+
+	router := gin.New()
+	router.Use(limiter.Limit())
+	router.GET("/test", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.RemoteAddr = "192.168.1.1:1234"
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	assert.Equal(t, countingRateLimitObserver{"lite": 2}, observer)
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"yuplan/internal/logging"
+	"yuplan/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Recovery turns a panic in a later handler into a 500 with the usual error
+// body, and logs it to logger with its stack and the request ID set by
+// RequestID. A client that hung up mid-response is not logged as a panic; gin
+// records it on the context for the access log instead.
+func Recovery(logger *slog.Logger) gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, recovered any) {
+		attrs := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			slog.String("path", c.Request.URL.Path),
+			slog.String("panic", fmt.Sprint(recovered)),
+			slog.String("stack", string(debug.Stack())),
+		}
+		if requestID := logging.RequestID(c.Request.Context()); requestID != "" {
+			attrs = append(attrs, slog.String("request_id", requestID))
+		}
+		logger.LogAttrs(c.Request.Context(), slog.LevelError, "panic", attrs...)
+
+		if c.Writer.Written() {
+			c.Abort()
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": "Internal server error",
+			"code":  models.ErrCodeInternal,
+		})
+	})
+}
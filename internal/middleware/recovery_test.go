@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"yuplan/internal/logging"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecovery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var buf bytes.Buffer
+	router := gin.New()
+	router.Use(RequestID(), Recovery(logging.New(&buf)))
+	router.GET("/boom", func(c *gin.Context) { panic("boom") })
+	router.GET("/partial", func(c *gin.Context) {
+		c.String(http.StatusOK, "half")
+		panic("after writing")
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/boom", nil)
+	req.Header.Set(RequestIDHeader, "req-1")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.JSONEq(t, `{"error": "Internal server error", "code": "internal_error"}`, w.Body.String())
+	var line map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, "panic", line["msg"])
+	assert.Equal(t, "boom", line["panic"])
+	assert.Equal(t, "req-1", line["request_id"])
+	assert.Contains(t, line["stack"], "recovery")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/partial", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "half", w.Body.String())
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"regexp"
+	"yuplan/internal/id"
+	"yuplan/internal/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader carries a request's ID in and out.
+const RequestIDHeader = "X-Request-ID"
+
+// Reuse a caller's ID only if it can't break a log line or a header
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestID gives every request an ID, kept from X-Request-ID when a proxy
+// or client already set one, and echoes it back in that header. The ID is on
+// the request context for logging.FromContext.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			requestID = id.New()
+		}
+		c.Header(RequestIDHeader, requestID)
+		c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), requestID))
+		c.Next()
+	}
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"yuplan/internal/id"
+	"yuplan/internal/logging"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var seen string
+	router := gin.New()
+	router.Use(RequestID())
+	router.GET("/test", func(c *gin.Context) {
+		seen = logging.RequestID(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	tests := []struct {
+		name     string
+		incoming string
+		keep     bool
+	}{
+		{"generated", "", false},
+		{"kept from proxy", "9f1c2d3e-load-balancer", true},
+		{"unsafe replaced", "evil\" injected=1", false},
+		{"too long replaced", strings.Repeat("a", 65), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/test", nil)
+			if tt.incoming != "" {
+				req.Header.Set(RequestIDHeader, tt.incoming)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, seen, w.Header().Get(RequestIDHeader))
+			if tt.keep {
+				assert.Equal(t, tt.incoming, seen)
+			} else {
+				assert.True(t, id.Valid(seen), seen)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"yuplan/internal/session"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sessionVerifier checks anonymous session tokens. Implemented by session.Signer.
+type sessionVerifier interface {
+	Verify(token string) (session.Session, error)
+}
+
+// AnonymousSession records the caller's anonymous session in the request
+// context when they send a valid token, in the session cookie or, for clients
+// without cookies, the X-Session-Token header. It never starts one or sets a
+// cookie, so cacheable responses stay free of Set-Cookie; a missing, invalid
+// or expired token just leaves the request without a session.
+func AnonymousSession(verifier sessionVerifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.GetHeader(session.Header)
+		if token == "" {
+			token, _ = c.Cookie(session.Cookie)
+		}
+		if token != "" {
+			if sess, err := verifier.Verify(token); err == nil {
+				c.Request = c.Request.WithContext(session.WithSession(c.Request.Context(), sess))
+			}
+		}
+		c.Next()
+	}
+}
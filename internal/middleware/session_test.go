@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"yuplan/internal/session"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnonymousSession(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	signer := session.NewSigner("secret", time.Hour)
+	sess, token, err := signer.Start()
+	assert.NoError(t, err)
+
+	router := gin.New()
+	router.Use(AnonymousSession(signer))
+	router.GET("/", func(c *gin.Context) {
+		got, _ := session.FromContext(c.Request.Context())
+		c.String(http.StatusOK, got.ID)
+	})
+
+	tests := []struct {
+		name string
+		set  func(r *http.Request)
+		want string
+	}{
+		{"cookie", func(r *http.Request) { r.AddCookie(&http.Cookie{Name: session.Cookie, Value: token}) }, sess.ID},
+		{"header", func(r *http.Request) { r.Header.Set(session.Header, token) }, sess.ID},
+		{"forged", func(r *http.Request) { r.Header.Set(session.Header, token+"x") }, ""},
+		{"none", func(r *http.Request) {}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			tt.set(req)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.want, w.Body.String())
+			assert.Empty(t, w.Header().Get("Set-Cookie"))
+		})
+	}
+}
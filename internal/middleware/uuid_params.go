@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+	"yuplan/internal/id"
+	"yuplan/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UUIDParams rejects requests whose named path parameters aren't UUIDs with
+// 400 and code invalid_id, so a malformed ID never reaches a query and comes
+// back as a database error. Routes without those parameters pass through.
+func UUIDParams(names ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, name := range names {
+			value, ok := c.Params.Get(name)
+			if !ok || id.Valid(value) {
+				continue
+			}
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": name + " must be a UUID",
+				"code":  models.ErrCodeInvalidID,
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
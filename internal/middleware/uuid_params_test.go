@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUUIDParams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(UUIDParams("id", "course_id"))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.POST("/admin/reviews/:id/publish", ok)
+	router.GET("/sections/:course_id", ok)
+	router.GET("/courses/:course_code", ok)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+	}{
+		{"valid id", http.MethodPost, "/admin/reviews/01928c6a-7b3e-7a21-9f00-0123456789ab/publish", http.StatusOK},
+		{"malformed id", http.MethodPost, "/admin/reviews/42/publish", http.StatusBadRequest},
+		{"malformed course_id", http.MethodGet, "/sections/EECS2030", http.StatusBadRequest},
+		{"other params unchecked", http.MethodGet, "/courses/EECS2030", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, tt.path, nil)
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			if tt.expectedStatus == http.StatusBadRequest {
+				assert.Contains(t, recorder.Body.String(), `"code":"invalid_id"`)
+			}
+		})
+	}
+}
@@ -0,0 +1,262 @@
+// Package migrate applies versioned SQL migrations such as the ones embedded
+// in package migrations. Its state is golang-migrate's schema_migrations
+// table, a single row of the current version and a dirty flag, so a database
+// migrated with the migrate CLI carries on from where it is and either tool
+// can be used on it.
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// ErrDirty means a migration failed part way under a tool that doesn't run
+// them in transactions. Fix the schema by hand, then Force the version it is at.
+var ErrDirty = errors.New("database is dirty")
+
+// Migration is one version's SQL. Down is empty when it has no down file.
+type Migration struct {
+	Version uint
+	Name    string
+	Up      string
+	Down    string
+}
+
+// fileName is golang-migrate's layout: 000001_create_courses_table.up.sql
+var fileName = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// Load reads the migrations in fsys's top directory, oldest first. Other
+// files are ignored; each version needs an up file.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("read migrations: %w", err)
+	}
+
+	byVersion := map[uint]*Migration{}
+	for _, entry := range entries {
+		match := fileName.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("migration %s: bad version", entry.Name())
+		}
+		sql, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("read migration %s: %w", entry.Name(), err)
+		}
+
+		m := byVersion[uint(version)]
+		if m == nil {
+			m = &Migration{Version: uint(version), Name: match[2]}
+			byVersion[uint(version)] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %d is named both %s and %s", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(sql)
+		} else {
+			m.Down = string(sql)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %06d_%s has no up file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// txStarter is what a Migrator runs on. Implemented by *pgxpool.Pool.
+type txStarter interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// Migrator moves a database between the versions of its migrations. Every
+// change runs in one transaction with its version update, under an advisory
+// lock, so instances booting together apply each migration once and a
+// failed one leaves the database as it was.
+type Migrator struct {
+	db         txStarter
+	migrations []Migration
+}
+
+// New loads the migrations in fsys to run against db.
+func New(db txStarter, fsys fs.FS) (*Migrator, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Version is the database's current version, 0 before any migration.
+func (m *Migrator) Version(ctx context.Context) (version uint, dirty bool, err error) {
+	err = m.locked(ctx, func(tx pgx.Tx, current uint, isDirty bool) error {
+		version, dirty = current, isDirty
+		return nil
+	})
+	return version, dirty, err
+}
+
+// Up applies every migration newer than the database and returns them. A
+// database newer than the latest migration is left alone.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+	err := m.locked(ctx, func(tx pgx.Tx, current uint, dirty bool) error {
+		if dirty {
+			return fmt.Errorf("%w at version %d", ErrDirty, current)
+		}
+		for _, migration := range m.migrations {
+			if migration.Version <= current {
+				continue
+			}
+			if _, err := tx.Exec(ctx, migration.Up); err != nil {
+				return fmt.Errorf("migration %06d_%s: %w", migration.Version, migration.Name, err)
+			}
+			applied = append(applied, migration)
+		}
+		if len(applied) == 0 {
+			return nil
+		}
+		return setVersion(ctx, tx, applied[len(applied)-1].Version)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return applied, nil
+}
+
+// Down reverts the latest steps migrations applied and returns them, newest
+// first.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	if steps < 1 {
+		return nil, fmt.Errorf("steps must be at least 1")
+	}
+	var reverted []Migration
+	err := m.locked(ctx, func(tx pgx.Tx, current uint, dirty bool) error {
+		if dirty {
+			return fmt.Errorf("%w at version %d", ErrDirty, current)
+		}
+		if current == 0 {
+			return nil
+		}
+		i := m.index(current)
+		if i < 0 {
+			return fmt.Errorf("database is at version %d, which has no migration here", current)
+		}
+		for ; i >= 0 && len(reverted) < steps; i-- {
+			migration := m.migrations[i]
+			if migration.Down == "" {
+				return fmt.Errorf("migration %06d_%s has no down file", migration.Version, migration.Name)
+			}
+			if _, err := tx.Exec(ctx, migration.Down); err != nil {
+				return fmt.Errorf("revert migration %06d_%s: %w", migration.Version, migration.Name, err)
+			}
+			reverted = append(reverted, migration)
+		}
+		var version uint
+		if i >= 0 {
+			version = m.migrations[i].Version
+		}
+		return setVersion(ctx, tx, version)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reverted, nil
+}
+
+// Force records version as the current one and clears the dirty flag without
+// running anything, for a database repaired by hand. 0 means none applied.
+func (m *Migrator) Force(ctx context.Context, version uint) error {
+	if version != 0 && m.index(version) < 0 {
+		return fmt.Errorf("no migration has version %d", version)
+	}
+	return m.locked(ctx, func(tx pgx.Tx, current uint, dirty bool) error {
+		return setVersion(ctx, tx, version)
+	})
+}
+
+func (m *Migrator) index(version uint) int {
+	i := sort.Search(len(m.migrations), func(i int) bool { return m.migrations[i].Version >= version })
+	if i < len(m.migrations) && m.migrations[i].Version == version {
+		return i
+	}
+	return -1
+}
+
+// lockKey is the advisory lock held while migrating.
+var lockKey = func() int64 {
+	h := fnv.New64a()
+	h.Write([]byte("yuplan/migrate"))
+	return int64(h.Sum64())
+}()
+
+// locked runs fn in a transaction holding the migration lock, with the
+// version as it stands once the lock is held, and commits if fn succeeds.
+func (m *Migrator) locked(ctx context.Context, fn func(tx pgx.Tx, version uint, dirty bool) error) error {
+	tx, err := m.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin migration: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", lockKey); err != nil {
+		return fmt.Errorf("lock migrations: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version BIGINT NOT NULL PRIMARY KEY,
+		dirty BOOLEAN NOT NULL
+	)`); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	var version int64
+	var dirty bool
+	err = tx.QueryRow(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("read schema version: %w", err)
+	}
+	if version < 0 {
+		// golang-migrate's "no version"
+		version = 0
+	}
+
+	if err := fn(tx, uint(version), dirty); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit migration: %w", err)
+	}
+	return nil
+}
+
+// setVersion replaces the version row; version 0 leaves the table empty, as
+// golang-migrate does once everything is reverted.
+func setVersion(ctx context.Context, tx pgx.Tx, version uint) error {
+	if _, err := tx.Exec(ctx, "DELETE FROM schema_migrations"); err != nil {
+		return fmt.Errorf("clear schema version: %w", err)
+	}
+	if version == 0 {
+		return nil
+	}
+	if _, err := tx.Exec(ctx, "INSERT INTO schema_migrations (version, dirty) VALUES ($1, false)", int64(version)); err != nil {
+		return fmt.Errorf("set schema version: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testFS = fstest.MapFS{
+	"000001_create_courses.up.sql":   {Data: []byte("CREATE TABLE courses (id INT)")},
+	"000001_create_courses.down.sql": {Data: []byte("DROP TABLE courses")},
+	"000002_add_title.up.sql":        {Data: []byte("ALTER TABLE courses ADD title TEXT")},
+	"000002_add_title.down.sql":      {Data: []byte("ALTER TABLE courses DROP title")},
+	"000003_create_reviews.up.sql":   {Data: []byte("CREATE TABLE reviews (id INT)")},
+	"000003_create_reviews.down.sql": {Data: []byte("DROP TABLE reviews")},
+	"migrations.go":                  {Data: []byte("package migrations")},
+}
+
+// expectLocked expects the transaction, lock and version read every operation starts with.
+func expectLocked(mock pgxmock.PgxPoolIface, version int64, dirty bool) {
+	mock.ExpectBegin()
+	mock.ExpectExec("SELECT pg_advisory_xact_lock\\(\\$1\\)").WithArgs(lockKey).
+		WillReturnResult(pgxmock.NewResult("SELECT", 1))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").
+		WillReturnResult(pgxmock.NewResult("CREATE TABLE", 0))
+	query := mock.ExpectQuery("SELECT version, dirty FROM schema_migrations")
+	if version == 0 {
+		query.WillReturnError(pgx.ErrNoRows)
+		return
+	}
+	query.WillReturnRows(pgxmock.NewRows([]string{"version", "dirty"}).AddRow(version, dirty))
+}
+
+func expectSetVersion(mock pgxmock.PgxPoolIface, version int64) {
+	mock.ExpectExec("DELETE FROM schema_migrations").WillReturnResult(pgxmock.NewResult("DELETE", 1))
+	mock.ExpectExec("INSERT INTO schema_migrations").WithArgs(version).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+}
+
+func TestLoad(t *testing.T) {
+	migrations, err := Load(testFS)
+	require.NoError(t, err)
+	require.Len(t, migrations, 3)
+	assert.Equal(t, uint(1), migrations[0].Version)
+	assert.Equal(t, "create_courses", migrations[0].Name)
+	assert.Equal(t, "CREATE TABLE courses (id INT)", migrations[0].Up)
+	assert.Equal(t, "DROP TABLE courses", migrations[0].Down)
+	assert.Equal(t, uint(3), migrations[2].Version)
+
+	_, err = Load(fstest.MapFS{"000001_a.down.sql": {Data: []byte("DROP TABLE a")}})
+	assert.ErrorContains(t, err, "has no up file")
+
+	_, err = Load(fstest.MapFS{
+		"000001_a.up.sql": {Data: []byte("CREATE TABLE a ()")},
+		"000001_b.up.sql": {Data: []byte("CREATE TABLE b ()")},
+	})
+	assert.ErrorContains(t, err, "named both")
+}
+
+func TestMigrator_UpAppliesPendingInOneTransaction(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	expectLocked(mock, 1, false)
+	mock.ExpectExec("ALTER TABLE courses ADD title TEXT").WillReturnResult(pgxmock.NewResult("ALTER TABLE", 0))
+	mock.ExpectExec("CREATE TABLE reviews").WillReturnResult(pgxmock.NewResult("CREATE TABLE", 0))
+	expectSetVersion(mock, 3)
+	mock.ExpectCommit()
+
+	m, err := New(mock, testFS)
+	require.NoError(t, err)
+	applied, err := m.Up(context.Background())
+	assert.NoError(t, err)
+	if assert.Len(t, applied, 2) {
+		assert.Equal(t, uint(2), applied[0].Version)
+		assert.Equal(t, uint(3), applied[1].Version)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_UpUpToDate(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	expectLocked(mock, 3, false)
+	mock.ExpectCommit()
+
+	m, err := New(mock, testFS)
+	require.NoError(t, err)
+	applied, err := m.Up(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, applied)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_UpRollsBackOnFailure(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	expectLocked(mock, 0, false)
+	mock.ExpectExec("CREATE TABLE courses").WillReturnResult(pgxmock.NewResult("CREATE TABLE", 0))
+	mock.ExpectExec("ALTER TABLE courses ADD title TEXT").WillReturnError(errors.New("column already exists"))
+	mock.ExpectRollback()
+
+	m, err := New(mock, testFS)
+	require.NoError(t, err)
+	applied, err := m.Up(context.Background())
+	assert.ErrorContains(t, err, "migration 000002_add_title: column already exists")
+	assert.Empty(t, applied)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_UpRefusesDirty(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	expectLocked(mock, 2, true)
+	mock.ExpectRollback()
+
+	m, err := New(mock, testFS)
+	require.NoError(t, err)
+	_, err = m.Up(context.Background())
+	assert.ErrorIs(t, err, ErrDirty)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Down(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	expectLocked(mock, 3, false)
+	mock.ExpectExec("DROP TABLE reviews").WillReturnResult(pgxmock.NewResult("DROP TABLE", 0))
+	mock.ExpectExec("ALTER TABLE courses DROP title").WillReturnResult(pgxmock.NewResult("ALTER TABLE", 0))
+	expectSetVersion(mock, 1)
+	mock.ExpectCommit()
+
+	m, err := New(mock, testFS)
+	require.NoError(t, err)
+	reverted, err := m.Down(context.Background(), 2)
+	assert.NoError(t, err)
+	if assert.Len(t, reverted, 2) {
+		assert.Equal(t, uint(3), reverted[0].Version)
+		assert.Equal(t, uint(2), reverted[1].Version)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_DownPastFirstEmptiesVersion(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	expectLocked(mock, 1, false)
+	mock.ExpectExec("DROP TABLE courses").WillReturnResult(pgxmock.NewResult("DROP TABLE", 0))
+	mock.ExpectExec("DELETE FROM schema_migrations").WillReturnResult(pgxmock.NewResult("DELETE", 1))
+	mock.ExpectCommit()
+
+	m, err := New(mock, testFS)
+	require.NoError(t, err)
+	reverted, err := m.Down(context.Background(), 5)
+	assert.NoError(t, err)
+	assert.Len(t, reverted, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Force(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	expectLocked(mock, 3, true)
+	expectSetVersion(mock, 2)
+	mock.ExpectCommit()
+
+	m, err := New(mock, testFS)
+	require.NoError(t, err)
+	assert.NoError(t, m.Force(context.Background(), 2))
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	assert.ErrorContains(t, m.Force(context.Background(), 7), "no migration has version 7")
+}
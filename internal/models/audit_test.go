@@ -0,0 +1,29 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditEntryJSON(t *testing.T) {
+	entry := AuditEntry{
+		ID:        42,
+		Entity:    "review",
+		EntityID:  "review-1",
+		Action:    "insert",
+		Details:   json.RawMessage(`{"course_code":"EECS2030"}`),
+		CreatedAt: time.Now(),
+	}
+
+	data, err := json.Marshal(entry)
+	assert.NoError(t, err)
+
+	var decoded map[string]interface{}
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, "review", decoded["entity"])
+	assert.Equal(t, "insert", decoded["action"])
+	assert.Equal(t, map[string]interface{}{"course_code": "EECS2030"}, decoded["details"])
+}
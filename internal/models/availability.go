@@ -0,0 +1,58 @@
+package models
+
+import "yuplan/internal/dbtypes"
+
+// MaxAvailabilityUpdates bounds one ingestion batch.
+const MaxAvailabilityUpdates = 2000
+
+// SeatAvailability is the last seat count ingested for a section or activity.
+// Every field is null until the scraper has reported one.
+type SeatAvailability struct {
+	Capacity       *int             `json:"capacity"`
+	Enrolled       *int             `json:"enrolled"`
+	SeatsRemaining *int             `json:"seats_remaining"` // capacity less enrolled, never below 0
+	UpdatedAt      dbtypes.NullTime `json:"updated_at"`
+}
+
+// FillRemaining sets SeatsRemaining from Capacity and Enrolled when both are known.
+func (a *SeatAvailability) FillRemaining() {
+	if a.Capacity == nil || a.Enrolled == nil {
+		a.SeatsRemaining = nil
+		return
+	}
+	remaining := max(*a.Capacity-*a.Enrolled, 0)
+	a.SeatsRemaining = &remaining
+}
+
+// SectionAvailability is a section's seat count with its activities' counts.
+type SectionAvailability struct {
+	SectionID string `json:"section_id"`
+	Letter    string `json:"letter"`
+	TermID    string `json:"term_id"`
+	SeatAvailability
+	Activities []ActivityAvailability `json:"activities"`
+}
+
+// ActivityAvailability is one section activity's seat count.
+type ActivityAvailability struct {
+	ID            string `json:"id"`
+	CourseType    string `json:"course_type"`
+	CatalogNumber string `json:"catalog_number"`
+	SeatAvailability
+}
+
+// AvailabilityUpdate is one seat count reported by the scraper. It names
+// either a section by SectionID, or an activity by its Term (e.g. FW2025)
+// and CatalogNumber, since that's how the registrar lists activities.
+type AvailabilityUpdate struct {
+	SectionID     string `json:"section_id,omitempty" binding:"omitempty,uuid"`
+	Term          string `json:"term,omitempty" binding:"required_with=CatalogNumber,max=10"`
+	CatalogNumber string `json:"catalog_number,omitempty" binding:"required_without=SectionID,excluded_with=SectionID,max=20"`
+	Capacity      *int   `json:"capacity" binding:"required,min=0"`
+	Enrolled      *int   `json:"enrolled" binding:"required,min=0"`
+}
+
+// UpdateAvailabilityRequest is the admin payload ingesting a batch of seat counts.
+type UpdateAvailabilityRequest struct {
+	Updates []AvailabilityUpdate `json:"updates" binding:"required,min=1,max=2000,dive"`
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+	"yuplan/internal/dbtypes"
+)
+
+// Badge is a rule for recognising reviewers, e.g. "Department Expert" for
+// five reviews in one department.
+type Badge struct {
+	Slug        string             `json:"slug"`
+	Name        string             `json:"name"`
+	Description dbtypes.NullString `json:"description"`
+	Metric      string             `json:"metric"`    // one of BadgeMetrics
+	Threshold   int                `json:"threshold"` // metric value at which the badge is earned
+	CreatedAt   time.Time          `json:"created_at"`
+}
+
+// CreateBadgeRequest is the admin payload for adding a badge rule.
+type CreateBadgeRequest struct {
+	Slug        string             `json:"slug" binding:"required,max=50"`
+	Name        string             `json:"name" binding:"required,max=100"`
+	Description dbtypes.NullString `json:"description"`
+	Metric      string             `json:"metric" binding:"required,oneof=reviews department_reviews helpful_votes"`
+	Threshold   int                `json:"threshold" binding:"required,min=1"`
+}
+
+// ReviewerStats are the published-review counts badge rules are checked against.
+type ReviewerStats struct {
+	Email             string
+	Reviews           int
+	DepartmentReviews int // reviews in the department the reviewer reviewed most
+	HelpfulVotes      int // helpful votes on the reviewer's published reviews
+}
+
+// BadgeAward is a badge held by a reviewer.
+type BadgeAward struct {
+	Email string
+	Badge string
+}
+
+// EarnedBy reports whether a reviewer with stats s has earned the badge.
+func (b Badge) EarnedBy(s ReviewerStats) bool {
+	switch b.Metric {
+	case BadgeMetricReviews:
+		return s.Reviews >= b.Threshold
+	case BadgeMetricDepartmentReviews:
+		return s.DepartmentReviews >= b.Threshold
+	case BadgeMetricHelpfulVotes:
+		return s.HelpfulVotes >= b.Threshold
+	default:
+		return false
+	}
+}
@@ -0,0 +1,29 @@
+package models
+
+import "testing"
+
+func TestBadgeEarnedBy(t *testing.T) {
+	stats := ReviewerStats{Email: "student@yorku.ca", Reviews: 6, DepartmentReviews: 4, HelpfulVotes: 12}
+
+	tests := []struct {
+		name  string
+		badge Badge
+		want  bool
+	}{
+		{"first review", Badge{Metric: BadgeMetricReviews, Threshold: 1}, true},
+		{"review count not reached", Badge{Metric: BadgeMetricReviews, Threshold: 10}, false},
+		{"department threshold reached", Badge{Metric: BadgeMetricDepartmentReviews, Threshold: 4}, true},
+		{"department threshold not reached", Badge{Metric: BadgeMetricDepartmentReviews, Threshold: 5}, false},
+		{"helpful votes reached", Badge{Metric: BadgeMetricHelpfulVotes, Threshold: 10}, true},
+		{"helpful votes not reached", Badge{Metric: BadgeMetricHelpfulVotes, Threshold: 20}, false},
+		{"unknown metric", Badge{Metric: "streak_days", Threshold: 1}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.badge.EarnedBy(stats); got != tt.want {
+				t.Errorf("EarnedBy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
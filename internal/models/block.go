@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Block is a fixed bundle of activities that must be taken together.
+type Block struct {
+	ID         string            `json:"id"`
+	CourseID   string            `json:"course_id"`
+	Name       string            `json:"name"`
+	Activities []SectionActivity `json:"activities"`
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+}
+
+// SetBlockRequest is the body of PUT /api/v1/admin/blocks/:course_id/:name,
+// the activities the block bundles.
+type SetBlockRequest struct {
+	ActivityIDs []string `json:"activity_ids" binding:"required,min=1,max=20,dive,uuid"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockModel(t *testing.T) {
+	block := Block{
+		ID:       "block-1",
+		CourseID: "course-1",
+		Name:     "Block 1",
+		Activities: []SectionActivity{
+			{ID: "act-1", CourseType: ActivityLecture, SectionID: "section-1"},
+			{ID: "act-2", CourseType: ActivityLab, SectionID: "section-1", CatalogNumber: "L01"},
+		},
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	assert.Equal(t, "block-1", block.ID)
+	assert.Equal(t, "course-1", block.CourseID)
+	assert.Equal(t, "Block 1", block.Name)
+	assert.Len(t, block.Activities, 2)
+}
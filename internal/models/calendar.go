@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+	"yuplan/internal/dbtypes"
+)
+
+// MaxCalendarIDs caps how many section and activity ids one calendar export takes.
+const MaxCalendarIDs = 40
+
+// CalendarActivity is an activity to put on a calendar, with the dates of
+// the session its section is offered in.
+type CalendarActivity struct {
+	ID           string
+	CourseCode   string
+	CourseName   string
+	Term         string // the course's term within the session, one of Terms
+	Section      string
+	Type         string
+	Times        dbtypes.NullString
+	SessionStart dbtypes.NullTime // NULL when the section has no session
+	SessionEnd   dbtypes.NullTime
+}
+
+// CourseTermDates narrows a session's dates to the part a course term runs
+// in. Fall and winter courses split a fall/winter session at the new year;
+// first- and second-half summer courses split a summer session in the
+// middle. Other terms run the whole session.
+func CourseTermDates(term string, sessionStart, sessionEnd time.Time) (start, end time.Time) {
+	start, end = sessionStart, sessionEnd
+	switch term {
+	case TermFall:
+		end = time.Date(sessionStart.Year(), time.December, 31, 0, 0, 0, 0, sessionStart.Location())
+	case TermWinter:
+		start = time.Date(sessionStart.Year()+1, time.January, 1, 0, 0, 0, 0, sessionStart.Location())
+	case TermSummer1, TermSummer2:
+		days := int(sessionEnd.Sub(sessionStart).Hours() / 24)
+		middle := sessionStart.AddDate(0, 0, days/2)
+		if term == TermSummer1 {
+			end = middle
+		} else {
+			start = middle.AddDate(0, 0, 1)
+		}
+	}
+	if end.After(sessionEnd) {
+		end = sessionEnd
+	}
+	if start.Before(sessionStart) {
+		start = sessionStart
+	}
+	return start, end
+}
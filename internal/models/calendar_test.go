@@ -0,0 +1,34 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCourseTermDates(t *testing.T) {
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+	}
+	fwStart, fwEnd := day(2025, time.September, 2), day(2026, time.April, 30)
+	suStart, suEnd := day(2026, time.May, 4), day(2026, time.August, 31)
+
+	tests := []struct {
+		term               string
+		sessionStart, end  time.Time
+		wantStart, wantEnd time.Time
+	}{
+		{TermFall, fwStart, fwEnd, fwStart, day(2025, time.December, 31)},
+		{TermWinter, fwStart, fwEnd, day(2026, time.January, 1), fwEnd},
+		{TermFullYear, fwStart, fwEnd, fwStart, fwEnd},
+		{TermSummer1, suStart, suEnd, suStart, day(2026, time.July, 2)},
+		{TermSummer2, suStart, suEnd, day(2026, time.July, 3), suEnd},
+		{TermSummer, suStart, suEnd, suStart, suEnd},
+	}
+	for _, tt := range tests {
+		start, end := CourseTermDates(tt.term, tt.sessionStart, tt.end)
+		if !start.Equal(tt.wantStart) || !end.Equal(tt.wantEnd) {
+			t.Errorf("CourseTermDates(%s) = %s to %s, want %s to %s", tt.term,
+				start.Format(time.DateOnly), end.Format(time.DateOnly), tt.wantStart.Format(time.DateOnly), tt.wantEnd.Format(time.DateOnly))
+		}
+	}
+}
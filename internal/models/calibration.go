@@ -0,0 +1,51 @@
+package models
+
+import (
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Departments rate difficulty on different scales, so a course's average
+// difficulty is also reported relative to the other courses in its department.
+const (
+	CalibrationMethod     = "department_z_score"
+	CalibrationMinReviews = 3 // published reviews a course needs to count toward its department's baseline
+	CalibrationMinCourses = 3 // courses a department needs before it has a baseline
+)
+
+// CourseDifficulty is one course's average difficulty, as read by the calibration job.
+type CourseDifficulty struct {
+	CourseCode    string
+	AvgDifficulty float64
+	Reviews       int
+}
+
+// DepartmentDifficulty is the spread of course average difficulty within a
+// department (difficulty_calibration).
+type DepartmentDifficulty struct {
+	Department string    `json:"department"`
+	Mean       float64   `json:"mean"`
+	StdDev     float64   `json:"stddev"`
+	Courses    int       `json:"courses"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// CalibratedDifficulty is a course's difficulty relative to its department,
+// shown in course review stats next to the raw average.
+type CalibratedDifficulty struct {
+	ZScore     float64              `json:"z_score"` // standard deviations above the department mean
+	Method     string               `json:"method"`
+	MinReviews int                  `json:"min_reviews"`
+	Baseline   DepartmentDifficulty `json:"baseline"`
+}
+
+// DepartmentOf returns the department of a course code: its leading letters.
+func DepartmentOf(courseCode string) string {
+	code := strings.ToUpper(courseCode)
+	end := strings.IndexFunc(code, func(r rune) bool { return !unicode.IsLetter(r) })
+	if end < 0 {
+		return code
+	}
+	return code[:end]
+}
@@ -0,0 +1,18 @@
+package models
+
+import "testing"
+
+func TestDepartmentOf(t *testing.T) {
+	tests := map[string]string{
+		"EECS2030": "EECS",
+		"math1013": "MATH",
+		"SC/BIOL":  "SC",
+		"ADMS":     "ADMS",
+		"":         "",
+	}
+	for code, expected := range tests {
+		if got := DepartmentOf(code); got != expected {
+			t.Errorf("DepartmentOf(%q) = %q, expected %q", code, got, expected)
+		}
+	}
+}
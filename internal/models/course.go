@@ -1,15 +1,86 @@
 package models
 
-import "time"
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+	"yuplan/internal/dbtypes"
+)
+
+// MaxCourseIDs caps how many courses one GET /courses?ids= looks up.
+const MaxCourseIDs = 100
 
 type Course struct {
-	ID          string    `json:"id"`
-	Name        string    `json:"name"`
-	Code        string    `json:"code"`
-	Credits     float64   `json:"credits"`
-	Description *string   `json:"description"`
-	Faculty     string    `json:"faculty"`
-	Term        string    `json:"term"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          string             `json:"id"`
+	Name        string             `json:"name"`
+	Code        string             `json:"code"`
+	Credits     float64            `json:"credits"`
+	Description dbtypes.NullString `json:"description"`
+	Faculty     string             `json:"faculty"`
+	Term        string             `json:"term"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+}
+
+// CourseDetail is everything the course page shows about one course offering.
+// Labs and Tutorials repeat the matching activities from Sections for clients
+// that only want those.
+type CourseDetail struct {
+	Course
+	Sections    []Section         `json:"sections"`
+	Instructors []Instructor      `json:"instructors"`
+	Labs        []SectionActivity `json:"labs"`
+	Tutorials   []SectionActivity `json:"tutorials"`
+	Stats       LiteCourse        `json:"stats"`
+	Deadlines   *TermDeadlines    `json:"deadlines,omitzero"`
+}
+
+// MergeCourseRequest is the body of POST /api/v1/admin/courses/:id/merge,
+// naming the course a duplicate is folded into.
+type MergeCourseRequest struct {
+	Into string `json:"into" binding:"required,uuid"`
+}
+
+// CourseMerge is the course a duplicate was merged into and how much moved
+// onto it.
+type CourseMerge struct {
+	Course   Course `json:"course"`
+	Sections int    `json:"sections"`
+	Blocks   int    `json:"blocks"`
+	Reviews  int    `json:"reviews"`
+}
+
+// courseCodePattern is the shape of every course code the scraper stores:
+// two to four department letters, four digits and sometimes a trailing letter.
+var courseCodePattern = regexp.MustCompile(`^[A-Z]{2,4}[0-9]{4}[A-Z]?$`)
+
+// CreateCourseRequest is the admin payload for adding a course the scraper
+// hasn't picked up, such as a special-topics offering.
+type CreateCourseRequest struct {
+	Code        string             `json:"code" binding:"required,max=50"`
+	Term        string             `json:"term" binding:"required,oneof=F W Y SU S1 S2 S"`
+	Name        string             `json:"name" binding:"required,max=500"`
+	Credits     float64            `json:"credits" binding:"required,gt=0,lt=100"`
+	Description dbtypes.NullString `json:"description"`
+	Faculty     string             `json:"faculty" binding:"required,max=10"`
+}
+
+// Normalize writes the code and faculty the way the scraper does, uppercase
+// and the code without spaces, and checks the code's shape.
+func (r *CreateCourseRequest) Normalize() error {
+	r.Code = strings.ToUpper(strings.ReplaceAll(r.Code, " ", ""))
+	r.Faculty = strings.ToUpper(strings.TrimSpace(r.Faculty))
+	r.Name = strings.TrimSpace(r.Name)
+	if !courseCodePattern.MatchString(r.Code) {
+		return errors.New("code must be a department and course number, e.g. EECS2030")
+	}
+	if r.Name == "" || r.Faculty == "" {
+		return errors.New("name and faculty can't be blank")
+	}
+	// An empty description is stored as none, as the scraper does
+	if r.Description.String = strings.TrimSpace(r.Description.String); r.Description.String == "" {
+		r.Description = dbtypes.NullString{}
+	}
+	return nil
 }
@@ -0,0 +1,112 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+	"yuplan/internal/dbtypes"
+)
+
+// CoursePatchFields are the course fields an admin can edit.
+var CoursePatchFields = []string{"name", "credits", "description", "faculty"}
+
+// CoursePatch is an admin's edit to a course, decoded from a JSON merge patch
+// (RFC 7396): fields the patch leaves out stay nil and are left alone, and a
+// null description clears it. Name, credits and faculty can't be cleared.
+type CoursePatch struct {
+	Name        *string
+	Credits     *float64
+	Description *dbtypes.NullString
+	Faculty     *string
+}
+
+// Empty reports whether the patch sets nothing.
+func (p CoursePatch) Empty() bool {
+	return p.Name == nil && p.Credits == nil && p.Description == nil && p.Faculty == nil
+}
+
+func (p *CoursePatch) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil || fields == nil {
+		return errors.New("patch must be a JSON object")
+	}
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	*p = CoursePatch{}
+	for _, key := range keys {
+		raw := fields[key]
+		if bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+			switch {
+			case key == "description":
+				p.Description = &dbtypes.NullString{}
+				continue
+			case slices.Contains(CoursePatchFields, key):
+				return fmt.Errorf("%s can't be cleared", key)
+			default:
+				return unknownCourseField(key)
+			}
+		}
+
+		switch key {
+		case "name":
+			name, err := patchString(key, raw, 500)
+			if err != nil {
+				return err
+			}
+			p.Name = &name
+		case "credits":
+			var credits float64
+			if err := json.Unmarshal(raw, &credits); err != nil || credits <= 0 || credits >= 100 {
+				return errors.New("credits must be a number between 0 and 100")
+			}
+			p.Credits = &credits
+		case "description":
+			var description string
+			if err := json.Unmarshal(raw, &description); err != nil {
+				return errors.New("description must be a string or null")
+			}
+			// An empty description is stored as none, as the scraper does
+			d := dbtypes.NullString{}
+			if description = strings.TrimSpace(description); description != "" {
+				d = dbtypes.NewNullString(description)
+			}
+			p.Description = &d
+		case "faculty":
+			faculty, err := patchString(key, raw, 10)
+			if err != nil {
+				return err
+			}
+			faculty = strings.ToUpper(faculty)
+			p.Faculty = &faculty
+		default:
+			return unknownCourseField(key)
+		}
+	}
+	return nil
+}
+
+// patchString reads a required text field of at most max characters.
+func patchString(key string, raw json.RawMessage, max int) (string, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return "", fmt.Errorf("%s must be a string", key)
+	}
+	s = strings.TrimSpace(s)
+	if s == "" || utf8.RuneCountInString(s) > max {
+		return "", fmt.Errorf("%s must be 1 to %d characters", key, max)
+	}
+	return s, nil
+}
+
+func unknownCourseField(key string) error {
+	return fmt.Errorf("%s can't be edited; editable fields are %s", key, strings.Join(CoursePatchFields, ", "))
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"yuplan/internal/dbtypes"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoursePatch_UnmarshalJSON(t *testing.T) {
+	var patch CoursePatch
+	assert.NoError(t, json.Unmarshal([]byte(`{"name": " Advanced OOP ", "credits": 4, "faculty": "le"}`), &patch))
+	assert.Equal(t, "Advanced OOP", *patch.Name)
+	assert.Equal(t, 4.0, *patch.Credits)
+	assert.Equal(t, "LE", *patch.Faculty)
+	assert.Nil(t, patch.Description, "left out of the patch")
+	assert.False(t, patch.Empty())
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"description": null}`), &patch))
+	assert.Nil(t, patch.Name, "each patch starts empty")
+	assert.Equal(t, &dbtypes.NullString{}, patch.Description)
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"description": "  "}`), &patch))
+	assert.False(t, patch.Description.Valid, "a blank description is none")
+
+	assert.NoError(t, json.Unmarshal([]byte(`{}`), &patch))
+	assert.True(t, patch.Empty())
+}
+
+func TestCoursePatch_UnmarshalJSON_Invalid(t *testing.T) {
+	tests := map[string]string{
+		`null`:                           "patch must be a JSON object",
+		`{"code": "EECS9999"}`:           "code can't be edited; editable fields are name, credits, description, faculty",
+		`{"term": null}`:                 "term can't be edited",
+		`{"name": null}`:                 "name can't be cleared",
+		`{"name": ""}`:                   "name must be 1 to 500 characters",
+		`{"credits": 0}`:                 "credits must be a number between 0 and 100",
+		`{"credits": 100}`:               "credits must be a number between 0 and 100",
+		`{"description": 3}`:             "description must be a string or null",
+		`{"faculty": "LASSONDE SCHOOL"}`: "faculty must be 1 to 10 characters",
+	}
+	for body, want := range tests {
+		var patch CoursePatch
+		err := json.Unmarshal([]byte(body), &patch)
+		assert.ErrorContains(t, err, want, body)
+	}
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Keys course listings can be sorted by with ?sort=key,asc|desc
+const (
+	CourseSortCode           = "code"
+	CourseSortName           = "name"
+	CourseSortLevel          = "level" // first digit of the course number: 4 for EECS4101
+	CourseSortCredits        = "credits"
+	CourseSortFaculty        = "faculty"
+	CourseSortTerm           = "term"
+	CourseSortAvgDifficulty  = "avg_difficulty"
+	CourseSortLikePercentage = "like_percentage"
+	CourseSortReviewCount    = "review_count"
+)
+
+var CourseSortKeys = []string{
+	CourseSortCode, CourseSortName, CourseSortLevel, CourseSortCredits, CourseSortFaculty, CourseSortTerm,
+	CourseSortAvgDifficulty, CourseSortLikePercentage, CourseSortReviewCount,
+}
+
+// MaxCourseSorts caps how many keys one listing can be sorted by.
+const MaxCourseSorts = 3
+
+// CourseSort is one key of a compound sort.
+type CourseSort struct {
+	Key  string
+	Desc bool
+}
+
+// ParseCourseSorts parses ?sort= values, each "key" or "key,asc|desc", in
+// priority order. Keys must be CourseSortKeys, each used once.
+func ParseCourseSorts(values []string) ([]CourseSort, error) {
+	if len(values) > MaxCourseSorts {
+		return nil, fmt.Errorf("at most %d sort keys", MaxCourseSorts)
+	}
+	sorts := make([]CourseSort, 0, len(values))
+	seen := map[string]bool{}
+	for _, value := range values {
+		key, direction, _ := strings.Cut(strings.ToLower(strings.TrimSpace(value)), ",")
+		key = strings.TrimSpace(key)
+		if !slices.Contains(CourseSortKeys, key) {
+			return nil, fmt.Errorf("unknown sort key %q; expected one of %s", key, strings.Join(CourseSortKeys, ", "))
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("sort key %q given twice", key)
+		}
+		seen[key] = true
+
+		sort := CourseSort{Key: key}
+		switch strings.TrimSpace(direction) {
+		case "", "asc":
+		case "desc":
+			sort.Desc = true
+		default:
+			return nil, fmt.Errorf("sort direction for %q must be asc or desc", key)
+		}
+		sorts = append(sorts, sort)
+	}
+	return sorts, nil
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCourseSorts(t *testing.T) {
+	sorts, err := ParseCourseSorts([]string{"level,asc", "avg_difficulty,DESC", "name"})
+	if err != nil {
+		t.Fatalf("ParseCourseSorts: %v", err)
+	}
+	want := []CourseSort{{Key: CourseSortLevel}, {Key: CourseSortAvgDifficulty, Desc: true}, {Key: CourseSortName}}
+	if !reflect.DeepEqual(sorts, want) {
+		t.Errorf("ParseCourseSorts = %+v, want %+v", sorts, want)
+	}
+
+	for _, bad := range [][]string{
+		{"created_at"},
+		{"code; DROP TABLE courses"},
+		{"level,sideways"},
+		{"level", "level,desc"},
+		{"code", "name", "level", "credits"},
+	} {
+		if _, err := ParseCourseSorts(bad); err == nil {
+			t.Errorf("ParseCourseSorts(%q) = nil error, want one", bad)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package models
 import (
 	"testing"
 	"time"
+	"yuplan/internal/dbtypes"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -26,3 +27,20 @@ func TestCourseModel(t *testing.T) {
 	assert.Equal(t, "SC", course.Faculty)
 	assert.Equal(t, "Fall", course.Term)
 }
+
+func TestCreateCourseRequest_Normalize(t *testing.T) {
+	req := CreateCourseRequest{Code: "eecs 4088", Name: " Special Topics ", Faculty: "le", Description: dbtypes.NewNullString("  ")}
+	assert.NoError(t, req.Normalize())
+	assert.Equal(t, "EECS4088", req.Code)
+	assert.Equal(t, "Special Topics", req.Name)
+	assert.Equal(t, "LE", req.Faculty)
+	assert.False(t, req.Description.Valid, "a blank description is none")
+
+	for _, bad := range []CreateCourseRequest{
+		{Code: "EECS", Name: "Topics", Faculty: "LE"},
+		{Code: "4088", Name: "Topics", Faculty: "LE"},
+		{Code: "EECS4088", Name: "  ", Faculty: "LE"},
+	} {
+		assert.Error(t, bad.Normalize(), bad.Code)
+	}
+}
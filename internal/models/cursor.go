@@ -0,0 +1,62 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ErrBadCursor is returned by DecodeCursor for a cursor it didn't issue.
+var ErrBadCursor = errors.New("cursor is not one this listing issued")
+
+// CourseCursor marks where a keyset page of courses, ordered by code, term
+// and id, left off: the last course it returned.
+type CourseCursor struct {
+	Code string `json:"c"`
+	Term string `json:"t"`
+	ID   string `json:"i"`
+}
+
+// CourseCursorAfter is the cursor for the page after course.
+func CourseCursorAfter(course Course) CourseCursor {
+	return CourseCursor{Code: course.Code, Term: course.Term, ID: course.ID}
+}
+
+// ReviewCursor marks where a keyset page of a course's reviews left off. Sort
+// is the order the page was in, since a cursor only makes sense in that
+// order; Score is the helpful minus not helpful votes for most_helpful.
+type ReviewCursor struct {
+	Sort      string    `json:"s"`
+	Score     int       `json:"h,omitempty"`
+	CreatedAt time.Time `json:"t"`
+	ID        string    `json:"i"`
+}
+
+// ReviewCursorAfter is the cursor for the page after review in sort order.
+func ReviewCursorAfter(sort string, review Review) ReviewCursor {
+	return ReviewCursor{
+		Sort:      sort,
+		Score:     review.HelpfulCount - review.NotHelpfulCount,
+		CreatedAt: review.CreatedAt,
+		ID:        review.ID,
+	}
+}
+
+// EncodeCursor turns a cursor into the opaque next_cursor handed to clients.
+func EncodeCursor(cursor any) string {
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeCursor reads a cursor from EncodeCursor back into cursor.
+func DecodeCursor(s string, cursor any) error {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return ErrBadCursor
+	}
+	if err := json.Unmarshal(raw, cursor); err != nil {
+		return ErrBadCursor
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	review := Review{ID: "review-1", CreatedAt: time.Date(2026, 3, 1, 12, 0, 0, 123456000, time.UTC), HelpfulCount: 5, NotHelpfulCount: 2}
+	want := ReviewCursorAfter(ReviewSortMostHelpful, review)
+
+	var got ReviewCursor
+	if err := DecodeCursor(EncodeCursor(want), &got); err != nil {
+		t.Fatalf("DecodeCursor: %v", err)
+	}
+	if got != want || got.Score != 3 {
+		t.Errorf("round trip = %+v, want %+v", got, want)
+	}
+}
+
+func TestDecodeCursor_Bad(t *testing.T) {
+	for _, raw := range []string{"not base64!", "bm90IGpzb24"} {
+		var cursor CourseCursor
+		if err := DecodeCursor(raw, &cursor); err != ErrBadCursor {
+			t.Errorf("DecodeCursor(%q) = %v, want ErrBadCursor", raw, err)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package models
+
+import "time"
+
+// Data quality issues a course can have after a seed, the scraper gaps worth
+// fixing first. Each costs its weight off a perfect score of 100.
+const (
+	DataIssueMissingDescription = "missing_description"
+	DataIssueNoSections         = "no_sections"
+	DataIssueUnparsedTimes      = "unparsed_times"      // an activity's times column isn't valid JSON
+	DataIssueMissingInstructors = "missing_instructors" // a section has no instructor
+)
+
+var DataIssues = []string{DataIssueMissingDescription, DataIssueNoSections, DataIssueUnparsedTimes, DataIssueMissingInstructors}
+
+var DataIssueWeights = map[string]int{
+	DataIssueMissingDescription: 20,
+	DataIssueNoSections:         50,
+	DataIssueUnparsedTimes:      15,
+	DataIssueMissingInstructors: 15,
+}
+
+// Ways to order GET /api/v1/admin/data-quality
+const (
+	DataQualitySortScore     = "score"  // worst first
+	DataQualitySortScoreDesc = "-score" // best first
+	DataQualitySortChange    = "change" // biggest drop since the previous seed first
+	DataQualitySortCode      = "code"
+)
+
+var DataQualitySorts = []string{DataQualitySortScore, DataQualitySortScoreDesc, DataQualitySortChange, DataQualitySortCode}
+
+// CourseData is what the data quality job reads about a course.
+type CourseData struct {
+	CourseCode                 string
+	Term                       string
+	HasDescription             bool
+	Sections                   int
+	SectionsWithoutInstructors int
+	Times                      []string // non-empty times columns of the course's activities
+}
+
+// CourseQuality is a course's data quality score after one seed
+// (course_data_quality). Courses are keyed by code and term, since a reseed
+// gives them new ids.
+type CourseQuality struct {
+	CourseCode    string    `json:"course_code"`
+	Term          string    `json:"term"`
+	Score         int       `json:"score"`          // 0-100
+	Issues        []string  `json:"issues"`         // in DataIssues order
+	PreviousScore *int      `json:"previous_score"` // after the previous seed; null if the course is new
+	ScoredAt      time.Time `json:"scored_at"`
+}
+
+// HasIssue reports whether the course has the given issue.
+func (q CourseQuality) HasIssue(issue string) bool {
+	for _, i := range q.Issues {
+		if i == issue {
+			return true
+		}
+	}
+	return false
+}
+
+// DataQualityRun summarizes the scores from one seed, for the trend.
+type DataQualityRun struct {
+	ScoredAt     time.Time      `json:"scored_at"`
+	Courses      int            `json:"courses"`
+	AverageScore float64        `json:"average_score"`
+	Issues       map[string]int `json:"issues"` // courses with each issue
+}
+
+// DataQualityFilter selects and orders the latest scores.
+type DataQualityFilter struct {
+	Department string // course code prefix, e.g. EECS; empty for all
+	Term       string
+	Issue      string // one of DataIssues; empty for all
+	MaxScore   int
+	Sort       string // one of DataQualitySorts
+	Limit      int
+}
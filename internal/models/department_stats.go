@@ -0,0 +1,105 @@
+package models
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	// DepartmentRankingMinReviews is how many reviews a course needs before it
+	// can be ranked among a department's most or least liked.
+	DepartmentRankingMinReviews = 3
+	// DepartmentRankingSize is how many courses each ranking lists.
+	DepartmentRankingSize = 5
+)
+
+// DepartmentStats rolls review stats up across a department's courses for
+// its landing page. AvgDifficulty and LikePercentage are null until one of
+// its courses has a review.
+type DepartmentStats struct {
+	Department      string                  `json:"department"`
+	Courses         int                     `json:"courses"`          // distinct course codes in the catalog
+	ReviewedCourses int                     `json:"reviewed_courses"` // of those, the ones with a review
+	Reviews         int                     `json:"reviews"`
+	AvgDifficulty   *float64                `json:"avg_difficulty"`
+	LikePercentage  *int                    `json:"like_percentage"`
+	MostLiked       []DepartmentCourseStats `json:"most_liked"`
+	LeastLiked      []DepartmentCourseStats `json:"least_liked"`
+}
+
+// DepartmentCourseStats is one course's share of its department's reviews.
+type DepartmentCourseStats struct {
+	Code            string  `json:"code"`
+	Name            string  `json:"name"`
+	Reviews         int     `json:"reviews"`
+	LikePercentage  int     `json:"like_percentage"`
+	AvgDifficulty   float64 `json:"avg_difficulty"`
+	Likes           int     `json:"-"`
+	DifficultyTotal int     `json:"-"`
+}
+
+// RollUpDepartment totals courses, which carry Code, Name, Reviews, Likes and
+// DifficultyTotal, into department stats. Courses with at least
+// DepartmentRankingMinReviews reviews are ranked by like percentage, ties
+// going to the course with more reviews.
+func RollUpDepartment(department string, courses []DepartmentCourseStats) *DepartmentStats {
+	stats := &DepartmentStats{
+		Department: department,
+		Courses:    len(courses),
+		MostLiked:  []DepartmentCourseStats{},
+		LeastLiked: []DepartmentCourseStats{},
+	}
+	var likes, difficulty int
+	var ranked []DepartmentCourseStats
+	for _, c := range courses {
+		if c.Reviews == 0 {
+			continue
+		}
+		stats.ReviewedCourses++
+		stats.Reviews += c.Reviews
+		likes += c.Likes
+		difficulty += c.DifficultyTotal
+
+		c.LikePercentage = likePercentage(c.Likes, c.Reviews)
+		c.AvgDifficulty = round1(float64(c.DifficultyTotal) / float64(c.Reviews))
+		if c.Reviews >= DepartmentRankingMinReviews {
+			ranked = append(ranked, c)
+		}
+	}
+	if stats.Reviews == 0 {
+		return stats
+	}
+	avg := round1(float64(difficulty) / float64(stats.Reviews))
+	pct := likePercentage(likes, stats.Reviews)
+	stats.AvgDifficulty, stats.LikePercentage = &avg, &pct
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].LikePercentage != ranked[j].LikePercentage {
+			return ranked[i].LikePercentage > ranked[j].LikePercentage
+		}
+		return ranked[i].Reviews > ranked[j].Reviews
+	})
+	for i := 0; i < len(ranked) && i < DepartmentRankingSize; i++ {
+		stats.MostLiked = append(stats.MostLiked, ranked[i])
+	}
+	// A small department's courses can be both; the least liked come from the
+	// bottom, worst first, with the same tie-break
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].LikePercentage != ranked[j].LikePercentage {
+			return ranked[i].LikePercentage < ranked[j].LikePercentage
+		}
+		return ranked[i].Reviews > ranked[j].Reviews
+	})
+	for i := 0; i < len(ranked) && i < DepartmentRankingSize; i++ {
+		stats.LeastLiked = append(stats.LeastLiked, ranked[i])
+	}
+	return stats
+}
+
+func likePercentage(likes, reviews int) int {
+	return int(float64(likes) / float64(reviews) * 100)
+}
+
+func round1(f float64) float64 {
+	return math.Round(f*10) / 10
+}
@@ -0,0 +1,51 @@
+package models
+
+import "testing"
+
+func TestRollUpDepartment(t *testing.T) {
+	courses := []DepartmentCourseStats{
+		{Code: "EECS1012", Reviews: 4, Likes: 4, DifficultyTotal: 8},
+		{Code: "EECS2030", Reviews: 10, Likes: 5, DifficultyTotal: 40},
+		{Code: "EECS2031", Reviews: 3, Likes: 0, DifficultyTotal: 15},
+		{Code: "EECS3101", Reviews: 2, Likes: 2, DifficultyTotal: 10}, // too few to rank
+		{Code: "EECS4088"},
+	}
+	stats := RollUpDepartment("EECS", courses)
+
+	if stats.Courses != 5 || stats.ReviewedCourses != 4 || stats.Reviews != 19 {
+		t.Errorf("Expected 5 courses, 4 reviewed, 19 reviews, got %+v", stats)
+	}
+	if stats.AvgDifficulty == nil || *stats.AvgDifficulty != 3.8 {
+		t.Errorf("Expected avg difficulty 3.8 (73/19), got %v", stats.AvgDifficulty)
+	}
+	if stats.LikePercentage == nil || *stats.LikePercentage != 57 {
+		t.Errorf("Expected 57%% liked (11/19), got %v", stats.LikePercentage)
+	}
+
+	codes := func(cs []DepartmentCourseStats) []string {
+		out := make([]string, len(cs))
+		for i, c := range cs {
+			out[i] = c.Code
+		}
+		return out
+	}
+	if got := codes(stats.MostLiked); len(got) != 3 || got[0] != "EECS1012" || got[1] != "EECS2030" || got[2] != "EECS2031" {
+		t.Errorf("MostLiked = %v", got)
+	}
+	if got := codes(stats.LeastLiked); len(got) != 3 || got[0] != "EECS2031" || got[2] != "EECS1012" {
+		t.Errorf("LeastLiked = %v", got)
+	}
+	if stats.MostLiked[1].LikePercentage != 50 || stats.MostLiked[1].AvgDifficulty != 4 {
+		t.Errorf("Expected per-course stats to be filled in, got %+v", stats.MostLiked[1])
+	}
+}
+
+func TestRollUpDepartment_NoReviews(t *testing.T) {
+	stats := RollUpDepartment("NURS", []DepartmentCourseStats{{Code: "NURS1000"}})
+	if stats.Reviews != 0 || stats.AvgDifficulty != nil || stats.LikePercentage != nil {
+		t.Errorf("Expected empty stats, got %+v", stats)
+	}
+	if stats.MostLiked == nil || stats.LeastLiked == nil {
+		t.Error("Expected empty rankings rather than nil")
+	}
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+	"time"
+	"yuplan/internal/dbtypes"
+)
+
+// CatalogChange is one difference between two seeds of the catalog.
+type CatalogChange struct {
+	ID         int64              `json:"id"`
+	Department string             `json:"department"`
+	Kind       string             `json:"kind"` // one of CatalogChangeKinds
+	CourseCode string             `json:"course_code"`
+	Term       string             `json:"term"`
+	Section    string             `json:"section,omitempty"`
+	Before     dbtypes.NullString `json:"before,omitzero"` // instructors before the change
+	After      dbtypes.NullString `json:"after,omitzero"`  // instructors after it
+	DetectedAt time.Time          `json:"detected_at"`
+}
+
+// DigestSubscriber is an email's digest frequency and the departments it follows.
+type DigestSubscriber struct {
+	Email       string    `json:"email"`
+	Frequency   string    `json:"frequency"`
+	Departments []string  `json:"departments"`
+	LastSentAt  time.Time `json:"last_sent_at"`
+}
+
+// Digest is the catalog changes due to go out to one subscriber, grouped by
+// department in CatalogChange order.
+type Digest struct {
+	Email   string          `json:"email"`
+	Changes []CatalogChange `json:"changes"`
+}
+
+// Through is when the latest change in the digest was detected, which becomes
+// the subscriber's last_sent_at once it is sent.
+func (d Digest) Through() time.Time {
+	var latest time.Time
+	for _, c := range d.Changes {
+		if c.DetectedAt.After(latest) {
+			latest = c.DetectedAt
+		}
+	}
+	return latest
+}
+
+// SubscribeRequest follows a department's catalog changes. Frequency sets how
+// often the email gets digests, for every department it follows; it defaults
+// to DigestDaily for a new subscriber and is left alone otherwise.
+type SubscribeRequest struct {
+	Email      string `json:"email" binding:"required,email,max=255"`
+	Department string `json:"department" binding:"required,max=10"`
+	Frequency  string `json:"frequency" binding:"omitempty,oneof=immediate daily weekly"`
+}
+
+// SetDigestFrequencyRequest changes how often an email gets digests.
+type SetDigestFrequencyRequest struct {
+	Email     string `json:"email" binding:"required,email,max=255"`
+	Frequency string `json:"frequency" binding:"required,oneof=immediate daily weekly"`
+}
+
+var departmentRe = regexp.MustCompile(`^[A-Z]{2,10}$`)
+
+// NormalizeDepartment upper-cases a department code such as "eecs", reporting
+// false if it can't be one.
+func NormalizeDepartment(department string) (string, bool) {
+	department = strings.ToUpper(strings.TrimSpace(department))
+	return department, departmentRe.MatchString(department)
+}
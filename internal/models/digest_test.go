@@ -0,0 +1,39 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNormalizeDepartment(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{"EECS", "EECS", true},
+		{" math ", "MATH", true},
+		{"EECS2030", "EECS2030", false},
+		{"E", "E", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := NormalizeDepartment(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("NormalizeDepartment(%q) = %q, %v; want %q, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestDigestThrough(t *testing.T) {
+	first := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	last := first.Add(time.Hour)
+	d := Digest{Changes: []CatalogChange{{DetectedAt: last}, {DetectedAt: first}}}
+
+	if got := d.Through(); !got.Equal(last) {
+		t.Errorf("Through() = %v, want %v", got, last)
+	}
+	if got := (Digest{}).Through(); !got.IsZero() {
+		t.Errorf("Through() of an empty digest = %v, want zero", got)
+	}
+}
@@ -0,0 +1,361 @@
+package models
+
+// Canonical enumerations shared with clients via GET /api/v1/meta/enums.
+// Keep these in sync with the values produced by the scrapers in scraping/.
+
+// Activity types (section_activities.course_type)
+const (
+	ActivityLecture            = "LECT"
+	ActivityTutorial           = "TUTR"
+	ActivityLab                = "LAB"
+	ActivitySeminar            = "SEMR"
+	ActivityOnline             = "ONLN"
+	ActivityStudio             = "STDO"
+	ActivityBlended            = "BLEN"
+	ActivityPracticum          = "PRAC"
+	ActivityIndependentStudy   = "ISTY"
+	ActivityDirectedReading    = "DIRD"
+	ActivityOnlineCampus       = "ONCA"
+	ActivityFieldExperience    = "FDEX"
+	ActivityInternship         = "INSP"
+	ActivityLanguageLab        = "LGCL"
+	ActivityHyflex             = "HYFX"
+	ActivityThesis             = "THES"
+	ActivityResearchProject    = "RESP"
+	ActivityReviewEvaluation   = "REEV"
+	ActivityIndependentDirect  = "IDS"
+	ActivityFieldTrip          = "FIEL"
+	ActivityPerformance        = "PERF"
+	ActivityCoop               = "COOP"
+	ActivityWorkshop           = "WKSP"
+	ActivityClinical           = "CLIN"
+	ActivityDissertation       = "DISS"
+	ActivityReviewPresentation = "REVP"
+)
+
+// ActivityTypes lists every known activity type in display order.
+var ActivityTypes = []string{
+	ActivityLecture, ActivityTutorial, ActivityLab, ActivitySeminar, ActivityOnline,
+	ActivityStudio, ActivityBlended, ActivityPracticum, ActivityIndependentStudy,
+	ActivityDirectedReading, ActivityOnlineCampus, ActivityFieldExperience,
+	ActivityInternship, ActivityLanguageLab, ActivityHyflex, ActivityThesis,
+	ActivityResearchProject, ActivityReviewEvaluation, ActivityIndependentDirect,
+	ActivityFieldTrip, ActivityPerformance, ActivityCoop, ActivityWorkshop,
+	ActivityClinical, ActivityDissertation, ActivityReviewPresentation,
+}
+
+// Campuses as they appear in section activity meeting times.
+var Campuses = []string{
+	"Keele",
+	"Glendon",
+	"Markham",
+	"Off Campus",
+	"Catholic Education Centre",
+	"Seneca at York",
+	"Toronto Metropolitan Univ",
+}
+
+// Term codes (courses.term)
+const (
+	TermFall      = "F"
+	TermWinter    = "W"
+	TermFullYear  = "Y"
+	TermSummer    = "SU"
+	TermSummer1   = "S1"
+	TermSummer2   = "S2"
+	TermSummerAlt = "S"
+)
+
+var Terms = []string{TermFall, TermWinter, TermFullYear, TermSummer, TermSummer1, TermSummer2, TermSummerAlt}
+
+// Session codes (terms.session)
+const (
+	SessionFallWinter = "FW" // terms F, W and Y
+	SessionSummer     = "SU" // terms SU, S1, S2 and S
+)
+
+var Sessions = []string{SessionFallWinter, SessionSummer}
+
+// Offering frequencies (course_offering_summaries.frequency)
+const (
+	OfferingAnnual      = "annual"      // offered every academic year on record
+	OfferingAlternating = "alternating" // offered every other academic year
+	OfferingIrregular   = "irregular"   // gaps with no consistent pattern
+	OfferingSingle      = "single"      // only one academic year on record
+)
+
+var OfferingFrequencies = []string{OfferingAnnual, OfferingAlternating, OfferingIrregular, OfferingSingle}
+
+// Whether a course is expected in a given term (GET /courses/:course_code/offering)
+const (
+	OfferingLikely   = "likely"
+	OfferingUnlikely = "unlikely"
+	OfferingUnknown  = "unknown"
+)
+
+// Activity delivery, derived from section_activities.times
+const (
+	DeliveryScheduled    = "scheduled"    // meets at set weekly times
+	DeliveryAsynchronous = "asynchronous" // no meeting times; doesn't occupy timetable slots
+)
+
+var Deliveries = []string{DeliveryScheduled, DeliveryAsynchronous}
+
+// Review sort modes accepted by GET /courses/:course_code/reviews
+const (
+	ReviewSortRecent      = "recent"
+	ReviewSortEarliest    = "earliest"
+	ReviewSortMostHelpful = "most_helpful" // helpful minus not helpful votes, then recent
+)
+
+var ReviewSortModes = []string{ReviewSortRecent, ReviewSortEarliest, ReviewSortMostHelpful}
+
+// Review publishing states. Reviews submitted during an exam period are
+// embargoed until grades for the term are released.
+const (
+	ReviewPublished  = "published"
+	ReviewEmbargoed  = "embargoed"
+	ReviewPending    = "pending"    // held by moderation until an admin publishes it
+	ReviewUnverified = "unverified" // waiting for its author to confirm their email
+)
+
+var ReviewStatuses = []string{ReviewPublished, ReviewEmbargoed, ReviewPending, ReviewUnverified}
+
+// How a reviewer took the course (reviews.delivery_mode). Optional, since
+// the same course can differ a lot between online and in-person offerings.
+const (
+	ReviewDeliveryInPerson = "in_person"
+	ReviewDeliveryOnline   = "online"
+	ReviewDeliveryHybrid   = "hybrid"
+)
+
+var ReviewDeliveryModes = []string{ReviewDeliveryInPerson, ReviewDeliveryOnline, ReviewDeliveryHybrid}
+
+// IsReviewDeliveryMode reports whether mode is in ReviewDeliveryModes.
+func IsReviewDeliveryMode(mode string) bool {
+	for _, m := range ReviewDeliveryModes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// Review lifecycle events (review_events.event), recorded for funnel analytics
+const (
+	ReviewEventCreated   = "created"
+	ReviewEventVerified  = "verified"
+	ReviewEventEdited    = "edited"
+	ReviewEventReported  = "reported"
+	ReviewEventModerated = "moderated" // an admin published or embargoed it
+	ReviewEventDeleted   = "deleted"
+)
+
+var ReviewEvents = []string{
+	ReviewEventCreated, ReviewEventVerified, ReviewEventEdited,
+	ReviewEventReported, ReviewEventModerated, ReviewEventDeleted,
+}
+
+// Review tags: "who should take this" labels reviewers can attach to a review
+const (
+	ReviewTagHeavyWorkload     = "heavy-workload"
+	ReviewTagLightWorkload     = "light-workload"
+	ReviewTagMathHeavy         = "math-heavy"
+	ReviewTagProgrammingHeavy  = "programming-heavy"
+	ReviewTagWritingHeavy      = "writing-heavy"
+	ReviewTagGreatForBeginners = "great-for-beginners"
+	ReviewTagNeedsPrereqs      = "strong-prereqs-needed"
+	ReviewTagGoodElective      = "good-elective"
+	ReviewTagCareerRelevant    = "career-relevant"
+	ReviewTagGroupProjects     = "group-projects"
+	ReviewTagExamHeavy         = "exam-heavy"
+	ReviewTagAttendanceMatters = "attendance-matters"
+)
+
+var ReviewTags = []string{
+	ReviewTagHeavyWorkload, ReviewTagLightWorkload, ReviewTagMathHeavy,
+	ReviewTagProgrammingHeavy, ReviewTagWritingHeavy, ReviewTagGreatForBeginners,
+	ReviewTagNeedsPrereqs, ReviewTagGoodElective, ReviewTagCareerRelevant,
+	ReviewTagGroupProjects, ReviewTagExamHeavy, ReviewTagAttendanceMatters,
+}
+
+// MaxReviewTags caps how many tags a single review may carry.
+const MaxReviewTags = 3
+
+// IsReviewTag reports whether tag is in the curated ReviewTags list.
+func IsReviewTag(tag string) bool {
+	for _, t := range ReviewTags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Badge metrics (badges.metric): what a badge rule's threshold counts
+const (
+	BadgeMetricReviews           = "reviews"            // published reviews
+	BadgeMetricDepartmentReviews = "department_reviews" // published reviews within a single department
+	BadgeMetricHelpfulVotes      = "helpful_votes"      // helpful votes on published reviews
+)
+
+var BadgeMetrics = []string{BadgeMetricReviews, BadgeMetricDepartmentReviews, BadgeMetricHelpfulVotes}
+
+// Review moderation states (reviews.moderation). Pending and unverified
+// reviews are left out of every public read, whatever their publish_at.
+const (
+	ModerationApproved   = "approved"
+	ModerationPending    = "pending"
+	ModerationUnverified = "unverified" // author has not followed the emailed link yet
+)
+
+// What a moderation rule does with a new review that meets all its conditions
+// (moderation_rules.action)
+const (
+	ModerationActionApprove = "approve"
+	ModerationActionQueue   = "queue"
+)
+
+var ModerationActions = []string{ModerationActionApprove, ModerationActionQueue}
+
+// How often a subscriber gets a catalog digest (digest_subscribers.frequency).
+// An immediate digest goes out on the first digest run after a change.
+const (
+	DigestImmediate = "immediate"
+	DigestDaily     = "daily"
+	DigestWeekly    = "weekly"
+)
+
+var DigestFrequencies = []string{DigestImmediate, DigestDaily, DigestWeekly}
+
+// Catalog changes found between seeds (catalog_changes.kind)
+const (
+	CatalogNewCourse        = "new_course"
+	CatalogRemovedSection   = "removed_section"
+	CatalogInstructorChange = "instructor_change"
+)
+
+var CatalogChangeKinds = []string{CatalogNewCourse, CatalogRemovedSection, CatalogInstructorChange}
+
+// Transfer equivalency confidence levels, most to least certain
+const (
+	EquivalencyConfidenceHigh   = "high"
+	EquivalencyConfidenceMedium = "medium"
+	EquivalencyConfidenceLow    = "low"
+)
+
+var EquivalencyConfidences = []string{EquivalencyConfidenceHigh, EquivalencyConfidenceMedium, EquivalencyConfidenceLow}
+
+// ConfidenceRank orders confidence levels for sorting; unknown values sort last.
+func ConfidenceRank(confidence string) int {
+	for i, c := range EquivalencyConfidences {
+		if c == confidence {
+			return i
+		}
+	}
+	return len(EquivalencyConfidences)
+}
+
+// Course requisite kinds (course_requisites.kind)
+const (
+	RequisitePrerequisite = "prerequisite" // must be completed first
+	RequisiteCorequisite  = "corequisite"  // completed first or taken at the same time
+	RequisiteExclusion    = "exclusion"    // no credit for both
+)
+
+var RequisiteKinds = []string{RequisitePrerequisite, RequisiteCorequisite, RequisiteExclusion}
+
+// Seed quarantine statuses (seed_quarantine.status)
+const (
+	QuarantinePending     = "pending"     // awaiting admin review
+	QuarantineReprocessed = "reprocessed" // fixed and inserted by an admin
+	QuarantineDismissed   = "dismissed"   // reviewed and intentionally left out
+)
+
+var QuarantineStatuses = []string{QuarantinePending, QuarantineReprocessed, QuarantineDismissed}
+
+// What a report is about (reports.entity_type)
+const (
+	ReportEntityCourse     = "course"
+	ReportEntityInstructor = "instructor"
+	ReportEntityReview     = "review"
+)
+
+// Report types (reports.type)
+const (
+	ReportWrongInstructorInfo     = "wrong_instructor_info"     // name or section assignment is wrong
+	ReportBrokenRMPLink           = "broken_rmp_link"           // RateMyProfessors link is dead or points at someone else
+	ReportOffensiveCourseResource = "offensive_course_resource" // description or linked material is offensive
+	ReportInappropriateReview     = "inappropriate_review"      // offensive, spam, or not about the course
+)
+
+var ReportTypes = []string{ReportWrongInstructorInfo, ReportBrokenRMPLink, ReportOffensiveCourseResource, ReportInappropriateReview}
+
+// ReportTypeEntities is the kind of entity each report type refers to.
+var ReportTypeEntities = map[string]string{
+	ReportWrongInstructorInfo:     ReportEntityInstructor,
+	ReportBrokenRMPLink:           ReportEntityInstructor,
+	ReportOffensiveCourseResource: ReportEntityCourse,
+	ReportInappropriateReview:     ReportEntityReview,
+}
+
+// Metadata report statuses (reports.status)
+const (
+	ReportOpen      = "open"      // awaiting admin review
+	ReportResolved  = "resolved"  // the metadata was fixed, or the review taken down
+	ReportDismissed = "dismissed" // reviewed and nothing needed changing
+)
+
+var ReportStatuses = []string{ReportOpen, ReportResolved, ReportDismissed}
+
+// Instructor claim statuses (instructor_claims.status)
+const (
+	ClaimUnverified = "unverified" // the link mailed to the claimed email hasn't been followed
+	ClaimPending    = "pending"    // email confirmed, awaiting admin approval
+	ClaimApproved   = "approved"   // the email holder is the instructor
+	ClaimRejected   = "rejected"
+)
+
+var ClaimStatuses = []string{ClaimUnverified, ClaimPending, ClaimApproved, ClaimRejected}
+
+// Why a review was reported (reports.reason); only review reports have one
+const (
+	ReportReasonSpam           = "spam"
+	ReportReasonHarassment     = "harassment"
+	ReportReasonMisinformation = "misinformation" // wrong about the course, its grading or its instructor
+	ReportReasonOffTopic       = "off_topic"
+	ReportReasonOther          = "other" // the default, and every report filed before reasons existed
+)
+
+var ReportReasons = []string{ReportReasonSpam, ReportReasonHarassment, ReportReasonMisinformation, ReportReasonOffTopic, ReportReasonOther}
+
+// Periods report trends are bucketed by, as date_trunc names them
+const (
+	TrendDay   = "day"
+	TrendWeek  = "week" // starting Monday
+	TrendMonth = "month"
+)
+
+var TrendIntervals = []string{TrendDay, TrendWeek, TrendMonth}
+
+// Machine-readable error codes returned alongside error messages.
+const (
+	ErrCodeBadRequest      = "bad_request"
+	ErrCodeInvalidID       = "invalid_id"
+	ErrCodeNotFound        = "not_found"
+	ErrCodeConflict        = "conflict"
+	ErrCodeDuplicateReview = "duplicate_review"
+	ErrCodeRateLimited     = "rate_limited"
+	ErrCodeContentRejected = "content_rejected" // caught by the review content filter
+	ErrCodeInternal        = "internal_error"
+	ErrCodeTimeout         = "timeout"
+	ErrCodeUnavailable     = "unavailable"
+	// If-Match named a version of the resource that is no longer current
+	ErrCodePreconditionFailed = "precondition_failed"
+)
+
+var ErrorCodes = []string{
+	ErrCodeBadRequest, ErrCodeInvalidID, ErrCodeNotFound, ErrCodeConflict,
+	ErrCodeDuplicateReview, ErrCodeRateLimited, ErrCodeInternal, ErrCodeTimeout,
+	ErrCodeUnavailable, ErrCodeContentRejected, ErrCodePreconditionFailed,
+}
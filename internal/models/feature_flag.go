@@ -0,0 +1,6 @@
+package models
+
+// SetFeatureFlagRequest is the body of PUT /api/v1/admin/config/flags/:flag.
+type SetFeatureFlagRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
@@ -0,0 +1,7 @@
+package models
+
+// RecordSeenRequest marks courses as seen in a reviewer's course feed.
+type RecordSeenRequest struct {
+	Email       string   `json:"email" binding:"required,email"`
+	CourseCodes []string `json:"course_codes" binding:"required,min=1,max=100,dive,required,max=50"`
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// FilterPreset is a named set of course browsing filters an email saved, run
+// with GET /courses?preset=. Empty filters match every course.
+type FilterPreset struct {
+	ID              string    `json:"id"`
+	Email           string    `json:"email" redact:"admin"`
+	Name            string    `json:"name"`
+	Faculty         string    `json:"faculty"`
+	CourseCodeRange string    `json:"course_code_range"` // e.g. "3000s", or "5000s+" for 5000 and up
+	TermID          string    `json:"term_id"`           // a Term id such as FW2025
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// FilterPresetRequest creates or replaces a preset. Email must be the owner's
+// when replacing.
+type FilterPresetRequest struct {
+	Email           string `json:"email" binding:"required,email,max=255"`
+	Name            string `json:"name" binding:"required,max=100"`
+	Faculty         string `json:"faculty" binding:"max=10"`
+	CourseCodeRange string `json:"course_code_range"`
+	TermID          string `json:"term_id"`
+}
+
+var courseCodeRangePattern = regexp.MustCompile(`^[1-9]000s\+?$`)
+
+// Normalize trims the request and puts its filters in the form the course
+// queries expect. It returns a message for the first filter that isn't valid.
+func (r *FilterPresetRequest) Normalize() (problem string) {
+	r.Email = strings.TrimSpace(r.Email)
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" {
+		return "name must not be blank"
+	}
+	r.Faculty = strings.ToUpper(strings.TrimSpace(r.Faculty))
+	r.CourseCodeRange = strings.ToLower(strings.TrimSpace(r.CourseCodeRange))
+	if r.CourseCodeRange != "" && !courseCodeRangePattern.MatchString(r.CourseCodeRange) {
+		return "course_code_range must be a level such as 3000s, or 5000s+ for 5000 and up"
+	}
+	if strings.TrimSpace(r.TermID) != "" {
+		termID, ok := ParseTermID(r.TermID)
+		if !ok {
+			return "term_id must be a session and year, like FW2025 or SU2026"
+		}
+		r.TermID = termID
+	}
+	return ""
+}
@@ -0,0 +1,31 @@
+package models
+
+import "testing"
+
+func TestFilterPresetRequest_Normalize(t *testing.T) {
+	req := FilterPresetRequest{Email: " a@yorku.ca ", Name: " 3000-level EECS ", Faculty: " le ", CourseCodeRange: "3000S", TermID: "fw2025"}
+	if problem := req.Normalize(); problem != "" {
+		t.Fatalf("Normalize() = %q", problem)
+	}
+	want := FilterPresetRequest{Email: "a@yorku.ca", Name: "3000-level EECS", Faculty: "LE", CourseCodeRange: "3000s", TermID: "FW2025"}
+	if req != want {
+		t.Errorf("Normalize() left %+v, want %+v", req, want)
+	}
+
+	empty := FilterPresetRequest{Email: "a@yorku.ca", Name: "Everything"}
+	if problem := empty.Normalize(); problem != "" {
+		t.Errorf("Expected empty filters to be allowed, got %q", problem)
+	}
+
+	for _, bad := range []FilterPresetRequest{
+		{Name: "  "},
+		{Name: "x", CourseCodeRange: "3500s"},
+		{Name: "x", CourseCodeRange: "3000"},
+		{Name: "x", CourseCodeRange: "0000s"},
+		{Name: "x", TermID: "F2025"},
+	} {
+		if problem := bad.Normalize(); problem == "" {
+			t.Errorf("Expected %+v to be rejected", bad)
+		}
+	}
+}
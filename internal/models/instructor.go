@@ -1,14 +1,77 @@
 package models
 
-import "time"
+import (
+	"time"
+	"yuplan/internal/dbtypes"
+)
+
+// MaxInstructorIDs caps how many instructors one GET /instructors?ids= looks up.
+const MaxInstructorIDs = 100
 
 type Instructor struct {
-	ID            string     `json:"id"`
-	FirstName     string     `json:"first_name"`
-	LastName      string     `json:"last_name"`
-	RateMyProfLink *string   `json:"rate_my_prof_link,omitempty"`
-	SectionID     *string    `json:"section_id,omitempty"`
-	CreatedAt     time.Time  `json:"created_at"`
-	UpdatedAt     time.Time  `json:"updated_at"`
+	ID             string             `json:"id"`
+	FirstName      string             `json:"first_name"`
+	LastName       string             `json:"last_name"`
+	RateMyProfLink dbtypes.NullString `json:"rate_my_prof_link,omitzero"`
+	SectionID      dbtypes.NullString `json:"section_id,omitzero"`
+	PhotoID        dbtypes.NullString `json:"photo_id,omitzero"` // Stored photo; see Photo
+	Photo          *InstructorPhoto   `json:"photo,omitempty"`   // URLs of PhotoID; filled in by the handler
+	RMP            *InstructorRating  `json:"rmp,omitempty"`     // RateMyProfessors rating; nil until a match is found
+	CreatedAt      time.Time          `json:"created_at"`
+	UpdatedAt      time.Time          `json:"updated_at"`
+}
+
+// InstructorPhoto is where each standard size of an instructor's photo is
+// served. The URLs never change what they serve, so they can be cached forever.
+type InstructorPhoto struct {
+	Small  string `json:"small"`
+	Medium string `json:"medium"`
+	Large  string `json:"large"`
+}
+
+// RMPProfessorURL is the RateMyProfessors page of a professor, by their RMP id.
+const RMPProfessorURL = "https://www.ratemyprofessors.com/professor/"
+
+// InstructorName is how instructor rows that are the same person are matched.
+type InstructorName struct {
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
 }
 
+// InstructorRating is an instructor's RateMyProfessors rating as last
+// fetched. Ratings are kept by name, like instructor reviews.
+type InstructorRating struct {
+	RMPID          string    `json:"rmp_id"`
+	URL            string    `json:"url"`              // RMPProfessorURL of RMPID
+	Rating         *float64  `json:"rating"`           // 1 to 5; null with no ratings
+	Difficulty     *float64  `json:"difficulty"`       // 1 to 5; likewise
+	WouldTakeAgain *int      `json:"would_take_again"` // percent; null when nobody answered
+	NumRatings     int       `json:"num_ratings"`
+	FetchedAt      time.Time `json:"fetched_at"`
+}
+
+// InstructorProfile is everything known about an instructor across the
+// sections they teach. The seed writes one instructor row per section, so the
+// rows are merged by name; ID is the row the profile was looked up by.
+type InstructorProfile struct {
+	ID             string             `json:"id"`
+	FirstName      string             `json:"first_name"`
+	LastName       string             `json:"last_name"`
+	RateMyProfLink dbtypes.NullString `json:"rate_my_prof_link,omitzero"`
+	PhotoID        dbtypes.NullString `json:"photo_id,omitzero"`
+	Photo          *InstructorPhoto   `json:"photo,omitempty"`
+	RMP            *InstructorRating  `json:"rmp,omitempty"`
+	SectionCount   int                `json:"section_count"`
+	CourseCount    int                `json:"course_count"` // distinct course codes
+	Terms          []string           `json:"terms"`
+}
+
+// InstructorCourse is a course offering an instructor teaches, with the
+// sections they teach in it.
+type InstructorCourse struct {
+	CourseID string   `json:"course_id"`
+	Code     string   `json:"code"`
+	Name     string   `json:"name"`
+	Term     string   `json:"term"`
+	Sections []string `json:"sections"`
+}
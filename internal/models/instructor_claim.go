@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+	"yuplan/internal/dbtypes"
+)
+
+// InstructorClaim is someone's claim, made with their institutional email,
+// to be an instructor. Claims are kept by name, like instructor reviews, so
+// one covers every section the instructor teaches. An approved claim links
+// the instructor to that email.
+type InstructorClaim struct {
+	ID         string           `json:"id"`
+	FirstName  string           `json:"first_name"`
+	LastName   string           `json:"last_name"`
+	Email      string           `json:"email,omitzero" redact:"admin"`
+	Status     string           `json:"status"` // One of ClaimStatuses
+	CreatedAt  time.Time        `json:"created_at"`
+	VerifiedAt dbtypes.NullTime `json:"verified_at"`
+	DecidedAt  dbtypes.NullTime `json:"decided_at"`
+}
+
+type CreateInstructorClaimRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
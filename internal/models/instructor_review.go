@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+	"yuplan/internal/dbtypes"
+)
+
+// InstructorReview rates an instructor, whichever course it was in. Ratings
+// run from 1 to 5; a higher workload means more work.
+type InstructorReview struct {
+	ID           string             `json:"id"`
+	Email        string             `json:"email,omitempty" redact:"admin"` // Reviewer email; stripped for everyone but admins
+	AuthorName   dbtypes.NullString `json:"author_name"`                    // Nullable: null = anonymous
+	Clarity      int                `json:"clarity"`
+	Helpfulness  int                `json:"helpfulness"`
+	Workload     int                `json:"workload"`
+	ReviewText   dbtypes.NullString `json:"review_text"`   // Raw markdown as submitted
+	RenderedHTML dbtypes.NullString `json:"rendered_html"` // Sanitized HTML of ReviewText; computed, not stored
+	CreatedAt    time.Time          `json:"created_at"`
+	UpdatedAt    time.Time          `json:"updated_at"`
+}
+
+type CreateInstructorReviewRequest struct {
+	Email       string             `json:"email" binding:"required,email"`
+	AuthorName  dbtypes.NullString `json:"author_name"` // Optional: provide name or leave null for "Anonymous"
+	Clarity     int                `json:"clarity" binding:"required,min=1,max=5"`
+	Helpfulness int                `json:"helpfulness" binding:"required,min=1,max=5"`
+	Workload    int                `json:"workload" binding:"required,min=1,max=5"`
+	ReviewText  dbtypes.NullString `json:"review_text"`
+}
+
+// InstructorReviewStats aggregates every review of an instructor; the
+// averages are 0 when there are none.
+type InstructorReviewStats struct {
+	TotalReviews   int     `json:"total_reviews"`
+	AvgClarity     float64 `json:"avg_clarity"`
+	AvgHelpfulness float64 `json:"avg_helpfulness"`
+	AvgWorkload    float64 `json:"avg_workload"`
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"sort"
+	"yuplan/internal/dbtypes"
+)
+
+// Weekdays in meeting-time notation, Monday first.
+var Weekdays = []string{"M", "T", "W", "R", "F", "S", "U"}
+
+// TeachingActivity is a section activity taught by an instructor, with its raw times column.
+type TeachingActivity struct {
+	CourseCode string
+	Term       string
+	Section    string
+	Type       string
+	Times      dbtypes.NullString
+}
+
+// TeachingMeeting is one weekly meeting in an instructor's schedule.
+type TeachingMeeting struct {
+	CourseCode string `json:"course_code"`
+	Term       string `json:"term"`
+	Section    string `json:"section"`
+	Type       string `json:"type"`
+	Start      int    `json:"start"` // minutes since midnight
+	End        int    `json:"end"`
+	Campus     string `json:"campus"`
+	Room       string `json:"room"`
+}
+
+// ScheduleDay is one column of a week grid.
+type ScheduleDay struct {
+	Day      string            `json:"day"`
+	Meetings []TeachingMeeting `json:"meetings"`
+}
+
+// WeekGrid lays out the scheduled meetings of activities by weekday, every
+// day from Monday to Sunday, each day's meetings earliest first. Activities
+// whose times can't be read are left out, as are asynchronous meetings.
+func WeekGrid(activities []TeachingActivity) []ScheduleDay {
+	byDay := map[string][]TeachingMeeting{}
+	for _, a := range activities {
+		meetings, err := ScheduledMeetings(a.Times)
+		if err != nil {
+			continue
+		}
+		for _, m := range meetings {
+			start, end, ok := m.Window()
+			if !ok {
+				continue
+			}
+			byDay[m.Day] = append(byDay[m.Day], TeachingMeeting{
+				CourseCode: a.CourseCode,
+				Term:       a.Term,
+				Section:    a.Section,
+				Type:       a.Type,
+				Start:      start,
+				End:        end,
+				Campus:     m.Campus,
+				Room:       m.Room,
+			})
+		}
+	}
+
+	days := make([]ScheduleDay, 0, len(Weekdays))
+	for _, day := range Weekdays {
+		meetings := byDay[day]
+		if meetings == nil {
+			meetings = []TeachingMeeting{}
+		}
+		sort.SliceStable(meetings, func(i, j int) bool { return meetings[i].Start < meetings[j].Start })
+		days = append(days, ScheduleDay{Day: day, Meetings: meetings})
+	}
+	return days
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"testing"
+	"yuplan/internal/dbtypes"
+)
+
+func TestWeekGrid(t *testing.T) {
+	days := WeekGrid([]TeachingActivity{
+		{
+			CourseCode: "EECS2030", Term: TermFall, Section: "A", Type: ActivityLecture,
+			Times: dbtypes.NewNullString(`[{"day": "T", "time": "14:30", "duration": "80", "campus": "Keele", "room": "LAS B"},
+				{"day": "R", "time": "14:30", "duration": "80", "campus": "Keele", "room": "LAS B"}]`),
+		},
+		{
+			CourseCode: "EECS3101", Term: TermFall, Section: "M", Type: ActivityLecture,
+			Times: dbtypes.NewNullString(`[{"day": "T", "time": "8:30", "duration": "80", "campus": "Keele", "room": "CLH I"}]`),
+		},
+		{CourseCode: "EECS1001", Term: TermFall, Section: "Z", Type: ActivityOnline, Times: dbtypes.NewNullString(`[{"day": "", "time": "0:00", "duration": "0"}]`)},
+		{CourseCode: "EECS4000", Term: TermFall, Section: "B", Type: ActivityLecture, Times: dbtypes.NewNullString("not json")},
+	})
+
+	if len(days) != len(Weekdays) {
+		t.Fatalf("Expected every weekday, got %d days", len(days))
+	}
+	tuesday := days[1]
+	if tuesday.Day != "T" || len(tuesday.Meetings) != 2 {
+		t.Fatalf("Unexpected Tuesday: %+v", tuesday)
+	}
+	if tuesday.Meetings[0].CourseCode != "EECS3101" || tuesday.Meetings[0].Start != 510 || tuesday.Meetings[0].End != 590 {
+		t.Errorf("Expected the 8:30 EECS3101 lecture first, got %+v", tuesday.Meetings[0])
+	}
+	if got := days[3]; got.Day != "R" || len(got.Meetings) != 1 || got.Meetings[0].Room != "LAS B" {
+		t.Errorf("Unexpected Thursday: %+v", got)
+	}
+	if days[0].Meetings == nil || len(days[0].Meetings) != 0 {
+		t.Errorf("Expected an empty, non-nil Monday, got %+v", days[0].Meetings)
+	}
+}
@@ -1,8 +1,10 @@
 package models
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
+	"yuplan/internal/dbtypes"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -15,8 +17,8 @@ func TestInstructorModel(t *testing.T) {
 		ID:            "instructor-1",
 		FirstName:     "John",
 		LastName:      "Doe",
-		RateMyProfLink: &rmpLink,
-		SectionID:     &sectionID,
+		RateMyProfLink: dbtypes.NewNullString(rmpLink),
+		SectionID:     dbtypes.NewNullString(sectionID),
 		CreatedAt:     time.Now(),
 		UpdatedAt:     time.Now(),
 	}
@@ -24,10 +26,10 @@ func TestInstructorModel(t *testing.T) {
 	assert.Equal(t, "instructor-1", instructor.ID)
 	assert.Equal(t, "John", instructor.FirstName)
 	assert.Equal(t, "Doe", instructor.LastName)
-	assert.NotNil(t, instructor.RateMyProfLink)
-	assert.Equal(t, rmpLink, *instructor.RateMyProfLink)
-	assert.NotNil(t, instructor.SectionID)
-	assert.Equal(t, sectionID, *instructor.SectionID)
+	assert.True(t, instructor.RateMyProfLink.Valid)
+	assert.Equal(t, rmpLink, instructor.RateMyProfLink.String)
+	assert.True(t, instructor.SectionID.Valid)
+	assert.Equal(t, sectionID, instructor.SectionID.String)
 }
 
 func TestInstructorModel_WithNilFields(t *testing.T) {
@@ -42,7 +44,19 @@ func TestInstructorModel_WithNilFields(t *testing.T) {
 	assert.Equal(t, "instructor-2", instructor.ID)
 	assert.Equal(t, "Jane", instructor.FirstName)
 	assert.Equal(t, "Smith", instructor.LastName)
-	assert.Nil(t, instructor.RateMyProfLink)
-	assert.Nil(t, instructor.SectionID)
+	assert.False(t, instructor.RateMyProfLink.Valid)
+	assert.False(t, instructor.SectionID.Valid)
 }
 
+
+func TestInstructorModel_JSONOmitsNullOptionalFields(t *testing.T) {
+	instructor := Instructor{ID: "instructor-3", FirstName: "Ada", LastName: "Lovelace"}
+
+	data, err := json.Marshal(instructor)
+	assert.NoError(t, err)
+
+	var decoded map[string]interface{}
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.NotContains(t, decoded, "rate_my_prof_link")
+	assert.NotContains(t, decoded, "section_id")
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// Keyword is a word or two-word phrase and the number of reviews that use it.
+type Keyword struct {
+	Term  string `json:"term"`
+	Count int    `json:"count"`
+}
+
+// CourseKeywords is the aggregated keyword list for one course, most used first.
+type CourseKeywords struct {
+	CourseCode string    `json:"course_code"`
+	Keywords   []Keyword `json:"keywords"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// ReviewText is the part of a review the keyword job reads.
+type ReviewText struct {
+	CourseCode string
+	Text       string
+}
@@ -1,12 +1,15 @@
 package models
 
-import "time"
+import (
+	"time"
+	"yuplan/internal/dbtypes"
+)
 
 type Lab struct {
-	ID            string    `json:"id"`
-	SectionID     string    `json:"section_id"`
-	CatalogNumber string    `json:"catalog_number"`
-	Times         *string   `json:"times,omitempty"` // JSON string of schedule array
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID            string             `json:"id"`
+	SectionID     string             `json:"section_id"`
+	CatalogNumber string             `json:"catalog_number"`
+	Times         dbtypes.NullString `json:"times,omitzero"` // JSON string of schedule array
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at"`
 }
@@ -3,6 +3,7 @@ package models
 import (
 	"testing"
 	"time"
+	"yuplan/internal/dbtypes"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -13,7 +14,7 @@ func TestLabModel(t *testing.T) {
 		ID:            "lab-1",
 		SectionID:     "section-1",
 		CatalogNumber: "LAB001",
-		Times:         &times,
+		Times:         dbtypes.NewNullString(times),
 		CreatedAt:     time.Now(),
 		UpdatedAt:     time.Now(),
 	}
@@ -21,6 +22,6 @@ func TestLabModel(t *testing.T) {
 	assert.Equal(t, "lab-1", lab.ID)
 	assert.Equal(t, "section-1", lab.SectionID)
 	assert.Equal(t, "LAB001", lab.CatalogNumber)
-	assert.NotNil(t, lab.Times)
-	assert.Equal(t, times, *lab.Times)
+	assert.True(t, lab.Times.Valid)
+	assert.Equal(t, times, lab.Times.String)
 }
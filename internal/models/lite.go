@@ -0,0 +1,11 @@
+package models
+
+// LiteCourse is the trimmed course summary served to the browser extension.
+// AvgDifficulty and LikePercentage are null until the course has a review.
+type LiteCourse struct {
+	Code           string   `json:"code"`
+	Name           string   `json:"name"`
+	AvgDifficulty  *float64 `json:"avg_difficulty"`
+	LikePercentage *int     `json:"like_percentage"`
+	ReviewCount    int      `json:"review_count"`
+}
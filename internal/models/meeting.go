@@ -0,0 +1,80 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"yuplan/internal/dbtypes"
+)
+
+// Meeting is one weekly meeting from section_activities.times, as written by
+// the scrapers. Asynchronous activities either have no times at all or only
+// placeholder meetings with an empty day and a zero duration.
+type Meeting struct {
+	Day      string `json:"day"`
+	Time     string `json:"time"`
+	Duration string `json:"duration"` // minutes
+	Campus   string `json:"campus"`
+	Room     string `json:"room"`
+}
+
+// Scheduled reports whether the meeting happens at a set time each week.
+func (m Meeting) Scheduled() bool {
+	minutes, err := strconv.Atoi(m.Duration)
+	return m.Day != "" && err == nil && minutes > 0
+}
+
+// Window returns when a scheduled meeting starts and ends in minutes since
+// midnight. ok is false for unscheduled meetings and unreadable start times.
+func (m Meeting) Window() (start, end int, ok bool) {
+	if !m.Scheduled() {
+		return 0, 0, false
+	}
+	hours, minutes, found := strings.Cut(m.Time, ":")
+	h, errH := strconv.Atoi(hours)
+	mins, errM := strconv.Atoi(minutes)
+	if !found || errH != nil || errM != nil {
+		return 0, 0, false
+	}
+	duration, _ := strconv.Atoi(m.Duration) // Scheduled already checked it parses
+	start = h*60 + mins
+	return start, start + duration, true
+}
+
+// ParseMeetings decodes a times column. A null or empty column is no meetings, not an error.
+func ParseMeetings(times dbtypes.NullString) ([]Meeting, error) {
+	if !times.Valid || times.String == "" {
+		return nil, nil
+	}
+	var meetings []Meeting
+	if err := json.Unmarshal([]byte(times.String), &meetings); err != nil {
+		return nil, fmt.Errorf("parse meeting times: %w", err)
+	}
+	return meetings, nil
+}
+
+// ScheduledMeetings returns only the meetings that occupy a slot in a weekly timetable.
+func ScheduledMeetings(times dbtypes.NullString) ([]Meeting, error) {
+	meetings, err := ParseMeetings(times)
+	if err != nil {
+		return nil, err
+	}
+	scheduled := make([]Meeting, 0, len(meetings))
+	for _, m := range meetings {
+		if m.Scheduled() {
+			scheduled = append(scheduled, m)
+		}
+	}
+	return scheduled, nil
+}
+
+// DeliveryOf classifies an activity by its times column. Times that can't be
+// parsed are treated as scheduled so they're shown rather than hidden.
+func DeliveryOf(times dbtypes.NullString) string {
+	scheduled, err := ScheduledMeetings(times)
+	if err != nil || len(scheduled) > 0 {
+		return DeliveryScheduled
+	}
+	return DeliveryAsynchronous
+}
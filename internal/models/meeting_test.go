@@ -0,0 +1,77 @@
+package models
+
+import (
+	"testing"
+	"yuplan/internal/dbtypes"
+)
+
+func TestParseMeetings(t *testing.T) {
+	meetings, err := ParseMeetings(dbtypes.NewNullString(`[{"day": "M", "time": "18:00", "duration": "110", "campus": "Keele", "room": "SSB E118"}]`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(meetings) != 1 || meetings[0].Day != "M" || meetings[0].Room != "SSB E118" {
+		t.Errorf("Unexpected meetings: %+v", meetings)
+	}
+
+	for _, times := range []dbtypes.NullString{{}, dbtypes.NewNullString("")} {
+		meetings, err := ParseMeetings(times)
+		if err != nil || meetings != nil {
+			t.Errorf("Expected no meetings for %+v, got %+v, %v", times, meetings, err)
+		}
+	}
+
+	if _, err := ParseMeetings(dbtypes.NewNullString("not json")); err == nil {
+		t.Error("Expected error for malformed times")
+	}
+}
+
+func TestDeliveryOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		times    dbtypes.NullString
+		expected string
+	}{
+		{"null times", dbtypes.NullString{}, DeliveryAsynchronous},
+		{"empty array", dbtypes.NewNullString("[]"), DeliveryAsynchronous},
+		{"placeholder meeting", dbtypes.NewNullString(`[{"day": "", "time": "0:00", "duration": "0", "campus": "Keele", "room": ""}]`), DeliveryAsynchronous},
+		{"day but zero duration", dbtypes.NewNullString(`[{"day": "T", "time": "0:00", "duration": "0", "campus": "Keele", "room": ""}]`), DeliveryAsynchronous},
+		{"scheduled", dbtypes.NewNullString(`[{"day": "M", "time": "18:00", "duration": "110", "campus": "Keele", "room": ""}]`), DeliveryScheduled},
+		{"mixed", dbtypes.NewNullString(`[{"day": "", "time": "0:00", "duration": "0"}, {"day": "W", "time": "10:00", "duration": "80"}]`), DeliveryScheduled},
+		{"malformed", dbtypes.NewNullString("not json"), DeliveryScheduled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DeliveryOf(tt.times); got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestScheduledMeetings_DropsPlaceholders(t *testing.T) {
+	meetings, err := ScheduledMeetings(dbtypes.NewNullString(`[{"day": "", "time": "0:00", "duration": "0"}, {"day": "W", "time": "10:00", "duration": "80"}]`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(meetings) != 1 || meetings[0].Day != "W" {
+		t.Errorf("Expected only the Wednesday meeting, got %+v", meetings)
+	}
+}
+
+func TestMeetingWindow(t *testing.T) {
+	start, end, ok := Meeting{Day: "T", Time: "14:30", Duration: "110"}.Window()
+	if !ok || start != 870 || end != 980 {
+		t.Errorf("Expected 870-980, got %d-%d (ok=%v)", start, end, ok)
+	}
+
+	for _, m := range []Meeting{
+		{Day: "", Time: "0:00", Duration: "0"},
+		{Day: "M", Time: "noon", Duration: "50"},
+	} {
+		if _, _, ok := m.Window(); ok {
+			t.Errorf("Expected no window for %+v", m)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// ModerationRule is one step of review auto-moderation, e.g. "approve short
+// reviews from yorku.ca addresses with no blocked words". Conditions left at
+// their zero value don't constrain anything.
+type ModerationRule struct {
+	ID               int64     `json:"id"`
+	Position         int       `json:"position"` // evaluation order, from 1
+	Name             string    `json:"name"`
+	Action           string    `json:"action"` // one of ModerationActions
+	Enabled          bool      `json:"enabled"`
+	NoProfanity      bool      `json:"no_profanity"`        // the text and author name have no blocked words
+	EmailDomains     []string  `json:"email_domains"`       // the author's email is at one of these domains
+	MinAuthorAgeDays int       `json:"min_author_age_days"` // the author's first review is at least this many days old
+	MaxTextLength    int       `json:"max_text_length"`     // the review text is at most this many characters
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// ModerationSubject is what moderation rules are checked against for a new review.
+type ModerationSubject struct {
+	Email        string
+	TextLength   int           // in characters
+	HasProfanity bool          // in the text or author name
+	AuthorAge    time.Duration // since the author's first review; 0 for their first
+}
+
+// Matches reports whether every condition of an enabled rule holds for s.
+func (r ModerationRule) Matches(s ModerationSubject) bool {
+	if !r.Enabled {
+		return false
+	}
+	if r.NoProfanity && s.HasProfanity {
+		return false
+	}
+	if len(r.EmailDomains) > 0 && !emailAtDomain(s.Email, r.EmailDomains) {
+		return false
+	}
+	if r.MinAuthorAgeDays > 0 && s.AuthorAge < time.Duration(r.MinAuthorAgeDays)*24*time.Hour {
+		return false
+	}
+	if r.MaxTextLength > 0 && s.TextLength > r.MaxTextLength {
+		return false
+	}
+	return true
+}
+
+// emailAtDomain reports whether email is at one of domains or a subdomain of
+// one, so "yorku.ca" covers "my.yorku.ca".
+func emailAtDomain(email string, domains []string) bool {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	host := strings.ToLower(email[at+1:])
+	for _, d := range domains {
+		if host == d || strings.HasSuffix(host, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
+// ModerationDecision is the outcome of moderating a new review.
+type ModerationDecision struct {
+	Moderation string // ModerationApproved or ModerationPending
+	Rule       string // name of the rule that decided; empty when none did
+}
+
+// SetModerationRulesRequest is the admin payload replacing every moderation
+// rule, at most 50. Rules are evaluated in the order given.
+type SetModerationRulesRequest struct {
+	Rules []ModerationRuleInput `json:"rules" binding:"max=50,dive"`
+}
+
+// ModerationRuleInput is one rule of SetModerationRulesRequest. Enabled defaults to true.
+type ModerationRuleInput struct {
+	Name             string   `json:"name" binding:"required,max=100"`
+	Action           string   `json:"action" binding:"required,oneof=approve queue"`
+	Enabled          *bool    `json:"enabled"`
+	NoProfanity      bool     `json:"no_profanity"`
+	EmailDomains     []string `json:"email_domains" binding:"dive,required,max=100"`
+	MinAuthorAgeDays int      `json:"min_author_age_days" binding:"min=0"`
+	MaxTextLength    int      `json:"max_text_length" binding:"min=0"`
+}
+
+// ModerationRules turns the request into rules numbered from 1, with email
+// domains lower-cased and any leading "@" dropped.
+func (r SetModerationRulesRequest) ModerationRules() []ModerationRule {
+	rules := make([]ModerationRule, len(r.Rules))
+	for i, in := range r.Rules {
+		domains := make([]string, 0, len(in.EmailDomains))
+		for _, d := range in.EmailDomains {
+			if d = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(d), "@")); d != "" {
+				domains = append(domains, d)
+			}
+		}
+		rules[i] = ModerationRule{
+			Position:         i + 1,
+			Name:             strings.TrimSpace(in.Name),
+			Action:           in.Action,
+			Enabled:          in.Enabled == nil || *in.Enabled,
+			NoProfanity:      in.NoProfanity,
+			EmailDomains:     domains,
+			MinAuthorAgeDays: in.MinAuthorAgeDays,
+			MaxTextLength:    in.MaxTextLength,
+		}
+	}
+	return rules
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestModerationRuleMatches(t *testing.T) {
+	subject := ModerationSubject{
+		Email:      "student@my.yorku.ca",
+		TextLength: 400,
+		AuthorAge:  10 * 24 * time.Hour,
+	}
+
+	tests := []struct {
+		name    string
+		rule    ModerationRule
+		subject ModerationSubject
+		want    bool
+	}{
+		{"no conditions", ModerationRule{Enabled: true}, subject, true},
+		{"disabled", ModerationRule{}, subject, false},
+		{"clean text", ModerationRule{Enabled: true, NoProfanity: true}, subject, true},
+		{"profanity", ModerationRule{Enabled: true, NoProfanity: true}, ModerationSubject{HasProfanity: true}, false},
+		{"subdomain of listed domain", ModerationRule{Enabled: true, EmailDomains: []string{"yorku.ca"}}, subject, true},
+		{"other domain", ModerationRule{Enabled: true, EmailDomains: []string{"yorku.ca"}}, ModerationSubject{Email: "student@notyorku.ca"}, false},
+		{"author old enough", ModerationRule{Enabled: true, MinAuthorAgeDays: 7}, subject, true},
+		{"author too new", ModerationRule{Enabled: true, MinAuthorAgeDays: 30}, subject, false},
+		{"short enough", ModerationRule{Enabled: true, MaxTextLength: 400}, subject, true},
+		{"too long", ModerationRule{Enabled: true, MaxTextLength: 399}, subject, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.Matches(tt.subject); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetModerationRulesRequest_ModerationRules(t *testing.T) {
+	disabled := false
+	req := SetModerationRulesRequest{Rules: []ModerationRuleInput{
+		{Name: " Trusted ", Action: ModerationActionApprove, EmailDomains: []string{"@YorkU.ca", " "}},
+		{Name: "Everything else", Action: ModerationActionQueue, Enabled: &disabled},
+	}}
+
+	got := req.ModerationRules()
+	want := []ModerationRule{
+		{Position: 1, Name: "Trusted", Action: ModerationActionApprove, Enabled: true, EmailDomains: []string{"yorku.ca"}},
+		{Position: 2, Name: "Everything else", Action: ModerationActionQueue, Enabled: false, EmailDomains: []string{}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ModerationRules() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,120 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// CourseOffering records that a course code ran in a term of an academic year.
+type CourseOffering struct {
+	Code         string `json:"code"`
+	AcademicYear int    `json:"academic_year"`
+	Term         string `json:"term"`
+}
+
+// OfferingSummary is when a course last ran and how often it tends to.
+type OfferingSummary struct {
+	Code            string    `json:"code"`
+	LastOfferedYear int       `json:"last_offered_year"`
+	LastOfferedTerm string    `json:"last_offered_term"`
+	Frequency       string    `json:"frequency"`
+	Terms           []string  `json:"terms"` // terms it has been offered in, in Terms order
+	YearsObserved   int       `json:"years_observed"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// termOrder places terms chronologically within an academic year: fall, full
+// year, winter, then summer.
+var termOrder = map[string]int{
+	TermFall:      0,
+	TermFullYear:  1,
+	TermWinter:    2,
+	TermSummer:    3,
+	TermSummerAlt: 3,
+	TermSummer1:   3,
+	TermSummer2:   4,
+}
+
+// SummarizeOfferings derives a summary from one course's offerings. It returns
+// false when there are none.
+func SummarizeOfferings(code string, offerings []CourseOffering) (OfferingSummary, bool) {
+	if len(offerings) == 0 {
+		return OfferingSummary{}, false
+	}
+
+	summary := OfferingSummary{Code: code}
+	years := map[int]bool{}
+	terms := map[string]bool{}
+	for _, o := range offerings {
+		years[o.AcademicYear] = true
+		terms[o.Term] = true
+		if o.AcademicYear > summary.LastOfferedYear ||
+			(o.AcademicYear == summary.LastOfferedYear && termOrder[o.Term] > termOrder[summary.LastOfferedTerm]) {
+			summary.LastOfferedYear = o.AcademicYear
+			summary.LastOfferedTerm = o.Term
+		}
+	}
+
+	for _, t := range Terms {
+		if terms[t] {
+			summary.Terms = append(summary.Terms, t)
+		}
+	}
+
+	sortedYears := make([]int, 0, len(years))
+	for y := range years {
+		sortedYears = append(sortedYears, y)
+	}
+	sort.Ints(sortedYears)
+	summary.YearsObserved = len(sortedYears)
+	summary.Frequency = offeringFrequency(sortedYears)
+
+	return summary, true
+}
+
+func offeringFrequency(sortedYears []int) string {
+	if len(sortedYears) < 2 {
+		return OfferingSingle
+	}
+	gap := sortedYears[1] - sortedYears[0]
+	for i := 2; i < len(sortedYears); i++ {
+		if sortedYears[i]-sortedYears[i-1] != gap {
+			return OfferingIrregular
+		}
+	}
+	switch gap {
+	case 1:
+		return OfferingAnnual
+	case 2:
+		return OfferingAlternating
+	default:
+		return OfferingIrregular
+	}
+}
+
+// Likelihood estimates whether the course runs in term of academicYear.
+// Single and irregular histories give OfferingUnknown.
+func (s OfferingSummary) Likelihood(academicYear int, term string) string {
+	offeredInTerm := false
+	for _, t := range s.Terms {
+		if t == term {
+			offeredInTerm = true
+			break
+		}
+	}
+
+	switch s.Frequency {
+	case OfferingAnnual:
+		if offeredInTerm {
+			return OfferingLikely
+		}
+		return OfferingUnlikely
+	case OfferingAlternating:
+		if offeredInTerm && (academicYear-s.LastOfferedYear)%2 == 0 {
+			return OfferingLikely
+		}
+		return OfferingUnlikely
+	default:
+		return OfferingUnknown
+	}
+}
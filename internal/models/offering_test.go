@@ -0,0 +1,100 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSummarizeOfferings(t *testing.T) {
+	tests := []struct {
+		name      string
+		offerings []CourseOffering
+		frequency string
+		lastYear  int
+		lastTerm  string
+		terms     []string
+	}{
+		{
+			name:      "every fall",
+			offerings: []CourseOffering{{AcademicYear: 2023, Term: TermFall}, {AcademicYear: 2024, Term: TermFall}, {AcademicYear: 2025, Term: TermFall}},
+			frequency: OfferingAnnual,
+			lastYear:  2025,
+			lastTerm:  TermFall,
+			terms:     []string{TermFall},
+		},
+		{
+			name:      "alternating years",
+			offerings: []CourseOffering{{AcademicYear: 2021, Term: TermWinter}, {AcademicYear: 2023, Term: TermWinter}, {AcademicYear: 2025, Term: TermWinter}},
+			frequency: OfferingAlternating,
+			lastYear:  2025,
+			lastTerm:  TermWinter,
+			terms:     []string{TermWinter},
+		},
+		{
+			name:      "irregular",
+			offerings: []CourseOffering{{AcademicYear: 2020, Term: TermFall}, {AcademicYear: 2021, Term: TermFall}, {AcademicYear: 2025, Term: TermFall}},
+			frequency: OfferingIrregular,
+			lastYear:  2025,
+			lastTerm:  TermFall,
+			terms:     []string{TermFall},
+		},
+		{
+			name:      "single year, winter after fall",
+			offerings: []CourseOffering{{AcademicYear: 2025, Term: TermWinter}, {AcademicYear: 2025, Term: TermFall}, {AcademicYear: 2025, Term: TermSummer}},
+			frequency: OfferingSingle,
+			lastYear:  2025,
+			lastTerm:  TermSummer,
+			terms:     []string{TermFall, TermWinter, TermSummer},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary, ok := SummarizeOfferings("EECS2030", tt.offerings)
+			if !ok {
+				t.Fatal("Expected a summary")
+			}
+			if summary.Frequency != tt.frequency {
+				t.Errorf("Expected frequency %s, got %s", tt.frequency, summary.Frequency)
+			}
+			if summary.LastOfferedYear != tt.lastYear || summary.LastOfferedTerm != tt.lastTerm {
+				t.Errorf("Expected last offered %d %s, got %d %s", tt.lastYear, tt.lastTerm, summary.LastOfferedYear, summary.LastOfferedTerm)
+			}
+			if !reflect.DeepEqual(summary.Terms, tt.terms) {
+				t.Errorf("Expected terms %v, got %v", tt.terms, summary.Terms)
+			}
+		})
+	}
+
+	if _, ok := SummarizeOfferings("EECS2030", nil); ok {
+		t.Error("Expected no summary without offerings")
+	}
+}
+
+func TestOfferingSummary_Likelihood(t *testing.T) {
+	annual := OfferingSummary{Frequency: OfferingAnnual, LastOfferedYear: 2025, Terms: []string{TermFall}}
+	alternating := OfferingSummary{Frequency: OfferingAlternating, LastOfferedYear: 2025, Terms: []string{TermWinter}}
+	single := OfferingSummary{Frequency: OfferingSingle, LastOfferedYear: 2025, Terms: []string{TermFall}}
+
+	tests := []struct {
+		name     string
+		summary  OfferingSummary
+		year     int
+		term     string
+		expected string
+	}{
+		{"annual in usual term", annual, 2026, TermFall, OfferingLikely},
+		{"annual in other term", annual, 2026, TermWinter, OfferingUnlikely},
+		{"alternating off year", alternating, 2026, TermWinter, OfferingUnlikely},
+		{"alternating on year", alternating, 2027, TermWinter, OfferingLikely},
+		{"single year", single, 2026, TermFall, OfferingUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.summary.Likelihood(tt.year, tt.term); got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
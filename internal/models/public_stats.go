@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// PublicStats are the platform-wide counters shown on the landing page.
+type PublicStats struct {
+	Courses           int                     `json:"courses"` // distinct course codes indexed
+	Reviews           int                     `json:"reviews"` // published reviews
+	ReviewsThisWeek   int                     `json:"reviews_this_week"`
+	MostReviewedDepts []DepartmentReviewCount `json:"most_reviewed_departments"`
+	GeneratedAt       time.Time               `json:"generated_at"`
+}
+
+// DepartmentReviewCount is how many published reviews a department's courses have.
+type DepartmentReviewCount struct {
+	Department string `json:"department"`
+	Reviews    int    `json:"reviews"`
+}
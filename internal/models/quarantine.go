@@ -0,0 +1,24 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+	"yuplan/internal/dbtypes"
+)
+
+// QuarantinedRecord is a scraped course record that failed validation during seeding.
+type QuarantinedRecord struct {
+	ID         string           `json:"id"`
+	Source     string           `json:"source"`
+	RecordKey  string           `json:"record_key"`
+	Record     json.RawMessage  `json:"record"`
+	Reasons    []string         `json:"reasons"`
+	Status     string           `json:"status"`
+	CreatedAt  time.Time        `json:"created_at"`
+	ResolvedAt dbtypes.NullTime `json:"resolved_at"`
+}
+
+// ReprocessQuarantineRequest optionally replaces the stored record with a corrected one.
+type ReprocessQuarantineRequest struct {
+	Record json.RawMessage `json:"record"`
+}
@@ -0,0 +1,10 @@
+package models
+
+// RateLimitExemptionRequest is the body of POST /api/v1/admin/rate-limit/exemptions.
+// Limit is requests per rate limit window; Reason is kept in the audit log.
+type RateLimitExemptionRequest struct {
+	Name       string `json:"name" binding:"required,max=100"`
+	Limit      int    `json:"limit" binding:"required,min=1"`
+	TTLMinutes int    `json:"ttl_minutes" binding:"required,min=1"`
+	Reason     string `json:"reason" binding:"max=500"`
+}
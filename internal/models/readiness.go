@@ -0,0 +1,73 @@
+package models
+
+// Readiness says whether a student can take a course given the courses they
+// have completed and plan to take alongside it.
+type Readiness struct {
+	CourseCode string `json:"course_code"`
+	Name       string `json:"name"`
+	Eligible   bool   `json:"eligible"`
+	// Completed is true when the course itself is on the transcript
+	Completed bool `json:"completed"`
+	// Each missing group is met by completing any one of its courses
+	MissingPrerequisites []RequisiteGroup `json:"missing_prerequisites"`
+	// Each missing group is met by completing or planning any one of its courses
+	MissingCorequisites []RequisiteGroup `json:"missing_corequisites"`
+	// Completed or planned courses the course gives no credit alongside
+	ExcludedBy      []RequisiteNode  `json:"excluded_by"`
+	TransferCredits []TransferCredit `json:"transfer_credits"`
+}
+
+// TransferCredit is an external course counted as the York course it maps to.
+type TransferCredit struct {
+	ExternalCourseCode string `json:"external_course_code"`
+	YorkCourseCode     string `json:"york_course_code"`
+	Confidence         string `json:"confidence"`
+}
+
+// CheckReadiness checks tree's direct requisites against completed and
+// planned, sets of normalized course codes. Prerequisites must be completed;
+// corequisites may also be planned; an exclusion completed or planned rules
+// the course out. Deeper prerequisites are taken as met by completing the
+// course that needs them.
+func CheckReadiness(tree RequisiteTree, completed, planned map[string]bool) Readiness {
+	readiness := Readiness{
+		CourseCode:           tree.CourseCode,
+		Name:                 tree.Name,
+		Completed:            completed[tree.CourseCode],
+		MissingPrerequisites: unmetGroups(tree.Prerequisites, completed, nil),
+		MissingCorequisites:  unmetGroups(tree.Corequisites, completed, planned),
+		ExcludedBy:           []RequisiteNode{},
+		TransferCredits:      []TransferCredit{},
+	}
+	for _, exclusion := range tree.Exclusions {
+		if completed[exclusion.CourseCode] || planned[exclusion.CourseCode] {
+			readiness.ExcludedBy = append(readiness.ExcludedBy, exclusion)
+		}
+	}
+	readiness.Eligible = !readiness.Completed &&
+		len(readiness.MissingPrerequisites) == 0 &&
+		len(readiness.MissingCorequisites) == 0 &&
+		len(readiness.ExcludedBy) == 0
+	return readiness
+}
+
+// unmetGroups returns the groups none of whose courses is in either set,
+// without their nested prerequisites.
+func unmetGroups(groups []RequisiteGroup, completed, planned map[string]bool) []RequisiteGroup {
+	unmet := []RequisiteGroup{}
+	for _, group := range groups {
+		met := false
+		anyOf := make([]RequisiteNode, 0, len(group.AnyOf))
+		for _, node := range group.AnyOf {
+			if completed[node.CourseCode] || planned[node.CourseCode] {
+				met = true
+				break
+			}
+			anyOf = append(anyOf, RequisiteNode{CourseCode: node.CourseCode, Name: node.Name})
+		}
+		if !met {
+			unmet = append(unmet, RequisiteGroup{AnyOf: anyOf})
+		}
+	}
+	return unmet
+}
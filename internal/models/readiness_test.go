@@ -0,0 +1,53 @@
+package models
+
+import "testing"
+
+func TestCheckReadiness(t *testing.T) {
+	edges := []Requisite{
+		edge("EECS3101", RequisitePrerequisite, 1, "EECS2030"),
+		edge("EECS3101", RequisitePrerequisite, 2, "MATH1090"),
+		edge("EECS3101", RequisitePrerequisite, 2, "MATH1019"),
+		edge("EECS3101", RequisiteCorequisite, 1, "EECS2001"),
+		edge("EECS3101", RequisiteExclusion, 0, "EECS3100"),
+	}
+	tree := BuildRequisiteTree("EECS3101", "Design and Analysis of Algorithms", edges, 1)
+	set := func(codes ...string) map[string]bool {
+		s := map[string]bool{}
+		for _, code := range codes {
+			s[code] = true
+		}
+		return s
+	}
+
+	tests := []struct {
+		name           string
+		completed      map[string]bool
+		planned        map[string]bool
+		eligible       bool
+		missingPrereqs int
+		missingCoreqs  int
+		excluded       int
+	}{
+		{"all met", set("EECS2030", "MATH1019", "EECS2001"), nil, true, 0, 0, 0},
+		{"corequisite planned", set("EECS2030", "MATH1090"), set("EECS2001"), true, 0, 0, 0},
+		{"prerequisite only planned", set("MATH1090", "EECS2001"), set("EECS2030"), false, 1, 0, 0},
+		{"nothing", nil, nil, false, 2, 1, 0},
+		{"excluded", set("EECS2030", "MATH1090", "EECS2001", "EECS3100"), nil, false, 0, 0, 1},
+		{"already completed", set("EECS3101", "EECS2030", "MATH1090", "EECS2001"), nil, false, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CheckReadiness(tree, tt.completed, tt.planned)
+			if got.Eligible != tt.eligible || len(got.MissingPrerequisites) != tt.missingPrereqs ||
+				len(got.MissingCorequisites) != tt.missingCoreqs || len(got.ExcludedBy) != tt.excluded {
+				t.Errorf("CheckReadiness = %+v", got)
+			}
+		})
+	}
+
+	// A missing group lists every alternative
+	got := CheckReadiness(tree, set("EECS2030", "EECS2001"), nil)
+	if len(got.MissingPrerequisites) != 1 || len(got.MissingPrerequisites[0].AnyOf) != 2 {
+		t.Errorf("MissingPrerequisites = %+v, want the MATH alternatives", got.MissingPrerequisites)
+	}
+}
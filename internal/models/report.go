@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+	"yuplan/internal/dbtypes"
+)
+
+// Report is a user's report that a course's or instructor's metadata is wrong
+// or inappropriate, or that a review is, queued for admins.
+type Report struct {
+	ID          string             `json:"id"`
+	Type        string             `json:"type"`         // One of ReportTypes
+	EntityType  string             `json:"entity_type"`  // ReportEntityCourse, ReportEntityInstructor or ReportEntityReview
+	EntityID    string             `json:"entity_id"`    // courses.id, instructors.id or reviews.id
+	EntityLabel string             `json:"entity_label"` // course code and term, instructor name, or reviewed course code; empty once the entity is gone
+	Reason      dbtypes.NullString `json:"reason"`       // One of ReportReasons for review reports, null otherwise
+	Details     dbtypes.NullString `json:"details"`
+	Email       dbtypes.NullString `json:"email,omitzero" redact:"admin"` // Optional contact for follow-up
+	Status      string             `json:"status"`                        // One of ReportStatuses
+	CreatedAt   time.Time          `json:"created_at"`
+	ResolvedAt  dbtypes.NullTime   `json:"resolved_at"`
+}
+
+type CreateReportRequest struct {
+	Type       string             `json:"type" binding:"required"`        // One of ReportTypes
+	EntityType string             `json:"entity_type" binding:"required"` // Must match the type, see ReportTypeEntities
+	EntityID   string             `json:"entity_id" binding:"required,uuid"`
+	Reason     string             `json:"reason"` // One of ReportReasons, review reports only; defaults to other
+	Details    dbtypes.NullString `json:"details"`
+	Email      string             `json:"email" binding:"omitempty,email"`
+}
+
+// ReportTrend is how many reviews were reported in one period, by reason.
+type ReportTrend struct {
+	PeriodStart time.Time      `json:"period_start"`
+	Total       int            `json:"total"`
+	Reasons     map[string]int `json:"reasons"` // every one of ReportReasons, zeros included
+}
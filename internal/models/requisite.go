@@ -0,0 +1,134 @@
+package models
+
+// Requisite is one stored edge of the requisite graph: CourseCode requires
+// (or, for exclusions, can't be taken with) RequisiteCode.
+type Requisite struct {
+	CourseCode    string
+	Kind          string // One of RequisiteKinds
+	Group         int    // Requirements in the same group are alternatives
+	RequisiteCode string
+	RequisiteName string // Catalog name of RequisiteCode; empty when it isn't in the catalog
+}
+
+// RequisiteNode is a course in a requisite tree.
+type RequisiteNode struct {
+	CourseCode    string           `json:"course_code"`
+	Name          string           `json:"name,omitempty"`
+	Prerequisites []RequisiteGroup `json:"prerequisites,omitempty"` // Only below the root when resolved that deep
+	Cycle         bool             `json:"cycle,omitempty"`         // Already required further up; not expanded again
+}
+
+// RequisiteGroup is met by any one of its courses. Every group of a course must be met.
+type RequisiteGroup struct {
+	AnyOf []RequisiteNode `json:"any_of"`
+}
+
+// RequisiteTree is a course's requisites, with its prerequisites resolved to Depth levels.
+type RequisiteTree struct {
+	CourseCode    string           `json:"course_code"`
+	Name          string           `json:"name"`
+	Depth         int              `json:"depth"`
+	Prerequisites []RequisiteGroup `json:"prerequisites"`
+	Corequisites  []RequisiteGroup `json:"corequisites"`
+	Exclusions    []RequisiteNode  `json:"exclusions"`
+}
+
+// SetRequisitesRequest is the admin payload replacing a course's requisites.
+// Prerequisites and corequisites are lists of groups, each a list of
+// alternative course codes: [["EECS1012"], ["MATH1090", "MATH1019"]] reads
+// EECS1012 and either MATH1090 or MATH1019.
+type SetRequisitesRequest struct {
+	Prerequisites [][]string `json:"prerequisites" binding:"dive,min=1,dive,required,max=50"`
+	Corequisites  [][]string `json:"corequisites" binding:"dive,min=1,dive,required,max=50"`
+	Exclusions    []string   `json:"exclusions" binding:"dive,required,max=50"`
+}
+
+// Requisites flattens the request into edges from courseCode, with codes
+// normalized and repeats dropped. Groups are numbered from 1 in the order given.
+func (r SetRequisitesRequest) Requisites(courseCode string) []Requisite {
+	courseCode = NormalizeCourseCode(courseCode)
+	var edges []Requisite
+	seen := map[Requisite]bool{}
+	add := func(kind string, group int, code string) {
+		edge := Requisite{CourseCode: courseCode, Kind: kind, Group: group, RequisiteCode: NormalizeCourseCode(code)}
+		if edge.RequisiteCode == courseCode || seen[edge] {
+			return
+		}
+		seen[edge] = true
+		edges = append(edges, edge)
+	}
+	for i, group := range r.Prerequisites {
+		for _, code := range group {
+			add(RequisitePrerequisite, i+1, code)
+		}
+	}
+	for i, group := range r.Corequisites {
+		for _, code := range group {
+			add(RequisiteCorequisite, i+1, code)
+		}
+	}
+	for _, code := range r.Exclusions {
+		add(RequisiteExclusion, 0, code)
+	}
+	return edges
+}
+
+// BuildRequisiteTree assembles the tree for courseCode from edges, as loaded
+// for that depth. The root's direct prerequisites are level 1; courses at
+// depth aren't expanded, and neither is a course already on the path above it.
+func BuildRequisiteTree(courseCode, name string, edges []Requisite, depth int) RequisiteTree {
+	byCourse := map[string][]Requisite{}
+	for _, e := range edges {
+		byCourse[e.CourseCode] = append(byCourse[e.CourseCode], e)
+	}
+
+	tree := RequisiteTree{
+		CourseCode:    courseCode,
+		Name:          name,
+		Depth:         depth,
+		Prerequisites: []RequisiteGroup{},
+		Corequisites:  []RequisiteGroup{},
+		Exclusions:    []RequisiteNode{},
+	}
+	path := map[string]bool{courseCode: true}
+	tree.Prerequisites = prerequisiteGroups(byCourse, courseCode, RequisitePrerequisite, path, 1, depth)
+	tree.Corequisites = prerequisiteGroups(byCourse, courseCode, RequisiteCorequisite, nil, 1, 1)
+	for _, e := range byCourse[courseCode] {
+		if e.Kind == RequisiteExclusion {
+			tree.Exclusions = append(tree.Exclusions, RequisiteNode{CourseCode: e.RequisiteCode, Name: e.RequisiteName})
+		}
+	}
+	return tree
+}
+
+// prerequisiteGroups builds the groups of kind under code, whose requirements
+// sit at level, expanding their own prerequisites while level < depth.
+func prerequisiteGroups(byCourse map[string][]Requisite, code, kind string, path map[string]bool, level, depth int) []RequisiteGroup {
+	groups := []RequisiteGroup{}
+	index := map[int]int{}
+	for _, e := range byCourse[code] {
+		if e.Kind != kind {
+			continue
+		}
+		node := RequisiteNode{CourseCode: e.RequisiteCode, Name: e.RequisiteName}
+		switch {
+		case path[e.RequisiteCode]:
+			node.Cycle = true
+		case level < depth && path != nil:
+			path[e.RequisiteCode] = true
+			if below := prerequisiteGroups(byCourse, e.RequisiteCode, RequisitePrerequisite, path, level+1, depth); len(below) > 0 {
+				node.Prerequisites = below
+			}
+			delete(path, e.RequisiteCode)
+		}
+
+		i, ok := index[e.Group]
+		if !ok {
+			i = len(groups)
+			index[e.Group] = i
+			groups = append(groups, RequisiteGroup{})
+		}
+		groups[i].AnyOf = append(groups[i].AnyOf, node)
+	}
+	return groups
+}
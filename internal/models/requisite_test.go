@@ -0,0 +1,98 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func edge(course, kind string, group int, requisite string) Requisite {
+	return Requisite{CourseCode: course, Kind: kind, Group: group, RequisiteCode: requisite, RequisiteName: requisite + " name"}
+}
+
+func TestBuildRequisiteTree_GroupsAndDepth(t *testing.T) {
+	edges := []Requisite{
+		edge("EECS3101", RequisitePrerequisite, 1, "EECS2030"),
+		edge("EECS3101", RequisitePrerequisite, 2, "MATH1090"),
+		edge("EECS3101", RequisitePrerequisite, 2, "MATH1019"),
+		edge("EECS3101", RequisiteCorequisite, 1, "EECS2001"),
+		edge("EECS3101", RequisiteExclusion, 0, "EECS3100"),
+		edge("EECS2030", RequisitePrerequisite, 1, "EECS1022"),
+		edge("EECS1022", RequisitePrerequisite, 1, "EECS1012"),
+	}
+
+	tree := BuildRequisiteTree("EECS3101", "Design and Analysis of Algorithms", edges, 2)
+
+	if len(tree.Prerequisites) != 2 || len(tree.Prerequisites[1].AnyOf) != 2 {
+		t.Fatalf("Prerequisites = %+v, want two groups with the MATH alternatives together", tree.Prerequisites)
+	}
+	eecs2030 := tree.Prerequisites[0].AnyOf[0]
+	if eecs2030.CourseCode != "EECS2030" || eecs2030.Name != "EECS2030 name" {
+		t.Errorf("first prerequisite = %+v, want EECS2030 with its name", eecs2030)
+	}
+	if len(eecs2030.Prerequisites) != 1 || eecs2030.Prerequisites[0].AnyOf[0].CourseCode != "EECS1022" {
+		t.Fatalf("EECS2030 prerequisites = %+v, want EECS1022", eecs2030.Prerequisites)
+	}
+	if below := eecs2030.Prerequisites[0].AnyOf[0].Prerequisites; below != nil {
+		t.Errorf("EECS1022 expanded past depth 2: %+v", below)
+	}
+	if len(tree.Corequisites) != 1 || tree.Corequisites[0].AnyOf[0].CourseCode != "EECS2001" {
+		t.Errorf("Corequisites = %+v, want EECS2001", tree.Corequisites)
+	}
+	if len(tree.Exclusions) != 1 || tree.Exclusions[0].CourseCode != "EECS3100" {
+		t.Errorf("Exclusions = %+v, want EECS3100", tree.Exclusions)
+	}
+}
+
+func TestBuildRequisiteTree_Cycle(t *testing.T) {
+	edges := []Requisite{
+		edge("A1000", RequisitePrerequisite, 1, "B1000"),
+		edge("B1000", RequisitePrerequisite, 1, "A1000"),
+	}
+
+	tree := BuildRequisiteTree("A1000", "", edges, 10)
+
+	b := tree.Prerequisites[0].AnyOf[0]
+	if len(b.Prerequisites) != 1 {
+		t.Fatalf("B1000 prerequisites = %+v, want A1000", b.Prerequisites)
+	}
+	if a := b.Prerequisites[0].AnyOf[0]; !a.Cycle || a.Prerequisites != nil {
+		t.Errorf("A1000 under B1000 = %+v, want a cycle marker without children", a)
+	}
+}
+
+func TestBuildRequisiteTree_EmptyListsNotNull(t *testing.T) {
+	body, err := json.Marshal(BuildRequisiteTree("EECS1001", "Research Directions", nil, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"course_code":"EECS1001","name":"Research Directions","depth":1,"prerequisites":[],"corequisites":[],"exclusions":[]}`
+	if string(body) != want {
+		t.Errorf("got %s, want %s", body, want)
+	}
+}
+
+func TestSetRequisitesRequest_Requisites(t *testing.T) {
+	req := SetRequisitesRequest{
+		Prerequisites: [][]string{{"eecs 2030"}, {"MATH1090", "math1019", "MATH1090"}},
+		Corequisites:  [][]string{{"EECS2001"}},
+		Exclusions:    []string{"EECS3100", "eecs3101"},
+	}
+
+	got := req.Requisites("eecs3101")
+
+	want := []Requisite{
+		{CourseCode: "EECS3101", Kind: RequisitePrerequisite, Group: 1, RequisiteCode: "EECS2030"},
+		{CourseCode: "EECS3101", Kind: RequisitePrerequisite, Group: 2, RequisiteCode: "MATH1090"},
+		{CourseCode: "EECS3101", Kind: RequisitePrerequisite, Group: 2, RequisiteCode: "MATH1019"},
+		{CourseCode: "EECS3101", Kind: RequisiteCorequisite, Group: 1, RequisiteCode: "EECS2001"},
+		{CourseCode: "EECS3101", Kind: RequisiteExclusion, Group: 0, RequisiteCode: "EECS3100"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d edges %+v, want %d", len(got), got, len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("edge %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// Retention policies, one per kind of data that is purged once it's old enough.
+const (
+	RetentionSearchStats        = "search_stats"        // anonymized daily search counts
+	RetentionResolvedQuarantine = "resolved_quarantine" // reprocessed or dismissed seed_quarantine records
+	RetentionCourseViews        = "course_views"        // courses reviewers have seen in the course feed
+	RetentionAnonymousSessions  = "anonymous_sessions"  // ended anonymous sessions with their recent views and drafts
+	RetentionUnverifiedReviews  = "unverified_reviews"  // reviews whose author never followed the verification link
+	RetentionVerificationTokens = "verification_tokens" // expired verification links left behind by reviews no longer unverified
+)
+
+// RetentionPolicy purges Name's data older than MaxAge. A zero MaxAge keeps it forever.
+type RetentionPolicy struct {
+	Name   string        `json:"name"`
+	MaxAge time.Duration `json:"max_age"`
+}
+
+// RetentionStats is what a policy has done on this instance since startup.
+type RetentionStats struct {
+	Policy      string    `json:"policy"`
+	MaxAgeDays  int       `json:"max_age_days"`
+	Runs        int64     `json:"runs"`
+	Errors      int64     `json:"errors"`
+	Purged      int64     `json:"purged"` // rows deleted over all runs
+	LastRun     time.Time `json:"last_run,omitzero"`
+	LastMatched int64     `json:"last_matched"` // rows past MaxAge at the last run
+	LastDryRun  bool      `json:"last_dry_run"`
+	LastError   string    `json:"last_error,omitempty"`
+}
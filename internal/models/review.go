@@ -1,25 +1,191 @@
 package models
 
-import "time"
+import (
+	"slices"
+	"time"
+	"yuplan/internal/dbtypes"
+)
 
 type Review struct {
-	ID                  string    `json:"id"`
-	CourseCode          string    `json:"course_code"`
-	Email               string    `json:"-"` // Never expose email in API responses
-	AuthorName          *string   `json:"author_name"` // Nullable: null = anonymous, value = display name
-	Liked               bool      `json:"liked"`
-	Difficulty          int       `json:"difficulty"`
-	RealWorldRelevance  int       `json:"real_world_relevance"`
-	ReviewText          *string   `json:"review_text"`
-	CreatedAt           time.Time `json:"created_at"`
-	UpdatedAt           time.Time `json:"updated_at"`
+	ID                 string             `json:"id"`
+	CourseCode         string             `json:"course_code"`
+	Email              string             `json:"email,omitempty" redact:"admin"` // Reviewer email; stripped for everyone but admins
+	AuthorName         dbtypes.NullString `json:"author_name"`                    // Nullable: null = anonymous, value = display name
+	Liked              bool               `json:"liked"`
+	Difficulty         int                `json:"difficulty"`
+	RealWorldRelevance int                `json:"real_world_relevance"`
+	ReviewText         dbtypes.NullString `json:"review_text"`             // Raw markdown as submitted
+	DeliveryMode       dbtypes.NullString `json:"delivery_mode"`           // One of ReviewDeliveryModes; null = not given
+	AcademicYear       int                `json:"academic_year"`           // Session the course was taken in, by its starting year
+	Term               string             `json:"term"`                    // One of Terms
+	RenderedHTML       dbtypes.NullString `json:"rendered_html"`           // Sanitized HTML of ReviewText; computed, not stored
+	Tags               []string           `json:"tags,omitempty"`          // Subset of ReviewTags; only populated on create
+	PublishAt          dbtypes.NullTime   `json:"publish_at"`              // When an embargoed review goes public; null = published on submission
+	Moderation         string             `json:"-"`                       // ModerationApproved, ModerationPending or ModerationUnverified; shown through Status
+	Status             string             `json:"status,omitempty"`        // One of ReviewStatuses; computed, not stored
+	AuthorBadges       []string           `json:"author_badges,omitempty"` // Badge slugs held by the author; only on named reviews and the author's own view
+	HelpfulCount       int                `json:"helpful_count"`           // Readers who voted it helpful; only counted on course review listings
+	NotHelpfulCount    int                `json:"not_helpful_count"`       // Readers who voted it not helpful; likewise
+	CreatedAt          time.Time          `json:"created_at"`
+	UpdatedAt          time.Time          `json:"updated_at"`
+	EditedAt           dbtypes.NullTime   `json:"edited_at"` // Last author edit; null = never edited
+	Edited             bool               `json:"edited"`    // EditedAt is set; computed, not stored
 }
 
 type CreateReviewRequest struct {
-	Email              string  `json:"email" binding:"required,email"`
-	AuthorName         *string `json:"author_name"` // Optional: provide name or leave null for "Anonymous"
-	Liked              bool    `json:"liked"`
-	Difficulty         int     `json:"difficulty" binding:"required,min=1,max=5"`
-	RealWorldRelevance int     `json:"real_world_relevance" binding:"required,min=1,max=5"`
-	ReviewText         *string `json:"review_text"`
+	Email              string             `json:"email" binding:"required,email"`
+	AuthorName         dbtypes.NullString `json:"author_name"` // Optional: provide name or leave null for "Anonymous"
+	Liked              bool               `json:"liked"`
+	Difficulty         int                `json:"difficulty" binding:"required,min=1,max=5"`
+	RealWorldRelevance int                `json:"real_world_relevance" binding:"required,min=1,max=5"`
+	ReviewText         dbtypes.NullString `json:"review_text"`
+	DeliveryMode       dbtypes.NullString `json:"delivery_mode"` // Optional: one of ReviewDeliveryModes
+	Tags               []string           `json:"tags"`          // Optional: up to MaxReviewTags entries from ReviewTags
+	AcademicYear       int                `json:"academic_year"` // Optional with Term: when the course was taken; defaults to TermAt submission
+	Term               string             `json:"term"`
+}
+
+// UpdateReviewRequest replaces what a review says, tags included. Its course,
+// term and author stay as created, and Email must be the author's.
+type UpdateReviewRequest struct {
+	Email              string             `json:"email" binding:"required,email"`
+	AuthorName         dbtypes.NullString `json:"author_name"`
+	Liked              bool               `json:"liked"`
+	Difficulty         int                `json:"difficulty" binding:"required,min=1,max=5"`
+	RealWorldRelevance int                `json:"real_world_relevance" binding:"required,min=1,max=5"`
+	ReviewText         dbtypes.NullString `json:"review_text"`
+	DeliveryMode       dbtypes.NullString `json:"delivery_mode"`
+	Tags               []string           `json:"tags"`
+}
+
+// ResendVerificationRequest asks for a new confirmation link for an
+// unverified review. Email must be the author's.
+type ResendVerificationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// EmbargoReviewRequest is the admin payload for holding a review until a given time.
+type EmbargoReviewRequest struct {
+	Until time.Time `json:"until" binding:"required"`
+}
+
+// ReviewContent is what an author can change about a review by editing it.
+type ReviewContent struct {
+	AuthorName         dbtypes.NullString `json:"author_name"`
+	Liked              bool               `json:"liked"`
+	Difficulty         int                `json:"difficulty"`
+	RealWorldRelevance int                `json:"real_world_relevance"`
+	ReviewText         dbtypes.NullString `json:"review_text"`
+	DeliveryMode       dbtypes.NullString `json:"delivery_mode"`
+	Tags               []string           `json:"tags"`
+}
+
+// ContentOf returns what r says, for comparing with its earlier revisions.
+func ContentOf(r Review) ReviewContent {
+	return ReviewContent{
+		AuthorName:         r.AuthorName,
+		Liked:              r.Liked,
+		Difficulty:         r.Difficulty,
+		RealWorldRelevance: r.RealWorldRelevance,
+		ReviewText:         r.ReviewText,
+		DeliveryMode:       r.DeliveryMode,
+		Tags:               r.Tags,
+	}
+}
+
+// ChangedFields names the fields, as in the review JSON, that differ in next.
+// Tags are compared regardless of order.
+func (c ReviewContent) ChangedFields(next ReviewContent) []string {
+	changed := []string{}
+	if c.AuthorName != next.AuthorName {
+		changed = append(changed, "author_name")
+	}
+	if c.Liked != next.Liked {
+		changed = append(changed, "liked")
+	}
+	if c.Difficulty != next.Difficulty {
+		changed = append(changed, "difficulty")
+	}
+	if c.RealWorldRelevance != next.RealWorldRelevance {
+		changed = append(changed, "real_world_relevance")
+	}
+	if c.ReviewText != next.ReviewText {
+		changed = append(changed, "review_text")
+	}
+	if c.DeliveryMode != next.DeliveryMode {
+		changed = append(changed, "delivery_mode")
+	}
+	if !slices.Equal(slices.Sorted(slices.Values(c.Tags)), slices.Sorted(slices.Values(next.Tags))) {
+		changed = append(changed, "tags")
+	}
+	return changed
+}
+
+// ReviewRevision is an earlier version of an edited review, as it read until
+// an edit replaced it. Everyone sees when it was written and what the edit
+// changed; only admins see what it said.
+type ReviewRevision struct {
+	Revision   int            `json:"revision"`   // 1 is the review as first submitted
+	WrittenAt  time.Time      `json:"written_at"` // When this version was submitted or edited
+	ReplacedAt time.Time      `json:"replaced_at"`
+	Changed    []string       `json:"changed"` // Fields the replacing edit changed; see ReviewContent.ChangedFields
+	Content    *ReviewContent `json:"content,omitempty" redact:"admin"`
+}
+
+// ReviewHistory is a review's edit history, oldest revision first.
+type ReviewHistory struct {
+	ReviewID  string           `json:"review_id"`
+	Edited    bool             `json:"edited"`
+	EditedAt  dbtypes.NullTime `json:"edited_at"`
+	Revisions []ReviewRevision `json:"revisions"`
+}
+
+// StatusAt reports whether the review is public at now.
+func (r Review) StatusAt(now time.Time) string {
+	switch r.Moderation {
+	case ModerationPending:
+		return ReviewPending
+	case ModerationUnverified:
+		return ReviewUnverified
+	}
+	if r.PublishAt.Valid && r.PublishAt.Time.After(now) {
+		return ReviewEmbargoed
+	}
+	return ReviewPublished
+}
+
+// TermAt returns the academic year and term in progress at t: September to
+// December is fall, January to April winter and May to August summer, all in
+// the session that started the September before.
+func TermAt(t time.Time) (academicYear int, term string) {
+	switch month := t.Month(); {
+	case month >= time.September:
+		return t.Year(), TermFall
+	case month <= time.April:
+		return t.Year() - 1, TermWinter
+	default:
+		return t.Year() - 1, TermSummer
+	}
+}
+
+// DeliveryModeStats is a course's review stats among reviewers who took it one way.
+type DeliveryModeStats struct {
+	DeliveryMode          string  `json:"delivery_mode"`
+	TotalReviews          int     `json:"total_reviews"`
+	LikePercentage        int     `json:"like_percentage"`
+	AvgDifficulty         float64 `json:"avg_difficulty"`
+	AvgRealWorldRelevance float64 `json:"avg_real_world_relevance"`
+}
+
+// RatingHistogram is how many of a course's reviews gave each rating. Index 0
+// counts ratings of 1, index 4 ratings of 5.
+type RatingHistogram struct {
+	Difficulty         [5]int `json:"difficulty"`
+	RealWorldRelevance [5]int `json:"real_world_relevance"`
+}
+
+// TagCount is how many reviews of a course chose a tag.
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
 }
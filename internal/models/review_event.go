@@ -0,0 +1,12 @@
+package models
+
+// ReviewFunnel follows the reviews submitted in a window through verification
+// to publication (GET /api/v1/admin/analytics/reviews).
+type ReviewFunnel struct {
+	Submitted        int            `json:"submitted"`
+	Verified         int            `json:"verified"`
+	Published        int            `json:"published"` // public now, embargo lapsed or never held
+	VerificationRate float64        `json:"verification_rate"`
+	PublicationRate  float64        `json:"publication_rate"`
+	Events           map[string]int `json:"events"` // every event recorded in the window, by type
+}
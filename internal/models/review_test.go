@@ -2,8 +2,11 @@ package models
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 	"time"
+	"yuplan/internal/dbtypes"
+	"yuplan/internal/redact"
 )
 
 func TestReviewJSON(t *testing.T) {
@@ -13,16 +16,18 @@ func TestReviewJSON(t *testing.T) {
 		ID:                 "123e4567-e89b-12d3-a456-426614174000",
 		CourseCode:         "EECS2030",
 		Email:              "student@yorku.ca",
-		AuthorName:         &authorName,
+		AuthorName:         dbtypes.NewNullString(authorName),
 		Liked:              true,
 		Difficulty:         3,
 		RealWorldRelevance: 5,
-		ReviewText:         &reviewText,
+		ReviewText:         dbtypes.NewNullString(reviewText),
 		CreatedAt:          time.Now(),
 		UpdatedAt:          time.Now(),
 	}
 
-	data, err := json.Marshal(review)
+	public := review
+	redact.Apply(&public, redact.RolePublic)
+	data, err := json.Marshal(public)
 	if err != nil {
 		t.Fatalf("Failed to marshal review: %v", err)
 	}
@@ -38,10 +43,15 @@ func TestReviewJSON(t *testing.T) {
 	if decoded.CourseCode != review.CourseCode {
 		t.Errorf("Expected CourseCode %s, got %s", review.CourseCode, decoded.CourseCode)
 	}
-	// Email should not be in JSON
+	// Email is only serialized for admins
 	if decoded.Email != "" {
 		t.Errorf("Expected Email to be empty (not serialized), got %s", decoded.Email)
 	}
+	admin := review
+	redact.Apply(&admin, redact.RoleAdmin)
+	if data, _ := json.Marshal(admin); !strings.Contains(string(data), `"email":"student@yorku.ca"`) {
+		t.Errorf("Expected Email to be serialized for admins, got %s", data)
+	}
 	if decoded.Liked != review.Liked {
 		t.Errorf("Expected Liked %v, got %v", review.Liked, decoded.Liked)
 	}
@@ -59,11 +69,11 @@ func TestReviewAnonymous(t *testing.T) {
 		ID:                 "123e4567-e89b-12d3-a456-426614174000",
 		CourseCode:         "EECS2030",
 		Email:              "student@yorku.ca",
-		AuthorName:         nil, // Anonymous
+		AuthorName:         dbtypes.NullString{}, // Anonymous
 		Liked:              true,
 		Difficulty:         3,
 		RealWorldRelevance: 5,
-		ReviewText:         &reviewText,
+		ReviewText:         dbtypes.NewNullString(reviewText),
 		CreatedAt:          time.Now(),
 		UpdatedAt:          time.Now(),
 	}
@@ -89,11 +99,11 @@ func TestCreateReviewRequest(t *testing.T) {
 	authorName := "John Smith"
 	req := CreateReviewRequest{
 		Email:              "student@yorku.ca",
-		AuthorName:         &authorName,
+		AuthorName:         dbtypes.NewNullString(authorName),
 		Liked:              true,
 		Difficulty:         3,
 		RealWorldRelevance: 5,
-		ReviewText:         &reviewText,
+		ReviewText:         dbtypes.NewNullString(reviewText),
 	}
 
 	data, err := json.Marshal(req)
@@ -109,8 +119,8 @@ func TestCreateReviewRequest(t *testing.T) {
 	if decoded.Email != req.Email {
 		t.Errorf("Expected Email %s, got %s", req.Email, decoded.Email)
 	}
-	if *decoded.AuthorName != *req.AuthorName {
-		t.Errorf("Expected AuthorName %s, got %s", *req.AuthorName, *decoded.AuthorName)
+	if decoded.AuthorName.String != req.AuthorName.String {
+		t.Errorf("Expected AuthorName %s, got %s", req.AuthorName.String, decoded.AuthorName.String)
 	}
 	if decoded.Liked != req.Liked {
 		t.Errorf("Expected Liked %v, got %v", req.Liked, decoded.Liked)
@@ -122,3 +132,83 @@ func TestCreateReviewRequest(t *testing.T) {
 		t.Errorf("Expected RealWorldRelevance %d, got %d", req.RealWorldRelevance, decoded.RealWorldRelevance)
 	}
 }
+
+func TestIsReviewTag(t *testing.T) {
+	for _, tag := range ReviewTags {
+		if !IsReviewTag(tag) {
+			t.Errorf("Expected %q to be a review tag", tag)
+		}
+	}
+	for _, tag := range []string{"", "easy-a", "Heavy-Workload"} {
+		if IsReviewTag(tag) {
+			t.Errorf("Expected %q not to be a review tag", tag)
+		}
+	}
+}
+
+func TestTermAt(t *testing.T) {
+	tests := []struct {
+		at       time.Time
+		wantYear int
+		wantTerm string
+	}{
+		{time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC), 2026, TermFall},
+		{time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC), 2026, TermFall},
+		{time.Date(2027, 1, 5, 0, 0, 0, 0, time.UTC), 2026, TermWinter},
+		{time.Date(2027, 4, 30, 0, 0, 0, 0, time.UTC), 2026, TermWinter},
+		{time.Date(2027, 5, 1, 0, 0, 0, 0, time.UTC), 2026, TermSummer},
+		{time.Date(2027, 8, 31, 0, 0, 0, 0, time.UTC), 2026, TermSummer},
+	}
+
+	for _, tt := range tests {
+		year, term := TermAt(tt.at)
+		if year != tt.wantYear || term != tt.wantTerm {
+			t.Errorf("TermAt(%s) = %d %s, want %d %s", tt.at.Format("2006-01-02"), year, term, tt.wantYear, tt.wantTerm)
+		}
+	}
+}
+
+func TestReviewStatusAt(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		review Review
+		want   string
+	}{
+		{"published", Review{Moderation: ModerationApproved}, ReviewPublished},
+		{"embargo passed", Review{Moderation: ModerationApproved, PublishAt: dbtypes.NewNullTime(now.Add(-time.Hour))}, ReviewPublished},
+		{"embargoed", Review{Moderation: ModerationApproved, PublishAt: dbtypes.NewNullTime(now.Add(time.Hour))}, ReviewEmbargoed},
+		{"unverified", Review{Moderation: ModerationUnverified}, ReviewUnverified},
+		{"pending moderation outranks embargo", Review{Moderation: ModerationPending, PublishAt: dbtypes.NewNullTime(now.Add(time.Hour))}, ReviewPending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.review.StatusAt(now); got != tt.want {
+				t.Errorf("StatusAt() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReviewContentChangedFields(t *testing.T) {
+	before := ReviewContent{Difficulty: 3, RealWorldRelevance: 4, Tags: []string{"math_heavy", "heavy_workload"}}
+	tests := []struct {
+		name string
+		next ReviewContent
+		want string
+	}{
+		{"unchanged", ReviewContent{Difficulty: 3, RealWorldRelevance: 4, Tags: []string{"heavy_workload", "math_heavy"}}, ""},
+		{"ratings", ReviewContent{Liked: true, Difficulty: 2, RealWorldRelevance: 4, Tags: before.Tags}, "liked,difficulty"},
+		{"text and tags", ReviewContent{Difficulty: 3, RealWorldRelevance: 4, ReviewText: dbtypes.NewNullString("Edited")}, "review_text,tags"},
+		{"named", ReviewContent{AuthorName: dbtypes.NewNullString("Sam"), Difficulty: 3, RealWorldRelevance: 4, DeliveryMode: dbtypes.NewNullString(ReviewDeliveryOnline), Tags: before.Tags}, "author_name,delivery_mode"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strings.Join(before.ChangedFields(tt.next), ","); got != tt.want {
+				t.Errorf("ChangedFields() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package models
+
+// ReviewVoteRequest is a reader's verdict on a review. Helpful is a pointer so
+// a missing verdict is told apart from false.
+type ReviewVoteRequest struct {
+	Email   string `json:"email" binding:"required,email"`
+	Helpful *bool  `json:"helpful" binding:"required"`
+}
+
+// ReviewVotes tallies the votes on one review.
+type ReviewVotes struct {
+	ReviewID        string `json:"review_id"`
+	HelpfulCount    int    `json:"helpful_count"`
+	NotHelpfulCount int    `json:"not_helpful_count"`
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SavedSchedule is a set of sections and activities an email saved, which a
+// calendar app can subscribe to once a feed token is issued. The calendar is
+// rebuilt on every fetch, so swapping sections shows up on the next refresh.
+type SavedSchedule struct {
+	ID          string    `json:"id"`
+	Email       string    `json:"email" redact:"admin"`
+	Name        string    `json:"name"`
+	SectionIDs  []string  `json:"section_ids"`
+	ActivityIDs []string  `json:"activity_ids"` // labs and tutorials chosen within the sections
+	Subscribed  bool      `json:"subscribed"`   // a feed token is issued and not revoked
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// SavedScheduleRequest saves or replaces a schedule. Email must be the
+// owner's when replacing.
+type SavedScheduleRequest struct {
+	Email       string   `json:"email" binding:"required,email,max=255"`
+	Name        string   `json:"name" binding:"required,max=100"`
+	SectionIDs  []string `json:"section_ids" binding:"dive,uuid"`
+	ActivityIDs []string `json:"activity_ids" binding:"dive,uuid"`
+}
+
+// Normalize trims the request and drops repeated ids. It returns a message
+// when the name is blank or the ids are none or too many for a calendar.
+func (r *SavedScheduleRequest) Normalize() (problem string) {
+	r.Email = strings.TrimSpace(r.Email)
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" {
+		return "name must not be blank"
+	}
+	r.SectionIDs = uniqueIDs(r.SectionIDs)
+	r.ActivityIDs = uniqueIDs(r.ActivityIDs)
+	total := len(r.SectionIDs) + len(r.ActivityIDs)
+	if total == 0 {
+		return "section_ids or activity_ids is required"
+	}
+	if total > MaxCalendarIDs {
+		return fmt.Sprintf("At most %d ids per calendar", MaxCalendarIDs)
+	}
+	return ""
+}
+
+// CalendarTokenRequest issues a new feed token for one of email's schedules.
+type CalendarTokenRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// SwapSuggestionsRequest asks for the sections that could replace one of the
+// sections of email's schedule.
+type SwapSuggestionsRequest struct {
+	Email     string `json:"email" binding:"required,email"`
+	SectionID string `json:"section_id" binding:"required,uuid"`
+}
+
+func uniqueIDs(ids []string) []string {
+	unique := []string{}
+	seen := map[string]bool{}
+	for _, id := range ids {
+		id = strings.ToLower(id)
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSavedScheduleRequest_Normalize(t *testing.T) {
+	section := "0190f3a2-7b1c-7d2e-8f3a-1b2c3d4e5f60"
+	req := SavedScheduleRequest{
+		Email: " a@yorku.ca ", Name: " Fall plan ",
+		SectionIDs: []string{section, strings.ToUpper(section)},
+	}
+	if problem := req.Normalize(); problem != "" {
+		t.Fatalf("Normalize() = %q", problem)
+	}
+	want := SavedScheduleRequest{Email: "a@yorku.ca", Name: "Fall plan", SectionIDs: []string{section}, ActivityIDs: []string{}}
+	if !reflect.DeepEqual(req, want) {
+		t.Errorf("Normalize() left %+v, want %+v", req, want)
+	}
+
+	tooMany := make([]string, MaxCalendarIDs+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("0190f3a2-7b1c-7d2e-8f3a-%012d", i)
+	}
+	for _, bad := range []SavedScheduleRequest{
+		{Name: "  ", SectionIDs: []string{section}},
+		{Name: "No ids"},
+		{Name: "Too many", SectionIDs: tooMany},
+	} {
+		if problem := bad.Normalize(); problem == "" {
+			t.Errorf("Expected %+v to be rejected", bad)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package models
+
+// MaxScheduleCourses caps how many courses one timetable request may combine.
+const MaxScheduleCourses = 8
+
+// GenerateScheduleRequest asks for timetables combining the given courses in one term.
+type GenerateScheduleRequest struct {
+	CourseCodes           []string `json:"course_codes" binding:"required,min=1"`
+	Term                  string   `json:"term" binding:"required"`                         // One of Terms
+	EarliestStart         string   `json:"earliest_start"`                                  // Optional "HH:MM"; no meetings may start before it
+	LatestEnd             string   `json:"latest_end"`                                      // Optional "HH:MM"; no meetings may end after it
+	DaysOff               []string `json:"days_off"`                                        // Optional days in meeting-time notation (M, T, W, R, F, S, U)
+	TransferBufferMinutes int      `json:"transfer_buffer_minutes" binding:"min=0,max=120"` // Slack wanted on top of travel time between buildings
+	RejectTightTransfers  bool     `json:"reject_tight_transfers"`                          // Drop timetables with tight transfers instead of warning
+	Limit                 int      `json:"limit"`                                           // Timetables to return; defaults to 20, at most 50
+}
+
+// ScheduleImageRequest asks for a timetable of the given section activities
+// (lectures, labs, tutorials, ...) drawn as an image.
+type ScheduleImageRequest struct {
+	ActivityIDs []string `json:"activity_ids" binding:"required,min=1,max=40,dive,uuid"`
+	Title       string   `json:"title" binding:"max=60"`                                 // Optional heading above the grid
+	Theme       string   `json:"theme" binding:"omitempty,oneof=light dark"`             // Defaults to light
+	FontSize    string   `json:"font_size" binding:"omitempty,oneof=small medium large"` // Defaults to medium
+}
@@ -0,0 +1,8 @@
+package models
+
+// SearchStat is an aggregated count of searches for a normalized query.
+type SearchStat struct {
+	Query       string `json:"query"`
+	Searches    int    `json:"searches"`
+	ZeroResults int    `json:"zero_results"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchStatJSON(t *testing.T) {
+	stat := SearchStat{Query: "eecs", Searches: 12, ZeroResults: 1}
+
+	data, err := json.Marshal(stat)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"query":"eecs","searches":12,"zero_results":1}`, string(data))
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+	"yuplan/internal/dbtypes"
+)
+
+// MaxSeatWatches is how many sections one email can watch at once.
+const MaxSeatWatches = 25
+
+// SeatWatch is an email waiting to hear when a section has a free seat.
+type SeatWatch struct {
+	ID         string           `json:"id"`
+	Email      string           `json:"email" redact:"admin"`
+	SectionID  string           `json:"section_id"`
+	CourseCode string           `json:"course_code,omitempty"`
+	Letter     string           `json:"letter,omitempty"`
+	TermID     string           `json:"term_id,omitempty"`
+	CreatedAt  time.Time        `json:"created_at"`
+	NotifiedAt dbtypes.NullTime `json:"notified_at"` // last time an opening was mailed; null while waiting
+}
+
+// CreateSeatWatchRequest starts watching a section.
+type CreateSeatWatchRequest struct {
+	Email     string `json:"email" binding:"required,email,max=255"`
+	SectionID string `json:"section_id" binding:"required,uuid"`
+}
+
+// SeatOpening is a watched section that has seats again, with what the
+// notification needs to say.
+type SeatOpening struct {
+	WatchID        string
+	Email          string
+	CourseCode     string
+	CourseName     string
+	Letter         string
+	TermID         string
+	SeatsRemaining int
+}
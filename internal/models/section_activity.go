@@ -1,13 +1,17 @@
 package models
 
-import "time"
+import (
+	"time"
+	"yuplan/internal/dbtypes"
+)
 
 type SectionActivity struct {
-	ID            string    `json:"id"`
-	CourseType    string    `json:"course_type"`
-	SectionID     string    `json:"section_id"`
-	CatalogNumber string    `json:"catalog_number"`
-	Times         *string   `json:"times,omitempty"` // JSON string of schedule array
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID            string             `json:"id"`
+	CourseType    string             `json:"course_type"`
+	SectionID     string             `json:"section_id"`
+	CatalogNumber string             `json:"catalog_number"`
+	Times         dbtypes.NullString `json:"times,omitzero"` // JSON string of schedule array
+	Delivery      string             `json:"delivery"`       // DeliveryScheduled or DeliveryAsynchronous, derived from Times
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at"`
 }
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Limits on what an anonymous session keeps
+const (
+	MaxRecentViews   = 20        // most recent courses kept per session
+	MaxSessionDrafts = 20        // drafts per session
+	MaxDraftBytes    = 16 * 1024 // size of one draft's body
+)
+
+// RecentView is a course an anonymous visitor opened.
+type RecentView struct {
+	CourseCode string    `json:"course_code"`
+	ViewedAt   time.Time `json:"viewed_at"`
+}
+
+type RecordRecentViewRequest struct {
+	CourseCode string `json:"course_code" binding:"required,max=20"`
+}
+
+// SessionDraft is form content an anonymous visitor hasn't submitted yet,
+// stored as the client sent it under a key of its choosing.
+type SessionDraft struct {
+	Key       string          `json:"key"`
+	Body      json.RawMessage `json:"body,omitempty"` // left out of draft listings
+	UpdatedAt time.Time       `json:"updated_at"`
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"time"
+	"yuplan/internal/dbtypes"
+)
+
+// Sync run statuses.
+const (
+	SyncRunning   = "running"
+	SyncSucceeded = "succeeded"
+	SyncFailed    = "failed" // includes runs abandoned when their instance stopped
+)
+
+// RowDiff counts the rows of one table a sync wrote.
+type RowDiff struct {
+	Added   int `json:"added"`
+	Changed int `json:"changed"`
+	Removed int `json:"removed"`
+}
+
+func (d RowDiff) Add(other RowDiff) RowDiff {
+	return RowDiff{Added: d.Added + other.Added, Changed: d.Changed + other.Changed, Removed: d.Removed + other.Removed}
+}
+
+// CatalogDiff counts what loading scraped courses changed in each catalog table.
+type CatalogDiff struct {
+	Courses     RowDiff `json:"courses"`
+	Sections    RowDiff `json:"sections"`
+	Activities  RowDiff `json:"activities"`
+	Instructors RowDiff `json:"instructors"`
+}
+
+func (d CatalogDiff) Add(other CatalogDiff) CatalogDiff {
+	return CatalogDiff{
+		Courses:     d.Courses.Add(other.Courses),
+		Sections:    d.Sections.Add(other.Sections),
+		Activities:  d.Activities.Add(other.Activities),
+		Instructors: d.Instructors.Add(other.Instructors),
+	}
+}
+
+// CourseChange is what loading one scraped course changed, so the cached
+// reads of that course can be dropped without flushing the whole catalog.
+type CourseChange struct {
+	CourseID string
+	Code     string
+	TermID   string // the session its sections were loaded into
+	Diff     CatalogDiff
+}
+
+// Changed reports whether the load wrote anything.
+func (c CourseChange) Changed() bool {
+	return c.Diff != CatalogDiff{}
+}
+
+// TouchesInstructors reports whether the change can show in instructor
+// profiles and course lists, which gather sections across courses by name.
+// Only activity times leave them alone.
+func (c CourseChange) TouchesInstructors() bool {
+	return c.Diff.Courses != RowDiff{} || c.Diff.Sections != RowDiff{} || c.Diff.Instructors != RowDiff{}
+}
+
+// SyncRun is one scheduled re-scrape of the catalog, as kept in sync_history.
+type SyncRun struct {
+	ID          int64            `json:"id"`
+	Term        string           `json:"term"`
+	Status      string           `json:"status"`
+	StartedAt   time.Time        `json:"started_at"`
+	FinishedAt  dbtypes.NullTime `json:"finished_at"`
+	Pages       int              `json:"pages"`
+	Quarantined int              `json:"quarantined"` // records newly quarantined
+	Diff        CatalogDiff      `json:"diff"`
+	Error       string           `json:"error,omitempty"`
+}
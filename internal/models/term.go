@@ -0,0 +1,127 @@
+package models
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+	"yuplan/internal/dbtypes"
+)
+
+// Term is one session of the academic calendar, which sections belong to.
+// Its ID is the session code and the calendar year the session starts in,
+// e.g. FW2025 for fall/winter 2025-2026 or SU2026 for the summer after it.
+type Term struct {
+	ID           string    `json:"id"`
+	Session      string    `json:"session"`       // One of Sessions
+	AcademicYear int       `json:"academic_year"` // year the academic year starts, e.g. 2025 for both FW2025 and SU2026
+	StartsOn     time.Time `json:"starts_on"`
+	EndsOn       time.Time `json:"ends_on"`
+}
+
+// ParseTermID normalizes a term id like "fw2025" to "FW2025". ok is false
+// unless it is a session code followed by a four-digit year.
+func ParseTermID(raw string) (id string, ok bool) {
+	id = strings.ToUpper(strings.TrimSpace(raw))
+	if len(id) != 6 {
+		return "", false
+	}
+	if session := id[:2]; session != SessionFallWinter && session != SessionSummer {
+		return "", false
+	}
+	for _, r := range id[2:] {
+		if r < '0' || r > '9' {
+			return "", false
+		}
+	}
+	return id, true
+}
+
+// NewTerm returns the term with the given id, spanning September to April for
+// fall/winter and May to August for summer, the dates scripts/seed.sh gives a
+// new session. ok is false for ids ParseTermID rejects.
+func NewTerm(raw string) (t Term, ok bool) {
+	id, ok := ParseTermID(raw)
+	if !ok {
+		return Term{}, false
+	}
+	year, _ := strconv.Atoi(id[2:])
+	t = Term{ID: id, Session: id[:2]}
+	if t.Session == SessionFallWinter {
+		t.AcademicYear = year
+		t.StartsOn = time.Date(year, time.September, 1, 0, 0, 0, 0, time.UTC)
+		t.EndsOn = time.Date(year+1, time.April, 30, 0, 0, 0, 0, time.UTC)
+	} else {
+		t.AcademicYear = year - 1
+		t.StartsOn = time.Date(year, time.May, 1, 0, 0, 0, 0, time.UTC)
+		t.EndsOn = time.Date(year, time.August, 31, 0, 0, 0, 0, time.UTC)
+	}
+	return t, true
+}
+
+// AcademicTerm holds the registration, exam and grade-release dates of a term
+// in an academic year.
+type AcademicTerm struct {
+	AcademicYear   int              `json:"academic_year"` // year the session starts, e.g. 2025 for 2025-2026
+	Term           string           `json:"term"`
+	EnrollDeadline dbtypes.NullTime `json:"enroll_deadline"` // last day to enroll, null until published
+	DropDeadline   dbtypes.NullTime `json:"drop_deadline"`   // last day to drop without a grade, null until published
+	ExamsStart     time.Time        `json:"exams_start"`
+	ExamsEnd       time.Time        `json:"exams_end"`
+	GradesReleased time.Time        `json:"grades_released"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+}
+
+// UpsertAcademicTermRequest is the admin payload for setting a term's dates.
+// The deadlines are optional; leaving one out clears it.
+type UpsertAcademicTermRequest struct {
+	EnrollDeadline *time.Time `json:"enroll_deadline"`
+	DropDeadline   *time.Time `json:"drop_deadline"`
+	ExamsStart     time.Time  `json:"exams_start" binding:"required"`
+	ExamsEnd       time.Time  `json:"exams_end" binding:"required"`
+	GradesReleased time.Time  `json:"grades_released" binding:"required"`
+}
+
+// Validate checks the dates are in order (mirrors the academic_terms CHECK
+// constraint), and that the deadlines fall before exams start.
+func (r UpsertAcademicTermRequest) Validate() error {
+	if !r.ExamsStart.Before(r.ExamsEnd) {
+		return errors.New("exams_start must be before exams_end")
+	}
+	if r.GradesReleased.Before(r.ExamsEnd) {
+		return errors.New("grades_released must not be before exams_end")
+	}
+	if r.EnrollDeadline != nil && !r.EnrollDeadline.Before(r.ExamsStart) {
+		return errors.New("enroll_deadline must be before exams_start")
+	}
+	if r.DropDeadline != nil && !r.DropDeadline.Before(r.ExamsStart) {
+		return errors.New("drop_deadline must be before exams_start")
+	}
+	return nil
+}
+
+// TermDeadlines are the registration deadlines still ahead for a course's
+// term, for countdowns on course pages. A deadline that has passed, or isn't
+// published yet, is null.
+type TermDeadlines struct {
+	AcademicYear   int              `json:"academic_year"`
+	Term           string           `json:"term"`
+	EnrollDeadline dbtypes.NullTime `json:"enroll_deadline"`
+	DropDeadline   dbtypes.NullTime `json:"drop_deadline"`
+}
+
+// TermRunsIn reports whether a course offered in courseTerm meets during term.
+// Full-year courses run through fall and winter, and summer courses through
+// whichever half of the summer is asked about.
+func TermRunsIn(courseTerm, term string) bool {
+	if courseTerm == term {
+		return true
+	}
+	switch courseTerm {
+	case TermFullYear:
+		return term == TermFall || term == TermWinter
+	case TermSummer, TermSummerAlt:
+		return term == TermSummer1 || term == TermSummer2
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package models
+
+import "testing"
+
+func TestTermRunsIn(t *testing.T) {
+	tests := []struct {
+		courseTerm, term string
+		want             bool
+	}{
+		{TermFall, TermFall, true},
+		{TermFall, TermWinter, false},
+		{TermFullYear, TermFall, true},
+		{TermFullYear, TermWinter, true},
+		{TermFullYear, TermSummer, false},
+		{TermSummer, TermSummer1, true},
+		{TermSummerAlt, TermSummer2, true},
+		{TermSummer1, TermSummer, false},
+	}
+	for _, tt := range tests {
+		if got := TermRunsIn(tt.courseTerm, tt.term); got != tt.want {
+			t.Errorf("TermRunsIn(%q, %q) = %v, want %v", tt.courseTerm, tt.term, got, tt.want)
+		}
+	}
+}
+
+func TestParseTermID(t *testing.T) {
+	tests := []struct {
+		raw, want string
+		ok        bool
+	}{
+		{"FW2025", "FW2025", true},
+		{" su2026 ", "SU2026", true},
+		{"F2025", "", false},
+		{"XY2025", "", false},
+		{"FW25", "", false},
+		{"FW-202", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		if got, ok := ParseTermID(tt.raw); got != tt.want || ok != tt.ok {
+			t.Errorf("ParseTermID(%q) = %q, %v; want %q, %v", tt.raw, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestNewTerm(t *testing.T) {
+	fw, ok := NewTerm("fw2025")
+	if !ok || fw.ID != "FW2025" || fw.Session != SessionFallWinter || fw.AcademicYear != 2025 {
+		t.Fatalf("NewTerm(fw2025) = %+v, %v", fw, ok)
+	}
+	if got := fw.StartsOn.Format("2006-01-02") + " " + fw.EndsOn.Format("2006-01-02"); got != "2025-09-01 2026-04-30" {
+		t.Errorf("FW2025 spans %s", got)
+	}
+
+	su, ok := NewTerm("SU2026")
+	if !ok || su.Session != SessionSummer || su.AcademicYear != 2025 {
+		t.Fatalf("NewTerm(SU2026) = %+v, %v", su, ok)
+	}
+	if got := su.StartsOn.Format("2006-01-02") + " " + su.EndsOn.Format("2006-01-02"); got != "2026-05-01 2026-08-31" {
+		t.Errorf("SU2026 spans %s", got)
+	}
+
+	if _, ok := NewTerm("F2025"); ok {
+		t.Error("NewTerm(F2025) should fail")
+	}
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"strings"
+	"time"
+	"yuplan/internal/dbtypes"
+)
+
+// Equivalency maps a course at another institution to a York course.
+type Equivalency struct {
+	ID                 string             `json:"id"`
+	Institution        string             `json:"institution"`
+	ExternalCourseCode string             `json:"external_course_code"`
+	YorkCourseCode     string             `json:"york_course_code"`
+	Confidence         string             `json:"confidence"`
+	Notes              dbtypes.NullString `json:"notes"`
+	CreatedAt          time.Time          `json:"created_at"`
+	UpdatedAt          time.Time          `json:"updated_at"`
+}
+
+// UpsertEquivalencyRequest is the admin payload for creating or updating a mapping.
+type UpsertEquivalencyRequest struct {
+	Institution        string             `json:"institution" binding:"required,max=200"`
+	ExternalCourseCode string             `json:"external_course_code" binding:"required,max=50"`
+	YorkCourseCode     string             `json:"york_course_code" binding:"required,max=20"`
+	Confidence         string             `json:"confidence" binding:"required,oneof=high medium low"`
+	Notes              dbtypes.NullString `json:"notes"`
+}
+
+// TransferEvaluateRequest lists courses taken at one external institution.
+type TransferEvaluateRequest struct {
+	Institution string   `json:"institution" binding:"required,max=200"`
+	Courses     []string `json:"courses" binding:"required,min=1,max=50,dive,required,max=50"`
+}
+
+// TransferEvaluation is the result for a single external course.
+type TransferEvaluation struct {
+	ExternalCourseCode string        `json:"external_course_code"`
+	Equivalencies      []Equivalency `json:"equivalencies"`
+}
+
+// NormalizeInstitution makes institution names compare case- and spacing-insensitively.
+func NormalizeInstitution(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// NormalizeCourseCode uppercases a course code and drops spaces, so "math 1a" matches "MATH1A".
+func NormalizeCourseCode(code string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(code), ""))
+}
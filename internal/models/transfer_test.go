@@ -0,0 +1,33 @@
+package models
+
+import "testing"
+
+func TestNormalizeInstitution(t *testing.T) {
+	if got := NormalizeInstitution("  Seneca   Polytechnic "); got != "seneca polytechnic" {
+		t.Errorf("Expected 'seneca polytechnic', got %q", got)
+	}
+}
+
+func TestNormalizeCourseCode(t *testing.T) {
+	tests := map[string]string{
+		"MATH1A":   "MATH1A",
+		"math 1a":  "MATH1A",
+		" Eac 150": "EAC150",
+		"":         "",
+	}
+	for input, expected := range tests {
+		if got := NormalizeCourseCode(input); got != expected {
+			t.Errorf("NormalizeCourseCode(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
+
+func TestConfidenceRank(t *testing.T) {
+	if !(ConfidenceRank(EquivalencyConfidenceHigh) < ConfidenceRank(EquivalencyConfidenceMedium) &&
+		ConfidenceRank(EquivalencyConfidenceMedium) < ConfidenceRank(EquivalencyConfidenceLow)) {
+		t.Error("Expected high < medium < low")
+	}
+	if ConfidenceRank("unknown") <= ConfidenceRank(EquivalencyConfidenceLow) {
+		t.Error("Expected unknown confidence to sort last")
+	}
+}
@@ -3,6 +3,7 @@ package models
 import (
 	"testing"
 	"time"
+	"yuplan/internal/dbtypes"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -13,7 +14,7 @@ func TestTutorialModel(t *testing.T) {
 		ID:            "tutorial-1",
 		SectionID:     "section-1",
 		CatalogNumber: "TUT01",
-		Times:         &times,
+		Times:         dbtypes.NewNullString(times),
 		CreatedAt:     time.Now(),
 		UpdatedAt:     time.Now(),
 	}
@@ -21,6 +22,6 @@ func TestTutorialModel(t *testing.T) {
 	assert.Equal(t, "tutorial-1", tutorial.ID)
 	assert.Equal(t, "section-1", tutorial.SectionID)
 	assert.Equal(t, "TUT01", tutorial.CatalogNumber)
-	assert.NotNil(t, tutorial.Times)
-	assert.Equal(t, times, *tutorial.Times)
+	assert.True(t, tutorial.Times.Valid)
+	assert.Equal(t, times, tutorial.Times.String)
 }
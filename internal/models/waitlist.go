@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// Waitlist odds confidence levels, by how many past sections they rest on.
+const (
+	WaitlistConfidenceNone   = "none"   // no past section filled up; no estimate
+	WaitlistConfidenceLow    = "low"    // fewer than 5
+	WaitlistConfidenceMedium = "medium" // fewer than 15
+	WaitlistConfidenceHigh   = "high"
+)
+
+// MaxWaitlistPosition bounds the position GET /sections/:section_id/waitlist-odds accepts.
+const MaxWaitlistPosition = 1000
+
+// EnrollmentSnapshot is a section's seat count as ingested at one time.
+type EnrollmentSnapshot struct {
+	SectionID  string
+	Capacity   int
+	Enrolled   int
+	RecordedAt time.Time
+}
+
+// WaitlistOdds estimates the chance of getting into a full section from a
+// waitlist position: the share of past offerings of the course in which at
+// least that many seats opened up after they filled.
+type WaitlistOdds struct {
+	SectionID      string   `json:"section_id"`
+	Position       int      `json:"position"`
+	Probability    *float64 `json:"probability"`     // null when Confidence is none
+	ConfidenceLow  *float64 `json:"confidence_low"`  // 95% interval around Probability
+	ConfidenceHigh *float64 `json:"confidence_high"` // likewise
+	SampleSize     int      `json:"sample_size"`     // past sections that filled up
+	Confidence     string   `json:"confidence"`      // One of the WaitlistConfidence* levels
+	Caveats        []string `json:"caveats"`
+}
@@ -0,0 +1,109 @@
+// Package moderation decides whether a new review is published straight away
+// or held for an admin, by the ordered rules admins keep in moderation_rules.
+package moderation
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+	"yuplan/internal/models"
+)
+
+// Store loads the rules and the author history they are checked against.
+// Implemented by repository.ModerationRepository.
+type Store interface {
+	ListRules(ctx context.Context) ([]models.ModerationRule, error)
+	FirstReviewAt(ctx context.Context, email string) (time.Time, bool, error)
+}
+
+// DefaultBlockedWords are the words the no_profanity condition looks for when
+// MODERATION_BLOCKED_WORDS isn't set. Matching is by whole word, ignoring case.
+var DefaultBlockedWords = []string{
+	"asshole", "bastard", "bitch", "bullshit", "cunt", "dick", "fag", "faggot",
+	"fuck", "fucked", "fucking", "motherfucker", "nigger", "retard", "retarded",
+	"shit", "shitty", "slut", "whore",
+}
+
+// Moderator applies the moderation rules to new reviews.
+type Moderator struct {
+	store   Store
+	blocked map[string]bool
+	now     func() time.Time
+}
+
+// NewModerator checks no_profanity against blockedWords, or DefaultBlockedWords if there are none.
+func NewModerator(store Store, blockedWords []string) *Moderator {
+	if len(blockedWords) == 0 {
+		blockedWords = DefaultBlockedWords
+	}
+	blocked := make(map[string]bool, len(blockedWords))
+	for _, w := range blockedWords {
+		blocked[strings.ToLower(w)] = true
+	}
+	return &Moderator{store: store, blocked: blocked, now: time.Now}
+}
+
+// Decide moderates a review about to be created. The first enabled rule whose
+// conditions all hold decides; a review no rule matches is held. With no
+// enabled rules at all, every review is approved.
+func (m *Moderator) Decide(ctx context.Context, review *models.Review) (models.ModerationDecision, error) {
+	rules, err := m.store.ListRules(ctx)
+	if err != nil {
+		return models.ModerationDecision{}, err
+	}
+
+	var enabled []models.ModerationRule
+	needsAge := false
+	for _, rule := range rules {
+		if rule.Enabled {
+			enabled = append(enabled, rule)
+			needsAge = needsAge || rule.MinAuthorAgeDays > 0
+		}
+	}
+	if len(enabled) == 0 {
+		return models.ModerationDecision{Moderation: models.ModerationApproved}, nil
+	}
+
+	subject := models.ModerationSubject{
+		Email:        review.Email,
+		TextLength:   utf8.RuneCountInString(review.ReviewText.String),
+		HasProfanity: m.profane(review.ReviewText.String) || m.profane(review.AuthorName.String),
+	}
+	// Only looked up when a rule needs it, since most rule sets won't
+	if needsAge {
+		first, ok, err := m.store.FirstReviewAt(ctx, review.Email)
+		if err != nil {
+			return models.ModerationDecision{}, err
+		}
+		if ok {
+			subject.AuthorAge = m.now().Sub(first)
+		}
+	}
+
+	for _, rule := range enabled {
+		if !rule.Matches(subject) {
+			continue
+		}
+		decision := models.ModerationDecision{Moderation: models.ModerationApproved, Rule: rule.Name}
+		if rule.Action == models.ModerationActionQueue {
+			decision.Moderation = models.ModerationPending
+		}
+		return decision, nil
+	}
+	return models.ModerationDecision{Moderation: models.ModerationPending}, nil
+}
+
+// profane reports whether text contains a blocked word.
+func (m *Moderator) profane(text string) bool {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, w := range words {
+		if m.blocked[w] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"yuplan/internal/dbtypes"
+	"yuplan/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var now = time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+type fakeStore struct {
+	rules       []models.ModerationRule
+	err         error
+	firstReview time.Time
+	lookups     int
+}
+
+func (f *fakeStore) ListRules(ctx context.Context) ([]models.ModerationRule, error) {
+	return f.rules, f.err
+}
+
+func (f *fakeStore) FirstReviewAt(ctx context.Context, email string) (time.Time, bool, error) {
+	f.lookups++
+	return f.firstReview, !f.firstReview.IsZero(), nil
+}
+
+func newModerator(store *fakeStore, words ...string) *Moderator {
+	m := NewModerator(store, words)
+	m.now = func() time.Time { return now }
+	return m
+}
+
+func review(email, text string) *models.Review {
+	return &models.Review{Email: email, ReviewText: dbtypes.NewNullString(text)}
+}
+
+func TestDecide_NoRulesApproves(t *testing.T) {
+	store := &fakeStore{rules: []models.ModerationRule{{Name: "off", Action: models.ModerationActionQueue}}}
+
+	decision, err := newModerator(store).Decide(context.Background(), review("a@yorku.ca", "fuck this"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.ModerationApproved, decision.Moderation)
+	assert.Zero(t, store.lookups)
+}
+
+func TestDecide_FirstMatchWins(t *testing.T) {
+	store := &fakeStore{rules: []models.ModerationRule{
+		{Name: "gmail", Action: models.ModerationActionQueue, Enabled: true, EmailDomains: []string{"gmail.com"}},
+		{Name: "clean yorku", Action: models.ModerationActionApprove, Enabled: true, NoProfanity: true, EmailDomains: []string{"yorku.ca"}},
+	}}
+	m := newModerator(store)
+
+	tests := []struct {
+		name string
+		in   *models.Review
+		want string
+		rule string
+	}{
+		{"clean subdomain", review("s@my.yorku.ca", "Great prof."), models.ModerationApproved, "clean yorku"},
+		{"queued by earlier rule", review("s@gmail.com", "Great prof."), models.ModerationPending, "gmail"},
+		{"profanity matches nothing", review("s@yorku.ca", "Shit, hard."), models.ModerationPending, ""},
+		{"blocked words are whole words", review("s@yorku.ca", "Shitake mushrooms"), models.ModerationApproved, "clean yorku"},
+	}
+	for _, tt := range tests {
+		decision, err := m.Decide(context.Background(), tt.in)
+
+		assert.NoError(t, err, tt.name)
+		assert.Equal(t, tt.want, decision.Moderation, tt.name)
+		assert.Equal(t, tt.rule, decision.Rule, tt.name)
+	}
+}
+
+func TestDecide_AuthorAge(t *testing.T) {
+	rules := []models.ModerationRule{{Name: "regulars", Action: models.ModerationActionApprove, Enabled: true, MinAuthorAgeDays: 30}}
+
+	veteran := &fakeStore{rules: rules, firstReview: now.Add(-60 * 24 * time.Hour)}
+	decision, err := newModerator(veteran).Decide(context.Background(), review("a@yorku.ca", "ok"))
+	assert.NoError(t, err)
+	assert.Equal(t, models.ModerationApproved, decision.Moderation)
+	assert.Equal(t, 1, veteran.lookups)
+
+	newcomer := &fakeStore{rules: rules}
+	decision, err = newModerator(newcomer).Decide(context.Background(), review("a@yorku.ca", "ok"))
+	assert.NoError(t, err)
+	assert.Equal(t, models.ModerationPending, decision.Moderation)
+}
+
+func TestDecide_CustomWordsAndErrors(t *testing.T) {
+	store := &fakeStore{rules: []models.ModerationRule{{Name: "clean", Action: models.ModerationActionApprove, Enabled: true, NoProfanity: true}}}
+
+	decision, _ := newModerator(store, "Heck").Decide(context.Background(), review("a@yorku.ca", "what the heck"))
+	assert.Equal(t, models.ModerationPending, decision.Moderation)
+
+	_, err := newModerator(&fakeStore{err: errors.New("db down")}).Decide(context.Background(), review("a@yorku.ca", "ok"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,95 @@
+// Package offerings derives when courses are usually offered from their history.
+package offerings
+
+import (
+	"context"
+	"log"
+	"time"
+	"yuplan/internal/jobs"
+	"yuplan/internal/models"
+)
+
+// Store reads offering history and writes summaries. Implemented by repository.OfferingRepository.
+type Store interface {
+	ListOfferings(ctx context.Context) ([]models.CourseOffering, error)
+	SaveSummaries(ctx context.Context, summaries []models.OfferingSummary) error
+}
+
+// jobLocker keeps scheduled runs to one instance at a time, once per tick. Implemented by jobs.Locker.
+type jobLocker interface {
+	Do(ctx context.Context, job string, tick time.Time, fn func(ctx context.Context) error) (bool, error)
+}
+
+// Refresher recomputes course_offering_summaries from course_offerings.
+type Refresher struct {
+	store  Store
+	locker jobLocker
+}
+
+func NewRefresher(store Store) *Refresher {
+	return &Refresher{store: store}
+}
+
+// WithLocker makes Start skip runs while another instance holds the refresh lock.
+func (r *Refresher) WithLocker(locker jobLocker) *Refresher {
+	r.locker = locker
+	return r
+}
+
+// Run recomputes every summary and returns how many were written.
+func (r *Refresher) Run(ctx context.Context) (int, error) {
+	history, err := r.store.ListOfferings(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	// History is ordered by code, so each course's offerings are adjacent
+	var summaries []models.OfferingSummary
+	for start := 0; start < len(history); {
+		end := start
+		for end < len(history) && history[end].Code == history[start].Code {
+			end++
+		}
+		if summary, ok := models.SummarizeOfferings(history[start].Code, history[start:end]); ok {
+			summaries = append(summaries, summary)
+		}
+		start = end
+	}
+
+	if err := r.store.SaveSummaries(ctx, summaries); err != nil {
+		return 0, err
+	}
+	return len(summaries), nil
+}
+
+// Start refreshes immediately and then every interval until ctx is done.
+func (r *Refresher) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := r.runScheduled(ctx, jobs.Tick(time.Now(), interval)); err != nil {
+				log.Printf("offering refresh failed: %v", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (r *Refresher) runScheduled(ctx context.Context, tick time.Time) error {
+	run := func(ctx context.Context) error {
+		_, err := r.Run(ctx)
+		return err
+	}
+	if r.locker == nil {
+		return run(ctx)
+	}
+	_, err := r.locker.Do(ctx, "offering_refresh", tick, run)
+	return err
+}
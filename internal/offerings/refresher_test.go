@@ -0,0 +1,76 @@
+package offerings
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeStore struct {
+	history []models.CourseOffering
+	listErr error
+	saved   []models.OfferingSummary
+}
+
+func (f *fakeStore) ListOfferings(ctx context.Context) ([]models.CourseOffering, error) {
+	return f.history, f.listErr
+}
+
+func (f *fakeStore) SaveSummaries(ctx context.Context, summaries []models.OfferingSummary) error {
+	f.saved = summaries
+	return nil
+}
+
+type fakeLocker struct {
+	held bool
+}
+
+func (f *fakeLocker) Do(ctx context.Context, job string, tick time.Time, fn func(ctx context.Context) error) (bool, error) {
+	if f.held {
+		return false, nil
+	}
+	return true, fn(ctx)
+}
+
+func TestRefresher_Run(t *testing.T) {
+	store := &fakeStore{history: []models.CourseOffering{
+		{Code: "EECS2030", AcademicYear: 2024, Term: "F"},
+		{Code: "EECS2030", AcademicYear: 2025, Term: "F"},
+		{Code: "MATH1013", AcademicYear: 2025, Term: "W"},
+	}}
+
+	n, err := NewRefresher(store).Run(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Len(t, store.saved, 2)
+	assert.Equal(t, "EECS2030", store.saved[0].Code)
+	assert.Equal(t, models.OfferingAnnual, store.saved[0].Frequency)
+	assert.Equal(t, "MATH1013", store.saved[1].Code)
+	assert.Equal(t, models.OfferingSingle, store.saved[1].Frequency)
+}
+
+func TestRefresher_RunListError(t *testing.T) {
+	store := &fakeStore{listErr: errors.New("db down")}
+
+	_, err := NewRefresher(store).Run(context.Background())
+	assert.Error(t, err)
+	assert.Nil(t, store.saved)
+}
+
+func TestRefresher_ScheduledRunsRespectLocker(t *testing.T) {
+	store := &fakeStore{history: []models.CourseOffering{{Code: "EECS2030", AcademicYear: 2025, Term: "F"}}}
+	locker := &fakeLocker{held: true}
+	refresher := NewRefresher(store).WithLocker(locker)
+
+	assert.NoError(t, refresher.runScheduled(context.Background(), time.Now()))
+	assert.Nil(t, store.saved)
+
+	locker.held = false
+	assert.NoError(t, refresher.runScheduled(context.Background(), time.Now()))
+	assert.Len(t, store.saved, 1)
+}
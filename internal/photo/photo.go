@@ -0,0 +1,147 @@
+// Package photo stores instructor photos in standard square sizes and builds
+// the URLs they are served from.
+package photo
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif" // registered for image.Decode
+	"image/jpeg"
+	_ "image/png" // registered for image.Decode
+	"strings"
+	"yuplan/internal/export"
+	"yuplan/internal/models"
+)
+
+// Standard sizes, as the side of the square in pixels.
+const (
+	SizeSmall  = 64  // avatars in section lists
+	SizeMedium = 256 // instructor profile
+	SizeLarge  = 512
+)
+
+// Sizes is every size a photo is stored in, keyed by the name its URL goes under.
+var Sizes = map[string]int{"small": SizeSmall, "medium": SizeMedium, "large": SizeLarge}
+
+const (
+	// MaxUploadBytes caps an uploaded original.
+	MaxUploadBytes = 5 << 20
+	// maxSourcePixels caps an original's width times height, so a small file
+	// can't decode into a huge image.
+	maxSourcePixels = 25_000_000
+	jpegQuality     = 85
+	keyPrefix       = "instructors/"
+)
+
+// ErrUnsupported is returned by Save for data that isn't a JPEG, PNG or GIF
+// image of a sensible size.
+var ErrUnsupported = errors.New("not a supported image")
+
+// Library saves photos to a store and turns their ids into URLs under baseURL,
+// typically a CDN in front of the store's bucket.
+type Library struct {
+	store   export.Store
+	baseURL string
+}
+
+func NewLibrary(store export.Store, baseURL string) *Library {
+	return &Library{store: store, baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// Save crops the image to a centred square, stores it in every size and
+// returns its id. Ids are derived from the original's content, so saving the
+// same photo twice stores it once and its URLs never change what they serve.
+func (l *Library) Save(ctx context.Context, data []byte) (string, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || config.Width*config.Height > maxSourcePixels {
+		return "", ErrUnsupported
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return "", ErrUnsupported
+	}
+
+	sum := sha256.Sum256(data)
+	id := hex.EncodeToString(sum[:12])
+	square := cropSquare(src)
+	for name, side := range Sizes {
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, resize(square, side), &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return "", fmt.Errorf("encode %s photo: %w", name, err)
+		}
+		if err := l.store.Put(ctx, objectKey(id, name), buf.Bytes()); err != nil {
+			return "", fmt.Errorf("store %s photo: %w", name, err)
+		}
+	}
+	return id, nil
+}
+
+// URLs returns where each size of the photo with the given id is served.
+func (l *Library) URLs(id string) *models.InstructorPhoto {
+	url := func(name string) string { return l.baseURL + "/" + objectKey(id, name) }
+	return &models.InstructorPhoto{Small: url("small"), Medium: url("medium"), Large: url("large")}
+}
+
+func objectKey(id, size string) string {
+	return keyPrefix + id + "/" + size + ".jpg"
+}
+
+// cropSquare returns the largest square centred in img.
+func cropSquare(img image.Image) image.Image {
+	b := img.Bounds()
+	side := min(b.Dx(), b.Dy())
+	x0 := b.Min.X + (b.Dx()-side)/2
+	y0 := b.Min.Y + (b.Dy()-side)/2
+	return subImage(img, image.Rect(x0, y0, x0+side, y0+side))
+}
+
+func subImage(img image.Image, r image.Rectangle) image.Image {
+	if s, ok := img.(interface {
+		SubImage(image.Rectangle) image.Image
+	}); ok {
+		return s.SubImage(r)
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, r.Dx(), r.Dy()))
+	for y := 0; y < r.Dy(); y++ {
+		for x := 0; x < r.Dx(); x++ {
+			dst.Set(x, y, img.At(r.Min.X+x, r.Min.Y+y))
+		}
+	}
+	return dst
+}
+
+// resize scales a square image to side x side. Each output pixel averages the
+// source pixels it covers, which keeps downscaled photos free of aliasing;
+// upscaling repeats source pixels.
+func resize(src image.Image, side int) *image.RGBA {
+	b := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, side, side))
+	for y := 0; y < side; y++ {
+		sy0 := b.Min.Y + y*b.Dy()/side
+		sy1 := max(b.Min.Y+(y+1)*b.Dy()/side, sy0+1)
+		for x := 0; x < side; x++ {
+			sx0 := b.Min.X + x*b.Dx()/side
+			sx1 := max(b.Min.X+(x+1)*b.Dx()/side, sx0+1)
+
+			var r, g, bl, a, n uint64
+			for sy := sy0; sy < sy1; sy++ {
+				for sx := sx0; sx < sx1; sx++ {
+					pr, pg, pb, pa := src.At(sx, sy).RGBA()
+					r, g, bl, a = r+uint64(pr), g+uint64(pg), bl+uint64(pb), a+uint64(pa)
+					n++
+				}
+			}
+			i := dst.PixOffset(x, y)
+			dst.Pix[i+0] = uint8(r / n >> 8)
+			dst.Pix[i+1] = uint8(g / n >> 8)
+			dst.Pix[i+2] = uint8(bl / n >> 8)
+			dst.Pix[i+3] = uint8(a / n >> 8)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,99 @@
+package photo
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+	"yuplan/internal/export"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryStore struct {
+	objects map[string][]byte
+}
+
+func (m *memoryStore) Put(ctx context.Context, key string, body []byte) error {
+	m.objects[key] = body
+	return nil
+}
+
+func (m *memoryStore) List(ctx context.Context, prefix string) ([]export.Object, error) {
+	return nil, nil
+}
+
+func (m *memoryStore) Delete(ctx context.Context, key string) error {
+	delete(m.objects, key)
+	return nil
+}
+
+// portrait is a 300x400 PNG, red above the middle and blue below.
+func portrait(t *testing.T) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, 300, 400))
+	for y := 0; y < 400; y++ {
+		for x := 0; x < 300; x++ {
+			c := color.RGBA{R: 255, A: 255}
+			if y >= 200 {
+				c = color.RGBA{B: 255, A: 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestLibrary_Save(t *testing.T) {
+	store := &memoryStore{objects: map[string][]byte{}}
+	library := NewLibrary(store, "https://cdn.example.com/")
+	data := portrait(t)
+
+	id, err := library.Save(context.Background(), data)
+	assert.NoError(t, err)
+	assert.Len(t, store.objects, len(Sizes))
+
+	for name, side := range Sizes {
+		body, ok := store.objects["instructors/"+id+"/"+name+".jpg"]
+		if !assert.True(t, ok, name) {
+			continue
+		}
+		img, err := jpeg.Decode(bytes.NewReader(body))
+		assert.NoError(t, err)
+		assert.Equal(t, image.Rect(0, 0, side, side), img.Bounds())
+	}
+
+	// The same photo keeps its id
+	again, err := library.Save(context.Background(), data)
+	assert.NoError(t, err)
+	assert.Equal(t, id, again)
+
+	urls := library.URLs(id)
+	assert.Equal(t, "https://cdn.example.com/instructors/"+id+"/medium.jpg", urls.Medium)
+}
+
+func TestLibrary_Save_RejectsNonImages(t *testing.T) {
+	library := NewLibrary(&memoryStore{objects: map[string][]byte{}}, "")
+	_, err := library.Save(context.Background(), []byte("not an image"))
+	assert.ErrorIs(t, err, ErrUnsupported)
+}
+
+func TestCropSquare_KeepsTheCentre(t *testing.T) {
+	img, err := png.Decode(bytes.NewReader(portrait(t)))
+	assert.NoError(t, err)
+
+	square := cropSquare(img)
+	assert.Equal(t, 300, square.Bounds().Dx())
+	assert.Equal(t, 300, square.Bounds().Dy())
+
+	// The crop spans rows 50 to 350, so the halves stay even
+	small := resize(square, 2)
+	r, _, _, _ := small.At(0, 0).RGBA()
+	_, _, b, _ := small.At(0, 1).RGBA()
+	assert.Equal(t, uint32(0xffff), r)
+	assert.Equal(t, uint32(0xffff), b)
+}
@@ -0,0 +1,113 @@
+// Package planner checks candidate timetables and explains why a combination
+// of activities doesn't fit.
+package planner
+
+import (
+	"fmt"
+	"strings"
+	"yuplan/internal/models"
+)
+
+// Activity is one section activity placed in a candidate timetable.
+type Activity struct {
+	CourseCode string           `json:"course_code"`
+	Section    string           `json:"section"` // section letter
+	Type       string           `json:"type"`    // models.Activity*
+	Meetings   []models.Meeting `json:"-"`
+}
+
+// Conflict is two activities meeting at the same time on one weekday.
+type Conflict struct {
+	A           Activity `json:"a"`
+	B           Activity `json:"b"`
+	Day         string   `json:"day"`
+	Start       int      `json:"start"` // minutes since midnight the overlap begins
+	End         int      `json:"end"`
+	Explanation string   `json:"explanation"`
+}
+
+// FindConflicts returns every overlap between pairs of activities, one per
+// pair and day, in input order. Unscheduled meetings never conflict.
+func FindConflicts(activities []Activity) []Conflict {
+	var conflicts []Conflict
+	for i := range activities {
+		for j := i + 1; j < len(activities); j++ {
+			conflicts = append(conflicts, conflictsBetween(activities[i], activities[j])...)
+		}
+	}
+	return conflicts
+}
+
+func conflictsBetween(a, b Activity) []Conflict {
+	var conflicts []Conflict
+	seen := map[string]bool{}
+	for _, ma := range a.Meetings {
+		aStart, aEnd, ok := ma.Window()
+		if !ok {
+			continue
+		}
+		for _, mb := range b.Meetings {
+			bStart, bEnd, ok := mb.Window()
+			if !ok || ma.Day != mb.Day || seen[ma.Day] {
+				continue
+			}
+			start, end := max(aStart, bStart), min(aEnd, bEnd)
+			if start >= end {
+				continue
+			}
+			seen[ma.Day] = true
+			c := Conflict{A: a, B: b, Day: ma.Day, Start: start, End: end}
+			c.Explanation = Explain(c)
+			conflicts = append(conflicts, c)
+		}
+	}
+	return conflicts
+}
+
+var dayNames = map[string]string{
+	"M": "Mondays",
+	"T": "Tuesdays",
+	"W": "Wednesdays",
+	"R": "Thursdays",
+	"F": "Fridays",
+	"S": "Saturdays",
+	"U": "Sundays",
+}
+
+var activityNames = map[string]string{
+	models.ActivityLecture:  "Lecture",
+	models.ActivityTutorial: "Tutorial",
+	models.ActivityLab:      "Lab",
+	models.ActivitySeminar:  "Seminar",
+	models.ActivityStudio:   "Studio",
+}
+
+// Explain describes a conflict for display, e.g. "EECS2030 Section A Lab
+// overlaps MATH1090 Section M Tutorial on Tuesdays 14:30–16:20".
+func Explain(c Conflict) string {
+	day, ok := dayNames[c.Day]
+	if !ok {
+		day = c.Day
+	}
+	return fmt.Sprintf("%s overlaps %s on %s %s–%s",
+		describe(c.A), describe(c.B), day, clock(c.Start), clock(c.End))
+}
+
+func describe(a Activity) string {
+	name, ok := activityNames[a.Type]
+	if !ok {
+		name = a.Type
+	}
+	parts := []string{a.CourseCode}
+	if a.Section != "" {
+		parts = append(parts, "Section "+a.Section)
+	}
+	if name != "" {
+		parts = append(parts, name)
+	}
+	return strings.Join(parts, " ")
+}
+
+func clock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
@@ -0,0 +1,73 @@
+package planner
+
+import (
+	"testing"
+	"yuplan/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func meeting(day, time, duration string) models.Meeting {
+	return models.Meeting{Day: day, Time: time, Duration: duration, Campus: "Keele"}
+}
+
+func TestFindConflicts_ExplainsOverlap(t *testing.T) {
+	lab := Activity{CourseCode: "EECS2030", Section: "A", Type: models.ActivityLab,
+		Meetings: []models.Meeting{meeting("T", "14:30", "170")}}
+	tutorial := Activity{CourseCode: "MATH1090", Section: "M", Type: models.ActivityTutorial,
+		Meetings: []models.Meeting{meeting("T", "13:30", "170")}}
+
+	conflicts := FindConflicts([]Activity{lab, tutorial})
+
+	assert.Len(t, conflicts, 1)
+	assert.Equal(t, "T", conflicts[0].Day)
+	assert.Equal(t, 14*60+30, conflicts[0].Start)
+	assert.Equal(t, 16*60+20, conflicts[0].End)
+	assert.Equal(t, "EECS2030 Section A Lab overlaps MATH1090 Section M Tutorial on Tuesdays 14:30–16:20", conflicts[0].Explanation)
+}
+
+func TestFindConflicts_OnePerDay(t *testing.T) {
+	a := Activity{CourseCode: "EECS2030", Section: "A", Type: models.ActivityLecture,
+		Meetings: []models.Meeting{meeting("M", "10:00", "80"), meeting("W", "10:00", "80"), meeting("W", "11:00", "30")}}
+	b := Activity{CourseCode: "EECS2021", Section: "B", Type: models.ActivityLecture,
+		Meetings: []models.Meeting{meeting("W", "10:30", "80"), meeting("F", "10:00", "80")}}
+
+	conflicts := FindConflicts([]Activity{a, b})
+
+	assert.Len(t, conflicts, 1)
+	assert.Equal(t, "EECS2030 Section A Lecture overlaps EECS2021 Section B Lecture on Wednesdays 10:30–11:20", conflicts[0].Explanation)
+}
+
+func TestFindConflicts_NoOverlap(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b models.Meeting
+	}{
+		{"back to back", meeting("M", "10:00", "60"), meeting("M", "11:00", "60")},
+		{"different days", meeting("M", "10:00", "60"), meeting("T", "10:00", "60")},
+		{"asynchronous", meeting("", "0:00", "0"), meeting("M", "10:00", "60")},
+		{"unparseable time", meeting("M", "noon", "60"), meeting("M", "10:00", "60")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conflicts := FindConflicts([]Activity{
+				{CourseCode: "EECS2030", Meetings: []models.Meeting{tt.a}},
+				{CourseCode: "MATH1090", Meetings: []models.Meeting{tt.b}},
+			})
+			assert.Empty(t, conflicts)
+		})
+	}
+}
+
+func TestExplain_UnknownCodesShownAsIs(t *testing.T) {
+	c := Conflict{
+		A:     Activity{CourseCode: "ADMS1000", Type: models.ActivityBlended},
+		B:     Activity{CourseCode: "ADMS1010", Section: "Q", Type: models.ActivityLecture},
+		Day:   "X",
+		Start: 9 * 60,
+		End:   9*60 + 5,
+	}
+
+	assert.Equal(t, "ADMS1000 BLEN overlaps ADMS1010 Section Q Lecture on X 09:00–09:05", Explain(c))
+}
@@ -0,0 +1,294 @@
+package planner
+
+import (
+	"fmt"
+	"sort"
+	"yuplan/internal/models"
+)
+
+const (
+	// maxSchedules stops the search once this many timetables fit; they are
+	// ranked before the caller's limit is applied.
+	maxSchedules = 500
+	// maxSteps bounds the work spent on one request, counted in activities tried.
+	maxSteps = 200_000
+	// maxReasons caps the explanations returned when nothing fits.
+	maxReasons = 5
+)
+
+// Course is one requested course with the sections of every offering of it
+// that runs in the term being planned, and the blocks those offerings enroll
+// students into, if any.
+type Course struct {
+	CourseCode string
+	Sections   []models.Section
+	Blocks     []models.Block
+}
+
+// Constraints are the student's hard limits on a timetable.
+type Constraints struct {
+	EarliestStart int      // minutes since midnight no meeting may start before; 0 = none
+	LatestEnd     int      // minutes since midnight no meeting may end after; 0 = none
+	DaysOff       []string // days in meeting-time notation with no meetings at all
+}
+
+// allows reports whether every meeting of an activity fits the constraints.
+func (c Constraints) allows(meetings []models.Meeting) bool {
+	for _, m := range meetings {
+		start, end, ok := m.Window()
+		if !ok {
+			continue
+		}
+		if start < c.EarliestStart || (c.LatestEnd > 0 && end > c.LatestEnd) {
+			return false
+		}
+		for _, day := range c.DaysOff {
+			if m.Day == day {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Placed is an activity chosen for a timetable, with its meetings.
+type Placed struct {
+	Activity
+	ID            string           `json:"id"` // the section activity's id
+	CatalogNumber string           `json:"catalog_number"`
+	Meetings      []models.Meeting `json:"meetings"`
+}
+
+// Schedule is one timetable in which nothing overlaps.
+type Schedule struct {
+	Activities  []Placed   `json:"activities"`
+	Days        int        `json:"days"`         // weekdays with at least one meeting
+	IdleMinutes int        `json:"idle_minutes"` // time between meetings on the same day, summed over the week
+	Warnings    []Transfer `json:"warnings"`     // tight transfers, when the preference allows them
+}
+
+// Result is what Generate found.
+type Result struct {
+	Schedules []Schedule `json:"schedules"`
+	Found     int        `json:"found"`             // timetables that fit, before the limit
+	Truncated bool       `json:"truncated"`         // the search stopped early, so more may fit
+	Reasons   []string   `json:"reasons,omitempty"` // when nothing fits, a sample of why
+}
+
+// Generate finds timetables that take one section of every course and, within
+// it, one activity of each type it offers (e.g. its lecture and one of its
+// tutorials), or one whole block of a course that has blocks, with no
+// overlaps and within the constraints. Tight transfers
+// either rule a timetable out or are attached as warnings, per pref.
+// Timetables come back with the fewest days on campus first, then the least
+// idle time, at most limit of them.
+func Generate(courses []Course, constraints Constraints, pref TransferPreference, limit int) Result {
+	g := generator{constraints: constraints, pref: pref, schedules: []Schedule{}}
+
+	options := make([][][]Placed, len(courses))
+	for i, course := range courses {
+		options[i] = g.options(course)
+		if len(options[i]) == 0 {
+			g.reason(fmt.Sprintf("%s has no section that fits the constraints", course.CourseCode))
+		}
+	}
+	if len(g.reasons) > 0 {
+		return Result{Schedules: g.schedules, Reasons: g.reasons}
+	}
+
+	// Courses with the fewest options first prunes the most
+	sort.SliceStable(options, func(i, j int) bool { return len(options[i]) < len(options[j]) })
+	g.search(options, nil)
+
+	sort.SliceStable(g.schedules, func(i, j int) bool {
+		a, b := g.schedules[i], g.schedules[j]
+		if a.Days != b.Days {
+			return a.Days < b.Days
+		}
+		return a.IdleMinutes < b.IdleMinutes
+	})
+
+	result := Result{Found: len(g.schedules), Truncated: g.truncated, Schedules: g.schedules}
+	if len(result.Schedules) > limit {
+		result.Schedules = result.Schedules[:limit]
+	}
+	if result.Found == 0 {
+		result.Reasons = g.reasons
+	}
+	return result
+}
+
+type generator struct {
+	constraints Constraints
+	pref        TransferPreference
+	schedules   []Schedule
+	reasons     []string
+	steps       int
+	truncated   bool
+}
+
+// options lists the ways to take a course: per section, every pick of one
+// activity of each type. Activities whose times can't be read are skipped,
+// since they can't be checked for conflicts. A course with blocks can only be
+// taken a whole block at a time, so its options are its blocks instead.
+func (g *generator) options(course Course) [][]Placed {
+	if len(course.Blocks) > 0 {
+		return g.blockOptions(course)
+	}
+
+	var options [][]Placed
+	for _, section := range course.Sections {
+		byType := map[string][]Placed{}
+		var types []string
+		for _, a := range section.Activities {
+			if _, seen := byType[a.CourseType]; !seen {
+				types = append(types, a.CourseType)
+				byType[a.CourseType] = []Placed{}
+			}
+			meetings, err := models.ScheduledMeetings(a.Times)
+			if err != nil || !g.constraints.allows(meetings) {
+				continue
+			}
+			byType[a.CourseType] = append(byType[a.CourseType], Placed{
+				Activity:      Activity{CourseCode: course.CourseCode, Section: section.Letter, Type: a.CourseType, Meetings: meetings},
+				ID:            a.ID,
+				CatalogNumber: a.CatalogNumber,
+				Meetings:      meetings,
+			})
+		}
+		if len(types) == 0 {
+			continue
+		}
+
+		picks := [][]Placed{{}}
+		for _, t := range types {
+			var next [][]Placed
+			for _, pick := range picks {
+				for _, a := range byType[t] {
+					next = append(next, append(pick[:len(pick):len(pick)], a))
+				}
+			}
+			picks = next
+		}
+		options = append(options, picks...)
+	}
+	return options
+}
+
+// blockOptions lists each of the course's blocks whose activities can all be
+// read and fit the constraints, with every one of its activities.
+func (g *generator) blockOptions(course Course) [][]Placed {
+	letters := map[string]string{}
+	for _, section := range course.Sections {
+		letters[section.ID] = section.Letter
+	}
+
+	var options [][]Placed
+next:
+	for _, block := range course.Blocks {
+		option := make([]Placed, 0, len(block.Activities))
+		for _, a := range block.Activities {
+			meetings, err := models.ScheduledMeetings(a.Times)
+			if err != nil || !g.constraints.allows(meetings) {
+				continue next
+			}
+			option = append(option, Placed{
+				Activity:      Activity{CourseCode: course.CourseCode, Section: letters[a.SectionID], Type: a.CourseType, Meetings: meetings},
+				ID:            a.ID,
+				CatalogNumber: a.CatalogNumber,
+				Meetings:      meetings,
+			})
+		}
+		if len(option) > 0 {
+			options = append(options, option)
+		}
+	}
+	return options
+}
+
+// search places each remaining course's options in turn after chosen,
+// backtracking on overlaps.
+func (g *generator) search(options [][][]Placed, chosen []Placed) {
+	if len(g.schedules) >= maxSchedules || g.steps >= maxSteps {
+		g.truncated = true
+		return
+	}
+	if len(options) == 0 {
+		g.complete(chosen)
+		return
+	}
+
+next:
+	for _, option := range options[0] {
+		placed := chosen
+		for _, a := range option {
+			g.steps++
+			for _, other := range placed {
+				if conflicts := conflictsBetween(other.Activity, a.Activity); len(conflicts) > 0 {
+					g.reason(conflicts[0].Explanation)
+					continue next
+				}
+			}
+			placed = append(placed[:len(placed):len(placed)], a)
+		}
+		g.search(options[1:], placed)
+		if g.truncated {
+			return
+		}
+	}
+}
+
+func (g *generator) complete(chosen []Placed) {
+	activities := make([]Activity, len(chosen))
+	for i, p := range chosen {
+		activities[i] = p.Activity
+	}
+	transfers := FindTightTransfers(activities, g.pref)
+	if g.pref.Reject && len(transfers) > 0 {
+		g.reason(transfers[0].Explanation)
+		return
+	}
+	if transfers == nil {
+		transfers = []Transfer{}
+	}
+
+	schedule := Schedule{Activities: chosen, Warnings: transfers}
+	schedule.Days, schedule.IdleMinutes = score(activities)
+	g.schedules = append(g.schedules, schedule)
+}
+
+// score counts the weekdays a timetable has meetings on and the time between
+// meetings on the same day, summed over the week.
+func score(activities []Activity) (days, idleMinutes int) {
+	byDay := map[string][][2]int{}
+	for _, a := range activities {
+		for _, m := range a.Meetings {
+			if start, end, ok := m.Window(); ok {
+				byDay[m.Day] = append(byDay[m.Day], [2]int{start, end})
+			}
+		}
+	}
+	for _, windows := range byDay {
+		days++
+		sort.Slice(windows, func(i, j int) bool { return windows[i][0] < windows[j][0] })
+		for i := 1; i < len(windows); i++ {
+			if gap := windows[i][0] - windows[i-1][1]; gap > 0 {
+				idleMinutes += gap
+			}
+		}
+	}
+	return days, idleMinutes
+}
+
+// reason keeps the first few distinct explanations of why timetables failed.
+func (g *generator) reason(explanation string) {
+	if len(g.reasons) >= maxReasons {
+		return
+	}
+	for _, r := range g.reasons {
+		if r == explanation {
+			return
+		}
+	}
+	g.reasons = append(g.reasons, explanation)
+}
@@ -0,0 +1,171 @@
+package planner
+
+import (
+	"encoding/json"
+	"testing"
+	"yuplan/internal/dbtypes"
+	"yuplan/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func activity(courseType, catalog string, meetings ...models.Meeting) models.SectionActivity {
+	times, _ := json.Marshal(meetings)
+	return models.SectionActivity{CourseType: courseType, CatalogNumber: catalog, Times: dbtypes.NewNullString(string(times))}
+}
+
+func section(letter string, activities ...models.SectionActivity) models.Section {
+	return models.Section{Letter: letter, Activities: activities}
+}
+
+func catalogs(s Schedule) []string {
+	var numbers []string
+	for _, a := range s.Activities {
+		numbers = append(numbers, a.CatalogNumber)
+	}
+	return numbers
+}
+
+func TestGenerate_PicksOneActivityPerType(t *testing.T) {
+	eecs := Course{CourseCode: "EECS2030", Sections: []models.Section{
+		section("A",
+			activity(models.ActivityLecture, "L1", meeting("M", "10:00", "80"), meeting("W", "10:00", "80")),
+			activity(models.ActivityTutorial, "T1", meeting("M", "11:30", "50")),
+			activity(models.ActivityTutorial, "T2", meeting("W", "13:00", "50"))),
+	}}
+	math := Course{CourseCode: "MATH1090", Sections: []models.Section{
+		section("M", activity(models.ActivityLecture, "M1", meeting("M", "11:30", "80"))),
+	}}
+
+	result := Generate([]Course{eecs, math}, Constraints{}, TransferPreference{}, 10)
+
+	assert.Equal(t, 1, result.Found)
+	assert.False(t, result.Truncated)
+	assert.Len(t, result.Schedules, 1)
+	assert.ElementsMatch(t, []string{"L1", "T2", "M1"}, catalogs(result.Schedules[0]))
+	assert.Equal(t, 2, result.Schedules[0].Days)
+	assert.Empty(t, result.Reasons)
+}
+
+func TestGenerate_RanksFewestDaysFirst(t *testing.T) {
+	course := Course{CourseCode: "EECS2030", Sections: []models.Section{
+		section("A", activity(models.ActivityLecture, "A1", meeting("M", "10:00", "80"), meeting("W", "10:00", "80"))),
+		section("B", activity(models.ActivityLecture, "B1", meeting("T", "10:00", "170"))),
+	}}
+
+	result := Generate([]Course{course}, Constraints{}, TransferPreference{}, 1)
+
+	assert.Equal(t, 2, result.Found)
+	assert.Len(t, result.Schedules, 1)
+	assert.Equal(t, []string{"B1"}, catalogs(result.Schedules[0]))
+}
+
+func TestGenerate_Constraints(t *testing.T) {
+	course := Course{CourseCode: "EECS2030", Sections: []models.Section{
+		section("A", activity(models.ActivityLecture, "A1", meeting("M", "08:30", "80"))),
+		section("B", activity(models.ActivityLecture, "B1", meeting("F", "13:00", "80"))),
+		section("C", activity(models.ActivityLecture, "C1", meeting("T", "13:00", "80"))),
+		section("D", activity(models.ActivityLecture, "D1", meeting("R", "19:00", "170"))),
+	}}
+
+	result := Generate([]Course{course}, Constraints{EarliestStart: 10 * 60, LatestEnd: 18 * 60, DaysOff: []string{"F"}}, TransferPreference{}, 10)
+
+	assert.Equal(t, 1, result.Found)
+	assert.Equal(t, []string{"C1"}, catalogs(result.Schedules[0]))
+}
+
+func TestGenerate_NothingFits(t *testing.T) {
+	eecs := Course{CourseCode: "EECS2030", Sections: []models.Section{
+		section("A", activity(models.ActivityLecture, "L1", meeting("M", "10:00", "80"))),
+	}}
+	math := Course{CourseCode: "MATH1090", Sections: []models.Section{
+		section("M", activity(models.ActivityLecture, "M1", meeting("M", "11:00", "80"))),
+	}}
+
+	result := Generate([]Course{eecs, math}, Constraints{}, TransferPreference{}, 10)
+
+	assert.Equal(t, 0, result.Found)
+	assert.Empty(t, result.Schedules)
+	assert.NotNil(t, result.Schedules)
+	assert.Equal(t, []string{"EECS2030 Section A Lecture overlaps MATH1090 Section M Lecture on Mondays 11:00–11:20"}, result.Reasons)
+}
+
+func TestGenerate_NoSectionFitsConstraints(t *testing.T) {
+	course := Course{CourseCode: "EECS2030", Sections: []models.Section{
+		section("A", activity(models.ActivityLecture, "L1", meeting("F", "10:00", "80"))),
+	}}
+
+	result := Generate([]Course{course}, Constraints{DaysOff: []string{"F"}}, TransferPreference{}, 10)
+
+	assert.Equal(t, 0, result.Found)
+	assert.Equal(t, []string{"EECS2030 has no section that fits the constraints"}, result.Reasons)
+}
+
+func TestGenerate_TightTransfers(t *testing.T) {
+	keele := Course{CourseCode: "EECS2030", Sections: []models.Section{
+		section("A", activity(models.ActivityLecture, "L1", room("M", "10:00", "80", "Keele", "LAS B"))),
+	}}
+	glendon := Course{CourseCode: "FRAN1000", Sections: []models.Section{
+		section("A", activity(models.ActivityLecture, "G1", room("M", "11:30", "80", "Glendon", "YH A"))),
+	}}
+
+	warned := Generate([]Course{keele, glendon}, Constraints{}, TransferPreference{}, 10)
+	assert.Equal(t, 1, warned.Found)
+	assert.Len(t, warned.Schedules[0].Warnings, 1)
+
+	rejected := Generate([]Course{keele, glendon}, Constraints{}, TransferPreference{Reject: true}, 10)
+	assert.Equal(t, 0, rejected.Found)
+	assert.Len(t, rejected.Reasons, 1)
+	assert.Contains(t, rejected.Reasons[0], "allow 45 minutes")
+}
+
+func TestGenerate_AsynchronousAndUnreadable(t *testing.T) {
+	course := Course{CourseCode: "EECS2030", Sections: []models.Section{
+		section("A",
+			activity(models.ActivityOnlineCampus, "O1"),
+			models.SectionActivity{CourseType: models.ActivityTutorial, CatalogNumber: "T1", Times: dbtypes.NewNullString("not json")},
+			activity(models.ActivityTutorial, "T2", meeting("T", "10:00", "50"))),
+	}}
+
+	result := Generate([]Course{course}, Constraints{}, TransferPreference{}, 10)
+
+	assert.Equal(t, 1, result.Found)
+	assert.Equal(t, []string{"O1", "T2"}, catalogs(result.Schedules[0]))
+	assert.Equal(t, 1, result.Schedules[0].Days)
+}
+
+func TestGenerate_BlocksAreTakenWhole(t *testing.T) {
+	lecture := activity(models.ActivityLecture, "L1", meeting("M", "10:00", "80"))
+	lab1 := activity(models.ActivityLab, "B1", meeting("M", "13:00", "110"))
+	lab2 := activity(models.ActivityLab, "B2", meeting("T", "13:00", "110"))
+	lab3 := activity(models.ActivityLab, "B3", meeting("F", "13:00", "110"))
+	for _, a := range []*models.SectionActivity{&lecture, &lab1, &lab2, &lab3} {
+		a.SectionID = "section-a"
+	}
+	a := section("A", lecture, lab1, lab2, lab3)
+	a.ID = "section-a"
+	eecs := Course{CourseCode: "EECS2030", Sections: []models.Section{a}, Blocks: []models.Block{
+		{Name: "Block 1", Activities: []models.SectionActivity{lecture, lab2}},
+		{Name: "Block 2", Activities: []models.SectionActivity{lecture, lab3}},
+	}}
+
+	// Without blocks any lab goes with the lecture; with them only the bundled ones do
+	free := Generate([]Course{{CourseCode: "EECS2030", Sections: eecs.Sections}}, Constraints{}, TransferPreference{}, 10)
+	assert.Equal(t, 3, free.Found)
+
+	result := Generate([]Course{eecs}, Constraints{}, TransferPreference{}, 10)
+	assert.Equal(t, 2, result.Found)
+	for _, s := range result.Schedules {
+		assert.Contains(t, [][]string{{"L1", "B2"}, {"L1", "B3"}}, catalogs(s))
+		assert.Equal(t, "A", s.Activities[0].Section)
+	}
+
+	// A block with any activity outside the constraints is out as a whole
+	result = Generate([]Course{eecs}, Constraints{DaysOff: []string{"F"}}, TransferPreference{}, 10)
+	assert.Equal(t, 1, result.Found)
+	assert.Equal(t, []string{"L1", "B2"}, catalogs(result.Schedules[0]))
+
+	result = Generate([]Course{eecs}, Constraints{DaysOff: []string{"M"}}, TransferPreference{}, 10)
+	assert.Equal(t, 0, result.Found)
+	assert.Equal(t, []string{"EECS2030 has no section that fits the constraints"}, result.Reasons)
+}
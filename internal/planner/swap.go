@@ -0,0 +1,54 @@
+package planner
+
+import (
+	"sort"
+	"yuplan/internal/models"
+)
+
+// Swap is another section of a course to take instead of the one being
+// dropped, with one pick of its activities.
+type Swap struct {
+	SectionID   string   `json:"section_id"`
+	Activities  []Placed `json:"activities"`
+	Days        int      `json:"days"`         // of the timetable with the swap made
+	IdleMinutes int      `json:"idle_minutes"` // likewise
+}
+
+// Swaps lists the ways to take course in a section other than the one with
+// the id drop while keeping the rest of a timetable: every pick of one
+// activity of each type, as Generate makes them, that overlaps nothing in
+// rest. Overlaps already within rest don't rule a pick out. Swaps come back
+// ranked as Generate ranks timetables, the fewest days on campus first, then
+// the least idle time.
+func Swaps(rest []Activity, course Course, drop string) []Swap {
+	var g generator
+	existing := len(FindConflicts(rest))
+
+	swaps := []Swap{}
+	for _, section := range course.Sections {
+		if section.ID == drop {
+			continue
+		}
+		for _, pick := range g.options(Course{CourseCode: course.CourseCode, Sections: []models.Section{section}}) {
+			timetable := rest[:len(rest):len(rest)]
+			for _, p := range pick {
+				timetable = append(timetable, p.Activity)
+			}
+			if len(FindConflicts(timetable)) > existing {
+				continue
+			}
+			swap := Swap{SectionID: section.ID, Activities: pick}
+			swap.Days, swap.IdleMinutes = score(timetable)
+			swaps = append(swaps, swap)
+		}
+	}
+
+	sort.SliceStable(swaps, func(i, j int) bool {
+		a, b := swaps[i], swaps[j]
+		if a.Days != b.Days {
+			return a.Days < b.Days
+		}
+		return a.IdleMinutes < b.IdleMinutes
+	})
+	return swaps
+}
@@ -0,0 +1,69 @@
+package planner
+
+import (
+	"testing"
+	"yuplan/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func swapSections(swaps []Swap) []string {
+	var ids []string
+	for _, s := range swaps {
+		ids = append(ids, s.SectionID)
+	}
+	return ids
+}
+
+func TestSwaps_RanksSectionsThatFit(t *testing.T) {
+	rest := []Activity{{CourseCode: "MATH1090", Section: "M", Type: models.ActivityLecture,
+		Meetings: []models.Meeting{meeting("M", "11:30", "80"), meeting("W", "11:30", "80")}}}
+	course := Course{CourseCode: "EECS2030", Sections: []models.Section{
+		{ID: "A", Letter: "A", Activities: []models.SectionActivity{activity(models.ActivityLecture, "A1", meeting("M", "10:00", "80"))}},
+		{ID: "B", Letter: "B", Activities: []models.SectionActivity{activity(models.ActivityLecture, "B1", meeting("T", "10:00", "170"))}},
+		{ID: "C", Letter: "C", Activities: []models.SectionActivity{activity(models.ActivityLecture, "C1", meeting("M", "12:00", "80"))}},
+		{ID: "D", Letter: "D", Activities: []models.SectionActivity{activity(models.ActivityLecture, "D1", meeting("M", "13:00", "80"), meeting("W", "13:00", "80"))}},
+		{ID: "E", Letter: "E", Activities: []models.SectionActivity{activity(models.ActivityLecture, "E1", meeting("M", "14:30", "80"), meeting("W", "14:30", "80"))}},
+	}}
+
+	swaps := Swaps(rest, course, "A")
+
+	assert.Equal(t, []string{"D", "E", "B"}, swapSections(swaps))
+	assert.Equal(t, 2, swaps[0].Days)
+	assert.Equal(t, 20, swaps[0].IdleMinutes)
+	assert.Equal(t, 3, swaps[2].Days)
+	assert.Equal(t, "D1", swaps[0].Activities[0].CatalogNumber)
+}
+
+func TestSwaps_PicksLabs(t *testing.T) {
+	rest := []Activity{{CourseCode: "MATH1090", Section: "M", Type: models.ActivityTutorial,
+		Meetings: []models.Meeting{meeting("F", "14:30", "50")}}}
+	course := Course{CourseCode: "EECS2030", Sections: []models.Section{
+		{ID: "A", Letter: "A", Activities: []models.SectionActivity{activity(models.ActivityLecture, "A1", meeting("F", "10:00", "80"))}},
+		{ID: "B", Letter: "B", Activities: []models.SectionActivity{
+			activity(models.ActivityLecture, "B1", meeting("F", "10:00", "80")),
+			activity(models.ActivityLab, "B-L1", meeting("F", "14:30", "170")),
+			activity(models.ActivityLab, "B-L2", meeting("F", "11:30", "170")),
+		}},
+	}}
+
+	swaps := Swaps(rest, course, "A")
+
+	if assert.Len(t, swaps, 1) {
+		assert.Equal(t, []string{"B1", "B-L2"}, catalogs(Schedule{Activities: swaps[0].Activities}))
+	}
+}
+
+func TestSwaps_IgnoresOverlapsAlreadyInSchedule(t *testing.T) {
+	rest := []Activity{
+		{CourseCode: "MATH1090", Section: "M", Type: models.ActivityLecture, Meetings: []models.Meeting{meeting("M", "10:00", "80")}},
+		{CourseCode: "PHYS1010", Section: "A", Type: models.ActivityLecture, Meetings: []models.Meeting{meeting("M", "10:30", "80")}},
+	}
+	course := Course{CourseCode: "EECS2030", Sections: []models.Section{
+		{ID: "A", Letter: "A", Activities: []models.SectionActivity{activity(models.ActivityLecture, "A1", meeting("T", "10:00", "80"))}},
+		{ID: "B", Letter: "B", Activities: []models.SectionActivity{activity(models.ActivityLecture, "B1", meeting("W", "10:00", "80"))}},
+	}}
+
+	assert.Equal(t, []string{"B"}, swapSections(Swaps(rest, course, "A")))
+	assert.Empty(t, Swaps(rest, Course{CourseCode: "EECS2030", Sections: course.Sections[:1]}, "A"))
+}
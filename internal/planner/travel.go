@@ -0,0 +1,144 @@
+package planner
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"yuplan/internal/models"
+)
+
+// campusTravel is the usual door-to-door time in minutes between campuses by
+// shuttle or transit. Pairs not listed, such as anything off campus, have no
+// estimate and are never flagged.
+var campusTravel = map[[2]string]int{
+	{"Keele", "Glendon"}:                     45,
+	{"Keele", "Markham"}:                     45,
+	{"Keele", "Seneca at York"}:              10,
+	{"Keele", "Toronto Metropolitan Univ"}:   60,
+	{"Glendon", "Markham"}:                   60,
+	{"Glendon", "Seneca at York"}:            50,
+	{"Glendon", "Toronto Metropolitan Univ"}: 40,
+	{"Markham", "Seneca at York"}:            45,
+}
+
+// buildingWalk is the walk in minutes between two buildings on the same campus.
+var buildingWalk = map[string]int{
+	"Keele": 10,
+}
+
+// defaultBuildingWalk covers campuses too small to list.
+const defaultBuildingWalk = 5
+
+// TravelMinutes estimates how long it takes to get from one meeting's room to
+// another's. ok is false when there is no estimate for the two campuses.
+func TravelMinutes(fromCampus, fromRoom, toCampus, toRoom string) (minutes int, ok bool) {
+	if fromCampus == toCampus {
+		if building(fromRoom) == building(toRoom) {
+			return 0, true
+		}
+		if walk, ok := buildingWalk[fromCampus]; ok {
+			return walk, true
+		}
+		return defaultBuildingWalk, true
+	}
+	if minutes, ok := campusTravel[[2]string{fromCampus, toCampus}]; ok {
+		return minutes, true
+	}
+	minutes, ok = campusTravel[[2]string{toCampus, fromCampus}]
+	return minutes, ok
+}
+
+// building is the building code at the start of a room such as "LAS B".
+// Rooms without one count as different buildings from everything.
+func building(room string) string {
+	code, _, _ := strings.Cut(strings.TrimSpace(room), " ")
+	if code == "" {
+		return "?" + room
+	}
+	return code
+}
+
+// TransferPreference is how much slack a student wants when moving between
+// rooms, and whether a schedule without it is acceptable at all.
+type TransferPreference struct {
+	BufferMinutes int  `json:"buffer_minutes"` // added to the travel time between different buildings
+	Reject        bool `json:"reject"`         // drop schedules with tight transfers instead of warning
+}
+
+// Transfer is two consecutive meetings on one day with too little time
+// between them to get from one room to the other.
+type Transfer struct {
+	From        Activity `json:"from"`
+	To          Activity `json:"to"`
+	Day         string   `json:"day"`
+	Gap         int      `json:"gap"`    // minutes from the end of From to the start of To
+	Needed      int      `json:"needed"` // travel time plus the preferred buffer
+	Explanation string   `json:"explanation"`
+}
+
+type placedMeeting struct {
+	activity   Activity
+	start, end int
+	campus     string
+	room       string
+}
+
+// FindTightTransfers returns each pair of back-to-back meetings whose gap is
+// shorter than the travel between them plus pref.BufferMinutes, by day and
+// then time. Overlapping meetings are left to FindConflicts.
+func FindTightTransfers(activities []Activity, pref TransferPreference) []Transfer {
+	byDay := map[string][]placedMeeting{}
+	for _, a := range activities {
+		for _, m := range a.Meetings {
+			start, end, ok := m.Window()
+			if !ok {
+				continue
+			}
+			byDay[m.Day] = append(byDay[m.Day], placedMeeting{activity: a, start: start, end: end, campus: m.Campus, room: m.Room})
+		}
+	}
+
+	var transfers []Transfer
+	for _, day := range models.Weekdays {
+		meetings := byDay[day]
+		sort.SliceStable(meetings, func(i, j int) bool { return meetings[i].start < meetings[j].start })
+		for i := 0; i+1 < len(meetings); i++ {
+			from, to := meetings[i], meetings[i+1]
+			gap := to.start - from.end
+			if gap < 0 {
+				continue
+			}
+			travel, ok := TravelMinutes(from.campus, from.room, to.campus, to.room)
+			if !ok || travel == 0 {
+				continue
+			}
+			needed := travel + pref.BufferMinutes
+			if gap >= needed {
+				continue
+			}
+			t := Transfer{From: from.activity, To: to.activity, Day: day, Gap: gap, Needed: needed}
+			t.Explanation = explainTransfer(t, from, to)
+			transfers = append(transfers, t)
+		}
+	}
+	return transfers
+}
+
+// explainTransfer describes a tight transfer for display, e.g. "EECS2030
+// Section A Lecture ends at 11:20 at Keele and MATH1090 Section M Tutorial
+// starts at 11:30 at Glendon on Tuesdays; allow 45 minutes to get there".
+func explainTransfer(t Transfer, from, to placedMeeting) string {
+	day, ok := dayNames[t.Day]
+	if !ok {
+		day = t.Day
+	}
+	return fmt.Sprintf("%s ends at %s at %s and %s starts at %s at %s on %s; allow %d minutes to get there",
+		describe(t.From), clock(from.end), place(from), describe(t.To), clock(to.start), place(to), day, t.Needed)
+}
+
+func place(m placedMeeting) string {
+	if m.room != "" && m.campus != "" {
+		return m.campus + " " + m.room
+	}
+	return m.campus + m.room
+}
@@ -0,0 +1,85 @@
+package planner
+
+import (
+	"testing"
+	"yuplan/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func room(day, time, duration, campus, room string) models.Meeting {
+	return models.Meeting{Day: day, Time: time, Duration: duration, Campus: campus, Room: room}
+}
+
+func TestTravelMinutes(t *testing.T) {
+	tests := []struct {
+		name                 string
+		fromCampus, fromRoom string
+		toCampus, toRoom     string
+		want                 int
+		ok                   bool
+	}{
+		{"same building", "Keele", "LAS B", "Keele", "LAS C", 0, true},
+		{"across Keele", "Keele", "LAS B", "Keele", "CLH I", 10, true},
+		{"across Glendon", "Glendon", "YH A", "Glendon", "CAT 1", defaultBuildingWalk, true},
+		{"between campuses", "Keele", "LAS B", "Glendon", "YH A", 45, true},
+		{"either direction", "Glendon", "YH A", "Keele", "LAS B", 45, true},
+		{"no estimate", "Keele", "LAS B", "Off Campus", "", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := TravelMinutes(tt.fromCampus, tt.fromRoom, tt.toCampus, tt.toRoom)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFindTightTransfers_ExplainsTransfer(t *testing.T) {
+	lecture := Activity{CourseCode: "EECS2030", Section: "A", Type: models.ActivityLecture,
+		Meetings: []models.Meeting{room("T", "10:00", "80", "Keele", "LAS B")}}
+	tutorial := Activity{CourseCode: "MATH1090", Section: "M", Type: models.ActivityTutorial,
+		Meetings: []models.Meeting{room("T", "11:30", "50", "Glendon", "YH A")}}
+
+	transfers := FindTightTransfers([]Activity{tutorial, lecture}, TransferPreference{})
+
+	assert.Len(t, transfers, 1)
+	assert.Equal(t, "T", transfers[0].Day)
+	assert.Equal(t, 10, transfers[0].Gap)
+	assert.Equal(t, 45, transfers[0].Needed)
+	assert.Equal(t, "EECS2030 Section A Lecture ends at 11:20 at Keele LAS B and MATH1090 Section M Tutorial starts at 11:30 at Glendon YH A on Tuesdays; allow 45 minutes to get there", transfers[0].Explanation)
+}
+
+func TestFindTightTransfers_Buffer(t *testing.T) {
+	a := Activity{CourseCode: "EECS2030", Section: "A", Type: models.ActivityLecture,
+		Meetings: []models.Meeting{room("M", "10:00", "50", "Keele", "LAS B")}}
+	b := Activity{CourseCode: "EECS2021", Section: "B", Type: models.ActivityLecture,
+		Meetings: []models.Meeting{room("M", "11:00", "50", "Keele", "CLH I")}}
+
+	assert.Empty(t, FindTightTransfers([]Activity{a, b}, TransferPreference{}))
+
+	transfers := FindTightTransfers([]Activity{a, b}, TransferPreference{BufferMinutes: 5})
+	assert.Len(t, transfers, 1)
+	assert.Equal(t, 15, transfers[0].Needed)
+}
+
+func TestFindTightTransfers_NotFlagged(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b models.Meeting
+	}{
+		{"same room", room("M", "10:00", "50", "Keele", "LAS B"), room("M", "10:50", "50", "Keele", "LAS B")},
+		{"enough time", room("M", "10:00", "50", "Keele", "LAS B"), room("M", "12:00", "50", "Glendon", "YH A")},
+		{"different days", room("M", "10:00", "50", "Keele", "LAS B"), room("T", "11:00", "50", "Glendon", "YH A")},
+		{"overlapping", room("M", "10:00", "80", "Keele", "LAS B"), room("M", "11:00", "50", "Glendon", "YH A")},
+		{"no estimate", room("M", "10:00", "50", "Keele", "LAS B"), room("M", "11:00", "50", "Off Campus", "")},
+		{"asynchronous", room("", "0:00", "0", "Keele", ""), room("M", "10:00", "50", "Glendon", "YH A")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := Activity{CourseCode: "A", Meetings: []models.Meeting{tt.a}}
+			b := Activity{CourseCode: "B", Meetings: []models.Meeting{tt.b}}
+			assert.Empty(t, FindTightTransfers([]Activity{a, b}, TransferPreference{}))
+		})
+	}
+}
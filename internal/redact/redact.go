@@ -0,0 +1,156 @@
+// Package redact strips fields from API responses that the caller's role may
+// not see. Fields opt in with a tag naming the least role that may see them:
+//
+//	Email string `json:"email,omitempty" redact:"admin"`
+//
+// Apply zeroes every such field the role doesn't reach, so with omitempty or
+// omitzero the field drops out of the JSON entirely.
+package redact
+
+import (
+	"context"
+	"reflect"
+	"sync"
+)
+
+// Role is what the caller is allowed to see. Roles are ordered: each one sees
+// everything the roles before it do.
+type Role string
+
+const (
+	RolePublic Role = "public"
+	// RoleInstructor is held by instructors whose claim on their profile an
+	// admin approved; see package claims.
+	RoleInstructor Role = "instructor"
+	RoleAdmin      Role = "admin"
+)
+
+var rank = map[Role]int{RolePublic: 0, RoleInstructor: 1, RoleAdmin: 2}
+
+// Allows reports whether r may see a field tagged with required. Unknown tags
+// are treated as higher than any role, so a typo hides the field.
+func (r Role) Allows(required Role) bool {
+	need, ok := rank[required]
+	return ok && rank[r] >= need
+}
+
+// Known reports whether r is one of the roles above.
+func (r Role) Known() bool {
+	_, ok := rank[r]
+	return ok
+}
+
+type roleKey struct{}
+
+// WithRole records the caller's role on a request context.
+func WithRole(ctx context.Context, role Role) context.Context {
+	return context.WithValue(ctx, roleKey{}, role)
+}
+
+// RoleFrom returns the caller's role, RolePublic if none was recorded.
+func RoleFrom(ctx context.Context) Role {
+	if role, ok := ctx.Value(roleKey{}).(Role); ok {
+		return role
+	}
+	return RolePublic
+}
+
+// Apply zeroes the fields of v that role may not see, in place. v is walked
+// through pointers, interfaces, slices, arrays, maps and struct fields, so a
+// gin.H holding models works as well as a pointer to a model. A struct passed
+// by value can't be changed and is left as is.
+func Apply(v any, role Role) {
+	if v == nil {
+		return
+	}
+	walk(reflect.ValueOf(v), role)
+}
+
+func walk(v reflect.Value, role Role) {
+	if !hasTags(v.Type()) {
+		return
+	}
+	switch v.Kind() {
+	case reflect.Pointer:
+		if !v.IsNil() {
+			walk(v.Elem(), role)
+		}
+	case reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+		// Values held in an interface can't be changed in place
+		elem := v.Elem()
+		if elem.Kind() == reflect.Pointer || !v.CanSet() {
+			walk(elem, role)
+			return
+		}
+		cp := reflect.New(elem.Type()).Elem()
+		cp.Set(elem)
+		walk(cp, role)
+		v.Set(cp)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			walk(v.Index(i), role)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			cp := reflect.New(iter.Value().Type()).Elem()
+			cp.Set(iter.Value())
+			walk(cp, role)
+			v.SetMapIndex(iter.Key(), cp)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := v.Field(i)
+			if !field.CanSet() {
+				continue
+			}
+			if required, ok := t.Field(i).Tag.Lookup("redact"); ok && !role.Allows(Role(required)) {
+				field.SetZero()
+				continue
+			}
+			walk(field, role)
+		}
+	}
+}
+
+var tagged sync.Map // reflect.Type -> bool
+
+// hasTags reports whether values of t can hold a redact-tagged field, so
+// responses without any are not walked.
+func hasTags(t reflect.Type) bool {
+	if found, ok := tagged.Load(t); ok {
+		return found.(bool)
+	}
+	// Assume yes while computing, which ends recursion through self-referencing
+	// types; a wrong yes only costs a walk
+	tagged.Store(t, true)
+	found := computeHasTags(t)
+	tagged.Store(t, found)
+	return found
+}
+
+func computeHasTags(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Interface:
+		return true // depends on the dynamic value
+	case reflect.Pointer, reflect.Slice, reflect.Array:
+		return hasTags(t.Elem())
+	case reflect.Map:
+		return hasTags(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			if _, ok := f.Tag.Lookup("redact"); ok || hasTags(f.Type) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package redact
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type author struct {
+	Name  string `json:"name"`
+	Email string `json:"email,omitempty" redact:"admin"`
+}
+
+type post struct {
+	Title   string    `json:"title"`
+	Author  author    `json:"author"`
+	Editors []*author `json:"editors"`
+	Secret  string    `json:"secret,omitempty" redact:"owner"` // unknown role
+}
+
+func TestRoleAllows(t *testing.T) {
+	assert.True(t, RolePublic.Allows(RolePublic))
+	assert.False(t, RolePublic.Allows(RoleAdmin))
+	assert.True(t, RoleAdmin.Allows(RolePublic))
+	assert.True(t, RoleAdmin.Allows(RoleAdmin))
+	assert.False(t, RoleAdmin.Allows("owner"))
+	assert.True(t, RoleInstructor.Allows(RolePublic))
+	assert.False(t, RoleInstructor.Allows(RoleAdmin))
+	assert.True(t, RoleAdmin.Allows(RoleInstructor))
+	assert.True(t, RoleAdmin.Known())
+	assert.False(t, Role("owner").Known())
+}
+
+func TestRoleFrom(t *testing.T) {
+	assert.Equal(t, RolePublic, RoleFrom(context.Background()))
+	assert.Equal(t, RoleAdmin, RoleFrom(WithRole(context.Background(), RoleAdmin)))
+}
+
+func newPost() post {
+	return post{
+		Title:   "Notes",
+		Author:  author{Name: "Ada", Email: "ada@yorku.ca"},
+		Editors: []*author{{Name: "Bo", Email: "bo@yorku.ca"}},
+		Secret:  "s",
+	}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		role     Role
+		expected string
+	}{
+		{"public", RolePublic, `{"title":"Notes","author":{"name":"Ada"},"editors":[{"name":"Bo"}]}`},
+		{"admin", RoleAdmin, `{"title":"Notes","author":{"name":"Ada","email":"ada@yorku.ca"},"editors":[{"name":"Bo","email":"bo@yorku.ca"}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newPost()
+			Apply(&p, tt.role)
+
+			b, err := json.Marshal(p)
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.expected, string(b))
+		})
+	}
+}
+
+func TestApply_MapOfValues(t *testing.T) {
+	// The shape handlers respond with: a gin.H holding struct values and slices
+	resp := map[string]any{
+		"data":  newPost(),
+		"list":  []post{newPost()},
+		"count": 1,
+	}
+	Apply(resp, RolePublic)
+
+	b, err := json.Marshal(resp)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(b), "yorku.ca")
+	assert.Contains(t, string(b), `"count":1`)
+	assert.Contains(t, string(b), `"name":"Ada"`)
+}
+
+func TestApply_Nil(t *testing.T) {
+	assert.NotPanics(t, func() {
+		Apply(nil, RolePublic)
+		var p *post
+		Apply(p, RolePublic)
+		Apply(map[string]any{"data": nil}, RolePublic)
+	})
+}
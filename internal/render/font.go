@@ -0,0 +1,106 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"unicode"
+)
+
+const (
+	glyphWidth   = 5
+	glyphHeight  = 7
+	glyphAdvance = glyphWidth + 1 // one blank column between characters
+	lineHeight   = glyphHeight + 3
+)
+
+// glyphs is a 5x7 bitmap font covering what course codes, rooms and times
+// use. Lower case is drawn as upper case; anything else falls back to '?'.
+var glyphs = map[rune][glyphHeight]string{
+	'A':  {".###.", "#...#", "#...#", "#####", "#...#", "#...#", "#...#"},
+	'B':  {"####.", "#...#", "#...#", "####.", "#...#", "#...#", "####."},
+	'C':  {".###.", "#...#", "#....", "#....", "#....", "#...#", ".###."},
+	'D':  {"####.", "#...#", "#...#", "#...#", "#...#", "#...#", "####."},
+	'E':  {"#####", "#....", "#....", "####.", "#....", "#....", "#####"},
+	'F':  {"#####", "#....", "#....", "####.", "#....", "#....", "#...."},
+	'G':  {".###.", "#...#", "#....", "#.###", "#...#", "#...#", ".####"},
+	'H':  {"#...#", "#...#", "#...#", "#####", "#...#", "#...#", "#...#"},
+	'I':  {".###.", "..#..", "..#..", "..#..", "..#..", "..#..", ".###."},
+	'J':  {"..###", "...#.", "...#.", "...#.", "...#.", "#..#.", ".##.."},
+	'K':  {"#...#", "#..#.", "#.#..", "##...", "#.#..", "#..#.", "#...#"},
+	'L':  {"#....", "#....", "#....", "#....", "#....", "#....", "#####"},
+	'M':  {"#...#", "##.##", "#.#.#", "#.#.#", "#...#", "#...#", "#...#"},
+	'N':  {"#...#", "#...#", "##..#", "#.#.#", "#..##", "#...#", "#...#"},
+	'O':  {".###.", "#...#", "#...#", "#...#", "#...#", "#...#", ".###."},
+	'P':  {"####.", "#...#", "#...#", "####.", "#....", "#....", "#...."},
+	'Q':  {".###.", "#...#", "#...#", "#...#", "#.#.#", "#..#.", ".##.#"},
+	'R':  {"####.", "#...#", "#...#", "####.", "#.#..", "#..#.", "#...#"},
+	'S':  {".####", "#....", "#....", ".###.", "....#", "....#", "####."},
+	'T':  {"#####", "..#..", "..#..", "..#..", "..#..", "..#..", "..#.."},
+	'U':  {"#...#", "#...#", "#...#", "#...#", "#...#", "#...#", ".###."},
+	'V':  {"#...#", "#...#", "#...#", "#...#", "#...#", ".#.#.", "..#.."},
+	'W':  {"#...#", "#...#", "#...#", "#.#.#", "#.#.#", "#.#.#", ".#.#."},
+	'X':  {"#...#", "#...#", ".#.#.", "..#..", ".#.#.", "#...#", "#...#"},
+	'Y':  {"#...#", "#...#", ".#.#.", "..#..", "..#..", "..#..", "..#.."},
+	'Z':  {"#####", "....#", "...#.", "..#..", ".#...", "#....", "#####"},
+	'0':  {".###.", "#...#", "#..##", "#.#.#", "##..#", "#...#", ".###."},
+	'1':  {"..#..", ".##..", "..#..", "..#..", "..#..", "..#..", ".###."},
+	'2':  {".###.", "#...#", "....#", "...#.", "..#..", ".#...", "#####"},
+	'3':  {"#####", "...#.", "..#..", "...#.", "....#", "#...#", ".###."},
+	'4':  {"...#.", "..##.", ".#.#.", "#..#.", "#####", "...#.", "...#."},
+	'5':  {"#####", "#....", "####.", "....#", "....#", "#...#", ".###."},
+	'6':  {"..##.", ".#...", "#....", "####.", "#...#", "#...#", ".###."},
+	'7':  {"#####", "....#", "...#.", "..#..", ".#...", ".#...", ".#..."},
+	'8':  {".###.", "#...#", "#...#", ".###.", "#...#", "#...#", ".###."},
+	'9':  {".###.", "#...#", "#...#", ".####", "....#", "...#.", ".##.."},
+	' ':  {".....", ".....", ".....", ".....", ".....", ".....", "....."},
+	':':  {".....", "..#..", "..#..", ".....", "..#..", "..#..", "....."},
+	'-':  {".....", ".....", ".....", "#####", ".....", ".....", "....."},
+	'/':  {".....", "....#", "...#.", "..#..", ".#...", "#....", "....."},
+	'.':  {".....", ".....", ".....", ".....", ".....", ".##..", ".##.."},
+	',':  {".....", ".....", ".....", ".....", ".##..", "..#..", ".#..."},
+	'\'': {"..#..", "..#..", ".#...", ".....", ".....", ".....", "....."},
+	'(':  {"...#.", "..#..", ".#...", ".#...", ".#...", "..#..", "...#."},
+	')':  {".#...", "..#..", "...#.", "...#.", "...#.", "..#..", ".#..."},
+	'&':  {".##..", "#..#.", "#.#..", ".#...", "#.#.#", "#..#.", ".##.#"},
+	'+':  {".....", "..#..", "..#..", "#####", "..#..", "..#..", "....."},
+	'#':  {".#.#.", ".#.#.", "#####", ".#.#.", "#####", ".#.#.", ".#.#."},
+	'?':  {".###.", "#...#", "....#", "...#.", "..#..", ".....", "..#.."},
+}
+
+// textWidth is how many pixels text takes at the given scale.
+func textWidth(text string, scale int) int {
+	n := len([]rune(text))
+	if n == 0 {
+		return 0
+	}
+	return (n*glyphAdvance - 1) * scale
+}
+
+// fit cuts text to the characters that fit in width pixels at the given scale.
+func fit(text string, width, scale int) string {
+	runes := []rune(text)
+	n := max((width/scale+1)/glyphAdvance, 0)
+	if len(runes) > n {
+		runes = runes[:n]
+	}
+	return string(runes)
+}
+
+// drawText draws text with its top-left corner at (x, y), each font pixel
+// as a square of scale by scale image pixels.
+func drawText(img *image.RGBA, x, y int, text string, scale int, c color.RGBA) {
+	for _, r := range text {
+		glyph, ok := glyphs[unicode.ToUpper(r)]
+		if !ok {
+			glyph = glyphs['?']
+		}
+		for row, bits := range glyph {
+			for col, bit := range bits {
+				if bit == '#' {
+					fillRect(img, image.Rect(x+col*scale, y+row*scale, x+(col+1)*scale, y+(row+1)*scale), c)
+				}
+			}
+		}
+		x += glyphAdvance * scale
+	}
+}
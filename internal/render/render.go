@@ -0,0 +1,231 @@
+// Package render draws weekly timetables as images for sharing.
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"yuplan/internal/models"
+)
+
+const (
+	ThemeLight = "light"
+	ThemeDark  = "dark"
+
+	FontSmall  = "small"
+	FontMedium = "medium"
+	FontLarge  = "large"
+)
+
+// Grid geometry in pixels; text is sized separately by Options.FontSize.
+const (
+	padding      = 16
+	gutterWidth  = 72  // hour labels
+	columnWidth  = 180 // one weekday
+	headerHeight = 36
+	titleHeight  = 48
+	hourHeight   = 64
+	blockInset   = 3
+	blockPad     = 6
+
+	// The hours drawn when there is nothing scheduled.
+	defaultFirstHour = 8
+	defaultLastHour  = 18
+)
+
+// Theme is the palette an image is drawn in. Blocks are handed out to
+// courses in the order they first appear, wrapping around.
+type Theme struct {
+	Background color.RGBA
+	Lines      color.RGBA
+	Text       color.RGBA
+	BlockText  color.RGBA
+	Blocks     []color.RGBA
+}
+
+// Options are the caller's choices for one image.
+type Options struct {
+	Title    string // drawn above the grid when set
+	Theme    string // one of the renderer's themes; defaults to ThemeLight
+	FontSize string // FontSmall, FontMedium or FontLarge; defaults to FontMedium
+}
+
+var fontScales = map[string]int{FontSmall: 1, FontMedium: 2, FontLarge: 3}
+
+var dayNames = map[string]string{"M": "Mon", "T": "Tue", "W": "Wed", "R": "Thu", "F": "Fri", "S": "Sat", "U": "Sun"}
+
+// Renderer draws week grids in its themes.
+type Renderer struct {
+	themes map[string]Theme
+}
+
+// NewRenderer returns a renderer with the light and dark themes.
+func NewRenderer() *Renderer {
+	return &Renderer{themes: map[string]Theme{
+		ThemeLight: {
+			Background: color.RGBA{0xff, 0xff, 0xff, 0xff},
+			Lines:      color.RGBA{0xe2, 0xe4, 0xe9, 0xff},
+			Text:       color.RGBA{0x33, 0x37, 0x41, 0xff},
+			BlockText:  color.RGBA{0xff, 0xff, 0xff, 0xff},
+			Blocks: []color.RGBA{
+				{0xe3, 0x1b, 0x23, 0xff}, {0x25, 0x63, 0xeb, 0xff}, {0x05, 0x96, 0x69, 0xff}, {0xd9, 0x77, 0x06, 0xff},
+				{0x7c, 0x3a, 0xed, 0xff}, {0xdb, 0x27, 0x77, 0xff}, {0x08, 0x91, 0xb2, 0xff}, {0x4d, 0x7c, 0x0f, 0xff},
+			},
+		},
+		ThemeDark: {
+			Background: color.RGBA{0x11, 0x14, 0x1b, 0xff},
+			Lines:      color.RGBA{0x2b, 0x30, 0x3b, 0xff},
+			Text:       color.RGBA{0xd1, 0xd5, 0xdb, 0xff},
+			BlockText:  color.RGBA{0x11, 0x14, 0x1b, 0xff},
+			Blocks: []color.RGBA{
+				{0xf8, 0x71, 0x71, 0xff}, {0x60, 0xa5, 0xfa, 0xff}, {0x34, 0xd3, 0x99, 0xff}, {0xfb, 0xbf, 0x24, 0xff},
+				{0xa7, 0x8b, 0xfa, 0xff}, {0xf4, 0x72, 0xb6, 0xff}, {0x22, 0xd3, 0xee, 0xff}, {0xa3, 0xe6, 0x35, 0xff},
+			},
+		},
+	}}
+}
+
+// PNG draws days as a timetable: Monday to Friday, plus a weekend day when
+// something meets on it, spanning the hours that have meetings. Overlapping
+// meetings are drawn over each other, later ones on top.
+func (r *Renderer) PNG(w io.Writer, days []models.ScheduleDay, opts Options) error {
+	theme, ok := r.themes[opts.Theme]
+	if opts.Theme == "" {
+		theme, ok = r.themes[ThemeLight], true
+	}
+	if !ok {
+		return fmt.Errorf("unknown theme %q", opts.Theme)
+	}
+	scale, ok := fontScales[opts.FontSize]
+	if opts.FontSize == "" {
+		scale, ok = fontScales[FontMedium], true
+	}
+	if !ok {
+		return fmt.Errorf("unknown font size %q", opts.FontSize)
+	}
+
+	shown := shownDays(days)
+	first, last := hourRange(shown)
+
+	top := padding
+	if opts.Title != "" {
+		top += titleHeight
+	}
+	gridTop := top + headerHeight
+	width := padding*2 + gutterWidth + columnWidth*len(shown)
+	height := gridTop + (last-first)*hourHeight + padding
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{theme.Background}, image.Point{}, draw.Src)
+
+	textHeight := glyphHeight * scale
+	if opts.Title != "" {
+		title := fit(opts.Title, width-padding*2, scale+1)
+		drawText(img, padding, padding+(titleHeight-glyphHeight*(scale+1))/2, title, scale+1, theme.Text)
+	}
+
+	left := padding + gutterWidth
+	for hour := first; hour <= last; hour++ {
+		y := gridTop + (hour-first)*hourHeight
+		fillRect(img, image.Rect(left, y, width-padding, y+1), theme.Lines)
+		if hour < last {
+			label := fmt.Sprintf("%d:00", hour)
+			drawText(img, left-blockPad-textWidth(label, scale), y+blockInset, label, scale, theme.Text)
+		}
+	}
+	for i, day := range shown {
+		x := left + i*columnWidth
+		fillRect(img, image.Rect(x, top, x+1, height-padding), theme.Lines)
+		name := dayNames[day.Day]
+		drawText(img, x+(columnWidth-textWidth(name, scale))/2, top+(headerHeight-textHeight)/2, name, scale, theme.Text)
+	}
+	fillRect(img, image.Rect(width-padding-1, top, width-padding, height-padding), theme.Lines)
+
+	colors := map[string]color.RGBA{}
+	for i, day := range shown {
+		x := left + i*columnWidth
+		for _, m := range day.Meetings {
+			c, ok := colors[m.CourseCode]
+			if !ok {
+				c = theme.Blocks[len(colors)%len(theme.Blocks)]
+				colors[m.CourseCode] = c
+			}
+			block := image.Rect(
+				x+blockInset, gridTop+(m.Start-first*60)*hourHeight/60,
+				x+columnWidth-blockInset, gridTop+(m.End-first*60)*hourHeight/60,
+			)
+			fillRect(img, block, c)
+			drawBlockText(img, block, blockLines(m), scale, theme.BlockText)
+		}
+	}
+
+	return png.Encode(w, img)
+}
+
+// shownDays keeps Monday to Friday and any weekend day with meetings, in week order.
+func shownDays(days []models.ScheduleDay) []models.ScheduleDay {
+	byDay := map[string]models.ScheduleDay{}
+	for _, day := range days {
+		byDay[day.Day] = day
+	}
+	shown := make([]models.ScheduleDay, 0, len(models.Weekdays))
+	for _, name := range models.Weekdays {
+		day, ok := byDay[name]
+		if !ok {
+			day = models.ScheduleDay{Day: name}
+		}
+		if (name == "S" || name == "U") && len(day.Meetings) == 0 {
+			continue
+		}
+		shown = append(shown, day)
+	}
+	return shown
+}
+
+// hourRange returns the whole hours around every meeting, or the default
+// working day when nothing meets.
+func hourRange(days []models.ScheduleDay) (first, last int) {
+	first, last = 24, 0
+	for _, day := range days {
+		for _, m := range day.Meetings {
+			first = min(first, m.Start/60)
+			last = max(last, (m.End+59)/60)
+		}
+	}
+	if first >= last {
+		return defaultFirstHour, defaultLastHour
+	}
+	return first, min(last, 24)
+}
+
+// blockLines is what a meeting's block says, most important first.
+func blockLines(m models.TeachingMeeting) []string {
+	lines := []string{m.CourseCode, m.Section + " " + m.Type, clock(m.Start) + "-" + clock(m.End)}
+	if m.Room != "" {
+		lines = append(lines, m.Room)
+	}
+	return lines
+}
+
+// drawBlockText writes as many lines as fit inside block, each cut to its width.
+func drawBlockText(img *image.RGBA, block image.Rectangle, lines []string, scale int, c color.RGBA) {
+	y := block.Min.Y + blockPad
+	for _, line := range lines {
+		if y+glyphHeight*scale > block.Max.Y-blockPad {
+			return
+		}
+		drawText(img, block.Min.X+blockPad, y, fit(line, block.Dx()-blockPad*2, scale), scale, c)
+		y += lineHeight * scale
+	}
+}
+
+func clock(minutes int) string {
+	return fmt.Sprintf("%d:%02d", minutes/60, minutes%60)
+}
+
+func fillRect(img *image.RGBA, r image.Rectangle, c color.RGBA) {
+	draw.Draw(img, r, &image.Uniform{c}, image.Point{}, draw.Src)
+}
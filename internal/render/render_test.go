@@ -0,0 +1,72 @@
+package render
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+	"yuplan/internal/models"
+)
+
+func TestPNG(t *testing.T) {
+	days := []models.ScheduleDay{
+		{Day: "M", Meetings: []models.TeachingMeeting{
+			{CourseCode: "EECS2030", Section: "A", Type: models.ActivityLecture, Start: 600, End: 680, Room: "CLH A"},
+		}},
+		{Day: "W", Meetings: []models.TeachingMeeting{
+			{CourseCode: "MATH1090", Section: "M", Type: models.ActivityLecture, Start: 780, End: 870},
+		}},
+		{Day: "S", Meetings: []models.TeachingMeeting{}},
+	}
+
+	var buf bytes.Buffer
+	if err := NewRenderer().PNG(&buf, days, Options{Title: "Fall 2025"}); err != nil {
+		t.Fatalf("PNG: %v", err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("Expected a valid PNG, got %v", err)
+	}
+
+	// Monday to Friday only, 10:00 to 15:00.
+	bounds := img.Bounds()
+	if want := padding*2 + gutterWidth + columnWidth*5; bounds.Dx() != want {
+		t.Errorf("Expected width %d, got %d", want, bounds.Dx())
+	}
+	gridTop := padding + titleHeight + headerHeight
+	if want := gridTop + 5*hourHeight + padding; bounds.Dy() != want {
+		t.Errorf("Expected height %d, got %d", want, bounds.Dy())
+	}
+
+	// The bottom-right corner of Monday's block is in the first course's colour.
+	light := NewRenderer().themes[ThemeLight]
+	x := padding + gutterWidth + columnWidth - blockInset - 1
+	y := gridTop + 80*hourHeight/60 - 1
+	r, g, b, _ := img.At(x, y).RGBA()
+	want := light.Blocks[0]
+	if uint8(r>>8) != want.R || uint8(g>>8) != want.G || uint8(b>>8) != want.B {
+		t.Errorf("Expected the block colour at (%d, %d), got %v", x, y, img.At(x, y))
+	}
+}
+
+func TestPNG_Options(t *testing.T) {
+	r := NewRenderer()
+	var buf bytes.Buffer
+	if err := r.PNG(&buf, nil, Options{Theme: ThemeDark, FontSize: FontLarge}); err != nil {
+		t.Errorf("Expected an empty grid to render, got %v", err)
+	}
+	if err := r.PNG(&buf, nil, Options{Theme: "neon"}); err == nil {
+		t.Error("Expected an unknown theme to be rejected")
+	}
+	if err := r.PNG(&buf, nil, Options{FontSize: "huge"}); err == nil {
+		t.Error("Expected an unknown font size to be rejected")
+	}
+}
+
+func TestFit(t *testing.T) {
+	if got := fit("EECS2030", textWidth("EECS", 2), 2); got != "EECS" {
+		t.Errorf("Expected text cut to the width, got %q", got)
+	}
+	if got := fit("EECS", 0, 1); got != "" {
+		t.Errorf("Expected nothing to fit in no width, got %q", got)
+	}
+}
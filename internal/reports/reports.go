@@ -0,0 +1,121 @@
+// Package reports files and closes user reports, hiding a review that enough
+// users have reported until a moderator gets to it.
+package reports
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"yuplan/internal/config"
+	"yuplan/internal/mailer"
+	"yuplan/internal/models"
+)
+
+// Store keeps the reports and the visibility of reported reviews.
+// Implemented by repository.ReportRepository.
+type Store interface {
+	Create(ctx context.Context, report *models.Report) (bool, error)
+	List(ctx context.Context, status string) ([]models.Report, error)
+	Close(ctx context.Context, id, status string) (*models.Report, error)
+	HideReview(ctx context.Context, reviewID string, threshold int) (bool, error)
+	UnhideReview(ctx context.Context, reviewID string) (bool, error)
+}
+
+// Notifier tells moderators a review was hidden and is waiting for them.
+type Notifier interface {
+	ReviewHidden(ctx context.Context, reviewID string, threshold int) error
+}
+
+type tunablesSource interface {
+	Current() config.Tunables
+}
+
+// Service is a report store that also hides a review once it has
+// REVIEW_REPORT_HIDE_THRESHOLD open reports, and republishes it when a
+// moderator dismisses every one. A report resolved against the review keeps
+// it hidden. It implements repository.ReportRepositoryInterface.
+type Service struct {
+	store    Store
+	tunables tunablesSource
+	notifier Notifier
+}
+
+func NewService(store Store, tunables tunablesSource, notifier Notifier) *Service {
+	return &Service{store: store, tunables: tunables, notifier: notifier}
+}
+
+// Create files a report. Hiding the reported review is best effort: the
+// report is filed either way, and failures are logged.
+func (s *Service) Create(ctx context.Context, report *models.Report) (bool, error) {
+	created, err := s.store.Create(ctx, report)
+	if err != nil || !created || report.EntityType != models.ReportEntityReview {
+		return created, err
+	}
+
+	threshold := s.tunables.Current().ReviewReportHideThreshold
+	if threshold == 0 {
+		return true, nil
+	}
+	hidden, err := s.store.HideReview(ctx, report.EntityID, threshold)
+	if err != nil {
+		log.Printf("hiding reported review %s: %v", report.EntityID, err)
+		return true, nil
+	}
+	if hidden {
+		if err := s.notifier.ReviewHidden(ctx, report.EntityID, threshold); err != nil {
+			log.Printf("notifying moderators of hidden review %s: %v", report.EntityID, err)
+		}
+	}
+	return true, nil
+}
+
+func (s *Service) List(ctx context.Context, status string) ([]models.Report, error) {
+	return s.store.List(ctx, status)
+}
+
+// Close closes a report. Dismissing the last open report on a hidden review
+// republishes it, unless an earlier report on it was resolved.
+func (s *Service) Close(ctx context.Context, id, status string) (*models.Report, error) {
+	report, err := s.store.Close(ctx, id, status)
+	if err != nil || report == nil || report.EntityType != models.ReportEntityReview || status != models.ReportDismissed {
+		return report, err
+	}
+	if _, err := s.store.UnhideReview(ctx, report.EntityID); err != nil {
+		log.Printf("republishing review %s: %v", report.EntityID, err)
+	}
+	return report, nil
+}
+
+// MailNotifier mails the moderators' address when a review is hidden.
+type MailNotifier struct {
+	mailer mailer.Mailer
+	to     string
+}
+
+func NewMailNotifier(m mailer.Mailer, to string) *MailNotifier {
+	return &MailNotifier{mailer: m, to: to}
+}
+
+func (n *MailNotifier) ReviewHidden(ctx context.Context, reviewID string, threshold int) error {
+	body := fmt.Sprintf("Review %s was reported %d times and is hidden until a moderator looks at it.\n\n"+
+		"Its reports are in GET /api/v1/admin/reports. Resolve them to keep the review hidden, "+
+		"or dismiss them to publish it again.\n", reviewID, threshold)
+	err := n.mailer.Send(ctx, mailer.Message{
+		To:      n.to,
+		Subject: "Reported review hidden: " + reviewID,
+		Body:    body,
+	})
+	if err != nil {
+		return fmt.Errorf("send moderator notification: %w", err)
+	}
+	return nil
+}
+
+// LogNotifier logs hidden reviews, for when no mailer is configured.
+// Moderators find them in GET /api/v1/admin/reviews/embargoed.
+type LogNotifier struct{}
+
+func (LogNotifier) ReviewHidden(ctx context.Context, reviewID string, threshold int) error {
+	log.Printf("review %s hidden after %d reports; awaiting moderation", reviewID, threshold)
+	return nil
+}
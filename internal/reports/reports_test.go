@@ -0,0 +1,179 @@
+package reports
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"yuplan/internal/config"
+	"yuplan/internal/mailer"
+	"yuplan/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeStore struct {
+	closed    *models.Report
+	hideErr   error
+	hides     map[string]int // review id -> threshold it was checked against
+	hidden    bool           // what HideReview reports
+	unhidden  []string
+	createErr error
+}
+
+func (f *fakeStore) Create(ctx context.Context, report *models.Report) (bool, error) {
+	if f.createErr != nil {
+		return false, f.createErr
+	}
+	report.ID = "rep-1"
+	return true, nil
+}
+
+func (f *fakeStore) List(ctx context.Context, status string) ([]models.Report, error) {
+	return nil, nil
+}
+
+func (f *fakeStore) Close(ctx context.Context, id, status string) (*models.Report, error) {
+	if f.closed == nil {
+		return nil, nil
+	}
+	f.closed.Status = status
+	return f.closed, nil
+}
+
+func (f *fakeStore) HideReview(ctx context.Context, reviewID string, threshold int) (bool, error) {
+	if f.hides == nil {
+		f.hides = map[string]int{}
+	}
+	f.hides[reviewID] = threshold
+	return f.hidden, f.hideErr
+}
+
+func (f *fakeStore) UnhideReview(ctx context.Context, reviewID string) (bool, error) {
+	f.unhidden = append(f.unhidden, reviewID)
+	return true, nil
+}
+
+type fakeTunables struct {
+	threshold int
+}
+
+func (f fakeTunables) Current() config.Tunables {
+	t := config.DefaultTunables()
+	t.ReviewReportHideThreshold = f.threshold
+	return t
+}
+
+type fakeNotifier struct {
+	hidden []string
+}
+
+func (f *fakeNotifier) ReviewHidden(ctx context.Context, reviewID string, threshold int) error {
+	f.hidden = append(f.hidden, reviewID)
+	return nil
+}
+
+func reviewReport() *models.Report {
+	return &models.Report{Type: models.ReportInappropriateReview, EntityType: models.ReportEntityReview, EntityID: "rev-1"}
+}
+
+func TestCreate_HidesReview(t *testing.T) {
+	store := &fakeStore{hidden: true}
+	notifier := &fakeNotifier{}
+	service := NewService(store, fakeTunables{threshold: 3}, notifier)
+
+	created, err := service.Create(context.Background(), reviewReport())
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, map[string]int{"rev-1": 3}, store.hides)
+	assert.Equal(t, []string{"rev-1"}, notifier.hidden)
+}
+
+func TestCreate_BelowThreshold(t *testing.T) {
+	store := &fakeStore{hidden: false}
+	notifier := &fakeNotifier{}
+
+	_, err := NewService(store, fakeTunables{threshold: 3}, notifier).Create(context.Background(), reviewReport())
+	assert.NoError(t, err)
+	assert.Contains(t, store.hides, "rev-1")
+	assert.Empty(t, notifier.hidden)
+}
+
+func TestCreate_NotHidden(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold int
+		report    *models.Report
+	}{
+		{"disabled", 0, reviewReport()},
+		{"not a review", 3, &models.Report{Type: models.ReportBrokenRMPLink, EntityType: models.ReportEntityInstructor, EntityID: "inst-1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeStore{hidden: true}
+			created, err := NewService(store, fakeTunables{threshold: tt.threshold}, &fakeNotifier{}).Create(context.Background(), tt.report)
+			assert.NoError(t, err)
+			assert.True(t, created)
+			assert.Empty(t, store.hides)
+		})
+	}
+}
+
+func TestCreate_Errors(t *testing.T) {
+	// A failed hide still files the report
+	store := &fakeStore{hideErr: errors.New("db down")}
+	created, err := NewService(store, fakeTunables{threshold: 1}, &fakeNotifier{}).Create(context.Background(), reviewReport())
+	assert.NoError(t, err)
+	assert.True(t, created)
+
+	store = &fakeStore{createErr: errors.New("db down")}
+	_, err = NewService(store, fakeTunables{threshold: 1}, &fakeNotifier{}).Create(context.Background(), reviewReport())
+	assert.Error(t, err)
+	assert.Empty(t, store.hides)
+}
+
+func TestClose_UnhidesOnDismissal(t *testing.T) {
+	tests := []struct {
+		name     string
+		closed   *models.Report
+		status   string
+		unhidden []string
+	}{
+		{"dismissed review report", reviewReport(), models.ReportDismissed, []string{"rev-1"}},
+		{"resolved review report", reviewReport(), models.ReportResolved, nil},
+		{"dismissed course report", &models.Report{EntityType: models.ReportEntityCourse, EntityID: "course-1"}, models.ReportDismissed, nil},
+		{"not open", nil, models.ReportDismissed, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeStore{closed: tt.closed}
+			closed, err := NewService(store, fakeTunables{threshold: 3}, &fakeNotifier{}).Close(context.Background(), "rep-1", tt.status)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.closed, closed)
+			assert.Equal(t, tt.unhidden, store.unhidden)
+		})
+	}
+}
+
+type fakeMailer struct {
+	sent []mailer.Message
+	err  error
+}
+
+func (f *fakeMailer) Send(ctx context.Context, msg mailer.Message) error {
+	f.sent = append(f.sent, msg)
+	return f.err
+}
+
+func TestMailNotifier(t *testing.T) {
+	m := &fakeMailer{}
+	notifier := NewMailNotifier(m, "moderators@yuplan.ca")
+
+	assert.NoError(t, notifier.ReviewHidden(context.Background(), "rev-1", 3))
+	assert.Len(t, m.sent, 1)
+	assert.Equal(t, "moderators@yuplan.ca", m.sent[0].To)
+	assert.Contains(t, m.sent[0].Subject, "rev-1")
+	assert.Contains(t, m.sent[0].Body, "reported 3 times")
+
+	m.err = errors.New("smtp down")
+	assert.Error(t, notifier.ReviewHidden(context.Background(), "rev-1", 3))
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgconn"
+)
+
+type AuditRepositoryInterface interface {
+	Record(ctx context.Context, entity, entityID, action string, details any) error
+}
+
+type auditDB interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+// AuditRepository appends to audit_log the admin actions no trigger sees,
+// such as issuing rate limit exemptions. Review changes are logged by trigger.
+type AuditRepository struct {
+	db auditDB
+}
+
+func NewAuditRepository(db auditDB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// Record appends an entry; details is stored as JSON.
+func (r *AuditRepository) Record(ctx context.Context, entity, entityID, action string, details any) error {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	payload, err := json.Marshal(details)
+	if err != nil {
+		return fmt.Errorf("encode audit details: %w", err)
+	}
+	if _, err := r.db.Exec(ctx,
+		`INSERT INTO audit_log (entity, entity_id, action, details) VALUES ($1, $2, $3, $4)`,
+		entity, entityID, action, payload,
+	); err != nil {
+		return fmt.Errorf("insert audit entry: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditRepository_Record(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewAuditRepository(mock)
+
+	mock.ExpectExec("INSERT INTO audit_log").
+		WithArgs("rate_limit_exemption", "ex-1", "issue", []byte(`{"limit":5000,"name":"k6 nightly"}`)).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	err = repo.Record(context.Background(), "rate_limit_exemption", "ex-1", "issue", map[string]any{"name": "k6 nightly", "limit": 5000})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
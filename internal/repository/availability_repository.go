@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"yuplan/internal/models"
+
+	"github.com/jackc/pgx/v4"
+)
+
+type AvailabilityRepositoryInterface interface {
+	GetBySection(ctx context.Context, sectionID string) (*models.SectionAvailability, error)
+	Apply(ctx context.Context, updates []models.AvailabilityUpdate) ([]models.AvailabilityUpdate, error)
+	PastOfferingSnapshots(ctx context.Context, sectionID string) (bool, []models.EnrollmentSnapshot, error)
+}
+
+type availabilityDB interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+type AvailabilityRepository struct {
+	db availabilityDB
+}
+
+func NewAvailabilityRepository(db availabilityDB) *AvailabilityRepository {
+	return &AvailabilityRepository{db: db}
+}
+
+// GetBySection returns a section's seat counts and its activities', or nil
+// when there's no such section.
+func (r *AvailabilityRepository) GetBySection(ctx context.Context, sectionID string) (*models.SectionAvailability, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	section := models.SectionAvailability{Activities: []models.ActivityAvailability{}}
+	err := r.db.QueryRow(ctx,
+		`SELECT id, letter, COALESCE(term_id, ''), capacity, enrolled, availability_updated_at
+		 FROM sections
+		 WHERE id = $1`,
+		sectionID,
+	).Scan(&section.SectionID, &section.Letter, &section.TermID, &section.Capacity, &section.Enrolled, &section.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query section availability: %w", err)
+	}
+	section.FillRemaining()
+
+	rows, err := r.db.Query(ctx,
+		`SELECT id, course_type, catalog_number, capacity, enrolled, availability_updated_at
+		 FROM section_activities
+		 WHERE section_id = $1
+		 ORDER BY course_type, catalog_number`,
+		sectionID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query activity availability: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var activity models.ActivityAvailability
+		if err := rows.Scan(&activity.ID, &activity.CourseType, &activity.CatalogNumber, &activity.Capacity, &activity.Enrolled, &activity.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan activity availability: %w", err)
+		}
+		activity.FillRemaining()
+		section.Activities = append(section.Activities, activity)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate activity availability: %w", err)
+	}
+	return &section, nil
+}
+
+// Apply stores a batch of seat counts and returns the updates that matched no
+// section or activity. Each section or activity should appear at most once.
+// Section counts that changed are also kept as enrollment snapshots.
+func (r *AvailabilityRepository) Apply(ctx context.Context, updates []models.AvailabilityUpdate) ([]models.AvailabilityUpdate, error) {
+	ctx, cancel := withDeadline(ctx, opAggregate)
+	defer cancel()
+
+	var sections, activities []models.AvailabilityUpdate
+	for _, u := range updates {
+		if u.SectionID != "" {
+			sections = append(sections, u)
+		} else {
+			activities = append(activities, u)
+		}
+	}
+
+	var unmatched []models.AvailabilityUpdate
+	if len(sections) > 0 {
+		ids := make([]string, len(sections))
+		capacity, enrolled := seatCounts(sections)
+		for i, u := range sections {
+			ids[i] = u.SectionID
+		}
+		// previous reads the counts from before the update, since every part
+		// of the statement sees the same snapshot
+		matched, err := r.applied(ctx,
+			`WITH previous AS (
+				SELECT id, capacity, enrolled FROM sections WHERE id = ANY($1::uuid[])
+			), updated AS (
+				UPDATE sections s
+				SET capacity = u.capacity, enrolled = u.enrolled, availability_updated_at = NOW()
+				FROM unnest($1::uuid[], $2::int[], $3::int[]) AS u(id, capacity, enrolled)
+				WHERE s.id = u.id
+				RETURNING s.id, s.capacity, s.enrolled
+			), snapshots AS (
+				INSERT INTO enrollment_snapshots (section_id, capacity, enrolled)
+				SELECT updated.id, updated.capacity, updated.enrolled
+				FROM updated JOIN previous USING (id)
+				WHERE (previous.capacity, previous.enrolled) IS DISTINCT FROM (updated.capacity, updated.enrolled)
+			)
+			SELECT id::text FROM updated`,
+			ids, capacity, enrolled,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("update section availability: %w", err)
+		}
+		for _, u := range sections {
+			if !matched[u.SectionID] {
+				unmatched = append(unmatched, u)
+			}
+		}
+	}
+
+	if len(activities) > 0 {
+		terms := make([]string, len(activities))
+		catalog := make([]string, len(activities))
+		capacity, enrolled := seatCounts(activities)
+		for i, u := range activities {
+			terms[i], catalog[i] = u.Term, u.CatalogNumber
+		}
+		matched, err := r.applied(ctx,
+			`UPDATE section_activities sa
+			 SET capacity = u.capacity, enrolled = u.enrolled, availability_updated_at = NOW()
+			 FROM unnest($1::text[], $2::text[], $3::int[], $4::int[]) AS u(term_id, catalog_number, capacity, enrolled),
+			      sections s
+			 WHERE s.id = sa.section_id AND s.term_id = u.term_id AND sa.catalog_number = u.catalog_number
+			 RETURNING u.term_id || '/' || u.catalog_number`,
+			terms, catalog, capacity, enrolled,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("update activity availability: %w", err)
+		}
+		for _, u := range activities {
+			if !matched[u.Term+"/"+u.CatalogNumber] {
+				unmatched = append(unmatched, u)
+			}
+		}
+	}
+	return unmatched, nil
+}
+
+// PastOfferingSnapshots returns the enrollment snapshots of the sections of
+// the same course in other terms, oldest first within each section, and
+// whether the section exists at all.
+func (r *AvailabilityRepository) PastOfferingSnapshots(ctx context.Context, sectionID string) (bool, []models.EnrollmentSnapshot, error) {
+	ctx, cancel := withDeadline(ctx, opAggregate)
+	defer cancel()
+
+	var found bool
+	if err := r.db.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM sections WHERE id = $1)`, sectionID).Scan(&found); err != nil {
+		return false, nil, fmt.Errorf("query section: %w", err)
+	}
+	if !found {
+		return false, nil, nil
+	}
+
+	rows, err := r.db.Query(ctx,
+		`SELECT es.section_id::text, es.capacity, es.enrolled, es.recorded_at
+		 FROM sections target
+		 JOIN courses tc ON tc.id = target.course_id
+		 JOIN courses pc ON pc.code = tc.code
+		 JOIN sections past ON past.course_id = pc.id AND past.term_id IS DISTINCT FROM target.term_id
+		 JOIN enrollment_snapshots es ON es.section_id = past.id
+		 WHERE target.id = $1
+		 ORDER BY es.section_id, es.recorded_at, es.id`,
+		sectionID,
+	)
+	if err != nil {
+		return false, nil, fmt.Errorf("query enrollment snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	snapshots := []models.EnrollmentSnapshot{}
+	for rows.Next() {
+		var s models.EnrollmentSnapshot
+		if err := rows.Scan(&s.SectionID, &s.Capacity, &s.Enrolled, &s.RecordedAt); err != nil {
+			return false, nil, fmt.Errorf("scan enrollment snapshot: %w", err)
+		}
+		snapshots = append(snapshots, s)
+	}
+	if err := rows.Err(); err != nil {
+		return false, nil, fmt.Errorf("iterate enrollment snapshots: %w", err)
+	}
+	return true, snapshots, nil
+}
+
+// applied runs an update returning one key per updated row and collects the keys.
+func (r *AvailabilityRepository) applied(ctx context.Context, sql string, args ...any) (map[string]bool, error) {
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	matched := map[string]bool{}
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		matched[key] = true
+	}
+	return matched, rows.Err()
+}
+
+func seatCounts(updates []models.AvailabilityUpdate) (capacity, enrolled []int32) {
+	capacity = make([]int32, len(updates))
+	enrolled = make([]int32, len(updates))
+	for i, u := range updates {
+		capacity[i], enrolled[i] = int32(*u.Capacity), int32(*u.Enrolled)
+	}
+	return capacity, enrolled
+}
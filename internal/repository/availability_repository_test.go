@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"yuplan/internal/dbtypes"
+	"yuplan/internal/models"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAvailabilityRepository_GetBySection(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewAvailabilityRepository(mock)
+	now := time.Now()
+	capacity, enrolled := 100, 40
+
+	mock.ExpectQuery("SELECT id, letter, (.+) FROM sections WHERE id = \\$1").
+		WithArgs("sec-1").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "letter", "term_id", "capacity", "enrolled", "availability_updated_at"}).
+			AddRow("sec-1", "A", "FW2025", &capacity, &enrolled, dbtypes.NewNullTime(now)))
+	mock.ExpectQuery("SELECT id, course_type, catalog_number, (.+) FROM section_activities WHERE section_id = \\$1").
+		WithArgs("sec-1").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "course_type", "catalog_number", "capacity", "enrolled", "availability_updated_at"}).
+			AddRow("act-1", "LECT", "K12A01", nil, nil, dbtypes.NullTime{}))
+
+	section, err := repo.GetBySection(context.Background(), "sec-1")
+	assert.NoError(t, err)
+	assert.Equal(t, 60, *section.SeatsRemaining)
+	assert.Equal(t, now, section.UpdatedAt.Time)
+	assert.Len(t, section.Activities, 1)
+	assert.Nil(t, section.Activities[0].SeatsRemaining)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAvailabilityRepository_GetBySection_Missing(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewAvailabilityRepository(mock)
+
+	mock.ExpectQuery("FROM sections").WithArgs("missing").WillReturnError(pgx.ErrNoRows)
+
+	section, err := repo.GetBySection(context.Background(), "missing")
+	assert.NoError(t, err)
+	assert.Nil(t, section)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAvailabilityRepository_Apply(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewAvailabilityRepository(mock)
+	hundred, forty, fifty, ten := 100, 40, 50, 10
+	updates := []models.AvailabilityUpdate{
+		{SectionID: "sec-1", Capacity: &hundred, Enrolled: &forty},
+		{SectionID: "sec-2", Capacity: &hundred, Enrolled: &forty},
+		{Term: "FW2025", CatalogNumber: "K12A01", Capacity: &fifty, Enrolled: &ten},
+	}
+
+	mock.ExpectQuery("UPDATE sections s SET capacity = u.capacity(.+)unnest\\(\\$1::uuid\\[\\](.+)INSERT INTO enrollment_snapshots(.+)IS DISTINCT FROM").
+		WithArgs([]string{"sec-1", "sec-2"}, []int32{100, 100}, []int32{40, 40}).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow("sec-1"))
+	mock.ExpectQuery("UPDATE section_activities sa (.+) s.term_id = u.term_id AND sa.catalog_number = u.catalog_number").
+		WithArgs([]string{"FW2025"}, []string{"K12A01"}, []int32{50}, []int32{10}).
+		WillReturnRows(pgxmock.NewRows([]string{"key"}).AddRow("FW2025/K12A01"))
+
+	unmatched, err := repo.Apply(context.Background(), updates)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.AvailabilityUpdate{updates[1]}, unmatched)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAvailabilityRepository_PastOfferingSnapshots(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewAvailabilityRepository(mock)
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT EXISTS\\(SELECT 1 FROM sections WHERE id = \\$1\\)").
+		WithArgs("sec-1").
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery("JOIN courses pc ON pc.code = tc.code(.+)past.term_id IS DISTINCT FROM target.term_id(.+)WHERE target.id = \\$1").
+		WithArgs("sec-1").
+		WillReturnRows(pgxmock.NewRows([]string{"section_id", "capacity", "enrolled", "recorded_at"}).
+			AddRow("sec-0", 100, 100, now.Add(-time.Hour)).
+			AddRow("sec-0", 100, 97, now))
+	mock.ExpectQuery("SELECT EXISTS").
+		WithArgs("missing").
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(false))
+
+	found, snapshots, err := repo.PastOfferingSnapshots(context.Background(), "sec-1")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Len(t, snapshots, 2)
+	assert.Equal(t, 97, snapshots[1].Enrolled)
+
+	found, snapshots, err = repo.PastOfferingSnapshots(context.Background(), "missing")
+	assert.NoError(t, err)
+	assert.False(t, found)
+	assert.Empty(t, snapshots)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAvailabilityRepository_Apply_Error(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewAvailabilityRepository(mock)
+	one := 1
+
+	mock.ExpectQuery("UPDATE section_activities").WillReturnError(errors.New("db down"))
+
+	_, err = repo.Apply(context.Background(), []models.AvailabilityUpdate{{Term: "FW2025", CatalogNumber: "K12A01", Capacity: &one, Enrolled: &one}})
+	assert.ErrorContains(t, err, "update activity availability")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"yuplan/internal/models"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+type BadgeRepositoryInterface interface {
+	ListBadges(ctx context.Context) ([]models.Badge, error)
+	CreateBadge(ctx context.Context, badge *models.Badge) (bool, error)
+	DeleteBadge(ctx context.Context, slug string) (bool, error)
+	ListReviewerStats(ctx context.Context) ([]models.ReviewerStats, error)
+	ReplaceAwards(ctx context.Context, awards []models.BadgeAward) error
+	BadgesByEmail(ctx context.Context, emails []string) (map[string][]string, error)
+}
+
+type badgeDB interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+type BadgeRepository struct {
+	db badgeDB
+}
+
+func NewBadgeRepository(db badgeDB) *BadgeRepository {
+	return &BadgeRepository{db: db}
+}
+
+// ListBadges returns every badge rule, grouped by metric and lowest threshold first.
+func (r *BadgeRepository) ListBadges(ctx context.Context) ([]models.Badge, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	rows, err := r.db.Query(ctx,
+		`SELECT slug, name, description, metric, threshold, created_at
+		 FROM badges
+		 ORDER BY metric, threshold, slug`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query badges: %w", err)
+	}
+	defer rows.Close()
+
+	badges := []models.Badge{}
+	for rows.Next() {
+		var b models.Badge
+		if err := rows.Scan(&b.Slug, &b.Name, &b.Description, &b.Metric, &b.Threshold, &b.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan badge: %w", err)
+		}
+		badges = append(badges, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate badges: %w", err)
+	}
+	return badges, nil
+}
+
+// CreateBadge adds a badge rule and reports false if the slug is taken.
+func (r *BadgeRepository) CreateBadge(ctx context.Context, badge *models.Badge) (bool, error) {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	err := r.db.QueryRow(ctx,
+		`INSERT INTO badges (slug, name, description, metric, threshold)
+		 VALUES ($1, $2, $3, $4, $5)
+		 ON CONFLICT (slug) DO NOTHING
+		 RETURNING created_at`,
+		badge.Slug, badge.Name, badge.Description, badge.Metric, badge.Threshold,
+	).Scan(&badge.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("create badge: %w", err)
+	}
+	return true, nil
+}
+
+// DeleteBadge removes a badge rule along with every award of it.
+func (r *BadgeRepository) DeleteBadge(ctx context.Context, slug string) (bool, error) {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	tag, err := r.db.Exec(ctx, `DELETE FROM badges WHERE slug = $1`, slug)
+	if err != nil {
+		return false, fmt.Errorf("delete badge: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// ListReviewerStats counts each reviewer's published reviews, overall and in
+// the department they reviewed most, and the helpful votes those reviews got.
+// A department is the letters of a course code.
+func (r *BadgeRepository) ListReviewerStats(ctx context.Context) ([]models.ReviewerStats, error) {
+	ctx, cancel := withDeadline(ctx, opAggregate)
+	defer cancel()
+
+	rows, err := r.db.Query(ctx,
+		`WITH published AS (
+		     SELECT id, email, substring(course_code from '^[A-Z]+') AS department
+		     FROM reviews
+		     WHERE `+publishedFilter+`
+		 ),
+		 per_department AS (
+		     SELECT email, department, COUNT(*) AS reviews
+		     FROM published
+		     GROUP BY email, department
+		 ),
+		 helpful AS (
+		     SELECT p.email, COUNT(*) AS votes
+		     FROM published p
+		     INNER JOIN review_votes v ON v.review_id = p.id AND v.helpful
+		     GROUP BY p.email
+		 )
+		 SELECT d.email, SUM(d.reviews)::int, MAX(d.reviews)::int, COALESCE(MAX(h.votes), 0)::int
+		 FROM per_department d
+		 LEFT JOIN helpful h ON h.email = d.email
+		 GROUP BY d.email
+		 ORDER BY d.email`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query reviewer stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := make([]models.ReviewerStats, 0)
+	for rows.Next() {
+		var s models.ReviewerStats
+		if err := rows.Scan(&s.Email, &s.Reviews, &s.DepartmentReviews, &s.HelpfulVotes); err != nil {
+			return nil, fmt.Errorf("scan reviewer stats: %w", err)
+		}
+		stats = append(stats, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate reviewer stats: %w", err)
+	}
+	return stats, nil
+}
+
+// ReplaceAwards makes reviewer_badges hold exactly the given awards, in one
+// statement. Awards that already exist keep their awarded_at.
+func (r *BadgeRepository) ReplaceAwards(ctx context.Context, awards []models.BadgeAward) error {
+	ctx, cancel := withDeadline(ctx, opAggregate)
+	defer cancel()
+
+	emails := make([]string, 0, len(awards))
+	badges := make([]string, 0, len(awards))
+	for _, a := range awards {
+		emails = append(emails, a.Email)
+		badges = append(badges, a.Badge)
+	}
+
+	_, err := r.db.Exec(ctx,
+		`WITH incoming AS (
+		     SELECT * FROM unnest($1::text[], $2::text[]) AS a(email, badge)
+		 ),
+		 stale AS (
+		     DELETE FROM reviewer_badges rb
+		     WHERE NOT EXISTS (SELECT 1 FROM incoming i WHERE i.email = rb.email AND i.badge = rb.badge)
+		 )
+		 INSERT INTO reviewer_badges (email, badge)
+		 SELECT email, badge FROM incoming
+		 ON CONFLICT (email, badge) DO NOTHING`,
+		emails, badges,
+	)
+	if err != nil {
+		return fmt.Errorf("replace reviewer badges: %w", err)
+	}
+	return nil
+}
+
+// BadgesByEmail returns the badge slugs held by each of the given reviewers,
+// oldest award first. Reviewers without badges are left out.
+func (r *BadgeRepository) BadgesByEmail(ctx context.Context, emails []string) (map[string][]string, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	badges := make(map[string][]string)
+	if len(emails) == 0 {
+		return badges, nil
+	}
+
+	rows, err := r.db.Query(ctx,
+		`SELECT email, badge
+		 FROM reviewer_badges
+		 WHERE email = ANY($1::text[])
+		 ORDER BY email, awarded_at, badge`,
+		emails,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query reviewer badges: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var email, badge string
+		if err := rows.Scan(&email, &badge); err != nil {
+			return nil, fmt.Errorf("scan reviewer badge: %w", err)
+		}
+		badges[email] = append(badges[email], badge)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate reviewer badges: %w", err)
+	}
+	return badges, nil
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"yuplan/internal/models"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBadgeRepository_CreateBadge_SlugTaken(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewBadgeRepository(mock)
+	badge := &models.Badge{Slug: "first-review", Name: "First Review", Metric: models.BadgeMetricReviews, Threshold: 1}
+
+	mock.ExpectQuery("INSERT INTO badges(.+)ON CONFLICT \\(slug\\) DO NOTHING").
+		WithArgs("first-review", "First Review", badge.Description, "reviews", 1).
+		WillReturnError(pgx.ErrNoRows)
+
+	created, err := repo.CreateBadge(context.Background(), badge)
+	assert.NoError(t, err)
+	assert.False(t, created)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBadgeRepository_ListReviewerStats(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewBadgeRepository(mock)
+
+	mock.ExpectQuery("substring\\(course_code from '\\^\\[A-Z\\]\\+'\\)(.+)publish_at IS NULL(.+)GROUP BY email, department(.+)" +
+		"INNER JOIN review_votes v ON v.review_id = p.id AND v.helpful(.+)LEFT JOIN helpful h ON h.email = d.email").
+		WillReturnRows(pgxmock.NewRows([]string{"email", "sum", "max", "coalesce"}).
+			AddRow("a@yorku.ca", 7, 5, 11))
+
+	stats, err := repo.ListReviewerStats(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []models.ReviewerStats{{Email: "a@yorku.ca", Reviews: 7, DepartmentReviews: 5, HelpfulVotes: 11}}, stats)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBadgeRepository_ReplaceAwards(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewBadgeRepository(mock)
+
+	mock.ExpectExec("DELETE FROM reviewer_badges(.+)INSERT INTO reviewer_badges").
+		WithArgs([]string{"a@yorku.ca", "a@yorku.ca"}, []string{"first-review", "department-expert"}).
+		WillReturnResult(pgxmock.NewResult("INSERT", 2))
+
+	err = repo.ReplaceAwards(context.Background(), []models.BadgeAward{
+		{Email: "a@yorku.ca", Badge: "first-review"},
+		{Email: "a@yorku.ca", Badge: "department-expert"},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBadgeRepository_BadgesByEmail(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewBadgeRepository(mock)
+
+	mock.ExpectQuery("FROM reviewer_badges\\s+WHERE email = ANY").
+		WithArgs([]string{"a@yorku.ca", "b@yorku.ca"}).
+		WillReturnRows(pgxmock.NewRows([]string{"email", "badge"}).
+			AddRow("a@yorku.ca", "first-review").
+			AddRow("a@yorku.ca", "department-expert"))
+
+	badges, err := repo.BadgesByEmail(context.Background(), []string{"a@yorku.ca", "b@yorku.ca"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{"a@yorku.ca": {"first-review", "department-expert"}}, badges)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"yuplan/internal/models"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// ErrForeignBlockActivity is returned by Set when an activity isn't one of the
+// course's own.
+var ErrForeignBlockActivity = errors.New("every activity must belong to one of the course's sections")
+
+type BlockRepositoryInterface interface {
+	GetByCourseID(ctx context.Context, courseID string) ([]models.Block, error)
+	Set(ctx context.Context, courseID, name string, activityIDs []string) (*models.Block, error)
+	Delete(ctx context.Context, courseID, name string) (bool, error)
+}
+
+type blockDB interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+type BlockRepository struct {
+	db blockDB
+}
+
+func NewBlockRepository(db blockDB) *BlockRepository {
+	return &BlockRepository{db: db}
+}
+
+// GetByCourseID returns every block for a course offering with its activities, in one query.
+func (r *BlockRepository) GetByCourseID(ctx context.Context, courseID string) ([]models.Block, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	rows, err := r.db.Query(
+		ctx,
+		`SELECT b.id, b.course_id, b.name, b.created_at, b.updated_at,
+		        sa.id, sa.course_type, sa.section_id, sa.catalog_number, sa.times, sa.created_at, sa.updated_at
+		 FROM blocks b
+		 INNER JOIN block_activities ba ON ba.block_id = b.id
+		 INNER JOIN section_activities sa ON sa.id = ba.activity_id
+		 WHERE b.course_id = $1
+		 ORDER BY b.name, sa.course_type, sa.catalog_number`,
+		courseID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query blocks by course_id: %w", err)
+	}
+	defer rows.Close()
+
+	blocks := make([]models.Block, 0)
+	for rows.Next() {
+		var b models.Block
+		var a models.SectionActivity
+		if err := rows.Scan(&b.ID, &b.CourseID, &b.Name, &b.CreatedAt, &b.UpdatedAt,
+			&a.ID, &a.CourseType, &a.SectionID, &a.CatalogNumber, &a.Times, &a.CreatedAt, &a.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan block: %w", err)
+		}
+		a.Delivery = models.DeliveryOf(a.Times)
+
+		// Rows are ordered by block, so activities for the same block are adjacent
+		if n := len(blocks); n > 0 && blocks[n-1].ID == b.ID {
+			blocks[n-1].Activities = append(blocks[n-1].Activities, a)
+			continue
+		}
+		b.Activities = []models.SectionActivity{a}
+		blocks = append(blocks, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate blocks: %w", err)
+	}
+
+	return blocks, nil
+}
+
+// Set creates the course's block called name, or replaces the activities of
+// the one it has, in one statement written to the audit log, and returns it.
+// It returns nil if the course doesn't exist, and ErrForeignBlockActivity,
+// changing nothing, unless every activity is in one of the course's sections.
+func (r *BlockRepository) Set(ctx context.Context, courseID, name string, activityIDs []string) (*models.Block, error) {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	var courseExists bool
+	var blockID *string
+	err := r.db.QueryRow(ctx,
+		`WITH course AS (
+		     SELECT id FROM courses WHERE id = $1
+		 ),
+		 wanted AS (
+		     SELECT sa.id FROM section_activities sa
+		     INNER JOIN sections s ON s.id = sa.section_id
+		     WHERE sa.id = ANY($3::uuid[]) AND s.course_id = $1
+		 ),
+		 block AS (
+		     INSERT INTO blocks (course_id, name)
+		     SELECT id, $2 FROM course
+		     WHERE (SELECT COUNT(*) FROM wanted) = (SELECT COUNT(DISTINCT a) FROM unnest($3::uuid[]) a)
+		     ON CONFLICT (course_id, name) DO UPDATE SET updated_at = NOW()
+		     RETURNING id, course_id, name
+		 ),
+		 removed AS (
+		     DELETE FROM block_activities ba USING block
+		     WHERE ba.block_id = block.id AND ba.activity_id <> ALL($3::uuid[])
+		 ),
+		 added AS (
+		     INSERT INTO block_activities (block_id, activity_id)
+		     SELECT block.id, wanted.id FROM block, wanted
+		     ON CONFLICT DO NOTHING
+		 ),
+		 audit AS (
+		     INSERT INTO audit_log (entity, entity_id, action, details)
+		     SELECT 'block', id::text, 'set', jsonb_build_object('course_id', course_id, 'name', name, 'activities', $3::uuid[])
+		     FROM block
+		 )
+		 SELECT EXISTS (SELECT 1 FROM course), (SELECT id::text FROM block)`,
+		courseID, name, activityIDs,
+	).Scan(&courseExists, &blockID)
+	if err != nil {
+		return nil, fmt.Errorf("set block %q of course %s: %w", name, courseID, err)
+	}
+	if !courseExists {
+		return nil, nil
+	}
+	if blockID == nil {
+		return nil, ErrForeignBlockActivity
+	}
+
+	// The statement can't read back the activities it wrote
+	blocks, err := r.GetByCourseID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range blocks {
+		if b.ID == *blockID {
+			return &b, nil
+		}
+	}
+	return nil, fmt.Errorf("block %s vanished after it was set", *blockID)
+}
+
+// Delete removes the course's block called name and writes it to the audit
+// log. Its activities stay. It reports false if there is no such block.
+func (r *BlockRepository) Delete(ctx context.Context, courseID, name string) (bool, error) {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	var deleted bool
+	err := r.db.QueryRow(ctx,
+		`WITH deleted AS (
+		     DELETE FROM blocks WHERE course_id = $1 AND name = $2 RETURNING id, course_id, name
+		 ),
+		 audit AS (
+		     INSERT INTO audit_log (entity, entity_id, action, details)
+		     SELECT 'block', id::text, 'delete', jsonb_build_object('course_id', course_id, 'name', name)
+		     FROM deleted
+		 )
+		 SELECT EXISTS (SELECT 1 FROM deleted)`,
+		courseID, name,
+	).Scan(&deleted)
+	if err != nil {
+		return false, fmt.Errorf("delete block %q of course %s: %w", name, courseID, err)
+	}
+	return deleted, nil
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var blockColumns = []string{
+	"id", "course_id", "name", "created_at", "updated_at",
+	"id", "course_type", "section_id", "catalog_number", "times", "created_at", "updated_at",
+}
+
+func TestGetBlocksByCourseID(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewBlockRepository(mock)
+	now := time.Now()
+	labTimes := `[{"day": "T", "time": "10:00", "duration": "80", "campus": "Keele", "room": "LAS 1006"}]`
+
+	mock.ExpectQuery("FROM blocks b\\s+INNER JOIN block_activities ba ON ba.block_id = b.id").
+		WithArgs("course-1").
+		WillReturnRows(pgxmock.NewRows(blockColumns).
+			AddRow("block-1", "course-1", "Block 1", now, now, "act-1", "LECT", "section-1", "", nil, now, now).
+			AddRow("block-1", "course-1", "Block 1", now, now, "act-2", "LAB", "section-1", "L01", &labTimes, now, now).
+			AddRow("block-2", "course-1", "Block 2", now, now, "act-3", "LECT", "section-2", "", nil, now, now))
+
+	blocks, err := repo.GetByCourseID(context.Background(), "course-1")
+
+	assert.NoError(t, err)
+	assert.Len(t, blocks, 2)
+	assert.Equal(t, "block-1", blocks[0].ID)
+	assert.Len(t, blocks[0].Activities, 2)
+	assert.Equal(t, "act-2", blocks[0].Activities[1].ID)
+	assert.Equal(t, models.DeliveryAsynchronous, blocks[0].Activities[0].Delivery)
+	assert.Equal(t, models.DeliveryScheduled, blocks[0].Activities[1].Delivery)
+	assert.Equal(t, "block-2", blocks[1].ID)
+	assert.Len(t, blocks[1].Activities, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetBlocksByCourseID_EmptyResult(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewBlockRepository(mock)
+
+	mock.ExpectQuery("FROM blocks b").
+		WithArgs("course-1").
+		WillReturnRows(pgxmock.NewRows(blockColumns))
+
+	blocks, err := repo.GetByCourseID(context.Background(), "course-1")
+
+	assert.NoError(t, err)
+	assert.NotNil(t, blocks)
+	assert.Empty(t, blocks)
+}
+
+func TestGetBlocksByCourseID_WhenQueryErrors_ReturnsError(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewBlockRepository(mock)
+
+	mock.ExpectQuery("FROM blocks b").
+		WithArgs("course-1").
+		WillReturnError(errors.New("db down"))
+
+	blocks, err := repo.GetByCourseID(context.Background(), "course-1")
+
+	assert.Error(t, err)
+	assert.Nil(t, blocks)
+	assert.Contains(t, err.Error(), "query blocks by course_id")
+}
+
+func TestBlockRepository_Set(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewBlockRepository(mock)
+	now := time.Now()
+	ids := []string{"act-1", "act-2"}
+	blockID := "block-1"
+
+	mock.ExpectQuery("INSERT INTO blocks \\(course_id, name\\)(.+)ON CONFLICT \\(course_id, name\\) DO UPDATE(.+)"+
+		"DELETE FROM block_activities(.+)INSERT INTO block_activities(.+)INSERT INTO audit_log").
+		WithArgs("course-1", "Block 1", ids).
+		WillReturnRows(pgxmock.NewRows([]string{"exists", "id"}).AddRow(true, &blockID))
+	mock.ExpectQuery("FROM blocks b").
+		WithArgs("course-1").
+		WillReturnRows(pgxmock.NewRows(blockColumns).
+			AddRow("block-0", "course-1", "Block 0", now, now, "act-3", "LECT", "section-2", "", nil, now, now).
+			AddRow("block-1", "course-1", "Block 1", now, now, "act-1", "LECT", "section-1", "", nil, now, now).
+			AddRow("block-1", "course-1", "Block 1", now, now, "act-2", "LAB", "section-1", "L01", nil, now, now))
+
+	block, err := repo.Set(context.Background(), "course-1", "Block 1", ids)
+
+	assert.NoError(t, err)
+	if assert.NotNil(t, block) {
+		assert.Equal(t, "block-1", block.ID)
+		assert.Len(t, block.Activities, 2)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBlockRepository_SetRejectsForeignActivitiesAndMissingCourses(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewBlockRepository(mock)
+
+	mock.ExpectQuery("INSERT INTO blocks").
+		WithArgs("course-1", "Block 1", []string{"other-course-act"}).
+		WillReturnRows(pgxmock.NewRows([]string{"exists", "id"}).AddRow(true, (*string)(nil)))
+	mock.ExpectQuery("INSERT INTO blocks").
+		WithArgs("missing", "Block 1", []string{"act-1"}).
+		WillReturnRows(pgxmock.NewRows([]string{"exists", "id"}).AddRow(false, (*string)(nil)))
+
+	block, err := repo.Set(context.Background(), "course-1", "Block 1", []string{"other-course-act"})
+	assert.ErrorIs(t, err, ErrForeignBlockActivity)
+	assert.Nil(t, block)
+
+	block, err = repo.Set(context.Background(), "missing", "Block 1", []string{"act-1"})
+	assert.NoError(t, err)
+	assert.Nil(t, block)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBlockRepository_Delete(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewBlockRepository(mock)
+
+	mock.ExpectQuery("DELETE FROM blocks WHERE course_id = \\$1 AND name = \\$2(.+)INSERT INTO audit_log").
+		WithArgs("course-1", "Block 1").
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(true))
+
+	deleted, err := repo.Delete(context.Background(), "course-1", "Block 1")
+
+	assert.NoError(t, err)
+	assert.True(t, deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
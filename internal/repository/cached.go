@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"yuplan/internal/cache"
+	"yuplan/internal/models"
+)
+
+// Cache namespaces of the cached repositories, for cache.Store.Invalidate.
+const (
+	CacheCourses     = "courses"
+	CacheSections    = "sections"
+	CacheInstructors = "instructors"
+	CacheDepartments = "departments"
+)
+
+// CatalogCaches are the namespaces holding seeded catalog data, which change
+// together when the catalog is re-seeded.
+var CatalogCaches = []string{CacheCourses, CacheSections, CacheInstructors}
+
+// Keys of the cached catalog reads, shared with CatalogCacheInvalidator.
+func courseIDKey(courseID string) string             { return "id:" + courseID }
+func courseCodeKey(code string) string               { return "code:" + models.NormalizeCourseCode(code) }
+func sectionsKey(courseID string) string             { return courseID }
+func termSectionsKey(courseID, termID string) string { return courseID + ":" + termID }
+func courseInstructorsKey(courseID string) string    { return "course:" + courseID }
+
+// CatalogCacheInvalidator drops the cached reads of the courses a catalog
+// load changed, rather than the whole catalog cache.
+type CatalogCacheInvalidator struct {
+	cache *cache.Store
+}
+
+func NewCatalogCacheInvalidator(store *cache.Store) *CatalogCacheInvalidator {
+	return &CatalogCacheInvalidator{cache: store}
+}
+
+// Invalidate drops each changed course's lookups, sections and instructors.
+// Instructor profiles and course lists gather sections across courses by
+// name, so a change that reaches them drops the instructors namespace whole.
+// It stops at the first failure; the caller then falls back to dropping
+// everything.
+func (i *CatalogCacheInvalidator) Invalidate(ctx context.Context, changes []models.CourseChange) error {
+	var courses, sections, instructors []string
+	instructorsTouched := false
+	for _, c := range changes {
+		if !c.Changed() {
+			continue
+		}
+		courses = append(courses, courseIDKey(c.CourseID), courseCodeKey(c.Code))
+		sections = append(sections, sectionsKey(c.CourseID), termSectionsKey(c.CourseID, c.TermID))
+		instructors = append(instructors, courseInstructorsKey(c.CourseID))
+		instructorsTouched = instructorsTouched || c.TouchesInstructors()
+	}
+
+	if err := i.cache.Delete(ctx, CacheCourses, courses...); err != nil {
+		return err
+	}
+	if err := i.cache.Delete(ctx, CacheSections, sections...); err != nil {
+		return err
+	}
+	if instructorsTouched {
+		return i.cache.Invalidate(ctx, CacheInstructors)
+	}
+	return i.cache.Delete(ctx, CacheInstructors, instructors...)
+}
+
+// CachedCourseRepository caches course lookups by id and code. Searches and
+// listings vary too much to be worth caching and go straight to the database.
+type CachedCourseRepository struct {
+	CourseRepositoryInterface
+	cache *cache.Store
+}
+
+func NewCachedCourseRepository(repo CourseRepositoryInterface, store *cache.Store) *CachedCourseRepository {
+	return &CachedCourseRepository{CourseRepositoryInterface: repo, cache: store}
+}
+
+func (r *CachedCourseRepository) GetByID(ctx context.Context, courseID string) (*models.Course, error) {
+	return cache.Fetch(ctx, r.cache, CacheCourses, courseIDKey(courseID), func() (*models.Course, error) {
+		return r.CourseRepositoryInterface.GetByID(ctx, courseID)
+	})
+}
+
+func (r *CachedCourseRepository) GetByCode(ctx context.Context, courseCode string) ([]models.Course, error) {
+	return cache.Fetch(ctx, r.cache, CacheCourses, courseCodeKey(courseCode), func() ([]models.Course, error) {
+		return r.CourseRepositoryInterface.GetByCode(ctx, courseCode)
+	})
+}
+
+// CachedSectionRepository caches a course's sections with their activities.
+type CachedSectionRepository struct {
+	SectionRepositoryInterface
+	cache *cache.Store
+}
+
+func NewCachedSectionRepository(repo SectionRepositoryInterface, store *cache.Store) *CachedSectionRepository {
+	return &CachedSectionRepository{SectionRepositoryInterface: repo, cache: store}
+}
+
+func (r *CachedSectionRepository) GetByCourseID(ctx context.Context, courseID string) ([]models.Section, error) {
+	return cache.Fetch(ctx, r.cache, CacheSections, sectionsKey(courseID), func() ([]models.Section, error) {
+		return r.SectionRepositoryInterface.GetByCourseID(ctx, courseID)
+	})
+}
+
+func (r *CachedSectionRepository) GetByCourseIDInTerm(ctx context.Context, courseID, termID string) ([]models.Section, error) {
+	return cache.Fetch(ctx, r.cache, CacheSections, termSectionsKey(courseID, termID), func() ([]models.Section, error) {
+		return r.SectionRepositoryInterface.GetByCourseIDInTerm(ctx, courseID, termID)
+	})
+}
+
+// CachedInstructorRepository caches instructor lookups. Teaching schedules are
+// filtered by term per request and are not cached.
+type CachedInstructorRepository struct {
+	InstructorRepositoryInterface
+	cache *cache.Store
+}
+
+func NewCachedInstructorRepository(repo InstructorRepositoryInterface, store *cache.Store) *CachedInstructorRepository {
+	return &CachedInstructorRepository{InstructorRepositoryInterface: repo, cache: store}
+}
+
+func (r *CachedInstructorRepository) GetByCourseID(ctx context.Context, courseID string) ([]models.Instructor, error) {
+	return cache.Fetch(ctx, r.cache, CacheInstructors, courseInstructorsKey(courseID), func() ([]models.Instructor, error) {
+		return r.InstructorRepositoryInterface.GetByCourseID(ctx, courseID)
+	})
+}
+
+func (r *CachedInstructorRepository) GetByID(ctx context.Context, id string) (*models.Instructor, error) {
+	return cache.Fetch(ctx, r.cache, CacheInstructors, "id:"+id, func() (*models.Instructor, error) {
+		return r.InstructorRepositoryInterface.GetByID(ctx, id)
+	})
+}
+
+func (r *CachedInstructorRepository) GetProfile(ctx context.Context, id string) (*models.InstructorProfile, error) {
+	return cache.Fetch(ctx, r.cache, CacheInstructors, "profile:"+id, func() (*models.InstructorProfile, error) {
+		return r.InstructorRepositoryInterface.GetProfile(ctx, id)
+	})
+}
+
+func (r *CachedInstructorRepository) ListCourses(ctx context.Context, id string) ([]models.InstructorCourse, error) {
+	return cache.Fetch(ctx, r.cache, CacheInstructors, "courses:"+id, func() ([]models.InstructorCourse, error) {
+		return r.InstructorRepositoryInterface.ListCourses(ctx, id)
+	})
+}
+
+// CachedDepartmentRepository caches department stats rollups, which read
+// every review in the department. since moves with the clock, so it is left
+// out of the key; a cached rollup is at most the cache TTL out of date.
+type CachedDepartmentRepository struct {
+	DepartmentRepositoryInterface
+	cache *cache.Store
+}
+
+func NewCachedDepartmentRepository(repo DepartmentRepositoryInterface, store *cache.Store) *CachedDepartmentRepository {
+	return &CachedDepartmentRepository{DepartmentRepositoryInterface: repo, cache: store}
+}
+
+func (r *CachedDepartmentRepository) GetStats(ctx context.Context, department string, since time.Time) (*models.DepartmentStats, error) {
+	return cache.Fetch(ctx, r.cache, CacheDepartments, "stats:"+department, func() (*models.DepartmentStats, error) {
+		return r.DepartmentRepositoryInterface.GetStats(ctx, department, since)
+	})
+}
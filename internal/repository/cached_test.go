@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"yuplan/internal/cache"
+	"yuplan/internal/models"
+
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCachedCourseRepository_GetByCode(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	store := cache.NewStore(cache.NewMemory(100), time.Minute)
+	repo := NewCachedCourseRepository(NewCourseRepository(mock), store)
+	now := time.Now()
+
+	// Only the first lookup reaches the database, whatever the code's spelling
+	mock.ExpectQuery(courseByCodeQueryPattern).
+		WithArgs("eecs2030").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "name", "code", "credits", "description", "faculty", "term", "created_at", "updated_at"}).
+			AddRow("id-fall", "Test Course", "EECS2030", 3.0, nil, "SC", "F", now, now))
+
+	for _, code := range []string{"EECS 2030", "eecs2030"} {
+		courses, err := repo.GetByCode(context.Background(), code)
+		assert.NoError(t, err)
+		assert.Len(t, courses, 1)
+		assert.Equal(t, "id-fall", courses[0].ID)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// After invalidation the next lookup reloads
+	assert.NoError(t, store.Invalidate(context.Background(), CacheCourses))
+	mock.ExpectQuery(courseByCodeQueryPattern).
+		WithArgs("eecs2030").
+		WillReturnError(errors.New("db down"))
+	_, err = repo.GetByCode(context.Background(), "EECS2030")
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCachedSectionRepository_GetByCourseID(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewCachedSectionRepository(NewSectionRepository(mock, &mockActivityRepo{}), cache.NewStore(cache.NewMemory(100), time.Minute))
+	now := time.Now()
+
+	mock.ExpectQuery("FROM sections").
+		WithArgs("course-1").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "course_id", "letter", "created_at", "updated_at"}).
+			AddRow("section-1", "course-1", "A", now, now))
+
+	for i := 0; i < 2; i++ {
+		sections, err := repo.GetByCourseID(context.Background(), "course-1")
+		assert.NoError(t, err)
+		assert.Len(t, sections, 1)
+		assert.Equal(t, "A", sections[0].Letter)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCatalogCacheInvalidator(t *testing.T) {
+	ctx := context.Background()
+	store := cache.NewStore(cache.NewMemory(100), time.Minute)
+	loads := map[string]int{}
+	fetch := func(namespace, key string) {
+		cache.Fetch(ctx, store, namespace, key, func() (int, error) {
+			loads[namespace+" "+key]++
+			return 1, nil
+		})
+	}
+	fill := func() {
+		fetch(CacheCourses, courseIDKey("c1"))
+		fetch(CacheCourses, courseCodeKey("EECS 2030"))
+		fetch(CacheCourses, courseIDKey("c2"))
+		fetch(CacheSections, sectionsKey("c1"))
+		fetch(CacheSections, termSectionsKey("c1", "FW2025"))
+		fetch(CacheSections, sectionsKey("c2"))
+		fetch(CacheInstructors, courseInstructorsKey("c1"))
+		fetch(CacheInstructors, "profile:i1")
+	}
+	fill()
+
+	// New activity times only: c1's entries go, instructor profiles stay
+	invalidator := NewCatalogCacheInvalidator(store)
+	err := invalidator.Invalidate(ctx, []models.CourseChange{
+		{CourseID: "c1", Code: "EECS2030", TermID: "FW2025", Diff: models.CatalogDiff{Activities: models.RowDiff{Changed: 1}}},
+		{CourseID: "c2", Code: "EECS3101", TermID: "FW2025"},
+	})
+	assert.NoError(t, err)
+	fill()
+	for key, want := range map[string]int{
+		"courses id:c1": 2, "courses code:EECS2030": 2, "sections c1": 2, "sections c1:FW2025": 2, "instructors course:c1": 2,
+		"courses id:c2": 1, "sections c2": 1, "instructors profile:i1": 1,
+	} {
+		assert.Equal(t, want, loads[key], key)
+	}
+
+	// A new section reaches instructor profiles, so the namespace goes
+	err = invalidator.Invalidate(ctx, []models.CourseChange{
+		{CourseID: "c2", Code: "EECS3101", TermID: "FW2025", Diff: models.CatalogDiff{Sections: models.RowDiff{Added: 1}}},
+	})
+	assert.NoError(t, err)
+	fill()
+	assert.Equal(t, 2, loads["courses id:c2"])
+	assert.Equal(t, 2, loads["instructors profile:i1"])
+	assert.Equal(t, 2, loads["courses id:c1"], "unchanged courses stay cached")
+}
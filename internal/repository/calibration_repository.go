@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+type CalibrationRepositoryInterface interface {
+	ListCourseDifficulties(ctx context.Context, since time.Time) ([]models.CourseDifficulty, error)
+	ReplaceCalibration(ctx context.Context, departments []models.DepartmentDifficulty) error
+	GetDepartment(ctx context.Context, department string) (*models.DepartmentDifficulty, error)
+}
+
+type calibrationDB interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+type CalibrationRepository struct {
+	db calibrationDB
+}
+
+func NewCalibrationRepository(db calibrationDB) *CalibrationRepository {
+	return &CalibrationRepository{db: db}
+}
+
+// ListCourseDifficulties returns each course's average difficulty over its
+// published reviews created since the given time, ordered by course code.
+func (r *CalibrationRepository) ListCourseDifficulties(ctx context.Context, since time.Time) ([]models.CourseDifficulty, error) {
+	ctx, cancel := withDeadline(ctx, opAggregate)
+	defer cancel()
+
+	rows, err := r.db.Query(ctx,
+		`SELECT course_code, AVG(difficulty)::float8, COUNT(*)::int
+		 FROM reviews
+		 WHERE created_at >= $1 AND `+publishedFilter+`
+		 GROUP BY course_code
+		 ORDER BY course_code`,
+		since,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query course difficulties: %w", err)
+	}
+	defer rows.Close()
+
+	courses := make([]models.CourseDifficulty, 0)
+	for rows.Next() {
+		var c models.CourseDifficulty
+		if err := rows.Scan(&c.CourseCode, &c.AvgDifficulty, &c.Reviews); err != nil {
+			return nil, fmt.Errorf("scan course difficulty: %w", err)
+		}
+		courses = append(courses, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate course difficulties: %w", err)
+	}
+	return courses, nil
+}
+
+// ReplaceCalibration makes difficulty_calibration hold exactly the given departments, in one statement.
+func (r *CalibrationRepository) ReplaceCalibration(ctx context.Context, departments []models.DepartmentDifficulty) error {
+	ctx, cancel := withDeadline(ctx, opAggregate)
+	defer cancel()
+
+	names := make([]string, len(departments))
+	means := make([]float64, len(departments))
+	stddevs := make([]float64, len(departments))
+	courses := make([]int32, len(departments))
+	for i, d := range departments {
+		names[i] = d.Department
+		means[i] = d.Mean
+		stddevs[i] = d.StdDev
+		courses[i] = int32(d.Courses)
+	}
+
+	_, err := r.db.Exec(ctx,
+		`WITH incoming AS (
+		     SELECT * FROM unnest($1::text[], $2::float8[], $3::float8[], $4::int[]) AS d(department, mean, stddev, courses)
+		 ),
+		 stale AS (
+		     DELETE FROM difficulty_calibration c
+		     WHERE NOT EXISTS (SELECT 1 FROM incoming i WHERE i.department = c.department)
+		 )
+		 INSERT INTO difficulty_calibration (department, mean, stddev, courses, updated_at)
+		 SELECT department, mean, stddev, courses, NOW() FROM incoming
+		 ON CONFLICT (department) DO UPDATE
+		 SET mean = EXCLUDED.mean, stddev = EXCLUDED.stddev, courses = EXCLUDED.courses, updated_at = EXCLUDED.updated_at`,
+		names, means, stddevs, courses,
+	)
+	if err != nil {
+		return fmt.Errorf("replace difficulty calibration: %w", err)
+	}
+	return nil
+}
+
+// GetDepartment returns a department's difficulty baseline, or nil if it has none.
+func (r *CalibrationRepository) GetDepartment(ctx context.Context, department string) (*models.DepartmentDifficulty, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	d := &models.DepartmentDifficulty{Department: department}
+	err := r.db.QueryRow(ctx,
+		`SELECT mean, stddev, courses, updated_at FROM difficulty_calibration WHERE department = $1`,
+		department,
+	).Scan(&d.Mean, &d.StdDev, &d.Courses, &d.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query difficulty calibration: %w", err)
+	}
+	return d, nil
+}
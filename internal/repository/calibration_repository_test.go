@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalibrationRepository_ListCourseDifficulties(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewCalibrationRepository(mock)
+	since := time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`FROM reviews\s+WHERE created_at >= \$1 AND \(moderation = 'approved' AND \(publish_at IS NULL`).
+		WithArgs(since).
+		WillReturnRows(pgxmock.NewRows([]string{"course_code", "avg", "count"}).
+			AddRow("EECS2030", 3.5, 4).
+			AddRow("MATH1013", 2.0, 1))
+
+	courses, err := repo.ListCourseDifficulties(context.Background(), since)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.CourseDifficulty{
+		{CourseCode: "EECS2030", AvgDifficulty: 3.5, Reviews: 4},
+		{CourseCode: "MATH1013", AvgDifficulty: 2.0, Reviews: 1},
+	}, courses)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCalibrationRepository_ReplaceCalibration(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewCalibrationRepository(mock)
+
+	mock.ExpectExec("INSERT INTO difficulty_calibration").
+		WithArgs([]string{"EECS"}, []float64{3.2}, []float64{0.6}, []int32{12}).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	err = repo.ReplaceCalibration(context.Background(), []models.DepartmentDifficulty{
+		{Department: "EECS", Mean: 3.2, StdDev: 0.6, Courses: 12},
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCalibrationRepository_ReplaceCalibrationError(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewCalibrationRepository(mock)
+	mock.ExpectExec("INSERT INTO difficulty_calibration").WillReturnError(errors.New("db down"))
+
+	err = repo.ReplaceCalibration(context.Background(), nil)
+	assert.ErrorContains(t, err, "replace difficulty calibration")
+}
+
+func TestCalibrationRepository_GetDepartment(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewCalibrationRepository(mock)
+	updated := time.Date(2026, 10, 1, 3, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("FROM difficulty_calibration WHERE department = \\$1").
+		WithArgs("EECS").
+		WillReturnRows(pgxmock.NewRows([]string{"mean", "stddev", "courses", "updated_at"}).AddRow(3.2, 0.6, 12, updated))
+	mock.ExpectQuery("FROM difficulty_calibration WHERE department = \\$1").
+		WithArgs("NURS").
+		WillReturnError(pgx.ErrNoRows)
+
+	d, err := repo.GetDepartment(context.Background(), "EECS")
+	assert.NoError(t, err)
+	assert.Equal(t, &models.DepartmentDifficulty{Department: "EECS", Mean: 3.2, StdDev: 0.6, Courses: 12, UpdatedAt: updated}, d)
+
+	d, err = repo.GetDepartment(context.Background(), "NURS")
+	assert.NoError(t, err)
+	assert.Nil(t, d)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
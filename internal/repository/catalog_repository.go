@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"yuplan/internal/models"
+	"yuplan/internal/seedcheck"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+type CatalogRepositoryInterface interface {
+	EnsureTerm(ctx context.Context, term models.Term) error
+	UpsertCourse(ctx context.Context, plan seedcheck.Plan, term models.Term) (models.CourseChange, error)
+	MarkSeeded(ctx context.Context, checksum string, cacheInvalidated bool) error
+}
+
+type catalogDB interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+// CatalogRepository writes scraped courses, sections, activities and
+// instructors, the tables scripts/seed.sh used to truncate and refill.
+type CatalogRepository struct {
+	db catalogDB
+}
+
+func NewCatalogRepository(db catalogDB) *CatalogRepository {
+	return &CatalogRepository{db: db}
+}
+
+// EnsureTerm adds the session sections are loaded into, leaving the dates of
+// one that already exists alone.
+func (r *CatalogRepository) EnsureTerm(ctx context.Context, term models.Term) error {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO terms (id, session, academic_year, starts_on, ends_on)
+		 VALUES ($1, $2, $3, $4, $5)
+		 ON CONFLICT DO NOTHING`,
+		term.ID, term.Session, term.AcademicYear, term.StartsOn, term.EndsOn,
+	)
+	if err != nil {
+		return fmt.Errorf("ensure term: %w", err)
+	}
+	return nil
+}
+
+// UpsertCourse brings one course's rows for a term in line with a validated
+// record, all in one statement, and counts the rows it added, changed and
+// removed in each table, along with the course's id. The course is matched by code and term, its sections
+// by letter within the term and their activities by type and catalog number,
+// so ids that seat watches, blocks and enrollment history point at survive a
+// rescrape. Rows are only rewritten when something in them differs. Sections,
+// activities and instructors the record no longer lists are deleted; a
+// description the record lacks is kept. The offering is added to
+// course_offerings as scripts/seed.sh did.
+func (r *CatalogRepository) UpsertCourse(ctx context.Context, plan seedcheck.Plan, term models.Term) (models.CourseChange, error) {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	act, inst := planColumns(plan)
+	change := models.CourseChange{Code: plan.Code, TermID: term.ID}
+	diff := &change.Diff
+	err := r.db.QueryRow(ctx,
+		`WITH existing AS (
+		     SELECT id FROM courses WHERE code = $1 AND term = $2 ORDER BY created_at, id LIMIT 1
+		 ),
+		 updated AS (
+		     UPDATE courses c
+		     SET name = $3, credits = $4, description = COALESCE(NULLIF($5, ''), c.description),
+		         faculty = NULLIF($6, ''), updated_at = NOW()
+		     FROM existing
+		     WHERE c.id = existing.id
+		       AND (c.name IS DISTINCT FROM $3 OR c.credits IS DISTINCT FROM $4
+		            OR ($5 <> '' AND c.description IS DISTINCT FROM $5)
+		            OR c.faculty IS DISTINCT FROM NULLIF($6, ''))
+		     RETURNING c.id
+		 ),
+		 inserted AS (
+		     INSERT INTO courses (id, name, code, credits, description, faculty, term)
+		     SELECT gen_random_uuid(), $3, $1, $4, NULLIF($5, ''), NULLIF($6, ''), $2
+		     WHERE NOT EXISTS (SELECT 1 FROM existing)
+		     RETURNING id
+		 ),
+		 course AS (
+		     SELECT id FROM existing UNION ALL SELECT id FROM inserted
+		 ),
+		 kept_section AS (
+		     SELECT s.id, s.letter FROM sections s JOIN existing ON s.course_id = existing.id
+		     WHERE s.term_id = $7 AND s.letter = ANY($8::text[])
+		 ),
+		 new_section AS (
+		     INSERT INTO sections (id, course_id, letter, term_id)
+		     SELECT gen_random_uuid(), course.id, l.letter, $7
+		     FROM course, unnest($8::text[]) AS l(letter)
+		     WHERE l.letter NOT IN (SELECT letter FROM kept_section)
+		     RETURNING id, letter
+		 ),
+		 stale_section AS (
+		     DELETE FROM sections s USING existing
+		     WHERE s.course_id = existing.id AND s.term_id = $7 AND s.letter <> ALL($8::text[])
+		     RETURNING 1
+		 ),
+		 section AS (
+		     SELECT id, letter FROM kept_section UNION ALL SELECT id, letter FROM new_section
+		 ),
+		 planned_activity AS (
+		     SELECT section.id AS section_id, a.course_type, a.catalog_number, NULLIF(a.times, '') AS times
+		     FROM unnest($9::text[], $10::text[], $11::text[], $12::text[]) AS a(letter, course_type, catalog_number, times)
+		     JOIN section ON section.letter = a.letter
+		 ),
+		 updated_activity AS (
+		     UPDATE section_activities sa SET times = p.times, updated_at = NOW()
+		     FROM planned_activity p
+		     WHERE sa.section_id = p.section_id AND sa.course_type = p.course_type AND sa.catalog_number = p.catalog_number
+		       AND sa.times IS DISTINCT FROM p.times
+		     RETURNING 1
+		 ),
+		 new_activity AS (
+		     INSERT INTO section_activities (id, course_type, section_id, catalog_number, times)
+		     SELECT gen_random_uuid(), p.course_type, p.section_id, p.catalog_number, p.times
+		     FROM planned_activity p
+		     WHERE NOT EXISTS (
+		         SELECT 1 FROM section_activities sa
+		         WHERE sa.section_id = p.section_id AND sa.course_type = p.course_type AND sa.catalog_number = p.catalog_number
+		     )
+		     RETURNING 1
+		 ),
+		 stale_activity AS (
+		     DELETE FROM section_activities sa USING kept_section
+		     WHERE sa.section_id = kept_section.id
+		       AND NOT EXISTS (
+		           SELECT 1 FROM planned_activity p
+		           WHERE p.section_id = sa.section_id AND p.course_type = sa.course_type AND p.catalog_number = sa.catalog_number
+		       )
+		     RETURNING 1
+		 ),
+		 planned_instructor AS (
+		     SELECT section.id AS section_id, i.first_name, i.last_name, NULLIF(i.link, '') AS link
+		     FROM unnest($13::text[], $14::text[], $15::text[], $16::text[]) AS i(letter, first_name, last_name, link)
+		     JOIN section ON section.letter = i.letter
+		 ),
+		 new_instructor AS (
+		     INSERT INTO instructors (id, first_name, last_name, rate_my_prof_link, section_id)
+		     SELECT gen_random_uuid(), NULLIF(p.first_name, ''), NULLIF(p.last_name, ''), p.link, p.section_id
+		     FROM planned_instructor p
+		     WHERE NOT EXISTS (
+		         SELECT 1 FROM instructors i
+		         WHERE i.section_id = p.section_id
+		           AND COALESCE(i.first_name, '') = p.first_name AND COALESCE(i.last_name, '') = p.last_name
+		     )
+		     RETURNING 1
+		 ),
+		 stale_instructor AS (
+		     DELETE FROM instructors i USING kept_section
+		     WHERE i.section_id = kept_section.id
+		       AND NOT EXISTS (
+		           SELECT 1 FROM planned_instructor p
+		           WHERE p.section_id = i.section_id
+		             AND p.first_name = COALESCE(i.first_name, '') AND p.last_name = COALESCE(i.last_name, '')
+		       )
+		     RETURNING 1
+		 ),
+		 offering AS (
+		     INSERT INTO course_offerings (code, academic_year, term)
+		     VALUES ($1, $17, $2)
+		     ON CONFLICT DO NOTHING
+		 )
+		 SELECT (SELECT id FROM course), (SELECT COUNT(*) FROM inserted)::int, (SELECT COUNT(*) FROM updated)::int,
+		        (SELECT COUNT(*) FROM new_section)::int, (SELECT COUNT(*) FROM stale_section)::int,
+		        (SELECT COUNT(*) FROM new_activity)::int, (SELECT COUNT(*) FROM updated_activity)::int,
+		        (SELECT COUNT(*) FROM stale_activity)::int,
+		        (SELECT COUNT(*) FROM new_instructor)::int, (SELECT COUNT(*) FROM stale_instructor)::int`,
+		plan.Code, plan.Term, plan.Name, plan.Credits, plan.Description, plan.Faculty,
+		term.ID, plan.Letters,
+		act.letters, act.types, act.catalogs, act.times,
+		inst.letters, inst.first, inst.last, inst.links,
+		term.AcademicYear,
+	).Scan(
+		&change.CourseID, &diff.Courses.Added, &diff.Courses.Changed,
+		&diff.Sections.Added, &diff.Sections.Removed,
+		&diff.Activities.Added, &diff.Activities.Changed, &diff.Activities.Removed,
+		&diff.Instructors.Added, &diff.Instructors.Removed,
+	)
+	if err != nil {
+		return models.CourseChange{}, fmt.Errorf("upsert course %s %s: %w", plan.Code, plan.Term, err)
+	}
+	return change, nil
+}
+
+// MarkSeeded records the checksum of a finished load in _seed_checksum, as
+// scripts/seed.sh does last, so catalog change detection and cache
+// invalidation pick it up only once everything is written. cacheInvalidated
+// says the load already dropped the cached reads it changed. It isn't
+// recorded while an earlier load that didn't is still waiting to be noticed,
+// so that load's changes still empty the whole catalog cache.
+func (r *CatalogRepository) MarkSeeded(ctx context.Context, checksum string, cacheInvalidated bool) error {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	_, err := r.db.Exec(ctx,
+		`WITH pending AS (
+		     SELECT 1 FROM _seed_checksum s
+		     WHERE NOT s.cache_invalidated
+		       AND s.checksum IS DISTINCT FROM (SELECT checksum FROM catalog_snapshot_state LIMIT 1)
+		 ),
+		 cleared AS (DELETE FROM _seed_checksum)
+		 INSERT INTO _seed_checksum (checksum, cache_invalidated)
+		 VALUES ($1, $2 AND NOT EXISTS (SELECT 1 FROM pending))`,
+		checksum, cacheInvalidated,
+	)
+	if err != nil {
+		return fmt.Errorf("mark seeded: %w", err)
+	}
+	return nil
+}
+
+type activityColumns struct{ letters, types, catalogs, times []string }
+
+type instructorColumns struct{ letters, first, last, links []string }
+
+// planColumns lays a plan's activities and instructors out as parallel
+// arrays, for statements that unnest them.
+func planColumns(plan seedcheck.Plan) (activityColumns, instructorColumns) {
+	var act activityColumns
+	for _, a := range plan.Activities {
+		act.letters = append(act.letters, a.Letter)
+		act.types = append(act.types, a.CourseType)
+		act.catalogs = append(act.catalogs, a.CatalogNumber)
+		act.times = append(act.times, a.Times)
+	}
+	var inst instructorColumns
+	for _, i := range plan.Instructors {
+		inst.letters = append(inst.letters, i.Letter)
+		inst.first = append(inst.first, i.FirstName)
+		inst.last = append(inst.last, i.LastName)
+		inst.links = append(inst.links, i.RateMyProfLink)
+	}
+	return act, inst
+}
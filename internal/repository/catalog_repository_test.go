@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"yuplan/internal/models"
+	"yuplan/internal/seedcheck"
+
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatalogRepository_EnsureTerm(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewCatalogRepository(mock)
+	term, _ := models.NewTerm("FW2025")
+
+	mock.ExpectExec("INSERT INTO terms (.+) ON CONFLICT DO NOTHING").
+		WithArgs("FW2025", "FW", 2025, term.StartsOn, term.EndsOn).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	assert.NoError(t, repo.EnsureTerm(context.Background(), term))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCatalogRepository_UpsertCourse(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewCatalogRepository(mock)
+	term, _ := models.NewTerm("FW2025")
+	plan := seedcheck.Plan{
+		Code: "EECS2030", Name: "OOP", Credits: 3, Faculty: "LE", Term: "F",
+		Letters:     []string{"A"},
+		Activities:  []seedcheck.PlannedActivity{{Letter: "A", CourseType: "LECT", CatalogNumber: "K12A01", Times: `[{"day":"M"}]`}},
+		Instructors: []seedcheck.PlannedInstructor{{Letter: "A", FirstName: "Jane", LastName: "Doe", RateMyProfLink: "https://rmp/?q=Jane+Doe"}},
+	}
+	args := []any{"EECS2030", "F", "OOP", 3.0, "", "LE", "FW2025", []string{"A"},
+		[]string{"A"}, []string{"LECT"}, []string{"K12A01"}, []string{`[{"day":"M"}]`},
+		[]string{"A"}, []string{"Jane"}, []string{"Doe"}, []string{"https://rmp/?q=Jane+Doe"},
+		2025}
+
+	mock.ExpectQuery("UPDATE courses (.+) INSERT INTO courses (.+) INSERT INTO sections (.+) DELETE FROM sections (.+) " +
+		"UPDATE section_activities (.+) INSERT INTO section_activities (.+) DELETE FROM section_activities (.+) " +
+		"INSERT INTO instructors (.+) DELETE FROM instructors (.+) INSERT INTO course_offerings").
+		WithArgs(args...).
+		WillReturnRows(pgxmock.NewRows([]string{
+			"id", "courses_added", "courses_changed", "sections_added", "sections_removed",
+			"activities_added", "activities_changed", "activities_removed", "instructors_added", "instructors_removed",
+		}).AddRow("c1", 0, 1, 1, 2, 1, 0, 3, 1, 0))
+	mock.ExpectQuery("INSERT INTO courses").
+		WithArgs(args...).
+		WillReturnError(errors.New("db down"))
+
+	change, err := repo.UpsertCourse(context.Background(), plan, term)
+	assert.NoError(t, err)
+	assert.Equal(t, models.CourseChange{
+		CourseID: "c1",
+		Code:     "EECS2030",
+		TermID:   "FW2025",
+		Diff: models.CatalogDiff{
+			Courses:     models.RowDiff{Changed: 1},
+			Sections:    models.RowDiff{Added: 1, Removed: 2},
+			Activities:  models.RowDiff{Added: 1, Removed: 3},
+			Instructors: models.RowDiff{Added: 1},
+		},
+	}, change)
+
+	_, err = repo.UpsertCourse(context.Background(), plan, term)
+	assert.ErrorContains(t, err, "upsert course EECS2030 F")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCatalogRepository_MarkSeeded(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewCatalogRepository(mock)
+
+	mock.ExpectExec("WHERE NOT s.cache_invalidated(.+)DELETE FROM _seed_checksum(.+)INSERT INTO _seed_checksum \\(checksum, cache_invalidated\\)").
+		WithArgs("abc123", true).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	assert.NoError(t, repo.MarkSeeded(context.Background(), "abc123", true))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
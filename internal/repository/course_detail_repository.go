@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"yuplan/internal/dbtypes"
+	"yuplan/internal/models"
+
+	"github.com/jackc/pgx/v4"
+)
+
+type CourseDetailRepositoryInterface interface {
+	GetFull(ctx context.Context, courseID string, since time.Time) (*models.CourseDetail, error)
+}
+
+type courseDetailDB interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// CourseDetailRepository loads a course page in a fixed number of queries,
+// however many sections the course has.
+type CourseDetailRepository struct {
+	db          courseDetailDB
+	instructors InstructorRepositoryInterface
+}
+
+func NewCourseDetailRepository(db courseDetailDB, instructors InstructorRepositoryInterface) *CourseDetailRepository {
+	return &CourseDetailRepository{db: db, instructors: instructors}
+}
+
+// GetFull returns the course with the given id, its sections and their
+// activities, its instructors and stats over published reviews created at or
+// after since. It returns nil if there is no such course.
+func (r *CourseDetailRepository) GetFull(ctx context.Context, courseID string, since time.Time) (*models.CourseDetail, error) {
+	detail, err := r.getCourse(ctx, courseID, since)
+	if err != nil || detail == nil {
+		return nil, err
+	}
+
+	detail.Sections, err = r.getSections(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	detail.Labs = []models.SectionActivity{}
+	detail.Tutorials = []models.SectionActivity{}
+	for _, section := range detail.Sections {
+		for _, activity := range section.Activities {
+			switch activity.CourseType {
+			case models.ActivityLab:
+				detail.Labs = append(detail.Labs, activity)
+			case models.ActivityTutorial:
+				detail.Tutorials = append(detail.Tutorials, activity)
+			}
+		}
+	}
+
+	detail.Instructors, err = r.instructors.GetByCourseID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	return detail, nil
+}
+
+// getCourse reads the course row and its review stats together.
+func (r *CourseDetailRepository) getCourse(ctx context.Context, courseID string, since time.Time) (*models.CourseDetail, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	var detail models.CourseDetail
+	var likes int
+	err := r.db.QueryRow(ctx,
+		`WITH course AS (
+		     SELECT id, name, code, credits, description, faculty, term, created_at, updated_at
+		     FROM courses
+		     WHERE id = $1
+		 ),
+		 review AS (
+		     SELECT liked, difficulty FROM reviews
+		     WHERE course_code IN (SELECT code FROM course) AND created_at >= $2 AND `+publishedFilter+`
+		 )
+		 SELECT course.id, course.name, course.code, course.credits, course.description, course.faculty, course.term, course.created_at, course.updated_at,
+		        (SELECT ROUND(AVG(difficulty)::numeric, 1)::float8 FROM review),
+		        (SELECT COUNT(*) FROM review),
+		        (SELECT COUNT(*) FROM review WHERE liked)
+		 FROM course`,
+		courseID, since,
+	).Scan(&detail.ID, &detail.Name, &detail.Code, &detail.Credits, &detail.Description, &detail.Faculty, &detail.Term, &detail.CreatedAt, &detail.UpdatedAt,
+		&detail.Stats.AvgDifficulty, &detail.Stats.ReviewCount, &likes)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query course detail: %w", err)
+	}
+
+	detail.Stats.Code = detail.Code
+	detail.Stats.Name = detail.Name
+	if detail.Stats.ReviewCount > 0 {
+		pct := int(float64(likes) / float64(detail.Stats.ReviewCount) * 100)
+		detail.Stats.LikePercentage = &pct
+	}
+	return &detail, nil
+}
+
+// getSections reads the sections and their activities in one query, where
+// SectionRepository.GetByCourseID makes one per section.
+func (r *CourseDetailRepository) getSections(ctx context.Context, courseID string) ([]models.Section, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	rows, err := r.db.Query(ctx,
+		`SELECT s.id, s.course_id, s.letter, s.created_at, s.updated_at,
+		        a.id, a.course_type, a.catalog_number, a.times, a.created_at, a.updated_at
+		 FROM sections s
+		 LEFT JOIN section_activities a ON a.section_id = s.id
+		 WHERE s.course_id = $1
+		 ORDER BY s.letter, s.id, a.course_type, a.catalog_number`,
+		courseID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query course detail sections: %w", err)
+	}
+	defer rows.Close()
+
+	sections := []models.Section{}
+	for rows.Next() {
+		var sec models.Section
+		var activityID, courseType, catalogNumber *string
+		var times dbtypes.NullString
+		var createdAt, updatedAt *time.Time
+		if err := rows.Scan(&sec.ID, &sec.CourseID, &sec.Letter, &sec.CreatedAt, &sec.UpdatedAt,
+			&activityID, &courseType, &catalogNumber, &times, &createdAt, &updatedAt); err != nil {
+			return nil, fmt.Errorf("scan course detail section: %w", err)
+		}
+		// Rows come grouped by section, one per activity
+		if n := len(sections); n == 0 || sections[n-1].ID != sec.ID {
+			sec.Activities = []models.SectionActivity{}
+			sections = append(sections, sec)
+		}
+		if activityID == nil {
+			continue
+		}
+		last := &sections[len(sections)-1]
+		last.Activities = append(last.Activities, models.SectionActivity{
+			ID:            *activityID,
+			CourseType:    *courseType,
+			SectionID:     sec.ID,
+			CatalogNumber: *catalogNumber,
+			Times:         times,
+			Delivery:      models.DeliveryOf(times),
+			CreatedAt:     *createdAt,
+			UpdatedAt:     *updatedAt,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate course detail sections: %w", err)
+	}
+	return sections, nil
+}
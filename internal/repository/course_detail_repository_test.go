@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCourseDetailRepository_GetFull(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewCourseDetailRepository(mock, NewInstructorRepository(mock))
+	now := time.Now()
+	since := time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC)
+	courseID := "01928c6a-7b3e-7a21-9f00-0123456789ab"
+
+	avg := 3.5
+	mock.ExpectQuery("FROM courses (.+) FROM reviews").
+		WithArgs(courseID, since).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "name", "code", "credits", "description", "faculty", "term", "created_at", "updated_at", "avg", "count", "likes"}).
+			AddRow(courseID, "Advanced Object Oriented Programming", "EECS2030", 3.0, nil, "LE", models.TermFall, now, now, &avg, 4, 3))
+
+	times := `[{"day":"M","time":"10:00","duration":"90"}]`
+	sectionA := "section-a"
+	act1, act2, act3 := "act-1", "act-2", "act-3"
+	lect, lab, tutr := models.ActivityLecture, models.ActivityLab, models.ActivityTutorial
+	cat1, cat2, cat3 := "X12A01", "X12A02", "X12B01"
+	mock.ExpectQuery("FROM sections s LEFT JOIN section_activities a").
+		WithArgs(courseID).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "course_id", "letter", "created_at", "updated_at", "a_id", "course_type", "catalog_number", "times", "a_created_at", "a_updated_at"}).
+			AddRow("section-a", courseID, "A", now, now, &act1, &lect, &cat1, &times, &now, &now).
+			AddRow("section-a", courseID, "A", now, now, &act2, &lab, &cat2, &times, &now, &now).
+			AddRow("section-b", courseID, "B", now, now, &act3, &tutr, &cat3, nil, &now, &now).
+			AddRow("section-c", courseID, "C", now, now, nil, nil, nil, nil, nil, nil))
+
+	mock.ExpectQuery("FROM instructors i").
+		WithArgs(courseID).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "first_name", "last_name", "rate_my_prof_link", "section_id", "photo_id", "created_at", "updated_at", "rmp_id", "rating", "difficulty", "would_take_again", "num_ratings", "fetched_at"}).
+			AddRow("instructor-1", "John", "Doe", nil, &sectionA, nil, now, now, nil, nil, nil, nil, nil, nil))
+
+	detail, err := repo.GetFull(context.Background(), courseID, since)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	assert.Equal(t, "EECS2030", detail.Code)
+	assert.Equal(t, 4, detail.Stats.ReviewCount)
+	assert.Equal(t, 75, *detail.Stats.LikePercentage)
+	if assert.Len(t, detail.Sections, 3) {
+		assert.Len(t, detail.Sections[0].Activities, 2)
+		assert.Equal(t, "section-b", detail.Sections[1].Activities[0].SectionID)
+		assert.Equal(t, models.DeliveryAsynchronous, detail.Sections[1].Activities[0].Delivery)
+		assert.Empty(t, detail.Sections[2].Activities)
+	}
+	assert.Equal(t, "act-2", detail.Labs[0].ID)
+	assert.Equal(t, "act-3", detail.Tutorials[0].ID)
+	assert.Len(t, detail.Instructors, 1)
+}
+
+func TestCourseDetailRepository_GetFull_NotFound(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewCourseDetailRepository(mock, NewInstructorRepository(mock))
+
+	mock.ExpectQuery("FROM courses").WillReturnError(pgx.ErrNoRows)
+
+	detail, err := repo.GetFull(context.Background(), "01928c6a-7b3e-7a21-9f00-0123456789ab", time.Time{})
+	assert.NoError(t, err)
+	assert.Nil(t, detail)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCourseDetailRepository_GetFull_Error(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewCourseDetailRepository(mock, NewInstructorRepository(mock))
+
+	mock.ExpectQuery("FROM courses").WillReturnError(errors.New("db down"))
+
+	_, err = repo.GetFull(context.Background(), "01928c6a-7b3e-7a21-9f00-0123456789ab", time.Time{})
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
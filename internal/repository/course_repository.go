@@ -2,8 +2,11 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
+	"unicode"
 	"yuplan/internal/models"
 
 	"github.com/jackc/pgx/v4"
@@ -12,10 +15,14 @@ import (
 type CourseRepositoryInterface interface {
 	GetRandomCourses(ctx context.Context, limit int) ([]models.Course, error)
 	GetByID(ctx context.Context, courseID string) (*models.Course, error)
+	GetByIDs(ctx context.Context, ids []string) ([]models.Course, error)
 	GetByCode(ctx context.Context, courseCode string) ([]models.Course, error)
-	Search(ctx context.Context, query string, limit, offset int) ([]models.Course, error)
-	GetPaginatedCourses(ctx context.Context, page, pageSize int, faculty, courseCodeRange *string) ([]models.Course, error)
-	GetCoursesCount(ctx context.Context, faculty, courseCodeRange *string) (int, error)
+	Search(ctx context.Context, query, termID string, limit, offset int) ([]models.Course, error)
+	SearchExactCode(ctx context.Context, code, termID string) ([]models.Course, error)
+	GetPaginatedCourses(ctx context.Context, page, pageSize int, faculty, courseCodeRange, termID *string, sorts []models.CourseSort) ([]models.Course, error)
+	GetCoursesAfter(ctx context.Context, after *models.CourseCursor, limit int, faculty, courseCodeRange, termID *string) ([]models.Course, error)
+	GetCoursesCount(ctx context.Context, faculty, courseCodeRange, termID *string) (int, error)
+	StreamAll(ctx context.Context, fn func(models.Course) error) error
 }
 
 type courseDB interface {
@@ -32,6 +39,9 @@ func NewCourseRepository(db courseDB) *CourseRepository {
 }
 
 func (r *CourseRepository) GetRandomCourses(ctx context.Context, limit int) ([]models.Course, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
 	rows, err := r.db.Query(
 		ctx,
 		`SELECT id, name, code, credits, description, faculty, term, created_at, updated_at
@@ -60,7 +70,44 @@ func (r *CourseRepository) GetRandomCourses(ctx context.Context, limit int) ([]m
 	return courses, nil
 }
 
+// GetShuffledCourses returns a page of every course in an order fixed by
+// seed, so one anonymous session can page through the same shuffle while
+// another sees a different one.
+func (r *CourseRepository) GetShuffledCourses(ctx context.Context, seed string, limit, offset int) ([]models.Course, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	rows, err := r.db.Query(
+		ctx,
+		`SELECT id, name, code, credits, description, faculty, term, created_at, updated_at
+		 FROM courses
+		 ORDER BY md5($1 || id::text)
+		 LIMIT $2 OFFSET $3`,
+		seed, limit, offset,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query courses: %w", err)
+	}
+	defer rows.Close()
+
+	courses := make([]models.Course, 0)
+	for rows.Next() {
+		var c models.Course
+		if err := rows.Scan(&c.ID, &c.Name, &c.Code, &c.Credits, &c.Description, &c.Faculty, &c.Term, &c.CreatedAt, &c.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan course: %w", err)
+		}
+		courses = append(courses, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate courses: %w", err)
+	}
+	return courses, nil
+}
+
 func (r *CourseRepository) GetByID(ctx context.Context, courseID string) (*models.Course, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
 	row := r.db.QueryRow(
 		ctx,
 		`SELECT id, name, code, credits, description, faculty, term, created_at, updated_at
@@ -76,7 +123,25 @@ func (r *CourseRepository) GetByID(ctx context.Context, courseID string) (*model
 	return &course, nil
 }
 
+// GetByIDs returns the courses with the given ids in one query, in the order
+// the ids are given. Ids with no course are left out.
+func (r *CourseRepository) GetByIDs(ctx context.Context, ids []string) ([]models.Course, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	return r.queryCourses(ctx,
+		`SELECT id, name, code, credits, description, faculty, term, created_at, updated_at
+		 FROM courses
+		 WHERE id = ANY($1::uuid[])
+		 ORDER BY array_position($1::uuid[], id)`,
+		ids,
+	)
+}
+
 func (r *CourseRepository) GetByCode(ctx context.Context, courseCode string) ([]models.Course, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
 	// Normalize to match regardless of spaces/case.
 	normalized := strings.ToLower(strings.ReplaceAll(courseCode, " ", ""))
 
@@ -108,17 +173,31 @@ func (r *CourseRepository) GetByCode(ctx context.Context, courseCode string) ([]
 	return courses, nil
 }
 
-func (r *CourseRepository) Search(ctx context.Context, query string, limit, offset int) ([]models.Course, error) {
-	searchPattern := "%" + query + "%"
-	normalizedPattern := "%" + strings.ReplaceAll(query, " ", "") + "%"
+// Search finds courses by code, name or description, most relevant first.
+// Codes match with or without spaces and put their course at the top; names
+// and descriptions match whole words or word prefixes, and names also match
+// by substring or close spelling (trigram similarity). A termID other than ""
+// keeps only courses with sections in that term.
+func (r *CourseRepository) Search(ctx context.Context, query, termID string, limit, offset int) ([]models.Course, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	compactPattern := "%" + strings.ReplaceAll(query, " ", "") + "%"
 	rows, err := r.db.Query(
 		ctx,
-		`SELECT id, name, code, credits, description, faculty, term, created_at, updated_at
-		 FROM courses
-		 WHERE name ILIKE $1 OR code ILIKE $1 OR REPLACE(code, ' ', '') ILIKE $2
-		 ORDER BY code
-		 LIMIT $3 OFFSET $4`,
-		searchPattern, normalizedPattern, limit, offset,
+		`WITH q AS (SELECT to_tsquery('english', $2) AS ts)
+		 SELECT c.id, c.name, c.code, c.credits, c.description, c.faculty, c.term, c.created_at, c.updated_at
+		 FROM courses c, q
+		 WHERE (c.search_vector @@ q.ts
+		    OR REPLACE(c.code, ' ', '') ILIKE $3
+		    OR c.name ILIKE '%' || $1 || '%'
+		    OR c.name % $1)
+		   AND ($6 = '' OR EXISTS (SELECT 1 FROM sections s WHERE s.course_id = c.id AND s.term_id = $6))
+		 ORDER BY REPLACE(c.code, ' ', '') ILIKE $3 DESC,
+		          ts_rank(c.search_vector, q.ts) + similarity(c.name, $1) DESC,
+		          c.code
+		 LIMIT $4 OFFSET $5`,
+		query, prefixTSQuery(query), compactPattern, limit, offset, termID,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("search courses: %w", err)
@@ -140,80 +219,119 @@ func (r *CourseRepository) Search(ctx context.Context, query string, limit, offs
 	return courses, nil
 }
 
-// GetPaginatedCourses retrieves courses with pagination and optional filtering by faculty and course code range
-func (r *CourseRepository) GetPaginatedCourses(ctx context.Context, page, pageSize int, faculty, courseCodeRange *string) ([]models.Course, error) {
-	offset := (page - 1) * pageSize
-	
-	// Build the WHERE clause dynamically
-	whereClauses := []string{}
-	args := []interface{}{}
-	argIndex := 1
-	
-	if faculty != nil && *faculty != "" {
-		whereClauses = append(whereClauses, fmt.Sprintf("faculty = $%d", argIndex))
-		args = append(args, *faculty)
-		argIndex++
+// SearchExactCode returns every offering of one course code, matched with or
+// without spaces and in any case, optionally only those with sections in a
+// term. It uses the normalized-code index, so it answers without ranking.
+func (r *CourseRepository) SearchExactCode(ctx context.Context, code, termID string) ([]models.Course, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	rows, err := r.db.Query(
+		ctx,
+		`SELECT c.id, c.name, c.code, c.credits, c.description, c.faculty, c.term, c.created_at, c.updated_at
+		 FROM courses c
+		 WHERE REPLACE(LOWER(c.code), ' ', '') = $1
+		   AND ($2 = '' OR EXISTS (SELECT 1 FROM sections s WHERE s.course_id = c.id AND s.term_id = $2))
+		 ORDER BY c.code, c.term`,
+		strings.ToLower(strings.ReplaceAll(code, " ", "")), termID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("search courses by code: %w", err)
 	}
-	
-	if courseCodeRange != nil && *courseCodeRange != "" {
-		// Parse course code range (e.g., "1000s", "2000s", "5000s+")
-		rangeStr := *courseCodeRange
-		if strings.HasSuffix(rangeStr, "s+") {
-			// Handle "5000s+" case
-			baseNum := strings.TrimSuffix(rangeStr, "s+")
-			whereClauses = append(whereClauses, fmt.Sprintf(
-				"CAST(SUBSTRING(code FROM '\\d+') AS INTEGER) >= $%d",
-				argIndex,
-			))
-			args = append(args, baseNum)
-		} else if strings.HasSuffix(rangeStr, "s") {
-			// Handle "1000s", "2000s", etc.
-			baseNum := strings.TrimSuffix(rangeStr, "s")
-			whereClauses = append(whereClauses, fmt.Sprintf(
-				"CAST(SUBSTRING(code FROM '\\d+') AS INTEGER) >= $%d AND CAST(SUBSTRING(code FROM '\\d+') AS INTEGER) < $%d",
-				argIndex, argIndex+1,
-			))
-			args = append(args, baseNum)
-			argIndex++
-			// Calculate upper bound (e.g., 1000s -> 1000-1999, 2000s -> 2000-2999)
-			// For "1000s", we want range 1000-1999, so upperBound = first digit + "999"
-			var upperBound string
-			if len(baseNum) >= 4 {
-				// Take first digit and pad rest with 9s (e.g., "1000" -> "1999", "2000" -> "2999")
-				upperBound = string(baseNum[0]) + strings.Repeat("9", len(baseNum)-1)
-			} else {
-				upperBound = baseNum + "999"
-			}
-			args = append(args, upperBound)
+	defer rows.Close()
+
+	courses := make([]models.Course, 0)
+	for rows.Next() {
+		var c models.Course
+		if err := rows.Scan(&c.ID, &c.Name, &c.Code, &c.Credits, &c.Description, &c.Faculty, &c.Term, &c.CreatedAt, &c.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan course: %w", err)
 		}
-		argIndex++
+		courses = append(courses, c)
 	}
-	
-	whereClause := ""
-	if len(whereClauses) > 0 {
-		whereClause = "WHERE " + strings.Join(whereClauses, " AND ")
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate courses by code: %w", err)
+	}
+
+	return courses, nil
+}
+
+// prefixTSQuery turns free text into a to_tsquery expression matching every
+// word as a prefix, so "softw eng" finds "Software Engineering". Anything but
+// letters and digits separates words, which also keeps tsquery operators out.
+func prefixTSQuery(query string) string {
+	words := strings.FieldsFunc(query, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, w := range words {
+		words[i] = w + ":*"
 	}
-	
+	return strings.Join(words, " & ")
+}
+
+// GetPaginatedCourses retrieves courses with pagination and optional filtering by faculty, course code range and term,
+// ordered by sorts and then code and term; see courseOrderBy
+func (r *CourseRepository) GetPaginatedCourses(ctx context.Context, page, pageSize int, faculty, courseCodeRange, termID *string, sorts []models.CourseSort) ([]models.Course, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	offset := (page - 1) * pageSize
+	whereClause, args := courseFilters(faculty, courseCodeRange, termID)
+
 	// Add pagination parameters
 	args = append(args, pageSize, offset)
-	limitArg := argIndex
-	offsetArg := argIndex + 1
-	
+	limitArg := len(args) - 1
+	offsetArg := len(args)
+
+	orderBy, reviewJoin := courseOrderBy(sorts)
 	query := fmt.Sprintf(
 		`SELECT id, name, code, credits, description, faculty, term, created_at, updated_at
 		 FROM courses
 		 %s
-		 ORDER BY code, term
+		 %s
+		 ORDER BY %s
 		 LIMIT $%d OFFSET $%d`,
-		whereClause, limitArg, offsetArg,
+		reviewJoin, whereClause, orderBy, limitArg, offsetArg,
+	)
+	return r.queryCourses(ctx, query, args...)
+}
+
+// GetCoursesAfter lists the courses matching the filters that come after the
+// cursor in code, term and id order, or from the start when it is nil. Unlike
+// pages by offset, rows added or removed before the cursor don't shift it.
+func (r *CourseRepository) GetCoursesAfter(ctx context.Context, after *models.CourseCursor, limit int, faculty, courseCodeRange, termID *string) ([]models.Course, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	whereClause, args := courseFilters(faculty, courseCodeRange, termID)
+	if after != nil {
+		args = append(args, after.Code, after.Term, after.ID)
+		keyset := fmt.Sprintf("(code, term, id) > ($%d, $%d, $%d)", len(args)-2, len(args)-1, len(args))
+		if whereClause == "" {
+			whereClause = "WHERE " + keyset
+		} else {
+			whereClause += " AND " + keyset
+		}
+	}
+	args = append(args, limit)
+
+	query := fmt.Sprintf(
+		`SELECT id, name, code, credits, description, faculty, term, created_at, updated_at
+		 FROM courses
+		 %s
+		 ORDER BY code, term, id
+		 LIMIT $%d`,
+		whereClause, len(args),
 	)
-	
+	return r.queryCourses(ctx, query, args...)
+}
+
+func (r *CourseRepository) queryCourses(ctx context.Context, query string, args ...any) ([]models.Course, error) {
 	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("query paginated courses: %w", err)
+		return nil, fmt.Errorf("query courses: %w", err)
 	}
 	defer rows.Close()
-	
+
 	courses := make([]models.Course, 0)
 	for rows.Next() {
 		var c models.Course
@@ -225,26 +343,48 @@ func (r *CourseRepository) GetPaginatedCourses(ctx context.Context, page, pageSi
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("iterate courses: %w", err)
 	}
-	
+
 	return courses, nil
 }
 
 // GetCoursesCount returns the total count of courses matching the filters
-func (r *CourseRepository) GetCoursesCount(ctx context.Context, faculty, courseCodeRange *string) (int, error) {
-	// Build the WHERE clause dynamically (same logic as GetPaginatedCourses)
+func (r *CourseRepository) GetCoursesCount(ctx context.Context, faculty, courseCodeRange, termID *string) (int, error) {
+	ctx, cancel := withDeadline(ctx, opAggregate)
+	defer cancel()
+
+	whereClause, args := courseFilters(faculty, courseCodeRange, termID)
+	query := fmt.Sprintf(
+		`SELECT COUNT(DISTINCT code) FROM courses %s`,
+		whereClause,
+	)
+
+	var count int
+	err := r.db.QueryRow(ctx, query, args...).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("count courses: %w", err)
+	}
+
+	return count, nil
+}
+
+// courseFilters builds the WHERE clause for the course listing filters, with
+// its arguments numbered from $1. It is empty when no filter is set.
+func courseFilters(faculty, courseCodeRange, termID *string) (string, []any) {
 	whereClauses := []string{}
-	args := []interface{}{}
+	args := []any{}
 	argIndex := 1
-	
+
 	if faculty != nil && *faculty != "" {
 		whereClauses = append(whereClauses, fmt.Sprintf("faculty = $%d", argIndex))
 		args = append(args, *faculty)
 		argIndex++
 	}
-	
+
 	if courseCodeRange != nil && *courseCodeRange != "" {
+		// Parse course code range (e.g., "1000s", "2000s", "5000s+")
 		rangeStr := *courseCodeRange
 		if strings.HasSuffix(rangeStr, "s+") {
+			// Handle "5000s+" case
 			baseNum := strings.TrimSuffix(rangeStr, "s+")
 			whereClauses = append(whereClauses, fmt.Sprintf(
 				"CAST(SUBSTRING(code FROM '\\d+') AS INTEGER) >= $%d",
@@ -252,6 +392,7 @@ func (r *CourseRepository) GetCoursesCount(ctx context.Context, faculty, courseC
 			))
 			args = append(args, baseNum)
 		} else if strings.HasSuffix(rangeStr, "s") {
+			// Handle "1000s", "2000s", etc.
 			baseNum := strings.TrimSuffix(rangeStr, "s")
 			whereClauses = append(whereClauses, fmt.Sprintf(
 				"CAST(SUBSTRING(code FROM '\\d+') AS INTEGER) >= $%d AND CAST(SUBSTRING(code FROM '\\d+') AS INTEGER) < $%d",
@@ -260,6 +401,7 @@ func (r *CourseRepository) GetCoursesCount(ctx context.Context, faculty, courseC
 			args = append(args, baseNum)
 			argIndex++
 			// Calculate upper bound (e.g., 1000s -> 1000-1999, 2000s -> 2000-2999)
+			// For "1000s", we want range 1000-1999, so upperBound = first digit + "999"
 			var upperBound string
 			if len(baseNum) >= 4 {
 				// Take first digit and pad rest with 9s (e.g., "1000" -> "1999", "2000" -> "2999")
@@ -271,22 +413,314 @@ func (r *CourseRepository) GetCoursesCount(ctx context.Context, faculty, courseC
 		}
 		argIndex++
 	}
-	
-	whereClause := ""
-	if len(whereClauses) > 0 {
-		whereClause = "WHERE " + strings.Join(whereClauses, " AND ")
+
+	if termID != nil && *termID != "" {
+		whereClauses = append(whereClauses, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM sections s WHERE s.course_id = courses.id AND s.term_id = $%d)",
+			argIndex,
+		))
+		args = append(args, *termID)
 	}
-	
-	query := fmt.Sprintf(
-		`SELECT COUNT(DISTINCT code) FROM courses %s`,
-		whereClause,
+
+	if len(whereClauses) == 0 {
+		return "", args
+	}
+	return "WHERE " + strings.Join(whereClauses, " AND "), args
+}
+
+// StreamAll calls fn with every course, ordered by code and term, without
+// holding the catalog in memory. It stops at the first error fn returns.
+func (r *CourseRepository) StreamAll(ctx context.Context, fn func(models.Course) error) error {
+	ctx, cancel := withDeadline(ctx, opExport)
+	defer cancel()
+
+	rows, err := r.db.Query(
+		ctx,
+		`SELECT id, name, code, credits, description, faculty, term, created_at, updated_at
+		 FROM courses
+		 ORDER BY code, term`,
 	)
-	
-	var count int
-	err := r.db.QueryRow(ctx, query, args...).Scan(&count)
 	if err != nil {
-		return 0, fmt.Errorf("count courses: %w", err)
+		return fmt.Errorf("query all courses: %w", err)
 	}
-	
-	return count, nil
+	defer rows.Close()
+
+	for rows.Next() {
+		var c models.Course
+		if err := rows.Scan(&c.ID, &c.Name, &c.Code, &c.Credits, &c.Description, &c.Faculty, &c.Term, &c.CreatedAt, &c.UpdatedAt); err != nil {
+			return fmt.Errorf("scan course: %w", err)
+		}
+		if err := fn(c); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate all courses: %w", err)
+	}
+	return nil
+}
+
+// Patch applies an admin's edit to a course in one statement. Only the
+// columns the patch sets are written, and each one whose value changes gets
+// its own audit_log entry with the value before and after. With updatedAt
+// set, the edit only applies while the course's updated_at is still exactly
+// that. It returns the course as it now is, or nil if there is no such
+// course, and whether the edit applied.
+func (r *CourseRepository) Patch(ctx context.Context, courseID string, patch models.CoursePatch, updatedAt *time.Time) (*models.Course, bool, error) {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	args := []any{courseID, updatedAt}
+	set := make([]string, 0, len(models.CoursePatchFields)+1)
+	changes := make([]string, 0, len(models.CoursePatchFields))
+	for _, col := range coursePatchColumns(patch) {
+		args = append(args, col.value)
+		set = append(set, fmt.Sprintf("%s = $%d::%s", col.name, len(args), col.cast))
+		changes = append(changes, fmt.Sprintf("('%[1]s', to_jsonb(b.%[1]s), to_jsonb(u.%[1]s))", col.name))
+	}
+	if len(changes) == 0 {
+		return nil, false, fmt.Errorf("patch course %s: nothing to update", courseID)
+	}
+	set = append(set, "updated_at = NOW()")
+
+	var course models.Course
+	var applied bool
+	err := r.db.QueryRow(ctx,
+		fmt.Sprintf(`WITH before AS (
+		     SELECT id, name, credits, description, faculty FROM courses WHERE id = $1 FOR UPDATE
+		 ),
+		 updated AS (
+		     UPDATE courses c SET %s
+		     FROM before
+		     WHERE c.id = before.id AND ($2::timestamp IS NULL OR c.updated_at = $2::timestamp)
+		     RETURNING c.id, c.name, c.code, c.credits, c.description, c.faculty, c.term, c.created_at, c.updated_at
+		 ),
+		 audit AS (
+		     INSERT INTO audit_log (entity, entity_id, action, details)
+		     SELECT 'course', u.id::text, 'update', jsonb_build_object('field', f.field, 'from', f.old, 'to', f.new)
+		     FROM updated u
+		     JOIN before b ON b.id = u.id
+		     CROSS JOIN LATERAL (VALUES %s) AS f(field, old, new)
+		     WHERE f.old IS DISTINCT FROM f.new
+		 )
+		 SELECT true, id, name, code, credits, description, faculty, term, created_at, updated_at FROM updated
+		 UNION ALL
+		 SELECT false, id, name, code, credits, description, faculty, term, created_at, updated_at
+		 FROM courses
+		 WHERE id = $1 AND NOT EXISTS (SELECT 1 FROM updated)`,
+			strings.Join(set, ", "), strings.Join(changes, ", ")),
+		args...,
+	).Scan(&applied, &course.ID, &course.Name, &course.Code, &course.Credits, &course.Description, &course.Faculty, &course.Term, &course.CreatedAt, &course.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("patch course %s: %w", courseID, err)
+	}
+	return &course, applied, nil
+}
+
+// Create adds a course, unless one with the same code already runs in the
+// same term, and writes it to the audit log. It returns nil if the course
+// already exists.
+func (r *CourseRepository) Create(ctx context.Context, req models.CreateCourseRequest) (*models.Course, error) {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	var course models.Course
+	err := r.db.QueryRow(ctx,
+		`WITH inserted AS (
+		     INSERT INTO courses (id, name, code, credits, description, faculty, term)
+		     SELECT gen_random_uuid(), $3, $1, $4, $5, $6, $2
+		     WHERE NOT EXISTS (SELECT 1 FROM courses WHERE code = $1 AND term = $2)
+		     RETURNING id, name, code, credits, description, faculty, term, created_at, updated_at
+		 ),
+		 audit AS (
+		     INSERT INTO audit_log (entity, entity_id, action, details)
+		     SELECT 'course', id::text, 'create', jsonb_build_object('code', code, 'term', term)
+		     FROM inserted
+		 )
+		 SELECT id, name, code, credits, description, faculty, term, created_at, updated_at FROM inserted`,
+		req.Code, req.Term, req.Name, req.Credits, req.Description.Ptr(), req.Faculty,
+	).Scan(&course.ID, &course.Name, &course.Code, &course.Credits, &course.Description, &course.Faculty, &course.Term, &course.CreatedAt, &course.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("create course %s %s: %w", req.Code, req.Term, err)
+	}
+	return &course, nil
+}
+
+// Delete removes a course along with its sections and everything hanging off
+// them, and writes it to the audit log. Instructors are deleted here since
+// their foreign key only unlinks them. Reviews are kept: they name courses by
+// code, not row. It reports false if there is no such course.
+func (r *CourseRepository) Delete(ctx context.Context, courseID string) (bool, error) {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	var deleted bool
+	err := r.db.QueryRow(ctx,
+		`WITH instructor AS (
+		     DELETE FROM instructors WHERE section_id IN (SELECT id FROM sections WHERE course_id = $1)
+		 ),
+		 deleted AS (
+		     DELETE FROM courses WHERE id = $1 RETURNING id, code, term
+		 ),
+		 audit AS (
+		     INSERT INTO audit_log (entity, entity_id, action, details)
+		     SELECT 'course', id::text, 'delete', jsonb_build_object('code', code, 'term', term)
+		     FROM deleted
+		 )
+		 SELECT EXISTS (SELECT 1 FROM deleted)`,
+		courseID,
+	).Scan(&deleted)
+	if err != nil {
+		return false, fmt.Errorf("delete course %s: %w", courseID, err)
+	}
+	return deleted, nil
+}
+
+// Merge folds the duplicate course sourceID into targetID and deletes it,
+// writing the merge to the audit log. Its sections move over with everything
+// hanging off them, and its blocks unless the target has one of the same name.
+// When that was the last course with the duplicate's code, its reviews move
+// to the target's code too, except where the same author already reviewed
+// the target that term. Requisites and other data kept by code stay where
+// they are. It returns nil if either course doesn't exist.
+func (r *CourseRepository) Merge(ctx context.Context, sourceID, targetID string) (*models.CourseMerge, error) {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	var merge models.CourseMerge
+	course := &merge.Course
+	err := r.db.QueryRow(ctx,
+		`WITH source AS (
+		     SELECT id, code, term FROM courses WHERE id = $1 AND id <> $2 FOR UPDATE
+		 ),
+		 target AS (
+		     SELECT id, name, code, credits, description, faculty, term, created_at, updated_at
+		     FROM courses WHERE id = $2 AND EXISTS (SELECT 1 FROM source) FOR UPDATE
+		 ),
+		 sections_moved AS (
+		     UPDATE sections s SET course_id = target.id
+		     FROM target
+		     WHERE s.course_id = $1
+		     RETURNING s.id
+		 ),
+		 blocks_moved AS (
+		     UPDATE blocks b SET course_id = target.id, updated_at = NOW()
+		     FROM target
+		     WHERE b.course_id = $1
+		       AND NOT EXISTS (SELECT 1 FROM blocks t WHERE t.course_id = target.id AND t.name = b.name)
+		     RETURNING b.id
+		 ),
+		 reviews_moved AS (
+		     UPDATE reviews rv SET course_code = target.code
+		     FROM source, target
+		     WHERE rv.course_code = source.code AND source.code <> target.code
+		       AND NOT EXISTS (SELECT 1 FROM courses c WHERE c.code = source.code AND c.id <> source.id)
+		       AND NOT EXISTS (
+		           SELECT 1 FROM reviews d
+		           WHERE d.course_code = target.code AND d.email = rv.email
+		             AND d.academic_year = rv.academic_year AND d.term = rv.term
+		       )
+		     RETURNING rv.id
+		 ),
+		 deleted AS (
+		     DELETE FROM courses c USING target WHERE c.id = $1 RETURNING c.id
+		 ),
+		 audit AS (
+		     INSERT INTO audit_log (entity, entity_id, action, details)
+		     SELECT 'course', source.id::text, 'merge', jsonb_build_object(
+		         'code', source.code, 'term', source.term, 'into', target.id,
+		         'sections', (SELECT COUNT(*) FROM sections_moved),
+		         'blocks', (SELECT COUNT(*) FROM blocks_moved),
+		         'reviews', (SELECT COUNT(*) FROM reviews_moved))
+		     FROM source, target
+		 )
+		 SELECT id, name, code, credits, description, faculty, term, created_at, updated_at,
+		        (SELECT COUNT(*) FROM sections_moved), (SELECT COUNT(*) FROM blocks_moved), (SELECT COUNT(*) FROM reviews_moved)
+		 FROM target`,
+		sourceID, targetID,
+	).Scan(&course.ID, &course.Name, &course.Code, &course.Credits, &course.Description, &course.Faculty, &course.Term, &course.CreatedAt, &course.UpdatedAt,
+		&merge.Sections, &merge.Blocks, &merge.Reviews)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("merge course %s into %s: %w", sourceID, targetID, err)
+	}
+	return &merge, nil
+}
+
+// patchColumn is a column an UPDATE sets, with the type its value is cast to.
+type patchColumn struct {
+	name, cast string
+	value      any
+}
+
+// coursePatchColumns lists the columns a patch sets. Only these fixed names
+// ever reach the SET list.
+func coursePatchColumns(patch models.CoursePatch) []patchColumn {
+	var cols []patchColumn
+	if patch.Name != nil {
+		cols = append(cols, patchColumn{"name", "text", *patch.Name})
+	}
+	if patch.Credits != nil {
+		cols = append(cols, patchColumn{"credits", "numeric", *patch.Credits})
+	}
+	if patch.Description != nil {
+		cols = append(cols, patchColumn{"description", "text", patch.Description.Ptr()})
+	}
+	if patch.Faculty != nil {
+		cols = append(cols, patchColumn{"faculty", "text", *patch.Faculty})
+	}
+	return cols
+}
+
+// courseSortColumns maps each of models.CourseSortKeys to the SQL it orders
+// by. Only these fixed strings ever reach ORDER BY; the review keys read the
+// course's published reviews joined in as review_stats.
+var courseSortColumns = map[string]string{
+	models.CourseSortCode:           "code",
+	models.CourseSortName:           "name",
+	models.CourseSortLevel:          `CAST(SUBSTRING(code FROM '\d+') AS INTEGER) / 1000`,
+	models.CourseSortCredits:        "credits",
+	models.CourseSortFaculty:        "faculty",
+	models.CourseSortTerm:           "term",
+	models.CourseSortAvgDifficulty:  "review_stats.avg_difficulty",
+	models.CourseSortLikePercentage: "review_stats.like_ratio",
+	models.CourseSortReviewCount:    "review_stats.review_count",
+}
+
+// courseReviewStatsJoin adds review_stats for the review sort keys. It is
+// backed by idx_reviews_published_stats; see migration 000036.
+const courseReviewStatsJoin = `LEFT JOIN LATERAL (
+		     SELECT AVG(difficulty) AS avg_difficulty, AVG(liked::int) AS like_ratio, COUNT(*) AS review_count
+		     FROM reviews WHERE course_code = courses.code AND ` + publishedFilter + `
+		 ) review_stats ON true`
+
+// courseOrderBy builds the ORDER BY list for sorts, and the join the review
+// keys need, if any. Courses without reviews have no avg_difficulty or
+// like_percentage and sort after those that do either way. Code and term
+// always break ties so pages don't overlap.
+func courseOrderBy(sorts []models.CourseSort) (orderBy, join string) {
+	terms := make([]string, 0, len(sorts)+2)
+	for _, sort := range sorts {
+		column, ok := courseSortColumns[sort.Key]
+		if !ok {
+			continue
+		}
+		if strings.HasPrefix(column, "review_stats.") {
+			join = courseReviewStatsJoin
+		}
+		direction := "ASC"
+		if sort.Desc {
+			direction = "DESC"
+		}
+		terms = append(terms, column+" "+direction+" NULLS LAST")
+	}
+	return strings.Join(append(terms, "code", "term"), ", "), join
 }
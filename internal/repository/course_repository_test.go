@@ -5,12 +5,15 @@ import (
 	"errors"
 	"testing"
 	"time"
+	"yuplan/internal/dbtypes"
+	"yuplan/internal/models"
 
+	"github.com/jackc/pgx/v4"
 	"github.com/pashagolub/pgxmock"
 	"github.com/stretchr/testify/assert"
 )
 
-const courseSearchQueryPattern = "SELECT id, name, code, credits, description, faculty, term, created_at, updated_at FROM courses\\s+WHERE name ILIKE \\$1 OR code ILIKE \\$1 OR REPLACE\\(code, ' ', ''\\) ILIKE \\$2\\s+ORDER BY code\\s+LIMIT \\$3 OFFSET \\$4"
+const courseSearchQueryPattern = "WITH q AS \\(SELECT to_tsquery\\('english', \\$2\\) AS ts\\)\\s+SELECT c.id, (.+) FROM courses c, q\\s+WHERE \\(c.search_vector @@ q.ts(.+)AND \\(\\$6 = '' OR EXISTS(.+)ORDER BY REPLACE\\(c.code, ' ', ''\\) ILIKE \\$3 DESC,\\s+ts_rank(.+)LIMIT \\$4 OFFSET \\$5"
 const courseByCodeQueryPattern = "SELECT id, name, code, credits, description, faculty, term, created_at, updated_at FROM courses\\s+WHERE REPLACE\\(LOWER\\(code\\), ' ', ''\\) = \\$1\\s+ORDER BY term, code"
 
 func TestGetAllCourses(t *testing.T) {
@@ -30,6 +33,24 @@ func TestGetAllCourses(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestGetShuffledCourses(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewCourseRepository(mock)
+
+	mock.ExpectQuery("FROM courses ORDER BY md5\\(\\$1 \\|\\| id::text\\) LIMIT \\$2 OFFSET \\$3").
+		WithArgs("sess-1", 20, 40).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "name", "code", "credits", "description", "faculty", "term", "created_at", "updated_at"}).
+			AddRow("c-1", "Intro", "EECS1001", 1.0, nil, "LE", "F", time.Now(), time.Now()))
+
+	courses, err := repo.GetShuffledCourses(context.Background(), "sess-1", 20, 40)
+	assert.NoError(t, err)
+	assert.Len(t, courses, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestGetCourseByID(t *testing.T) {
 	mock, err := pgxmock.NewPool()
 	assert.NoError(t, err)
@@ -50,6 +71,28 @@ func TestGetCourseByID(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestGetCoursesByIDs(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewCourseRepository(mock)
+
+	now := time.Now()
+	ids := []string{"course-2", "course-1"}
+	mock.ExpectQuery("FROM courses\\s+WHERE id = ANY\\(\\$1::uuid\\[\\]\\)\\s+ORDER BY array_position\\(\\$1::uuid\\[\\], id\\)").
+		WithArgs(ids).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "name", "code", "credits", "description", "faculty", "term", "created_at", "updated_at"}).
+			AddRow("course-2", "Discrete Math", "MATH1090", 3.0, nil, "SC", "F", now, now).
+			AddRow("course-1", "Advanced OOP", "EECS2030", 3.0, nil, "LE", "F", now, now))
+
+	courses, err := repo.GetByIDs(context.Background(), ids)
+	assert.NoError(t, err)
+	assert.Len(t, courses, 2)
+	assert.Equal(t, "MATH1090", courses[0].Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestGetCoursesByCode(t *testing.T) {
 	mock, err := pgxmock.NewPool()
 	assert.NoError(t, err)
@@ -171,12 +214,12 @@ func TestSearchCourses(t *testing.T) {
 	now := time.Now()
 	desc := "Software engineering course"
 	mock.ExpectQuery(courseSearchQueryPattern).
-		WithArgs("%EECS%", "%EECS%", 50, 0).
+		WithArgs("EECS", "EECS:*", "%EECS%", 50, 0, "").
 		WillReturnRows(pgxmock.NewRows([]string{"id", "name", "code", "credits", "description", "faculty", "term", "created_at", "updated_at"}).
 			AddRow("id-1", "Software Design", "EECS3311", 3.0, &desc, "SC", "Fall", now, now).
 			AddRow("id-2", "Software Engineering", "EECS4313", 3.0, &desc, "SC", "Winter", now, now))
 
-	courses, err := repo.Search(context.Background(), "EECS", 50, 0)
+	courses, err := repo.Search(context.Background(), "EECS", "", 50, 0)
 	assert.NoError(t, err)
 	assert.NotNil(t, courses)
 	assert.Equal(t, 2, len(courses))
@@ -195,11 +238,11 @@ func TestSearchCourses_ByCodeWithSpaces(t *testing.T) {
 	now := time.Now()
 	desc := "Software development"
 	mock.ExpectQuery(courseSearchQueryPattern).
-		WithArgs("%EECS 2030%", "%EECS2030%", 50, 0).
+		WithArgs("EECS 2030", "EECS:* & 2030:*", "%EECS2030%", 50, 0, "").
 		WillReturnRows(pgxmock.NewRows([]string{"id", "name", "code", "credits", "description", "faculty", "term", "created_at", "updated_at"}).
 			AddRow("id-3", "Software Tools", "EECS2030", 3.0, &desc, "SC", "Fall", now, now))
 
-	courses, err := repo.Search(context.Background(), "EECS 2030", 50, 0)
+	courses, err := repo.Search(context.Background(), "EECS 2030", "", 50, 0)
 	assert.NoError(t, err)
 	assert.NotNil(t, courses)
 	assert.Equal(t, 1, len(courses))
@@ -217,11 +260,11 @@ func TestSearchCourses_ByName(t *testing.T) {
 	now := time.Now()
 	desc := "Software design course"
 	mock.ExpectQuery(courseSearchQueryPattern).
-		WithArgs("%Software%", "%Software%", 50, 0).
+		WithArgs("Software", "Software:*", "%Software%", 50, 0, "").
 		WillReturnRows(pgxmock.NewRows([]string{"id", "name", "code", "credits", "description", "faculty", "term", "created_at", "updated_at"}).
 			AddRow("id-1", "Software Design", "EECS3311", 3.0, &desc, "SC", "Fall", now, now))
 
-	courses, err := repo.Search(context.Background(), "Software", 50, 0)
+	courses, err := repo.Search(context.Background(), "Software", "", 50, 0)
 	assert.NoError(t, err)
 	assert.NotNil(t, courses)
 	assert.Equal(t, 1, len(courses))
@@ -237,10 +280,10 @@ func TestSearchCourses_NoResults(t *testing.T) {
 	repo := NewCourseRepository(mock)
 
 	mock.ExpectQuery(courseSearchQueryPattern).
-		WithArgs("%NONEXISTENT%", "%NONEXISTENT%", 50, 0).
+		WithArgs("NONEXISTENT", "NONEXISTENT:*", "%NONEXISTENT%", 50, 0, "").
 		WillReturnRows(pgxmock.NewRows([]string{"id", "name", "code", "credits", "description", "faculty", "term", "created_at", "updated_at"}))
 
-	courses, err := repo.Search(context.Background(), "NONEXISTENT", 50, 0)
+	courses, err := repo.Search(context.Background(), "NONEXISTENT", "", 50, 0)
 	assert.NoError(t, err)
 	assert.NotNil(t, courses)
 	assert.Equal(t, 0, len(courses))
@@ -255,10 +298,10 @@ func TestSearchCourses_WhenQueryErrors_ReturnsError(t *testing.T) {
 	repo := NewCourseRepository(mock)
 
 	mock.ExpectQuery(courseSearchQueryPattern).
-		WithArgs("%EECS%", "%EECS%", 50, 0).
+		WithArgs("EECS", "EECS:*", "%EECS%", 50, 0, "").
 		WillReturnError(errors.New("db error"))
 
-	courses, err := repo.Search(context.Background(), "EECS", 50, 0)
+	courses, err := repo.Search(context.Background(), "EECS", "", 50, 0)
 	assert.Error(t, err)
 	assert.Nil(t, courses)
 	assert.NoError(t, mock.ExpectationsWereMet())
@@ -277,10 +320,10 @@ func TestSearchCourses_WhenScanFails_ReturnsError(t *testing.T) {
 		AddRow("id-1", "Course 1", "C1", "INVALID_FLOAT", &desc, "SC", "Fall", now, now)
 
 	mock.ExpectQuery(courseSearchQueryPattern).
-		WithArgs("%EECS%", "%EECS%", 50, 0).
+		WithArgs("EECS", "EECS:*", "%EECS%", 50, 0, "").
 		WillReturnRows(rows)
 
-	courses, err := repo.Search(context.Background(), "EECS", 50, 0)
+	courses, err := repo.Search(context.Background(), "EECS", "", 50, 0)
 	assert.Error(t, err)
 	assert.Nil(t, courses)
 	assert.NoError(t, mock.ExpectationsWereMet())
@@ -300,10 +343,10 @@ func TestSearchCourses_WhenRowsErr_ReturnsError(t *testing.T) {
 		RowError(0, errors.New("rows error"))
 
 	mock.ExpectQuery(courseSearchQueryPattern).
-		WithArgs("%EECS%", "%EECS%", 50, 0).
+		WithArgs("EECS", "EECS:*", "%EECS%", 50, 0, "").
 		WillReturnRows(rows)
 
-	courses, err := repo.Search(context.Background(), "EECS", 50, 0)
+	courses, err := repo.Search(context.Background(), "EECS", "", 50, 0)
 	assert.Error(t, err)
 	assert.Nil(t, courses)
 	assert.NoError(t, mock.ExpectationsWereMet())
@@ -325,7 +368,7 @@ func TestGetPaginatedCourses_NoFilters(t *testing.T) {
 			AddRow("id-1", "Course 1", "EECS1000", 3.0, &desc, "SC", "Fall", now, now).
 			AddRow("id-2", "Course 2", "MATH1010", 3.0, &desc, "SC", "Winter", now, now))
 
-	courses, err := repo.GetPaginatedCourses(context.Background(), 1, 20, nil, nil)
+	courses, err := repo.GetPaginatedCourses(context.Background(), 1, 20, nil, nil, nil, nil)
 	assert.NoError(t, err)
 	assert.NotNil(t, courses)
 	assert.Equal(t, 2, len(courses))
@@ -347,7 +390,7 @@ func TestGetPaginatedCourses_WithFacultyFilter(t *testing.T) {
 		WillReturnRows(pgxmock.NewRows([]string{"id", "name", "code", "credits", "description", "faculty", "term", "created_at", "updated_at"}).
 			AddRow("id-1", "Course 1", "EECS1000", 3.0, &desc, "SC", "Fall", now, now))
 
-	courses, err := repo.GetPaginatedCourses(context.Background(), 1, 20, &faculty, nil)
+	courses, err := repo.GetPaginatedCourses(context.Background(), 1, 20, &faculty, nil, nil, nil)
 	assert.NoError(t, err)
 	assert.NotNil(t, courses)
 	assert.Equal(t, 1, len(courses))
@@ -371,7 +414,7 @@ func TestGetPaginatedCourses_WithCourseCodeRangeFilter(t *testing.T) {
 			AddRow("id-1", "Course 1", "EECS1000", 3.0, &desc, "SC", "Fall", now, now).
 			AddRow("id-2", "Course 2", "MATH1500", 3.0, &desc, "SC", "Winter", now, now))
 
-	courses, err := repo.GetPaginatedCourses(context.Background(), 1, 20, nil, &courseCodeRange)
+	courses, err := repo.GetPaginatedCourses(context.Background(), 1, 20, nil, &courseCodeRange, nil, nil)
 	assert.NoError(t, err)
 	assert.NotNil(t, courses)
 	assert.Equal(t, 2, len(courses))
@@ -393,7 +436,7 @@ func TestGetPaginatedCourses_WithCourseCodeRange5000sPlus(t *testing.T) {
 		WillReturnRows(pgxmock.NewRows([]string{"id", "name", "code", "credits", "description", "faculty", "term", "created_at", "updated_at"}).
 			AddRow("id-1", "Course 1", "EECS5000", 3.0, &desc, "SC", "Fall", now, now))
 
-	courses, err := repo.GetPaginatedCourses(context.Background(), 1, 20, nil, &courseCodeRange)
+	courses, err := repo.GetPaginatedCourses(context.Background(), 1, 20, nil, &courseCodeRange, nil, nil)
 	assert.NoError(t, err)
 	assert.NotNil(t, courses)
 	assert.Equal(t, 1, len(courses))
@@ -416,7 +459,7 @@ func TestGetPaginatedCourses_WithBothFilters(t *testing.T) {
 		WillReturnRows(pgxmock.NewRows([]string{"id", "name", "code", "credits", "description", "faculty", "term", "created_at", "updated_at"}).
 			AddRow("id-1", "Course 1", "EECS2030", 3.0, &desc, "SC", "Fall", now, now))
 
-	courses, err := repo.GetPaginatedCourses(context.Background(), 1, 20, &faculty, &courseCodeRange)
+	courses, err := repo.GetPaginatedCourses(context.Background(), 1, 20, &faculty, &courseCodeRange, nil, nil)
 	assert.NoError(t, err)
 	assert.NotNil(t, courses)
 	assert.Equal(t, 1, len(courses))
@@ -440,13 +483,84 @@ func TestGetPaginatedCourses_WithPagination(t *testing.T) {
 		WillReturnRows(pgxmock.NewRows([]string{"id", "name", "code", "credits", "description", "faculty", "term", "created_at", "updated_at"}).
 			AddRow("id-11", "Course 11", "EECS3010", 3.0, &desc, "SC", "Fall", now, now))
 
-	courses, err := repo.GetPaginatedCourses(context.Background(), 2, 10, nil, nil)
+	courses, err := repo.GetPaginatedCourses(context.Background(), 2, 10, nil, nil, nil, nil)
 	assert.NoError(t, err)
 	assert.NotNil(t, courses)
 	assert.Equal(t, 1, len(courses))
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestGetCoursesAfter(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewCourseRepository(mock)
+
+	now := time.Now()
+	faculty := "LE"
+	mock.ExpectQuery("SELECT id, (.+) FROM courses\\s+WHERE faculty = \\$1 AND \\(code, term, id\\) > \\(\\$2, \\$3, \\$4\\)\\s+ORDER BY code, term, id\\s+LIMIT \\$5").
+		WithArgs("LE", "EECS2030", "F", "id-1", 21).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "name", "code", "credits", "description", "faculty", "term", "created_at", "updated_at"}).
+			AddRow("id-2", "Course 2", "EECS2030", 3.0, nil, "LE", "W", now, now))
+
+	after := &models.CourseCursor{Code: "EECS2030", Term: "F", ID: "id-1"}
+	courses, err := repo.GetCoursesAfter(context.Background(), after, 21, &faculty, nil, nil)
+	assert.NoError(t, err)
+	assert.Len(t, courses, 1)
+	assert.Equal(t, "W", courses[0].Term)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetCoursesAfter_FirstPage(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewCourseRepository(mock)
+
+	mock.ExpectQuery("SELECT id, (.+) FROM courses\\s+ORDER BY code, term, id\\s+LIMIT \\$1").
+		WithArgs(11).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "name", "code", "credits", "description", "faculty", "term", "created_at", "updated_at"}))
+
+	courses, err := repo.GetCoursesAfter(context.Background(), nil, 11, nil, nil, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, courses)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetPaginatedCourses_WithSorts(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewCourseRepository(mock)
+	courseCodeRange := "4000s"
+
+	mock.ExpectQuery("FROM courses\\s+LEFT JOIN LATERAL \\((.+)FROM reviews WHERE course_code = courses.code AND \\(moderation = 'approved'(.+)\\) review_stats ON true\\s+WHERE (.+)ORDER BY CAST\\(SUBSTRING\\(code FROM '\\\\d\\+'\\) AS INTEGER\\) / 1000 ASC NULLS LAST, review_stats.avg_difficulty DESC NULLS LAST, code, term").
+		WithArgs("4000", "4999", 20, 0).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "name", "code", "credits", "description", "faculty", "term", "created_at", "updated_at"}))
+
+	sorts := []models.CourseSort{{Key: models.CourseSortLevel}, {Key: models.CourseSortAvgDifficulty, Desc: true}}
+	_, err = repo.GetPaginatedCourses(context.Background(), 1, 20, nil, &courseCodeRange, nil, sorts)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCourseOrderBy(t *testing.T) {
+	orderBy, join := courseOrderBy(nil)
+	assert.Equal(t, "code, term", orderBy)
+	assert.Empty(t, join)
+
+	// Catalog keys don't need the review join; keys outside the whitelist never reach SQL
+	orderBy, join = courseOrderBy([]models.CourseSort{{Key: models.CourseSortName, Desc: true}, {Key: "id; DROP TABLE courses"}})
+	assert.Equal(t, "name DESC NULLS LAST, code, term", orderBy)
+	assert.Empty(t, join)
+
+	_, join = courseOrderBy([]models.CourseSort{{Key: models.CourseSortReviewCount}})
+	assert.Contains(t, join, "review_stats")
+}
+
 func TestGetPaginatedCourses_WhenQueryErrors_ReturnsError(t *testing.T) {
 	mock, err := pgxmock.NewPool()
 	assert.NoError(t, err)
@@ -458,7 +572,7 @@ func TestGetPaginatedCourses_WhenQueryErrors_ReturnsError(t *testing.T) {
 		WithArgs(20, 0).
 		WillReturnError(errors.New("db error"))
 
-	courses, err := repo.GetPaginatedCourses(context.Background(), 1, 20, nil, nil)
+	courses, err := repo.GetPaginatedCourses(context.Background(), 1, 20, nil, nil, nil, nil)
 	assert.Error(t, err)
 	assert.Nil(t, courses)
 	assert.NoError(t, mock.ExpectationsWereMet())
@@ -480,7 +594,7 @@ func TestGetPaginatedCourses_WhenScanFails_ReturnsError(t *testing.T) {
 		WithArgs(20, 0).
 		WillReturnRows(rows)
 
-	courses, err := repo.GetPaginatedCourses(context.Background(), 1, 20, nil, nil)
+	courses, err := repo.GetPaginatedCourses(context.Background(), 1, 20, nil, nil, nil, nil)
 	assert.Error(t, err)
 	assert.Nil(t, courses)
 	assert.NoError(t, mock.ExpectationsWereMet())
@@ -497,7 +611,7 @@ func TestGetCoursesCount_NoFilters(t *testing.T) {
 	mock.ExpectQuery("SELECT COUNT\\(DISTINCT code\\) FROM courses").
 		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(100))
 
-	count, err := repo.GetCoursesCount(context.Background(), nil, nil)
+	count, err := repo.GetCoursesCount(context.Background(), nil, nil, nil)
 	assert.NoError(t, err)
 	assert.Equal(t, 100, count)
 	assert.NoError(t, mock.ExpectationsWereMet())
@@ -515,7 +629,7 @@ func TestGetCoursesCount_WithFacultyFilter(t *testing.T) {
 		WithArgs("SC").
 		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(50))
 
-	count, err := repo.GetCoursesCount(context.Background(), &faculty, nil)
+	count, err := repo.GetCoursesCount(context.Background(), &faculty, nil, nil)
 	assert.NoError(t, err)
 	assert.Equal(t, 50, count)
 	assert.NoError(t, mock.ExpectationsWereMet())
@@ -533,7 +647,7 @@ func TestGetCoursesCount_WithCourseCodeRangeFilter(t *testing.T) {
 		WithArgs("1000", "1999").
 		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(25))
 
-	count, err := repo.GetCoursesCount(context.Background(), nil, &courseCodeRange)
+	count, err := repo.GetCoursesCount(context.Background(), nil, &courseCodeRange, nil)
 	assert.NoError(t, err)
 	assert.Equal(t, 25, count)
 	assert.NoError(t, mock.ExpectationsWereMet())
@@ -552,7 +666,7 @@ func TestGetCoursesCount_WithBothFilters(t *testing.T) {
 		WithArgs("SC", "2000", "2999").
 		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(15))
 
-	count, err := repo.GetCoursesCount(context.Background(), &faculty, &courseCodeRange)
+	count, err := repo.GetCoursesCount(context.Background(), &faculty, &courseCodeRange, nil)
 	assert.NoError(t, err)
 	assert.Equal(t, 15, count)
 	assert.NoError(t, mock.ExpectationsWereMet())
@@ -568,8 +682,272 @@ func TestGetCoursesCount_WhenQueryErrors_ReturnsError(t *testing.T) {
 	mock.ExpectQuery("SELECT COUNT\\(DISTINCT code\\) FROM courses").
 		WillReturnError(errors.New("db error"))
 
-	count, err := repo.GetCoursesCount(context.Background(), nil, nil)
+	count, err := repo.GetCoursesCount(context.Background(), nil, nil, nil)
 	assert.Error(t, err)
 	assert.Equal(t, 0, count)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestStreamAllCourses(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewCourseRepository(mock)
+
+	now := time.Now()
+	mock.ExpectQuery("SELECT id, name, code, credits, description, faculty, term, created_at, updated_at FROM courses\\s+ORDER BY code, term").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "name", "code", "credits", "description", "faculty", "term", "created_at", "updated_at"}).
+			AddRow("id-1", "Test Course", "EECS2030", 3.0, nil, "LE", "F", now, now).
+			AddRow("id-2", "Test Course", "EECS2030", 3.0, nil, "LE", "W", now, now))
+
+	var ids []string
+	err = repo.StreamAll(context.Background(), func(c models.Course) error {
+		ids = append(ids, c.ID)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"id-1", "id-2"}, ids)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStreamAllCourses_StopsOnCallbackError(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewCourseRepository(mock)
+
+	now := time.Now()
+	mock.ExpectQuery("FROM courses\\s+ORDER BY code, term").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "name", "code", "credits", "description", "faculty", "term", "created_at", "updated_at"}).
+			AddRow("id-1", "Test Course", "EECS2030", 3.0, nil, "LE", "F", now, now).
+			AddRow("id-2", "Test Course", "EECS2030", 3.0, nil, "LE", "W", now, now))
+
+	clientGone := errors.New("client gone")
+	calls := 0
+	err = repo.StreamAll(context.Background(), func(c models.Course) error {
+		calls++
+		return clientGone
+	})
+	assert.ErrorIs(t, err, clientGone)
+	assert.Equal(t, 1, calls)
+}
+
+func TestPrefixTSQuery(t *testing.T) {
+	assert.Equal(t, "softw:* & eng:*", prefixTSQuery("softw eng"))
+	assert.Equal(t, "EECS:* & 2030:*", prefixTSQuery("EECS 2030"))
+	assert.Equal(t, "C:* & programming:*", prefixTSQuery("C++ (programming) | !"))
+	assert.Equal(t, "", prefixTSQuery("  &|! "))
+}
+
+func TestSearchCourses_InTerm(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewCourseRepository(mock)
+
+	mock.ExpectQuery(courseSearchQueryPattern).
+		WithArgs("EECS", "EECS:*", "%EECS%", 50, 0, "FW2025").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "name", "code", "credits", "description", "faculty", "term", "created_at", "updated_at"}))
+
+	courses, err := repo.Search(context.Background(), "EECS", "FW2025", 50, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, courses)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetPaginatedCourses_WithTermFilter(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewCourseRepository(mock)
+	faculty := "SC"
+	termID := "SU2026"
+
+	mock.ExpectQuery("FROM courses\\s+WHERE faculty = \\$1 AND EXISTS \\(SELECT 1 FROM sections s WHERE s.course_id = courses.id AND s.term_id = \\$2\\)\\s+ORDER BY code, term\\s+LIMIT \\$3 OFFSET \\$4").
+		WithArgs("SC", "SU2026", 20, 0).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "name", "code", "credits", "description", "faculty", "term", "created_at", "updated_at"}))
+
+	_, err = repo.GetPaginatedCourses(context.Background(), 1, 20, &faculty, nil, &termID, nil)
+	assert.NoError(t, err)
+
+	mock.ExpectQuery("SELECT COUNT\\(DISTINCT code\\) FROM courses\\s+WHERE EXISTS \\(SELECT 1 FROM sections s WHERE s.course_id = courses.id AND s.term_id = \\$1\\)").
+		WithArgs("SU2026").
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(7))
+
+	count, err := repo.GetCoursesCount(context.Background(), nil, nil, &termID)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSearchExactCode(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewCourseRepository(mock)
+	now := time.Now()
+
+	mock.ExpectQuery("FROM courses c\\s+WHERE REPLACE\\(LOWER\\(c.code\\), ' ', ''\\) = \\$1\\s+AND \\(\\$2 = '' OR EXISTS(.+)ORDER BY c.code, c.term").
+		WithArgs("eecs2030", "FW2025").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "name", "code", "credits", "description", "faculty", "term", "created_at", "updated_at"}).
+			AddRow("id-1", "Advanced OOP", "EECS2030", 3.0, nil, "LE", "F", now, now))
+
+	courses, err := repo.SearchExactCode(context.Background(), "EECS 2030", "FW2025")
+	assert.NoError(t, err)
+	assert.Len(t, courses, 1)
+	assert.Equal(t, "EECS2030", courses[0].Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCourseRepository_Patch(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewCourseRepository(mock)
+	now := time.Now().UTC()
+	name := "Advanced OOP"
+	patch := models.CoursePatch{Name: &name, Description: &dbtypes.NullString{}}
+	columns := []string{"applied", "id", "name", "code", "credits", "description", "faculty", "term", "created_at", "updated_at"}
+
+	// Only the columns the patch sets are written and audited
+	mock.ExpectQuery("UPDATE courses c SET name = \\$3::text, description = \\$4::text, updated_at = NOW\\(\\) "+
+		"FROM before WHERE c.id = before.id AND \\(\\$2::timestamp IS NULL OR c.updated_at = \\$2::timestamp\\)(.+)"+
+		"INSERT INTO audit_log(.+)VALUES \\('name', to_jsonb\\(b.name\\), to_jsonb\\(u.name\\)\\), "+
+		"\\('description', to_jsonb\\(b.description\\), to_jsonb\\(u.description\\)\\)\\) AS f").
+		WithArgs("c-1", &now, name, (*string)(nil)).
+		WillReturnRows(pgxmock.NewRows(columns).
+			AddRow(true, "c-1", name, "EECS2030", 3.0, nil, "LE", "F", now, now))
+
+	course, applied, err := repo.Patch(context.Background(), "c-1", patch, &now)
+	assert.NoError(t, err)
+	assert.True(t, applied)
+	assert.Equal(t, name, course.Name)
+
+	// A stale version gets the course as it is, unchanged
+	mock.ExpectQuery("UPDATE courses c SET name").
+		WithArgs("c-1", &now, name, (*string)(nil)).
+		WillReturnRows(pgxmock.NewRows(columns).
+			AddRow(false, "c-1", "OOP", "EECS2030", 3.0, nil, "LE", "F", now, now))
+
+	course, applied, err = repo.Patch(context.Background(), "c-1", patch, &now)
+	assert.NoError(t, err)
+	assert.False(t, applied)
+	assert.Equal(t, "OOP", course.Name)
+
+	mock.ExpectQuery("UPDATE courses c SET name").
+		WithArgs("missing", (*time.Time)(nil), name, (*string)(nil)).
+		WillReturnError(pgx.ErrNoRows)
+
+	course, _, err = repo.Patch(context.Background(), "missing", patch, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, course)
+
+	_, _, err = repo.Patch(context.Background(), "c-1", models.CoursePatch{}, nil)
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCourseRepository_Create(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewCourseRepository(mock)
+	now := time.Now().UTC()
+	req := models.CreateCourseRequest{Code: "EECS4088", Term: "F", Name: "Special Topics", Credits: 3, Faculty: "LE"}
+	columns := []string{"id", "name", "code", "credits", "description", "faculty", "term", "created_at", "updated_at"}
+
+	mock.ExpectQuery("INSERT INTO courses (.+) WHERE NOT EXISTS (.+) INSERT INTO audit_log (.+) 'create'").
+		WithArgs("EECS4088", "F", "Special Topics", 3.0, (*string)(nil), "LE").
+		WillReturnRows(pgxmock.NewRows(columns).
+			AddRow("c-1", "Special Topics", "EECS4088", 3.0, nil, "LE", "F", now, now))
+
+	course, err := repo.Create(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, "c-1", course.ID)
+
+	// Already in the term
+	mock.ExpectQuery("INSERT INTO courses").
+		WithArgs("EECS4088", "F", "Special Topics", 3.0, (*string)(nil), "LE").
+		WillReturnError(pgx.ErrNoRows)
+
+	course, err = repo.Create(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Nil(t, course)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCourseRepository_Delete(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewCourseRepository(mock)
+
+	mock.ExpectQuery("DELETE FROM instructors (.+) DELETE FROM courses WHERE id = \\$1 (.+) INSERT INTO audit_log (.+) 'delete'").
+		WithArgs("c-1").
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery("DELETE FROM courses").
+		WithArgs("missing").
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectQuery("DELETE FROM courses").
+		WithArgs("c-2").
+		WillReturnError(errors.New("db down"))
+
+	deleted, err := repo.Delete(context.Background(), "c-1")
+	assert.NoError(t, err)
+	assert.True(t, deleted)
+
+	deleted, err = repo.Delete(context.Background(), "missing")
+	assert.NoError(t, err)
+	assert.False(t, deleted)
+
+	_, err = repo.Delete(context.Background(), "c-2")
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCourseRepository_Merge(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewCourseRepository(mock)
+	now := time.Now()
+	columns := []string{"id", "name", "code", "credits", "description", "faculty", "term", "created_at", "updated_at", "sections", "blocks", "reviews"}
+
+	mock.ExpectQuery("UPDATE sections (.+) UPDATE blocks (.+) UPDATE reviews (.+) DELETE FROM courses (.+) INSERT INTO audit_log (.+) 'merge'").
+		WithArgs("c-dup", "c-1").
+		WillReturnRows(pgxmock.NewRows(columns).
+			AddRow("c-1", "Advanced Object Oriented Programming", "EECS2030", 3.0, dbtypes.NullString{}, "LE", "F", now, now, 2, 1, 4))
+	mock.ExpectQuery("UPDATE sections").
+		WithArgs("missing", "c-1").
+		WillReturnError(pgx.ErrNoRows)
+	mock.ExpectQuery("UPDATE sections").
+		WithArgs("c-dup", "c-2").
+		WillReturnError(errors.New("db down"))
+
+	merge, err := repo.Merge(context.Background(), "c-dup", "c-1")
+	assert.NoError(t, err)
+	if assert.NotNil(t, merge) {
+		assert.Equal(t, "c-1", merge.Course.ID)
+		assert.Equal(t, "EECS2030", merge.Course.Code)
+		assert.Equal(t, 2, merge.Sections)
+		assert.Equal(t, 1, merge.Blocks)
+		assert.Equal(t, 4, merge.Reviews)
+	}
+
+	merge, err = repo.Merge(context.Background(), "missing", "c-1")
+	assert.NoError(t, err)
+	assert.Nil(t, merge)
+
+	_, err = repo.Merge(context.Background(), "c-dup", "c-2")
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
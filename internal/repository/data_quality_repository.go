@@ -0,0 +1,222 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"yuplan/internal/models"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+type DataQualityRepositoryInterface interface {
+	ListCourseData(ctx context.Context) ([]models.CourseData, error)
+	RecordScores(ctx context.Context, scores []models.CourseQuality) error
+	ListScores(ctx context.Context, filter models.DataQualityFilter) ([]models.CourseQuality, error)
+	ListRuns(ctx context.Context, limit int) ([]models.DataQualityRun, error)
+}
+
+type dataQualityDB interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+type DataQualityRepository struct {
+	db dataQualityDB
+}
+
+func NewDataQualityRepository(db dataQualityDB) *DataQualityRepository {
+	return &DataQualityRepository{db: db}
+}
+
+// dataIssueColumns are the course_data_quality columns recording each issue.
+var dataIssueColumns = map[string]string{
+	models.DataIssueMissingDescription: "missing_description",
+	models.DataIssueNoSections:         "no_sections",
+	models.DataIssueUnparsedTimes:      "unparsed_times",
+	models.DataIssueMissingInstructors: "missing_instructors",
+}
+
+var dataQualityOrders = map[string]string{
+	models.DataQualitySortScore:     "q.score",
+	models.DataQualitySortScoreDesc: "q.score DESC",
+	models.DataQualitySortChange:    "q.score - p.score NULLS LAST",
+	models.DataQualitySortCode:      "q.course_code",
+}
+
+// ListCourseData reads what every course's score is based on, ordered by code and term.
+func (r *DataQualityRepository) ListCourseData(ctx context.Context) ([]models.CourseData, error) {
+	ctx, cancel := withDeadline(ctx, opAggregate)
+	defer cancel()
+
+	rows, err := r.db.Query(ctx,
+		`SELECT c.code, COALESCE(c.term, ''), COALESCE(TRIM(c.description), '') <> '',
+		        (SELECT COUNT(*) FROM sections s WHERE s.course_id = c.id)::int,
+		        (SELECT COUNT(*) FROM sections s
+		         WHERE s.course_id = c.id
+		           AND NOT EXISTS (SELECT 1 FROM instructors i WHERE i.section_id = s.id))::int,
+		        COALESCE((SELECT array_agg(a.times) FROM section_activities a
+		                  JOIN sections s ON s.id = a.section_id
+		                  WHERE s.course_id = c.id AND a.times <> ''), '{}')
+		 FROM courses c
+		 ORDER BY c.code, c.term`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query course data: %w", err)
+	}
+	defer rows.Close()
+
+	courses := []models.CourseData{}
+	for rows.Next() {
+		var c models.CourseData
+		if err := rows.Scan(&c.CourseCode, &c.Term, &c.HasDescription, &c.Sections, &c.SectionsWithoutInstructors, &c.Times); err != nil {
+			return nil, fmt.Errorf("scan course data: %w", err)
+		}
+		courses = append(courses, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate course data: %w", err)
+	}
+	return courses, nil
+}
+
+// RecordScores saves scores as one run, stamped with the current time, in one statement.
+func (r *DataQualityRepository) RecordScores(ctx context.Context, scores []models.CourseQuality) error {
+	ctx, cancel := withDeadline(ctx, opAggregate)
+	defer cancel()
+
+	codes := make([]string, len(scores))
+	terms := make([]string, len(scores))
+	values := make([]int32, len(scores))
+	issues := map[string][]bool{}
+	for _, issue := range models.DataIssues {
+		issues[issue] = make([]bool, len(scores))
+	}
+	for i, q := range scores {
+		codes[i] = q.CourseCode
+		terms[i] = q.Term
+		values[i] = int32(q.Score)
+		for _, issue := range q.Issues {
+			issues[issue][i] = true
+		}
+	}
+
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO course_data_quality (scored_at, course_code, term, score, missing_description, no_sections, unparsed_times, missing_instructors)
+		 SELECT NOW(), * FROM unnest($1::text[], $2::text[], $3::int[], $4::bool[], $5::bool[], $6::bool[], $7::bool[])`,
+		codes, terms, values,
+		issues[models.DataIssueMissingDescription], issues[models.DataIssueNoSections],
+		issues[models.DataIssueUnparsedTimes], issues[models.DataIssueMissingInstructors],
+	)
+	if err != nil {
+		return fmt.Errorf("record data quality: %w", err)
+	}
+	return nil
+}
+
+// ListScores returns the latest run's scores matching filter, each with the
+// course's score from the run before. Ties are broken by code and term.
+func (r *DataQualityRepository) ListScores(ctx context.Context, filter models.DataQualityFilter) ([]models.CourseQuality, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	order, ok := dataQualityOrders[filter.Sort]
+	if !ok {
+		return nil, fmt.Errorf("unknown data quality sort %q", filter.Sort)
+	}
+	issueFilter := "TRUE"
+	if filter.Issue != "" {
+		column, ok := dataIssueColumns[filter.Issue]
+		if !ok {
+			return nil, fmt.Errorf("unknown data quality issue %q", filter.Issue)
+		}
+		issueFilter = "q." + column
+	}
+
+	rows, err := r.db.Query(ctx,
+		`WITH latest AS (
+		     SELECT MAX(scored_at) AS at FROM course_data_quality
+		 ),
+		 previous AS (
+		     SELECT MAX(scored_at) AS at FROM course_data_quality WHERE scored_at < (SELECT at FROM latest)
+		 )
+		 SELECT q.course_code, q.term, q.score, q.missing_description, q.no_sections, q.unparsed_times, q.missing_instructors,
+		        p.score, q.scored_at
+		 FROM course_data_quality q
+		 LEFT JOIN course_data_quality p
+		     ON p.scored_at = (SELECT at FROM previous) AND p.course_code = q.course_code AND p.term = q.term
+		 WHERE q.scored_at = (SELECT at FROM latest)
+		   AND ($1 = '' OR upper(substring(q.course_code from '^[A-Za-z]+')) = $1)
+		   AND ($2 = '' OR q.term = $2)
+		   AND q.score <= $3
+		   AND `+issueFilter+`
+		 ORDER BY `+order+`, q.course_code, q.term
+		 LIMIT $4`,
+		filter.Department, filter.Term, filter.MaxScore, filter.Limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query data quality: %w", err)
+	}
+	defer rows.Close()
+
+	scores := []models.CourseQuality{}
+	for rows.Next() {
+		var q models.CourseQuality
+		var has [4]bool
+		if err := rows.Scan(&q.CourseCode, &q.Term, &q.Score, &has[0], &has[1], &has[2], &has[3], &q.PreviousScore, &q.ScoredAt); err != nil {
+			return nil, fmt.Errorf("scan data quality: %w", err)
+		}
+		q.Issues = []string{}
+		for i, issue := range models.DataIssues {
+			if has[i] {
+				q.Issues = append(q.Issues, issue)
+			}
+		}
+		scores = append(scores, q)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate data quality: %w", err)
+	}
+	return scores, nil
+}
+
+// ListRuns summarizes the latest limit runs, newest first.
+func (r *DataQualityRepository) ListRuns(ctx context.Context, limit int) ([]models.DataQualityRun, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	rows, err := r.db.Query(ctx,
+		`SELECT scored_at, COUNT(*)::int, AVG(score)::float8,
+		        COUNT(*) FILTER (WHERE missing_description)::int,
+		        COUNT(*) FILTER (WHERE no_sections)::int,
+		        COUNT(*) FILTER (WHERE unparsed_times)::int,
+		        COUNT(*) FILTER (WHERE missing_instructors)::int
+		 FROM course_data_quality
+		 GROUP BY scored_at
+		 ORDER BY scored_at DESC
+		 LIMIT $1`,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query data quality runs: %w", err)
+	}
+	defer rows.Close()
+
+	runs := []models.DataQualityRun{}
+	for rows.Next() {
+		var run models.DataQualityRun
+		var counts [4]int
+		if err := rows.Scan(&run.ScoredAt, &run.Courses, &run.AverageScore, &counts[0], &counts[1], &counts[2], &counts[3]); err != nil {
+			return nil, fmt.Errorf("scan data quality run: %w", err)
+		}
+		run.Issues = make(map[string]int, len(models.DataIssues))
+		for i, issue := range models.DataIssues {
+			run.Issues[issue] = counts[i]
+		}
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate data quality runs: %w", err)
+	}
+	return runs, nil
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataQualityRepository_ListCourseData(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewDataQualityRepository(mock)
+
+	mock.ExpectQuery("SELECT c.code, (.+) FROM courses c ORDER BY c.code, c.term").
+		WillReturnRows(pgxmock.NewRows([]string{"code", "term", "has_description", "sections", "without_instructors", "times"}).
+			AddRow("EECS2030", "F", true, 2, 1, []string{`[{"day":"M"}]`}))
+
+	courses, err := repo.ListCourseData(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []models.CourseData{{
+		CourseCode: "EECS2030", Term: "F", HasDescription: true, Sections: 2, SectionsWithoutInstructors: 1,
+		Times: []string{`[{"day":"M"}]`},
+	}}, courses)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDataQualityRepository_RecordScores(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewDataQualityRepository(mock)
+
+	mock.ExpectExec("INSERT INTO course_data_quality (.+) SELECT NOW\\(\\), \\* FROM unnest").
+		WithArgs([]string{"EECS2030", "EECS3311"}, []string{"F", "W"}, []int32{100, 35},
+			[]bool{false, true}, []bool{false, false}, []bool{false, true}, []bool{false, true}).
+		WillReturnResult(pgxmock.NewResult("INSERT", 2))
+
+	err = repo.RecordScores(context.Background(), []models.CourseQuality{
+		{CourseCode: "EECS2030", Term: "F", Score: 100},
+		{CourseCode: "EECS3311", Term: "W", Score: 35, Issues: []string{
+			models.DataIssueMissingDescription, models.DataIssueUnparsedTimes, models.DataIssueMissingInstructors,
+		}},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDataQualityRepository_ListScores(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewDataQualityRepository(mock)
+	now := time.Now()
+	previous := 100
+
+	mock.ExpectQuery("AND q.no_sections ORDER BY q.score - p.score NULLS LAST, q.course_code, q.term LIMIT \\$4").
+		WithArgs("EECS", "", 80, 50).
+		WillReturnRows(pgxmock.NewRows([]string{"code", "term", "score", "d", "s", "t", "i", "previous", "scored_at"}).
+			AddRow("EECS1001", "F", 50, false, true, false, false, &previous, now).
+			AddRow("EECS1002", "F", 30, true, true, false, false, nil, now))
+
+	scores, err := repo.ListScores(context.Background(), models.DataQualityFilter{
+		Department: "EECS", Issue: models.DataIssueNoSections, MaxScore: 80, Sort: models.DataQualitySortChange, Limit: 50,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, scores, 2)
+	assert.Equal(t, []string{models.DataIssueNoSections}, scores[0].Issues)
+	assert.Equal(t, 100, *scores[0].PreviousScore)
+	assert.Equal(t, []string{models.DataIssueMissingDescription, models.DataIssueNoSections}, scores[1].Issues)
+	assert.Nil(t, scores[1].PreviousScore)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	_, err = repo.ListScores(context.Background(), models.DataQualityFilter{Sort: "name"})
+	assert.Error(t, err)
+	_, err = repo.ListScores(context.Background(), models.DataQualityFilter{Sort: models.DataQualitySortScore, Issue: "typos"})
+	assert.Error(t, err)
+}
+
+func TestDataQualityRepository_ListRuns(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewDataQualityRepository(mock)
+	now := time.Now()
+
+	mock.ExpectQuery("FROM course_data_quality GROUP BY scored_at ORDER BY scored_at DESC LIMIT \\$1").
+		WithArgs(10).
+		WillReturnRows(pgxmock.NewRows([]string{"scored_at", "courses", "avg", "d", "s", "t", "i"}).
+			AddRow(now, 120, 87.5, 10, 4, 2, 30))
+	mock.ExpectQuery("FROM course_data_quality").WithArgs(5).WillReturnError(errors.New("db down"))
+
+	runs, err := repo.ListRuns(context.Background(), 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.DataQualityRun{{
+		ScoredAt: now, Courses: 120, AverageScore: 87.5,
+		Issues: map[string]int{
+			models.DataIssueMissingDescription: 10, models.DataIssueNoSections: 4,
+			models.DataIssueUnparsedTimes: 2, models.DataIssueMissingInstructors: 30,
+		},
+	}}, runs)
+
+	_, err = repo.ListRuns(context.Background(), 5)
+	assert.ErrorContains(t, err, "query data quality runs")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/jackc/pgx/v4"
+)
+
+type DepartmentRepositoryInterface interface {
+	GetStats(ctx context.Context, department string, since time.Time) (*models.DepartmentStats, error)
+}
+
+type departmentDB interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+type DepartmentRepository struct {
+	db departmentDB
+}
+
+func NewDepartmentRepository(db departmentDB) *DepartmentRepository {
+	return &DepartmentRepository{db: db}
+}
+
+// GetStats rolls up the published reviews created since the given time across
+// department's courses, or returns nil if it has no courses. A department is
+// the letters of a course code.
+func (r *DepartmentRepository) GetStats(ctx context.Context, department string, since time.Time) (*models.DepartmentStats, error) {
+	ctx, cancel := withDeadline(ctx, opAggregate)
+	defer cancel()
+
+	// The LIKE lets the code indexes narrow the scan; the substring keeps
+	// EE from matching EECS
+	rows, err := r.db.Query(ctx,
+		`WITH course AS (
+		     SELECT DISTINCT ON (code) code, name FROM courses
+		     WHERE code LIKE $1::text || '%' AND substring(code from '^[A-Za-z]+') = $1::text
+		     ORDER BY code, name
+		 ),
+		 review AS (
+		     SELECT course_code, liked, difficulty FROM reviews
+		     WHERE course_code LIKE $1::text || '%' AND substring(course_code from '^[A-Za-z]+') = $1::text
+		       AND created_at >= $2 AND `+publishedFilter+`
+		 )
+		 SELECT course.code, course.name,
+		        COUNT(review.course_code)::int,
+		        COUNT(*) FILTER (WHERE review.liked)::int,
+		        COALESCE(SUM(review.difficulty), 0)::int
+		 FROM course
+		 LEFT JOIN review ON review.course_code = course.code
+		 GROUP BY course.code, course.name
+		 ORDER BY course.code`,
+		department, since,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query department courses: %w", err)
+	}
+	defer rows.Close()
+
+	courses := []models.DepartmentCourseStats{}
+	for rows.Next() {
+		var c models.DepartmentCourseStats
+		if err := rows.Scan(&c.Code, &c.Name, &c.Reviews, &c.Likes, &c.DifficultyTotal); err != nil {
+			return nil, fmt.Errorf("scan department course: %w", err)
+		}
+		courses = append(courses, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate department courses: %w", err)
+	}
+	if len(courses) == 0 {
+		return nil, nil
+	}
+	return models.RollUpDepartment(department, courses), nil
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+	"yuplan/internal/cache"
+
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var departmentCourseColumns = []string{"code", "name", "reviews", "likes", "difficulty_total"}
+
+func TestDepartmentRepository_GetStats(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewDepartmentRepository(mock)
+	since := time.Now().AddDate(-3, 0, 0)
+
+	mock.ExpectQuery("WITH course AS (.+)substring\\(code from '\\^\\[A-Za-z\\]\\+'\\) = \\$1(.+)created_at >= \\$2 AND \\(moderation = 'approved'").
+		WithArgs("EECS", since).
+		WillReturnRows(pgxmock.NewRows(departmentCourseColumns).
+			AddRow("EECS1012", "Net-centric Introduction to Computing", 4, 3, 10).
+			AddRow("EECS2030", "Advanced Object Oriented Programming", 0, 0, 0))
+
+	stats, err := repo.GetStats(context.Background(), "EECS", since)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, stats.Courses)
+	assert.Equal(t, 1, stats.ReviewedCourses)
+	assert.Equal(t, 4, stats.Reviews)
+	assert.Equal(t, 2.5, *stats.AvgDifficulty)
+	assert.Equal(t, 75, *stats.LikePercentage)
+	assert.Len(t, stats.MostLiked, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDepartmentRepository_GetStats_UnknownDepartment(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewDepartmentRepository(mock)
+
+	mock.ExpectQuery("WITH course AS").WillReturnRows(pgxmock.NewRows(departmentCourseColumns))
+
+	stats, err := repo.GetStats(context.Background(), "ZZZ", time.Now())
+	assert.NoError(t, err)
+	assert.Nil(t, stats)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCachedDepartmentRepository_GetStats(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewCachedDepartmentRepository(NewDepartmentRepository(mock), cache.NewStore(cache.NewMemory(100), time.Minute))
+
+	mock.ExpectQuery("WITH course AS").
+		WillReturnRows(pgxmock.NewRows(departmentCourseColumns).AddRow("MATH1090", "Introduction to Logic", 3, 1, 12))
+
+	// The second call, a moment later, is answered from the cache
+	for i := 0; i < 2; i++ {
+		stats, err := repo.GetStats(context.Background(), "MATH", time.Now().AddDate(-3, 0, 0))
+		assert.NoError(t, err)
+		assert.Equal(t, 3, stats.Reviews)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
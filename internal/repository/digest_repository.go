@@ -0,0 +1,267 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+type DigestRepositoryInterface interface {
+	DepartmentExists(ctx context.Context, department string) (bool, error)
+	Subscribe(ctx context.Context, email, department, frequency string) error
+	Unsubscribe(ctx context.Context, email, department string) (bool, error)
+	GetSubscriber(ctx context.Context, email string) (*models.DigestSubscriber, error)
+	SetFrequency(ctx context.Context, email, frequency string) (bool, error)
+	DetectCatalogChanges(ctx context.Context) (snapshotted bool, changes int, cacheInvalidated bool, err error)
+	ListDueDigests(ctx context.Context, now time.Time) ([]models.Digest, error)
+	MarkDigestSent(ctx context.Context, email string, through time.Time) error
+}
+
+type digestDB interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+type DigestRepository struct {
+	db digestDB
+}
+
+func NewDigestRepository(db digestDB) *DigestRepository {
+	return &DigestRepository{db: db}
+}
+
+// DepartmentExists reports whether any course code starts with department.
+func (r *DigestRepository) DepartmentExists(ctx context.Context, department string) (bool, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	var exists bool
+	err := r.db.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM courses WHERE code LIKE $1::text || '%' AND substring(code from '^[A-Za-z]+') = $1::text)`,
+		department,
+	).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("check department: %w", err)
+	}
+	return exists, nil
+}
+
+// Subscribe follows department for email, creating the subscriber if needed.
+// An empty frequency keeps the subscriber's current one, or DigestDaily for a
+// new subscriber.
+func (r *DigestRepository) Subscribe(ctx context.Context, email, department, frequency string) error {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	_, err := r.db.Exec(ctx,
+		`WITH subscriber AS (
+		     INSERT INTO digest_subscribers (email, frequency)
+		     VALUES ($1, COALESCE(NULLIF($3::text, ''), '`+models.DigestDaily+`'))
+		     ON CONFLICT (email) DO UPDATE SET frequency = COALESCE(NULLIF($3::text, ''), digest_subscribers.frequency)
+		     RETURNING email
+		 )
+		 INSERT INTO department_subscriptions (email, department)
+		 SELECT email, $2 FROM subscriber
+		 ON CONFLICT DO NOTHING`,
+		email, department, frequency,
+	)
+	if err != nil {
+		return fmt.Errorf("subscribe to department: %w", err)
+	}
+	return nil
+}
+
+// Unsubscribe stops email following department, reporting false if it wasn't.
+func (r *DigestRepository) Unsubscribe(ctx context.Context, email, department string) (bool, error) {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	tag, err := r.db.Exec(ctx,
+		`DELETE FROM department_subscriptions WHERE email = $1 AND department = $2`,
+		email, department,
+	)
+	if err != nil {
+		return false, fmt.Errorf("unsubscribe from department: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// GetSubscriber returns email's frequency and departments, or nil if it never subscribed.
+func (r *DigestRepository) GetSubscriber(ctx context.Context, email string) (*models.DigestSubscriber, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	var s models.DigestSubscriber
+	err := r.db.QueryRow(ctx,
+		`SELECT s.email, s.frequency, s.last_sent_at,
+		        COALESCE(array_agg(d.department ORDER BY d.department) FILTER (WHERE d.department IS NOT NULL), '{}')
+		 FROM digest_subscribers s
+		 LEFT JOIN department_subscriptions d ON d.email = s.email
+		 WHERE s.email = $1
+		 GROUP BY s.email`,
+		email,
+	).Scan(&s.Email, &s.Frequency, &s.LastSentAt, &s.Departments)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query digest subscriber: %w", err)
+	}
+	return &s, nil
+}
+
+// SetFrequency changes how often email gets digests, reporting false if it never subscribed.
+func (r *DigestRepository) SetFrequency(ctx context.Context, email, frequency string) (bool, error) {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	tag, err := r.db.Exec(ctx,
+		`UPDATE digest_subscribers SET frequency = $2 WHERE email = $1`,
+		email, frequency,
+	)
+	if err != nil {
+		return false, fmt.Errorf("set digest frequency: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// DetectCatalogChanges compares the catalog with the snapshot taken after the
+// previous seed and records the differences, then retakes the snapshot. It
+// does nothing until _seed_checksum changes, which seed.sh writes last, so a
+// seed still in progress is never compared. The first snapshot records no
+// changes, since there is nothing to compare it with. snapshotted reports
+// whether a new seed was found, and cacheInvalidated whether its load already
+// dropped the cached reads it changed.
+func (r *DigestRepository) DetectCatalogChanges(ctx context.Context) (snapshotted bool, changes int, cacheInvalidated bool, err error) {
+	ctx, cancel := withDeadline(ctx, opAggregate)
+	defer cancel()
+
+	err = r.db.QueryRow(ctx,
+		`WITH previous AS (
+		     SELECT checksum FROM catalog_snapshot_state LIMIT 1
+		 ),
+		 seed AS (
+		     SELECT checksum, cache_invalidated, EXISTS (SELECT 1 FROM previous) AS compare
+		     FROM _seed_checksum
+		     WHERE checksum IS DISTINCT FROM (SELECT checksum FROM previous)
+		     LIMIT 1
+		 ),
+		 catalog AS (
+		     SELECT c.code AS course_code, c.term, COALESCE(s.letter, '') AS letter,
+		            COALESCE(string_agg(DISTINCT NULLIF(TRIM(concat_ws(' ', i.first_name, i.last_name)), ''), ', '
+		                                ORDER BY NULLIF(TRIM(concat_ws(' ', i.first_name, i.last_name)), '')), '') AS instructors
+		     FROM courses c
+		     LEFT JOIN sections s ON s.course_id = c.id
+		     LEFT JOIN instructors i ON i.section_id = s.id
+		     WHERE EXISTS (SELECT 1 FROM seed)
+		     GROUP BY c.code, c.term, COALESCE(s.letter, '')
+		 ),
+		 diff AS (
+		     SELECT '`+models.CatalogNewCourse+`' AS kind, n.course_code, n.term, '' AS section, NULL::text AS before, NULL::text AS after
+		     FROM (SELECT DISTINCT course_code, term FROM catalog) n
+		     WHERE NOT EXISTS (SELECT 1 FROM catalog_snapshot p WHERE p.course_code = n.course_code AND p.term = n.term)
+		     UNION ALL
+		     SELECT '`+models.CatalogRemovedSection+`', p.course_code, p.term, p.letter, p.instructors, NULL
+		     FROM catalog_snapshot p
+		     WHERE p.letter <> '' AND EXISTS (SELECT 1 FROM seed)
+		       AND NOT EXISTS (SELECT 1 FROM catalog c WHERE c.course_code = p.course_code AND c.term = p.term AND c.letter = p.letter)
+		     UNION ALL
+		     SELECT '`+models.CatalogInstructorChange+`', c.course_code, c.term, c.letter, p.instructors, c.instructors
+		     FROM catalog c
+		     JOIN catalog_snapshot p ON p.course_code = c.course_code AND p.term = c.term AND p.letter = c.letter
+		     WHERE c.letter <> '' AND c.instructors <> p.instructors
+		 ),
+		 recorded AS (
+		     INSERT INTO catalog_changes (department, kind, course_code, term, section, before, after)
+		     SELECT upper(substring(course_code from '^[A-Za-z]+')), kind, course_code, term, section, before, after
+		     FROM diff
+		     WHERE (SELECT compare FROM seed)
+		     RETURNING 1
+		 ),
+		 cleared AS (
+		     DELETE FROM catalog_snapshot WHERE EXISTS (SELECT 1 FROM seed)
+		 ),
+		 saved AS (
+		     INSERT INTO catalog_snapshot (course_code, term, letter, instructors)
+		     SELECT course_code, term, letter, instructors FROM catalog
+		 ),
+		 cleared_state AS (
+		     DELETE FROM catalog_snapshot_state WHERE EXISTS (SELECT 1 FROM seed)
+		 ),
+		 saved_state AS (
+		     INSERT INTO catalog_snapshot_state (checksum)
+		     SELECT checksum FROM seed
+		 )
+		 SELECT EXISTS (SELECT 1 FROM seed), (SELECT COUNT(*) FROM recorded),
+		        COALESCE((SELECT cache_invalidated FROM seed), false)`,
+	).Scan(&snapshotted, &changes, &cacheInvalidated)
+	if err != nil {
+		return false, 0, false, fmt.Errorf("detect catalog changes: %w", err)
+	}
+	return snapshotted, changes, cacheInvalidated, nil
+}
+
+// ListDueDigests returns, for each subscriber whose frequency has come round
+// by now, the changes in the departments it follows since its last digest.
+// Subscribers with nothing new are left out.
+func (r *DigestRepository) ListDueDigests(ctx context.Context, now time.Time) ([]models.Digest, error) {
+	ctx, cancel := withDeadline(ctx, opAggregate)
+	defer cancel()
+
+	rows, err := r.db.Query(ctx,
+		`SELECT s.email, ch.id, ch.department, ch.kind, ch.course_code, ch.term, ch.section, ch.before, ch.after, ch.detected_at
+		 FROM digest_subscribers s
+		 JOIN department_subscriptions d ON d.email = s.email
+		 JOIN catalog_changes ch ON ch.department = d.department AND ch.detected_at > GREATEST(s.last_sent_at, d.created_at)
+		 WHERE s.last_sent_at <= $1::timestamp - CASE s.frequency
+		           WHEN '`+models.DigestDaily+`' THEN INTERVAL '1 day'
+		           WHEN '`+models.DigestWeekly+`' THEN INTERVAL '7 days'
+		           ELSE INTERVAL '0'
+		       END
+		 ORDER BY s.email, ch.department, ch.course_code, ch.term, ch.kind, ch.section`,
+		now,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query due digests: %w", err)
+	}
+	defer rows.Close()
+
+	digests := []models.Digest{}
+	for rows.Next() {
+		var email string
+		var c models.CatalogChange
+		if err := rows.Scan(&email, &c.ID, &c.Department, &c.Kind, &c.CourseCode, &c.Term, &c.Section, &c.Before, &c.After, &c.DetectedAt); err != nil {
+			return nil, fmt.Errorf("scan digest change: %w", err)
+		}
+		if n := len(digests); n == 0 || digests[n-1].Email != email {
+			digests = append(digests, models.Digest{Email: email})
+		}
+		last := &digests[len(digests)-1]
+		last.Changes = append(last.Changes, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate due digests: %w", err)
+	}
+	return digests, nil
+}
+
+// MarkDigestSent moves email's cursor to through, the latest change it was sent.
+func (r *DigestRepository) MarkDigestSent(ctx context.Context, email string, through time.Time) error {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	_, err := r.db.Exec(ctx,
+		`UPDATE digest_subscribers SET last_sent_at = GREATEST(last_sent_at, $2) WHERE email = $1`,
+		email, through,
+	)
+	if err != nil {
+		return fmt.Errorf("mark digest sent: %w", err)
+	}
+	return nil
+}
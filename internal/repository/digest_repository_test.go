@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDigestRepository_Subscribe(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewDigestRepository(mock)
+
+	mock.ExpectExec("INSERT INTO digest_subscribers (.+) INSERT INTO department_subscriptions").
+		WithArgs("a@yorku.ca", "EECS", "").
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	assert.NoError(t, repo.Subscribe(context.Background(), "a@yorku.ca", "EECS", ""))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDigestRepository_Unsubscribe(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewDigestRepository(mock)
+
+	mock.ExpectExec("DELETE FROM department_subscriptions").
+		WithArgs("a@yorku.ca", "EECS").
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+
+	removed, err := repo.Unsubscribe(context.Background(), "a@yorku.ca", "EECS")
+	assert.NoError(t, err)
+	assert.False(t, removed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDigestRepository_GetSubscriber(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewDigestRepository(mock)
+	now := time.Now()
+
+	mock.ExpectQuery("FROM digest_subscribers s LEFT JOIN department_subscriptions").
+		WithArgs("a@yorku.ca").
+		WillReturnRows(pgxmock.NewRows([]string{"email", "frequency", "last_sent_at", "departments"}).
+			AddRow("a@yorku.ca", models.DigestWeekly, now, []string{"EECS", "MATH"}))
+	mock.ExpectQuery("FROM digest_subscribers").WillReturnError(pgx.ErrNoRows)
+
+	subscriber, err := repo.GetSubscriber(context.Background(), "a@yorku.ca")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"EECS", "MATH"}, subscriber.Departments)
+	assert.Equal(t, models.DigestWeekly, subscriber.Frequency)
+
+	subscriber, err = repo.GetSubscriber(context.Background(), "b@yorku.ca")
+	assert.NoError(t, err)
+	assert.Nil(t, subscriber)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDigestRepository_DetectCatalogChanges(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewDigestRepository(mock)
+
+	mock.ExpectQuery("FROM _seed_checksum (.+) INSERT INTO catalog_changes (.+) DELETE FROM catalog_snapshot (.+) INSERT INTO catalog_snapshot_state").
+		WillReturnRows(pgxmock.NewRows([]string{"snapshotted", "changes", "cache_invalidated"}).AddRow(true, 3, true))
+
+	snapshotted, changes, cacheInvalidated, err := repo.DetectCatalogChanges(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, snapshotted)
+	assert.Equal(t, 3, changes)
+	assert.True(t, cacheInvalidated)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDigestRepository_ListDueDigests(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewDigestRepository(mock)
+	now := time.Now()
+	before, after := "Jane Smith", "John Doe"
+
+	mock.ExpectQuery("FROM digest_subscribers s JOIN department_subscriptions d (.+) JOIN catalog_changes").
+		WithArgs(now).
+		WillReturnRows(pgxmock.NewRows([]string{"email", "id", "department", "kind", "course_code", "term", "section", "before", "after", "detected_at"}).
+			AddRow("a@yorku.ca", int64(1), "EECS", models.CatalogNewCourse, "EECS4000", models.TermFall, "", nil, nil, now).
+			AddRow("a@yorku.ca", int64(2), "EECS", models.CatalogInstructorChange, "EECS2030", models.TermFall, "A", &before, &after, now).
+			AddRow("b@yorku.ca", int64(1), "EECS", models.CatalogNewCourse, "EECS4000", models.TermFall, "", nil, nil, now))
+
+	digests, err := repo.ListDueDigests(context.Background(), now)
+	assert.NoError(t, err)
+	if assert.Len(t, digests, 2) {
+		assert.Len(t, digests[0].Changes, 2)
+		assert.Equal(t, "John Doe", digests[0].Changes[1].After.String)
+		assert.Equal(t, "b@yorku.ca", digests[1].Email)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDigestRepository_Errors(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewDigestRepository(mock)
+
+	mock.ExpectQuery("FROM _seed_checksum").WillReturnError(errors.New("db down"))
+	mock.ExpectExec("UPDATE digest_subscribers SET last_sent_at").WillReturnError(errors.New("db down"))
+
+	_, _, _, err = repo.DetectCatalogChanges(context.Background())
+	assert.Error(t, err)
+	assert.Error(t, repo.MarkDigestSent(context.Background(), "a@yorku.ca", time.Now()))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"yuplan/internal/models"
+
+	"github.com/jackc/pgx/v4"
+)
+
+type ExportRepositoryInterface interface {
+	StreamReviews(ctx context.Context, fn func(models.Review) error) error
+	StreamAuditLog(ctx context.Context, fn func(models.AuditEntry) error) error
+}
+
+type exportDB interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// ExportRepository reads full tables row by row, so rows are never all
+// decoded at once. Exporter still keeps each compressed snapshot in memory
+// until it is stored.
+type ExportRepository struct {
+	db exportDB
+}
+
+func NewExportRepository(db exportDB) *ExportRepository {
+	return &ExportRepository{db: db}
+}
+
+func (r *ExportRepository) StreamReviews(ctx context.Context, fn func(models.Review) error) error {
+	ctx, cancel := withDeadline(ctx, opExport)
+	defer cancel()
+
+	rows, err := r.db.Query(
+		ctx,
+		`SELECT id, course_code, author_name, liked, difficulty, real_world_relevance, review_text, created_at, updated_at
+		 FROM reviews
+		 ORDER BY created_at, id`,
+	)
+	if err != nil {
+		return fmt.Errorf("query reviews for export: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var review models.Review
+		if err := rows.Scan(&review.ID, &review.CourseCode, &review.AuthorName, &review.Liked, &review.Difficulty, &review.RealWorldRelevance, &review.ReviewText, &review.CreatedAt, &review.UpdatedAt); err != nil {
+			return fmt.Errorf("scan review for export: %w", err)
+		}
+		if err := fn(review); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate reviews for export: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ExportRepository) StreamAuditLog(ctx context.Context, fn func(models.AuditEntry) error) error {
+	ctx, cancel := withDeadline(ctx, opExport)
+	defer cancel()
+
+	rows, err := r.db.Query(
+		ctx,
+		`SELECT id, entity, entity_id, action, details, created_at
+		 FROM audit_log
+		 ORDER BY id`,
+	)
+	if err != nil {
+		return fmt.Errorf("query audit_log for export: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry models.AuditEntry
+		var details []byte
+		if err := rows.Scan(&entry.ID, &entry.Entity, &entry.EntityID, &entry.Action, &details, &entry.CreatedAt); err != nil {
+			return fmt.Errorf("scan audit entry for export: %w", err)
+		}
+		entry.Details = details
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate audit_log for export: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportRepository_StreamReviews(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewExportRepository(mock)
+	now := time.Now()
+	text := "Great course!"
+
+	mock.ExpectQuery("SELECT id, course_code, author_name, liked, difficulty, real_world_relevance, review_text, created_at, updated_at\\s+FROM reviews").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "course_code", "author_name", "liked", "difficulty", "real_world_relevance", "review_text", "created_at", "updated_at"}).
+			AddRow("r1", "EECS2030", nil, true, 3, 4, &text, now, now).
+			AddRow("r2", "EECS2030", nil, false, 5, 2, nil, now, now))
+
+	var got []models.Review
+	err = repo.StreamReviews(context.Background(), func(r models.Review) error {
+		got = append(got, r)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, got, 2)
+	assert.Equal(t, "r1", got[0].ID)
+	assert.Empty(t, got[0].Email)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExportRepository_StreamReviews_CallbackErrorStops(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewExportRepository(mock)
+	now := time.Now()
+
+	mock.ExpectQuery("FROM reviews").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "course_code", "author_name", "liked", "difficulty", "real_world_relevance", "review_text", "created_at", "updated_at"}).
+			AddRow("r1", "EECS2030", nil, true, 3, 4, nil, now, now).
+			AddRow("r2", "EECS2030", nil, true, 3, 4, nil, now, now))
+
+	calls := 0
+	stop := errors.New("stop")
+	err = repo.StreamReviews(context.Background(), func(r models.Review) error {
+		calls++
+		return stop
+	})
+
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, 1, calls)
+}
+
+func TestExportRepository_StreamAuditLog(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewExportRepository(mock)
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT id, entity, entity_id, action, details, created_at\\s+FROM audit_log").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "entity", "entity_id", "action", "details", "created_at"}).
+			AddRow(int64(1), "review", "r1", "insert", []byte(`{"liked":true}`), now))
+
+	var got []models.AuditEntry
+	err = repo.StreamAuditLog(context.Background(), func(e models.AuditEntry) error {
+		got = append(got, e)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, got, 1)
+	assert.JSONEq(t, `{"liked":true}`, string(got[0].Details))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExportRepository_StreamAuditLog_QueryError(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewExportRepository(mock)
+	mock.ExpectQuery("FROM audit_log").WillReturnError(errors.New("db down"))
+
+	err = repo.StreamAuditLog(context.Background(), func(e models.AuditEntry) error { return nil })
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "query audit_log for export")
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+type FeedRepositoryInterface interface {
+	PersonalizedCourses(ctx context.Context, email string, seenSince time.Time, limit int) ([]models.Course, error)
+	RecordSeen(ctx context.Context, email string, courseCodes []string) error
+}
+
+type feedDB interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+type FeedRepository struct {
+	db feedDB
+}
+
+func NewFeedRepository(db feedDB) *FeedRepository {
+	return &FeedRepository{db: db}
+}
+
+// PersonalizedCourses returns up to limit random courses the reviewer hasn't
+// reviewed or seen since seenSince. Courses in departments the reviewer has
+// reviewed or seen are three times as likely to be picked as others, using
+// weighted sampling without replacement (key -ln(u)/weight, smallest first).
+func (r *FeedRepository) PersonalizedCourses(ctx context.Context, email string, seenSince time.Time, limit int) ([]models.Course, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	rows, err := r.db.Query(ctx,
+		`WITH engaged AS (
+		     SELECT REPLACE(UPPER(course_code), ' ', '') AS code FROM reviews WHERE email = $1
+		     UNION
+		     SELECT course_code FROM course_views WHERE email = $1 AND seen_at >= $2
+		 ),
+		 departments AS (
+		     SELECT DISTINCT substring(code FROM '^[A-Z]+') AS department FROM engaged
+		 )
+		 SELECT id, name, code, credits, description, faculty, term, created_at, updated_at
+		 FROM courses
+		 WHERE code NOT IN (SELECT code FROM engaged)
+		 ORDER BY -ln(1 - random()) / CASE
+		     WHEN substring(code FROM '^[A-Z]+') IN (SELECT department FROM departments) THEN 3
+		     ELSE 1
+		 END
+		 LIMIT $3`,
+		email, seenSince, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query personalized courses: %w", err)
+	}
+	defer rows.Close()
+
+	courses := make([]models.Course, 0)
+	for rows.Next() {
+		var c models.Course
+		if err := rows.Scan(&c.ID, &c.Name, &c.Code, &c.Credits, &c.Description, &c.Faculty, &c.Term, &c.CreatedAt, &c.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan course: %w", err)
+		}
+		courses = append(courses, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate personalized courses: %w", err)
+	}
+	return courses, nil
+}
+
+// RecordSeen marks courses as seen by the reviewer now, restarting the clock
+// on ones seen before.
+func (r *FeedRepository) RecordSeen(ctx context.Context, email string, courseCodes []string) error {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO course_views (email, course_code)
+		 SELECT DISTINCT $1, code FROM unnest($2::text[]) AS code
+		 ON CONFLICT (email, course_code) DO UPDATE SET seen_at = NOW()`,
+		email, courseCodes,
+	)
+	if err != nil {
+		return fmt.Errorf("record seen courses: %w", err)
+	}
+	return nil
+}
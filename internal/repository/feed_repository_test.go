@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeedRepository_PersonalizedCourses(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewFeedRepository(mock)
+	since := time.Date(2026, 9, 16, 0, 0, 0, 0, time.UTC)
+	now := time.Now()
+
+	mock.ExpectQuery("WITH engaged AS (.+) FROM course_views (.+) WHERE code NOT IN").
+		WithArgs("student@my.yorku.ca", since, 10).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "name", "code", "credits", "description", "faculty", "term", "created_at", "updated_at"}).
+			AddRow("c-1", "Advanced Object Oriented Programming", "EECS2030", 3.0, nil, "LE", "F", now, now))
+
+	courses, err := repo.PersonalizedCourses(context.Background(), "student@my.yorku.ca", since, 10)
+	assert.NoError(t, err)
+	assert.Len(t, courses, 1)
+	assert.Equal(t, "EECS2030", courses[0].Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFeedRepository_PersonalizedCourses_Error(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewFeedRepository(mock)
+
+	mock.ExpectQuery("WITH engaged AS").WillReturnError(errors.New("db down"))
+
+	_, err = repo.PersonalizedCourses(context.Background(), "student@my.yorku.ca", time.Now(), 10)
+	assert.ErrorContains(t, err, "query personalized courses")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFeedRepository_RecordSeen(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewFeedRepository(mock)
+
+	mock.ExpectExec("INSERT INTO course_views (.+) ON CONFLICT \\(email, course_code\\) DO UPDATE SET seen_at = NOW\\(\\)").
+		WithArgs("student@my.yorku.ca", []string{"EECS2030", "MATH1090"}).
+		WillReturnResult(pgxmock.NewResult("INSERT", 2))
+
+	err = repo.RecordSeen(context.Background(), "student@my.yorku.ca", []string{"EECS2030", "MATH1090"})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
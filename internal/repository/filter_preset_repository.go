@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"yuplan/internal/id"
+	"yuplan/internal/models"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+// ErrDuplicatePresetName is returned by Create and Update when the email
+// already has another preset with that name.
+var ErrDuplicatePresetName = errors.New("a filter preset with this name already exists")
+
+type FilterPresetRepositoryInterface interface {
+	List(ctx context.Context, email string) ([]models.FilterPreset, error)
+	Get(ctx context.Context, id string) (*models.FilterPreset, error)
+	Create(ctx context.Context, preset *models.FilterPreset) error
+	Update(ctx context.Context, preset *models.FilterPreset) (bool, error)
+	Delete(ctx context.Context, id, email string) (bool, error)
+}
+
+type filterPresetDB interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+type FilterPresetRepository struct {
+	db filterPresetDB
+}
+
+func NewFilterPresetRepository(db filterPresetDB) *FilterPresetRepository {
+	return &FilterPresetRepository{db: db}
+}
+
+const filterPresetColumns = `id, email, name, faculty, course_code_range, term_id, created_at, updated_at`
+
+// List returns an email's presets by name.
+func (r *FilterPresetRepository) List(ctx context.Context, email string) ([]models.FilterPreset, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	rows, err := r.db.Query(ctx,
+		`SELECT `+filterPresetColumns+`
+		 FROM filter_presets
+		 WHERE email = $1
+		 ORDER BY name`,
+		email,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query filter presets: %w", err)
+	}
+	defer rows.Close()
+
+	presets := []models.FilterPreset{}
+	for rows.Next() {
+		var p models.FilterPreset
+		if err := scanFilterPreset(rows, &p); err != nil {
+			return nil, fmt.Errorf("scan filter preset: %w", err)
+		}
+		presets = append(presets, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate filter presets: %w", err)
+	}
+	return presets, nil
+}
+
+// Get returns the preset with the given id, or nil if there is none.
+func (r *FilterPresetRepository) Get(ctx context.Context, id string) (*models.FilterPreset, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	var p models.FilterPreset
+	err := scanFilterPreset(r.db.QueryRow(ctx,
+		`SELECT `+filterPresetColumns+` FROM filter_presets WHERE id = $1`,
+		id,
+	), &p)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get filter preset: %w", err)
+	}
+	return &p, nil
+}
+
+// Create saves a new preset, filling in its id and timestamps.
+func (r *FilterPresetRepository) Create(ctx context.Context, preset *models.FilterPreset) error {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	preset.ID = id.New()
+	err := r.db.QueryRow(ctx,
+		`INSERT INTO filter_presets (id, email, name, faculty, course_code_range, term_id)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 RETURNING created_at, updated_at`,
+		preset.ID, preset.Email, preset.Name, preset.Faculty, preset.CourseCodeRange, preset.TermID,
+	).Scan(&preset.CreatedAt, &preset.UpdatedAt)
+	if isDuplicatePresetName(err) {
+		preset.ID = ""
+		return ErrDuplicatePresetName
+	}
+	if err != nil {
+		return fmt.Errorf("insert filter preset: %w", err)
+	}
+	return nil
+}
+
+// Update replaces the name and filters of the preset with preset.ID, filling
+// in its timestamps. It reports false when preset.Email has no such preset.
+func (r *FilterPresetRepository) Update(ctx context.Context, preset *models.FilterPreset) (bool, error) {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	err := r.db.QueryRow(ctx,
+		`UPDATE filter_presets
+		 SET name = $3, faculty = $4, course_code_range = $5, term_id = $6, updated_at = NOW()
+		 WHERE id = $1 AND email = $2
+		 RETURNING created_at, updated_at`,
+		preset.ID, preset.Email, preset.Name, preset.Faculty, preset.CourseCodeRange, preset.TermID,
+	).Scan(&preset.CreatedAt, &preset.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if isDuplicatePresetName(err) {
+		return false, ErrDuplicatePresetName
+	}
+	if err != nil {
+		return false, fmt.Errorf("update filter preset: %w", err)
+	}
+	return true, nil
+}
+
+// Delete removes one of email's presets and reports whether it existed.
+func (r *FilterPresetRepository) Delete(ctx context.Context, id, email string) (bool, error) {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	tag, err := r.db.Exec(ctx, `DELETE FROM filter_presets WHERE id = $1 AND email = $2`, id, email)
+	if err != nil {
+		return false, fmt.Errorf("delete filter preset: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func scanFilterPreset(row pgx.Row, p *models.FilterPreset) error {
+	return row.Scan(&p.ID, &p.Email, &p.Name, &p.Faculty, &p.CourseCodeRange, &p.TermID, &p.CreatedAt, &p.UpdatedAt)
+}
+
+func isDuplicatePresetName(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "filter_presets_email_name_key"
+}
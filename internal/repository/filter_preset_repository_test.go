@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var filterPresetRowColumns = []string{"id", "email", "name", "faculty", "course_code_range", "term_id", "created_at", "updated_at"}
+
+func TestFilterPresetRepository_List(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewFilterPresetRepository(mock)
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT (.+) FROM filter_presets WHERE email = \\$1 ORDER BY name").
+		WithArgs("a@yorku.ca").
+		WillReturnRows(pgxmock.NewRows(filterPresetRowColumns).
+			AddRow("p-1", "a@yorku.ca", "3000-level EECS", "LE", "3000s", "FW2025", now, now).
+			AddRow("p-2", "a@yorku.ca", "Everything", "", "", "", now, now))
+
+	presets, err := repo.List(context.Background(), "a@yorku.ca")
+	assert.NoError(t, err)
+	assert.Len(t, presets, 2)
+	assert.Equal(t, "3000s", presets[0].CourseCodeRange)
+	assert.Equal(t, "FW2025", presets[0].TermID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFilterPresetRepository_Get(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewFilterPresetRepository(mock)
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT (.+) FROM filter_presets WHERE id = \\$1").
+		WithArgs("p-1").
+		WillReturnRows(pgxmock.NewRows(filterPresetRowColumns).AddRow("p-1", "a@yorku.ca", "Mine", "LE", "", "", now, now))
+	mock.ExpectQuery("SELECT (.+) FROM filter_presets WHERE id = \\$1").
+		WithArgs("missing").
+		WillReturnError(pgx.ErrNoRows)
+
+	preset, err := repo.Get(context.Background(), "p-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "LE", preset.Faculty)
+
+	preset, err = repo.Get(context.Background(), "missing")
+	assert.NoError(t, err)
+	assert.Nil(t, preset)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFilterPresetRepository_Create(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewFilterPresetRepository(mock)
+	now := time.Now()
+	preset := &models.FilterPreset{Email: "a@yorku.ca", Name: "Mine", Faculty: "LE", CourseCodeRange: "3000s"}
+
+	mock.ExpectQuery("INSERT INTO filter_presets").
+		WithArgs(pgxmock.AnyArg(), "a@yorku.ca", "Mine", "LE", "3000s", "").
+		WillReturnRows(pgxmock.NewRows([]string{"created_at", "updated_at"}).AddRow(now, now))
+	mock.ExpectQuery("INSERT INTO filter_presets").
+		WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "filter_presets_email_name_key"})
+
+	assert.NoError(t, repo.Create(context.Background(), preset))
+	assert.NotEmpty(t, preset.ID)
+	assert.Equal(t, now, preset.CreatedAt)
+
+	duplicate := &models.FilterPreset{Email: "a@yorku.ca", Name: "Mine"}
+	assert.ErrorIs(t, repo.Create(context.Background(), duplicate), ErrDuplicatePresetName)
+	assert.Empty(t, duplicate.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFilterPresetRepository_Update(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewFilterPresetRepository(mock)
+	now := time.Now()
+
+	mock.ExpectQuery("UPDATE filter_presets (.+) WHERE id = \\$1 AND email = \\$2").
+		WithArgs("p-1", "a@yorku.ca", "Renamed", "", "5000s+", "SU2026").
+		WillReturnRows(pgxmock.NewRows([]string{"created_at", "updated_at"}).AddRow(now, now))
+	mock.ExpectQuery("UPDATE filter_presets").WillReturnError(pgx.ErrNoRows)
+	mock.ExpectQuery("UPDATE filter_presets").
+		WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "filter_presets_email_name_key"})
+
+	updated, err := repo.Update(context.Background(), &models.FilterPreset{ID: "p-1", Email: "a@yorku.ca", Name: "Renamed", CourseCodeRange: "5000s+", TermID: "SU2026"})
+	assert.NoError(t, err)
+	assert.True(t, updated)
+
+	updated, err = repo.Update(context.Background(), &models.FilterPreset{ID: "p-1", Email: "b@yorku.ca", Name: "Renamed"})
+	assert.NoError(t, err)
+	assert.False(t, updated)
+
+	_, err = repo.Update(context.Background(), &models.FilterPreset{ID: "p-1", Email: "a@yorku.ca", Name: "Taken"})
+	assert.ErrorIs(t, err, ErrDuplicatePresetName)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFilterPresetRepository_Delete(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewFilterPresetRepository(mock)
+
+	mock.ExpectExec("DELETE FROM filter_presets WHERE id = \\$1 AND email = \\$2").
+		WithArgs("p-1", "a@yorku.ca").
+		WillReturnResult(pgxmock.NewResult("DELETE", 1))
+	mock.ExpectExec("DELETE FROM filter_presets").
+		WithArgs("p-1", "b@yorku.ca").
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+
+	removed, err := repo.Delete(context.Background(), "p-1", "a@yorku.ca")
+	assert.NoError(t, err)
+	assert.True(t, removed)
+
+	removed, err = repo.Delete(context.Background(), "p-1", "b@yorku.ca")
+	assert.NoError(t, err)
+	assert.False(t, removed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
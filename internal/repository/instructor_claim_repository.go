@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"yuplan/internal/id"
+	"yuplan/internal/models"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// ErrInstructorClaimed is returned when the instructor already has an approved claim.
+var ErrInstructorClaimed = errors.New("instructor already claimed")
+
+type instructorClaimDB interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// InstructorClaimRepository keeps claims on instructors under the
+// instructor's name, like InstructorReviewRepository. Only the hash of each
+// link token is stored.
+type InstructorClaimRepository struct {
+	db instructorClaimDB
+}
+
+func NewInstructorClaimRepository(db instructorClaimDB) *InstructorClaimRepository {
+	return &InstructorClaimRepository{db: db}
+}
+
+const instructorClaimColumns = `id, first_name, last_name, email, status, created_at, verified_at, decided_at`
+
+// Create records an unverified claim by email on the instructor with the
+// given row id. A claim the email already made on them that wasn't approved
+// starts over with the new token. It returns nil when there is no such
+// instructor, and ErrInstructorClaimed when someone's claim on them was
+// approved.
+func (r *InstructorClaimRepository) Create(ctx context.Context, instructorID, email, tokenHash string, expiresAt time.Time) (*models.InstructorClaim, error) {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	claim, err := scanInstructorClaim(r.db.QueryRow(ctx,
+		`INSERT INTO instructor_claims (id, first_name, last_name, email, token_hash, expires_at)
+		 SELECT $2, me.first_name, me.last_name, $3, $4, $5
+		 FROM instructors me
+		 WHERE me.id = $1
+		   AND NOT EXISTS (SELECT 1 FROM instructor_claims c
+		                   WHERE c.first_name = me.first_name AND c.last_name = me.last_name
+		                     AND c.status = 'approved')
+		 ON CONFLICT (last_name, first_name, email) DO UPDATE
+		 SET status = 'unverified', token_hash = EXCLUDED.token_hash, expires_at = EXCLUDED.expires_at,
+		     created_at = NOW(), verified_at = NULL, decided_at = NULL
+		 RETURNING `+instructorClaimColumns,
+		instructorID, id.New(), email, tokenHash, expiresAt,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("insert instructor claim: %w", err)
+	}
+	if claim != nil {
+		return claim, nil
+	}
+
+	// Nothing was inserted: either the instructor doesn't exist or they're claimed
+	var exists bool
+	if err := r.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM instructors WHERE id = $1)`, instructorID).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("check instructor: %w", err)
+	}
+	if exists {
+		return nil, ErrInstructorClaimed
+	}
+	return nil, nil
+}
+
+// Verify uses up an unexpired token of an unverified claim, moving the claim
+// to pending, and returns it, or nil if the token is unknown or expired.
+func (r *InstructorClaimRepository) Verify(ctx context.Context, tokenHash string) (*models.InstructorClaim, error) {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	claim, err := scanInstructorClaim(r.db.QueryRow(ctx,
+		`UPDATE instructor_claims SET status = 'pending', token_hash = NULL, verified_at = NOW()
+		 WHERE token_hash = $1 AND expires_at > NOW() AND status = 'unverified'
+		 RETURNING `+instructorClaimColumns,
+		tokenHash,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("verify instructor claim: %w", err)
+	}
+	return claim, nil
+}
+
+// List returns the claims with status, or every claim when status is empty,
+// oldest first.
+func (r *InstructorClaimRepository) List(ctx context.Context, status string) ([]models.InstructorClaim, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	rows, err := r.db.Query(ctx,
+		`SELECT `+instructorClaimColumns+`
+		 FROM instructor_claims
+		 WHERE $1 = '' OR status = $1
+		 ORDER BY created_at, id`,
+		status,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query instructor claims: %w", err)
+	}
+	defer rows.Close()
+
+	claims := []models.InstructorClaim{}
+	for rows.Next() {
+		var claim models.InstructorClaim
+		if err := rows.Scan(
+			&claim.ID,
+			&claim.FirstName,
+			&claim.LastName,
+			&claim.Email,
+			&claim.Status,
+			&claim.CreatedAt,
+			&claim.VerifiedAt,
+			&claim.DecidedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan instructor claim: %w", err)
+		}
+		claims = append(claims, claim)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate instructor claims: %w", err)
+	}
+	return claims, nil
+}
+
+// Decide moves a pending claim to status (approved or rejected) and returns
+// it, or nil if it isn't pending. An approved claim can be approved again,
+// so its instructor can be sent a fresh token. A claim isn't approved while
+// another claim on the same instructor is.
+func (r *InstructorClaimRepository) Decide(ctx context.Context, id, status string) (*models.InstructorClaim, error) {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	claim, err := scanInstructorClaim(r.db.QueryRow(ctx,
+		`UPDATE instructor_claims c SET status = $2, decided_at = NOW()
+		 WHERE c.id = $1
+		   AND (c.status = 'pending' OR (c.status = 'approved' AND $2 = 'approved'))
+		   AND ($2 <> 'approved' OR NOT EXISTS (
+		        SELECT 1 FROM instructor_claims o
+		        WHERE o.first_name = c.first_name AND o.last_name = c.last_name
+		          AND o.status = 'approved' AND o.id <> c.id))
+		 RETURNING `+instructorClaimColumns,
+		id, status,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("decide instructor claim: %w", err)
+	}
+	return claim, nil
+}
+
+// scanInstructorClaim scans a row of instructorClaimColumns, returning nil
+// for no row.
+func scanInstructorClaim(row pgx.Row) (*models.InstructorClaim, error) {
+	var claim models.InstructorClaim
+	err := row.Scan(
+		&claim.ID,
+		&claim.FirstName,
+		&claim.LastName,
+		&claim.Email,
+		&claim.Status,
+		&claim.CreatedAt,
+		&claim.VerifiedAt,
+		&claim.DecidedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &claim, nil
+}
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"yuplan/internal/dbtypes"
+	"yuplan/internal/models"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var instructorClaimCols = []string{"id", "first_name", "last_name", "email", "status", "created_at", "verified_at", "decided_at"}
+
+func TestInstructorClaimRepository_Create(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewInstructorClaimRepository(mock)
+	now := time.Now()
+	expires := now.Add(time.Hour)
+
+	mock.ExpectQuery("INSERT INTO instructor_claims (.+) FROM instructors me (.+) NOT EXISTS (.+) c.status = 'approved'(.+) ON CONFLICT \\(last_name, first_name, email\\) DO UPDATE SET status = 'unverified'").
+		WithArgs("inst-1", pgxmock.AnyArg(), "jwang@yorku.ca", "hash", expires).
+		WillReturnRows(pgxmock.NewRows(instructorClaimCols).
+			AddRow("claim-1", "Jackie", "Wang", "jwang@yorku.ca", "unverified", now, dbtypes.NullTime{}, dbtypes.NullTime{}))
+
+	claim, err := repo.Create(context.Background(), "inst-1", "jwang@yorku.ca", "hash", expires)
+	assert.NoError(t, err)
+	assert.Equal(t, "claim-1", claim.ID)
+	assert.Equal(t, "Wang", claim.LastName)
+	assert.Equal(t, models.ClaimUnverified, claim.Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInstructorClaimRepository_Create_NotInserted(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewInstructorClaimRepository(mock)
+	expires := time.Now().Add(time.Hour)
+
+	mock.ExpectQuery("INSERT INTO instructor_claims").WillReturnError(pgx.ErrNoRows)
+	mock.ExpectQuery("SELECT EXISTS \\(SELECT 1 FROM instructors WHERE id = \\$1\\)").
+		WithArgs("claimed").
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery("INSERT INTO instructor_claims").WillReturnError(pgx.ErrNoRows)
+	mock.ExpectQuery("SELECT EXISTS").
+		WithArgs("missing").
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(false))
+
+	claim, err := repo.Create(context.Background(), "claimed", "jwang@yorku.ca", "hash", expires)
+	assert.ErrorIs(t, err, ErrInstructorClaimed)
+	assert.Nil(t, claim)
+
+	claim, err = repo.Create(context.Background(), "missing", "jwang@yorku.ca", "hash", expires)
+	assert.NoError(t, err)
+	assert.Nil(t, claim)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInstructorClaimRepository_Verify(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewInstructorClaimRepository(mock)
+	now := time.Now()
+
+	mock.ExpectQuery("UPDATE instructor_claims SET status = 'pending', token_hash = NULL(.+) WHERE token_hash = \\$1 AND expires_at > NOW\\(\\) AND status = 'unverified'").
+		WithArgs("hash").
+		WillReturnRows(pgxmock.NewRows(instructorClaimCols).
+			AddRow("claim-1", "Jackie", "Wang", "jwang@yorku.ca", "pending", now, dbtypes.NewNullTime(now), dbtypes.NullTime{}))
+	mock.ExpectQuery("UPDATE instructor_claims").
+		WithArgs("stale").
+		WillReturnError(pgx.ErrNoRows)
+	mock.ExpectQuery("UPDATE instructor_claims").
+		WithArgs("broken").
+		WillReturnError(errors.New("db down"))
+
+	claim, err := repo.Verify(context.Background(), "hash")
+	assert.NoError(t, err)
+	assert.Equal(t, models.ClaimPending, claim.Status)
+
+	claim, err = repo.Verify(context.Background(), "stale")
+	assert.NoError(t, err)
+	assert.Nil(t, claim)
+
+	_, err = repo.Verify(context.Background(), "broken")
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInstructorClaimRepository_List(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewInstructorClaimRepository(mock)
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT (.+) FROM instructor_claims WHERE \\$1 = '' OR status = \\$1 ORDER BY created_at, id").
+		WithArgs("pending").
+		WillReturnRows(pgxmock.NewRows(instructorClaimCols).
+			AddRow("claim-1", "Jackie", "Wang", "jwang@yorku.ca", "pending", now, dbtypes.NewNullTime(now), dbtypes.NullTime{}))
+
+	claims, err := repo.List(context.Background(), models.ClaimPending)
+	assert.NoError(t, err)
+	assert.Len(t, claims, 1)
+	assert.Equal(t, "jwang@yorku.ca", claims[0].Email)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInstructorClaimRepository_Decide(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewInstructorClaimRepository(mock)
+	now := time.Now()
+
+	mock.ExpectQuery("UPDATE instructor_claims c SET status = \\$2, decided_at = NOW\\(\\)(.+)c.status = 'pending'(.+) o.status = 'approved' AND o.id <> c.id").
+		WithArgs("claim-1", models.ClaimApproved).
+		WillReturnRows(pgxmock.NewRows(instructorClaimCols).
+			AddRow("claim-1", "Jackie", "Wang", "jwang@yorku.ca", "approved", now, dbtypes.NewNullTime(now), dbtypes.NewNullTime(now)))
+	mock.ExpectQuery("UPDATE instructor_claims c").
+		WithArgs("claim-2", models.ClaimRejected).
+		WillReturnError(pgx.ErrNoRows)
+
+	claim, err := repo.Decide(context.Background(), "claim-1", models.ClaimApproved)
+	assert.NoError(t, err)
+	assert.Equal(t, models.ClaimApproved, claim.Status)
+
+	claim, err = repo.Decide(context.Background(), "claim-2", models.ClaimRejected)
+	assert.NoError(t, err)
+	assert.Nil(t, claim)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"yuplan/internal/models"
+)
+
+// photoURLs turns a stored photo id into the URLs it is served from.
+// Implemented by photo.Library.
+type photoURLs interface {
+	URLs(id string) *models.InstructorPhoto
+}
+
+// PhotoInstructorRepository adds photo URLs to the instructors another
+// repository returns. It sits outside the cache so cached rows don't pin the
+// URLs to a base URL that has since changed.
+type PhotoInstructorRepository struct {
+	InstructorRepositoryInterface
+	urls photoURLs
+}
+
+func NewPhotoInstructorRepository(repo InstructorRepositoryInterface, urls photoURLs) *PhotoInstructorRepository {
+	return &PhotoInstructorRepository{InstructorRepositoryInterface: repo, urls: urls}
+}
+
+func (r *PhotoInstructorRepository) GetByCourseID(ctx context.Context, courseID string) ([]models.Instructor, error) {
+	instructors, err := r.InstructorRepositoryInterface.GetByCourseID(ctx, courseID)
+	for i := range instructors {
+		instructors[i].Photo = r.photo(instructors[i].PhotoID.String)
+	}
+	return instructors, err
+}
+
+func (r *PhotoInstructorRepository) GetByID(ctx context.Context, id string) (*models.Instructor, error) {
+	instructor, err := r.InstructorRepositoryInterface.GetByID(ctx, id)
+	if instructor != nil {
+		instructor.Photo = r.photo(instructor.PhotoID.String)
+	}
+	return instructor, err
+}
+
+func (r *PhotoInstructorRepository) GetByIDs(ctx context.Context, ids []string) ([]models.Instructor, error) {
+	instructors, err := r.InstructorRepositoryInterface.GetByIDs(ctx, ids)
+	for i := range instructors {
+		instructors[i].Photo = r.photo(instructors[i].PhotoID.String)
+	}
+	return instructors, err
+}
+
+func (r *PhotoInstructorRepository) GetProfile(ctx context.Context, id string) (*models.InstructorProfile, error) {
+	profile, err := r.InstructorRepositoryInterface.GetProfile(ctx, id)
+	if profile != nil {
+		profile.Photo = r.photo(profile.PhotoID.String)
+	}
+	return profile, err
+}
+
+func (r *PhotoInstructorRepository) photo(id string) *models.InstructorPhoto {
+	if id == "" {
+		return nil
+	}
+	return r.urls.URLs(id)
+}
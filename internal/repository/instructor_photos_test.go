@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"yuplan/internal/dbtypes"
+	"yuplan/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubInstructorRepository struct {
+	InstructorRepositoryInterface
+	instructors []models.Instructor
+}
+
+func (s stubInstructorRepository) GetByCourseID(ctx context.Context, courseID string) ([]models.Instructor, error) {
+	return s.instructors, nil
+}
+
+func (s stubInstructorRepository) GetByID(ctx context.Context, id string) (*models.Instructor, error) {
+	return nil, nil
+}
+
+type cdnURLs struct{}
+
+func (cdnURLs) URLs(id string) *models.InstructorPhoto {
+	return &models.InstructorPhoto{Medium: "https://cdn.example.com/" + id}
+}
+
+func TestPhotoInstructorRepository(t *testing.T) {
+	repo := NewPhotoInstructorRepository(stubInstructorRepository{instructors: []models.Instructor{
+		{ID: "with", PhotoID: dbtypes.NewNullString("abc")},
+		{ID: "without"},
+	}}, cdnURLs{})
+
+	instructors, err := repo.GetByCourseID(context.Background(), "course-1")
+	assert.NoError(t, err)
+	if assert.NotNil(t, instructors[0].Photo) {
+		assert.Equal(t, "https://cdn.example.com/abc", instructors[0].Photo.Medium)
+	}
+	assert.Nil(t, instructors[1].Photo)
+
+	instructor, err := repo.GetByID(context.Background(), "missing")
+	assert.NoError(t, err)
+	assert.Nil(t, instructor)
+}
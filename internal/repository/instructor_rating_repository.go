@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+type InstructorRatingRepositoryInterface interface {
+	ListStale(ctx context.Context, before time.Time, limit int) ([]models.InstructorName, error)
+	SaveRating(ctx context.Context, name models.InstructorName, rating *models.InstructorRating) error
+}
+
+type instructorRatingDB interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+type InstructorRatingRepository struct {
+	db instructorRatingDB
+}
+
+func NewInstructorRatingRepository(db instructorRatingDB) *InstructorRatingRepository {
+	return &InstructorRatingRepository{db: db}
+}
+
+// ListStale returns up to limit instructor names whose rating was never
+// fetched or was last fetched before the given time, those never fetched first.
+func (r *InstructorRatingRepository) ListStale(ctx context.Context, before time.Time, limit int) ([]models.InstructorName, error) {
+	ctx, cancel := withDeadline(ctx, opAggregate)
+	defer cancel()
+
+	rows, err := r.db.Query(ctx,
+		`SELECT i.first_name, i.last_name
+		 FROM instructors i
+		 LEFT JOIN instructor_ratings ir ON ir.first_name = i.first_name AND ir.last_name = i.last_name
+		 WHERE i.first_name <> '' AND i.last_name <> '' AND (ir.fetched_at IS NULL OR ir.fetched_at < $1)
+		 GROUP BY i.first_name, i.last_name, ir.fetched_at
+		 ORDER BY ir.fetched_at NULLS FIRST, i.last_name, i.first_name
+		 LIMIT $2`,
+		before, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query stale instructor ratings: %w", err)
+	}
+	defer rows.Close()
+
+	names := make([]models.InstructorName, 0)
+	for rows.Next() {
+		var name models.InstructorName
+		if err := rows.Scan(&name.FirstName, &name.LastName); err != nil {
+			return nil, fmt.Errorf("scan instructor name: %w", err)
+		}
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate instructor names: %w", err)
+	}
+	return names, nil
+}
+
+// SaveRating stores the rating fetched for an instructor, replacing any
+// earlier one. A nil rating records that RateMyProfessors had no match, so
+// the name isn't looked up again until it is stale.
+func (r *InstructorRatingRepository) SaveRating(ctx context.Context, name models.InstructorName, rating *models.InstructorRating) error {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	if rating == nil {
+		rating = &models.InstructorRating{}
+	}
+	var rmpID *string
+	if rating.RMPID != "" {
+		rmpID = &rating.RMPID
+	}
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO instructor_ratings (first_name, last_name, rmp_id, rating, difficulty, would_take_again, num_ratings, fetched_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+		 ON CONFLICT (last_name, first_name) DO UPDATE
+		 SET rmp_id = EXCLUDED.rmp_id,
+		     rating = EXCLUDED.rating,
+		     difficulty = EXCLUDED.difficulty,
+		     would_take_again = EXCLUDED.would_take_again,
+		     num_ratings = EXCLUDED.num_ratings,
+		     fetched_at = EXCLUDED.fetched_at`,
+		name.FirstName, name.LastName, rmpID, rating.Rating, rating.Difficulty, rating.WouldTakeAgain, rating.NumRatings,
+	)
+	if err != nil {
+		return fmt.Errorf("save instructor rating: %w", err)
+	}
+	return nil
+}
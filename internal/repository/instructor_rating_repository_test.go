@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstructorRatingRepository_ListStale(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewInstructorRatingRepository(mock)
+	before := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`LEFT JOIN instructor_ratings ir(.+)ir.fetched_at IS NULL OR ir.fetched_at < \$1(.+)ORDER BY ir.fetched_at NULLS FIRST(.+)LIMIT \$2`).
+		WithArgs(before, 50).
+		WillReturnRows(pgxmock.NewRows([]string{"first_name", "last_name"}).
+			AddRow("Jackie", "Wang").
+			AddRow("Sam", "Okafor"))
+
+	names, err := repo.ListStale(context.Background(), before, 50)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.InstructorName{{FirstName: "Jackie", LastName: "Wang"}, {FirstName: "Sam", LastName: "Okafor"}}, names)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInstructorRatingRepository_SaveRating(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewInstructorRatingRepository(mock)
+	name := models.InstructorName{FirstName: "Jackie", LastName: "Wang"}
+	rating, wouldTakeAgain := 4.2, 87
+
+	mock.ExpectExec(`INSERT INTO instructor_ratings(.+)ON CONFLICT \(last_name, first_name\) DO UPDATE`).
+		WithArgs("Jackie", "Wang", pgxmock.AnyArg(), &rating, pgxmock.AnyArg(), &wouldTakeAgain, 31).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	err = repo.SaveRating(context.Background(), name, &models.InstructorRating{RMPID: "12345", Rating: &rating, WouldTakeAgain: &wouldTakeAgain, NumRatings: 31})
+	assert.NoError(t, err)
+
+	// No match is saved too, so the name waits until it is stale
+	mock.ExpectExec(`INSERT INTO instructor_ratings`).
+		WithArgs("Jackie", "Wang", pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), 0).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	assert.NoError(t, repo.SaveRating(context.Background(), name, nil))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
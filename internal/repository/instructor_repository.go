@@ -2,18 +2,29 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"yuplan/internal/dbtypes"
 	"yuplan/internal/models"
 
+	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
 )
 
 type InstructorRepositoryInterface interface {
 	GetByCourseID(ctx context.Context, courseID string) ([]models.Instructor, error)
+	GetByID(ctx context.Context, id string) (*models.Instructor, error)
+	GetByIDs(ctx context.Context, ids []string) ([]models.Instructor, error)
+	ListTeachingActivities(ctx context.Context, id, term string) ([]models.TeachingActivity, error)
+	GetProfile(ctx context.Context, id string) (*models.InstructorProfile, error)
+	ListCourses(ctx context.Context, id string) ([]models.InstructorCourse, error)
+	SetPhoto(ctx context.Context, id string, photoID dbtypes.NullString) (bool, error)
 }
 
 type instructorDB interface {
 	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
 }
 
 type InstructorRepository struct {
@@ -24,12 +35,69 @@ func NewInstructorRepository(db instructorDB) *InstructorRepository {
 	return &InstructorRepository{db: db}
 }
 
+// instructorRatingJoin attaches the RateMyProfessors rating of instructor row
+// i, matched by name. Its columns are null when there is none.
+const instructorRatingJoin = `LEFT JOIN instructor_ratings ir
+		   ON ir.first_name = i.first_name AND ir.last_name = i.last_name AND ir.rmp_id IS NOT NULL`
+
+// instructorRatingColumns are the columns ratingScan reads.
+const instructorRatingColumns = `ir.rmp_id, ir.rating::float8, ir.difficulty::float8, ir.would_take_again, ir.num_ratings, ir.fetched_at`
+
+// ratingScan reads instructorRatingColumns, which a LEFT JOIN may leave null.
+type ratingScan struct {
+	rmpID          dbtypes.NullString
+	rating         *float64
+	difficulty     *float64
+	wouldTakeAgain *int
+	numRatings     *int
+	fetchedAt      dbtypes.NullTime
+}
+
+func (s *ratingScan) dest() []any {
+	return []any{&s.rmpID, &s.rating, &s.difficulty, &s.wouldTakeAgain, &s.numRatings, &s.fetchedAt}
+}
+
+// result is the rating read, or nil when the instructor has none.
+func (s *ratingScan) result() *models.InstructorRating {
+	if !s.rmpID.Valid {
+		return nil
+	}
+	rating := &models.InstructorRating{
+		RMPID:          s.rmpID.String,
+		URL:            models.RMPProfessorURL + s.rmpID.String,
+		Rating:         s.rating,
+		Difficulty:     s.difficulty,
+		WouldTakeAgain: s.wouldTakeAgain,
+		FetchedAt:      s.fetchedAt.Time,
+	}
+	if s.numRatings != nil {
+		rating.NumRatings = *s.numRatings
+	}
+	return rating
+}
+
+// scanInstructor reads an instructor row followed by instructorRatingColumns.
+func scanInstructor(row pgx.Row) (*models.Instructor, error) {
+	var inst models.Instructor
+	var rating ratingScan
+	dest := append([]any{&inst.ID, &inst.FirstName, &inst.LastName, &inst.RateMyProfLink, &inst.SectionID, &inst.PhotoID, &inst.CreatedAt, &inst.UpdatedAt}, rating.dest()...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	inst.RMP = rating.result()
+	return &inst, nil
+}
+
 func (r *InstructorRepository) GetByCourseID(ctx context.Context, courseID string) ([]models.Instructor, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
 	rows, err := r.db.Query(
 		ctx,
-		`SELECT i.id, i.first_name, i.last_name, i.rate_my_prof_link, i.section_id, i.created_at, i.updated_at
+		`SELECT i.id, i.first_name, i.last_name, i.rate_my_prof_link, i.section_id, i.photo_id, i.created_at, i.updated_at, `+instructorRatingColumns+`
 		 FROM instructors i
 		 INNER JOIN sections s ON i.section_id = s.id
+		 `+instructorRatingJoin+`
 		 WHERE s.course_id = $1
 		 ORDER BY s.letter, i.last_name, i.first_name`,
 		courseID,
@@ -41,11 +109,11 @@ func (r *InstructorRepository) GetByCourseID(ctx context.Context, courseID strin
 
 	instructors := make([]models.Instructor, 0)
 	for rows.Next() {
-		var inst models.Instructor
-		if err := rows.Scan(&inst.ID, &inst.FirstName, &inst.LastName, &inst.RateMyProfLink, &inst.SectionID, &inst.CreatedAt, &inst.UpdatedAt); err != nil {
+		inst, err := scanInstructor(rows)
+		if err != nil {
 			return nil, fmt.Errorf("scan instructor: %w", err)
 		}
-		instructors = append(instructors, inst)
+		instructors = append(instructors, *inst)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("iterate instructors: %w", err)
@@ -54,3 +122,182 @@ func (r *InstructorRepository) GetByCourseID(ctx context.Context, courseID strin
 	return instructors, nil
 }
 
+// GetByID returns one instructor row, or nil if there is none.
+func (r *InstructorRepository) GetByID(ctx context.Context, id string) (*models.Instructor, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	inst, err := scanInstructor(r.db.QueryRow(ctx,
+		`SELECT i.id, i.first_name, i.last_name, i.rate_my_prof_link, i.section_id, i.photo_id, i.created_at, i.updated_at, `+instructorRatingColumns+`
+		 FROM instructors i
+		 `+instructorRatingJoin+`
+		 WHERE i.id = $1`,
+		id,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query instructor: %w", err)
+	}
+	return inst, nil
+}
+
+// GetByIDs returns the instructor rows with the given ids in one query,
+// ordered by name. Ids with no row are left out.
+func (r *InstructorRepository) GetByIDs(ctx context.Context, ids []string) ([]models.Instructor, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	rows, err := r.db.Query(ctx,
+		`SELECT i.id, i.first_name, i.last_name, i.rate_my_prof_link, i.section_id, i.photo_id, i.created_at, i.updated_at, `+instructorRatingColumns+`
+		 FROM instructors i
+		 `+instructorRatingJoin+`
+		 WHERE i.id = ANY($1)
+		 ORDER BY i.last_name, i.first_name, i.id`,
+		ids,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query instructors by ids: %w", err)
+	}
+	defer rows.Close()
+
+	instructors := make([]models.Instructor, 0, len(ids))
+	for rows.Next() {
+		inst, err := scanInstructor(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan instructor: %w", err)
+		}
+		instructors = append(instructors, *inst)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate instructors: %w", err)
+	}
+	return instructors, nil
+}
+
+// ListTeachingActivities returns the activities of every section taught by
+// the instructor with the given id, in term when it isn't empty. The seed
+// writes one instructor row per section, so sections are matched by name.
+// Tutorials and labs are left out since teaching assistants lead them.
+func (r *InstructorRepository) ListTeachingActivities(ctx context.Context, id, term string) ([]models.TeachingActivity, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	rows, err := r.db.Query(ctx,
+		`SELECT c.code, COALESCE(c.term, ''), s.letter, sa.course_type, sa.times
+		 FROM instructors me
+		 INNER JOIN instructors i ON i.first_name = me.first_name AND i.last_name = me.last_name
+		 INNER JOIN sections s ON s.id = i.section_id
+		 INNER JOIN courses c ON c.id = s.course_id
+		 INNER JOIN section_activities sa ON sa.section_id = s.id
+		 WHERE me.id = $1 AND ($2 = '' OR c.term = $2) AND sa.course_type NOT IN ($3, $4)
+		 ORDER BY c.code, s.letter, sa.course_type`,
+		id, term, models.ActivityTutorial, models.ActivityLab,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query teaching activities: %w", err)
+	}
+	defer rows.Close()
+
+	activities := make([]models.TeachingActivity, 0)
+	for rows.Next() {
+		var a models.TeachingActivity
+		if err := rows.Scan(&a.CourseCode, &a.Term, &a.Section, &a.Type, &a.Times); err != nil {
+			return nil, fmt.Errorf("scan teaching activity: %w", err)
+		}
+		activities = append(activities, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate teaching activities: %w", err)
+	}
+	return activities, nil
+}
+
+// GetProfile merges every instructor row with the same name as the row with
+// the given id into one profile, or returns nil if there is no such row.
+func (r *InstructorRepository) GetProfile(ctx context.Context, id string) (*models.InstructorProfile, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	var p models.InstructorProfile
+	var rating ratingScan
+	err := r.db.QueryRow(ctx,
+		`SELECT me.id, me.first_name, me.last_name, MAX(i.rate_my_prof_link), MAX(i.photo_id),
+		        COUNT(DISTINCT s.id), COUNT(DISTINCT c.code),
+		        COALESCE(array_agg(DISTINCT c.term ORDER BY c.term) FILTER (WHERE c.term <> ''), '{}'),
+		        `+instructorRatingColumns+`
+		 FROM instructors me
+		 INNER JOIN instructors i ON i.first_name = me.first_name AND i.last_name = me.last_name
+		 LEFT JOIN sections s ON s.id = i.section_id
+		 LEFT JOIN courses c ON c.id = s.course_id
+		 LEFT JOIN instructor_ratings ir
+		   ON ir.first_name = me.first_name AND ir.last_name = me.last_name AND ir.rmp_id IS NOT NULL
+		 WHERE me.id = $1
+		 GROUP BY me.id, me.first_name, me.last_name, ir.last_name, ir.first_name`,
+		id,
+	).Scan(append([]any{&p.ID, &p.FirstName, &p.LastName, &p.RateMyProfLink, &p.PhotoID, &p.SectionCount, &p.CourseCount, &p.Terms}, rating.dest()...)...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query instructor profile: %w", err)
+	}
+	p.RMP = rating.result()
+	return &p, nil
+}
+
+// ListCourses returns each course offering taught under the name of the
+// instructor with the given id, once however many of its sections they teach.
+func (r *InstructorRepository) ListCourses(ctx context.Context, id string) ([]models.InstructorCourse, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	rows, err := r.db.Query(ctx,
+		`SELECT c.id, c.code, c.name, COALESCE(c.term, ''), array_agg(DISTINCT s.letter ORDER BY s.letter)
+		 FROM instructors me
+		 INNER JOIN instructors i ON i.first_name = me.first_name AND i.last_name = me.last_name
+		 INNER JOIN sections s ON s.id = i.section_id
+		 INNER JOIN courses c ON c.id = s.course_id
+		 WHERE me.id = $1
+		 GROUP BY c.id, c.code, c.name, c.term
+		 ORDER BY c.code, c.term`,
+		id,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query instructor courses: %w", err)
+	}
+	defer rows.Close()
+
+	courses := make([]models.InstructorCourse, 0)
+	for rows.Next() {
+		var course models.InstructorCourse
+		if err := rows.Scan(&course.CourseID, &course.Code, &course.Name, &course.Term, &course.Sections); err != nil {
+			return nil, fmt.Errorf("scan instructor course: %w", err)
+		}
+		courses = append(courses, course)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate instructor courses: %w", err)
+	}
+	return courses, nil
+}
+
+// SetPhoto sets the photo of every instructor row with the same name as the
+// row with the given id, or clears it when photoID is null. It reports false
+// if there is no such row.
+func (r *InstructorRepository) SetPhoto(ctx context.Context, id string, photoID dbtypes.NullString) (bool, error) {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	tag, err := r.db.Exec(ctx,
+		`UPDATE instructors i SET photo_id = $2, updated_at = NOW()
+		 FROM instructors me
+		 WHERE me.id = $1 AND i.first_name = me.first_name AND i.last_name = me.last_name`,
+		id, photoID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("set instructor photo: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
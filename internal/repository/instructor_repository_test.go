@@ -5,7 +5,10 @@ import (
 	"errors"
 	"testing"
 	"time"
+	"yuplan/internal/dbtypes"
+	"yuplan/internal/models"
 
+	"github.com/jackc/pgx/v4"
 	"github.com/pashagolub/pgxmock"
 	"github.com/stretchr/testify/assert"
 )
@@ -23,11 +26,11 @@ func TestGetInstructorsByCourseID(t *testing.T) {
 	sectionID1 := "section-1"
 	sectionID2 := "section-2"
 	
-	mock.ExpectQuery("SELECT i.id, i.first_name, i.last_name, i.rate_my_prof_link, i.section_id, i.created_at, i.updated_at FROM instructors i\\s+INNER JOIN sections s ON i.section_id = s.id\\s+WHERE s.course_id = \\$1\\s+ORDER BY s.letter, i.last_name, i.first_name").
+	mock.ExpectQuery("SELECT i.id, i.first_name, i.last_name, i.rate_my_prof_link, i.section_id, i.photo_id, i.created_at, i.updated_at, ir.rmp_id(.+)FROM instructors i\\s+INNER JOIN sections s ON i.section_id = s.id\\s+LEFT JOIN instructor_ratings ir(.+)WHERE s.course_id = \\$1\\s+ORDER BY s.letter, i.last_name, i.first_name").
 		WithArgs("course-1").
-		WillReturnRows(pgxmock.NewRows([]string{"id", "first_name", "last_name", "rate_my_prof_link", "section_id", "created_at", "updated_at"}).
-			AddRow("instructor-1", "John", "Doe", &rmpLink1, &sectionID1, now, now).
-			AddRow("instructor-2", "Jane", "Smith", &rmpLink2, &sectionID2, now, now))
+		WillReturnRows(pgxmock.NewRows([]string{"id", "first_name", "last_name", "rate_my_prof_link", "section_id", "photo_id", "created_at", "updated_at", "rmp_id", "rating", "difficulty", "would_take_again", "num_ratings", "fetched_at"}).
+			AddRow("instructor-1", "John", "Doe", &rmpLink1, &sectionID1, nil, now, now, nil, nil, nil, nil, nil, nil).
+			AddRow("instructor-2", "Jane", "Smith", &rmpLink2, &sectionID2, nil, now, now, nil, nil, nil, nil, nil, nil))
 
 	instructors, err := repo.GetByCourseID(context.Background(), "course-1")
 	assert.NoError(t, err)
@@ -49,7 +52,7 @@ func TestGetInstructorsByCourseID_WhenQueryErrors_ReturnsError(t *testing.T) {
 
 	repo := NewInstructorRepository(mock)
 
-	mock.ExpectQuery("SELECT i.id, i.first_name, i.last_name, i.rate_my_prof_link, i.section_id, i.created_at, i.updated_at FROM instructors i\\s+INNER JOIN sections s ON i.section_id = s.id\\s+WHERE s.course_id = \\$1\\s+ORDER BY s.letter, i.last_name, i.first_name").
+	mock.ExpectQuery("SELECT i.id, i.first_name, i.last_name, i.rate_my_prof_link, i.section_id, i.photo_id, i.created_at, i.updated_at, ir.rmp_id(.+)FROM instructors i\\s+INNER JOIN sections s ON i.section_id = s.id\\s+LEFT JOIN instructor_ratings ir(.+)WHERE s.course_id = \\$1\\s+ORDER BY s.letter, i.last_name, i.first_name").
 		WithArgs("course-1").
 		WillReturnError(errors.New("db error"))
 
@@ -70,10 +73,10 @@ func TestGetInstructorsByCourseID_WhenScanFails_ReturnsError(t *testing.T) {
 	rmpLink := "https://www.ratemyprofessors.com/search/professors/?q=John+Doe"
 	sectionID := "section-1"
 	// Using wrong type for id to force scan error
-	rows := pgxmock.NewRows([]string{"id", "first_name", "last_name", "rate_my_prof_link", "section_id", "created_at", "updated_at"}).
-		AddRow(12345, "John", "Doe", &rmpLink, &sectionID, now, now)
+	rows := pgxmock.NewRows([]string{"id", "first_name", "last_name", "rate_my_prof_link", "section_id", "photo_id", "created_at", "updated_at", "rmp_id", "rating", "difficulty", "would_take_again", "num_ratings", "fetched_at"}).
+		AddRow(12345, "John", "Doe", &rmpLink, &sectionID, nil, now, now, nil, nil, nil, nil, nil, nil)
 
-	mock.ExpectQuery("SELECT i.id, i.first_name, i.last_name, i.rate_my_prof_link, i.section_id, i.created_at, i.updated_at FROM instructors i\\s+INNER JOIN sections s ON i.section_id = s.id\\s+WHERE s.course_id = \\$1\\s+ORDER BY s.letter, i.last_name, i.first_name").
+	mock.ExpectQuery("SELECT i.id, i.first_name, i.last_name, i.rate_my_prof_link, i.section_id, i.photo_id, i.created_at, i.updated_at, ir.rmp_id(.+)FROM instructors i\\s+INNER JOIN sections s ON i.section_id = s.id\\s+LEFT JOIN instructor_ratings ir(.+)WHERE s.course_id = \\$1\\s+ORDER BY s.letter, i.last_name, i.first_name").
 		WithArgs("course-1").
 		WillReturnRows(rows)
 
@@ -93,12 +96,12 @@ func TestGetInstructorsByCourseID_WhenRowsErr_ReturnsError(t *testing.T) {
 	now := time.Now()
 	rmpLink := "https://www.ratemyprofessors.com/search/professors/?q=John+Doe"
 	sectionID := "section-1"
-	rows := pgxmock.NewRows([]string{"id", "first_name", "last_name", "rate_my_prof_link", "section_id", "created_at", "updated_at"}).
-		AddRow("instructor-1", "John", "Doe", &rmpLink, &sectionID, now, now).
-		AddRow("instructor-2", "Jane", "Smith", &rmpLink, &sectionID, now, now).
+	rows := pgxmock.NewRows([]string{"id", "first_name", "last_name", "rate_my_prof_link", "section_id", "photo_id", "created_at", "updated_at", "rmp_id", "rating", "difficulty", "would_take_again", "num_ratings", "fetched_at"}).
+		AddRow("instructor-1", "John", "Doe", &rmpLink, &sectionID, nil, now, now, nil, nil, nil, nil, nil, nil).
+		AddRow("instructor-2", "Jane", "Smith", &rmpLink, &sectionID, nil, now, now, nil, nil, nil, nil, nil, nil).
 		RowError(1, errors.New("rows err"))
 
-	mock.ExpectQuery("SELECT i.id, i.first_name, i.last_name, i.rate_my_prof_link, i.section_id, i.created_at, i.updated_at FROM instructors i\\s+INNER JOIN sections s ON i.section_id = s.id\\s+WHERE s.course_id = \\$1\\s+ORDER BY s.letter, i.last_name, i.first_name").
+	mock.ExpectQuery("SELECT i.id, i.first_name, i.last_name, i.rate_my_prof_link, i.section_id, i.photo_id, i.created_at, i.updated_at, ir.rmp_id(.+)FROM instructors i\\s+INNER JOIN sections s ON i.section_id = s.id\\s+LEFT JOIN instructor_ratings ir(.+)WHERE s.course_id = \\$1\\s+ORDER BY s.letter, i.last_name, i.first_name").
 		WithArgs("course-1").
 		WillReturnRows(rows)
 
@@ -115,9 +118,9 @@ func TestGetInstructorsByCourseID_EmptyResult(t *testing.T) {
 
 	repo := NewInstructorRepository(mock)
 
-	mock.ExpectQuery("SELECT i.id, i.first_name, i.last_name, i.rate_my_prof_link, i.section_id, i.created_at, i.updated_at FROM instructors i\\s+INNER JOIN sections s ON i.section_id = s.id\\s+WHERE s.course_id = \\$1\\s+ORDER BY s.letter, i.last_name, i.first_name").
+	mock.ExpectQuery("SELECT i.id, i.first_name, i.last_name, i.rate_my_prof_link, i.section_id, i.photo_id, i.created_at, i.updated_at, ir.rmp_id(.+)FROM instructors i\\s+INNER JOIN sections s ON i.section_id = s.id\\s+LEFT JOIN instructor_ratings ir(.+)WHERE s.course_id = \\$1\\s+ORDER BY s.letter, i.last_name, i.first_name").
 		WithArgs("course-1").
-		WillReturnRows(pgxmock.NewRows([]string{"id", "first_name", "last_name", "rate_my_prof_link", "section_id", "created_at", "updated_at"}))
+		WillReturnRows(pgxmock.NewRows([]string{"id", "first_name", "last_name", "rate_my_prof_link", "section_id", "photo_id", "created_at", "updated_at", "rmp_id", "rating", "difficulty", "would_take_again", "num_ratings", "fetched_at"}))
 
 	instructors, err := repo.GetByCourseID(context.Background(), "course-1")
 	assert.NoError(t, err)
@@ -135,17 +138,181 @@ func TestGetInstructorsByCourseID_WithNilFields(t *testing.T) {
 
 	now := time.Now()
 	
-	mock.ExpectQuery("SELECT i.id, i.first_name, i.last_name, i.rate_my_prof_link, i.section_id, i.created_at, i.updated_at FROM instructors i\\s+INNER JOIN sections s ON i.section_id = s.id\\s+WHERE s.course_id = \\$1\\s+ORDER BY s.letter, i.last_name, i.first_name").
+	mock.ExpectQuery("SELECT i.id, i.first_name, i.last_name, i.rate_my_prof_link, i.section_id, i.photo_id, i.created_at, i.updated_at, ir.rmp_id(.+)FROM instructors i\\s+INNER JOIN sections s ON i.section_id = s.id\\s+LEFT JOIN instructor_ratings ir(.+)WHERE s.course_id = \\$1\\s+ORDER BY s.letter, i.last_name, i.first_name").
 		WithArgs("course-1").
-		WillReturnRows(pgxmock.NewRows([]string{"id", "first_name", "last_name", "rate_my_prof_link", "section_id", "created_at", "updated_at"}).
-			AddRow("instructor-1", "John", "Doe", nil, nil, now, now))
+		WillReturnRows(pgxmock.NewRows([]string{"id", "first_name", "last_name", "rate_my_prof_link", "section_id", "photo_id", "created_at", "updated_at", "rmp_id", "rating", "difficulty", "would_take_again", "num_ratings", "fetched_at"}).
+			AddRow("instructor-1", "John", "Doe", nil, nil, nil, now, now, nil, nil, nil, nil, nil, nil))
 
 	instructors, err := repo.GetByCourseID(context.Background(), "course-1")
 	assert.NoError(t, err)
 	assert.NotNil(t, instructors)
 	assert.Len(t, instructors, 1)
-	assert.Nil(t, instructors[0].RateMyProfLink)
-	assert.Nil(t, instructors[0].SectionID)
+	assert.False(t, instructors[0].RateMyProfLink.Valid)
+	assert.False(t, instructors[0].SectionID.Valid)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestGetInstructorByID(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewInstructorRepository(mock)
+	now := time.Now()
+	sectionID := "section-1"
+
+	mock.ExpectQuery("FROM instructors i\\s+LEFT JOIN instructor_ratings ir(.+)WHERE i.id = \\$1").
+		WithArgs("instructor-1").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "first_name", "last_name", "rate_my_prof_link", "section_id", "photo_id", "created_at", "updated_at", "rmp_id", "rating", "difficulty", "would_take_again", "num_ratings", "fetched_at"}).
+			AddRow("instructor-1", "John", "Doe", nil, &sectionID, nil, now, now, nil, nil, nil, nil, nil, nil))
+
+	instructor, err := repo.GetByID(context.Background(), "instructor-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Doe", instructor.LastName)
+
+	mock.ExpectQuery("FROM instructors i\\s+LEFT JOIN instructor_ratings ir(.+)WHERE i.id = \\$1").
+		WithArgs("missing").
+		WillReturnError(pgx.ErrNoRows)
+
+	instructor, err = repo.GetByID(context.Background(), "missing")
+	assert.NoError(t, err)
+	assert.Nil(t, instructor)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListTeachingActivities(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewInstructorRepository(mock)
+	times := `[{"day": "M", "time": "10:00", "duration": "80"}]`
+
+	mock.ExpectQuery("INNER JOIN instructors i ON i.first_name = me.first_name AND i.last_name = me.last_name(.+)WHERE me.id = \\$1 AND \\(\\$2 = '' OR c.term = \\$2\\) AND sa.course_type NOT IN \\(\\$3, \\$4\\)").
+		WithArgs("instructor-1", models.TermFall, models.ActivityTutorial, models.ActivityLab).
+		WillReturnRows(pgxmock.NewRows([]string{"code", "term", "letter", "course_type", "times"}).
+			AddRow("EECS2030", models.TermFall, "A", models.ActivityLecture, &times).
+			AddRow("EECS3101", models.TermFall, "M", models.ActivitySeminar, nil))
+
+	activities, err := repo.ListTeachingActivities(context.Background(), "instructor-1", models.TermFall)
+	assert.NoError(t, err)
+	assert.Len(t, activities, 2)
+	assert.Equal(t, "EECS2030", activities[0].CourseCode)
+	assert.Equal(t, times, activities[0].Times.String)
+	assert.False(t, activities[1].Times.Valid)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetInstructorProfile(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewInstructorRepository(mock)
+	link := "https://www.ratemyprofessors.com/search/professors/?q=John+Doe"
+	rmpID, rating, wouldTakeAgain, numRatings := "12345", 4.2, 87, 31
+	fetched := time.Date(2026, 10, 1, 6, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("FROM instructors me\\s+INNER JOIN instructors i ON i.first_name = me.first_name AND i.last_name = me.last_name(.+)LEFT JOIN instructor_ratings ir(.+)WHERE me.id = \\$1").
+		WithArgs("instructor-1").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "first_name", "last_name", "rate_my_prof_link", "photo_id", "sections", "courses", "terms", "rmp_id", "rating", "difficulty", "would_take_again", "num_ratings", "fetched_at"}).
+			AddRow("instructor-1", "John", "Doe", &link, nil, 3, 2, []string{models.TermFall, models.TermWinter}, &rmpID, &rating, nil, &wouldTakeAgain, &numRatings, dbtypes.NewNullTime(fetched)))
+
+	profile, err := repo.GetProfile(context.Background(), "instructor-1")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, profile.SectionCount)
+	assert.Equal(t, 2, profile.CourseCount)
+	assert.Equal(t, []string{models.TermFall, models.TermWinter}, profile.Terms)
+	assert.Equal(t, link, profile.RateMyProfLink.String)
+	if assert.NotNil(t, profile.RMP) {
+		assert.Equal(t, models.RMPProfessorURL+"12345", profile.RMP.URL)
+		assert.Equal(t, 4.2, *profile.RMP.Rating)
+		assert.Nil(t, profile.RMP.Difficulty)
+		assert.Equal(t, 87, *profile.RMP.WouldTakeAgain)
+		assert.Equal(t, 31, profile.RMP.NumRatings)
+		assert.Equal(t, fetched, profile.RMP.FetchedAt)
+	}
+
+	mock.ExpectQuery("FROM instructors me").
+		WithArgs("missing").
+		WillReturnError(pgx.ErrNoRows)
+
+	profile, err = repo.GetProfile(context.Background(), "missing")
+	assert.NoError(t, err)
+	assert.Nil(t, profile)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListInstructorCourses(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewInstructorRepository(mock)
+
+	mock.ExpectQuery("array_agg\\(DISTINCT s.letter ORDER BY s.letter\\)(.+)WHERE me.id = \\$1\\s+GROUP BY c.id, c.code, c.name, c.term").
+		WithArgs("instructor-1").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "code", "name", "term", "sections"}).
+			AddRow("course-1", "EECS2030", "Advanced OOP", models.TermFall, []string{"A", "B"}).
+			AddRow("course-2", "EECS3101", "Algorithms", models.TermWinter, []string{"M"}))
+
+	courses, err := repo.ListCourses(context.Background(), "instructor-1")
+	assert.NoError(t, err)
+	assert.Len(t, courses, 2)
+	assert.Equal(t, []string{"A", "B"}, courses[0].Sections)
+	assert.Equal(t, "EECS3101", courses[1].Code)
+
+	mock.ExpectQuery("FROM instructors me").
+		WithArgs("instructor-1").
+		WillReturnError(errors.New("db down"))
+
+	_, err = repo.ListCourses(context.Background(), "instructor-1")
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInstructorRepository_SetPhoto(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewInstructorRepository(mock)
+	photoID := dbtypes.NewNullString("0a1b2c")
+
+	mock.ExpectExec("UPDATE instructors i SET photo_id = \\$2(.+)FROM instructors me\\s+WHERE me.id = \\$1 AND i.first_name = me.first_name AND i.last_name = me.last_name").
+		WithArgs("instructor-1", photoID).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 3))
+	mock.ExpectExec("UPDATE instructors").
+		WithArgs("missing", dbtypes.NullString{}).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+
+	found, err := repo.SetPhoto(context.Background(), "instructor-1", photoID)
+	assert.NoError(t, err)
+	assert.True(t, found)
+
+	found, err = repo.SetPhoto(context.Background(), "missing", dbtypes.NullString{})
+	assert.NoError(t, err)
+	assert.False(t, found)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetInstructorsByIDs(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewInstructorRepository(mock)
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT (.+) FROM instructors i LEFT JOIN instructor_ratings ir (.+) WHERE i.id = ANY\\(\\$1\\) ORDER BY i.last_name, i.first_name, i.id").
+		WithArgs([]string{"instructor-1", "instructor-2"}).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "first_name", "last_name", "rate_my_prof_link", "section_id", "photo_id", "created_at", "updated_at", "rmp_id", "rating", "difficulty", "would_take_again", "num_ratings", "fetched_at"}).
+			AddRow("instructor-1", "John", "Doe", nil, nil, nil, now, now, nil, nil, nil, nil, nil, nil))
+
+	instructors, err := repo.GetByIDs(context.Background(), []string{"instructor-1", "instructor-2"})
+	assert.NoError(t, err)
+	assert.Len(t, instructors, 1)
+	assert.Equal(t, "Doe", instructors[0].LastName)
+	assert.Nil(t, instructors[0].RMP, "no rating without an RMP match")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
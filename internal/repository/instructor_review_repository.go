@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"yuplan/internal/id"
+	"yuplan/internal/models"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+// ErrDuplicateInstructorReview is returned when the email has already reviewed the instructor.
+var ErrDuplicateInstructorReview = errors.New("instructor already reviewed by this email")
+
+type InstructorReviewRepositoryInterface interface {
+	Create(ctx context.Context, instructorID string, review *models.InstructorReview) (bool, error)
+	ListByInstructor(ctx context.Context, instructorID string, limit, offset int) ([]models.InstructorReview, error)
+	GetStats(ctx context.Context, instructorID string) (models.InstructorReviewStats, error)
+}
+
+type instructorReviewDB interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// InstructorReviewRepository keeps instructor reviews under the instructor's
+// name. Like the rest of the instructor reads, every method takes the id of
+// any one of the instructor's rows and covers all rows with that name.
+type InstructorReviewRepository struct {
+	db instructorReviewDB
+}
+
+func NewInstructorReviewRepository(db instructorReviewDB) *InstructorReviewRepository {
+	return &InstructorReviewRepository{db: db}
+}
+
+// Create stores a review of the instructor with the given row id, filling in
+// its id and timestamps. It reports false without inserting when there is no
+// such instructor, and returns ErrDuplicateInstructorReview when the email has
+// already reviewed them.
+func (r *InstructorReviewRepository) Create(ctx context.Context, instructorID string, review *models.InstructorReview) (bool, error) {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	review.ID = id.New()
+	review.CreatedAt = time.Now()
+	review.UpdatedAt = review.CreatedAt
+	err := r.db.QueryRow(ctx,
+		`INSERT INTO instructor_reviews (id, first_name, last_name, email, author_name, clarity, helpfulness, workload, review_text, created_at, updated_at)
+		 SELECT $2, me.first_name, me.last_name, $3, $4, $5, $6, $7, $8, $9, $9
+		 FROM instructors me
+		 WHERE me.id = $1
+		 RETURNING id`,
+		instructorID, review.ID, review.Email, review.AuthorName,
+		review.Clarity, review.Helpfulness, review.Workload, review.ReviewText, review.CreatedAt,
+	).Scan(&review.ID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		review.ID = ""
+		return false, nil
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return false, ErrDuplicateInstructorReview
+	}
+	if err != nil {
+		return false, fmt.Errorf("insert instructor review: %w", err)
+	}
+	return true, nil
+}
+
+// ListByInstructor returns a page of the instructor's reviews, newest first.
+func (r *InstructorReviewRepository) ListByInstructor(ctx context.Context, instructorID string, limit, offset int) ([]models.InstructorReview, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	rows, err := r.db.Query(ctx,
+		`SELECT ir.id, ir.email, ir.author_name, ir.clarity, ir.helpfulness, ir.workload, ir.review_text, ir.created_at, ir.updated_at
+		 FROM instructors me
+		 INNER JOIN instructor_reviews ir ON ir.first_name = me.first_name AND ir.last_name = me.last_name
+		 WHERE me.id = $1
+		 ORDER BY ir.created_at DESC, ir.id
+		 LIMIT $2 OFFSET $3`,
+		instructorID, limit, offset,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query instructor reviews: %w", err)
+	}
+	defer rows.Close()
+
+	reviews := make([]models.InstructorReview, 0)
+	for rows.Next() {
+		var review models.InstructorReview
+		if err := rows.Scan(&review.ID, &review.Email, &review.AuthorName, &review.Clarity, &review.Helpfulness,
+			&review.Workload, &review.ReviewText, &review.CreatedAt, &review.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan instructor review: %w", err)
+		}
+		reviews = append(reviews, review)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate instructor reviews: %w", err)
+	}
+	return reviews, nil
+}
+
+// GetStats aggregates every review of the instructor.
+func (r *InstructorReviewRepository) GetStats(ctx context.Context, instructorID string) (models.InstructorReviewStats, error) {
+	ctx, cancel := withDeadline(ctx, opAggregate)
+	defer cancel()
+
+	var stats models.InstructorReviewStats
+	err := r.db.QueryRow(ctx,
+		`SELECT COUNT(ir.id), COALESCE(AVG(ir.clarity), 0), COALESCE(AVG(ir.helpfulness), 0), COALESCE(AVG(ir.workload), 0)
+		 FROM instructors me
+		 INNER JOIN instructor_reviews ir ON ir.first_name = me.first_name AND ir.last_name = me.last_name
+		 WHERE me.id = $1`,
+		instructorID,
+	).Scan(&stats.TotalReviews, &stats.AvgClarity, &stats.AvgHelpfulness, &stats.AvgWorkload)
+	if err != nil {
+		return stats, fmt.Errorf("query instructor review stats: %w", err)
+	}
+	return stats, nil
+}
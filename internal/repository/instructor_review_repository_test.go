@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"yuplan/internal/dbtypes"
+	"yuplan/internal/models"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstructorReviewRepository_Create(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewInstructorReviewRepository(mock)
+	review := &models.InstructorReview{Email: "student@my.yorku.ca", Clarity: 5, Helpfulness: 4, Workload: 2}
+
+	mock.ExpectQuery("INSERT INTO instructor_reviews (.+) SELECT \\$2, me.first_name, me.last_name, (.+) WHERE me.id = \\$1").
+		WithArgs("inst-1", pgxmock.AnyArg(), review.Email, review.AuthorName, 5, 4, 2, review.ReviewText, pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow("ir-1"))
+	mock.ExpectQuery("INSERT INTO instructor_reviews").WillReturnError(pgx.ErrNoRows)
+	mock.ExpectQuery("INSERT INTO instructor_reviews").WillReturnError(&pgconn.PgError{Code: "23505"})
+
+	created, err := repo.Create(context.Background(), "inst-1", review)
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, "ir-1", review.ID)
+
+	created, err = repo.Create(context.Background(), "missing", &models.InstructorReview{})
+	assert.NoError(t, err)
+	assert.False(t, created)
+
+	_, err = repo.Create(context.Background(), "inst-1", &models.InstructorReview{})
+	assert.ErrorIs(t, err, ErrDuplicateInstructorReview)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInstructorReviewRepository_ListByInstructor(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewInstructorReviewRepository(mock)
+	now := time.Now()
+
+	mock.ExpectQuery("FROM instructors me INNER JOIN instructor_reviews ir ON ir.first_name = me.first_name AND ir.last_name = me.last_name (.+) LIMIT \\$2 OFFSET \\$3").
+		WithArgs("inst-1", 10, 0).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "email", "author_name", "clarity", "helpfulness", "workload", "review_text", "created_at", "updated_at"}).
+			AddRow("ir-1", "student@my.yorku.ca", dbtypes.NullString{}, 5, 4, 2, dbtypes.NewNullString("Clear"), now, now))
+	mock.ExpectQuery("FROM instructors me").WillReturnError(errors.New("db down"))
+
+	reviews, err := repo.ListByInstructor(context.Background(), "inst-1", 10, 0)
+	assert.NoError(t, err)
+	assert.Len(t, reviews, 1)
+	assert.Equal(t, 2, reviews[0].Workload)
+	assert.False(t, reviews[0].AuthorName.Valid)
+
+	_, err = repo.ListByInstructor(context.Background(), "inst-1", 10, 0)
+	assert.ErrorContains(t, err, "query instructor reviews")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInstructorReviewRepository_GetStats(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewInstructorReviewRepository(mock)
+
+	mock.ExpectQuery("SELECT COUNT\\(ir.id\\), (.+) FROM instructors me").
+		WithArgs("inst-1").
+		WillReturnRows(pgxmock.NewRows([]string{"count", "clarity", "helpfulness", "workload"}).AddRow(2, 4.5, 3.0, 2.5))
+
+	stats, err := repo.GetStats(context.Background(), "inst-1")
+	assert.NoError(t, err)
+	assert.Equal(t, models.InstructorReviewStats{TotalReviews: 2, AvgClarity: 4.5, AvgHelpfulness: 3, AvgWorkload: 2.5}, stats)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
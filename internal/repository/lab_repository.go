@@ -25,6 +25,9 @@ func NewLabRepository(db labDB) *LabRepository {
 }
 
 func (r *LabRepository) GetBySectionID(ctx context.Context, sectionID string) ([]models.Lab, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
 	rows, err := r.db.Query(
 		ctx,
 		`SELECT id, section_id, catalog_number, times, created_at, updated_at
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/jackc/pgx/v4"
+)
+
+type LiteRepositoryInterface interface {
+	CourseSummaries(ctx context.Context, codes []string, since time.Time) ([]models.LiteCourse, error)
+}
+
+type liteDB interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+type LiteRepository struct {
+	db liteDB
+}
+
+func NewLiteRepository(db liteDB) *LiteRepository {
+	return &LiteRepository{db: db}
+}
+
+// CourseSummaries returns a summary of each known course in codes, ordered by
+// code, with review stats over published reviews since the given time.
+// Unknown codes are left out.
+func (r *LiteRepository) CourseSummaries(ctx context.Context, codes []string, since time.Time) ([]models.LiteCourse, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	rows, err := r.db.Query(ctx,
+		`WITH course AS (
+		     SELECT DISTINCT ON (code) code, name FROM courses
+		     WHERE code = ANY($1::text[])
+		     ORDER BY code, name
+		 ),
+		 review AS (
+		     SELECT course_code, liked, difficulty FROM reviews
+		     WHERE course_code = ANY($1::text[]) AND created_at >= $2 AND `+publishedFilter+`
+		 )
+		 SELECT course.code, course.name,
+		        ROUND(AVG(review.difficulty)::numeric, 1)::float8,
+		        COUNT(review.course_code),
+		        COUNT(*) FILTER (WHERE review.liked)
+		 FROM course
+		 LEFT JOIN review ON review.course_code = course.code
+		 GROUP BY course.code, course.name
+		 ORDER BY course.code`,
+		codes, since,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query lite courses: %w", err)
+	}
+	defer rows.Close()
+
+	courses := []models.LiteCourse{}
+	for rows.Next() {
+		var c models.LiteCourse
+		var likes int
+		if err := rows.Scan(&c.Code, &c.Name, &c.AvgDifficulty, &c.ReviewCount, &likes); err != nil {
+			return nil, fmt.Errorf("scan lite course: %w", err)
+		}
+		if c.ReviewCount > 0 {
+			pct := int(float64(likes) / float64(c.ReviewCount) * 100)
+			c.LikePercentage = &pct
+		}
+		courses = append(courses, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate lite courses: %w", err)
+	}
+	return courses, nil
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLiteRepository_CourseSummaries(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewLiteRepository(mock)
+	since := time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC)
+	codes := []string{"EECS2030", "MATH1013"}
+
+	avg := 3.5
+	mock.ExpectQuery("FROM courses (.+) FROM reviews (.+) LEFT JOIN review").
+		WithArgs(codes, since).
+		WillReturnRows(pgxmock.NewRows([]string{"code", "name", "avg_difficulty", "review_count", "likes"}).
+			AddRow("EECS2030", "Advanced Object Oriented Programming", &avg, 3, 2).
+			AddRow("MATH1013", "Applied Calculus I", (*float64)(nil), 0, 0))
+
+	courses, err := repo.CourseSummaries(context.Background(), codes, since)
+	assert.NoError(t, err)
+
+	pct := 66
+	assert.Equal(t, []models.LiteCourse{
+		{Code: "EECS2030", Name: "Advanced Object Oriented Programming", AvgDifficulty: &avg, LikePercentage: &pct, ReviewCount: 3},
+		{Code: "MATH1013", Name: "Applied Calculus I"},
+	}, courses)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLiteRepository_CourseSummaries_Error(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewLiteRepository(mock)
+
+	mock.ExpectQuery("FROM courses").WillReturnError(errors.New("db down"))
+
+	_, err = repo.CourseSummaries(context.Background(), []string{"EECS2030"}, time.Time{})
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
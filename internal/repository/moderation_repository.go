@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/jackc/pgx/v4"
+)
+
+type ModerationRepositoryInterface interface {
+	ListRules(ctx context.Context) ([]models.ModerationRule, error)
+	SetRules(ctx context.Context, rules []models.ModerationRule) ([]models.ModerationRule, error)
+	FirstReviewAt(ctx context.Context, email string) (time.Time, bool, error)
+}
+
+type moderationDB interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+type ModerationRepository struct {
+	db moderationDB
+}
+
+func NewModerationRepository(db moderationDB) *ModerationRepository {
+	return &ModerationRepository{db: db}
+}
+
+const moderationRuleColumns = `id, position, name, action, enabled, no_profanity, email_domains, min_author_age_days, max_text_length, updated_at`
+
+// ListRules returns every moderation rule in evaluation order.
+func (r *ModerationRepository) ListRules(ctx context.Context) ([]models.ModerationRule, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	rows, err := r.db.Query(ctx,
+		`SELECT `+moderationRuleColumns+` FROM moderation_rules ORDER BY position, id`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query moderation rules: %w", err)
+	}
+	return scanModerationRules(rows)
+}
+
+// SetRules replaces every moderation rule with rules, in one statement so
+// reviews are never moderated against a half-saved list. It returns the saved
+// rules in evaluation order.
+func (r *ModerationRepository) SetRules(ctx context.Context, rules []models.ModerationRule) ([]models.ModerationRule, error) {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	payload, err := json.Marshal(rules)
+	if err != nil {
+		return nil, fmt.Errorf("encode moderation rules: %w", err)
+	}
+	rows, err := r.db.Query(ctx,
+		`WITH cleared AS (
+			DELETE FROM moderation_rules
+		), saved AS (
+			INSERT INTO moderation_rules (position, name, action, enabled, no_profanity, email_domains, min_author_age_days, max_text_length)
+			SELECT position, name, action, enabled, no_profanity, COALESCE(email_domains, '{}'), min_author_age_days, max_text_length
+			FROM jsonb_to_recordset($1::jsonb) AS r(
+				position INTEGER, name TEXT, action TEXT, enabled BOOLEAN, no_profanity BOOLEAN,
+				email_domains TEXT[], min_author_age_days INTEGER, max_text_length INTEGER
+			)
+			RETURNING `+moderationRuleColumns+`
+		)
+		SELECT `+moderationRuleColumns+` FROM saved ORDER BY position, id`,
+		string(payload),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("replace moderation rules: %w", err)
+	}
+	return scanModerationRules(rows)
+}
+
+// FirstReviewAt returns when email submitted its first review, and false if
+// it never has.
+func (r *ModerationRepository) FirstReviewAt(ctx context.Context, email string) (time.Time, bool, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	var first *time.Time
+	if err := r.db.QueryRow(ctx,
+		`SELECT MIN(created_at) FROM reviews WHERE email = $1`,
+		email,
+	).Scan(&first); err != nil {
+		return time.Time{}, false, fmt.Errorf("query first review: %w", err)
+	}
+	if first == nil {
+		return time.Time{}, false, nil
+	}
+	return *first, true, nil
+}
+
+func scanModerationRules(rows pgx.Rows) ([]models.ModerationRule, error) {
+	defer rows.Close()
+
+	rules := []models.ModerationRule{}
+	for rows.Next() {
+		var rule models.ModerationRule
+		if err := rows.Scan(
+			&rule.ID, &rule.Position, &rule.Name, &rule.Action, &rule.Enabled, &rule.NoProfanity,
+			&rule.EmailDomains, &rule.MinAuthorAgeDays, &rule.MaxTextLength, &rule.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan moderation rule: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate moderation rules: %w", err)
+	}
+	return rules, nil
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var moderationRuleRowColumns = []string{
+	"id", "position", "name", "action", "enabled", "no_profanity", "email_domains", "min_author_age_days", "max_text_length", "updated_at",
+}
+
+func TestModerationRepository_ListRules(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewModerationRepository(mock)
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT (.+) FROM moderation_rules ORDER BY position, id").
+		WillReturnRows(pgxmock.NewRows(moderationRuleRowColumns).
+			AddRow(int64(1), 1, "Trusted", models.ModerationActionApprove, true, true, []string{"yorku.ca"}, 0, 2000, now).
+			AddRow(int64(2), 2, "New authors", models.ModerationActionQueue, true, false, []string{}, 0, 0, now))
+
+	rules, err := repo.ListRules(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, rules, 2)
+	assert.Equal(t, []string{"yorku.ca"}, rules[0].EmailDomains)
+	assert.Equal(t, models.ModerationActionQueue, rules[1].Action)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestModerationRepository_SetRules(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewModerationRepository(mock)
+	now := time.Now()
+	rules := []models.ModerationRule{
+		{Position: 1, Name: "Trusted", Action: models.ModerationActionApprove, Enabled: true, EmailDomains: []string{"yorku.ca"}},
+	}
+
+	mock.ExpectQuery("WITH cleared AS \\(\\s+DELETE FROM moderation_rules\\s+\\), saved AS \\(\\s+INSERT INTO moderation_rules (.+) FROM jsonb_to_recordset\\(\\$1::jsonb\\)").
+		WithArgs(pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows(moderationRuleRowColumns).
+			AddRow(int64(7), 1, "Trusted", models.ModerationActionApprove, true, false, []string{"yorku.ca"}, 0, 0, now))
+
+	saved, err := repo.SetRules(context.Background(), rules)
+	assert.NoError(t, err)
+	assert.Len(t, saved, 1)
+	assert.Equal(t, int64(7), saved[0].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestModerationRepository_SetRules_Error(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewModerationRepository(mock)
+
+	mock.ExpectQuery("WITH cleared AS").WillReturnError(errors.New("db down"))
+
+	_, err = repo.SetRules(context.Background(), nil)
+	assert.ErrorContains(t, err, "replace moderation rules")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestModerationRepository_FirstReviewAt(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewModerationRepository(mock)
+	first := time.Date(2025, 9, 20, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("SELECT MIN\\(created_at\\) FROM reviews WHERE email = \\$1").
+		WithArgs("student@yorku.ca").
+		WillReturnRows(pgxmock.NewRows([]string{"min"}).AddRow(&first))
+	mock.ExpectQuery("SELECT MIN\\(created_at\\) FROM reviews WHERE email = \\$1").
+		WithArgs("new@yorku.ca").
+		WillReturnRows(pgxmock.NewRows([]string{"min"}).AddRow(nil))
+
+	at, ok, err := repo.FirstReviewAt(context.Background(), "student@yorku.ca")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, first, at)
+
+	_, ok, err = repo.FirstReviewAt(context.Background(), "new@yorku.ca")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
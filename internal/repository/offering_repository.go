@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"yuplan/internal/models"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+type OfferingRepositoryInterface interface {
+	ListOfferings(ctx context.Context) ([]models.CourseOffering, error)
+	SaveSummaries(ctx context.Context, summaries []models.OfferingSummary) error
+	GetSummary(ctx context.Context, courseCode string) (*models.OfferingSummary, error)
+}
+
+type offeringDB interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+type OfferingRepository struct {
+	db offeringDB
+}
+
+func NewOfferingRepository(db offeringDB) *OfferingRepository {
+	return &OfferingRepository{db: db}
+}
+
+// ListOfferings returns the full offering history ordered by code, so each course's rows are adjacent.
+func (r *OfferingRepository) ListOfferings(ctx context.Context) ([]models.CourseOffering, error) {
+	ctx, cancel := withDeadline(ctx, opAggregate)
+	defer cancel()
+
+	rows, err := r.db.Query(ctx,
+		`SELECT code, academic_year, term
+		 FROM course_offerings
+		 ORDER BY code, academic_year, term`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query course_offerings: %w", err)
+	}
+	defer rows.Close()
+
+	offerings := make([]models.CourseOffering, 0)
+	for rows.Next() {
+		var o models.CourseOffering
+		if err := rows.Scan(&o.Code, &o.AcademicYear, &o.Term); err != nil {
+			return nil, fmt.Errorf("scan course_offering: %w", err)
+		}
+		offerings = append(offerings, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate course_offerings: %w", err)
+	}
+	return offerings, nil
+}
+
+// SaveSummaries upserts summaries in a single round trip.
+func (r *OfferingRepository) SaveSummaries(ctx context.Context, summaries []models.OfferingSummary) error {
+	ctx, cancel := withDeadline(ctx, opAggregate)
+	defer cancel()
+
+	if len(summaries) == 0 {
+		return nil
+	}
+
+	codes := make([]string, len(summaries))
+	lastYears := make([]int32, len(summaries))
+	lastTerms := make([]string, len(summaries))
+	frequencies := make([]string, len(summaries))
+	terms := make([]string, len(summaries)) // comma-joined; text[][] can't hold ragged arrays
+	yearsObserved := make([]int32, len(summaries))
+	for i, s := range summaries {
+		codes[i] = s.Code
+		lastYears[i] = int32(s.LastOfferedYear)
+		lastTerms[i] = s.LastOfferedTerm
+		frequencies[i] = s.Frequency
+		terms[i] = strings.Join(s.Terms, ",")
+		yearsObserved[i] = int32(s.YearsObserved)
+	}
+
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO course_offering_summaries (code, last_offered_year, last_offered_term, frequency, terms, years_observed, updated_at)
+		 SELECT c, y, lt, f, string_to_array(t, ','), n, NOW()
+		 FROM unnest($1::text[], $2::int[], $3::text[], $4::text[], $5::text[], $6::int[]) AS s(c, y, lt, f, t, n)
+		 ON CONFLICT (code) DO UPDATE
+		 SET last_offered_year = EXCLUDED.last_offered_year,
+		     last_offered_term = EXCLUDED.last_offered_term,
+		     frequency = EXCLUDED.frequency,
+		     terms = EXCLUDED.terms,
+		     years_observed = EXCLUDED.years_observed,
+		     updated_at = EXCLUDED.updated_at`,
+		codes, lastYears, lastTerms, frequencies, terms, yearsObserved,
+	)
+	if err != nil {
+		return fmt.Errorf("save offering summaries: %w", err)
+	}
+	return nil
+}
+
+// GetSummary returns the offering summary for a course code, or nil if there is none.
+func (r *OfferingRepository) GetSummary(ctx context.Context, courseCode string) (*models.OfferingSummary, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	// Normalize to match regardless of spaces/case, like CourseRepository.GetByCode
+	normalized := strings.ToLower(strings.ReplaceAll(courseCode, " ", ""))
+
+	var s models.OfferingSummary
+	err := r.db.QueryRow(ctx,
+		`SELECT code, last_offered_year, last_offered_term, frequency, terms, years_observed, updated_at
+		 FROM course_offering_summaries
+		 WHERE REPLACE(LOWER(code), ' ', '') = $1`,
+		normalized,
+	).Scan(&s.Code, &s.LastOfferedYear, &s.LastOfferedTerm, &s.Frequency, &s.Terms, &s.YearsObserved, &s.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scan offering summary: %w", err)
+	}
+	return &s, nil
+}
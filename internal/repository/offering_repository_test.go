@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOfferingRepository_ListOfferings(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewOfferingRepository(mock)
+
+	mock.ExpectQuery("SELECT code, academic_year, term\\s+FROM course_offerings\\s+ORDER BY code").
+		WillReturnRows(pgxmock.NewRows([]string{"code", "academic_year", "term"}).
+			AddRow("EECS2030", 2024, "F").
+			AddRow("EECS2030", 2025, "F"))
+
+	offerings, err := repo.ListOfferings(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []models.CourseOffering{
+		{Code: "EECS2030", AcademicYear: 2024, Term: "F"},
+		{Code: "EECS2030", AcademicYear: 2025, Term: "F"},
+	}, offerings)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOfferingRepository_SaveSummaries(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewOfferingRepository(mock)
+
+	mock.ExpectExec("INSERT INTO course_offering_summaries(.+)ON CONFLICT \\(code\\) DO UPDATE").
+		WithArgs([]string{"EECS2030", "MATH1013"}, []int32{2025, 2024}, []string{"W", "F"},
+			[]string{"annual", "single"}, []string{"F,W", "F"}, []int32{3, 1}).
+		WillReturnResult(pgxmock.NewResult("INSERT", 2))
+
+	err = repo.SaveSummaries(context.Background(), []models.OfferingSummary{
+		{Code: "EECS2030", LastOfferedYear: 2025, LastOfferedTerm: "W", Frequency: "annual", Terms: []string{"F", "W"}, YearsObserved: 3},
+		{Code: "MATH1013", LastOfferedYear: 2024, LastOfferedTerm: "F", Frequency: "single", Terms: []string{"F"}, YearsObserved: 1},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	assert.NoError(t, repo.SaveSummaries(context.Background(), nil))
+}
+
+func TestOfferingRepository_GetSummary(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewOfferingRepository(mock)
+	now := time.Now()
+
+	mock.ExpectQuery("FROM course_offering_summaries\\s+WHERE REPLACE\\(LOWER\\(code\\), ' ', ''\\) = \\$1").
+		WithArgs("eecs2030").
+		WillReturnRows(pgxmock.NewRows([]string{"code", "last_offered_year", "last_offered_term", "frequency", "terms", "years_observed", "updated_at"}).
+			AddRow("EECS2030", 2025, "W", "annual", []string{"F", "W"}, 3, now))
+	mock.ExpectQuery("FROM course_offering_summaries").
+		WithArgs("new1000").
+		WillReturnError(pgx.ErrNoRows)
+	mock.ExpectQuery("FROM course_offering_summaries").
+		WithArgs("eecs2030").
+		WillReturnError(errors.New("db down"))
+
+	summary, err := repo.GetSummary(context.Background(), "EECS 2030")
+	assert.NoError(t, err)
+	assert.Equal(t, "annual", summary.Frequency)
+	assert.Equal(t, []string{"F", "W"}, summary.Terms)
+
+	summary, err = repo.GetSummary(context.Background(), "NEW1000")
+	assert.NoError(t, err)
+	assert.Nil(t, summary)
+
+	_, err = repo.GetSummary(context.Background(), "EECS2030")
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/jackc/pgx/v4"
+)
+
+type PublicStatsRepositoryInterface interface {
+	PublicStats(ctx context.Context, weekStart time.Time, departments int) (*models.PublicStats, error)
+}
+
+type publicStatsDB interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+type PublicStatsRepository struct {
+	db publicStatsDB
+}
+
+func NewPublicStatsRepository(db publicStatsDB) *PublicStatsRepository {
+	return &PublicStatsRepository{db: db}
+}
+
+// PublicStats counts indexed courses and published reviews, the reviews
+// created since weekStart, and the departments with the most reviews. A
+// department is the letters of a course code.
+func (r *PublicStatsRepository) PublicStats(ctx context.Context, weekStart time.Time, departments int) (*models.PublicStats, error) {
+	ctx, cancel := withDeadline(ctx, opAggregate)
+	defer cancel()
+
+	stats := &models.PublicStats{MostReviewedDepts: []models.DepartmentReviewCount{}}
+	err := r.db.QueryRow(ctx,
+		`SELECT (SELECT COUNT(DISTINCT code) FROM courses),
+		        COUNT(*),
+		        COUNT(*) FILTER (WHERE created_at >= $1)
+		 FROM reviews
+		 WHERE `+publishedFilter,
+		weekStart,
+	).Scan(&stats.Courses, &stats.Reviews, &stats.ReviewsThisWeek)
+	if err != nil {
+		return nil, fmt.Errorf("query public stats: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx,
+		`SELECT substring(course_code from '^[A-Z]+') AS department, COUNT(*)::int AS reviews
+		 FROM reviews
+		 WHERE `+publishedFilter+`
+		 GROUP BY department
+		 ORDER BY reviews DESC, department
+		 LIMIT $1`,
+		departments,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query most reviewed departments: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var d models.DepartmentReviewCount
+		if err := rows.Scan(&d.Department, &d.Reviews); err != nil {
+			return nil, fmt.Errorf("scan department review count: %w", err)
+		}
+		stats.MostReviewedDepts = append(stats.MostReviewedDepts, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate department review counts: %w", err)
+	}
+	return stats, nil
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublicStatsRepository_PublicStats(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewPublicStatsRepository(mock)
+	weekStart := time.Date(2026, 10, 9, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`COUNT\(DISTINCT code\) FROM courses`).
+		WithArgs(weekStart).
+		WillReturnRows(pgxmock.NewRows([]string{"courses", "reviews", "week"}).AddRow(5321, 812, 37))
+	mock.ExpectQuery("GROUP BY department").
+		WithArgs(5).
+		WillReturnRows(pgxmock.NewRows([]string{"department", "reviews"}).
+			AddRow("EECS", 240).
+			AddRow("MATH", 133))
+
+	stats, err := repo.PublicStats(context.Background(), weekStart, 5)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &models.PublicStats{
+		Courses:         5321,
+		Reviews:         812,
+		ReviewsThisWeek: 37,
+		MostReviewedDepts: []models.DepartmentReviewCount{
+			{Department: "EECS", Reviews: 240},
+			{Department: "MATH", Reviews: 133},
+		},
+	}, stats)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPublicStatsRepository_PublicStatsError(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewPublicStatsRepository(mock)
+	mock.ExpectQuery("FROM reviews").WillReturnError(errors.New("db down"))
+
+	_, err = repo.PublicStats(context.Background(), time.Now(), 5)
+	assert.ErrorContains(t, err, "query public stats")
+}
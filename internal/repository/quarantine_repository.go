@@ -0,0 +1,236 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"yuplan/internal/models"
+	"yuplan/internal/seedcheck"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+type QuarantineRepositoryInterface interface {
+	List(ctx context.Context, status string) ([]models.QuarantinedRecord, error)
+	Get(ctx context.Context, id string) (*models.QuarantinedRecord, error)
+	Conflicts(ctx context.Context, code, term string, catalogNumbers []string) ([]string, error)
+	Release(ctx context.Context, id string, record json.RawMessage, plan seedcheck.Plan) (string, error)
+	Dismiss(ctx context.Context, id string) (bool, error)
+	Add(ctx context.Context, source, key string, record json.RawMessage, reasons []string) (bool, error)
+}
+
+type quarantineDB interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+type QuarantineRepository struct {
+	db quarantineDB
+}
+
+func NewQuarantineRepository(db quarantineDB) *QuarantineRepository {
+	return &QuarantineRepository{db: db}
+}
+
+const quarantineColumns = `id, source, record_key, record::text, reasons, status, created_at, resolved_at`
+
+// List returns quarantined records with the given status, or all of them when status is empty.
+func (r *QuarantineRepository) List(ctx context.Context, status string) ([]models.QuarantinedRecord, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	rows, err := r.db.Query(ctx,
+		`SELECT `+quarantineColumns+`
+		 FROM seed_quarantine
+		 WHERE $1 = '' OR status = $1
+		 ORDER BY source, record_key`,
+		status,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query quarantine: %w", err)
+	}
+	defer rows.Close()
+
+	records := []models.QuarantinedRecord{}
+	for rows.Next() {
+		q, err := scanQuarantinedRecord(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, *q)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate quarantine: %w", err)
+	}
+	return records, nil
+}
+
+// Get returns one quarantined record, or nil if it doesn't exist.
+func (r *QuarantineRepository) Get(ctx context.Context, id string) (*models.QuarantinedRecord, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	q, err := scanQuarantinedRecord(r.db.QueryRow(ctx,
+		`SELECT `+quarantineColumns+` FROM seed_quarantine WHERE id = $1`,
+		id,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return q, err
+}
+
+// Conflicts returns the reasons a record can't be inserted alongside the
+// current data: the course already exists for that term, or one of its
+// catalog numbers already belongs to another activity.
+func (r *QuarantineRepository) Conflicts(ctx context.Context, code, term string, catalogNumbers []string) ([]string, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	rows, err := r.db.Query(ctx,
+		`(SELECT format('course %s %s already exists', code, term)
+		  FROM courses WHERE code = $1 AND term = $2 LIMIT 1)
+		 UNION ALL
+		 (SELECT DISTINCT format('duplicate catalog number %s (also %s %s)', sa.catalog_number, c.code, c.term)
+		  FROM section_activities sa
+		  JOIN sections s ON s.id = sa.section_id
+		  JOIN courses c ON c.id = s.course_id
+		  WHERE sa.catalog_number = ANY($3::text[]))`,
+		code, term, catalogNumbers,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query quarantine conflicts: %w", err)
+	}
+	defer rows.Close()
+
+	var conflicts []string
+	for rows.Next() {
+		var reason string
+		if err := rows.Scan(&reason); err != nil {
+			return nil, fmt.Errorf("scan quarantine conflict: %w", err)
+		}
+		conflicts = append(conflicts, reason)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate quarantine conflicts: %w", err)
+	}
+	return conflicts, nil
+}
+
+// Release inserts a validated record's course, sections, activities and
+// instructors and marks the quarantine entry reprocessed, all in one
+// statement. It returns the new course id, or "" if the entry is not pending.
+func (r *QuarantineRepository) Release(ctx context.Context, id string, record json.RawMessage, plan seedcheck.Plan) (string, error) {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	act, inst := planColumns(plan)
+	var courseID string
+	err := r.db.QueryRow(ctx,
+		`WITH target AS (
+		     SELECT id FROM seed_quarantine WHERE id = $1 AND status = 'pending' FOR UPDATE
+		 ),
+		 course AS (
+		     INSERT INTO courses (id, name, code, credits, description, faculty, term)
+		     SELECT gen_random_uuid(), $3, $4, $5, NULLIF($6, ''), NULLIF($7, ''), $8 FROM target
+		     RETURNING id
+		 ),
+		 section AS (
+		     INSERT INTO sections (id, course_id, letter)
+		     SELECT gen_random_uuid(), course.id, l.letter FROM course, unnest($9::text[]) AS l(letter)
+		     RETURNING id, letter
+		 ),
+		 activity AS (
+		     INSERT INTO section_activities (id, course_type, section_id, catalog_number, times)
+		     SELECT gen_random_uuid(), a.course_type, section.id, a.catalog_number, NULLIF(a.times, '')
+		     FROM unnest($10::text[], $11::text[], $12::text[], $13::text[]) AS a(letter, course_type, catalog_number, times)
+		     JOIN section ON section.letter = a.letter
+		 ),
+		 instructor AS (
+		     INSERT INTO instructors (id, first_name, last_name, rate_my_prof_link, section_id)
+		     SELECT gen_random_uuid(), i.first_name, i.last_name, NULLIF(i.link, ''), section.id
+		     FROM unnest($14::text[], $15::text[], $16::text[], $17::text[]) AS i(letter, first_name, last_name, link)
+		     JOIN section ON section.letter = i.letter
+		 )
+		 UPDATE seed_quarantine q
+		 SET status = 'reprocessed', record = $2::jsonb, resolved_at = NOW()
+		 FROM course
+		 WHERE q.id = $1
+		 RETURNING course.id`,
+		id, string(record),
+		plan.Name, plan.Code, plan.Credits, plan.Description, plan.Faculty, plan.Term,
+		plan.Letters,
+		act.letters, act.types, act.catalogs, act.times,
+		inst.letters, inst.first, inst.last, inst.links,
+	).Scan(&courseID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("release quarantined record: %w", err)
+	}
+	return courseID, nil
+}
+
+// Add quarantines a record that failed validation while loading source. A
+// record already quarantined from the same source with the same content,
+// whatever its status, isn't added again; the result reports whether it was.
+func (r *QuarantineRepository) Add(ctx context.Context, source, key string, record json.RawMessage, reasons []string) (bool, error) {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	tag, err := r.db.Exec(ctx,
+		`INSERT INTO seed_quarantine (source, record_key, record, reasons)
+		 SELECT $1, $2, $3::jsonb, $4
+		 WHERE NOT EXISTS (
+		     SELECT 1 FROM seed_quarantine
+		     WHERE source = $1 AND record_key = $2 AND record = $3::jsonb
+		 )`,
+		source, key, string(record), reasons,
+	)
+	if err != nil {
+		return false, fmt.Errorf("quarantine record: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// Dismiss marks a pending record as intentionally left out and reports whether it was pending.
+func (r *QuarantineRepository) Dismiss(ctx context.Context, id string) (bool, error) {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	tag, err := r.db.Exec(ctx,
+		`UPDATE seed_quarantine SET status = 'dismissed', resolved_at = NOW()
+		 WHERE id = $1 AND status = 'pending'`,
+		id,
+	)
+	if err != nil {
+		return false, fmt.Errorf("dismiss quarantined record: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func scanQuarantinedRecord(row pgx.Row) (*models.QuarantinedRecord, error) {
+	var q models.QuarantinedRecord
+	var record string
+	if err := row.Scan(
+		&q.ID,
+		&q.Source,
+		&q.RecordKey,
+		&record,
+		&q.Reasons,
+		&q.Status,
+		&q.CreatedAt,
+		&q.ResolvedAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("scan quarantined record: %w", err)
+	}
+	q.Record = json.RawMessage(record)
+	return &q, nil
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+	"yuplan/internal/seedcheck"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var quarantineRowColumns = []string{"id", "source", "record_key", "record", "reasons", "status", "created_at", "resolved_at"}
+
+func TestQuarantineRepository_List(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewQuarantineRepository(mock)
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT (.+) FROM seed_quarantine WHERE \\$1 = '' OR status = \\$1").
+		WithArgs("pending").
+		WillReturnRows(pgxmock.NewRows(quarantineRowColumns).
+			AddRow("q-1", "summer-2026/schulich.json", "SUST W2", `{"department": "SUST"}`, []string{"missing courseId"}, "pending", now, nil))
+
+	records, err := repo.List(context.Background(), "pending")
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+	assert.Equal(t, "SUST W2", records[0].RecordKey)
+	assert.JSONEq(t, `{"department": "SUST"}`, string(records[0].Record))
+	assert.Equal(t, []string{"missing courseId"}, records[0].Reasons)
+	assert.False(t, records[0].ResolvedAt.Valid)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestQuarantineRepository_Get_NotFound(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewQuarantineRepository(mock)
+
+	mock.ExpectQuery("SELECT (.+) FROM seed_quarantine WHERE id = \\$1").
+		WithArgs("missing").
+		WillReturnError(pgx.ErrNoRows)
+
+	record, err := repo.Get(context.Background(), "missing")
+	assert.NoError(t, err)
+	assert.Nil(t, record)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestQuarantineRepository_Conflicts(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewQuarantineRepository(mock)
+
+	mock.ExpectQuery("already exists(.+)duplicate catalog number").
+		WithArgs("EECS2030", "F", []string{"K12A01"}).
+		WillReturnRows(pgxmock.NewRows([]string{"format"}).
+			AddRow("duplicate catalog number K12A01 (also EECS1012 F)"))
+
+	conflicts, err := repo.Conflicts(context.Background(), "EECS2030", "F", []string{"K12A01"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"duplicate catalog number K12A01 (also EECS1012 F)"}, conflicts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestQuarantineRepository_Release(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewQuarantineRepository(mock)
+	record := json.RawMessage(`{"department": "EECS"}`)
+	plan := seedcheck.Plan{
+		Code: "EECS2030", Name: "OOP", Credits: 3, Term: "F",
+		Letters:     []string{"A"},
+		Activities:  []seedcheck.PlannedActivity{{Letter: "A", CourseType: "LECT", CatalogNumber: "K12A01"}},
+		Instructors: []seedcheck.PlannedInstructor{{Letter: "A", FirstName: "Jane", LastName: "Doe"}},
+	}
+
+	mock.ExpectQuery("INSERT INTO courses (.+) INSERT INTO sections (.+) INSERT INTO section_activities (.+) INSERT INTO instructors (.+) UPDATE seed_quarantine").
+		WithArgs("q-1", string(record), "OOP", "EECS2030", 3.0, "", "", "F",
+			[]string{"A"},
+			[]string{"A"}, []string{"LECT"}, []string{"K12A01"}, []string{""},
+			[]string{"A"}, []string{"Jane"}, []string{"Doe"}, []string{""}).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow("course-1"))
+
+	courseID, err := repo.Release(context.Background(), "q-1", record, plan)
+	assert.NoError(t, err)
+	assert.Equal(t, "course-1", courseID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestQuarantineRepository_Release_NotPending(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewQuarantineRepository(mock)
+
+	mock.ExpectQuery("UPDATE seed_quarantine").WillReturnError(pgx.ErrNoRows)
+
+	courseID, err := repo.Release(context.Background(), "q-1", json.RawMessage(`{}`), seedcheck.Plan{})
+	assert.NoError(t, err)
+	assert.Empty(t, courseID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestQuarantineRepository_Dismiss(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewQuarantineRepository(mock)
+
+	mock.ExpectExec("UPDATE seed_quarantine SET status = 'dismissed'").
+		WithArgs("q-1").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectExec("UPDATE seed_quarantine SET status = 'dismissed'").
+		WithArgs("q-2").
+		WillReturnError(errors.New("db down"))
+
+	dismissed, err := repo.Dismiss(context.Background(), "q-1")
+	assert.NoError(t, err)
+	assert.True(t, dismissed)
+
+	_, err = repo.Dismiss(context.Background(), "q-2")
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestQuarantineRepository_Add(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewQuarantineRepository(mock)
+	record := json.RawMessage(`{"department": "EECS"}`)
+	reasons := []string{"missing courseId"}
+
+	mock.ExpectExec("INSERT INTO seed_quarantine (.+) WHERE NOT EXISTS").
+		WithArgs("FW2025/FW2025LE.html", "EECS? F", string(record), reasons).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectExec("INSERT INTO seed_quarantine").
+		WithArgs("FW2025/FW2025LE.html", "EECS? F", string(record), reasons).
+		WillReturnResult(pgxmock.NewResult("INSERT", 0))
+
+	added, err := repo.Add(context.Background(), "FW2025/FW2025LE.html", "EECS? F", record, reasons)
+	assert.NoError(t, err)
+	assert.True(t, added)
+
+	added, err = repo.Add(context.Background(), "FW2025/FW2025LE.html", "EECS? F", record, reasons)
+	assert.NoError(t, err)
+	assert.False(t, added, "the same record isn't quarantined twice")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,258 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"yuplan/internal/id"
+	"yuplan/internal/models"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+type ReportRepositoryInterface interface {
+	Create(ctx context.Context, report *models.Report) (bool, error)
+	List(ctx context.Context, status string) ([]models.Report, error)
+	Close(ctx context.Context, id, status string) (*models.Report, error)
+}
+
+type reportDB interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+type ReportRepository struct {
+	db reportDB
+}
+
+func NewReportRepository(db reportDB) *ReportRepository {
+	return &ReportRepository{db: db}
+}
+
+// Create files a report, filling in its id, status and creation time, and
+// records a reported event for a reported review in the same statement. It
+// reports false without inserting when the referenced entity doesn't exist.
+func (r *ReportRepository) Create(ctx context.Context, report *models.Report) (bool, error) {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	report.ID = id.New()
+	err := r.db.QueryRow(ctx,
+		`WITH inserted AS (
+		     INSERT INTO reports (id, type, entity_type, entity_id, reason, details, email)
+		     SELECT $6, $1, $2, $3, $7, $4, $5
+		     WHERE CASE $2
+		         WHEN 'course' THEN EXISTS (SELECT 1 FROM courses WHERE id = $3::uuid)
+		         WHEN 'instructor' THEN EXISTS (SELECT 1 FROM instructors WHERE id = $3::uuid)
+		         WHEN 'review' THEN EXISTS (SELECT 1 FROM reviews WHERE id = $3::uuid)
+		     END
+		     RETURNING id, entity_type, entity_id, type, reason, status, created_at
+		 ),
+		 event AS (
+		     INSERT INTO review_events (review_id, event, details)
+		     SELECT entity_id, $8, jsonb_build_object('report_id', id, 'type', type, 'reason', reason)
+		     FROM inserted WHERE entity_type = 'review'
+		 )
+		 SELECT id, status, created_at FROM inserted`,
+		report.Type, report.EntityType, report.EntityID, report.Details, report.Email, report.ID, report.Reason,
+		models.ReviewEventReported,
+	).Scan(&report.ID, &report.Status, &report.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		report.ID = ""
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("insert report: %w", err)
+	}
+	return true, nil
+}
+
+// List returns reports with the given status, or all of them when status is
+// empty, oldest first so the queue is worked in order.
+func (r *ReportRepository) List(ctx context.Context, status string) ([]models.Report, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	rows, err := r.db.Query(ctx,
+		`SELECT r.id, r.type, r.entity_type, r.entity_id,
+		        COALESCE(c.code || ' ' || c.term, i.first_name || ' ' || i.last_name, rv.course_code, ''),
+		        r.reason, r.details, r.email, r.status, r.created_at, r.resolved_at
+		 FROM reports r
+		 LEFT JOIN courses c ON r.entity_type = 'course' AND c.id = r.entity_id
+		 LEFT JOIN instructors i ON r.entity_type = 'instructor' AND i.id = r.entity_id
+		 LEFT JOIN reviews rv ON r.entity_type = 'review' AND rv.id = r.entity_id
+		 WHERE $1 = '' OR r.status = $1
+		 ORDER BY r.created_at, r.id`,
+		status,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query reports: %w", err)
+	}
+	defer rows.Close()
+
+	reports := []models.Report{}
+	for rows.Next() {
+		var report models.Report
+		if err := rows.Scan(
+			&report.ID,
+			&report.Type,
+			&report.EntityType,
+			&report.EntityID,
+			&report.EntityLabel,
+			&report.Reason,
+			&report.Details,
+			&report.Email,
+			&report.Status,
+			&report.CreatedAt,
+			&report.ResolvedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan report: %w", err)
+		}
+		reports = append(reports, report)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate reports: %w", err)
+	}
+	return reports, nil
+}
+
+// Close moves an open report to status (resolved or dismissed) and returns
+// it, or nil if it wasn't open. EntityLabel is left empty.
+func (r *ReportRepository) Close(ctx context.Context, id, status string) (*models.Report, error) {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	var report models.Report
+	err := r.db.QueryRow(ctx,
+		`UPDATE reports SET status = $2, resolved_at = NOW()
+		 WHERE id = $1 AND status = 'open'
+		 RETURNING id, type, entity_type, entity_id, reason, details, email, status, created_at, resolved_at`,
+		id, status,
+	).Scan(
+		&report.ID,
+		&report.Type,
+		&report.EntityType,
+		&report.EntityID,
+		&report.Reason,
+		&report.Details,
+		&report.Email,
+		&report.Status,
+		&report.CreatedAt,
+		&report.ResolvedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("close report: %w", err)
+	}
+	return &report, nil
+}
+
+// Trends counts the reviews reported since since in each interval (one of
+// models.TrendIntervals), by reason, oldest period first. Periods without
+// reports are included with zero counts so charts have no gaps.
+func (r *ReportRepository) Trends(ctx context.Context, since time.Time, interval string) ([]models.ReportTrend, error) {
+	ctx, cancel := withDeadline(ctx, opAggregate)
+	defer cancel()
+
+	rows, err := r.db.Query(ctx,
+		`SELECT p.period, r.reason, COUNT(r.id)
+		 FROM generate_series(date_trunc($2, $1::timestamp), date_trunc($2, NOW()::timestamp), ('1 ' || $2)::interval) AS p(period)
+		 LEFT JOIN reports r ON r.entity_type = 'review' AND r.created_at >= $1 AND date_trunc($2, r.created_at) = p.period
+		 GROUP BY p.period, r.reason
+		 ORDER BY p.period, r.reason`,
+		since, interval,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query report trends: %w", err)
+	}
+	defer rows.Close()
+
+	trends := []models.ReportTrend{}
+	for rows.Next() {
+		var period time.Time
+		var reason *string
+		var count int
+		if err := rows.Scan(&period, &reason, &count); err != nil {
+			return nil, fmt.Errorf("scan report trend: %w", err)
+		}
+		if len(trends) == 0 || !trends[len(trends)-1].PeriodStart.Equal(period) {
+			reasons := make(map[string]int, len(models.ReportReasons))
+			for _, name := range models.ReportReasons {
+				reasons[name] = 0
+			}
+			trends = append(trends, models.ReportTrend{PeriodStart: period, Reasons: reasons})
+		}
+		if reason != nil {
+			trend := &trends[len(trends)-1]
+			trend.Reasons[*reason] += count
+			trend.Total += count
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate report trends: %w", err)
+	}
+	return trends, nil
+}
+
+// HideReview moves an approved review back to pending moderation once it has
+// at least threshold open reports, recording a moderated event in the same
+// statement, and reports whether it did.
+func (r *ReportRepository) HideReview(ctx context.Context, reviewID string, threshold int) (bool, error) {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	var hidden bool
+	err := r.db.QueryRow(ctx,
+		`WITH hidden AS (
+		     UPDATE reviews SET moderation = 'pending', hidden_at = NOW()
+		     WHERE id = $1 AND moderation = 'approved'
+		       AND (SELECT COUNT(*) FROM reports
+		            WHERE entity_type = 'review' AND entity_id = $1 AND status = 'open') >= $2
+		     RETURNING id
+		 ),
+		 event AS (
+		     INSERT INTO review_events (review_id, event, details)
+		     SELECT id, $3, jsonb_build_object('action', 'hide', 'open_reports', $2::int) FROM hidden
+		 )
+		 SELECT EXISTS (SELECT 1 FROM hidden)`,
+		reviewID, threshold, models.ReviewEventModerated,
+	).Scan(&hidden)
+	if err != nil {
+		return false, fmt.Errorf("hide review: %w", err)
+	}
+	return hidden, nil
+}
+
+// UnhideReview republishes a review HideReview hid, once none of its reports
+// are open and none were resolved, i.e. every one was dismissed, recording a
+// moderated event in the same statement. It reports whether it did.
+func (r *ReportRepository) UnhideReview(ctx context.Context, reviewID string) (bool, error) {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	var unhidden bool
+	err := r.db.QueryRow(ctx,
+		`WITH unhidden AS (
+		     UPDATE reviews SET moderation = 'approved', hidden_at = NULL
+		     WHERE id = $1 AND moderation = 'pending' AND hidden_at IS NOT NULL
+		       AND NOT EXISTS (SELECT 1 FROM reports
+		                       WHERE entity_type = 'review' AND entity_id = $1 AND status <> 'dismissed')
+		     RETURNING id
+		 ),
+		 event AS (
+		     INSERT INTO review_events (review_id, event, details)
+		     SELECT id, $2, jsonb_build_object('action', 'unhide') FROM unhidden
+		 )
+		 SELECT EXISTS (SELECT 1 FROM unhidden)`,
+		reviewID, models.ReviewEventModerated,
+	).Scan(&unhidden)
+	if err != nil {
+		return false, fmt.Errorf("unhide review: %w", err)
+	}
+	return unhidden, nil
+}
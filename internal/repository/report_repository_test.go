@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"yuplan/internal/dbtypes"
+	"yuplan/internal/models"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportRepository_Create(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewReportRepository(mock)
+	now := time.Now()
+	report := &models.Report{
+		Type:       models.ReportBrokenRMPLink,
+		EntityType: models.ReportEntityInstructor,
+		EntityID:   "inst-1",
+		Details:    dbtypes.NewNullString("Links to a different professor"),
+	}
+
+	mock.ExpectQuery("INSERT INTO reports (.+) WHERE CASE \\$2(.+)"+
+		"INSERT INTO review_events \\(review_id, event, details\\)\\s+SELECT entity_id, \\$8(.+)FROM inserted WHERE entity_type = 'review'").
+		WithArgs(report.Type, report.EntityType, report.EntityID, report.Details, report.Email, pgxmock.AnyArg(), report.Reason, models.ReviewEventReported).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "status", "created_at"}).AddRow("rep-1", "open", now))
+
+	created, err := repo.Create(context.Background(), report)
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, "rep-1", report.ID)
+	assert.Equal(t, models.ReportOpen, report.Status)
+	assert.Equal(t, now, report.CreatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReportRepository_Create_MissingEntity(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewReportRepository(mock)
+
+	mock.ExpectQuery("INSERT INTO reports").WillReturnError(pgx.ErrNoRows)
+
+	created, err := repo.Create(context.Background(), &models.Report{Type: models.ReportOffensiveCourseResource, EntityType: models.ReportEntityCourse, EntityID: "missing"})
+	assert.NoError(t, err)
+	assert.False(t, created)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReportRepository_List(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewReportRepository(mock)
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT (.+) FROM reports r (.+) WHERE \\$1 = '' OR r.status = \\$1").
+		WithArgs("open").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "type", "entity_type", "entity_id", "label", "reason", "details", "email", "status", "created_at", "resolved_at"}).
+			AddRow("rep-1", "broken_rmp_link", "instructor", "inst-1", "Jane Doe", nil, nil, "student@my.yorku.ca", "open", now, nil))
+
+	reports, err := repo.List(context.Background(), "open")
+	assert.NoError(t, err)
+	assert.Len(t, reports, 1)
+	assert.Equal(t, "Jane Doe", reports[0].EntityLabel)
+	assert.False(t, reports[0].Reason.Valid)
+	assert.False(t, reports[0].Details.Valid)
+	assert.Equal(t, "student@my.yorku.ca", reports[0].Email.String)
+	assert.False(t, reports[0].ResolvedAt.Valid)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReportRepository_List_Error(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewReportRepository(mock)
+
+	mock.ExpectQuery("FROM reports").WillReturnError(errors.New("db down"))
+
+	_, err = repo.List(context.Background(), "")
+	assert.ErrorContains(t, err, "query reports")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReportRepository_Close(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewReportRepository(mock)
+	now := time.Now()
+
+	mock.ExpectQuery("UPDATE reports SET status = \\$2, (.+) RETURNING").
+		WithArgs("rep-1", "resolved").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "type", "entity_type", "entity_id", "reason", "details", "email", "status", "created_at", "resolved_at"}).
+			AddRow("rep-1", "inappropriate_review", "review", "rev-1", "spam", nil, nil, "resolved", now, dbtypes.NewNullTime(now)))
+	mock.ExpectQuery("UPDATE reports SET status = \\$2").
+		WithArgs("rep-2", "dismissed").
+		WillReturnError(pgx.ErrNoRows)
+
+	closed, err := repo.Close(context.Background(), "rep-1", models.ReportResolved)
+	assert.NoError(t, err)
+	assert.Equal(t, models.ReportEntityReview, closed.EntityType)
+	assert.Equal(t, "rev-1", closed.EntityID)
+	assert.Equal(t, models.ReportReasonSpam, closed.Reason.String)
+	assert.True(t, closed.ResolvedAt.Valid)
+
+	closed, err = repo.Close(context.Background(), "rep-2", models.ReportDismissed)
+	assert.NoError(t, err)
+	assert.Nil(t, closed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReportRepository_HideReview(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewReportRepository(mock)
+
+	mock.ExpectQuery("UPDATE reviews SET moderation = 'pending', hidden_at = NOW\\(\\) (.+) >= \\$2(.+)"+
+		"INSERT INTO review_events \\(review_id, event, details\\)\\s+SELECT id, \\$3, jsonb_build_object\\('action', 'hide'").
+		WithArgs("rev-1", 3, models.ReviewEventModerated).
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery("UPDATE reviews SET moderation = 'pending'").
+		WithArgs("rev-2", 3, models.ReviewEventModerated).
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(false))
+
+	hidden, err := repo.HideReview(context.Background(), "rev-1", 3)
+	assert.NoError(t, err)
+	assert.True(t, hidden)
+
+	hidden, err = repo.HideReview(context.Background(), "rev-2", 3)
+	assert.NoError(t, err)
+	assert.False(t, hidden)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReportRepository_UnhideReview(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewReportRepository(mock)
+
+	mock.ExpectQuery("UPDATE reviews SET moderation = 'approved', hidden_at = NULL (.+) hidden_at IS NOT NULL (.+) status <> 'dismissed'(.+)"+
+		"INSERT INTO review_events \\(review_id, event, details\\)\\s+SELECT id, \\$2, jsonb_build_object\\('action', 'unhide'\\)").
+		WithArgs("rev-1", models.ReviewEventModerated).
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery("UPDATE reviews").
+		WithArgs("rev-2", models.ReviewEventModerated).
+		WillReturnError(errors.New("db down"))
+
+	unhidden, err := repo.UnhideReview(context.Background(), "rev-1")
+	assert.NoError(t, err)
+	assert.True(t, unhidden)
+
+	_, err = repo.UnhideReview(context.Background(), "rev-2")
+	assert.ErrorContains(t, err, "unhide review")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReportRepository_Trends(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewReportRepository(mock)
+	since := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	week1, week2 := time.Date(2026, 8, 31, 0, 0, 0, 0, time.UTC), time.Date(2026, 9, 7, 0, 0, 0, 0, time.UTC)
+	spam, offTopic := "spam", "off_topic"
+
+	mock.ExpectQuery("FROM generate_series(.+) LEFT JOIN reports r ON r.entity_type = 'review'").
+		WithArgs(since, "week").
+		WillReturnRows(pgxmock.NewRows([]string{"period", "reason", "count"}).
+			AddRow(week1, &offTopic, 1).
+			AddRow(week1, &spam, 4).
+			AddRow(week2, (*string)(nil), 0))
+
+	trends, err := repo.Trends(context.Background(), since, models.TrendWeek)
+	assert.NoError(t, err)
+	assert.Len(t, trends, 2)
+	assert.Equal(t, week1, trends[0].PeriodStart)
+	assert.Equal(t, 5, trends[0].Total)
+	assert.Equal(t, 4, trends[0].Reasons["spam"])
+	assert.Equal(t, 0, trends[0].Reasons["harassment"], "every reason is listed")
+	assert.Equal(t, 0, trends[1].Total)
+	assert.Len(t, trends[1].Reasons, len(models.ReportReasons))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"yuplan/internal/models"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+type RequisiteRepositoryInterface interface {
+	Graph(ctx context.Context, courseCode string, depth int) ([]models.Requisite, error)
+	Set(ctx context.Context, courseCode string, requisites []models.Requisite) error
+}
+
+type requisiteDB interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+type RequisiteRepository struct {
+	db requisiteDB
+}
+
+func NewRequisiteRepository(db requisiteDB) *RequisiteRepository {
+	return &RequisiteRepository{db: db}
+}
+
+// Graph returns every requisite of courseCode, plus the prerequisites of
+// its prerequisites down to depth levels (1 is direct only), each with the
+// required course's catalog name where known. courseCode must be normalized.
+func (r *RequisiteRepository) Graph(ctx context.Context, courseCode string, depth int) ([]models.Requisite, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	rows, err := r.db.Query(ctx,
+		`WITH RECURSIVE reach(code, level) AS (
+		     SELECT $1::text, 0
+		     UNION
+		     SELECT cr.requisite_code, reach.level + 1
+		     FROM course_requisites cr
+		     JOIN reach ON cr.course_code = reach.code
+		     WHERE cr.kind = 'prerequisite' AND reach.level + 1 < $2
+		 )
+		 SELECT cr.course_code, cr.kind, cr.group_no, cr.requisite_code, COALESCE(c.name, '')
+		 FROM course_requisites cr
+		 LEFT JOIN LATERAL (
+		     SELECT name FROM courses WHERE code = cr.requisite_code ORDER BY term LIMIT 1
+		 ) c ON true
+		 WHERE cr.course_code IN (SELECT code FROM reach)
+		   AND (cr.kind = 'prerequisite' OR cr.course_code = $1)
+		 ORDER BY cr.course_code, cr.kind, cr.group_no, cr.requisite_code`,
+		courseCode, depth,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query requisites: %w", err)
+	}
+	defer rows.Close()
+
+	var requisites []models.Requisite
+	for rows.Next() {
+		var req models.Requisite
+		if err := rows.Scan(&req.CourseCode, &req.Kind, &req.Group, &req.RequisiteCode, &req.RequisiteName); err != nil {
+			return nil, fmt.Errorf("scan requisite: %w", err)
+		}
+		requisites = append(requisites, req)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate requisites: %w", err)
+	}
+	return requisites, nil
+}
+
+// Set replaces courseCode's requisites with the given ones in one statement:
+// rows no longer wanted are deleted and new ones inserted.
+func (r *RequisiteRepository) Set(ctx context.Context, courseCode string, requisites []models.Requisite) error {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	kinds := make([]string, len(requisites))
+	groups := make([]int32, len(requisites))
+	codes := make([]string, len(requisites))
+	for i, req := range requisites {
+		kinds[i], groups[i], codes[i] = req.Kind, int32(req.Group), req.RequisiteCode
+	}
+
+	_, err := r.db.Exec(ctx,
+		`WITH wanted AS (
+		     SELECT * FROM unnest($2::text[], $3::int[], $4::text[]) AS w(kind, group_no, requisite_code)
+		 ),
+		 removed AS (
+		     DELETE FROM course_requisites cr
+		     WHERE cr.course_code = $1
+		       AND NOT EXISTS (
+		           SELECT 1 FROM wanted w
+		           WHERE (w.kind, w.group_no, w.requisite_code) = (cr.kind, cr.group_no, cr.requisite_code)
+		       )
+		 )
+		 INSERT INTO course_requisites (course_code, kind, group_no, requisite_code)
+		 SELECT $1, kind, group_no, requisite_code FROM wanted
+		 ON CONFLICT DO NOTHING`,
+		courseCode, kinds, groups, codes,
+	)
+	if err != nil {
+		return fmt.Errorf("set requisites: %w", err)
+	}
+	return nil
+}
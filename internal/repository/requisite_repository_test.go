@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"yuplan/internal/models"
+
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequisiteRepository_Graph(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewRequisiteRepository(mock)
+
+	mock.ExpectQuery("WITH RECURSIVE reach(.+) FROM course_requisites cr").
+		WithArgs("EECS3101", 3).
+		WillReturnRows(pgxmock.NewRows([]string{"course_code", "kind", "group_no", "requisite_code", "name"}).
+			AddRow("EECS2030", "prerequisite", 1, "EECS1022", "Programming for Mobile Computing").
+			AddRow("EECS3101", "prerequisite", 1, "EECS2030", "Advanced Object Oriented Programming").
+			AddRow("EECS3101", "exclusion", 0, "EECS3100", ""))
+
+	requisites, err := repo.Graph(context.Background(), "EECS3101", 3)
+	assert.NoError(t, err)
+	assert.Len(t, requisites, 3)
+	assert.Equal(t, models.Requisite{CourseCode: "EECS2030", Kind: models.RequisitePrerequisite, Group: 1, RequisiteCode: "EECS1022", RequisiteName: "Programming for Mobile Computing"}, requisites[0])
+	assert.Equal(t, "", requisites[2].RequisiteName)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRequisiteRepository_Graph_Error(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewRequisiteRepository(mock)
+
+	mock.ExpectQuery("FROM course_requisites").WillReturnError(errors.New("db down"))
+
+	_, err = repo.Graph(context.Background(), "EECS3101", 1)
+	assert.ErrorContains(t, err, "query requisites")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRequisiteRepository_Set(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewRequisiteRepository(mock)
+
+	mock.ExpectExec("DELETE FROM course_requisites (.+) INSERT INTO course_requisites").
+		WithArgs("EECS3101", []string{"prerequisite", "prerequisite"}, []int32{1, 2}, []string{"EECS2030", "MATH1090"}).
+		WillReturnResult(pgxmock.NewResult("INSERT", 2))
+
+	err = repo.Set(context.Background(), "EECS3101", []models.Requisite{
+		{CourseCode: "EECS3101", Kind: models.RequisitePrerequisite, Group: 1, RequisiteCode: "EECS2030"},
+		{CourseCode: "EECS3101", Kind: models.RequisitePrerequisite, Group: 2, RequisiteCode: "MATH1090"},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
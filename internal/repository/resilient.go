@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+// ErrUnavailable is returned without touching the database while the circuit
+// breaker is open.
+var ErrUnavailable = errors.New("database unavailable: circuit breaker open")
+
+// IsUnavailable reports whether err came from an open circuit breaker.
+func IsUnavailable(err error) bool {
+	return errors.Is(err, ErrUnavailable)
+}
+
+// Resilience is the retry and circuit breaker policy for ResilientDB.
+type Resilience struct {
+	Retries   int           // extra attempts after a transient error
+	BaseDelay time.Duration // backoff before the first retry; doubles each time, with full jitter
+	MaxDelay  time.Duration
+
+	// After BreakerThreshold consecutive failures that point at an unhealthy
+	// database, calls fail fast for BreakerCooldown before one is let through
+	// to probe it. Zero disables the breaker.
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
+func DefaultResilience() Resilience {
+	return Resilience{
+		Retries:          2,
+		BaseDelay:        25 * time.Millisecond,
+		MaxDelay:         250 * time.Millisecond,
+		BreakerThreshold: 5,
+		BreakerCooldown:  10 * time.Second,
+	}
+}
+
+type resilientInner interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+// ResilientDB wraps the pool handed to repositories. Transient errors are
+// retried with backoff inside the caller's deadline, when running the call
+// again can't apply a write twice, and a shared circuit
+// breaker turns a struggling database into fast ErrUnavailable failures
+// instead of a queue of calls waiting to time out. Only errors returned by
+// Query itself are seen; failures while iterating rows are not retried.
+type ResilientDB struct {
+	db      resilientInner
+	policy  Resilience
+	breaker *breaker
+}
+
+func NewResilientDB(db resilientInner, policy Resilience) *ResilientDB {
+	return &ResilientDB{
+		db:      db,
+		policy:  policy,
+		breaker: &breaker{threshold: policy.BreakerThreshold, cooldown: policy.BreakerCooldown, now: time.Now},
+	}
+}
+
+func (d *ResilientDB) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	var rows pgx.Rows
+	err := d.do(ctx, func() error {
+		var err error
+		rows, err = d.db.Query(ctx, sql, args...)
+		return err
+	})
+	return rows, err
+}
+
+// QueryRow defers the query to Scan, where it can be retried.
+func (d *ResilientDB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return resilientRow(func(dest ...any) error {
+		return d.do(ctx, func() error {
+			return d.db.QueryRow(ctx, sql, args...).Scan(dest...)
+		})
+	})
+}
+
+func (d *ResilientDB) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	var tag pgconn.CommandTag
+	err := d.do(ctx, func() error {
+		var err error
+		tag, err = d.db.Exec(ctx, sql, args...)
+		return err
+	})
+	return tag, err
+}
+
+type resilientRow func(dest ...any) error
+
+func (r resilientRow) Scan(dest ...any) error { return r(dest...) }
+
+func (d *ResilientDB) do(ctx context.Context, call func() error) error {
+	for attempt := 0; ; attempt++ {
+		if !d.breaker.allow() {
+			return ErrUnavailable
+		}
+		err := call()
+		d.breaker.record(ctx, err)
+		if err == nil || attempt >= d.policy.Retries || !retryable(ctx, err) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(d.backoff(attempt)):
+		}
+	}
+}
+
+func (d *ResilientDB) backoff(attempt int) time.Duration {
+	delay := d.policy.BaseDelay << attempt
+	if delay > d.policy.MaxDelay || delay <= 0 {
+		delay = d.policy.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(delay)))
+}
+
+// retryable reports whether a call can safely run again: the statement was
+// never sent, the server rolled it back (serialization failure, deadlock), or
+// it was refused a connection. A connection lost or dropped by an admin
+// mid-statement may have committed first, so only reads retry those.
+func retryable(ctx context.Context, err error) bool {
+	if pgconn.SafeToRetry(err) {
+		return true
+	}
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	switch pgErr.Code {
+	case "40001", "40P01", "53300":
+		return true
+	case "57P01":
+		return isRead(ctx)
+	}
+	return strings.HasPrefix(pgErr.Code, "08") && isRead(ctx)
+}
+
+// unhealthy reports whether err says more about the database than about the
+// query: timeouts, lost connections and the server refusing work. Query errors
+// such as constraint violations or no rows count as the database responding.
+func unhealthy(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return strings.HasPrefix(pgErr.Code, "08") || strings.HasPrefix(pgErr.Code, "53") || strings.HasPrefix(pgErr.Code, "57P")
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// breaker opens after threshold consecutive unhealthy results and, once the
+// cooldown has passed, lets a single call through to decide whether to close.
+type breaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+func (b *breaker) allow() bool {
+	if b.threshold <= 0 {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.threshold {
+		return true
+	}
+	if b.now().Before(b.openUntil) || b.probing {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+// record counts err against the database unless it comes from ctx being done,
+// which says nothing about the database: a client that disconnects or a
+// caller's timeout running out. pgconn reports those as Timeout errors once
+// the statement is on the wire.
+func (b *breaker) record(ctx context.Context, err error) {
+	if b.threshold <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if ctx.Err() != nil && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || pgconn.Timeout(err)) {
+		return
+	}
+	if err == nil || !unhealthy(err) {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openUntil = b.now().Add(b.cooldown)
+	}
+}
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func testResilience() Resilience {
+	return Resilience{Retries: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, BreakerThreshold: 3, BreakerCooldown: time.Minute}
+}
+
+func TestResilientDB_RetriesTransientErrors(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	db := NewResilientDB(mock, testResilience())
+
+	mock.ExpectExec("UPDATE reviews").WillReturnError(&pgconn.PgError{Code: "40001"})
+	mock.ExpectExec("UPDATE reviews").WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	tag, err := db.Exec(context.Background(), "UPDATE reviews SET liked = true")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), tag.RowsAffected())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestResilientDB_QueryRowRetriesOnScan(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	db := NewResilientDB(mock, testResilience())
+
+	mock.ExpectQuery("SELECT COUNT").WillReturnError(&pgconn.PgError{Code: "40P01"})
+	mock.ExpectQuery("SELECT COUNT").WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(7))
+
+	var count int
+	err = db.QueryRow(context.Background(), "SELECT COUNT(*) FROM reviews").Scan(&count)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 7, count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestResilientDB_GivesUpAfterRetries(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	db := NewResilientDB(mock, Resilience{Retries: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+
+	for i := 0; i < 3; i++ {
+		mock.ExpectQuery("SELECT").WillReturnError(&pgconn.PgError{Code: "40001"})
+	}
+
+	_, err = db.Query(context.Background(), "SELECT 1")
+
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestResilientDB_DoesNotRetryQueryErrors(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	db := NewResilientDB(mock, testResilience())
+
+	mock.ExpectExec("INSERT INTO reviews").WillReturnError(&pgconn.PgError{Code: "23505"})
+	mock.ExpectQuery("SELECT").WillReturnError(pgx.ErrNoRows)
+
+	_, err = db.Exec(context.Background(), "INSERT INTO reviews DEFAULT VALUES")
+	assert.Error(t, err)
+	err = db.QueryRow(context.Background(), "SELECT 1").Scan(new(int))
+	assert.ErrorIs(t, err, pgx.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestResilientDB_BreakerOpensAndFailsFast(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	db := NewResilientDB(mock, Resilience{BreakerThreshold: 2, BreakerCooldown: time.Minute})
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	db.breaker.now = func() time.Time { return now }
+
+	timeout := fmt.Errorf("query: %w", context.DeadlineExceeded)
+	mock.ExpectExec("SELECT").WillReturnError(timeout)
+	mock.ExpectExec("SELECT").WillReturnError(timeout)
+
+	for i := 0; i < 2; i++ {
+		_, err = db.Exec(context.Background(), "SELECT 1")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	}
+
+	// Open: calls fail without reaching the database
+	_, err = db.Exec(context.Background(), "SELECT 1")
+	assert.True(t, IsUnavailable(err))
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// After the cooldown one probe goes through; its success closes the breaker
+	now = now.Add(time.Minute)
+	mock.ExpectExec("SELECT").WillReturnResult(pgxmock.NewResult("SELECT", 1))
+	mock.ExpectExec("SELECT").WillReturnResult(pgxmock.NewResult("SELECT", 1))
+
+	_, err = db.Exec(context.Background(), "SELECT 1")
+	assert.NoError(t, err)
+	_, err = db.Exec(context.Background(), "SELECT 1")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBreaker_FailedProbeReopens(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	b := &breaker{threshold: 1, cooldown: time.Minute, now: func() time.Time { return now }}
+
+	b.record(context.Background(), &pgconn.PgError{Code: "57P03"})
+	assert.False(t, b.allow())
+
+	now = now.Add(time.Minute)
+	assert.True(t, b.allow())
+	assert.False(t, b.allow(), "only one probe at a time")
+
+	b.record(context.Background(), fmt.Errorf("connect: %w", &pgconn.PgError{Code: "08006"}))
+	assert.False(t, b.allow())
+
+	// Errors that aren't about the database's health leave it closed
+	healthy := &breaker{threshold: 1, cooldown: time.Minute, now: time.Now}
+	healthy.record(context.Background(), errors.New("scan review: invalid input syntax"))
+	healthy.record(context.Background(), &pgconn.PgError{Code: "23505"})
+	assert.True(t, healthy.allow())
+}
+
+func TestResilientDB_RetriesLostConnectionsOnlyForReads(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	db := NewResilientDB(mock, Resilience{Retries: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+	lost := &pgconn.PgError{Code: "08006"}
+
+	// The write may have committed before the connection dropped
+	mock.ExpectExec("UPDATE reviews").WillReturnError(lost)
+	ctx, cancel := withDeadline(context.Background(), opWrite)
+	_, err = db.Exec(ctx, "UPDATE reviews SET liked = true")
+	cancel()
+	assert.ErrorIs(t, err, lost)
+
+	mock.ExpectQuery("INSERT INTO reports").WillReturnError(&pgconn.PgError{Code: "57P01"})
+	err = db.QueryRow(context.Background(), "INSERT INTO reports DEFAULT VALUES RETURNING id").Scan(new(int))
+	assert.Error(t, err)
+
+	mock.ExpectQuery("SELECT COUNT").WillReturnError(lost)
+	mock.ExpectQuery("SELECT COUNT").WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(7))
+	ctx, cancel = withDeadline(context.Background(), opRead)
+	defer cancel()
+	var count int
+	err = db.QueryRow(ctx, "SELECT COUNT(*) FROM reviews").Scan(&count)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBreaker_IgnoresCallersOwnCancellation(t *testing.T) {
+	b := &breaker{threshold: 1, cooldown: time.Minute, now: time.Now}
+
+	done, cancel := context.WithCancel(context.Background())
+	cancel()
+	b.record(done, fmt.Errorf("query: %w", context.Canceled))
+	b.record(done, fmt.Errorf("query: %w", context.DeadlineExceeded))
+	assert.True(t, b.allow(), "a client going away isn't the database failing")
+
+	// Nothing was sent, but the pool couldn't reach the server either
+	b.record(context.Background(), fmt.Errorf("connect: %w", syscall.ECONNREFUSED))
+	assert.False(t, b.allow())
+}
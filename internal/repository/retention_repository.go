@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+// retentionScopes is each retention policy's table and the condition that
+// makes one of its rows expired, given the cutoff as $1. An unverified review
+// is kept while its link is still good, so one reissued late isn't purged
+// from under its author; deleting it takes its verification with it.
+// Verifications of reviews still unverified stay with them, since reissuing
+// a link needs one.
+var retentionScopes = map[string]string{
+	models.RetentionSearchStats:        `search_stats WHERE day < $1::date`,
+	models.RetentionResolvedQuarantine: `seed_quarantine WHERE status <> 'pending' AND resolved_at < $1`,
+	models.RetentionCourseViews:        `course_views WHERE seen_at < $1`,
+	models.RetentionAnonymousSessions:  `anonymous_sessions WHERE started_at < $1`,
+	models.RetentionUnverifiedReviews: `reviews r WHERE r.moderation = 'unverified' AND r.created_at < $1
+		AND NOT EXISTS (SELECT 1 FROM review_verifications v WHERE v.review_id = r.id AND v.expires_at >= $1)`,
+	models.RetentionVerificationTokens: `review_verifications v WHERE v.expires_at < $1
+		AND NOT EXISTS (SELECT 1 FROM reviews r WHERE r.id = v.review_id AND r.moderation = 'unverified')`,
+}
+
+type RetentionRepositoryInterface interface {
+	CountExpired(ctx context.Context, policy string, before time.Time) (int64, error)
+	PurgeExpired(ctx context.Context, policy string, before time.Time) (int64, error)
+}
+
+type retentionDB interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+type RetentionRepository struct {
+	db retentionDB
+}
+
+func NewRetentionRepository(db retentionDB) *RetentionRepository {
+	return &RetentionRepository{db: db}
+}
+
+// CountExpired returns how many of a policy's rows are older than before.
+func (r *RetentionRepository) CountExpired(ctx context.Context, policy string, before time.Time) (int64, error) {
+	ctx, cancel := withDeadline(ctx, opAggregate)
+	defer cancel()
+
+	scope, ok := retentionScopes[policy]
+	if !ok {
+		return 0, fmt.Errorf("unknown retention policy %q", policy)
+	}
+
+	var n int64
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM `+scope, before).Scan(&n); err != nil {
+		return 0, fmt.Errorf("count expired %s: %w", policy, err)
+	}
+	return n, nil
+}
+
+// PurgeExpired deletes a policy's rows older than before and returns how many went.
+func (r *RetentionRepository) PurgeExpired(ctx context.Context, policy string, before time.Time) (int64, error) {
+	ctx, cancel := withDeadline(ctx, opAggregate)
+	defer cancel()
+
+	scope, ok := retentionScopes[policy]
+	if !ok {
+		return 0, fmt.Errorf("unknown retention policy %q", policy)
+	}
+
+	tag, err := r.db.Exec(ctx, `DELETE FROM `+scope, before)
+	if err != nil {
+		return 0, fmt.Errorf("purge expired %s: %w", policy, err)
+	}
+	return tag.RowsAffected(), nil
+}
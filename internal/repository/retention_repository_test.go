@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetentionRepository_CountExpired(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewRetentionRepository(mock)
+	before := time.Date(2025, 10, 16, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM search_stats WHERE day < \$1::date`).
+		WithArgs(before).
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(int64(42)))
+
+	n, err := repo.CountExpired(context.Background(), models.RetentionSearchStats, before)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), n)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRetentionRepository_PurgeExpired(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewRetentionRepository(mock)
+	before := time.Date(2026, 7, 18, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec(`DELETE FROM seed_quarantine WHERE status <> 'pending' AND resolved_at < \$1`).
+		WithArgs(before).
+		WillReturnResult(pgxmock.NewResult("DELETE", 7))
+
+	n, err := repo.PurgeExpired(context.Background(), models.RetentionResolvedQuarantine, before)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), n)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRetentionRepository_PurgeExpiredError(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewRetentionRepository(mock)
+
+	mock.ExpectExec(`DELETE FROM search_stats`).
+		WillReturnError(errors.New("canceling statement due to statement timeout"))
+
+	_, err = repo.PurgeExpired(context.Background(), models.RetentionSearchStats, time.Now())
+
+	assert.ErrorContains(t, err, "purge expired search_stats")
+}
+
+func TestRetentionRepository_UnverifiedReviews(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewRetentionRepository(mock)
+	before := time.Date(2026, 9, 16, 0, 0, 0, 0, time.UTC)
+
+	// Only unverified reviews without a link still good at the cutoff
+	mock.ExpectExec(`DELETE FROM reviews r WHERE r.moderation = 'unverified' AND r.created_at < \$1\s+` +
+		`AND NOT EXISTS \(SELECT 1 FROM review_verifications v WHERE v.review_id = r.id AND v.expires_at >= \$1\)`).
+		WithArgs(before).
+		WillReturnResult(pgxmock.NewResult("DELETE", 4))
+	// Leftover links, never those of reviews still waiting on one
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM review_verifications v WHERE v.expires_at < \$1\s+` +
+		`AND NOT EXISTS \(SELECT 1 FROM reviews r WHERE r.id = v.review_id AND r.moderation = 'unverified'\)`).
+		WithArgs(before).
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(int64(2)))
+
+	n, err := repo.PurgeExpired(context.Background(), models.RetentionUnverifiedReviews, before)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), n)
+
+	n, err = repo.CountExpired(context.Background(), models.RetentionVerificationTokens, before)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRetentionRepository_UnknownPolicy(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	_, err = NewRetentionRepository(mock).PurgeExpired(context.Background(), "drafts", time.Now())
+
+	assert.ErrorContains(t, err, `unknown retention policy "drafts"`)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
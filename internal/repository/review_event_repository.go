@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+type ReviewEventRepositoryInterface interface {
+	Record(ctx context.Context, reviewID, event string, details map[string]any) error
+	Funnel(ctx context.Context, since time.Time) (*models.ReviewFunnel, error)
+}
+
+type reviewEventDB interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+type ReviewEventRepository struct {
+	db reviewEventDB
+}
+
+func NewReviewEventRepository(db reviewEventDB) *ReviewEventRepository {
+	return &ReviewEventRepository{db: db}
+}
+
+// Record appends one lifecycle event (models.ReviewEvent*) for a review.
+func (r *ReviewEventRepository) Record(ctx context.Context, reviewID, event string, details map[string]any) error {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	var raw []byte
+	if details != nil {
+		var err error
+		if raw, err = json.Marshal(details); err != nil {
+			return fmt.Errorf("encode review event details: %w", err)
+		}
+	}
+
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO review_events (review_id, event, details) VALUES ($1, $2, $3)`,
+		reviewID, event, raw,
+	)
+	if err != nil {
+		return fmt.Errorf("record review event: %w", err)
+	}
+	return nil
+}
+
+// Funnel follows the reviews created since the given time: how many were
+// verified and how many are public now. Events counts everything recorded in
+// the same window, whichever review it belongs to.
+func (r *ReviewEventRepository) Funnel(ctx context.Context, since time.Time) (*models.ReviewFunnel, error) {
+	ctx, cancel := withDeadline(ctx, opAggregate)
+	defer cancel()
+
+	funnel := &models.ReviewFunnel{Events: map[string]int{}}
+	err := r.db.QueryRow(ctx,
+		`SELECT COUNT(*),
+		        COUNT(*) FILTER (WHERE EXISTS (
+		            SELECT 1 FROM review_events v WHERE v.review_id = c.review_id AND v.event = $2)),
+		        COUNT(*) FILTER (WHERE EXISTS (
+		            SELECT 1 FROM reviews WHERE id = c.review_id AND `+publishedFilter+`))
+		 FROM review_events c
+		 WHERE c.event = $1 AND c.created_at >= $3`,
+		models.ReviewEventCreated, models.ReviewEventVerified, since,
+	).Scan(&funnel.Submitted, &funnel.Verified, &funnel.Published)
+	if err != nil {
+		return nil, fmt.Errorf("query review funnel: %w", err)
+	}
+	if funnel.Submitted > 0 {
+		funnel.VerificationRate = float64(funnel.Verified) / float64(funnel.Submitted)
+		funnel.PublicationRate = float64(funnel.Published) / float64(funnel.Submitted)
+	}
+
+	rows, err := r.db.Query(ctx,
+		`SELECT event, COUNT(*) FROM review_events WHERE created_at >= $1 GROUP BY event`,
+		since,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query review event counts: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var event string
+		var count int
+		if err := rows.Scan(&event, &count); err != nil {
+			return nil, fmt.Errorf("scan review event count: %w", err)
+		}
+		funnel.Events[event] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate review event counts: %w", err)
+	}
+	return funnel, nil
+}
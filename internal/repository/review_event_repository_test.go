@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReviewEventRepository_Record(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewReviewEventRepository(mock)
+
+	mock.ExpectExec("INSERT INTO review_events").
+		WithArgs("r1", models.ReviewEventModerated, []byte(`{"action":"publish"}`)).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectExec("INSERT INTO review_events").
+		WithArgs("r1", models.ReviewEventCreated, []byte(nil)).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	assert.NoError(t, repo.Record(context.Background(), "r1", models.ReviewEventModerated, map[string]any{"action": "publish"}))
+	assert.NoError(t, repo.Record(context.Background(), "r1", models.ReviewEventCreated, nil))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewEventRepository_RecordError(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewReviewEventRepository(mock)
+	mock.ExpectExec("INSERT INTO review_events").WillReturnError(errors.New("db down"))
+
+	err = repo.Record(context.Background(), "r1", models.ReviewEventCreated, nil)
+	assert.ErrorContains(t, err, "record review event")
+}
+
+func TestReviewEventRepository_Funnel(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewReviewEventRepository(mock)
+	since := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`FROM review_events c\s+WHERE c.event = \$1`).
+		WithArgs(models.ReviewEventCreated, models.ReviewEventVerified, since).
+		WillReturnRows(pgxmock.NewRows([]string{"count", "verified", "published"}).AddRow(40, 30, 10))
+	mock.ExpectQuery("GROUP BY event").
+		WithArgs(since).
+		WillReturnRows(pgxmock.NewRows([]string{"event", "count"}).
+			AddRow(models.ReviewEventCreated, 40).
+			AddRow(models.ReviewEventModerated, 3))
+
+	funnel, err := repo.Funnel(context.Background(), since)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &models.ReviewFunnel{
+		Submitted:        40,
+		Verified:         30,
+		Published:        10,
+		VerificationRate: 0.75,
+		PublicationRate:  0.25,
+		Events:           map[string]int{models.ReviewEventCreated: 40, models.ReviewEventModerated: 3},
+	}, funnel)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewEventRepository_FunnelEmpty(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewReviewEventRepository(mock)
+
+	mock.ExpectQuery("FROM review_events c").
+		WillReturnRows(pgxmock.NewRows([]string{"count", "verified", "published"}).AddRow(0, 0, 0))
+	mock.ExpectQuery("GROUP BY event").
+		WillReturnRows(pgxmock.NewRows([]string{"event", "count"}))
+
+	funnel, err := repo.Funnel(context.Background(), time.Now())
+
+	assert.NoError(t, err)
+	assert.Zero(t, funnel.PublicationRate)
+	assert.Empty(t, funnel.Events)
+}
+
+func TestReviewEventRepository_FunnelError(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewReviewEventRepository(mock)
+	mock.ExpectQuery("FROM review_events c").WillReturnError(errors.New("db down"))
+
+	_, err = repo.Funnel(context.Background(), time.Now())
+	assert.ErrorContains(t, err, "query review funnel")
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"yuplan/internal/models"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+type ReviewKeywordRepositoryInterface interface {
+	ListReviewTexts(ctx context.Context) ([]models.ReviewText, error)
+	ReplaceKeywords(ctx context.Context, keywords []models.CourseKeywords) error
+	GetKeywords(ctx context.Context, courseCode string, limit int) (*models.CourseKeywords, error)
+}
+
+type reviewKeywordDB interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+type ReviewKeywordRepository struct {
+	db reviewKeywordDB
+}
+
+func NewReviewKeywordRepository(db reviewKeywordDB) *ReviewKeywordRepository {
+	return &ReviewKeywordRepository{db: db}
+}
+
+// ListReviewTexts returns the text of every published review that has any, ordered by course code.
+func (r *ReviewKeywordRepository) ListReviewTexts(ctx context.Context) ([]models.ReviewText, error) {
+	ctx, cancel := withDeadline(ctx, opAggregate)
+	defer cancel()
+
+	rows, err := r.db.Query(ctx,
+		`SELECT course_code, review_text
+		 FROM reviews
+		 WHERE review_text IS NOT NULL AND review_text <> '' AND `+publishedFilter+`
+		 ORDER BY course_code`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query review texts: %w", err)
+	}
+	defer rows.Close()
+
+	texts := make([]models.ReviewText, 0)
+	for rows.Next() {
+		var t models.ReviewText
+		if err := rows.Scan(&t.CourseCode, &t.Text); err != nil {
+			return nil, fmt.Errorf("scan review text: %w", err)
+		}
+		texts = append(texts, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate review texts: %w", err)
+	}
+	return texts, nil
+}
+
+// ReplaceKeywords makes review_keywords hold exactly the given keywords, in one statement.
+func (r *ReviewKeywordRepository) ReplaceKeywords(ctx context.Context, keywords []models.CourseKeywords) error {
+	ctx, cancel := withDeadline(ctx, opAggregate)
+	defer cancel()
+
+	codes := make([]string, 0)
+	terms := make([]string, 0)
+	counts := make([]int32, 0)
+	for _, course := range keywords {
+		for _, k := range course.Keywords {
+			codes = append(codes, course.CourseCode)
+			terms = append(terms, k.Term)
+			counts = append(counts, int32(k.Count))
+		}
+	}
+
+	_, err := r.db.Exec(ctx,
+		`WITH incoming AS (
+		     SELECT * FROM unnest($1::text[], $2::text[], $3::int[]) AS k(course_code, term, count)
+		 ),
+		 stale AS (
+		     DELETE FROM review_keywords r
+		     WHERE NOT EXISTS (SELECT 1 FROM incoming i WHERE i.course_code = r.course_code AND i.term = r.term)
+		 )
+		 INSERT INTO review_keywords (course_code, term, count, updated_at)
+		 SELECT course_code, term, count, NOW() FROM incoming
+		 ON CONFLICT (course_code, term) DO UPDATE
+		 SET count = EXCLUDED.count, updated_at = EXCLUDED.updated_at`,
+		codes, terms, counts,
+	)
+	if err != nil {
+		return fmt.Errorf("replace review keywords: %w", err)
+	}
+	return nil
+}
+
+// GetKeywords returns a course's top keywords, most used first. Courses
+// without enough reviews have an empty list.
+func (r *ReviewKeywordRepository) GetKeywords(ctx context.Context, courseCode string, limit int) (*models.CourseKeywords, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	rows, err := r.db.Query(ctx,
+		`SELECT term, count, updated_at
+		 FROM review_keywords
+		 WHERE course_code = $1
+		 ORDER BY count DESC, term
+		 LIMIT $2`,
+		courseCode, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query review keywords: %w", err)
+	}
+	defer rows.Close()
+
+	result := &models.CourseKeywords{CourseCode: courseCode, Keywords: []models.Keyword{}}
+	for rows.Next() {
+		var k models.Keyword
+		if err := rows.Scan(&k.Term, &k.Count, &result.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan review keyword: %w", err)
+		}
+		result.Keywords = append(result.Keywords, k)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate review keywords: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReviewKeywordRepository_ListReviewTexts(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewReviewKeywordRepository(mock)
+
+	mock.ExpectQuery("SELECT course_code, review_text FROM reviews WHERE review_text IS NOT NULL (.+) ORDER BY course_code").
+		WillReturnRows(pgxmock.NewRows([]string{"course_code", "review_text"}).
+			AddRow("EECS2030", "Great labs").
+			AddRow("EECS2030", "Hard midterm"))
+
+	texts, err := repo.ListReviewTexts(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []models.ReviewText{
+		{CourseCode: "EECS2030", Text: "Great labs"},
+		{CourseCode: "EECS2030", Text: "Hard midterm"},
+	}, texts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewKeywordRepository_ReplaceKeywords(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewReviewKeywordRepository(mock)
+
+	mock.ExpectExec("DELETE FROM review_keywords (.+) INSERT INTO review_keywords").
+		WithArgs([]string{"EECS2030", "EECS2030", "MATH1013"}, []string{"labs", "office hours", "proofs"}, []int32{4, 2, 3}).
+		WillReturnResult(pgxmock.NewResult("INSERT", 3))
+
+	err = repo.ReplaceKeywords(context.Background(), []models.CourseKeywords{
+		{CourseCode: "EECS2030", Keywords: []models.Keyword{{Term: "labs", Count: 4}, {Term: "office hours", Count: 2}}},
+		{CourseCode: "MATH1013", Keywords: []models.Keyword{{Term: "proofs", Count: 3}}},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewKeywordRepository_ReplaceKeywords_EmptyClearsTable(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewReviewKeywordRepository(mock)
+
+	mock.ExpectExec("DELETE FROM review_keywords").
+		WithArgs([]string{}, []string{}, []int32{}).
+		WillReturnError(errors.New("db down"))
+
+	assert.Error(t, repo.ReplaceKeywords(context.Background(), nil))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewKeywordRepository_GetKeywords(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewReviewKeywordRepository(mock)
+	updated := time.Date(2026, 10, 1, 3, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("SELECT term, count, updated_at FROM review_keywords WHERE course_code = \\$1").
+		WithArgs("EECS2030", 20).
+		WillReturnRows(pgxmock.NewRows([]string{"term", "count", "updated_at"}).
+			AddRow("labs", 4, updated).
+			AddRow("office hours", 2, updated))
+	mock.ExpectQuery("SELECT term, count, updated_at FROM review_keywords").
+		WithArgs("NEW1000", 20).
+		WillReturnRows(pgxmock.NewRows([]string{"term", "count", "updated_at"}))
+
+	result, err := repo.GetKeywords(context.Background(), "EECS2030", 20)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Keyword{{Term: "labs", Count: 4}, {Term: "office hours", Count: 2}}, result.Keywords)
+	assert.Equal(t, updated, result.UpdatedAt)
+
+	result, err = repo.GetKeywords(context.Background(), "NEW1000", 20)
+	assert.NoError(t, err)
+	assert.NotNil(t, result.Keywords)
+	assert.Empty(t, result.Keywords)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
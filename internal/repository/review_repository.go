@@ -2,19 +2,49 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
+	"yuplan/internal/dbtypes"
+	"yuplan/internal/id"
 	"yuplan/internal/models"
 
 	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
 )
 
+// A tag only shows up in a course's top tags once enough reviewers agree on it,
+// so a single reviewer can't label a course on their own.
+const (
+	minTagVotes  = 3   // reviews that must choose the tag
+	minTagShare  = 0.2 // fraction of the course's reviews that must choose the tag
+	topTagsLimit = 5
+)
+
+// Embargoed reviews are left out of every public read until publish_at passes,
+// so they publish themselves without a job. Reviews held by moderation are
+// left out until an admin publishes them.
+const publishedFilter = `(moderation = 'approved' AND (publish_at IS NULL OR publish_at <= NOW()))`
+
+// ErrDuplicateReview is returned by Create when the author already reviewed
+// the course for that term.
+var ErrDuplicateReview = errors.New("review already exists for this course and term")
+
 type ReviewRepositoryInterface interface {
 	Create(ctx context.Context, review *models.Review) error
-	GetByCourseCode(ctx context.Context, courseCode string, sortBy string, limit, offset int) ([]models.Review, error)
-	GetCourseStats(ctx context.Context, courseCode string) (map[string]interface{}, error)
-	GetAll(ctx context.Context) ([]models.Review, error)
+	GetByCourseCode(ctx context.Context, courseCode string, sortBy, deliveryMode string, limit, offset int) ([]models.Review, error)
+	GetByCourseCodeAfter(ctx context.Context, courseCode string, sortBy, deliveryMode string, after *models.ReviewCursor, limit int) ([]models.Review, error)
+	GetCourseStats(ctx context.Context, courseCode string, since time.Time) (map[string]interface{}, error)
+	GetRatingHistogram(ctx context.Context, courseCode string, since time.Time) (*models.RatingHistogram, error)
+	StreamAll(ctx context.Context, fn func(models.Review) error) error
+	HasReviewed(ctx context.Context, courseCode, email string, academicYear int, term string) (bool, error)
+	GetByAuthor(ctx context.Context, courseCode, email string) (*models.Review, error)
+	ListEmbargoed(ctx context.Context) ([]models.Review, error)
+	SetPublishAt(ctx context.Context, id string, publishAt dbtypes.NullTime) (*models.Review, error)
+	Update(ctx context.Context, review *models.Review) (*models.Review, error)
+	GetByID(ctx context.Context, id string) (*models.Review, error)
+	ListRevisions(ctx context.Context, reviewID string) ([]models.ReviewRevision, error)
+	Delete(ctx context.Context, id, courseCode, email string) (bool, error)
 }
 
 type reviewDB interface {
@@ -32,13 +62,27 @@ func NewReviewRepository(db reviewDB) *ReviewRepository {
 }
 
 func (r *ReviewRepository) Create(ctx context.Context, review *models.Review) error {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	review.ID = id.New()
+	if review.Moderation == "" {
+		review.Moderation = models.ModerationApproved
+	}
 	review.CreatedAt = time.Now()
 	review.UpdatedAt = time.Now()
 
+	// Review and tags go in one statement so a review never exists without its tags
 	query := `
-		INSERT INTO reviews (course_code, email, author_name, liked, difficulty, real_world_relevance, review_text, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-		RETURNING id
+		WITH new_review AS (
+			INSERT INTO reviews (id, course_code, email, author_name, liked, difficulty, real_world_relevance, review_text, created_at, updated_at, publish_at, delivery_mode, academic_year, term, moderation)
+			VALUES ($15, $1, $2, $3, $4, $5, $6, $7, $8, $9, $11, $12, $13, $14, $16)
+			RETURNING id
+		), new_tags AS (
+			INSERT INTO review_tags (review_id, tag)
+			SELECT id, unnest($10::text[]) FROM new_review
+		)
+		SELECT id FROM new_review
 	`
 	err := r.db.QueryRow(ctx, query,
 		review.CourseCode,
@@ -50,19 +94,61 @@ func (r *ReviewRepository) Create(ctx context.Context, review *models.Review) er
 		review.ReviewText,
 		review.CreatedAt,
 		review.UpdatedAt,
+		review.Tags,
+		review.PublishAt,
+		review.DeliveryMode,
+		review.AcademicYear,
+		review.Term,
+		review.ID,
+		review.Moderation,
 	).Scan(&review.ID)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "reviews_course_code_email_term_key" {
+		return ErrDuplicateReview
+	}
 	return err
 }
 
-func (r *ReviewRepository) GetByCourseCode(ctx context.Context, courseCode string, sortBy string, limit, offset int) ([]models.Review, error) {
-	var orderClause string
+// GetByCourseCode lists a course's published reviews with their vote counts. An empty deliveryMode matches every review.
+func (r *ReviewRepository) GetByCourseCode(ctx context.Context, courseCode string, sortBy, deliveryMode string, limit, offset int) ([]models.Review, error) {
+	return r.listByCourse(ctx, courseCode, sortBy, deliveryMode, nil, limit, offset)
+}
+
+// GetByCourseCodeAfter lists like GetByCourseCode, from the review after the
+// cursor instead of an offset, so pages don't shift as reviews are added.
+// The cursor must come from a page in the same sortBy order.
+func (r *ReviewRepository) GetByCourseCodeAfter(ctx context.Context, courseCode string, sortBy, deliveryMode string, after *models.ReviewCursor, limit int) ([]models.Review, error) {
+	return r.listByCourse(ctx, courseCode, sortBy, deliveryMode, after, limit, 0)
+}
+
+// listByCourse pages through a course's reviews by offset, or after a cursor
+// when one is given. id breaks ties in every order so cursors are exact.
+func (r *ReviewRepository) listByCourse(ctx context.Context, courseCode string, sortBy, deliveryMode string, after *models.ReviewCursor, limit, offset int) ([]models.Review, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	args := []any{courseCode, limit, offset, deliveryMode}
+	var orderClause, keyset string
 	switch sortBy {
-	case "earliest":
-		orderClause = "ORDER BY created_at ASC"
-	case "recent":
+	case models.ReviewSortEarliest:
+		orderClause = "ORDER BY created_at ASC, id ASC"
+		keyset = "AND (created_at, id) > ($5, $6)"
+	case models.ReviewSortMostHelpful:
+		orderClause = "ORDER BY votes.helpful_count - votes.not_helpful_count DESC, created_at DESC, id DESC"
+		keyset = "AND (votes.helpful_count - votes.not_helpful_count, created_at, id) < ($7, $5, $6)"
+	case models.ReviewSortRecent:
 		fallthrough
 	default:
-		orderClause = "ORDER BY created_at DESC"
+		orderClause = "ORDER BY created_at DESC, id DESC"
+		keyset = "AND (created_at, id) < ($5, $6)"
+	}
+	if after == nil {
+		keyset = ""
+	} else {
+		args = append(args, after.CreatedAt, after.ID)
+		if sortBy == models.ReviewSortMostHelpful {
+			args = append(args, after.Score)
+		}
 	}
 
 	query := fmt.Sprintf(`
@@ -75,21 +161,32 @@ func (r *ReviewRepository) GetByCourseCode(ctx context.Context, courseCode strin
 			difficulty,
 			real_world_relevance,
 			review_text,
+			delivery_mode,
+			academic_year,
+			term,
 			created_at,
-			updated_at
+			updated_at,
+			edited_at,
+			votes.helpful_count,
+			votes.not_helpful_count
 		FROM reviews
-		WHERE course_code = $1
+		LEFT JOIN LATERAL (
+			SELECT COUNT(*) FILTER (WHERE helpful) AS helpful_count, COUNT(*) FILTER (WHERE NOT helpful) AS not_helpful_count
+			FROM review_votes WHERE review_id = reviews.id
+		) votes ON true
+		WHERE course_code = $1 AND %s AND ($4 = '' OR delivery_mode = $4)
+		%s
 		%s
 		LIMIT $2 OFFSET $3
-	`, orderClause)
+	`, publishedFilter, keyset, orderClause)
 
-	rows, err := r.db.Query(ctx, query, courseCode, limit, offset)
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var reviews []models.Review
+	reviews := []models.Review{}
 	for rows.Next() {
 		var review models.Review
 		err := rows.Scan(
@@ -101,8 +198,14 @@ func (r *ReviewRepository) GetByCourseCode(ctx context.Context, courseCode strin
 			&review.Difficulty,
 			&review.RealWorldRelevance,
 			&review.ReviewText,
+			&review.DeliveryMode,
+			&review.AcademicYear,
+			&review.Term,
 			&review.CreatedAt,
 			&review.UpdatedAt,
+			&review.EditedAt,
+			&review.HelpfulCount,
+			&review.NotHelpfulCount,
 		)
 		if err != nil {
 			return nil, err
@@ -113,7 +216,11 @@ func (r *ReviewRepository) GetByCourseCode(ctx context.Context, courseCode strin
 	return reviews, nil
 }
 
-func (r *ReviewRepository) GetCourseStats(ctx context.Context, courseCode string) (map[string]interface{}, error) {
+// GetCourseStats aggregates reviews created at or after since. Pass the zero time to include every review.
+func (r *ReviewRepository) GetCourseStats(ctx context.Context, courseCode string, since time.Time) (map[string]interface{}, error) {
+	ctx, cancel := withDeadline(ctx, opAggregate)
+	defer cancel()
+
 	query := `
 		SELECT 
 			COUNT(*) as total_reviews,
@@ -122,7 +229,7 @@ func (r *ReviewRepository) GetCourseStats(ctx context.Context, courseCode string
 			COALESCE(AVG(difficulty), 0) as avg_difficulty,
 			COALESCE(AVG(real_world_relevance), 0) as avg_real_world_relevance
 		FROM reviews
-		WHERE course_code = $1
+		WHERE course_code = $1 AND created_at >= $2 AND ` + publishedFilter + `
 	`
 
 	var stats struct {
@@ -133,7 +240,7 @@ func (r *ReviewRepository) GetCourseStats(ctx context.Context, courseCode string
 		AvgRealWorldRelevance float64
 	}
 
-	err := r.db.QueryRow(ctx, query, courseCode).Scan(
+	err := r.db.QueryRow(ctx, query, courseCode, since).Scan(
 		&stats.TotalReviews,
 		&stats.Likes,
 		&stats.Dislikes,
@@ -149,6 +256,16 @@ func (r *ReviewRepository) GetCourseStats(ctx context.Context, courseCode string
 		likePercentage = int(float64(stats.Likes) / float64(stats.TotalReviews) * 100)
 	}
 
+	topTags, err := r.getTopTags(ctx, courseCode, since, stats.TotalReviews)
+	if err != nil {
+		return nil, err
+	}
+
+	byDeliveryMode, err := r.getDeliveryModeStats(ctx, courseCode, since, stats.TotalReviews)
+	if err != nil {
+		return nil, err
+	}
+
 	return map[string]interface{}{
 		"total_reviews":            stats.TotalReviews,
 		"likes":                    stats.Likes,
@@ -156,36 +273,158 @@ func (r *ReviewRepository) GetCourseStats(ctx context.Context, courseCode string
 		"like_percentage":          likePercentage,
 		"avg_difficulty":           stats.AvgDifficulty,
 		"avg_real_world_relevance": stats.AvgRealWorldRelevance,
+		"top_tags":                 topTags,
+		"by_delivery_mode":         byDeliveryMode,
 	}, nil
 }
 
-func (r *ReviewRepository) GetAll(ctx context.Context) ([]models.Review, error) {
+// GetRatingHistogram counts a course's published reviews since since by their
+// difficulty and real-world relevance ratings.
+func (r *ReviewRepository) GetRatingHistogram(ctx context.Context, courseCode string, since time.Time) (*models.RatingHistogram, error) {
+	ctx, cancel := withDeadline(ctx, opAggregate)
+	defer cancel()
+
+	rows, err := r.db.Query(ctx,
+		`SELECT 'difficulty', difficulty, COUNT(*)
+		 FROM reviews
+		 WHERE course_code = $1 AND created_at >= $2 AND `+publishedFilter+`
+		 GROUP BY difficulty
+		 UNION ALL
+		 SELECT 'real_world_relevance', real_world_relevance, COUNT(*)
+		 FROM reviews
+		 WHERE course_code = $1 AND created_at >= $2 AND `+publishedFilter+`
+		 GROUP BY real_world_relevance`,
+		courseCode, since,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query rating histogram: %w", err)
+	}
+	defer rows.Close()
+
+	histogram := &models.RatingHistogram{}
+	for rows.Next() {
+		var rating string
+		var value, count int
+		if err := rows.Scan(&rating, &value, &count); err != nil {
+			return nil, fmt.Errorf("scan rating histogram: %w", err)
+		}
+		if value < 1 || value > 5 {
+			continue
+		}
+		switch rating {
+		case "difficulty":
+			histogram.Difficulty[value-1] = count
+		case "real_world_relevance":
+			histogram.RealWorldRelevance[value-1] = count
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rating histogram: %w", err)
+	}
+	return histogram, nil
+}
+
+// getDeliveryModeStats breaks a course's stats down by how reviewers took it.
+// Reviews that don't say are only in the overall stats.
+func (r *ReviewRepository) getDeliveryModeStats(ctx context.Context, courseCode string, since time.Time, totalReviews int) ([]models.DeliveryModeStats, error) {
+	modes := []models.DeliveryModeStats{}
+	if totalReviews == 0 {
+		return modes, nil
+	}
+
+	rows, err := r.db.Query(ctx,
+		`SELECT delivery_mode,
+		        COUNT(*),
+		        COUNT(*) FILTER (WHERE liked),
+		        AVG(difficulty)::float8,
+		        AVG(real_world_relevance)::float8
+		 FROM reviews
+		 WHERE course_code = $1 AND created_at >= $2 AND delivery_mode IS NOT NULL AND `+publishedFilter+`
+		 GROUP BY delivery_mode
+		 ORDER BY delivery_mode`,
+		courseCode, since,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query delivery mode stats: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var m models.DeliveryModeStats
+		var likes int
+		if err := rows.Scan(&m.DeliveryMode, &m.TotalReviews, &likes, &m.AvgDifficulty, &m.AvgRealWorldRelevance); err != nil {
+			return nil, fmt.Errorf("failed to scan delivery mode stats: %w", err)
+		}
+		m.LikePercentage = int(float64(likes) / float64(m.TotalReviews) * 100)
+		modes = append(modes, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate delivery mode stats: %w", err)
+	}
+	return modes, nil
+}
+
+// getTopTags returns the most chosen tags for a course that clear the minTagVotes and minTagShare thresholds.
+func (r *ReviewRepository) getTopTags(ctx context.Context, courseCode string, since time.Time, totalReviews int) ([]models.TagCount, error) {
+	topTags := []models.TagCount{}
+	if totalReviews == 0 {
+		return topTags, nil
+	}
+
 	query := `
-		SELECT 
-			id,
-			course_code,
-			email,
-			author_name,
-			liked,
-			difficulty,
-			real_world_relevance,
-			review_text,
-			created_at,
-			updated_at
-		FROM reviews
-		ORDER BY created_at DESC
+		SELECT rt.tag, COUNT(*) AS votes
+		FROM review_tags rt
+		JOIN reviews r ON r.id = rt.review_id
+		WHERE r.course_code = $1 AND r.created_at >= $2 AND r.moderation = 'approved' AND (r.publish_at IS NULL OR r.publish_at <= NOW())
+		GROUP BY rt.tag
+		HAVING COUNT(*) >= $3
+		ORDER BY votes DESC, rt.tag
+		LIMIT $4
 	`
 
-	rows, err := r.db.Query(ctx, query)
+	rows, err := r.db.Query(ctx, query, courseCode, since, minTagVotes, topTagsLimit)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to query top tags: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tc models.TagCount
+		if err := rows.Scan(&tc.Tag, &tc.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan tag count: %w", err)
+		}
+		if float64(tc.Count) < minTagShare*float64(totalReviews) {
+			continue
+		}
+		topTags = append(topTags, tc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate top tags: %w", err)
+	}
+
+	return topTags, nil
+}
+
+// StreamAll calls fn with each published review, newest first, without
+// holding the whole table in memory. It stops at the first error fn returns.
+func (r *ReviewRepository) StreamAll(ctx context.Context, fn func(models.Review) error) error {
+	ctx, cancel := withDeadline(ctx, opExport)
+	defer cancel()
+
+	rows, err := r.db.Query(ctx,
+		`SELECT id, course_code, email, author_name, liked, difficulty, real_world_relevance, review_text, delivery_mode, academic_year, term, created_at, updated_at, edited_at
+		 FROM reviews
+		 WHERE `+publishedFilter+`
+		 ORDER BY created_at DESC`,
+	)
+	if err != nil {
+		return fmt.Errorf("query reviews: %w", err)
 	}
 	defer rows.Close()
 
-	var reviews []models.Review
 	for rows.Next() {
 		var review models.Review
-		err := rows.Scan(
+		if err := rows.Scan(
 			&review.ID,
 			&review.CourseCode,
 			&review.Email,
@@ -194,14 +433,282 @@ func (r *ReviewRepository) GetAll(ctx context.Context) ([]models.Review, error)
 			&review.Difficulty,
 			&review.RealWorldRelevance,
 			&review.ReviewText,
+			&review.DeliveryMode,
+			&review.AcademicYear,
+			&review.Term,
 			&review.CreatedAt,
 			&review.UpdatedAt,
-		)
+			&review.EditedAt,
+		); err != nil {
+			return fmt.Errorf("scan review: %w", err)
+		}
+		if err := fn(review); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate reviews: %w", err)
+	}
+	return nil
+}
+
+// HasReviewed reports whether email already has a review for courseCode in
+// the given term (mirrors the UNIQUE constraint).
+func (r *ReviewRepository) HasReviewed(ctx context.Context, courseCode, email string, academicYear int, term string) (bool, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	var exists bool
+	err := r.db.QueryRow(ctx,
+		`SELECT EXISTS(SELECT 1 FROM reviews WHERE course_code = $1 AND email = $2 AND academic_year = $3 AND term = $4)`,
+		courseCode, email, academicYear, term,
+	).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// GetByAuthor returns email's latest review of courseCode whether or not it
+// is published, or nil if there is none.
+func (r *ReviewRepository) GetByAuthor(ctx context.Context, courseCode, email string) (*models.Review, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	review, err := scanHeldReview(r.db.QueryRow(ctx,
+		`SELECT `+heldReviewColumns+` FROM reviews WHERE course_code = $1 AND email = $2
+		 ORDER BY created_at DESC LIMIT 1`,
+		courseCode, email,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return review, err
+}
+
+// ListEmbargoed returns reviews that are not published yet: those held by
+// moderation, oldest first, then embargoed ones, soonest to publish first.
+func (r *ReviewRepository) ListEmbargoed(ctx context.Context) ([]models.Review, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	rows, err := r.db.Query(ctx,
+		`SELECT `+heldReviewColumns+`
+		 FROM reviews
+		 WHERE publish_at > NOW() OR moderation = 'pending'
+		 ORDER BY moderation = 'pending' DESC, publish_at, created_at`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query embargoed reviews: %w", err)
+	}
+	defer rows.Close()
+
+	reviews := []models.Review{}
+	for rows.Next() {
+		review, err := scanHeldReview(rows)
 		if err != nil {
 			return nil, err
 		}
-		reviews = append(reviews, review)
+		reviews = append(reviews, *review)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate embargoed reviews: %w", err)
 	}
-
 	return reviews, nil
 }
+
+// SetPublishAt changes when a review goes public; NULL publishes it now,
+// approving it if moderation or its reports held it. It returns the updated review, or nil if
+// there is no review with that id.
+func (r *ReviewRepository) SetPublishAt(ctx context.Context, id string, publishAt dbtypes.NullTime) (*models.Review, error) {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	review, err := scanHeldReview(r.db.QueryRow(ctx,
+		`UPDATE reviews
+		 SET publish_at = $2,
+		     moderation = CASE WHEN $2::timestamp IS NULL THEN 'approved' ELSE moderation END,
+		     hidden_at = CASE WHEN $2::timestamp IS NULL THEN NULL ELSE hidden_at END,
+		     updated_at = NOW()
+		 WHERE id = $1
+		 RETURNING `+heldReviewColumns,
+		id, publishAt,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return review, err
+}
+
+// Update replaces the content and tags of the review matching review's ID,
+// CourseCode and Email, so only its author can change it, and keeps what it
+// replaced as a revision. A review.Moderation of pending holds the edit for a
+// moderator: an approved review goes back to pending, and an unverified one
+// will be pending once confirmed. Otherwise moderation is left alone. It
+// returns the updated review, or nil if no review matches.
+func (r *ReviewRepository) Update(ctx context.Context, review *models.Review) (*models.Review, error) {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	// Review and tags in one statement, as in Create. Tags that stay are left
+	// alone rather than deleted and re-inserted, which one statement can't do.
+	// previous reads the review as it was, since every part sees the same snapshot
+	updated, err := scanHeldReview(r.db.QueryRow(ctx,
+		`WITH previous AS (
+			SELECT id, author_name, liked, difficulty, real_world_relevance, review_text, delivery_mode,
+			       ARRAY(SELECT tag FROM review_tags WHERE review_id = reviews.id ORDER BY tag) AS tags,
+			       COALESCE(edited_at, created_at) AS written_at
+			FROM reviews
+			WHERE id = $1 AND course_code = $2 AND email = $3
+		), updated AS (
+			UPDATE reviews
+			SET author_name = $4, liked = $5, difficulty = $6, real_world_relevance = $7,
+			    review_text = $8, delivery_mode = $9, updated_at = NOW(), edited_at = NOW(),
+			    moderation = CASE WHEN $11 = 'pending' AND moderation = 'approved' THEN 'pending' ELSE moderation END
+			WHERE id = $1 AND course_code = $2 AND email = $3
+			RETURNING `+heldReviewColumns+`
+		), held_verification AS (
+			UPDATE review_verifications v SET moderation = 'pending'
+			FROM updated
+			WHERE v.review_id = updated.id AND $11 = 'pending'
+		), revision AS (
+			INSERT INTO review_revisions (review_id, author_name, liked, difficulty, real_world_relevance, review_text, delivery_mode, tags, written_at)
+			SELECT id, author_name, liked, difficulty, real_world_relevance, review_text, delivery_mode, tags, written_at
+			FROM previous
+		), dropped_tags AS (
+			DELETE FROM review_tags t USING updated
+			WHERE t.review_id = updated.id AND t.tag <> ALL(COALESCE($10::text[], '{}'))
+		), added_tags AS (
+			INSERT INTO review_tags (review_id, tag)
+			SELECT id, unnest($10::text[]) FROM updated
+			ON CONFLICT DO NOTHING
+		)
+		SELECT `+heldReviewColumns+` FROM updated`,
+		review.ID, review.CourseCode, review.Email,
+		review.AuthorName, review.Liked, review.Difficulty, review.RealWorldRelevance,
+		review.ReviewText, review.DeliveryMode, review.Tags, review.Moderation,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	updated.Tags = review.Tags
+	return updated, nil
+}
+
+// GetByID returns the review with id and its tags, whether or not it is
+// published, or nil if there is none.
+func (r *ReviewRepository) GetByID(ctx context.Context, id string) (*models.Review, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	var tags []string
+	review, err := scanHeldReview(r.db.QueryRow(ctx,
+		`SELECT `+heldReviewColumns+`,
+		        ARRAY(SELECT tag FROM review_tags WHERE review_id = reviews.id ORDER BY tag)
+		 FROM reviews WHERE id = $1`,
+		id,
+	), &tags)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	review.Tags = tags
+	return review, nil
+}
+
+// ListRevisions returns the earlier versions of a review, oldest first and
+// numbered from 1. Changed is left for the caller, which knows the current
+// version.
+func (r *ReviewRepository) ListRevisions(ctx context.Context, reviewID string) ([]models.ReviewRevision, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	rows, err := r.db.Query(ctx,
+		`SELECT author_name, liked, difficulty, real_world_relevance, review_text, delivery_mode, tags, written_at, replaced_at
+		 FROM review_revisions
+		 WHERE review_id = $1
+		 ORDER BY id`,
+		reviewID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query review revisions: %w", err)
+	}
+	defer rows.Close()
+
+	revisions := []models.ReviewRevision{}
+	for rows.Next() {
+		var content models.ReviewContent
+		revision := models.ReviewRevision{Revision: len(revisions) + 1, Content: &content}
+		if err := rows.Scan(
+			&content.AuthorName,
+			&content.Liked,
+			&content.Difficulty,
+			&content.RealWorldRelevance,
+			&content.ReviewText,
+			&content.DeliveryMode,
+			&content.Tags,
+			&revision.WrittenAt,
+			&revision.ReplacedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan review revision: %w", err)
+		}
+		revisions = append(revisions, revision)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate review revisions: %w", err)
+	}
+	return revisions, nil
+}
+
+// Delete removes the review with id on courseCode written from email, and its
+// tags with it. It reports false if no review matches.
+func (r *ReviewRepository) Delete(ctx context.Context, id, courseCode, email string) (bool, error) {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	tag, err := r.db.Exec(ctx,
+		`DELETE FROM reviews WHERE id = $1 AND course_code = $2 AND email = $3`,
+		id, courseCode, email,
+	)
+	if err != nil {
+		return false, fmt.Errorf("delete review: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// heldReviewColumns are read by the queries that can see embargoed reviews.
+const heldReviewColumns = `id, course_code, email, author_name, liked, difficulty, real_world_relevance, review_text, delivery_mode, academic_year, term, publish_at, moderation, created_at, updated_at, edited_at`
+
+// scanHeldReview scans heldReviewColumns, then any extra columns into extra.
+func scanHeldReview(row pgx.Row, extra ...any) (*models.Review, error) {
+	var review models.Review
+	if err := row.Scan(append([]any{
+		&review.ID,
+		&review.CourseCode,
+		&review.Email,
+		&review.AuthorName,
+		&review.Liked,
+		&review.Difficulty,
+		&review.RealWorldRelevance,
+		&review.ReviewText,
+		&review.DeliveryMode,
+		&review.AcademicYear,
+		&review.Term,
+		&review.PublishAt,
+		&review.Moderation,
+		&review.CreatedAt,
+		&review.UpdatedAt,
+		&review.EditedAt,
+	}, extra...)...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("scan review: %w", err)
+	}
+	return &review, nil
+}
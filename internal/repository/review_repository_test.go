@@ -4,8 +4,11 @@ import (
 	"context"
 	"testing"
 	"time"
+	"yuplan/internal/dbtypes"
 	"yuplan/internal/models"
 
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
 	"github.com/pashagolub/pgxmock"
 	"github.com/stretchr/testify/assert"
 )
@@ -23,11 +26,11 @@ func TestReviewRepository_Create(t *testing.T) {
 	review := &models.Review{
 		CourseCode:         "EECS2030",
 		Email:              "student@yorku.ca",
-		AuthorName:         &authorName,
+		AuthorName:         dbtypes.NewNullString(authorName),
 		Liked:              true,
 		Difficulty:         3,
 		RealWorldRelevance: 5,
-		ReviewText:         &reviewText,
+		ReviewText:         dbtypes.NewNullString(reviewText),
 	}
 
 	now := time.Now()
@@ -42,6 +45,13 @@ func TestReviewRepository_Create(t *testing.T) {
 			review.ReviewText,
 			pgxmock.AnyArg(), // created_at
 			pgxmock.AnyArg(), // updated_at
+			review.Tags,
+			review.PublishAt,
+			review.DeliveryMode,
+			review.AcademicYear,
+			review.Term,
+			pgxmock.AnyArg(), // id
+			models.ModerationApproved,
 		).
 		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow("test-review-id"))
 
@@ -57,6 +67,23 @@ func TestReviewRepository_Create(t *testing.T) {
 	assert.WithinDuration(t, now, review.UpdatedAt, 5*time.Second)
 }
 
+func TestReviewRepository_CreateDuplicateTerm(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewReviewRepository(mock)
+
+	mock.ExpectQuery("INSERT INTO reviews").
+		WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "reviews_course_code_email_term_key"})
+
+	err = repo.Create(context.Background(), &models.Review{
+		CourseCode: "EECS2030", Email: "student@yorku.ca", AcademicYear: 2025, Term: models.TermFall,
+	})
+	assert.ErrorIs(t, err, ErrDuplicateReview)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestReviewRepository_CreateAnonymous(t *testing.T) {
 	mock, err := pgxmock.NewPool()
 	assert.NoError(t, err)
@@ -69,11 +96,11 @@ func TestReviewRepository_CreateAnonymous(t *testing.T) {
 	review := &models.Review{
 		CourseCode:         "EECS2030",
 		Email:              "student@yorku.ca",
-		AuthorName:         nil, // Anonymous
+		AuthorName:         dbtypes.NullString{}, // Anonymous
 		Liked:              true,
 		Difficulty:         3,
 		RealWorldRelevance: 5,
-		ReviewText:         &reviewText,
+		ReviewText:         dbtypes.NewNullString(reviewText),
 	}
 
 	mock.ExpectQuery("INSERT INTO reviews").
@@ -87,13 +114,64 @@ func TestReviewRepository_CreateAnonymous(t *testing.T) {
 			review.ReviewText,
 			pgxmock.AnyArg(), // created_at
 			pgxmock.AnyArg(), // updated_at
+			review.Tags,
+			review.PublishAt,
+			review.DeliveryMode,
+			review.AcademicYear,
+			review.Term,
+			pgxmock.AnyArg(), // id
+			models.ModerationApproved,
+		).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow("test-review-id"))
+
+	err = repo.Create(ctx, review)
+	assert.NoError(t, err)
+	assert.Equal(t, "test-review-id", review.ID)
+	assert.False(t, review.AuthorName.Valid)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewRepository_CreateWithTags(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewReviewRepository(mock)
+	ctx := context.Background()
+
+	review := &models.Review{
+		CourseCode:         "EECS2030",
+		Email:              "student@yorku.ca",
+		Liked:              true,
+		Difficulty:         4,
+		RealWorldRelevance: 5,
+		Tags:               []string{models.ReviewTagProgrammingHeavy, models.ReviewTagCareerRelevant},
+	}
+
+	mock.ExpectQuery("INSERT INTO reviews(.+)INSERT INTO review_tags(.+)unnest\\(\\$10::text\\[\\]\\)").
+		WithArgs(
+			review.CourseCode,
+			review.Email,
+			review.AuthorName,
+			review.Liked,
+			review.Difficulty,
+			review.RealWorldRelevance,
+			review.ReviewText,
+			pgxmock.AnyArg(), // created_at
+			pgxmock.AnyArg(), // updated_at
+			review.Tags,
+			review.PublishAt,
+			review.DeliveryMode,
+			review.AcademicYear,
+			review.Term,
+			pgxmock.AnyArg(), // id
+			models.ModerationApproved,
 		).
 		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow("test-review-id"))
 
 	err = repo.Create(ctx, review)
 	assert.NoError(t, err)
 	assert.Equal(t, "test-review-id", review.ID)
-	assert.Nil(t, review.AuthorName)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -112,28 +190,90 @@ func TestReviewRepository_GetByCourseCode(t *testing.T) {
 
 	rows := pgxmock.NewRows([]string{
 		"id", "course_code", "email", "author_name", "liked", "difficulty", "real_world_relevance",
-		"review_text", "created_at", "updated_at",
+		"review_text", "delivery_mode", "academic_year", "term", "created_at", "updated_at", "edited_at", "helpful_count", "not_helpful_count",
 	}).
 		AddRow(
 			"review-1", courseCode, "student1@yorku.ca", &authorName, true, 3, 5,
-			&reviewText, now, now,
+			&reviewText, nil, 2025, models.TermFall, now, now, dbtypes.NullTime{}, 4, 1,
 		).
 		AddRow(
 			"review-2", courseCode, "student2@yorku.ca", nil, true, 4, 4,
-			&reviewText, now.Add(-1*time.Hour), now.Add(-1*time.Hour),
+			&reviewText, nil, 2025, models.TermFall, now.Add(-1*time.Hour), now.Add(-1*time.Hour), dbtypes.NullTime{}, 0, 0,
 		)
 
 	mock.ExpectQuery("SELECT(.+)FROM reviews(.+)WHERE course_code = (.+)ORDER BY created_at DESC").
-		WithArgs(courseCode, 10, 0).
+		WithArgs(courseCode, 10, 0, "").
 		WillReturnRows(rows)
 
-	reviews, err := repo.GetByCourseCode(ctx, courseCode, "recent", 10, 0)
+	reviews, err := repo.GetByCourseCode(ctx, courseCode, "recent", "", 10, 0)
 	assert.NoError(t, err)
 	assert.Len(t, reviews, 2)
 	assert.Equal(t, "review-1", reviews[0].ID)
-	assert.NotNil(t, reviews[0].AuthorName)
-	assert.Equal(t, "John Smith", *reviews[0].AuthorName)
-	assert.Nil(t, reviews[1].AuthorName) // Anonymous
+	assert.True(t, reviews[0].AuthorName.Valid)
+	assert.Equal(t, "John Smith", reviews[0].AuthorName.String)
+	assert.False(t, reviews[1].AuthorName.Valid) // Anonymous
+	assert.Equal(t, 4, reviews[0].HelpfulCount)
+	assert.Equal(t, 1, reviews[0].NotHelpfulCount)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewRepository_GetByCourseCodeAfter(t *testing.T) {
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		sort   string
+		query  string
+		args   []any
+		cursor models.ReviewCursor
+	}{
+		{models.ReviewSortRecent, "AND \\(created_at, id\\) < \\(\\$5, \\$6\\)\\s+ORDER BY created_at DESC, id DESC",
+			[]any{"EECS2030", 11, 0, "", createdAt, "review-1"}, models.ReviewCursor{CreatedAt: createdAt, ID: "review-1"}},
+		{models.ReviewSortEarliest, "AND \\(created_at, id\\) > \\(\\$5, \\$6\\)\\s+ORDER BY created_at ASC, id ASC",
+			[]any{"EECS2030", 11, 0, "", createdAt, "review-1"}, models.ReviewCursor{CreatedAt: createdAt, ID: "review-1"}},
+		{models.ReviewSortMostHelpful, "AND \\(votes.helpful_count - votes.not_helpful_count, created_at, id\\) < \\(\\$7, \\$5, \\$6\\)",
+			[]any{"EECS2030", 11, 0, "", createdAt, "review-1", 3}, models.ReviewCursor{Score: 3, CreatedAt: createdAt, ID: "review-1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			assert.NoError(t, err)
+			defer mock.Close()
+
+			repo := NewReviewRepository(mock)
+			mock.ExpectQuery("SELECT(.+)FROM reviews(.+)" + tt.query).
+				WithArgs(tt.args...).
+				WillReturnRows(pgxmock.NewRows([]string{
+					"id", "course_code", "email", "author_name", "liked", "difficulty", "real_world_relevance",
+					"review_text", "delivery_mode", "academic_year", "term", "created_at", "updated_at", "edited_at",
+					"helpful_count", "not_helpful_count",
+				}))
+
+			cursor := tt.cursor
+			reviews, err := repo.GetByCourseCodeAfter(context.Background(), "EECS2030", tt.sort, "", &cursor, 11)
+			assert.NoError(t, err)
+			assert.Empty(t, reviews)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestReviewRepository_GetByCourseCode_NoReviews(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewReviewRepository(mock)
+
+	mock.ExpectQuery("SELECT(.+)FROM reviews").
+		WithArgs("EECS2030", 10, 0, "").
+		WillReturnRows(pgxmock.NewRows([]string{
+			"id", "course_code", "email", "author_name", "liked", "difficulty", "real_world_relevance",
+			"review_text", "delivery_mode", "academic_year", "term", "created_at", "updated_at", "edited_at", "helpful_count", "not_helpful_count",
+		}))
+
+	reviews, err := repo.GetByCourseCode(context.Background(), "EECS2030", "recent", "", 10, 0)
+	assert.NoError(t, err)
+	assert.NotNil(t, reviews) // encodes as [] rather than null
+	assert.Empty(t, reviews)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -151,28 +291,47 @@ func TestReviewRepository_GetByCourseCode_WithEarliestSort(t *testing.T) {
 
 	rows := pgxmock.NewRows([]string{
 		"id", "course_code", "email", "author_name", "liked", "difficulty", "real_world_relevance",
-		"review_text", "created_at", "updated_at",
+		"review_text", "delivery_mode", "academic_year", "term", "created_at", "updated_at", "edited_at", "helpful_count", "not_helpful_count",
 	}).
 		AddRow(
 			"review-1", courseCode, "student1@yorku.ca", nil, true, 3, 5,
-			&reviewText, now.Add(-2*time.Hour), now.Add(-2*time.Hour),
+			&reviewText, nil, 2025, models.TermFall, now.Add(-2*time.Hour), now.Add(-2*time.Hour), dbtypes.NullTime{}, 0, 0,
 		).
 		AddRow(
 			"review-2", courseCode, "student2@yorku.ca", nil, true, 4, 4,
-			&reviewText, now, now,
+			&reviewText, nil, 2025, models.TermFall, now, now, dbtypes.NullTime{}, 4, 1,
 		)
 
 	mock.ExpectQuery("SELECT(.+)FROM reviews(.+)WHERE course_code = (.+)ORDER BY created_at ASC").
-		WithArgs(courseCode, 10, 0).
+		WithArgs(courseCode, 10, 0, "").
 		WillReturnRows(rows)
 
-	reviews, err := repo.GetByCourseCode(ctx, courseCode, "earliest", 10, 0)
+	reviews, err := repo.GetByCourseCode(ctx, courseCode, "earliest", "", 10, 0)
 	assert.NoError(t, err)
 	assert.Len(t, reviews, 2)
 	assert.Equal(t, "review-1", reviews[0].ID)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestReviewRepository_GetByCourseCode_MostHelpful(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewReviewRepository(mock)
+
+	mock.ExpectQuery("LEFT JOIN LATERAL(.+)FROM review_votes WHERE review_id = reviews.id(.+)ORDER BY votes.helpful_count - votes.not_helpful_count DESC, created_at DESC").
+		WithArgs("EECS2030", 10, 0, "").
+		WillReturnRows(pgxmock.NewRows([]string{
+			"id", "course_code", "email", "author_name", "liked", "difficulty", "real_world_relevance",
+			"review_text", "delivery_mode", "academic_year", "term", "created_at", "updated_at", "edited_at", "helpful_count", "not_helpful_count",
+		}))
+
+	_, err = repo.GetByCourseCode(context.Background(), "EECS2030", models.ReviewSortMostHelpful, "", 10, 0)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestReviewRepository_GetCourseStats(t *testing.T) {
 	mock, err := pgxmock.NewPool()
 	assert.NoError(t, err)
@@ -187,11 +346,25 @@ func TestReviewRepository_GetCourseStats(t *testing.T) {
 		"total_reviews", "likes", "dislikes", "avg_difficulty", "avg_real_world_relevance",
 	}).AddRow(10, 7, 3, 3.5, 4.2)
 
-	mock.ExpectQuery("SELECT(.+)FROM reviews(.+)WHERE course_code = (.+)").
-		WithArgs(courseCode).
+	since := time.Now().AddDate(-3, 0, 0)
+
+	mock.ExpectQuery("SELECT(.+)FROM reviews(.+)WHERE course_code = \\$1 AND created_at >= \\$2").
+		WithArgs(courseCode, since).
 		WillReturnRows(rows)
 
-	stats, err := repo.GetCourseStats(ctx, courseCode)
+	mock.ExpectQuery("SELECT rt.tag, COUNT(.+)FROM review_tags rt(.+)HAVING COUNT\\(\\*\\) >= \\$3").
+		WithArgs(courseCode, since, minTagVotes, topTagsLimit).
+		WillReturnRows(pgxmock.NewRows([]string{"tag", "votes"}).
+			AddRow(models.ReviewTagMathHeavy, 6).
+			AddRow(models.ReviewTagHeavyWorkload, 4))
+
+	mock.ExpectQuery("SELECT delivery_mode(.+)FROM reviews(.+)GROUP BY delivery_mode").
+		WithArgs(courseCode, since).
+		WillReturnRows(pgxmock.NewRows([]string{"delivery_mode", "count", "likes", "avg_difficulty", "avg_real_world_relevance"}).
+			AddRow(models.ReviewDeliveryInPerson, 4, 3, 3.0, 4.0).
+			AddRow(models.ReviewDeliveryOnline, 3, 1, 4.5, 4.0))
+
+	stats, err := repo.GetCourseStats(ctx, courseCode, since)
 	assert.NoError(t, err)
 	assert.Equal(t, 10, stats["total_reviews"])
 	assert.Equal(t, 7, stats["likes"])
@@ -199,10 +372,90 @@ func TestReviewRepository_GetCourseStats(t *testing.T) {
 	assert.Equal(t, 70, stats["like_percentage"])
 	assert.Equal(t, 3.5, stats["avg_difficulty"])
 	assert.Equal(t, 4.2, stats["avg_real_world_relevance"])
+	assert.Equal(t, []models.TagCount{
+		{Tag: models.ReviewTagMathHeavy, Count: 6},
+		{Tag: models.ReviewTagHeavyWorkload, Count: 4},
+	}, stats["top_tags"])
+	assert.Equal(t, []models.DeliveryModeStats{
+		{DeliveryMode: models.ReviewDeliveryInPerson, TotalReviews: 4, LikePercentage: 75, AvgDifficulty: 3.0, AvgRealWorldRelevance: 4.0},
+		{DeliveryMode: models.ReviewDeliveryOnline, TotalReviews: 3, LikePercentage: 33, AvgDifficulty: 4.5, AvgRealWorldRelevance: 4.0},
+	}, stats["by_delivery_mode"])
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestReviewRepository_GetAll(t *testing.T) {
+func TestReviewRepository_GetRatingHistogram(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewReviewRepository(mock)
+	since := time.Now().AddDate(-3, 0, 0)
+
+	mock.ExpectQuery("SELECT 'difficulty', difficulty, COUNT(.+)GROUP BY difficulty UNION ALL SELECT 'real_world_relevance'").
+		WithArgs("EECS2030", since).
+		WillReturnRows(pgxmock.NewRows([]string{"rating", "value", "count"}).
+			AddRow("difficulty", 2, 3).
+			AddRow("difficulty", 5, 1).
+			AddRow("real_world_relevance", 4, 4))
+
+	histogram, err := repo.GetRatingHistogram(context.Background(), "EECS2030", since)
+	assert.NoError(t, err)
+	assert.Equal(t, [5]int{0, 3, 0, 0, 1}, histogram.Difficulty)
+	assert.Equal(t, [5]int{0, 0, 0, 4, 0}, histogram.RealWorldRelevance)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewRepository_GetCourseStats_TagShareThreshold(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewReviewRepository(mock)
+	ctx := context.Background()
+	since := time.Time{}
+
+	mock.ExpectQuery("SELECT(.+)FROM reviews").
+		WithArgs("EECS2030", since).
+		WillReturnRows(pgxmock.NewRows([]string{
+			"total_reviews", "likes", "dislikes", "avg_difficulty", "avg_real_world_relevance",
+		}).AddRow(20, 10, 10, 3.0, 3.0))
+
+	// great-for-beginners clears minTagVotes but is under 20% of 20 reviews
+	mock.ExpectQuery("SELECT rt.tag, COUNT(.+)FROM review_tags").
+		WithArgs("EECS2030", since, minTagVotes, topTagsLimit).
+		WillReturnRows(pgxmock.NewRows([]string{"tag", "votes"}).
+			AddRow(models.ReviewTagExamHeavy, 5).
+			AddRow(models.ReviewTagGreatForBeginners, 3))
+
+	mock.ExpectQuery("SELECT delivery_mode").
+		WithArgs("EECS2030", since).
+		WillReturnRows(pgxmock.NewRows([]string{"delivery_mode", "count", "likes", "avg_difficulty", "avg_real_world_relevance"}))
+
+	stats, err := repo.GetCourseStats(ctx, "EECS2030", since)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.TagCount{{Tag: models.ReviewTagExamHeavy, Count: 5}}, stats["top_tags"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewRepository_GetCourseStats_NoReviewsSkipsTags(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewReviewRepository(mock)
+
+	mock.ExpectQuery("SELECT(.+)FROM reviews").
+		WillReturnRows(pgxmock.NewRows([]string{
+			"total_reviews", "likes", "dislikes", "avg_difficulty", "avg_real_world_relevance",
+		}).AddRow(0, 0, 0, 0.0, 0.0))
+
+	stats, err := repo.GetCourseStats(context.Background(), "EECS2030", time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, []models.TagCount{}, stats["top_tags"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewRepository_StreamAll(t *testing.T) {
 	mock, err := pgxmock.NewPool()
 	assert.NoError(t, err)
 	defer mock.Close()
@@ -216,33 +469,340 @@ func TestReviewRepository_GetAll(t *testing.T) {
 
 	rows := pgxmock.NewRows([]string{
 		"id", "course_code", "email", "author_name", "liked", "difficulty", "real_world_relevance",
-		"review_text", "created_at", "updated_at",
+		"review_text", "delivery_mode", "academic_year", "term", "created_at", "updated_at", "edited_at",
 	}).
 		AddRow(
 			"review-1", "EECS2030", "student1@yorku.ca", &authorName, true, 3, 5,
-			&reviewText, now, now,
+			&reviewText, nil, 2025, models.TermFall, now, now, dbtypes.NullTime{},
 		).
 		AddRow(
 			"review-2", "EECS3101", "student2@yorku.ca", nil, false, 4, 3,
-			&reviewText, now.Add(-1*time.Hour), now.Add(-1*time.Hour),
+			&reviewText, nil, 2025, models.TermFall, now.Add(-1*time.Hour), now.Add(-1*time.Hour), dbtypes.NullTime{},
 		).
 		AddRow(
 			"review-3", "EECS2030", "student3@yorku.ca", &authorName, true, 2, 4,
-			&reviewText, now.Add(-2*time.Hour), now.Add(-2*time.Hour),
+			&reviewText, nil, 2025, models.TermFall, now.Add(-2*time.Hour), now.Add(-2*time.Hour), dbtypes.NullTime{},
 		)
 
 	mock.ExpectQuery("SELECT(.+)FROM reviews(.+)ORDER BY created_at DESC").
 		WillReturnRows(rows)
 
-	reviews, err := repo.GetAll(ctx)
+	var reviews []models.Review
+	err = repo.StreamAll(ctx, func(review models.Review) error {
+		reviews = append(reviews, review)
+		return nil
+	})
 	assert.NoError(t, err)
 	assert.Len(t, reviews, 3)
 	assert.Equal(t, "review-1", reviews[0].ID)
 	assert.Equal(t, "EECS2030", reviews[0].CourseCode)
-	assert.NotNil(t, reviews[0].AuthorName)
+	assert.True(t, reviews[0].AuthorName.Valid)
 	assert.Equal(t, "review-2", reviews[1].ID)
 	assert.Equal(t, "EECS3101", reviews[1].CourseCode)
-	assert.Nil(t, reviews[1].AuthorName) // Anonymous
+	assert.False(t, reviews[1].AuthorName.Valid) // Anonymous
 	assert.Equal(t, "review-3", reviews[2].ID)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestReviewRepository_HasReviewed(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewReviewRepository(mock)
+	ctx := context.Background()
+
+	mock.ExpectQuery("SELECT EXISTS\\(SELECT 1 FROM reviews WHERE course_code = \\$1 AND email = \\$2 AND academic_year = \\$3 AND term = \\$4\\)").
+		WithArgs("EECS2030", "student@yorku.ca", 2025, models.TermFall).
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(true))
+
+	exists, err := repo.HasReviewed(ctx, "EECS2030", "student@yorku.ca", 2025, models.TermFall)
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+var heldReviewRowColumns = []string{
+	"id", "course_code", "email", "author_name", "liked", "difficulty", "real_world_relevance",
+	"review_text", "delivery_mode", "academic_year", "term", "publish_at", "moderation", "created_at", "updated_at", "edited_at",
+}
+
+func TestReviewRepository_PublicReadsSkipEmbargoed(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewReviewRepository(mock)
+
+	mock.ExpectQuery("WHERE course_code = \\$1 AND \\(moderation = 'approved' AND \\(publish_at IS NULL OR publish_at <= NOW\\(\\)\\)\\)").
+		WithArgs("EECS2030", 10, 0, "").
+		WillReturnRows(pgxmock.NewRows([]string{
+			"id", "course_code", "email", "author_name", "liked", "difficulty", "real_world_relevance", "review_text", "delivery_mode", "academic_year", "term", "created_at", "updated_at", "edited_at", "helpful_count", "not_helpful_count",
+		}))
+
+	reviews, err := repo.GetByCourseCode(context.Background(), "EECS2030", models.ReviewSortRecent, "", 10, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, reviews)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewRepository_GetByCourseCode_FiltersDeliveryMode(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewReviewRepository(mock)
+	now := time.Now()
+	online := models.ReviewDeliveryOnline
+
+	mock.ExpectQuery("AND \\(\\$4 = '' OR delivery_mode = \\$4\\)").
+		WithArgs("EECS2030", 10, 0, models.ReviewDeliveryOnline).
+		WillReturnRows(pgxmock.NewRows([]string{
+			"id", "course_code", "email", "author_name", "liked", "difficulty", "real_world_relevance", "review_text", "delivery_mode", "academic_year", "term", "created_at", "updated_at", "edited_at", "helpful_count", "not_helpful_count",
+		}).AddRow("review-1", "EECS2030", "a@yorku.ca", nil, true, 4, 4, nil, &online, 2025, models.TermWinter, now, now, dbtypes.NullTime{}, 0, 0))
+
+	reviews, err := repo.GetByCourseCode(context.Background(), "EECS2030", models.ReviewSortRecent, models.ReviewDeliveryOnline, 10, 0)
+	assert.NoError(t, err)
+	assert.Len(t, reviews, 1)
+	assert.Equal(t, dbtypes.NewNullString(models.ReviewDeliveryOnline), reviews[0].DeliveryMode)
+	assert.Equal(t, 2025, reviews[0].AcademicYear)
+	assert.Equal(t, models.TermWinter, reviews[0].Term)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewRepository_GetByAuthor(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewReviewRepository(mock)
+	now := time.Now()
+	publishAt := now.Add(24 * time.Hour)
+
+	mock.ExpectQuery("SELECT (.+) FROM reviews WHERE course_code = \\$1 AND email = \\$2\\s+ORDER BY created_at DESC LIMIT 1").
+		WithArgs("EECS2030", "student@yorku.ca").
+		WillReturnRows(pgxmock.NewRows(heldReviewRowColumns).
+			AddRow("review-1", "EECS2030", "student@yorku.ca", dbtypes.NullString{}, true, 3, 4, dbtypes.NullString{}, dbtypes.NullString{}, 2025, models.TermFall, &publishAt, models.ModerationApproved, now, now, dbtypes.NullTime{}))
+
+	review, err := repo.GetByAuthor(context.Background(), "EECS2030", "student@yorku.ca")
+	assert.NoError(t, err)
+	assert.Equal(t, "review-1", review.ID)
+	assert.True(t, review.PublishAt.Valid)
+	assert.Equal(t, models.ReviewEmbargoed, review.StatusAt(now))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewRepository_ListEmbargoed(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewReviewRepository(mock)
+	now := time.Now()
+	publishAt := now.Add(24 * time.Hour)
+
+	mock.ExpectQuery("FROM reviews\\s+WHERE publish_at > NOW\\(\\) OR moderation = 'pending'\\s+ORDER BY moderation = 'pending' DESC, publish_at").
+		WillReturnRows(pgxmock.NewRows(heldReviewRowColumns).
+			AddRow("review-1", "EECS2030", "a@yorku.ca", dbtypes.NullString{}, true, 3, 4, dbtypes.NullString{}, dbtypes.NullString{}, 2025, models.TermFall, &publishAt, models.ModerationApproved, now, now, dbtypes.NullTime{}))
+
+	reviews, err := repo.ListEmbargoed(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, reviews, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewRepository_SetPublishAt_NotFound(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewReviewRepository(mock)
+
+	mock.ExpectQuery("UPDATE reviews SET publish_at = \\$2").
+		WithArgs("missing", dbtypes.NullTime{}).
+		WillReturnError(pgx.ErrNoRows)
+
+	review, err := repo.SetPublishAt(context.Background(), "missing", dbtypes.NullTime{})
+	assert.NoError(t, err)
+	assert.Nil(t, review)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewRepository_Update(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewReviewRepository(mock)
+	now := time.Now()
+	text := dbtypes.NewNullString("Better on a second look")
+	review := &models.Review{
+		ID:                 "review-1",
+		CourseCode:         "EECS2030",
+		Email:              "student@yorku.ca",
+		Liked:              true,
+		Difficulty:         2,
+		RealWorldRelevance: 4,
+		ReviewText:         text,
+		Tags:               []string{"beginners"},
+	}
+
+	mock.ExpectQuery("WITH previous AS (.+)FROM reviews WHERE id = \\$1(.+)updated AS \\( UPDATE reviews (.+)edited_at = NOW\\(\\),(.+) WHERE id = \\$1 AND course_code = \\$2 AND email = \\$3(.+)INSERT INTO review_revisions(.+)FROM previous(.+)DELETE FROM review_tags(.+)INSERT INTO review_tags(.+)ON CONFLICT DO NOTHING").
+		WithArgs("review-1", "EECS2030", "student@yorku.ca", review.AuthorName, true, 2, 4, text, review.DeliveryMode, []string{"beginners"}, "").
+		WillReturnRows(pgxmock.NewRows(heldReviewRowColumns).
+			AddRow("review-1", "EECS2030", "student@yorku.ca", dbtypes.NullString{}, true, 2, 4, text, dbtypes.NullString{}, 2025, models.TermFall, nil, models.ModerationApproved, now, now, dbtypes.NullTime{}))
+
+	updated, err := repo.Update(context.Background(), review)
+	assert.NoError(t, err)
+	assert.Equal(t, "review-1", updated.ID)
+	assert.Equal(t, 2, updated.Difficulty)
+	assert.Equal(t, []string{"beginners"}, updated.Tags)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewRepository_Update_Held(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewReviewRepository(mock)
+	now := time.Now()
+	text := dbtypes.NewNullString("Notes at example.com")
+	review := &models.Review{
+		ID:                 "review-1",
+		CourseCode:         "EECS2030",
+		Email:              "student@yorku.ca",
+		Difficulty:         2,
+		RealWorldRelevance: 4,
+		ReviewText:         text,
+		Moderation:         models.ModerationPending,
+	}
+
+	mock.ExpectQuery("UPDATE reviews (.+)moderation = CASE WHEN \\$11 = 'pending' AND moderation = 'approved' THEN 'pending' ELSE moderation END(.+)"+
+		"UPDATE review_verifications v SET moderation = 'pending'(.+)AND \\$11 = 'pending'").
+		WithArgs("review-1", "EECS2030", "student@yorku.ca", review.AuthorName, false, 2, 4, text, review.DeliveryMode, []string(nil), models.ModerationPending).
+		WillReturnRows(pgxmock.NewRows(heldReviewRowColumns).
+			AddRow("review-1", "EECS2030", "student@yorku.ca", dbtypes.NullString{}, false, 2, 4, text, dbtypes.NullString{}, 2025, models.TermFall, nil, models.ModerationPending, now, now, dbtypes.NewNullTime(now)))
+
+	updated, err := repo.Update(context.Background(), review)
+	assert.NoError(t, err)
+	assert.Equal(t, models.ModerationPending, updated.Moderation)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewRepository_Update_NotOwned(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewReviewRepository(mock)
+
+	mock.ExpectQuery("WITH previous AS").WillReturnError(pgx.ErrNoRows)
+
+	updated, err := repo.Update(context.Background(), &models.Review{ID: "review-1", CourseCode: "EECS2030", Email: "someone-else@yorku.ca", Difficulty: 1, RealWorldRelevance: 1})
+	assert.NoError(t, err)
+	assert.Nil(t, updated)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewRepository_GetByID(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewReviewRepository(mock)
+	now := time.Now()
+	edited := dbtypes.NewNullTime(now)
+
+	mock.ExpectQuery("SELECT (.+)ARRAY\\(SELECT tag FROM review_tags WHERE review_id = reviews.id ORDER BY tag\\) FROM reviews WHERE id = \\$1").
+		WithArgs("review-1").
+		WillReturnRows(pgxmock.NewRows(append(heldReviewRowColumns, "tags")).
+			AddRow("review-1", "EECS2030", "a@yorku.ca", dbtypes.NullString{}, true, 3, 4, dbtypes.NullString{}, dbtypes.NullString{}, 2025, models.TermFall, nil, models.ModerationPending, now, now, edited, []string{"beginners"}))
+	mock.ExpectQuery("FROM reviews WHERE id = \\$1").
+		WithArgs("missing").
+		WillReturnError(pgx.ErrNoRows)
+
+	review, err := repo.GetByID(context.Background(), "review-1")
+	assert.NoError(t, err)
+	assert.Equal(t, models.ModerationPending, review.Moderation)
+	assert.Equal(t, edited, review.EditedAt)
+	assert.Equal(t, []string{"beginners"}, review.Tags)
+
+	review, err = repo.GetByID(context.Background(), "missing")
+	assert.NoError(t, err)
+	assert.Nil(t, review)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewRepository_ListRevisions(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewReviewRepository(mock)
+	created := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	firstEdit := created.Add(24 * time.Hour)
+	secondEdit := firstEdit.Add(24 * time.Hour)
+
+	mock.ExpectQuery("SELECT (.+) FROM review_revisions WHERE review_id = \\$1 ORDER BY id").
+		WithArgs("review-1").
+		WillReturnRows(pgxmock.NewRows([]string{
+			"author_name", "liked", "difficulty", "real_world_relevance", "review_text", "delivery_mode", "tags", "written_at", "replaced_at",
+		}).
+			AddRow(dbtypes.NullString{}, false, 5, 2, dbtypes.NewNullString("Brutal"), dbtypes.NullString{}, []string{}, created, firstEdit).
+			AddRow(dbtypes.NullString{}, true, 4, 2, dbtypes.NewNullString("Brutal but fair"), dbtypes.NullString{}, []string{"heavy_workload"}, firstEdit, secondEdit))
+
+	revisions, err := repo.ListRevisions(context.Background(), "review-1")
+	assert.NoError(t, err)
+	assert.Len(t, revisions, 2)
+	assert.Equal(t, 1, revisions[0].Revision)
+	assert.Equal(t, created, revisions[0].WrittenAt)
+	assert.Equal(t, 5, revisions[0].Content.Difficulty)
+	assert.Equal(t, 2, revisions[1].Revision)
+	assert.Equal(t, secondEdit, revisions[1].ReplacedAt)
+	assert.Equal(t, []string{"heavy_workload"}, revisions[1].Content.Tags)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewRepository_Delete(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewReviewRepository(mock)
+
+	mock.ExpectExec("DELETE FROM reviews WHERE id = \\$1 AND course_code = \\$2 AND email = \\$3").
+		WithArgs("review-1", "EECS2030", "student@yorku.ca").
+		WillReturnResult(pgxmock.NewResult("DELETE", 1))
+	mock.ExpectExec("DELETE FROM reviews").
+		WithArgs("review-1", "EECS2030", "someone-else@yorku.ca").
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+
+	deleted, err := repo.Delete(context.Background(), "review-1", "EECS2030", "student@yorku.ca")
+	assert.NoError(t, err)
+	assert.True(t, deleted)
+
+	deleted, err = repo.Delete(context.Background(), "review-1", "EECS2030", "someone-else@yorku.ca")
+	assert.NoError(t, err)
+	assert.False(t, deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewRepository_SetPublishAt_ApprovesHeldReview(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewReviewRepository(mock)
+	now := time.Now()
+
+	mock.ExpectQuery("UPDATE reviews SET publish_at = \\$2, moderation = CASE WHEN \\$2::timestamp IS NULL THEN 'approved' ELSE moderation END").
+		WithArgs("review-1", dbtypes.NullTime{}).
+		WillReturnRows(pgxmock.NewRows(heldReviewRowColumns).
+			AddRow("review-1", "EECS2030", "a@yorku.ca", dbtypes.NullString{}, true, 3, 4, dbtypes.NullString{}, dbtypes.NullString{}, 2025, models.TermFall, nil, models.ModerationApproved, now, now, dbtypes.NullTime{}))
+
+	review, err := repo.SetPublishAt(context.Background(), "review-1", dbtypes.NullTime{})
+	assert.NoError(t, err)
+	assert.Equal(t, models.ReviewPublished, review.StatusAt(now))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
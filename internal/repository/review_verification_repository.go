@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+type reviewVerificationDB interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+// ReviewVerificationRepository keeps the email verifications of new reviews.
+// Only the hash of each link token is stored.
+type ReviewVerificationRepository struct {
+	db reviewVerificationDB
+}
+
+func NewReviewVerificationRepository(db reviewVerificationDB) *ReviewVerificationRepository {
+	return &ReviewVerificationRepository{db: db}
+}
+
+// Save records the token for an unverified review, replacing any earlier one.
+func (r *ReviewVerificationRepository) Save(ctx context.Context, reviewID, tokenHash, moderation string, expiresAt time.Time) error {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO review_verifications (review_id, token_hash, moderation, expires_at)
+		 VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (review_id) DO UPDATE
+		 SET token_hash = EXCLUDED.token_hash, moderation = EXCLUDED.moderation,
+		     expires_at = EXCLUDED.expires_at, created_at = NOW()`,
+		reviewID, tokenHash, moderation, expiresAt,
+	)
+	return err
+}
+
+// Reissue replaces the token of the unverified review matching courseCode,
+// reviewID and email, and reports whether there was one.
+func (r *ReviewVerificationRepository) Reissue(ctx context.Context, courseCode, reviewID, email, tokenHash string, expiresAt time.Time) (bool, error) {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	tag, err := r.db.Exec(ctx,
+		`UPDATE review_verifications v
+		 SET token_hash = $4, expires_at = $5, created_at = NOW()
+		 FROM reviews r
+		 WHERE v.review_id = r.id AND r.id = $2 AND r.course_code = $1
+		   AND r.email = $3 AND r.moderation = 'unverified'`,
+		courseCode, reviewID, email, tokenHash, expiresAt,
+	)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// Confirm uses up an unexpired token and gives its review the moderation
+// state saved with it. It returns the review's id, course and new moderation,
+// or nil if the token is unknown or expired.
+func (r *ReviewVerificationRepository) Confirm(ctx context.Context, tokenHash string) (*models.Review, error) {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	var review models.Review
+	err := r.db.QueryRow(ctx,
+		`WITH used AS (
+			DELETE FROM review_verifications
+			WHERE token_hash = $1 AND expires_at > NOW()
+			RETURNING review_id, moderation
+		)
+		UPDATE reviews r SET moderation = used.moderation, updated_at = NOW()
+		FROM used
+		WHERE r.id = used.review_id AND r.moderation = 'unverified'
+		RETURNING r.id, r.course_code, r.moderation, r.publish_at`,
+		tokenHash,
+	).Scan(&review.ID, &review.CourseCode, &review.Moderation, &review.PublishAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("confirm review: %w", err)
+	}
+	return &review, nil
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"yuplan/internal/dbtypes"
+	"yuplan/internal/models"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReviewVerificationRepository_Save(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewReviewVerificationRepository(mock)
+	expires := time.Now().Add(time.Hour)
+
+	mock.ExpectExec("INSERT INTO review_verifications (.+) ON CONFLICT \\(review_id\\) DO UPDATE").
+		WithArgs("rev-1", "hash", models.ModerationApproved, expires).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	assert.NoError(t, repo.Save(context.Background(), "rev-1", "hash", models.ModerationApproved, expires))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewVerificationRepository_Reissue(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewReviewVerificationRepository(mock)
+	expires := time.Now().Add(time.Hour)
+
+	mock.ExpectExec("UPDATE review_verifications v (.+) r.moderation = 'unverified'").
+		WithArgs("EECS2030", "rev-1", "student@my.yorku.ca", "hash", expires).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectExec("UPDATE review_verifications").
+		WithArgs("EECS2030", "rev-2", "student@my.yorku.ca", "hash", expires).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+
+	ok, err := repo.Reissue(context.Background(), "EECS2030", "rev-1", "student@my.yorku.ca", "hash", expires)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = repo.Reissue(context.Background(), "EECS2030", "rev-2", "student@my.yorku.ca", "hash", expires)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewVerificationRepository_Confirm(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewReviewVerificationRepository(mock)
+
+	mock.ExpectQuery("DELETE FROM review_verifications (.+) expires_at > NOW\\(\\) (.+) UPDATE reviews r SET moderation = used.moderation").
+		WithArgs("hash").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "course_code", "moderation", "publish_at"}).
+			AddRow("rev-1", "EECS2030", "pending", dbtypes.NullTime{}))
+	mock.ExpectQuery("DELETE FROM review_verifications").
+		WithArgs("stale").
+		WillReturnError(pgx.ErrNoRows)
+	mock.ExpectQuery("DELETE FROM review_verifications").
+		WithArgs("broken").
+		WillReturnError(errors.New("db down"))
+
+	review, err := repo.Confirm(context.Background(), "hash")
+	assert.NoError(t, err)
+	assert.Equal(t, "rev-1", review.ID)
+	assert.Equal(t, models.ModerationPending, review.Moderation)
+
+	review, err = repo.Confirm(context.Background(), "stale")
+	assert.NoError(t, err)
+	assert.Nil(t, review)
+
+	_, err = repo.Confirm(context.Background(), "broken")
+	assert.ErrorContains(t, err, "confirm review")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
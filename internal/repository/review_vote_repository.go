@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"yuplan/internal/models"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+// ErrOwnReviewVote is returned by Vote when the voter wrote the review.
+var ErrOwnReviewVote = errors.New("authors can't vote on their own review")
+
+type ReviewVoteRepositoryInterface interface {
+	Vote(ctx context.Context, reviewID, email string, helpful bool) (*models.ReviewVotes, error)
+}
+
+type reviewVoteDB interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+type ReviewVoteRepository struct {
+	db reviewVoteDB
+}
+
+func NewReviewVoteRepository(db reviewVoteDB) *ReviewVoteRepository {
+	return &ReviewVoteRepository{db: db}
+}
+
+// Vote records email's vote on a published review, replacing any earlier one,
+// and returns the review's tallies after it. It returns nil when there is no
+// such published review.
+func (r *ReviewVoteRepository) Vote(ctx context.Context, reviewID, email string, helpful bool) (*models.ReviewVotes, error) {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	var author string
+	err := r.db.QueryRow(ctx,
+		`SELECT email FROM reviews WHERE id = $1 AND `+publishedFilter,
+		reviewID,
+	).Scan(&author)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find voted review: %w", err)
+	}
+	if author == email {
+		return nil, ErrOwnReviewVote
+	}
+
+	_, err = r.db.Exec(ctx,
+		`INSERT INTO review_votes (review_id, email, helpful) VALUES ($1, $2, $3)
+		 ON CONFLICT (review_id, email) DO UPDATE SET helpful = EXCLUDED.helpful, updated_at = NOW()`,
+		reviewID, email, helpful,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("record review vote: %w", err)
+	}
+
+	votes := &models.ReviewVotes{ReviewID: reviewID}
+	err = r.db.QueryRow(ctx,
+		`SELECT COUNT(*) FILTER (WHERE helpful), COUNT(*) FILTER (WHERE NOT helpful)
+		 FROM review_votes WHERE review_id = $1`,
+		reviewID,
+	).Scan(&votes.HelpfulCount, &votes.NotHelpfulCount)
+	if err != nil {
+		return nil, fmt.Errorf("count review votes: %w", err)
+	}
+	return votes, nil
+}
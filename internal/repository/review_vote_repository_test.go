@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReviewVoteRepository_Vote(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewReviewVoteRepository(mock)
+
+	mock.ExpectQuery("SELECT email FROM reviews WHERE id = \\$1 AND \\(moderation = 'approved'").
+		WithArgs("review-1").
+		WillReturnRows(pgxmock.NewRows([]string{"email"}).AddRow("author@yorku.ca"))
+	mock.ExpectExec("INSERT INTO review_votes(.+)ON CONFLICT \\(review_id, email\\) DO UPDATE SET helpful = EXCLUDED.helpful").
+		WithArgs("review-1", "reader@yorku.ca", true).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FILTER \\(WHERE helpful\\), COUNT\\(\\*\\) FILTER \\(WHERE NOT helpful\\)").
+		WithArgs("review-1").
+		WillReturnRows(pgxmock.NewRows([]string{"helpful", "not_helpful"}).AddRow(3, 1))
+
+	votes, err := repo.Vote(context.Background(), "review-1", "reader@yorku.ca", true)
+	assert.NoError(t, err)
+	if assert.NotNil(t, votes) {
+		assert.Equal(t, "review-1", votes.ReviewID)
+		assert.Equal(t, 3, votes.HelpfulCount)
+		assert.Equal(t, 1, votes.NotHelpfulCount)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewVoteRepository_Vote_NoSuchReview(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewReviewVoteRepository(mock)
+
+	mock.ExpectQuery("SELECT email FROM reviews").
+		WithArgs("review-1").
+		WillReturnError(pgx.ErrNoRows)
+
+	votes, err := repo.Vote(context.Background(), "review-1", "reader@yorku.ca", false)
+	assert.NoError(t, err)
+	assert.Nil(t, votes)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewVoteRepository_Vote_OwnReview(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewReviewVoteRepository(mock)
+
+	mock.ExpectQuery("SELECT email FROM reviews").
+		WithArgs("review-1").
+		WillReturnRows(pgxmock.NewRows([]string{"email"}).AddRow("author@yorku.ca"))
+
+	votes, err := repo.Vote(context.Background(), "review-1", "author@yorku.ca", true)
+	assert.ErrorIs(t, err, ErrOwnReviewVote)
+	assert.Nil(t, votes)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"yuplan/internal/id"
+	"yuplan/internal/models"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+// ErrDuplicateScheduleName is returned by Create and Update when the email
+// already has another saved schedule with that name.
+var ErrDuplicateScheduleName = errors.New("a saved schedule with this name already exists")
+
+type SavedScheduleRepositoryInterface interface {
+	List(ctx context.Context, email string) ([]models.SavedSchedule, error)
+	Get(ctx context.Context, id, email string) (*models.SavedSchedule, error)
+	Create(ctx context.Context, schedule *models.SavedSchedule) error
+	Update(ctx context.Context, schedule *models.SavedSchedule) (bool, error)
+	Delete(ctx context.Context, id, email string) (bool, error)
+	SetFeedToken(ctx context.Context, id, email, tokenHash string) (bool, error)
+	GetByFeedToken(ctx context.Context, id, tokenHash string) (*models.SavedSchedule, error)
+}
+
+type savedScheduleDB interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+type SavedScheduleRepository struct {
+	db savedScheduleDB
+}
+
+func NewSavedScheduleRepository(db savedScheduleDB) *SavedScheduleRepository {
+	return &SavedScheduleRepository{db: db}
+}
+
+const savedScheduleColumns = `id, email, name, section_ids::text[], activity_ids::text[], feed_token_hash IS NOT NULL, created_at, updated_at`
+
+// List returns an email's saved schedules by name.
+func (r *SavedScheduleRepository) List(ctx context.Context, email string) ([]models.SavedSchedule, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	rows, err := r.db.Query(ctx,
+		`SELECT `+savedScheduleColumns+`
+		 FROM saved_schedules
+		 WHERE email = $1
+		 ORDER BY name`,
+		email,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query saved schedules: %w", err)
+	}
+	defer rows.Close()
+
+	schedules := []models.SavedSchedule{}
+	for rows.Next() {
+		var s models.SavedSchedule
+		if err := scanSavedSchedule(rows, &s); err != nil {
+			return nil, fmt.Errorf("scan saved schedule: %w", err)
+		}
+		schedules = append(schedules, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate saved schedules: %w", err)
+	}
+	return schedules, nil
+}
+
+// Get returns one of email's schedules, or nil when email has no such
+// schedule.
+func (r *SavedScheduleRepository) Get(ctx context.Context, id, email string) (*models.SavedSchedule, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	var s models.SavedSchedule
+	err := scanSavedSchedule(r.db.QueryRow(ctx,
+		`SELECT `+savedScheduleColumns+` FROM saved_schedules WHERE id = $1 AND email = $2`,
+		id, email,
+	), &s)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get saved schedule: %w", err)
+	}
+	return &s, nil
+}
+
+// Create saves a new schedule without a feed token, filling in its id and
+// timestamps.
+func (r *SavedScheduleRepository) Create(ctx context.Context, schedule *models.SavedSchedule) error {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	schedule.ID = id.New()
+	err := r.db.QueryRow(ctx,
+		`INSERT INTO saved_schedules (id, email, name, section_ids, activity_ids)
+		 VALUES ($1, $2, $3, $4::uuid[], $5::uuid[])
+		 RETURNING created_at, updated_at`,
+		schedule.ID, schedule.Email, schedule.Name, schedule.SectionIDs, schedule.ActivityIDs,
+	).Scan(&schedule.CreatedAt, &schedule.UpdatedAt)
+	if isDuplicateScheduleName(err) {
+		schedule.ID = ""
+		return ErrDuplicateScheduleName
+	}
+	if err != nil {
+		return fmt.Errorf("insert saved schedule: %w", err)
+	}
+	return nil
+}
+
+// Update replaces the name, sections and activities of the schedule with
+// schedule.ID, keeping its feed token, and fills in the rest. It reports
+// false when schedule.Email has no such schedule.
+func (r *SavedScheduleRepository) Update(ctx context.Context, schedule *models.SavedSchedule) (bool, error) {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	err := r.db.QueryRow(ctx,
+		`UPDATE saved_schedules
+		 SET name = $3, section_ids = $4::uuid[], activity_ids = $5::uuid[], updated_at = NOW()
+		 WHERE id = $1 AND email = $2
+		 RETURNING feed_token_hash IS NOT NULL, created_at, updated_at`,
+		schedule.ID, schedule.Email, schedule.Name, schedule.SectionIDs, schedule.ActivityIDs,
+	).Scan(&schedule.Subscribed, &schedule.CreatedAt, &schedule.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if isDuplicateScheduleName(err) {
+		return false, ErrDuplicateScheduleName
+	}
+	if err != nil {
+		return false, fmt.Errorf("update saved schedule: %w", err)
+	}
+	return true, nil
+}
+
+// Delete removes one of email's schedules, and with it its feed, and reports
+// whether it existed.
+func (r *SavedScheduleRepository) Delete(ctx context.Context, id, email string) (bool, error) {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	tag, err := r.db.Exec(ctx, `DELETE FROM saved_schedules WHERE id = $1 AND email = $2`, id, email)
+	if err != nil {
+		return false, fmt.Errorf("delete saved schedule: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// SetFeedToken replaces the feed token hash of one of email's schedules,
+// revoking the previous token; an empty hash revokes without issuing another.
+// It reports false when email has no such schedule.
+func (r *SavedScheduleRepository) SetFeedToken(ctx context.Context, id, email, tokenHash string) (bool, error) {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	tag, err := r.db.Exec(ctx,
+		`UPDATE saved_schedules SET feed_token_hash = NULLIF($3, '') WHERE id = $1 AND email = $2`,
+		id, email, tokenHash,
+	)
+	if err != nil {
+		return false, fmt.Errorf("set schedule feed token: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// GetByFeedToken returns the schedule with the given id if tokenHash is its
+// current feed token, or nil.
+func (r *SavedScheduleRepository) GetByFeedToken(ctx context.Context, id, tokenHash string) (*models.SavedSchedule, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	var s models.SavedSchedule
+	err := scanSavedSchedule(r.db.QueryRow(ctx,
+		`SELECT `+savedScheduleColumns+` FROM saved_schedules WHERE id = $1 AND feed_token_hash = $2`,
+		id, tokenHash,
+	), &s)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get saved schedule by feed token: %w", err)
+	}
+	return &s, nil
+}
+
+func scanSavedSchedule(row pgx.Row, s *models.SavedSchedule) error {
+	return row.Scan(&s.ID, &s.Email, &s.Name, &s.SectionIDs, &s.ActivityIDs, &s.Subscribed, &s.CreatedAt, &s.UpdatedAt)
+}
+
+func isDuplicateScheduleName(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "saved_schedules_email_name_key"
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var savedScheduleRowColumns = []string{"id", "email", "name", "section_ids", "activity_ids", "subscribed", "created_at", "updated_at"}
+
+func TestSavedScheduleRepository_List(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewSavedScheduleRepository(mock)
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT (.+) feed_token_hash IS NOT NULL(.+) FROM saved_schedules WHERE email = \\$1 ORDER BY name").
+		WithArgs("a@yorku.ca").
+		WillReturnRows(pgxmock.NewRows(savedScheduleRowColumns).
+			AddRow("s-1", "a@yorku.ca", "Fall plan", []string{"sec-1"}, []string{"lab-1"}, true, now, now))
+
+	schedules, err := repo.List(context.Background(), "a@yorku.ca")
+	assert.NoError(t, err)
+	if assert.Len(t, schedules, 1) {
+		assert.Equal(t, []string{"sec-1"}, schedules[0].SectionIDs)
+		assert.Equal(t, []string{"lab-1"}, schedules[0].ActivityIDs)
+		assert.True(t, schedules[0].Subscribed)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSavedScheduleRepository_Get(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewSavedScheduleRepository(mock)
+	now := time.Now()
+
+	mock.ExpectQuery("FROM saved_schedules WHERE id = \\$1 AND email = \\$2").
+		WithArgs("s-1", "a@yorku.ca").
+		WillReturnRows(pgxmock.NewRows(savedScheduleRowColumns).
+			AddRow("s-1", "a@yorku.ca", "Fall plan", []string{"sec-1"}, []string{"lab-1"}, false, now, now))
+	mock.ExpectQuery("FROM saved_schedules WHERE id = \\$1 AND email = \\$2").
+		WithArgs("s-1", "b@yorku.ca").
+		WillReturnError(pgx.ErrNoRows)
+
+	schedule, err := repo.Get(context.Background(), "s-1", "a@yorku.ca")
+	assert.NoError(t, err)
+	if assert.NotNil(t, schedule) {
+		assert.Equal(t, []string{"lab-1"}, schedule.ActivityIDs)
+	}
+
+	schedule, err = repo.Get(context.Background(), "s-1", "b@yorku.ca")
+	assert.NoError(t, err)
+	assert.Nil(t, schedule, "only the owner can read it")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSavedScheduleRepository_Create(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewSavedScheduleRepository(mock)
+	now := time.Now()
+	schedule := &models.SavedSchedule{Email: "a@yorku.ca", Name: "Fall plan", SectionIDs: []string{"sec-1"}, ActivityIDs: []string{}}
+
+	mock.ExpectQuery("INSERT INTO saved_schedules").
+		WithArgs(pgxmock.AnyArg(), "a@yorku.ca", "Fall plan", []string{"sec-1"}, []string{}).
+		WillReturnRows(pgxmock.NewRows([]string{"created_at", "updated_at"}).AddRow(now, now))
+	mock.ExpectQuery("INSERT INTO saved_schedules").
+		WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "saved_schedules_email_name_key"})
+
+	assert.NoError(t, repo.Create(context.Background(), schedule))
+	assert.NotEmpty(t, schedule.ID)
+
+	dup := &models.SavedSchedule{Email: "a@yorku.ca", Name: "Fall plan"}
+	assert.ErrorIs(t, repo.Create(context.Background(), dup), ErrDuplicateScheduleName)
+	assert.Empty(t, dup.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSavedScheduleRepository_Update(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewSavedScheduleRepository(mock)
+	now := time.Now()
+	schedule := &models.SavedSchedule{ID: "s-1", Email: "a@yorku.ca", Name: "Fall plan", SectionIDs: []string{"sec-2"}, ActivityIDs: []string{}}
+
+	mock.ExpectQuery("UPDATE saved_schedules SET name = \\$3, section_ids = \\$4::uuid\\[\\](.+)WHERE id = \\$1 AND email = \\$2").
+		WithArgs("s-1", "a@yorku.ca", "Fall plan", []string{"sec-2"}, []string{}).
+		WillReturnRows(pgxmock.NewRows([]string{"subscribed", "created_at", "updated_at"}).AddRow(true, now, now))
+	mock.ExpectQuery("UPDATE saved_schedules").
+		WillReturnError(pgx.ErrNoRows)
+
+	updated, err := repo.Update(context.Background(), schedule)
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	assert.True(t, schedule.Subscribed, "the feed token survives an update")
+
+	updated, err = repo.Update(context.Background(), schedule)
+	assert.NoError(t, err)
+	assert.False(t, updated)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSavedScheduleRepository_FeedToken(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewSavedScheduleRepository(mock)
+	now := time.Now()
+
+	mock.ExpectExec("UPDATE saved_schedules SET feed_token_hash = NULLIF\\(\\$3, ''\\) WHERE id = \\$1 AND email = \\$2").
+		WithArgs("s-1", "a@yorku.ca", "hash").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectExec("UPDATE saved_schedules SET feed_token_hash").
+		WithArgs("s-1", "b@yorku.ca", "").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+	mock.ExpectQuery("FROM saved_schedules WHERE id = \\$1 AND feed_token_hash = \\$2").
+		WithArgs("s-1", "hash").
+		WillReturnRows(pgxmock.NewRows(savedScheduleRowColumns).
+			AddRow("s-1", "a@yorku.ca", "Fall plan", []string{"sec-1"}, []string{}, true, now, now))
+	mock.ExpectQuery("FROM saved_schedules WHERE id = \\$1 AND feed_token_hash = \\$2").
+		WithArgs("s-1", "revoked").
+		WillReturnError(pgx.ErrNoRows)
+
+	issued, err := repo.SetFeedToken(context.Background(), "s-1", "a@yorku.ca", "hash")
+	assert.NoError(t, err)
+	assert.True(t, issued)
+
+	revoked, err := repo.SetFeedToken(context.Background(), "s-1", "b@yorku.ca", "")
+	assert.NoError(t, err)
+	assert.False(t, revoked, "only the owner can revoke")
+
+	schedule, err := repo.GetByFeedToken(context.Background(), "s-1", "hash")
+	assert.NoError(t, err)
+	if assert.NotNil(t, schedule) {
+		assert.Equal(t, []string{"sec-1"}, schedule.SectionIDs)
+	}
+
+	schedule, err = repo.GetByFeedToken(context.Background(), "s-1", "revoked")
+	assert.NoError(t, err)
+	assert.Nil(t, schedule)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+type SearchStatsRepositoryInterface interface {
+	Increment(ctx context.Context, day time.Time, stats []models.SearchStat) error
+	Top(ctx context.Context, since time.Time, limit int) ([]models.SearchStat, error)
+	TopZeroResult(ctx context.Context, since time.Time, limit int) ([]models.SearchStat, error)
+}
+
+type searchStatsDB interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+type SearchStatsRepository struct {
+	db searchStatsDB
+}
+
+func NewSearchStatsRepository(db searchStatsDB) *SearchStatsRepository {
+	return &SearchStatsRepository{db: db}
+}
+
+// Increment upserts a batch of counts for one day in a single round trip.
+func (r *SearchStatsRepository) Increment(ctx context.Context, day time.Time, stats []models.SearchStat) error {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	if len(stats) == 0 {
+		return nil
+	}
+
+	queries := make([]string, len(stats))
+	searches := make([]int32, len(stats))
+	zeroResults := make([]int32, len(stats))
+	for i, s := range stats {
+		queries[i] = s.Query
+		searches[i] = int32(s.Searches)
+		zeroResults[i] = int32(s.ZeroResults)
+	}
+
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO search_stats (query, day, searches, zero_results)
+		 SELECT q, $1::date, s, z FROM unnest($2::text[], $3::int[], $4::int[]) AS t(q, s, z)
+		 ON CONFLICT (query, day) DO UPDATE
+		 SET searches = search_stats.searches + EXCLUDED.searches,
+		     zero_results = search_stats.zero_results + EXCLUDED.zero_results`,
+		day, queries, searches, zeroResults,
+	)
+	if err != nil {
+		return fmt.Errorf("increment search stats: %w", err)
+	}
+	return nil
+}
+
+// Top returns the most searched queries since the given day.
+func (r *SearchStatsRepository) Top(ctx context.Context, since time.Time, limit int) ([]models.SearchStat, error) {
+	ctx, cancel := withDeadline(ctx, opAggregate)
+	defer cancel()
+
+	return r.query(ctx,
+		`SELECT query, SUM(searches), SUM(zero_results)
+		 FROM search_stats
+		 WHERE day >= $1::date
+		 GROUP BY query
+		 ORDER BY SUM(searches) DESC, query
+		 LIMIT $2`,
+		since, limit,
+	)
+}
+
+// TopZeroResult returns the queries that most often found nothing since the given day.
+func (r *SearchStatsRepository) TopZeroResult(ctx context.Context, since time.Time, limit int) ([]models.SearchStat, error) {
+	ctx, cancel := withDeadline(ctx, opAggregate)
+	defer cancel()
+
+	return r.query(ctx,
+		`SELECT query, SUM(searches), SUM(zero_results)
+		 FROM search_stats
+		 WHERE day >= $1::date
+		 GROUP BY query
+		 HAVING SUM(zero_results) > 0
+		 ORDER BY SUM(zero_results) DESC, query
+		 LIMIT $2`,
+		since, limit,
+	)
+}
+
+func (r *SearchStatsRepository) query(ctx context.Context, sql string, args ...any) ([]models.SearchStat, error) {
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query search stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := make([]models.SearchStat, 0)
+	for rows.Next() {
+		var s models.SearchStat
+		if err := rows.Scan(&s.Query, &s.Searches, &s.ZeroResults); err != nil {
+			return nil, fmt.Errorf("scan search stat: %w", err)
+		}
+		stats = append(stats, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate search stats: %w", err)
+	}
+
+	return stats, nil
+}
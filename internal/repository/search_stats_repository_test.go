@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchStatsRepository_Increment(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewSearchStatsRepository(mock)
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec("INSERT INTO search_stats").
+		WithArgs(day, []string{"eecs", "zzz"}, []int32{3, 1}, []int32{0, 1}).
+		WillReturnResult(pgxmock.NewResult("INSERT", 2))
+
+	err = repo.Increment(context.Background(), day, []models.SearchStat{
+		{Query: "eecs", Searches: 3},
+		{Query: "zzz", Searches: 1, ZeroResults: 1},
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSearchStatsRepository_IncrementEmptyIsNoop(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewSearchStatsRepository(mock)
+
+	assert.NoError(t, repo.Increment(context.Background(), time.Now(), nil))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSearchStatsRepository_IncrementError(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewSearchStatsRepository(mock)
+	mock.ExpectExec("INSERT INTO search_stats").WillReturnError(errors.New("db down"))
+
+	err = repo.Increment(context.Background(), time.Now(), []models.SearchStat{{Query: "eecs", Searches: 1}})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "increment search stats")
+}
+
+func TestSearchStatsRepository_Top(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewSearchStatsRepository(mock)
+	since := time.Now().AddDate(0, 0, -30)
+
+	mock.ExpectQuery("FROM search_stats\\s+WHERE day >= \\$1::date\\s+GROUP BY query\\s+ORDER BY SUM\\(searches\\) DESC").
+		WithArgs(since, 10).
+		WillReturnRows(pgxmock.NewRows([]string{"query", "searches", "zero_results"}).
+			AddRow("eecs", 40, 0).
+			AddRow("math", 12, 2))
+
+	stats, err := repo.Top(context.Background(), since, 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.SearchStat{{Query: "eecs", Searches: 40}, {Query: "math", Searches: 12, ZeroResults: 2}}, stats)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSearchStatsRepository_TopZeroResult(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewSearchStatsRepository(mock)
+	since := time.Now().AddDate(0, 0, -30)
+
+	mock.ExpectQuery("HAVING SUM\\(zero_results\\) > 0").
+		WithArgs(since, 5).
+		WillReturnRows(pgxmock.NewRows([]string{"query", "searches", "zero_results"}).
+			AddRow("nurs", 9, 9))
+
+	stats, err := repo.TopZeroResult(context.Background(), since, 5)
+
+	assert.NoError(t, err)
+	assert.Len(t, stats, 1)
+	assert.Equal(t, "nurs", stats[0].Query)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
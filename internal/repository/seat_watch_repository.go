@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"yuplan/internal/id"
+	"yuplan/internal/models"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+var (
+	// ErrDuplicateSeatWatch is returned by Create when the email already watches the section.
+	ErrDuplicateSeatWatch = errors.New("already watching this section")
+	// ErrTooManySeatWatches is returned by Create when the email watches models.MaxSeatWatches sections.
+	ErrTooManySeatWatches = errors.New("too many seat watches")
+)
+
+type SeatWatchRepositoryInterface interface {
+	Create(ctx context.Context, watch *models.SeatWatch) (bool, error)
+	List(ctx context.Context, email string) ([]models.SeatWatch, error)
+	Delete(ctx context.Context, id, email string) (bool, error)
+}
+
+type seatWatchDB interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+type SeatWatchRepository struct {
+	db seatWatchDB
+}
+
+func NewSeatWatchRepository(db seatWatchDB) *SeatWatchRepository {
+	return &SeatWatchRepository{db: db}
+}
+
+// Create stores a watch, filling in its ID and CreatedAt. It reports false
+// when there's no such section.
+func (r *SeatWatchRepository) Create(ctx context.Context, watch *models.SeatWatch) (bool, error) {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	watch.ID = id.New()
+	var sectionExists, duplicate, full bool
+	var createdAt *time.Time
+	err := r.db.QueryRow(ctx,
+		`WITH existing AS (
+		     SELECT COUNT(*) AS watches, COUNT(*) FILTER (WHERE section_id = $3) AS same_section
+		     FROM seat_watches WHERE email = $2
+		 ),
+		 inserted AS (
+		     INSERT INTO seat_watches (id, email, section_id)
+		     SELECT $1, $2, s.id
+		     FROM sections s, existing e
+		     WHERE s.id = $3 AND e.same_section = 0 AND e.watches < $4
+		     ON CONFLICT (email, section_id) DO NOTHING
+		     RETURNING created_at
+		 )
+		 SELECT EXISTS (SELECT 1 FROM sections WHERE id = $3),
+		        (SELECT same_section > 0 FROM existing),
+		        (SELECT watches >= $4 FROM existing),
+		        (SELECT created_at FROM inserted)`,
+		watch.ID, watch.Email, watch.SectionID, models.MaxSeatWatches,
+	).Scan(&sectionExists, &duplicate, &full, &createdAt)
+	if err != nil {
+		return false, fmt.Errorf("insert seat watch: %w", err)
+	}
+	switch {
+	case !sectionExists:
+		return false, nil
+	case duplicate:
+		return true, ErrDuplicateSeatWatch
+	case full:
+		return true, ErrTooManySeatWatches
+	case createdAt == nil:
+		// Lost a race with the same watch being created
+		return true, ErrDuplicateSeatWatch
+	}
+	watch.CreatedAt = *createdAt
+	return true, nil
+}
+
+// List returns an email's watches, newest first, with the section each is for.
+func (r *SeatWatchRepository) List(ctx context.Context, email string) ([]models.SeatWatch, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	rows, err := r.db.Query(ctx,
+		`SELECT w.id, w.email, w.section_id, c.code, s.letter, COALESCE(s.term_id, ''), w.created_at, w.notified_at
+		 FROM seat_watches w
+		 JOIN sections s ON s.id = w.section_id
+		 JOIN courses c ON c.id = s.course_id
+		 WHERE w.email = $1
+		 ORDER BY w.created_at DESC, w.id`,
+		email,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query seat watches: %w", err)
+	}
+	defer rows.Close()
+
+	watches := []models.SeatWatch{}
+	for rows.Next() {
+		var w models.SeatWatch
+		if err := rows.Scan(&w.ID, &w.Email, &w.SectionID, &w.CourseCode, &w.Letter, &w.TermID, &w.CreatedAt, &w.NotifiedAt); err != nil {
+			return nil, fmt.Errorf("scan seat watch: %w", err)
+		}
+		watches = append(watches, w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate seat watches: %w", err)
+	}
+	return watches, nil
+}
+
+// Delete removes one of an email's watches, reporting false if it had no such watch.
+func (r *SeatWatchRepository) Delete(ctx context.Context, id, email string) (bool, error) {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	tag, err := r.db.Exec(ctx, `DELETE FROM seat_watches WHERE id = $1 AND email = $2`, id, email)
+	if err != nil {
+		return false, fmt.Errorf("delete seat watch: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// Rearm clears notified_at on watches whose section is full again, so its
+// next opening is mailed too, and returns how many it cleared.
+func (r *SeatWatchRepository) Rearm(ctx context.Context) (int, error) {
+	ctx, cancel := withDeadline(ctx, opAggregate)
+	defer cancel()
+
+	tag, err := r.db.Exec(ctx,
+		`UPDATE seat_watches w
+		 SET notified_at = NULL
+		 FROM sections s
+		 WHERE s.id = w.section_id AND w.notified_at IS NOT NULL AND s.enrolled >= s.capacity`,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("rearm seat watches: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// ListOpenings returns the watches not yet notified whose section has a free seat.
+func (r *SeatWatchRepository) ListOpenings(ctx context.Context) ([]models.SeatOpening, error) {
+	ctx, cancel := withDeadline(ctx, opAggregate)
+	defer cancel()
+
+	rows, err := r.db.Query(ctx,
+		`SELECT w.id, w.email, c.code, c.name, s.letter, COALESCE(s.term_id, ''), s.capacity - s.enrolled
+		 FROM seat_watches w
+		 JOIN sections s ON s.id = w.section_id
+		 JOIN courses c ON c.id = s.course_id
+		 WHERE w.notified_at IS NULL AND s.capacity > s.enrolled
+		 ORDER BY w.created_at, w.id`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query seat openings: %w", err)
+	}
+	defer rows.Close()
+
+	var openings []models.SeatOpening
+	for rows.Next() {
+		var o models.SeatOpening
+		if err := rows.Scan(&o.WatchID, &o.Email, &o.CourseCode, &o.CourseName, &o.Letter, &o.TermID, &o.SeatsRemaining); err != nil {
+			return nil, fmt.Errorf("scan seat opening: %w", err)
+		}
+		openings = append(openings, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate seat openings: %w", err)
+	}
+	return openings, nil
+}
+
+// MarkNotified records that a watch's opening was mailed.
+func (r *SeatWatchRepository) MarkNotified(ctx context.Context, watchID string) error {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	if _, err := r.db.Exec(ctx, `UPDATE seat_watches SET notified_at = NOW() WHERE id = $1`, watchID); err != nil {
+		return fmt.Errorf("mark seat watch notified: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeatWatchRepository_Create(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name        string
+		row         []any
+		wantFound   bool
+		wantErr     error
+		wantCreated bool
+	}{
+		{"created", []any{true, false, false, &now}, true, nil, true},
+		{"no such section", []any{false, false, false, nil}, false, nil, false},
+		{"duplicate", []any{true, true, false, nil}, true, ErrDuplicateSeatWatch, false},
+		{"too many", []any{true, false, true, nil}, true, ErrTooManySeatWatches, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			assert.NoError(t, err)
+			defer mock.Close()
+
+			repo := NewSeatWatchRepository(mock)
+			watch := &models.SeatWatch{Email: "student@my.yorku.ca", SectionID: "sec-1"}
+
+			mock.ExpectQuery("INSERT INTO seat_watches (.+) ON CONFLICT \\(email, section_id\\) DO NOTHING").
+				WithArgs(pgxmock.AnyArg(), "student@my.yorku.ca", "sec-1", models.MaxSeatWatches).
+				WillReturnRows(pgxmock.NewRows([]string{"exists", "duplicate", "full", "created_at"}).AddRow(tt.row...))
+
+			found, err := repo.Create(context.Background(), watch)
+			assert.Equal(t, tt.wantFound, found)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			if tt.wantCreated {
+				assert.NotEmpty(t, watch.ID)
+				assert.Equal(t, now, watch.CreatedAt)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSeatWatchRepository_List(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewSeatWatchRepository(mock)
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT w.id, (.+) FROM seat_watches w (.+) WHERE w.email = \\$1").
+		WithArgs("student@my.yorku.ca").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "email", "section_id", "code", "letter", "term_id", "created_at", "notified_at"}).
+			AddRow("watch-1", "student@my.yorku.ca", "sec-1", "EECS2030", "A", "FW2025", now, nil))
+
+	watches, err := repo.List(context.Background(), "student@my.yorku.ca")
+	assert.NoError(t, err)
+	assert.Len(t, watches, 1)
+	assert.Equal(t, "EECS2030", watches[0].CourseCode)
+	assert.False(t, watches[0].NotifiedAt.Valid)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSeatWatchRepository_Openings(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewSeatWatchRepository(mock)
+
+	mock.ExpectExec("UPDATE seat_watches w SET notified_at = NULL (.+) s.enrolled >= s.capacity").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 2))
+	mock.ExpectQuery("FROM seat_watches w (.+) WHERE w.notified_at IS NULL AND s.capacity > s.enrolled").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "email", "code", "name", "letter", "term_id", "remaining"}).
+			AddRow("watch-1", "student@my.yorku.ca", "EECS2030", "Advanced OOP", "A", "FW2025", 3))
+	mock.ExpectExec("UPDATE seat_watches SET notified_at = NOW\\(\\) WHERE id = \\$1").
+		WithArgs("watch-1").
+		WillReturnError(errors.New("db down"))
+
+	rearmed, err := repo.Rearm(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, rearmed)
+
+	openings, err := repo.ListOpenings(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []models.SeatOpening{{WatchID: "watch-1", Email: "student@my.yorku.ca", CourseCode: "EECS2030", CourseName: "Advanced OOP", Letter: "A", TermID: "FW2025", SeatsRemaining: 3}}, openings)
+
+	assert.ErrorContains(t, repo.MarkNotified(context.Background(), "watch-1"), "mark seat watch notified")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSeatWatchRepository_Delete(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewSeatWatchRepository(mock)
+
+	mock.ExpectExec("DELETE FROM seat_watches WHERE id = \\$1 AND email = \\$2").
+		WithArgs("watch-1", "student@my.yorku.ca").
+		WillReturnResult(pgxmock.NewResult("DELETE", 1))
+
+	removed, err := repo.Delete(context.Background(), "watch-1", "student@my.yorku.ca")
+	assert.NoError(t, err)
+	assert.True(t, removed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -11,6 +11,8 @@ import (
 type SectionActivityRepositoryInterface interface {
 	GetBySectionID(ctx context.Context, sectionID string) ([]models.SectionActivity, error)
 	GetBySectionIDAndType(ctx context.Context, sectionID string, courseType string) ([]models.SectionActivity, error)
+	ListByIDs(ctx context.Context, ids []string) ([]models.TeachingActivity, error)
+	ListForCalendar(ctx context.Context, sectionIDs, activityIDs []string) ([]models.CalendarActivity, error)
 }
 
 type sectionActivityDB interface {
@@ -26,6 +28,9 @@ func NewSectionActivityRepository(db sectionActivityDB) *SectionActivityReposito
 }
 
 func (r *SectionActivityRepository) GetBySectionID(ctx context.Context, sectionID string) ([]models.SectionActivity, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
 	rows, err := r.db.Query(
 		ctx,
 		`SELECT id, course_type, section_id, catalog_number, times, created_at, updated_at
@@ -45,6 +50,7 @@ func (r *SectionActivityRepository) GetBySectionID(ctx context.Context, sectionI
 		if err := rows.Scan(&activity.ID, &activity.CourseType, &activity.SectionID, &activity.CatalogNumber, &activity.Times, &activity.CreatedAt, &activity.UpdatedAt); err != nil {
 			return nil, fmt.Errorf("scan section_activity: %w", err)
 		}
+		activity.Delivery = models.DeliveryOf(activity.Times)
 		activities = append(activities, activity)
 	}
 	if err := rows.Err(); err != nil {
@@ -55,6 +61,9 @@ func (r *SectionActivityRepository) GetBySectionID(ctx context.Context, sectionI
 }
 
 func (r *SectionActivityRepository) GetBySectionIDAndType(ctx context.Context, sectionID string, courseType string) ([]models.SectionActivity, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
 	rows, err := r.db.Query(
 		ctx,
 		`SELECT id, course_type, section_id, catalog_number, times, created_at, updated_at
@@ -75,6 +84,7 @@ func (r *SectionActivityRepository) GetBySectionIDAndType(ctx context.Context, s
 		if err := rows.Scan(&activity.ID, &activity.CourseType, &activity.SectionID, &activity.CatalogNumber, &activity.Times, &activity.CreatedAt, &activity.UpdatedAt); err != nil {
 			return nil, fmt.Errorf("scan section_activity: %w", err)
 		}
+		activity.Delivery = models.DeliveryOf(activity.Times)
 		activities = append(activities, activity)
 	}
 	if err := rows.Err(); err != nil {
@@ -83,3 +93,77 @@ func (r *SectionActivityRepository) GetBySectionIDAndType(ctx context.Context, s
 
 	return activities, nil
 }
+
+// ListByIDs returns the activities with the given ids with the course code,
+// term and section letter each belongs to. Unknown ids are skipped.
+func (r *SectionActivityRepository) ListByIDs(ctx context.Context, ids []string) ([]models.TeachingActivity, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	rows, err := r.db.Query(
+		ctx,
+		`SELECT c.code, COALESCE(c.term, ''), s.letter, sa.course_type, sa.times
+		 FROM section_activities sa
+		 INNER JOIN sections s ON s.id = sa.section_id
+		 INNER JOIN courses c ON c.id = s.course_id
+		 WHERE sa.id = ANY($1::uuid[])
+		 ORDER BY c.code, s.letter, sa.course_type`,
+		ids,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query section_activities by id: %w", err)
+	}
+	defer rows.Close()
+
+	activities := make([]models.TeachingActivity, 0, len(ids))
+	for rows.Next() {
+		var a models.TeachingActivity
+		if err := rows.Scan(&a.CourseCode, &a.Term, &a.Section, &a.Type, &a.Times); err != nil {
+			return nil, fmt.Errorf("scan section_activity: %w", err)
+		}
+		activities = append(activities, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate section_activities: %w", err)
+	}
+	return activities, nil
+}
+
+// ListForCalendar returns the activities to put on a calendar with the dates
+// of their section's session: each of activityIDs, and every activity of
+// sectionIDs except labs and tutorials, of which a student attends only the
+// one they picked.
+func (r *SectionActivityRepository) ListForCalendar(ctx context.Context, sectionIDs, activityIDs []string) ([]models.CalendarActivity, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	rows, err := r.db.Query(
+		ctx,
+		`SELECT sa.id, c.code, c.name, COALESCE(c.term, ''), s.letter, sa.course_type, sa.times, t.starts_on, t.ends_on
+		 FROM section_activities sa
+		 INNER JOIN sections s ON s.id = sa.section_id
+		 INNER JOIN courses c ON c.id = s.course_id
+		 LEFT JOIN terms t ON t.id = s.term_id
+		 WHERE sa.id = ANY($2::uuid[])
+		    OR (sa.section_id = ANY($1::uuid[]) AND sa.course_type NOT IN ('LAB', 'TUTR'))
+		 ORDER BY c.code, s.letter, sa.course_type`,
+		sectionIDs, activityIDs,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query section_activities for calendar: %w", err)
+	}
+	defer rows.Close()
+
+	activities := make([]models.CalendarActivity, 0)
+	for rows.Next() {
+		var a models.CalendarActivity
+		if err := rows.Scan(&a.ID, &a.CourseCode, &a.CourseName, &a.Term, &a.Section, &a.Type, &a.Times, &a.SessionStart, &a.SessionEnd); err != nil {
+			return nil, fmt.Errorf("scan section_activity: %w", err)
+		}
+		activities = append(activities, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate section_activities: %w", err)
+	}
+	return activities, nil
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+	"yuplan/internal/dbtypes"
+	"yuplan/internal/models"
+
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSectionActivityRepository_GetBySectionID_SetsDelivery(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewSectionActivityRepository(mock)
+	now := time.Now()
+	lectureTimes := `[{"day": "M", "time": "18:00", "duration": "110", "campus": "Keele", "room": "SSB E118"}]`
+	placeholderTimes := `[{"day": "", "time": "0:00", "duration": "0", "campus": "Keele", "room": ""}]`
+
+	mock.ExpectQuery("FROM section_activities\\s+WHERE section_id = \\$1").
+		WithArgs("section-1").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "course_type", "section_id", "catalog_number", "times", "created_at", "updated_at"}).
+			AddRow("act-1", "LECT", "section-1", "", &lectureTimes, now, now).
+			AddRow("act-2", "ONLN", "section-1", "A01", &placeholderTimes, now, now).
+			AddRow("act-3", "ONLN", "section-1", "A02", nil, now, now))
+
+	activities, err := repo.GetBySectionID(context.Background(), "section-1")
+
+	assert.NoError(t, err)
+	assert.Len(t, activities, 3)
+	assert.Equal(t, models.DeliveryScheduled, activities[0].Delivery)
+	assert.Equal(t, models.DeliveryAsynchronous, activities[1].Delivery)
+	assert.Equal(t, models.DeliveryAsynchronous, activities[2].Delivery)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSectionActivityRepository_ListByIDs(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewSectionActivityRepository(mock)
+	times := `[{"day": "M", "time": "18:00", "duration": "110", "campus": "Keele", "room": "SSB E118"}]`
+	ids := []string{"act-1", "act-2"}
+
+	mock.ExpectQuery("FROM section_activities sa(.+)WHERE sa.id = ANY\\(\\$1::uuid\\[\\]\\)").
+		WithArgs(ids).
+		WillReturnRows(pgxmock.NewRows([]string{"code", "term", "letter", "course_type", "times"}).
+			AddRow("EECS2030", models.TermFall, "A", models.ActivityLecture, &times).
+			AddRow("EECS2030", models.TermFall, "A", models.ActivityLab, nil))
+
+	activities, err := repo.ListByIDs(context.Background(), ids)
+
+	assert.NoError(t, err)
+	assert.Len(t, activities, 2)
+	assert.Equal(t, "EECS2030", activities[0].CourseCode)
+	assert.Equal(t, times, activities[0].Times.String)
+	assert.False(t, activities[1].Times.Valid)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSectionActivityRepository_ListForCalendar(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewSectionActivityRepository(mock)
+	times := `[{"day": "W", "time": "10:00", "duration": "80", "campus": "Keele", "room": "CLH A"}]`
+	starts, ends := time.Date(2025, time.September, 2, 0, 0, 0, 0, time.UTC), time.Date(2026, time.April, 30, 0, 0, 0, 0, time.UTC)
+	sectionIDs, activityIDs := []string{"sec-1"}, []string{"lab-1"}
+
+	mock.ExpectQuery("LEFT JOIN terms t ON t.id = s.term_id(.+)WHERE sa.id = ANY\\(\\$2::uuid\\[\\]\\)(.+)sa.course_type NOT IN \\('LAB', 'TUTR'\\)").
+		WithArgs(sectionIDs, activityIDs).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "code", "name", "term", "letter", "course_type", "times", "starts_on", "ends_on"}).
+			AddRow("act-1", "EECS2030", "Advanced OOP", models.TermFall, "A", models.ActivityLecture, &times, dbtypes.NewNullTime(starts), dbtypes.NewNullTime(ends)).
+			AddRow("lab-1", "EECS2030", "Advanced OOP", models.TermFall, "A", models.ActivityLab, nil, dbtypes.NullTime{}, dbtypes.NullTime{}))
+
+	activities, err := repo.ListForCalendar(context.Background(), sectionIDs, activityIDs)
+
+	assert.NoError(t, err)
+	assert.Len(t, activities, 2)
+	assert.Equal(t, "Advanced OOP", activities[0].CourseName)
+	assert.True(t, activities[0].SessionStart.Time.Equal(starts))
+	assert.False(t, activities[1].SessionStart.Valid)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -10,6 +10,7 @@ import (
 
 type SectionRepositoryInterface interface {
 	GetByCourseID(ctx context.Context, courseID string) ([]models.Section, error)
+	GetByCourseIDInTerm(ctx context.Context, courseID, termID string) ([]models.Section, error)
 }
 
 type sectionDB interface {
@@ -29,6 +30,9 @@ func NewSectionRepository(db sectionDB, activityRepo SectionActivityRepositoryIn
 }
 
 func (r *SectionRepository) GetByCourseID(ctx context.Context, courseID string) ([]models.Section, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
 	rows, err := r.db.Query(
 		ctx,
 		`SELECT id, course_id, letter, created_at, updated_at
@@ -40,6 +44,75 @@ func (r *SectionRepository) GetByCourseID(ctx context.Context, courseID string)
 	if err != nil {
 		return nil, fmt.Errorf("query sections by course_id: %w", err)
 	}
+	return r.collect(ctx, rows)
+}
+
+// GetByCourseIDInTerm is GetByCourseID keeping only the sections in a term, e.g. FW2025.
+func (r *SectionRepository) GetByCourseIDInTerm(ctx context.Context, courseID, termID string) ([]models.Section, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	rows, err := r.db.Query(
+		ctx,
+		`SELECT id, course_id, letter, created_at, updated_at
+		 FROM sections
+		 WHERE course_id = $1 AND term_id = $2
+		 ORDER BY letter`,
+		courseID, termID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query sections by course_id and term: %w", err)
+	}
+	return r.collect(ctx, rows)
+}
+
+// GetSiblings returns the code of the course a section belongs to and every
+// section of that course in the section's term, the section included, with
+// their activities. The code is empty when there is no such section.
+func (r *SectionRepository) GetSiblings(ctx context.Context, sectionID string) (string, []models.Section, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	rows, err := r.db.Query(
+		ctx,
+		`SELECT c.code, s.id, s.course_id, s.letter, s.created_at, s.updated_at
+		 FROM sections target
+		 INNER JOIN courses c ON c.id = target.course_id
+		 INNER JOIN sections s ON s.course_id = target.course_id AND s.term_id IS NOT DISTINCT FROM target.term_id
+		 WHERE target.id = $1
+		 ORDER BY s.letter`,
+		sectionID,
+	)
+	if err != nil {
+		return "", nil, fmt.Errorf("query sibling sections: %w", err)
+	}
+	defer rows.Close()
+
+	var code string
+	sections := make([]models.Section, 0)
+	for rows.Next() {
+		var sec models.Section
+		if err := rows.Scan(&code, &sec.ID, &sec.CourseID, &sec.Letter, &sec.CreatedAt, &sec.UpdatedAt); err != nil {
+			return "", nil, fmt.Errorf("scan section: %w", err)
+		}
+		sections = append(sections, sec)
+	}
+	if err := rows.Err(); err != nil {
+		return "", nil, fmt.Errorf("iterate sections: %w", err)
+	}
+
+	for i := range sections {
+		activities, err := r.activityRepo.GetBySectionID(ctx, sections[i].ID)
+		if err != nil {
+			return "", nil, fmt.Errorf("fetch activities for section %s: %w", sections[i].ID, err)
+		}
+		sections[i].Activities = activities
+	}
+	return code, sections, nil
+}
+
+// collect scans sections and fetches each one's activities.
+func (r *SectionRepository) collect(ctx context.Context, rows pgx.Rows) ([]models.Section, error) {
 	defer rows.Close()
 
 	sections := make([]models.Section, 0)
@@ -33,6 +33,14 @@ func (m *mockActivityRepo) GetBySectionIDAndType(ctx context.Context, sectionID
 	return result, nil
 }
 
+func (m *mockActivityRepo) ListByIDs(ctx context.Context, ids []string) ([]models.TeachingActivity, error) {
+	return []models.TeachingActivity{}, nil
+}
+
+func (m *mockActivityRepo) ListForCalendar(ctx context.Context, sectionIDs, activityIDs []string) ([]models.CalendarActivity, error) {
+	return []models.CalendarActivity{}, nil
+}
+
 func TestGetSectionsByCourseID(t *testing.T) {
 	mock, err := pgxmock.NewPool()
 	assert.NoError(t, err)
@@ -41,10 +49,10 @@ func TestGetSectionsByCourseID(t *testing.T) {
 	activityRepo := &mockActivityRepo{
 		activities: map[string][]models.SectionActivity{
 			"section-1": {
-				{ID: "act-1", CourseType: "LECT", SectionID: "section-1", CatalogNumber: "", Times: nil},
+				{ID: "act-1", CourseType: "LECT", SectionID: "section-1", CatalogNumber: ""},
 			},
 			"section-2": {
-				{ID: "act-2", CourseType: "LAB", SectionID: "section-2", CatalogNumber: "L01", Times: nil},
+				{ID: "act-2", CourseType: "LAB", SectionID: "section-2", CatalogNumber: "L01"},
 			},
 		},
 	}
@@ -155,3 +163,65 @@ func TestGetSectionsByCourseID_EmptyResult(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+
+func TestGetSectionsByCourseIDInTerm(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	activityRepo := &mockActivityRepo{activities: map[string][]models.SectionActivity{
+		"section-1": {{ID: "act-1", CourseType: "LECT", SectionID: "section-1"}},
+	}}
+	repo := NewSectionRepository(mock, activityRepo)
+	now := time.Now()
+
+	mock.ExpectQuery("FROM sections\\s+WHERE course_id = \\$1 AND term_id = \\$2\\s+ORDER BY letter").
+		WithArgs("course-1", "FW2025").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "course_id", "letter", "created_at", "updated_at"}).
+			AddRow("section-1", "course-1", "A", now, now))
+
+	sections, err := repo.GetByCourseIDInTerm(context.Background(), "course-1", "FW2025")
+	assert.NoError(t, err)
+	assert.Len(t, sections, 1)
+	assert.Len(t, sections[0].Activities, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetSiblings(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	activityRepo := &mockActivityRepo{
+		activities: map[string][]models.SectionActivity{
+			"section-2": {
+				{ID: "act-2", CourseType: "LAB", SectionID: "section-2", CatalogNumber: "L01"},
+			},
+		},
+	}
+	repo := NewSectionRepository(mock, activityRepo)
+	now := time.Now()
+
+	mock.ExpectQuery("FROM sections target (.+) s.term_id IS NOT DISTINCT FROM target.term_id WHERE target.id = \\$1").
+		WithArgs("section-1").
+		WillReturnRows(pgxmock.NewRows([]string{"code", "id", "course_id", "letter", "created_at", "updated_at"}).
+			AddRow("EECS2030", "section-1", "course-1", "A", now, now).
+			AddRow("EECS2030", "section-2", "course-1", "B", now, now))
+	mock.ExpectQuery("FROM sections target").
+		WithArgs("missing").
+		WillReturnRows(pgxmock.NewRows([]string{"code", "id", "course_id", "letter", "created_at", "updated_at"}))
+
+	code, sections, err := repo.GetSiblings(context.Background(), "section-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "EECS2030", code)
+	if assert.Len(t, sections, 2) {
+		assert.Empty(t, sections[0].Activities)
+		assert.Equal(t, "L01", sections[1].Activities[0].CatalogNumber)
+	}
+
+	code, sections, err = repo.GetSiblings(context.Background(), "missing")
+	assert.NoError(t, err)
+	assert.Empty(t, code)
+	assert.Empty(t, sections)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"yuplan/internal/models"
+	"yuplan/internal/session"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+// ErrTooManyDrafts is returned when a session already holds MaxSessionDrafts drafts.
+var ErrTooManyDrafts = errors.New("too many drafts in this session")
+
+type SessionRepositoryInterface interface {
+	RecordView(ctx context.Context, sess session.Session, courseCode string) error
+	ListRecentViews(ctx context.Context, sessionID string) ([]models.RecentView, error)
+	ListDrafts(ctx context.Context, sessionID string) ([]models.SessionDraft, error)
+	GetDraft(ctx context.Context, sessionID, key string) (*models.SessionDraft, error)
+	SaveDraft(ctx context.Context, sess session.Session, key string, body json.RawMessage) (*models.SessionDraft, error)
+	DeleteDraft(ctx context.Context, sessionID, key string) (bool, error)
+	Delete(ctx context.Context, sessionID string) error
+}
+
+type sessionDB interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+// SessionRepository keeps what anonymous sessions store. A session's row is
+// written on its first write, so sessions that never store anything cost nothing.
+type SessionRepository struct {
+	db sessionDB
+}
+
+func NewSessionRepository(db sessionDB) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+func (r *SessionRepository) ensure(ctx context.Context, sess session.Session) error {
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO anonymous_sessions (id, started_at) VALUES ($1, $2) ON CONFLICT (id) DO NOTHING`,
+		sess.ID, sess.StartedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert session: %w", err)
+	}
+	return nil
+}
+
+// RecordView moves courseCode to the top of the session's recent views,
+// dropping the oldest beyond MaxRecentViews.
+func (r *SessionRepository) RecordView(ctx context.Context, sess session.Session, courseCode string) error {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	if err := r.ensure(ctx, sess); err != nil {
+		return err
+	}
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO session_recent_views (session_id, course_code, viewed_at)
+		 VALUES ($1, $2, NOW())
+		 ON CONFLICT (session_id, course_code) DO UPDATE SET viewed_at = EXCLUDED.viewed_at`,
+		sess.ID, courseCode,
+	)
+	if err != nil {
+		return fmt.Errorf("record recent view: %w", err)
+	}
+	_, err = r.db.Exec(ctx,
+		`DELETE FROM session_recent_views
+		 WHERE session_id = $1 AND course_code NOT IN (
+		     SELECT course_code FROM session_recent_views
+		     WHERE session_id = $1 ORDER BY viewed_at DESC LIMIT $2)`,
+		sess.ID, models.MaxRecentViews,
+	)
+	if err != nil {
+		return fmt.Errorf("trim recent views: %w", err)
+	}
+	return nil
+}
+
+// ListRecentViews returns the session's recently viewed courses, latest first.
+func (r *SessionRepository) ListRecentViews(ctx context.Context, sessionID string) ([]models.RecentView, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	rows, err := r.db.Query(ctx,
+		`SELECT course_code, viewed_at FROM session_recent_views
+		 WHERE session_id = $1
+		 ORDER BY viewed_at DESC, course_code`,
+		sessionID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query recent views: %w", err)
+	}
+	defer rows.Close()
+
+	views := make([]models.RecentView, 0)
+	for rows.Next() {
+		var v models.RecentView
+		if err := rows.Scan(&v.CourseCode, &v.ViewedAt); err != nil {
+			return nil, fmt.Errorf("scan recent view: %w", err)
+		}
+		views = append(views, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate recent views: %w", err)
+	}
+	return views, nil
+}
+
+// ListDrafts returns the keys of the session's drafts, most recently saved first, without their bodies.
+func (r *SessionRepository) ListDrafts(ctx context.Context, sessionID string) ([]models.SessionDraft, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	rows, err := r.db.Query(ctx,
+		`SELECT key, updated_at FROM session_drafts
+		 WHERE session_id = $1
+		 ORDER BY updated_at DESC, key`,
+		sessionID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query drafts: %w", err)
+	}
+	defer rows.Close()
+
+	drafts := make([]models.SessionDraft, 0)
+	for rows.Next() {
+		var d models.SessionDraft
+		if err := rows.Scan(&d.Key, &d.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan draft: %w", err)
+		}
+		drafts = append(drafts, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate drafts: %w", err)
+	}
+	return drafts, nil
+}
+
+// GetDraft returns one of the session's drafts, or nil if there is none under key.
+func (r *SessionRepository) GetDraft(ctx context.Context, sessionID, key string) (*models.SessionDraft, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	d := models.SessionDraft{Key: key}
+	err := r.db.QueryRow(ctx,
+		`SELECT body, updated_at FROM session_drafts WHERE session_id = $1 AND key = $2`,
+		sessionID, key,
+	).Scan(&d.Body, &d.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query draft: %w", err)
+	}
+	return &d, nil
+}
+
+// SaveDraft stores body under key, replacing any draft already there. A new
+// key is refused with ErrTooManyDrafts once the session holds MaxSessionDrafts.
+func (r *SessionRepository) SaveDraft(ctx context.Context, sess session.Session, key string, body json.RawMessage) (*models.SessionDraft, error) {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	if err := r.ensure(ctx, sess); err != nil {
+		return nil, err
+	}
+	d := models.SessionDraft{Key: key, Body: body}
+	err := r.db.QueryRow(ctx,
+		`INSERT INTO session_drafts (session_id, key, body, updated_at)
+		 SELECT $1, $2, $3, NOW()
+		 WHERE EXISTS (SELECT 1 FROM session_drafts WHERE session_id = $1 AND key = $2)
+		    OR (SELECT COUNT(*) FROM session_drafts WHERE session_id = $1) < $4
+		 ON CONFLICT (session_id, key) DO UPDATE SET body = EXCLUDED.body, updated_at = EXCLUDED.updated_at
+		 RETURNING updated_at`,
+		sess.ID, key, body, models.MaxSessionDrafts,
+	).Scan(&d.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrTooManyDrafts
+	}
+	if err != nil {
+		return nil, fmt.Errorf("save draft: %w", err)
+	}
+	return &d, nil
+}
+
+// DeleteDraft removes one of the session's drafts and reports whether there was one.
+func (r *SessionRepository) DeleteDraft(ctx context.Context, sessionID, key string) (bool, error) {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	tag, err := r.db.Exec(ctx, `DELETE FROM session_drafts WHERE session_id = $1 AND key = $2`, sessionID, key)
+	if err != nil {
+		return false, fmt.Errorf("delete draft: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// Delete removes the session with everything it stored.
+func (r *SessionRepository) Delete(ctx context.Context, sessionID string) error {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	if _, err := r.db.Exec(ctx, `DELETE FROM anonymous_sessions WHERE id = $1`, sessionID); err != nil {
+		return fmt.Errorf("delete session: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+	"yuplan/internal/models"
+	"yuplan/internal/session"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var testSession = session.Session{ID: "sess-1", StartedAt: time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)}
+
+func TestSessionRepository_RecordView(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewSessionRepository(mock)
+
+	mock.ExpectExec("INSERT INTO anonymous_sessions \\(id, started_at\\) (.+) ON CONFLICT \\(id\\) DO NOTHING").
+		WithArgs("sess-1", testSession.StartedAt).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectExec("INSERT INTO session_recent_views (.+) ON CONFLICT \\(session_id, course_code\\) DO UPDATE").
+		WithArgs("sess-1", "EECS2030").
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectExec("DELETE FROM session_recent_views (.+) ORDER BY viewed_at DESC LIMIT \\$2").
+		WithArgs("sess-1", models.MaxRecentViews).
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+
+	assert.NoError(t, repo.RecordView(context.Background(), testSession, "EECS2030"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionRepository_ListRecentViews(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewSessionRepository(mock)
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT course_code, viewed_at FROM session_recent_views").
+		WithArgs("sess-1").
+		WillReturnRows(pgxmock.NewRows([]string{"course_code", "viewed_at"}).AddRow("EECS2030", now))
+
+	views, err := repo.ListRecentViews(context.Background(), "sess-1")
+	assert.NoError(t, err)
+	assert.Equal(t, []models.RecentView{{CourseCode: "EECS2030", ViewedAt: now}}, views)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionRepository_Drafts(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewSessionRepository(mock)
+	now := time.Now()
+	body := json.RawMessage(`{"review_text":"So far"}`)
+
+	mock.ExpectExec("INSERT INTO anonymous_sessions").WillReturnResult(pgxmock.NewResult("INSERT", 0))
+	mock.ExpectQuery("INSERT INTO session_drafts (.+) COUNT\\(\\*\\) FROM session_drafts WHERE session_id = \\$1\\) < \\$4 ON CONFLICT").
+		WithArgs("sess-1", "review:EECS2030", body, models.MaxSessionDrafts).
+		WillReturnRows(pgxmock.NewRows([]string{"updated_at"}).AddRow(now))
+	mock.ExpectExec("INSERT INTO anonymous_sessions").WillReturnResult(pgxmock.NewResult("INSERT", 0))
+	mock.ExpectQuery("INSERT INTO session_drafts").WillReturnError(pgx.ErrNoRows)
+	mock.ExpectQuery("SELECT body, updated_at FROM session_drafts").
+		WithArgs("sess-1", "missing").
+		WillReturnError(pgx.ErrNoRows)
+	mock.ExpectExec("DELETE FROM session_drafts").
+		WithArgs("sess-1", "review:EECS2030").
+		WillReturnResult(pgxmock.NewResult("DELETE", 1))
+
+	draft, err := repo.SaveDraft(context.Background(), testSession, "review:EECS2030", body)
+	assert.NoError(t, err)
+	assert.Equal(t, now, draft.UpdatedAt)
+
+	_, err = repo.SaveDraft(context.Background(), testSession, "one-more", body)
+	assert.ErrorIs(t, err, ErrTooManyDrafts)
+
+	draft, err = repo.GetDraft(context.Background(), "sess-1", "missing")
+	assert.NoError(t, err)
+	assert.Nil(t, draft)
+
+	deleted, err := repo.DeleteDraft(context.Background(), "sess-1", "review:EECS2030")
+	assert.NoError(t, err)
+	assert.True(t, deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionRepository_Delete(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewSessionRepository(mock)
+
+	mock.ExpectExec("DELETE FROM anonymous_sessions WHERE id = \\$1").
+		WithArgs("sess-1").
+		WillReturnError(errors.New("db down"))
+
+	assert.ErrorContains(t, repo.Delete(context.Background(), "sess-1"), "delete session")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
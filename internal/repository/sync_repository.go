@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"yuplan/internal/models"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+type SyncRepositoryInterface interface {
+	Start(ctx context.Context, term string) (int64, error)
+	Finish(ctx context.Context, run models.SyncRun) error
+	List(ctx context.Context, limit int) ([]models.SyncRun, error)
+}
+
+type syncDB interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+// SyncRepository keeps the history of scheduled catalog syncs in sync_history.
+type SyncRepository struct {
+	db syncDB
+}
+
+func NewSyncRepository(db syncDB) *SyncRepository {
+	return &SyncRepository{db: db}
+}
+
+// Start records a sync as running and returns its id. Syncs only run under
+// the job lock, so any run still marked running was cut short when its
+// instance stopped; those are marked failed first.
+func (r *SyncRepository) Start(ctx context.Context, term string) (int64, error) {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	var id int64
+	err := r.db.QueryRow(ctx,
+		`WITH abandoned AS (
+		     UPDATE sync_history
+		     SET status = 'failed', finished_at = NOW(), error = 'abandoned: the instance running it stopped'
+		     WHERE status = 'running'
+		 )
+		 INSERT INTO sync_history (term) VALUES ($1)
+		 RETURNING id`,
+		term,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("start sync: %w", err)
+	}
+	return id, nil
+}
+
+// Finish records how a sync ended.
+func (r *SyncRepository) Finish(ctx context.Context, run models.SyncRun) error {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	diff, err := json.Marshal(run.Diff)
+	if err != nil {
+		return fmt.Errorf("encode sync diff: %w", err)
+	}
+	_, err = r.db.Exec(ctx,
+		`UPDATE sync_history
+		 SET status = $2, finished_at = NOW(), pages = $3, quarantined = $4, diff = $5::jsonb, error = NULLIF($6, '')
+		 WHERE id = $1`,
+		run.ID, run.Status, run.Pages, run.Quarantined, string(diff), run.Error,
+	)
+	if err != nil {
+		return fmt.Errorf("finish sync %d: %w", run.ID, err)
+	}
+	return nil
+}
+
+// List returns the most recent syncs, newest first.
+func (r *SyncRepository) List(ctx context.Context, limit int) ([]models.SyncRun, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	rows, err := r.db.Query(ctx,
+		`SELECT id, term, status, started_at, finished_at, pages, quarantined, diff::text, COALESCE(error, '')
+		 FROM sync_history
+		 ORDER BY started_at DESC, id DESC
+		 LIMIT $1`,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query sync history: %w", err)
+	}
+	defer rows.Close()
+
+	runs := []models.SyncRun{}
+	for rows.Next() {
+		var run models.SyncRun
+		var diff string
+		if err := rows.Scan(&run.ID, &run.Term, &run.Status, &run.StartedAt, &run.FinishedAt,
+			&run.Pages, &run.Quarantined, &diff, &run.Error); err != nil {
+			return nil, fmt.Errorf("scan sync run: %w", err)
+		}
+		if err := json.Unmarshal([]byte(diff), &run.Diff); err != nil {
+			return nil, fmt.Errorf("decode sync %d diff: %w", run.ID, err)
+		}
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate sync history: %w", err)
+	}
+	return runs, nil
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+	"yuplan/internal/dbtypes"
+	"yuplan/internal/models"
+
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncRepository_Start(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewSyncRepository(mock)
+
+	mock.ExpectQuery("UPDATE sync_history (.+) WHERE status = 'running' (.+) INSERT INTO sync_history").
+		WithArgs("FW2025").
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(int64(7)))
+
+	id, err := repo.Start(context.Background(), "FW2025")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSyncRepository_Finish(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewSyncRepository(mock)
+
+	mock.ExpectExec("UPDATE sync_history SET status = \\$2").
+		WithArgs(int64(7), models.SyncSucceeded, 10, 2,
+			`{"courses":{"added":1,"changed":0,"removed":0},"sections":{"added":0,"changed":0,"removed":0},`+
+				`"activities":{"added":0,"changed":3,"removed":0},"instructors":{"added":0,"changed":0,"removed":0}}`,
+			"").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	err = repo.Finish(context.Background(), models.SyncRun{
+		ID: 7, Status: models.SyncSucceeded, Pages: 10, Quarantined: 2,
+		Diff: models.CatalogDiff{Courses: models.RowDiff{Added: 1}, Activities: models.RowDiff{Changed: 3}},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSyncRepository_List(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewSyncRepository(mock)
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT (.+) FROM sync_history ORDER BY started_at DESC").
+		WithArgs(20).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "term", "status", "started_at", "finished_at", "pages", "quarantined", "diff", "error"}).
+			AddRow(int64(8), "FW2025", models.SyncRunning, now, dbtypes.NullTime{}, 0, 0, `{}`, "").
+			AddRow(int64(7), "FW2025", models.SyncFailed, now.Add(-24*time.Hour), dbtypes.NewNullTime(now.Add(-23*time.Hour)), 3, 0,
+				`{"sections": {"added": 2, "changed": 0, "removed": 1}}`, "fetch timetable: 503"))
+
+	runs, err := repo.List(context.Background(), 20)
+	assert.NoError(t, err)
+	assert.Len(t, runs, 2)
+	assert.False(t, runs[0].FinishedAt.Valid)
+	assert.Equal(t, models.CatalogDiff{}, runs[0].Diff)
+	assert.True(t, runs[1].FinishedAt.Valid)
+	assert.Equal(t, models.RowDiff{Added: 2, Removed: 1}, runs[1].Diff.Sections)
+	assert.Equal(t, "fetch timetable: 503", runs[1].Error)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
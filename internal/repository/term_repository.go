@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"yuplan/internal/models"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+type TermRepositoryInterface interface {
+	List(ctx context.Context) ([]models.AcademicTerm, error)
+	Upsert(ctx context.Context, term *models.AcademicTerm) error
+	CurrentExamPeriod(ctx context.Context) (*models.AcademicTerm, error)
+	ListSessions(ctx context.Context) ([]models.Term, error)
+	UpcomingDeadlines(ctx context.Context, terms []string) ([]models.TermDeadlines, error)
+}
+
+type termDB interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+type TermRepository struct {
+	db termDB
+}
+
+func NewTermRepository(db termDB) *TermRepository {
+	return &TermRepository{db: db}
+}
+
+const termColumns = `academic_year, term, enroll_deadline, drop_deadline, exams_start, exams_end, grades_released, updated_at`
+
+// List returns every term's dates, most recent first.
+func (r *TermRepository) List(ctx context.Context) ([]models.AcademicTerm, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	rows, err := r.db.Query(ctx,
+		`SELECT `+termColumns+` FROM academic_terms ORDER BY exams_start DESC`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query academic terms: %w", err)
+	}
+	defer rows.Close()
+
+	terms := []models.AcademicTerm{}
+	for rows.Next() {
+		t, err := scanAcademicTerm(rows)
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, *t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate academic terms: %w", err)
+	}
+	return terms, nil
+}
+
+// Upsert sets a term's dates, creating the term if needed.
+func (r *TermRepository) Upsert(ctx context.Context, term *models.AcademicTerm) error {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	err := r.db.QueryRow(ctx,
+		`INSERT INTO academic_terms (academic_year, term, enroll_deadline, drop_deadline, exams_start, exams_end, grades_released)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)
+		 ON CONFLICT (academic_year, term) DO UPDATE
+		 SET enroll_deadline = EXCLUDED.enroll_deadline,
+		     drop_deadline = EXCLUDED.drop_deadline,
+		     exams_start = EXCLUDED.exams_start,
+		     exams_end = EXCLUDED.exams_end,
+		     grades_released = EXCLUDED.grades_released,
+		     updated_at = NOW()
+		 RETURNING updated_at`,
+		term.AcademicYear, term.Term, term.EnrollDeadline.Ptr(), term.DropDeadline.Ptr(),
+		term.ExamsStart, term.ExamsEnd, term.GradesReleased,
+	).Scan(&term.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("upsert academic term: %w", err)
+	}
+	return nil
+}
+
+// CurrentExamPeriod returns the term whose exams are under way, or nil if none
+// are. When exam periods overlap, the one with the latest grade release wins.
+func (r *TermRepository) CurrentExamPeriod(ctx context.Context) (*models.AcademicTerm, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	t, err := scanAcademicTerm(r.db.QueryRow(ctx,
+		`SELECT `+termColumns+`
+		 FROM academic_terms
+		 WHERE exams_start <= NOW() AND NOW() < exams_end
+		 ORDER BY grades_released DESC
+		 LIMIT 1`,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return t, err
+}
+
+// ListSessions returns the sessions sections can belong to, most recent first.
+func (r *TermRepository) ListSessions(ctx context.Context) ([]models.Term, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	rows, err := r.db.Query(ctx,
+		`SELECT id, session, academic_year, starts_on, ends_on FROM terms ORDER BY starts_on DESC`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query terms: %w", err)
+	}
+	defer rows.Close()
+
+	terms := []models.Term{}
+	for rows.Next() {
+		var t models.Term
+		if err := rows.Scan(&t.ID, &t.Session, &t.AcademicYear, &t.StartsOn, &t.EndsOn); err != nil {
+			return nil, fmt.Errorf("scan term: %w", err)
+		}
+		terms = append(terms, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate terms: %w", err)
+	}
+	return terms, nil
+}
+
+// UpcomingDeadlines returns, for each of terms (course term codes like F or
+// Y), the soonest academic year with a registration deadline still ahead.
+// Deadlines already past come back null, and terms with none ahead are left
+// out.
+func (r *TermRepository) UpcomingDeadlines(ctx context.Context, terms []string) ([]models.TermDeadlines, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	// GREATEST skips NULLs, so a term with one deadline published still counts
+	rows, err := r.db.Query(ctx,
+		`SELECT DISTINCT ON (term) academic_year, term,
+		        CASE WHEN enroll_deadline > NOW() THEN enroll_deadline END,
+		        CASE WHEN drop_deadline > NOW() THEN drop_deadline END
+		 FROM academic_terms
+		 WHERE term = ANY($1) AND GREATEST(enroll_deadline, drop_deadline) > NOW()
+		 ORDER BY term, academic_year`,
+		terms,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query term deadlines: %w", err)
+	}
+	defer rows.Close()
+
+	deadlines := []models.TermDeadlines{}
+	for rows.Next() {
+		var d models.TermDeadlines
+		if err := rows.Scan(&d.AcademicYear, &d.Term, &d.EnrollDeadline, &d.DropDeadline); err != nil {
+			return nil, fmt.Errorf("scan term deadlines: %w", err)
+		}
+		deadlines = append(deadlines, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate term deadlines: %w", err)
+	}
+	return deadlines, nil
+}
+
+func scanAcademicTerm(row pgx.Row) (*models.AcademicTerm, error) {
+	var t models.AcademicTerm
+	if err := row.Scan(&t.AcademicYear, &t.Term, &t.EnrollDeadline, &t.DropDeadline, &t.ExamsStart, &t.ExamsEnd, &t.GradesReleased, &t.UpdatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("scan academic term: %w", err)
+	}
+	return &t, nil
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+	"yuplan/internal/dbtypes"
+	"yuplan/internal/models"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var termRowColumns = []string{"academic_year", "term", "enroll_deadline", "drop_deadline", "exams_start", "exams_end", "grades_released", "updated_at"}
+
+func TestTermRepository_List(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewTermRepository(mock)
+	start := time.Date(2026, 12, 8, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("SELECT (.+) FROM academic_terms ORDER BY exams_start DESC").
+		WillReturnRows(pgxmock.NewRows(termRowColumns).
+			AddRow(2026, "F", dbtypes.NullTime{}, dbtypes.NewNullTime(start.AddDate(0, -1, 0)), start, start.AddDate(0, 0, 15), start.AddDate(0, 0, 30), start))
+
+	terms, err := repo.List(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, terms, 1)
+	assert.Equal(t, 2026, terms[0].AcademicYear)
+	assert.Equal(t, start.AddDate(0, 0, 30), terms[0].GradesReleased)
+	assert.False(t, terms[0].EnrollDeadline.Valid)
+	assert.Equal(t, start.AddDate(0, -1, 0), terms[0].DropDeadline.Time)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTermRepository_Upsert(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewTermRepository(mock)
+	start := time.Date(2027, 4, 10, 0, 0, 0, 0, time.UTC)
+	term := &models.AcademicTerm{
+		AcademicYear:   2026,
+		Term:           "W",
+		ExamsStart:     start,
+		ExamsEnd:       start.AddDate(0, 0, 18),
+		GradesReleased: start.AddDate(0, 0, 35),
+		DropDeadline:   dbtypes.NewNullTime(start.AddDate(0, -1, 0)),
+	}
+
+	mock.ExpectQuery("INSERT INTO academic_terms(.+)ON CONFLICT \\(academic_year, term\\) DO UPDATE").
+		WithArgs(2026, "W", (*time.Time)(nil), term.DropDeadline.Ptr(), term.ExamsStart, term.ExamsEnd, term.GradesReleased).
+		WillReturnRows(pgxmock.NewRows([]string{"updated_at"}).AddRow(start))
+
+	assert.NoError(t, repo.Upsert(context.Background(), term))
+	assert.Equal(t, start, term.UpdatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTermRepository_CurrentExamPeriod_None(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewTermRepository(mock)
+
+	mock.ExpectQuery("WHERE exams_start <= NOW\\(\\) AND NOW\\(\\) < exams_end").
+		WillReturnError(pgx.ErrNoRows)
+
+	term, err := repo.CurrentExamPeriod(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, term)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTermRepository_ListSessions(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewTermRepository(mock)
+	start := time.Date(2025, 9, 2, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("SELECT id, session, academic_year, starts_on, ends_on FROM terms ORDER BY starts_on DESC").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "session", "academic_year", "starts_on", "ends_on"}).
+			AddRow("FW2025", models.SessionFallWinter, 2025, start, start.AddDate(0, 8, 0)))
+
+	terms, err := repo.ListSessions(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, terms, 1)
+	assert.Equal(t, "FW2025", terms[0].ID)
+	assert.Equal(t, start, terms[0].StartsOn)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTermRepository_UpcomingDeadlines(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewTermRepository(mock)
+	dropBy := time.Date(2026, 11, 8, 4, 59, 0, 0, time.UTC)
+
+	mock.ExpectQuery("SELECT DISTINCT ON \\(term\\) (.+) FROM academic_terms WHERE term = ANY\\(\\$1\\) AND GREATEST\\(enroll_deadline, drop_deadline\\) > NOW\\(\\)").
+		WithArgs([]string{"F", "W"}).
+		WillReturnRows(pgxmock.NewRows([]string{"academic_year", "term", "enroll_deadline", "drop_deadline"}).
+			AddRow(2026, "F", dbtypes.NullTime{}, dbtypes.NewNullTime(dropBy)))
+
+	deadlines, err := repo.UpcomingDeadlines(context.Background(), []string{"F", "W"})
+	assert.NoError(t, err)
+	assert.Equal(t, []models.TermDeadlines{{AcademicYear: 2026, Term: "F", DropDeadline: dbtypes.NewNullTime(dropBy)}}, deadlines)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// operation classifies repository calls for the timeout policy.
+type operation int
+
+const (
+	opRead      operation = iota // indexed lookups and short lists
+	opWrite                      // inserts, updates and deletes
+	opAggregate                  // stats, full scans and bulk job writes
+	opExport                     // streamed exports; never time out
+)
+
+// Timeouts is the deadline applied to each class of repository call, so one
+// hung aggregate can't hold pool connections that page reads need. Zero
+// disables the deadline for that class.
+type Timeouts struct {
+	Read      time.Duration
+	Write     time.Duration
+	Aggregate time.Duration
+}
+
+func DefaultTimeouts() Timeouts {
+	return Timeouts{
+		Read:      500 * time.Millisecond,
+		Write:     time.Second,
+		Aggregate: 2 * time.Second,
+	}
+}
+
+var timeouts atomic.Value // Timeouts
+
+func init() {
+	timeouts.Store(DefaultTimeouts())
+}
+
+// SetTimeouts replaces the timeout policy for all repositories.
+func SetTimeouts(t Timeouts) {
+	timeouts.Store(t)
+}
+
+type operationKey struct{}
+
+// withDeadline bounds ctx by the policy for op and records op on it for
+// ResilientDB's retry policy. The caller must call cancel once it is done
+// with the results, including iterating rows.
+func withDeadline(ctx context.Context, op operation) (context.Context, context.CancelFunc) {
+	ctx = context.WithValue(ctx, operationKey{}, op)
+	t := timeouts.Load().(Timeouts)
+	var d time.Duration
+	switch op {
+	case opRead:
+		d = t.Read
+	case opWrite:
+		d = t.Write
+	case opAggregate:
+		d = t.Aggregate
+	}
+	if d <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, d)
+}
+
+// isRead reports whether ctx comes from withDeadline for an opRead call, the
+// only class that never writes.
+func isRead(ctx context.Context) bool {
+	op, ok := ctx.Value(operationKey{}).(operation)
+	return ok && op == opRead
+}
+
+// IsTimeout reports whether err came from a repository call running out of time.
+func IsTimeout(err error) bool {
+	return errors.Is(err, context.DeadlineExceeded)
+}
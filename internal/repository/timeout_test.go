@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithDeadline_AppliesPolicyPerOperation(t *testing.T) {
+	defer SetTimeouts(DefaultTimeouts())
+	SetTimeouts(Timeouts{Read: time.Minute, Write: 2 * time.Minute, Aggregate: 3 * time.Minute})
+
+	for op, want := range map[operation]time.Duration{
+		opRead:      time.Minute,
+		opWrite:     2 * time.Minute,
+		opAggregate: 3 * time.Minute,
+	} {
+		ctx, cancel := withDeadline(context.Background(), op)
+		deadline, ok := ctx.Deadline()
+		cancel()
+
+		assert.True(t, ok)
+		assert.WithinDuration(t, time.Now().Add(want), deadline, 5*time.Second)
+	}
+}
+
+func TestWithDeadline_ExportsAndDisabledClassesHaveNoDeadline(t *testing.T) {
+	defer SetTimeouts(DefaultTimeouts())
+	SetTimeouts(Timeouts{Read: time.Second})
+
+	for _, op := range []operation{opExport, opWrite, opAggregate} {
+		ctx, cancel := withDeadline(context.Background(), op)
+		_, ok := ctx.Deadline()
+		cancel()
+
+		assert.False(t, ok)
+	}
+}
+
+func TestWithDeadline_KeepsEarlierCallerDeadline(t *testing.T) {
+	parent, cancelParent := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancelParent()
+
+	ctx, cancel := withDeadline(parent, opAggregate)
+	defer cancel()
+
+	want, _ := parent.Deadline()
+	got, _ := ctx.Deadline()
+	assert.Equal(t, want, got)
+}
+
+func TestIsTimeout(t *testing.T) {
+	assert.True(t, IsTimeout(fmt.Errorf("query courses: %w", context.DeadlineExceeded)))
+	assert.False(t, IsTimeout(context.Canceled))
+	assert.False(t, IsTimeout(nil))
+}
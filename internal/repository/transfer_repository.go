@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"yuplan/internal/models"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+type TransferRepositoryInterface interface {
+	FindEquivalencies(ctx context.Context, institution string, externalCodes []string) ([]models.Equivalency, error)
+	ListEquivalencies(ctx context.Context, institution string) ([]models.Equivalency, error)
+	UpsertEquivalency(ctx context.Context, eq *models.Equivalency) error
+	DeleteEquivalency(ctx context.Context, id string) (bool, error)
+}
+
+type transferDB interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+type TransferRepository struct {
+	db transferDB
+}
+
+func NewTransferRepository(db transferDB) *TransferRepository {
+	return &TransferRepository{db: db}
+}
+
+const equivalencyColumns = `id, institution, external_course_code, york_course_code, confidence, notes, created_at, updated_at`
+
+// FindEquivalencies returns every mapping for the given normalized institution and external course codes.
+func (r *TransferRepository) FindEquivalencies(ctx context.Context, institution string, externalCodes []string) ([]models.Equivalency, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	return r.query(ctx,
+		`SELECT `+equivalencyColumns+`
+		 FROM transfer_equivalencies
+		 WHERE institution = $1 AND external_course_code = ANY($2)
+		 ORDER BY external_course_code, york_course_code`,
+		institution, externalCodes,
+	)
+}
+
+// ListEquivalencies returns all mappings, or only one institution's when institution is non-empty.
+func (r *TransferRepository) ListEquivalencies(ctx context.Context, institution string) ([]models.Equivalency, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
+	return r.query(ctx,
+		`SELECT `+equivalencyColumns+`
+		 FROM transfer_equivalencies
+		 WHERE $1 = '' OR institution = $1
+		 ORDER BY institution, external_course_code, york_course_code`,
+		institution,
+	)
+}
+
+// UpsertEquivalency inserts a mapping or updates confidence and notes of an existing one.
+func (r *TransferRepository) UpsertEquivalency(ctx context.Context, eq *models.Equivalency) error {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	err := r.db.QueryRow(ctx,
+		`INSERT INTO transfer_equivalencies (institution, external_course_code, york_course_code, confidence, notes)
+		 VALUES ($1, $2, $3, $4, $5)
+		 ON CONFLICT (institution, external_course_code, york_course_code) DO UPDATE
+		 SET confidence = EXCLUDED.confidence, notes = EXCLUDED.notes, updated_at = NOW()
+		 RETURNING id, created_at, updated_at`,
+		eq.Institution, eq.ExternalCourseCode, eq.YorkCourseCode, eq.Confidence, eq.Notes,
+	).Scan(&eq.ID, &eq.CreatedAt, &eq.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("upsert equivalency: %w", err)
+	}
+	return nil
+}
+
+// DeleteEquivalency removes a mapping and reports whether it existed.
+func (r *TransferRepository) DeleteEquivalency(ctx context.Context, id string) (bool, error) {
+	ctx, cancel := withDeadline(ctx, opWrite)
+	defer cancel()
+
+	tag, err := r.db.Exec(ctx, `DELETE FROM transfer_equivalencies WHERE id = $1`, id)
+	if err != nil {
+		return false, fmt.Errorf("delete equivalency: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *TransferRepository) query(ctx context.Context, sql string, args ...any) ([]models.Equivalency, error) {
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query equivalencies: %w", err)
+	}
+	defer rows.Close()
+
+	equivalencies := []models.Equivalency{}
+	for rows.Next() {
+		var eq models.Equivalency
+		if err := rows.Scan(
+			&eq.ID,
+			&eq.Institution,
+			&eq.ExternalCourseCode,
+			&eq.YorkCourseCode,
+			&eq.Confidence,
+			&eq.Notes,
+			&eq.CreatedAt,
+			&eq.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan equivalency: %w", err)
+		}
+		equivalencies = append(equivalencies, eq)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate equivalencies: %w", err)
+	}
+	return equivalencies, nil
+}
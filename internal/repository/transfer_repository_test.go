@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"yuplan/internal/dbtypes"
+	"yuplan/internal/models"
+
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var equivalencyRowColumns = []string{
+	"id", "institution", "external_course_code", "york_course_code", "confidence", "notes", "created_at", "updated_at",
+}
+
+func TestTransferRepository_FindEquivalencies(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewTransferRepository(mock)
+	now := time.Now()
+	notes := "Assessed 2025"
+
+	mock.ExpectQuery("SELECT (.+) FROM transfer_equivalencies WHERE institution = \\$1 AND external_course_code = ANY\\(\\$2\\)").
+		WithArgs("seneca polytechnic", []string{"IPC144", "EAC150"}).
+		WillReturnRows(pgxmock.NewRows(equivalencyRowColumns).
+			AddRow("eq-1", "seneca polytechnic", "IPC144", "EECS1012", "high", &notes, now, now).
+			AddRow("eq-2", "seneca polytechnic", "IPC144", "EECS1015", "low", nil, now, now))
+
+	equivalencies, err := repo.FindEquivalencies(context.Background(), "seneca polytechnic", []string{"IPC144", "EAC150"})
+	assert.NoError(t, err)
+	assert.Len(t, equivalencies, 2)
+	assert.Equal(t, "EECS1012", equivalencies[0].YorkCourseCode)
+	assert.Equal(t, dbtypes.NewNullString(notes), equivalencies[0].Notes)
+	assert.False(t, equivalencies[1].Notes.Valid)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransferRepository_ListEquivalencies(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewTransferRepository(mock)
+
+	mock.ExpectQuery("SELECT (.+) FROM transfer_equivalencies WHERE \\$1 = '' OR institution = \\$1").
+		WithArgs("").
+		WillReturnRows(pgxmock.NewRows(equivalencyRowColumns))
+
+	equivalencies, err := repo.ListEquivalencies(context.Background(), "")
+	assert.NoError(t, err)
+	assert.NotNil(t, equivalencies)
+	assert.Empty(t, equivalencies)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransferRepository_QueryError(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewTransferRepository(mock)
+	mock.ExpectQuery("SELECT (.+) FROM transfer_equivalencies").WillReturnError(errors.New("db down"))
+
+	_, err = repo.ListEquivalencies(context.Background(), "")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "query equivalencies")
+}
+
+func TestTransferRepository_UpsertEquivalency(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewTransferRepository(mock)
+	now := time.Now()
+	eq := &models.Equivalency{
+		Institution:        "seneca polytechnic",
+		ExternalCourseCode: "IPC144",
+		YorkCourseCode:     "EECS1012",
+		Confidence:         models.EquivalencyConfidenceHigh,
+	}
+
+	mock.ExpectQuery("INSERT INTO transfer_equivalencies(.+)ON CONFLICT").
+		WithArgs(eq.Institution, eq.ExternalCourseCode, eq.YorkCourseCode, eq.Confidence, eq.Notes).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow("eq-1", now, now))
+
+	assert.NoError(t, repo.UpsertEquivalency(context.Background(), eq))
+	assert.Equal(t, "eq-1", eq.ID)
+	assert.Equal(t, now, eq.UpdatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransferRepository_DeleteEquivalency(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mock.Close()
+
+	repo := NewTransferRepository(mock)
+
+	mock.ExpectExec("DELETE FROM transfer_equivalencies WHERE id = \\$1").
+		WithArgs("eq-1").
+		WillReturnResult(pgxmock.NewResult("DELETE", 1))
+	mock.ExpectExec("DELETE FROM transfer_equivalencies WHERE id = \\$1").
+		WithArgs("missing").
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+
+	deleted, err := repo.DeleteEquivalency(context.Background(), "eq-1")
+	assert.NoError(t, err)
+	assert.True(t, deleted)
+
+	deleted, err = repo.DeleteEquivalency(context.Background(), "missing")
+	assert.NoError(t, err)
+	assert.False(t, deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -25,6 +25,9 @@ func NewTutorialRepository(db tutorialDB) *TutorialRepository {
 }
 
 func (r *TutorialRepository) GetBySectionID(ctx context.Context, sectionID string) ([]models.Tutorial, error) {
+	ctx, cancel := withDeadline(ctx, opRead)
+	defer cancel()
+
 	rows, err := r.db.Query(
 		ctx,
 		`SELECT id, section_id, catalog_number, times, created_at, updated_at
@@ -0,0 +1,153 @@
+// Package retention deletes data once it is older than its policy allows.
+package retention
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+	"yuplan/internal/jobs"
+	"yuplan/internal/models"
+)
+
+// Store counts and deletes a policy's expired rows. Implemented by repository.RetentionRepository.
+type Store interface {
+	CountExpired(ctx context.Context, policy string, before time.Time) (int64, error)
+	PurgeExpired(ctx context.Context, policy string, before time.Time) (int64, error)
+}
+
+// jobLocker keeps scheduled runs to one instance at a time, once per tick. Implemented by jobs.Locker.
+type jobLocker interface {
+	Do(ctx context.Context, job string, tick time.Time, fn func(ctx context.Context) error) (bool, error)
+}
+
+// Purger applies every retention policy in turn.
+type Purger struct {
+	store    Store
+	policies []models.RetentionPolicy
+	dryRun   bool
+	locker   jobLocker
+	now      func() time.Time
+
+	mu    sync.Mutex
+	stats map[string]*models.RetentionStats
+}
+
+// NewPurger applies policies in the order given; policies with no MaxAge are skipped.
+func NewPurger(store Store, policies []models.RetentionPolicy) *Purger {
+	stats := make(map[string]*models.RetentionStats, len(policies))
+	for _, p := range policies {
+		stats[p.Name] = &models.RetentionStats{Policy: p.Name, MaxAgeDays: int(p.MaxAge / (24 * time.Hour))}
+	}
+	return &Purger{store: store, policies: policies, now: time.Now, stats: stats}
+}
+
+// WithDryRun makes scheduled runs only count what they would delete.
+func (p *Purger) WithDryRun(dryRun bool) *Purger {
+	p.dryRun = dryRun
+	return p
+}
+
+// WithLocker makes Start skip runs while another instance holds the retention lock.
+func (p *Purger) WithLocker(locker jobLocker) *Purger {
+	p.locker = locker
+	return p
+}
+
+// DryRun reports whether scheduled runs leave data in place.
+func (p *Purger) DryRun() bool {
+	return p.dryRun
+}
+
+// Run applies every policy and returns their stats afterwards. With dryRun
+// the expired rows are counted but kept. A failing policy doesn't stop the
+// others; their errors are joined.
+func (p *Purger) Run(ctx context.Context, dryRun bool) ([]models.RetentionStats, error) {
+	now := p.now().UTC()
+	var errs []error
+	for _, policy := range p.policies {
+		if policy.MaxAge <= 0 {
+			continue
+		}
+		before := now.Add(-policy.MaxAge)
+
+		var n int64
+		var err error
+		if dryRun {
+			n, err = p.store.CountExpired(ctx, policy.Name, before)
+		} else {
+			n, err = p.store.PurgeExpired(ctx, policy.Name, before)
+		}
+		p.record(policy.Name, now, dryRun, n, err)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("retention policy %s: %w", policy.Name, err))
+		}
+	}
+	return p.Stats(), errors.Join(errs...)
+}
+
+func (p *Purger) record(policy string, at time.Time, dryRun bool, n int64, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	s := p.stats[policy]
+	s.Runs++
+	s.LastRun = at
+	s.LastDryRun = dryRun
+	s.LastError = ""
+	if err != nil {
+		s.Errors++
+		s.LastError = err.Error()
+		return
+	}
+	s.LastMatched = n
+	if !dryRun {
+		s.Purged += n
+	}
+}
+
+// Stats returns a snapshot of every policy's counters in policy order.
+func (p *Purger) Stats() []models.RetentionStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	out := make([]models.RetentionStats, 0, len(p.policies))
+	for _, policy := range p.policies {
+		out = append(out, *p.stats[policy.Name])
+	}
+	return out
+}
+
+// Start purges immediately and then every interval until ctx is done.
+func (p *Purger) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := p.runScheduled(ctx, jobs.Tick(time.Now(), interval)); err != nil {
+				log.Printf("retention purge failed: %v", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (p *Purger) runScheduled(ctx context.Context, tick time.Time) error {
+	run := func(ctx context.Context) error {
+		_, err := p.Run(ctx, p.dryRun)
+		return err
+	}
+	if p.locker == nil {
+		return run(ctx)
+	}
+	_, err := p.locker.Do(ctx, "retention", tick, run)
+	return err
+}
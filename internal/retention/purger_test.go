@@ -0,0 +1,130 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeStore struct {
+	expired map[string]int64
+	failing map[string]error
+	before  map[string]time.Time
+	purged  []string
+}
+
+func (f *fakeStore) CountExpired(ctx context.Context, policy string, before time.Time) (int64, error) {
+	f.before[policy] = before
+	return f.expired[policy], f.failing[policy]
+}
+
+func (f *fakeStore) PurgeExpired(ctx context.Context, policy string, before time.Time) (int64, error) {
+	f.before[policy] = before
+	if err := f.failing[policy]; err != nil {
+		return 0, err
+	}
+	f.purged = append(f.purged, policy)
+	n := f.expired[policy]
+	f.expired[policy] = 0
+	return n, nil
+}
+
+type fakeLocker struct {
+	held bool
+}
+
+func (f *fakeLocker) Do(ctx context.Context, job string, tick time.Time, fn func(ctx context.Context) error) (bool, error) {
+	if f.held {
+		return false, nil
+	}
+	return true, fn(ctx)
+}
+
+func newStore() *fakeStore {
+	return &fakeStore{
+		expired: map[string]int64{models.RetentionSearchStats: 40, models.RetentionResolvedQuarantine: 3},
+		failing: map[string]error{},
+		before:  map[string]time.Time{},
+	}
+}
+
+var testPolicies = []models.RetentionPolicy{
+	{Name: models.RetentionSearchStats, MaxAge: 365 * 24 * time.Hour},
+	{Name: models.RetentionResolvedQuarantine, MaxAge: 90 * 24 * time.Hour},
+}
+
+func TestRun_Purges(t *testing.T) {
+	store := newStore()
+	p := NewPurger(store, testPolicies)
+	now := time.Date(2026, 10, 16, 3, 0, 0, 0, time.UTC)
+	p.now = func() time.Time { return now }
+
+	stats, err := p.Run(context.Background(), false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{models.RetentionSearchStats, models.RetentionResolvedQuarantine}, store.purged)
+	assert.Equal(t, now.AddDate(0, 0, -365), store.before[models.RetentionSearchStats])
+	assert.Equal(t, now.AddDate(0, 0, -90), store.before[models.RetentionResolvedQuarantine])
+	assert.Equal(t, models.RetentionStats{
+		Policy: models.RetentionSearchStats, MaxAgeDays: 365, Runs: 1, Purged: 40, LastRun: now, LastMatched: 40,
+	}, stats[0])
+	assert.Equal(t, int64(3), stats[1].Purged)
+
+	stats, _ = p.Run(context.Background(), false)
+	assert.Equal(t, int64(2), stats[0].Runs)
+	assert.Equal(t, int64(0), stats[0].LastMatched)
+	assert.Equal(t, int64(40), stats[0].Purged, "purged accumulates across runs")
+}
+
+func TestRun_DryRunOnlyCounts(t *testing.T) {
+	store := newStore()
+	stats, err := NewPurger(store, testPolicies).Run(context.Background(), true)
+
+	assert.NoError(t, err)
+	assert.Empty(t, store.purged)
+	assert.Equal(t, int64(40), stats[0].LastMatched)
+	assert.Equal(t, int64(0), stats[0].Purged)
+	assert.True(t, stats[0].LastDryRun)
+}
+
+func TestRun_SkipsPoliciesWithoutMaxAge(t *testing.T) {
+	store := newStore()
+	stats, err := NewPurger(store, []models.RetentionPolicy{
+		{Name: models.RetentionSearchStats},
+		{Name: models.RetentionResolvedQuarantine, MaxAge: time.Hour},
+	}).Run(context.Background(), false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{models.RetentionResolvedQuarantine}, store.purged)
+	assert.Equal(t, int64(0), stats[0].Runs)
+}
+
+func TestRun_FailingPolicyDoesNotStopOthers(t *testing.T) {
+	store := newStore()
+	store.failing[models.RetentionSearchStats] = errors.New("statement timeout")
+
+	stats, err := NewPurger(store, testPolicies).Run(context.Background(), false)
+
+	assert.ErrorContains(t, err, "retention policy search_stats: statement timeout")
+	assert.Equal(t, []string{models.RetentionResolvedQuarantine}, store.purged)
+	assert.Equal(t, int64(1), stats[0].Errors)
+	assert.Equal(t, "statement timeout", stats[0].LastError)
+	assert.Equal(t, int64(3), stats[1].Purged)
+}
+
+func TestRunScheduled_UsesDryRunAndLock(t *testing.T) {
+	store := newStore()
+	p := NewPurger(store, testPolicies).WithDryRun(true).WithLocker(&fakeLocker{})
+
+	assert.NoError(t, p.runScheduled(context.Background(), time.Now()))
+	assert.Empty(t, store.purged)
+	assert.Equal(t, int64(1), p.Stats()[0].Runs)
+
+	p.WithLocker(&fakeLocker{held: true})
+	assert.NoError(t, p.runScheduled(context.Background(), time.Now()))
+	assert.Equal(t, int64(1), p.Stats()[0].Runs, "skipped while another instance holds the lock")
+}
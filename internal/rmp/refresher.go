@@ -0,0 +1,114 @@
+package rmp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+	"yuplan/internal/jobs"
+	"yuplan/internal/models"
+)
+
+// MaxAge is how long a rating is served before it is fetched again.
+const MaxAge = 7 * 24 * time.Hour
+
+// batchSize caps the lookups one run makes, so runs stay short and
+// RateMyProfessors isn't flooded; the rest wait for the next run.
+const batchSize = 200
+
+// Store lists instructors whose ratings are due and saves what was fetched.
+// Implemented by repository.InstructorRatingRepository.
+type Store interface {
+	ListStale(ctx context.Context, before time.Time, limit int) ([]models.InstructorName, error)
+	SaveRating(ctx context.Context, name models.InstructorName, rating *models.InstructorRating) error
+}
+
+// Source finds an instructor's rating. Implemented by Client.
+type Source interface {
+	Lookup(ctx context.Context, name models.InstructorName) (*models.InstructorRating, error)
+}
+
+// jobLocker keeps scheduled runs to one instance at a time, once per tick. Implemented by jobs.Locker.
+type jobLocker interface {
+	Do(ctx context.Context, job string, tick time.Time, fn func(ctx context.Context) error) (bool, error)
+}
+
+// Refresher fetches the ratings of instructors never looked up, then of those
+// looked up longest ago.
+type Refresher struct {
+	store  Store
+	source Source
+	pause  time.Duration // between lookups
+	locker jobLocker
+}
+
+func NewRefresher(store Store, source Source) *Refresher {
+	return &Refresher{store: store, source: source, pause: 250 * time.Millisecond}
+}
+
+// WithLocker makes Start skip runs while another instance holds the refresh lock.
+func (r *Refresher) WithLocker(locker jobLocker) *Refresher {
+	r.locker = locker
+	return r
+}
+
+// Run fetches one batch of due ratings and returns how many were saved. It
+// stops at the first failed lookup, keeping the ratings saved before it.
+func (r *Refresher) Run(ctx context.Context) (int, error) {
+	names, err := r.store.ListStale(ctx, time.Now().UTC().Add(-MaxAge), batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	saved := 0
+	for i, name := range names {
+		if i > 0 && r.pause > 0 {
+			select {
+			case <-ctx.Done():
+				return saved, ctx.Err()
+			case <-time.After(r.pause):
+			}
+		}
+		rating, err := r.source.Lookup(ctx, name)
+		if err != nil {
+			return saved, fmt.Errorf("look up %s %s: %w", name.FirstName, name.LastName, err)
+		}
+		if err := r.store.SaveRating(ctx, name, rating); err != nil {
+			return saved, err
+		}
+		saved++
+	}
+	return saved, nil
+}
+
+// Start refreshes immediately and then every interval until ctx is done.
+func (r *Refresher) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := r.runScheduled(ctx, jobs.Tick(time.Now(), interval)); err != nil {
+				log.Printf("rmp rating refresh failed: %v", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (r *Refresher) runScheduled(ctx context.Context, tick time.Time) error {
+	run := func(ctx context.Context) error {
+		_, err := r.Run(ctx)
+		return err
+	}
+	if r.locker == nil {
+		return run(ctx)
+	}
+	_, err := r.locker.Do(ctx, "rmp_refresh", tick, run)
+	return err
+}
@@ -0,0 +1,97 @@
+package rmp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeStore struct {
+	stale  []models.InstructorName
+	before time.Time
+	saved  map[models.InstructorName]*models.InstructorRating
+}
+
+func (f *fakeStore) ListStale(ctx context.Context, before time.Time, limit int) ([]models.InstructorName, error) {
+	f.before = before
+	return f.stale, nil
+}
+
+func (f *fakeStore) SaveRating(ctx context.Context, name models.InstructorName, rating *models.InstructorRating) error {
+	if f.saved == nil {
+		f.saved = map[models.InstructorName]*models.InstructorRating{}
+	}
+	f.saved[name] = rating
+	return nil
+}
+
+type fakeSource struct {
+	ratings map[string]*models.InstructorRating // by last name
+	fail    string
+}
+
+func (f *fakeSource) Lookup(ctx context.Context, name models.InstructorName) (*models.InstructorRating, error) {
+	if name.LastName == f.fail {
+		return nil, errors.New("rmp down")
+	}
+	return f.ratings[name.LastName], nil
+}
+
+type fakeLocker struct {
+	held bool
+}
+
+func (f *fakeLocker) Do(ctx context.Context, job string, tick time.Time, fn func(ctx context.Context) error) (bool, error) {
+	if f.held {
+		return false, nil
+	}
+	return true, fn(ctx)
+}
+
+var (
+	wang   = models.InstructorName{FirstName: "Jackie", LastName: "Wang"}
+	okafor = models.InstructorName{FirstName: "Sam", LastName: "Okafor"}
+)
+
+func TestRefresher_Run(t *testing.T) {
+	store := &fakeStore{stale: []models.InstructorName{wang, okafor}}
+	source := &fakeSource{ratings: map[string]*models.InstructorRating{"Wang": {RMPID: "222"}}}
+	refresher := NewRefresher(store, source)
+	refresher.pause = 0
+
+	n, err := refresher.Run(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, "222", store.saved[wang].RMPID)
+	assert.Contains(t, store.saved, okafor, "no match is saved too")
+	assert.Nil(t, store.saved[okafor])
+	assert.WithinDuration(t, time.Now().Add(-MaxAge), store.before, time.Minute)
+}
+
+func TestRefresher_RunStopsAtFailedLookup(t *testing.T) {
+	store := &fakeStore{stale: []models.InstructorName{wang, okafor}}
+	refresher := NewRefresher(store, &fakeSource{fail: "Okafor"})
+	refresher.pause = 0
+
+	n, err := refresher.Run(context.Background())
+	assert.ErrorContains(t, err, "look up Sam Okafor: rmp down")
+	assert.Equal(t, 1, n)
+	assert.Contains(t, store.saved, wang)
+}
+
+func TestRefresher_ScheduledRunsRespectLocker(t *testing.T) {
+	store := &fakeStore{stale: []models.InstructorName{wang}}
+	locker := &fakeLocker{held: true}
+	refresher := NewRefresher(store, &fakeSource{}).WithLocker(locker)
+
+	assert.NoError(t, refresher.runScheduled(context.Background(), time.Now()))
+	assert.Nil(t, store.saved)
+
+	locker.held = false
+	assert.NoError(t, refresher.runScheduled(context.Background(), time.Now()))
+	assert.Len(t, store.saved, 1)
+}
@@ -0,0 +1,178 @@
+// Package rmp looks instructors up on RateMyProfessors and keeps their
+// ratings fresh in instructor_ratings. RateMyProfessors has no documented
+// API; this uses the GraphQL endpoint its own site searches with.
+package rmp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"yuplan/internal/models"
+)
+
+// DefaultURL is the GraphQL endpoint RMP_URL defaults to.
+const DefaultURL = "https://www.ratemyprofessors.com/graphql"
+
+// authorization is the fixed credential RateMyProfessors' own pages send.
+const authorization = "Basic dGVzdDp0ZXN0"
+
+// searchQuery finds teachers at one school by name. RateMyProfessors puts
+// -1 in wouldTakeAgainPercent when nobody has answered.
+const searchQuery = `query TeacherSearch($text: String!, $schoolID: ID!) {
+  newSearch {
+    teachers(query: {text: $text, schoolID: $schoolID}, first: 10) {
+      edges {
+        node {
+          legacyId
+          firstName
+          lastName
+          avgRating
+          avgDifficulty
+          wouldTakeAgainPercent
+          numRatings
+        }
+      }
+    }
+  }
+}`
+
+// Client searches one school's professors.
+type Client struct {
+	url      string
+	schoolID string
+	client   *http.Client
+}
+
+// NewClient searches the school with the given RateMyProfessors school ID,
+// the base64 GraphQL id such as York University's "U2Nob29sLTE0OTU=".
+func NewClient(url, schoolID string) *Client {
+	return &Client{
+		url:      url,
+		schoolID: schoolID,
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type searchRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables"`
+}
+
+type teacher struct {
+	LegacyID              int     `json:"legacyId"`
+	FirstName             string  `json:"firstName"`
+	LastName              string  `json:"lastName"`
+	AvgRating             float64 `json:"avgRating"`
+	AvgDifficulty         float64 `json:"avgDifficulty"`
+	WouldTakeAgainPercent float64 `json:"wouldTakeAgainPercent"`
+	NumRatings            int     `json:"numRatings"`
+}
+
+type searchResponse struct {
+	Data struct {
+		NewSearch struct {
+			Teachers struct {
+				Edges []struct {
+					Node teacher `json:"node"`
+				} `json:"edges"`
+			} `json:"teachers"`
+		} `json:"newSearch"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// Lookup returns the rating of the professor at the school with the given
+// name, or nil if there is no such professor. When several match, the one
+// with the most ratings wins.
+func (c *Client) Lookup(ctx context.Context, name models.InstructorName) (*models.InstructorRating, error) {
+	body, err := json.Marshal(searchRequest{
+		Query:     searchQuery,
+		Variables: map[string]any{"text": name.FirstName + " " + name.LastName, "schoolID": c.schoolID},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("encode rmp search: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("build rmp search: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", authorization)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("rmp search: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("rmp search: status %d", resp.StatusCode)
+	}
+
+	var result searchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode rmp search: %w", err)
+	}
+	if len(result.Errors) > 0 {
+		return nil, fmt.Errorf("rmp search: %s", result.Errors[0].Message)
+	}
+
+	var best *teacher
+	for _, edge := range result.Data.NewSearch.Teachers.Edges {
+		t := edge.Node
+		if sameName(name, t) && (best == nil || t.NumRatings > best.NumRatings) {
+			best = &t
+		}
+	}
+	if best == nil {
+		return nil, nil
+	}
+	return ratingOf(*best), nil
+}
+
+// sameName matches last names exactly, ignoring case, and first names by
+// their first word, since either side may carry a middle name.
+func sameName(name models.InstructorName, t teacher) bool {
+	if !strings.EqualFold(strings.TrimSpace(name.LastName), strings.TrimSpace(t.LastName)) {
+		return false
+	}
+	return strings.EqualFold(firstWord(name.FirstName), firstWord(t.FirstName))
+}
+
+func firstWord(s string) string {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+// ratingOf leaves out averages a professor with no ratings only has as zeros.
+func ratingOf(t teacher) *models.InstructorRating {
+	rating := &models.InstructorRating{
+		RMPID:      strconv.Itoa(t.LegacyID),
+		NumRatings: t.NumRatings,
+	}
+	rating.URL = models.RMPProfessorURL + rating.RMPID
+	if t.NumRatings == 0 {
+		return rating
+	}
+	avg, difficulty := round1(t.AvgRating), round1(t.AvgDifficulty)
+	rating.Rating, rating.Difficulty = &avg, &difficulty
+	if t.WouldTakeAgainPercent >= 0 {
+		percent := int(math.Round(t.WouldTakeAgainPercent))
+		rating.WouldTakeAgain = &percent
+	}
+	return rating
+}
+
+func round1(f float64) float64 {
+	return math.Round(f*10) / 10
+}
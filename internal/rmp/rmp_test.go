@@ -0,0 +1,76 @@
+package rmp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"yuplan/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func rmpServer(t *testing.T, status int, body string) (*httptest.Server, *searchRequest) {
+	var got searchRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, authorization, r.Header.Get("Authorization"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server, &got
+}
+
+func TestClient_Lookup(t *testing.T) {
+	server, got := rmpServer(t, http.StatusOK, `{"data": {"newSearch": {"teachers": {"edges": [
+		{"node": {"legacyId": 111, "firstName": "Jackie", "lastName": "Wangsa", "avgRating": 2.0, "avgDifficulty": 2.0, "wouldTakeAgainPercent": 10, "numRatings": 90}},
+		{"node": {"legacyId": 222, "firstName": "Jackie", "lastName": "Wang", "avgRating": 4.23, "avgDifficulty": 3.07, "wouldTakeAgainPercent": 86.6, "numRatings": 41}},
+		{"node": {"legacyId": 333, "firstName": "Jackie S.", "lastName": "WANG", "avgRating": 3.0, "avgDifficulty": 3.0, "wouldTakeAgainPercent": -1, "numRatings": 2}}
+	]}}}}`)
+
+	rating, err := NewClient(server.URL, "U2Nob29sLTE0OTU=").Lookup(context.Background(), models.InstructorName{FirstName: "Jackie", LastName: "Wang"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Jackie Wang", got.Variables["text"])
+	assert.Equal(t, "U2Nob29sLTE0OTU=", got.Variables["schoolID"])
+	if assert.NotNil(t, rating) {
+		assert.Equal(t, "222", rating.RMPID, "the matching name with the most ratings")
+		assert.Equal(t, models.RMPProfessorURL+"222", rating.URL)
+		assert.Equal(t, 4.2, *rating.Rating)
+		assert.Equal(t, 3.1, *rating.Difficulty)
+		assert.Equal(t, 87, *rating.WouldTakeAgain)
+		assert.Equal(t, 41, rating.NumRatings)
+	}
+}
+
+func TestClient_LookupNoRatings(t *testing.T) {
+	server, _ := rmpServer(t, http.StatusOK, `{"data": {"newSearch": {"teachers": {"edges": [
+		{"node": {"legacyId": 444, "firstName": "Sam", "lastName": "Okafor", "avgRating": 0, "avgDifficulty": 0, "wouldTakeAgainPercent": -1, "numRatings": 0}}
+	]}}}}`)
+
+	rating, err := NewClient(server.URL, "school").Lookup(context.Background(), models.InstructorName{FirstName: "Sam", LastName: "Okafor"})
+	assert.NoError(t, err)
+	if assert.NotNil(t, rating) {
+		assert.Equal(t, "444", rating.RMPID)
+		assert.Nil(t, rating.Rating)
+		assert.Nil(t, rating.Difficulty)
+		assert.Nil(t, rating.WouldTakeAgain)
+	}
+
+	rating, err = NewClient(server.URL, "school").Lookup(context.Background(), models.InstructorName{FirstName: "Priya", LastName: "Natarajan"})
+	assert.NoError(t, err)
+	assert.Nil(t, rating, "no professor by that name")
+}
+
+func TestClient_LookupErrors(t *testing.T) {
+	name := models.InstructorName{FirstName: "Jackie", LastName: "Wang"}
+
+	server, _ := rmpServer(t, http.StatusUnauthorized, `{}`)
+	_, err := NewClient(server.URL, "school").Lookup(context.Background(), name)
+	assert.ErrorContains(t, err, "status 401")
+
+	server, _ = rmpServer(t, http.StatusOK, `{"errors": [{"message": "Variable \"$schoolID\" got invalid value"}]}`)
+	_, err = NewClient(server.URL, "school").Lookup(context.Background(), name)
+	assert.ErrorContains(t, err, "got invalid value")
+}
@@ -0,0 +1,482 @@
+// Package schema checks the live database against the tables and columns the
+// code expects, so a deploy whose migrations weren't applied is caught at
+// startup instead of as query errors later.
+package schema
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// Expected is every table the migrations create, with each column's type as
+// Postgres reports it in information_schema.columns.udt_name. Keep it in step
+// with migrations/; TestExpectedMatchesMigrations replays them to check.
+var Expected = map[string]map[string]string{
+	"_seed_checksum": {
+		"cache_invalidated": "bool",
+		"checksum":          "text",
+	},
+	"academic_terms": {
+		"academic_year":   "int4",
+		"term":            "varchar",
+		"enroll_deadline": "timestamp",
+		"drop_deadline":   "timestamp",
+		"exams_start":     "timestamp",
+		"exams_end":       "timestamp",
+		"grades_released": "timestamp",
+		"updated_at":      "timestamp",
+	},
+	"anonymous_sessions": {
+		"id":         "uuid",
+		"started_at": "timestamp",
+	},
+	"audit_log": {
+		"id":         "int8",
+		"entity":     "varchar",
+		"entity_id":  "text",
+		"action":     "varchar",
+		"details":    "jsonb",
+		"created_at": "timestamp",
+	},
+	"badges": {
+		"slug":        "varchar",
+		"name":        "varchar",
+		"description": "text",
+		"metric":      "varchar",
+		"threshold":   "int4",
+		"created_at":  "timestamp",
+	},
+	"block_activities": {
+		"block_id":    "uuid",
+		"activity_id": "uuid",
+	},
+	"blocks": {
+		"id":         "uuid",
+		"course_id":  "uuid",
+		"name":       "varchar",
+		"created_at": "timestamp",
+		"updated_at": "timestamp",
+	},
+	"catalog_changes": {
+		"id":          "int8",
+		"department":  "varchar",
+		"kind":        "varchar",
+		"course_code": "varchar",
+		"term":        "varchar",
+		"section":     "varchar",
+		"before":      "text",
+		"after":       "text",
+		"detected_at": "timestamp",
+	},
+	"catalog_snapshot": {
+		"course_code": "varchar",
+		"term":        "varchar",
+		"letter":      "varchar",
+		"instructors": "text",
+	},
+	"catalog_snapshot_state": {
+		"checksum": "text",
+		"taken_at": "timestamp",
+	},
+	"course_data_quality": {
+		"scored_at":           "timestamp",
+		"course_code":         "varchar",
+		"term":                "varchar",
+		"score":               "int4",
+		"missing_description": "bool",
+		"no_sections":         "bool",
+		"unparsed_times":      "bool",
+		"missing_instructors": "bool",
+	},
+	"course_offering_summaries": {
+		"code":              "varchar",
+		"last_offered_year": "int4",
+		"last_offered_term": "varchar",
+		"frequency":         "varchar",
+		"terms":             "_text",
+		"years_observed":    "int4",
+		"updated_at":        "timestamp",
+	},
+	"course_offerings": {
+		"code":          "varchar",
+		"academic_year": "int4",
+		"term":          "varchar",
+		"recorded_at":   "timestamp",
+	},
+	"course_requisites": {
+		"course_code":    "varchar",
+		"kind":           "varchar",
+		"group_no":       "int4",
+		"requisite_code": "varchar",
+	},
+	"course_views": {
+		"email":       "varchar",
+		"course_code": "varchar",
+		"seen_at":     "timestamp",
+	},
+	"courses": {
+		"id":            "uuid",
+		"name":          "varchar",
+		"code":          "varchar",
+		"credits":       "numeric",
+		"description":   "text",
+		"faculty":       "varchar",
+		"term":          "varchar",
+		"created_at":    "timestamp",
+		"updated_at":    "timestamp",
+		"search_vector": "tsvector",
+	},
+	"department_subscriptions": {
+		"email":      "varchar",
+		"department": "varchar",
+		"created_at": "timestamp",
+	},
+	"difficulty_calibration": {
+		"department": "varchar",
+		"mean":       "float8",
+		"stddev":     "float8",
+		"courses":    "int4",
+		"updated_at": "timestamp",
+	},
+	"digest_subscribers": {
+		"email":        "varchar",
+		"frequency":    "varchar",
+		"last_sent_at": "timestamp",
+		"created_at":   "timestamp",
+	},
+	"enrollment_snapshots": {
+		"id":          "int8",
+		"section_id":  "uuid",
+		"capacity":    "int4",
+		"enrolled":    "int4",
+		"recorded_at": "timestamp",
+	},
+	"filter_presets": {
+		"id":                "uuid",
+		"email":             "varchar",
+		"name":              "varchar",
+		"faculty":           "varchar",
+		"course_code_range": "varchar",
+		"term_id":           "varchar",
+		"created_at":        "timestamp",
+		"updated_at":        "timestamp",
+	},
+	"instructor_claims": {
+		"id":          "uuid",
+		"first_name":  "varchar",
+		"last_name":   "varchar",
+		"email":       "varchar",
+		"status":      "varchar",
+		"token_hash":  "varchar",
+		"expires_at":  "timestamp",
+		"created_at":  "timestamp",
+		"verified_at": "timestamp",
+		"decided_at":  "timestamp",
+	},
+	"instructor_ratings": {
+		"first_name":       "varchar",
+		"last_name":        "varchar",
+		"rmp_id":           "varchar",
+		"rating":           "numeric",
+		"difficulty":       "numeric",
+		"would_take_again": "int4",
+		"num_ratings":      "int4",
+		"fetched_at":       "timestamp",
+	},
+	"instructor_reviews": {
+		"id":          "uuid",
+		"first_name":  "varchar",
+		"last_name":   "varchar",
+		"email":       "varchar",
+		"author_name": "varchar",
+		"clarity":     "int4",
+		"helpfulness": "int4",
+		"workload":    "int4",
+		"review_text": "text",
+		"created_at":  "timestamp",
+		"updated_at":  "timestamp",
+	},
+	"instructors": {
+		"id":                "uuid",
+		"first_name":        "varchar",
+		"last_name":         "varchar",
+		"rate_my_prof_link": "text",
+		"section_id":        "uuid",
+		"photo_id":          "varchar",
+		"created_at":        "timestamp",
+		"updated_at":        "timestamp",
+	},
+	"job_runs": {
+		"job":        "varchar",
+		"tick":       "timestamp",
+		"started_at": "timestamp",
+	},
+	"moderation_rules": {
+		"id":                  "int8",
+		"position":            "int4",
+		"name":                "varchar",
+		"action":              "varchar",
+		"enabled":             "bool",
+		"no_profanity":        "bool",
+		"email_domains":       "_text",
+		"min_author_age_days": "int4",
+		"max_text_length":     "int4",
+		"updated_at":          "timestamp",
+	},
+	"reports": {
+		"id":          "uuid",
+		"type":        "varchar",
+		"entity_type": "varchar",
+		"entity_id":   "uuid",
+		"reason":      "varchar",
+		"details":     "text",
+		"email":       "varchar",
+		"status":      "varchar",
+		"created_at":  "timestamp",
+		"resolved_at": "timestamp",
+	},
+	"review_events": {
+		"id":         "int8",
+		"review_id":  "uuid",
+		"event":      "varchar",
+		"details":    "jsonb",
+		"created_at": "timestamp",
+	},
+	"review_keywords": {
+		"course_code": "varchar",
+		"term":        "varchar",
+		"count":       "int4",
+		"updated_at":  "timestamp",
+	},
+	"review_revisions": {
+		"id":                   "int8",
+		"review_id":            "uuid",
+		"author_name":          "varchar",
+		"liked":                "bool",
+		"difficulty":           "int4",
+		"real_world_relevance": "int4",
+		"review_text":          "text",
+		"delivery_mode":        "varchar",
+		"tags":                 "_text",
+		"written_at":           "timestamp",
+		"replaced_at":          "timestamp",
+	},
+	"review_tags": {
+		"review_id": "uuid",
+		"tag":       "varchar",
+	},
+	"review_verifications": {
+		"review_id":  "uuid",
+		"token_hash": "varchar",
+		"moderation": "varchar",
+		"expires_at": "timestamp",
+		"created_at": "timestamp",
+	},
+	"review_votes": {
+		"review_id":  "uuid",
+		"email":      "varchar",
+		"helpful":    "bool",
+		"created_at": "timestamp",
+		"updated_at": "timestamp",
+	},
+	"reviewer_badges": {
+		"email":      "varchar",
+		"badge":      "varchar",
+		"awarded_at": "timestamp",
+	},
+	"reviews": {
+		"id":                   "uuid",
+		"course_code":          "varchar",
+		"email":                "varchar",
+		"author_name":          "varchar",
+		"liked":                "bool",
+		"difficulty":           "int4",
+		"real_world_relevance": "int4",
+		"review_text":          "text",
+		"publish_at":           "timestamp",
+		"delivery_mode":        "varchar",
+		"academic_year":        "int4",
+		"term":                 "varchar",
+		"created_at":           "timestamp",
+		"updated_at":           "timestamp",
+		"moderation":           "varchar",
+		"hidden_at":            "timestamp",
+		"edited_at":            "timestamp",
+	},
+	"saved_schedules": {
+		"id":              "uuid",
+		"email":           "varchar",
+		"name":            "varchar",
+		"section_ids":     "_uuid",
+		"activity_ids":    "_uuid",
+		"feed_token_hash": "varchar",
+		"created_at":      "timestamp",
+		"updated_at":      "timestamp",
+	},
+	"seat_watches": {
+		"id":          "uuid",
+		"email":       "varchar",
+		"section_id":  "uuid",
+		"created_at":  "timestamp",
+		"notified_at": "timestamp",
+	},
+	"search_stats": {
+		"query":        "text",
+		"day":          "date",
+		"searches":     "int4",
+		"zero_results": "int4",
+	},
+	"section_activities": {
+		"id":                      "uuid",
+		"course_type":             "varchar",
+		"section_id":              "uuid",
+		"catalog_number":          "varchar",
+		"times":                   "text",
+		"created_at":              "timestamp",
+		"updated_at":              "timestamp",
+		"capacity":                "int4",
+		"enrolled":                "int4",
+		"availability_updated_at": "timestamp",
+	},
+	"sections": {
+		"id":                      "uuid",
+		"course_id":               "uuid",
+		"letter":                  "varchar",
+		"term_id":                 "varchar",
+		"created_at":              "timestamp",
+		"updated_at":              "timestamp",
+		"capacity":                "int4",
+		"enrolled":                "int4",
+		"availability_updated_at": "timestamp",
+	},
+	"seed_quarantine": {
+		"id":          "uuid",
+		"source":      "varchar",
+		"record_key":  "varchar",
+		"record":      "jsonb",
+		"reasons":     "_text",
+		"status":      "varchar",
+		"created_at":  "timestamp",
+		"resolved_at": "timestamp",
+	},
+	"session_drafts": {
+		"session_id": "uuid",
+		"key":        "varchar",
+		"body":       "jsonb",
+		"updated_at": "timestamp",
+	},
+	"session_recent_views": {
+		"session_id":  "uuid",
+		"course_code": "varchar",
+		"viewed_at":   "timestamp",
+	},
+	"sync_history": {
+		"id":          "int8",
+		"term":        "varchar",
+		"status":      "varchar",
+		"started_at":  "timestamp",
+		"finished_at": "timestamp",
+		"pages":       "int4",
+		"quarantined": "int4",
+		"diff":        "jsonb",
+		"error":       "text",
+	},
+	"terms": {
+		"id":            "varchar",
+		"session":       "varchar",
+		"academic_year": "int4",
+		"starts_on":     "date",
+		"ends_on":       "date",
+	},
+	"transfer_equivalencies": {
+		"id":                   "uuid",
+		"institution":          "varchar",
+		"external_course_code": "varchar",
+		"york_course_code":     "varchar",
+		"confidence":           "varchar",
+		"notes":                "text",
+		"created_at":           "timestamp",
+		"updated_at":           "timestamp",
+	},
+}
+
+// Drift is one way the live schema differs from Expected.
+type Drift struct {
+	Table    string
+	Column   string // empty when the whole table is missing
+	Expected string // expected type; empty when the whole table is missing
+	Actual   string // live type; empty when the column is missing
+}
+
+func (d Drift) String() string {
+	switch {
+	case d.Column == "":
+		return fmt.Sprintf("table %s is missing", d.Table)
+	case d.Actual == "":
+		return fmt.Sprintf("column %s.%s (%s) is missing", d.Table, d.Column, d.Expected)
+	default:
+		return fmt.Sprintf("column %s.%s is %s, expected %s", d.Table, d.Column, d.Actual, d.Expected)
+	}
+}
+
+type schemaDB interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// Check compares the live schema with Expected and returns every difference,
+// ordered by table and column. Tables and columns the code doesn't know about
+// are ignored, so a database migrated ahead of this build passes.
+func Check(ctx context.Context, db schemaDB) ([]Drift, error) {
+	rows, err := db.Query(ctx,
+		`SELECT table_name, column_name, udt_name
+		 FROM information_schema.columns
+		 WHERE table_schema = current_schema()`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query schema: %w", err)
+	}
+	defer rows.Close()
+
+	live := map[string]map[string]string{}
+	for rows.Next() {
+		var table, column, udt string
+		if err := rows.Scan(&table, &column, &udt); err != nil {
+			return nil, fmt.Errorf("scan schema column: %w", err)
+		}
+		if live[table] == nil {
+			live[table] = map[string]string{}
+		}
+		live[table][column] = udt
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate schema: %w", err)
+	}
+
+	var drifts []Drift
+	for _, table := range sortedKeys(Expected) {
+		columns, ok := live[table]
+		if !ok {
+			drifts = append(drifts, Drift{Table: table})
+			continue
+		}
+		for _, column := range sortedKeys(Expected[table]) {
+			want := Expected[table][column]
+			if got := columns[column]; got != want {
+				drifts = append(drifts, Drift{Table: table, Column: column, Expected: want, Actual: got})
+			}
+		}
+	}
+	return drifts, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,189 @@
+package schema
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// liveRows returns information_schema rows matching Expected exactly.
+func liveRows() *pgxmock.Rows {
+	rows := pgxmock.NewRows([]string{"table_name", "column_name", "udt_name"})
+	for table, columns := range Expected {
+		for column, udt := range columns {
+			rows.AddRow(table, column, udt)
+		}
+	}
+	return rows
+}
+
+func TestCheck_NoDrift(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	rows := liveRows()
+	rows.AddRow("schema_migrations", "version", "int8") // unknown tables are ignored
+	rows.AddRow("reviews", "some_future_column", "text")
+	mock.ExpectQuery("FROM information_schema.columns").WillReturnRows(rows)
+
+	drifts, err := Check(context.Background(), mock)
+
+	require.NoError(t, err)
+	assert.Empty(t, drifts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCheck_ReportsDrift(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	rows := pgxmock.NewRows([]string{"table_name", "column_name", "udt_name"})
+	for table, columns := range Expected {
+		if table == "badges" {
+			continue
+		}
+		for column, udt := range columns {
+			switch {
+			case table == "reviews" && column == "delivery_mode":
+			case table == "courses" && column == "credits":
+				rows.AddRow(table, column, "int4")
+			default:
+				rows.AddRow(table, column, udt)
+			}
+		}
+	}
+	mock.ExpectQuery("FROM information_schema.columns").WillReturnRows(rows)
+
+	drifts, err := Check(context.Background(), mock)
+
+	require.NoError(t, err)
+	assert.Equal(t, []Drift{
+		{Table: "badges"},
+		{Table: "courses", Column: "credits", Expected: "numeric", Actual: "int4"},
+		{Table: "reviews", Column: "delivery_mode", Expected: "varchar"},
+	}, drifts)
+	assert.Equal(t, "table badges is missing", drifts[0].String())
+	assert.Equal(t, "column courses.credits is int4, expected numeric", drifts[1].String())
+	assert.Equal(t, "column reviews.delivery_mode (varchar) is missing", drifts[2].String())
+}
+
+func TestCheck_QueryError(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	mock.ExpectQuery("FROM information_schema.columns").WillReturnError(errors.New("connection refused"))
+
+	_, err = Check(context.Background(), mock)
+
+	assert.ErrorContains(t, err, "connection refused")
+}
+
+var (
+	sqlComment  = regexp.MustCompile(`--[^\n]*`)
+	createTable = regexp.MustCompile(`(?is)^CREATE TABLE (?:IF NOT EXISTS )?(\w+)\s*\((.*)\)$`)
+	addColumn   = regexp.MustCompile(`(?is)^ALTER TABLE (\w+) ADD COLUMN (?:IF NOT EXISTS )?(\w+) (\S+)`)
+	dropColumn  = regexp.MustCompile(`(?is)^ALTER TABLE (\w+) DROP COLUMN (?:IF EXISTS )?(\w+)`)
+	dropTable   = regexp.MustCompile(`(?is)^DROP TABLE (?:IF EXISTS )?(\w+)`)
+)
+
+// udtNames maps the SQL types the migrations use to information_schema udt_names.
+var udtNames = map[string]string{
+	"BIGSERIAL": "int8",
+	"BOOLEAN":   "bool",
+	"DATE":      "date",
+	"DECIMAL":   "numeric",
+	"DOUBLE":    "float8",
+	"INTEGER":   "int4",
+	"JSONB":     "jsonb",
+	"TEXT":      "text",
+	"TEXT[]":    "_text",
+	"TIMESTAMP": "timestamp",
+	"TSVECTOR":  "tsvector",
+	"UUID":      "uuid",
+	"UUID[]":    "_uuid",
+	"VARCHAR":   "varchar",
+}
+
+func udtName(t *testing.T, sqlType string) string {
+	t.Helper()
+	sqlType = strings.ToUpper(strings.TrimRight(sqlType, ","))
+	if i := strings.Index(sqlType, "("); i >= 0 {
+		sqlType = sqlType[:i]
+	}
+	udt, ok := udtNames[sqlType]
+	require.True(t, ok, "no udt_name for SQL type %q; add it to udtNames", sqlType)
+	return udt
+}
+
+// splitTopLevel splits a CREATE TABLE body on commas outside parentheses.
+func splitTopLevel(body string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, r := range body {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, body[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, body[start:])
+}
+
+// TestExpectedMatchesMigrations replays the up migrations and checks that
+// Expected describes the schema they leave behind.
+func TestExpectedMatchesMigrations(t *testing.T) {
+	files, err := filepath.Glob("../../migrations/*.up.sql")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	sort.Strings(files)
+
+	got := map[string]map[string]string{}
+	for _, file := range files {
+		raw, err := os.ReadFile(file)
+		require.NoError(t, err)
+		for _, stmt := range strings.Split(sqlComment.ReplaceAllString(string(raw), ""), ";") {
+			stmt = strings.TrimSpace(stmt)
+			if m := createTable.FindStringSubmatch(stmt); m != nil {
+				columns := map[string]string{}
+				for _, def := range splitTopLevel(m[2]) {
+					fields := strings.Fields(def)
+					if len(fields) < 2 {
+						continue
+					}
+					switch strings.ToUpper(strings.SplitN(fields[0], "(", 2)[0]) {
+					case "PRIMARY", "UNIQUE", "CHECK", "CONSTRAINT", "FOREIGN":
+						continue
+					}
+					columns[fields[0]] = udtName(t, fields[1])
+				}
+				got[m[1]] = columns
+			} else if m := addColumn.FindStringSubmatch(stmt); m != nil {
+				got[m[1]][m[2]] = udtName(t, m[3])
+			} else if m := dropColumn.FindStringSubmatch(stmt); m != nil {
+				delete(got[m[1]], m[2])
+			} else if m := dropTable.FindStringSubmatch(stmt); m != nil {
+				delete(got, m[1])
+			}
+		}
+	}
+
+	assert.Equal(t, got, Expected)
+}
@@ -0,0 +1,210 @@
+package scraper
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrNotArchived is returned when replaying a page that was never recorded.
+var ErrNotArchived = errors.New("page not in archive")
+
+// Archive is a directory of fetched timetable pages, for running the scraper
+// against the full catalog without the York Courses Website. Pages are
+// stored gzipped under pages/ by the SHA-256 of their content, so a page that
+// doesn't change between recordings is kept once; index.json maps each URL to
+// the page last recorded for it.
+//
+// Record a session with Recorder as the scraper's transport, then replay it
+// in tests and local dev with Replayer.
+type Archive struct {
+	dir string
+
+	mu    sync.Mutex
+	index map[string]ArchivedPage
+}
+
+// ArchivedPage is an index entry.
+type ArchivedPage struct {
+	SHA256    string    `json:"sha256"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+const archiveIndex = "index.json"
+
+// OpenArchive opens the archive in dir, creating the directory if needed.
+func OpenArchive(dir string) (*Archive, error) {
+	if err := os.MkdirAll(filepath.Join(dir, "pages"), 0o755); err != nil {
+		return nil, fmt.Errorf("create archive: %w", err)
+	}
+	a := &Archive{dir: dir, index: map[string]ArchivedPage{}}
+	raw, err := os.ReadFile(filepath.Join(dir, archiveIndex))
+	if errors.Is(err, os.ErrNotExist) {
+		return a, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read archive index: %w", err)
+	}
+	if err := json.Unmarshal(raw, &a.index); err != nil {
+		return nil, fmt.Errorf("decode archive index: %w", err)
+	}
+	return a, nil
+}
+
+// URLs lists the archived pages' URLs, sorted.
+func (a *Archive) URLs() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	urls := make([]string, 0, len(a.index))
+	for url := range a.index {
+		urls = append(urls, url)
+	}
+	sort.Strings(urls)
+	return urls
+}
+
+// Open returns the page archived for url, checked against its hash.
+func (a *Archive) Open(url string) (io.ReadCloser, error) {
+	a.mu.Lock()
+	page, ok := a.index[url]
+	a.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", url, ErrNotArchived)
+	}
+
+	f, err := os.Open(a.pagePath(page.SHA256))
+	if err != nil {
+		return nil, fmt.Errorf("open archived page: %w", err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("read archived page %s: %w", page.SHA256, err)
+	}
+	content, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("read archived page %s: %w", page.SHA256, err)
+	}
+	if sum := sha256.Sum256(content); hex.EncodeToString(sum[:]) != page.SHA256 {
+		return nil, fmt.Errorf("archived page %s is corrupt", page.SHA256)
+	}
+	return io.NopCloser(bytes.NewReader(content)), nil
+}
+
+// Recorder returns a transport that fetches pages through next and archives
+// every successful GET.
+func (a *Archive) Recorder(next http.RoundTripper) http.RoundTripper {
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		resp, err := next.RoundTrip(req)
+		if err != nil || req.Method != http.MethodGet || resp.StatusCode != http.StatusOK {
+			return resp, err
+		}
+		content, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", req.URL, err)
+		}
+		if err := a.store(req.URL.String(), content); err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(content))
+		return resp, nil
+	})
+}
+
+// Replayer returns a transport that answers GETs from the archive and never
+// touches the network. Pages that weren't recorded fail with ErrNotArchived.
+func (a *Archive) Replayer() http.RoundTripper {
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if req.Method != http.MethodGet {
+			return nil, fmt.Errorf("replay %s %s: only GET is archived", req.Method, req.URL)
+		}
+		body, err := a.Open(req.URL.String())
+		if err != nil {
+			return nil, err
+		}
+		return &http.Response{
+			Status:     "200 OK",
+			StatusCode: http.StatusOK,
+			Proto:      "HTTP/1.1",
+			ProtoMajor: 1,
+			ProtoMinor: 1,
+			Header:     http.Header{"Content-Type": {"text/html"}},
+			Body:       body,
+			Request:    req,
+		}, nil
+	})
+}
+
+// store writes content under its hash, unless it is already there, and points
+// url at it.
+func (a *Archive) store(url string, content []byte) error {
+	sum := sha256.Sum256(content)
+	hash := hex.EncodeToString(sum[:])
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, err := os.Stat(a.pagePath(hash)); errors.Is(err, os.ErrNotExist) {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		if _, err := zw.Write(content); err != nil {
+			return fmt.Errorf("compress page: %w", err)
+		}
+		if err := zw.Close(); err != nil {
+			return fmt.Errorf("compress page: %w", err)
+		}
+		if err := writeFileAtomic(a.pagePath(hash), buf.Bytes()); err != nil {
+			return fmt.Errorf("archive page: %w", err)
+		}
+	}
+
+	a.index[url] = ArchivedPage{SHA256: hash, FetchedAt: time.Now().UTC()}
+	raw, err := json.MarshalIndent(a.index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode archive index: %w", err)
+	}
+	if err := writeFileAtomic(filepath.Join(a.dir, archiveIndex), raw); err != nil {
+		return fmt.Errorf("write archive index: %w", err)
+	}
+	return nil
+}
+
+func (a *Archive) pagePath(hash string) string {
+	return filepath.Join(a.dir, "pages", hash+".html.gz")
+}
+
+// writeFileAtomic replaces path with data through a rename, so an interrupted
+// recording never leaves a truncated page or index behind.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,118 @@
+package scraper
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"yuplan/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchive_RecordThenReplay(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path == "/FW2025SC.html" {
+			http.NotFound(w, r)
+			return
+		}
+		io.WriteString(w, timetable)
+	}))
+
+	term, _ := models.NewTerm("FW2025")
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	dir := t.TempDir()
+
+	archive, err := OpenArchive(dir)
+	require.NoError(t, err)
+	recording := &http.Client{Transport: archive.Recorder(server.Client().Transport)}
+	_, err = New(&fakeCatalog{}, &fakeQuarantine{}, logger).
+		WithHTTPClient(recording).
+		Run(context.Background(), term, FacultyPages(server.URL, term, []string{"LE", "ES"}))
+	require.NoError(t, err)
+
+	_, err = recording.Get(server.URL + "/FW2025SC.html")
+	require.NoError(t, err)
+	server.Close()
+
+	// Both pages have the same content, so it is stored once
+	pages, err := os.ReadDir(filepath.Join(dir, "pages"))
+	require.NoError(t, err)
+	assert.Len(t, pages, 1)
+	assert.Equal(t, []string{server.URL + "/FW2025ES.html", server.URL + "/FW2025LE.html"}, archive.URLs(),
+		"failed fetches aren't archived")
+
+	replay, err := OpenArchive(dir)
+	require.NoError(t, err)
+	catalog := &fakeCatalog{}
+	summary, err := New(catalog, &fakeQuarantine{}, logger).
+		WithHTTPClient(&http.Client{Transport: replay.Replayer()}).
+		Run(context.Background(), term, FacultyPages(server.URL, term, []string{"LE", "ES"}))
+	require.NoError(t, err)
+	assert.Equal(t, 2, summary.Pages)
+	assert.NotEmpty(t, catalog.checksum)
+	assert.Equal(t, 3, requests, "replay never reaches the server")
+
+	_, err = New(&fakeCatalog{}, &fakeQuarantine{}, logger).
+		WithHTTPClient(&http.Client{Transport: replay.Replayer()}).
+		Run(context.Background(), term, FacultyPages(server.URL, term, []string{"SC"}))
+	assert.ErrorIs(t, err, ErrNotArchived)
+}
+
+func TestArchive_OpenDetectsCorruption(t *testing.T) {
+	dir := t.TempDir()
+	archive, err := OpenArchive(dir)
+	require.NoError(t, err)
+	require.NoError(t, archive.store("https://example.com/FW2025LE.html", []byte(timetable)))
+
+	body, err := archive.Open("https://example.com/FW2025LE.html")
+	require.NoError(t, err)
+	content, _ := io.ReadAll(body)
+	assert.Equal(t, timetable, string(content))
+
+	pages, _ := os.ReadDir(filepath.Join(dir, "pages"))
+	require.Len(t, pages, 1)
+	var tampered bytes.Buffer
+	zw := gzip.NewWriter(&tampered)
+	io.WriteString(zw, timetable+"<!-- edited -->")
+	require.NoError(t, zw.Close())
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pages", pages[0].Name()), tampered.Bytes(), 0o644))
+
+	_, err = archive.Open("https://example.com/FW2025LE.html")
+	assert.ErrorContains(t, err, "corrupt")
+}
+
+// TestArchive_ParsesRecordedCatalog parses every page of a recorded archive,
+// to check parser changes against the full catalog offline:
+//
+//	go run ./cmd/scraper -term FW2025 -record /tmp/fw2025
+//	SCRAPER_ARCHIVE=/tmp/fw2025 go test ./internal/scraper -run RecordedCatalog
+func TestArchive_ParsesRecordedCatalog(t *testing.T) {
+	dir := os.Getenv("SCRAPER_ARCHIVE")
+	if dir == "" {
+		t.Skip("SCRAPER_ARCHIVE not set")
+	}
+	archive, err := OpenArchive(dir)
+	require.NoError(t, err)
+	require.NotEmpty(t, archive.URLs())
+
+	for _, url := range archive.URLs() {
+		t.Run(filepath.Base(url), func(t *testing.T) {
+			body, err := archive.Open(url)
+			require.NoError(t, err)
+			defer body.Close()
+			records, err := Parse(body)
+			require.NoError(t, err)
+			assert.NotEmpty(t, records)
+		})
+	}
+}
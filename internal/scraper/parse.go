@@ -0,0 +1,351 @@
+package scraper
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"yuplan/internal/models"
+	"yuplan/internal/seedcheck"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// summaryRe reads a row's "2030  3.00 A" cell: course number, credits and
+// section letter. Only the first row of a course sets its number and credits.
+var summaryRe = regexp.MustCompile(`(\d{3,4}[A-Z]?)\s+([0-9]+\.[0-9]{2})\s*([A-Z0-9]?)`)
+
+// activityTypes maps the spellings of activity types on the timetable, with
+// everything but letters removed, to the canonical type. A cell is matched by
+// the longest spelling it contains, as scraping/scrapers/helpers/section_types.py does.
+var activityTypes = []struct{ spelling, kind string }{
+	{"LECT", "LECT"}, {"LEC", "LECT"},
+	{"LAB", "LAB"},
+	{"TUTR", "TUTR"}, {"TUT", "TUTR"},
+	{"SEMR", "SEMR"}, {"SEMINAR", "SEMR"}, {"SEM", "SEMR"},
+	{"STDO", "STDO"}, {"STUDIO", "STDO"},
+	{"BLEN", "BLEN"}, {"BLENDED", "BLEN"},
+	{"ONLN", "ONLN"}, {"ONLINE", "ONLN"}, {"ONL", "ONLN"},
+	{"ONCA", "ONCA"},
+	{"COOP", "COOP"}, {"COOPTERM", "COOP"}, {"COOPWORKTERM", "COOP"},
+	{"ISTY", "ISTY"}, {"INDEPENDENTSTUDY", "ISTY"}, {"INDSTUDY", "ISTY"},
+	{"DIRD", "DIRD"}, {"DIRECTEDSTUDY", "DIRD"},
+	{"FDEX", "FDEX"}, {"FIELDEXERCISE", "FDEX"},
+	{"FIEL", "FIEL"}, {"FIELDWORK", "FIEL"},
+	{"INSP", "INSP"}, {"INTERNSHIP", "INSP"},
+	{"RESP", "RESP"}, {"RESEARCH", "RESP"},
+	{"REEV", "REEV"}, {"RESEARCHEVALUATION", "REEV"},
+	{"THES", "THES"}, {"THESIS", "THES"},
+	{"WKSP", "WKSP"}, {"WORKSHOP", "WKSP"},
+	{"WRKS", "WRKS"}, {"WRK", "WRKS"},
+	{"PRAC", "PRAC"}, {"PRA", "PRAC"},
+	{"CLIN", "CLIN"}, {"CLINICAL", "CLIN"},
+	{"HYFX", "HYFX"}, {"HYBRIDFLEX", "HYFX"},
+	{"CORS", "CORS"}, {"CORRESPONDENCE", "CORS"},
+	{"DISS", "DISS"}, {"DISSERTATION", "DISS"},
+	{"LGCL", "LGCL"}, {"LANGUAGECLASSES", "LGCL"},
+	{"PERF", "PERF"}, {"PERFORMANCE", "PERF"},
+	{"REMT", "REMT"}, {"REMOTE", "REMT"},
+	{"REVP", "REVP"}, {"REVIEWPAPER", "REVP"},
+	{"IDS", "IDS"}, {"INDIVIDUALDIRECTEDSTUDY", "IDS"},
+}
+
+func init() {
+	sort.SliceStable(activityTypes, func(i, j int) bool {
+		return len(activityTypes[i].spelling) > len(activityTypes[j].spelling)
+	})
+}
+
+// Parse reads one timetable page and returns its courses as the Python
+// scrapers write them to scraping/data. Each course starts at a header row
+// naming its faculty, department, term and title; the rows after it are its
+// activities, with the course number and credits on the first of them.
+func Parse(r io.Reader) ([]seedcheck.Record, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, fmt.Errorf("parse timetable: %w", err)
+	}
+
+	records := []seedcheck.Record{}
+	table := first(doc, atom.Table)
+	if table == nil {
+		return records, nil
+	}
+	for _, row := range rows(table) {
+		cells := children(row, atom.Td)
+		if header := headerCells(cells); header != nil {
+			records = append(records, seedcheck.Record{
+				Faculty:     cellText(header[0]),
+				Department:  cellText(header[1]),
+				Term:        cellText(header[2]),
+				CourseTitle: cellText(header[3]),
+				Credits:     "",
+				Sections:    []seedcheck.Activity{},
+			})
+			continue
+		}
+		if len(records) == 0 {
+			continue
+		}
+		course := &records[len(records)-1]
+		if a, ok := parseActivity(cells, course); ok {
+			course.Sections = append(course.Sections, a)
+		}
+	}
+	return records, nil
+}
+
+// headerCells returns a course header row's bodytext cells, or nil if the row
+// isn't one. Only header rows span their title over the activity columns.
+func headerCells(cells []*html.Node) []*html.Node {
+	var body []*html.Node
+	for _, c := range cells {
+		if hasClass(c, "bodytext") {
+			body = append(body, c)
+		}
+	}
+	if len(body) < 4 || !hasAttr(body[3], "colspan") {
+		return nil
+	}
+	return body
+}
+
+// parseActivity reads an activity row, filling in the course's number,
+// credits and language from it when they aren't known yet. Rows with no
+// activity type, like the column headings, are skipped.
+func parseActivity(cells []*html.Node, course *seedcheck.Record) (seedcheck.Activity, bool) {
+	typeAt := -1
+	var kind string
+	for i, c := range cells {
+		if kind = activityType(cellText(c)); kind != "" {
+			typeAt = i
+			break
+		}
+	}
+	if typeAt < 0 {
+		return seedcheck.Activity{}, false
+	}
+
+	a := seedcheck.Activity{Type: kind, Schedule: []models.Meeting{}, Instructors: []string{}}
+	for i := typeAt - 1; i >= 0; i-- {
+		m := summaryRe.FindStringSubmatch(cellText(cells[i]))
+		if m == nil {
+			continue
+		}
+		if course.CourseID == "" {
+			course.CourseID = m[1]
+		}
+		if course.Credits == "" {
+			course.Credits = m[2]
+		}
+		a.Section = m[3]
+		break
+	}
+	if course.LanguageOfInstruction == "" {
+		for i := typeAt - 1; i >= 0; i-- {
+			if token := cellText(cells[i]); isLanguage(token) {
+				course.LanguageOfInstruction = token
+				break
+			}
+		}
+	}
+
+	cell := func(offset int) *html.Node {
+		if typeAt+offset < len(cells) {
+			return cells[typeAt+offset]
+		}
+		return nil
+	}
+	a.MeetNumber = cellText(cell(1))
+	a.CatalogNumber = cellText(cell(2))
+	a.Schedule = parseSchedule(cell(3))
+	a.Instructors = parseInstructors(cell(4))
+	a.Notes = strings.Trim(cellLines(cell(5), " | "), " |")
+	return a, true
+}
+
+// parseSchedule reads the meetings table of an activity. Activities without
+// one show a single line of text, kept as the time of a lone meeting.
+func parseSchedule(cell *html.Node) []models.Meeting {
+	meetings := []models.Meeting{}
+	if cell == nil {
+		return meetings
+	}
+	inner := first(cell, atom.Table)
+	if inner == nil {
+		if text := cellText(cell); text != "" && !strings.EqualFold(text, "cancelled") {
+			meetings = append(meetings, models.Meeting{Time: text})
+		}
+		return meetings
+	}
+	for _, row := range all(inner, atom.Tr) {
+		cells := all(row, atom.Td)
+		if len(cells) < 5 {
+			continue
+		}
+		m := models.Meeting{
+			Day:      cellText(cells[0]),
+			Time:     cellText(cells[1]),
+			Duration: cellText(cells[2]),
+			Campus:   cellText(cells[3]),
+			Room:     cellText(cells[4]),
+		}
+		if m != (models.Meeting{}) {
+			meetings = append(meetings, m)
+		}
+	}
+	return meetings
+}
+
+// parseInstructors splits the instructor cell, which lists names one per
+// line or separated by commas, semicolons or ampersands.
+func parseInstructors(cell *html.Node) []string {
+	names := []string{}
+	text := cellLines(cell, "|")
+	for _, part := range strings.FieldsFunc(text, func(r rune) bool { return strings.ContainsRune("|,;&", r) }) {
+		if name := normalize(part); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func activityType(text string) string {
+	compact := strings.Map(func(r rune) rune {
+		if r >= 'A' && r <= 'Z' {
+			return r
+		}
+		return -1
+	}, strings.ToUpper(text))
+	for _, t := range activityTypes {
+		if strings.Contains(compact, t.spelling) {
+			return t.kind
+		}
+	}
+	return ""
+}
+
+// isLanguage reports whether a cell holds a language of instruction code such as EN or FR.
+func isLanguage(token string) bool {
+	if len(token) < 2 || len(token) > 3 {
+		return false
+	}
+	for _, r := range token {
+		if !unicode.IsUpper(r) || !unicode.IsLetter(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// cellText is the text of a cell with whitespace, including non-breaking
+// spaces, collapsed.
+func cellText(n *html.Node) string {
+	return cellLines(n, " ")
+}
+
+// cellLines is like cellText but marks line breaks with sep.
+func cellLines(n *html.Node, sep string) string {
+	if n == nil {
+		return ""
+	}
+	var b strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			switch {
+			case c.Type == html.TextNode:
+				b.WriteString(c.Data)
+			case c.DataAtom == atom.Br:
+				b.WriteString(sep)
+			case c.Type == html.ElementNode:
+				b.WriteString(" ")
+				walk(c)
+				b.WriteString(" ")
+			}
+		}
+	}
+	walk(n)
+	return normalize(b.String())
+}
+
+func normalize(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// rows returns the rows of a table, leaving out those of tables nested in its cells.
+func rows(table *html.Node) []*html.Node {
+	var out []*html.Node
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			switch c.DataAtom {
+			case atom.Tr:
+				out = append(out, c)
+			case atom.Table:
+			default:
+				walk(c)
+			}
+		}
+	}
+	walk(table)
+	return out
+}
+
+func children(n *html.Node, a atom.Atom) []*html.Node {
+	var out []*html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.DataAtom == a {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func all(n *html.Node, a atom.Atom) []*html.Node {
+	var out []*html.Node
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.DataAtom == a {
+				out = append(out, c)
+			}
+			walk(c)
+		}
+	}
+	walk(n)
+	return out
+}
+
+func first(n *html.Node, a atom.Atom) *html.Node {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.DataAtom == a {
+			return c
+		}
+		if found := first(c, a); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func hasClass(n *html.Node, class string) bool {
+	for _, attr := range n.Attr {
+		if attr.Key == "class" && strings.Contains(" "+attr.Val+" ", " "+class+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAttr(n *html.Node, key string) bool {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return true
+		}
+	}
+	return false
+}
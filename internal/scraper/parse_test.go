@@ -0,0 +1,140 @@
+package scraper
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"yuplan/internal/models"
+	"yuplan/internal/seedcheck"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The timetable leaves the meetings cell open, so the instructor and notes
+// cells that follow its table become cells of the row.
+const timetable = `<html><body>
+<p class='heading'>View Active Course Timetables by Faculty</p>
+<table border='1'>
+<tr bgcolor='#000000'>
+<td class='bodytext'><strong>Fac</strong></td><td class='bodytext'><strong>Dept</strong></td>
+<td class='bodytext'><strong>Term</strong></td><td class='bodytext'><strong>Course ID</strong></td>
+<td class='bodytext'><strong>Type</strong></td>
+</tr>
+<tr>
+<td class='bodytext'><strong>LE</strong></td>
+<td class='bodytext'><strong>EECS</strong></td>
+<td class='bodytext'><strong>F </strong></td>
+<td colspan='8' class='bodytext'><strong>Advanced Object Oriented Programming</strong></td>
+</tr>
+<tr>
+<td colspan='3'>&nbsp;</td>
+<td class='smallbodytext'>2030 &nbsp;3.00&nbsp;A&nbsp;</td>
+<td class='smallbodytext'>EN</td>
+<td class='smallbodytext'>LECT&nbsp;</td>
+<td class='smallbodytext'>01&nbsp;</td>
+<td class='smallbodytext'>K12A01&nbsp;</td>
+<td class='smallbodytext'><table border='0'><tr><td>M</td><td>10:30</td><td>80</td><td>Keele</td><td>CLH&nbsp; J</td></tr><tr><td>W</td><td>10:30</td><td>80</td><td>Keele</td><td>CLH J</td></tr></table><td class='smallbodytext'>Jane Doe<br>Alan Turing &amp; Grace Hopper&nbsp;</td><td class='smallbodytext'>Bring a laptop.<br>Open to <a href='#'>majors</a>.&nbsp;</td></tr>
+<tr>
+<td colspan = 5 class='smallbodytext'>&nbsp;</td><td class='smallbodytext'>LAB &nbsp;</td>
+<td class='smallbodytext'>01&nbsp;</td>
+<td class='smallbodytext'>K12A02&nbsp;</td>
+<td class='smallbodytext'><table border='0'><tr><td>R</td><td>14:30</td><td>180</td><td>Keele</td><td>LAS 1006</td></tr></table><td class='smallbodytext'>&nbsp;</td><td class='smallbodytext'>&nbsp;</td></tr>
+<tr>
+<td colspan='3'>&nbsp;</td>
+<td class='smallbodytext'>2030 &nbsp;3.00&nbsp;B&nbsp;</td>
+<td class='smallbodytext'>EN</td>
+<td class='smallbodytext'>LECT&nbsp;</td>
+<td class='smallbodytext'>01&nbsp;</td>
+<td class='smallbodytext'>Cancelled</td>
+<td class='smallbodytext'>&nbsp;</td>
+<td class='smallbodytext'>&nbsp;</td><td class='smallbodytext'>&nbsp;</td></tr>
+<tr>
+<td class='bodytext'><strong>LE</strong></td>
+<td class='bodytext'><strong>EECS</strong></td>
+<td class='bodytext'><strong>W </strong></td>
+<td colspan='8' class='bodytext'><strong>Directed Reading</strong></td>
+</tr>
+<tr>
+<td colspan='3'>&nbsp;</td>
+<td class='smallbodytext'>4080 &nbsp;3.00&nbsp;M&nbsp;</td>
+<td class='smallbodytext'>FR</td>
+<td class='smallbodytext'>DIRECTED STUDY</td>
+<td class='smallbodytext'>01&nbsp;</td>
+<td class='smallbodytext'>M55Q01&nbsp;</td>
+<td class='smallbodytext'>To be arranged</td>
+<td class='smallbodytext'>Staff</td><td class='smallbodytext'></td></tr>
+</table>
+</body></html>`
+
+func TestParse(t *testing.T) {
+	records, err := Parse(strings.NewReader(timetable))
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	oop := records[0]
+	assert.Equal(t, "LE", oop.Faculty)
+	assert.Equal(t, "EECS2030", oop.Code())
+	assert.Equal(t, "F", oop.Term)
+	assert.Equal(t, "Advanced Object Oriented Programming", oop.CourseTitle)
+	assert.Equal(t, "3.00", oop.Credits)
+	assert.Equal(t, "EN", oop.LanguageOfInstruction)
+	require.Len(t, oop.Sections, 3)
+
+	lecture := oop.Sections[0]
+	assert.Equal(t, "LECT", lecture.Type)
+	assert.Equal(t, "A", lecture.Section)
+	assert.Equal(t, "01", lecture.MeetNumber)
+	assert.Equal(t, "K12A01", lecture.CatalogNumber)
+	assert.Equal(t, []models.Meeting{
+		{Day: "M", Time: "10:30", Duration: "80", Campus: "Keele", Room: "CLH J"},
+		{Day: "W", Time: "10:30", Duration: "80", Campus: "Keele", Room: "CLH J"},
+	}, lecture.Schedule)
+	assert.Equal(t, []string{"Jane Doe", "Alan Turing", "Grace Hopper"}, lecture.Instructors)
+	assert.Equal(t, "Bring a laptop. | Open to majors .", lecture.Notes)
+
+	lab := oop.Sections[1]
+	assert.Equal(t, "LAB", lab.Type)
+	assert.Empty(t, lab.Section, "an unlettered row belongs to the section before it")
+	assert.Equal(t, "K12A02", lab.CatalogNumber)
+	assert.Empty(t, lab.Instructors)
+
+	cancelled := oop.Sections[2]
+	assert.Equal(t, "B", cancelled.Section)
+	assert.Equal(t, "Cancelled", cancelled.CatalogNumber)
+	assert.Empty(t, cancelled.Schedule)
+
+	reading := records[1]
+	assert.Equal(t, "EECS4080", reading.Code())
+	assert.Equal(t, "FR", reading.LanguageOfInstruction)
+	assert.Equal(t, "DIRD", reading.Sections[0].Type)
+	assert.Equal(t, []models.Meeting{{Time: "To be arranged"}}, reading.Sections[0].Schedule)
+	assert.Equal(t, []string{"Staff"}, reading.Sections[0].Instructors)
+
+	assert.Empty(t, seedcheck.Validate(oop))
+}
+
+func TestParse_NoTimetable(t *testing.T) {
+	records, err := Parse(strings.NewReader(`<html><body><p>No timetable yet</p></body></html>`))
+	require.NoError(t, err)
+	assert.Empty(t, records)
+}
+
+// A saved page must parse to what the Python scraper wrote for it.
+func TestParse_MatchesScrapedData(t *testing.T) {
+	page, err := os.Open("../../scraping/page_source/fall-winter-2025-2026/urban.html")
+	require.NoError(t, err)
+	defer page.Close()
+	raw, err := os.ReadFile("../../scraping/data/fall-winter-2025-2026/urban.json")
+	require.NoError(t, err)
+
+	var want struct {
+		Courses []seedcheck.Record `json:"courses"`
+	}
+	require.NoError(t, json.Unmarshal(raw, &want))
+
+	got, err := Parse(page)
+	require.NoError(t, err)
+	assert.Equal(t, want.Courses, got)
+}
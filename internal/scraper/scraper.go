@@ -0,0 +1,286 @@
+// Package scraper loads the York Courses Website timetable pages into the
+// database: it fetches each faculty's page, parses its courses, sections,
+// activities and instructors, and upserts the records that pass seedcheck.
+// Records that fail are quarantined with their reasons, as
+// scripts/generate_seed.py does for seed.sql.
+package scraper
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+	"yuplan/internal/models"
+	"yuplan/internal/seedcheck"
+)
+
+// DefaultBaseURL is where the York Courses Website publishes each faculty's
+// timetable, as <term id><faculty code>.html.
+const DefaultBaseURL = "https://apps1.sis.yorku.ca/WebObjects/cdm.woa/Contents/WebServerResources"
+
+// DefaultFaculties are the faculty codes with a timetable page of their own.
+var DefaultFaculties = []string{"AP", "ED", "ES", "FA", "GL", "GS", "HH", "LE", "SB", "SC"}
+
+// Catalog stores validated records. Implemented by repository.CatalogRepository.
+type Catalog interface {
+	EnsureTerm(ctx context.Context, term models.Term) error
+	UpsertCourse(ctx context.Context, plan seedcheck.Plan, term models.Term) (models.CourseChange, error)
+	MarkSeeded(ctx context.Context, checksum string, cacheInvalidated bool) error
+}
+
+// Invalidator drops the cached reads of the courses a run changed.
+// Implemented by repository.CatalogCacheInvalidator.
+type Invalidator interface {
+	Invalidate(ctx context.Context, changes []models.CourseChange) error
+}
+
+// Quarantine keeps records that failed validation. Implemented by repository.QuarantineRepository.
+type Quarantine interface {
+	Add(ctx context.Context, source, key string, record json.RawMessage, reasons []string) (bool, error)
+}
+
+// Page is one timetable page to load.
+type Page struct {
+	Source string // names the page in logs and the quarantine table, e.g. FW2025/LE.html
+	URL    string // an http(s) URL, or the path of a saved copy
+}
+
+// FacultyPages lists the timetable page of each faculty for a term.
+func FacultyPages(baseURL string, term models.Term, faculties []string) []Page {
+	pages := make([]Page, 0, len(faculties))
+	for _, faculty := range faculties {
+		name := term.ID + strings.ToUpper(faculty) + ".html"
+		pages = append(pages, Page{Source: term.ID + "/" + name, URL: strings.TrimSuffix(baseURL, "/") + "/" + name})
+	}
+	return pages
+}
+
+// LocalPages names pages given as URLs or file paths after the term and
+// their file name.
+func LocalPages(term models.Term, locations []string) []Page {
+	pages := make([]Page, 0, len(locations))
+	for _, location := range locations {
+		pages = append(pages, Page{Source: term.ID + "/" + path.Base(location), URL: location})
+	}
+	return pages
+}
+
+// Summary counts what one run did.
+type Summary struct {
+	Pages       int
+	Quarantined int // records newly quarantined; ones already there aren't counted
+	Diff        models.CatalogDiff
+}
+
+type Scraper struct {
+	catalog      Catalog
+	quarantine   Quarantine
+	client       *http.Client
+	descriptions map[string]string
+	invalidator  Invalidator
+	logger       *slog.Logger
+}
+
+func New(catalog Catalog, quarantine Quarantine, logger *slog.Logger) *Scraper {
+	return &Scraper{
+		catalog:    catalog,
+		quarantine: quarantine,
+		client:     &http.Client{Timeout: time.Minute},
+		logger:     logger,
+	}
+}
+
+// WithHTTPClient replaces the client pages are fetched with.
+func (s *Scraper) WithHTTPClient(client *http.Client) *Scraper {
+	s.client = client
+	return s
+}
+
+// WithDescriptions sets course descriptions by code, which the timetable
+// doesn't carry. Courses without one keep the description they have.
+func (s *Scraper) WithDescriptions(descriptions map[string]string) *Scraper {
+	s.descriptions = descriptions
+	return s
+}
+
+// WithInvalidator drops the cached reads of each course a run changes, so
+// the rest of the catalog cache survives the load.
+func (s *Scraper) WithInvalidator(invalidator Invalidator) *Scraper {
+	s.invalidator = invalidator
+	return s
+}
+
+// Run loads every page into term and, once all of them are written, marks
+// the load finished so catalog digests and cache invalidation notice it. A
+// catalog number claimed by two courses across the pages quarantines the
+// second, as validate_seed.py does within a session. Run stops at the first
+// page that can't be fetched or parsed, or write that fails.
+//
+// With an invalidator, the courses the run changed have their cached reads
+// dropped before the load is marked finished, even when it stops part way.
+// If that fails the load is marked as not invalidated, and the whole catalog
+// cache is dropped when it is noticed instead.
+func (s *Scraper) Run(ctx context.Context, term models.Term, pages []Page) (summary Summary, err error) {
+	if err := s.catalog.EnsureTerm(ctx, term); err != nil {
+		return summary, err
+	}
+
+	var changes []models.CourseChange
+	defer func() {
+		if err != nil && len(changes) > 0 {
+			s.invalidate(context.WithoutCancel(ctx), changes)
+		}
+	}()
+
+	checksum := sha256.New()
+	owners := map[string]string{}
+	for _, page := range pages {
+		records, err := s.load(ctx, page)
+		if err != nil {
+			return summary, err
+		}
+		summary.Pages++
+
+		for _, record := range records {
+			raw, err := json.Marshal(record)
+			if err != nil {
+				return summary, fmt.Errorf("encode %s: %w", record.Key(), err)
+			}
+			checksum.Write(raw)
+
+			if reasons := validate(record, owners); len(reasons) > 0 {
+				added, err := s.quarantine.Add(ctx, page.Source, record.Key(), raw, reasons)
+				if err != nil {
+					return summary, err
+				}
+				if added {
+					summary.Quarantined++
+					s.logger.Warn("quarantined scraped record", "source", page.Source, "record", record.Key(), "reasons", reasons)
+				}
+				continue
+			}
+
+			plan := seedcheck.NewPlan(record)
+			if description := s.descriptions[plan.Code]; description != "" {
+				plan.Description = description
+			}
+			change, err := s.catalog.UpsertCourse(ctx, plan, term)
+			if err != nil {
+				return summary, err
+			}
+			summary.Diff = summary.Diff.Add(change.Diff)
+			if change.Changed() {
+				changes = append(changes, change)
+			}
+		}
+		s.logger.Info("loaded timetable page", "source", page.Source, "records", len(records))
+	}
+
+	invalidated := s.invalidate(ctx, changes)
+	if err := s.catalog.MarkSeeded(ctx, hex.EncodeToString(checksum.Sum(nil)), invalidated); err != nil {
+		return summary, err
+	}
+	return summary, nil
+}
+
+// invalidate drops the cached reads of changes and reports whether it did.
+func (s *Scraper) invalidate(ctx context.Context, changes []models.CourseChange) bool {
+	if s.invalidator == nil {
+		return false
+	}
+	if err := s.invalidator.Invalidate(ctx, changes); err != nil {
+		s.logger.Warn("dropping cached reads of changed courses; the whole catalog cache will be dropped instead", "courses", len(changes), "error", err)
+		return false
+	}
+	return true
+}
+
+// validate returns why a record can't be loaded, claiming its catalog numbers
+// for it when it can. The same course can be split over several records, so
+// only another course holding a catalog number is a conflict.
+func validate(record seedcheck.Record, owners map[string]string) []string {
+	reasons := seedcheck.Validate(record)
+	if len(reasons) > 0 {
+		return reasons
+	}
+	key := record.Key()
+	for _, number := range record.CatalogNumbers() {
+		if owner, ok := owners[number]; ok && owner != key {
+			reasons = append(reasons, fmt.Sprintf("duplicate catalog number %s (also %s)", number, owner))
+		}
+	}
+	if len(reasons) > 0 {
+		return reasons
+	}
+	for _, number := range record.CatalogNumbers() {
+		if _, ok := owners[number]; !ok {
+			owners[number] = key
+		}
+	}
+	return nil
+}
+
+func (s *Scraper) load(ctx context.Context, page Page) ([]seedcheck.Record, error) {
+	body, err := s.fetch(ctx, page.URL)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	records, err := Parse(body)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", page.Source, err)
+	}
+	return records, nil
+}
+
+func (s *Scraper) fetch(ctx context.Context, location string) (io.ReadCloser, error) {
+	if !strings.HasPrefix(location, "http://") && !strings.HasPrefix(location, "https://") {
+		f, err := os.Open(location)
+		if err != nil {
+			return nil, fmt.Errorf("open timetable: %w", err)
+		}
+		return f, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build timetable request: %w", err)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch timetable: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("fetch timetable %s: %s", location, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// LoadDescriptions reads course descriptions in the format of
+// scraping/scrapers/descriptions/course_descriptions.json.
+func LoadDescriptions(r io.Reader) (map[string]string, error) {
+	var entries []struct {
+		CourseCode  string `json:"course_code"`
+		Description string `json:"description"`
+	}
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("decode course descriptions: %w", err)
+	}
+	descriptions := make(map[string]string, len(entries))
+	for _, e := range entries {
+		if e.CourseCode != "" && e.Description != "" {
+			descriptions[e.CourseCode] = e.Description
+		}
+	}
+	return descriptions, nil
+}
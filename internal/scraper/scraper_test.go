@@ -0,0 +1,201 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"yuplan/internal/models"
+	"yuplan/internal/seedcheck"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeCatalog struct {
+	terms       []string
+	plans       []seedcheck.Plan
+	existing    map[string]bool // "<code> <term>" of courses already stored
+	checksum    string
+	invalidated bool
+}
+
+func (f *fakeCatalog) EnsureTerm(ctx context.Context, term models.Term) error {
+	f.terms = append(f.terms, term.ID)
+	return nil
+}
+
+func (f *fakeCatalog) UpsertCourse(ctx context.Context, plan seedcheck.Plan, term models.Term) (models.CourseChange, error) {
+	f.plans = append(f.plans, plan)
+	change := models.CourseChange{CourseID: "id-" + plan.Code, Code: plan.Code, TermID: term.ID}
+	if f.existing[plan.Code+" "+plan.Term] {
+		change.Diff = models.CatalogDiff{Activities: models.RowDiff{Changed: 1}}
+		return change, nil
+	}
+	change.Diff = models.CatalogDiff{
+		Courses:    models.RowDiff{Added: 1},
+		Sections:   models.RowDiff{Added: len(plan.Letters)},
+		Activities: models.RowDiff{Added: len(plan.Activities)},
+	}
+	return change, nil
+}
+
+func (f *fakeCatalog) MarkSeeded(ctx context.Context, checksum string, cacheInvalidated bool) error {
+	f.checksum = checksum
+	f.invalidated = cacheInvalidated
+	return nil
+}
+
+type fakeInvalidator struct {
+	changes []models.CourseChange
+	err     error
+}
+
+func (f *fakeInvalidator) Invalidate(ctx context.Context, changes []models.CourseChange) error {
+	f.changes = append(f.changes, changes...)
+	return f.err
+}
+
+type quarantined struct {
+	source, key string
+	reasons     []string
+}
+
+type fakeQuarantine struct {
+	added []quarantined
+}
+
+func (f *fakeQuarantine) Add(ctx context.Context, source, key string, record json.RawMessage, reasons []string) (bool, error) {
+	f.added = append(f.added, quarantined{source, key, reasons})
+	return true, nil
+}
+
+// Another course claiming EECS2030's catalog number K12A01.
+const clashingTimetable = `<table>
+<tr><td class='bodytext'>LE</td><td class='bodytext'>EECS</td><td class='bodytext'>W</td><td colspan='8' class='bodytext'>Capstone</td></tr>
+<tr><td colspan='3'></td><td>4088 3.00 A</td><td>EN</td><td>LECT</td><td>01</td><td>K12A01</td><td>&nbsp;</td><td>Ada Lovelace</td><td></td></tr>
+</table>`
+
+func TestScraper_Run(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "lassonde.html")
+	second := filepath.Join(dir, "capstone.html")
+	require.NoError(t, os.WriteFile(first, []byte(timetable), 0o644))
+	require.NoError(t, os.WriteFile(second, []byte(clashingTimetable), 0o644))
+
+	catalog := &fakeCatalog{existing: map[string]bool{"EECS2030 F": true}}
+	quarantine := &fakeQuarantine{}
+	term, _ := models.NewTerm("FW2025")
+
+	s := New(catalog, quarantine, slog.New(slog.NewTextHandler(io.Discard, nil))).
+		WithDescriptions(map[string]string{"EECS2030": "Objects, properly."})
+	summary, err := s.Run(context.Background(), term, LocalPages(term, []string{first, second}))
+	require.NoError(t, err)
+
+	assert.Equal(t, Summary{Pages: 2, Quarantined: 2, Diff: models.CatalogDiff{Activities: models.RowDiff{Changed: 1}}}, summary)
+	assert.Equal(t, []string{"FW2025"}, catalog.terms)
+	require.Len(t, catalog.plans, 1)
+	assert.Equal(t, "EECS2030", catalog.plans[0].Code)
+	assert.Equal(t, "Objects, properly.", catalog.plans[0].Description)
+	assert.Equal(t, []string{"A", "B"}, catalog.plans[0].Letters)
+
+	require.Len(t, quarantine.added, 2)
+	assert.Equal(t, "FW2025/lassonde.html", quarantine.added[0].source)
+	assert.Equal(t, "EECS4080 W", quarantine.added[0].key)
+	assert.Equal(t, []string{"sections[0] invalid time 'To be arranged'"}, quarantine.added[0].reasons)
+	assert.Equal(t, "FW2025/capstone.html", quarantine.added[1].source)
+	assert.Equal(t, []string{"duplicate catalog number K12A01 (also EECS2030 F)"}, quarantine.added[1].reasons)
+
+	assert.Len(t, catalog.checksum, 64, "a finished run is marked seeded")
+	assert.False(t, catalog.invalidated, "without an invalidator the whole cache is left to be dropped")
+}
+
+func TestScraper_Run_InvalidatesChangedCourses(t *testing.T) {
+	dir := t.TempDir()
+	page := filepath.Join(dir, "lassonde.html")
+	require.NoError(t, os.WriteFile(page, []byte(timetable), 0o644))
+	term, _ := models.NewTerm("FW2025")
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	catalog := &fakeCatalog{existing: map[string]bool{"EECS2030 F": true}}
+	invalidator := &fakeInvalidator{}
+	_, err := New(catalog, &fakeQuarantine{}, logger).WithInvalidator(invalidator).
+		Run(context.Background(), term, LocalPages(term, []string{page}))
+	require.NoError(t, err)
+	require.Len(t, invalidator.changes, 1)
+	assert.Equal(t, "id-EECS2030", invalidator.changes[0].CourseID)
+	assert.Equal(t, "FW2025", invalidator.changes[0].TermID)
+	assert.True(t, catalog.invalidated)
+
+	// A failed invalidation leaves the whole cache to be dropped
+	catalog = &fakeCatalog{existing: map[string]bool{"EECS2030 F": true}}
+	_, err = New(catalog, &fakeQuarantine{}, logger).WithInvalidator(&fakeInvalidator{err: errors.New("redis down")}).
+		Run(context.Background(), term, LocalPages(term, []string{page}))
+	require.NoError(t, err)
+	assert.NotEmpty(t, catalog.checksum)
+	assert.False(t, catalog.invalidated)
+
+	// A run that stops part way still drops what it wrote
+	catalog = &fakeCatalog{existing: map[string]bool{"EECS2030 F": true}}
+	invalidator = &fakeInvalidator{}
+	_, err = New(catalog, &fakeQuarantine{}, logger).WithInvalidator(invalidator).
+		Run(context.Background(), term, LocalPages(term, []string{page, filepath.Join(dir, "missing.html")}))
+	require.Error(t, err)
+	assert.Empty(t, catalog.checksum)
+	assert.Len(t, invalidator.changes, 1)
+}
+
+func TestScraper_Run_FetchesPages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/FW2025LE.html" {
+			http.NotFound(w, r)
+			return
+		}
+		io.WriteString(w, timetable)
+	}))
+	defer server.Close()
+
+	term, _ := models.NewTerm("FW2025")
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	catalog := &fakeCatalog{}
+	summary, err := New(catalog, &fakeQuarantine{}, logger).
+		WithHTTPClient(server.Client()).
+		Run(context.Background(), term, FacultyPages(server.URL, term, []string{"le"}))
+	require.NoError(t, err)
+	assert.Equal(t, models.RowDiff{Added: 1}, summary.Diff.Courses)
+	assert.NotEmpty(t, catalog.checksum)
+
+	catalog = &fakeCatalog{}
+	summary, err = New(catalog, &fakeQuarantine{}, logger).
+		WithHTTPClient(server.Client()).
+		Run(context.Background(), term, FacultyPages(server.URL, term, []string{"LE", "SC"}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "404")
+	assert.Equal(t, 1, summary.Pages)
+	assert.Empty(t, catalog.checksum, "a run that stops early isn't marked seeded")
+}
+
+func TestFacultyPages(t *testing.T) {
+	term, _ := models.NewTerm("SU2026")
+	assert.Equal(t, []Page{
+		{Source: "SU2026/SU2026AP.html", URL: "https://example.com/tt/SU2026AP.html"},
+	}, FacultyPages("https://example.com/tt/", term, []string{"ap"}))
+}
+
+func TestLoadDescriptions(t *testing.T) {
+	descriptions, err := LoadDescriptions(strings.NewReader(
+		`[{"course_code": "EECS2030", "description": "Objects."}, {"course_code": "EECS1001", "description": ""}]`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"EECS2030": "Objects."}, descriptions)
+
+	_, err = LoadDescriptions(strings.NewReader(`{}`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,74 @@
+// Package search answers course searches in two tiers: a query shaped like a
+// course code is looked up exactly first, and everything else, or a code
+// that matches nothing, goes to the ranked fuzzy search.
+package search
+
+import (
+	"context"
+	"regexp"
+	"yuplan/internal/models"
+)
+
+// Paths a search can be answered by.
+const (
+	PathExact = "exact" // the normalized course-code lookup
+	PathFuzzy = "fuzzy" // ranked full-text and trigram search
+)
+
+// codePattern is the shape of every course code in the catalog: two to four
+// department letters, four digits and sometimes a trailing letter.
+var codePattern = regexp.MustCompile(`^[A-Z]{2,4}[0-9]{4}[A-Z]?$`)
+
+// Store runs both kinds of search. Implemented by repository.CourseRepository.
+type Store interface {
+	SearchExactCode(ctx context.Context, code, termID string) ([]models.Course, error)
+	Search(ctx context.Context, query, termID string, limit, offset int) ([]models.Course, error)
+}
+
+// Service serves course search.
+type Service struct {
+	store Store
+}
+
+func NewService(store Store) *Service {
+	return &Service{store: store}
+}
+
+// Courses returns a page of courses matching query, optionally only those
+// offered in termID, and the path that answered it. A code's offerings are
+// paged like any other results, so later pages of a code search stay on the
+// exact path rather than jumping to the fuzzy ranking.
+func (s *Service) Courses(ctx context.Context, query, termID string, limit, offset int) ([]models.Course, string, error) {
+	if code, ok := LooksLikeCode(query); ok {
+		courses, err := s.store.SearchExactCode(ctx, code, termID)
+		if err != nil {
+			return nil, PathExact, err
+		}
+		if len(courses) > 0 {
+			return page(courses, limit, offset), PathExact, nil
+		}
+	}
+	courses, err := s.store.Search(ctx, query, termID, limit, offset)
+	return courses, PathFuzzy, err
+}
+
+// LooksLikeCode reports whether query is a whole course code, such as
+// "EECS2030" or "eecs 2030", and returns it normalized.
+func LooksLikeCode(query string) (string, bool) {
+	code := models.NormalizeCourseCode(query)
+	return code, codePattern.MatchString(code)
+}
+
+func page(courses []models.Course, limit, offset int) []models.Course {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= len(courses) {
+		return []models.Course{}
+	}
+	courses = courses[offset:]
+	if limit >= 0 && limit < len(courses) {
+		courses = courses[:limit]
+	}
+	return courses
+}
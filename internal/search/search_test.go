@@ -0,0 +1,104 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"yuplan/internal/models"
+)
+
+type fakeStore struct {
+	byCode    map[string][]models.Course
+	fuzzy     []models.Course
+	err       error
+	exactArgs []string
+	fuzzyCall bool
+}
+
+func (f *fakeStore) SearchExactCode(ctx context.Context, code, termID string) ([]models.Course, error) {
+	f.exactArgs = []string{code, termID}
+	return f.byCode[code], f.err
+}
+
+func (f *fakeStore) Search(ctx context.Context, query, termID string, limit, offset int) ([]models.Course, error) {
+	f.fuzzyCall = true
+	return f.fuzzy, nil
+}
+
+func TestLooksLikeCode(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+		ok    bool
+	}{
+		{"EECS2030", "EECS2030", true},
+		{"eecs 2030", "EECS2030", true},
+		{"ADMS 1000 ", "ADMS1000", true},
+		{"MUS1000A", "MUS1000A", true},
+		{"SC1010", "SC1010", true},
+		{"EECS", "EECS", false},
+		{"EECS20", "EECS20", false},
+		{"software design", "SOFTWAREDESIGN", false},
+		{"EECS2030 intro", "EECS2030INTRO", false},
+	}
+	for _, tt := range tests {
+		if got, ok := LooksLikeCode(tt.query); got != tt.want || ok != tt.ok {
+			t.Errorf("LooksLikeCode(%q) = %q, %v; want %q, %v", tt.query, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestCourses_ExactCode(t *testing.T) {
+	store := &fakeStore{byCode: map[string][]models.Course{
+		"EECS2030": {{Code: "EECS2030", Term: models.TermFall}, {Code: "EECS2030", Term: models.TermWinter}},
+	}}
+	s := NewService(store)
+
+	courses, path, err := s.Courses(context.Background(), "eecs 2030", "FW2025", 50, 0)
+	if err != nil {
+		t.Fatalf("Courses: %v", err)
+	}
+	if path != PathExact || len(courses) != 2 || store.fuzzyCall {
+		t.Errorf("Expected both offerings from the exact path, got %d from %s (fuzzy called: %v)", len(courses), path, store.fuzzyCall)
+	}
+	if store.exactArgs[0] != "EECS2030" || store.exactArgs[1] != "FW2025" {
+		t.Errorf("Expected the normalized code and term, got %v", store.exactArgs)
+	}
+
+	courses, path, _ = s.Courses(context.Background(), "EECS2030", "", 1, 1)
+	if path != PathExact || len(courses) != 1 || courses[0].Term != models.TermWinter {
+		t.Errorf("Expected the second offering on the second page, got %+v from %s", courses, path)
+	}
+	courses, path, _ = s.Courses(context.Background(), "EECS2030", "", 50, 5)
+	if path != PathExact || len(courses) != 0 {
+		t.Errorf("Expected an empty page past the end, got %+v from %s", courses, path)
+	}
+}
+
+func TestCourses_FallsBackToFuzzy(t *testing.T) {
+	store := &fakeStore{fuzzy: []models.Course{{Code: "EECS3311", Name: "Software Design"}}}
+	s := NewService(store)
+
+	// Not shaped like a code: straight to fuzzy
+	courses, path, err := s.Courses(context.Background(), "software", "", 50, 0)
+	if err != nil || path != PathFuzzy || len(courses) != 1 || store.exactArgs != nil {
+		t.Errorf("Expected a fuzzy search only, got %d from %s, exact args %v, err %v", len(courses), path, store.exactArgs, err)
+	}
+
+	// Shaped like a code that doesn't exist: exact first, then fuzzy
+	courses, path, _ = s.Courses(context.Background(), "EECS9999", "", 50, 0)
+	if path != PathFuzzy || len(courses) != 1 || store.exactArgs == nil {
+		t.Errorf("Expected the exact miss to fall back to fuzzy, got %d from %s", len(courses), path)
+	}
+}
+
+func TestCourses_ExactError(t *testing.T) {
+	store := &fakeStore{err: errors.New("db down")}
+
+	if _, _, err := NewService(store).Courses(context.Background(), "EECS2030", "", 50, 0); err == nil {
+		t.Error("Expected the exact lookup's error")
+	}
+	if store.fuzzyCall {
+		t.Error("Expected no fuzzy search after an error")
+	}
+}
@@ -0,0 +1,115 @@
+package seedcheck
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+)
+
+// Plan is a validated record laid out as rows for courses, sections,
+// section_activities and instructors, the same way scripts/generate_seed.py
+// lays out a record in seed.sql.
+type Plan struct {
+	Code        string
+	Name        string
+	Credits     float64
+	Description string
+	Faculty     string
+	Term        string
+	Letters     []string // one sections row per distinct letter, in order
+	Activities  []PlannedActivity
+	Instructors []PlannedInstructor
+}
+
+type PlannedActivity struct {
+	Letter        string
+	CourseType    string
+	CatalogNumber string
+	Times         string // JSON, empty when the activity has no meetings
+}
+
+type PlannedInstructor struct {
+	Letter         string
+	FirstName      string
+	LastName       string
+	RateMyProfLink string
+}
+
+// NewPlan lays out a record that passed Validate. Activities without a letter
+// belong to the most recent lettered section, or the first one if none came yet.
+func NewPlan(r Record) Plan {
+	credits, _ := r.CreditValue()
+	p := Plan{
+		Code:        r.Code(),
+		Name:        r.CourseTitle,
+		Credits:     credits,
+		Description: r.Notes,
+		Faculty:     r.Faculty,
+		Term:        r.Term,
+	}
+
+	seen := map[string]bool{}
+	for _, a := range r.Sections {
+		if a.Section != "" && !seen[a.Section] {
+			seen[a.Section] = true
+			p.Letters = append(p.Letters, a.Section)
+		}
+	}
+	if len(p.Letters) == 0 {
+		return p
+	}
+
+	current := p.Letters[0]
+	for _, a := range r.Sections {
+		if a.Section != "" {
+			current = a.Section
+		}
+
+		var times string
+		if len(a.Schedule) > 0 {
+			b, _ := json.Marshal(a.Schedule)
+			times = string(b)
+		}
+		p.Activities = append(p.Activities, PlannedActivity{
+			Letter:        current,
+			CourseType:    strings.ToUpper(a.Type),
+			CatalogNumber: a.CatalogNumber,
+			Times:         times,
+		})
+
+		for _, name := range a.Instructors {
+			if strings.TrimSpace(name) == "" {
+				continue
+			}
+			first, last := splitName(name)
+			p.Instructors = append(p.Instructors, PlannedInstructor{
+				Letter:         current,
+				FirstName:      first,
+				LastName:       last,
+				RateMyProfLink: rateMyProfLink(first, last),
+			})
+		}
+	}
+	return p
+}
+
+// splitName treats the last word as the last name, as generate_seed.py does.
+func splitName(name string) (string, string) {
+	parts := strings.Fields(name)
+	switch len(parts) {
+	case 0:
+		return "", ""
+	case 1:
+		return parts[0], ""
+	default:
+		return strings.Join(parts[:len(parts)-1], " "), parts[len(parts)-1]
+	}
+}
+
+func rateMyProfLink(first, last string) string {
+	full := strings.TrimSpace(first + " " + last)
+	if full == "" {
+		return ""
+	}
+	return "https://www.ratemyprofessors.com/search/professors/?q=" + url.QueryEscape(full)
+}
@@ -0,0 +1,57 @@
+package seedcheck
+
+import (
+	"testing"
+	"yuplan/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPlan(t *testing.T) {
+	r := Record{
+		Faculty:     "LE",
+		Department:  "EECS",
+		CourseID:    "2030",
+		CourseTitle: "Object Oriented Programming",
+		Credits:     "3.00",
+		Term:        "F",
+		Notes:       "Design by contract.",
+		Sections: []Activity{
+			{Type: "tutr", CatalogNumber: "K12A00"}, // before any letter: belongs to the first section
+			{Type: "LECT", Section: "A", CatalogNumber: "K12A01", Schedule: []models.Meeting{{Day: "M", Time: "10:00", Duration: "80"}}, Instructors: []string{"Mary Jane Watson", " "}},
+			{Type: "LAB", CatalogNumber: "K12A02"},
+			{Type: "LECT", Section: "B", CatalogNumber: "K12B01", Instructors: []string{"Cher"}},
+			{Type: "LAB", CatalogNumber: "K12B02"},
+			{Type: "LECT", Section: "A", CatalogNumber: "K12A03"},
+		},
+	}
+
+	p := NewPlan(r)
+
+	assert.Equal(t, "EECS2030", p.Code)
+	assert.Equal(t, "Object Oriented Programming", p.Name)
+	assert.Equal(t, 3.0, p.Credits)
+	assert.Equal(t, "Design by contract.", p.Description)
+	assert.Equal(t, []string{"A", "B"}, p.Letters)
+
+	var letters, types []string
+	for _, a := range p.Activities {
+		letters = append(letters, a.Letter)
+		types = append(types, a.CourseType)
+	}
+	assert.Equal(t, []string{"A", "A", "A", "B", "B", "A"}, letters)
+	assert.Equal(t, "TUTR", types[0])
+	assert.JSONEq(t, `[{"day":"M","time":"10:00","duration":"80","campus":"","room":""}]`, p.Activities[1].Times)
+	assert.Empty(t, p.Activities[2].Times)
+
+	assert.Equal(t, []PlannedInstructor{
+		{Letter: "A", FirstName: "Mary Jane", LastName: "Watson", RateMyProfLink: "https://www.ratemyprofessors.com/search/professors/?q=Mary+Jane+Watson"},
+		{Letter: "B", FirstName: "Cher", LastName: "", RateMyProfLink: "https://www.ratemyprofessors.com/search/professors/?q=Cher"},
+	}, p.Instructors)
+}
+
+func TestNewPlan_WithoutLetters(t *testing.T) {
+	p := NewPlan(Record{Department: "EECS", CourseID: "2030", Sections: []Activity{{Type: "LECT"}}})
+	assert.Empty(t, p.Letters)
+	assert.Empty(t, p.Activities)
+}
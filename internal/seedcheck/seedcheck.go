@@ -0,0 +1,178 @@
+// Package seedcheck validates scraped course records. It applies the same
+// rules as scripts/validate_seed.py so that records quarantined during seeding
+// can be fixed and re-processed through the admin API.
+package seedcheck
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"yuplan/internal/models"
+)
+
+var (
+	validDays = map[string]bool{"M": true, "T": true, "W": true, "R": true, "F": true, "S": true, "U": true}
+	timeRe    = regexp.MustCompile(`^([01]?\d|2[0-3]):[0-5]\d$`)
+
+	// Only plain catalog numbers are checked for duplicates; cross-listed
+	// activities share a longer string and placeholders like "Cancelled" repeat.
+	catalogNumberRe = regexp.MustCompile(`^[A-Z0-9]{6}$`)
+)
+
+// Record is one course as written to scraping/data by the scrapers.
+type Record struct {
+	Faculty               string     `json:"faculty"`
+	Department            string     `json:"department"`
+	CourseID              string     `json:"courseId"`
+	CourseTitle           string     `json:"courseTitle"`
+	Credits               any        `json:"credits"` // usually a string such as "3.00"
+	LanguageOfInstruction string     `json:"languageOfInstruction,omitempty"`
+	Term                  string     `json:"term"`
+	Notes                 string     `json:"notes"`
+	Sections              []Activity `json:"sections"`
+}
+
+// Activity is one entry of a record's sections list. Only some entries carry
+// a section letter; the rest belong to the most recent lettered section.
+type Activity struct {
+	Type          string           `json:"type"`
+	MeetNumber    string           `json:"meetNumber,omitempty"`
+	Section       string           `json:"section"`
+	CatalogNumber string           `json:"catalogNumber"`
+	Schedule      []models.Meeting `json:"schedule"`
+	Instructors   []string         `json:"instructors"`
+	Notes         string           `json:"notes,omitempty"`
+}
+
+// Parse decodes a raw record. A record that doesn't fit the scraper format is
+// reported as a problem rather than an error, like any other invalid record.
+func Parse(raw json.RawMessage) (Record, []string) {
+	var r Record
+	if err := json.Unmarshal(raw, &r); err != nil {
+		return r, []string{fmt.Sprintf("record does not match the scraper format: %v", err)}
+	}
+	return r, nil
+}
+
+// Code is the course code, e.g. EECS2030.
+func (r Record) Code() string {
+	return r.Department + r.CourseID
+}
+
+// Key identifies the record in messages and the quarantine table, e.g. "EECS2030 F".
+func (r Record) Key() string {
+	code, term := r.Code(), r.Term
+	if code == "" {
+		code = "?"
+	}
+	if term == "" {
+		term = "?"
+	}
+	return code + " " + term
+}
+
+// CreditValue parses credits, which the scrapers write as a string.
+func (r Record) CreditValue() (float64, bool) {
+	switch v := r.Credits.(type) {
+	case float64:
+		return v, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// CatalogNumbers returns the plain catalog numbers of the record's activities.
+func (r Record) CatalogNumbers() []string {
+	var numbers []string
+	for _, a := range r.Sections {
+		if catalogNumberRe.MatchString(a.CatalogNumber) {
+			numbers = append(numbers, a.CatalogNumber)
+		}
+	}
+	return numbers
+}
+
+// Validate returns every schema, integrity and schedule problem with the
+// record; none means it can be inserted. Duplicate catalog numbers need the
+// database and are checked by the caller.
+func Validate(r Record) []string {
+	var problems []string
+	for _, f := range []struct{ name, value string }{
+		{"department", r.Department},
+		{"courseId", r.CourseID},
+		{"courseTitle", r.CourseTitle},
+		{"term", r.Term},
+	} {
+		if strings.TrimSpace(f.value) == "" {
+			problems = append(problems, "missing "+f.name)
+		}
+	}
+
+	if credits, ok := r.CreditValue(); !ok {
+		problems = append(problems, fmt.Sprintf("credits %s is not a number", quote(r.Credits)))
+	} else if credits < 0 {
+		problems = append(problems, fmt.Sprintf("negative credits %s", quote(r.Credits)))
+	}
+
+	for i, a := range r.Sections {
+		if a.Type == "" {
+			problems = append(problems, fmt.Sprintf("sections[%d] has no activity type", i))
+		}
+	}
+
+	if !r.hasLetteredSection() {
+		problems = append(problems, "no lettered section to attach activities to")
+	}
+
+	for i, a := range r.Sections {
+		for _, m := range a.Schedule {
+			problems = append(problems, meetingProblems(i, m)...)
+		}
+	}
+	return problems
+}
+
+func meetingProblems(i int, m models.Meeting) []string {
+	var problems []string
+	if m.Day != "" && !validDays[m.Day] {
+		problems = append(problems, fmt.Sprintf("sections[%d] invalid day %s", i, quote(m.Day)))
+	}
+	if m.Time != "" && !timeRe.MatchString(m.Time) {
+		problems = append(problems, fmt.Sprintf("sections[%d] invalid time %s", i, quote(m.Time)))
+	}
+	minutes, err := strconv.Atoi(m.Duration)
+	if m.Duration != "" && (err != nil || minutes < 0) {
+		problems = append(problems, fmt.Sprintf("sections[%d] invalid duration %s", i, quote(m.Duration)))
+	}
+	// A meeting that occupies a weekly slot needs a start time
+	if m.Scheduled() && m.Time == "" {
+		problems = append(problems, fmt.Sprintf("sections[%d] meets on %s with no start time", i, m.Day))
+	}
+	return problems
+}
+
+func (r Record) hasLetteredSection() bool {
+	for _, a := range r.Sections {
+		if a.Section != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// quote formats a value the way Python's repr does in validate_seed.py, so
+// both sides produce the same reasons.
+func quote(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "None"
+	case string:
+		return "'" + v + "'"
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,112 @@
+package seedcheck
+
+import (
+	"encoding/json"
+	"testing"
+	"yuplan/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func validRecord() Record {
+	return Record{
+		Faculty:     "LE",
+		Department:  "EECS",
+		CourseID:    "2030",
+		CourseTitle: "Object Oriented Programming",
+		Credits:     "3.00",
+		Term:        "F",
+		Sections: []Activity{
+			{Type: "LECT", Section: "A", CatalogNumber: "K12A01", Schedule: []models.Meeting{{Day: "M", Time: "10:00", Duration: "80"}}},
+			{Type: "LAB", CatalogNumber: "K12A02", Schedule: []models.Meeting{{Day: "", Time: "0:00", Duration: "0"}}},
+		},
+	}
+}
+
+func TestValidate_ValidRecord(t *testing.T) {
+	assert.Empty(t, Validate(validRecord()))
+}
+
+func TestValidate_AsynchronousPlaceholders(t *testing.T) {
+	r := validRecord()
+	r.Sections[0].Schedule = []models.Meeting{
+		{Day: "", Time: "", Duration: "0"},
+		{Day: "W", Time: "", Duration: "0"},
+		{Day: "", Time: "0:00", Duration: "50"},
+	}
+	assert.Empty(t, Validate(r))
+}
+
+func TestValidate_Schema(t *testing.T) {
+	r := validRecord()
+	r.CourseID = ""
+	r.CourseTitle = "  "
+	r.Credits = ""
+	r.Sections[1].Type = ""
+
+	problems := Validate(r)
+	assert.Contains(t, problems, "missing courseId")
+	assert.Contains(t, problems, "missing courseTitle")
+	assert.Contains(t, problems, "credits '' is not a number")
+	assert.Contains(t, problems, "sections[1] has no activity type")
+}
+
+func TestValidate_Credits(t *testing.T) {
+	r := validRecord()
+	r.Credits = 6.0
+	assert.Empty(t, Validate(r))
+
+	r.Credits = "-3"
+	assert.Equal(t, []string{"negative credits '-3'"}, Validate(r))
+
+	r.Credits = nil
+	assert.Equal(t, []string{"credits None is not a number"}, Validate(r))
+}
+
+func TestValidate_NoLetteredSection(t *testing.T) {
+	r := validRecord()
+	r.Sections[0].Section = ""
+	assert.Equal(t, []string{"no lettered section to attach activities to"}, Validate(r))
+}
+
+func TestValidate_Schedule(t *testing.T) {
+	r := validRecord()
+	r.Sections[0].Schedule = []models.Meeting{
+		{Day: "X", Time: "10:00", Duration: "50"},
+		{Day: "M", Time: "25:00", Duration: "50"},
+		{Day: "T", Time: "10:00", Duration: "fifty"},
+		{Day: "R", Time: "", Duration: "50"},
+	}
+
+	assert.Equal(t, []string{
+		"sections[0] invalid day 'X'",
+		"sections[0] invalid time '25:00'",
+		"sections[0] invalid duration 'fifty'",
+		"sections[0] meets on R with no start time",
+	}, Validate(r))
+}
+
+func TestParse(t *testing.T) {
+	r, problems := Parse(json.RawMessage(`{"department":"EECS","courseId":"2030","term":"F","credits":"3.00","sections":[{"type":"LECT","section":"A"}]}`))
+	assert.Empty(t, problems)
+	assert.Equal(t, "EECS2030", r.Code())
+	assert.Equal(t, "EECS2030 F", r.Key())
+
+	_, problems = Parse(json.RawMessage(`{"sections":{"type":"LECT"}}`))
+	assert.Len(t, problems, 1)
+	assert.Contains(t, problems[0], "record does not match the scraper format")
+}
+
+func TestRecord_Key(t *testing.T) {
+	assert.Equal(t, "? ?", Record{}.Key())
+}
+
+func TestRecord_CatalogNumbers(t *testing.T) {
+	r := validRecord()
+	r.Sections = append(r.Sections,
+		Activity{Type: "TUTR", CatalogNumber: "W55H01 (AP HRM ) H82U01 (AP ADMS)"},
+		Activity{Type: "TUTR", CatalogNumber: "Cancelled"},
+		Activity{Type: "TUTR"},
+	)
+	assert.Equal(t, []string{"K12A01", "K12A02"}, r.CatalogNumbers())
+}
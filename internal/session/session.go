@@ -0,0 +1,124 @@
+// Package session gives visitors without an account an anonymous session, so
+// the course shuffle, recently viewed courses and drafts stay consistent
+// between visits. A session is only a random id and when it started, signed so
+// it can't be forged; nothing about the visitor is in it. Sessions end a fixed
+// time after they start, however active they are, and their data is purged
+// with them.
+package session
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"yuplan/internal/id"
+)
+
+const (
+	// Cookie is the cookie browsers carry the session token in.
+	Cookie = "yuplan_session"
+	// Header carries the token for clients without cookies, such as apps.
+	Header = "X-Session-Token"
+)
+
+var (
+	ErrInvalid = errors.New("invalid session")
+	ErrExpired = errors.New("session expired")
+)
+
+// Session is one visitor's anonymous session.
+type Session struct {
+	ID        string    `json:"-"`
+	StartedAt time.Time `json:"started_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type claims struct {
+	ID        string    `json:"id"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+// Signer starts sessions and verifies their tokens. Expiry is worked out from
+// the start time and the current TTL, so shortening SESSION_TTL also ends
+// sessions already handed out.
+type Signer struct {
+	secret []byte
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+func NewSigner(secret string, ttl time.Duration) *Signer {
+	return &Signer{secret: []byte(secret), ttl: ttl, now: time.Now}
+}
+
+// TTL is how long a session lasts from its start.
+func (s *Signer) TTL() time.Duration {
+	return s.ttl
+}
+
+// Start begins a new session and returns it with its token.
+func (s *Signer) Start() (Session, string, error) {
+	c := claims{ID: id.New(), StartedAt: s.now().UTC().Truncate(time.Second)}
+	payload, err := json.Marshal(c)
+	if err != nil {
+		return Session{}, "", fmt.Errorf("encode session: %w", err)
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	token := encoded + "." + base64.RawURLEncoding.EncodeToString(s.sign(encoded))
+	return s.session(c), token, nil
+}
+
+// Verify returns the session a token was issued for. It returns ErrExpired
+// for a genuine token whose session has ended and ErrInvalid for anything else.
+func (s *Signer) Verify(token string) (Session, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return Session{}, ErrInvalid
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(sig, s.sign(encoded)) {
+		return Session{}, ErrInvalid
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return Session{}, ErrInvalid
+	}
+	var c claims
+	if err := json.Unmarshal(payload, &c); err != nil || c.ID == "" || c.StartedAt.IsZero() {
+		return Session{}, ErrInvalid
+	}
+
+	sess := s.session(c)
+	if !s.now().Before(sess.ExpiresAt) {
+		return Session{}, ErrExpired
+	}
+	return sess, nil
+}
+
+func (s *Signer) session(c claims) Session {
+	return Session{ID: c.ID, StartedAt: c.StartedAt, ExpiresAt: c.StartedAt.Add(s.ttl)}
+}
+
+func (s *Signer) sign(encoded string) []byte {
+	h := hmac.New(sha256.New, s.secret)
+	h.Write([]byte(encoded))
+	return h.Sum(nil)
+}
+
+type sessionKey struct{}
+
+// WithSession records the caller's session in ctx.
+func WithSession(ctx context.Context, sess Session) context.Context {
+	return context.WithValue(ctx, sessionKey{}, sess)
+}
+
+// FromContext returns the caller's session, if they sent a valid one.
+func FromContext(ctx context.Context) (Session, bool) {
+	sess, ok := ctx.Value(sessionKey{}).(Session)
+	return sess, ok
+}
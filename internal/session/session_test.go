@@ -0,0 +1,72 @@
+package session
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStartAndVerify(t *testing.T) {
+	signer := NewSigner("secret", 30*24*time.Hour)
+	now := time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)
+	signer.now = func() time.Time { return now }
+
+	sess, token, err := signer.Start()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, sess.ID)
+	assert.Equal(t, now.Add(30*24*time.Hour), sess.ExpiresAt)
+
+	verified, err := signer.Verify(token)
+	assert.NoError(t, err)
+	assert.Equal(t, sess, verified)
+
+	now = now.Add(30 * 24 * time.Hour)
+	_, err = signer.Verify(token)
+	assert.ErrorIs(t, err, ErrExpired)
+}
+
+func TestVerify_ShorterTTLEndsOldSessions(t *testing.T) {
+	signer := NewSigner("secret", 30*24*time.Hour)
+	now := time.Now()
+	signer.now = func() time.Time { return now }
+	_, token, err := signer.Start()
+	assert.NoError(t, err)
+
+	shorter := NewSigner("secret", 7*24*time.Hour)
+	shorter.now = func() time.Time { return now.Add(8 * 24 * time.Hour) }
+	_, err = shorter.Verify(token)
+	assert.ErrorIs(t, err, ErrExpired)
+}
+
+func TestVerify_Invalid(t *testing.T) {
+	signer := NewSigner("secret", time.Hour)
+	_, token, err := signer.Start()
+	assert.NoError(t, err)
+	encoded, sig, _ := strings.Cut(token, ".")
+
+	for _, bad := range []string{
+		"",
+		"no-dot",
+		encoded + ".",
+		"e30." + sig, // {} signed with someone else's signature
+	} {
+		_, err := signer.Verify(bad)
+		assert.ErrorIs(t, err, ErrInvalid, bad)
+	}
+
+	_, err = NewSigner("other secret", time.Hour).Verify(token)
+	assert.ErrorIs(t, err, ErrInvalid)
+}
+
+func TestFromContext(t *testing.T) {
+	_, ok := FromContext(context.Background())
+	assert.False(t, ok)
+
+	sess := Session{ID: "s-1"}
+	got, ok := FromContext(WithSession(context.Background(), sess))
+	assert.True(t, ok)
+	assert.Equal(t, sess, got)
+}
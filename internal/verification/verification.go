@@ -0,0 +1,123 @@
+// Package verification confirms that a new review was written by the owner of
+// the email it was submitted with. The review stays unverified, and out of
+// every public read and stat, until its author follows the link mailed to them.
+package verification
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"time"
+	"yuplan/internal/mailer"
+	"yuplan/internal/models"
+)
+
+// Store keeps the pending verifications. Implemented by repository.ReviewVerificationRepository.
+type Store interface {
+	// Save records the token for an unverified review and the moderation
+	// state to give the review once it is confirmed.
+	Save(ctx context.Context, reviewID, tokenHash, moderation string, expiresAt time.Time) error
+	// Reissue replaces the token of a still unverified review by email, and
+	// reports false when there is no such review.
+	Reissue(ctx context.Context, courseCode, reviewID, email, tokenHash string, expiresAt time.Time) (bool, error)
+	// Confirm applies the saved moderation state to the review an unexpired
+	// token belongs to, and returns it, or nil if the token is unknown or expired.
+	Confirm(ctx context.Context, tokenHash string) (*models.Review, error)
+}
+
+// Verifier mails confirmation links and confirms reviews when they are followed.
+type Verifier struct {
+	store   Store
+	mailer  mailer.Mailer
+	linkURL string // the token is added to it as ?token=
+	ttl     time.Duration
+}
+
+func New(store Store, m mailer.Mailer, linkURL string, ttl time.Duration) *Verifier {
+	return &Verifier{store: store, mailer: m, linkURL: linkURL, ttl: ttl}
+}
+
+// Request mails the author of a review just stored as unverified a link to
+// confirm it. Once confirmed the review takes the moderation state decided
+// at submission.
+func (v *Verifier) Request(ctx context.Context, review *models.Review, moderation string) error {
+	token, hash, err := newToken()
+	if err != nil {
+		return err
+	}
+	if err := v.store.Save(ctx, review.ID, hash, moderation, time.Now().Add(v.ttl)); err != nil {
+		return fmt.Errorf("save verification: %w", err)
+	}
+	return v.send(ctx, review.Email, review.CourseCode, token)
+}
+
+// Resend mails a fresh link for an unverified review, invalidating the old
+// one. It reports false when no unverified review matches.
+func (v *Verifier) Resend(ctx context.Context, courseCode, reviewID, email string) (bool, error) {
+	token, hash, err := newToken()
+	if err != nil {
+		return false, err
+	}
+	ok, err := v.store.Reissue(ctx, courseCode, reviewID, email, hash, time.Now().Add(v.ttl))
+	if err != nil {
+		return false, fmt.Errorf("reissue verification: %w", err)
+	}
+	if !ok {
+		return false, nil
+	}
+	return true, v.send(ctx, email, courseCode, token)
+}
+
+// Confirm verifies the review token was mailed for and returns it, or nil
+// if the token is unknown, already used or expired.
+func (v *Verifier) Confirm(ctx context.Context, token string) (*models.Review, error) {
+	if token == "" {
+		return nil, nil
+	}
+	return v.store.Confirm(ctx, hashToken(token))
+}
+
+func (v *Verifier) send(ctx context.Context, to, courseCode, token string) error {
+	link := v.linkURL + "?token=" + url.QueryEscape(token)
+	body := fmt.Sprintf("Thanks for reviewing %s on YU Plan.\n\n"+
+		"Follow this link to confirm it's your review and get it published:\n\n%s\n\n"+
+		"The link expires in %s. If you didn't write a review, ignore this email and it will never appear.\n",
+		courseCode, link, formatTTL(v.ttl))
+	err := v.mailer.Send(ctx, mailer.Message{
+		To:      to,
+		Subject: "Confirm your review of " + courseCode,
+		Body:    body,
+	})
+	if err != nil {
+		return fmt.Errorf("send verification email: %w", err)
+	}
+	return nil
+}
+
+// newToken returns a random link token and the hash stored for it.
+func newToken() (token, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("generate token: %w", err)
+	}
+	token = hex.EncodeToString(b)
+	return token, hashToken(token), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func formatTTL(d time.Duration) string {
+	if d >= 48*time.Hour && d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%d days", d/(24*time.Hour))
+	}
+	if d >= 2*time.Hour && d%time.Hour == 0 {
+		return fmt.Sprintf("%d hours", d/time.Hour)
+	}
+	return d.String()
+}
@@ -0,0 +1,129 @@
+package verification
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+	"yuplan/internal/mailer"
+	"yuplan/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeStore struct {
+	saved      map[string]string // token hash -> review id
+	moderation string
+	reissued   bool
+	saveErr    error
+}
+
+func (f *fakeStore) Save(ctx context.Context, reviewID, tokenHash, moderation string, expiresAt time.Time) error {
+	if f.saved == nil {
+		f.saved = map[string]string{}
+	}
+	f.saved[tokenHash] = reviewID
+	f.moderation = moderation
+	return f.saveErr
+}
+
+func (f *fakeStore) Reissue(ctx context.Context, courseCode, reviewID, email, tokenHash string, expiresAt time.Time) (bool, error) {
+	if !f.reissued {
+		return false, nil
+	}
+	f.saved = map[string]string{tokenHash: reviewID}
+	return true, nil
+}
+
+func (f *fakeStore) Confirm(ctx context.Context, tokenHash string) (*models.Review, error) {
+	reviewID, ok := f.saved[tokenHash]
+	if !ok {
+		return nil, nil
+	}
+	delete(f.saved, tokenHash)
+	return &models.Review{ID: reviewID, Moderation: f.moderation}, nil
+}
+
+type fakeMailer struct{ sent []mailer.Message }
+
+func (f *fakeMailer) Send(ctx context.Context, msg mailer.Message) error {
+	f.sent = append(f.sent, msg)
+	return nil
+}
+
+// linkToken pulls the token out of the link in a verification email.
+func linkToken(t *testing.T, body string) string {
+	t.Helper()
+	for _, line := range strings.Split(body, "\n") {
+		if strings.HasPrefix(line, "https://") {
+			u, err := url.Parse(line)
+			assert.NoError(t, err)
+			return u.Query().Get("token")
+		}
+	}
+	t.Fatalf("no link in %q", body)
+	return ""
+}
+
+func TestRequestAndConfirm(t *testing.T) {
+	store, m := &fakeStore{}, &fakeMailer{}
+	v := New(store, m, "https://yuplan.test/verify", 72*time.Hour)
+
+	err := v.Request(context.Background(), &models.Review{ID: "review-1", CourseCode: "EECS2030", Email: "student@my.yorku.ca"}, models.ModerationPending)
+	assert.NoError(t, err)
+	assert.Len(t, m.sent, 1)
+	assert.Equal(t, "student@my.yorku.ca", m.sent[0].To)
+	assert.Equal(t, "Confirm your review of EECS2030", m.sent[0].Subject)
+	assert.Contains(t, m.sent[0].Body, "expires in 3 days")
+
+	token := linkToken(t, m.sent[0].Body)
+	assert.Len(t, token, 64)
+	assert.NotContains(t, store.saved, token, "only the hash is stored")
+
+	review, err := v.Confirm(context.Background(), token)
+	assert.NoError(t, err)
+	assert.Equal(t, "review-1", review.ID)
+	assert.Equal(t, models.ModerationPending, review.Moderation)
+
+	review, err = v.Confirm(context.Background(), token)
+	assert.NoError(t, err)
+	assert.Nil(t, review, "a token only works once")
+}
+
+func TestRequest_SaveFails(t *testing.T) {
+	m := &fakeMailer{}
+	v := New(&fakeStore{saveErr: errors.New("db down")}, m, "https://yuplan.test/verify", time.Hour)
+
+	err := v.Request(context.Background(), &models.Review{ID: "review-1"}, models.ModerationApproved)
+	assert.ErrorContains(t, err, "save verification")
+	assert.Empty(t, m.sent, "no link is mailed that could never be confirmed")
+}
+
+func TestResend(t *testing.T) {
+	m := &fakeMailer{}
+	v := New(&fakeStore{}, m, "https://yuplan.test/verify", time.Hour)
+	sent, err := v.Resend(context.Background(), "EECS2030", "review-1", "student@my.yorku.ca")
+	assert.NoError(t, err)
+	assert.False(t, sent)
+	assert.Empty(t, m.sent)
+
+	store := &fakeStore{reissued: true}
+	v = New(store, m, "https://yuplan.test/verify", time.Hour)
+	sent, err = v.Resend(context.Background(), "EECS2030", "review-1", "student@my.yorku.ca")
+	assert.NoError(t, err)
+	assert.True(t, sent)
+	assert.Contains(t, m.sent[0].Body, "expires in 1h0m0s")
+
+	review, err := v.Confirm(context.Background(), linkToken(t, m.sent[0].Body))
+	assert.NoError(t, err)
+	assert.Equal(t, "review-1", review.ID)
+}
+
+func TestConfirm_EmptyToken(t *testing.T) {
+	v := New(&fakeStore{}, &fakeMailer{}, "https://yuplan.test/verify", time.Hour)
+	review, err := v.Confirm(context.Background(), "")
+	assert.NoError(t, err)
+	assert.Nil(t, review)
+}
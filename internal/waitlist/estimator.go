@@ -0,0 +1,135 @@
+// Package waitlist estimates the odds of getting into a full section from a
+// waitlist position, from how many seats opened up in past offerings of the
+// same course after they filled.
+package waitlist
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"yuplan/internal/models"
+)
+
+// History loads enrollment snapshots. Implemented by repository.AvailabilityRepository.
+type History interface {
+	PastOfferingSnapshots(ctx context.Context, sectionID string) (bool, []models.EnrollmentSnapshot, error)
+}
+
+// z is the normal quantile of the 95% Wilson score interval.
+const z = 1.96
+
+// Below these many past sections the estimate is low or medium confidence.
+const (
+	lowConfidenceBelow    = 5
+	mediumConfidenceBelow = 15
+)
+
+// Caveats shown with every estimate, and with thin or missing history.
+const (
+	caveatModel     = "Estimated from past offerings of this course; it is not a guarantee, and reserved seats and waitlist rules are not taken into account"
+	caveatSampling  = "Seat counts are sampled, so a seat that opened and was taken between samples is missed and the odds may be understated"
+	caveatNoHistory = "No past offering of this course filled up since enrolment history began, so there is nothing to estimate from"
+	caveatThin      = "Only %d past section(s) filled up; treat this as a rough guess"
+)
+
+type Estimator struct {
+	history History
+}
+
+func NewEstimator(history History) *Estimator {
+	return &Estimator{history: history}
+}
+
+// Odds estimates the odds of getting into sectionID from position on its
+// waitlist, or returns nil if there is no such section.
+func (e *Estimator) Odds(ctx context.Context, sectionID string, position int) (*models.WaitlistOdds, error) {
+	found, snapshots, err := e.history.PastOfferingSnapshots(ctx, sectionID)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	odds := Estimate(position, SeatsOpened(snapshots))
+	odds.SectionID = sectionID
+	return &odds, nil
+}
+
+// SeatsOpened returns, for each section in snapshots that filled up, how many
+// seats opened after it first did. Every rise in free seats (capacity less
+// enrolled) from one snapshot to the next counts, so seats freed by a drop or
+// added by raising the capacity both do. Snapshots must be grouped by section
+// and oldest first within each.
+func SeatsOpened(snapshots []models.EnrollmentSnapshot) []int {
+	var opened []int
+	for start := 0; start < len(snapshots); {
+		end := start
+		for end < len(snapshots) && snapshots[end].SectionID == snapshots[start].SectionID {
+			end++
+		}
+		filled, seats := false, 0
+		for i := start; i < end; i++ {
+			s := snapshots[i]
+			if filled {
+				prev := snapshots[i-1]
+				seats += max(0, (s.Capacity-s.Enrolled)-(prev.Capacity-prev.Enrolled))
+			} else if s.Capacity > 0 && s.Enrolled >= s.Capacity {
+				filled = true
+			}
+		}
+		if filled {
+			opened = append(opened, seats)
+		}
+		start = end
+	}
+	return opened
+}
+
+// Estimate turns the seats opened in past sections into odds for position:
+// the share of them in which at least position seats opened, with a 95%
+// Wilson score interval.
+func Estimate(position int, opened []int) models.WaitlistOdds {
+	odds := models.WaitlistOdds{
+		Position:   position,
+		SampleSize: len(opened),
+		Caveats:    []string{caveatModel, caveatSampling},
+	}
+	n := len(opened)
+	switch {
+	case n == 0:
+		odds.Confidence = models.WaitlistConfidenceNone
+		odds.Caveats = append(odds.Caveats, caveatNoHistory)
+		return odds
+	case n < lowConfidenceBelow:
+		odds.Confidence = models.WaitlistConfidenceLow
+		odds.Caveats = append(odds.Caveats, fmt.Sprintf(caveatThin, n))
+	case n < mediumConfidenceBelow:
+		odds.Confidence = models.WaitlistConfidenceMedium
+	default:
+		odds.Confidence = models.WaitlistConfidenceHigh
+	}
+
+	admitted := 0
+	for _, seats := range opened {
+		if seats >= position {
+			admitted++
+		}
+	}
+	p := float64(admitted) / float64(n)
+	low, high := wilson(p, float64(n))
+	odds.Probability, odds.ConfidenceLow, odds.ConfidenceHigh = rounded(p), rounded(low), rounded(high)
+	return odds
+}
+
+// wilson returns the 95% Wilson score interval of a proportion p observed over n trials.
+func wilson(p, n float64) (low, high float64) {
+	denominator := 1 + z*z/n
+	centre := (p + z*z/(2*n)) / denominator
+	spread := z * math.Sqrt(p*(1-p)/n+z*z/(4*n*n)) / denominator
+	return max(0, centre-spread), min(1, centre+spread)
+}
+
+func rounded(x float64) *float64 {
+	r := math.Round(x*1000) / 1000
+	return &r
+}
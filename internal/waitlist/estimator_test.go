@@ -0,0 +1,89 @@
+package waitlist
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"yuplan/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func snapshot(section string, capacity, enrolled int, day int) models.EnrollmentSnapshot {
+	return models.EnrollmentSnapshot{
+		SectionID:  section,
+		Capacity:   capacity,
+		Enrolled:   enrolled,
+		RecordedAt: time.Date(2025, 9, day, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestSeatsOpened(t *testing.T) {
+	opened := SeatsOpened([]models.EnrollmentSnapshot{
+		// Drops before filling don't count; after it two drops and a capacity raise do
+		snapshot("a", 100, 90, 1), snapshot("a", 100, 80, 2), snapshot("a", 100, 100, 3),
+		snapshot("a", 100, 99, 4), snapshot("a", 100, 100, 5), snapshot("a", 100, 99, 6),
+		snapshot("a", 110, 99, 7),
+		// Never filled, so it had no waitlist
+		snapshot("b", 50, 40, 1), snapshot("b", 50, 45, 2),
+		// Over-enrolled, then back under capacity
+		snapshot("c", 30, 32, 1), snapshot("c", 30, 29, 2),
+		// Full with nothing freed
+		snapshot("d", 20, 20, 1),
+	})
+	assert.Equal(t, []int{12, 3, 0}, opened)
+}
+
+func TestEstimate(t *testing.T) {
+	odds := Estimate(3, []int{0, 1, 3, 5, 8, 13, 2, 4, 1, 0})
+	assert.Equal(t, 10, odds.SampleSize)
+	assert.Equal(t, models.WaitlistConfidenceMedium, odds.Confidence)
+	assert.Equal(t, 0.5, *odds.Probability)
+	assert.InDelta(t, 0.237, *odds.ConfidenceLow, 0.001)
+	assert.InDelta(t, 0.763, *odds.ConfidenceHigh, 0.001)
+	assert.Len(t, odds.Caveats, 2)
+
+	odds = Estimate(1, []int{2, 0})
+	assert.Equal(t, models.WaitlistConfidenceLow, odds.Confidence)
+	assert.Equal(t, 0.5, *odds.Probability)
+	assert.Contains(t, odds.Caveats[2], "Only 2 past section(s)")
+
+	odds = Estimate(1, nil)
+	assert.Equal(t, models.WaitlistConfidenceNone, odds.Confidence)
+	assert.Nil(t, odds.Probability)
+	assert.Nil(t, odds.ConfidenceLow)
+	assert.Len(t, odds.Caveats, 3)
+
+	odds = Estimate(1, make([]int, 20))
+	assert.Equal(t, models.WaitlistConfidenceHigh, odds.Confidence)
+	assert.Equal(t, 0.0, *odds.Probability)
+	assert.Equal(t, 0.0, *odds.ConfidenceLow)
+}
+
+type fakeHistory struct {
+	found     bool
+	snapshots []models.EnrollmentSnapshot
+	err       error
+}
+
+func (f fakeHistory) PastOfferingSnapshots(ctx context.Context, sectionID string) (bool, []models.EnrollmentSnapshot, error) {
+	return f.found, f.snapshots, f.err
+}
+
+func TestEstimatorOdds(t *testing.T) {
+	history := fakeHistory{found: true, snapshots: []models.EnrollmentSnapshot{
+		snapshot("a", 10, 10, 1), snapshot("a", 10, 8, 2),
+	}}
+	odds, err := NewEstimator(history).Odds(context.Background(), "section-1", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, "section-1", odds.SectionID)
+	assert.Equal(t, 1.0, *odds.Probability)
+
+	odds, err = NewEstimator(fakeHistory{}).Odds(context.Background(), "missing", 1)
+	assert.NoError(t, err)
+	assert.Nil(t, odds)
+
+	_, err = NewEstimator(fakeHistory{err: errors.New("db down")}).Odds(context.Background(), "section-1", 1)
+	assert.Error(t, err)
+}
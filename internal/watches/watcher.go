@@ -0,0 +1,125 @@
+// Package watches mails the people watching a full section when seats open
+// in it. Seat counts come from the scraper through the availability ingest;
+// the watcher only reads them.
+package watches
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+	"yuplan/internal/jobs"
+	"yuplan/internal/mailer"
+	"yuplan/internal/models"
+)
+
+// Store finds and records seat openings. Implemented by repository.SeatWatchRepository.
+type Store interface {
+	// Rearm clears notified watches whose section has filled up again.
+	Rearm(ctx context.Context) (int, error)
+	ListOpenings(ctx context.Context) ([]models.SeatOpening, error)
+	MarkNotified(ctx context.Context, watchID string) error
+}
+
+// jobLocker keeps scheduled runs to one instance at a time, once per tick. Implemented by jobs.Locker.
+type jobLocker interface {
+	Do(ctx context.Context, job string, tick time.Time, fn func(ctx context.Context) error) (bool, error)
+}
+
+// Watcher polls watched sections and mails each watcher once per opening.
+type Watcher struct {
+	store  Store
+	mailer mailer.Mailer
+	locker jobLocker
+}
+
+func NewWatcher(store Store, m mailer.Mailer) *Watcher {
+	return &Watcher{store: store, mailer: m}
+}
+
+// WithLocker makes Start skip runs while another instance holds the watch lock.
+func (w *Watcher) WithLocker(locker jobLocker) *Watcher {
+	w.locker = locker
+	return w
+}
+
+// Run mails every watcher whose section has seats and returns how many were
+// mailed. A watch whose email fails stays pending for the next run; the
+// first such error is returned once the rest have been tried.
+func (w *Watcher) Run(ctx context.Context) (int, error) {
+	if _, err := w.store.Rearm(ctx); err != nil {
+		return 0, err
+	}
+	openings, err := w.store.ListOpenings(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	var firstErr error
+	for _, opening := range openings {
+		if err := w.notify(ctx, opening); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if err := w.store.MarkNotified(ctx, opening.WatchID); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+	return sent, firstErr
+}
+
+func (w *Watcher) notify(ctx context.Context, opening models.SeatOpening) error {
+	seats := "A seat has"
+	if opening.SeatsRemaining > 1 {
+		seats = fmt.Sprintf("%d seats have", opening.SeatsRemaining)
+	}
+	body := fmt.Sprintf("%s opened up in %s %s, section %s (%s).\n\n"+
+		"Seats go quickly, so enrol soon if you still want one. You'll hear from us again "+
+		"only if the section fills up and a seat opens once more.\n",
+		seats, opening.CourseCode, opening.CourseName, opening.Letter, opening.TermID)
+	err := w.mailer.Send(ctx, mailer.Message{
+		To:      opening.Email,
+		Subject: fmt.Sprintf("Seats open in %s section %s", opening.CourseCode, opening.Letter),
+		Body:    body,
+	})
+	if err != nil {
+		return fmt.Errorf("send seat opening email: %w", err)
+	}
+	return nil
+}
+
+// Start checks immediately and then every interval until ctx is done.
+func (w *Watcher) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := w.runScheduled(ctx, jobs.Tick(time.Now(), interval)); err != nil {
+				log.Printf("seat watch check failed: %v", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (w *Watcher) runScheduled(ctx context.Context, tick time.Time) error {
+	run := func(ctx context.Context) error {
+		_, err := w.Run(ctx)
+		return err
+	}
+	if w.locker == nil {
+		return run(ctx)
+	}
+	_, err := w.locker.Do(ctx, "seat_watches", tick, run)
+	return err
+}
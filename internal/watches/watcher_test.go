@@ -0,0 +1,84 @@
+package watches
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"yuplan/internal/mailer"
+	"yuplan/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeStore struct {
+	rearmed  bool
+	openings []models.SeatOpening
+	notified []string
+	err      error
+}
+
+func (f *fakeStore) Rearm(ctx context.Context) (int, error) {
+	f.rearmed = true
+	return 0, f.err
+}
+
+func (f *fakeStore) ListOpenings(ctx context.Context) ([]models.SeatOpening, error) {
+	return f.openings, nil
+}
+
+func (f *fakeStore) MarkNotified(ctx context.Context, watchID string) error {
+	f.notified = append(f.notified, watchID)
+	return nil
+}
+
+type fakeMailer struct {
+	sent   []mailer.Message
+	failTo string
+}
+
+func (f *fakeMailer) Send(ctx context.Context, msg mailer.Message) error {
+	if msg.To == f.failTo {
+		return errors.New("mailbox unavailable")
+	}
+	f.sent = append(f.sent, msg)
+	return nil
+}
+
+func TestWatcher_Run(t *testing.T) {
+	store := &fakeStore{openings: []models.SeatOpening{
+		{WatchID: "w-1", Email: "a@my.yorku.ca", CourseCode: "EECS2030", CourseName: "Advanced OOP", Letter: "A", TermID: "FW2025", SeatsRemaining: 3},
+		{WatchID: "w-2", Email: "b@my.yorku.ca", CourseCode: "MATH1090", CourseName: "Logic", Letter: "M", TermID: "FW2025", SeatsRemaining: 1},
+	}}
+	m := &fakeMailer{}
+
+	n, err := NewWatcher(store, m).Run(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.True(t, store.rearmed)
+	assert.Equal(t, []string{"w-1", "w-2"}, store.notified)
+	assert.Equal(t, "Seats open in EECS2030 section A", m.sent[0].Subject)
+	assert.Contains(t, m.sent[0].Body, "3 seats have opened up in EECS2030 Advanced OOP, section A (FW2025)")
+	assert.Contains(t, m.sent[1].Body, "A seat has opened up")
+}
+
+func TestWatcher_Run_FailedEmailStaysPending(t *testing.T) {
+	store := &fakeStore{openings: []models.SeatOpening{
+		{WatchID: "w-1", Email: "bounce@my.yorku.ca"},
+		{WatchID: "w-2", Email: "b@my.yorku.ca"},
+	}}
+
+	n, err := NewWatcher(store, &fakeMailer{failTo: "bounce@my.yorku.ca"}).Run(context.Background())
+
+	assert.ErrorContains(t, err, "mailbox unavailable")
+	assert.Equal(t, 1, n)
+	assert.Equal(t, []string{"w-2"}, store.notified)
+}
+
+func TestWatcher_Run_StoreError(t *testing.T) {
+	store := &fakeStore{err: errors.New("db down")}
+
+	_, err := NewWatcher(store, &fakeMailer{}).Run(context.Background())
+
+	assert.ErrorContains(t, err, "db down")
+}
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS reviews_audit ON reviews;
+DROP FUNCTION IF EXISTS audit_reviews();
+DROP TABLE IF EXISTS audit_log;
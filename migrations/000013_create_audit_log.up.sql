@@ -0,0 +1,30 @@
+-- Append-only audit log of changes to user-generated data (reviews)
+CREATE TABLE audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    entity VARCHAR(50) NOT NULL,
+    entity_id TEXT NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    details JSONB,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_audit_log_created_at ON audit_log(created_at);
+CREATE INDEX idx_audit_log_entity ON audit_log(entity, entity_id);
+
+-- Record every review insert/update/delete. Email is stripped from the snapshot.
+CREATE OR REPLACE FUNCTION audit_reviews() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        INSERT INTO audit_log (entity, entity_id, action, details)
+        VALUES ('review', OLD.id::text, 'delete', to_jsonb(OLD) - 'email');
+        RETURN OLD;
+    END IF;
+    INSERT INTO audit_log (entity, entity_id, action, details)
+    VALUES ('review', NEW.id::text, LOWER(TG_OP), to_jsonb(NEW) - 'email');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER reviews_audit
+AFTER INSERT OR UPDATE OR DELETE ON reviews
+FOR EACH ROW EXECUTE FUNCTION audit_reviews();
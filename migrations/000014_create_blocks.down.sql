@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS block_activities;
+DROP TABLE IF EXISTS blocks;
@@ -0,0 +1,20 @@
+-- Blocks bundle specific activities (e.g. lecture + lab + tutorial) that some
+-- programs enroll students into as a fixed unit.
+CREATE TABLE blocks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+
+    UNIQUE(course_id, name)
+);
+
+CREATE TABLE block_activities (
+    block_id UUID NOT NULL REFERENCES blocks(id) ON DELETE CASCADE,
+    activity_id UUID NOT NULL REFERENCES section_activities(id) ON DELETE CASCADE,
+    PRIMARY KEY (block_id, activity_id)
+);
+
+CREATE INDEX idx_blocks_course ON blocks(course_id);
+CREATE INDEX idx_block_activities_activity ON block_activities(activity_id);
@@ -0,0 +1,10 @@
+-- Anonymized daily search counts: only the normalized query text is stored, never who searched.
+CREATE TABLE search_stats (
+    query TEXT NOT NULL,
+    day DATE NOT NULL,
+    searches INTEGER NOT NULL DEFAULT 0,
+    zero_results INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (query, day)
+);
+
+CREATE INDEX idx_search_stats_day ON search_stats(day);
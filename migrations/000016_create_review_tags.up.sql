@@ -0,0 +1,8 @@
+-- "Who should take this" tags chosen by reviewers from the curated list in internal/models/enums.go
+CREATE TABLE review_tags (
+    review_id UUID NOT NULL REFERENCES reviews(id) ON DELETE CASCADE,
+    tag VARCHAR(50) NOT NULL,
+    PRIMARY KEY (review_id, tag)
+);
+
+CREATE INDEX idx_review_tags_tag ON review_tags(tag);
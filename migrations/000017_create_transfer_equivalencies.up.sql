@@ -0,0 +1,16 @@
+-- Curated mappings from courses at other institutions to York courses.
+-- institution and external_course_code are stored normalized (see models.NormalizeCourseCode).
+CREATE TABLE transfer_equivalencies (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    institution VARCHAR(200) NOT NULL,
+    external_course_code VARCHAR(50) NOT NULL,
+    york_course_code VARCHAR(20) NOT NULL,
+    confidence VARCHAR(10) NOT NULL CHECK (confidence IN ('high', 'medium', 'low')),
+    notes TEXT,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+
+    UNIQUE(institution, external_course_code, york_course_code)
+);
+
+CREATE INDEX idx_transfer_equivalencies_york ON transfer_equivalencies(york_course_code);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS course_offering_summaries;
+DROP TABLE IF EXISTS course_offerings;
@@ -0,0 +1,26 @@
+-- History of which terms each course code was offered in. The seed replaces
+-- courses every session, so this table is keyed by code (no foreign key) and
+-- only ever appended to by scripts/seed.sh.
+CREATE TABLE course_offerings (
+    code VARCHAR(50) NOT NULL,
+    academic_year INTEGER NOT NULL, -- year the session starts, e.g. 2025 for 2025-2026 (including Summer 2026)
+    term VARCHAR(10) NOT NULL,
+    recorded_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (code, academic_year, term)
+);
+
+-- Derived from course_offerings by the offering refresh job
+CREATE TABLE course_offering_summaries (
+    code VARCHAR(50) PRIMARY KEY,
+    last_offered_year INTEGER NOT NULL,
+    last_offered_term VARCHAR(10) NOT NULL,
+    frequency VARCHAR(20) NOT NULL,
+    terms TEXT[] NOT NULL DEFAULT '{}',
+    years_observed INTEGER NOT NULL,
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+-- The data loaded so far is the 2025-2026 session
+INSERT INTO course_offerings (code, academic_year, term)
+SELECT DISTINCT code, 2025, term FROM courses WHERE term IS NOT NULL AND term <> ''
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,14 @@
+-- Scraped records that failed validation in scripts/generate_seed.py. They are
+-- written by seed.sql instead of being inserted, and refilled on every reseed.
+CREATE TABLE seed_quarantine (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    source VARCHAR(200) NOT NULL, -- scraped file, e.g. summer-2026/schulich.json
+    record_key VARCHAR(100) NOT NULL, -- course code and term, e.g. EECS2030 F
+    record JSONB NOT NULL,
+    reasons TEXT[] NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'reprocessed', 'dismissed')),
+    created_at TIMESTAMP DEFAULT NOW(),
+    resolved_at TIMESTAMP
+);
+
+CREATE INDEX idx_seed_quarantine_status ON seed_quarantine(status);
@@ -0,0 +1,8 @@
+-- Top review terms per course, rebuilt from reviews by the keyword aggregation job
+CREATE TABLE review_keywords (
+    course_code VARCHAR(20) NOT NULL,
+    term VARCHAR(100) NOT NULL, -- a word or two-word phrase
+    count INTEGER NOT NULL, -- number of reviews that use the term
+    updated_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (course_code, term)
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_reviews_publish_at;
+ALTER TABLE reviews DROP COLUMN IF EXISTS publish_at;
+DROP TABLE IF EXISTS academic_terms;
@@ -0,0 +1,17 @@
+-- Exam and grade-release dates per term, maintained through the admin API.
+-- Reviews submitted during an exam period are held until that term's grades are out.
+CREATE TABLE academic_terms (
+    academic_year INTEGER NOT NULL, -- year the session starts, as in course_offerings
+    term VARCHAR(10) NOT NULL,
+    exams_start TIMESTAMP NOT NULL,
+    exams_end TIMESTAMP NOT NULL,
+    grades_released TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (academic_year, term),
+    CHECK (exams_start < exams_end AND exams_end <= grades_released)
+);
+
+-- NULL means published on submission; otherwise the review stays hidden until then
+ALTER TABLE reviews ADD COLUMN publish_at TIMESTAMP;
+
+CREATE INDEX idx_reviews_publish_at ON reviews(publish_at) WHERE publish_at IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS reviewer_badges;
+DROP TABLE IF EXISTS badges;
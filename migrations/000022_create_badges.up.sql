@@ -0,0 +1,23 @@
+-- Badge rules. A reviewer earns a badge once their count for the rule's metric
+-- reaches threshold; admins add rules through /api/v1/admin/badges.
+CREATE TABLE badges (
+    slug VARCHAR(50) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    metric VARCHAR(30) NOT NULL CHECK (metric IN ('reviews', 'department_reviews')),
+    threshold INTEGER NOT NULL CHECK (threshold >= 1),
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+-- Badges each reviewer holds, rebuilt by the badge job. Reviewers are
+-- identified by email, as in reviews.
+CREATE TABLE reviewer_badges (
+    email VARCHAR(255) NOT NULL,
+    badge VARCHAR(50) NOT NULL REFERENCES badges(slug) ON DELETE CASCADE,
+    awarded_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (email, badge)
+);
+
+INSERT INTO badges (slug, name, description, metric, threshold) VALUES
+('first-review', 'First Review', 'Published a course review', 'reviews', 1),
+('department-expert', 'Department Expert', 'Reviewed five courses in one department', 'department_reviews', 5);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_reviews_course_delivery_mode;
+ALTER TABLE reviews DROP COLUMN IF EXISTS delivery_mode;
@@ -0,0 +1,5 @@
+-- How the reviewer took the course; NULL when they didn't say
+ALTER TABLE reviews ADD COLUMN delivery_mode VARCHAR(20)
+    CHECK (delivery_mode IN ('in_person', 'online', 'hybrid'));
+
+CREATE INDEX idx_reviews_course_delivery_mode ON reviews(course_code, delivery_mode) WHERE delivery_mode IS NOT NULL;
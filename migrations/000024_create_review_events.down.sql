@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS review_events_append_only ON review_events;
+DROP FUNCTION IF EXISTS review_events_append_only();
+DROP TABLE IF EXISTS review_events;
@@ -0,0 +1,22 @@
+-- Append-only review lifecycle events for funnel analytics. review_id has no
+-- foreign key so a review's history outlives the review.
+CREATE TABLE review_events (
+    id BIGSERIAL PRIMARY KEY,
+    review_id UUID NOT NULL,
+    event VARCHAR(20) NOT NULL CHECK (event IN ('created', 'verified', 'edited', 'reported', 'moderated', 'deleted')),
+    details JSONB,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_review_events_event_created_at ON review_events(event, created_at);
+CREATE INDEX idx_review_events_review ON review_events(review_id);
+
+CREATE OR REPLACE FUNCTION review_events_append_only() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'review_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER review_events_append_only
+BEFORE UPDATE OR DELETE ON review_events
+FOR EACH ROW EXECUTE FUNCTION review_events_append_only();
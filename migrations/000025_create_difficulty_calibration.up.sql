@@ -0,0 +1,9 @@
+-- Per-department baseline for course difficulty, rebuilt by the calibration job.
+-- A department is the letters of a course code.
+CREATE TABLE difficulty_calibration (
+    department VARCHAR(10) PRIMARY KEY,
+    mean DOUBLE PRECISION NOT NULL,
+    stddev DOUBLE PRECISION NOT NULL,
+    courses INTEGER NOT NULL,
+    updated_at TIMESTAMP DEFAULT NOW()
+);
@@ -0,0 +1,11 @@
+-- Only the latest review per email per course survives going back to one review per course
+DELETE FROM reviews r
+USING reviews newer
+WHERE newer.course_code = r.course_code AND newer.email = r.email
+  AND (newer.created_at, newer.id) > (r.created_at, r.id);
+
+ALTER TABLE reviews DROP CONSTRAINT IF EXISTS reviews_course_code_email_term_key;
+ALTER TABLE reviews ADD CONSTRAINT reviews_course_code_email_key UNIQUE (course_code, email);
+
+ALTER TABLE reviews DROP COLUMN IF EXISTS term;
+ALTER TABLE reviews DROP COLUMN IF EXISTS academic_year;
@@ -0,0 +1,22 @@
+-- The term a review is about, so students who retake a course can review each
+-- attempt. academic_year is the year the session starts, as in course_offerings.
+ALTER TABLE reviews ADD COLUMN academic_year INTEGER;
+ALTER TABLE reviews ADD COLUMN term VARCHAR(10);
+
+-- Existing reviews are attributed to the term in progress when they were
+-- written: Sep-Dec fall, Jan-Apr winter, May-Aug summer
+UPDATE reviews SET
+    academic_year = CASE WHEN EXTRACT(MONTH FROM created_at) >= 9
+        THEN EXTRACT(YEAR FROM created_at) ELSE EXTRACT(YEAR FROM created_at) - 1 END,
+    term = CASE
+        WHEN EXTRACT(MONTH FROM created_at) >= 9 THEN 'F'
+        WHEN EXTRACT(MONTH FROM created_at) <= 4 THEN 'W'
+        ELSE 'SU'
+    END;
+
+ALTER TABLE reviews ALTER COLUMN academic_year SET NOT NULL;
+ALTER TABLE reviews ALTER COLUMN term SET NOT NULL;
+
+-- One review per email per course per term, instead of per course
+ALTER TABLE reviews DROP CONSTRAINT reviews_course_code_email_key;
+ALTER TABLE reviews ADD CONSTRAINT reviews_course_code_email_term_key UNIQUE (course_code, email, academic_year, term);
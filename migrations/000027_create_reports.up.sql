@@ -0,0 +1,18 @@
+-- Reports from users about course and instructor metadata, e.g. a wrong
+-- instructor or a dead RateMyProfessors link, worked through by admins.
+-- entity_id is a courses.id or instructors.id depending on entity_type; it is
+-- not a foreign key so reports outlive a reseed.
+CREATE TABLE reports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    type VARCHAR(50) NOT NULL CHECK (type IN ('wrong_instructor_info', 'broken_rmp_link', 'offensive_course_resource')),
+    entity_type VARCHAR(20) NOT NULL CHECK (entity_type IN ('course', 'instructor')),
+    entity_id UUID NOT NULL,
+    details TEXT,
+    email VARCHAR(255), -- optional, for follow-up
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'resolved', 'dismissed')),
+    created_at TIMESTAMP DEFAULT NOW(),
+    resolved_at TIMESTAMP
+);
+
+CREATE INDEX idx_reports_status ON reports(status);
+CREATE INDEX idx_reports_entity ON reports(entity_type, entity_id);
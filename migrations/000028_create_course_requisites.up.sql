@@ -0,0 +1,14 @@
+-- Prerequisites, corequisites and exclusions between courses, by course code
+-- since they hold across terms. A course needs one course from every group of
+-- its prerequisites: groups are ANDed, the courses within one are ORed.
+-- Corequisites group the same way; exclusions use group 0.
+CREATE TABLE course_requisites (
+    course_code VARCHAR(50) NOT NULL,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('prerequisite', 'corequisite', 'exclusion')),
+    group_no INTEGER NOT NULL DEFAULT 0,
+    requisite_code VARCHAR(50) NOT NULL,
+    PRIMARY KEY (course_code, kind, group_no, requisite_code),
+    CHECK (requisite_code <> course_code)
+);
+
+CREATE INDEX idx_course_requisites_requisite ON course_requisites(requisite_code);
@@ -0,0 +1,11 @@
+-- Courses a reviewer has seen in the randomized course feed, so the feed can
+-- skip them for a while (COURSE_SEEN_TTL_DAYS). Rows older than that are
+-- purged by the course_views retention policy.
+CREATE TABLE course_views (
+    email VARCHAR(255) NOT NULL,
+    course_code VARCHAR(50) NOT NULL,
+    seen_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (email, course_code)
+);
+
+CREATE INDEX idx_course_views_seen_at ON course_views(seen_at);
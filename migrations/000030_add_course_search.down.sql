@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_courses_compact_code_trgm;
+DROP INDEX IF EXISTS idx_courses_name_trgm;
+DROP INDEX IF EXISTS idx_courses_search_vector;
+ALTER TABLE courses DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,13 @@
+-- Ranked course search: a weighted full-text vector over code, name and
+-- description, plus trigram indexes for partial and misspelled names and codes.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE courses ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', coalesce(code, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(name, '')), 'B') ||
+    setweight(to_tsvector('english', coalesce(description, '')), 'C')
+) STORED;
+
+CREATE INDEX idx_courses_search_vector ON courses USING GIN (search_vector);
+CREATE INDEX idx_courses_name_trgm ON courses USING GIN (name gin_trgm_ops);
+CREATE INDEX idx_courses_compact_code_trgm ON courses USING GIN ((REPLACE(code, ' ', '')) gin_trgm_ops);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS moderation_rules;
+DROP INDEX IF EXISTS idx_reviews_pending;
+ALTER TABLE reviews DROP COLUMN IF EXISTS moderation;
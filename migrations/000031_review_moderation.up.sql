@@ -0,0 +1,24 @@
+-- Review auto-moderation. Each new review is checked against the enabled
+-- moderation_rules in position order; the first rule whose conditions all
+-- hold approves it or queues it for an admin, and a review no rule matches is
+-- queued. With no enabled rules every review is approved, as before.
+ALTER TABLE reviews ADD COLUMN moderation VARCHAR(20) NOT NULL DEFAULT 'approved' CHECK (moderation IN ('approved', 'pending'));
+
+CREATE INDEX idx_reviews_pending ON reviews(created_at) WHERE moderation = 'pending';
+
+-- Conditions left at their defaults don't constrain anything. Admins replace
+-- the whole ordered list through /api/v1/admin/moderation/rules.
+CREATE TABLE moderation_rules (
+    id BIGSERIAL PRIMARY KEY,
+    position INTEGER NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    action VARCHAR(20) NOT NULL CHECK (action IN ('approve', 'queue')),
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    no_profanity BOOLEAN NOT NULL DEFAULT FALSE,
+    email_domains TEXT[] NOT NULL DEFAULT '{}',
+    min_author_age_days INTEGER NOT NULL DEFAULT 0 CHECK (min_author_age_days >= 0),
+    max_text_length INTEGER NOT NULL DEFAULT 0 CHECK (max_text_length >= 0),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_moderation_rules_position ON moderation_rules(position);
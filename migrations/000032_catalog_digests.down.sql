@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS department_subscriptions;
+DROP TABLE IF EXISTS digest_subscribers;
+DROP TABLE IF EXISTS catalog_changes;
+DROP TABLE IF EXISTS catalog_snapshot_state;
+DROP TABLE IF EXISTS catalog_snapshot;
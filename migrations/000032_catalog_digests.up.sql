@@ -0,0 +1,50 @@
+-- Department digests. Seeding regenerates every course, section and
+-- instructor id, so catalog changes are found by comparing the catalog with a
+-- snapshot keyed by course code, term and section letter, retaken whenever
+-- _seed_checksum moves on.
+CREATE TABLE catalog_snapshot (
+    course_code VARCHAR(20) NOT NULL,
+    term VARCHAR(10) NOT NULL,
+    letter VARCHAR(10) NOT NULL,       -- '' for a course without sections
+    instructors TEXT NOT NULL          -- sorted full names, comma separated
+);
+
+CREATE INDEX idx_catalog_snapshot_key ON catalog_snapshot(course_code, term, letter);
+
+-- The _seed_checksum the snapshot was taken at; empty until the first snapshot
+CREATE TABLE catalog_snapshot_state (
+    checksum TEXT NOT NULL,
+    taken_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE catalog_changes (
+    id BIGSERIAL PRIMARY KEY,
+    department VARCHAR(10) NOT NULL,
+    kind VARCHAR(30) NOT NULL CHECK (kind IN ('new_course', 'removed_section', 'instructor_change')),
+    course_code VARCHAR(20) NOT NULL,
+    term VARCHAR(10) NOT NULL,
+    section VARCHAR(10) NOT NULL DEFAULT '',
+    before TEXT,                       -- instructors, for removed sections and instructor changes
+    after TEXT,
+    detected_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_catalog_changes_department ON catalog_changes(department, detected_at);
+
+-- One row per email, holding how often it wants a digest. Changes detected
+-- after last_sent_at go in the next one.
+CREATE TABLE digest_subscribers (
+    email VARCHAR(255) PRIMARY KEY,
+    frequency VARCHAR(20) NOT NULL CHECK (frequency IN ('immediate', 'daily', 'weekly')),
+    last_sent_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE department_subscriptions (
+    email VARCHAR(255) NOT NULL REFERENCES digest_subscribers(email) ON DELETE CASCADE,
+    department VARCHAR(10) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (email, department)
+);
+
+CREATE INDEX idx_department_subscriptions_department ON department_subscriptions(department);
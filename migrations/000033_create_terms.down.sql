@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_sections_term_id;
+ALTER TABLE sections DROP COLUMN IF EXISTS term_id;
+DROP TABLE IF EXISTS terms;
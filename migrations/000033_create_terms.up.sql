@@ -0,0 +1,28 @@
+-- Sessions of the academic calendar, so catalog data from different years
+-- doesn't mix. The id is the session code and the calendar year the session
+-- starts in: FW2025 is fall/winter 2025-2026 (courses.term F, W and Y) and
+-- SU2026 the summer after it (SU, S1, S2 and S).
+CREATE TABLE terms (
+    id VARCHAR(10) PRIMARY KEY,
+    session VARCHAR(2) NOT NULL CHECK (session IN ('FW', 'SU')),
+    academic_year INTEGER NOT NULL, -- year the academic year starts, as in course_offerings
+    starts_on DATE NOT NULL,
+    ends_on DATE NOT NULL,
+    UNIQUE (session, academic_year),
+    CHECK (starts_on < ends_on)
+);
+
+-- The data loaded so far is the 2025-2026 session
+INSERT INTO terms (id, session, academic_year, starts_on, ends_on) VALUES
+('FW2025', 'FW', 2025, '2025-09-02', '2026-04-30'),
+('SU2026', 'SU', 2025, '2026-05-04', '2026-08-31');
+
+-- Set by scripts/seed.sh after every seed
+ALTER TABLE sections ADD COLUMN term_id VARCHAR(10) REFERENCES terms(id);
+
+UPDATE sections s
+SET term_id = CASE WHEN c.term IN ('F', 'W', 'Y') THEN 'FW2025' ELSE 'SU2026' END
+FROM courses c
+WHERE c.id = s.course_id;
+
+CREATE INDEX idx_sections_term_id ON sections(term_id, course_id);
@@ -0,0 +1,4 @@
+-- Exact course-code lookups, written as REPLACE(LOWER(code), ' ', '') = $1
+-- so "eecs 2030" finds EECS2030. Backs GET /courses/:course_code and the
+-- code fast path of course search.
+CREATE INDEX idx_courses_normalized_code ON courses ((REPLACE(LOWER(code), ' ', '')));
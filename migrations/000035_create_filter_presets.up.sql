@@ -0,0 +1,14 @@
+-- Named course browsing filters a user saves to rerun later with
+-- GET /courses?preset=. There are no accounts, so presets belong to an email
+-- as digest subscriptions do. An empty filter matches everything.
+CREATE TABLE filter_presets (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    email VARCHAR(255) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    faculty VARCHAR(10) NOT NULL DEFAULT '',
+    course_code_range VARCHAR(10) NOT NULL DEFAULT '', -- e.g. 3000s or 5000s+, as on /courses/paginated
+    term_id VARCHAR(10) NOT NULL DEFAULT '',           -- a terms.id, not a foreign key so presets outlive old terms
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (email, name)
+);
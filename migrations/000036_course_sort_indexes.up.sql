@@ -0,0 +1,11 @@
+-- Indexes behind ?sort= on course listings (see courseOrderBy).
+--
+-- The review keys (avg_difficulty, like_percentage, review_count) aggregate a
+-- course's published reviews per listed course. This covering index lets each
+-- of those lookups read only the index rather than every review row.
+CREATE INDEX idx_reviews_published_stats ON reviews(course_code) INCLUDE (difficulty, liked, publish_at) WHERE moderation = 'approved';
+
+-- level sorts by the course number's thousands digit. It is computed per row
+-- and not indexed: listings sorted by level are nearly always filtered to one
+-- course_code_range, which leaves few rows to sort. name, credits, faculty and
+-- term sort in memory for the same reason; code is already indexed.
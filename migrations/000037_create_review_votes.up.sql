@@ -0,0 +1,11 @@
+-- Whether readers found a review helpful, one vote per email per review. A
+-- second vote from the same email replaces the first. Backs the helpful
+-- counts on course review listings and their most_helpful sort.
+CREATE TABLE review_votes (
+    review_id UUID NOT NULL REFERENCES reviews(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    helpful BOOLEAN NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (review_id, email)
+);
@@ -0,0 +1 @@
+ALTER TABLE instructors DROP COLUMN IF EXISTS photo_id;
@@ -0,0 +1,5 @@
+-- Instructor photos, uploaded by admins. The images live in object storage
+-- under the photo id (see internal/photo); this is only which one an
+-- instructor has. The seed writes one row per section, so a photo is set on
+-- every row with the instructor's name.
+ALTER TABLE instructors ADD COLUMN photo_id VARCHAR(64);
@@ -0,0 +1,9 @@
+ALTER TABLE reviews DROP COLUMN IF EXISTS hidden_at;
+
+DELETE FROM reports WHERE entity_type = 'review';
+ALTER TABLE reports DROP CONSTRAINT reports_entity_type_check;
+ALTER TABLE reports ADD CONSTRAINT reports_entity_type_check
+    CHECK (entity_type IN ('course', 'instructor'));
+ALTER TABLE reports DROP CONSTRAINT reports_type_check;
+ALTER TABLE reports ADD CONSTRAINT reports_type_check
+    CHECK (type IN ('wrong_instructor_info', 'broken_rmp_link', 'offensive_course_resource'));
@@ -0,0 +1,12 @@
+-- Reports on reviews. Once a review has enough open reports
+-- (REVIEW_REPORT_HIDE_THRESHOLD) it is hidden by moving it back to pending
+-- moderation; hidden_at marks reviews hidden that way, so dismissing the
+-- reports can republish them without touching reviews a moderation rule held.
+ALTER TABLE reports DROP CONSTRAINT reports_type_check;
+ALTER TABLE reports ADD CONSTRAINT reports_type_check
+    CHECK (type IN ('wrong_instructor_info', 'broken_rmp_link', 'offensive_course_resource', 'inappropriate_review'));
+ALTER TABLE reports DROP CONSTRAINT reports_entity_type_check;
+ALTER TABLE reports ADD CONSTRAINT reports_entity_type_check
+    CHECK (entity_type IN ('course', 'instructor', 'review'));
+
+ALTER TABLE reviews ADD COLUMN hidden_at TIMESTAMP;
@@ -0,0 +1,16 @@
+-- Per-course data quality scores, one set per seed (see internal/dataquality).
+-- Rows are kept so scores can be compared across seeds; courses are keyed by
+-- code and term since a reseed gives them new ids.
+CREATE TABLE course_data_quality (
+    scored_at TIMESTAMP NOT NULL,
+    course_code VARCHAR(50) NOT NULL,
+    term VARCHAR(10) NOT NULL DEFAULT '',
+    score INTEGER NOT NULL CHECK (score BETWEEN 0 AND 100),
+    missing_description BOOLEAN NOT NULL,
+    no_sections BOOLEAN NOT NULL,
+    unparsed_times BOOLEAN NOT NULL,
+    missing_instructors BOOLEAN NOT NULL
+);
+
+CREATE INDEX idx_course_data_quality_run ON course_data_quality(scored_at);
+CREATE INDEX idx_course_data_quality_course ON course_data_quality(course_code, term, scored_at);
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS review_verifications;
+
+DELETE FROM reviews WHERE moderation = 'unverified';
+ALTER TABLE reviews DROP CONSTRAINT reviews_moderation_check;
+ALTER TABLE reviews ADD CONSTRAINT reviews_moderation_check
+    CHECK (moderation IN ('approved', 'pending'));
@@ -0,0 +1,15 @@
+-- Email verification for new reviews. A review is stored as 'unverified'
+-- until its author follows the link mailed to them; the moderation decision
+-- made at submission waits in review_verifications and is applied then.
+-- Only the SHA-256 of each link token is kept.
+ALTER TABLE reviews DROP CONSTRAINT reviews_moderation_check;
+ALTER TABLE reviews ADD CONSTRAINT reviews_moderation_check
+    CHECK (moderation IN ('approved', 'pending', 'unverified'));
+
+CREATE TABLE review_verifications (
+    review_id UUID PRIMARY KEY REFERENCES reviews(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    moderation VARCHAR(20) NOT NULL CHECK (moderation IN ('approved', 'pending')),
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW()
+);
@@ -0,0 +1,17 @@
+-- Reviews of instructors, parallel to course reviews. The seed writes one
+-- instructor row per section, so a review is kept against the instructor's
+-- name, which all of their rows share, rather than any one row's id.
+CREATE TABLE instructor_reviews (
+    id UUID PRIMARY KEY,
+    first_name VARCHAR(255) NOT NULL,
+    last_name VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    author_name VARCHAR(255),
+    clarity INTEGER NOT NULL CHECK (clarity BETWEEN 1 AND 5),
+    helpfulness INTEGER NOT NULL CHECK (helpfulness BETWEEN 1 AND 5),
+    workload INTEGER NOT NULL CHECK (workload BETWEEN 1 AND 5),
+    review_text TEXT,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (last_name, first_name, email)
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS session_drafts;
+DROP TABLE IF EXISTS session_recent_views;
+DROP TABLE IF EXISTS anonymous_sessions;
@@ -0,0 +1,28 @@
+-- Anonymous sessions for visitors without an account (see internal/session).
+-- A row is only written once a session stores something, and its
+-- started_at comes from the session token, so the anonymous_sessions
+-- retention policy purges it, and everything below it, once the session has
+-- ended.
+CREATE TABLE anonymous_sessions (
+    id UUID PRIMARY KEY,
+    started_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_anonymous_sessions_started_at ON anonymous_sessions(started_at);
+
+-- Courses the visitor opened, latest first; only the most recent few are kept.
+CREATE TABLE session_recent_views (
+    session_id UUID NOT NULL REFERENCES anonymous_sessions(id) ON DELETE CASCADE,
+    course_code VARCHAR(20) NOT NULL,
+    viewed_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (session_id, course_code)
+);
+
+-- Unsent form contents, e.g. a half-written review, by a client-chosen key.
+CREATE TABLE session_drafts (
+    session_id UUID NOT NULL REFERENCES anonymous_sessions(id) ON DELETE CASCADE,
+    key VARCHAR(100) NOT NULL,
+    body JSONB NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (session_id, key)
+);
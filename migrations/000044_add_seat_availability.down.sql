@@ -0,0 +1,7 @@
+ALTER TABLE section_activities DROP COLUMN IF EXISTS availability_updated_at;
+ALTER TABLE section_activities DROP COLUMN IF EXISTS enrolled;
+ALTER TABLE section_activities DROP COLUMN IF EXISTS capacity;
+
+ALTER TABLE sections DROP COLUMN IF EXISTS availability_updated_at;
+ALTER TABLE sections DROP COLUMN IF EXISTS enrolled;
+ALTER TABLE sections DROP COLUMN IF EXISTS capacity;
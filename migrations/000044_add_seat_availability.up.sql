@@ -0,0 +1,9 @@
+-- Seat counts the scraper reports for sections and their activities. NULL
+-- until a count has been ingested; enrolled may exceed capacity (overrides).
+ALTER TABLE sections ADD COLUMN capacity INTEGER CHECK (capacity >= 0);
+ALTER TABLE sections ADD COLUMN enrolled INTEGER CHECK (enrolled >= 0);
+ALTER TABLE sections ADD COLUMN availability_updated_at TIMESTAMP;
+
+ALTER TABLE section_activities ADD COLUMN capacity INTEGER CHECK (capacity >= 0);
+ALTER TABLE section_activities ADD COLUMN enrolled INTEGER CHECK (enrolled >= 0);
+ALTER TABLE section_activities ADD COLUMN availability_updated_at TIMESTAMP;
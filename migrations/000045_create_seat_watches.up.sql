@@ -0,0 +1,13 @@
+-- Emails waiting for a seat in a section. notified_at is set when a seat
+-- opening is mailed, and cleared when the section fills up again so the next
+-- opening is mailed too.
+CREATE TABLE IF NOT EXISTS seat_watches (
+    id UUID PRIMARY KEY,
+    email VARCHAR(255) NOT NULL,
+    section_id UUID NOT NULL REFERENCES sections(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    notified_at TIMESTAMP,
+    UNIQUE (email, section_id)
+);
+
+CREATE INDEX idx_seat_watches_section ON seat_watches(section_id);
@@ -0,0 +1,2 @@
+ALTER TABLE academic_terms DROP COLUMN IF EXISTS drop_deadline;
+ALTER TABLE academic_terms DROP COLUMN IF EXISTS enroll_deadline;
@@ -0,0 +1,4 @@
+-- Registration deadlines per term, set alongside the exam dates through the
+-- admin API. NULL until the registrar publishes them.
+ALTER TABLE academic_terms ADD COLUMN enroll_deadline TIMESTAMP; -- last day to enroll
+ALTER TABLE academic_terms ADD COLUMN drop_deadline TIMESTAMP;   -- last day to drop without a grade
@@ -0,0 +1,2 @@
+ALTER TABLE reviews DROP COLUMN IF EXISTS edited_at;
+DROP TABLE IF EXISTS review_revisions;
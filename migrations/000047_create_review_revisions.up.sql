@@ -0,0 +1,22 @@
+-- Earlier versions of edited reviews. Each edit saves the review as it read
+-- before, so readers can see that and when it changed and moderators what it
+-- said. written_at is when that version was submitted or last edited.
+CREATE TABLE IF NOT EXISTS review_revisions (
+    id BIGSERIAL PRIMARY KEY,
+    review_id UUID NOT NULL REFERENCES reviews(id) ON DELETE CASCADE,
+    author_name VARCHAR(100),
+    liked BOOLEAN NOT NULL,
+    difficulty INTEGER NOT NULL,
+    real_world_relevance INTEGER NOT NULL,
+    review_text TEXT,
+    delivery_mode VARCHAR(20),
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    written_at TIMESTAMP NOT NULL,
+    replaced_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_review_revisions_review ON review_revisions(review_id, id);
+
+-- Set by author edits only; updated_at also moves when moderators publish or
+-- embargo a review.
+ALTER TABLE reviews ADD COLUMN edited_at TIMESTAMP;
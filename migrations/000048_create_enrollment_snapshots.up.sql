@@ -0,0 +1,12 @@
+-- Section seat counts over time, one row each time ingestion changes them.
+-- Waitlist odds are estimated from how many seats opened up in past offerings
+-- of a course after they filled.
+CREATE TABLE IF NOT EXISTS enrollment_snapshots (
+    id BIGSERIAL PRIMARY KEY,
+    section_id UUID NOT NULL REFERENCES sections(id) ON DELETE CASCADE,
+    capacity INTEGER NOT NULL,
+    enrolled INTEGER NOT NULL,
+    recorded_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_enrollment_snapshots_section ON enrollment_snapshots(section_id, recorded_at);
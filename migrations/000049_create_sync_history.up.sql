@@ -0,0 +1,15 @@
+-- One row per scheduled catalog sync: when it ran, whether it finished, and
+-- how many rows it added, changed and removed in each catalog table.
+CREATE TABLE IF NOT EXISTS sync_history (
+    id BIGSERIAL PRIMARY KEY,
+    term VARCHAR(10) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'running' CHECK (status IN ('running', 'succeeded', 'failed')),
+    started_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMP,
+    pages INTEGER NOT NULL DEFAULT 0,
+    quarantined INTEGER NOT NULL DEFAULT 0,
+    diff JSONB NOT NULL DEFAULT '{}',
+    error TEXT
+);
+
+CREATE INDEX idx_sync_history_started_at ON sync_history(started_at DESC);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_reports_review_created_at;
+ALTER TABLE reports DROP CONSTRAINT IF EXISTS reports_reason_entity_check;
+ALTER TABLE reports DROP COLUMN IF EXISTS reason;
@@ -0,0 +1,10 @@
+-- Why a review was reported, so moderators can work the queue by reason and
+-- see how each one trends. Only review reports have a reason; those filed
+-- before reasons existed count as 'other'.
+ALTER TABLE reports ADD COLUMN reason VARCHAR(20)
+    CHECK (reason IN ('spam', 'harassment', 'misinformation', 'off_topic', 'other'));
+UPDATE reports SET reason = 'other' WHERE entity_type = 'review';
+ALTER TABLE reports ADD CONSTRAINT reports_reason_entity_check
+    CHECK ((entity_type = 'review') = (reason IS NOT NULL));
+
+CREATE INDEX idx_reports_review_created_at ON reports(created_at) WHERE entity_type = 'review';
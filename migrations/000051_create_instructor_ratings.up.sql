@@ -0,0 +1,17 @@
+-- RateMyProfessors ratings, refreshed in the background by internal/rmp. Like
+-- instructor_reviews they are kept by name, which all of an instructor's
+-- per-section rows share. A name RateMyProfessors has no match for is still
+-- stored, with a null rmp_id, so it isn't looked up again until it is stale.
+CREATE TABLE instructor_ratings (
+    first_name VARCHAR(255) NOT NULL,
+    last_name VARCHAR(255) NOT NULL,
+    rmp_id VARCHAR(20),
+    rating DECIMAL(2, 1),           -- 1 to 5; null with no ratings
+    difficulty DECIMAL(2, 1),       -- likewise
+    would_take_again INTEGER CHECK (would_take_again BETWEEN 0 AND 100), -- percent; null when nobody answered
+    num_ratings INTEGER NOT NULL DEFAULT 0,
+    fetched_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (last_name, first_name)
+);
+
+CREATE INDEX idx_instructor_ratings_fetched_at ON instructor_ratings(fetched_at);
@@ -0,0 +1,4 @@
+DELETE FROM badges WHERE metric = 'helpful_votes';
+ALTER TABLE badges DROP CONSTRAINT IF EXISTS badges_metric_check;
+ALTER TABLE badges ADD CONSTRAINT badges_metric_check
+    CHECK (metric IN ('reviews', 'department_reviews'));
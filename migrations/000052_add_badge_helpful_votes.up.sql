@@ -0,0 +1,9 @@
+-- Badges for reviews readers found useful: helpful_votes counts the helpful
+-- votes on a reviewer's published reviews.
+ALTER TABLE badges DROP CONSTRAINT badges_metric_check;
+ALTER TABLE badges ADD CONSTRAINT badges_metric_check
+    CHECK (metric IN ('reviews', 'department_reviews', 'helpful_votes'));
+
+INSERT INTO badges (slug, name, description, metric, threshold) VALUES
+('helpful-votes', '10 Helpful Votes', 'Reviews voted helpful ten times', 'helpful_votes', 10)
+ON CONFLICT (slug) DO NOTHING;
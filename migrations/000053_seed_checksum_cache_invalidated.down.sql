@@ -0,0 +1 @@
+ALTER TABLE _seed_checksum DROP COLUMN IF EXISTS cache_invalidated;
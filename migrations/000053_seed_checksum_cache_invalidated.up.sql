@@ -0,0 +1,4 @@
+-- Whether the load that wrote the checksum already dropped the cached reads
+-- of the courses it changed. Loads that didn't, such as scripts/seed.sh, leave
+-- it false and the whole catalog cache is dropped once the seed is noticed.
+ALTER TABLE _seed_checksum ADD COLUMN IF NOT EXISTS cache_invalidated BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,17 @@
+-- Schedules a user saves so a calendar app can subscribe to them with
+-- GET /users/me/schedules/:id/calendar.ics. There are no accounts, so they
+-- belong to an email as filter presets do. Section and activity ids aren't
+-- foreign keys: ones a rescrape removes just drop out of the calendar. The
+-- feed token is kept only as its SHA-256 hash, NULL until issued or once
+-- revoked.
+CREATE TABLE saved_schedules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    email VARCHAR(255) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    section_ids UUID[] NOT NULL DEFAULT '{}',
+    activity_ids UUID[] NOT NULL DEFAULT '{}',
+    feed_token_hash VARCHAR(64) UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (email, name)
+);
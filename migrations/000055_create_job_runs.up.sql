@@ -0,0 +1,8 @@
+-- The schedule tick each background job last ran for. Instances' timers
+-- don't fire together, so one that reaches a tick after another instance
+-- already ran it finds it here and skips it.
+CREATE TABLE job_runs (
+    job VARCHAR(64) PRIMARY KEY,
+    tick TIMESTAMP NOT NULL,
+    started_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
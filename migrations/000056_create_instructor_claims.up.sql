@@ -0,0 +1,23 @@
+-- Claims on instructor profiles. Like instructor_reviews they are kept by
+-- name, which all of an instructor's per-section rows share, so a rescrape
+-- replacing those rows doesn't lose them. A claim is unverified until the link
+-- mailed to its institutional email is followed, then pending until an admin
+-- approves or rejects it. An approved claim links the instructor to the
+-- email; there is at most one per instructor. The link token is kept only as
+-- its SHA-256 hash, NULL once used.
+CREATE TABLE instructor_claims (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    first_name VARCHAR(255) NOT NULL,
+    last_name VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'unverified',
+    token_hash VARCHAR(64) UNIQUE,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    verified_at TIMESTAMP,
+    decided_at TIMESTAMP,
+    UNIQUE (last_name, first_name, email)
+);
+
+CREATE UNIQUE INDEX idx_instructor_claims_approved ON instructor_claims(last_name, first_name) WHERE status = 'approved';
+CREATE INDEX idx_instructor_claims_status ON instructor_claims(status);
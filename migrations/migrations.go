@@ -0,0 +1,10 @@
+// Package migrations embeds the versioned SQL migrations, so the API binary
+// can apply them itself; see internal/migrate.
+package migrations
+
+import "embed"
+
+// FS holds every NNNNNN_name.up.sql and NNNNNN_name.down.sql file.
+//
+//go:embed *.sql
+var FS embed.FS
@@ -5,6 +5,8 @@ from pathlib import Path
 from typing import Dict, List, Tuple, Set
 from urllib.parse import quote_plus
 
+from validate_seed import validate_courses
+
 def escape_sql_string(s: str) -> str:
     """Escape single quotes for SQL"""
     if s is None:
@@ -307,6 +309,27 @@ def generate_section_sql(sections_list: List[Dict], courses_list: List[Dict]) ->
     
     return sql_lines
 
+def generate_quarantine_sql(quarantined: List[Dict]) -> List[str]:
+    """Generate SQL INSERT statements for records that failed validation"""
+    sql_lines = ["-- Insert quarantined records"]
+
+    if not quarantined:
+        return sql_lines
+
+    quarantine_inserts = []
+    for entry in quarantined:
+        source = escape_sql_string(entry['source'])
+        key = escape_sql_string(entry['key'])
+        record = escape_sql_string(json.dumps(entry['record']))
+        reasons = ', '.join(escape_sql_string(r) for r in entry['reasons'])
+        quarantine_inserts.append(f"({source}, {key}, {record}::jsonb, ARRAY[{reasons}]::text[])")
+
+    sql_lines.append("INSERT INTO seed_quarantine (source, record_key, record, reasons) VALUES")
+    sql_lines.append(",\n".join(quarantine_inserts) + ";")
+    sql_lines.append("")
+
+    return sql_lines
+
 def generate_seed_sql(json_files: list[str], output_file: str, descriptions_file: str = None):
     """Generate seed.sql for all generated JSON data"""
 
@@ -322,6 +345,9 @@ def generate_seed_sql(json_files: list[str], output_file: str, descriptions_file
     all_section_activities_list = []
     all_sections_list = []
     all_instructors_list = []
+    all_quarantined = []
+    # Catalog numbers claimed so far, per session directory
+    session_catalog_owners: Dict[str, Dict[str, str]] = defaultdict(dict)
     
     # Process each JSON file
     for json_file in json_files:
@@ -330,7 +356,12 @@ def generate_seed_sql(json_files: list[str], output_file: str, descriptions_file
         with open(json_file, 'r', encoding='utf-8') as f:
             data = json.load(f)
         
-        courses = data.get('courses', [])
+        session = Path(json_file).parent.name
+        courses, quarantined = validate_courses(data.get('courses', []), session_catalog_owners[session])
+        for entry in quarantined:
+            entry['source'] = f"{session}/{Path(json_file).name}"
+            print(f"  ! quarantined {entry['key']}: {'; '.join(entry['reasons'])}")
+        all_quarantined.extend(quarantined)
         
         # Collect data from JSON
         courses_list, course_code_to_uuid, course_code_to_index = collect_courses_and_instructors(courses, descriptions_map)
@@ -373,6 +404,7 @@ def generate_seed_sql(json_files: list[str], output_file: str, descriptions_file
     sql_lines.extend(generate_section_sql(all_sections_list, all_courses_list))
     sql_lines.extend(generate_section_activity_sql(all_section_activities_list))
     sql_lines.extend(generate_instructor_sql(all_instructors_list))
+    sql_lines.extend(generate_quarantine_sql(all_quarantined))
     
     sql_lines.append("COMMIT;")
     sql_lines.append("")
@@ -386,6 +418,7 @@ def generate_seed_sql(json_files: list[str], output_file: str, descriptions_file
     print(f"   - {len(all_courses_list)} courses")
     print(f"   - {len(all_section_activities_list)} section activities")
     print(f"   - {len(all_sections_list)} sections")
+    print(f"   - {len(all_quarantined)} quarantined records")
 
 if __name__ == '__main__':
     import sys
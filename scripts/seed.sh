@@ -50,8 +50,24 @@ if [ -n "$stored_sha" ] && [ "$current_sha" = "$stored_sha" ]; then
 fi
 
 echo "seed.sql changed or first run. Truncating only seed tables (reviews untouched), then seeding..."
-psql "$SEED_URL" -c "TRUNCATE instructors, section_activities, sections, courses RESTART IDENTITY CASCADE;"
+psql "$SEED_URL" -c "TRUNCATE instructors, section_activities, sections, courses, seed_quarantine RESTART IDENTITY CASCADE;"
 psql "$SEED_URL" -f ./db/seed.sql
+
+# Record this session in the offering history (never truncated). The session
+# starting in September of the current year is published in spring.
+if [ -z "$SEED_ACADEMIC_YEAR" ]; then
+    if [ "$(date +%-m)" -ge 5 ]; then
+        SEED_ACADEMIC_YEAR=$(date +%Y)
+    else
+        SEED_ACADEMIC_YEAR=$(( $(date +%Y) - 1 ))
+    fi
+fi
+psql "$SEED_URL" -c "INSERT INTO course_offerings (code, academic_year, term) SELECT DISTINCT code, $SEED_ACADEMIC_YEAR, term FROM courses WHERE term IS NOT NULL AND term <> '' ON CONFLICT DO NOTHING;"
+# Tag every section with its session: fall/winter starting in September of
+# SEED_ACADEMIC_YEAR, or the summer after it. Dates can be corrected in terms.
+next_year=$(( SEED_ACADEMIC_YEAR + 1 ))
+psql "$SEED_URL" -c "INSERT INTO terms (id, session, academic_year, starts_on, ends_on) VALUES ('FW$SEED_ACADEMIC_YEAR', 'FW', $SEED_ACADEMIC_YEAR, '$SEED_ACADEMIC_YEAR-09-01', '$next_year-04-30'), ('SU$next_year', 'SU', $SEED_ACADEMIC_YEAR, '$next_year-05-01', '$next_year-08-31') ON CONFLICT DO NOTHING;"
+psql "$SEED_URL" -c "UPDATE sections s SET term_id = CASE WHEN c.term IN ('F', 'W', 'Y') THEN 'FW$SEED_ACADEMIC_YEAR' ELSE 'SU$next_year' END FROM courses c WHERE c.id = s.course_id;"
 psql "$SEED_URL" -c "DELETE FROM _seed_checksum; INSERT INTO _seed_checksum (checksum) VALUES ('$current_sha');"
 echo "Database seeded successfully!"
 
@@ -28,13 +28,19 @@ else
     echo "Waiting a moment for database to be ready..."
     sleep 3
 
-    # Run migrations (migrate tool will handle connection retries)
+    # Apply the migrations embedded in the API binary
     echo "Running database migrations..."
-    ./scripts/migrate.sh
-
-    # Seed the database (psql will handle connection retries)
-    echo "Seeding database..."
-    ./scripts/seed.sh
+    /app/bin/api migrate up
+
+    # Load the catalog: scrape SCRAPER_TERM from the York Courses Website
+    # when it's set, otherwise fall back to the committed seed.sql
+    if [ -n "$SCRAPER_TERM" ]; then
+        echo "Scraping $SCRAPER_TERM timetable..."
+        /app/bin/scraper
+    else
+        echo "Seeding database..."
+        ./scripts/seed.sh
+    fi
 fi
 
 # Start the server (Go app will handle connection retries)
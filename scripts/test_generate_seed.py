@@ -21,6 +21,7 @@ from generate_seed import (
     generate_course_sql,
     generate_section_activity_sql,
     generate_section_sql,
+    generate_quarantine_sql,
     generate_seed_sql
 )
 
@@ -986,6 +987,29 @@ class TestGenerateSectionSQL(unittest.TestCase):
         self.assertIn('-- Insert sections', sql_lines)
 
 
+class TestGenerateQuarantineSQL(unittest.TestCase):
+    """Test generate_quarantine_sql function"""
+
+    def test_generates_insert_with_reasons(self):
+        quarantined = [{
+            'source': 'summer-2026/test.json',
+            'key': 'SUST W2',
+            'record': {'department': 'SUST', 'courseTitle': "Women's Studies"},
+            'reasons': ['missing courseId', "credits '' is not a number"],
+        }]
+        sql_lines = generate_quarantine_sql(quarantined)
+
+        self.assertIn('INSERT INTO seed_quarantine (source, record_key, record, reasons) VALUES', sql_lines)
+        sql = '\n'.join(sql_lines)
+        self.assertIn("'summer-2026/test.json', 'SUST W2'", sql)
+        self.assertIn("Women''s Studies", sql)
+        self.assertIn("::jsonb", sql)
+        self.assertIn("ARRAY['missing courseId', 'credits '''' is not a number']::text[]", sql)
+
+    def test_empty_list(self):
+        self.assertEqual(generate_quarantine_sql([]), ['-- Insert quarantined records'])
+
+
 class TestGenerateSeedSQLIntegration(unittest.TestCase):
     """Integration test for generate_seed_sql function"""
     
@@ -1090,6 +1114,33 @@ class TestGenerateSeedSQLIntegration(unittest.TestCase):
                 if os.path.exists(json_file):
                     os.unlink(json_file)
 
+    def test_invalid_records_are_quarantined(self):
+        bad = {'courses': [dict(self.test_json_1['courses'][0], courseId='', credits='')]}
+        json_files = []
+        try:
+            with tempfile.NamedTemporaryFile(mode='w', suffix='.json', delete=False) as json_file:
+                json.dump(bad, json_file)
+                json_files.append(json_file.name)
+
+            with tempfile.NamedTemporaryFile(mode='w', suffix='.sql', delete=False) as sql_file:
+                sql_path = sql_file.name
+
+            with patch('builtins.print'):
+                generate_seed_sql(json_files, sql_path, None)
+
+            with open(sql_path, 'r') as f:
+                content = f.read()
+
+            self.assertNotIn('INSERT INTO courses', content)
+            self.assertIn('INSERT INTO seed_quarantine', content)
+            self.assertIn('missing courseId', content)
+        finally:
+            if os.path.exists(sql_path):
+                os.unlink(sql_path)
+            for json_file in json_files:
+                if os.path.exists(json_file):
+                    os.unlink(json_file)
+
     def test_generation_with_descriptions_file(self):
         descriptions = [
             {"course_code": "TEST1000", "description": "From map"}
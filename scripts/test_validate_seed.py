@@ -0,0 +1,154 @@
+"""
+Test cases for validate_seed.py
+"""
+
+import copy
+import unittest
+from validate_seed import (
+    record_key,
+    validate_course,
+    validate_courses,
+)
+
+
+VALID_COURSE = {
+    'courseTitle': 'Object Oriented Programming',
+    'department': 'EECS',
+    'courseId': '2030',
+    'credits': '3.00',
+    'faculty': 'LE',
+    'term': 'F',
+    'sections': [
+        {
+            'type': 'LECT',
+            'section': 'A',
+            'catalogNumber': 'K12A01',
+            'schedule': [{'day': 'M', 'time': '10:00', 'duration': '80'}],
+            'instructors': ['Jane Doe'],
+        },
+        {
+            'type': 'LAB',
+            'catalogNumber': 'K12A02',
+            'schedule': [{'day': '', 'time': '0:00', 'duration': '0'}],
+            'instructors': [],
+        },
+    ],
+}
+
+
+def course(**overrides):
+    c = copy.deepcopy(VALID_COURSE)
+    c.update(overrides)
+    return c
+
+
+class TestValidateCourse(unittest.TestCase):
+    """Test validate_course function"""
+
+    def test_valid_course(self):
+        self.assertEqual(validate_course(course()), [])
+
+    def test_asynchronous_placeholders_are_valid(self):
+        c = course()
+        c['sections'][0]['schedule'] = [
+            {'day': '', 'time': '', 'duration': '0'},
+            {'day': 'W', 'time': '', 'duration': '0'},
+            {'day': '', 'time': '0:00', 'duration': '50'},
+        ]
+        self.assertEqual(validate_course(c), [])
+
+    def test_missing_fields(self):
+        problems = validate_course(course(courseId='', courseTitle=None, credits=''))
+        self.assertIn('missing courseId', problems)
+        self.assertIn('missing courseTitle', problems)
+        self.assertIn("credits '' is not a number", problems)
+
+    def test_negative_credits(self):
+        self.assertIn("negative credits '-3'", validate_course(course(credits='-3')))
+
+    def test_sections_not_a_list(self):
+        self.assertEqual(validate_course(course(sections={'type': 'LECT'})), ['sections is not a list'])
+
+    def test_activity_without_type(self):
+        c = course()
+        c['sections'][1]['type'] = ''
+        self.assertIn('sections[1] has no activity type', validate_course(c))
+
+    def test_no_lettered_section(self):
+        c = course()
+        del c['sections'][0]['section']
+        self.assertIn('no lettered section to attach activities to', validate_course(c))
+
+    def test_bad_schedule(self):
+        c = course()
+        c['sections'][0]['schedule'] = [
+            {'day': 'X', 'time': '10:00', 'duration': '50'},
+            {'day': 'M', 'time': '25:00', 'duration': '50'},
+            {'day': 'T', 'time': '10:00', 'duration': 'fifty'},
+            {'day': 'R', 'time': '', 'duration': '50'},
+        ]
+        problems = validate_course(c)
+        self.assertIn("sections[0] invalid day 'X'", problems)
+        self.assertIn("sections[0] invalid time '25:00'", problems)
+        self.assertIn("sections[0] invalid duration 'fifty'", problems)
+        self.assertIn('sections[0] meets on R with no start time', problems)
+
+    def test_not_an_object(self):
+        self.assertEqual(validate_course(['EECS2030']), ['record is not an object'])
+
+
+class TestValidateCourses(unittest.TestCase):
+    """Test validate_courses function"""
+
+    def test_splits_valid_and_quarantined(self):
+        bad = course(courseId='')
+        valid, quarantined = validate_courses([course(), bad], {})
+
+        self.assertEqual(len(valid), 1)
+        self.assertEqual(len(quarantined), 1)
+        self.assertEqual(quarantined[0]['key'], 'EECS F')
+        self.assertIs(quarantined[0]['record'], bad)
+        self.assertIn('missing courseId', quarantined[0]['reasons'])
+
+    def test_duplicate_catalog_number_across_courses(self):
+        owners = {}
+        other = course(department='MATH', courseId='1090')
+        valid, quarantined = validate_courses([course(), other], owners)
+
+        self.assertEqual(len(valid), 1)
+        self.assertEqual(quarantined[0]['reasons'], [
+            'duplicate catalog number K12A01 (also EECS2030 F)',
+            'duplicate catalog number K12A02 (also EECS2030 F)',
+        ])
+
+    def test_duplicates_are_tracked_across_calls(self):
+        owners = {}
+        validate_courses([course()], owners)
+        _, quarantined = validate_courses([course(term='W')], owners)
+        self.assertEqual(len(quarantined), 1)
+
+    def test_same_course_split_over_records_is_not_a_duplicate(self):
+        valid, quarantined = validate_courses([course(), course()], {})
+        self.assertEqual(len(valid), 2)
+        self.assertEqual(quarantined, [])
+
+    def test_cross_listed_and_placeholder_numbers_are_ignored(self):
+        first, second = course(), course(department='HRM', courseId='3450')
+        for c in (first, second):
+            c['sections'][0]['catalogNumber'] = 'W55H01 (AP HRM ) H82U01 (AP ADMS)'
+            c['sections'][1]['catalogNumber'] = 'Cancelled'
+        valid, quarantined = validate_courses([first, second], {})
+        self.assertEqual(len(valid), 2)
+        self.assertEqual(quarantined, [])
+
+
+class TestRecordKey(unittest.TestCase):
+    """Test record_key function"""
+
+    def test_key(self):
+        self.assertEqual(record_key(course()), 'EECS2030 F')
+        self.assertEqual(record_key({}), '? ?')
+
+
+if __name__ == '__main__':
+    unittest.main()
@@ -0,0 +1,138 @@
+"""Validation of scraped course records before they are written to seed.sql.
+
+Records that fail are quarantined into the seed_quarantine table instead of
+being inserted, so an admin can inspect them and re-process fixed versions
+(see internal/seedcheck for the Go side, which must apply the same rules).
+"""
+
+import re
+from typing import Dict, List, Tuple
+
+VALID_DAYS = {'M', 'T', 'W', 'R', 'F', 'S', 'U'}
+TIME_RE = re.compile(r'^([01]?\d|2[0-3]):[0-5]\d$')
+# A single activity's catalog number. Cross-listed activities carry a longer
+# string naming every listing and placeholders like "Cancelled" are reused, so
+# only plain numbers are checked for duplicates.
+CATALOG_NUMBER_RE = re.compile(r'^[A-Z0-9]{6}$')
+
+
+def course_code(course: Dict) -> str:
+    return f"{course.get('department', '')}{course.get('courseId', '')}"
+
+
+def record_key(course: Dict) -> str:
+    """Identifies a record in messages and the quarantine table, e.g. EECS2030 F"""
+    return f"{course_code(course) or '?'} {course.get('term', '') or '?'}"
+
+
+def validate_schema(course: Dict) -> List[str]:
+    problems = []
+    for field in ('department', 'courseId', 'courseTitle', 'term'):
+        value = course.get(field)
+        if not isinstance(value, str) or not value.strip():
+            problems.append(f"missing {field}")
+
+    credits = course.get('credits')
+    try:
+        if float(credits) < 0:
+            problems.append(f"negative credits {credits!r}")
+    except (TypeError, ValueError):
+        problems.append(f"credits {credits!r} is not a number")
+
+    sections = course.get('sections')
+    if not isinstance(sections, list):
+        problems.append("sections is not a list")
+        return problems
+    for i, section in enumerate(sections):
+        if not isinstance(section, dict):
+            problems.append(f"sections[{i}] is not an object")
+        elif not section.get('type'):
+            problems.append(f"sections[{i}] has no activity type")
+    return problems
+
+
+def validate_integrity(course: Dict) -> List[str]:
+    """Every activity must attach to a lettered section, or it is silently dropped"""
+    sections = [s for s in course.get('sections') or [] if isinstance(s, dict)]
+    if not any(s.get('section') for s in sections):
+        return ["no lettered section to attach activities to"]
+    return []
+
+
+def validate_schedule(course: Dict) -> List[str]:
+    problems = []
+    for i, section in enumerate(course.get('sections') or []):
+        if not isinstance(section, dict):
+            continue
+        schedule = section.get('schedule') or []
+        if not isinstance(schedule, list):
+            problems.append(f"sections[{i}] schedule is not a list")
+            continue
+        for meeting in schedule:
+            if not isinstance(meeting, dict):
+                problems.append(f"sections[{i}] has a meeting that is not an object")
+                continue
+            day = meeting.get('day', '')
+            time = meeting.get('time', '')
+            duration = meeting.get('duration', '')
+            if day and day not in VALID_DAYS:
+                problems.append(f"sections[{i}] invalid day {day!r}")
+            if time and not TIME_RE.match(time):
+                problems.append(f"sections[{i}] invalid time {time!r}")
+            if duration and not str(duration).isdigit():
+                problems.append(f"sections[{i}] invalid duration {duration!r}")
+            # A meeting that occupies a weekly slot needs a start time
+            if day and str(duration).isdigit() and int(duration) > 0 and not time:
+                problems.append(f"sections[{i}] meets on {day} with no start time")
+    return problems
+
+
+def validate_course(course: Dict) -> List[str]:
+    """Return every problem with a single record; empty means it can be seeded"""
+    if not isinstance(course, dict):
+        return ["record is not an object"]
+    problems = validate_schema(course)
+    if "sections is not a list" in problems:
+        return problems
+    return problems + validate_integrity(course) + validate_schedule(course)
+
+
+def catalog_numbers(course: Dict) -> List[str]:
+    numbers = []
+    for section in course.get('sections') or []:
+        if isinstance(section, dict):
+            number = section.get('catalogNumber', '')
+            if CATALOG_NUMBER_RE.match(number or ''):
+                numbers.append(number)
+    return numbers
+
+
+def validate_courses(courses: List, catalog_owners: Dict[str, str]) -> Tuple[List[Dict], List[Dict]]:
+    """Split records into (valid, quarantined).
+
+    catalog_owners maps catalog numbers already claimed this session to the
+    record that claimed them; it is shared across files of the same session.
+    Quarantined entries are {'key', 'record', 'reasons'}.
+    """
+    valid = []
+    quarantined = []
+    for course in courses:
+        reasons = validate_course(course)
+        key = record_key(course) if isinstance(course, dict) else '?'
+
+        if not reasons:
+            # The same course can be split over several records; only another
+            # course claiming a catalog number is a conflict.
+            for number in catalog_numbers(course):
+                owner = catalog_owners.get(number)
+                if owner and owner != key:
+                    reasons.append(f"duplicate catalog number {number} (also {owner})")
+
+        if reasons:
+            quarantined.append({'key': key, 'record': course, 'reasons': reasons})
+            continue
+
+        for number in catalog_numbers(course):
+            catalog_owners.setdefault(number, key)
+        valid.append(course)
+    return valid, quarantined